@@ -89,7 +89,7 @@ var _ = Describe("Job Builds", func() {
 					Jobs: atc.JobConfigs{
 						{Name: "job-name"},
 					},
-				}, db.ConfigVersion(1), db.PipelineUnpaused)
+				}, db.ConfigVersion(1), db.PipelineUnpaused, "some-author")
 				Expect(err).NotTo(HaveOccurred())
 
 				savedPipeline, found, err := teamDB.GetPipelineByName(pipelineName)
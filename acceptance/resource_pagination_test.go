@@ -62,7 +62,7 @@ var _ = Describe("Resource Pagination", func() {
 			Resources: atc.ResourceConfigs{
 				{Name: "resource-name"},
 			},
-		}, db.ConfigVersion(1), db.PipelineUnpaused)
+		}, db.ConfigVersion(1), db.PipelineUnpaused, "some-author")
 		Expect(err).NotTo(HaveOccurred())
 
 		savedPipeline, found, err := teamDB.GetPipelineByName(atc.DefaultPipelineName)
@@ -0,0 +1,28 @@
+package atc
+
+type AlertType string
+
+const (
+	// AlertTypeDuration is raised for a build that has been running for
+	// longer than most of its job's recent builds took.
+	AlertTypeDuration AlertType = "duration"
+)
+
+// Alert is something unusual about a build. Threshold and Elapsed are in
+// seconds: how long Percentile percent of the job's recent builds took, and
+// how long the build had been running when the alert was raised.
+type Alert struct {
+	Type AlertType `json:"type"`
+
+	BuildID      int    `json:"build_id"`
+	BuildName    string `json:"build_name"`
+	TeamName     string `json:"team_name"`
+	PipelineName string `json:"pipeline_name"`
+	JobName      string `json:"job_name"`
+
+	Percentile int     `json:"percentile"`
+	Threshold  float64 `json:"threshold"`
+	Elapsed    float64 `json:"elapsed"`
+
+	CreatedAt int64 `json:"created_at"`
+}
@@ -0,0 +1,121 @@
+package api_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/atc/db"
+)
+
+var _ = Describe("Alerts API", func() {
+	Describe("GET /api/v1/teams/:team_name/alerts", func() {
+		var response *http.Response
+
+		BeforeEach(func() {
+			alertsDB.GetDurationAlertsReturns([]db.DurationAlert{
+				{
+					BuildID:      42,
+					BuildName:    "7",
+					TeamName:     "some-team",
+					PipelineName: "some-pipeline",
+					JobName:      "some-job",
+					Percentile:   95,
+					Threshold:    4 * time.Minute,
+					Elapsed:      40 * time.Minute,
+					CreatedAt:    time.Unix(100, 0),
+				},
+			}, nil)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+
+			response, err = client.Get(server.URL + "/api/v1/teams/some-team/alerts")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when not authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns 401", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+
+		Context("when authorized for another team", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("some-other-team", 6, false, true)
+			})
+
+			It("returns 403 without getting the alerts", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+				Expect(alertsDB.GetDurationAlertsCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("some-team", 5, false, true)
+			})
+
+			It("lists the team's alerts", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+				Expect(response.Header.Get("Content-Type")).To(Equal("application/json"))
+
+				body, err := ioutil.ReadAll(response.Body)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(body).To(MatchJSON(`[
+					{
+						"type": "duration",
+						"build_id": 42,
+						"build_name": "7",
+						"team_name": "some-team",
+						"pipeline_name": "some-pipeline",
+						"job_name": "some-job",
+						"percentile": 95,
+						"threshold": 240,
+						"elapsed": 2400,
+						"created_at": 100
+					}
+				]`))
+
+				Expect(alertsDB.GetDurationAlertsCallCount()).To(Equal(1))
+				_, teamName := alertsDB.GetDurationAlertsArgsForCall(0)
+				Expect(teamName).To(Equal("some-team"))
+			})
+
+			Context("when there are no alerts", func() {
+				BeforeEach(func() {
+					alertsDB.GetDurationAlertsReturns([]db.DurationAlert{}, nil)
+				})
+
+				It("returns an empty list", func() {
+					body, err := ioutil.ReadAll(response.Body)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(body).To(MatchJSON(`[]`))
+				})
+			})
+
+			Context("when getting the alerts fails", func() {
+				BeforeEach(func() {
+					alertsDB.GetDurationAlertsReturns(nil, errors.New("nope"))
+				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+	})
+})
@@ -0,0 +1,82 @@
+// This file was generated by counterfeiter
+package alertserverfakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/concourse/atc/api/alertserver"
+	"github.com/concourse/atc/db"
+)
+
+type FakeAlertsDB struct {
+	GetDurationAlertsStub        func(ctx context.Context, teamName string) ([]db.DurationAlert, error)
+	getDurationAlertsMutex       sync.RWMutex
+	getDurationAlertsArgsForCall []struct {
+		ctx      context.Context
+		teamName string
+	}
+	getDurationAlertsReturns struct {
+		result1 []db.DurationAlert
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeAlertsDB) GetDurationAlerts(ctx context.Context, teamName string) ([]db.DurationAlert, error) {
+	fake.getDurationAlertsMutex.Lock()
+	fake.getDurationAlertsArgsForCall = append(fake.getDurationAlertsArgsForCall, struct {
+		ctx      context.Context
+		teamName string
+	}{ctx, teamName})
+	fake.recordInvocation("GetDurationAlerts", []interface{}{ctx, teamName})
+	fake.getDurationAlertsMutex.Unlock()
+	if fake.GetDurationAlertsStub != nil {
+		return fake.GetDurationAlertsStub(ctx, teamName)
+	} else {
+		return fake.getDurationAlertsReturns.result1, fake.getDurationAlertsReturns.result2
+	}
+}
+
+func (fake *FakeAlertsDB) GetDurationAlertsCallCount() int {
+	fake.getDurationAlertsMutex.RLock()
+	defer fake.getDurationAlertsMutex.RUnlock()
+	return len(fake.getDurationAlertsArgsForCall)
+}
+
+func (fake *FakeAlertsDB) GetDurationAlertsArgsForCall(i int) (context.Context, string) {
+	fake.getDurationAlertsMutex.RLock()
+	defer fake.getDurationAlertsMutex.RUnlock()
+	return fake.getDurationAlertsArgsForCall[i].ctx, fake.getDurationAlertsArgsForCall[i].teamName
+}
+
+func (fake *FakeAlertsDB) GetDurationAlertsReturns(result1 []db.DurationAlert, result2 error) {
+	fake.GetDurationAlertsStub = nil
+	fake.getDurationAlertsReturns = struct {
+		result1 []db.DurationAlert
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAlertsDB) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.getDurationAlertsMutex.RLock()
+	defer fake.getDurationAlertsMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeAlertsDB) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ alertserver.AlertsDB = new(FakeAlertsDB)
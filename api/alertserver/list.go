@@ -0,0 +1,34 @@
+package alertserver
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/api/present"
+	"github.com/tedsuo/rata"
+)
+
+// ListAlerts lists the alerts raised for the team's builds, most recent
+// first.
+func (s *Server) ListAlerts(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.Session("list-alerts")
+
+	alerts, err := s.db.GetDurationAlerts(r.Context(), rata.Param(r, "team_name"))
+	if err != nil {
+		logger.Error("failed-to-get-duration-alerts", err)
+		apierror.DBFailure(w, "failed to get alerts")
+		return
+	}
+
+	presented := make([]atc.Alert, len(alerts))
+	for i, alert := range alerts {
+		presented[i] = present.DurationAlert(alert)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(presented)
+}
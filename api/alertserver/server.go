@@ -0,0 +1,30 @@
+package alertserver
+
+import (
+	"context"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/db"
+)
+
+//go:generate counterfeiter . AlertsDB
+
+type AlertsDB interface {
+	GetDurationAlerts(ctx context.Context, teamName string) ([]db.DurationAlert, error)
+}
+
+type Server struct {
+	logger lager.Logger
+
+	db AlertsDB
+}
+
+func NewServer(
+	logger lager.Logger,
+	db AlertsDB,
+) *Server {
+	return &Server{
+		logger: logger,
+		db:     db,
+	}
+}
@@ -6,29 +6,43 @@ import (
 	"net/http/httptest"
 	"sync"
 	"testing"
+	"time"
 
 	"code.cloudfoundry.org/lager"
 	"code.cloudfoundry.org/lager/lagertest"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
 
 	"github.com/concourse/atc"
 	"github.com/concourse/atc/api"
 	"github.com/concourse/atc/auth"
 
+	"github.com/concourse/atc/api/alertserver/alertserverfakes"
+	"github.com/concourse/atc/api/auditserver/auditserverfakes"
+	"github.com/concourse/atc/api/buildserver"
 	"github.com/concourse/atc/api/buildserver/buildserverfakes"
 	"github.com/concourse/atc/api/containerserver/containerserverfakes"
+	"github.com/concourse/atc/api/gcserver/gcserverfakes"
+	"github.com/concourse/atc/api/hookserver/hookserverfakes"
+	"github.com/concourse/atc/api/infoserver/infoserverfakes"
 	"github.com/concourse/atc/api/jobserver/jobserverfakes"
 	"github.com/concourse/atc/api/pipes/pipesfakes"
+	"github.com/concourse/atc/api/queueserver/queueserverfakes"
 	"github.com/concourse/atc/api/resourceserver/resourceserverfakes"
+	"github.com/concourse/atc/api/resourcetypeserver/resourcetypeserverfakes"
 	"github.com/concourse/atc/api/teamserver/teamserverfakes"
+	"github.com/concourse/atc/api/tokenserver/tokenserverfakes"
+	"github.com/concourse/atc/api/usageserver/usageserverfakes"
 	"github.com/concourse/atc/api/volumeserver/volumeserverfakes"
 	"github.com/concourse/atc/api/workerserver/workerserverfakes"
 	"github.com/concourse/atc/auth/authfakes"
 	"github.com/concourse/atc/config"
+	"github.com/concourse/atc/creds/credsfakes"
 	"github.com/concourse/atc/db"
 	"github.com/concourse/atc/db/dbfakes"
 	"github.com/concourse/atc/engine/enginefakes"
+	"github.com/concourse/atc/lockrunner/lockrunnerfakes"
 	"github.com/concourse/atc/worker/workerfakes"
 	"github.com/concourse/atc/wrappa"
 )
@@ -54,6 +68,16 @@ var (
 	teamDBFactory                 *dbfakes.FakeTeamDBFactory
 	teamDB                        *dbfakes.FakeTeamDB
 	pipelinesDB                   *dbfakes.FakePipelinesDB
+	auditDB                       *auditserverfakes.FakeAuditDB
+	webhookDB                     *hookserverfakes.FakeWebhookDB
+	apiTokenDB                    *tokenserverfakes.FakeAPITokenDB
+	leaderDB                      *infoserverfakes.FakeLeaderDB
+	resourceTypesDB               *resourcetypeserverfakes.FakeResourceTypesDB
+	gcDB                          *gcserverfakes.FakeGCDB
+	usageDB                       *usageserverfakes.FakeUsageDB
+	queueDB                       *queueserverfakes.FakeQueueDB
+	alertsDB                      *alertserverfakes.FakeAlertsDB
+	fakeGCCollector               *lockrunnerfakes.FakeTask
 	buildsDB                      *authfakes.FakeBuildsDB
 	buildServerDB                 *buildserverfakes.FakeBuildsDB
 	build                         *dbfakes.FakeBuild
@@ -61,9 +85,16 @@ var (
 	fakeScannerFactory            *resourceserverfakes.FakeScannerFactory
 	configValidationErrorMessages []string
 	configValidationWarnings      []config.Warning
+	fakeCredsManager              *credsfakes.FakeManager
 	peerAddr                      string
 	drain                         chan struct{}
+	drainGracePeriod              time.Duration
+	fakeArtifactStore             *buildserverfakes.FakeArtifactStore
+	fakeTaskCaches                *jobserverfakes.FakeTaskCacheInvalidator
+	fakeBuildReconciler           *buildserverfakes.FakeBuildReconciler
 	cliDownloadsDir               string
+	cliDownloadURL                string
+	cliDownloadServer             *ghttp.Server
 	logger                        *lagertest.TestLogger
 
 	constructedEventHandler *fakeEventHandlerFactory
@@ -104,6 +135,16 @@ var _ = BeforeEach(func() {
 	volumesDB = new(volumeserverfakes.FakeVolumesDB)
 	pipeDB = new(pipesfakes.FakePipeDB)
 	pipelinesDB = new(dbfakes.FakePipelinesDB)
+	auditDB = new(auditserverfakes.FakeAuditDB)
+	webhookDB = new(hookserverfakes.FakeWebhookDB)
+	apiTokenDB = new(tokenserverfakes.FakeAPITokenDB)
+	leaderDB = new(infoserverfakes.FakeLeaderDB)
+	resourceTypesDB = new(resourcetypeserverfakes.FakeResourceTypesDB)
+	gcDB = new(gcserverfakes.FakeGCDB)
+	usageDB = new(usageserverfakes.FakeUsageDB)
+	queueDB = new(queueserverfakes.FakeQueueDB)
+	alertsDB = new(alertserverfakes.FakeAlertsDB)
+	fakeGCCollector = new(lockrunnerfakes.FakeTask)
 	buildsDB = new(authfakes.FakeBuildsDB)
 
 	authValidator = new(authfakes.FakeValidator)
@@ -113,8 +154,15 @@ var _ = BeforeEach(func() {
 
 	configValidationErrorMessages = []string{}
 	configValidationWarnings = []config.Warning{}
+
+	fakeCredsManager = new(credsfakes.FakeManager)
 	peerAddr = "127.0.0.1:1234"
 	drain = make(chan struct{})
+	drainGracePeriod = time.Second
+
+	fakeArtifactStore = new(buildserverfakes.FakeArtifactStore)
+	fakeTaskCaches = new(jobserverfakes.FakeTaskCacheInvalidator)
+	fakeBuildReconciler = new(buildserverfakes.FakeBuildReconciler)
 
 	fakeEngine = new(enginefakes.FakeEngine)
 	fakeWorkerClient = new(workerfakes.FakeClient)
@@ -127,6 +175,9 @@ var _ = BeforeEach(func() {
 	cliDownloadsDir, err = ioutil.TempDir("", "cli-downloads")
 	Expect(err).NotTo(HaveOccurred())
 
+	cliDownloadServer = ghttp.NewServer()
+	cliDownloadURL = cliDownloadServer.URL() + "/fly/v{version}/fly_{platform}_{arch}"
+
 	constructedEventHandler = &fakeEventHandlerFactory{}
 
 	logger = lagertest.NewTestLogger("callbacks")
@@ -170,23 +221,45 @@ var _ = BeforeEach(func() {
 		volumesDB,
 		pipeDB,
 		pipelinesDB,
+		auditDB,
+		webhookDB,
+		apiTokenDB,
+		leaderDB,
+		resourceTypesDB,
+		gcDB,
+		usageDB,
+		queueDB,
+		alertsDB,
 
 		func(atc.Config) ([]config.Warning, []string) {
 			return configValidationWarnings, configValidationErrorMessages
 		},
+		fakeCredsManager,
 		peerAddr,
 		constructedEventHandler.Construct,
+		buildserver.CensorPolicies{},
+		nil,
 		drain,
+		drainGracePeriod,
+		buildserver.KeepAlivePolicy{},
+		fakeArtifactStore,
+		fakeTaskCaches,
+		fakeBuildReconciler,
 
 		fakeEngine,
 		fakeWorkerClient,
+		http.DefaultClient,
 
 		fakeSchedulerFactory,
 		fakeScannerFactory,
 
+		fakeGCCollector,
+
 		sink,
 
 		cliDownloadsDir,
+		cliDownloadURL,
+		http.DefaultClient,
 		"1.2.3",
 	)
 	Expect(err).NotTo(HaveOccurred())
@@ -200,6 +273,7 @@ var _ = BeforeEach(func() {
 
 var _ = AfterEach(func() {
 	server.Close()
+	cliDownloadServer.Close()
 })
 
 func TestAPI(t *testing.T) {
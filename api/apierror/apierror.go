@@ -0,0 +1,66 @@
+package apierror
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/concourse/atc"
+)
+
+// Write responds with the given error as the JSON body. Every API server
+// reports failures this way so that clients can tell them apart by code.
+func Write(w http.ResponseWriter, status int, apiErr atc.APIError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(apiErr)
+}
+
+// NotFound is for when the resource a request refers to does not exist.
+func NotFound(w http.ResponseWriter, message string) {
+	Write(w, http.StatusNotFound, atc.APIError{
+		Code:    atc.ErrorCodeNotFound,
+		Message: message,
+	})
+}
+
+// Unauthorized is for requests that need to be authenticated but are not.
+func Unauthorized(w http.ResponseWriter, message string) {
+	Write(w, http.StatusUnauthorized, atc.APIError{
+		Code:    atc.ErrorCodeUnauthorized,
+		Message: message,
+	})
+}
+
+// Forbidden is for authenticated requests that are not allowed to do what
+// they are asking.
+func Forbidden(w http.ResponseWriter, message string) {
+	Write(w, http.StatusForbidden, atc.APIError{
+		Code:    atc.ErrorCodeForbidden,
+		Message: message,
+	})
+}
+
+// DBFailure is for when reading from or writing to the database fails.
+func DBFailure(w http.ResponseWriter, message string) {
+	Write(w, http.StatusInternalServerError, atc.APIError{
+		Code:    atc.ErrorCodeDBFailure,
+		Message: message,
+	})
+}
+
+// BuilderFailure is for when the engine, scheduler, or a worker fails to
+// carry out what was asked of it.
+func BuilderFailure(w http.ResponseWriter, message string) {
+	Write(w, http.StatusInternalServerError, atc.APIError{
+		Code:    atc.ErrorCodeBuilderFailure,
+		Message: message,
+	})
+}
+
+// Internal is for any other failure on the server's side.
+func Internal(w http.ResponseWriter, message string) {
+	Write(w, http.StatusInternalServerError, atc.APIError{
+		Code:    atc.ErrorCodeInternal,
+		Message: message,
+	})
+}
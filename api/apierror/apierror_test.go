@@ -0,0 +1,61 @@
+package apierror_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Write", func() {
+	var recorder *httptest.ResponseRecorder
+
+	BeforeEach(func() {
+		recorder = httptest.NewRecorder()
+	})
+
+	It("writes the status and the error as JSON", func() {
+		apierror.Write(recorder, http.StatusConflict, atc.APIError{
+			Code:    atc.ErrorCodeInternal,
+			Message: "something conflicted",
+			Details: map[string]string{"handle": "some-handle"},
+		})
+
+		Expect(recorder.Code).To(Equal(http.StatusConflict))
+		Expect(recorder.Header().Get("Content-Type")).To(Equal("application/json"))
+		Expect(recorder.Body.String()).To(MatchJSON(`{
+			"code": "internal",
+			"message": "something conflicted",
+			"details": {"handle": "some-handle"}
+		}`))
+	})
+
+	It("omits details when there are none", func() {
+		apierror.Write(recorder, http.StatusInternalServerError, atc.APIError{
+			Code:    atc.ErrorCodeInternal,
+			Message: "oops",
+		})
+
+		Expect(recorder.Body.String()).To(MatchJSON(`{"code":"internal","message":"oops"}`))
+	})
+
+	DescribeTable("helpers",
+		func(write func(http.ResponseWriter, string), status int, code atc.ErrorCode) {
+			write(recorder, "some message")
+
+			Expect(recorder.Code).To(Equal(status))
+			Expect(recorder.Body.String()).To(MatchJSON(`{"code":"` + string(code) + `","message":"some message"}`))
+		},
+		Entry("NotFound", apierror.NotFound, http.StatusNotFound, atc.ErrorCodeNotFound),
+		Entry("Unauthorized", apierror.Unauthorized, http.StatusUnauthorized, atc.ErrorCodeUnauthorized),
+		Entry("Forbidden", apierror.Forbidden, http.StatusForbidden, atc.ErrorCodeForbidden),
+		Entry("DBFailure", apierror.DBFailure, http.StatusInternalServerError, atc.ErrorCodeDBFailure),
+		Entry("BuilderFailure", apierror.BuilderFailure, http.StatusInternalServerError, atc.ErrorCodeBuilderFailure),
+		Entry("Internal", apierror.Internal, http.StatusInternalServerError, atc.ErrorCodeInternal),
+	)
+})
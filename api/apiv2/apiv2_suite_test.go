@@ -0,0 +1,13 @@
+package apiv2_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestAPIV2(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "API V2 Suite")
+}
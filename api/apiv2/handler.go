@@ -0,0 +1,282 @@
+package apiv2
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/concourse/atc"
+)
+
+// shape is how a route's v2 response differs from its v1 one, beyond being
+// wrapped in an envelope.
+type shape struct {
+	// paginate is for routes whose v1 handler responds with everything at
+	// once; v2 responds with a page of it at a time
+	paginate bool
+
+	// addFields adds v2's new fields to each object responded with
+	addFields func(map[string]interface{})
+}
+
+type handler struct {
+	externalURL string
+	handler     http.Handler
+	shape       shape
+}
+
+var linkPattern = regexp.MustCompile(`<([^>]*)>\s*;\s*rel="([^"]*)"`)
+
+func (h handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	contentType, acceptable := negotiate(r.Header.Get("Accept"))
+	if !acceptable {
+		writeError(w, "application/json", http.StatusNotAcceptable, atc.APIError{
+			Code:    atc.ErrorCodeNotAcceptable,
+			Message: "responses can only be sent as " + atc.V2MediaType + " or application/json",
+		})
+		return
+	}
+
+	var limit, offset int
+	if h.shape.paginate {
+		var err error
+		limit, offset, err = pageOf(r.URL.Query())
+		if err != nil {
+			writeError(w, contentType, http.StatusBadRequest, atc.APIError{
+				Code:    atc.ErrorCodeBadRequest,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	// the response is read before it's reshaped, so it mustn't be compressed
+	v1Request := r.WithContext(r.Context())
+	v1Request.Header = http.Header{}
+	for name, values := range r.Header {
+		if name != "Accept-Encoding" {
+			v1Request.Header[name] = values
+		}
+	}
+
+	recorded := newRecorder()
+	h.handler.ServeHTTP(recorded, v1Request)
+
+	for name, values := range recorded.header {
+		switch name {
+		case "Content-Type", "Content-Length", "Content-Encoding", "Link", "Vary":
+		default:
+			w.Header()[name] = values
+		}
+	}
+
+	w.Header().Set("Vary", "Accept")
+
+	if recorded.status == http.StatusNotModified || recorded.status == http.StatusNoContent {
+		w.WriteHeader(recorded.status)
+		return
+	}
+
+	if recorded.status >= 400 {
+		writeError(w, contentType, recorded.status, errorFrom(recorded))
+		return
+	}
+
+	decoder := json.NewDecoder(&recorded.body)
+	decoder.UseNumber()
+
+	var data interface{}
+	err := decoder.Decode(&data)
+	if err != nil {
+		writeError(w, contentType, http.StatusInternalServerError, atc.APIError{
+			Code:    atc.ErrorCodeInternal,
+			Message: "failed to read response",
+		})
+		return
+	}
+
+	var pagination atc.EnvelopePagination
+
+	for _, link := range recorded.header["Link"] {
+		for _, match := range linkPattern.FindAllStringSubmatch(link, -1) {
+			href := strings.Replace(match[1], "/api/v1/", "/api/v2/", 1)
+
+			switch match[2] {
+			case atc.LinkRelNext:
+				pagination.Next = href
+			case atc.LinkRelPrevious:
+				pagination.Previous = href
+			}
+		}
+	}
+
+	if h.shape.paginate {
+		// handlers encode empty lists as null as often as not
+		if items, ok := data.([]interface{}); ok || data == nil {
+			data, pagination = h.page(r.URL, items, limit, offset)
+		}
+	}
+
+	if h.shape.addFields != nil {
+		addFields(data, h.shape.addFields)
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		writeError(w, contentType, http.StatusInternalServerError, atc.APIError{
+			Code:    atc.ErrorCodeInternal,
+			Message: "failed to write response",
+		})
+		return
+	}
+
+	envelope := atc.Envelope{Data: payload}
+
+	if pagination.Next != "" {
+		w.Header().Add("Link", `<`+pagination.Next+`>; rel="`+atc.LinkRelNext+`"`)
+	}
+
+	if pagination.Previous != "" {
+		w.Header().Add("Link", `<`+pagination.Previous+`>; rel="`+atc.LinkRelPrevious+`"`)
+	}
+
+	if pagination != (atc.EnvelopePagination{}) {
+		envelope.Pagination = &pagination
+	}
+
+	writeEnvelope(w, contentType, recorded.status, envelope)
+}
+
+func (h handler) page(requestURL *url.URL, items []interface{}, limit int, offset int) (interface{}, atc.EnvelopePagination) {
+	var pagination atc.EnvelopePagination
+
+	linkTo := func(offset int) string {
+		query := requestURL.Query()
+		query.Set(atc.PaginationQueryLimit, strconv.Itoa(limit))
+		query.Set(atc.PaginationQueryOffset, strconv.Itoa(offset))
+		return h.externalURL + requestURL.Path + "?" + query.Encode()
+	}
+
+	if offset > 0 {
+		previous := offset - limit
+		if previous < 0 {
+			previous = 0
+		}
+
+		pagination.Previous = linkTo(previous)
+	}
+
+	if offset >= len(items) {
+		return []interface{}{}, pagination
+	}
+
+	end := offset + limit
+	if end < len(items) {
+		pagination.Next = linkTo(end)
+	} else {
+		end = len(items)
+	}
+
+	return items[offset:end], pagination
+}
+
+func pageOf(query url.Values) (int, int, error) {
+	limit := atc.PaginationAPIDefaultLimit
+	offset := 0
+
+	if value := query.Get(atc.PaginationQueryLimit); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			return 0, 0, errInvalidQuery(atc.PaginationQueryLimit)
+		}
+
+		limit = parsed
+		if limit > atc.PaginationAPIMaxLimit {
+			limit = atc.PaginationAPIMaxLimit
+		}
+	}
+
+	if value := query.Get(atc.PaginationQueryOffset); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return 0, 0, errInvalidQuery(atc.PaginationQueryOffset)
+		}
+
+		offset = parsed
+	}
+
+	return limit, offset, nil
+}
+
+type errInvalidQuery string
+
+func (err errInvalidQuery) Error() string {
+	return "invalid " + string(err)
+}
+
+func addFields(data interface{}, add func(map[string]interface{})) {
+	switch data := data.(type) {
+	case map[string]interface{}:
+		add(data)
+	case []interface{}:
+		for _, item := range data {
+			if object, ok := item.(map[string]interface{}); ok {
+				add(object)
+			}
+		}
+	}
+}
+
+// errorFrom keeps the error a v1 handler responded with, if it was one of
+// the API's own, and otherwise makes one of its status and body.
+func errorFrom(recorded *recorder) atc.APIError {
+	var apiErr atc.APIError
+	err := json.Unmarshal(recorded.body.Bytes(), &apiErr)
+	if err == nil && apiErr.Code != "" {
+		return apiErr
+	}
+
+	message := strings.TrimSpace(recorded.body.String())
+	if message == "" {
+		message = strings.ToLower(http.StatusText(recorded.status))
+	}
+
+	return atc.APIError{
+		Code:    errorCodeFor(recorded.status),
+		Message: message,
+	}
+}
+
+func errorCodeFor(status int) atc.ErrorCode {
+	switch status {
+	case http.StatusBadRequest:
+		return atc.ErrorCodeBadRequest
+	case http.StatusUnauthorized:
+		return atc.ErrorCodeUnauthorized
+	case http.StatusForbidden:
+		return atc.ErrorCodeForbidden
+	case http.StatusNotFound:
+		return atc.ErrorCodeNotFound
+	case http.StatusTooManyRequests:
+		return atc.ErrorCodeRateLimited
+	default:
+		return atc.ErrorCodeInternal
+	}
+}
+
+func writeError(w http.ResponseWriter, contentType string, status int, apiErr atc.APIError) {
+	writeEnvelope(w, contentType, status, atc.Envelope{Error: &apiErr})
+}
+
+func writeEnvelope(w http.ResponseWriter, contentType string, status int, envelope atc.Envelope) {
+	var body bytes.Buffer
+	json.NewEncoder(&body).Encode(envelope)
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	body.WriteTo(w)
+}
@@ -0,0 +1,57 @@
+package apiv2
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/concourse/atc"
+)
+
+// negotiate picks what to respond with from the request's Accept header. It
+// returns false if the client accepts neither v2's own media type nor plain
+// JSON.
+func negotiate(accept string) (string, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return "application/json", true
+	}
+
+	var best string
+	var bestQ float64
+
+	for _, mediaRange := range strings.Split(accept, ",") {
+		params := strings.Split(mediaRange, ";")
+
+		q := 1.0
+		for _, param := range params[1:] {
+			pair := strings.SplitN(param, "=", 2)
+			if len(pair) == 2 && strings.TrimSpace(pair[0]) == "q" {
+				parsed, err := strconv.ParseFloat(strings.TrimSpace(pair[1]), 64)
+				if err == nil {
+					q = parsed
+				}
+			}
+		}
+
+		if q <= 0 {
+			continue
+		}
+
+		var contentType string
+		switch strings.ToLower(strings.TrimSpace(params[0])) {
+		case atc.V2MediaType:
+			contentType = atc.V2MediaType
+		case "application/json", "application/*", "*/*":
+			contentType = "application/json"
+		default:
+			continue
+		}
+
+		// the v2 media type wins ties, as it's the more specific
+		if q > bestQ || (q == bestQ && contentType == atc.V2MediaType) {
+			best = contentType
+			bestQ = q
+		}
+	}
+
+	return best, best != ""
+}
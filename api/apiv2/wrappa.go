@@ -0,0 +1,79 @@
+package apiv2
+
+import (
+	"encoding/json"
+
+	"github.com/concourse/atc"
+	"github.com/tedsuo/rata"
+)
+
+var shapes = map[string]shape{
+	atc.ListTeams:        {paginate: true},
+	atc.ListAllPipelines: {paginate: true},
+	atc.ListPipelines:    {paginate: true},
+	atc.ListJobs:         {paginate: true},
+	atc.ListResources:    {paginate: true},
+	atc.ListWorkers:      {paginate: true},
+
+	atc.ListBuilds:    {addFields: addBuildDuration},
+	atc.ListJobBuilds: {addFields: addBuildDuration},
+	atc.GetBuild:      {addFields: addBuildDuration},
+}
+
+// Wrappa serves the handlers of the routes in atc.V2Routes with their
+// responses reshaped for v2: enveloped, paginated, and with new fields
+// added, leaving the handlers themselves, and so v1, as they are.
+type Wrappa struct {
+	externalURL string
+}
+
+func NewWrappa(externalURL string) Wrappa {
+	return Wrappa{
+		externalURL: externalURL,
+	}
+}
+
+func (wrappa Wrappa) Wrap(handlers rata.Handlers) rata.Handlers {
+	wrapped := rata.Handlers{}
+
+	for _, route := range atc.V2Routes {
+		v1Handler, found := handlers[route.Name]
+		if !found {
+			// left for rata to complain about
+			continue
+		}
+
+		wrapped[route.Name] = handler{
+			externalURL: wrappa.externalURL,
+			handler:     v1Handler,
+			shape:       shapes[route.Name],
+		}
+	}
+
+	return wrapped
+}
+
+// addBuildDuration adds how many seconds a finished build took.
+func addBuildDuration(build map[string]interface{}) {
+	start, found := build["start_time"].(json.Number)
+	if !found {
+		return
+	}
+
+	end, found := build["end_time"].(json.Number)
+	if !found {
+		return
+	}
+
+	startTime, err := start.Int64()
+	if err != nil {
+		return
+	}
+
+	endTime, err := end.Int64()
+	if err != nil {
+		return
+	}
+
+	build["duration"] = endTime - startTime
+}
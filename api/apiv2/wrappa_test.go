@@ -0,0 +1,287 @@
+package apiv2_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/api/apiv2"
+	"github.com/tedsuo/rata"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Wrappa", func() {
+	var (
+		v1Handler http.HandlerFunc
+		route     string
+
+		request  *http.Request
+		recorder *httptest.ResponseRecorder
+		envelope atc.Envelope
+	)
+
+	respondWith := func(status int, body string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			fmt.Fprint(w, body)
+		}
+	}
+
+	BeforeEach(func() {
+		route = atc.ListTeams
+		v1Handler = respondWith(http.StatusOK, `[{"name":"a"},{"name":"b"},{"name":"c"}]`)
+
+		var err error
+		request, err = http.NewRequest("GET", "/api/v2/teams", nil)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		handlers := rata.Handlers{}
+		for _, r := range atc.V2Routes {
+			handlers[r.Name] = http.NotFoundHandler()
+		}
+
+		handlers[route] = v1Handler
+
+		wrapped := apiv2.NewWrappa("https://example.com").Wrap(handlers)
+
+		recorder = httptest.NewRecorder()
+		wrapped[route].ServeHTTP(recorder, request)
+
+		envelope = atc.Envelope{}
+		if recorder.Code != http.StatusNotModified {
+			err := json.Unmarshal(recorder.Body.Bytes(), &envelope)
+			Expect(err).NotTo(HaveOccurred())
+		}
+	})
+
+	It("wraps only the v2 routes", func() {
+		wrapped := apiv2.NewWrappa("https://example.com").Wrap(rata.Handlers{
+			atc.ListTeams: v1Handler,
+			atc.SetTeam:   v1Handler,
+		})
+
+		Expect(wrapped).To(HaveKey(atc.ListTeams))
+		Expect(wrapped).NotTo(HaveKey(atc.SetTeam))
+	})
+
+	It("puts what the v1 handler responded with in an envelope", func() {
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(recorder.Header().Get("Content-Type")).To(Equal("application/json"))
+		Expect(envelope.Data).To(MatchJSON(`[{"name":"a"},{"name":"b"},{"name":"c"}]`))
+		Expect(envelope.Pagination).To(BeNil())
+		Expect(envelope.Error).To(BeNil())
+	})
+
+	Describe("content negotiation", func() {
+		Context("when the v2 media type is asked for", func() {
+			BeforeEach(func() {
+				request.Header.Set("Accept", "application/json;q=0.5, "+atc.V2MediaType)
+			})
+
+			It("responds with it", func() {
+				Expect(recorder.Code).To(Equal(http.StatusOK))
+				Expect(recorder.Header().Get("Content-Type")).To(Equal(atc.V2MediaType))
+				Expect(recorder.Header().Get("Vary")).To(Equal("Accept"))
+			})
+		})
+
+		Context("when anything is accepted", func() {
+			BeforeEach(func() {
+				request.Header.Set("Accept", "text/html, */*;q=0.1")
+			})
+
+			It("responds with plain JSON", func() {
+				Expect(recorder.Code).To(Equal(http.StatusOK))
+				Expect(recorder.Header().Get("Content-Type")).To(Equal("application/json"))
+			})
+		})
+
+		Context("when neither JSON nor the v2 media type is accepted", func() {
+			BeforeEach(func() {
+				request.Header.Set("Accept", "text/html, application/json;q=0")
+			})
+
+			It("responds with 406 Not Acceptable", func() {
+				Expect(recorder.Code).To(Equal(http.StatusNotAcceptable))
+				Expect(envelope.Error.Code).To(Equal(atc.ErrorCodeNotAcceptable))
+			})
+		})
+	})
+
+	Describe("pagination", func() {
+		Context("when a page is asked for", func() {
+			BeforeEach(func() {
+				request.URL.RawQuery = "limit=1&offset=1"
+			})
+
+			It("responds with that page, linking to either side of it", func() {
+				Expect(envelope.Data).To(MatchJSON(`[{"name":"b"}]`))
+				Expect(envelope.Pagination).To(Equal(&atc.EnvelopePagination{
+					Next:     "https://example.com/api/v2/teams?limit=1&offset=2",
+					Previous: "https://example.com/api/v2/teams?limit=1&offset=0",
+				}))
+
+				Expect(recorder.Header()["Link"]).To(ConsistOf(
+					`<https://example.com/api/v2/teams?limit=1&offset=2>; rel="next"`,
+					`<https://example.com/api/v2/teams?limit=1&offset=0>; rel="previous"`,
+				))
+			})
+		})
+
+		Context("when the last page is asked for", func() {
+			BeforeEach(func() {
+				request.URL.RawQuery = "limit=2&offset=2"
+			})
+
+			It("only links to the page before", func() {
+				Expect(envelope.Data).To(MatchJSON(`[{"name":"c"}]`))
+				Expect(envelope.Pagination).To(Equal(&atc.EnvelopePagination{
+					Previous: "https://example.com/api/v2/teams?limit=2&offset=0",
+				}))
+			})
+		})
+
+		Context("when the page is past the end", func() {
+			BeforeEach(func() {
+				request.URL.RawQuery = "offset=10"
+			})
+
+			It("responds with an empty list", func() {
+				Expect(envelope.Data).To(MatchJSON(`[]`))
+			})
+		})
+
+		Context("when the v1 handler responds with null", func() {
+			BeforeEach(func() {
+				v1Handler = respondWith(http.StatusOK, `null`)
+			})
+
+			It("responds with an empty list", func() {
+				Expect(envelope.Data).To(MatchJSON(`[]`))
+			})
+		})
+
+		Context("when the limit is invalid", func() {
+			BeforeEach(func() {
+				request.URL.RawQuery = "limit=0"
+			})
+
+			It("responds with 400 Bad Request", func() {
+				Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				Expect(envelope.Error.Code).To(Equal(atc.ErrorCodeBadRequest))
+			})
+		})
+
+		Context("when the v1 handler paginates", func() {
+			BeforeEach(func() {
+				route = atc.ListBuilds
+				v1Handler = func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Add("Link", `<https://example.com/api/v1/builds?since=3&limit=2>; rel="next"`)
+					w.Header().Add("Link", `<https://example.com/api/v1/builds?until=4&limit=2>; rel="previous"`)
+					respondWith(http.StatusOK, `[]`)(w, r)
+				}
+			})
+
+			It("links to the v2 pages", func() {
+				Expect(envelope.Pagination).To(Equal(&atc.EnvelopePagination{
+					Next:     "https://example.com/api/v2/builds?since=3&limit=2",
+					Previous: "https://example.com/api/v2/builds?until=4&limit=2",
+				}))
+			})
+		})
+	})
+
+	Describe("new fields", func() {
+		BeforeEach(func() {
+			route = atc.GetBuild
+			v1Handler = respondWith(http.StatusOK, `{"id":12,"start_time":100,"end_time":160}`)
+		})
+
+		It("adds how long builds took", func() {
+			Expect(envelope.Data).To(MatchJSON(`{"id":12,"start_time":100,"end_time":160,"duration":60}`))
+		})
+
+		Context("when the build hasn't finished", func() {
+			BeforeEach(func() {
+				v1Handler = respondWith(http.StatusOK, `{"id":12,"start_time":100}`)
+			})
+
+			It("leaves the duration out", func() {
+				Expect(envelope.Data).To(MatchJSON(`{"id":12,"start_time":100}`))
+			})
+		})
+	})
+
+	Describe("errors", func() {
+		Context("when the v1 handler responds with an API error", func() {
+			BeforeEach(func() {
+				v1Handler = func(w http.ResponseWriter, r *http.Request) {
+					apierror.NotFound(w, "team not found")
+				}
+			})
+
+			It("keeps it", func() {
+				Expect(recorder.Code).To(Equal(http.StatusNotFound))
+				Expect(envelope.Data).To(BeEmpty())
+				Expect(envelope.Error).To(Equal(&atc.APIError{
+					Code:    atc.ErrorCodeNotFound,
+					Message: "team not found",
+				}))
+			})
+		})
+
+		Context("when the v1 handler only responds with a status", func() {
+			BeforeEach(func() {
+				v1Handler = func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusForbidden)
+				}
+			})
+
+			It("makes an error of it", func() {
+				Expect(recorder.Code).To(Equal(http.StatusForbidden))
+				Expect(envelope.Error).To(Equal(&atc.APIError{
+					Code:    atc.ErrorCodeForbidden,
+					Message: "forbidden",
+				}))
+			})
+		})
+	})
+
+	Context("when the request asks for a compressed response", func() {
+		BeforeEach(func() {
+			request.Header.Set("Accept-Encoding", "gzip")
+			v1Handler = func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.Header.Get("Accept-Encoding")).To(BeEmpty())
+				respondWith(http.StatusOK, `[]`)(w, r)
+			}
+		})
+
+		It("asks the v1 handler for an uncompressed one, as it has to read it", func() {
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(request.Header.Get("Accept-Encoding")).To(Equal("gzip"))
+		})
+	})
+
+	Context("when the v1 handler responds with 304 Not Modified", func() {
+		BeforeEach(func() {
+			v1Handler = func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("ETag", `W/"abc"`)
+				w.WriteHeader(http.StatusNotModified)
+			}
+		})
+
+		It("passes it on without a body", func() {
+			Expect(recorder.Code).To(Equal(http.StatusNotModified))
+			Expect(recorder.Header().Get("ETag")).To(Equal(`W/"abc"`))
+			Expect(recorder.Body.Len()).To(BeZero())
+		})
+	})
+})
@@ -0,0 +1,134 @@
+package api_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/concourse/atc/db"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Audit API", func() {
+	Describe("GET /api/v1/audit", func() {
+		var (
+			query    string
+			response *http.Response
+		)
+
+		BeforeEach(func() {
+			query = ""
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			response, err = client.Get(server.URL + "/api/v1/audit" + query)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when not authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns 401", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+
+		Context("when authenticated as a team that isn't an admin", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("some-team", 5, false, true)
+			})
+
+			It("returns 403", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+				Expect(auditDB.GetAuditEventsCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when authenticated as an admin", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("main", 1, true, true)
+
+				auditDB.GetAuditEventsReturns([]db.AuditEvent{
+					{
+						ID:         2,
+						Time:       time.Unix(100, 0),
+						Actor:      "team:some-team",
+						Action:     "AbortBuild",
+						Resource:   "/api/v1/builds/42/abort",
+						BodySHA256: "some-sha",
+						Status:     204,
+					},
+				}, nil)
+			})
+
+			It("returns the events", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+				Expect(response.Header.Get("Content-Type")).To(Equal("application/json"))
+
+				body, err := ioutil.ReadAll(response.Body)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(body).To(MatchJSON(`[
+					{
+						"id": 2,
+						"time": 100,
+						"actor": "team:some-team",
+						"action": "AbortBuild",
+						"resource": "/api/v1/builds/42/abort",
+						"body_sha256": "some-sha",
+						"status": 204
+					}
+				]`))
+			})
+
+			It("returns the newest page of every event by default", func() {
+				Expect(auditDB.GetAuditEventsArgsForCall(0)).To(Equal(db.AuditEventFilter{
+					Limit: 100,
+				}))
+			})
+
+			Context("when filtering", func() {
+				BeforeEach(func() {
+					query = "?actor=system&action=SaveConfig&until=10&limit=5"
+				})
+
+				It("passes the filter along", func() {
+					Expect(auditDB.GetAuditEventsArgsForCall(0)).To(Equal(db.AuditEventFilter{
+						Actor:  "system",
+						Action: "SaveConfig",
+						Until:  10,
+						Limit:  5,
+					}))
+				})
+			})
+
+			Context("when the limit is too large", func() {
+				BeforeEach(func() {
+					query = "?limit=100000"
+				})
+
+				It("caps it", func() {
+					Expect(auditDB.GetAuditEventsArgsForCall(0).Limit).To(Equal(1000))
+				})
+			})
+
+			Context("when getting the events fails", func() {
+				BeforeEach(func() {
+					auditDB.GetAuditEventsReturns(nil, errors.New("nope"))
+				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+	})
+})
@@ -0,0 +1,79 @@
+// This file was generated by counterfeiter
+package auditserverfakes
+
+import (
+	"sync"
+
+	"github.com/concourse/atc/api/auditserver"
+	"github.com/concourse/atc/db"
+)
+
+type FakeAuditDB struct {
+	GetAuditEventsStub        func(arg1 db.AuditEventFilter) ([]db.AuditEvent, error)
+	getAuditEventsMutex       sync.RWMutex
+	getAuditEventsArgsForCall []struct {
+		arg1 db.AuditEventFilter
+	}
+	getAuditEventsReturns struct {
+		result1 []db.AuditEvent
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeAuditDB) GetAuditEvents(arg1 db.AuditEventFilter) ([]db.AuditEvent, error) {
+	fake.getAuditEventsMutex.Lock()
+	fake.getAuditEventsArgsForCall = append(fake.getAuditEventsArgsForCall, struct {
+		arg1 db.AuditEventFilter
+	}{arg1})
+	fake.recordInvocation("GetAuditEvents", []interface{}{arg1})
+	fake.getAuditEventsMutex.Unlock()
+	if fake.GetAuditEventsStub != nil {
+		return fake.GetAuditEventsStub(arg1)
+	} else {
+		return fake.getAuditEventsReturns.result1, fake.getAuditEventsReturns.result2
+	}
+}
+
+func (fake *FakeAuditDB) GetAuditEventsCallCount() int {
+	fake.getAuditEventsMutex.RLock()
+	defer fake.getAuditEventsMutex.RUnlock()
+	return len(fake.getAuditEventsArgsForCall)
+}
+
+func (fake *FakeAuditDB) GetAuditEventsArgsForCall(i int) db.AuditEventFilter {
+	fake.getAuditEventsMutex.RLock()
+	defer fake.getAuditEventsMutex.RUnlock()
+	return fake.getAuditEventsArgsForCall[i].arg1
+}
+
+func (fake *FakeAuditDB) GetAuditEventsReturns(result1 []db.AuditEvent, result2 error) {
+	fake.GetAuditEventsStub = nil
+	fake.getAuditEventsReturns = struct {
+		result1 []db.AuditEvent
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAuditDB) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.getAuditEventsMutex.RLock()
+	defer fake.getAuditEventsMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeAuditDB) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ auditserver.AuditDB = new(FakeAuditDB)
@@ -0,0 +1,47 @@
+package auditserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/api/present"
+	"github.com/concourse/atc/db"
+)
+
+func (s *Server) ListAuditEvents(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.Session("list-audit-events")
+
+	until, _ := strconv.Atoi(r.FormValue(atc.PaginationQueryUntil))
+
+	limit, _ := strconv.Atoi(r.FormValue(atc.PaginationQueryLimit))
+	if limit <= 0 {
+		limit = atc.PaginationAPIDefaultLimit
+	}
+
+	if limit > atc.PaginationAPIMaxLimit {
+		limit = atc.PaginationAPIMaxLimit
+	}
+
+	savedEvents, err := s.db.GetAuditEvents(db.AuditEventFilter{
+		Actor:  r.FormValue("actor"),
+		Action: r.FormValue("action"),
+		Until:  until,
+		Limit:  limit,
+	})
+	if err != nil {
+		logger.Error("failed-to-get-audit-events", err)
+		apierror.DBFailure(w, "failed to get audit events")
+		return
+	}
+
+	events := make([]atc.AuditEvent, len(savedEvents))
+	for i, event := range savedEvents {
+		events[i] = present.AuditEvent(event)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(events)
+}
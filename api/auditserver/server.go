@@ -0,0 +1,28 @@
+package auditserver
+
+import (
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/db"
+)
+
+type Server struct {
+	logger lager.Logger
+
+	db AuditDB
+}
+
+//go:generate counterfeiter . AuditDB
+
+type AuditDB interface {
+	GetAuditEvents(db.AuditEventFilter) ([]db.AuditEvent, error)
+}
+
+func NewServer(
+	logger lager.Logger,
+	db AuditDB,
+) *Server {
+	return &Server{
+		logger: logger,
+		db:     db,
+	}
+}
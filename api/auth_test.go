@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/concourse/atc"
 	"github.com/concourse/atc/db"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -96,11 +97,24 @@ var _ = Describe("Auth API", func() {
 
 						Expect(body).To(MatchJSON(`{"type":"some type","value":"some value"}`))
 
-						expiration, teamName, teamID, isAdmin := fakeTokenGenerator.GenerateTokenArgsForCall(0)
+						expiration, teamName, teamID, isAdmin, role := fakeTokenGenerator.GenerateTokenArgsForCall(0)
 						Expect(expiration).To(BeTemporally("~", time.Now().Add(24*time.Hour), time.Minute))
 						Expect(teamName).To(Equal(savedTeam.Name))
 						Expect(teamID).To(Equal(savedTeam.ID))
 						Expect(isAdmin).To(Equal(savedTeam.Admin))
+						Expect(role).To(Equal(atc.RoleAdmin))
+					})
+
+					Context("when the team gives basic auth users a role", func() {
+						BeforeEach(func() {
+							savedTeam.Roles.BasicAuth = atc.RoleOperator
+							teamDB.GetTeamReturns(savedTeam, true, nil)
+						})
+
+						It("generates a token with that role", func() {
+							_, _, _, _, role := fakeTokenGenerator.GenerateTokenArgsForCall(0)
+							Expect(role).To(Equal(atc.RoleOperator))
+						})
 					})
 				})
 
@@ -171,6 +185,15 @@ var _ = Describe("Auth API", func() {
 							ClientSecret: "client-secret",
 							DisplayName:  "custom secure auth",
 						},
+						OIDCAuth: &db.OIDCAuth{
+							ClientID:     "client-id",
+							ClientSecret: "client-secret",
+							DisplayName:  "Google",
+						},
+						SAMLAuth: &db.SAMLAuth{
+							DisplayName: "Okta",
+							SSOURL:      "https://okta.example.com/sso",
+						},
 					},
 				}
 
@@ -210,6 +233,16 @@ var _ = Describe("Auth API", func() {
 						"display_name": "GitHub",
 						"auth_url": "https://oauth.example.com/auth/github?team_name=some-team"
 					},
+					{
+						"type": "oauth",
+						"display_name": "Google",
+						"auth_url": "https://oauth.example.com/auth/oidc?team_name=some-team"
+					},
+					{
+						"type": "oauth",
+						"display_name": "Okta",
+						"auth_url": "https://oauth.example.com/auth/saml/login?team_name=some-team"
+					},
 					{
 						"type": "oauth",
 						"display_name": "UAA",
@@ -8,6 +8,7 @@ import (
 
 	"code.cloudfoundry.org/lager"
 	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
 	"github.com/concourse/atc/auth"
 )
 
@@ -30,21 +31,21 @@ func (s *Server) GetAuthToken(w http.ResponseWriter, r *http.Request) {
 		team, found, err := teamDB.GetTeam()
 		if err != nil {
 			logger.Error("get-team-by-name", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.DBFailure(w, "failed to get team")
 			return
 		}
 		if !found {
 			logger.Info("cannot-find-team-by-name", lager.Data{
 				"teamName": teamName,
 			})
-			w.WriteHeader(http.StatusUnauthorized)
+			apierror.Unauthorized(w, "team not found")
 			return
 		}
 
-		tokenType, tokenValue, err := s.tokenGenerator.GenerateToken(time.Now().Add(tokenDuration), team.Name, team.ID, team.Admin)
+		tokenType, tokenValue, err := s.tokenGenerator.GenerateToken(time.Now().Add(tokenDuration), team.Name, team.ID, team.Admin, team.Roles.BasicAuth.OrAdmin())
 		if err != nil {
 			logger.Error("generate-token", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.Internal(w, "failed to generate token")
 			return
 		}
 
@@ -7,9 +7,11 @@ import (
 	"sort"
 
 	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
 	"github.com/concourse/atc/auth"
 	"github.com/concourse/atc/auth/genericoauth"
 	"github.com/concourse/atc/auth/github"
+	"github.com/concourse/atc/auth/oidc"
 	"github.com/concourse/atc/auth/uaa"
 	"github.com/concourse/atc/db"
 	"github.com/concourse/atc/web"
@@ -24,19 +26,19 @@ func (s *Server) ListAuthMethods(w http.ResponseWriter, r *http.Request) {
 	team, found, err := teamDB.GetTeam()
 	if err != nil {
 		s.logger.Error("failed-to-get-team", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		apierror.DBFailure(w, "failed to get team")
 		return
 	}
 	if !found {
 		s.logger.Info("team-not-found")
-		w.WriteHeader(http.StatusNotFound)
+		apierror.NotFound(w, "team not found")
 		return
 	}
 
 	methods, err := s.authMethods(team)
 	if err != nil {
 		s.logger.Error("failed-to-get-auth-methods", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		apierror.DBFailure(w, "failed to get auth methods")
 		return
 	}
 
@@ -116,6 +118,39 @@ func (s *Server) authMethods(team db.SavedTeam) ([]atc.AuthMethod, error) {
 		})
 	}
 
+	if team.OIDCAuth != nil {
+		path, err := auth.OAuthRoutes.CreatePathForRoute(
+			auth.OAuthBegin,
+			rata.Params{"provider": oidc.ProviderName},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		path = path + fmt.Sprintf("?team_name=%s", team.Name)
+		methods = append(methods, atc.AuthMethod{
+			Type:        atc.AuthTypeOAuth,
+			DisplayName: team.OIDCAuth.DisplayName,
+			AuthURL:     s.oAuthBaseURL + path,
+		})
+	}
+
+	if team.SAMLAuth != nil {
+		path, err := auth.OAuthRoutes.CreatePathForRoute(auth.SAMLLogIn, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		// clients log in through SAML just as they do through OAuth: by
+		// visiting the URL and ending up with a token
+		path = path + fmt.Sprintf("?team_name=%s", team.Name)
+		methods = append(methods, atc.AuthMethod{
+			Type:        atc.AuthTypeOAuth,
+			DisplayName: team.SAMLAuth.DisplayName,
+			AuthURL:     s.oAuthBaseURL + path,
+		})
+	}
+
 	if team.BasicAuth != nil {
 		path, err := web.Routes.CreatePathForRoute(
 			web.TeamLogIn,
@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
 	"github.com/concourse/atc/api/present"
 	"github.com/concourse/atc/auth"
 )
@@ -21,14 +22,14 @@ func (s *Server) GetUser(w http.ResponseWriter, r *http.Request) {
 		authTeam, authTeamFound := auth.GetTeam(r)
 		if !authTeamFound {
 			hLog.Error("failed-to-get-team-from-auth", errors.New("failed-to-get-team-from-auth"))
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.Internal(w, "failed to get team from auth")
 			return
 		}
 
 		savedTeam, found, err := s.teamDBFactory.GetTeamDB(authTeam.Name()).GetTeam()
 		if err != nil {
 			hLog.Error("failed-to-get-team-from-db", errors.New("failed-to-get-team-from-db"))
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.DBFailure(w, "failed to get team from db")
 			return
 		}
 
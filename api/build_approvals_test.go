@@ -0,0 +1,264 @@
+package api_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/engine/enginefakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Build Approvals API", func() {
+	Describe("GET /api/v1/builds/:build_id/approvals", func() {
+		var response *http.Response
+
+		BeforeEach(func() {
+			build.IDReturns(128)
+			build.JobNameReturns("some-job")
+			build.TeamNameReturns("some-team")
+			buildsDB.GetBuildByIDReturns(build, true, nil)
+
+			authValidator.IsAuthenticatedReturns(true)
+			userContextReader.GetTeamReturns("some-team", 5, false, true)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			response, err = client.Get(server.URL + "/api/v1/builds/128/approvals")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when the approvals can be listed", func() {
+			BeforeEach(func() {
+				build.GetApprovalsReturns([]db.BuildApproval{
+					{
+						Name:        "ship-it",
+						Status:      db.ApprovalApproved,
+						Approver:    "team:some-team",
+						RequestedAt: time.Unix(100, 0),
+						DecidedAt:   time.Unix(200, 0),
+					},
+					{
+						Name:        "ship-it-again",
+						Status:      db.ApprovalPending,
+						RequestedAt: time.Unix(300, 0),
+					},
+				}, nil)
+			})
+
+			It("returns them", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+				Expect(response.Header.Get("Content-Type")).To(Equal("application/json"))
+
+				body, err := ioutil.ReadAll(response.Body)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(body).To(MatchJSON(`[
+					{"name":"ship-it","status":"approved","approver":"team:some-team","requested_at":100,"decided_at":200},
+					{"name":"ship-it-again","status":"pending","requested_at":300}
+				]`))
+			})
+		})
+
+		Context("when listing the approvals fails", func() {
+			BeforeEach(func() {
+				build.GetApprovalsReturns(nil, errors.New("nope"))
+			})
+
+			It("returns 500", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+		})
+	})
+
+	Describe("POST /api/v1/builds/:build_id/approvals/:step", func() {
+		var (
+			body     string
+			response *http.Response
+		)
+
+		BeforeEach(func() {
+			body = `{"approved":true}`
+
+			build.IDReturns(128)
+			build.TeamNameReturns("some-team")
+			build.IsRunningReturns(true)
+			buildsDB.GetBuildByIDReturns(build, true, nil)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			response, err = client.Post(server.URL+"/api/v1/builds/128/approvals/ship-it", "application/json", bytes.NewBufferString(body))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when not authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns 401", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+
+		Context("when authenticated as another team", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("some-other-team", 5, false, true)
+			})
+
+			It("returns 403", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+			})
+
+			It("does not decide the approval", func() {
+				Expect(build.DecideApprovalCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when authorized", func() {
+			var fakeBuild *enginefakes.FakeBuild
+
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("some-team", 5, false, true)
+
+				build.GetApprovalReturns(db.BuildApproval{
+					Name:        "ship-it",
+					Status:      db.ApprovalPending,
+					RequestedAt: time.Unix(100, 0),
+				}, true, nil)
+				build.DecideApprovalReturns(true, nil)
+
+				fakeBuild = new(enginefakes.FakeBuild)
+				fakeEngine.LookupBuildReturns(fakeBuild, nil)
+			})
+
+			It("decides the approval as the requester", func() {
+				Expect(build.DecideApprovalCallCount()).To(Equal(1))
+
+				name, approved, approver := build.DecideApprovalArgsForCall(0)
+				Expect(name).To(Equal("ship-it"))
+				Expect(approved).To(BeTrue())
+				Expect(approver).To(Equal("team:some-team"))
+			})
+
+			It("does not abort the build", func() {
+				Expect(fakeBuild.AbortCallCount()).To(BeZero())
+			})
+
+			It("returns 200 with the approval", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+				body, err := ioutil.ReadAll(response.Body)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(body).To(MatchJSON(`{
+					"name": "ship-it",
+					"status": "pending",
+					"requested_at": 100
+				}`))
+			})
+
+			Context("when it is rejected", func() {
+				BeforeEach(func() {
+					body = `{"approved":false}`
+				})
+
+				It("records the rejection", func() {
+					_, approved, _ := build.DecideApprovalArgsForCall(0)
+					Expect(approved).To(BeFalse())
+				})
+
+				It("aborts the build", func() {
+					Expect(fakeBuild.AbortCallCount()).To(Equal(1))
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+				})
+
+				Context("when aborting fails", func() {
+					BeforeEach(func() {
+						fakeBuild.AbortReturns(errors.New("nope"))
+					})
+
+					It("returns 500", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+					})
+				})
+			})
+
+			Context("when the build is not waiting on the step", func() {
+				BeforeEach(func() {
+					build.GetApprovalReturns(db.BuildApproval{}, false, nil)
+				})
+
+				It("returns 404", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+					Expect(build.DecideApprovalCallCount()).To(BeZero())
+				})
+			})
+
+			Context("when the step has already been decided", func() {
+				BeforeEach(func() {
+					build.GetApprovalReturns(db.BuildApproval{
+						Name:   "ship-it",
+						Status: db.ApprovalApproved,
+					}, true, nil)
+				})
+
+				It("returns 409", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusConflict))
+					Expect(build.DecideApprovalCallCount()).To(BeZero())
+				})
+			})
+
+			Context("when the build has finished", func() {
+				BeforeEach(func() {
+					build.IsRunningReturns(false)
+				})
+
+				It("returns 409", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusConflict))
+					Expect(build.DecideApprovalCallCount()).To(BeZero())
+				})
+			})
+
+			Context("when someone else decides it first", func() {
+				BeforeEach(func() {
+					build.DecideApprovalReturns(false, nil)
+				})
+
+				It("returns 409", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusConflict))
+				})
+			})
+
+			Context("when deciding the approval fails", func() {
+				BeforeEach(func() {
+					build.DecideApprovalReturns(false, errors.New("nope"))
+				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+
+			Context("when the request body is malformed", func() {
+				BeforeEach(func() {
+					body = `{`
+				})
+
+				It("returns 400", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+					Expect(build.DecideApprovalCallCount()).To(BeZero())
+				})
+			})
+		})
+	})
+})
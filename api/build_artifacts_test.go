@@ -0,0 +1,349 @@
+package api_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"code.cloudfoundry.org/garden"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/worker/workerfakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Build Artifacts API", func() {
+	Describe("POST /api/v1/builds/:build_id/artifacts", func() {
+		var (
+			body     string
+			response *http.Response
+		)
+
+		BeforeEach(func() {
+			body = `{"name":"some-artifact","container_handle":"some-handle","path":"/tmp/build/some-output"}`
+
+			build.IDReturns(128)
+			build.TeamNameReturns("some-team")
+			buildsDB.GetBuildByIDReturns(build, true, nil)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			response, err = client.Post(server.URL+"/api/v1/builds/128/artifacts", "application/json", bytes.NewBufferString(body))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when not authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns 401", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+
+		Context("when authenticated as another team", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("some-other-team", 5, false, true)
+			})
+
+			It("returns 403", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+			})
+
+			It("does not save the artifact", func() {
+				Expect(build.SaveArtifactCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("some-team", 5, false, true)
+			})
+
+			Context("when the container belongs to the build", func() {
+				BeforeEach(func() {
+					teamDB.GetContainerReturns(db.SavedContainer{
+						Container: db.Container{
+							ContainerIdentifier: db.ContainerIdentifier{BuildID: 128},
+						},
+					}, true, nil)
+				})
+
+				It("returns 201", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusCreated))
+				})
+
+				It("saves the artifact", func() {
+					Expect(teamDB.GetContainerArgsForCall(0)).To(Equal("some-handle"))
+
+					Expect(build.SaveArtifactCallCount()).To(Equal(1))
+					Expect(build.SaveArtifactArgsForCall(0)).To(Equal(db.BuildArtifact{
+						Name:            "some-artifact",
+						ContainerHandle: "some-handle",
+						Path:            "/tmp/build/some-output",
+					}))
+				})
+
+				Context("when saving the artifact fails", func() {
+					BeforeEach(func() {
+						build.SaveArtifactReturns(errors.New("nope"))
+					})
+
+					It("returns 500", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+					})
+				})
+			})
+
+			Context("when the container belongs to another build", func() {
+				BeforeEach(func() {
+					teamDB.GetContainerReturns(db.SavedContainer{
+						Container: db.Container{
+							ContainerIdentifier: db.ContainerIdentifier{BuildID: 42},
+						},
+					}, true, nil)
+				})
+
+				It("returns 422", func() {
+					Expect(response.StatusCode).To(Equal(422))
+					Expect(build.SaveArtifactCallCount()).To(BeZero())
+				})
+			})
+
+			Context("when the container cannot be found", func() {
+				BeforeEach(func() {
+					teamDB.GetContainerReturns(db.SavedContainer{}, false, nil)
+				})
+
+				It("returns 422", func() {
+					Expect(response.StatusCode).To(Equal(422))
+				})
+			})
+
+			Context("when the artifact name contains a slash", func() {
+				BeforeEach(func() {
+					body = `{"name":"../escape","container_handle":"some-handle","path":"/tmp"}`
+				})
+
+				It("returns 400", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+				})
+			})
+
+			Context("when the request body is malformed", func() {
+				BeforeEach(func() {
+					body = `{`
+				})
+
+				It("returns 400", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+				})
+			})
+		})
+	})
+
+	Describe("GET /api/v1/builds/:build_id/artifacts", func() {
+		var response *http.Response
+
+		BeforeEach(func() {
+			build.IDReturns(128)
+			build.JobNameReturns("some-job")
+			build.TeamNameReturns("some-team")
+			buildsDB.GetBuildByIDReturns(build, true, nil)
+
+			authValidator.IsAuthenticatedReturns(true)
+			userContextReader.GetTeamReturns("some-team", 5, false, true)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			response, err = client.Get(server.URL + "/api/v1/builds/128/artifacts")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when the artifacts can be listed", func() {
+			BeforeEach(func() {
+				build.GetArtifactsReturns([]db.BuildArtifact{
+					{Name: "some-artifact", ContainerHandle: "some-handle", Path: "/some/path"},
+				}, nil)
+			})
+
+			It("returns them", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+				Expect(response.Header.Get("Content-Type")).To(Equal("application/json"))
+
+				body, err := ioutil.ReadAll(response.Body)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(body).To(MatchJSON(`[
+					{"name":"some-artifact","container_handle":"some-handle","path":"/some/path"}
+				]`))
+			})
+		})
+
+		Context("when listing the artifacts fails", func() {
+			BeforeEach(func() {
+				build.GetArtifactsReturns(nil, errors.New("nope"))
+			})
+
+			It("returns 500", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+		})
+	})
+
+	Describe("GET /api/v1/builds/:build_id/artifacts/:artifact_name", func() {
+		var (
+			response *http.Response
+
+			fakeContainer *workerfakes.FakeContainer
+		)
+
+		BeforeEach(func() {
+			build.IDReturns(128)
+			build.JobNameReturns("some-job")
+			build.TeamNameReturns("some-team")
+			buildsDB.GetBuildByIDReturns(build, true, nil)
+
+			authValidator.IsAuthenticatedReturns(true)
+			userContextReader.GetTeamReturns("some-team", 5, false, true)
+
+			build.GetArtifactReturns(db.BuildArtifact{
+				Name:            "some-artifact",
+				ContainerHandle: "some-handle",
+				Path:            "/tmp/build/some-output",
+			}, true, nil)
+
+			fakeContainer = new(workerfakes.FakeContainer)
+			fakeContainer.StreamOutReturns(ioutil.NopCloser(bytes.NewBufferString("some-tarball")), nil)
+			fakeWorkerClient.LookupContainerReturns(fakeContainer, true, nil)
+
+			fakeArtifactStore.StoreStub = func(buildID int, name string, tarball io.Reader) error {
+				_, err := ioutil.ReadAll(tarball)
+				return err
+			}
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			response, err = client.Get(server.URL + "/api/v1/builds/128/artifacts/some-artifact")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("streams the tarball out of the container", func() {
+			Expect(response.StatusCode).To(Equal(http.StatusOK))
+			Expect(response.Header.Get("Content-Type")).To(Equal("application/x-tar"))
+			Expect(response.Header.Get("Content-Disposition")).To(Equal(`attachment; filename="some-artifact.tar"`))
+
+			body, err := ioutil.ReadAll(response.Body)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(body)).To(Equal("some-tarball"))
+
+			Expect(build.GetArtifactArgsForCall(0)).To(Equal("some-artifact"))
+
+			_, handle := fakeWorkerClient.LookupContainerArgsForCall(0)
+			Expect(handle).To(Equal("some-handle"))
+
+			Expect(fakeContainer.StreamOutArgsForCall(0)).To(Equal(garden.StreamOutSpec{
+				Path: "/tmp/build/some-output",
+			}))
+
+			Eventually(fakeContainer.ReleaseCallCount).Should(Equal(1))
+		})
+
+		It("stores a copy of the tarball", func() {
+			Eventually(fakeArtifactStore.StoreCallCount).Should(Equal(1))
+
+			buildID, name, _ := fakeArtifactStore.StoreArgsForCall(0)
+			Expect(buildID).To(Equal(128))
+			Expect(name).To(Equal("some-artifact"))
+		})
+
+		Context("when storing the copy fails", func() {
+			BeforeEach(func() {
+				fakeArtifactStore.StoreReturns(errors.New("disk full"))
+				fakeArtifactStore.StoreStub = nil
+			})
+
+			It("still streams the whole tarball", func() {
+				body, err := ioutil.ReadAll(response.Body)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(body)).To(Equal("some-tarball"))
+			})
+		})
+
+		Context("when a copy has already been stored", func() {
+			BeforeEach(func() {
+				fakeArtifactStore.OpenReturns(ioutil.NopCloser(bytes.NewBufferString("stored-tarball")), true, nil)
+			})
+
+			It("serves the stored copy without touching the container", func() {
+				body, err := ioutil.ReadAll(response.Body)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(body)).To(Equal("stored-tarball"))
+
+				buildID, name := fakeArtifactStore.OpenArgsForCall(0)
+				Expect(buildID).To(Equal(128))
+				Expect(name).To(Equal("some-artifact"))
+
+				Expect(fakeWorkerClient.LookupContainerCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when the artifact has not been registered", func() {
+			BeforeEach(func() {
+				build.GetArtifactReturns(db.BuildArtifact{}, false, nil)
+			})
+
+			It("returns 404", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+			})
+
+			It("describes the error as JSON", func() {
+				Expect(response.Header.Get("Content-Type")).To(Equal("application/json"))
+
+				body, err := ioutil.ReadAll(response.Body)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(body).To(MatchJSON(`{"code":"not-found","message":"artifact not found"}`))
+			})
+		})
+
+		Context("when the container is gone", func() {
+			BeforeEach(func() {
+				fakeWorkerClient.LookupContainerReturns(nil, false, nil)
+			})
+
+			It("returns 410", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusGone))
+			})
+		})
+
+		Context("when streaming out of the container fails", func() {
+			BeforeEach(func() {
+				fakeContainer.StreamOutReturns(nil, errors.New("nope"))
+			})
+
+			It("returns 500", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+		})
+
+		Context("when not authorized", func() {
+			BeforeEach(func() {
+				userContextReader.GetTeamReturns("some-other-team", 5, false, true)
+			})
+
+			It("returns 403", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+			})
+		})
+	})
+})
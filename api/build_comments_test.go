@@ -0,0 +1,230 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Build Comments API", func() {
+	Describe("POST /api/v1/builds/:build_id/comments", func() {
+		var (
+			body     string
+			response *http.Response
+		)
+
+		BeforeEach(func() {
+			body = `{"text":"known flake"}`
+
+			build.IDReturns(128)
+			build.TeamNameReturns("some-team")
+			buildsDB.GetBuildByIDReturns(build, true, nil)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			response, err = client.Post(server.URL+"/api/v1/builds/128/comments", "application/json", bytes.NewBufferString(body))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when not authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns 401", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+
+		Context("when authenticated as another team", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("some-other-team", 5, false, true)
+			})
+
+			It("returns 403", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+			})
+
+			It("does not save the comment", func() {
+				Expect(build.SaveCommentCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("some-team", 5, false, true)
+
+				build.SaveCommentReturns(db.BuildComment{
+					ID:        1,
+					Author:    "team:some-team",
+					Text:      "known flake",
+					CreatedAt: time.Unix(100, 0),
+				}, nil)
+			})
+
+			It("saves the comment as the requester", func() {
+				Expect(build.SaveCommentCallCount()).To(Equal(1))
+
+				author, text := build.SaveCommentArgsForCall(0)
+				Expect(author).To(Equal("team:some-team"))
+				Expect(text).To(Equal("known flake"))
+			})
+
+			It("returns 201 with the comment", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusCreated))
+
+				body, err := ioutil.ReadAll(response.Body)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(body).To(MatchJSON(`{
+					"id": 1,
+					"author": "team:some-team",
+					"text": "known flake",
+					"created_at": 100
+				}`))
+			})
+
+			Context("when saving the comment fails", func() {
+				BeforeEach(func() {
+					build.SaveCommentReturns(db.BuildComment{}, errors.New("nope"))
+				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+
+			Context("when the comment is blank", func() {
+				BeforeEach(func() {
+					body = `{"text":"  "}`
+				})
+
+				It("returns 400", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+					Expect(build.SaveCommentCallCount()).To(BeZero())
+				})
+			})
+
+			Context("when the request body is malformed", func() {
+				BeforeEach(func() {
+					body = `{`
+				})
+
+				It("returns 400", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+				})
+			})
+		})
+	})
+
+	Describe("GET /api/v1/builds/:build_id/comments", func() {
+		var response *http.Response
+
+		BeforeEach(func() {
+			build.IDReturns(128)
+			build.JobNameReturns("some-job")
+			build.TeamNameReturns("some-team")
+			buildsDB.GetBuildByIDReturns(build, true, nil)
+
+			authValidator.IsAuthenticatedReturns(true)
+			userContextReader.GetTeamReturns("some-team", 5, false, true)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			response, err = client.Get(server.URL + "/api/v1/builds/128/comments")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when the comments can be listed", func() {
+			BeforeEach(func() {
+				build.GetCommentsReturns([]db.BuildComment{
+					{ID: 1, Author: "team:some-team", Text: "known flake", CreatedAt: time.Unix(100, 0)},
+					{ID: 2, Author: "team:main", Text: "fixed upstream", CreatedAt: time.Unix(200, 0)},
+				}, nil)
+			})
+
+			It("returns them", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+				Expect(response.Header.Get("Content-Type")).To(Equal("application/json"))
+
+				body, err := ioutil.ReadAll(response.Body)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(body).To(MatchJSON(`[
+					{"id":1,"author":"team:some-team","text":"known flake","created_at":100},
+					{"id":2,"author":"team:main","text":"fixed upstream","created_at":200}
+				]`))
+			})
+		})
+
+		Context("when listing the comments fails", func() {
+			BeforeEach(func() {
+				build.GetCommentsReturns(nil, errors.New("nope"))
+			})
+
+			It("returns 500", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+		})
+	})
+
+	Describe("GET /api/v1/builds/:build_id?include=comments", func() {
+		var response *http.Response
+
+		BeforeEach(func() {
+			build.IDReturns(128)
+			build.JobNameReturns("some-job")
+			build.TeamNameReturns("some-team")
+			build.StatusReturns(db.StatusSucceeded)
+			buildsDB.GetBuildByIDReturns(build, true, nil)
+
+			authValidator.IsAuthenticatedReturns(true)
+			userContextReader.GetTeamReturns("some-team", 5, false, true)
+
+			build.GetCommentsReturns([]db.BuildComment{
+				{ID: 1, Author: "team:some-team", Text: "known flake", CreatedAt: time.Unix(100, 0)},
+			}, nil)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			response, err = client.Get(server.URL + "/api/v1/builds/128?include=comments")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("includes the comments in the build", func() {
+			Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+			var returned atc.Build
+			err := json.NewDecoder(response.Body).Decode(&returned)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(returned.Comments).To(Equal([]atc.BuildComment{
+				{ID: 1, Author: "team:some-team", Text: "known flake", CreatedAt: 100},
+			}))
+		})
+
+		Context("when getting the comments fails", func() {
+			BeforeEach(func() {
+				build.GetCommentsReturns(nil, errors.New("nope"))
+			})
+
+			It("returns 500", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+		})
+	})
+})
@@ -0,0 +1,311 @@
+package api_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/event"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Build Log Search API", func() {
+	someMatches := []db.LogMatch{
+		{
+			BuildID: 128,
+			EventID: 3,
+			Origin:  event.Origin{ID: "some-step", Source: event.OriginSourceStderr},
+			Payload: "connection refused\n",
+		},
+	}
+
+	someMatchesJSON := `[
+		{
+			"build_id": 128,
+			"event_id": 3,
+			"origin_id": "some-step",
+			"source": "stderr",
+			"payload": "connection refused\n"
+		}
+	]`
+
+	Describe("GET /api/v1/builds/:build_id/events/search", func() {
+		var (
+			query    string
+			response *http.Response
+		)
+
+		BeforeEach(func() {
+			query = "?q=connection+refused"
+
+			build.IDReturns(128)
+			build.JobNameReturns("some-job")
+			build.TeamNameReturns("some-team")
+			buildsDB.GetBuildByIDReturns(build, true, nil)
+
+			authValidator.IsAuthenticatedReturns(true)
+			userContextReader.GetTeamReturns("some-team", 5, false, true)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			response, err = client.Get(server.URL + "/api/v1/builds/128/events/search" + query)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when the search succeeds", func() {
+			BeforeEach(func() {
+				build.SearchLogsReturns(someMatches, nil)
+			})
+
+			It("returns the matching log lines", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+				Expect(response.Header.Get("Content-Type")).To(Equal("application/json"))
+
+				body, err := ioutil.ReadAll(response.Body)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(body).To(MatchJSON(someMatchesJSON))
+			})
+
+			It("searches with the default limit", func() {
+				searched, limit := build.SearchLogsArgsForCall(0)
+				Expect(searched).To(Equal("connection refused"))
+				Expect(limit).To(Equal(100))
+			})
+		})
+
+		Context("when a match is of the build's secrets", func() {
+			BeforeEach(func() {
+				build.SearchLogsReturns(append(someMatches, db.LogMatch{
+					BuildID: 128,
+					EventID: 4,
+					Payload: "logging in with hunter2: connection refused\n",
+				}), nil)
+
+				build.GetSecretsReturns([]string{"hunter2"}, nil)
+			})
+
+			It("leaves it out", func() {
+				body, err := ioutil.ReadAll(response.Body)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(body).To(MatchJSON(someMatchesJSON))
+			})
+
+			Context("when the build is of a public pipeline searched by another team", func() {
+				BeforeEach(func() {
+					userContextReader.GetTeamReturns("some-other-team", 6, false, true)
+
+					build.GetPipelineReturns(db.SavedPipeline{Public: true}, nil)
+					build.GetConfigReturns(atc.Config{
+						Jobs: atc.JobConfigs{
+							{Name: "some-job", Public: true},
+						},
+					}, 1, nil)
+				})
+
+				It("leaves it out too", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+					body, err := ioutil.ReadAll(response.Body)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(body).To(MatchJSON(someMatchesJSON))
+				})
+			})
+		})
+
+		Context("when getting the build's secrets fails", func() {
+			BeforeEach(func() {
+				build.SearchLogsReturns(someMatches, nil)
+				build.GetSecretsReturns(nil, errors.New("nope"))
+			})
+
+			It("returns 500", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+		})
+
+		Context("when a limit is given", func() {
+			BeforeEach(func() {
+				query = "?q=refused&limit=5"
+			})
+
+			It("searches with it", func() {
+				_, limit := build.SearchLogsArgsForCall(0)
+				Expect(limit).To(Equal(5))
+			})
+		})
+
+		Context("when no query is given", func() {
+			BeforeEach(func() {
+				query = ""
+			})
+
+			It("returns 400", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+				Expect(build.SearchLogsCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when the search fails", func() {
+			BeforeEach(func() {
+				build.SearchLogsReturns(nil, errors.New("nope"))
+			})
+
+			It("returns 500", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+		})
+
+		Context("when not authorized", func() {
+			BeforeEach(func() {
+				userContextReader.GetTeamReturns("some-other-team", 5, false, true)
+			})
+
+			It("returns 403", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+			})
+		})
+	})
+
+	Describe("GET /api/v1/builds/events/search", func() {
+		var (
+			query    string
+			response *http.Response
+		)
+
+		BeforeEach(func() {
+			query = "?q=refused"
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			response, err = client.Get(server.URL + "/api/v1/builds/events/search" + query)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when not authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns 401", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+
+		Context("when authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("some-team", 5, false, true)
+			})
+
+			Context("when the search succeeds", func() {
+				BeforeEach(func() {
+					teamDB.SearchBuildLogsReturns(someMatches, nil)
+
+					build.IDReturns(128)
+					build.TeamNameReturns("some-team")
+					buildServerDB.GetBuildsReturns([]db.Build{build}, nil)
+				})
+
+				It("searches the logs of the team's builds", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+					Expect(teamDBFactory.GetTeamDBArgsForCall(0)).To(Equal("some-team"))
+
+					searched, _, hidden := teamDB.SearchBuildLogsArgsForCall(0)
+					Expect(searched).To(Equal("refused"))
+					Expect(hidden).To(BeEmpty())
+
+					body, err := ioutil.ReadAll(response.Body)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(body).To(MatchJSON(someMatchesJSON))
+
+					_, buildIDs := buildServerDB.GetBuildsArgsForCall(0)
+					Expect(buildIDs).To(Equal([]int{128}))
+				})
+
+				Context("when a match is of a build's secrets", func() {
+					BeforeEach(func() {
+						teamDB.SearchBuildLogsReturns(append(someMatches, db.LogMatch{
+							BuildID: 128,
+							EventID: 4,
+							Payload: "logging in with hunter2: connection refused\n",
+						}), nil)
+
+						build.GetSecretsReturns([]string{"hunter2"}, nil)
+					})
+
+					It("leaves it out", func() {
+						body, err := ioutil.ReadAll(response.Body)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(body).To(MatchJSON(someMatchesJSON))
+					})
+				})
+
+				Context("when a match's build has since gone", func() {
+					BeforeEach(func() {
+						buildServerDB.GetBuildsReturns([]db.Build{}, nil)
+					})
+
+					It("leaves it out", func() {
+						body, err := ioutil.ReadAll(response.Body)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(body).To(MatchJSON(`[]`))
+					})
+				})
+			})
+
+			Context("when the role isn't enough to view some of the team's pipelines", func() {
+				BeforeEach(func() {
+					userContextReader.GetRoleReturns(atc.RoleViewer, true)
+
+					teamDB.GetPipelinesReturns([]db.SavedPipeline{
+						{ID: 1},
+						{ID: 2, Permissions: atc.PipelinePermissions{View: atc.RoleOperator}},
+					}, nil)
+				})
+
+				It("leaves out their builds", func() {
+					_, _, hidden := teamDB.SearchBuildLogsArgsForCall(0)
+					Expect(hidden).To(Equal([]int{2}))
+				})
+
+				Context("when getting the pipelines fails", func() {
+					BeforeEach(func() {
+						teamDB.GetPipelinesReturns(nil, errors.New("nope"))
+					})
+
+					It("returns 500 without searching", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+						Expect(teamDB.SearchBuildLogsCallCount()).To(BeZero())
+					})
+				})
+			})
+
+			Context("when no query is given", func() {
+				BeforeEach(func() {
+					query = ""
+				})
+
+				It("returns 400", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+				})
+			})
+
+			Context("when the search fails", func() {
+				BeforeEach(func() {
+					teamDB.SearchBuildLogsReturns(nil, errors.New("nope"))
+				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+	})
+})
@@ -0,0 +1,187 @@
+package api_test
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/engine/enginefakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Build Report API", func() {
+	Describe("GET /api/v1/builds/:build_id/report", func() {
+		var query string
+		var response *http.Response
+
+		type testCase struct {
+			Name    string `xml:"name,attr"`
+			Time    string `xml:"time,attr"`
+			Failure *struct {
+				Message string `xml:"message,attr"`
+			} `xml:"failure"`
+		}
+
+		type testSuites struct {
+			XMLName  xml.Name
+			Name     string     `xml:"name,attr"`
+			Tests    int        `xml:"tests,attr"`
+			Failures int        `xml:"failures,attr"`
+			Cases    []testCase `xml:"testsuite>testcase"`
+		}
+
+		BeforeEach(func() {
+			query = "?format=junit"
+
+			build.IDReturns(128)
+			build.NameReturns("7")
+			build.PipelineNameReturns("some-pipeline")
+			build.JobNameReturns("some-job")
+			build.TeamNameReturns("some-team")
+			build.StatusReturns(db.StatusFailed)
+			build.StartTimeReturns(time.Unix(100, 0))
+			build.EndTimeReturns(time.Unix(160, 0))
+			buildsDB.GetBuildByIDReturns(build, true, nil)
+
+			build.GetStepsReturns([]db.BuildStep{
+				{
+					PlanID:     "1",
+					Type:       "get",
+					Status:     db.BuildStepSucceeded,
+					StartedAt:  time.Unix(100, 0),
+					FinishedAt: time.Unix(130, 0),
+				},
+				{
+					PlanID:     "2",
+					Type:       "task",
+					Status:     db.BuildStepFailed,
+					StartedAt:  time.Unix(130, 0),
+					FinishedAt: time.Unix(160, 0),
+				},
+			}, nil)
+
+			authValidator.IsAuthenticatedReturns(true)
+			userContextReader.GetTeamReturns("some-team", 5, false, true)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			response, err = client.Get(server.URL + "/api/v1/builds/128/report" + query)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		parseReport := func() testSuites {
+			body, err := ioutil.ReadAll(response.Body)
+			Expect(err).NotTo(HaveOccurred())
+
+			var report testSuites
+			err = xml.Unmarshal(body, &report)
+			Expect(err).NotTo(HaveOccurred())
+
+			return report
+		}
+
+		It("returns the steps as a JUnit test suite", func() {
+			Expect(response.StatusCode).To(Equal(http.StatusOK))
+			Expect(response.Header.Get("Content-Type")).To(Equal("application/xml; charset=utf-8"))
+
+			report := parseReport()
+			Expect(report.XMLName.Local).To(Equal("testsuites"))
+			Expect(report.Name).To(Equal("some-pipeline/some-job #7"))
+			Expect(report.Tests).To(Equal(2))
+			Expect(report.Failures).To(Equal(1))
+
+			Expect(report.Cases).To(HaveLen(2))
+			Expect(report.Cases[0].Name).To(Equal("get 1"))
+			Expect(report.Cases[0].Time).To(Equal("30.000"))
+			Expect(report.Cases[0].Failure).To(BeNil())
+			Expect(report.Cases[1].Name).To(Equal("task 2"))
+			Expect(report.Cases[1].Failure).NotTo(BeNil())
+			Expect(report.Cases[1].Failure.Message).To(Equal("task failed"))
+		})
+
+		Context("when no format is given", func() {
+			BeforeEach(func() {
+				query = ""
+			})
+
+			It("returns JUnit", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+				Expect(parseReport().XMLName.Local).To(Equal("testsuites"))
+			})
+		})
+
+		Context("when the build has started", func() {
+			var engineBuild *enginefakes.FakeBuild
+
+			BeforeEach(func() {
+				build.EngineReturns("some-engine")
+
+				engineBuild = new(enginefakes.FakeBuild)
+				fakeEngine.LookupBuildReturns(engineBuild, nil)
+			})
+
+			Context("when its plan names the steps", func() {
+				BeforeEach(func() {
+					var plan json.RawMessage = []byte(`{
+						"id": "0",
+						"do": [
+							{"id": "1", "get": {"type": "git", "name": "some-input"}},
+							{"id": "2", "task": {"name": "unit", "privileged": false}}
+						]
+					}`)
+
+					engineBuild.PublicPlanReturns(atc.PublicBuildPlan{Schema: "exec.v2", Plan: &plan}, nil)
+				})
+
+				It("names the test cases after them", func() {
+					report := parseReport()
+					Expect(report.Cases[0].Name).To(Equal("get some-input"))
+					Expect(report.Cases[1].Name).To(Equal("task unit"))
+				})
+			})
+
+			Context("when the plan can't be looked up", func() {
+				BeforeEach(func() {
+					engineBuild.PublicPlanReturns(atc.PublicBuildPlan{}, errors.New("nope"))
+				})
+
+				It("reports the steps unnamed", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+					Expect(parseReport().Cases[0].Name).To(Equal("get 1"))
+				})
+			})
+		})
+
+		Context("when the format is unknown", func() {
+			BeforeEach(func() {
+				query = "?format=tap"
+			})
+
+			It("returns 400", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+			})
+
+			It("doesn't look at the steps", func() {
+				Expect(build.GetStepsCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when listing the steps fails", func() {
+			BeforeEach(func() {
+				build.GetStepsReturns(nil, errors.New("nope"))
+			})
+
+			It("returns 500", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+		})
+	})
+})
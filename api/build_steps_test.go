@@ -0,0 +1,78 @@
+package api_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/concourse/atc/db"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Build Steps API", func() {
+	Describe("GET /api/v1/builds/:build_id/steps", func() {
+		var response *http.Response
+
+		BeforeEach(func() {
+			build.IDReturns(128)
+			build.JobNameReturns("some-job")
+			build.TeamNameReturns("some-team")
+			buildsDB.GetBuildByIDReturns(build, true, nil)
+
+			authValidator.IsAuthenticatedReturns(true)
+			userContextReader.GetTeamReturns("some-team", 5, false, true)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			response, err = client.Get(server.URL + "/api/v1/builds/128/steps")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when the steps can be listed", func() {
+			BeforeEach(func() {
+				build.GetStepsReturns([]db.BuildStep{
+					{
+						PlanID:     "1",
+						Type:       "get",
+						Status:     db.BuildStepSucceeded,
+						StartedAt:  time.Unix(100, 0),
+						FinishedAt: time.Unix(130, 0),
+					},
+					{
+						PlanID:    "2",
+						Type:      "task",
+						Status:    db.BuildStepStarted,
+						StartedAt: time.Unix(130, 0),
+					},
+				}, nil)
+			})
+
+			It("returns them with how long the finished ones took", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+				Expect(response.Header.Get("Content-Type")).To(Equal("application/json"))
+
+				body, err := ioutil.ReadAll(response.Body)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(body).To(MatchJSON(`[
+					{"id":"1","type":"get","status":"succeeded","start_time":100,"end_time":130,"duration":30},
+					{"id":"2","type":"task","status":"started","start_time":130}
+				]`))
+			})
+		})
+
+		Context("when listing the steps fails", func() {
+			BeforeEach(func() {
+				build.GetStepsReturns(nil, errors.New("nope"))
+			})
+
+			It("returns 500", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+		})
+	})
+})
@@ -2,11 +2,14 @@ package api_test
 
 import (
 	"bytes"
+	"compress/gzip"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
@@ -16,18 +19,23 @@ import (
 	"github.com/onsi/gomega/ghttp"
 
 	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/buildserver"
 	"github.com/concourse/atc/db"
 	"github.com/concourse/atc/db/dbfakes"
+	"github.com/concourse/atc/engine"
 	"github.com/concourse/atc/engine/enginefakes"
+	"github.com/concourse/atc/event"
 )
 
 var _ = Describe("Builds API", func() {
 	Describe("POST /api/v1/builds", func() {
 		var plan atc.Plan
+		var queryParams string
 
 		var response *http.Response
 
 		BeforeEach(func() {
+			queryParams = ""
 			plan = atc.Plan{
 				Task: &atc.TaskPlan{
 					Config: &atc.TaskConfig{
@@ -43,7 +51,7 @@ var _ = Describe("Builds API", func() {
 			reqPayload, err := json.Marshal(plan)
 			Expect(err).NotTo(HaveOccurred())
 
-			req, err := http.NewRequest("POST", server.URL+"/api/v1/builds", bytes.NewBuffer(reqPayload))
+			req, err := http.NewRequest("POST", server.URL+"/api/v1/builds"+queryParams, bytes.NewBuffer(reqPayload))
 			Expect(err).NotTo(HaveOccurred())
 
 			req.Header.Set("Content-Type", "application/json")
@@ -60,9 +68,7 @@ var _ = Describe("Builds API", func() {
 
 			Context("when creating a one-off build succeeds", func() {
 				BeforeEach(func() {
-					teamDB.CreateOneOffBuildStub = func() (db.Build, error) {
-						Expect(teamDBFactory.GetTeamDBCallCount()).To(Equal(1))
-						teamName := teamDBFactory.GetTeamDBArgsForCall(0)
+					buildServerDB.CreateOneOffBuildStub = func(_ context.Context, teamName string, spec db.OneOffBuildSpec) (db.Build, error) {
 						build.IDReturns(42)
 						build.NameReturns("1")
 						build.TeamNameReturns(teamName)
@@ -72,6 +78,10 @@ var _ = Describe("Builds API", func() {
 						build.ReapTimeReturns(time.Unix(200, 0))
 						return build, nil
 					}
+
+					build.ClaimPendingPlanStub = func() (atc.Plan, bool, error) {
+						return plan, true, nil
+					}
 				})
 
 				Context("and building succeeds", func() {
@@ -95,6 +105,8 @@ var _ = Describe("Builds API", func() {
 						}
 
 						fakeEngine.CreateBuildReturns(fakeBuild, nil)
+
+						build.ReloadReturns(true, nil)
 					})
 
 					AfterEach(func() {
@@ -105,6 +117,30 @@ var _ = Describe("Builds API", func() {
 						Expect(response.StatusCode).To(Equal(http.StatusCreated))
 					})
 
+					It("reloads the build after starting it", func() {
+						Expect(build.ReloadCallCount()).To(Equal(1))
+					})
+
+					Context("when reloading the build fails", func() {
+						BeforeEach(func() {
+							build.ReloadReturns(false, errors.New("nope"))
+						})
+
+						It("returns 500 Internal Server Error", func() {
+							Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+						})
+					})
+
+					Context("when the build disappears", func() {
+						BeforeEach(func() {
+							build.ReloadReturns(false, nil)
+						})
+
+						It("returns 500 Internal Server Error", func() {
+							Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+						})
+					})
+
 					It("creates build for specified team", func() {
 						body, err := ioutil.ReadAll(response.Body)
 						Expect(err).NotTo(HaveOccurred())
@@ -122,8 +158,16 @@ var _ = Describe("Builds API", func() {
 						}`))
 					})
 
-					It("creates a one-off build and runs it asynchronously", func() {
-						Expect(teamDB.CreateOneOffBuildCallCount()).To(Equal(1))
+					It("creates a one-off build with its plan", func() {
+						Expect(buildServerDB.CreateOneOffBuildCallCount()).To(Equal(1))
+
+						_, teamName, spec := buildServerDB.CreateOneOffBuildArgsForCall(0)
+						Expect(teamName).To(Equal("some-team"))
+						Expect(spec).To(Equal(db.OneOffBuildSpec{Plan: plan}))
+					})
+
+					It("claims the plan and runs the build with it asynchronously", func() {
+						Expect(build.ClaimPendingPlanCallCount()).To(Equal(1))
 
 						Expect(fakeEngine.CreateBuildCallCount()).To(Equal(1))
 						_, oneOffBuild, builtPlan := fakeEngine.CreateBuildArgsForCall(0)
@@ -133,6 +177,124 @@ var _ = Describe("Builds API", func() {
 
 						<-resumed
 					})
+
+					Context("when the plan has already been claimed", func() {
+						BeforeEach(func() {
+							build.ClaimPendingPlanStub = nil
+							build.ClaimPendingPlanReturns(atc.Plan{}, false, nil)
+						})
+
+						It("returns 201 Created", func() {
+							Expect(response.StatusCode).To(Equal(http.StatusCreated))
+						})
+
+						It("leaves starting the build to whoever claimed it", func() {
+							Expect(fakeEngine.CreateBuildCallCount()).To(BeZero())
+						})
+					})
+
+					Context("when claiming the plan fails", func() {
+						BeforeEach(func() {
+							build.ClaimPendingPlanStub = nil
+							build.ClaimPendingPlanReturns(atc.Plan{}, false, errors.New("nope"))
+						})
+
+						It("returns 500 Internal Server Error", func() {
+							Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+						})
+
+						It("does not start the build", func() {
+							Expect(fakeEngine.CreateBuildCallCount()).To(BeZero())
+						})
+					})
+
+					Context("when labels are given", func() {
+						BeforeEach(func() {
+							queryParams = "?label=env:staging&label=ticket:ABC-123:4"
+						})
+
+						It("creates the build with them", func() {
+							_, _, spec := buildServerDB.CreateOneOffBuildArgsForCall(0)
+							Expect(spec.Labels).To(Equal(map[string]string{
+								"env":    "staging",
+								"ticket": "ABC-123:4",
+							}))
+						})
+					})
+
+					Context("when a label is malformed", func() {
+						BeforeEach(func() {
+							queryParams = "?label=nope"
+						})
+
+						It("returns 400 Bad Request", func() {
+							Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+						})
+
+						It("does not create a build", func() {
+							Expect(buildServerDB.CreateOneOffBuildCallCount()).To(BeZero())
+						})
+					})
+
+					Context("when a priority is given", func() {
+						BeforeEach(func() {
+							queryParams = "?priority=3"
+						})
+
+						It("creates the build with it", func() {
+							_, _, spec := buildServerDB.CreateOneOffBuildArgsForCall(0)
+							Expect(spec.Priority).To(Equal(3))
+						})
+					})
+
+					Context("when the priority is not a number", func() {
+						BeforeEach(func() {
+							queryParams = "?priority=urgent"
+						})
+
+						It("returns 400 Bad Request", func() {
+							Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+						})
+
+						It("does not create a build", func() {
+							Expect(buildServerDB.CreateOneOffBuildCallCount()).To(BeZero())
+						})
+					})
+
+					Context("when a timeout is given", func() {
+						BeforeEach(func() {
+							queryParams = "?timeout=90m"
+						})
+
+						It("creates the build with it", func() {
+							_, _, spec := buildServerDB.CreateOneOffBuildArgsForCall(0)
+							Expect(spec.Timeout).To(Equal(90 * time.Minute))
+						})
+					})
+
+					Context("when the timeout is not a duration", func() {
+						BeforeEach(func() {
+							queryParams = "?timeout=forever"
+						})
+
+						It("returns 400 Bad Request", func() {
+							Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+						})
+
+						It("does not create a build", func() {
+							Expect(buildServerDB.CreateOneOffBuildCallCount()).To(BeZero())
+						})
+					})
+
+					Context("when the timeout is negative", func() {
+						BeforeEach(func() {
+							queryParams = "?timeout=-1h"
+						})
+
+						It("returns 400 Bad Request", func() {
+							Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+						})
+					})
 				})
 
 				Context("and building fails", func() {
@@ -144,11 +306,101 @@ var _ = Describe("Builds API", func() {
 						Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
 					})
 				})
+
+				Context("when dependencies are given", func() {
+					var dependency1 *dbfakes.FakeBuild
+					var dependency2 *dbfakes.FakeBuild
+
+					BeforeEach(func() {
+						queryParams = "?depends_on=12&depends_on=13"
+
+						dependency1 = new(dbfakes.FakeBuild)
+						dependency1.IDReturns(12)
+						dependency1.TeamNameReturns("some-team")
+
+						dependency2 = new(dbfakes.FakeBuild)
+						dependency2.IDReturns(13)
+						dependency2.TeamNameReturns("some-team")
+
+						buildServerDB.GetBuildsReturns([]db.Build{dependency1, dependency2}, nil)
+					})
+
+					It("returns 201 Created", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusCreated))
+					})
+
+					It("looks the dependencies up", func() {
+						Expect(buildServerDB.GetBuildsCallCount()).To(Equal(1))
+						_, buildIDs := buildServerDB.GetBuildsArgsForCall(0)
+						Expect(buildIDs).To(Equal([]int{12, 13}))
+					})
+
+					It("creates the build with them and its plan", func() {
+						Expect(buildServerDB.CreateOneOffBuildCallCount()).To(Equal(1))
+
+						_, _, spec := buildServerDB.CreateOneOffBuildArgsForCall(0)
+						Expect(spec.DependsOn).To(Equal([]int{12, 13}))
+						Expect(spec.Plan).To(Equal(plan))
+					})
+
+					It("leaves the plan for the dependency starter to claim", func() {
+						Expect(build.ClaimPendingPlanCallCount()).To(BeZero())
+						Expect(fakeEngine.CreateBuildCallCount()).To(BeZero())
+					})
+
+					Context("when a dependency does not exist", func() {
+						BeforeEach(func() {
+							buildServerDB.GetBuildsReturns([]db.Build{dependency1}, nil)
+						})
+
+						It("returns 422 Unprocessable Entity", func() {
+							Expect(response.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+						})
+
+						It("does not create a build", func() {
+							Expect(buildServerDB.CreateOneOffBuildCallCount()).To(BeZero())
+						})
+					})
+
+					Context("when a dependency belongs to another team", func() {
+						BeforeEach(func() {
+							dependency2.TeamNameReturns("some-other-team")
+						})
+
+						It("returns 422 Unprocessable Entity", func() {
+							Expect(response.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+						})
+					})
+
+					Context("when looking them up fails", func() {
+						BeforeEach(func() {
+							buildServerDB.GetBuildsReturns(nil, errors.New("nope"))
+						})
+
+						It("returns 500 Internal Server Error", func() {
+							Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+						})
+					})
+				})
+
+				Context("when a dependency is not a build id", func() {
+					BeforeEach(func() {
+						queryParams = "?depends_on=latest"
+					})
+
+					It("returns 400 Bad Request", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+					})
+
+					It("does not create a build", func() {
+						Expect(buildServerDB.CreateOneOffBuildCallCount()).To(BeZero())
+					})
+				})
 			})
 
 			Context("when creating a one-off build fails", func() {
 				BeforeEach(func() {
-					teamDB.CreateOneOffBuildReturns(nil, errors.New("oh no!"))
+					buildServerDB.CreateOneOffBuildReturns(nil, errors.New("oh no!"))
 				})
 
 				It("returns 500 Internal Server Error", func() {
@@ -167,7 +419,7 @@ var _ = Describe("Builds API", func() {
 			})
 
 			It("does not trigger a build", func() {
-				Expect(teamDB.CreateOneOffBuildCallCount()).To(BeZero())
+				Expect(buildServerDB.CreateOneOffBuildCallCount()).To(BeZero())
 				Expect(fakeEngine.CreateBuildCallCount()).To(BeZero())
 			})
 		})
@@ -300,6 +552,20 @@ var _ = Describe("Builds API", func() {
 						Expect(response.StatusCode).To(Equal(http.StatusOK))
 					})
 
+					It("returns 304 when fetched again with the same ETag", func() {
+						etag := response.Header.Get("ETag")
+						Expect(etag).NotTo(BeEmpty())
+
+						request, err := http.NewRequest("GET", server.URL+"/api/v1/builds/1", nil)
+						Expect(err).NotTo(HaveOccurred())
+
+						request.Header.Set("If-None-Match", etag)
+
+						refetched, err := client.Do(request)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(refetched.StatusCode).To(Equal(http.StatusNotModified))
+					})
+
 					It("returns the build with the given build_id", func() {
 						Expect(buildsDB.GetBuildByIDCallCount()).To(Equal(1))
 						buildID := buildsDB.GetBuildByIDArgsForCall(0)
@@ -322,7 +588,251 @@ var _ = Describe("Builds API", func() {
 						"reap_time": 200
 					}`))
 					})
+
+					It("does not look up a queue position for builds that aren't pending", func() {
+						Expect(build.QueuePositionCallCount()).To(BeZero())
+					})
+
+					Context("when the build is pending", func() {
+						BeforeEach(func() {
+							build.StatusReturns(db.StatusPending)
+							build.QueuePositionReturns(3, true, nil)
+						})
+
+						It("includes its queue position", func() {
+							var returned atc.Build
+							err := json.NewDecoder(response.Body).Decode(&returned)
+							Expect(err).NotTo(HaveOccurred())
+
+							Expect(returned.QueuePosition).To(Equal(3))
+						})
+
+						Context("when looking up the queue position fails", func() {
+							BeforeEach(func() {
+								build.QueuePositionReturns(0, false, errors.New("disaster"))
+							})
+
+							It("returns 500 Internal Server Error", func() {
+								Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+							})
+						})
+					})
+
+					Context("when the build's resource usage has been measured", func() {
+						BeforeEach(func() {
+							build.ResourceUsageReturns(db.ResourceUsage{
+								CPUNanoseconds: 100,
+								MemoryBytes:    4096,
+								DiskBytes:      10,
+							})
+						})
+
+						It("includes it", func() {
+							var returned atc.Build
+							err := json.NewDecoder(response.Body).Decode(&returned)
+							Expect(err).NotTo(HaveOccurred())
+
+							Expect(returned.Usage).To(Equal(&atc.BuildUsage{
+								CPUNanoseconds: 100,
+								MemoryBytes:    4096,
+								DiskBytes:      10,
+							}))
+						})
+					})
+				})
+			})
+		})
+	})
+
+	Describe("GET /api/v1/builds/:build_id/diff", func() {
+		var response *http.Response
+
+		BeforeEach(func() {
+			build.JobNameReturns("some-job")
+			build.TeamNameReturns("some-team")
+			buildsDB.GetBuildByIDReturns(build, true, nil)
+
+			authValidator.IsAuthenticatedReturns(true)
+			userContextReader.GetTeamReturns("some-team", 5, false, true)
+
+			build.GetResourcesReturns([]db.BuildInput{
+				{
+					Name: "some-repo",
+					VersionedResource: db.VersionedResource{
+						Resource:   "some-repo",
+						Type:       "git",
+						Version:    db.Version{"ref": "c3"},
+						Metadata:   []db.MetadataField{{Name: "message", Value: "fix it"}},
+						PipelineID: 42,
+					},
+				},
+				{
+					Name: "some-image",
+					VersionedResource: db.VersionedResource{
+						Resource:   "some-image",
+						Type:       "docker-image",
+						Version:    db.Version{"digest": "d1"},
+						PipelineID: 42,
+					},
+				},
+			}, nil, nil)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+
+			response, err = client.Get(server.URL + "/api/v1/builds/3/diff")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		readDiff := func() atc.BuildDiff {
+			var diff atc.BuildDiff
+			err := json.NewDecoder(response.Body).Decode(&diff)
+			Expect(err).NotTo(HaveOccurred())
+			return diff
+		}
+
+		Context("when the job has succeeded before", func() {
+			var previousBuild *dbfakes.FakeBuild
+
+			BeforeEach(func() {
+				previousBuild = new(dbfakes.FakeBuild)
+				previousBuild.IDReturns(2)
+				previousBuild.NameReturns("2")
+				previousBuild.JobNameReturns("some-job")
+				previousBuild.TeamNameReturns("some-team")
+				previousBuild.PipelineNameReturns("some-pipeline")
+				previousBuild.StatusReturns(db.StatusSucceeded)
+				previousBuild.GetResourcesReturns([]db.BuildInput{
+					{
+						Name: "some-repo",
+						VersionedResource: db.VersionedResource{
+							Resource:   "some-repo",
+							Type:       "git",
+							Version:    db.Version{"ref": "c1"},
+							PipelineID: 42,
+						},
+					},
+					{
+						Name: "some-image",
+						VersionedResource: db.VersionedResource{
+							Resource:   "some-image",
+							Type:       "docker-image",
+							Version:    db.Version{"digest": "d1"},
+							PipelineID: 42,
+						},
+					},
+					{
+						Name: "some-dropped-input",
+						VersionedResource: db.VersionedResource{
+							Resource:   "some-dropped-input",
+							Type:       "s3",
+							Version:    db.Version{"path": "p1"},
+							PipelineID: 42,
+						},
+					},
+				}, nil, nil)
+
+				build.GetPreviousSuccessfulBuildReturns(previousBuild, true, nil)
+
+				buildServerDB.GetResourceVersionsBetweenReturns([]db.SavedVersionedResource{
+					{
+						ID: 3,
+						VersionedResource: db.VersionedResource{
+							Resource: "some-repo",
+							Version:  db.Version{"ref": "c3"},
+							Metadata: []db.MetadataField{{Name: "message", Value: "fix it"}},
+						},
+					},
+					{
+						ID: 2,
+						VersionedResource: db.VersionedResource{
+							Resource: "some-repo",
+							Version:  db.Version{"ref": "c2"},
+							Metadata: []db.MetadataField{{Name: "message", Value: "break it"}},
+						},
+					},
+				}, nil)
+			})
+
+			It("returns 200", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+			})
+
+			It("names the previous build", func() {
+				diff := readDiff()
+				Expect(diff.PreviousBuild).NotTo(BeNil())
+				Expect(diff.PreviousBuild.ID).To(Equal(2))
+			})
+
+			It("compares each input with the previous build's", func() {
+				diff := readDiff()
+				Expect(diff.Inputs).To(HaveLen(3))
+
+				Expect(diff.Inputs[0].Name).To(Equal("some-repo"))
+				Expect(diff.Inputs[0].Changed).To(BeTrue())
+				Expect(diff.Inputs[0].Input.Version).To(Equal(atc.Version{"ref": "c3"}))
+				Expect(diff.Inputs[0].Input.Metadata).To(Equal([]atc.MetadataField{{Name: "message", Value: "fix it"}}))
+				Expect(diff.Inputs[0].Previous.Version).To(Equal(atc.Version{"ref": "c1"}))
+
+				Expect(diff.Inputs[1].Name).To(Equal("some-image"))
+				Expect(diff.Inputs[1].Changed).To(BeFalse())
+				Expect(diff.Inputs[1].NewVersions).To(BeEmpty())
+
+				Expect(diff.Inputs[2].Name).To(Equal("some-dropped-input"))
+				Expect(diff.Inputs[2].Changed).To(BeTrue())
+				Expect(diff.Inputs[2].Input).To(BeNil())
+				Expect(diff.Inputs[2].Previous.Version).To(Equal(atc.Version{"path": "p1"}))
+			})
+
+			It("lists the versions found in between for changed inputs", func() {
+				diff := readDiff()
+				Expect(diff.Inputs[0].NewVersions).To(HaveLen(2))
+				Expect(diff.Inputs[0].NewVersions[1].Version).To(Equal(atc.Version{"ref": "c2"}))
+				Expect(diff.Inputs[0].NewVersions[1].Metadata).To(Equal([]atc.MetadataField{{Name: "message", Value: "break it"}}))
+
+				Expect(buildServerDB.GetResourceVersionsBetweenCallCount()).To(Equal(1))
+				_, pipelineID, resourceName, after, upTo, limit := buildServerDB.GetResourceVersionsBetweenArgsForCall(0)
+				Expect(pipelineID).To(Equal(42))
+				Expect(resourceName).To(Equal("some-repo"))
+				Expect(after).To(Equal(db.Version{"ref": "c1"}))
+				Expect(upTo).To(Equal(db.Version{"ref": "c3"}))
+				Expect(limit).To(Equal(buildserver.DiffVersionLimit))
+			})
+
+			Context("when getting the versions in between fails", func() {
+				BeforeEach(func() {
+					buildServerDB.GetResourceVersionsBetweenReturns(nil, errors.New("nope"))
 				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+
+		Context("when the job has not succeeded before", func() {
+			BeforeEach(func() {
+				build.GetPreviousSuccessfulBuildReturns(nil, false, nil)
+			})
+
+			It("counts every input as changed", func() {
+				diff := readDiff()
+				Expect(diff.PreviousBuild).To(BeNil())
+				Expect(diff.Inputs).To(HaveLen(2))
+				Expect(diff.Inputs[0].Changed).To(BeTrue())
+				Expect(diff.Inputs[0].Previous).To(BeNil())
+				Expect(diff.Inputs[1].Changed).To(BeTrue())
+			})
+		})
+
+		Context("when getting the previous build fails", func() {
+			BeforeEach(func() {
+				build.GetPreviousSuccessfulBuildReturns(nil, false, errors.New("nope"))
+			})
+
+			It("returns 500", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
 			})
 		})
 	})
@@ -585,10 +1095,12 @@ var _ = Describe("Builds API", func() {
 	Describe("GET /api/v1/builds", func() {
 		var response *http.Response
 		var queryParams string
+		var acceptEncoding string
 		var returnedBuilds []db.Build
 
 		BeforeEach(func() {
 			queryParams = ""
+			acceptEncoding = ""
 			build1 := new(dbfakes.FakeBuild)
 			build1.IDReturns(4)
 			build1.NameReturns("2")
@@ -617,30 +1129,82 @@ var _ = Describe("Builds API", func() {
 		})
 
 		JustBeforeEach(func() {
-			var err error
+			request, err := http.NewRequest("GET", server.URL+"/api/v1/builds"+queryParams, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			if acceptEncoding != "" {
+				request.Header.Set("Accept-Encoding", acceptEncoding)
+			}
 
-			response, err = client.Get(server.URL + "/api/v1/builds" + queryParams)
+			response, err = client.Do(request)
 			Expect(err).NotTo(HaveOccurred())
 		})
 
-		Context("when not authenticated", func() {
+		Context("when the builds are fetched again", func() {
+			var refetch func() *http.Response
+
 			BeforeEach(func() {
-				authValidator.IsAuthenticatedReturns(false)
-				userContextReader.GetTeamReturns("", 0, false, false)
-			})
+				buildServerDB.GetPublicBuildsReturns(returnedBuilds, db.Pagination{}, nil)
 
-			Context("when no params are passed", func() {
-				BeforeEach(func() {
-					queryParams = ""
-				})
+				refetch = func() *http.Response {
+					request, err := http.NewRequest("GET", server.URL+"/api/v1/builds", nil)
+					Expect(err).NotTo(HaveOccurred())
 
-				It("does not set defaults for since and until", func() {
-					Expect(buildServerDB.GetPublicBuildsCallCount()).To(Equal(1))
+					request.Header.Set("If-None-Match", response.Header.Get("ETag"))
 
-					page := buildServerDB.GetPublicBuildsArgsForCall(0)
-					Expect(page).To(Equal(db.Page{
-						Since: 0,
-						Until: 0,
+					refetched, err := client.Do(request)
+					Expect(err).NotTo(HaveOccurred())
+
+					return refetched
+				}
+			})
+
+			It("tags the response", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+				Expect(response.Header.Get("ETag")).To(MatchRegexp(`^W/"[0-9a-f]+"$`))
+			})
+
+			Context("when they have not changed", func() {
+				It("returns 304 without a body", func() {
+					refetched := refetch()
+					Expect(refetched.StatusCode).To(Equal(http.StatusNotModified))
+					Expect(refetched.Header.Get("ETag")).To(Equal(response.Header.Get("ETag")))
+
+					body, err := ioutil.ReadAll(refetched.Body)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(body).To(BeEmpty())
+				})
+			})
+
+			Context("when one of them has changed", func() {
+				It("returns them again", func() {
+					returnedBuilds[0].(*dbfakes.FakeBuild).StatusReturns(db.StatusSucceeded)
+
+					refetched := refetch()
+					Expect(refetched.StatusCode).To(Equal(http.StatusOK))
+					Expect(refetched.Header.Get("ETag")).NotTo(Equal(response.Header.Get("ETag")))
+				})
+			})
+		})
+
+		Context("when not authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+				userContextReader.GetTeamReturns("", 0, false, false)
+			})
+
+			Context("when no params are passed", func() {
+				BeforeEach(func() {
+					queryParams = ""
+				})
+
+				It("does not set defaults for since and until", func() {
+					Expect(buildServerDB.GetPublicBuildsCallCount()).To(Equal(1))
+
+					_, page, _ := buildServerDB.GetPublicBuildsArgsForCall(0)
+					Expect(page).To(Equal(db.Page{
+						Since: 0,
+						Until: 0,
 						Limit: 100,
 					}))
 				})
@@ -654,7 +1218,7 @@ var _ = Describe("Builds API", func() {
 				It("passes them through", func() {
 					Expect(buildServerDB.GetPublicBuildsCallCount()).To(Equal(1))
 
-					page := buildServerDB.GetPublicBuildsArgsForCall(0)
+					_, page, _ := buildServerDB.GetPublicBuildsArgsForCall(0)
 					Expect(page).To(Equal(db.Page{
 						Since: 2,
 						Until: 3,
@@ -663,6 +1227,98 @@ var _ = Describe("Builds API", func() {
 				})
 			})
 
+			Context("when the limit exceeds the maximum page size", func() {
+				BeforeEach(func() {
+					queryParams = "?limit=100000"
+				})
+
+				It("caps the limit", func() {
+					Expect(buildServerDB.GetPublicBuildsCallCount()).To(Equal(1))
+
+					_, page, _ := buildServerDB.GetPublicBuildsArgsForCall(0)
+					Expect(page.Limit).To(Equal(atc.PaginationAPIMaxLimit))
+				})
+			})
+
+			Context("when filtering by label", func() {
+				BeforeEach(func() {
+					queryParams = "?label=env:staging&label=user:some-user"
+
+					labelledBuild := new(dbfakes.FakeBuild)
+					labelledBuild.IDReturns(5)
+					labelledBuild.NameReturns("3")
+					labelledBuild.TeamNameReturns("some-team")
+					labelledBuild.StatusReturns(db.StatusSucceeded)
+					labelledBuild.LabelsReturns(map[string]string{"env": "staging", "user": "some-user"})
+
+					buildServerDB.GetPublicBuildsReturns([]db.Build{labelledBuild}, db.Pagination{
+						Next: &db.Page{Since: 5, Limit: 100},
+					}, nil)
+				})
+
+				It("filters the builds by the labels", func() {
+					Expect(buildServerDB.GetPublicBuildsCallCount()).To(Equal(1))
+
+					_, _, filter := buildServerDB.GetPublicBuildsArgsForCall(0)
+					Expect(filter).To(Equal(db.BuildFilter{
+						Labels: map[string]string{
+							"env":  "staging",
+							"user": "some-user",
+						},
+					}))
+				})
+
+				It("returns the labels of each build", func() {
+					body, err := ioutil.ReadAll(response.Body)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(body).To(MatchJSON(`[
+						{
+							"id": 5,
+							"name": "3",
+							"team_name": "some-team",
+							"status": "succeeded",
+							"url": "/builds/5",
+							"api_url": "/api/v1/builds/5",
+							"labels": {"env": "staging", "user": "some-user"}
+						}
+					]`))
+				})
+
+				It("keeps the filter in the pagination links", func() {
+					Expect(response.Header["Link"]).To(ConsistOf([]string{
+						fmt.Sprintf(`<%s/api/v1/builds?since=5&limit=100&label=env%%3Astaging&label=user%%3Asome-user>; rel="next"`, externalURL),
+					}))
+				})
+			})
+
+			Context("when a label filter is malformed", func() {
+				BeforeEach(func() {
+					queryParams = "?label=:staging"
+				})
+
+				It("returns 400 Bad Request", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+				})
+
+				It("does not look up any builds", func() {
+					Expect(buildServerDB.GetPublicBuildsCallCount()).To(BeZero())
+				})
+			})
+
+			Context("when the limit is negative", func() {
+				BeforeEach(func() {
+					queryParams = "?limit=-5"
+				})
+
+				It("uses the default limit", func() {
+					Expect(buildServerDB.GetPublicBuildsCallCount()).To(Equal(1))
+
+					_, page, _ := buildServerDB.GetPublicBuildsArgsForCall(0)
+					Expect(page.Limit).To(Equal(atc.PaginationAPIDefaultLimit))
+				})
+			})
+
 			Context("when getting the builds succeeds", func() {
 				BeforeEach(func() {
 					buildServerDB.GetPublicBuildsReturns(returnedBuilds, db.Pagination{}, nil)
@@ -707,6 +1363,26 @@ var _ = Describe("Builds API", func() {
 				})
 			})
 
+			Context("when the request accepts gzip", func() {
+				BeforeEach(func() {
+					acceptEncoding = "gzip"
+					buildServerDB.GetPublicBuildsReturns(returnedBuilds, db.Pagination{}, nil)
+				})
+
+				It("compresses the response", func() {
+					Expect(response.Header.Get("Content-Encoding")).To(Equal("gzip"))
+					Expect(response.Header.Get("Vary")).To(Equal("Accept-Encoding"))
+
+					gz, err := gzip.NewReader(response.Body)
+					Expect(err).NotTo(HaveOccurred())
+
+					var builds []atc.Build
+					err = json.NewDecoder(gz).Decode(&builds)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(builds).To(HaveLen(2))
+				})
+			})
+
 			Context("when next/previous pages are available", func() {
 				BeforeEach(func() {
 					buildServerDB.GetPublicBuildsReturns(returnedBuilds, db.Pagination{
@@ -748,7 +1424,7 @@ var _ = Describe("Builds API", func() {
 				It("does not set defaults for since and until", func() {
 					Expect(teamDB.GetPrivateAndPublicBuildsCallCount()).To(Equal(1))
 
-					page := teamDB.GetPrivateAndPublicBuildsArgsForCall(0)
+					page, _ := teamDB.GetPrivateAndPublicBuildsArgsForCall(0)
 					Expect(page).To(Equal(db.Page{
 						Since: 0,
 						Until: 0,
@@ -765,7 +1441,7 @@ var _ = Describe("Builds API", func() {
 				It("passes them through", func() {
 					Expect(teamDB.GetPrivateAndPublicBuildsCallCount()).To(Equal(1))
 
-					page := teamDB.GetPrivateAndPublicBuildsArgsForCall(0)
+					page, _ := teamDB.GetPrivateAndPublicBuildsArgsForCall(0)
 					Expect(page).To(Equal(db.Page{
 						Since: 2,
 						Until: 3,
@@ -850,102 +1526,464 @@ var _ = Describe("Builds API", func() {
 					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
 				})
 			})
+
+			Context("without a role", func() {
+				It("shows the builds of all of the team's pipelines", func() {
+					Expect(teamDB.GetPipelinesCallCount()).To(BeZero())
+
+					_, filter := teamDB.GetPrivateAndPublicBuildsArgsForCall(0)
+					Expect(filter.HiddenPipelineIDs).To(BeEmpty())
+				})
+			})
+
+			Context("when the role isn't enough to view some of the team's pipelines", func() {
+				BeforeEach(func() {
+					userContextReader.GetRoleReturns(atc.RoleViewer, true)
+
+					teamDB.GetPipelinesReturns([]db.SavedPipeline{
+						{ID: 1},
+						{ID: 2, Permissions: atc.PipelinePermissions{View: atc.RoleOperator}},
+						{ID: 3, Public: true, Permissions: atc.PipelinePermissions{View: atc.RoleOperator}},
+					}, nil)
+				})
+
+				It("leaves out the builds of the private ones", func() {
+					_, filter := teamDB.GetPrivateAndPublicBuildsArgsForCall(0)
+					Expect(filter.HiddenPipelineIDs).To(Equal([]int{2}))
+				})
+			})
+
+			Context("when getting the team's pipelines fails", func() {
+				BeforeEach(func() {
+					userContextReader.GetRoleReturns(atc.RoleViewer, true)
+					teamDB.GetPipelinesReturns(nil, errors.New("oh no!"))
+				})
+
+				It("returns 500 Internal Server Error", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+					Expect(teamDB.GetPrivateAndPublicBuildsCallCount()).To(BeZero())
+				})
+			})
 		})
 	})
 
-	Describe("GET /api/v1/builds/:build_id/events", func() {
-		var (
-			request  *http.Request
-			response *http.Response
-		)
+	Describe("GET /api/v1/teams/:team_name/builds", func() {
+		var response *http.Response
+		var queryParams string
+		var returnedBuilds []db.Build
 
 		BeforeEach(func() {
-			var err error
+			queryParams = ""
 
-			request, err = http.NewRequest("GET", server.URL+"/api/v1/builds/128/events", nil)
-			Expect(err).NotTo(HaveOccurred())
+			build1 := new(dbfakes.FakeBuild)
+			build1.IDReturns(4)
+			build1.NameReturns("2")
+			build1.TeamNameReturns("some-team")
+			build1.StatusReturns(db.StatusStarted)
+
+			build2 := new(dbfakes.FakeBuild)
+			build2.IDReturns(3)
+			build2.NameReturns("1")
+			build2.TeamNameReturns("some-team")
+			build2.StatusReturns(db.StatusSucceeded)
+
+			returnedBuilds = []db.Build{build1, build2}
 		})
 
 		JustBeforeEach(func() {
 			var err error
-
-			response, err = client.Do(request)
+			response, err = client.Get(server.URL + "/api/v1/teams/some-team/builds" + queryParams)
 			Expect(err).NotTo(HaveOccurred())
 		})
 
-		Context("when the build can be found", func() {
+		Context("when not authenticated", func() {
 			BeforeEach(func() {
-				build.JobNameReturns("some-job")
-				build.TeamNameReturns("some-team")
-				buildsDB.GetBuildByIDReturns(build, true, nil)
+				authValidator.IsAuthenticatedReturns(false)
 			})
 
-			Context("when authenticated, but not authorized", func() {
+			It("returns 401 Unauthorized", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+
+			It("does not look up any builds", func() {
+				Expect(teamDB.GetBuildsCallCount()).To(BeZero())
+				Expect(buildServerDB.GetPublicBuildsCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when authenticated as another team", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("other-team", 6, false, true)
+			})
+
+			It("returns 403 Forbidden", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+			})
+
+			It("does not look up any builds", func() {
+				Expect(teamDB.GetBuildsCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("some-team", 5, false, true)
+
+				teamDB.GetBuildsReturns(returnedBuilds, db.Pagination{}, nil)
+			})
+
+			It("returns 200 OK", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+			})
+
+			It("returns only the team's builds", func() {
+				Expect(teamDB.GetBuildsCallCount()).To(Equal(1))
+				Expect(teamDB.GetPrivateAndPublicBuildsCallCount()).To(BeZero())
+
+				Expect(teamDBFactory.GetTeamDBCallCount()).To(Equal(1))
+				Expect(teamDBFactory.GetTeamDBArgsForCall(0)).To(Equal("some-team"))
+
+				body, err := ioutil.ReadAll(response.Body)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(body).To(MatchJSON(`[
+					{
+						"id": 4,
+						"name": "2",
+						"team_name": "some-team",
+						"status": "started",
+						"url": "/builds/4",
+						"api_url": "/api/v1/builds/4"
+					},
+					{
+						"id": 3,
+						"name": "1",
+						"team_name": "some-team",
+						"status": "succeeded",
+						"url": "/builds/3",
+						"api_url": "/api/v1/builds/3"
+					}
+				]`))
+			})
+
+			Context("when params are passed", func() {
 				BeforeEach(func() {
-					authValidator.IsAuthenticatedReturns(true)
-					userContextReader.GetTeamReturns("some-other-team", 5, false, true)
+					queryParams = "?since=2&limit=8&label=env:staging"
 				})
 
-				It("returns 403", func() {
-					Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+				It("passes them through", func() {
+					page, filter := teamDB.GetBuildsArgsForCall(0)
+					Expect(page).To(Equal(db.Page{Since: 2, Limit: 8}))
+					Expect(filter).To(Equal(db.BuildFilter{Labels: map[string]string{"env": "staging"}}))
 				})
 			})
 
-			Context("when authorized", func() {
+			Context("when next/previous pages are available", func() {
 				BeforeEach(func() {
-					authValidator.IsAuthenticatedReturns(true)
-					userContextReader.GetTeamReturns("some-team", 5, false, true)
+					teamDB.GetBuildsReturns(returnedBuilds, db.Pagination{
+						Previous: &db.Page{Until: 4, Limit: 2},
+						Next:     &db.Page{Since: 3, Limit: 2},
+					}, nil)
 				})
 
-				It("returns 200", func() {
-					Expect(response.StatusCode).To(Equal(200))
+				It("links to pages of the team's builds", func() {
+					Expect(response.Header["Link"]).To(ConsistOf([]string{
+						fmt.Sprintf(`<%s/api/v1/teams/some-team/builds?until=4&limit=2>; rel="previous"`, externalURL),
+						fmt.Sprintf(`<%s/api/v1/teams/some-team/builds?since=3&limit=2>; rel="next"`, externalURL),
+					}))
 				})
+			})
 
-				It("serves the request via the event handler", func() {
-					body, err := ioutil.ReadAll(response.Body)
-					Expect(err).NotTo(HaveOccurred())
-
-					Expect(string(body)).To(Equal("fake event handler factory was here"))
+			Context("when getting the builds fails", func() {
+				BeforeEach(func() {
+					teamDB.GetBuildsReturns(nil, db.Pagination{}, errors.New("oh no!"))
+				})
 
-					Expect(constructedEventHandler.build).To(Equal(build))
-					Expect(buildsDB.GetBuildByIDCallCount()).To(Equal(1))
-					buildID := buildsDB.GetBuildByIDArgsForCall(0)
-					Expect(buildID).To(Equal(128))
+				It("returns 500 Internal Server Error", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
 				})
 			})
 
-			Context("when not authenticated", func() {
+			Context("when the role isn't enough to view some of the team's pipelines", func() {
 				BeforeEach(func() {
-					authValidator.IsAuthenticatedReturns(false)
-				})
+					userContextReader.GetRoleReturns(atc.RoleViewer, true)
 
-				Context("and the pipeline is private", func() {
-					BeforeEach(func() {
-						build.GetPipelineReturns(db.SavedPipeline{Public: false}, nil)
-					})
+					teamDB.GetPipelinesReturns([]db.SavedPipeline{
+						{ID: 1},
+						{ID: 2, Permissions: atc.PipelinePermissions{View: atc.RoleOperator}},
+					}, nil)
+				})
 
-					It("returns 401", func() {
-						Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
-					})
+				It("leaves out their builds", func() {
+					_, filter := teamDB.GetBuildsArgsForCall(0)
+					Expect(filter.HiddenPipelineIDs).To(Equal([]int{2}))
 				})
+			})
+		})
+	})
 
-				Context("and the pipeline is public", func() {
-					BeforeEach(func() {
-						build.GetPipelineReturns(db.SavedPipeline{Public: true}, nil)
-					})
+	Describe("POST /api/v1/teams/:team_name/builds", func() {
+		var response *http.Response
 
-					It("looks up the config from the buildsDB", func() {
-						Expect(build.GetConfigCallCount()).To(Equal(1))
-					})
+		JustBeforeEach(func() {
+			reqPayload, err := json.Marshal(atc.Plan{
+				Task: &atc.TaskPlan{
+					Config: &atc.TaskConfig{
+						Run: atc.TaskRunConfig{Path: "ls"},
+					},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
 
-					Context("and the job is private", func() {
-						BeforeEach(func() {
-							build.GetConfigReturns(atc.Config{
-								Jobs: atc.JobConfigs{
-									{Name: "some-job", Public: false},
-								},
-							}, 1, nil)
-						})
+			req, err := http.NewRequest("POST", server.URL+"/api/v1/teams/some-team/builds", bytes.NewBuffer(reqPayload))
+			Expect(err).NotTo(HaveOccurred())
+
+			req.Header.Set("Content-Type", "application/json")
+
+			response, err = client.Do(req)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when authenticated as another team", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("other-team", 6, false, true)
+			})
+
+			It("returns 403 Forbidden", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+			})
+
+			It("does not create a build", func() {
+				Expect(buildServerDB.CreateOneOffBuildCallCount()).To(BeZero())
+				Expect(fakeEngine.CreateBuildCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when authorized", func() {
+			var fakeBuild *enginefakes.FakeBuild
+
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("some-team", 5, false, true)
+
+				build.IDReturns(42)
+				build.NameReturns("1")
+				build.TeamNameReturns("some-team")
+				build.StatusReturns(db.StatusStarted)
+				build.ReloadReturns(true, nil)
+				build.ClaimPendingPlanReturns(atc.Plan{}, true, nil)
+				buildServerDB.CreateOneOffBuildReturns(build, nil)
+
+				fakeBuild = new(enginefakes.FakeBuild)
+				fakeEngine.CreateBuildReturns(fakeBuild, nil)
+			})
+
+			It("returns 201 Created", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusCreated))
+			})
+
+			It("creates a one-off build for the team", func() {
+				Expect(buildServerDB.CreateOneOffBuildCallCount()).To(Equal(1))
+
+				_, teamName, _ := buildServerDB.CreateOneOffBuildArgsForCall(0)
+				Expect(teamName).To(Equal("some-team"))
+
+				Expect(fakeEngine.CreateBuildCallCount()).To(Equal(1))
+			})
+		})
+	})
+
+	Describe("GET /api/v1/builds/:build_id/events", func() {
+		var (
+			request  *http.Request
+			response *http.Response
+		)
+
+		BeforeEach(func() {
+			var err error
+
+			request, err = http.NewRequest("GET", server.URL+"/api/v1/builds/128/events", nil)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		JustBeforeEach(func() {
+			var err error
+
+			response, err = client.Do(request)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when the build can be found", func() {
+			BeforeEach(func() {
+				build.JobNameReturns("some-job")
+				build.TeamNameReturns("some-team")
+				buildsDB.GetBuildByIDReturns(build, true, nil)
+			})
+
+			Context("when authenticated, but not authorized", func() {
+				BeforeEach(func() {
+					authValidator.IsAuthenticatedReturns(true)
+					userContextReader.GetTeamReturns("some-other-team", 5, false, true)
+				})
+
+				It("returns 403", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+				})
+			})
+
+			Context("when authorized", func() {
+				BeforeEach(func() {
+					authValidator.IsAuthenticatedReturns(true)
+					userContextReader.GetTeamReturns("some-team", 5, false, true)
+				})
+
+				It("returns 200", func() {
+					Expect(response.StatusCode).To(Equal(200))
+				})
+
+				It("serves the request via the event handler", func() {
+					body, err := ioutil.ReadAll(response.Body)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(string(body)).To(Equal("fake event handler factory was here"))
+
+					Expect(constructedEventHandler.build).To(Equal(build))
+					Expect(buildsDB.GetBuildByIDCallCount()).To(Equal(1))
+					buildID := buildsDB.GetBuildByIDArgsForCall(0)
+					Expect(buildID).To(Equal(128))
+				})
+
+				Context("when given an event to list the events around", func() {
+					BeforeEach(func() {
+						build.IDReturns(128)
+
+						buildServerDB.GetBuildEventsFromStub = func(_ context.Context, _ int, offset uint, limit int) ([]event.Envelope, error) {
+							events := make([]event.Envelope, limit)
+							for i := range events {
+								data := json.RawMessage(fmt.Sprintf(`{"payload":"line %d\n"}`, int(offset)+i))
+								events[i] = event.Envelope{
+									Data:    &data,
+									Event:   event.EventTypeLog,
+									Version: "5.0",
+								}
+							}
+
+							return events, nil
+						}
+					})
+
+					Context("with some context", func() {
+						BeforeEach(func() {
+							request.URL.RawQuery = "around=2045&context=2"
+						})
+
+						It("lists the events either side of it, with their ids", func() {
+							Expect(response.StatusCode).To(Equal(http.StatusOK))
+							Expect(response.Header.Get("Content-Type")).To(Equal("application/json"))
+
+							body, err := ioutil.ReadAll(response.Body)
+							Expect(err).NotTo(HaveOccurred())
+
+							Expect(body).To(MatchJSON(`[
+								{"id":2043,"event":"log","version":"5.0","data":{"payload":"line 2043\n"}},
+								{"id":2044,"event":"log","version":"5.0","data":{"payload":"line 2044\n"}},
+								{"id":2045,"event":"log","version":"5.0","data":{"payload":"line 2045\n"}},
+								{"id":2046,"event":"log","version":"5.0","data":{"payload":"line 2046\n"}},
+								{"id":2047,"event":"log","version":"5.0","data":{"payload":"line 2047\n"}}
+							]`))
+
+							Expect(constructedEventHandler.build).To(BeNil())
+
+							Expect(buildServerDB.GetBuildEventsFromCallCount()).To(Equal(1))
+							_, buildID, offset, limit := buildServerDB.GetBuildEventsFromArgsForCall(0)
+							Expect(buildID).To(Equal(128))
+							Expect(offset).To(Equal(uint(2043)))
+							Expect(limit).To(Equal(5))
+						})
+					})
+
+					Context("with no context given", func() {
+						BeforeEach(func() {
+							request.URL.RawQuery = "around=2045"
+						})
+
+						It("lists the default number either side", func() {
+							Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+							Expect(buildServerDB.GetBuildEventsFromCallCount()).To(Equal(1))
+							_, _, offset, limit := buildServerDB.GetBuildEventsFromArgsForCall(0)
+							Expect(offset).To(Equal(uint(2045 - buildserver.DefaultEventContext)))
+							Expect(limit).To(Equal(2*buildserver.DefaultEventContext + 1))
+						})
+					})
+
+					Context("when the event is near the start", func() {
+						BeforeEach(func() {
+							request.URL.RawQuery = "around=1&context=50"
+						})
+
+						It("lists from the first event", func() {
+							Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+							Expect(buildServerDB.GetBuildEventsFromCallCount()).To(Equal(1))
+							_, _, offset, limit := buildServerDB.GetBuildEventsFromArgsForCall(0)
+							Expect(offset).To(BeZero())
+							Expect(limit).To(Equal(52))
+						})
+					})
+
+					for _, invalid := range []string{"around=-1", "around=last", "around=3&context=-1", "around=3&context=lots"} {
+						invalid := invalid
+
+						Context("when given "+invalid, func() {
+							BeforeEach(func() {
+								request.URL.RawQuery = invalid
+							})
+
+							It("returns 400", func() {
+								Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+								Expect(buildServerDB.GetBuildEventsFromCallCount()).To(BeZero())
+							})
+						})
+					}
+				})
+			})
+
+			Context("when not authenticated", func() {
+				BeforeEach(func() {
+					authValidator.IsAuthenticatedReturns(false)
+				})
+
+				Context("and the pipeline is private", func() {
+					BeforeEach(func() {
+						build.GetPipelineReturns(db.SavedPipeline{Public: false}, nil)
+					})
+
+					It("returns 401", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+					})
+				})
+
+				Context("and the pipeline is public", func() {
+					BeforeEach(func() {
+						build.GetPipelineReturns(db.SavedPipeline{Public: true}, nil)
+					})
+
+					It("looks up the config from the buildsDB", func() {
+						Expect(build.GetConfigCallCount()).To(Equal(1))
+					})
+
+					Context("and the job is private", func() {
+						BeforeEach(func() {
+							build.GetConfigReturns(atc.Config{
+								Jobs: atc.JobConfigs{
+									{Name: "some-job", Public: false},
+								},
+							}, 1, nil)
+						})
 
 						It("returns 401", func() {
 							Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
@@ -1012,6 +2050,107 @@ var _ = Describe("Builds API", func() {
 		})
 	})
 
+	Describe("PUT /api/v1/builds/:build_id/priority", func() {
+		var (
+			body     string
+			response *http.Response
+		)
+
+		BeforeEach(func() {
+			body = `{"priority":7}`
+
+			build.IDReturns(128)
+			build.NameReturns("3")
+			build.TeamNameReturns("some-team")
+			build.StatusReturns(db.StatusPending)
+			buildsDB.GetBuildByIDReturns(build, true, nil)
+		})
+
+		JustBeforeEach(func() {
+			req, err := http.NewRequest("PUT", server.URL+"/api/v1/builds/128/priority", bytes.NewBufferString(body))
+			Expect(err).NotTo(HaveOccurred())
+
+			response, err = client.Do(req)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when not authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns 401", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+				Expect(build.SetPriorityCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("some-team", 5, false, true)
+			})
+
+			Context("when the build is still pending", func() {
+				BeforeEach(func() {
+					build.SetPriorityStub = func(priority int) (bool, error) {
+						build.PriorityReturns(priority)
+						return true, nil
+					}
+				})
+
+				It("sets the priority and returns the build", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+					Expect(build.SetPriorityArgsForCall(0)).To(Equal(7))
+
+					body, err := ioutil.ReadAll(response.Body)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(body).To(MatchJSON(`{
+						"id": 128,
+						"name": "3",
+						"team_name": "some-team",
+						"status": "pending",
+						"url": "/builds/128",
+						"api_url": "/api/v1/builds/128",
+						"priority": 7
+					}`))
+				})
+			})
+
+			Context("when the build is no longer pending", func() {
+				BeforeEach(func() {
+					build.SetPriorityReturns(false, nil)
+				})
+
+				It("returns 409", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusConflict))
+				})
+			})
+
+			Context("when setting the priority fails", func() {
+				BeforeEach(func() {
+					build.SetPriorityReturns(false, errors.New("nope"))
+				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+
+			Context("when the request body is malformed", func() {
+				BeforeEach(func() {
+					body = `{`
+				})
+
+				It("returns 400", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+					Expect(build.SetPriorityCallCount()).To(BeZero())
+				})
+			})
+		})
+	})
+
 	Describe("POST /api/v1/builds/:build_id/abort", func() {
 		var (
 			abortTarget *ghttp.Server
@@ -1055,27 +2194,76 @@ var _ = Describe("Builds API", func() {
 				Context("when accessing same team's build", func() {
 					BeforeEach(func() {
 						userContextReader.GetTeamReturns("some-team", 2, true, true)
+						build.IsRunningReturns(true)
 					})
 
-					Context("when the engine returns a build", func() {
-						var fakeBuild *enginefakes.FakeBuild
-
+					Context("when triggering the build's pipeline needs the admin role", func() {
 						BeforeEach(func() {
-							fakeBuild = new(enginefakes.FakeBuild)
-							fakeEngine.LookupBuildReturns(fakeBuild, nil)
-						})
-
-						It("aborts the build", func() {
-							Expect(fakeBuild.AbortCallCount()).To(Equal(1))
+							build.GetPipelineReturns(db.SavedPipeline{
+								Permissions: atc.PipelinePermissions{Trigger: atc.RoleAdmin},
+							}, nil)
 						})
 
-						Context("when aborting succeeds", func() {
+						Context("and the request has the operator role", func() {
 							BeforeEach(func() {
-								fakeBuild.AbortReturns(nil)
+								userContextReader.GetRoleReturns(atc.RoleOperator, true)
 							})
 
-							It("returns 204", func() {
-								Expect(response.StatusCode).To(Equal(http.StatusNoContent))
+							It("returns 403", func() {
+								Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+							})
+
+							It("does not abort the build", func() {
+								Expect(fakeEngine.LookupBuildCallCount()).To(BeZero())
+							})
+						})
+
+						Context("and the build is a one-off", func() {
+							BeforeEach(func() {
+								userContextReader.GetRoleReturns(atc.RoleOperator, true)
+								build.IsOneOffReturns(true)
+							})
+
+							It("does not look up a pipeline", func() {
+								Expect(build.GetPipelineCallCount()).To(BeZero())
+							})
+						})
+					})
+
+					Context("when the build has already finished", func() {
+						BeforeEach(func() {
+							build.IsRunningReturns(false)
+							build.StatusReturns(db.StatusSucceeded)
+						})
+
+						It("returns 409", func() {
+							Expect(response.StatusCode).To(Equal(http.StatusConflict))
+						})
+
+						It("does not abort the build", func() {
+							Expect(fakeEngine.LookupBuildCallCount()).To(BeZero())
+						})
+					})
+
+					Context("when the engine returns a build", func() {
+						var fakeBuild *enginefakes.FakeBuild
+
+						BeforeEach(func() {
+							fakeBuild = new(enginefakes.FakeBuild)
+							fakeEngine.LookupBuildReturns(fakeBuild, nil)
+						})
+
+						It("aborts the build", func() {
+							Expect(fakeBuild.AbortCallCount()).To(Equal(1))
+						})
+
+						Context("when aborting succeeds", func() {
+							BeforeEach(func() {
+								fakeBuild.AbortReturns(nil)
+							})
+
+							It("returns 204", func() {
+								Expect(response.StatusCode).To(Equal(http.StatusNoContent))
 							})
 						})
 
@@ -1095,43 +2283,1674 @@ var _ = Describe("Builds API", func() {
 							fakeEngine.LookupBuildReturns(nil, errors.New("oh no!"))
 						})
 
-						It("returns Internal Server Error", func() {
-							Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
-						})
-					})
+						It("returns Internal Server Error", func() {
+							Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+						})
+					})
+				})
+
+				Context("when accessing other team's build", func() {
+					BeforeEach(func() {
+						userContextReader.GetTeamReturns("some-other-team", 2, true, true)
+					})
+
+					It("returns 403", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+					})
+				})
+			})
+
+			Context("when the build can not be found", func() {
+				BeforeEach(func() {
+					buildsDB.GetBuildByIDReturns(nil, false, nil)
+				})
+
+				It("returns Not Found", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+				})
+			})
+
+			Context("when calling the database fails", func() {
+				BeforeEach(func() {
+					buildsDB.GetBuildByIDReturns(nil, false, errors.New("nope"))
+				})
+
+				It("returns Internal Server Error", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+
+		Context("when not authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns 401", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+
+			It("does not abort the build", func() {
+				Expect(abortTarget.ReceivedRequests()).To(BeEmpty())
+			})
+		})
+	})
+
+	Describe("POST /api/v1/builds/:build_id/rerun", func() {
+		var response *http.Response
+
+		JustBeforeEach(func() {
+			req, err := http.NewRequest("POST", server.URL+"/api/v1/builds/128/rerun", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			response, err = client.Do(req)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when not authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns 401", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+
+			It("does not create a build", func() {
+				Expect(teamDB.CreateRerunBuildCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+
+				build.IDReturns(128)
+				build.TeamNameReturns("some-team")
+				build.EngineReturns("exec.v2")
+				buildsDB.GetBuildByIDReturns(build, true, nil)
+			})
+
+			Context("when accessing other team's build", func() {
+				BeforeEach(func() {
+					userContextReader.GetTeamReturns("some-other-team", 2, false, true)
+				})
+
+				It("returns 403", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+				})
+
+				It("does not create a build", func() {
+					Expect(teamDB.CreateRerunBuildCallCount()).To(BeZero())
+				})
+			})
+
+			Context("when accessing same team's build", func() {
+				var originalBuild *enginefakes.FakeBuild
+				var rerun *dbfakes.FakeBuild
+				var rerunEngineBuild *enginefakes.FakeBuild
+
+				BeforeEach(func() {
+					userContextReader.GetTeamReturns("some-team", 2, false, true)
+
+					originalBuild = new(enginefakes.FakeBuild)
+					fakeEngine.LookupBuildReturns(originalBuild, nil)
+
+					originalBuild.PlanReturns(atc.Plan{
+						ID: "aggregate",
+						Aggregate: &atc.AggregatePlan{
+							{
+								ID: "get-pipeline-input",
+								Get: &atc.GetPlan{
+									Name:     "some-input",
+									Resource: "some-resource",
+								},
+							},
+							{
+								ID: "get-pinned-input",
+								Get: &atc.GetPlan{
+									Name:     "some-other-input",
+									Resource: "some-other-resource",
+									Version:  atc.Version{"ver": "pinned"},
+								},
+							},
+						},
+					}, nil)
+
+					build.GetResourcesReturns([]db.BuildInput{
+						{
+							Name: "some-input",
+							VersionedResource: db.VersionedResource{
+								Resource: "some-resource",
+								Version:  db.Version{"ver": "1"},
+							},
+						},
+						{
+							Name: "some-other-input",
+							VersionedResource: db.VersionedResource{
+								Resource: "some-other-resource",
+								Version:  db.Version{"ver": "2"},
+							},
+						},
+					}, nil, nil)
+
+					build.LabelsReturns(map[string]string{"env": "staging"})
+					build.GetSecretsReturns([]string{"some-password"}, nil)
+
+					rerun = new(dbfakes.FakeBuild)
+					rerun.IDReturns(129)
+					rerun.NameReturns("7")
+					rerun.TeamNameReturns("some-team")
+					rerun.StatusReturns(db.StatusStarted)
+					rerun.RerunOfReturns(128)
+					rerun.ReloadReturns(true, nil)
+					teamDB.CreateRerunBuildReturns(rerun, nil)
+
+					rerunEngineBuild = new(enginefakes.FakeBuild)
+					fakeEngine.CreateBuildReturns(rerunEngineBuild, nil)
+				})
+
+				It("returns 201 with the rerun, linked to the original", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusCreated))
+
+					body, err := ioutil.ReadAll(response.Body)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(body).To(MatchJSON(`{
+						"id": 129,
+						"name": "7",
+						"team_name": "some-team",
+						"status": "started",
+						"url": "/builds/129",
+						"api_url": "/api/v1/builds/129",
+						"rerun_of": 128
+					}`))
+				})
+
+				It("creates the rerun for the build's team", func() {
+					Expect(teamDBFactory.GetTeamDBArgsForCall(teamDBFactory.GetTeamDBCallCount() - 1)).To(Equal("some-team"))
+
+					Expect(teamDB.CreateRerunBuildCallCount()).To(Equal(1))
+					Expect(teamDB.CreateRerunBuildArgsForCall(0)).To(Equal(build))
+				})
+
+				It("carries over the original's labels", func() {
+					Expect(rerun.SaveLabelsCallCount()).To(Equal(1))
+					Expect(rerun.SaveLabelsArgsForCall(0)).To(Equal(map[string]string{"env": "staging"}))
+				})
+
+				It("carries over the original's secrets, so they're still redacted", func() {
+					Expect(rerun.SaveSecretsCallCount()).To(Equal(1))
+					Expect(rerun.SaveSecretsArgsForCall(0)).To(Equal([]string{"some-password"}))
+				})
+
+				It("runs the original plan with the original's input versions", func() {
+					Expect(fakeEngine.CreateBuildCallCount()).To(Equal(1))
+
+					_, createdBuild, plan := fakeEngine.CreateBuildArgsForCall(0)
+					Expect(createdBuild).To(Equal(rerun))
+					Expect(plan).To(Equal(atc.Plan{
+						ID: "aggregate",
+						Aggregate: &atc.AggregatePlan{
+							{
+								ID: "get-pipeline-input",
+								Get: &atc.GetPlan{
+									Name:     "some-input",
+									Resource: "some-resource",
+									Version:  atc.Version{"ver": "1"},
+								},
+							},
+							{
+								ID: "get-pinned-input",
+								Get: &atc.GetPlan{
+									Name:     "some-other-input",
+									Resource: "some-other-resource",
+									Version:  atc.Version{"ver": "pinned"},
+								},
+							},
+						},
+					}))
+
+					Eventually(rerunEngineBuild.ResumeCallCount).Should(Equal(1))
+				})
+
+				Context("when the build has not started", func() {
+					BeforeEach(func() {
+						build.EngineReturns("")
+					})
+
+					It("returns 409", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusConflict))
+					})
+
+					It("does not create a build", func() {
+						Expect(teamDB.CreateRerunBuildCallCount()).To(BeZero())
+					})
+				})
+
+				Context("when the engine did not keep the plan", func() {
+					BeforeEach(func() {
+						originalBuild.PlanReturns(atc.Plan{}, engine.ErrPlanUnavailable)
+					})
+
+					It("returns 422", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+					})
+
+					It("does not create a build", func() {
+						Expect(teamDB.CreateRerunBuildCallCount()).To(BeZero())
+					})
+				})
+
+				Context("when getting the plan fails", func() {
+					BeforeEach(func() {
+						originalBuild.PlanReturns(atc.Plan{}, errors.New("nope"))
+					})
+
+					It("returns 500", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+					})
+				})
+
+				Context("when creating the rerun fails", func() {
+					BeforeEach(func() {
+						teamDB.CreateRerunBuildReturns(nil, errors.New("nope"))
+					})
+
+					It("returns 500", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+					})
+
+					It("does not start anything", func() {
+						Expect(fakeEngine.CreateBuildCallCount()).To(BeZero())
+					})
+				})
+
+				Context("when starting the rerun fails", func() {
+					BeforeEach(func() {
+						fakeEngine.CreateBuildReturns(nil, errors.New("nope"))
+					})
+
+					It("returns 500", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+					})
+				})
+			})
+		})
+	})
+
+	Describe("GET /api/v1/builds/:build_id/log", func() {
+		var queryParams string
+		var response *http.Response
+		var eventSource *dbfakes.FakeEventSource
+
+		envelope := func(ev atc.Event, at time.Time) event.Envelope {
+			payload, err := json.Marshal(ev)
+			Expect(err).NotTo(HaveOccurred())
+
+			data := json.RawMessage(payload)
+
+			return event.Envelope{
+				Event:   ev.EventType(),
+				Version: ev.Version(),
+				Data:    &data,
+				Time:    at,
+			}
+		}
+
+		BeforeEach(func() {
+			queryParams = ""
+
+			build.JobNameReturns("some-job")
+			build.TeamNameReturns("some-team")
+			buildsDB.GetBuildByIDReturns(build, true, nil)
+
+			envelopes := []event.Envelope{
+				envelope(event.Log{Payload: "\x1b[1mfetching\x1b[0m some-"}, time.Date(2016, 1, 2, 3, 4, 5, 0, time.UTC)),
+				envelope(event.Status{Status: atc.StatusStarted}, time.Time{}),
+				envelope(event.Log{Payload: "input\ndone\n"}, time.Date(2016, 1, 2, 3, 4, 6, 0, time.UTC)),
+				envelope(event.Error{Message: "oh no"}, time.Date(2016, 1, 2, 3, 4, 7, 0, time.UTC)),
+			}
+
+			eventSource = new(dbfakes.FakeEventSource)
+			eventSource.NextStub = func() (event.Envelope, error) {
+				calls := eventSource.NextCallCount()
+				if calls > len(envelopes) {
+					return event.Envelope{}, db.ErrEndOfBuildEventStream
+				}
+
+				return envelopes[calls-1], nil
+			}
+
+			build.EventsReturns(eventSource, nil)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+
+			response, err = client.Get(server.URL + "/api/v1/builds/128/log" + queryParams)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when authenticated, but not authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("some-other-team", 5, false, true)
+			})
+
+			It("returns 403", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+			})
+		})
+
+		Context("when authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("some-team", 5, false, true)
+			})
+
+			It("returns the output as plain text, without escape sequences", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+				Expect(response.Header.Get("Content-Type")).To(Equal("text/plain; charset=utf-8"))
+
+				body, err := ioutil.ReadAll(response.Body)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(string(body)).To(Equal("fetching some-input\ndone\noh no\n"))
+			})
+
+			Context("when the build was warned about", func() {
+				BeforeEach(func() {
+					envelopes := []event.Envelope{
+						envelope(event.Log{Payload: "still going\n"}, time.Date(2016, 1, 2, 3, 4, 5, 0, time.UTC)),
+						envelope(event.Warning{Message: "build has been running for 40m0s"}, time.Date(2016, 1, 2, 3, 4, 6, 0, time.UTC)),
+					}
+
+					eventSource.NextStub = func() (event.Envelope, error) {
+						calls := eventSource.NextCallCount()
+						if calls > len(envelopes) {
+							return event.Envelope{}, db.ErrEndOfBuildEventStream
+						}
+
+						return envelopes[calls-1], nil
+					}
+				})
+
+				It("includes the warnings", func() {
+					body, err := ioutil.ReadAll(response.Body)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(string(body)).To(Equal("still going\nwarning: build has been running for 40m0s\n"))
+				})
+			})
+
+			It("reads the events from the start and closes them", func() {
+				Expect(build.EventsCallCount()).To(Equal(1))
+				Expect(build.EventsArgsForCall(0)).To(BeZero())
+
+				Eventually(eventSource.CloseCallCount).Should(Equal(1))
+			})
+
+			Context("when asked to keep escape sequences", func() {
+				BeforeEach(func() {
+					queryParams = "?ansi=true"
+				})
+
+				It("leaves them in", func() {
+					body, err := ioutil.ReadAll(response.Body)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(string(body)).To(Equal("\x1b[1mfetching\x1b[0m some-input\ndone\noh no\n"))
+				})
+			})
+
+			Context("when asked for timestamps", func() {
+				BeforeEach(func() {
+					queryParams = "?timestamps=true"
+				})
+
+				It("prefixes each line with the time it was started at", func() {
+					body, err := ioutil.ReadAll(response.Body)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(string(body)).To(Equal(
+						"2016-01-02T03:04:05Z fetching some-input\n" +
+							"2016-01-02T03:04:06Z done\n" +
+							"2016-01-02T03:04:07Z oh no\n",
+					))
+				})
+			})
+
+			Context("when asked to mark the time elapsed", func() {
+				BeforeEach(func() {
+					queryParams = "?elapsed=1s"
+				})
+
+				It("marks each interval before the next line", func() {
+					body, err := ioutil.ReadAll(response.Body)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(string(body)).To(Equal(
+						"fetching some-input\n" +
+							"--- 1s elapsed ---\n" +
+							"done\n" +
+							"--- 2s elapsed ---\n" +
+							"oh no\n",
+					))
+				})
+
+				Context("when the build has a start time", func() {
+					BeforeEach(func() {
+						queryParams = "?elapsed=1m"
+						build.StartTimeReturns(time.Date(2016, 1, 2, 3, 3, 5, 0, time.UTC))
+					})
+
+					It("marks the time since the build started", func() {
+						body, err := ioutil.ReadAll(response.Body)
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(string(body)).To(Equal(
+							"--- 1m0s elapsed ---\n" +
+								"fetching some-input\n" +
+								"done\n" +
+								"oh no\n",
+						))
+					})
+				})
+
+				Context("along with timestamps", func() {
+					BeforeEach(func() {
+						queryParams = "?elapsed=2s&timestamps=true"
+					})
+
+					It("prefixes the marks too", func() {
+						body, err := ioutil.ReadAll(response.Body)
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(string(body)).To(Equal(
+							"2016-01-02T03:04:05Z fetching some-input\n" +
+								"2016-01-02T03:04:06Z done\n" +
+								"2016-01-02T03:04:07Z --- 2s elapsed ---\n" +
+								"2016-01-02T03:04:07Z oh no\n",
+						))
+					})
+				})
+
+				Context("when the interval is malformed", func() {
+					BeforeEach(func() {
+						queryParams = "?elapsed=often"
+					})
+
+					It("returns 400", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+					})
+				})
+			})
+
+			Context("when getting the events fails", func() {
+				BeforeEach(func() {
+					build.EventsReturns(nil, errors.New("nope"))
+				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+	})
+
+	Describe("GET /api/v1/builds/:build_id/log.html", func() {
+		var queryParams string
+		var response *http.Response
+		var envelopes []event.Envelope
+		var eventSource *dbfakes.FakeEventSource
+
+		envelope := func(ev atc.Event, at time.Time) event.Envelope {
+			payload, err := json.Marshal(ev)
+			Expect(err).NotTo(HaveOccurred())
+
+			data := json.RawMessage(payload)
+
+			return event.Envelope{
+				Event:   ev.EventType(),
+				Version: ev.Version(),
+				Data:    &data,
+				Time:    at,
+			}
+		}
+
+		readPre := func() string {
+			body, err := ioutil.ReadAll(response.Body)
+			Expect(err).NotTo(HaveOccurred())
+
+			page := string(body)
+			Expect(page).To(HavePrefix("<!DOCTYPE html>"))
+			Expect(page).To(HaveSuffix("</pre>\n</body>\n</html>\n"))
+
+			start := strings.Index(page, "<pre>") + len("<pre>")
+			end := strings.LastIndex(page, "</pre>")
+			return page[start:end]
+		}
+
+		BeforeEach(func() {
+			queryParams = ""
+
+			build.IDReturns(128)
+			build.NameReturns("3")
+			build.JobNameReturns("some-job")
+			build.TeamNameReturns("some-team")
+			buildsDB.GetBuildByIDReturns(build, true, nil)
+
+			envelopes = []event.Envelope{
+				envelope(event.Log{Payload: "\x1b[1;31mfetching\x1b[0m <some>-"}, time.Date(2016, 1, 2, 3, 4, 5, 0, time.UTC)),
+				envelope(event.Status{Status: atc.StatusStarted}, time.Time{}),
+				envelope(event.Log{Payload: "input\n\x1b[38;5;196mdone\x1b["}, time.Date(2016, 1, 2, 3, 4, 6, 0, time.UTC)),
+				envelope(event.Log{Payload: "0m\n"}, time.Date(2016, 1, 2, 3, 4, 6, 0, time.UTC)),
+				envelope(event.Error{Message: "oh & no"}, time.Date(2016, 1, 2, 3, 4, 7, 0, time.UTC)),
+			}
+
+			eventSource = new(dbfakes.FakeEventSource)
+			eventSource.NextStub = func() (event.Envelope, error) {
+				calls := eventSource.NextCallCount()
+				if calls > len(envelopes) {
+					return event.Envelope{}, db.ErrEndOfBuildEventStream
+				}
+
+				return envelopes[calls-1], nil
+			}
+
+			build.EventsReturns(eventSource, nil)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+
+			response, err = client.Get(server.URL + "/api/v1/builds/128/log.html" + queryParams)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when authenticated, but not authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("some-other-team", 5, false, true)
+			})
+
+			It("returns 403", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+			})
+		})
+
+		Context("when authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("some-team", 5, false, true)
+			})
+
+			It("returns the output as HTML, with escape sequences turned into styled spans", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+				Expect(response.Header.Get("Content-Type")).To(Equal("text/html; charset=utf-8"))
+
+				Expect(readPre()).To(Equal(
+					`<span class="ansi-bold ansi-fg-1">fetching</span> &lt;some&gt;-input` + "\n" +
+						`<span style="color: #ff0000">done</span>` + "\n" +
+						"oh &amp; no\n",
+				))
+			})
+
+			It("titles the page with the build", func() {
+				body, err := ioutil.ReadAll(response.Body)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(string(body)).To(ContainSubstring("<title>some-job #3</title>"))
+			})
+
+			It("reads the events from the start and closes them", func() {
+				Expect(build.EventsCallCount()).To(Equal(1))
+				Expect(build.EventsArgsForCall(0)).To(BeZero())
+
+				Eventually(eventSource.CloseCallCount).Should(Equal(1))
+			})
+
+			Context("when the build ends with a style still set", func() {
+				BeforeEach(func() {
+					envelopes = []event.Envelope{
+						envelope(event.Log{Payload: "\x1b[4;42mstill going"}, time.Time{}),
+					}
+				})
+
+				It("closes the span", func() {
+					Expect(readPre()).To(Equal(`<span class="ansi-underline ansi-bg-2">still going</span>`))
+				})
+			})
+
+			Context("when the output has escape sequences that don't set styles", func() {
+				BeforeEach(func() {
+					envelopes = []event.Envelope{
+						envelope(event.Log{Payload: "\x1b[2Kprogress\x1b[?25l\r done"}, time.Time{}),
+					}
+				})
+
+				It("drops them", func() {
+					Expect(readPre()).To(Equal("progress\r done"))
+				})
+			})
+
+			Context("when asked for timestamps", func() {
+				BeforeEach(func() {
+					queryParams = "?timestamps=true"
+				})
+
+				It("prefixes each line with the time it was started at", func() {
+					Expect(readPre()).To(Equal(
+						`2016-01-02T03:04:05Z <span class="ansi-bold ansi-fg-1">fetching</span> &lt;some&gt;-input` + "\n" +
+							`2016-01-02T03:04:06Z <span style="color: #ff0000">done</span>` + "\n" +
+							"2016-01-02T03:04:07Z oh &amp; no\n",
+					))
+				})
+			})
+
+			Context("when getting the events fails", func() {
+				BeforeEach(func() {
+					build.EventsReturns(nil, errors.New("nope"))
+				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+	})
+
+	Describe("GET /api/v1/builds/:build_id/usage", func() {
+		var response *http.Response
+
+		BeforeEach(func() {
+			buildsDB.GetBuildByIDReturns(build, true, nil)
+			build.IDReturns(42)
+			build.JobNameReturns("job1")
+			build.TeamNameReturns("some-team")
+			build.ResourceUsageReturns(db.ResourceUsage{
+				CPUNanoseconds: 100,
+				MemoryBytes:    4096,
+				DiskBytes:      10,
+			})
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			response, err = client.Get(server.URL + "/api/v1/builds/42/usage")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("some-team", 5, false, true)
+			})
+
+			It("returns the build's resource usage", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+				Expect(response.Header.Get("Content-Type")).To(Equal("application/json"))
+
+				body, err := ioutil.ReadAll(response.Body)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(body).To(MatchJSON(`{
+					"cpu_nanoseconds": 100,
+					"memory_bytes": 4096,
+					"disk_bytes": 10
+				}`))
+			})
+		})
+
+		Context("when authenticated, but not authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("some-other-team", 5, false, true)
+			})
+
+			It("returns 403", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+			})
+		})
+	})
+
+	Describe("GET /api/v1/builds/:build_id/events/verification", func() {
+		var response *http.Response
+
+		BeforeEach(func() {
+			buildsDB.GetBuildByIDReturns(build, true, nil)
+			build.IDReturns(42)
+			build.JobNameReturns("job1")
+			build.TeamNameReturns("some-team")
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			response, err = client.Get(server.URL + "/api/v1/builds/42/events/verification")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("some-team", 5, false, true)
+			})
+
+			Context("when the events are intact", func() {
+				BeforeEach(func() {
+					build.VerifyEventsReturns(db.EventChainVerification{
+						UnsignedEvents: 2,
+						SignedEvents:   10,
+						Anchors:        1,
+						Valid:          true,
+					}, nil)
+				})
+
+				It("says so", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+					Expect(response.Header.Get("Content-Type")).To(Equal("application/json"))
+
+					body, err := ioutil.ReadAll(response.Body)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(body).To(MatchJSON(`{
+						"valid": true,
+						"signed_events": 10,
+						"unsigned_events": 2,
+						"anchors": 1
+					}`))
+				})
+			})
+
+			Context("when the chain is broken", func() {
+				BeforeEach(func() {
+					build.VerifyEventsReturns(db.EventChainVerification{
+						SignedEvents: 10,
+						Anchors:      1,
+						Valid:        false,
+						BrokenAt:     0,
+						Reason:       "signature does not match",
+					}, nil)
+				})
+
+				It("says where and why", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+					body, err := ioutil.ReadAll(response.Body)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(body).To(MatchJSON(`{
+						"valid": false,
+						"signed_events": 10,
+						"unsigned_events": 0,
+						"anchors": 1,
+						"broken_at": 0,
+						"reason": "signature does not match"
+					}`))
+				})
+			})
+
+			Context("when events aren't being signed", func() {
+				BeforeEach(func() {
+					build.VerifyEventsReturns(db.EventChainVerification{}, db.ErrEventSigningNotConfigured)
+				})
+
+				It("returns 404", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+				})
+			})
+
+			Context("when verifying fails", func() {
+				BeforeEach(func() {
+					build.VerifyEventsReturns(db.EventChainVerification{}, errors.New("nope"))
+				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+
+		Context("when authenticated, but not authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("some-other-team", 5, false, true)
+			})
+
+			It("returns 403", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+			})
+
+			It("doesn't verify anything", func() {
+				Expect(build.VerifyEventsCallCount()).To(BeZero())
+			})
+		})
+	})
+
+	Describe("GET /api/v1/builds/:build_id/events/list", func() {
+		var (
+			query    string
+			response *http.Response
+		)
+
+		logEvent := func(payload string) event.Envelope {
+			data := json.RawMessage(`{"payload":"` + payload + `"}`)
+			return event.Envelope{
+				Data:    &data,
+				Event:   event.EventTypeLog,
+				Version: "5.0",
+			}
+		}
+
+		BeforeEach(func() {
+			query = ""
+
+			buildsDB.GetBuildByIDReturns(build, true, nil)
+			build.IDReturns(42)
+			build.JobNameReturns("job1")
+			build.TeamNameReturns("some-team")
+
+			authValidator.IsAuthenticatedReturns(true)
+			userContextReader.GetTeamReturns("some-team", 5, false, true)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			response, err = client.Get(server.URL + "/api/v1/builds/42/events/list" + query)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when given an offset and limit", func() {
+			BeforeEach(func() {
+				query = "?offset=3&limit=2"
+
+				buildServerDB.GetBuildEventsFromReturns([]event.Envelope{
+					logEvent("fourth"),
+					logEvent("fifth"),
+				}, nil)
+			})
+
+			It("lists that many events from the offset on, with their ids", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+				Expect(response.Header.Get("Content-Type")).To(Equal("application/json"))
+
+				body, err := ioutil.ReadAll(response.Body)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(body).To(MatchJSON(`[
+					{"id":3,"event":"log","version":"5.0","data":{"payload":"fourth"}},
+					{"id":4,"event":"log","version":"5.0","data":{"payload":"fifth"}}
+				]`))
+
+				Expect(buildServerDB.GetBuildEventsFromCallCount()).To(Equal(1))
+				_, buildID, offset, limit := buildServerDB.GetBuildEventsFromArgsForCall(0)
+				Expect(buildID).To(Equal(42))
+				Expect(offset).To(Equal(uint(3)))
+				Expect(limit).To(Equal(2))
+			})
+		})
+
+		Context("when given no limit", func() {
+			BeforeEach(func() {
+				buildServerDB.GetBuildEventsFromStub = func(_ context.Context, _ int, offset uint, limit int) ([]event.Envelope, error) {
+					if offset > 0 {
+						return []event.Envelope{logEvent("last")}, nil
+					}
+
+					events := make([]event.Envelope, limit)
+					for i := range events {
+						events[i] = logEvent("more")
+					}
+
+					return events, nil
+				}
+			})
+
+			It("lists every event, a batch at a time", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+				var events []atc.BuildEvent
+				err := json.NewDecoder(response.Body).Decode(&events)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(events).To(HaveLen(buildserver.EventBatchSize + 1))
+				Expect(events[buildserver.EventBatchSize].ID).To(Equal(uint(buildserver.EventBatchSize)))
+
+				Expect(buildServerDB.GetBuildEventsFromCallCount()).To(Equal(2))
+
+				_, _, offset, limit := buildServerDB.GetBuildEventsFromArgsForCall(0)
+				Expect(offset).To(BeZero())
+				Expect(limit).To(Equal(buildserver.EventBatchSize))
+
+				_, _, offset, limit = buildServerDB.GetBuildEventsFromArgsForCall(1)
+				Expect(offset).To(Equal(uint(buildserver.EventBatchSize)))
+				Expect(limit).To(Equal(buildserver.EventBatchSize))
+			})
+		})
+
+		Context("when there are no more events", func() {
+			BeforeEach(func() {
+				query = "?offset=10"
+
+				buildServerDB.GetBuildEventsFromReturns([]event.Envelope{}, nil)
+			})
+
+			It("returns an empty list", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+				body, err := ioutil.ReadAll(response.Body)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(body).To(MatchJSON(`[]`))
+			})
+		})
+
+		for _, invalid := range []string{"?offset=-1", "?offset=first", "?limit=0", "?limit=lots"} {
+			invalid := invalid
+
+			Context("when given "+invalid, func() {
+				BeforeEach(func() {
+					query = invalid
+				})
+
+				It("returns 400", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+					Expect(buildServerDB.GetBuildEventsFromCallCount()).To(BeZero())
+				})
+			})
+		}
+
+		Context("when getting the events fails", func() {
+			BeforeEach(func() {
+				buildServerDB.GetBuildEventsFromReturns(nil, errors.New("nope"))
+			})
+
+			It("returns 500", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+		})
+
+		Context("when authenticated, but not authorized", func() {
+			BeforeEach(func() {
+				userContextReader.GetTeamReturns("some-other-team", 5, false, true)
+			})
+
+			It("returns 403 without getting the events", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+				Expect(buildServerDB.GetBuildEventsFromCallCount()).To(BeZero())
+			})
+		})
+	})
+
+	Describe("POST /api/v1/builds/reconcile", func() {
+		var response *http.Response
+
+		JustBeforeEach(func() {
+			var err error
+			response, err = client.Post(server.URL+"/api/v1/builds/reconcile", "", nil)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when authenticated but not admin", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+			})
+
+			It("returns 403 without reconciling", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+				Expect(fakeBuildReconciler.ReconcileCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when authenticated as an admin", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("main", 1, true, true)
+
+				fakeBuildReconciler.ReconcileReturns([]int{4, 2}, nil)
+			})
+
+			It("returns the builds that were errored", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+				Expect(fakeBuildReconciler.ReconcileCallCount()).To(Equal(1))
+
+				body, err := ioutil.ReadAll(response.Body)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(body).To(MatchJSON(`{"errored_builds":[4,2]}`))
+			})
+
+			Context("when reconciling fails", func() {
+				BeforeEach(func() {
+					fakeBuildReconciler.ReconcileReturns(nil, errors.New("nope"))
+				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+	})
+
+	Describe("GET /api/v1/builds/:build_id/wait", func() {
+		var response *http.Response
+		var query string
+
+		var notifier *dbfakes.FakeNotifier
+		var notify chan struct{}
+
+		BeforeEach(func() {
+			query = ""
+
+			buildsDB.GetBuildByIDReturns(build, true, nil)
+			build.IDReturns(42)
+			build.NameReturns("1")
+			build.JobNameReturns("job1")
+			build.PipelineNameReturns("pipeline1")
+			build.TeamNameReturns("some-team")
+			build.StatusReturns(db.StatusStarted)
+
+			notify = make(chan struct{}, 1)
+			notifier = new(dbfakes.FakeNotifier)
+			notifier.NotifyReturns(notify)
+			build.FinishNotifierReturns(notifier, nil)
+
+			build.ReloadStub = func() (bool, error) {
+				build.StatusReturns(db.StatusSucceeded)
+				return true, nil
+			}
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			response, err = client.Get(server.URL + "/api/v1/builds/42/wait" + query)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("some-team", 5, false, true)
+			})
+
+			Context("when the build finishes", func() {
+				BeforeEach(func() {
+					notify <- struct{}{}
+				})
+
+				It("returns the finished build", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+					Expect(response.Header.Get("Content-Type")).To(Equal("application/json"))
+
+					var presented atc.Build
+					err := json.NewDecoder(response.Body).Decode(&presented)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(presented.ID).To(Equal(42))
+					Expect(presented.Status).To(Equal("succeeded"))
+				})
+
+				It("stops listening for the build to finish", func() {
+					ioutil.ReadAll(response.Body)
+					Expect(notifier.CloseCallCount()).To(Equal(1))
+				})
+			})
+
+			Context("when the timeout elapses first", func() {
+				BeforeEach(func() {
+					query = "?timeout=10ms"
+
+					build.ReloadStub = nil
+					build.ReloadReturns(true, nil)
+				})
+
+				It("returns the build as it is", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+					var presented atc.Build
+					err := json.NewDecoder(response.Body).Decode(&presented)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(presented.Status).To(Equal("started"))
+					Expect(build.ReloadCallCount()).To(Equal(1))
+				})
+			})
+
+			Context("when the timeout is malformed", func() {
+				BeforeEach(func() {
+					query = "?timeout=soon"
+				})
+
+				It("returns 400", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+				})
+
+				It("doesn't wait", func() {
+					Expect(build.FinishNotifierCallCount()).To(BeZero())
+				})
+			})
+
+			Context("when listening for the build to finish fails", func() {
+				BeforeEach(func() {
+					build.FinishNotifierReturns(nil, errors.New("nope"))
+				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+
+		Context("when not authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+				build.GetPipelineReturns(db.SavedPipeline{Public: false}, nil)
+			})
+
+			It("returns 401", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+	})
+
+	Describe("GET /api/v1/builds/:build_id/preparation", func() {
+		var response *http.Response
+
+		JustBeforeEach(func() {
+			var err error
+			response, err = http.Get(server.URL + "/api/v1/builds/42/preparation")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when the build is found", func() {
+			var buildPrep db.BuildPreparation
+
+			BeforeEach(func() {
+				buildPrep = db.BuildPreparation{
+					BuildID:          42,
+					PausedPipeline:   db.BuildPreparationStatusNotBlocking,
+					PausedJob:        db.BuildPreparationStatusNotBlocking,
+					MaxRunningBuilds: db.BuildPreparationStatusBlocking,
+					Inputs: map[string]db.BuildPreparationStatus{
+						"foo": db.BuildPreparationStatusUnknown,
+						"bar": db.BuildPreparationStatusBlocking,
+					},
+					InputsSatisfied:     db.BuildPreparationStatusBlocking,
+					MissingInputReasons: db.MissingInputReasons{"some-input": "some-reason"},
+				}
+				buildsDB.GetBuildByIDReturns(build, true, nil)
+				build.JobNameReturns("job1")
+				build.TeamNameReturns("some-team")
+				build.GetPreparationReturns(buildPrep, true, nil)
+			})
+
+			Context("when authenticated, but not authorized", func() {
+				BeforeEach(func() {
+					authValidator.IsAuthenticatedReturns(true)
+					userContextReader.GetTeamReturns("some-other-team", 5, false, true)
+				})
+
+				It("returns 403", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+				})
+			})
+
+			Context("when not authenticated", func() {
+				BeforeEach(func() {
+					authValidator.IsAuthenticatedReturns(false)
+				})
+
+				Context("and build is one off", func() {
+					BeforeEach(func() {
+						build.IsOneOffReturns(true)
+					})
+
+					It("returns 401", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+					})
+				})
+
+				Context("and the pipeline is private", func() {
+					BeforeEach(func() {
+						build.GetPipelineReturns(db.SavedPipeline{Public: false}, nil)
+					})
+
+					It("returns 401", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+					})
+				})
+
+				Context("and the pipeline is public", func() {
+					BeforeEach(func() {
+						build.GetPipelineReturns(db.SavedPipeline{Public: true}, nil)
+					})
+
+					Context("when job is private", func() {
+						BeforeEach(func() {
+							build.GetConfigReturns(atc.Config{
+								Jobs: atc.JobConfigs{
+									{Name: "job1", Public: false},
+								},
+							}, 1, nil)
+						})
+
+						It("returns 401", func() {
+							Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+						})
+					})
+
+					Context("when job is public", func() {
+						BeforeEach(func() {
+							build.GetConfigReturns(atc.Config{
+								Jobs: atc.JobConfigs{
+									{Name: "job1", Public: true},
+								},
+							}, 1, nil)
+						})
+
+						It("returns 200", func() {
+							Expect(response.StatusCode).To(Equal(http.StatusOK))
+						})
+					})
+				})
+			})
+
+			Context("when authenticated", func() {
+				BeforeEach(func() {
+					authValidator.IsAuthenticatedReturns(true)
+					userContextReader.GetTeamReturns("some-team", 5, false, true)
+				})
+
+				It("fetches data from the db", func() {
+					Expect(build.GetPreparationCallCount()).To(Equal(1))
+				})
+
+				It("returns OK", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+				})
+
+				It("returns the build preparation", func() {
+					body, err := ioutil.ReadAll(response.Body)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(body).To(MatchJSON(`{
+					"build_id": 42,
+					"paused_pipeline": "not_blocking",
+					"paused_job": "not_blocking",
+					"max_running_builds": "blocking",
+					"inputs": {
+						"foo": "unknown",
+						"bar": "blocking"
+					},
+					"inputs_satisfied": "blocking",
+					"missing_input_reasons": {
+						"some-input": "some-reason"
+					}
+				}`))
+				})
+
+				Context("when the build preparation is not found", func() {
+					BeforeEach(func() {
+						buildsDB.GetBuildByIDReturns(build, true, nil)
+						build.GetPreparationReturns(db.BuildPreparation{}, false, nil)
+					})
+
+					It("returns Not Found", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+					})
+				})
+
+				Context("when looking up the build preparation fails", func() {
+					BeforeEach(func() {
+						buildsDB.GetBuildByIDReturns(build, true, nil)
+						build.GetPreparationReturns(db.BuildPreparation{}, false, errors.New("ho ho ho merry festivus"))
+					})
+
+					It("returns 500 Internal Server Error", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+					})
+				})
+			})
+		})
+
+		Context("when looking up the build fails", func() {
+			BeforeEach(func() {
+				buildsDB.GetBuildByIDReturns(nil, false, errors.New("ho ho ho merry festivus"))
+			})
+
+			It("returns 500 Internal Server Error", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+		})
+
+		Context("when build is not found", func() {
+			BeforeEach(func() {
+				buildsDB.GetBuildByIDReturns(nil, false, nil)
+			})
+
+			It("returns 404", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+			})
+		})
+	})
+
+	Describe("POST /api/v1/builds/status", func() {
+		var (
+			requestBody string
+			response    *http.Response
+
+			teamBuild            *dbfakes.FakeBuild
+			publicBuild          *dbfakes.FakeBuild
+			privateBuild         *dbfakes.FakeBuild
+			otherTeamOneOffBuild *dbfakes.FakeBuild
+		)
+
+		BeforeEach(func() {
+			requestBody = `[1, 2, 3, 4]`
+
+			teamBuild = new(dbfakes.FakeBuild)
+			teamBuild.IDReturns(1)
+			teamBuild.NameReturns("1")
+			teamBuild.TeamNameReturns("some-team")
+			teamBuild.StatusReturns(db.StatusStarted)
+
+			publicBuild = new(dbfakes.FakeBuild)
+			publicBuild.IDReturns(2)
+			publicBuild.NameReturns("7")
+			publicBuild.TeamNameReturns("some-other-team")
+			publicBuild.JobNameReturns("some-job")
+			publicBuild.PipelineNameReturns("some-public-pipeline")
+			publicBuild.StatusReturns(db.StatusSucceeded)
+			publicBuild.GetPipelineReturns(db.SavedPipeline{Public: true}, nil)
+
+			privateBuild = new(dbfakes.FakeBuild)
+			privateBuild.IDReturns(3)
+			privateBuild.TeamNameReturns("some-other-team")
+			privateBuild.JobNameReturns("some-job")
+			privateBuild.PipelineNameReturns("some-private-pipeline")
+			privateBuild.StatusReturns(db.StatusFailed)
+			privateBuild.GetPipelineReturns(db.SavedPipeline{Public: false}, nil)
+
+			otherTeamOneOffBuild = new(dbfakes.FakeBuild)
+			otherTeamOneOffBuild.IDReturns(4)
+			otherTeamOneOffBuild.TeamNameReturns("some-other-team")
+			otherTeamOneOffBuild.IsOneOffReturns(true)
+			otherTeamOneOffBuild.StatusReturns(db.StatusErrored)
+
+			buildServerDB.GetBuildsReturns([]db.Build{teamBuild, publicBuild, privateBuild, otherTeamOneOffBuild}, nil)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+
+			response, err = client.Post(server.URL+"/api/v1/builds/status", "application/json", bytes.NewBufferString(requestBody))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		returnedBuildIDs := func() []int {
+			var builds []atc.Build
+			err := json.NewDecoder(response.Body).Decode(&builds)
+			Expect(err).NotTo(HaveOccurred())
+
+			ids := []int{}
+			for _, build := range builds {
+				ids = append(ids, build.ID)
+			}
+
+			return ids
+		}
+
+		It("looks up the requested builds", func() {
+			Expect(buildServerDB.GetBuildsCallCount()).To(Equal(1))
+			_, buildIDs := buildServerDB.GetBuildsArgsForCall(0)
+			Expect(buildIDs).To(Equal([]int{1, 2, 3, 4}))
+		})
+
+		Context("when authorized for a team", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("some-team", 5, false, true)
+			})
+
+			It("returns 200 OK", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+			})
+
+			It("returns the team's builds and builds of public pipelines", func() {
+				Expect(returnedBuildIDs()).To(Equal([]int{1, 2}))
+			})
+
+			It("includes the status of each build", func() {
+				body, err := ioutil.ReadAll(response.Body)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(body).To(MatchJSON(`[
+					{
+						"id": 1,
+						"name": "1",
+						"status": "started",
+						"team_name": "some-team",
+						"url": "/builds/1",
+						"api_url": "/api/v1/builds/1"
+					},
+					{
+						"id": 2,
+						"name": "7",
+						"status": "succeeded",
+						"team_name": "some-other-team",
+						"job_name": "some-job",
+						"pipeline_name": "some-public-pipeline",
+						"url": "/teams/some-other-team/pipelines/some-public-pipeline/jobs/some-job/builds/7",
+						"api_url": "/api/v1/builds/2"
+					}
+				]`))
+			})
+
+			Context("when the role isn't enough to view the pipeline of the team's build", func() {
+				BeforeEach(func() {
+					userContextReader.GetRoleReturns(atc.RoleViewer, true)
+
+					teamBuild.GetPipelineReturns(db.SavedPipeline{
+						Permissions: atc.PipelinePermissions{View: atc.RoleOperator},
+					}, nil)
+				})
+
+				It("leaves it out", func() {
+					Expect(returnedBuildIDs()).To(Equal([]int{2}))
+				})
+			})
+
+			Context("when the role is enough to view it", func() {
+				BeforeEach(func() {
+					userContextReader.GetRoleReturns(atc.RoleOperator, true)
+
+					teamBuild.GetPipelineReturns(db.SavedPipeline{
+						Permissions: atc.PipelinePermissions{View: atc.RoleOperator},
+					}, nil)
+				})
+
+				It("returns it", func() {
+					Expect(returnedBuildIDs()).To(Equal([]int{1, 2}))
+				})
+			})
+		})
+
+		Context("when not authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+				userContextReader.GetTeamReturns("", 0, false, false)
+			})
+
+			It("only returns builds of public pipelines", func() {
+				Expect(returnedBuildIDs()).To(Equal([]int{2}))
+			})
+		})
+
+		Context("when looking up a pipeline fails", func() {
+			BeforeEach(func() {
+				publicBuild.GetPipelineReturns(db.SavedPipeline{}, errors.New("nope"))
+			})
+
+			It("returns 500 Internal Server Error", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+		})
+
+		Context("when the request body is malformed", func() {
+			BeforeEach(func() {
+				requestBody = `{"nope"`
+			})
+
+			It("returns 400 Bad Request", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+			})
+
+			It("does not look up any builds", func() {
+				Expect(buildServerDB.GetBuildsCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when more builds are requested than fit in a page", func() {
+			BeforeEach(func() {
+				ids := make([]int, atc.PaginationAPIMaxLimit+1)
+				for i := range ids {
+					ids[i] = i + 1
+				}
+
+				payload, err := json.Marshal(ids)
+				Expect(err).NotTo(HaveOccurred())
+
+				requestBody = string(payload)
+			})
+
+			It("returns 400 Bad Request", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+			})
+
+			It("does not look up any builds", func() {
+				Expect(buildServerDB.GetBuildsCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when looking up the builds fails", func() {
+			BeforeEach(func() {
+				buildServerDB.GetBuildsReturns(nil, errors.New("nope"))
+			})
+
+			It("returns 500 Internal Server Error", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+		})
+	})
+
+	Describe("GET /api/v1/build-reaper", func() {
+		var response *http.Response
+
+		JustBeforeEach(func() {
+			var err error
+
+			response, err = client.Get(server.URL + "/api/v1/build-reaper")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when not authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns 401", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+
+		Context("when authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+			})
+
+			Context("when builds have been reaped", func() {
+				BeforeEach(func() {
+					buildServerDB.GetLastBuildReapTimeReturns(time.Unix(1234, 0), true, nil)
+				})
+
+				It("returns 200 OK", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+				})
+
+				It("returns the last reap time", func() {
+					body, err := ioutil.ReadAll(response.Body)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(body).To(MatchJSON(`{"last_reap_time": 1234}`))
+				})
+			})
+
+			Context("when no builds have been reaped", func() {
+				BeforeEach(func() {
+					buildServerDB.GetLastBuildReapTimeReturns(time.Time{}, false, nil)
+				})
+
+				It("returns an empty status", func() {
+					body, err := ioutil.ReadAll(response.Body)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(body).To(MatchJSON(`{}`))
 				})
+			})
 
-				Context("when accessing other team's build", func() {
-					BeforeEach(func() {
-						userContextReader.GetTeamReturns("some-other-team", 2, true, true)
-					})
+			Context("when looking up the last reap time fails", func() {
+				BeforeEach(func() {
+					buildServerDB.GetLastBuildReapTimeReturns(time.Time{}, false, errors.New("nope"))
+				})
 
-					It("returns 403", func() {
-						Expect(response.StatusCode).To(Equal(http.StatusForbidden))
-					})
+				It("returns 500 Internal Server Error", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
 				})
 			})
 
-			Context("when the build can not be found", func() {
+			Context("when builds have been pruned", func() {
 				BeforeEach(func() {
-					buildsDB.GetBuildByIDReturns(nil, false, nil)
+					buildServerDB.GetLastBuildReapTimeReturns(time.Unix(1234, 0), true, nil)
+					buildServerDB.GetLastBuildPruneReturns(db.BuildPrune{
+						PrunedAt: time.Unix(5678, 0),
+						Builds:   42,
+					}, true, nil)
 				})
 
-				It("returns Not Found", func() {
-					Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+				It("returns the last prune time and how many builds it deleted", func() {
+					body, err := ioutil.ReadAll(response.Body)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(body).To(MatchJSON(`{
+						"last_reap_time": 1234,
+						"last_prune_time": 5678,
+						"pruned_builds": 42
+					}`))
 				})
 			})
 
-			Context("when calling the database fails", func() {
+			Context("when looking up the last prune fails", func() {
 				BeforeEach(func() {
-					buildsDB.GetBuildByIDReturns(nil, false, errors.New("nope"))
+					buildServerDB.GetLastBuildPruneReturns(db.BuildPrune{}, false, errors.New("nope"))
 				})
 
-				It("returns Internal Server Error", func() {
+				It("returns 500 Internal Server Error", func() {
 					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
 				})
 			})
 		})
+	})
+
+	Describe("GET /api/v1/max-in-flight", func() {
+		var response *http.Response
+
+		JustBeforeEach(func() {
+			var err error
+
+			response, err = client.Get(server.URL + "/api/v1/max-in-flight")
+			Expect(err).NotTo(HaveOccurred())
+		})
 
 		Context("when not authenticated", func() {
 			BeforeEach(func() {
@@ -1141,190 +3960,155 @@ var _ = Describe("Builds API", func() {
 			It("returns 401", func() {
 				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
 			})
-
-			It("does not abort the build", func() {
-				Expect(abortTarget.ReceivedRequests()).To(BeEmpty())
-			})
 		})
-	})
 
-	Describe("GET /api/v1/builds/:build_id/preparation", func() {
-		var response *http.Response
+		Context("when authenticated but not admin", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+			})
 
-		JustBeforeEach(func() {
-			var err error
-			response, err = http.Get(server.URL + "/api/v1/builds/42/preparation")
-			Expect(err).NotTo(HaveOccurred())
+			It("returns 403", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+			})
 		})
 
-		Context("when the build is found", func() {
-			var buildPrep db.BuildPreparation
-
+		Context("when authenticated as an admin", func() {
 			BeforeEach(func() {
-				buildPrep = db.BuildPreparation{
-					BuildID:          42,
-					PausedPipeline:   db.BuildPreparationStatusNotBlocking,
-					PausedJob:        db.BuildPreparationStatusNotBlocking,
-					MaxRunningBuilds: db.BuildPreparationStatusBlocking,
-					Inputs: map[string]db.BuildPreparationStatus{
-						"foo": db.BuildPreparationStatusUnknown,
-						"bar": db.BuildPreparationStatusBlocking,
-					},
-					InputsSatisfied:     db.BuildPreparationStatusBlocking,
-					MissingInputReasons: db.MissingInputReasons{"some-input": "some-reason"},
-				}
-				buildsDB.GetBuildByIDReturns(build, true, nil)
-				build.JobNameReturns("job1")
-				build.TeamNameReturns("some-team")
-				build.GetPreparationReturns(buildPrep, true, nil)
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("main", 1, true, true)
 			})
 
-			Context("when authenticated, but not authorized", func() {
+			Context("when the limit can be looked up", func() {
 				BeforeEach(func() {
-					authValidator.IsAuthenticatedReturns(true)
-					userContextReader.GetTeamReturns("some-other-team", 5, false, true)
+					buildServerDB.GetGlobalMaxInFlightReturns(5, nil)
 				})
 
-				It("returns 403", func() {
-					Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+				It("returns 200 OK", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+				})
+
+				It("returns the limit", func() {
+					body, err := ioutil.ReadAll(response.Body)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(body).To(MatchJSON(`{"max_in_flight": 5}`))
 				})
 			})
 
-			Context("when not authenticated", func() {
+			Context("when looking up the limit fails", func() {
 				BeforeEach(func() {
-					authValidator.IsAuthenticatedReturns(false)
+					buildServerDB.GetGlobalMaxInFlightReturns(0, errors.New("nope"))
 				})
 
-				Context("and build is one off", func() {
-					BeforeEach(func() {
-						build.IsOneOffReturns(true)
-					})
-
-					It("returns 401", func() {
-						Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
-					})
+				It("returns 500 Internal Server Error", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
 				})
+			})
+		})
+	})
 
-				Context("and the pipeline is private", func() {
-					BeforeEach(func() {
-						build.GetPipelineReturns(db.SavedPipeline{Public: false}, nil)
-					})
+	Describe("PUT /api/v1/max-in-flight", func() {
+		var (
+			payload string
 
-					It("returns 401", func() {
-						Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
-					})
-				})
+			response *http.Response
+		)
 
-				Context("and the pipeline is public", func() {
-					BeforeEach(func() {
-						build.GetPipelineReturns(db.SavedPipeline{Public: true}, nil)
-					})
+		BeforeEach(func() {
+			payload = `{"max_in_flight": 3}`
+		})
 
-					Context("when job is private", func() {
-						BeforeEach(func() {
-							build.GetConfigReturns(atc.Config{
-								Jobs: atc.JobConfigs{
-									{Name: "job1", Public: false},
-								},
-							}, 1, nil)
-						})
+		JustBeforeEach(func() {
+			req, err := http.NewRequest("PUT", server.URL+"/api/v1/max-in-flight", bytes.NewBufferString(payload))
+			Expect(err).NotTo(HaveOccurred())
 
-						It("returns 401", func() {
-							Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
-						})
-					})
+			response, err = client.Do(req)
+			Expect(err).NotTo(HaveOccurred())
+		})
 
-					Context("when job is public", func() {
-						BeforeEach(func() {
-							build.GetConfigReturns(atc.Config{
-								Jobs: atc.JobConfigs{
-									{Name: "job1", Public: true},
-								},
-							}, 1, nil)
-						})
+		Context("when not authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
 
-						It("returns 200", func() {
-							Expect(response.StatusCode).To(Equal(http.StatusOK))
-						})
-					})
-				})
+			It("returns 401", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
 			})
 
-			Context("when authenticated", func() {
-				BeforeEach(func() {
-					authValidator.IsAuthenticatedReturns(true)
-					userContextReader.GetTeamReturns("some-team", 5, false, true)
-				})
+			It("does not set the limit", func() {
+				Expect(buildServerDB.SetGlobalMaxInFlightCallCount()).To(BeZero())
+			})
+		})
 
-				It("fetches data from the db", func() {
-					Expect(build.GetPreparationCallCount()).To(Equal(1))
-				})
+		Context("when authenticated but not admin", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+			})
 
-				It("returns OK", func() {
-					Expect(response.StatusCode).To(Equal(http.StatusOK))
-				})
+			It("returns 403", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+			})
 
-				It("returns the build preparation", func() {
-					body, err := ioutil.ReadAll(response.Body)
-					Expect(err).NotTo(HaveOccurred())
+			It("does not set the limit", func() {
+				Expect(buildServerDB.SetGlobalMaxInFlightCallCount()).To(BeZero())
+			})
+		})
 
-					Expect(body).To(MatchJSON(`{
-					"build_id": 42,
-					"paused_pipeline": "not_blocking",
-					"paused_job": "not_blocking",
-					"max_running_builds": "blocking",
-					"inputs": {
-						"foo": "unknown",
-						"bar": "blocking"
-					},
-					"inputs_satisfied": "blocking",
-					"missing_input_reasons": {
-						"some-input": "some-reason"
-					}
-				}`))
-				})
+		Context("when authenticated as an admin", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("main", 1, true, true)
+			})
 
-				Context("when the build preparation is not found", func() {
-					BeforeEach(func() {
-						buildsDB.GetBuildByIDReturns(build, true, nil)
-						build.GetPreparationReturns(db.BuildPreparation{}, false, nil)
-					})
+			It("returns 200 OK", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+			})
 
-					It("returns Not Found", func() {
-						Expect(response.StatusCode).To(Equal(http.StatusNotFound))
-					})
+			It("sets the limit", func() {
+				Expect(buildServerDB.SetGlobalMaxInFlightCallCount()).To(Equal(1))
+				_, maxInFlight := buildServerDB.SetGlobalMaxInFlightArgsForCall(0)
+				Expect(maxInFlight).To(Equal(3))
+			})
+
+			It("returns the new limit", func() {
+				body, err := ioutil.ReadAll(response.Body)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(body).To(MatchJSON(`{"max_in_flight": 3}`))
+			})
+
+			Context("when the limit is negative", func() {
+				BeforeEach(func() {
+					payload = `{"max_in_flight": -1}`
 				})
 
-				Context("when looking up the build preparation fails", func() {
-					BeforeEach(func() {
-						buildsDB.GetBuildByIDReturns(build, true, nil)
-						build.GetPreparationReturns(db.BuildPreparation{}, false, errors.New("ho ho ho merry festivus"))
-					})
+				It("returns 400 Bad Request", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+				})
 
-					It("returns 500 Internal Server Error", func() {
-						Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
-					})
+				It("does not set the limit", func() {
+					Expect(buildServerDB.SetGlobalMaxInFlightCallCount()).To(BeZero())
 				})
 			})
-		})
 
-		Context("when looking up the build fails", func() {
-			BeforeEach(func() {
-				buildsDB.GetBuildByIDReturns(nil, false, errors.New("ho ho ho merry festivus"))
-			})
+			Context("when the request is malformed", func() {
+				BeforeEach(func() {
+					payload = `{`
+				})
 
-			It("returns 500 Internal Server Error", func() {
-				Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				It("returns 400 Bad Request", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+				})
 			})
-		})
 
-		Context("when build is not found", func() {
-			BeforeEach(func() {
-				buildsDB.GetBuildByIDReturns(nil, false, nil)
-			})
+			Context("when setting the limit fails", func() {
+				BeforeEach(func() {
+					buildServerDB.SetGlobalMaxInFlightReturns(errors.New("nope"))
+				})
 
-			It("returns 404", func() {
-				Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+				It("returns 500 Internal Server Error", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
 			})
 		})
 	})
@@ -1355,6 +4139,7 @@ var _ = Describe("Builds API", func() {
 			BeforeEach(func() {
 				build.JobNameReturns("job1")
 				build.TeamNameReturns("some-team")
+				build.EngineReturns("some-engine")
 				buildsDB.GetBuildByIDReturns(build, true, nil)
 
 				engineBuild = new(enginefakes.FakeBuild)
@@ -1447,6 +4232,10 @@ var _ = Describe("Builds API", func() {
 						Expect(response.StatusCode).To(Equal(http.StatusOK))
 					})
 
+					It("returns Content-Type 'application/json'", func() {
+						Expect(response.Header.Get("Content-Type")).To(Equal("application/json"))
+					})
+
 					It("returns the plan", func() {
 						body, err := ioutil.ReadAll(response.Body)
 						Expect(err).NotTo(HaveOccurred())
@@ -1467,6 +4256,20 @@ var _ = Describe("Builds API", func() {
 						Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
 					})
 				})
+
+				Context("when the build has not started yet", func() {
+					BeforeEach(func() {
+						build.EngineReturns("")
+					})
+
+					It("returns 404 Not Found", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+					})
+
+					It("does not look up the build in the engine", func() {
+						Expect(fakeEngine.LookupBuildCallCount()).To(BeZero())
+					})
+				})
 			})
 		})
 
@@ -3,28 +3,41 @@ package buildserver
 import (
 	"net/http"
 
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
 	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/requestlog"
 
 	"code.cloudfoundry.org/lager"
 )
 
 func (s *Server) AbortBuild(build db.Build) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		aLog := s.logger.Session("abort", lager.Data{
+		aLog := requestlog.Logger(s.logger.Session("abort", lager.Data{
 			"build": build.ID(),
-		})
+		}), r)
+
+		if !checkPipelinePermission(aLog, w, r, build, atc.PipelinePermissionTrigger) {
+			return
+		}
+
+		if !build.IsRunning() {
+			aLog.Info("build-already-finished", lager.Data{"status": build.Status()})
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
 
 		engineBuild, err := s.engine.LookupBuild(aLog, build)
 		if err != nil {
 			aLog.Error("failed-to-lookup-build", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.BuilderFailure(w, "failed to lookup build")
 			return
 		}
 
 		err = engineBuild.Abort(aLog)
 		if err != nil {
 			aLog.Error("failed-to-abort-build", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.BuilderFailure(w, "failed to abort build")
 			return
 		}
 
@@ -0,0 +1,126 @@
+package buildserver
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/api/present"
+	"github.com/concourse/atc/auth"
+	"github.com/concourse/atc/db"
+)
+
+func (s *Server) ListBuildApprovals(build db.Build) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger := s.logger.Session("list-build-approvals", lager.Data{"build-id": build.ID()})
+
+		approvals, err := build.GetApprovals()
+		if err != nil {
+			logger.Error("failed-to-get-approvals", err)
+			apierror.DBFailure(w, "failed to get approvals")
+			return
+		}
+
+		presentedApprovals := make([]atc.BuildApproval, len(approvals))
+		for i, approval := range approvals {
+			presentedApprovals[i] = present.BuildApproval(approval)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(presentedApprovals)
+	})
+}
+
+// DecideBuildApproval approves or rejects one of the build's approval steps
+// on behalf of whoever made the request. Rejecting it also aborts the build,
+// as there is no point in running the rest of it.
+func (s *Server) DecideBuildApproval(build db.Build) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stepName := r.FormValue(":step")
+
+		logger := s.logger.Session("decide-build-approval", lager.Data{
+			"build-id": build.ID(),
+			"step":     stepName,
+		})
+
+		if !checkPipelinePermission(logger, w, r, build, atc.PipelinePermissionTrigger) {
+			return
+		}
+
+		var decision atc.ApprovalDecision
+		err := json.NewDecoder(r.Body).Decode(&decision)
+		if err != nil {
+			logger.Info("malformed-request", lager.Data{"error": err.Error()})
+			http.Error(w, "malformed request", http.StatusBadRequest)
+			return
+		}
+
+		approval, found, err := build.GetApproval(stepName)
+		if err != nil {
+			logger.Error("failed-to-get-approval", err)
+			apierror.DBFailure(w, "failed to get approval")
+			return
+		}
+
+		if !found {
+			apierror.NotFound(w, "build is not waiting on approval step")
+			return
+		}
+
+		if approval.Status != db.ApprovalPending || !build.IsRunning() {
+			logger.Info("already-decided", lager.Data{"status": approval.Status})
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+
+		approver := auth.GetActor(r)
+
+		decided, err := build.DecideApproval(stepName, decision.Approved, approver)
+		if err != nil {
+			logger.Error("failed-to-decide-approval", err)
+			apierror.DBFailure(w, "failed to decide approval")
+			return
+		}
+
+		if !decided {
+			logger.Info("decided-concurrently")
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+
+		logger.Info("decided", lager.Data{
+			"approved": decision.Approved,
+			"approver": approver,
+		})
+
+		if !decision.Approved {
+			engineBuild, err := s.engine.LookupBuild(logger, build)
+			if err != nil {
+				logger.Error("failed-to-lookup-build", err)
+				apierror.BuilderFailure(w, "failed to lookup build")
+				return
+			}
+
+			err = engineBuild.Abort(logger)
+			if err != nil {
+				logger.Error("failed-to-abort-build", err)
+				apierror.BuilderFailure(w, "failed to abort build")
+				return
+			}
+		}
+
+		approval, _, err = build.GetApproval(stepName)
+		if err != nil {
+			logger.Error("failed-to-get-approval", err)
+			apierror.DBFailure(w, "failed to get approval")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(present.BuildApproval(approval))
+	})
+}
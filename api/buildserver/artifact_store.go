@@ -0,0 +1,54 @@
+package buildserver
+
+import (
+	"fmt"
+	"io"
+	"path"
+
+	"github.com/concourse/atc/storage"
+)
+
+//go:generate counterfeiter . ArtifactStore
+
+// ArtifactStore keeps copies of build artifacts so that they can still be
+// downloaded once the containers that produced them are gone.
+type ArtifactStore interface {
+	Open(buildID int, name string) (io.ReadCloser, bool, error)
+	Store(buildID int, name string, tarball io.Reader) error
+	Delete(buildID int, name string) error
+}
+
+type driverArtifactStore struct {
+	driver storage.Driver
+}
+
+// NewDirArtifactStore stores artifact tarballs on local disk, under one
+// directory per build.
+func NewDirArtifactStore(dir string) ArtifactStore {
+	return NewDriverArtifactStore(storage.NewLocalDriver(dir))
+}
+
+// NewDriverArtifactStore stores artifact tarballs with the storage driver,
+// e.g. in a bucket that outlives every worker and ATC.
+func NewDriverArtifactStore(driver storage.Driver) ArtifactStore {
+	return driverArtifactStore{driver: driver}
+}
+
+func (store driverArtifactStore) Open(buildID int, name string) (io.ReadCloser, bool, error) {
+	return store.driver.Get(artifactKey(buildID, name))
+}
+
+// Store leaves nothing behind if the tarball can't be read completely, as the
+// driver never keeps partial contents.
+func (store driverArtifactStore) Store(buildID int, name string, tarball io.Reader) error {
+	return store.driver.Put(artifactKey(buildID, name), tarball)
+}
+
+// Delete is fine with there being nothing stored under the name.
+func (store driverArtifactStore) Delete(buildID int, name string) error {
+	return store.driver.Delete(artifactKey(buildID, name))
+}
+
+func artifactKey(buildID int, name string) string {
+	return fmt.Sprintf("%d/%s.tar", buildID, path.Base(name))
+}
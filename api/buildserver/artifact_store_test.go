@@ -0,0 +1,98 @@
+package buildserver_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+
+	. "github.com/concourse/atc/api/buildserver"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DirArtifactStore", func() {
+	var (
+		dir   string
+		store ArtifactStore
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "artifact-store")
+		Expect(err).NotTo(HaveOccurred())
+
+		store = NewDirArtifactStore(dir)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("returns stored tarballs", func() {
+		err := store.Store(42, "some-artifact", bytes.NewBufferString("some-tarball"))
+		Expect(err).NotTo(HaveOccurred())
+
+		tarball, found, err := store.Open(42, "some-artifact")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeTrue())
+
+		defer tarball.Close()
+
+		Expect(ioutil.ReadAll(tarball)).To(Equal([]byte("some-tarball")))
+	})
+
+	It("does not find tarballs that were never stored", func() {
+		_, found, err := store.Open(42, "some-artifact")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeFalse())
+	})
+
+	It("keeps artifacts of different builds apart", func() {
+		err := store.Store(42, "some-artifact", bytes.NewBufferString("some-tarball"))
+		Expect(err).NotTo(HaveOccurred())
+
+		_, found, err := store.Open(43, "some-artifact")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeFalse())
+	})
+
+	It("deletes stored tarballs", func() {
+		err := store.Store(42, "some-artifact", bytes.NewBufferString("some-tarball"))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(store.Delete(42, "some-artifact")).To(Succeed())
+
+		_, found, err := store.Open(42, "some-artifact")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeFalse())
+
+		Expect(store.Delete(42, "some-artifact")).To(Succeed())
+	})
+
+	Context("when the tarball cannot be read completely", func() {
+		disaster := errors.New("connection reset")
+
+		It("returns the error and does not keep a partial copy", func() {
+			err := store.Store(42, "some-artifact", io.MultiReader(
+				bytes.NewBufferString("some-"),
+				failingReader{disaster},
+			))
+			Expect(err).To(Equal(disaster))
+
+			_, found, err := store.Open(42, "some-artifact")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+	})
+})
+
+type failingReader struct {
+	err error
+}
+
+func (reader failingReader) Read([]byte) (int, error) {
+	return 0, reader.err
+}
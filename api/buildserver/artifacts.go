@@ -0,0 +1,203 @@
+package buildserver
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/api/present"
+	"github.com/concourse/atc/db"
+)
+
+func (s *Server) RegisterBuildArtifact(build db.Build) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger := s.logger.Session("register-build-artifact", lager.Data{"build-id": build.ID()})
+
+		var artifact atc.BuildArtifact
+		err := json.NewDecoder(r.Body).Decode(&artifact)
+		if err == nil {
+			err = validateArtifact(artifact)
+		}
+
+		if err != nil {
+			logger.Info("malformed-request", lager.Data{"error": err.Error()})
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		teamDB := s.teamDBFactory.GetTeamDB(build.TeamName())
+
+		container, found, err := teamDB.GetContainer(artifact.ContainerHandle)
+		if err != nil {
+			logger.Error("failed-to-find-container", err)
+			apierror.DBFailure(w, "failed to find container")
+			return
+		}
+
+		if !found || container.BuildID != build.ID() {
+			logger.Info("container-not-part-of-build", lager.Data{"handle": artifact.ContainerHandle})
+			http.Error(w, "container does not belong to the build", http.StatusUnprocessableEntity)
+			return
+		}
+
+		err = build.SaveArtifact(db.BuildArtifact{
+			Name:            artifact.Name,
+			ContainerHandle: artifact.ContainerHandle,
+			Path:            artifact.Path,
+		})
+		if err != nil {
+			logger.Error("failed-to-save-artifact", err)
+			apierror.DBFailure(w, "failed to save artifact")
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+	})
+}
+
+func (s *Server) ListBuildArtifacts(build db.Build) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger := s.logger.Session("list-build-artifacts", lager.Data{"build-id": build.ID()})
+
+		artifacts, err := build.GetArtifacts()
+		if err != nil {
+			logger.Error("failed-to-get-artifacts", err)
+			apierror.DBFailure(w, "failed to get artifacts")
+			return
+		}
+
+		presentedArtifacts := make([]atc.BuildArtifact, len(artifacts))
+		for i, artifact := range artifacts {
+			presentedArtifacts[i] = present.BuildArtifact(artifact)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(presentedArtifacts)
+	})
+}
+
+// DownloadBuildArtifact streams an artifact's tarball out of the container
+// that produced it. If an artifact store is configured, a stored copy is
+// served instead when there is one, and otherwise a copy is stored as the
+// tarball is streamed.
+func (s *Server) DownloadBuildArtifact(build db.Build) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := r.FormValue(":artifact_name")
+
+		logger := s.logger.Session("download-build-artifact", lager.Data{
+			"build-id": build.ID(),
+			"artifact": name,
+		})
+
+		artifact, found, err := build.GetArtifact(name)
+		if err != nil {
+			logger.Error("failed-to-get-artifact", err)
+			apierror.DBFailure(w, "failed to get artifact")
+			return
+		}
+
+		if !found {
+			logger.Info("artifact-not-found")
+			apierror.NotFound(w, "artifact not found")
+			return
+		}
+
+		if s.artifactStore != nil {
+			stored, found, err := s.artifactStore.Open(build.ID(), artifact.Name)
+			if err != nil {
+				logger.Error("failed-to-open-stored-artifact", err)
+			} else if found {
+				defer stored.Close()
+
+				writeArtifactHeaders(w, artifact.Name)
+				io.Copy(w, stored)
+				return
+			}
+		}
+
+		container, found, err := s.workerClient.LookupContainer(logger, artifact.ContainerHandle)
+		if err != nil {
+			logger.Error("failed-to-lookup-container", err)
+			apierror.BuilderFailure(w, "failed to lookup container")
+			return
+		}
+
+		if !found {
+			logger.Info("container-gone")
+			w.WriteHeader(http.StatusGone)
+			return
+		}
+
+		defer container.Release(nil)
+
+		tarball, err := container.StreamOut(garden.StreamOutSpec{Path: artifact.Path})
+		if err != nil {
+			logger.Error("failed-to-stream-out", err)
+			apierror.BuilderFailure(w, "failed to stream out")
+			return
+		}
+
+		defer tarball.Close()
+
+		writeArtifactHeaders(w, artifact.Name)
+
+		if s.artifactStore == nil {
+			io.Copy(w, tarball)
+			return
+		}
+
+		storeR, storeW := io.Pipe()
+		stored := make(chan error, 1)
+
+		go func() {
+			err := s.artifactStore.Store(build.ID(), artifact.Name, storeR)
+
+			// keep draining so a failing store can't stall the download
+			io.Copy(ioutil.Discard, storeR)
+
+			stored <- err
+		}()
+
+		_, err = io.Copy(w, io.TeeReader(tarball, storeW))
+		if err == nil {
+			storeW.Close()
+		} else {
+			storeW.CloseWithError(err)
+		}
+
+		err = <-stored
+		if err != nil {
+			logger.Error("failed-to-store-artifact", err)
+		}
+	})
+}
+
+func writeArtifactHeaders(w http.ResponseWriter, name string) {
+	w.Header().Set("Content-Type", "application/x-tar")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+name+`.tar"`)
+	w.WriteHeader(http.StatusOK)
+}
+
+func validateArtifact(artifact atc.BuildArtifact) error {
+	if artifact.Name == "" || strings.ContainsAny(artifact.Name, `/\"`) || artifact.Name == "." || artifact.Name == ".." {
+		return errors.New("artifact name must be non-empty and cannot contain slashes or quotes")
+	}
+
+	if artifact.ContainerHandle == "" {
+		return errors.New("artifact container handle must be given")
+	}
+
+	if artifact.Path == "" {
+		return errors.New("artifact path must be given")
+	}
+
+	return nil
+}
@@ -0,0 +1,175 @@
+// This file was generated by counterfeiter
+package buildserverfakes
+
+import (
+	"io"
+	"sync"
+
+	"github.com/concourse/atc/api/buildserver"
+)
+
+type FakeArtifactStore struct {
+	OpenStub        func(buildID int, name string) (io.ReadCloser, bool, error)
+	openMutex       sync.RWMutex
+	openArgsForCall []struct {
+		buildID int
+		name    string
+	}
+	openReturns struct {
+		result1 io.ReadCloser
+		result2 bool
+		result3 error
+	}
+	StoreStub        func(buildID int, name string, tarball io.Reader) error
+	storeMutex       sync.RWMutex
+	storeArgsForCall []struct {
+		buildID int
+		name    string
+		tarball io.Reader
+	}
+	storeReturns struct {
+		result1 error
+	}
+	DeleteStub        func(buildID int, name string) error
+	deleteMutex       sync.RWMutex
+	deleteArgsForCall []struct {
+		buildID int
+		name    string
+	}
+	deleteReturns struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeArtifactStore) Open(buildID int, name string) (io.ReadCloser, bool, error) {
+	fake.openMutex.Lock()
+	fake.openArgsForCall = append(fake.openArgsForCall, struct {
+		buildID int
+		name    string
+	}{buildID, name})
+	fake.recordInvocation("Open", []interface{}{buildID, name})
+	fake.openMutex.Unlock()
+	if fake.OpenStub != nil {
+		return fake.OpenStub(buildID, name)
+	} else {
+		return fake.openReturns.result1, fake.openReturns.result2, fake.openReturns.result3
+	}
+}
+
+func (fake *FakeArtifactStore) OpenCallCount() int {
+	fake.openMutex.RLock()
+	defer fake.openMutex.RUnlock()
+	return len(fake.openArgsForCall)
+}
+
+func (fake *FakeArtifactStore) OpenArgsForCall(i int) (int, string) {
+	fake.openMutex.RLock()
+	defer fake.openMutex.RUnlock()
+	return fake.openArgsForCall[i].buildID, fake.openArgsForCall[i].name
+}
+
+func (fake *FakeArtifactStore) OpenReturns(result1 io.ReadCloser, result2 bool, result3 error) {
+	fake.OpenStub = nil
+	fake.openReturns = struct {
+		result1 io.ReadCloser
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeArtifactStore) Store(buildID int, name string, tarball io.Reader) error {
+	fake.storeMutex.Lock()
+	fake.storeArgsForCall = append(fake.storeArgsForCall, struct {
+		buildID int
+		name    string
+		tarball io.Reader
+	}{buildID, name, tarball})
+	fake.recordInvocation("Store", []interface{}{buildID, name, tarball})
+	fake.storeMutex.Unlock()
+	if fake.StoreStub != nil {
+		return fake.StoreStub(buildID, name, tarball)
+	} else {
+		return fake.storeReturns.result1
+	}
+}
+
+func (fake *FakeArtifactStore) StoreCallCount() int {
+	fake.storeMutex.RLock()
+	defer fake.storeMutex.RUnlock()
+	return len(fake.storeArgsForCall)
+}
+
+func (fake *FakeArtifactStore) StoreArgsForCall(i int) (int, string, io.Reader) {
+	fake.storeMutex.RLock()
+	defer fake.storeMutex.RUnlock()
+	return fake.storeArgsForCall[i].buildID, fake.storeArgsForCall[i].name, fake.storeArgsForCall[i].tarball
+}
+
+func (fake *FakeArtifactStore) StoreReturns(result1 error) {
+	fake.StoreStub = nil
+	fake.storeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeArtifactStore) Delete(buildID int, name string) error {
+	fake.deleteMutex.Lock()
+	fake.deleteArgsForCall = append(fake.deleteArgsForCall, struct {
+		buildID int
+		name    string
+	}{buildID, name})
+	fake.recordInvocation("Delete", []interface{}{buildID, name})
+	fake.deleteMutex.Unlock()
+	if fake.DeleteStub != nil {
+		return fake.DeleteStub(buildID, name)
+	} else {
+		return fake.deleteReturns.result1
+	}
+}
+
+func (fake *FakeArtifactStore) DeleteCallCount() int {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	return len(fake.deleteArgsForCall)
+}
+
+func (fake *FakeArtifactStore) DeleteArgsForCall(i int) (int, string) {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	return fake.deleteArgsForCall[i].buildID, fake.deleteArgsForCall[i].name
+}
+
+func (fake *FakeArtifactStore) DeleteReturns(result1 error) {
+	fake.DeleteStub = nil
+	fake.deleteReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeArtifactStore) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.openMutex.RLock()
+	defer fake.openMutex.RUnlock()
+	fake.storeMutex.RLock()
+	defer fake.storeMutex.RUnlock()
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeArtifactStore) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ buildserver.ArtifactStore = new(FakeArtifactStore)
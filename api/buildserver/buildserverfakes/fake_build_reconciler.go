@@ -0,0 +1,79 @@
+// This file was generated by counterfeiter
+package buildserverfakes
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/api/buildserver"
+)
+
+type FakeBuildReconciler struct {
+	ReconcileStub        func(logger lager.Logger) ([]int, error)
+	reconcileMutex       sync.RWMutex
+	reconcileArgsForCall []struct {
+		logger lager.Logger
+	}
+	reconcileReturns struct {
+		result1 []int
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeBuildReconciler) Reconcile(logger lager.Logger) ([]int, error) {
+	fake.reconcileMutex.Lock()
+	fake.reconcileArgsForCall = append(fake.reconcileArgsForCall, struct {
+		logger lager.Logger
+	}{logger})
+	fake.recordInvocation("Reconcile", []interface{}{logger})
+	fake.reconcileMutex.Unlock()
+	if fake.ReconcileStub != nil {
+		return fake.ReconcileStub(logger)
+	} else {
+		return fake.reconcileReturns.result1, fake.reconcileReturns.result2
+	}
+}
+
+func (fake *FakeBuildReconciler) ReconcileCallCount() int {
+	fake.reconcileMutex.RLock()
+	defer fake.reconcileMutex.RUnlock()
+	return len(fake.reconcileArgsForCall)
+}
+
+func (fake *FakeBuildReconciler) ReconcileArgsForCall(i int) lager.Logger {
+	fake.reconcileMutex.RLock()
+	defer fake.reconcileMutex.RUnlock()
+	return fake.reconcileArgsForCall[i].logger
+}
+
+func (fake *FakeBuildReconciler) ReconcileReturns(result1 []int, result2 error) {
+	fake.ReconcileStub = nil
+	fake.reconcileReturns = struct {
+		result1 []int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuildReconciler) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.reconcileMutex.RLock()
+	defer fake.reconcileMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeBuildReconciler) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ buildserver.BuildReconciler = new(FakeBuildReconciler)
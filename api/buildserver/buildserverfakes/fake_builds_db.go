@@ -2,36 +2,155 @@
 package buildserverfakes
 
 import (
+	"context"
 	"sync"
+	"time"
 
 	"github.com/concourse/atc/api/buildserver"
 	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/event"
 )
 
 type FakeBuildsDB struct {
-	GetPublicBuildsStub        func(page db.Page) ([]db.Build, db.Pagination, error)
+	GetPublicBuildsStub        func(ctx context.Context, page db.Page, filter db.BuildFilter) ([]db.Build, db.Pagination, error)
 	getPublicBuildsMutex       sync.RWMutex
 	getPublicBuildsArgsForCall []struct {
-		page db.Page
+		ctx    context.Context
+		page   db.Page
+		filter db.BuildFilter
 	}
 	getPublicBuildsReturns struct {
 		result1 []db.Build
 		result2 db.Pagination
 		result3 error
 	}
+	GetLastBuildReapTimeStub        func(ctx context.Context) (time.Time, bool, error)
+	getLastBuildReapTimeMutex       sync.RWMutex
+	getLastBuildReapTimeArgsForCall []struct {
+		ctx context.Context
+	}
+	getLastBuildReapTimeReturns struct {
+		result1 time.Time
+		result2 bool
+		result3 error
+	}
+	GetBuildsStub        func(ctx context.Context, buildIDs []int) ([]db.Build, error)
+	getBuildsMutex       sync.RWMutex
+	getBuildsArgsForCall []struct {
+		ctx      context.Context
+		buildIDs []int
+	}
+	getBuildsReturns struct {
+		result1 []db.Build
+		result2 error
+	}
+	GetGlobalMaxInFlightStub        func(ctx context.Context) (int, error)
+	getGlobalMaxInFlightMutex       sync.RWMutex
+	getGlobalMaxInFlightArgsForCall []struct {
+		ctx context.Context
+	}
+	getGlobalMaxInFlightReturns struct {
+		result1 int
+		result2 error
+	}
+	SetGlobalMaxInFlightStub        func(ctx context.Context, maxInFlight int) error
+	setGlobalMaxInFlightMutex       sync.RWMutex
+	setGlobalMaxInFlightArgsForCall []struct {
+		ctx         context.Context
+		maxInFlight int
+	}
+	setGlobalMaxInFlightReturns struct {
+		result1 error
+	}
+	CreateOneOffBuildStub        func(ctx context.Context, teamName string, spec db.OneOffBuildSpec) (db.Build, error)
+	createOneOffBuildMutex       sync.RWMutex
+	createOneOffBuildArgsForCall []struct {
+		ctx      context.Context
+		teamName string
+		spec     db.OneOffBuildSpec
+	}
+	createOneOffBuildReturns struct {
+		result1 db.Build
+		result2 error
+	}
+	GetMaintenanceStub        func(ctx context.Context) (db.Maintenance, error)
+	getMaintenanceMutex       sync.RWMutex
+	getMaintenanceArgsForCall []struct {
+		ctx context.Context
+	}
+	getMaintenanceReturns struct {
+		result1 db.Maintenance
+		result2 error
+	}
+	SetMaintenanceWindowsStub        func(ctx context.Context, windows []db.MaintenanceWindow) error
+	setMaintenanceWindowsMutex       sync.RWMutex
+	setMaintenanceWindowsArgsForCall []struct {
+		ctx     context.Context
+		windows []db.MaintenanceWindow
+	}
+	setMaintenanceWindowsReturns struct {
+		result1 error
+	}
+	SetMaintenanceOverrideStub        func(ctx context.Context, until time.Time) error
+	setMaintenanceOverrideMutex       sync.RWMutex
+	setMaintenanceOverrideArgsForCall []struct {
+		ctx   context.Context
+		until time.Time
+	}
+	setMaintenanceOverrideReturns struct {
+		result1 error
+	}
+	GetBuildEventsFromStub        func(ctx context.Context, buildID int, offset uint, limit int) ([]event.Envelope, error)
+	getBuildEventsFromMutex       sync.RWMutex
+	getBuildEventsFromArgsForCall []struct {
+		ctx     context.Context
+		buildID int
+		offset  uint
+		limit   int
+	}
+	getBuildEventsFromReturns struct {
+		result1 []event.Envelope
+		result2 error
+	}
+	GetResourceVersionsBetweenStub        func(ctx context.Context, pipelineID int, resourceName string, after db.Version, upTo db.Version, limit int) ([]db.SavedVersionedResource, error)
+	getResourceVersionsBetweenMutex       sync.RWMutex
+	getResourceVersionsBetweenArgsForCall []struct {
+		ctx          context.Context
+		pipelineID   int
+		resourceName string
+		after        db.Version
+		upTo         db.Version
+		limit        int
+	}
+	getResourceVersionsBetweenReturns struct {
+		result1 []db.SavedVersionedResource
+		result2 error
+	}
+	GetLastBuildPruneStub        func(ctx context.Context) (db.BuildPrune, bool, error)
+	getLastBuildPruneMutex       sync.RWMutex
+	getLastBuildPruneArgsForCall []struct {
+		ctx context.Context
+	}
+	getLastBuildPruneReturns struct {
+		result1 db.BuildPrune
+		result2 bool
+		result3 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeBuildsDB) GetPublicBuilds(page db.Page) ([]db.Build, db.Pagination, error) {
+func (fake *FakeBuildsDB) GetPublicBuilds(ctx context.Context, page db.Page, filter db.BuildFilter) ([]db.Build, db.Pagination, error) {
 	fake.getPublicBuildsMutex.Lock()
 	fake.getPublicBuildsArgsForCall = append(fake.getPublicBuildsArgsForCall, struct {
-		page db.Page
-	}{page})
-	fake.recordInvocation("GetPublicBuilds", []interface{}{page})
+		ctx    context.Context
+		page   db.Page
+		filter db.BuildFilter
+	}{ctx, page, filter})
+	fake.recordInvocation("GetPublicBuilds", []interface{}{ctx, page, filter})
 	fake.getPublicBuildsMutex.Unlock()
 	if fake.GetPublicBuildsStub != nil {
-		return fake.GetPublicBuildsStub(page)
+		return fake.GetPublicBuildsStub(ctx, page, filter)
 	} else {
 		return fake.getPublicBuildsReturns.result1, fake.getPublicBuildsReturns.result2, fake.getPublicBuildsReturns.result3
 	}
@@ -43,10 +162,10 @@ func (fake *FakeBuildsDB) GetPublicBuildsCallCount() int {
 	return len(fake.getPublicBuildsArgsForCall)
 }
 
-func (fake *FakeBuildsDB) GetPublicBuildsArgsForCall(i int) db.Page {
+func (fake *FakeBuildsDB) GetPublicBuildsArgsForCall(i int) (context.Context, db.Page, db.BuildFilter) {
 	fake.getPublicBuildsMutex.RLock()
 	defer fake.getPublicBuildsMutex.RUnlock()
-	return fake.getPublicBuildsArgsForCall[i].page
+	return fake.getPublicBuildsArgsForCall[i].ctx, fake.getPublicBuildsArgsForCall[i].page, fake.getPublicBuildsArgsForCall[i].filter
 }
 
 func (fake *FakeBuildsDB) GetPublicBuildsReturns(result1 []db.Build, result2 db.Pagination, result3 error) {
@@ -58,11 +177,430 @@ func (fake *FakeBuildsDB) GetPublicBuildsReturns(result1 []db.Build, result2 db.
 	}{result1, result2, result3}
 }
 
+func (fake *FakeBuildsDB) GetLastBuildReapTime(ctx context.Context) (time.Time, bool, error) {
+	fake.getLastBuildReapTimeMutex.Lock()
+	fake.getLastBuildReapTimeArgsForCall = append(fake.getLastBuildReapTimeArgsForCall, struct {
+		ctx context.Context
+	}{ctx})
+	fake.recordInvocation("GetLastBuildReapTime", []interface{}{ctx})
+	fake.getLastBuildReapTimeMutex.Unlock()
+	if fake.GetLastBuildReapTimeStub != nil {
+		return fake.GetLastBuildReapTimeStub(ctx)
+	} else {
+		return fake.getLastBuildReapTimeReturns.result1, fake.getLastBuildReapTimeReturns.result2, fake.getLastBuildReapTimeReturns.result3
+	}
+}
+
+func (fake *FakeBuildsDB) GetLastBuildReapTimeCallCount() int {
+	fake.getLastBuildReapTimeMutex.RLock()
+	defer fake.getLastBuildReapTimeMutex.RUnlock()
+	return len(fake.getLastBuildReapTimeArgsForCall)
+}
+
+func (fake *FakeBuildsDB) GetLastBuildReapTimeArgsForCall(i int) context.Context {
+	fake.getLastBuildReapTimeMutex.RLock()
+	defer fake.getLastBuildReapTimeMutex.RUnlock()
+	return fake.getLastBuildReapTimeArgsForCall[i].ctx
+}
+
+func (fake *FakeBuildsDB) GetLastBuildReapTimeReturns(result1 time.Time, result2 bool, result3 error) {
+	fake.GetLastBuildReapTimeStub = nil
+	fake.getLastBuildReapTimeReturns = struct {
+		result1 time.Time
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeBuildsDB) GetBuilds(ctx context.Context, buildIDs []int) ([]db.Build, error) {
+	var buildIDsCopy []int
+	if buildIDs != nil {
+		buildIDsCopy = make([]int, len(buildIDs))
+		copy(buildIDsCopy, buildIDs)
+	}
+	fake.getBuildsMutex.Lock()
+	fake.getBuildsArgsForCall = append(fake.getBuildsArgsForCall, struct {
+		ctx      context.Context
+		buildIDs []int
+	}{ctx, buildIDsCopy})
+	fake.recordInvocation("GetBuilds", []interface{}{ctx, buildIDsCopy})
+	fake.getBuildsMutex.Unlock()
+	if fake.GetBuildsStub != nil {
+		return fake.GetBuildsStub(ctx, buildIDs)
+	} else {
+		return fake.getBuildsReturns.result1, fake.getBuildsReturns.result2
+	}
+}
+
+func (fake *FakeBuildsDB) GetBuildsCallCount() int {
+	fake.getBuildsMutex.RLock()
+	defer fake.getBuildsMutex.RUnlock()
+	return len(fake.getBuildsArgsForCall)
+}
+
+func (fake *FakeBuildsDB) GetBuildsArgsForCall(i int) (context.Context, []int) {
+	fake.getBuildsMutex.RLock()
+	defer fake.getBuildsMutex.RUnlock()
+	return fake.getBuildsArgsForCall[i].ctx, fake.getBuildsArgsForCall[i].buildIDs
+}
+
+func (fake *FakeBuildsDB) GetBuildsReturns(result1 []db.Build, result2 error) {
+	fake.GetBuildsStub = nil
+	fake.getBuildsReturns = struct {
+		result1 []db.Build
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuildsDB) GetGlobalMaxInFlight(ctx context.Context) (int, error) {
+	fake.getGlobalMaxInFlightMutex.Lock()
+	fake.getGlobalMaxInFlightArgsForCall = append(fake.getGlobalMaxInFlightArgsForCall, struct {
+		ctx context.Context
+	}{ctx})
+	fake.recordInvocation("GetGlobalMaxInFlight", []interface{}{ctx})
+	fake.getGlobalMaxInFlightMutex.Unlock()
+	if fake.GetGlobalMaxInFlightStub != nil {
+		return fake.GetGlobalMaxInFlightStub(ctx)
+	} else {
+		return fake.getGlobalMaxInFlightReturns.result1, fake.getGlobalMaxInFlightReturns.result2
+	}
+}
+
+func (fake *FakeBuildsDB) GetGlobalMaxInFlightCallCount() int {
+	fake.getGlobalMaxInFlightMutex.RLock()
+	defer fake.getGlobalMaxInFlightMutex.RUnlock()
+	return len(fake.getGlobalMaxInFlightArgsForCall)
+}
+
+func (fake *FakeBuildsDB) GetGlobalMaxInFlightArgsForCall(i int) context.Context {
+	fake.getGlobalMaxInFlightMutex.RLock()
+	defer fake.getGlobalMaxInFlightMutex.RUnlock()
+	return fake.getGlobalMaxInFlightArgsForCall[i].ctx
+}
+
+func (fake *FakeBuildsDB) GetGlobalMaxInFlightReturns(result1 int, result2 error) {
+	fake.GetGlobalMaxInFlightStub = nil
+	fake.getGlobalMaxInFlightReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuildsDB) SetGlobalMaxInFlight(ctx context.Context, maxInFlight int) error {
+	fake.setGlobalMaxInFlightMutex.Lock()
+	fake.setGlobalMaxInFlightArgsForCall = append(fake.setGlobalMaxInFlightArgsForCall, struct {
+		ctx         context.Context
+		maxInFlight int
+	}{ctx, maxInFlight})
+	fake.recordInvocation("SetGlobalMaxInFlight", []interface{}{ctx, maxInFlight})
+	fake.setGlobalMaxInFlightMutex.Unlock()
+	if fake.SetGlobalMaxInFlightStub != nil {
+		return fake.SetGlobalMaxInFlightStub(ctx, maxInFlight)
+	} else {
+		return fake.setGlobalMaxInFlightReturns.result1
+	}
+}
+
+func (fake *FakeBuildsDB) SetGlobalMaxInFlightCallCount() int {
+	fake.setGlobalMaxInFlightMutex.RLock()
+	defer fake.setGlobalMaxInFlightMutex.RUnlock()
+	return len(fake.setGlobalMaxInFlightArgsForCall)
+}
+
+func (fake *FakeBuildsDB) SetGlobalMaxInFlightArgsForCall(i int) (context.Context, int) {
+	fake.setGlobalMaxInFlightMutex.RLock()
+	defer fake.setGlobalMaxInFlightMutex.RUnlock()
+	return fake.setGlobalMaxInFlightArgsForCall[i].ctx, fake.setGlobalMaxInFlightArgsForCall[i].maxInFlight
+}
+
+func (fake *FakeBuildsDB) SetGlobalMaxInFlightReturns(result1 error) {
+	fake.SetGlobalMaxInFlightStub = nil
+	fake.setGlobalMaxInFlightReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBuildsDB) CreateOneOffBuild(ctx context.Context, teamName string, spec db.OneOffBuildSpec) (db.Build, error) {
+	fake.createOneOffBuildMutex.Lock()
+	fake.createOneOffBuildArgsForCall = append(fake.createOneOffBuildArgsForCall, struct {
+		ctx      context.Context
+		teamName string
+		spec     db.OneOffBuildSpec
+	}{ctx, teamName, spec})
+	fake.recordInvocation("CreateOneOffBuild", []interface{}{ctx, teamName, spec})
+	fake.createOneOffBuildMutex.Unlock()
+	if fake.CreateOneOffBuildStub != nil {
+		return fake.CreateOneOffBuildStub(ctx, teamName, spec)
+	} else {
+		return fake.createOneOffBuildReturns.result1, fake.createOneOffBuildReturns.result2
+	}
+}
+
+func (fake *FakeBuildsDB) CreateOneOffBuildCallCount() int {
+	fake.createOneOffBuildMutex.RLock()
+	defer fake.createOneOffBuildMutex.RUnlock()
+	return len(fake.createOneOffBuildArgsForCall)
+}
+
+func (fake *FakeBuildsDB) CreateOneOffBuildArgsForCall(i int) (context.Context, string, db.OneOffBuildSpec) {
+	fake.createOneOffBuildMutex.RLock()
+	defer fake.createOneOffBuildMutex.RUnlock()
+	return fake.createOneOffBuildArgsForCall[i].ctx, fake.createOneOffBuildArgsForCall[i].teamName, fake.createOneOffBuildArgsForCall[i].spec
+}
+
+func (fake *FakeBuildsDB) CreateOneOffBuildReturns(result1 db.Build, result2 error) {
+	fake.CreateOneOffBuildStub = nil
+	fake.createOneOffBuildReturns = struct {
+		result1 db.Build
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuildsDB) GetMaintenance(ctx context.Context) (db.Maintenance, error) {
+	fake.getMaintenanceMutex.Lock()
+	fake.getMaintenanceArgsForCall = append(fake.getMaintenanceArgsForCall, struct {
+		ctx context.Context
+	}{ctx})
+	fake.recordInvocation("GetMaintenance", []interface{}{ctx})
+	fake.getMaintenanceMutex.Unlock()
+	if fake.GetMaintenanceStub != nil {
+		return fake.GetMaintenanceStub(ctx)
+	} else {
+		return fake.getMaintenanceReturns.result1, fake.getMaintenanceReturns.result2
+	}
+}
+
+func (fake *FakeBuildsDB) GetMaintenanceCallCount() int {
+	fake.getMaintenanceMutex.RLock()
+	defer fake.getMaintenanceMutex.RUnlock()
+	return len(fake.getMaintenanceArgsForCall)
+}
+
+func (fake *FakeBuildsDB) GetMaintenanceArgsForCall(i int) context.Context {
+	fake.getMaintenanceMutex.RLock()
+	defer fake.getMaintenanceMutex.RUnlock()
+	return fake.getMaintenanceArgsForCall[i].ctx
+}
+
+func (fake *FakeBuildsDB) GetMaintenanceReturns(result1 db.Maintenance, result2 error) {
+	fake.GetMaintenanceStub = nil
+	fake.getMaintenanceReturns = struct {
+		result1 db.Maintenance
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuildsDB) SetMaintenanceWindows(ctx context.Context, windows []db.MaintenanceWindow) error {
+	var windowsCopy []db.MaintenanceWindow
+	if windows != nil {
+		windowsCopy = make([]db.MaintenanceWindow, len(windows))
+		copy(windowsCopy, windows)
+	}
+	fake.setMaintenanceWindowsMutex.Lock()
+	fake.setMaintenanceWindowsArgsForCall = append(fake.setMaintenanceWindowsArgsForCall, struct {
+		ctx     context.Context
+		windows []db.MaintenanceWindow
+	}{ctx, windowsCopy})
+	fake.recordInvocation("SetMaintenanceWindows", []interface{}{ctx, windowsCopy})
+	fake.setMaintenanceWindowsMutex.Unlock()
+	if fake.SetMaintenanceWindowsStub != nil {
+		return fake.SetMaintenanceWindowsStub(ctx, windows)
+	} else {
+		return fake.setMaintenanceWindowsReturns.result1
+	}
+}
+
+func (fake *FakeBuildsDB) SetMaintenanceWindowsCallCount() int {
+	fake.setMaintenanceWindowsMutex.RLock()
+	defer fake.setMaintenanceWindowsMutex.RUnlock()
+	return len(fake.setMaintenanceWindowsArgsForCall)
+}
+
+func (fake *FakeBuildsDB) SetMaintenanceWindowsArgsForCall(i int) (context.Context, []db.MaintenanceWindow) {
+	fake.setMaintenanceWindowsMutex.RLock()
+	defer fake.setMaintenanceWindowsMutex.RUnlock()
+	return fake.setMaintenanceWindowsArgsForCall[i].ctx, fake.setMaintenanceWindowsArgsForCall[i].windows
+}
+
+func (fake *FakeBuildsDB) SetMaintenanceWindowsReturns(result1 error) {
+	fake.SetMaintenanceWindowsStub = nil
+	fake.setMaintenanceWindowsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBuildsDB) SetMaintenanceOverride(ctx context.Context, until time.Time) error {
+	fake.setMaintenanceOverrideMutex.Lock()
+	fake.setMaintenanceOverrideArgsForCall = append(fake.setMaintenanceOverrideArgsForCall, struct {
+		ctx   context.Context
+		until time.Time
+	}{ctx, until})
+	fake.recordInvocation("SetMaintenanceOverride", []interface{}{ctx, until})
+	fake.setMaintenanceOverrideMutex.Unlock()
+	if fake.SetMaintenanceOverrideStub != nil {
+		return fake.SetMaintenanceOverrideStub(ctx, until)
+	} else {
+		return fake.setMaintenanceOverrideReturns.result1
+	}
+}
+
+func (fake *FakeBuildsDB) SetMaintenanceOverrideCallCount() int {
+	fake.setMaintenanceOverrideMutex.RLock()
+	defer fake.setMaintenanceOverrideMutex.RUnlock()
+	return len(fake.setMaintenanceOverrideArgsForCall)
+}
+
+func (fake *FakeBuildsDB) SetMaintenanceOverrideArgsForCall(i int) (context.Context, time.Time) {
+	fake.setMaintenanceOverrideMutex.RLock()
+	defer fake.setMaintenanceOverrideMutex.RUnlock()
+	return fake.setMaintenanceOverrideArgsForCall[i].ctx, fake.setMaintenanceOverrideArgsForCall[i].until
+}
+
+func (fake *FakeBuildsDB) SetMaintenanceOverrideReturns(result1 error) {
+	fake.SetMaintenanceOverrideStub = nil
+	fake.setMaintenanceOverrideReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBuildsDB) GetBuildEventsFrom(ctx context.Context, buildID int, offset uint, limit int) ([]event.Envelope, error) {
+	fake.getBuildEventsFromMutex.Lock()
+	fake.getBuildEventsFromArgsForCall = append(fake.getBuildEventsFromArgsForCall, struct {
+		ctx     context.Context
+		buildID int
+		offset  uint
+		limit   int
+	}{ctx, buildID, offset, limit})
+	fake.recordInvocation("GetBuildEventsFrom", []interface{}{ctx, buildID, offset, limit})
+	fake.getBuildEventsFromMutex.Unlock()
+	if fake.GetBuildEventsFromStub != nil {
+		return fake.GetBuildEventsFromStub(ctx, buildID, offset, limit)
+	} else {
+		return fake.getBuildEventsFromReturns.result1, fake.getBuildEventsFromReturns.result2
+	}
+}
+
+func (fake *FakeBuildsDB) GetBuildEventsFromCallCount() int {
+	fake.getBuildEventsFromMutex.RLock()
+	defer fake.getBuildEventsFromMutex.RUnlock()
+	return len(fake.getBuildEventsFromArgsForCall)
+}
+
+func (fake *FakeBuildsDB) GetBuildEventsFromArgsForCall(i int) (context.Context, int, uint, int) {
+	fake.getBuildEventsFromMutex.RLock()
+	defer fake.getBuildEventsFromMutex.RUnlock()
+	return fake.getBuildEventsFromArgsForCall[i].ctx, fake.getBuildEventsFromArgsForCall[i].buildID, fake.getBuildEventsFromArgsForCall[i].offset, fake.getBuildEventsFromArgsForCall[i].limit
+}
+
+func (fake *FakeBuildsDB) GetBuildEventsFromReturns(result1 []event.Envelope, result2 error) {
+	fake.GetBuildEventsFromStub = nil
+	fake.getBuildEventsFromReturns = struct {
+		result1 []event.Envelope
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuildsDB) GetResourceVersionsBetween(ctx context.Context, pipelineID int, resourceName string, after db.Version, upTo db.Version, limit int) ([]db.SavedVersionedResource, error) {
+	fake.getResourceVersionsBetweenMutex.Lock()
+	fake.getResourceVersionsBetweenArgsForCall = append(fake.getResourceVersionsBetweenArgsForCall, struct {
+		ctx          context.Context
+		pipelineID   int
+		resourceName string
+		after        db.Version
+		upTo         db.Version
+		limit        int
+	}{ctx, pipelineID, resourceName, after, upTo, limit})
+	fake.recordInvocation("GetResourceVersionsBetween", []interface{}{ctx, pipelineID, resourceName, after, upTo, limit})
+	fake.getResourceVersionsBetweenMutex.Unlock()
+	if fake.GetResourceVersionsBetweenStub != nil {
+		return fake.GetResourceVersionsBetweenStub(ctx, pipelineID, resourceName, after, upTo, limit)
+	} else {
+		return fake.getResourceVersionsBetweenReturns.result1, fake.getResourceVersionsBetweenReturns.result2
+	}
+}
+
+func (fake *FakeBuildsDB) GetResourceVersionsBetweenCallCount() int {
+	fake.getResourceVersionsBetweenMutex.RLock()
+	defer fake.getResourceVersionsBetweenMutex.RUnlock()
+	return len(fake.getResourceVersionsBetweenArgsForCall)
+}
+
+func (fake *FakeBuildsDB) GetResourceVersionsBetweenArgsForCall(i int) (context.Context, int, string, db.Version, db.Version, int) {
+	fake.getResourceVersionsBetweenMutex.RLock()
+	defer fake.getResourceVersionsBetweenMutex.RUnlock()
+	return fake.getResourceVersionsBetweenArgsForCall[i].ctx, fake.getResourceVersionsBetweenArgsForCall[i].pipelineID, fake.getResourceVersionsBetweenArgsForCall[i].resourceName, fake.getResourceVersionsBetweenArgsForCall[i].after, fake.getResourceVersionsBetweenArgsForCall[i].upTo, fake.getResourceVersionsBetweenArgsForCall[i].limit
+}
+
+func (fake *FakeBuildsDB) GetResourceVersionsBetweenReturns(result1 []db.SavedVersionedResource, result2 error) {
+	fake.GetResourceVersionsBetweenStub = nil
+	fake.getResourceVersionsBetweenReturns = struct {
+		result1 []db.SavedVersionedResource
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuildsDB) GetLastBuildPrune(ctx context.Context) (db.BuildPrune, bool, error) {
+	fake.getLastBuildPruneMutex.Lock()
+	fake.getLastBuildPruneArgsForCall = append(fake.getLastBuildPruneArgsForCall, struct {
+		ctx context.Context
+	}{ctx})
+	fake.recordInvocation("GetLastBuildPrune", []interface{}{ctx})
+	fake.getLastBuildPruneMutex.Unlock()
+	if fake.GetLastBuildPruneStub != nil {
+		return fake.GetLastBuildPruneStub(ctx)
+	} else {
+		return fake.getLastBuildPruneReturns.result1, fake.getLastBuildPruneReturns.result2, fake.getLastBuildPruneReturns.result3
+	}
+}
+
+func (fake *FakeBuildsDB) GetLastBuildPruneCallCount() int {
+	fake.getLastBuildPruneMutex.RLock()
+	defer fake.getLastBuildPruneMutex.RUnlock()
+	return len(fake.getLastBuildPruneArgsForCall)
+}
+
+func (fake *FakeBuildsDB) GetLastBuildPruneArgsForCall(i int) context.Context {
+	fake.getLastBuildPruneMutex.RLock()
+	defer fake.getLastBuildPruneMutex.RUnlock()
+	return fake.getLastBuildPruneArgsForCall[i].ctx
+}
+
+func (fake *FakeBuildsDB) GetLastBuildPruneReturns(result1 db.BuildPrune, result2 bool, result3 error) {
+	fake.GetLastBuildPruneStub = nil
+	fake.getLastBuildPruneReturns = struct {
+		result1 db.BuildPrune
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
 func (fake *FakeBuildsDB) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.getPublicBuildsMutex.RLock()
 	defer fake.getPublicBuildsMutex.RUnlock()
+	fake.getLastBuildReapTimeMutex.RLock()
+	defer fake.getLastBuildReapTimeMutex.RUnlock()
+	fake.getBuildsMutex.RLock()
+	defer fake.getBuildsMutex.RUnlock()
+	fake.getGlobalMaxInFlightMutex.RLock()
+	defer fake.getGlobalMaxInFlightMutex.RUnlock()
+	fake.setGlobalMaxInFlightMutex.RLock()
+	defer fake.setGlobalMaxInFlightMutex.RUnlock()
+	fake.createOneOffBuildMutex.RLock()
+	defer fake.createOneOffBuildMutex.RUnlock()
+	fake.getMaintenanceMutex.RLock()
+	defer fake.getMaintenanceMutex.RUnlock()
+	fake.setMaintenanceWindowsMutex.RLock()
+	defer fake.setMaintenanceWindowsMutex.RUnlock()
+	fake.setMaintenanceOverrideMutex.RLock()
+	defer fake.setMaintenanceOverrideMutex.RUnlock()
+	fake.getBuildEventsFromMutex.RLock()
+	defer fake.getBuildEventsFromMutex.RUnlock()
+	fake.getResourceVersionsBetweenMutex.RLock()
+	defer fake.getResourceVersionsBetweenMutex.RUnlock()
+	fake.getLastBuildPruneMutex.RLock()
+	defer fake.getLastBuildPruneMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -0,0 +1,186 @@
+package buildserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"sync"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/creds"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/event"
+	"gopkg.in/yaml.v2"
+)
+
+// CensorRule describes what to withhold from a build's event stream.
+type CensorRule struct {
+	// Hide lists event types that are dropped from the stream entirely.
+	Hide []atc.EventType `yaml:"hide"`
+
+	// Redact lists, per event type, fields of the event payload to remove.
+	// Fields are dot-separated paths into the payload, e.g. "config.run.args".
+	Redact map[atc.EventType][]string `yaml:"redact"`
+
+	// secrets are the credentials interpolated into the build's plan, which
+	// are redacted from every event whatever the policy says
+	secrets *buildSecrets
+}
+
+// buildSecrets loads a build's secrets the first time they're needed; by the
+// time there are events to censor the build has started, so they're saved.
+type buildSecrets struct {
+	build db.Build
+
+	once   sync.Once
+	values []string
+	err    error
+}
+
+func (secrets *buildSecrets) Values() ([]string, error) {
+	if secrets == nil {
+		return nil, nil
+	}
+
+	secrets.once.Do(func() {
+		secrets.values, secrets.err = secrets.build.GetSecrets()
+	})
+
+	return secrets.values, secrets.err
+}
+
+// CensorPolicy distinguishes between viewers authorized for the build's team
+// and everyone else, e.g. unauthenticated viewers of a public pipeline.
+type CensorPolicy struct {
+	Team   CensorRule `yaml:"team"`
+	Public CensorRule `yaml:"public"`
+}
+
+// CensorPolicies applies the default policy to every build, except for
+// builds of pipelines listed by "team/pipeline", which use their own.
+type CensorPolicies struct {
+	Default   CensorPolicy            `yaml:"default"`
+	Pipelines map[string]CensorPolicy `yaml:"pipelines"`
+}
+
+func LoadCensorPolicies(path string) (CensorPolicies, error) {
+	var policies CensorPolicies
+
+	payload, err := ioutil.ReadFile(path)
+	if err != nil {
+		return CensorPolicies{}, err
+	}
+
+	err = yaml.Unmarshal(payload, &policies)
+	if err != nil {
+		return CensorPolicies{}, err
+	}
+
+	return policies, nil
+}
+
+func (policies CensorPolicies) RuleFor(build db.Build, teamMember bool) CensorRule {
+	policy := policies.Default
+
+	if !build.IsOneOff() {
+		pipelinePolicy, found := policies.Pipelines[build.TeamName()+"/"+build.PipelineName()]
+		if found {
+			policy = pipelinePolicy
+		}
+	}
+
+	rule := policy.Public
+	if teamMember {
+		rule = policy.Team
+	}
+
+	rule.secrets = &buildSecrets{build: build}
+
+	return rule
+}
+
+// Censor returns the envelope with redacted fields removed and the build's
+// secrets replaced with creds.Redacted, or false if the event should not be
+// sent at all.
+func (rule CensorRule) Censor(envelope event.Envelope) (event.Envelope, bool, error) {
+	for _, hidden := range rule.Hide {
+		if envelope.Event == hidden {
+			return event.Envelope{}, false, nil
+		}
+	}
+
+	secrets, err := rule.secrets.Values()
+	if err != nil {
+		return event.Envelope{}, false, err
+	}
+
+	fields := rule.Redact[envelope.Event]
+	if (len(fields) == 0 && len(secrets) == 0) || envelope.Data == nil {
+		return envelope, true, nil
+	}
+
+	var payload interface{}
+	err = json.Unmarshal(*envelope.Data, &payload)
+	if err != nil {
+		return event.Envelope{}, false, err
+	}
+
+	if len(fields) > 0 {
+		object, ok := payload.(map[string]interface{})
+		if !ok {
+			return event.Envelope{}, false, fmt.Errorf("cannot redact fields of non-object %s payload", envelope.Event)
+		}
+
+		for _, field := range fields {
+			redact(object, strings.Split(field, "."))
+		}
+	}
+
+	if len(secrets) > 0 {
+		payload = redactSecrets(payload, secrets)
+	}
+
+	redacted, err := json.Marshal(payload)
+	if err != nil {
+		return event.Envelope{}, false, err
+	}
+
+	data := json.RawMessage(redacted)
+	envelope.Data = &data
+
+	return envelope, true, nil
+}
+
+func redact(payload map[string]interface{}, path []string) {
+	if len(path) == 1 {
+		delete(payload, path[0])
+		return
+	}
+
+	nested, ok := payload[path[0]].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	redact(nested, path[1:])
+}
+
+func redactSecrets(payload interface{}, secrets []string) interface{} {
+	switch payload := payload.(type) {
+	case map[string]interface{}:
+		for key, value := range payload {
+			payload[key] = redactSecrets(value, secrets)
+		}
+
+	case []interface{}:
+		for i, value := range payload {
+			payload[i] = redactSecrets(value, secrets)
+		}
+
+	case string:
+		return creds.Redact(payload, secrets)
+	}
+
+	return payload
+}
@@ -0,0 +1,327 @@
+package buildserver_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	"github.com/concourse/atc"
+	. "github.com/concourse/atc/api/buildserver"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/db/dbfakes"
+	"github.com/concourse/atc/event"
+	"github.com/vito/go-sse/sse"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Censoring", func() {
+	Describe("LoadCensorPolicies", func() {
+		var tmpdir string
+
+		BeforeEach(func() {
+			var err error
+			tmpdir, err = ioutil.TempDir("", "censor-policies")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(tmpdir)
+		})
+
+		It("loads policies from YAML", func() {
+			path := filepath.Join(tmpdir, "policies.yml")
+			err := ioutil.WriteFile(path, []byte(`
+default:
+  public:
+    hide: [initialize-task]
+pipelines:
+  some-team/some-pipeline:
+    public:
+      redact:
+        initialize-task: [config.run.args]
+`), 0644)
+			Expect(err).NotTo(HaveOccurred())
+
+			policies, err := LoadCensorPolicies(path)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(policies).To(Equal(CensorPolicies{
+				Default: CensorPolicy{
+					Public: CensorRule{
+						Hide: []atc.EventType{"initialize-task"},
+					},
+				},
+				Pipelines: map[string]CensorPolicy{
+					"some-team/some-pipeline": {
+						Public: CensorRule{
+							Redact: map[atc.EventType][]string{
+								"initialize-task": {"config.run.args"},
+							},
+						},
+					},
+				},
+			}))
+		})
+
+		It("fails when the file is malformed", func() {
+			path := filepath.Join(tmpdir, "policies.yml")
+			err := ioutil.WriteFile(path, []byte(`{{{`), 0644)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = LoadCensorPolicies(path)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("CensorPolicies.RuleFor", func() {
+		var (
+			policies CensorPolicies
+			build    *dbfakes.FakeBuild
+		)
+
+		BeforeEach(func() {
+			policies = CensorPolicies{
+				Default: CensorPolicy{
+					Team:   CensorRule{Hide: []atc.EventType{"default-team"}},
+					Public: CensorRule{Hide: []atc.EventType{"default-public"}},
+				},
+				Pipelines: map[string]CensorPolicy{
+					"some-team/some-pipeline": {
+						Team:   CensorRule{Hide: []atc.EventType{"pipeline-team"}},
+						Public: CensorRule{Hide: []atc.EventType{"pipeline-public"}},
+					},
+				},
+			}
+
+			build = new(dbfakes.FakeBuild)
+			build.TeamNameReturns("some-team")
+		})
+
+		Context("when the build belongs to a pipeline with its own policy", func() {
+			BeforeEach(func() {
+				build.PipelineNameReturns("some-pipeline")
+			})
+
+			It("uses the pipeline's policy", func() {
+				Expect(policies.RuleFor(build, true).Hide).To(ConsistOf(atc.EventType("pipeline-team")))
+				Expect(policies.RuleFor(build, false).Hide).To(ConsistOf(atc.EventType("pipeline-public")))
+			})
+		})
+
+		Context("when the build belongs to any other pipeline", func() {
+			BeforeEach(func() {
+				build.PipelineNameReturns("some-other-pipeline")
+			})
+
+			It("uses the default policy", func() {
+				Expect(policies.RuleFor(build, true).Hide).To(ConsistOf(atc.EventType("default-team")))
+				Expect(policies.RuleFor(build, false).Hide).To(ConsistOf(atc.EventType("default-public")))
+			})
+		})
+
+		Context("when the build is a one-off", func() {
+			BeforeEach(func() {
+				build.IsOneOffReturns(true)
+			})
+
+			It("uses the default policy", func() {
+				Expect(policies.RuleFor(build, false).Hide).To(ConsistOf(atc.EventType("default-public")))
+			})
+		})
+	})
+
+	Describe("CensorRule.Censor", func() {
+		var rule CensorRule
+
+		BeforeEach(func() {
+			rule = CensorRule{
+				Hide: []atc.EventType{"hidden"},
+				Redact: map[atc.EventType][]string{
+					"redacted": {"config.run.args", "origin", "config.bogus.path"},
+				},
+			}
+		})
+
+		It("hides events of hidden types", func() {
+			_, send, err := rule.Censor(fakeTypedEvent("hidden", `{}`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(send).To(BeFalse())
+		})
+
+		It("removes redacted fields", func() {
+			censored, send, err := rule.Censor(fakeTypedEvent("redacted", `{
+				"config": {"run": {"path": "ls", "args": ["-al"]}},
+				"origin": {"id": "some-id"},
+				"time": 42
+			}`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(send).To(BeTrue())
+
+			Expect(censored.Event).To(Equal(atc.EventType("redacted")))
+			Expect([]byte(*censored.Data)).To(MatchJSON(`{
+				"config": {"run": {"path": "ls"}},
+				"time": 42
+			}`))
+		})
+
+		It("leaves other events alone", func() {
+			ev := fakeTypedEvent("log", `{"payload":"hello"}`)
+
+			censored, send, err := rule.Censor(ev)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(send).To(BeTrue())
+			Expect(censored).To(Equal(ev))
+		})
+
+		It("fails when a redacted event's payload is not an object", func() {
+			_, _, err := rule.Censor(fakeTypedEvent("redacted", `"nope"`))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("censoring a build's secrets", func() {
+		var (
+			build *dbfakes.FakeBuild
+			rule  CensorRule
+		)
+
+		BeforeEach(func() {
+			build = new(dbfakes.FakeBuild)
+			build.IsOneOffReturns(true)
+			build.GetSecretsReturns([]string{"some-password", "some-private-key"}, nil)
+
+			rule = CensorPolicies{}.RuleFor(build, true)
+		})
+
+		It("replaces them wherever they appear in an event", func() {
+			censored, send, err := rule.Censor(fakeTypedEvent("log", `{
+				"payload": "logging in with some-password",
+				"origin": {"id": "some-id"},
+				"keys": ["some-private-key"]
+			}`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(send).To(BeTrue())
+
+			Expect([]byte(*censored.Data)).To(MatchJSON(`{
+				"payload": "logging in with ((redacted))",
+				"origin": {"id": "some-id"},
+				"keys": ["((redacted))"]
+			}`))
+		})
+
+		It("only loads them once", func() {
+			_, _, err := rule.Censor(fakeTypedEvent("log", `{"payload":"a"}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			_, _, err = rule.Censor(fakeTypedEvent("log", `{"payload":"b"}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(build.GetSecretsCallCount()).To(Equal(1))
+		})
+
+		Context("when loading them fails", func() {
+			BeforeEach(func() {
+				build.GetSecretsReturns(nil, errors.New("nope"))
+			})
+
+			It("fails rather than sending the event as it is", func() {
+				_, send, err := rule.Censor(fakeTypedEvent("log", `{"payload":"hello"}`))
+				Expect(err).To(HaveOccurred())
+				Expect(send).To(BeFalse())
+			})
+		})
+	})
+
+	Describe("a censoring event handler", func() {
+		var (
+			build  *dbfakes.FakeBuild
+			server *httptest.Server
+
+			fakeEventSource *dbfakes.FakeEventSource
+		)
+
+		BeforeEach(func() {
+			build = new(dbfakes.FakeBuild)
+			build.TeamNameReturns("some-team")
+			build.IsOneOffReturns(true)
+
+			returnedEvents := []event.Envelope{
+				fakeTypedEvent("log", `{"payload":"one"}`),
+				fakeTypedEvent("initialize-task", `{"config":{"run":{"path":"ls","args":["-al"]}}}`),
+				fakeTypedEvent("log", `{"payload":"two"}`),
+			}
+
+			fakeEventSource = new(dbfakes.FakeEventSource)
+
+			build.EventsStub = func(from uint) (db.EventSource, error) {
+				fakeEventSource.NextStub = func() (event.Envelope, error) {
+					if from >= uint(len(returnedEvents)) {
+						return event.Envelope{}, db.ErrEndOfBuildEventStream
+					}
+
+					from++
+
+					return returnedEvents[from-1], nil
+				}
+
+				return fakeEventSource, nil
+			}
+
+			handlerFactory := NewCensoringEventHandlerFactory(CensorPolicies{
+				Default: CensorPolicy{
+					Public: CensorRule{
+						Hide: []atc.EventType{"initialize-task"},
+					},
+				},
+			}, nil)
+
+			server = httptest.NewServer(handlerFactory(lagertest.NewTestLogger("test"), build))
+		})
+
+		AfterEach(func() {
+			Eventually(fakeEventSource.CloseCallCount, 30*time.Second).Should(Equal(1))
+			server.Close()
+		})
+
+		It("applies the public rule to unauthenticated viewers, preserving event ids", func() {
+			response, err := http.Get(server.URL)
+			Expect(err).NotTo(HaveOccurred())
+
+			reader := sse.NewReadCloser(response.Body)
+
+			Expect(reader.Next()).To(Equal(sse.Event{
+				ID:   "0",
+				Name: "event",
+				Data: []byte(`{"data":{"payload":"one"},"event":"log","version":"42.0"},"id":0}`),
+			}))
+
+			Expect(reader.Next()).To(Equal(sse.Event{
+				ID:   "2",
+				Name: "event",
+				Data: []byte(`{"data":{"payload":"two"},"event":"log","version":"42.0"},"id":2}`),
+			}))
+
+			Expect(reader.Next()).To(Equal(sse.Event{
+				Name: "end",
+				Data: []byte{},
+			}))
+		})
+	})
+})
+
+func fakeTypedEvent(eventType atc.EventType, payload string) event.Envelope {
+	msg := json.RawMessage(payload)
+	return event.Envelope{
+		Data:    &msg,
+		Event:   eventType,
+		Version: "42.0",
+	}
+}
@@ -0,0 +1,76 @@
+package buildserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/api/present"
+	"github.com/concourse/atc/auth"
+	"github.com/concourse/atc/db"
+)
+
+// CreateBuildComment leaves a comment on the build. The author is whoever
+// made the request, not something the request gets to say.
+func (s *Server) CreateBuildComment(build db.Build) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger := s.logger.Session("create-build-comment", lager.Data{"build-id": build.ID()})
+
+		var request struct {
+			Text string `json:"text"`
+		}
+
+		err := json.NewDecoder(r.Body).Decode(&request)
+		if err != nil {
+			logger.Info("malformed-request", lager.Data{"error": err.Error()})
+			http.Error(w, "malformed request", http.StatusBadRequest)
+			return
+		}
+
+		if strings.TrimSpace(request.Text) == "" {
+			logger.Info("empty-comment")
+			http.Error(w, "comment text must be given", http.StatusBadRequest)
+			return
+		}
+
+		comment, err := build.SaveComment(auth.GetActor(r), request.Text)
+		if err != nil {
+			logger.Error("failed-to-save-comment", err)
+			apierror.DBFailure(w, "failed to save comment")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(present.BuildComment(comment))
+	})
+}
+
+func (s *Server) ListBuildComments(build db.Build) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger := s.logger.Session("list-build-comments", lager.Data{"build-id": build.ID()})
+
+		comments, err := build.GetComments()
+		if err != nil {
+			logger.Error("failed-to-get-comments", err)
+			apierror.DBFailure(w, "failed to get comments")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(presentBuildComments(comments))
+	})
+}
+
+func presentBuildComments(comments []db.BuildComment) []atc.BuildComment {
+	presentedComments := make([]atc.BuildComment, len(comments))
+	for i, comment := range comments {
+		presentedComments[i] = present.BuildComment(comment)
+	}
+
+	return presentedComments
+}
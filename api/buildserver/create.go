@@ -2,18 +2,24 @@ package buildserver
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 
 	"code.cloudfoundry.org/lager"
 	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
 	"github.com/concourse/atc/api/present"
+	"github.com/concourse/atc/auth"
 	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/requestlog"
 )
 
 func (s *Server) CreateBuild(teamDB db.TeamDB) http.Handler {
 	hLog := s.logger.Session("create-build")
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hLog := requestlog.Logger(hLog, r)
+
 		var plan atc.Plan
 		err := json.NewDecoder(r.Body).Decode(&plan)
 		if err != nil {
@@ -22,21 +28,118 @@ func (s *Server) CreateBuild(teamDB db.TeamDB) http.Handler {
 			return
 		}
 
-		build, err := teamDB.CreateOneOffBuild()
+		labels, err := parseLabels(r)
+		if err != nil {
+			hLog.Info("malformed-label", lager.Data{"error": err.Error()})
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		priority, err := parsePriority(r)
+		if err != nil {
+			hLog.Info("malformed-priority", lager.Data{"error": err.Error()})
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		timeout, err := parseTimeout(r)
+		if err != nil {
+			hLog.Info("malformed-timeout", lager.Data{"error": err.Error()})
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		dependsOn, err := parseDependencies(r)
+		if err != nil {
+			hLog.Info("malformed-dependency", lager.Data{"error": err.Error()})
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		authTeam, _ := auth.GetTeam(r)
+
+		if len(dependsOn) > 0 {
+			dependencies, err := s.buildsDB.GetBuilds(r.Context(), dependsOn)
+			if err != nil {
+				hLog.Error("failed-to-get-dependencies", err)
+				apierror.DBFailure(w, "failed to get dependencies")
+				return
+			}
+
+			found := map[int]bool{}
+			for _, dependency := range dependencies {
+				// builds of other teams are treated as if they don't exist, so
+				// that their IDs can't be probed for
+				if authTeam != nil && authTeam.IsAuthorized(dependency.TeamName()) {
+					found[dependency.ID()] = true
+				}
+			}
+
+			for _, id := range dependsOn {
+				if !found[id] {
+					hLog.Info("unknown-dependency", lager.Data{"build-id": id})
+					http.Error(w, fmt.Sprintf("build %d does not exist", id), http.StatusUnprocessableEntity)
+					return
+				}
+			}
+		}
+
+		build, err := s.buildsDB.CreateOneOffBuild(r.Context(), authTeam.Name(), db.OneOffBuildSpec{
+			Plan:      plan,
+			Labels:    labels,
+			Priority:  priority,
+			Timeout:   timeout,
+			DependsOn: dependsOn,
+		})
 		if err != nil {
 			hLog.Error("failed-to-create-one-off-build", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.DBFailure(w, "failed to create one off build")
+			return
+		}
+
+		if len(dependsOn) > 0 {
+			// the build stays pending until the dependency starter sees all of
+			// its dependencies succeed, at which point it starts the build itself
+			w.WriteHeader(http.StatusCreated)
+
+			json.NewEncoder(w).Encode(present.Build(build))
 			return
 		}
 
-		engineBuild, err := s.engine.CreateBuild(hLog, build, plan)
+		// whoever claims the plan starts the build; should that not be us, the
+		// dependency starter got to it first
+		pendingPlan, claimed, err := build.ClaimPendingPlan()
 		if err != nil {
-			hLog.Error("failed-to-start-build", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			hLog.Error("failed-to-claim-pending-plan", err)
+			apierror.DBFailure(w, "failed to claim pending plan")
 			return
 		}
 
-		go engineBuild.Resume(hLog)
+		if claimed {
+			engineBuild, err := s.engine.CreateBuild(hLog, build, pendingPlan)
+			if err != nil {
+				hLog.Error("failed-to-start-build", err)
+				apierror.BuilderFailure(w, "failed to start build")
+				return
+			}
+
+			go engineBuild.Resume(hLog)
+		}
+
+		// the engine has moved the build along to started; pick that up so the
+		// response reflects it rather than the pending build we created
+		found, err := build.Reload()
+		if err != nil {
+			hLog.Error("failed-to-reload-build", err)
+			apierror.DBFailure(w, "failed to reload build")
+			return
+		}
+
+		if !found {
+			hLog.Info("build-disappeared", lager.Data{"build-id": build.ID()})
+			apierror.DBFailure(w, "build disappeared")
+			return
+		}
 
 		w.WriteHeader(http.StatusCreated)
 
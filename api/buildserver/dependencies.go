@@ -0,0 +1,36 @@
+package buildserver
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// DependsOnQueryParam names a build that must succeed before the one being
+// created is started. It may be given more than once.
+const DependsOnQueryParam = "depends_on"
+
+func parseDependencies(r *http.Request) ([]int, error) {
+	values := r.URL.Query()[DependsOnQueryParam]
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	seen := map[int]bool{}
+	ids := []int{}
+	for _, value := range values {
+		id, err := strconv.Atoi(value)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("malformed build id '%s'", value)
+		}
+
+		if seen[id] {
+			continue
+		}
+
+		seen[id] = true
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
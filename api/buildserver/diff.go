@@ -0,0 +1,115 @@
+package buildserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/api/present"
+	"github.com/concourse/atc/db"
+)
+
+// DiffVersionLimit is the most new versions listed for each input.
+const DiffVersionLimit = 50
+
+// GetBuildDiff compares the build's inputs with those of the job's last
+// successful build before it, to show what changed since it last went green.
+func (s *Server) GetBuildDiff(build db.Build) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger := s.logger.Session("get-build-diff", lager.Data{"build-id": build.ID()})
+
+		inputs, _, err := build.GetResources()
+		if err != nil {
+			logger.Error("failed-to-get-inputs", err)
+			apierror.DBFailure(w, "failed to get build inputs")
+			return
+		}
+
+		previousBuild, found, err := build.GetPreviousSuccessfulBuild()
+		if err != nil {
+			logger.Error("failed-to-get-previous-successful-build", err)
+			apierror.DBFailure(w, "failed to get previous successful build")
+			return
+		}
+
+		diff := atc.BuildDiff{
+			Inputs: []atc.BuildInputDiff{},
+		}
+
+		previousInputs := map[string]db.BuildInput{}
+		var previousOrder []string
+
+		if found {
+			presented := present.Build(previousBuild)
+			diff.PreviousBuild = &presented
+
+			previous, _, err := previousBuild.GetResources()
+			if err != nil {
+				logger.Error("failed-to-get-previous-inputs", err)
+				apierror.DBFailure(w, "failed to get previous build inputs")
+				return
+			}
+
+			for _, input := range previous {
+				previousInputs[input.Name] = input
+				previousOrder = append(previousOrder, input.Name)
+			}
+		}
+
+		current := map[string]bool{}
+		for _, input := range inputs {
+			current[input.Name] = true
+
+			presentedInput := present.PublicBuildInput(input)
+			inputDiff := atc.BuildInputDiff{
+				Name:    input.Name,
+				Changed: true,
+				Input:   &presentedInput,
+			}
+
+			previous, hadInput := previousInputs[input.Name]
+			if hadInput {
+				presentedPrevious := present.PublicBuildInput(previous)
+				inputDiff.Previous = &presentedPrevious
+
+				sameResource := previous.Resource == input.Resource && previous.PipelineID == input.PipelineID
+				inputDiff.Changed = !sameResource || !reflect.DeepEqual(previous.Version, input.Version)
+
+				if inputDiff.Changed && sameResource {
+					versions, err := s.buildsDB.GetResourceVersionsBetween(r.Context(), input.PipelineID, input.Resource, previous.Version, input.Version, DiffVersionLimit)
+					if err != nil {
+						logger.Error("failed-to-get-new-versions", err, lager.Data{"input": input.Name})
+						apierror.DBFailure(w, "failed to get new versions")
+						return
+					}
+
+					for _, version := range versions {
+						inputDiff.NewVersions = append(inputDiff.NewVersions, present.SavedVersionedResource(version))
+					}
+				}
+			}
+
+			diff.Inputs = append(diff.Inputs, inputDiff)
+		}
+
+		for _, name := range previousOrder {
+			if current[name] {
+				continue
+			}
+
+			presentedPrevious := present.PublicBuildInput(previousInputs[name])
+			diff.Inputs = append(diff.Inputs, atc.BuildInputDiff{
+				Name:     name,
+				Changed:  true,
+				Previous: &presentedPrevious,
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(diff)
+	})
+}
@@ -0,0 +1,56 @@
+package buildserver
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+type drainingKey struct{}
+
+func withDraining(r *http.Request, draining <-chan struct{}) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), drainingKey{}, draining))
+}
+
+// drainingFrom returns a channel that is closed when the server starts
+// draining. Requests that were not routed through the Server never drain, so
+// the channel is nil.
+func drainingFrom(r *http.Request) <-chan struct{} {
+	draining, _ := r.Context().Value(drainingKey{}).(<-chan struct{})
+	return draining
+}
+
+// superviseStream watches an open event stream until stop is closed. When the
+// server starts draining, onDrain is called so the client can be told to
+// reconnect elsewhere; once the request is done the events are closed, which
+// ends the stream. In the meantime onKeepAlive is called on the request's
+// keepalive interval, if it has one.
+func superviseStream(r *http.Request, closeEvents func(), onDrain func(), onKeepAlive func(), stop <-chan struct{}) {
+	draining := drainingFrom(r)
+
+	var keepAlive <-chan time.Time
+	if interval := keepAliveIntervalFrom(r); interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		keepAlive = ticker.C
+	}
+
+	for {
+		select {
+		case <-draining:
+			draining = nil
+			onDrain()
+
+		case <-keepAlive:
+			onKeepAlive()
+
+		case <-r.Context().Done():
+			closeEvents()
+			return
+
+		case <-stop:
+			return
+		}
+	}
+}
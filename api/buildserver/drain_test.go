@@ -0,0 +1,124 @@
+package buildserver_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/concourse/atc/api/buildserver"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/db/dbfakes"
+	"github.com/concourse/atc/event"
+	"github.com/vito/go-sse/sse"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Draining", func() {
+	var (
+		build           *dbfakes.FakeBuild
+		fakeEventSource *dbfakes.FakeEventSource
+		closed          chan struct{}
+
+		drain  chan struct{}
+		server *httptest.Server
+	)
+
+	BeforeEach(func() {
+		build = new(dbfakes.FakeBuild)
+
+		fakeEventSource = new(dbfakes.FakeEventSource)
+
+		closed = make(chan struct{})
+		fakeEventSource.CloseStub = func() error {
+			close(closed)
+			return nil
+		}
+
+		sent := false
+		fakeEventSource.NextStub = func() (event.Envelope, error) {
+			if !sent {
+				sent = true
+				return fakeEvent(`{"event":1}`), nil
+			}
+
+			<-closed
+
+			return event.Envelope{}, db.ErrBuildEventStreamClosed
+		}
+
+		build.EventsReturns(fakeEventSource, nil)
+
+		drain = make(chan struct{})
+
+		buildServer := NewServer(
+			lagertest.NewTestLogger("test"),
+			"http://example.com",
+			nil,
+			nil,
+			nil,
+			nil,
+			NewEventHandler,
+			CensorPolicies{},
+			nil,
+			drain,
+			100*time.Millisecond,
+			KeepAlivePolicy{},
+			nil,
+			nil,
+		)
+
+		server = httptest.NewServer(buildServer.BuildEvents(build))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Context("when a stream is open", func() {
+		It("tells the client to reconnect, and closes the stream after the grace period", func() {
+			response, err := http.Get(server.URL)
+			Expect(err).NotTo(HaveOccurred())
+
+			reader := sse.NewReadCloser(response.Body)
+			defer reader.Close()
+
+			Expect(reader.Next()).To(Equal(sse.Event{
+				ID:   "0",
+				Name: "event",
+				Data: []byte(`{"data":{"event":1},"event":"fake","version":"42.0"},"id":0}`),
+			}))
+
+			close(drain)
+
+			Expect(reader.Next()).To(Equal(sse.Event{
+				Name: "drain",
+				Data: []byte{},
+			}))
+
+			_, err = reader.Next()
+			Expect(err).To(Equal(io.EOF))
+
+			Expect(fakeEventSource.CloseCallCount()).To(Equal(1))
+		})
+	})
+
+	Context("when the server is already draining", func() {
+		BeforeEach(func() {
+			close(drain)
+		})
+
+		It("refuses new streams", func() {
+			response, err := http.Get(server.URL)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(response.StatusCode).To(Equal(http.StatusServiceUnavailable))
+			Expect(response.Header.Get("Retry-After")).To(Equal("1"))
+
+			Expect(build.EventsCallCount()).To(BeZero())
+		})
+	})
+})
@@ -0,0 +1,68 @@
+package buildserver
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	EncodingGzip    = "gzip"
+	EncodingDeflate = "deflate"
+)
+
+type compressingWriter interface {
+	io.WriteCloser
+	Flush() error
+}
+
+// negotiateEncoding picks the encoding to respond with based on the
+// request's Accept-Encoding, preferring gzip. An empty string means the
+// response should not be compressed.
+func negotiateEncoding(r *http.Request) string {
+	accepted := map[string]bool{}
+
+	for _, coding := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		params := strings.Split(coding, ";")
+
+		name := strings.ToLower(strings.TrimSpace(params[0]))
+
+		refused := false
+		for _, param := range params[1:] {
+			param = strings.TrimSpace(param)
+			if !strings.HasPrefix(param, "q=") {
+				continue
+			}
+
+			q, err := strconv.ParseFloat(param[2:], 64)
+			if err == nil && q == 0 {
+				refused = true
+			}
+		}
+
+		if !refused {
+			accepted[name] = true
+		}
+	}
+
+	switch {
+	case accepted[EncodingGzip]:
+		return EncodingGzip
+	case accepted[EncodingDeflate]:
+		return EncodingDeflate
+	default:
+		return ""
+	}
+}
+
+// "deflate" in HTTP means zlib-wrapped deflate, not a raw deflate stream.
+func newCompressingWriter(encoding string, w io.Writer) compressingWriter {
+	if encoding == EncodingDeflate {
+		return zlib.NewWriter(w)
+	}
+
+	return gzip.NewWriter(w)
+}
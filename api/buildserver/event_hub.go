@@ -0,0 +1,295 @@
+package buildserver
+
+import (
+	"sync"
+
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/event"
+)
+
+// EventHub shares one read of a running build's events between everyone
+// watching it, rather than each of them polling the database on their own.
+//
+// The first subscriber to a running build starts a feed from wherever they
+// asked to start. Later subscribers read from the feed, first catching up
+// from the database if they asked for events from before it started. The feed
+// keeps the events it has read in memory until its last subscriber goes away.
+//
+// Streams to clients subscribe through SubscribeStream, which buffers a
+// running build's events for each of them so that one that can't keep up
+// skips log events rather than falling ever further behind.
+type EventHub struct {
+	lock  sync.Mutex
+	feeds map[int]*eventFeed
+
+	archive EventArchive
+
+	streamBufferSize int
+}
+
+//go:generate counterfeiter . EventArchive
+
+// EventArchive holds the events of builds that have been moved out of the
+// database.
+type EventArchive interface {
+	Events(buildID int, from uint) (db.EventSource, bool, error)
+}
+
+// NewEventHub returns a hub that falls back to the archive for builds whose
+// events are no longer in the database. The archive may be nil. Streams
+// buffer up to streamBufferSize events each; zero leaves them unbuffered, so
+// that they never skip anything.
+func NewEventHub(archive EventArchive, streamBufferSize int) *EventHub {
+	return &EventHub{
+		feeds: map[int]*eventFeed{},
+
+		archive: archive,
+
+		streamBufferSize: streamBufferSize,
+	}
+}
+
+// Subscribe returns the build's events from the given one on. Builds that are
+// no longer running, or a nil hub, read straight from the database, or from
+// the archive if they've been reaped from it.
+func (hub *EventHub) Subscribe(build db.Build, from uint) (db.EventSource, error) {
+	if hub == nil || !build.IsRunning() {
+		return hub.finishedBuildEvents(build, from)
+	}
+
+	hub.lock.Lock()
+
+	feed, found := hub.feeds[build.ID()]
+	if !found {
+		source, err := build.Events(from)
+		if err != nil {
+			hub.lock.Unlock()
+			return nil, err
+		}
+
+		feed = &eventFeed{
+			start:   from,
+			source:  source,
+			changed: make(chan struct{}),
+		}
+
+		hub.feeds[build.ID()] = feed
+
+		go feed.pump(func() { hub.remove(build.ID(), feed) })
+	}
+
+	feed.subscribers++
+
+	hub.lock.Unlock()
+
+	subscription := &hubSubscription{
+		feed:    feed,
+		release: func() { hub.release(build.ID(), feed) },
+		closed:  make(chan struct{}),
+	}
+
+	if from < feed.start {
+		catchUp, err := build.Events(from)
+		if err != nil {
+			subscription.Close()
+			return nil, err
+		}
+
+		subscription.catchUp = catchUp
+		subscription.catchUpLeft = feed.start - from
+	} else {
+		subscription.cursor = int(from - feed.start)
+	}
+
+	return subscription, nil
+}
+
+// SubscribeStream is like Subscribe, but for streaming a build's events to a
+// client as they happen. Running builds' events are buffered for the client,
+// skipping log events it can't keep up with; transport is what the skips are
+// counted under.
+func (hub *EventHub) SubscribeStream(build db.Build, from uint, transport string) (db.EventSource, error) {
+	source, err := hub.Subscribe(build, from)
+	if err != nil {
+		return nil, err
+	}
+
+	if hub == nil || hub.streamBufferSize == 0 || !build.IsRunning() {
+		return source, nil
+	}
+
+	return newBufferedEventSource(source, from, hub.streamBufferSize, transport), nil
+}
+
+func (hub *EventHub) finishedBuildEvents(build db.Build, from uint) (db.EventSource, error) {
+	if hub != nil && hub.archive != nil && !build.ReapTime().IsZero() {
+		source, found, err := hub.archive.Events(build.ID(), from)
+		if err != nil {
+			return nil, err
+		}
+
+		if found {
+			return source, nil
+		}
+	}
+
+	return build.Events(from)
+}
+
+func (hub *EventHub) remove(buildID int, feed *eventFeed) {
+	hub.lock.Lock()
+	defer hub.lock.Unlock()
+
+	if hub.feeds[buildID] == feed {
+		delete(hub.feeds, buildID)
+	}
+}
+
+func (hub *EventHub) release(buildID int, feed *eventFeed) {
+	hub.lock.Lock()
+
+	feed.subscribers--
+
+	last := feed.subscribers == 0
+	if last && hub.feeds[buildID] == feed {
+		delete(hub.feeds, buildID)
+	}
+
+	hub.lock.Unlock()
+
+	if last {
+		feed.source.Close()
+	}
+}
+
+type eventFeed struct {
+	start  uint
+	source db.EventSource
+
+	// guarded by the hub's lock
+	subscribers int
+
+	lock    sync.Mutex
+	events  []event.Envelope
+	err     error
+	changed chan struct{}
+}
+
+func (feed *eventFeed) pump(done func()) {
+	for {
+		ev, err := feed.source.Next()
+
+		feed.lock.Lock()
+
+		if err != nil {
+			feed.err = err
+		} else {
+			feed.events = append(feed.events, ev)
+		}
+
+		close(feed.changed)
+		feed.changed = make(chan struct{})
+
+		feed.lock.Unlock()
+
+		if err != nil {
+			done()
+			return
+		}
+	}
+}
+
+func (feed *eventFeed) next(cursor *int, closed <-chan struct{}) (event.Envelope, error) {
+	for {
+		feed.lock.Lock()
+
+		if *cursor < len(feed.events) {
+			ev := feed.events[*cursor]
+			feed.lock.Unlock()
+
+			*cursor++
+
+			return ev, nil
+		}
+
+		if feed.err != nil {
+			err := feed.err
+			feed.lock.Unlock()
+			return event.Envelope{}, err
+		}
+
+		changed := feed.changed
+
+		feed.lock.Unlock()
+
+		select {
+		case <-changed:
+		case <-closed:
+			return event.Envelope{}, db.ErrBuildEventStreamClosed
+		}
+	}
+}
+
+type hubSubscription struct {
+	feed    *eventFeed
+	release func()
+	cursor  int
+
+	catchUpLock sync.Mutex
+	catchUp     db.EventSource
+	catchUpLeft uint
+
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func (subscription *hubSubscription) Next() (event.Envelope, error) {
+	select {
+	case <-subscription.closed:
+		return event.Envelope{}, db.ErrBuildEventStreamClosed
+	default:
+	}
+
+	subscription.catchUpLock.Lock()
+	catchUp := subscription.catchUp
+	subscription.catchUpLock.Unlock()
+
+	if catchUp != nil {
+		ev, err := catchUp.Next()
+		if err != nil {
+			return event.Envelope{}, err
+		}
+
+		subscription.catchUpLeft--
+
+		if subscription.catchUpLeft == 0 {
+			subscription.closeCatchUp()
+		}
+
+		return ev, nil
+	}
+
+	return subscription.feed.next(&subscription.cursor, subscription.closed)
+}
+
+func (subscription *hubSubscription) Close() error {
+	subscription.closeOnce.Do(func() {
+		close(subscription.closed)
+
+		subscription.closeCatchUp()
+
+		subscription.release()
+	})
+
+	return nil
+}
+
+func (subscription *hubSubscription) closeCatchUp() {
+	subscription.catchUpLock.Lock()
+	defer subscription.catchUpLock.Unlock()
+
+	if subscription.catchUp != nil {
+		subscription.catchUp.Close()
+		subscription.catchUp = nil
+	}
+}
@@ -0,0 +1,388 @@
+package buildserver_test
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	. "github.com/concourse/atc/api/buildserver"
+	"github.com/concourse/atc/api/buildserver/buildserverfakes"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/db/dbfakes"
+	"github.com/concourse/atc/event"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type pushedEventSource struct {
+	*dbfakes.FakeEventSource
+
+	events chan event.Envelope
+	closed chan struct{}
+}
+
+func newPushedEventSource() *pushedEventSource {
+	source := &pushedEventSource{
+		FakeEventSource: new(dbfakes.FakeEventSource),
+
+		events: make(chan event.Envelope, 100),
+		closed: make(chan struct{}),
+	}
+
+	source.NextStub = func() (event.Envelope, error) {
+		select {
+		case ev, ok := <-source.events:
+			if !ok {
+				return event.Envelope{}, db.ErrEndOfBuildEventStream
+			}
+
+			return ev, nil
+		case <-source.closed:
+			return event.Envelope{}, db.ErrBuildEventStreamClosed
+		}
+	}
+
+	var closeOnce sync.Once
+	source.CloseStub = func() error {
+		closeOnce.Do(func() { close(source.closed) })
+		return nil
+	}
+
+	return source
+}
+
+var _ = Describe("EventHub", func() {
+	var (
+		hub         *EventHub
+		fakeArchive *buildserverfakes.FakeEventArchive
+		build       *dbfakes.FakeBuild
+
+		sources []*pushedEventSource
+	)
+
+	BeforeEach(func() {
+		fakeArchive = new(buildserverfakes.FakeEventArchive)
+		hub = NewEventHub(fakeArchive, 0)
+
+		build = new(dbfakes.FakeBuild)
+		build.IDReturns(42)
+		build.IsRunningReturns(true)
+
+		sources = nil
+
+		var sourcesLock sync.Mutex
+		build.EventsStub = func(uint) (db.EventSource, error) {
+			sourcesLock.Lock()
+			defer sourcesLock.Unlock()
+
+			source := newPushedEventSource()
+			sources = append(sources, source)
+			return source, nil
+		}
+	})
+
+	next := func(source db.EventSource) event.Envelope {
+		ev, err := source.Next()
+		Expect(err).NotTo(HaveOccurred())
+		return ev
+	}
+
+	It("reads a running build's events once for every subscriber", func() {
+		first, err := hub.Subscribe(build, 0)
+		Expect(err).NotTo(HaveOccurred())
+
+		second, err := hub.Subscribe(build, 0)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(build.EventsCallCount()).To(Equal(1))
+		Expect(build.EventsArgsForCall(0)).To(BeZero())
+
+		sources[0].events <- fakeEvent(`{"event":1}`)
+		sources[0].events <- fakeEvent(`{"event":2}`)
+		close(sources[0].events)
+
+		for _, subscription := range []db.EventSource{first, second} {
+			Expect(next(subscription)).To(Equal(fakeEvent(`{"event":1}`)))
+			Expect(next(subscription)).To(Equal(fakeEvent(`{"event":2}`)))
+
+			_, err := subscription.Next()
+			Expect(err).To(Equal(db.ErrEndOfBuildEventStream))
+		}
+	})
+
+	It("catches up from the database for events from before the feed started", func() {
+		first, err := hub.Subscribe(build, 2)
+		Expect(err).NotTo(HaveOccurred())
+
+		sources[0].events <- fakeEvent(`{"event":3}`)
+		Expect(next(first)).To(Equal(fakeEvent(`{"event":3}`)))
+
+		late, err := hub.Subscribe(build, 0)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(build.EventsCallCount()).To(Equal(2))
+		Expect(build.EventsArgsForCall(1)).To(BeZero())
+
+		sources[1].events <- fakeEvent(`{"event":1}`)
+		sources[1].events <- fakeEvent(`{"event":2}`)
+
+		Expect(next(late)).To(Equal(fakeEvent(`{"event":1}`)))
+		Expect(next(late)).To(Equal(fakeEvent(`{"event":2}`)))
+
+		By("closing the catch-up read once it reaches the feed")
+		Eventually(sources[1].CloseCallCount).Should(Equal(1))
+
+		Expect(next(late)).To(Equal(fakeEvent(`{"event":3}`)))
+	})
+
+	It("skips ahead for subscribers starting after the feed", func() {
+		_, err := hub.Subscribe(build, 0)
+		Expect(err).NotTo(HaveOccurred())
+
+		ahead, err := hub.Subscribe(build, 1)
+		Expect(err).NotTo(HaveOccurred())
+
+		sources[0].events <- fakeEvent(`{"event":1}`)
+		sources[0].events <- fakeEvent(`{"event":2}`)
+
+		Expect(next(ahead)).To(Equal(fakeEvent(`{"event":2}`)))
+		Expect(build.EventsCallCount()).To(Equal(1))
+	})
+
+	It("stops reading once the last subscriber closes", func() {
+		first, err := hub.Subscribe(build, 0)
+		Expect(err).NotTo(HaveOccurred())
+
+		second, err := hub.Subscribe(build, 0)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(first.Close()).To(Succeed())
+		Expect(sources[0].CloseCallCount()).To(BeZero())
+
+		_, err = first.Next()
+		Expect(err).To(Equal(db.ErrBuildEventStreamClosed))
+
+		Expect(second.Close()).To(Succeed())
+		Expect(sources[0].CloseCallCount()).To(Equal(1))
+
+		By("starting a new feed for the next subscriber")
+		_, err = hub.Subscribe(build, 0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(build.EventsCallCount()).To(Equal(2))
+	})
+
+	It("unblocks subscribers waiting for events when they close", func() {
+		subscription, err := hub.Subscribe(build, 0)
+		Expect(err).NotTo(HaveOccurred())
+
+		errs := make(chan error)
+		go func() {
+			_, err := subscription.Next()
+			errs <- err
+		}()
+
+		Consistently(errs).ShouldNot(Receive())
+
+		Expect(subscription.Close()).To(Succeed())
+		Eventually(errs).Should(Receive(Equal(db.ErrBuildEventStreamClosed)))
+	})
+
+	Context("when the build is not running", func() {
+		BeforeEach(func() {
+			build.IsRunningReturns(false)
+		})
+
+		It("reads straight from the database", func() {
+			source, err := hub.Subscribe(build, 3)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(build.EventsCallCount()).To(Equal(1))
+			Expect(build.EventsArgsForCall(0)).To(Equal(uint(3)))
+			Expect(source).To(Equal(sources[0]))
+		})
+
+		It("does not look in the archive", func() {
+			_, err := hub.Subscribe(build, 3)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeArchive.EventsCallCount()).To(BeZero())
+		})
+
+		Context("when its events have been reaped", func() {
+			BeforeEach(func() {
+				build.ReapTimeReturns(time.Unix(123, 0))
+			})
+
+			Context("and archived", func() {
+				var archived *dbfakes.FakeEventSource
+
+				BeforeEach(func() {
+					archived = new(dbfakes.FakeEventSource)
+					fakeArchive.EventsReturns(archived, true, nil)
+				})
+
+				It("reads from the archive", func() {
+					source, err := hub.Subscribe(build, 3)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeArchive.EventsCallCount()).To(Equal(1))
+					buildID, from := fakeArchive.EventsArgsForCall(0)
+					Expect(buildID).To(Equal(42))
+					Expect(from).To(Equal(uint(3)))
+
+					Expect(source).To(Equal(archived))
+					Expect(build.EventsCallCount()).To(BeZero())
+				})
+			})
+
+			Context("but not archived", func() {
+				BeforeEach(func() {
+					fakeArchive.EventsReturns(nil, false, nil)
+				})
+
+				It("reads from the database", func() {
+					source, err := hub.Subscribe(build, 3)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(source).To(Equal(sources[0]))
+				})
+			})
+
+			Context("when reading the archive fails", func() {
+				var disaster error
+
+				BeforeEach(func() {
+					disaster = errors.New("nope")
+					fakeArchive.EventsReturns(nil, false, disaster)
+				})
+
+				It("returns the error", func() {
+					_, err := hub.Subscribe(build, 3)
+					Expect(err).To(Equal(disaster))
+				})
+			})
+		})
+	})
+
+	Context("without a hub", func() {
+		BeforeEach(func() {
+			hub = nil
+		})
+
+		It("reads straight from the database", func() {
+			source, err := hub.Subscribe(build, 3)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(source).To(Equal(sources[0]))
+		})
+
+		It("streams straight from the database", func() {
+			source, err := hub.SubscribeStream(build, 3, "sse")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(source).To(Equal(sources[0]))
+		})
+	})
+
+	Describe("SubscribeStream", func() {
+		logEvent := func(payload string) event.Envelope {
+			data, err := json.Marshal(event.Log{Payload: payload})
+			Expect(err).NotTo(HaveOccurred())
+
+			return event.Envelope{
+				Data:    (*json.RawMessage)(&data),
+				Event:   event.EventTypeLog,
+				Version: event.Log{}.Version(),
+				Time:    time.Unix(int64(len(payload)), 0),
+			}
+		}
+
+		withID := func(ev event.Envelope, id uint) event.Envelope {
+			ev.ID = &id
+			return ev
+		}
+
+		skipped := func(message string, id uint, at time.Time) event.Envelope {
+			data, err := json.Marshal(event.Warning{Message: message, Time: at.Unix()})
+			Expect(err).NotTo(HaveOccurred())
+
+			return withID(event.Envelope{
+				Data:    (*json.RawMessage)(&data),
+				Event:   event.EventTypeWarning,
+				Version: event.Warning{}.Version(),
+				Time:    at,
+			}, id)
+		}
+
+		BeforeEach(func() {
+			hub = NewEventHub(fakeArchive, 2)
+		})
+
+		It("skips log events the subscriber falls behind on, saying how many", func() {
+			stream, err := hub.SubscribeStream(build, 3, "sse")
+			Expect(err).NotTo(HaveOccurred())
+
+			sources[0].events <- logEvent("a")
+			sources[0].events <- logEvent("bb")
+			sources[0].events <- logEvent("ccc")
+			sources[0].events <- logEvent("dddd")
+			sources[0].events <- fakeEvent(`{"event":"status"}`)
+			sources[0].events <- logEvent("eeeee")
+			close(sources[0].events)
+
+			By("letting go of the feed once it has everything")
+			Eventually(sources[0].CloseCallCount).Should(Equal(1))
+
+			Expect(next(stream)).To(Equal(withID(logEvent("a"), 3)))
+			Expect(next(stream)).To(Equal(withID(logEvent("bb"), 4)))
+			Expect(next(stream)).To(Equal(skipped("skipped 2 log events", 6, time.Unix(4, 0))))
+			Expect(next(stream)).To(Equal(withID(fakeEvent(`{"event":"status"}`), 7)))
+			Expect(next(stream)).To(Equal(skipped("skipped 1 log event", 8, time.Unix(5, 0))))
+
+			_, err = stream.Next()
+			Expect(err).To(Equal(db.ErrEndOfBuildEventStream))
+		})
+
+		It("does not hold up other subscribers", func() {
+			_, err := hub.SubscribeStream(build, 0, "sse")
+			Expect(err).NotTo(HaveOccurred())
+
+			other, err := hub.Subscribe(build, 0)
+			Expect(err).NotTo(HaveOccurred())
+
+			for i := 0; i < 10; i++ {
+				sources[0].events <- logEvent("a")
+			}
+
+			for i := 0; i < 10; i++ {
+				Expect(next(other)).To(Equal(logEvent("a")))
+			}
+		})
+
+		It("closes the feed when the subscriber closes", func() {
+			stream, err := hub.SubscribeStream(build, 0, "sse")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(stream.Close()).To(Succeed())
+			Expect(sources[0].CloseCallCount()).To(Equal(1))
+
+			_, err = stream.Next()
+			Expect(err).To(Equal(db.ErrBuildEventStreamClosed))
+		})
+
+		Context("when the build is not running", func() {
+			BeforeEach(func() {
+				build.IsRunningReturns(false)
+			})
+
+			It("reads straight from the database", func() {
+				source, err := hub.SubscribeStream(build, 3, "sse")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(source).To(Equal(sources[0]))
+			})
+		})
+	})
+})
@@ -1,22 +1,50 @@
 package buildserver
 
 import (
-	"compress/gzip"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/auth"
 	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/event"
+	"github.com/concourse/atc/metric"
 	"github.com/vito/go-sse/sse"
 )
 
 const ProtocolVersionHeader = "X-ATC-Stream-Version"
 const CurrentProtocolVersion = "2.0"
 
+// FromQueryParam may be given instead of the Last-Event-ID header, for
+// clients that cannot set headers. Unlike Last-Event-ID it names the first
+// event to send, not the last one received.
+const FromQueryParam = "from"
+
+// TypesQueryParam limits the stream to a comma-separated list of event types,
+// e.g. ?types=log,error.
+const TypesQueryParam = "types"
+
 func NewEventHandler(logger lager.Logger, build db.Build) http.Handler {
+	return newEventHandler(logger, build, CensorPolicies{}, nil)
+}
+
+// NewCensoringEventHandlerFactory returns handlers that stream events through
+// the given hub, which may be nil to have every stream read the database.
+func NewCensoringEventHandlerFactory(policies CensorPolicies, hub *EventHub) EventHandlerFactory {
+	return func(logger lager.Logger, build db.Build) http.Handler {
+		return newEventHandler(logger, build, policies, hub)
+	}
+}
+
+func newEventHandler(logger lager.Logger, build db.Build, policies CensorPolicies, hub *EventHub) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		var start uint = 0
 		if r.Header.Get("Last-Event-ID") != "" {
@@ -29,11 +57,58 @@ func NewEventHandler(logger lager.Logger, build db.Build) http.Handler {
 			}
 
 			start++
+		} else if r.FormValue(FromQueryParam) != "" {
+			fromString := r.FormValue(FromQueryParam)
+			_, err := fmt.Sscanf(fromString, "%d", &start)
+			if err != nil {
+				logger.Info("failed-to-parse-from", lager.Data{"from": fromString})
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+		}
+
+		schema, ok := negotiateSchema(r)
+		if !ok {
+			logger.Info("unknown-event-schema", lager.Data{"accept": r.Header.Get("Accept")})
+			w.WriteHeader(http.StatusNotAcceptable)
+			return
+		}
+
+		authTeam, authTeamFound := auth.GetTeam(r)
+
+		filter := eventFilter{
+			censor: policies.RuleFor(build, authTeamFound && authTeam.IsAuthorized(build.TeamName())),
+			schema: schema,
+		}
+
+		if r.FormValue(TypesQueryParam) != "" {
+			filter.types = map[atc.EventType]bool{}
+
+			for _, eventType := range strings.Split(r.FormValue(TypesQueryParam), ",") {
+				filter.types[atc.EventType(strings.TrimSpace(eventType))] = true
+			}
+		}
+
+		var pacer *replayPacer
+		switch r.FormValue(ReplayQueryParam) {
+		case "":
+		case ReplayRealtime:
+			pacer = &replayPacer{}
+		default:
+			logger.Info("unknown-replay-mode", lager.Data{"replay": r.FormValue(ReplayQueryParam)})
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if isWebSocketRequest(r) {
+			serveWebSocketEvents(logger, hub, build, start, filter, pacer, w, r)
+			return
 		}
 
 		w.Header().Add("Content-Type", "text/event-stream; charset=utf-8")
 		w.Header().Add("Cache-Control", "no-cache, no-store, must-revalidate")
 		w.Header().Add(ProtocolVersionHeader, CurrentProtocolVersion)
+		w.Header().Add(EventSchemaHeader, strconv.Itoa(int(schema)))
 
 		writer := eventWriter{
 			responseWriter:  w,
@@ -42,24 +117,115 @@ func NewEventHandler(logger lager.Logger, build db.Build) http.Handler {
 		}
 
 		w.Header().Add("Vary", "Accept-Encoding")
-		if strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
-			w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Add("Vary", "Accept")
+		if encoding := negotiateEncoding(r); encoding != "" {
+			compressor := newCompressingWriter(encoding, w)
+
+			defer compressor.Close()
 
-			gz := gzip.NewWriter(w)
-			defer gz.Close()
+			w.Header().Set("Content-Encoding", encoding)
 
-			writer.responseWriter = gz
-			writer.writeFlusher = gz
+			writer.responseWriter = compressor
+			writer.writeFlusher = compressor
 		}
 
-		events, err := build.Events(start)
+		subscribeStart := time.Now()
+
+		events, err := subscribeStream(hub, build, start, pacer, "sse")
 		if err != nil {
 			logger.Error("failed-to-get-build-events", err, lager.Data{"build-id": build.ID(), "start": start})
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.DBFailure(w, "failed to get build events")
 			return
 		}
 
-		defer events.Close()
+		metric.BuildEventsLatency.Observe(time.Since(subscribeStart))
+
+		metric.ActiveEventStreams.Inc()
+		defer metric.ActiveEventStreams.Dec()
+
+		var closeOnce sync.Once
+		closeEvents := func() {
+			closeOnce.Do(func() {
+				events.Close()
+			})
+		}
+
+		defer closeEvents()
+
+		// only touched by the supervisor
+		reaped := false
+		reap := func() {
+			reaped = true
+			metric.ReapedEventStreams.Inc("sse")
+			closeEvents()
+
+			// closing the events does nothing for a write that's stuck on the
+			// client, which holds the write lock; make it give up, along with
+			// any that follow, so the stream can finish
+			err := http.NewResponseController(w).SetWriteDeadline(time.Now())
+			if err != nil {
+				logger.Info("failed-to-expire-writes", lager.Data{"error": err.Error()})
+			}
+		}
+
+		keepAliveTimeout := keepAliveIntervalFrom(r)
+
+		var writeLock sync.Mutex
+		finished := false
+		stopSupervising := make(chan struct{})
+
+		defer func() {
+			writeLock.Lock()
+			finished = true
+			writeLock.Unlock()
+
+			close(stopSupervising)
+		}()
+
+		go superviseStream(r, closeEvents, func() {
+			writeLock.Lock()
+			defer writeLock.Unlock()
+
+			if finished {
+				return
+			}
+
+			err := writer.WriteDrain()
+			if err != nil {
+				logger.Info("failed-to-write-drain", lager.Data{"error": err.Error()})
+			}
+		}, func() {
+			if reaped {
+				return
+			}
+
+			// a dead client isn't noticed until writing to it fails, which can
+			// take much longer than this, so don't wait on the write
+			written := make(chan error, 1)
+			go func() {
+				writeLock.Lock()
+				defer writeLock.Unlock()
+
+				if finished {
+					written <- nil
+					return
+				}
+
+				written <- writer.WriteKeepAlive()
+			}()
+
+			select {
+			case err := <-written:
+				if err != nil {
+					logger.Info("failed-to-write-keepalive", lager.Data{"error": err.Error()})
+					reap()
+				}
+
+			case <-time.After(keepAliveTimeout):
+				logger.Info("client-unresponsive", lager.Data{"timeout": keepAliveTimeout.String()})
+				reap()
+			}
+		}, stopSupervising)
 
 		for {
 			logger = logger.WithData(lager.Data{"id": start})
@@ -67,12 +233,14 @@ func NewEventHandler(logger lager.Logger, build db.Build) http.Handler {
 			ev, err := events.Next()
 			if err != nil {
 				if err == db.ErrEndOfBuildEventStream {
+					writeLock.Lock()
 					err := writer.WriteEnd()
+					writeLock.Unlock()
 					if err != nil {
 						logger.Info("failed-to-write-end", lager.Data{"error": err.Error()})
 						return
 					}
-				} else {
+				} else if err != db.ErrBuildEventStreamClosed {
 					logger.Error("failed-to-get-next-build-event", err)
 					return
 				}
@@ -80,7 +248,33 @@ func NewEventHandler(logger lager.Logger, build db.Build) http.Handler {
 				return
 			}
 
+			// buffered streams skip log events the client couldn't keep up
+			// with, standing in for them with a single warning
+			if ev.ID != nil {
+				start = *ev.ID
+			}
+
+			if !pacer.Wait(r.Context(), ev) {
+				return
+			}
+
+			ev, send, err := filter.Filter(ev)
+			if err != nil {
+				logger.Error("failed-to-filter-event", err)
+				return
+			}
+
+			if !send {
+				start++
+				continue
+			}
+
+			id := start
+			ev.ID = &id
+
+			writeLock.Lock()
 			err = writer.WriteEvent(start, ev)
+			writeLock.Unlock()
 			if err != nil {
 				logger.Info("failed-to-write-event", lager.Data{"error": err.Error()})
 				return
@@ -91,6 +285,43 @@ func NewEventHandler(logger lager.Logger, build db.Build) http.Handler {
 	})
 }
 
+// subscribeStream subscribes the stream to the build's events. Replays hold
+// themselves back on purpose, so they aren't buffered; they'd only skip what
+// they were holding back.
+func subscribeStream(hub *EventHub, build db.Build, start uint, pacer *replayPacer, transport string) (db.EventSource, error) {
+	if pacer != nil {
+		return hub.Subscribe(build, start)
+	}
+
+	return hub.SubscribeStream(build, start, transport)
+}
+
+type eventFilter struct {
+	censor CensorRule
+	types  map[atc.EventType]bool
+
+	// schema is the version to downgrade events to; zero leaves them as they
+	// are
+	schema event.SchemaVersion
+}
+
+func (filter eventFilter) Filter(ev event.Envelope) (event.Envelope, bool, error) {
+	if filter.types != nil && !filter.types[ev.Event] {
+		return event.Envelope{}, false, nil
+	}
+
+	ev, send, err := filter.censor.Censor(ev)
+	if err != nil || !send {
+		return ev, send, err
+	}
+
+	if filter.schema == 0 {
+		return ev, true, nil
+	}
+
+	return event.Downgrade(ev, filter.schema)
+}
+
 type flusher interface {
 	Flush() error
 }
@@ -128,6 +359,28 @@ func (writer eventWriter) WriteEnd() error {
 	return writer.flush()
 }
 
+// WriteDrain tells the client that this ATC is going away, so that it can
+// reconnect (with Last-Event-ID) to another one before being cut off.
+func (writer eventWriter) WriteDrain() error {
+	err := sse.Event{Name: "drain"}.Write(writer.responseWriter)
+	if err != nil {
+		return err
+	}
+
+	return writer.flush()
+}
+
+// WriteKeepAlive writes a comment, which clients ignore, to keep an idle
+// stream's connection open.
+func (writer eventWriter) WriteKeepAlive() error {
+	_, err := io.WriteString(writer.responseWriter, ": ping\n\n")
+	if err != nil {
+		return err
+	}
+
+	return writer.flush()
+}
+
 func (writer eventWriter) flush() error {
 	if writer.writeFlusher != nil {
 		err := writer.writeFlusher.Flush()
@@ -1,11 +1,15 @@
 package buildserver_test
 
 import (
+	"compress/gzip"
+	"compress/zlib"
 	"encoding/json"
 	"errors"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"time"
 
 	"code.cloudfoundry.org/lager/lagertest"
@@ -13,6 +17,7 @@ import (
 	"github.com/concourse/atc/db"
 	"github.com/concourse/atc/db/dbfakes"
 	"github.com/concourse/atc/event"
+	"github.com/gorilla/websocket"
 	"github.com/vito/go-sse/sse"
 
 	. "github.com/onsi/ginkgo"
@@ -120,25 +125,169 @@ var _ = Describe("Handler", func() {
 				Expect(response.Header.Get("X-ATC-Stream-Version")).To(Equal("2.0"))
 			})
 
+			It("returns the event schema version as X-ATC-Event-Schema", func() {
+				Expect(response.Header.Get("X-ATC-Event-Schema")).To(Equal("4"))
+			})
+
+			Context("when the request accepts an older event schema", func() {
+				BeforeEach(func() {
+					request.Header.Set("Accept", "text/event-stream; schema=1")
+
+					truncated := json.RawMessage(`{"origin":{"id":"some-id"},"max_bytes":1024}`)
+
+					returnedEvents = []event.Envelope{
+						fakeEvent(`{"event":1}`),
+						{
+							Data:    &truncated,
+							Event:   event.EventTypeLogTruncated,
+							Version: "1.0",
+						},
+					}
+				})
+
+				It("returns the older version as X-ATC-Event-Schema", func() {
+					Expect(response.Header.Get("X-ATC-Event-Schema")).To(Equal("1"))
+				})
+
+				It("downgrades events the schema doesn't have", func() {
+					reader := sse.NewReadCloser(response.Body)
+
+					Expect(reader.Next()).To(Equal(sse.Event{
+						ID:   "0",
+						Name: "event",
+						Data: []byte(`{"data":{"event":1},"event":"fake","version":"42.0"},"id":0}`),
+					}))
+
+					Expect(reader.Next()).To(Equal(sse.Event{
+						ID:   "1",
+						Name: "event",
+						Data: []byte(`{"data":{"origin":{"id":"some-id"},"payload":"\nlog truncated after 1024 bytes\n"},"event":"log","version":"5.0"},"id":1}`),
+					}))
+
+					Expect(reader.Next()).To(Equal(sse.Event{
+						Name: "end",
+						Data: []byte{},
+					}))
+				})
+			})
+
+			Context("when the events were saved with a time", func() {
+				BeforeEach(func() {
+					timed := fakeEvent(`{"event":1}`)
+					timed.Time = time.Date(2016, 1, 2, 3, 4, 5, 6000000, time.FixedZone("EST", -5*60*60))
+
+					returnedEvents = []event.Envelope{timed, fakeEvent(`{"event":2}`)}
+				})
+
+				It("sends the time along with those that have one", func() {
+					reader := sse.NewReadCloser(response.Body)
+
+					Expect(reader.Next()).To(Equal(sse.Event{
+						ID:   "0",
+						Name: "event",
+						Data: []byte(`{"data":{"event":1},"event":"fake","version":"42.0","time":"2016-01-02T08:04:05.006Z"},"id":0}`),
+					}))
+
+					Expect(reader.Next()).To(Equal(sse.Event{
+						ID:   "1",
+						Name: "event",
+						Data: []byte(`{"data":{"event":2},"event":"fake","version":"42.0"},"id":1}`),
+					}))
+				})
+			})
+
+			Context("when the request accepts the event schema from before approvals", func() {
+				BeforeEach(func() {
+					request.Header.Set("Accept", "text/event-stream; schema=2")
+
+					decided := json.RawMessage(`{"origin":{"id":"some-id"},"name":"ship-it","approved":true,"approver":"some-user","time":1}`)
+
+					returnedEvents = []event.Envelope{
+						{
+							Data:    &decided,
+							Event:   event.EventTypeApprovalDecided,
+							Version: "1.0",
+						},
+					}
+				})
+
+				It("downgrades approval events to logs", func() {
+					reader := sse.NewReadCloser(response.Body)
+
+					Expect(reader.Next()).To(Equal(sse.Event{
+						ID:   "0",
+						Name: "event",
+						Data: []byte(`{"data":{"origin":{"id":"some-id"},"payload":"ship-it approved by some-user\n"},"event":"log","version":"5.0"},"id":0}`),
+					}))
+
+					Expect(reader.Next()).To(Equal(sse.Event{
+						Name: "end",
+						Data: []byte{},
+					}))
+				})
+			})
+
+			Context("when the request accepts the event schema from before warnings", func() {
+				BeforeEach(func() {
+					request.Header.Set("Accept", "text/event-stream; schema=3")
+
+					warning := json.RawMessage(`{"message":"build has been running for 40m0s","time":1}`)
+
+					returnedEvents = []event.Envelope{
+						{
+							Data:    &warning,
+							Event:   event.EventTypeWarning,
+							Version: "1.0",
+						},
+					}
+				})
+
+				It("downgrades warning events to logs", func() {
+					reader := sse.NewReadCloser(response.Body)
+
+					Expect(reader.Next()).To(Equal(sse.Event{
+						ID:   "0",
+						Name: "event",
+						Data: []byte(`{"data":{"origin":{},"payload":"warning: build has been running for 40m0s\n"},"event":"log","version":"5.0"},"id":0}`),
+					}))
+
+					Expect(reader.Next()).To(Equal(sse.Event{
+						Name: "end",
+						Data: []byte{},
+					}))
+				})
+			})
+
+			Context("when the request accepts an event schema newer than the current one", func() {
+				BeforeEach(func() {
+					request.Header.Set("Accept", "text/event-stream; schema=99")
+				})
+
+				It("streams in the current one", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+					Expect(response.Header.Get("X-ATC-Event-Schema")).To(Equal("4"))
+				})
+			})
+
 			It("emits them, followed by an end event", func() {
 				reader := sse.NewReadCloser(response.Body)
 
 				Expect(reader.Next()).To(Equal(sse.Event{
 					ID:   "0",
 					Name: "event",
-					Data: []byte(`{"data":{"event":1},"event":"fake","version":"42.0"}`),
+					Data: []byte(`{"data":{"event":1},"event":"fake","version":"42.0"},"id":0}`),
 				}))
 
 				Expect(reader.Next()).To(Equal(sse.Event{
 					ID:   "1",
 					Name: "event",
-					Data: []byte(`{"data":{"event":2},"event":"fake","version":"42.0"}`),
+					Data: []byte(`{"data":{"event":2},"event":"fake","version":"42.0"},"id":1}`),
 				}))
 
 				Expect(reader.Next()).To(Equal(sse.Event{
 					ID:   "2",
 					Name: "event",
-					Data: []byte(`{"data":{"event":3},"event":"fake","version":"42.0"}`),
+					Data: []byte(`{"data":{"event":3},"event":"fake","version":"42.0"},"id":2}`),
 				}))
 
 				Expect(reader.Next()).To(Equal(sse.Event{
@@ -162,6 +311,247 @@ var _ = Describe("Handler", func() {
 					Expect(actualFrom).To(Equal(uint(2)))
 				})
 			})
+
+			Context("when the from query parameter is given", func() {
+				BeforeEach(func() {
+					request.URL.RawQuery = "from=1"
+				})
+
+				It("starts subscribing from the given event", func() {
+					Eventually(build.EventsCallCount).Should(Equal(1))
+					actualFrom := build.EventsArgsForCall(0)
+					Expect(actualFrom).To(Equal(uint(1)))
+				})
+
+				Context("and the Last-Event-ID header is given too", func() {
+					BeforeEach(func() {
+						request.Header.Set("Last-Event-ID", "1")
+					})
+
+					It("prefers the header", func() {
+						Eventually(build.EventsCallCount).Should(Equal(1))
+						actualFrom := build.EventsArgsForCall(0)
+						Expect(actualFrom).To(Equal(uint(2)))
+					})
+				})
+			})
+
+			Context("when the types query parameter is given", func() {
+				BeforeEach(func() {
+					returnedEvents = []event.Envelope{
+						fakeTypedEvent("status", `{"status":"started"}`),
+						fakeTypedEvent("log", `{"payload":"hello"}`),
+						fakeTypedEvent("initialize-task", `{}`),
+						fakeTypedEvent("error", `{"message":"oh no"}`),
+					}
+
+					request.URL.RawQuery = "types=log,error"
+				})
+
+				It("only emits events of the given types, keeping their ids", func() {
+					reader := sse.NewReadCloser(response.Body)
+
+					Expect(reader.Next()).To(Equal(sse.Event{
+						ID:   "1",
+						Name: "event",
+						Data: []byte(`{"data":{"payload":"hello"},"event":"log","version":"42.0"},"id":1}`),
+					}))
+
+					Expect(reader.Next()).To(Equal(sse.Event{
+						ID:   "3",
+						Name: "event",
+						Data: []byte(`{"data":{"message":"oh no"},"event":"error","version":"42.0"},"id":3}`),
+					}))
+
+					Expect(reader.Next()).To(Equal(sse.Event{
+						Name: "end",
+						Data: []byte{},
+					}))
+				})
+			})
+
+			Context("when replaying in realtime", func() {
+				BeforeEach(func() {
+					start := time.Now()
+
+					returnedEvents = []event.Envelope{
+						fakeEvent(`{"event":1}`),
+						fakeEvent(`{"event":2}`),
+						fakeEvent(`{"event":3}`),
+						fakeEvent(`{"event":4}`),
+					}
+
+					returnedEvents[0].Time = start
+					returnedEvents[1].Time = start.Add(500 * time.Millisecond)
+
+					// saved before times were recorded
+					returnedEvents[2].Time = time.Time{}
+
+					returnedEvents[3].Time = start.Add(time.Second)
+
+					request.URL.RawQuery = "replay=realtime"
+				})
+
+				It("waits between events as long as they originally took", func() {
+					reader := sse.NewReadCloser(response.Body)
+
+					_, err := reader.Next()
+					Expect(err).NotTo(HaveOccurred())
+
+					before := time.Now()
+
+					_, err = reader.Next()
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(time.Since(before)).To(BeNumerically(">=", 400*time.Millisecond))
+
+					before = time.Now()
+
+					_, err = reader.Next()
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(time.Since(before)).To(BeNumerically("<", 400*time.Millisecond))
+
+					ev, err := reader.Next()
+					Expect(err).NotTo(HaveOccurred())
+					Expect(ev.ID).To(Equal("3"))
+
+					Expect(time.Since(before)).To(BeNumerically(">=", 400*time.Millisecond))
+				})
+			})
+
+			Context("when the request accepts gzip", func() {
+				BeforeEach(func() {
+					request.Header.Set("Accept-Encoding", "deflate, gzip")
+				})
+
+				It("compresses the stream with gzip", func() {
+					Expect(response.Header.Get("Content-Encoding")).To(Equal("gzip"))
+					Expect(response.Header.Get("Vary")).To(Equal("Accept-Encoding"))
+
+					gz, err := gzip.NewReader(response.Body)
+					Expect(err).NotTo(HaveOccurred())
+
+					reader := sse.NewReadCloser(ioutil.NopCloser(gz))
+
+					Expect(reader.Next()).To(Equal(sse.Event{
+						ID:   "0",
+						Name: "event",
+						Data: []byte(`{"data":{"event":1},"event":"fake","version":"42.0"},"id":0}`),
+					}))
+				})
+			})
+
+			Context("when the request only accepts deflate", func() {
+				BeforeEach(func() {
+					request.Header.Set("Accept-Encoding", "gzip;q=0, deflate")
+				})
+
+				It("compresses the stream with deflate", func() {
+					Expect(response.Header.Get("Content-Encoding")).To(Equal("deflate"))
+
+					zr, err := zlib.NewReader(response.Body)
+					Expect(err).NotTo(HaveOccurred())
+
+					reader := sse.NewReadCloser(zr)
+
+					Expect(reader.Next()).To(Equal(sse.Event{
+						ID:   "0",
+						Name: "event",
+						Data: []byte(`{"data":{"event":1},"event":"fake","version":"42.0"},"id":0}`),
+					}))
+				})
+			})
+		})
+
+		Context("when streaming over a websocket", func() {
+			var fakeEventSource *dbfakes.FakeEventSource
+			var conn *websocket.Conn
+			var response *http.Response
+
+			BeforeEach(func() {
+				returnedEvents := []event.Envelope{
+					fakeEvent(`{"event":1}`),
+					fakeEvent(`{"event":2}`),
+				}
+
+				fakeEventSource = new(dbfakes.FakeEventSource)
+
+				build.EventsStub = func(from uint) (db.EventSource, error) {
+					fakeEventSource.NextStub = func() (event.Envelope, error) {
+						if from >= uint(len(returnedEvents)) {
+							return event.Envelope{}, db.ErrEndOfBuildEventStream
+						}
+
+						from++
+
+						return returnedEvents[from-1], nil
+					}
+
+					return fakeEventSource, nil
+				}
+			})
+
+			JustBeforeEach(func() {
+				wsURL, err := url.Parse(server.URL)
+				Expect(err).NotTo(HaveOccurred())
+
+				wsURL.Scheme = "ws"
+
+				dialer := websocket.Dialer{}
+				conn, response, err = dialer.Dial(wsURL.String(), request.Header)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			AfterEach(func() {
+				conn.Close()
+				Eventually(fakeEventSource.CloseCallCount, 30*time.Second).Should(Equal(1))
+			})
+
+			It("returns the protocol version as X-ATC-Stream-Version", func() {
+				Expect(response.Header.Get("X-ATC-Stream-Version")).To(Equal("2.0"))
+			})
+
+			It("emits the event envelopes, followed by an end message", func() {
+				var msg map[string]interface{}
+
+				Expect(conn.ReadJSON(&msg)).To(Succeed())
+				Expect(msg).To(Equal(map[string]interface{}{
+					"id":   float64(0),
+					"name": "event",
+					"event": map[string]interface{}{
+						"data":    map[string]interface{}{"event": float64(1)},
+						"event":   "fake",
+						"version": "42.0",
+					},
+				}))
+
+				msg = nil
+				Expect(conn.ReadJSON(&msg)).To(Succeed())
+				Expect(msg["id"]).To(Equal(float64(1)))
+				Expect(msg["name"]).To(Equal("event"))
+
+				msg = nil
+				Expect(conn.ReadJSON(&msg)).To(Succeed())
+				Expect(msg).To(Equal(map[string]interface{}{
+					"id":   float64(2),
+					"name": "end",
+				}))
+
+				_, _, err := conn.ReadMessage()
+				Expect(websocket.IsCloseError(err, websocket.CloseNormalClosure)).To(BeTrue())
+			})
+
+			Context("when the Last-Event-ID header is given", func() {
+				BeforeEach(func() {
+					request.Header.Set("Last-Event-ID", "0")
+				})
+
+				It("starts subscribing from after the id", func() {
+					Eventually(build.EventsCallCount).Should(Equal(1))
+					Expect(build.EventsArgsForCall(0)).To(Equal(uint(1)))
+				})
+			})
 		})
 
 		Context("when the eventsource returns an error", func() {
@@ -211,7 +601,7 @@ var _ = Describe("Handler", func() {
 				Expect(reader.Next()).To(Equal(sse.Event{
 					ID:   "0",
 					Name: "event",
-					Data: []byte(`{"data":{"event":1},"event":"fake","version":"42.0"}`),
+					Data: []byte(`{"data":{"event":1},"event":"fake","version":"42.0"},"id":0}`),
 				}))
 
 				_, err := reader.Next()
@@ -251,6 +641,78 @@ var _ = Describe("Handler", func() {
 			})
 		})
 
+		Context("when the from query parameter is malformed", func() {
+			BeforeEach(func() {
+				request.URL.RawQuery = "from=nope"
+			})
+
+			JustBeforeEach(func() {
+				var err error
+
+				client := &http.Client{
+					Transport: &http.Transport{},
+				}
+				response, err = client.Do(request)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns 400", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+			})
+
+			It("does not subscribe to the build", func() {
+				Expect(build.EventsCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when the requested event schema is malformed", func() {
+			BeforeEach(func() {
+				request.Header.Set("Accept", "text/event-stream; schema=nope")
+			})
+
+			JustBeforeEach(func() {
+				var err error
+
+				client := &http.Client{
+					Transport: &http.Transport{},
+				}
+				response, err = client.Do(request)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns 406", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusNotAcceptable))
+			})
+
+			It("does not subscribe to the build", func() {
+				Expect(build.EventsCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when the replay mode is unknown", func() {
+			BeforeEach(func() {
+				request.URL.RawQuery = "replay=backwards"
+			})
+
+			JustBeforeEach(func() {
+				var err error
+
+				client := &http.Client{
+					Transport: &http.Transport{},
+				}
+				response, err = client.Do(request)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns 400", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+			})
+
+			It("does not subscribe to the build", func() {
+				Expect(build.EventsCallCount()).To(BeZero())
+			})
+		})
+
 		Context("when subscribing to it fails", func() {
 			BeforeEach(func() {
 				build.EventsReturns(nil, errors.New("nope"))
@@ -1,24 +1,118 @@
 package buildserver
 
 import (
+	"context"
 	"net/http"
+	"strconv"
+	"time"
 
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
 	"github.com/concourse/atc/db"
 )
 
+// AroundQueryParam is the offset of an event to list along with the events
+// either side of it, e.g. for following a link to a line of a build's log.
+const AroundQueryParam = "around"
+
+// ContextQueryParam is how many events either side of ?around= to list.
+const ContextQueryParam = "context"
+
+// DefaultEventContext is how many events either side of ?around= are listed
+// if ?context= isn't given, and MaxEventContext is the most that can be.
+const DefaultEventContext = 50
+const MaxEventContext = EventBatchSize
+
+// BuildEvents streams the build's events. Given ?around=, it instead lists
+// the events around that one, as ListBuildEvents would, without following
+// the build.
 func (s *Server) BuildEvents(build db.Build) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		streamDone := make(chan struct{})
+		logger := s.logger.Session("build-events", lager.Data{"build-id": build.ID()})
 
-		go func() {
-			defer close(streamDone)
+		if r.FormValue(AroundQueryParam) != "" {
+			s.listEventsAround(logger, build, w, r)
+			return
+		}
+
+		s.serveStream(logger, atc.BuildEvents, s.eventHandlerFactory(s.logger, build), w, r)
+	})
+}
 
-			s.eventHandlerFactory(s.logger, build).ServeHTTP(w, r)
-		}()
+func (s *Server) listEventsAround(logger lager.Logger, build db.Build, w http.ResponseWriter, r *http.Request) {
+	around, err := strconv.ParseUint(r.FormValue(AroundQueryParam), 10, 32)
+	if err != nil {
+		logger.Info("malformed-around", lager.Data{"around": r.FormValue(AroundQueryParam)})
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
 
-		select {
-		case <-streamDone:
-		case <-s.drain:
+	surrounding := DefaultEventContext
+	if r.FormValue(ContextQueryParam) != "" {
+		surrounding, err = strconv.Atoi(r.FormValue(ContextQueryParam))
+		if err != nil || surrounding < 0 {
+			logger.Info("malformed-context", lager.Data{"context": r.FormValue(ContextQueryParam)})
+			w.WriteHeader(http.StatusBadRequest)
+			return
 		}
-	})
+	}
+
+	if surrounding > MaxEventContext {
+		surrounding = MaxEventContext
+	}
+
+	offset := uint(0)
+	if uint(around) > uint(surrounding) {
+		offset = uint(around) - uint(surrounding)
+	}
+
+	s.listBuildEvents(logger, build, offset, int(uint(around)-offset)+surrounding+1, w, r)
+}
+
+// serveStream serves a long-lived stream with the keepalives configured for
+// the route, telling it when the server starts draining and cutting it off
+// once the drain grace period has elapsed.
+func (s *Server) serveStream(logger lager.Logger, route string, stream http.Handler, w http.ResponseWriter, r *http.Request) {
+	select {
+	case <-s.drain:
+		logger.Info("rejecting-stream-while-draining")
+		w.Header().Set("Retry-After", "1")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	default:
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	draining := make(chan struct{})
+
+	streamDone := make(chan struct{})
+
+	go func() {
+		defer close(streamDone)
+
+		streamRequest := withDraining(r.WithContext(ctx), draining)
+		streamRequest = withKeepAlive(streamRequest, s.keepAlives.IntervalFor(route))
+
+		stream.ServeHTTP(w, streamRequest)
+	}()
+
+	select {
+	case <-streamDone:
+		return
+	case <-s.drain:
+	}
+
+	close(draining)
+
+	select {
+	case <-streamDone:
+	case <-time.After(s.drainGracePeriod):
+		logger.Info("drain-grace-period-elapsed")
+
+		cancel()
+
+		<-streamDone
+	}
 }
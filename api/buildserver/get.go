@@ -1,17 +1,54 @@
 package buildserver
 
 import (
+	"bytes"
 	"encoding/json"
 	"net/http"
 
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/api/conditional"
 	"github.com/concourse/atc/api/present"
 	"github.com/concourse/atc/db"
 )
 
 func (s *Server) GetBuild(build db.Build) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		presentedBuild := present.Build(build)
+
+		if build.Status() == db.StatusPending {
+			position, queued, err := build.QueuePosition()
+			if err != nil {
+				s.logger.Error("failed-to-get-queue-position", err, lager.Data{"build": build.ID()})
+				apierror.DBFailure(w, "failed to get queue position")
+				return
+			}
+
+			if queued {
+				presentedBuild.QueuePosition = position
+			}
+		}
+
+		if r.URL.Query().Get("include") == "comments" {
+			comments, err := build.GetComments()
+			if err != nil {
+				s.logger.Error("failed-to-get-comments", err, lager.Data{"build": build.ID()})
+				apierror.DBFailure(w, "failed to get comments")
+				return
+			}
+
+			presentedBuild.Comments = presentBuildComments(comments)
+		}
+
+		var payload bytes.Buffer
+		json.NewEncoder(&payload).Encode(presentedBuild)
+
+		if conditional.NotModified(w, r, payload.Bytes()) {
+			return
+		}
+
 		w.WriteHeader(http.StatusOK)
 
-		json.NewEncoder(w).Encode(present.Build(build))
+		payload.WriteTo(w)
 	})
 }
@@ -0,0 +1,38 @@
+package buildserver
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// KeepAlivePolicy decides how often a stream sends something to the client
+// while it has no events to send, so that idle connections aren't closed by
+// load balancers in between. Intervals may be given per route, falling back
+// to Interval; a zero interval disables keepalives.
+type KeepAlivePolicy struct {
+	Interval time.Duration
+	Routes   map[string]time.Duration
+}
+
+func (policy KeepAlivePolicy) IntervalFor(route string) time.Duration {
+	if interval, found := policy.Routes[route]; found {
+		return interval
+	}
+
+	return policy.Interval
+}
+
+type keepAliveKey struct{}
+
+func withKeepAlive(r *http.Request, interval time.Duration) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), keepAliveKey{}, interval))
+}
+
+// keepAliveIntervalFrom returns how often the stream serving the request
+// should send keepalives. Requests that were not routed through the Server
+// have none.
+func keepAliveIntervalFrom(r *http.Request) time.Duration {
+	interval, _ := r.Context().Value(keepAliveKey{}).(time.Duration)
+	return interval
+}
@@ -0,0 +1,264 @@
+package buildserver_test
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	"github.com/concourse/atc"
+	. "github.com/concourse/atc/api/buildserver"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/db/dbfakes"
+	"github.com/concourse/atc/event"
+	"github.com/gorilla/websocket"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("KeepAlivePolicy", func() {
+	It("uses the interval for the route, falling back to the default", func() {
+		policy := KeepAlivePolicy{
+			Interval: time.Minute,
+			Routes: map[string]time.Duration{
+				atc.BuildEvents: 10 * time.Second,
+			},
+		}
+
+		Expect(policy.IntervalFor(atc.BuildEvents)).To(Equal(10 * time.Second))
+		Expect(policy.IntervalFor(atc.GetBuildLog)).To(Equal(time.Minute))
+	})
+})
+
+var _ = Describe("Keepalives", func() {
+	var (
+		build           *dbfakes.FakeBuild
+		fakeEventSource *dbfakes.FakeEventSource
+		closed          chan struct{}
+
+		keepAlives KeepAlivePolicy
+		server     *httptest.Server
+		served     chan struct{}
+	)
+
+	BeforeEach(func() {
+		build = new(dbfakes.FakeBuild)
+
+		fakeEventSource = new(dbfakes.FakeEventSource)
+
+		closed = make(chan struct{})
+		fakeEventSource.CloseStub = func() error {
+			close(closed)
+			return nil
+		}
+
+		fakeEventSource.NextStub = func() (event.Envelope, error) {
+			<-closed
+			return event.Envelope{}, db.ErrBuildEventStreamClosed
+		}
+
+		build.EventsReturns(fakeEventSource, nil)
+
+		keepAlives = KeepAlivePolicy{
+			Interval: time.Hour,
+			Routes: map[string]time.Duration{
+				atc.BuildEvents: 50 * time.Millisecond,
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		buildServer := NewServer(
+			lagertest.NewTestLogger("test"),
+			"http://example.com",
+			nil,
+			nil,
+			nil,
+			nil,
+			NewEventHandler,
+			CensorPolicies{},
+			nil,
+			make(chan struct{}),
+			time.Second,
+			keepAlives,
+			nil,
+			nil,
+		)
+
+		served = make(chan struct{})
+
+		handler := buildServer.BuildEvents(build)
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer close(served)
+			handler.ServeHTTP(w, r)
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("sends comments on idle server-sent event streams", func() {
+		response, err := http.Get(server.URL)
+		Expect(err).NotTo(HaveOccurred())
+
+		defer response.Body.Close()
+
+		reader := bufio.NewReader(response.Body)
+
+		for i := 0; i < 2; i++ {
+			line, err := reader.ReadString('\n')
+			Expect(err).NotTo(HaveOccurred())
+			Expect(line).To(Equal(": ping\n"))
+
+			line, err = reader.ReadString('\n')
+			Expect(err).NotTo(HaveOccurred())
+			Expect(line).To(Equal("\n"))
+		}
+	})
+
+	It("sends pings on idle websocket streams", func() {
+		wsURL, err := url.Parse(server.URL)
+		Expect(err).NotTo(HaveOccurred())
+
+		wsURL.Scheme = "ws"
+
+		conn, _, err := websocket.DefaultDialer.Dial(wsURL.String(), nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		defer conn.Close()
+
+		pinged := make(chan struct{}, 10)
+		conn.SetPingHandler(func(data string) error {
+			pinged <- struct{}{}
+			return conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second))
+		})
+
+		go func() {
+			for {
+				_, _, err := conn.NextReader()
+				if err != nil {
+					return
+				}
+			}
+		}()
+
+		Eventually(pinged).Should(Receive())
+		Eventually(pinged).Should(Receive())
+	})
+
+	It("tears down server-sent event streams once the client has gone away", func() {
+		response, err := http.Get(server.URL)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = bufio.NewReader(response.Body).ReadString('\n')
+		Expect(err).NotTo(HaveOccurred())
+
+		response.Body.Close()
+
+		Eventually(closed).Should(BeClosed())
+	})
+
+	It("tears down server-sent event streams whose client stops reading", func() {
+		bigEvent := fakeEvent(`{"payload":"` + strings.Repeat("x", 64*1024) + `"}`)
+
+		fakeEventSource.NextStub = func() (event.Envelope, error) {
+			select {
+			case <-closed:
+				return event.Envelope{}, db.ErrBuildEventStreamClosed
+			default:
+				return bigEvent, nil
+			}
+		}
+
+		conn, err := net.Dial("tcp", server.Listener.Addr().String())
+		Expect(err).NotTo(HaveOccurred())
+
+		defer conn.Close()
+
+		_, err = io.WriteString(conn, "GET / HTTP/1.1\r\nHost: example.com\r\n\r\n")
+		Expect(err).NotTo(HaveOccurred())
+
+		// the events back up until writing them blocks, and then the
+		// keepalives time out behind them
+		Eventually(closed, 10*time.Second).Should(BeClosed())
+		Eventually(served, 10*time.Second).Should(BeClosed())
+	})
+
+	It("tears down websocket streams whose client stops answering pings", func() {
+		wsURL, err := url.Parse(server.URL)
+		Expect(err).NotTo(HaveOccurred())
+
+		wsURL.Scheme = "ws"
+
+		conn, _, err := websocket.DefaultDialer.Dial(wsURL.String(), nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		defer conn.Close()
+
+		conn.SetPingHandler(func(string) error {
+			return nil
+		})
+
+		go func() {
+			for {
+				_, _, err := conn.NextReader()
+				if err != nil {
+					return
+				}
+			}
+		}()
+
+		Eventually(closed).Should(BeClosed())
+	})
+
+	It("keeps websocket streams whose client answers pings", func() {
+		wsURL, err := url.Parse(server.URL)
+		Expect(err).NotTo(HaveOccurred())
+
+		wsURL.Scheme = "ws"
+
+		conn, _, err := websocket.DefaultDialer.Dial(wsURL.String(), nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		defer conn.Close()
+
+		go func() {
+			for {
+				_, _, err := conn.NextReader()
+				if err != nil {
+					return
+				}
+			}
+		}()
+
+		Consistently(closed, 300*time.Millisecond).ShouldNot(BeClosed())
+	})
+
+	Context("when keepalives are disabled for the route", func() {
+		BeforeEach(func() {
+			keepAlives.Routes[atc.BuildEvents] = 0
+		})
+
+		It("sends nothing while the stream is idle", func() {
+			response, err := http.Get(server.URL)
+			Expect(err).NotTo(HaveOccurred())
+
+			defer response.Body.Close()
+
+			read := make(chan string, 1)
+			go func() {
+				line, _ := bufio.NewReader(response.Body).ReadString('\n')
+				read <- line
+			}()
+
+			Consistently(read, 200*time.Millisecond).ShouldNot(Receive())
+		})
+	})
+})
@@ -0,0 +1,48 @@
+package buildserver
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// LabelQueryParam carries build labels as key:value pairs. It may be given
+// more than once, both when creating builds and when filtering them.
+const LabelQueryParam = "label"
+
+func parseLabels(r *http.Request) (map[string]string, error) {
+	values := r.URL.Query()[LabelQueryParam]
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	labels := map[string]string{}
+	for _, value := range values {
+		segs := strings.SplitN(value, ":", 2)
+		if len(segs) != 2 || segs[0] == "" {
+			return nil, fmt.Errorf("malformed label '%s'; expected key:value", value)
+		}
+
+		labels[segs[0]] = segs[1]
+	}
+
+	return labels, nil
+}
+
+func labelsQuery(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	query := ""
+	for _, key := range keys {
+		query += "&" + LabelQueryParam + "=" + url.QueryEscape(key+":"+labels[key])
+	}
+
+	return query
+}
@@ -1,12 +1,17 @@
 package buildserver
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 
+	"code.cloudfoundry.org/lager"
 	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/api/conditional"
 	"github.com/concourse/atc/api/present"
 	"github.com/concourse/atc/auth"
 	"github.com/concourse/atc/db"
@@ -15,6 +20,46 @@ import (
 func (s *Server) ListBuilds(w http.ResponseWriter, r *http.Request) {
 	logger := s.logger.Session("list-builds")
 
+	s.listBuilds(logger, w, r, "/api/v1/builds", func(page db.Page, filter db.BuildFilter) ([]db.Build, db.Pagination, error) {
+		authTeam, authTeamFound := auth.GetTeam(r)
+		if authTeamFound {
+			teamDB := s.teamDBFactory.GetTeamDB(authTeam.Name())
+
+			hidden, err := hiddenPipelineIDs(r, teamDB)
+			if err != nil {
+				return nil, db.Pagination{}, err
+			}
+
+			filter.HiddenPipelineIDs = hidden
+
+			return teamDB.GetPrivateAndPublicBuilds(page, filter)
+		}
+
+		return s.buildsDB.GetPublicBuilds(r.Context(), page, filter)
+	})
+}
+
+func (s *Server) ListTeamBuilds(teamDB db.TeamDB) http.Handler {
+	logger := s.logger.Session("list-team-builds")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := fmt.Sprintf("/api/v1/teams/%s/builds", r.FormValue(":team_name"))
+		s.listBuilds(logger, w, r, path, func(page db.Page, filter db.BuildFilter) ([]db.Build, db.Pagination, error) {
+			hidden, err := hiddenPipelineIDs(r, teamDB)
+			if err != nil {
+				return nil, db.Pagination{}, err
+			}
+
+			filter.HiddenPipelineIDs = hidden
+
+			return teamDB.GetBuilds(page, filter)
+		})
+	})
+}
+
+type buildsFetcher func(db.Page, db.BuildFilter) ([]db.Build, db.Pagination, error)
+
+func (s *Server) listBuilds(logger lager.Logger, w http.ResponseWriter, r *http.Request, path string, getBuilds buildsFetcher) {
 	var (
 		err   error
 		until int
@@ -31,67 +76,95 @@ func (s *Server) ListBuilds(w http.ResponseWriter, r *http.Request) {
 	urlLimit := r.FormValue(atc.PaginationQueryLimit)
 
 	limit, _ = strconv.Atoi(urlLimit)
-	if limit == 0 {
+	if limit <= 0 {
 		limit = atc.PaginationAPIDefaultLimit
 	}
 
-	page := db.Page{Until: until, Since: since, Limit: limit}
-	var builds []db.Build
-	var pagination db.Pagination
-
-	authTeam, authTeamFound := auth.GetTeam(r)
-	if authTeamFound {
-		teamDB := s.teamDBFactory.GetTeamDB(authTeam.Name())
-		builds, pagination, err = teamDB.GetPrivateAndPublicBuilds(page)
-	} else {
-		builds, pagination, err = s.buildsDB.GetPublicBuilds(page)
+	if limit > atc.PaginationAPIMaxLimit {
+		limit = atc.PaginationAPIMaxLimit
+	}
+
+	labels, err := parseLabels(r)
+	if err != nil {
+		logger.Info("malformed-label", lager.Data{"error": err.Error()})
+		w.WriteHeader(http.StatusBadRequest)
+		return
 	}
 
+	page := db.Page{Until: until, Since: since, Limit: limit}
+	filter := db.BuildFilter{Labels: labels}
+
+	builds, pagination, err := getBuilds(page, filter)
 	if err != nil {
 		logger.Error("failed-to-get-all-builds", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		apierror.DBFailure(w, "failed to get all builds")
 		return
 	}
 
 	if pagination.Next != nil {
-		s.addNextLink(w, *pagination.Next)
+		s.addNextLink(w, path, *pagination.Next, filter)
 	}
 
 	if pagination.Previous != nil {
-		s.addPreviousLink(w, *pagination.Previous)
+		s.addPreviousLink(w, path, *pagination.Previous, filter)
 	}
 
-	w.WriteHeader(http.StatusOK)
-
 	atc := make([]atc.Build, len(builds))
 	for i := 0; i < len(builds); i++ {
 		build := builds[i]
 		atc[i] = present.Build(build)
 	}
 
-	json.NewEncoder(w).Encode(atc)
+	var payload bytes.Buffer
+	json.NewEncoder(&payload).Encode(atc)
+
+	w.Header().Add("Vary", "Accept-Encoding")
+
+	if conditional.NotModified(w, r, payload.Bytes()) {
+		return
+	}
+
+	var body io.Writer = w
+
+	if encoding := negotiateEncoding(r); encoding != "" {
+		compressor := newCompressingWriter(encoding, w)
+
+		defer compressor.Close()
+
+		w.Header().Set("Content-Encoding", encoding)
+
+		body = compressor
+	}
+
+	w.WriteHeader(http.StatusOK)
+
+	payload.WriteTo(body)
 }
 
-func (s *Server) addNextLink(w http.ResponseWriter, page db.Page) {
+func (s *Server) addNextLink(w http.ResponseWriter, path string, page db.Page, filter db.BuildFilter) {
 	w.Header().Add("Link", fmt.Sprintf(
-		`<%s/api/v1/builds?%s=%d&%s=%d>; rel="%s"`,
+		`<%s%s?%s=%d&%s=%d%s>; rel="%s"`,
 		s.externalURL,
+		path,
 		atc.PaginationQuerySince,
 		page.Since,
 		atc.PaginationQueryLimit,
 		page.Limit,
+		labelsQuery(filter.Labels),
 		atc.LinkRelNext,
 	))
 }
 
-func (s *Server) addPreviousLink(w http.ResponseWriter, page db.Page) {
+func (s *Server) addPreviousLink(w http.ResponseWriter, path string, page db.Page, filter db.BuildFilter) {
 	w.Header().Add("Link", fmt.Sprintf(
-		`<%s/api/v1/builds?%s=%d&%s=%d>; rel="%s"`,
+		`<%s%s?%s=%d&%s=%d%s>; rel="%s"`,
 		s.externalURL,
+		path,
 		atc.PaginationQueryUntil,
 		page.Until,
 		atc.PaginationQueryLimit,
 		page.Limit,
+		labelsQuery(filter.Labels),
 		atc.LinkRelPrevious,
 	))
 }
@@ -0,0 +1,188 @@
+package buildserver
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/api/present"
+	"github.com/concourse/atc/auth"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/event"
+)
+
+// OffsetQueryParam is the index of the first event to list.
+const OffsetQueryParam = "offset"
+
+// EventBatchSize is how many events are read at a time while listing them.
+const EventBatchSize = 500
+
+type eventBatches func(offset uint, limit int) ([]event.Envelope, error)
+
+// ListBuildEvents lists the build's events from ?offset= on, at most ?limit=
+// of them or all of them if no limit is given. Unlike BuildEvents it returns
+// as soon as it runs out of events instead of waiting for more, so clients
+// can poll it with the offset of the next event they haven't seen. The
+// events are read and written a batch at a time, so a huge build's events
+// are never all held at once.
+func (s *Server) ListBuildEvents(build db.Build) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger := s.logger.Session("list-build-events", lager.Data{"build-id": build.ID()})
+
+		var offset uint
+		if r.FormValue(OffsetQueryParam) != "" {
+			parsed, err := strconv.ParseUint(r.FormValue(OffsetQueryParam), 10, 32)
+			if err != nil {
+				logger.Info("malformed-offset", lager.Data{"offset": r.FormValue(OffsetQueryParam)})
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			offset = uint(parsed)
+		}
+
+		var limit int
+		if r.FormValue(atc.PaginationQueryLimit) != "" {
+			var err error
+			limit, err = strconv.Atoi(r.FormValue(atc.PaginationQueryLimit))
+			if err != nil || limit <= 0 {
+				logger.Info("malformed-limit", lager.Data{"limit": r.FormValue(atc.PaginationQueryLimit)})
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+		}
+
+		s.listBuildEvents(logger, build, offset, limit, w, r)
+	})
+}
+
+// listBuildEvents writes a JSON list of the build's events from the offset
+// on, at most limit of them or all of them if the limit is zero.
+func (s *Server) listBuildEvents(logger lager.Logger, build db.Build, offset uint, limit int, w http.ResponseWriter, r *http.Request) {
+	batches := eventBatches(func(offset uint, limit int) ([]event.Envelope, error) {
+		return s.buildsDB.GetBuildEventsFrom(r.Context(), build.ID(), offset, limit)
+	})
+
+	if !build.ReapTime().IsZero() {
+		// the events are no longer in the database, but may be archived
+		source, err := s.eventHub.Subscribe(build, offset)
+		if err != nil {
+			logger.Error("failed-to-get-build-events", err)
+			apierror.DBFailure(w, "failed to get build events")
+			return
+		}
+
+		defer source.Close()
+
+		batches = sourceBatches(source)
+	}
+
+	authTeam, authTeamFound := auth.GetTeam(r)
+
+	filter := eventFilter{
+		censor: s.censorPolicies.RuleFor(build, authTeamFound && authTeam.IsAuthorized(build.TeamName())),
+	}
+
+	batchSize := EventBatchSize
+	if limit > 0 && limit < batchSize {
+		batchSize = limit
+	}
+
+	// read the first batch before responding, so that failing to get
+	// any events comes back as an error rather than an empty list
+	events, err := batches(offset, batchSize)
+	if err != nil {
+		logger.Error("failed-to-get-build-events", err)
+		apierror.DBFailure(w, "failed to get build events")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	flusher, _ := w.(http.Flusher)
+
+	encoder := json.NewEncoder(w)
+
+	io.WriteString(w, "[")
+
+	listed := 0
+	read := 0
+	for {
+		for _, ev := range events {
+			id := offset
+			offset++
+
+			ev, send, err := filter.Filter(ev)
+			if err != nil {
+				logger.Error("failed-to-filter-event", err)
+				return
+			}
+
+			if !send {
+				continue
+			}
+
+			if listed > 0 {
+				io.WriteString(w, ",")
+			}
+
+			err = encoder.Encode(present.BuildEvent(id, ev))
+			if err != nil {
+				logger.Info("failed-to-write-event", lager.Data{"error": err.Error()})
+				return
+			}
+
+			listed++
+		}
+
+		read += len(events)
+
+		if len(events) < batchSize || (limit > 0 && read >= limit) {
+			break
+		}
+
+		if flusher != nil {
+			flusher.Flush()
+		}
+
+		if limit > 0 && limit-read < batchSize {
+			batchSize = limit - read
+		}
+
+		events, err = batches(offset, batchSize)
+		if err != nil {
+			// it's too late to say so; the list is left unterminated
+			logger.Error("failed-to-get-build-events", err)
+			return
+		}
+	}
+
+	io.WriteString(w, "]\n")
+}
+
+// sourceBatches reads batches from the source one after another, ignoring the
+// offsets asked for as the source is already positioned at them.
+func sourceBatches(source db.EventSource) eventBatches {
+	return func(_ uint, limit int) ([]event.Envelope, error) {
+		events := []event.Envelope{}
+		for len(events) < limit {
+			ev, err := source.Next()
+			if err != nil {
+				if err == db.ErrEndOfBuildEventStream {
+					break
+				}
+
+				return nil, err
+			}
+
+			events = append(events, ev)
+		}
+
+		return events, nil
+	}
+}
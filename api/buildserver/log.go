@@ -0,0 +1,241 @@
+package buildserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/auth"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/event"
+)
+
+// TimestampsQueryParam, when "true", prefixes every line of the log with the
+// time the output was saved at.
+const TimestampsQueryParam = "timestamps"
+
+// ANSIQueryParam, when "true", keeps the escape sequences in the log that
+// would otherwise be stripped, e.g. for viewing with colour in a terminal.
+const ANSIQueryParam = "ansi"
+
+// ElapsedQueryParam interleaves a line saying how long the build has been
+// running for every time the given interval passes between lines of the log,
+// e.g. ?elapsed=30s.
+const ElapsedQueryParam = "elapsed"
+
+const logTimestampLayout = "2006-01-02T15:04:05Z"
+
+var ansiEscape = regexp.MustCompile("\x1b\\[[0-9;?]*[ -/]*[@-~]")
+
+// GetBuildLog streams the build's output as plain text, following it until
+// the build finishes. Errors are included as lines of their own.
+func (s *Server) GetBuildLog(build db.Build) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger := s.logger.Session("get-build-log", lager.Data{"build-id": build.ID()})
+
+		var markEvery time.Duration
+		if r.FormValue(ElapsedQueryParam) != "" {
+			var err error
+			markEvery, err = time.ParseDuration(r.FormValue(ElapsedQueryParam))
+			if err != nil || markEvery <= 0 {
+				logger.Info("malformed-elapsed", lager.Data{"elapsed": r.FormValue(ElapsedQueryParam)})
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+		}
+
+		s.streamBuildLog(logger, build, w, r, "text/plain; charset=utf-8", &logWriter{
+			writer:     w,
+			timestamps: r.FormValue(TimestampsQueryParam) == "true",
+			ansi:       r.FormValue(ANSIQueryParam) == "true",
+			lineStart:  true,
+
+			markEvery: markEvery,
+			startedAt: build.StartTime(),
+		})
+	})
+}
+
+// streamBuildLog writes the text of the build's log and error events to the
+// writer as they're saved, flushing after each one, until the build finishes
+// or the request goes away. It returns false if it couldn't get the events,
+// in which case nothing will have been written.
+func (s *Server) streamBuildLog(logger lager.Logger, build db.Build, w http.ResponseWriter, r *http.Request, contentType string, writer *logWriter) bool {
+	authTeam, authTeamFound := auth.GetTeam(r)
+
+	filter := eventFilter{
+		censor: s.censorPolicies.RuleFor(build, authTeamFound && authTeam.IsAuthorized(build.TeamName())),
+		types: map[atc.EventType]bool{
+			event.EventTypeLog:     true,
+			event.EventTypeError:   true,
+			event.EventTypeWarning: true,
+		},
+	}
+
+	events, err := s.eventHub.Subscribe(build, 0)
+	if err != nil {
+		logger.Error("failed-to-get-build-events", err)
+		apierror.DBFailure(w, "failed to get build events")
+		return false
+	}
+
+	var closeOnce sync.Once
+	closeEvents := func() {
+		closeOnce.Do(func() {
+			events.Close()
+		})
+	}
+
+	defer closeEvents()
+
+	go func() {
+		<-r.Context().Done()
+		closeEvents()
+	}()
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	w.WriteHeader(http.StatusOK)
+
+	flusher, _ := w.(http.Flusher)
+
+	for {
+		ev, err := events.Next()
+		if err != nil {
+			if err != db.ErrEndOfBuildEventStream && err != db.ErrBuildEventStreamClosed {
+				logger.Error("failed-to-get-next-build-event", err)
+			}
+
+			return true
+		}
+
+		ev, send, err := filter.Filter(ev)
+		if err != nil {
+			logger.Error("failed-to-filter-event", err)
+			return true
+		}
+
+		if !send || ev.Data == nil {
+			continue
+		}
+
+		// every version of log, error, and warning events carries its text
+		// in one of these two fields, so there's no need to parse them by version
+		var output struct {
+			Payload string `json:"payload"`
+			Message string `json:"message"`
+		}
+
+		err = json.Unmarshal(*ev.Data, &output)
+		if err != nil {
+			logger.Error("failed-to-unmarshal-event", err)
+			return true
+		}
+
+		text := output.Payload
+		switch ev.Event {
+		case event.EventTypeError:
+			text = output.Message + "\n"
+		case event.EventTypeWarning:
+			text = "warning: " + output.Message + "\n"
+		}
+
+		err = writer.Write(ev.Time, text)
+		if err != nil {
+			logger.Info("failed-to-write-log", lager.Data{"error": err.Error()})
+			return true
+		}
+
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+}
+
+type logWriter struct {
+	writer io.Writer
+
+	timestamps bool
+	ansi       bool
+
+	lineStart bool
+
+	// markEvery is how often to mark the time elapsed since startedAt, or
+	// since the first line if the build has no start time. Lines without a
+	// time don't count.
+	markEvery time.Duration
+	startedAt time.Time
+	marked    time.Duration
+}
+
+func (writer *logWriter) Write(at time.Time, text string) error {
+	if !writer.ansi {
+		text = ansiEscape.ReplaceAllString(text, "")
+	}
+
+	if !writer.timestamps && writer.markEvery == 0 {
+		_, err := io.WriteString(writer.writer, text)
+		return err
+	}
+
+	var prefix string
+	if writer.timestamps {
+		// events saved before times were recorded get a blank prefix, so
+		// that the lines still line up
+		prefix = strings.Repeat(" ", len(logTimestampLayout)) + " "
+		if !at.IsZero() {
+			prefix = at.UTC().Format(logTimestampLayout) + " "
+		}
+	}
+
+	var buf bytes.Buffer
+	for _, line := range strings.SplitAfter(text, "\n") {
+		if line == "" {
+			continue
+		}
+
+		if writer.lineStart {
+			writer.mark(&buf, at, prefix)
+			buf.WriteString(prefix)
+		}
+
+		buf.WriteString(line)
+
+		writer.lineStart = strings.HasSuffix(line, "\n")
+	}
+
+	_, err := buf.WriteTo(writer.writer)
+	return err
+}
+
+// mark writes a line with the time elapsed if another interval has passed
+// since the last one. Intervals that pass without any lines are only marked
+// once, by the line after them.
+func (writer *logWriter) mark(buf *bytes.Buffer, at time.Time, prefix string) {
+	if writer.markEvery == 0 || at.IsZero() {
+		return
+	}
+
+	if writer.startedAt.IsZero() {
+		writer.startedAt = at
+	}
+
+	elapsed := at.Sub(writer.startedAt)
+	if elapsed < writer.marked+writer.markEvery {
+		return
+	}
+
+	writer.marked = elapsed - elapsed%writer.markEvery
+
+	buf.WriteString(prefix)
+	fmt.Fprintf(buf, "--- %s elapsed ---\n", writer.marked)
+}
@@ -0,0 +1,368 @@
+package buildserver
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/db"
+)
+
+// GetBuildLogHTML streams the build's output as an HTML page, with the colours
+// and styles set by escape sequences turned into styled spans. Like
+// GetBuildLog, it follows the output until the build finishes.
+func (s *Server) GetBuildLogHTML(build db.Build) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger := s.logger.Session("get-build-log-html", lager.Data{"build-id": build.ID()})
+
+		title := fmt.Sprintf("build #%d", build.ID())
+		if build.JobName() != "" {
+			title = fmt.Sprintf("%s #%s", build.JobName(), build.Name())
+		}
+
+		page := &htmlLogWriter{
+			writer: w,
+			title:  title,
+		}
+
+		streamed := s.streamBuildLog(logger, build, w, r, "text/html; charset=utf-8", &logWriter{
+			writer:     page,
+			timestamps: r.FormValue(TimestampsQueryParam) == "true",
+			ansi:       true,
+			lineStart:  true,
+		})
+		if !streamed {
+			return
+		}
+
+		err := page.Close()
+		if err != nil {
+			logger.Info("failed-to-finish-log", lager.Data{"error": err.Error()})
+		}
+	})
+}
+
+const htmlLogHeader = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>%s</title>
+<style>
+body { margin: 0; background: #1e1e1e; color: #e6e6e6; }
+pre { margin: 0; padding: 1em; font-family: monospace; white-space: pre-wrap; word-wrap: break-word; }
+.ansi-bold { font-weight: bold; }
+.ansi-faint { opacity: 0.6; }
+.ansi-italic { font-style: italic; }
+.ansi-underline { text-decoration: underline; }
+%s</style>
+</head>
+<body>
+<pre>`
+
+const htmlLogFooter = `</pre>
+</body>
+</html>
+`
+
+// ansiColors are the 16 standard and bright colours, in the order of their
+// escape codes.
+var ansiColors = []string{
+	"#000000", "#cd3131", "#0dbc79", "#e5e510", "#2472c8", "#bc3fbc", "#11a8cd", "#e5e5e5",
+	"#666666", "#f14c4c", "#23d18b", "#f5f543", "#3b8eea", "#d670d6", "#29b8db", "#ffffff",
+}
+
+var ansiColorStyles = func() string {
+	var buf bytes.Buffer
+	for i, color := range ansiColors {
+		fmt.Fprintf(&buf, ".ansi-fg-%d { color: %s; }\n", i, color)
+		fmt.Fprintf(&buf, ".ansi-bg-%d { background-color: %s; }\n", i, color)
+	}
+
+	return buf.String()
+}()
+
+// incompleteANSIEscape matches an escape sequence cut off at the end of a
+// chunk of output, to be finished by the next chunk.
+var incompleteANSIEscape = regexp.MustCompile("^\x1b(\\[[0-9;?]*[ -/]*)?$")
+
+var leadingANSIEscape = regexp.MustCompile("^\x1b\\[([0-9;?]*)([ -/]*)([@-~])")
+
+// ansiColor is either the index of one of the 16 ansiColors, or an RGB value
+// set by an extended colour code.
+type ansiColor struct {
+	set   bool
+	index int
+	rgb   string
+}
+
+type ansiStyle struct {
+	bold      bool
+	faint     bool
+	italic    bool
+	underline bool
+
+	fg ansiColor
+	bg ansiColor
+}
+
+func (style ansiStyle) span() string {
+	classes := []string{}
+	styles := []string{}
+
+	if style.bold {
+		classes = append(classes, "ansi-bold")
+	}
+
+	if style.faint {
+		classes = append(classes, "ansi-faint")
+	}
+
+	if style.italic {
+		classes = append(classes, "ansi-italic")
+	}
+
+	if style.underline {
+		classes = append(classes, "ansi-underline")
+	}
+
+	if style.fg.set {
+		if style.fg.rgb != "" {
+			styles = append(styles, "color: "+style.fg.rgb)
+		} else {
+			classes = append(classes, fmt.Sprintf("ansi-fg-%d", style.fg.index))
+		}
+	}
+
+	if style.bg.set {
+		if style.bg.rgb != "" {
+			styles = append(styles, "background-color: "+style.bg.rgb)
+		} else {
+			classes = append(classes, fmt.Sprintf("ansi-bg-%d", style.bg.index))
+		}
+	}
+
+	if len(classes) == 0 && len(styles) == 0 {
+		return ""
+	}
+
+	span := "<span"
+	if len(classes) > 0 {
+		span += ` class="` + strings.Join(classes, " ") + `"`
+	}
+
+	if len(styles) > 0 {
+		span += ` style="` + strings.Join(styles, "; ") + `"`
+	}
+
+	return span + ">"
+}
+
+// htmlLogWriter turns output containing escape sequences into HTML. Styles
+// carry over from one write to the next, as they would in a terminal, and
+// escape sequences other than those setting styles are dropped.
+type htmlLogWriter struct {
+	writer io.Writer
+	title  string
+
+	started  bool
+	style    ansiStyle
+	spanOpen bool
+	pending  string
+}
+
+func (writer *htmlLogWriter) Write(p []byte) (int, error) {
+	var buf bytes.Buffer
+
+	if !writer.started {
+		fmt.Fprintf(&buf, htmlLogHeader, html.EscapeString(writer.title), ansiColorStyles)
+		writer.started = true
+	}
+
+	text := writer.pending + string(p)
+	writer.pending = ""
+
+	for len(text) > 0 {
+		escape := strings.IndexByte(text, '\x1b')
+		if escape == -1 {
+			buf.WriteString(html.EscapeString(text))
+			break
+		}
+
+		buf.WriteString(html.EscapeString(text[:escape]))
+		text = text[escape:]
+
+		match := leadingANSIEscape.FindStringSubmatch(text)
+		if match == nil {
+			if incompleteANSIEscape.MatchString(text) {
+				writer.pending = text
+				break
+			}
+
+			// not an escape sequence we know about; drop the escape character
+			text = text[1:]
+			continue
+		}
+
+		text = text[len(match[0]):]
+
+		params, intermediate, final := match[1], match[2], match[3]
+		if final != "m" || intermediate != "" || strings.Contains(params, "?") {
+			continue
+		}
+
+		style := writer.style.apply(params)
+		if style == writer.style {
+			continue
+		}
+
+		writer.style = style
+
+		if writer.spanOpen {
+			buf.WriteString("</span>")
+			writer.spanOpen = false
+		}
+
+		span := style.span()
+		if span != "" {
+			buf.WriteString(span)
+			writer.spanOpen = true
+		}
+	}
+
+	_, err := buf.WriteTo(writer.writer)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Close finishes the page, closing any span that's still open.
+func (writer *htmlLogWriter) Close() error {
+	var buf bytes.Buffer
+
+	if !writer.started {
+		fmt.Fprintf(&buf, htmlLogHeader, html.EscapeString(writer.title), ansiColorStyles)
+		writer.started = true
+	}
+
+	if writer.spanOpen {
+		buf.WriteString("</span>")
+		writer.spanOpen = false
+	}
+
+	buf.WriteString(htmlLogFooter)
+
+	_, err := buf.WriteTo(writer.writer)
+	return err
+}
+
+func (style ansiStyle) apply(params string) ansiStyle {
+	codes := []int{}
+	for _, param := range strings.Split(params, ";") {
+		// an empty parameter, as in "\x1b[m", means 0
+		code, _ := strconv.Atoi(param)
+		codes = append(codes, code)
+	}
+
+	for i := 0; i < len(codes); i++ {
+		code := codes[i]
+
+		switch {
+		case code == 0:
+			style = ansiStyle{}
+		case code == 1:
+			style.bold = true
+		case code == 2:
+			style.faint = true
+		case code == 3:
+			style.italic = true
+		case code == 4:
+			style.underline = true
+		case code == 22:
+			style.bold = false
+			style.faint = false
+		case code == 23:
+			style.italic = false
+		case code == 24:
+			style.underline = false
+		case code >= 30 && code <= 37:
+			style.fg = ansiColor{set: true, index: code - 30}
+		case code == 38:
+			color, used := extendedANSIColor(codes[i+1:])
+			style.fg = color
+			i += used
+		case code == 39:
+			style.fg = ansiColor{}
+		case code >= 40 && code <= 47:
+			style.bg = ansiColor{set: true, index: code - 40}
+		case code == 48:
+			color, used := extendedANSIColor(codes[i+1:])
+			style.bg = color
+			i += used
+		case code == 49:
+			style.bg = ansiColor{}
+		case code >= 90 && code <= 97:
+			style.fg = ansiColor{set: true, index: code - 90 + 8}
+		case code >= 100 && code <= 107:
+			style.bg = ansiColor{set: true, index: code - 100 + 8}
+		}
+	}
+
+	return style
+}
+
+// extendedANSIColor parses the arguments to a 38 or 48 code, which are either
+// 5 and an index into the 256 colour palette, or 2 and an RGB value. It
+// returns how many of the codes it used.
+func extendedANSIColor(codes []int) (ansiColor, int) {
+	if len(codes) >= 2 && codes[0] == 5 {
+		return paletteANSIColor(codes[1]), 2
+	}
+
+	if len(codes) >= 4 && codes[0] == 2 {
+		return ansiColor{
+			set: true,
+			rgb: fmt.Sprintf("#%02x%02x%02x", clampColor(codes[1]), clampColor(codes[2]), clampColor(codes[3])),
+		}, 4
+	}
+
+	return ansiColor{}, len(codes)
+}
+
+func paletteANSIColor(index int) ansiColor {
+	switch {
+	case index < 0 || index > 255:
+		return ansiColor{}
+	case index < 16:
+		return ansiColor{set: true, index: index}
+	case index < 232:
+		levels := []int{0, 95, 135, 175, 215, 255}
+		index -= 16
+		return ansiColor{
+			set: true,
+			rgb: fmt.Sprintf("#%02x%02x%02x", levels[index/36], levels[index/6%6], levels[index%6]),
+		}
+	default:
+		gray := 8 + (index-232)*10
+		return ansiColor{set: true, rgb: fmt.Sprintf("#%02x%02x%02x", gray, gray, gray)}
+	}
+}
+
+func clampColor(value int) int {
+	if value < 0 {
+		return 0
+	}
+
+	if value > 255 {
+		return 255
+	}
+
+	return value
+}
@@ -0,0 +1,191 @@
+package buildserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/api/present"
+	"github.com/concourse/atc/auth"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/event"
+)
+
+const LogSearchQueryParam = "q"
+
+func (s *Server) SearchBuildLogs(build db.Build) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger := s.logger.Session("search-build-logs", lager.Data{
+			"build": build.ID(),
+		})
+
+		query, limit, ok := parseLogSearch(r)
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		matches, err := build.SearchLogs(query, limit)
+		if err != nil {
+			logger.Error("failed-to-search-logs", err)
+			apierror.DBFailure(w, "failed to search logs")
+			return
+		}
+
+		authTeam, authTeamFound := auth.GetTeam(r)
+		rule := s.censorPolicies.RuleFor(build, authTeamFound && authTeam.IsAuthorized(build.TeamName()))
+
+		censored := []db.LogMatch{}
+		for _, match := range matches {
+			keep, err := rule.keepLogMatch(match)
+			if err != nil {
+				logger.Error("failed-to-censor-logs", err)
+				apierror.Internal(w, "failed to censor logs")
+				return
+			}
+
+			if keep {
+				censored = append(censored, match)
+			}
+		}
+
+		writeLogMatches(w, censored)
+	})
+}
+
+func (s *Server) SearchAllBuildLogs(teamDB db.TeamDB) http.Handler {
+	logger := s.logger.Session("search-all-build-logs")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query, limit, ok := parseLogSearch(r)
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		hidden, err := hiddenPipelineIDs(r, teamDB)
+		if err != nil {
+			logger.Error("failed-to-get-pipelines", err)
+			apierror.DBFailure(w, "failed to get pipelines")
+			return
+		}
+
+		matches, err := teamDB.SearchBuildLogs(query, limit, hidden)
+		if err != nil {
+			logger.Error("failed-to-search-logs", err)
+			apierror.DBFailure(w, "failed to search logs")
+			return
+		}
+
+		buildIDs := []int{}
+		for _, match := range matches {
+			// the matches of each build come together
+			if len(buildIDs) == 0 || buildIDs[len(buildIDs)-1] != match.BuildID {
+				buildIDs = append(buildIDs, match.BuildID)
+			}
+		}
+
+		builds, err := s.buildsDB.GetBuilds(r.Context(), buildIDs)
+		if err != nil {
+			logger.Error("failed-to-get-builds", err)
+			apierror.DBFailure(w, "failed to get builds")
+			return
+		}
+
+		// they're all the team's own builds, so they're censored as they
+		// would be for its members
+		rules := map[int]CensorRule{}
+		for _, build := range builds {
+			rules[build.ID()] = s.censorPolicies.RuleFor(build, true)
+		}
+
+		censored := []db.LogMatch{}
+		for _, match := range matches {
+			rule, found := rules[match.BuildID]
+			if !found {
+				// reaped since it was searched
+				continue
+			}
+
+			keep, err := rule.keepLogMatch(match)
+			if err != nil {
+				logger.Error("failed-to-censor-logs", err)
+				apierror.Internal(w, "failed to censor logs")
+				return
+			}
+
+			if keep {
+				censored = append(censored, match)
+			}
+		}
+
+		writeLogMatches(w, censored)
+	})
+}
+
+// keepLogMatch says whether the matching log event would be left as it is
+// by the rule. Matches that would be censored at all are left out rather
+// than censored, since they may only have matched on what was censored,
+// which would give it away.
+func (rule CensorRule) keepLogMatch(match db.LogMatch) (bool, error) {
+	payload, err := json.Marshal(event.Log{Origin: match.Origin, Payload: match.Payload})
+	if err != nil {
+		return false, err
+	}
+
+	data := json.RawMessage(payload)
+
+	censored, send, err := rule.Censor(event.Envelope{
+		Data:    &data,
+		Event:   event.EventTypeLog,
+		Version: event.Log{}.Version(),
+	})
+	if err != nil || !send {
+		return false, err
+	}
+
+	if censored.Data == nil {
+		return false, nil
+	}
+
+	var log event.Log
+	err = json.Unmarshal(*censored.Data, &log)
+	if err != nil {
+		return false, err
+	}
+
+	return log.Origin == match.Origin && log.Payload == match.Payload, nil
+}
+
+func parseLogSearch(r *http.Request) (string, int, bool) {
+	query := r.FormValue(LogSearchQueryParam)
+	if query == "" {
+		return "", 0, false
+	}
+
+	limit, _ := strconv.Atoi(r.FormValue(atc.PaginationQueryLimit))
+	if limit <= 0 {
+		limit = atc.PaginationAPIDefaultLimit
+	}
+
+	if limit > atc.PaginationAPIMaxLimit {
+		limit = atc.PaginationAPIMaxLimit
+	}
+
+	return query, limit, true
+}
+
+func writeLogMatches(w http.ResponseWriter, matches []db.LogMatch) {
+	presented := make([]atc.BuildLogMatch, len(matches))
+	for i, match := range matches {
+		presented[i] = present.BuildLogMatch(match)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(presented)
+}
@@ -0,0 +1,158 @@
+package buildserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/api/present"
+	"github.com/concourse/atc/cron"
+	"github.com/concourse/atc/db"
+)
+
+func (s *Server) GetMaintenance(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.Session("get-maintenance")
+
+	maintenance, err := s.buildsDB.GetMaintenance(r.Context())
+	if err != nil {
+		logger.Error("failed-to-get-maintenance", err)
+		apierror.DBFailure(w, "failed to get maintenance windows")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	json.NewEncoder(w).Encode(present.Maintenance(maintenance, time.Now()))
+}
+
+// SetMaintenanceWindows replaces every maintenance window with the ones in
+// the request.
+func (s *Server) SetMaintenanceWindows(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.Session("set-maintenance-windows")
+
+	var windows []atc.MaintenanceWindow
+	err := json.NewDecoder(r.Body).Decode(&windows)
+	if err != nil {
+		logger.Info("malformed-request", lager.Data{"error": err.Error()})
+		http.Error(w, "malformed request", http.StatusBadRequest)
+		return
+	}
+
+	dbWindows := make([]db.MaintenanceWindow, len(windows))
+	names := map[string]bool{}
+	for i, window := range windows {
+		if names[window.Name] {
+			http.Error(w, fmt.Sprintf("maintenance window '%s' is given more than once", window.Name), http.StatusBadRequest)
+			return
+		}
+
+		names[window.Name] = true
+
+		dbWindows[i], err = maintenanceWindow(window)
+		if err != nil {
+			logger.Info("invalid-window", lager.Data{"window": window.Name, "error": err.Error()})
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	err = s.buildsDB.SetMaintenanceWindows(r.Context(), dbWindows)
+	if err != nil {
+		logger.Error("failed-to-set-maintenance-windows", err)
+		apierror.DBFailure(w, "failed to set maintenance windows")
+		return
+	}
+
+	logger.Info("set", lager.Data{"windows": len(windows)})
+
+	s.GetMaintenance(w, r)
+}
+
+// OverrideMaintenance lets builds start in spite of maintenance windows until
+// the time in the request, e.g. to ship a fix in the middle of a change
+// freeze.
+func (s *Server) OverrideMaintenance(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.Session("override-maintenance")
+
+	var override atc.MaintenanceOverride
+	err := json.NewDecoder(r.Body).Decode(&override)
+	if err != nil {
+		logger.Info("malformed-request", lager.Data{"error": err.Error()})
+		http.Error(w, "malformed request", http.StatusBadRequest)
+		return
+	}
+
+	until := time.Unix(override.Until, 0)
+	if !until.After(time.Now()) {
+		http.Error(w, "override must last until a time in the future", http.StatusBadRequest)
+		return
+	}
+
+	s.setMaintenanceOverride(logger, until, w, r)
+}
+
+// CancelMaintenanceOverride holds builds again in any maintenance window
+// that's open.
+func (s *Server) CancelMaintenanceOverride(w http.ResponseWriter, r *http.Request) {
+	s.setMaintenanceOverride(s.logger.Session("cancel-maintenance-override"), time.Time{}, w, r)
+}
+
+func (s *Server) setMaintenanceOverride(logger lager.Logger, until time.Time, w http.ResponseWriter, r *http.Request) {
+	err := s.buildsDB.SetMaintenanceOverride(r.Context(), until)
+	if err != nil {
+		logger.Error("failed-to-set-maintenance-override", err)
+		apierror.DBFailure(w, "failed to override maintenance windows")
+		return
+	}
+
+	logger.Info("set", lager.Data{"until": until.String()})
+
+	s.GetMaintenance(w, r)
+}
+
+func maintenanceWindow(window atc.MaintenanceWindow) (db.MaintenanceWindow, error) {
+	if window.Name == "" {
+		return db.MaintenanceWindow{}, errors.New("maintenance windows must have a name")
+	}
+
+	if window.Schedule == "" {
+		if window.Duration != "" {
+			return db.MaintenanceWindow{}, fmt.Errorf("maintenance window '%s' has a duration but no schedule", window.Name)
+		}
+
+		if window.EndTime <= window.StartTime {
+			return db.MaintenanceWindow{}, fmt.Errorf("maintenance window '%s' must end after it starts", window.Name)
+		}
+
+		return db.MaintenanceWindow{
+			Name:     window.Name,
+			StartsAt: time.Unix(window.StartTime, 0),
+			EndsAt:   time.Unix(window.EndTime, 0),
+		}, nil
+	}
+
+	if window.StartTime != 0 || window.EndTime != 0 {
+		return db.MaintenanceWindow{}, fmt.Errorf("maintenance window '%s' can't have both a schedule and start or end times", window.Name)
+	}
+
+	_, err := cron.Parse(window.Schedule)
+	if err != nil {
+		return db.MaintenanceWindow{}, fmt.Errorf("maintenance window '%s' has an invalid schedule: %s", window.Name, err)
+	}
+
+	duration, err := time.ParseDuration(window.Duration)
+	if err != nil || duration < time.Minute {
+		return db.MaintenanceWindow{}, fmt.Errorf("maintenance window '%s' must last for at least a minute", window.Name)
+	}
+
+	return db.MaintenanceWindow{
+		Name:     window.Name,
+		Schedule: window.Schedule,
+		Duration: duration - duration%time.Second,
+	}, nil
+}
@@ -0,0 +1,56 @@
+package buildserver
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+)
+
+func (s *Server) GetGlobalMaxInFlight(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.Session("get-global-max-in-flight")
+
+	maxInFlight, err := s.buildsDB.GetGlobalMaxInFlight(r.Context())
+	if err != nil {
+		logger.Error("failed-to-get-global-max-in-flight", err)
+		apierror.DBFailure(w, "failed to get global max in flight")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	json.NewEncoder(w).Encode(atc.GlobalMaxInFlight{MaxInFlight: maxInFlight})
+}
+
+func (s *Server) SetGlobalMaxInFlight(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.Session("set-global-max-in-flight")
+
+	var limit atc.GlobalMaxInFlight
+	err := json.NewDecoder(r.Body).Decode(&limit)
+	if err != nil {
+		logger.Info("malformed-request", lager.Data{"error": err.Error()})
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if limit.MaxInFlight < 0 {
+		logger.Info("negative-max-in-flight", lager.Data{"max-in-flight": limit.MaxInFlight})
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = s.buildsDB.SetGlobalMaxInFlight(r.Context(), limit.MaxInFlight)
+	if err != nil {
+		logger.Error("failed-to-set-global-max-in-flight", err)
+		apierror.DBFailure(w, "failed to set global max in flight")
+		return
+	}
+
+	logger.Info("set", lager.Data{"max-in-flight": limit.MaxInFlight})
+
+	w.Header().Set("Content-Type", "application/json")
+
+	json.NewEncoder(w).Encode(limit)
+}
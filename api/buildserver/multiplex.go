@@ -0,0 +1,423 @@
+package buildserver
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/auth"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/event"
+	"github.com/concourse/atc/metric"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	MultiplexSubscribe   = "subscribe"
+	MultiplexUnsubscribe = "unsubscribe"
+)
+
+// WebSocketMessageRejected is sent on the multiplexed stream in place of a
+// build's events when they can't be subscribed to.
+const WebSocketMessageRejected = "rejected"
+
+// MultiplexControlMessage is sent by clients of the multiplexed stream to
+// start or stop receiving a build's events.
+type MultiplexControlMessage struct {
+	Type    string `json:"type"`
+	BuildID int    `json:"build_id"`
+
+	// From names the first event to send, as with FromQueryParam.
+	From uint `json:"from,omitempty"`
+}
+
+// MultiplexedMessage is a WebSocketMessage on the multiplexed stream, naming
+// the build it's for. Drain messages are for the whole stream, so have none.
+type MultiplexedMessage struct {
+	BuildID int `json:"build_id,omitempty"`
+
+	WebSocketMessage
+
+	Reason string `json:"reason,omitempty"`
+}
+
+// MultiplexEvents streams the events of any number of builds over one
+// WebSocket, so that e.g. a dashboard doesn't need a connection per build.
+// Builds are subscribed to and unsubscribed from with control messages; each
+// is subject to the same access rules as streaming its events on their own.
+func (s *Server) MultiplexEvents(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.Session("multiplex-build-events")
+
+	s.serveStream(logger, atc.MultiplexEvents, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.serveMultiplexedEvents(logger, w, r)
+	}), w, r)
+}
+
+func (s *Server) serveMultiplexedEvents(logger lager.Logger, w http.ResponseWriter, r *http.Request) {
+	schema, ok := negotiateSchema(r)
+	if !ok {
+		logger.Info("unknown-event-schema", lager.Data{"accept": r.Header.Get("Accept")})
+		w.WriteHeader(http.StatusNotAcceptable)
+		return
+	}
+
+	responseHeader := http.Header{}
+	responseHeader.Add(ProtocolVersionHeader, CurrentProtocolVersion)
+	responseHeader.Add(EventSchemaHeader, strconv.Itoa(int(schema)))
+
+	conn, err := eventsUpgrader.Upgrade(w, r, responseHeader)
+	if err != nil {
+		logger.Error("unable-to-upgrade-connection-for-websockets", err)
+		return
+	}
+
+	metric.ActiveEventStreams.Inc()
+	defer metric.ActiveEventStreams.Dec()
+
+	stream := &multiplexedStream{
+		logger:  logger,
+		server:  s,
+		request: r,
+		conn:    conn,
+		schema:  schema,
+
+		keepAliveTimeout: keepAliveIntervalFrom(r),
+
+		subscriptions: map[int]db.EventSource{},
+	}
+
+	defer stream.close()
+
+	stopSupervising := make(chan struct{})
+	defer close(stopSupervising)
+
+	go superviseStream(r, stream.close, func() {
+		err := stream.write(MultiplexedMessage{
+			WebSocketMessage: WebSocketMessage{Name: WebSocketMessageDrain},
+		})
+		if err != nil {
+			logger.Info("failed-to-write-drain", lager.Data{"error": err.Error()})
+		}
+	}, stream.ping, stopSupervising)
+
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Time{})
+	})
+
+	for {
+		_, reader, err := conn.NextReader()
+		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+				logger.Info("client-unresponsive", lager.Data{"timeout": stream.keepAliveTimeout.String()})
+				metric.ReapedEventStreams.Inc("websocket")
+			}
+
+			break
+		}
+
+		var control MultiplexControlMessage
+		err = json.NewDecoder(reader).Decode(&control)
+		if err != nil {
+			logger.Info("malformed-control-message", lager.Data{"error": err.Error()})
+			continue
+		}
+
+		switch control.Type {
+		case MultiplexSubscribe:
+			stream.subscribe(control.BuildID, control.From)
+		case MultiplexUnsubscribe:
+			stream.unsubscribe(control.BuildID)
+		default:
+			logger.Info("unknown-control-message", lager.Data{"type": control.Type})
+		}
+	}
+
+	stream.close()
+	stream.pumps.Wait()
+}
+
+type multiplexedStream struct {
+	logger  lager.Logger
+	server  *Server
+	request *http.Request
+	conn    *websocket.Conn
+	schema  event.SchemaVersion
+
+	keepAliveTimeout time.Duration
+
+	writeLock sync.Mutex
+
+	// sources are closed by whoever takes them out of here
+	subscriptionsLock sync.Mutex
+	subscriptions     map[int]db.EventSource
+	closed            bool
+
+	pumps     sync.WaitGroup
+	closeOnce sync.Once
+}
+
+func (stream *multiplexedStream) subscribe(buildID int, from uint) {
+	logger := stream.logger.WithData(lager.Data{"build-id": buildID})
+
+	stream.subscriptionsLock.Lock()
+	_, subscribed := stream.subscriptions[buildID]
+	count := len(stream.subscriptions)
+	stream.subscriptionsLock.Unlock()
+
+	if subscribed {
+		return
+	}
+
+	if count >= atc.PaginationAPIMaxLimit {
+		logger.Info("too-many-subscriptions")
+		stream.reject(buildID, "too many subscriptions")
+		return
+	}
+
+	builds, err := stream.server.buildsDB.GetBuilds(stream.request.Context(), []int{buildID})
+	if err != nil {
+		logger.Error("failed-to-get-build", err)
+		stream.reject(buildID, "failed to get build")
+		return
+	}
+
+	if len(builds) == 0 {
+		stream.reject(buildID, "build not found")
+		return
+	}
+
+	build := builds[0]
+
+	authorized, err := canReadBuildEvents(stream.request, build)
+	if err != nil {
+		logger.Error("failed-to-check-access", err)
+		stream.reject(buildID, "failed to check access")
+		return
+	}
+
+	if !authorized {
+		stream.reject(buildID, "not authorized")
+		return
+	}
+
+	authTeam, authTeamFound := auth.GetTeam(stream.request)
+
+	filter := eventFilter{
+		censor: stream.server.censorPolicies.RuleFor(build, authTeamFound && authTeam.IsAuthorized(build.TeamName())),
+		schema: stream.schema,
+	}
+
+	events, err := stream.server.eventHub.SubscribeStream(build, from, "multiplex")
+	if err != nil {
+		logger.Error("failed-to-get-build-events", err)
+		stream.reject(buildID, "failed to get build events")
+		return
+	}
+
+	stream.subscriptionsLock.Lock()
+	if stream.closed {
+		stream.subscriptionsLock.Unlock()
+		events.Close()
+		return
+	}
+
+	stream.subscriptions[buildID] = events
+	stream.pumps.Add(1)
+	stream.subscriptionsLock.Unlock()
+
+	go stream.pump(logger, buildID, from, events, filter)
+}
+
+func (stream *multiplexedStream) unsubscribe(buildID int) {
+	stream.subscriptionsLock.Lock()
+	events, subscribed := stream.subscriptions[buildID]
+	delete(stream.subscriptions, buildID)
+	stream.subscriptionsLock.Unlock()
+
+	if subscribed {
+		events.Close()
+	}
+}
+
+func (stream *multiplexedStream) pump(logger lager.Logger, buildID int, from uint, events db.EventSource, filter eventFilter) {
+	defer stream.pumps.Done()
+
+	defer func() {
+		stream.subscriptionsLock.Lock()
+		current, subscribed := stream.subscriptions[buildID]
+		owned := subscribed && current == events
+		if owned {
+			delete(stream.subscriptions, buildID)
+		}
+		stream.subscriptionsLock.Unlock()
+
+		if owned {
+			events.Close()
+		}
+	}()
+
+	for {
+		ev, err := events.Next()
+		if err != nil {
+			if err == db.ErrEndOfBuildEventStream {
+				err := stream.write(MultiplexedMessage{
+					BuildID:          buildID,
+					WebSocketMessage: WebSocketMessage{ID: from, Name: WebSocketMessageEnd},
+				})
+				if err != nil {
+					logger.Info("failed-to-write-end", lager.Data{"error": err.Error()})
+				}
+			} else if err != db.ErrBuildEventStreamClosed {
+				logger.Error("failed-to-get-next-build-event", err)
+			}
+
+			return
+		}
+
+		if ev.ID != nil {
+			from = *ev.ID
+		}
+
+		ev, send, err := filter.Filter(ev)
+		if err != nil {
+			logger.Error("failed-to-filter-event", err)
+			return
+		}
+
+		if send {
+			err := stream.write(MultiplexedMessage{
+				BuildID: buildID,
+				WebSocketMessage: WebSocketMessage{
+					ID:    from,
+					Name:  WebSocketMessageEvent,
+					Event: &ev,
+				},
+			})
+			if err != nil {
+				logger.Info("failed-to-write-event", lager.Data{"error": err.Error()})
+				stream.close()
+				return
+			}
+		}
+
+		from++
+	}
+}
+
+func (stream *multiplexedStream) reject(buildID int, reason string) {
+	err := stream.write(MultiplexedMessage{
+		BuildID:          buildID,
+		WebSocketMessage: WebSocketMessage{Name: WebSocketMessageRejected},
+		Reason:           reason,
+	})
+	if err != nil {
+		stream.logger.Info("failed-to-write-rejection", lager.Data{"error": err.Error()})
+	}
+}
+
+func (stream *multiplexedStream) ping() {
+	stream.subscriptionsLock.Lock()
+	closed := stream.closed
+	stream.subscriptionsLock.Unlock()
+
+	if closed {
+		return
+	}
+
+	stream.writeLock.Lock()
+	defer stream.writeLock.Unlock()
+
+	// set before pinging, so that the pong can't arrive first
+	stream.conn.SetReadDeadline(stream.deadline())
+
+	err := stream.conn.WriteControl(websocket.PingMessage, nil, stream.deadline())
+	if err != nil {
+		stream.logger.Info("failed-to-write-keepalive", lager.Data{"error": err.Error()})
+		metric.ReapedEventStreams.Inc("websocket")
+		stream.close()
+	}
+}
+
+func (stream *multiplexedStream) write(message MultiplexedMessage) error {
+	stream.writeLock.Lock()
+	defer stream.writeLock.Unlock()
+
+	stream.conn.SetWriteDeadline(stream.deadline())
+
+	return stream.conn.WriteJSON(message)
+}
+
+func (stream *multiplexedStream) deadline() time.Time {
+	if stream.keepAliveTimeout == 0 {
+		return time.Time{}
+	}
+
+	return time.Now().Add(stream.keepAliveTimeout)
+}
+
+// close ends every subscription and the connection, which stops the control
+// messages being read.
+func (stream *multiplexedStream) close() {
+	stream.closeOnce.Do(func() {
+		stream.subscriptionsLock.Lock()
+		stream.closed = true
+		subscriptions := stream.subscriptions
+		stream.subscriptions = map[int]db.EventSource{}
+		stream.subscriptionsLock.Unlock()
+
+		for _, events := range subscriptions {
+			events.Close()
+		}
+
+		stream.conn.Close()
+	})
+}
+
+// canReadBuildEvents mirrors the access rules for a single build's events:
+// the authorized team can read any of its builds that its role lets it view,
+// and anyone can read those of public jobs in public pipelines.
+func canReadBuildEvents(r *http.Request, build db.Build) (bool, error) {
+	authTeam, authTeamFound := auth.GetTeam(r)
+	if auth.IsAuthenticated(r) && authTeamFound && authTeam.IsAuthorized(build.TeamName()) {
+		if _, hasRole := auth.GetRole(r); !hasRole || build.IsOneOff() {
+			return true, nil
+		}
+
+		pipeline, err := build.GetPipeline()
+		if err != nil {
+			return false, err
+		}
+
+		if pipeline.Public {
+			return true, nil
+		}
+
+		_, allowed := auth.HasPipelinePermission(r, pipeline.Permissions, atc.PipelinePermissionView)
+		return allowed, nil
+	}
+
+	if build.IsOneOff() {
+		return false, nil
+	}
+
+	pipeline, err := build.GetPipeline()
+	if err != nil {
+		return false, err
+	}
+
+	if !pipeline.Public {
+		return false, nil
+	}
+
+	config, _, err := build.GetConfig()
+	if err != nil {
+		return false, err
+	}
+
+	return config.JobIsPublic(build.JobName())
+}
@@ -0,0 +1,322 @@
+package buildserver_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"time"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	"github.com/concourse/atc"
+	. "github.com/concourse/atc/api/buildserver"
+	"github.com/concourse/atc/api/buildserver/buildserverfakes"
+	"github.com/concourse/atc/auth"
+	"github.com/concourse/atc/auth/authfakes"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/db/dbfakes"
+	"github.com/concourse/atc/event"
+	"github.com/gorilla/websocket"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Multiplexed event streams", func() {
+	var (
+		buildsDB        *buildserverfakes.FakeBuildsDB
+		build           *dbfakes.FakeBuild
+		fakeEventSource *dbfakes.FakeEventSource
+		closed          chan struct{}
+
+		authValidator     *authfakes.FakeValidator
+		userContextReader *authfakes.FakeUserContextReader
+
+		server *httptest.Server
+		conn   *websocket.Conn
+	)
+
+	BeforeEach(func() {
+		buildsDB = new(buildserverfakes.FakeBuildsDB)
+
+		authValidator = new(authfakes.FakeValidator)
+		userContextReader = new(authfakes.FakeUserContextReader)
+
+		build = new(dbfakes.FakeBuild)
+		build.IDReturns(42)
+		build.TeamNameReturns("some-team")
+		build.JobNameReturns("some-job")
+		build.GetPipelineReturns(db.SavedPipeline{Public: true}, nil)
+		build.GetConfigReturns(atc.Config{
+			Jobs: atc.JobConfigs{
+				{Name: "some-job", Public: true},
+			},
+		}, 1, nil)
+
+		buildsDB.GetBuildsReturns([]db.Build{build}, nil)
+
+		fakeEventSource = new(dbfakes.FakeEventSource)
+
+		closed = make(chan struct{})
+		fakeEventSource.CloseStub = func() error {
+			close(closed)
+			return nil
+		}
+
+		returnedEvents := 0
+		fakeEventSource.NextStub = func() (event.Envelope, error) {
+			returnedEvents++
+
+			switch returnedEvents {
+			case 1:
+				return fakeEvent(`{"event":1}`), nil
+			case 2:
+				return fakeEvent(`{"event":2}`), nil
+			default:
+				<-closed
+				return event.Envelope{}, db.ErrBuildEventStreamClosed
+			}
+		}
+
+		build.EventsReturns(fakeEventSource, nil)
+	})
+
+	JustBeforeEach(func() {
+		buildServer := NewServer(
+			lagertest.NewTestLogger("test"),
+			"http://example.com",
+			nil,
+			nil,
+			nil,
+			buildsDB,
+			NewEventHandler,
+			CensorPolicies{},
+			nil,
+			make(chan struct{}),
+			time.Second,
+			KeepAlivePolicy{},
+			nil,
+			nil,
+		)
+
+		server = httptest.NewServer(auth.WrapHandler(
+			http.HandlerFunc(buildServer.MultiplexEvents),
+			authValidator,
+			userContextReader,
+		))
+
+		wsURL, err := url.Parse(server.URL)
+		Expect(err).NotTo(HaveOccurred())
+
+		wsURL.Scheme = "ws"
+
+		conn, _, err = websocket.DefaultDialer.Dial(wsURL.String(), nil)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		conn.Close()
+		server.Close()
+	})
+
+	subscribe := func(buildID int) {
+		err := conn.WriteJSON(MultiplexControlMessage{Type: MultiplexSubscribe, BuildID: buildID})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	readMessage := func() MultiplexedMessage {
+		var msg MultiplexedMessage
+		err := conn.ReadJSON(&msg)
+		Expect(err).NotTo(HaveOccurred())
+		return msg
+	}
+
+	It("streams the events of subscribed builds, naming the build", func() {
+		subscribe(42)
+
+		Expect(readMessage()).To(Equal(MultiplexedMessage{
+			BuildID: 42,
+			WebSocketMessage: WebSocketMessage{
+				ID:    0,
+				Name:  WebSocketMessageEvent,
+				Event: envelope(fakeEvent(`{"event":1}`)),
+			},
+		}))
+
+		Expect(readMessage()).To(Equal(MultiplexedMessage{
+			BuildID: 42,
+			WebSocketMessage: WebSocketMessage{
+				ID:    1,
+				Name:  WebSocketMessageEvent,
+				Event: envelope(fakeEvent(`{"event":2}`)),
+			},
+		}))
+
+		_, buildIDs := buildsDB.GetBuildsArgsForCall(0)
+		Expect(buildIDs).To(Equal([]int{42}))
+	})
+
+	It("stops streaming a build's events once it's unsubscribed from", func() {
+		subscribe(42)
+
+		readMessage()
+		readMessage()
+
+		err := conn.WriteJSON(MultiplexControlMessage{Type: MultiplexUnsubscribe, BuildID: 42})
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(closed).Should(BeClosed())
+	})
+
+	It("closes every subscription when the client goes away", func() {
+		subscribe(42)
+
+		readMessage()
+		readMessage()
+
+		conn.Close()
+
+		Eventually(closed).Should(BeClosed())
+	})
+
+	Context("when the build is done", func() {
+		BeforeEach(func() {
+			fakeEventSource.NextReturns(event.Envelope{}, db.ErrEndOfBuildEventStream)
+			fakeEventSource.NextStub = nil
+		})
+
+		It("says so", func() {
+			subscribe(42)
+
+			Expect(readMessage()).To(Equal(MultiplexedMessage{
+				BuildID:          42,
+				WebSocketMessage: WebSocketMessage{Name: WebSocketMessageEnd},
+			}))
+		})
+	})
+
+	Context("when the build does not exist", func() {
+		BeforeEach(func() {
+			buildsDB.GetBuildsReturns([]db.Build{}, nil)
+		})
+
+		It("rejects the subscription", func() {
+			subscribe(42)
+
+			Expect(readMessage()).To(Equal(MultiplexedMessage{
+				BuildID:          42,
+				WebSocketMessage: WebSocketMessage{Name: WebSocketMessageRejected},
+				Reason:           "build not found",
+			}))
+		})
+	})
+
+	Context("when the build is not readable without authorization", func() {
+		BeforeEach(func() {
+			build.GetPipelineReturns(db.SavedPipeline{Public: false}, nil)
+		})
+
+		It("rejects the subscription without streaming anything", func() {
+			subscribe(42)
+
+			Expect(readMessage()).To(Equal(MultiplexedMessage{
+				BuildID:          42,
+				WebSocketMessage: WebSocketMessage{Name: WebSocketMessageRejected},
+				Reason:           "not authorized",
+			}))
+
+			Expect(build.EventsCallCount()).To(BeZero())
+		})
+	})
+
+	Context("when the request is from the build's team", func() {
+		BeforeEach(func() {
+			authValidator.IsAuthenticatedReturns(true)
+			userContextReader.GetTeamReturns("some-team", 1, false, true)
+			build.GetPipelineReturns(db.SavedPipeline{
+				Public:      false,
+				Permissions: atc.PipelinePermissions{View: atc.RoleOperator},
+			}, nil)
+		})
+
+		Context("with a role the pipeline lets view it", func() {
+			BeforeEach(func() {
+				userContextReader.GetRoleReturns(atc.RoleOperator, true)
+			})
+
+			It("streams the events", func() {
+				subscribe(42)
+
+				Expect(readMessage().Name).To(Equal(WebSocketMessageEvent))
+			})
+		})
+
+		Context("with a role the pipeline doesn't let view it", func() {
+			BeforeEach(func() {
+				userContextReader.GetRoleReturns(atc.RoleViewer, true)
+			})
+
+			It("rejects the subscription", func() {
+				subscribe(42)
+
+				Expect(readMessage()).To(Equal(MultiplexedMessage{
+					BuildID:          42,
+					WebSocketMessage: WebSocketMessage{Name: WebSocketMessageRejected},
+					Reason:           "not authorized",
+				}))
+
+				Expect(build.EventsCallCount()).To(BeZero())
+			})
+		})
+
+		Context("without a role", func() {
+			It("streams the events", func() {
+				subscribe(42)
+
+				Expect(readMessage().Name).To(Equal(WebSocketMessageEvent))
+			})
+		})
+	})
+
+	Context("when the build is of a private job", func() {
+		BeforeEach(func() {
+			build.GetConfigReturns(atc.Config{
+				Jobs: atc.JobConfigs{
+					{Name: "some-job", Public: false},
+				},
+			}, 1, nil)
+		})
+
+		It("rejects the subscription", func() {
+			subscribe(42)
+
+			Expect(readMessage().Name).To(Equal(WebSocketMessageRejected))
+		})
+	})
+
+	Context("when looking up the build fails", func() {
+		BeforeEach(func() {
+			buildsDB.GetBuildsReturns(nil, errors.New("nope"))
+		})
+
+		It("rejects the subscription and keeps the stream open for others", func() {
+			subscribe(42)
+
+			Expect(readMessage()).To(Equal(MultiplexedMessage{
+				BuildID:          42,
+				WebSocketMessage: WebSocketMessage{Name: WebSocketMessageRejected},
+				Reason:           "failed to get build",
+			}))
+
+			buildsDB.GetBuildsReturns([]db.Build{build}, nil)
+
+			subscribe(42)
+
+			Expect(readMessage().Name).To(Equal(WebSocketMessageEvent))
+		})
+	})
+})
+
+func envelope(ev event.Envelope) *event.Envelope {
+	return &ev
+}
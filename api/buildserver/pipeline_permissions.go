@@ -0,0 +1,50 @@
+package buildserver
+
+import (
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/auth"
+	"github.com/concourse/atc/db"
+)
+
+// checkPipelinePermission rejects the request if the build's pipeline needs
+// more of a role for it than it was made with. One-off builds have no
+// pipeline, and requests without a role are never held back, so neither
+// needs the pipeline looking up.
+func checkPipelinePermission(logger lager.Logger, w http.ResponseWriter, r *http.Request, build db.Build, permission atc.PipelinePermission) bool {
+	if build.IsOneOff() {
+		return true
+	}
+
+	if _, hasRole := auth.GetRole(r); !hasRole {
+		return true
+	}
+
+	pipeline, err := build.GetPipeline()
+	if err != nil {
+		logger.Error("failed-to-get-pipeline", err)
+		apierror.DBFailure(w, "failed to get pipeline")
+		return false
+	}
+
+	return auth.CheckPipelinePermission(w, r, pipeline.Permissions, permission)
+}
+
+// hiddenPipelineIDs returns the team's pipelines whose builds the request's
+// role isn't enough to see. Requests without a role can see all of them, so
+// the pipelines are only looked up for those with one.
+func hiddenPipelineIDs(r *http.Request, teamDB db.TeamDB) ([]int, error) {
+	if _, hasRole := auth.GetRole(r); !hasRole {
+		return nil, nil
+	}
+
+	pipelines, err := teamDB.GetPipelines()
+	if err != nil {
+		return nil, err
+	}
+
+	return auth.HiddenPipelineIDs(r, pipelines), nil
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/api/apierror"
 	"github.com/concourse/atc/db"
 )
 
@@ -11,20 +13,30 @@ func (s *Server) GetBuildPlan(build db.Build) http.Handler {
 	hLog := s.logger.Session("get-build-plan")
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the plan is saved along with the engine when the build starts, so
+		// builds that are still pending don't have one yet
+		if build.Engine() == "" {
+			hLog.Info("build-not-started", lager.Data{"build": build.ID(), "status": build.Status()})
+			http.Error(w, "build has not started yet", http.StatusNotFound)
+			return
+		}
+
 		engineBuild, err := s.engine.LookupBuild(hLog, build)
 		if err != nil {
 			hLog.Error("failed-to-lookup-build", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.BuilderFailure(w, "failed to lookup build")
 			return
 		}
 
 		plan, err := engineBuild.PublicPlan(hLog)
 		if err != nil {
 			hLog.Error("failed-to-generate-plan", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.BuilderFailure(w, "failed to generate plan")
 			return
 		}
 
+		w.Header().Set("Content-Type", "application/json")
+
 		json.NewEncoder(w).Encode(plan)
 	})
 }
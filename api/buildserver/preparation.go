@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/api/apierror"
 	"github.com/concourse/atc/api/present"
 	"github.com/concourse/atc/db"
 )
@@ -16,12 +17,12 @@ func (s *Server) GetBuildPreparation(build db.Build) http.Handler {
 		prep, found, err := build.GetPreparation()
 		if err != nil {
 			log.Error("cannot-find-build-preparation", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.DBFailure(w, "failed to get build preparation")
 			return
 		}
 
 		if !found {
-			w.WriteHeader(http.StatusNotFound)
+			apierror.NotFound(w, "build preparation not found")
 			return
 		}
 
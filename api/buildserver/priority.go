@@ -0,0 +1,59 @@
+package buildserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/api/present"
+	"github.com/concourse/atc/db"
+)
+
+// PriorityQueryParam sets the priority of a build being created. Pending
+// builds with a higher priority are scheduled before those with a lower one.
+const PriorityQueryParam = "priority"
+
+func parsePriority(r *http.Request) (int, error) {
+	value := r.URL.Query().Get(PriorityQueryParam)
+	if value == "" {
+		return 0, nil
+	}
+
+	return strconv.Atoi(value)
+}
+
+func (s *Server) SetBuildPriority(build db.Build) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger := s.logger.Session("set-build-priority", lager.Data{
+			"build": build.ID(),
+		})
+
+		var priority atc.BuildPriority
+		err := json.NewDecoder(r.Body).Decode(&priority)
+		if err != nil {
+			logger.Info("malformed-request", lager.Data{"error": err.Error()})
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		updated, err := build.SetPriority(priority.Priority)
+		if err != nil {
+			logger.Error("failed-to-set-priority", err)
+			apierror.DBFailure(w, "failed to set priority")
+			return
+		}
+
+		if !updated {
+			logger.Info("build-no-longer-pending", lager.Data{"status": build.Status()})
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		json.NewEncoder(w).Encode(present.Build(build))
+	})
+}
@@ -0,0 +1,41 @@
+package buildserver
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+)
+
+func (s *Server) GetBuildReaperStatus(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.Session("get-build-reaper-status")
+
+	lastReapTime, found, err := s.buildsDB.GetLastBuildReapTime(r.Context())
+	if err != nil {
+		logger.Error("failed-to-get-last-build-reap-time", err)
+		apierror.DBFailure(w, "failed to get last build reap time")
+		return
+	}
+
+	lastPrune, pruned, err := s.buildsDB.GetLastBuildPrune(r.Context())
+	if err != nil {
+		logger.Error("failed-to-get-last-build-prune", err)
+		apierror.DBFailure(w, "failed to get last build prune")
+		return
+	}
+
+	status := atc.BuildReaperStatus{}
+	if found {
+		status.LastReapTime = lastReapTime.Unix()
+	}
+
+	if pruned {
+		status.LastPruneTime = lastPrune.PrunedAt.Unix()
+		status.PrunedBuilds = lastPrune.Builds
+	}
+
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(status)
+}
@@ -0,0 +1,25 @@
+package buildserver
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+)
+
+// ReconcileBuilds errors builds that have been left running with nothing
+// tracking them right away, rather than waiting for the next pass.
+func (s *Server) ReconcileBuilds(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.Session("reconcile-builds")
+
+	errored, err := s.reconciler.Reconcile(logger)
+	if err != nil {
+		apierror.Internal(w, "failed to reconcile builds: "+err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	json.NewEncoder(w).Encode(atc.BuildReconciliation{ErroredBuilds: errored})
+}
@@ -0,0 +1,44 @@
+package buildserver
+
+import (
+	"context"
+	"time"
+
+	"github.com/concourse/atc/event"
+)
+
+// ReplayQueryParam, when set to ReplayRealtime, paces the stream using the
+// times the events were saved at, so that a finished build can be watched
+// as it originally ran.
+const ReplayQueryParam = "replay"
+const ReplayRealtime = "realtime"
+
+type replayPacer struct {
+	last time.Time
+}
+
+// Wait blocks until the event is due, relative to the last event it was
+// given. Events saved before times were recorded are never waited for. It
+// returns false if the context is done first.
+func (pacer *replayPacer) Wait(ctx context.Context, ev event.Envelope) bool {
+	if pacer == nil || ev.Time.IsZero() {
+		return true
+	}
+
+	last := pacer.last
+	pacer.last = ev.Time
+
+	if last.IsZero() || !ev.Time.After(last) {
+		return true
+	}
+
+	timer := time.NewTimer(ev.Time.Sub(last))
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
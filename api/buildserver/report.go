@@ -0,0 +1,146 @@
+package buildserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/buildreport"
+	"github.com/concourse/atc/db"
+)
+
+// FormatQueryParam is the format to write the report in, one of
+// buildreport.Formats. It defaults to buildreport.DefaultFormat.
+const FormatQueryParam = "format"
+
+// GetBuildReport reports how each of the build's steps went and how long it
+// took, in a format for other tools to read, e.g. ?format=junit.
+func (s *Server) GetBuildReport(build db.Build) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger := s.logger.Session("get-build-report", lager.Data{"build-id": build.ID()})
+
+		formatName := r.FormValue(FormatQueryParam)
+		if formatName == "" {
+			formatName = buildreport.DefaultFormat
+		}
+
+		format, found := buildreport.Formats[formatName]
+		if !found {
+			logger.Info("unknown-format", lager.Data{"format": formatName})
+			http.Error(w, "unknown report format", http.StatusBadRequest)
+			return
+		}
+
+		steps, err := build.GetSteps()
+		if err != nil {
+			logger.Error("failed-to-get-steps", err)
+			apierror.DBFailure(w, "failed to get steps")
+			return
+		}
+
+		names := s.stepNames(logger, build)
+
+		report := buildreport.Build{
+			ID:           build.ID(),
+			Name:         build.Name(),
+			TeamName:     build.TeamName(),
+			PipelineName: build.PipelineName(),
+			JobName:      build.JobName(),
+			Status:       string(build.Status()),
+			StartTime:    build.StartTime(),
+			EndTime:      build.EndTime(),
+			Steps:        make([]buildreport.Step, len(steps)),
+		}
+
+		for i, step := range steps {
+			report.Steps[i] = buildreport.Step{
+				ID:        string(step.PlanID),
+				Type:      step.Type,
+				Name:      names[string(step.PlanID)],
+				Status:    string(step.Status),
+				StartTime: step.StartedAt,
+				EndTime:   step.FinishedAt,
+			}
+		}
+
+		buf := new(bytes.Buffer)
+
+		err = format.Write(buf, report)
+		if err != nil {
+			logger.Error("failed-to-write-report", err, lager.Data{"format": formatName})
+			apierror.Internal(w, "failed to write report")
+			return
+		}
+
+		w.Header().Set("Content-Type", format.ContentType())
+		w.WriteHeader(http.StatusOK)
+		buf.WriteTo(w)
+	})
+}
+
+// stepNames looks up what the build's plan calls each of its steps, by plan
+// ID. Builds that haven't started don't have a plan yet, and failing to look
+// it up only leaves the steps unnamed.
+func (s *Server) stepNames(logger lager.Logger, build db.Build) map[string]string {
+	names := map[string]string{}
+
+	if build.Engine() == "" {
+		return names
+	}
+
+	engineBuild, err := s.engine.LookupBuild(logger, build)
+	if err != nil {
+		logger.Error("failed-to-lookup-build", err)
+		return names
+	}
+
+	plan, err := engineBuild.PublicPlan(logger)
+	if err != nil {
+		logger.Error("failed-to-generate-plan", err)
+		return names
+	}
+
+	if plan.Plan == nil {
+		return names
+	}
+
+	var tree interface{}
+	err = json.Unmarshal(*plan.Plan, &tree)
+	if err != nil {
+		logger.Error("failed-to-parse-plan", err)
+		return names
+	}
+
+	collectStepNames(tree, names)
+
+	return names
+}
+
+func collectStepNames(node interface{}, names map[string]string) {
+	switch node := node.(type) {
+	case map[string]interface{}:
+		if id, ok := node["id"].(string); ok {
+			for _, stepType := range []string{"get", "put", "task", "dependent_get", "approval"} {
+				step, ok := node[stepType].(map[string]interface{})
+				if !ok {
+					continue
+				}
+
+				if name, ok := step["name"].(string); ok {
+					names[id] = name
+				}
+			}
+		}
+
+		for _, child := range node {
+			collectStepNames(child, names)
+		}
+
+	case []interface{}:
+		for _, child := range node {
+			collectStepNames(child, names)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package buildserver
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/api/present"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/engine"
+	"github.com/concourse/atc/requestlog"
+)
+
+func (s *Server) RerunBuild(build db.Build) http.Handler {
+	hLog := s.logger.Session("rerun-build", lager.Data{
+		"build": build.ID(),
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hLog := requestlog.Logger(hLog, r)
+
+		if !checkPipelinePermission(hLog, w, r, build, atc.PipelinePermissionTrigger) {
+			return
+		}
+
+		if build.Engine() == "" {
+			hLog.Info("build-not-started", lager.Data{"status": build.Status()})
+			http.Error(w, "build has not started yet", http.StatusConflict)
+			return
+		}
+
+		engineBuild, err := s.engine.LookupBuild(hLog, build)
+		if err != nil {
+			hLog.Error("failed-to-lookup-build", err)
+			apierror.BuilderFailure(w, "failed to lookup build")
+			return
+		}
+
+		plan, err := engineBuild.Plan(hLog)
+		if err == engine.ErrPlanUnavailable {
+			hLog.Info("plan-unavailable")
+			http.Error(w, "build cannot be rerun", http.StatusUnprocessableEntity)
+			return
+		}
+
+		if err != nil {
+			hLog.Error("failed-to-get-plan", err)
+			apierror.BuilderFailure(w, "failed to get plan")
+			return
+		}
+
+		inputs, _, err := build.GetResources()
+		if err != nil {
+			hLog.Error("failed-to-get-resources", err)
+			apierror.DBFailure(w, "failed to get resources")
+			return
+		}
+
+		plan = pinInputs(plan, inputs)
+
+		teamDB := s.teamDBFactory.GetTeamDB(build.TeamName())
+
+		rerun, err := teamDB.CreateRerunBuild(build)
+		if err != nil {
+			hLog.Error("failed-to-create-rerun-build", err)
+			apierror.DBFailure(w, "failed to create rerun build")
+			return
+		}
+
+		if labels := build.Labels(); len(labels) > 0 {
+			err = rerun.SaveLabels(labels)
+			if err != nil {
+				hLog.Error("failed-to-save-labels", err)
+				apierror.DBFailure(w, "failed to save labels")
+				return
+			}
+		}
+
+		// the plan already has credentials interpolated, so the rerun needs
+		// to know what to redact from its events just the same
+		secrets, err := build.GetSecrets()
+		if err != nil {
+			hLog.Error("failed-to-get-secrets", err)
+			apierror.DBFailure(w, "failed to get secrets")
+			return
+		}
+
+		if len(secrets) > 0 {
+			err = rerun.SaveSecrets(secrets)
+			if err != nil {
+				hLog.Error("failed-to-save-secrets", err)
+				apierror.DBFailure(w, "failed to save secrets")
+				return
+			}
+		}
+
+		rerunEngineBuild, err := s.engine.CreateBuild(hLog, rerun, plan)
+		if err != nil {
+			hLog.Error("failed-to-start-build", err)
+			apierror.BuilderFailure(w, "failed to start build")
+			return
+		}
+
+		go rerunEngineBuild.Resume(hLog)
+
+		found, err := rerun.Reload()
+		if err != nil {
+			hLog.Error("failed-to-reload-build", err)
+			apierror.DBFailure(w, "failed to reload build")
+			return
+		}
+
+		if !found {
+			hLog.Info("build-disappeared", lager.Data{"build-id": rerun.ID()})
+			apierror.DBFailure(w, "build disappeared")
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+
+		json.NewEncoder(w).Encode(present.Build(rerun))
+	})
+}
+
+// pinInputs fixes every get step that was left to fetch the latest version
+// to the version the original build ended up with, so that a rerun sees the
+// same inputs even if the resource has moved on since.
+func pinInputs(plan atc.Plan, inputs []db.BuildInput) atc.Plan {
+	versions := map[string]atc.Version{}
+	for _, input := range inputs {
+		versions[input.Name] = atc.Version(input.Version)
+	}
+
+	return pinPlanInputs(plan, versions)
+}
+
+func pinPlanInputs(plan atc.Plan, versions map[string]atc.Version) atc.Plan {
+	pinAll := func(plans []atc.Plan) []atc.Plan {
+		pinned := make([]atc.Plan, len(plans))
+		for i, p := range plans {
+			pinned[i] = pinPlanInputs(p, versions)
+		}
+
+		return pinned
+	}
+
+	switch {
+	case plan.Get != nil:
+		get := *plan.Get
+
+		name := get.Name
+		if name == "" {
+			name = get.Resource
+		}
+
+		if version, found := versions[name]; found && get.Version == nil {
+			get.Version = version
+		}
+
+		plan.Get = &get
+
+	case plan.Aggregate != nil:
+		aggregate := atc.AggregatePlan(pinAll(*plan.Aggregate))
+		plan.Aggregate = &aggregate
+
+	case plan.Do != nil:
+		do := atc.DoPlan(pinAll(*plan.Do))
+		plan.Do = &do
+
+	case plan.Retry != nil:
+		retry := atc.RetryPlan(pinAll(*plan.Retry))
+		plan.Retry = &retry
+
+	case plan.OnSuccess != nil:
+		plan.OnSuccess = &atc.OnSuccessPlan{
+			Step: pinPlanInputs(plan.OnSuccess.Step, versions),
+			Next: pinPlanInputs(plan.OnSuccess.Next, versions),
+		}
+
+	case plan.OnFailure != nil:
+		plan.OnFailure = &atc.OnFailurePlan{
+			Step: pinPlanInputs(plan.OnFailure.Step, versions),
+			Next: pinPlanInputs(plan.OnFailure.Next, versions),
+		}
+
+	case plan.Ensure != nil:
+		plan.Ensure = &atc.EnsurePlan{
+			Step: pinPlanInputs(plan.Ensure.Step, versions),
+			Next: pinPlanInputs(plan.Ensure.Next, versions),
+		}
+
+	case plan.Try != nil:
+		plan.Try = &atc.TryPlan{
+			Step: pinPlanInputs(plan.Try.Step, versions),
+		}
+
+	case plan.Timeout != nil:
+		plan.Timeout = &atc.TimeoutPlan{
+			Step:     pinPlanInputs(plan.Timeout.Step, versions),
+			Duration: plan.Timeout.Duration,
+		}
+	}
+
+	return plan
+}
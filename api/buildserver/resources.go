@@ -6,6 +6,7 @@ import (
 
 	"code.cloudfoundry.org/lager"
 	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
 	"github.com/concourse/atc/api/present"
 	"github.com/concourse/atc/db"
 )
@@ -17,7 +18,7 @@ func (s *Server) BuildResources(build db.Build) http.Handler {
 		inputs, outputs, err := build.GetResources()
 		if err != nil {
 			log.Error("cannot-find-build", err, lager.Data{"buildID": r.FormValue(":build_id")})
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.DBFailure(w, "failed to get build resources")
 			return
 		}
 
@@ -0,0 +1,44 @@
+package buildserver
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/concourse/atc/event"
+)
+
+// EventSchemaHeader is set on event streams to the schema version the events
+// were written in.
+const EventSchemaHeader = "X-ATC-Event-Schema"
+
+// negotiateSchema picks the event schema version to stream in based on the
+// schema parameter of the request's Accept header, e.g.
+// "text/event-stream; schema=1". Clients that don't ask for a version get the
+// current one, as do clients asking for a version newer than this ATC knows
+// about. It returns false if the requested version is malformed.
+func negotiateSchema(r *http.Request) (event.SchemaVersion, bool) {
+	for _, mediaRange := range strings.Split(r.Header.Get("Accept"), ",") {
+		params := strings.Split(mediaRange, ";")
+
+		for _, param := range params[1:] {
+			param = strings.TrimSpace(param)
+			if !strings.HasPrefix(param, "schema=") {
+				continue
+			}
+
+			version, err := strconv.Atoi(strings.Trim(param[len("schema="):], `"`))
+			if err != nil || version < int(event.SchemaV1) {
+				return 0, false
+			}
+
+			if version > int(event.CurrentSchemaVersion) {
+				return event.CurrentSchemaVersion, true
+			}
+
+			return event.SchemaVersion(version), true
+		}
+	}
+
+	return event.CurrentSchemaVersion, true
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/api/apierror"
 	"github.com/concourse/atc/auth"
 	"github.com/concourse/atc/db"
 )
@@ -28,7 +29,7 @@ func (f *scopedHandlerFactory) HandlerFor(buildScopedHandler func(db.Build) http
 		build, ok := r.Context().Value(auth.BuildKey).(db.Build)
 		if !ok {
 			logger.Error("build-is-not-in-context", errors.New("build-is-not-in-context"))
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.Internal(w, "build is not in context")
 			return
 		}
 
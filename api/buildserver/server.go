@@ -1,6 +1,7 @@
 package buildserver
 
 import (
+	"context"
 	"net/http"
 	"time"
 
@@ -8,6 +9,7 @@ import (
 	"github.com/concourse/atc/auth"
 	"github.com/concourse/atc/db"
 	"github.com/concourse/atc/engine"
+	"github.com/concourse/atc/event"
 	"github.com/concourse/atc/worker"
 )
 
@@ -16,7 +18,28 @@ type EventHandlerFactory func(lager.Logger, db.Build) http.Handler
 //go:generate counterfeiter . BuildsDB
 
 type BuildsDB interface {
-	GetPublicBuilds(page db.Page) ([]db.Build, db.Pagination, error)
+	CreateOneOffBuild(ctx context.Context, teamName string, spec db.OneOffBuildSpec) (db.Build, error)
+	GetPublicBuilds(ctx context.Context, page db.Page, filter db.BuildFilter) ([]db.Build, db.Pagination, error)
+	GetBuilds(ctx context.Context, buildIDs []int) ([]db.Build, error)
+	GetLastBuildReapTime(ctx context.Context) (time.Time, bool, error)
+	GetLastBuildPrune(ctx context.Context) (db.BuildPrune, bool, error)
+	GetBuildEventsFrom(ctx context.Context, buildID int, offset uint, limit int) ([]event.Envelope, error)
+	GetResourceVersionsBetween(ctx context.Context, pipelineID int, resourceName string, after db.Version, upTo db.Version, limit int) ([]db.SavedVersionedResource, error)
+
+	GetGlobalMaxInFlight(ctx context.Context) (int, error)
+	SetGlobalMaxInFlight(ctx context.Context, maxInFlight int) error
+
+	GetMaintenance(ctx context.Context) (db.Maintenance, error)
+	SetMaintenanceWindows(ctx context.Context, windows []db.MaintenanceWindow) error
+	SetMaintenanceOverride(ctx context.Context, until time.Time) error
+}
+
+//go:generate counterfeiter . BuildReconciler
+
+// BuildReconciler errors builds that have been left running with nothing
+// tracking them, returning their IDs.
+type BuildReconciler interface {
+	Reconcile(logger lager.Logger) ([]int, error)
 }
 
 type Server struct {
@@ -29,7 +52,13 @@ type Server struct {
 	teamDBFactory       db.TeamDBFactory
 	buildsDB            BuildsDB
 	eventHandlerFactory EventHandlerFactory
+	censorPolicies      CensorPolicies
+	eventHub            *EventHub
 	drain               <-chan struct{}
+	drainGracePeriod    time.Duration
+	keepAlives          KeepAlivePolicy
+	artifactStore       ArtifactStore
+	reconciler          BuildReconciler
 	rejector            auth.Rejector
 
 	httpClient *http.Client
@@ -43,7 +72,14 @@ func NewServer(
 	teamDBFactory db.TeamDBFactory,
 	buildsDB BuildsDB,
 	eventHandlerFactory EventHandlerFactory,
+	censorPolicies CensorPolicies,
+	eventHub *EventHub,
 	drain <-chan struct{},
+	drainGracePeriod time.Duration,
+	keepAlives KeepAlivePolicy,
+	artifactStore ArtifactStore,
+	reconciler BuildReconciler,
+	httpClient *http.Client,
 ) *Server {
 	return &Server{
 		logger: logger,
@@ -55,14 +91,16 @@ func NewServer(
 		teamDBFactory:       teamDBFactory,
 		buildsDB:            buildsDB,
 		eventHandlerFactory: eventHandlerFactory,
+		censorPolicies:      censorPolicies,
+		eventHub:            eventHub,
 		drain:               drain,
+		drainGracePeriod:    drainGracePeriod,
+		keepAlives:          keepAlives,
+		artifactStore:       artifactStore,
+		reconciler:          reconciler,
 
 		rejector: auth.UnauthorizedRejector{},
 
-		httpClient: &http.Client{
-			Transport: &http.Transport{
-				ResponseHeaderTimeout: 5 * time.Minute,
-			},
-		},
+		httpClient: httpClient,
 	}
 }
@@ -0,0 +1,96 @@
+package buildserver
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/api/present"
+	"github.com/concourse/atc/auth"
+	"github.com/concourse/atc/db"
+)
+
+func (s *Server) GetBuildStatuses(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.Session("get-build-statuses")
+
+	var buildIDs []int
+	err := json.NewDecoder(r.Body).Decode(&buildIDs)
+	if err != nil {
+		logger.Info("malformed-request", lager.Data{"error": err.Error()})
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if len(buildIDs) > atc.PaginationAPIMaxLimit {
+		logger.Info("too-many-builds-requested", lager.Data{"count": len(buildIDs)})
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	builds, err := s.buildsDB.GetBuilds(r.Context(), buildIDs)
+	if err != nil {
+		logger.Error("failed-to-get-builds", err)
+		apierror.DBFailure(w, "failed to get builds")
+		return
+	}
+
+	authTeam, authTeamFound := auth.GetTeam(r)
+	_, hasRole := auth.GetRole(r)
+
+	pipelines := map[string]db.SavedPipeline{}
+
+	presentedBuilds := []atc.Build{}
+	for _, build := range builds {
+		member := authTeamFound && authTeam.IsAuthorized(build.TeamName())
+
+		// mirror the read access rules for individual builds: anything
+		// outside of the authorized team is only visible if it belongs to a
+		// public pipeline, and the team's own private pipelines may need more
+		// of a role to view than the request was made with
+		if build.IsOneOff() {
+			if member {
+				presentedBuilds = append(presentedBuilds, present.Build(build))
+			}
+
+			continue
+		}
+
+		if member && !hasRole {
+			presentedBuilds = append(presentedBuilds, present.Build(build))
+			continue
+		}
+
+		pipelineKey := build.TeamName() + "/" + build.PipelineName()
+
+		pipeline, cached := pipelines[pipelineKey]
+		if !cached {
+			var err error
+			pipeline, err = build.GetPipeline()
+			if err != nil {
+				logger.Error("failed-to-get-pipeline", err, lager.Data{"build-id": build.ID()})
+				apierror.DBFailure(w, "failed to get pipeline")
+				return
+			}
+
+			pipelines[pipelineKey] = pipeline
+		}
+
+		if !pipeline.Public {
+			if !member {
+				continue
+			}
+
+			if _, allowed := auth.HasPipelinePermission(r, pipeline.Permissions, atc.PipelinePermissionView); !allowed {
+				continue
+			}
+		}
+
+		presentedBuilds = append(presentedBuilds, present.Build(build))
+	}
+
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(presentedBuilds)
+}
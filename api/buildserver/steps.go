@@ -0,0 +1,36 @@
+package buildserver
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/api/present"
+	"github.com/concourse/atc/db"
+)
+
+// ListBuildSteps lists the build's steps in the order they started, with how
+// each of them went and how long it took.
+func (s *Server) ListBuildSteps(build db.Build) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger := s.logger.Session("list-build-steps", lager.Data{"build-id": build.ID()})
+
+		steps, err := build.GetSteps()
+		if err != nil {
+			logger.Error("failed-to-get-steps", err)
+			apierror.DBFailure(w, "failed to get steps")
+			return
+		}
+
+		presentedSteps := make([]atc.BuildStep, len(steps))
+		for i, step := range steps {
+			presentedSteps[i] = present.BuildStep(step)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(presentedSteps)
+	})
+}
@@ -0,0 +1,196 @@
+package buildserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/event"
+	"github.com/concourse/atc/metric"
+)
+
+// bufferedEventSource reads a running build's events as they come, whether
+// or not its client is keeping up, so that a slow client only ever holds up
+// itself. Once the buffer is full, log events are skipped until the client
+// catches up, and it is sent a warning saying how many it missed in their
+// place. Every other event is kept, as clients need them to make sense of
+// the build.
+//
+// Events come out with their IDs set, so that streams can tell where they
+// are after a skip. The source is closed as soon as it runs out, so that a
+// client still working through the buffer doesn't hold on to it.
+type bufferedEventSource struct {
+	source    db.EventSource
+	size      int
+	transport string
+
+	lock    sync.Mutex
+	queue   []bufferedEvent
+	err     error
+	changed chan struct{}
+
+	closed          chan struct{}
+	closeOnce       sync.Once
+	closeSourceOnce sync.Once
+}
+
+// bufferedEvent is either an event or, if skipped is non-zero, the log events
+// skipped up to and including the one with the given ID.
+type bufferedEvent struct {
+	id      uint
+	ev      event.Envelope
+	skipped int
+}
+
+func newBufferedEventSource(source db.EventSource, from uint, size int, transport string) *bufferedEventSource {
+	buffered := &bufferedEventSource{
+		source:    source,
+		size:      size,
+		transport: transport,
+
+		changed: make(chan struct{}, 1),
+		closed:  make(chan struct{}),
+	}
+
+	go buffered.pump(from)
+
+	return buffered
+}
+
+func (buffered *bufferedEventSource) pump(id uint) {
+	for {
+		ev, err := buffered.source.Next()
+
+		buffered.lock.Lock()
+
+		if err != nil {
+			buffered.err = err
+		} else {
+			buffered.add(id, ev)
+			id++
+		}
+
+		buffered.lock.Unlock()
+
+		select {
+		case buffered.changed <- struct{}{}:
+		default:
+		}
+
+		if err != nil {
+			buffered.closeSource()
+			return
+		}
+	}
+}
+
+// add must be called with the lock held.
+func (buffered *bufferedEventSource) add(id uint, ev event.Envelope) {
+	if len(buffered.queue) < buffered.size || ev.Event != event.EventTypeLog {
+		buffered.queue = append(buffered.queue, bufferedEvent{id: id, ev: ev})
+		return
+	}
+
+	metric.SkippedStreamEvents.Inc(buffered.transport)
+
+	last := len(buffered.queue) - 1
+	if buffered.queue[last].skipped > 0 {
+		buffered.queue[last].id = id
+		buffered.queue[last].ev.Time = ev.Time
+		buffered.queue[last].skipped++
+		return
+	}
+
+	buffered.queue = append(buffered.queue, bufferedEvent{
+		id:      id,
+		ev:      event.Envelope{Time: ev.Time},
+		skipped: 1,
+	})
+}
+
+func (buffered *bufferedEventSource) Next() (event.Envelope, error) {
+	for {
+		buffered.lock.Lock()
+
+		if len(buffered.queue) > 0 {
+			next := buffered.queue[0]
+			buffered.queue[0] = bufferedEvent{}
+			buffered.queue = buffered.queue[1:]
+			buffered.lock.Unlock()
+
+			ev := next.ev
+			if next.skipped > 0 {
+				var err error
+				ev, err = skippedEvents(next.skipped, ev)
+				if err != nil {
+					return event.Envelope{}, err
+				}
+			}
+
+			id := next.id
+			ev.ID = &id
+
+			return ev, nil
+		}
+
+		if buffered.err != nil {
+			err := buffered.err
+			buffered.lock.Unlock()
+			return event.Envelope{}, err
+		}
+
+		buffered.lock.Unlock()
+
+		select {
+		case <-buffered.changed:
+		case <-buffered.closed:
+			return event.Envelope{}, db.ErrBuildEventStreamClosed
+		}
+	}
+}
+
+func (buffered *bufferedEventSource) Close() error {
+	buffered.closeOnce.Do(func() {
+		close(buffered.closed)
+	})
+
+	return buffered.closeSource()
+}
+
+func (buffered *bufferedEventSource) closeSource() error {
+	var err error
+
+	buffered.closeSourceOnce.Do(func() {
+		err = buffered.source.Close()
+	})
+
+	return err
+}
+
+func skippedEvents(count int, last event.Envelope) (event.Envelope, error) {
+	noun := "events"
+	if count == 1 {
+		noun = "event"
+	}
+
+	warning := event.Warning{
+		Message: fmt.Sprintf("skipped %d log %s", count, noun),
+	}
+
+	if !last.Time.IsZero() {
+		warning.Time = last.Time.Unix()
+	}
+
+	payload, err := json.Marshal(warning)
+	if err != nil {
+		return event.Envelope{}, err
+	}
+
+	return event.Envelope{
+		Data:    (*json.RawMessage)(&payload),
+		Event:   warning.EventType(),
+		Version: warning.Version(),
+		Time:    last.Time,
+	}, nil
+}
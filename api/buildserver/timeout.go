@@ -0,0 +1,30 @@
+package buildserver
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+// TimeoutQueryParam sets how long a build being created may run for, e.g.
+// ?timeout=2h, before it is aborted and marked as timed out. Builds without
+// one use the ATC's default.
+const TimeoutQueryParam = "timeout"
+
+func parseTimeout(r *http.Request) (time.Duration, error) {
+	value := r.URL.Query().Get(TimeoutQueryParam)
+	if value == "" {
+		return 0, nil
+	}
+
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+
+	if timeout <= 0 {
+		return 0, errors.New("timeout must be positive")
+	}
+
+	return timeout, nil
+}
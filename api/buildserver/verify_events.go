@@ -0,0 +1,43 @@
+package buildserver
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/api/present"
+	"github.com/concourse/atc/db"
+)
+
+func (s *Server) VerifyBuildEvents(build db.Build) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger := s.logger.Session("verify-build-events", lager.Data{
+			"build": build.ID(),
+		})
+
+		verification, err := build.VerifyEvents()
+		if err == db.ErrEventSigningNotConfigured {
+			apierror.NotFound(w, "build events are not being signed")
+			return
+		}
+
+		if err != nil {
+			logger.Error("failed-to-verify-events", err)
+			apierror.DBFailure(w, "failed to verify events")
+			return
+		}
+
+		if !verification.Valid {
+			logger.Info("events-tampered-with", lager.Data{
+				"broken-at": verification.BrokenAt,
+				"reason":    verification.Reason,
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		json.NewEncoder(w).Encode(present.BuildEventVerification(verification))
+	})
+}
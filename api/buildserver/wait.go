@@ -0,0 +1,121 @@
+package buildserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/api/present"
+	"github.com/concourse/atc/db"
+)
+
+// DefaultWaitTimeout is how long WaitForBuild waits when no ?timeout= is
+// given. Longer timeouts are cut down to MaxWaitTimeout.
+const (
+	DefaultWaitTimeout = time.Minute
+	MaxWaitTimeout     = 10 * time.Minute
+)
+
+// WaitForBuild responds with the build once it has finished, or once the
+// timeout has elapsed or the server starts draining, whichever comes first.
+// Clients tell which by the status of the build they get back, and wait
+// again if it's still running.
+//
+// The response is started straight away, so while waiting the connection is
+// kept alive on the route's keepalive interval by writing whitespace ahead of
+// the build's JSON.
+func (s *Server) WaitForBuild(build db.Build) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger := s.logger.Session("wait-for-build", lager.Data{"build": build.ID()})
+
+		timeout, err := parseTimeout(r)
+		if err != nil {
+			logger.Info("malformed-timeout", lager.Data{"error": err.Error()})
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if timeout == 0 {
+			timeout = DefaultWaitTimeout
+		} else if timeout > MaxWaitTimeout {
+			timeout = MaxWaitTimeout
+		}
+
+		s.serveStream(logger, atc.WaitForBuild, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			s.waitForBuild(logger, build, timeout, w, r)
+		}), w, r)
+	})
+}
+
+func (s *Server) waitForBuild(logger lager.Logger, build db.Build, timeout time.Duration, w http.ResponseWriter, r *http.Request) {
+	notifier, err := build.FinishNotifier()
+	if err != nil {
+		logger.Error("failed-to-get-finish-notifier", err)
+		apierror.DBFailure(w, "failed to wait for build")
+		return
+	}
+
+	defer notifier.Close()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	w.WriteHeader(http.StatusOK)
+
+	flusher := w.(http.Flusher)
+	flusher.Flush()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	var keepAlive <-chan time.Time
+	if interval := keepAliveIntervalFrom(r); interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		keepAlive = ticker.C
+	}
+
+	draining := drainingFrom(r)
+
+wait:
+	for {
+		select {
+		case <-notifier.Notify():
+			break wait
+
+		case <-timer.C:
+			break wait
+
+		case <-draining:
+			break wait
+
+		case <-keepAlive:
+			_, err := w.Write([]byte("\n"))
+			if err != nil {
+				logger.Info("failed-to-write-keepalive", lager.Data{"error": err.Error()})
+				return
+			}
+
+			flusher.Flush()
+
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	found, err := build.Reload()
+	if err != nil {
+		logger.Error("failed-to-reload-build", err)
+		return
+	}
+
+	if !found {
+		logger.Info("build-went-away")
+		return
+	}
+
+	json.NewEncoder(w).Encode(present.Build(build))
+}
@@ -0,0 +1,221 @@
+package buildserver
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/event"
+	"github.com/concourse/atc/metric"
+	"github.com/gorilla/websocket"
+)
+
+const TransportQueryParam = "transport"
+const TransportWebSocket = "websocket"
+
+const (
+	WebSocketMessageEvent = "event"
+	WebSocketMessageEnd   = "end"
+	WebSocketMessageDrain = "drain"
+)
+
+var eventsUpgrader = websocket.Upgrader{
+	HandshakeTimeout: 5 * time.Second,
+}
+
+// WebSocketMessage is written for every event when streaming over a
+// WebSocket. It carries the same envelope as the data of an SSE event.
+type WebSocketMessage struct {
+	ID    uint            `json:"id"`
+	Name  string          `json:"name"`
+	Event *event.Envelope `json:"event,omitempty"`
+}
+
+func isWebSocketRequest(r *http.Request) bool {
+	return r.FormValue(TransportQueryParam) == TransportWebSocket || websocket.IsWebSocketUpgrade(r)
+}
+
+func serveWebSocketEvents(logger lager.Logger, hub *EventHub, build db.Build, start uint, filter eventFilter, pacer *replayPacer, w http.ResponseWriter, r *http.Request) {
+	subscribeStart := time.Now()
+
+	events, err := subscribeStream(hub, build, start, pacer, "websocket")
+	if err != nil {
+		logger.Error("failed-to-get-build-events", err, lager.Data{"build-id": build.ID(), "start": start})
+		apierror.DBFailure(w, "failed to get build events")
+		return
+	}
+
+	metric.BuildEventsLatency.Observe(time.Since(subscribeStart))
+
+	metric.ActiveEventStreams.Inc()
+	defer metric.ActiveEventStreams.Dec()
+
+	var closeOnce sync.Once
+	closeEvents := func() {
+		closeOnce.Do(func() {
+			events.Close()
+		})
+	}
+
+	defer closeEvents()
+
+	responseHeader := http.Header{}
+	responseHeader.Add(ProtocolVersionHeader, CurrentProtocolVersion)
+	responseHeader.Add(EventSchemaHeader, strconv.Itoa(int(filter.schema)))
+
+	conn, err := eventsUpgrader.Upgrade(w, r, responseHeader)
+	if err != nil {
+		logger.Error("unable-to-upgrade-connection-for-websockets", err)
+		return
+	}
+
+	defer conn.Close()
+
+	// with keepalives, a client that hasn't answered a ping or taken a write
+	// within the interval is taken to be gone
+	keepAliveTimeout := keepAliveIntervalFrom(r)
+
+	writeDeadline := func() time.Time {
+		if keepAliveTimeout == 0 {
+			return time.Time{}
+		}
+
+		return time.Now().Add(keepAliveTimeout)
+	}
+
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Time{})
+	})
+
+	var writeLock sync.Mutex
+	finished := false
+	stopSupervising := make(chan struct{})
+
+	defer func() {
+		writeLock.Lock()
+		finished = true
+		writeLock.Unlock()
+
+		close(stopSupervising)
+	}()
+
+	go superviseStream(r, closeEvents, func() {
+		writeLock.Lock()
+		defer writeLock.Unlock()
+
+		if finished {
+			return
+		}
+
+		conn.SetWriteDeadline(writeDeadline())
+
+		err := conn.WriteJSON(WebSocketMessage{Name: WebSocketMessageDrain})
+		if err != nil {
+			logger.Info("failed-to-write-drain", lager.Data{"error": err.Error()})
+		}
+	}, func() {
+		writeLock.Lock()
+		defer writeLock.Unlock()
+
+		if finished {
+			return
+		}
+
+		// set before pinging, so that the pong can't arrive first
+		conn.SetReadDeadline(writeDeadline())
+
+		err := conn.WriteControl(websocket.PingMessage, nil, writeDeadline())
+		if err != nil {
+			logger.Info("failed-to-write-keepalive", lager.Data{"error": err.Error()})
+			metric.ReapedEventStreams.Inc("websocket")
+			closeEvents()
+		}
+	}, stopSupervising)
+
+	// clients never send anything meaningful, but reading is needed to process
+	// control frames and notice when they go away
+	go func() {
+		for {
+			_, _, err := conn.NextReader()
+			if err != nil {
+				if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+					logger.Info("client-unresponsive", lager.Data{"timeout": keepAliveTimeout.String()})
+					metric.ReapedEventStreams.Inc("websocket")
+				}
+
+				closeEvents()
+				return
+			}
+		}
+	}()
+
+	for {
+		logger = logger.WithData(lager.Data{"id": start})
+
+		ev, err := events.Next()
+		if err != nil {
+			if err == db.ErrEndOfBuildEventStream {
+				writeLock.Lock()
+				conn.SetWriteDeadline(writeDeadline())
+				err := conn.WriteJSON(WebSocketMessage{ID: start, Name: WebSocketMessageEnd})
+				writeLock.Unlock()
+				if err != nil {
+					logger.Info("failed-to-write-end", lager.Data{"error": err.Error()})
+					return
+				}
+
+				err = conn.WriteControl(
+					websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+					time.Time{},
+				)
+				if err != nil {
+					logger.Info("failed-to-close-websocket", lager.Data{"error": err.Error()})
+				}
+			} else if err != db.ErrBuildEventStreamClosed {
+				logger.Error("failed-to-get-next-build-event", err)
+			}
+
+			return
+		}
+
+		if ev.ID != nil {
+			start = *ev.ID
+		}
+
+		if !pacer.Wait(r.Context(), ev) {
+			return
+		}
+
+		ev, send, err := filter.Filter(ev)
+		if err != nil {
+			logger.Error("failed-to-filter-event", err)
+			return
+		}
+
+		if !send {
+			start++
+			continue
+		}
+
+		writeLock.Lock()
+		conn.SetWriteDeadline(writeDeadline())
+		err = conn.WriteJSON(WebSocketMessage{
+			ID:    start,
+			Name:  WebSocketMessageEvent,
+			Event: &ev,
+		})
+		writeLock.Unlock()
+		if err != nil {
+			logger.Info("failed-to-write-event", lager.Data{"error": err.Error()})
+			return
+		}
+
+		start++
+	}
+}
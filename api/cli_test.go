@@ -1,13 +1,17 @@
 package api_test
 
 import (
+	"encoding/json"
 	"io/ioutil"
 	"net/http"
 	"os"
 	"path/filepath"
 
+	"github.com/concourse/atc"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
 )
 
 var _ = Describe("CLI Downloads API", func() {
@@ -82,6 +86,140 @@ var _ = Describe("CLI Downloads API", func() {
 		})
 	})
 
+	Describe("GET /api/v1/cli without a platform", func() {
+		var userAgent string
+
+		JustBeforeEach(func() {
+			req, err := http.NewRequest("GET", server.URL+"/api/v1/cli", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			req.Header.Set("User-Agent", userAgent)
+
+			response, err = client.Do(req)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("from a Mac", func() {
+			BeforeEach(func() {
+				userAgent = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_12_3) AppleWebKit/537.36 (KHTML, like Gecko)"
+			})
+
+			It("returns the darwin binary", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+				Expect(response.Header.Get("Content-Disposition")).To(Equal("attachment; filename=fly"))
+				Expect(ioutil.ReadAll(response.Body)).To(Equal([]byte("soi soi soi")))
+			})
+		})
+
+		Context("from Windows", func() {
+			BeforeEach(func() {
+				userAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64)"
+			})
+
+			It("returns the windows binary", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+				Expect(response.Header.Get("Content-Disposition")).To(Equal("attachment; filename=fly.exe"))
+			})
+		})
+
+		Context("from something unrecognizable", func() {
+			BeforeEach(func() {
+				userAgent = "curl/7.51.0"
+			})
+
+			It("returns Bad Request", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+
+	Describe("GET /api/v1/cli?platform=darwin&arch=x86_64", func() {
+		JustBeforeEach(func() {
+			var err error
+			response, err = client.Get(server.URL + "/api/v1/cli?platform=darwin&arch=x86_64")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("treats it as amd64", func() {
+			Expect(response.StatusCode).To(Equal(http.StatusOK))
+			Expect(ioutil.ReadAll(response.Body)).To(Equal([]byte("soi soi soi")))
+		})
+	})
+
+	Describe("GET /api/v1/cli?platform=linux&arch=arm64", func() {
+		JustBeforeEach(func() {
+			var err error
+			response, err = client.Get(server.URL + "/api/v1/cli?platform=linux&arch=arm64")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when the download URL has it", func() {
+			BeforeEach(func() {
+				cliDownloadServer.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/fly/v1.2.3/fly_linux_arm64"),
+						ghttp.RespondWith(http.StatusOK, "proxied fly"),
+					),
+				)
+			})
+
+			It("proxies it", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+				Expect(response.Header.Get("Content-Disposition")).To(Equal("attachment; filename=fly"))
+				Expect(ioutil.ReadAll(response.Body)).To(Equal([]byte("proxied fly")))
+			})
+
+			It("reports its checksum in the info", func() {
+				_, err := ioutil.ReadAll(response.Body)
+				Expect(err).NotTo(HaveOccurred())
+
+				clis := func() []atc.CLI {
+					infoResponse, err := client.Get(server.URL + "/api/v1/info")
+					Expect(err).NotTo(HaveOccurred())
+
+					defer infoResponse.Body.Close()
+
+					var info atc.Info
+					err = json.NewDecoder(infoResponse.Body).Decode(&info)
+					Expect(err).NotTo(HaveOccurred())
+
+					return info.CLIs
+				}
+
+				Eventually(clis).Should(ContainElement(atc.CLI{
+					Platform: "linux",
+					Arch:     "arm64",
+					Version:  "1.2.3",
+					SHA256:   "96961dbd9e96d1c994dd32aaf5b63f5d05bb22c0126bd270f7d0ede54fe2aad7",
+				}))
+			})
+		})
+
+		Context("when the download URL doesn't have it", func() {
+			BeforeEach(func() {
+				cliDownloadServer.AppendHandlers(
+					ghttp.RespondWith(http.StatusNotFound, ""),
+				)
+			})
+
+			It("returns 404", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+			})
+		})
+
+		Context("when the download URL fails", func() {
+			BeforeEach(func() {
+				cliDownloadServer.AppendHandlers(
+					ghttp.RespondWith(http.StatusInternalServerError, ""),
+				)
+			})
+
+			It("returns 502", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusBadGateway))
+			})
+		})
+	})
+
 	Describe("GET /api/v1/cli?platform=darwin&arch=../darwin/amd64", func() {
 		JustBeforeEach(func() {
 			req, err := http.NewRequest("GET", server.URL+"/api/v1/cli?platform=darwin&arch=../darwin/amd64", nil)
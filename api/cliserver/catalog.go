@@ -0,0 +1,158 @@
+package cliserver
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/concourse/atc"
+)
+
+// Platforms and Arches are those fly is built for.
+var Platforms = []string{"darwin", "linux", "windows"}
+var Arches = []string{"amd64", "arm64"}
+
+// Catalog knows which CLIs can be downloaded from the ATC: those bundled in
+// its directory, laid out as <platform>/<arch>/fly, and those that have been
+// proxied through it. Checksums of bundled CLIs are worked out when first
+// listed and kept until the file changes.
+type Catalog struct {
+	dir     string
+	version string
+
+	lock      sync.Mutex
+	checksums map[string]fileChecksum
+	proxied   map[string]string
+}
+
+type fileChecksum struct {
+	size    int64
+	modTime time.Time
+	sha256  string
+}
+
+func NewCatalog(dir string, version string) *Catalog {
+	return &Catalog{
+		dir:     dir,
+		version: version,
+
+		checksums: map[string]fileChecksum{},
+		proxied:   map[string]string{},
+	}
+}
+
+// List returns every CLI that's bundled or has been proxied, bundled ones
+// first.
+func (catalog *Catalog) List() ([]atc.CLI, error) {
+	clis := []atc.CLI{}
+	bundled := map[string]bool{}
+
+	for _, platform := range Platforms {
+		for _, arch := range Arches {
+			path, found := catalog.bundled(platform, arch)
+			if !found {
+				continue
+			}
+
+			checksum, err := catalog.checksum(path)
+			if err != nil {
+				return nil, err
+			}
+
+			bundled[platform+"/"+arch] = true
+
+			clis = append(clis, catalog.cli(platform, arch, checksum))
+		}
+	}
+
+	catalog.lock.Lock()
+	defer catalog.lock.Unlock()
+
+	for _, platform := range Platforms {
+		for _, arch := range Arches {
+			key := platform + "/" + arch
+
+			checksum, found := catalog.proxied[key]
+			if found && !bundled[key] {
+				clis = append(clis, catalog.cli(platform, arch, checksum))
+			}
+		}
+	}
+
+	return clis, nil
+}
+
+func (catalog *Catalog) cli(platform string, arch string, checksum string) atc.CLI {
+	return atc.CLI{
+		Platform: platform,
+		Arch:     arch,
+		Version:  catalog.version,
+		SHA256:   checksum,
+	}
+}
+
+func (catalog *Catalog) bundled(platform string, arch string) (string, bool) {
+	if catalog.dir == "" {
+		return "", false
+	}
+
+	path := filepath.Join(catalog.dir, platform, arch, "fly")
+
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return "", false
+	}
+
+	return path, true
+}
+
+func (catalog *Catalog) recordProxied(platform string, arch string, checksum string) {
+	catalog.lock.Lock()
+	catalog.proxied[platform+"/"+arch] = checksum
+	catalog.lock.Unlock()
+}
+
+func (catalog *Catalog) checksum(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	catalog.lock.Lock()
+	cached, found := catalog.checksums[path]
+	catalog.lock.Unlock()
+
+	if found && cached.size == info.Size() && cached.modTime.Equal(info.ModTime()) {
+		return cached.sha256, nil
+	}
+
+	hash := sha256.New()
+
+	_, err = io.Copy(hash, file)
+	if err != nil {
+		return "", err
+	}
+
+	checksum := hex.EncodeToString(hash.Sum(nil))
+
+	catalog.lock.Lock()
+	catalog.checksums[path] = fileChecksum{
+		size:    info.Size(),
+		modTime: info.ModTime(),
+		sha256:  checksum,
+	}
+	catalog.lock.Unlock()
+
+	return checksum, nil
+}
@@ -1,18 +1,20 @@
 package cliserver
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
 	"net/http"
-	"path/filepath"
+	"strings"
+
+	"code.cloudfoundry.org/lager"
 )
 
 func (s *Server) Download(w http.ResponseWriter, r *http.Request) {
-	if s.cliDownloadsDir == "" {
-		http.Error(w, "cli downloads directory not configured", http.StatusNotFound)
-		return
-	}
-	platform := r.URL.Query().Get("platform")
+	logger := s.logger.Session("download-cli")
 
-	arch := r.URL.Query().Get("arch")
+	platform, arch := negotiatePlatform(r)
 
 	var filename string
 
@@ -27,7 +29,7 @@ func (s *Server) Download(w http.ResponseWriter, r *http.Request) {
 	}
 
 	switch arch {
-	case "amd64":
+	case "amd64", "arm64":
 	case "i386":
 		http.Error(w, "too few bits", http.StatusPaymentRequired)
 		return
@@ -36,7 +38,69 @@ func (s *Server) Download(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	path, found := s.catalog.bundled(platform, arch)
+	if found {
+		w.Header().Set("Content-Disposition", "attachment; filename="+filename)
+		http.ServeFile(w, r, path)
+		return
+	}
+
+	if s.downloadURL == "" {
+		http.Error(w, "cli not found", http.StatusNotFound)
+		return
+	}
+
+	s.proxy(logger, w, platform, arch, filename)
+}
+
+func (s *Server) proxy(logger lager.Logger, w http.ResponseWriter, platform string, arch string, filename string) {
+	url := strings.NewReplacer(
+		"{version}", s.version,
+		"{platform}", platform,
+		"{arch}", arch,
+	).Replace(s.downloadURL)
+
+	logger = logger.WithData(lager.Data{"url": url})
+
+	response, err := s.httpClient.Get(url)
+	if err != nil {
+		logger.Error("failed-to-fetch-cli", err)
+		http.Error(w, "failed to fetch cli", http.StatusBadGateway)
+		return
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		http.Error(w, "cli not found", http.StatusNotFound)
+		return
+	}
+
+	if response.StatusCode != http.StatusOK {
+		logger.Info("unexpected-response", lager.Data{"status": response.StatusCode})
+		http.Error(w, fmt.Sprintf("fetching cli returned %d", response.StatusCode), http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
 	w.Header().Set("Content-Disposition", "attachment; filename="+filename)
 
-	http.ServeFile(w, r, filepath.Join(s.cliDownloadsDir, platform, arch, "fly"))
+	if response.ContentLength >= 0 {
+		w.Header().Set("Content-Length", fmt.Sprintf("%d", response.ContentLength))
+	}
+
+	hash := sha256.New()
+
+	written, err := io.Copy(w, io.TeeReader(response.Body, hash))
+	if err != nil {
+		logger.Info("failed-to-proxy-cli", lager.Data{"error": err.Error()})
+		return
+	}
+
+	// only a whole binary's checksum is worth reporting
+	if response.ContentLength >= 0 && written != response.ContentLength {
+		return
+	}
+
+	s.catalog.recordProxied(platform, arch, hex.EncodeToString(hash.Sum(nil)))
 }
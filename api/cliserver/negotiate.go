@@ -0,0 +1,48 @@
+package cliserver
+
+import (
+	"net/http"
+	"strings"
+)
+
+var archAliases = map[string]string{
+	"x86_64":  "amd64",
+	"aarch64": "arm64",
+}
+
+// negotiatePlatform returns the platform and arch asked for, guessing either
+// of them from the User-Agent if they weren't given, so that a browser can
+// just follow a link to the CLI for whatever it's running on.
+func negotiatePlatform(r *http.Request) (string, string) {
+	platform := r.URL.Query().Get("platform")
+	arch := r.URL.Query().Get("arch")
+
+	if alias, found := archAliases[arch]; found {
+		arch = alias
+	}
+
+	userAgent := r.UserAgent()
+
+	if platform == "" {
+		switch {
+		case strings.Contains(userAgent, "Windows"):
+			platform = "windows"
+		case strings.Contains(userAgent, "Macintosh"), strings.Contains(userAgent, "Mac OS X"):
+			platform = "darwin"
+		case strings.Contains(userAgent, "Linux"):
+			platform = "linux"
+		}
+	}
+
+	if arch == "" && platform != "" {
+		switch {
+		case strings.Contains(userAgent, "aarch64"), strings.Contains(userAgent, "arm64"):
+			arch = "arm64"
+		default:
+			// Macs on ARM still say they're Intel
+			arch = "amd64"
+		}
+	}
+
+	return platform, arch
+}
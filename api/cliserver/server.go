@@ -1,15 +1,35 @@
 package cliserver
 
-import "code.cloudfoundry.org/lager"
+import (
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+)
 
 type Server struct {
-	logger          lager.Logger
-	cliDownloadsDir string
+	logger  lager.Logger
+	catalog *Catalog
+
+	// downloadURL is where to fetch CLIs that aren't bundled, with {version},
+	// {platform} and {arch} in it replaced; empty to only serve bundled ones
+	downloadURL string
+	httpClient  *http.Client
+	version     string
 }
 
-func NewServer(logger lager.Logger, cliDownloadsDir string) *Server {
+func NewServer(
+	logger lager.Logger,
+	catalog *Catalog,
+	downloadURL string,
+	httpClient *http.Client,
+	version string,
+) *Server {
 	return &Server{
-		logger:          logger,
-		cliDownloadsDir: cliDownloadsDir,
+		logger:  logger,
+		catalog: catalog,
+
+		downloadURL: downloadURL,
+		httpClient:  httpClient,
+		version:     version,
 	}
 }
@@ -0,0 +1,41 @@
+package conditional
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// NotModified tags the response with a weak ETag derived from body. If the
+// request's If-None-Match already names that tag, it responds with 304 Not
+// Modified and returns true, and the caller must not write the body.
+//
+// The tag is weak because the same body may be sent with different content
+// encodings.
+func NotModified(w http.ResponseWriter, r *http.Request, body []byte) bool {
+	sum := sha1.Sum(body)
+	etag := `W/"` + hex.EncodeToString(sum[:]) + `"`
+
+	w.Header().Set("ETag", etag)
+
+	if !matches(r.Header.Get("If-None-Match"), etag) {
+		return false
+	}
+
+	w.WriteHeader(http.StatusNotModified)
+
+	return true
+}
+
+func matches(ifNoneMatch string, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
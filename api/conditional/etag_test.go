@@ -0,0 +1,54 @@
+package conditional_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/concourse/atc/api/conditional"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("NotModified", func() {
+	etagFor := func(body string) string {
+		recorder := httptest.NewRecorder()
+
+		request, err := http.NewRequest("GET", "/", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(conditional.NotModified(recorder, request, []byte(body))).To(BeFalse())
+
+		return recorder.Header().Get("ETag")
+	}
+
+	It("tags the same body with the same weak ETag", func() {
+		Expect(etagFor("some-body")).To(MatchRegexp(`^W/"[0-9a-f]{40}"$`))
+		Expect(etagFor("some-body")).To(Equal(etagFor("some-body")))
+		Expect(etagFor("some-body")).NotTo(Equal(etagFor("some-other-body")))
+	})
+
+	DescribeTable("If-None-Match",
+		func(ifNoneMatch func(etag string) string, notModified bool) {
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest("GET", "/", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			request.Header.Set("If-None-Match", ifNoneMatch(etagFor("some-body")))
+
+			Expect(conditional.NotModified(recorder, request, []byte("some-body"))).To(Equal(notModified))
+
+			if notModified {
+				Expect(recorder.Code).To(Equal(http.StatusNotModified))
+			}
+		},
+		Entry("matching", func(etag string) string { return etag }, true),
+		Entry("matching strongly", func(etag string) string { return etag[2:] }, true),
+		Entry("in a list", func(etag string) string { return `"nope", ` + etag }, true),
+		Entry("any", func(etag string) string { return "*" }, true),
+		Entry("stale", func(etag string) string { return `W/"nope"` }, false),
+		Entry("empty", func(etag string) string { return "" }, false),
+	)
+})
@@ -8,9 +8,11 @@ import (
 	"mime/multipart"
 	"net/http"
 	"net/textproto"
+	"time"
 
 	"github.com/concourse/atc"
 	"github.com/concourse/atc/config"
+	"github.com/concourse/atc/creds/credsfakes"
 	"github.com/concourse/atc/db"
 	"github.com/onsi/gomega/gbytes"
 	"github.com/tedsuo/rata"
@@ -319,11 +321,52 @@ var _ = Describe("Config API", func() {
 						It("saves it", func() {
 							Expect(teamDB.SaveConfigCallCount()).To(Equal(1))
 
-							name, savedConfig, id, pipelineState := teamDB.SaveConfigArgsForCall(0)
+							name, savedConfig, id, pipelineState, author := teamDB.SaveConfigArgsForCall(0)
 							Expect(name).To(Equal("a-pipeline"))
 							Expect(savedConfig).To(Equal(pipelineConfig))
 							Expect(id).To(Equal(db.ConfigVersion(42)))
 							Expect(pipelineState).To(Equal(db.PipelineNoChange))
+							Expect(author).To(Equal("team:a-team"))
+						})
+
+						Context("and the config has been changed since the given version", func() {
+							BeforeEach(func() {
+								teamDB.SaveConfigReturns(db.SavedPipeline{}, false, db.ErrConfigComparisonFailed)
+							})
+
+							It("returns 409", func() {
+								Expect(response.StatusCode).To(Equal(http.StatusConflict))
+							})
+
+							It("returns an error saying why in the response body", func() {
+								body, err := ioutil.ReadAll(response.Body)
+								Expect(err).NotTo(HaveOccurred())
+
+								Expect(body).To(MatchJSON(`{
+									"errors": [
+										"pipeline config has changed since version 42; fetch it again and reapply your changes"
+									]
+								}`))
+							})
+						})
+
+						Context("and the pipeline is an instance of another", func() {
+							BeforeEach(func() {
+								teamDB.SaveConfigReturns(db.SavedPipeline{}, false, db.ErrPipelineIsInstance)
+							})
+
+							It("returns 400 saying to set the template's config instead", func() {
+								Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+
+								body, err := ioutil.ReadAll(response.Body)
+								Expect(err).NotTo(HaveOccurred())
+
+								Expect(body).To(MatchJSON(`{
+									"errors": [
+										"pipeline is an instance of another pipeline; set the config of that one instead"
+									]
+								}`))
+							})
 						})
 
 						Context("and saving it fails", func() {
@@ -336,7 +379,10 @@ var _ = Describe("Config API", func() {
 							})
 
 							It("returns the error in the response body", func() {
-								Expect(ioutil.ReadAll(response.Body)).To(Equal([]byte("failed to save config: oh no!")))
+								body, err := ioutil.ReadAll(response.Body)
+								Expect(err).NotTo(HaveOccurred())
+
+								Expect(body).To(MatchJSON(`{"code":"db-failure","message":"failed to save config: oh no!"}`))
 							})
 						})
 
@@ -401,7 +447,7 @@ var _ = Describe("Config API", func() {
 						It("saves it", func() {
 							Expect(teamDB.SaveConfigCallCount()).To(Equal(1))
 
-							name, savedConfig, id, pipelineState := teamDB.SaveConfigArgsForCall(0)
+							name, savedConfig, id, pipelineState, _ := teamDB.SaveConfigArgsForCall(0)
 							Expect(name).To(Equal("a-pipeline"))
 							Expect(savedConfig).To(Equal(pipelineConfig))
 							Expect(id).To(Equal(db.ConfigVersion(42)))
@@ -411,7 +457,7 @@ var _ = Describe("Config API", func() {
 						It("does not give the DB a map of empty interfaces to empty interfaces", func() {
 							Expect(teamDB.SaveConfigCallCount()).To(Equal(1))
 
-							_, savedConfig, _, _ := teamDB.SaveConfigArgsForCall(0)
+							_, savedConfig, _, _, _ := teamDB.SaveConfigArgsForCall(0)
 							Expect(savedConfig).To(Equal(pipelineConfig))
 
 							_, err := json.Marshal(pipelineConfig)
@@ -448,7 +494,7 @@ jobs:
 							It("saves it", func() {
 								Expect(teamDB.SaveConfigCallCount()).To(Equal(1))
 
-								name, savedConfig, id, pipelineState := teamDB.SaveConfigArgsForCall(0)
+								name, savedConfig, id, pipelineState, _ := teamDB.SaveConfigArgsForCall(0)
 								Expect(name).To(Equal("a-pipeline"))
 								Expect(savedConfig).To(Equal(atc.Config{
 									Resources: []atc.ResourceConfig{
@@ -517,7 +563,10 @@ jobs:
 							})
 
 							It("returns the error in the response body", func() {
-								Expect(ioutil.ReadAll(response.Body)).To(Equal([]byte("failed to save config: oh no!")))
+								body, err := ioutil.ReadAll(response.Body)
+								Expect(err).NotTo(HaveOccurred())
+
+								Expect(body).To(MatchJSON(`{"code":"db-failure","message":"failed to save config: oh no!"}`))
 							})
 						})
 
@@ -586,7 +635,7 @@ jobs:
 							It("saves it", func() {
 								Expect(teamDB.SaveConfigCallCount()).To(Equal(1))
 
-								name, savedConfig, id, pipelineState := teamDB.SaveConfigArgsForCall(0)
+								name, savedConfig, id, pipelineState, _ := teamDB.SaveConfigArgsForCall(0)
 								Expect(name).To(Equal("a-pipeline"))
 								Expect(savedConfig).To(Equal(pipelineConfig))
 								Expect(id).To(Equal(db.ConfigVersion(42)))
@@ -623,7 +672,10 @@ jobs:
 								})
 
 								It("returns the error in the response body", func() {
-									Expect(ioutil.ReadAll(response.Body)).To(Equal([]byte("failed to save config: oh no!")))
+									body, err := ioutil.ReadAll(response.Body)
+									Expect(err).NotTo(HaveOccurred())
+
+									Expect(body).To(MatchJSON(`{"code":"db-failure","message":"failed to save config: oh no!"}`))
 								})
 							})
 
@@ -933,4 +985,842 @@ jobs:
 			})
 		})
 	})
+
+	Describe("GET /api/v1/teams/:team_name/pipelines/:name/config/history", func() {
+		var response *http.Response
+
+		JustBeforeEach(func() {
+			req, err := requestGenerator.CreateRequest(atc.GetConfigHistory, rata.Params{
+				"team_name":     "a-team",
+				"pipeline_name": "a-pipeline",
+			}, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			response, err = client.Do(req)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("a-team", 42, true, true)
+			})
+
+			Context("when the revisions can be loaded", func() {
+				BeforeEach(func() {
+					teamDB.GetConfigRevisionsReturns([]db.ConfigRevision{
+						{
+							Version:   2,
+							Config:    pipelineConfig,
+							Author:    "team:a-team",
+							CreatedAt: time.Unix(200, 0),
+						},
+						{
+							Version:   1,
+							Config:    atc.Config{},
+							Author:    "team:main",
+							CreatedAt: time.Unix(100, 0),
+						},
+					}, true, nil)
+				})
+
+				It("looks up the revisions of the right pipeline", func() {
+					Expect(teamDB.GetConfigRevisionsArgsForCall(0)).To(Equal("a-pipeline"))
+				})
+
+				It("returns 200", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+				})
+
+				It("returns application/json", func() {
+					Expect(response.Header.Get("Content-Type")).To(Equal("application/json"))
+				})
+
+				It("returns the revisions", func() {
+					var revisions []atc.ConfigRevision
+					err := json.NewDecoder(response.Body).Decode(&revisions)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(revisions).To(Equal([]atc.ConfigRevision{
+						{
+							Version:   2,
+							Config:    pipelineConfig,
+							Author:    "team:a-team",
+							CreatedAt: 200,
+						},
+						{
+							Version:   1,
+							Config:    atc.Config{},
+							Author:    "team:main",
+							CreatedAt: 100,
+						},
+					}))
+				})
+			})
+
+			Context("when the pipeline cannot be found", func() {
+				BeforeEach(func() {
+					teamDB.GetConfigRevisionsReturns(nil, false, nil)
+				})
+
+				It("returns 404", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+				})
+			})
+
+			Context("when loading the revisions fails", func() {
+				BeforeEach(func() {
+					teamDB.GetConfigRevisionsReturns(nil, false, errors.New("oh no!"))
+				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+
+		Context("when not authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns 401", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+	})
+
+	Describe("POST /api/v1/teams/:team_name/pipelines/:name/config/revert/:config_version", func() {
+		var (
+			configVersion string
+			response      *http.Response
+		)
+
+		BeforeEach(func() {
+			configVersion = "3"
+		})
+
+		JustBeforeEach(func() {
+			req, err := requestGenerator.CreateRequest(atc.RevertConfig, rata.Params{
+				"team_name":      "a-team",
+				"pipeline_name":  "a-pipeline",
+				"config_version": configVersion,
+			}, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			response, err = client.Do(req)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("a-team", 42, true, true)
+			})
+
+			Context("when reverting succeeds", func() {
+				BeforeEach(func() {
+					teamDB.RevertConfigReturns(db.SavedPipeline{
+						Pipeline: db.Pipeline{
+							Name:    "a-pipeline",
+							Version: 7,
+						},
+					}, true, nil)
+				})
+
+				It("reverts the right pipeline to the right version, as the requester", func() {
+					Expect(teamDB.RevertConfigCallCount()).To(Equal(1))
+
+					name, version, author := teamDB.RevertConfigArgsForCall(0)
+					Expect(name).To(Equal("a-pipeline"))
+					Expect(version).To(Equal(db.ConfigVersion(3)))
+					Expect(author).To(Equal("team:a-team"))
+				})
+
+				It("returns 200", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+				})
+
+				It("returns the new config version", func() {
+					Expect(response.Header.Get(atc.ConfigVersionHeader)).To(Equal("7"))
+				})
+			})
+
+			Context("when the revision cannot be found", func() {
+				BeforeEach(func() {
+					teamDB.RevertConfigReturns(db.SavedPipeline{}, false, nil)
+				})
+
+				It("returns 404", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+				})
+			})
+
+			Context("when the config changes while reverting", func() {
+				BeforeEach(func() {
+					teamDB.RevertConfigReturns(db.SavedPipeline{}, false, db.ErrConfigComparisonFailed)
+				})
+
+				It("returns 409", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusConflict))
+				})
+			})
+
+			Context("when the pipeline is an instance of another", func() {
+				BeforeEach(func() {
+					teamDB.RevertConfigReturns(db.SavedPipeline{}, false, db.ErrPipelineIsInstance)
+				})
+
+				It("returns 400", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+				})
+			})
+
+			Context("when reverting fails", func() {
+				BeforeEach(func() {
+					teamDB.RevertConfigReturns(db.SavedPipeline{}, false, errors.New("oh no!"))
+				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+
+			Context("when the version is not a number", func() {
+				BeforeEach(func() {
+					configVersion = "latest"
+				})
+
+				It("returns 400", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+				})
+
+				It("does not revert anything", func() {
+					Expect(teamDB.RevertConfigCallCount()).To(BeZero())
+				})
+			})
+		})
+
+		Context("when not authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns 401", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+
+			It("does not revert anything", func() {
+				Expect(teamDB.RevertConfigCallCount()).To(BeZero())
+			})
+		})
+	})
+
+	Describe("POST /api/v1/teams/:team_name/pipelines/:name/config/validate", func() {
+		var (
+			request  *http.Request
+			response *http.Response
+		)
+
+		BeforeEach(func() {
+			var err error
+			request, err = requestGenerator.CreateRequest(atc.ValidateConfig, rata.Params{
+				"team_name":     "a-team",
+				"pipeline_name": "a-pipeline",
+			}, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			request.Header.Set("Content-Type", "application/json")
+		})
+
+		JustBeforeEach(func() {
+			payload, err := json.Marshal(pipelineConfig)
+			Expect(err).NotTo(HaveOccurred())
+
+			request.Body = gbytes.BufferWithBytes(payload)
+
+			response, err = client.Do(request)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when authorized", func() {
+			var fakeVariables *credsfakes.FakeVariables
+
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("a-team", 42, true, true)
+
+				fakeVariables = new(credsfakes.FakeVariables)
+				fakeCredsManager.VariablesReturns(fakeVariables)
+			})
+
+			It("returns 200 without saving anything", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+				Expect(ioutil.ReadAll(response.Body)).To(MatchJSON(`{}`))
+				Expect(teamDB.SaveConfigCallCount()).To(BeZero())
+			})
+
+			Context("when the config is invalid", func() {
+				BeforeEach(func() {
+					configValidationErrorMessages = []string{"totally invalid"}
+					configValidationWarnings = []config.Warning{{Type: "deprecation", Message: "old"}}
+				})
+
+				It("returns 200 with the errors and warnings, without saving anything", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+					Expect(ioutil.ReadAll(response.Body)).To(MatchJSON(`{
+						"errors": ["totally invalid"],
+						"warnings": [{"type": "deprecation", "message": "old"}]
+					}`))
+					Expect(teamDB.SaveConfigCallCount()).To(BeZero())
+				})
+			})
+
+			Context("when the config refers to credentials", func() {
+				BeforeEach(func() {
+					pipelineConfig.Resources[0].Source["password"] = "((defined))"
+					pipelineConfig.Resources[0].Source["token"] = "((undefined))"
+
+					fakeVariables.GetStub = func(name string) (interface{}, bool, error) {
+						return "some-value", name == "defined", nil
+					}
+				})
+
+				It("looks them up for the pipeline", func() {
+					Expect(fakeCredsManager.VariablesCallCount()).To(Equal(1))
+					teamName, pipelineName := fakeCredsManager.VariablesArgsForCall(0)
+					Expect(teamName).To(Equal("a-team"))
+					Expect(pipelineName).To(Equal("a-pipeline"))
+				})
+
+				It("warns about the ones that aren't defined", func() {
+					Expect(ioutil.ReadAll(response.Body)).To(MatchJSON(`{
+						"warnings": [
+							{"type": "credential", "message": "((undefined)) is not defined for the pipeline or its team"}
+						]
+					}`))
+				})
+
+				Context("when looking one up fails", func() {
+					BeforeEach(func() {
+						fakeVariables.GetStub = nil
+						fakeVariables.GetReturns(nil, false, errors.New("nope"))
+					})
+
+					It("warns that it couldn't be looked up", func() {
+						var body struct {
+							Warnings []config.Warning `json:"warnings"`
+						}
+						err := json.NewDecoder(response.Body).Decode(&body)
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(body.Warnings).To(ContainElement(config.Warning{
+							Type:    "credential",
+							Message: "((defined)) could not be looked up",
+						}))
+					})
+				})
+			})
+
+			Context("when the config is malformed", func() {
+				JustBeforeEach(func() {
+					request, err := requestGenerator.CreateRequest(atc.ValidateConfig, rata.Params{
+						"team_name":     "a-team",
+						"pipeline_name": "a-pipeline",
+					}, gbytes.BufferWithBytes([]byte(`{`)))
+					Expect(err).NotTo(HaveOccurred())
+
+					request.Header.Set("Content-Type", "application/json")
+
+					response, err = client.Do(request)
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("returns 400", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+				})
+			})
+		})
+
+		Context("when not authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns 401", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+	})
+
+	Describe("PUT /api/v1/teams/:team_name/pipelines/:name/config/candidate", func() {
+		var (
+			request  *http.Request
+			response *http.Response
+		)
+
+		BeforeEach(func() {
+			var err error
+			request, err = requestGenerator.CreateRequest(atc.SaveCandidateConfig, rata.Params{
+				"team_name":     "a-team",
+				"pipeline_name": "a-pipeline",
+			}, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			request.Header.Set("Content-Type", "application/json")
+			request.URL.RawQuery = "percentage=25"
+		})
+
+		JustBeforeEach(func() {
+			payload, err := json.Marshal(pipelineConfig)
+			Expect(err).NotTo(HaveOccurred())
+
+			request.Body = gbytes.BufferWithBytes(payload)
+
+			response, err = client.Do(request)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("a-team", 42, true, true)
+			})
+
+			Context("when saving succeeds", func() {
+				BeforeEach(func() {
+					teamDB.SaveCandidateConfigReturns(true, nil)
+				})
+
+				It("saves the candidate for the percentage, as the requester", func() {
+					Expect(teamDB.SaveCandidateConfigCallCount()).To(Equal(1))
+
+					name, savedConfig, percentage, author := teamDB.SaveCandidateConfigArgsForCall(0)
+					Expect(name).To(Equal("a-pipeline"))
+					Expect(savedConfig).To(Equal(pipelineConfig))
+					Expect(percentage).To(Equal(25))
+					Expect(author).To(Equal("team:a-team"))
+				})
+
+				It("returns 200", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+				})
+
+				It("leaves the pipeline's config alone", func() {
+					Expect(teamDB.SaveConfigCallCount()).To(BeZero())
+				})
+			})
+
+			Context("when the pipeline cannot be found", func() {
+				BeforeEach(func() {
+					teamDB.SaveCandidateConfigReturns(false, nil)
+				})
+
+				It("returns 404", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+				})
+			})
+
+			Context("when the pipeline is an instance of another", func() {
+				BeforeEach(func() {
+					teamDB.SaveCandidateConfigReturns(false, db.ErrPipelineIsInstance)
+				})
+
+				It("returns 400", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+				})
+			})
+
+			Context("when saving fails", func() {
+				BeforeEach(func() {
+					teamDB.SaveCandidateConfigReturns(false, errors.New("oh no!"))
+				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+
+			Context("when the config is invalid", func() {
+				BeforeEach(func() {
+					configValidationErrorMessages = []string{"totally invalid"}
+				})
+
+				It("returns 400 with the errors, without saving anything", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+					Expect(ioutil.ReadAll(response.Body)).To(MatchJSON(`{"errors": ["totally invalid"]}`))
+					Expect(teamDB.SaveCandidateConfigCallCount()).To(BeZero())
+				})
+			})
+
+			Context("when the percentage is missing", func() {
+				BeforeEach(func() {
+					request.URL.RawQuery = ""
+				})
+
+				It("returns 400 without saving anything", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+					Expect(teamDB.SaveCandidateConfigCallCount()).To(BeZero())
+				})
+			})
+
+			Context("when the percentage is out of range", func() {
+				BeforeEach(func() {
+					request.URL.RawQuery = "percentage=101"
+				})
+
+				It("returns 400 without saving anything", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+					Expect(teamDB.SaveCandidateConfigCallCount()).To(BeZero())
+				})
+			})
+		})
+
+		Context("when not authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns 401", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+
+			It("does not save anything", func() {
+				Expect(teamDB.SaveCandidateConfigCallCount()).To(BeZero())
+			})
+		})
+	})
+
+	Describe("GET /api/v1/teams/:team_name/pipelines/:name/config/candidate", func() {
+		var response *http.Response
+
+		JustBeforeEach(func() {
+			req, err := requestGenerator.CreateRequest(atc.GetCandidateConfig, rata.Params{
+				"team_name":     "a-team",
+				"pipeline_name": "a-pipeline",
+			}, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			response, err = client.Do(req)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("a-team", 42, true, true)
+			})
+
+			Context("when the pipeline has a candidate", func() {
+				BeforeEach(func() {
+					teamDB.GetCandidateConfigReturns(db.CandidateConfig{
+						ID:         3,
+						Percentage: 25,
+						Author:     "team:a-team",
+						CreatedAt:  time.Unix(1500000000, 0),
+						Config:     atc.Config{Jobs: atc.JobConfigs{{Name: "some-job"}}},
+					}, true, nil)
+				})
+
+				It("returns 200 with the candidate", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+					var candidate atc.CandidateConfig
+					err := json.NewDecoder(response.Body).Decode(&candidate)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(candidate).To(Equal(atc.CandidateConfig{
+						Percentage: 25,
+						Author:     "team:a-team",
+						CreatedAt:  1500000000,
+						Config:     atc.Config{Jobs: atc.JobConfigs{{Name: "some-job"}}},
+					}))
+				})
+
+				It("looks up the right pipeline", func() {
+					Expect(teamDB.GetCandidateConfigArgsForCall(0)).To(Equal("a-pipeline"))
+				})
+			})
+
+			Context("when the pipeline has no candidate", func() {
+				BeforeEach(func() {
+					teamDB.GetCandidateConfigReturns(db.CandidateConfig{}, false, nil)
+				})
+
+				It("returns 404", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+				})
+			})
+
+			Context("when getting the candidate fails", func() {
+				BeforeEach(func() {
+					teamDB.GetCandidateConfigReturns(db.CandidateConfig{}, false, errors.New("oh no!"))
+				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+
+		Context("when not authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns 401", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+	})
+
+	Describe("DELETE /api/v1/teams/:team_name/pipelines/:name/config/candidate", func() {
+		var response *http.Response
+
+		JustBeforeEach(func() {
+			req, err := requestGenerator.CreateRequest(atc.DeleteCandidateConfig, rata.Params{
+				"team_name":     "a-team",
+				"pipeline_name": "a-pipeline",
+			}, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			response, err = client.Do(req)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("a-team", 42, true, true)
+			})
+
+			Context("when the pipeline has a candidate", func() {
+				BeforeEach(func() {
+					teamDB.DeleteCandidateConfigReturns(true, nil)
+				})
+
+				It("deletes the right pipeline's candidate", func() {
+					Expect(teamDB.DeleteCandidateConfigCallCount()).To(Equal(1))
+					Expect(teamDB.DeleteCandidateConfigArgsForCall(0)).To(Equal("a-pipeline"))
+				})
+
+				It("returns 204", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusNoContent))
+				})
+			})
+
+			Context("when the pipeline has no candidate", func() {
+				BeforeEach(func() {
+					teamDB.DeleteCandidateConfigReturns(false, nil)
+				})
+
+				It("returns 404", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+				})
+			})
+
+			Context("when deleting fails", func() {
+				BeforeEach(func() {
+					teamDB.DeleteCandidateConfigReturns(false, errors.New("oh no!"))
+				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+
+		Context("when not authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns 401", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+
+			It("does not delete anything", func() {
+				Expect(teamDB.DeleteCandidateConfigCallCount()).To(BeZero())
+			})
+		})
+	})
+
+	Describe("POST /api/v1/teams/:team_name/pipelines/:name/config/candidate/promote", func() {
+		var response *http.Response
+
+		JustBeforeEach(func() {
+			req, err := requestGenerator.CreateRequest(atc.PromoteCandidateConfig, rata.Params{
+				"team_name":     "a-team",
+				"pipeline_name": "a-pipeline",
+			}, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			response, err = client.Do(req)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("a-team", 42, true, true)
+			})
+
+			Context("when promoting succeeds", func() {
+				BeforeEach(func() {
+					teamDB.PromoteCandidateConfigReturns(db.SavedPipeline{
+						Pipeline: db.Pipeline{
+							Name:    "a-pipeline",
+							Version: 8,
+						},
+					}, true, nil)
+				})
+
+				It("promotes the right pipeline's candidate, as the requester", func() {
+					Expect(teamDB.PromoteCandidateConfigCallCount()).To(Equal(1))
+
+					name, author := teamDB.PromoteCandidateConfigArgsForCall(0)
+					Expect(name).To(Equal("a-pipeline"))
+					Expect(author).To(Equal("team:a-team"))
+				})
+
+				It("returns 200 with the new config version", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+					Expect(response.Header.Get(atc.ConfigVersionHeader)).To(Equal("8"))
+				})
+			})
+
+			Context("when the pipeline has no candidate", func() {
+				BeforeEach(func() {
+					teamDB.PromoteCandidateConfigReturns(db.SavedPipeline{}, false, nil)
+				})
+
+				It("returns 404", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+				})
+			})
+
+			Context("when the config changes while promoting", func() {
+				BeforeEach(func() {
+					teamDB.PromoteCandidateConfigReturns(db.SavedPipeline{}, false, db.ErrConfigComparisonFailed)
+				})
+
+				It("returns 409", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusConflict))
+				})
+			})
+
+			Context("when promoting fails", func() {
+				BeforeEach(func() {
+					teamDB.PromoteCandidateConfigReturns(db.SavedPipeline{}, false, errors.New("oh no!"))
+				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+
+		Context("when not authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns 401", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+
+			It("does not promote anything", func() {
+				Expect(teamDB.PromoteCandidateConfigCallCount()).To(BeZero())
+			})
+		})
+	})
+
+	Describe("GET /api/v1/teams/:team_name/pipelines/:name/config/candidate/comparison", func() {
+		var response *http.Response
+
+		JustBeforeEach(func() {
+			req, err := requestGenerator.CreateRequest(atc.GetCandidateComparison, rata.Params{
+				"team_name":     "a-team",
+				"pipeline_name": "a-pipeline",
+			}, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			response, err = client.Do(req)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("a-team", 42, true, true)
+			})
+
+			Context("when the pipeline has a candidate", func() {
+				BeforeEach(func() {
+					teamDB.GetCandidateComparisonReturns([]db.CandidateComparison{
+						{
+							JobName: "some-job",
+							Current: db.CandidateBuildStats{
+								Builds:          4,
+								Succeeded:       3,
+								Failed:          1,
+								AverageDuration: 90 * time.Second,
+							},
+							Candidate: db.CandidateBuildStats{
+								Builds:          2,
+								Succeeded:       1,
+								Errored:         1,
+								AverageDuration: time.Minute,
+							},
+						},
+					}, true, nil)
+				})
+
+				It("returns 200 with each job's builds side by side", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+					Expect(ioutil.ReadAll(response.Body)).To(MatchJSON(`[
+						{
+							"job_name": "some-job",
+							"current": {"builds": 4, "succeeded": 3, "failed": 1, "errored": 0, "aborted": 0, "average_duration": 90},
+							"candidate": {"builds": 2, "succeeded": 1, "failed": 0, "errored": 1, "aborted": 0, "average_duration": 60}
+						}
+					]`))
+				})
+
+				It("compares the right pipeline", func() {
+					Expect(teamDB.GetCandidateComparisonArgsForCall(0)).To(Equal("a-pipeline"))
+				})
+			})
+
+			Context("when the pipeline has no candidate", func() {
+				BeforeEach(func() {
+					teamDB.GetCandidateComparisonReturns(nil, false, nil)
+				})
+
+				It("returns 404", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+				})
+			})
+
+			Context("when comparing fails", func() {
+				BeforeEach(func() {
+					teamDB.GetCandidateComparisonReturns(nil, false, errors.New("oh no!"))
+				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+
+		Context("when not authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns 401", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+	})
 })
@@ -0,0 +1,175 @@
+package configserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/api/present"
+	"github.com/concourse/atc/auth"
+	"github.com/concourse/atc/db"
+	"github.com/tedsuo/rata"
+)
+
+// PercentageQueryParam is the percentage of the pipeline's builds to build
+// from the candidate, from 1 to 100.
+const PercentageQueryParam = "percentage"
+
+func (s *Server) SaveCandidateConfig(w http.ResponseWriter, r *http.Request) {
+	session := s.logger.Session("save-candidate-config")
+	pipelineName := rata.Param(r, "pipeline_name")
+
+	percentage, err := strconv.Atoi(r.URL.Query().Get(PercentageQueryParam))
+	if err != nil || percentage < 1 || percentage > 100 {
+		s.handleBadRequest(w, []string{"percentage must be a number from 1 to 100"}, session)
+		return
+	}
+
+	config, _, ok := s.decodeConfig(w, r, session)
+	if !ok {
+		return
+	}
+
+	warnings, errorMessages := s.validate(config)
+	if len(errorMessages) > 0 {
+		session.Info("ignoring-invalid-config")
+		s.handleBadRequest(w, errorMessages, session)
+		return
+	}
+
+	teamDB := s.teamDBFactory.GetTeamDB(rata.Param(r, "team_name"))
+
+	found, err := teamDB.SaveCandidateConfig(pipelineName, config, percentage, auth.GetActor(r))
+	if err == db.ErrPipelineIsInstance {
+		session.Info("pipeline-is-instance")
+		s.handleBadRequest(w, []string{"pipeline is an instance of another pipeline; try the candidate out on that one instead"}, session)
+		return
+	}
+
+	if err != nil {
+		session.Error("failed-to-save-candidate-config", err)
+		apierror.DBFailure(w, fmt.Sprintf("failed to save candidate config: %s", err))
+		return
+	}
+
+	if !found {
+		session.Debug("pipeline-not-found", lager.Data{"pipeline": pipelineName})
+		apierror.NotFound(w, "pipeline not found")
+		return
+	}
+
+	session.Info("saved", lager.Data{"pipeline": pipelineName, "percentage": percentage})
+
+	w.WriteHeader(http.StatusOK)
+
+	s.writeSaveConfigResponse(w, SaveConfigResponse{Warnings: warnings}, session)
+}
+
+func (s *Server) GetCandidateConfig(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.Session("get-candidate-config")
+	pipelineName := rata.Param(r, "pipeline_name")
+	teamDB := s.teamDBFactory.GetTeamDB(rata.Param(r, "team_name"))
+
+	candidate, found, err := teamDB.GetCandidateConfig(pipelineName)
+	if err != nil {
+		logger.Error("failed-to-get-candidate-config", err)
+		apierror.DBFailure(w, "failed to get candidate config")
+		return
+	}
+
+	if !found {
+		logger.Debug("candidate-config-not-found", lager.Data{"pipeline": pipelineName})
+		apierror.NotFound(w, "candidate config not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	json.NewEncoder(w).Encode(present.CandidateConfig(candidate))
+}
+
+func (s *Server) DeleteCandidateConfig(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.Session("delete-candidate-config")
+	pipelineName := rata.Param(r, "pipeline_name")
+	teamDB := s.teamDBFactory.GetTeamDB(rata.Param(r, "team_name"))
+
+	found, err := teamDB.DeleteCandidateConfig(pipelineName)
+	if err != nil {
+		logger.Error("failed-to-delete-candidate-config", err)
+		apierror.DBFailure(w, "failed to delete candidate config")
+		return
+	}
+
+	if !found {
+		logger.Debug("candidate-config-not-found", lager.Data{"pipeline": pipelineName})
+		apierror.NotFound(w, "candidate config not found")
+		return
+	}
+
+	logger.Info("deleted", lager.Data{"pipeline": pipelineName})
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) PromoteCandidateConfig(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.Session("promote-candidate-config")
+	pipelineName := rata.Param(r, "pipeline_name")
+	teamDB := s.teamDBFactory.GetTeamDB(rata.Param(r, "team_name"))
+
+	savedPipeline, found, err := teamDB.PromoteCandidateConfig(pipelineName, auth.GetActor(r))
+	if err != nil {
+		// someone saved a config while we were promoting; let them look at
+		// what they'd be replacing before trying again
+		if err == db.ErrConfigComparisonFailed {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+
+		logger.Error("failed-to-promote-candidate-config", err)
+		apierror.DBFailure(w, fmt.Sprintf("failed to promote candidate config: %s", err))
+		return
+	}
+
+	if !found {
+		logger.Debug("candidate-config-not-found", lager.Data{"pipeline": pipelineName})
+		apierror.NotFound(w, "candidate config not found")
+		return
+	}
+
+	logger.Info("promoted", lager.Data{"pipeline": pipelineName, "new-version": savedPipeline.Version})
+
+	w.Header().Set(atc.ConfigVersionHeader, fmt.Sprintf("%d", savedPipeline.Version))
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) GetCandidateComparison(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.Session("get-candidate-comparison")
+	pipelineName := rata.Param(r, "pipeline_name")
+	teamDB := s.teamDBFactory.GetTeamDB(rata.Param(r, "team_name"))
+
+	comparisons, found, err := teamDB.GetCandidateComparison(pipelineName)
+	if err != nil {
+		logger.Error("failed-to-get-candidate-comparison", err)
+		apierror.DBFailure(w, "failed to compare candidate config")
+		return
+	}
+
+	if !found {
+		logger.Debug("candidate-config-not-found", lager.Data{"pipeline": pipelineName})
+		apierror.NotFound(w, "candidate config not found")
+		return
+	}
+
+	presented := make([]atc.CandidateComparison, len(comparisons))
+	for i, comparison := range comparisons {
+		presented[i] = present.CandidateComparison(comparison)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	json.NewEncoder(w).Encode(presented)
+}
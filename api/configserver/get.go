@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
 	"github.com/tedsuo/rata"
 )
 
@@ -23,7 +24,7 @@ func (s *Server) GetConfig(w http.ResponseWriter, r *http.Request) {
 
 			responseJSON, err := json.Marshal(getConfigResponse)
 			if err != nil {
-				w.WriteHeader(http.StatusInternalServerError)
+				apierror.Internal(w, "failed to marshal config response")
 			}
 
 			w.Header().Set(atc.ConfigVersionHeader, fmt.Sprintf("%d", id))
@@ -33,7 +34,7 @@ func (s *Server) GetConfig(w http.ResponseWriter, r *http.Request) {
 		}
 
 		logger.Error("failed-to-get-config", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		apierror.DBFailure(w, "failed to get config")
 
 		return
 	}
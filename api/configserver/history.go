@@ -0,0 +1,40 @@
+package configserver
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/api/present"
+	"github.com/tedsuo/rata"
+)
+
+func (s *Server) GetConfigHistory(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.Session("get-config-history")
+	pipelineName := rata.Param(r, "pipeline_name")
+	teamDB := s.teamDBFactory.GetTeamDB(rata.Param(r, "team_name"))
+
+	revisions, found, err := teamDB.GetConfigRevisions(pipelineName)
+	if err != nil {
+		logger.Error("failed-to-get-config-revisions", err)
+		apierror.DBFailure(w, "failed to get config history")
+		return
+	}
+
+	if !found {
+		logger.Debug("pipeline-not-found", lager.Data{"pipeline": pipelineName})
+		apierror.NotFound(w, "pipeline not found")
+		return
+	}
+
+	presented := make([]atc.ConfigRevision, len(revisions))
+	for i, revision := range revisions {
+		presented[i] = present.ConfigRevision(revision)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	json.NewEncoder(w).Encode(presented)
+}
@@ -0,0 +1,62 @@
+package configserver
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/auth"
+	"github.com/concourse/atc/db"
+	"github.com/tedsuo/rata"
+)
+
+func (s *Server) RevertConfig(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.Session("revert-config")
+	pipelineName := rata.Param(r, "pipeline_name")
+
+	version, err := strconv.Atoi(rata.Param(r, "config_version"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	logger = logger.WithData(lager.Data{
+		"pipeline": pipelineName,
+		"version":  version,
+	})
+
+	teamDB := s.teamDBFactory.GetTeamDB(rata.Param(r, "team_name"))
+
+	savedPipeline, found, err := teamDB.RevertConfig(pipelineName, db.ConfigVersion(version), auth.GetActor(r))
+	if err != nil {
+		// someone saved a config while we were reverting; let them look at
+		// what they'd be replacing before trying again
+		if err == db.ErrConfigComparisonFailed {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+
+		if err == db.ErrPipelineIsInstance {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		logger.Error("failed-to-revert-config", err)
+		apierror.DBFailure(w, fmt.Sprintf("failed to revert config: %s", err))
+		return
+	}
+
+	if !found {
+		logger.Debug("config-revision-not-found")
+		apierror.NotFound(w, "config revision not found")
+		return
+	}
+
+	logger.Info("reverted", lager.Data{"new-version": savedPipeline.Version})
+
+	w.Header().Set(atc.ConfigVersionHeader, fmt.Sprintf("%d", savedPipeline.Version))
+	w.WriteHeader(http.StatusOK)
+}
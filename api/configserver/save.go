@@ -13,6 +13,8 @@ import (
 
 	"code.cloudfoundry.org/lager"
 	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/auth"
 	"github.com/concourse/atc/config"
 	"github.com/concourse/atc/db"
 	"github.com/mitchellh/mapstructure"
@@ -65,42 +67,9 @@ func (s *Server) SaveConfig(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	config, pausedState, err := saveConfigRequestUnmarshaler(r)
-
-	switch err {
-	case ErrStatusUnsupportedMediaType:
-		w.WriteHeader(http.StatusUnsupportedMediaType)
-		return
-	case ErrMalformedRequestPayload:
-		session.Error("malformed-request-payload", err, lager.Data{
-			"content-type": r.Header.Get("Content-Type"),
-		})
-
-		s.handleBadRequest(w, []string{"malformed config"}, session)
+	config, pausedState, ok := s.decodeConfig(w, r, session)
+	if !ok {
 		return
-	case ErrFailedToConstructDecoder:
-		session.Error("failed-to-construct-decoder", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		return
-	case ErrCouldNotDecode:
-		session.Error("could-not-decode", err)
-		s.handleBadRequest(w, []string{"failed to decode config"}, session)
-		return
-	case ErrInvalidPausedValue:
-		session.Error("invalid-paused-value", err)
-		s.handleBadRequest(w, []string{"invalid paused value"}, session)
-		return
-	default:
-		if err != nil {
-			if eke, ok := err.(ExtraKeysError); ok {
-				s.handleBadRequest(w, []string{eke.Error()}, session)
-			} else {
-				session.Error("unexpected-error", err)
-				w.WriteHeader(http.StatusInternalServerError)
-			}
-
-			return
-		}
 	}
 
 	warnings, errorMessages := s.validate(config)
@@ -116,11 +85,25 @@ func (s *Server) SaveConfig(w http.ResponseWriter, r *http.Request) {
 	teamName := rata.Param(r, "team_name")
 
 	teamDB := s.teamDBFactory.GetTeamDB(teamName)
-	_, created, err := teamDB.SaveConfig(pipelineName, config, version, pausedState)
+	_, created, err := teamDB.SaveConfig(pipelineName, config, version, pausedState, auth.GetActor(r))
+	if err == db.ErrConfigComparisonFailed {
+		session.Info("config-version-conflict", lager.Data{"version": version})
+		w.WriteHeader(http.StatusConflict)
+		s.writeSaveConfigResponse(w, SaveConfigResponse{
+			Errors: []string{fmt.Sprintf("pipeline config has changed since version %d; fetch it again and reapply your changes", version)},
+		}, session)
+		return
+	}
+
+	if err == db.ErrPipelineIsInstance {
+		session.Info("pipeline-is-instance")
+		s.handleBadRequest(w, []string{"pipeline is an instance of another pipeline; set the config of that one instead"}, session)
+		return
+	}
+
 	if err != nil {
 		session.Error("failed-to-save-config", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		fmt.Fprintf(w, "failed to save config: %s", err)
+		apierror.DBFailure(w, fmt.Sprintf("failed to save config: %s", err))
 		return
 	}
 
@@ -135,6 +118,50 @@ func (s *Server) SaveConfig(w http.ResponseWriter, r *http.Request) {
 	s.writeSaveConfigResponse(w, SaveConfigResponse{Warnings: warnings}, session)
 }
 
+// decodeConfig reads the config from the request, writing the response
+// itself if it can't.
+func (s *Server) decodeConfig(w http.ResponseWriter, r *http.Request, session lager.Logger) (atc.Config, db.PipelinePausedState, bool) {
+	config, pausedState, err := saveConfigRequestUnmarshaler(r)
+
+	switch err {
+	case ErrStatusUnsupportedMediaType:
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+		return atc.Config{}, db.PipelineNoChange, false
+	case ErrMalformedRequestPayload:
+		session.Error("malformed-request-payload", err, lager.Data{
+			"content-type": r.Header.Get("Content-Type"),
+		})
+
+		s.handleBadRequest(w, []string{"malformed config"}, session)
+		return atc.Config{}, db.PipelineNoChange, false
+	case ErrFailedToConstructDecoder:
+		session.Error("failed-to-construct-decoder", err)
+		apierror.Internal(w, "failed to construct decoder")
+		return atc.Config{}, db.PipelineNoChange, false
+	case ErrCouldNotDecode:
+		session.Error("could-not-decode", err)
+		s.handleBadRequest(w, []string{"failed to decode config"}, session)
+		return atc.Config{}, db.PipelineNoChange, false
+	case ErrInvalidPausedValue:
+		session.Error("invalid-paused-value", err)
+		s.handleBadRequest(w, []string{"invalid paused value"}, session)
+		return atc.Config{}, db.PipelineNoChange, false
+	default:
+		if err != nil {
+			if eke, ok := err.(ExtraKeysError); ok {
+				s.handleBadRequest(w, []string{eke.Error()}, session)
+			} else {
+				session.Error("unexpected-error", err)
+				apierror.Internal(w, "unexpected error")
+			}
+
+			return atc.Config{}, db.PipelineNoChange, false
+		}
+	}
+
+	return config, pausedState, true
+}
+
 func (s *Server) handleBadRequest(w http.ResponseWriter, errorMessages []string, session lager.Logger) {
 	w.WriteHeader(http.StatusBadRequest)
 	s.writeSaveConfigResponse(w, SaveConfigResponse{
@@ -146,8 +173,7 @@ func (s *Server) writeSaveConfigResponse(w http.ResponseWriter, saveConfigRespon
 	responseJSON, err := json.Marshal(saveConfigResponse)
 	if err != nil {
 		session.Error("failed-to-marshal-validation-response", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		fmt.Fprintf(w, "failed to generate error response: %s", err)
+		apierror.Internal(w, fmt.Sprintf("failed to generate error response: %s", err))
 		return
 	}
 
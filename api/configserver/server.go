@@ -4,6 +4,7 @@ import (
 	"code.cloudfoundry.org/lager"
 	"github.com/concourse/atc"
 	"github.com/concourse/atc/config"
+	"github.com/concourse/atc/creds"
 	"github.com/concourse/atc/db"
 )
 
@@ -11,6 +12,9 @@ type Server struct {
 	logger        lager.Logger
 	teamDBFactory db.TeamDBFactory
 	validate      ConfigValidator
+
+	// nil if no credential manager is configured
+	credsManager creds.Manager
 }
 
 type ConfigValidator func(atc.Config) ([]config.Warning, []string)
@@ -19,10 +23,12 @@ func NewServer(
 	logger lager.Logger,
 	teamDBFactory db.TeamDBFactory,
 	validator ConfigValidator,
+	credsManager creds.Manager,
 ) *Server {
 	return &Server{
 		logger:        logger,
 		teamDBFactory: teamDBFactory,
 		validate:      validator,
+		credsManager:  credsManager,
 	}
 }
@@ -0,0 +1,87 @@
+package configserver
+
+import (
+	"fmt"
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/config"
+	"github.com/concourse/atc/creds"
+	"github.com/tedsuo/rata"
+)
+
+// ValidateConfig checks a config the same way as saving it would, without
+// saving it. As it's only a report, it responds with 200 OK even when the
+// config is invalid, unless it couldn't be decoded at all.
+func (s *Server) ValidateConfig(w http.ResponseWriter, r *http.Request) {
+	session := s.logger.Session("validate-config")
+
+	config, _, ok := s.decodeConfig(w, r, session)
+	if !ok {
+		return
+	}
+
+	warnings, errorMessages := s.validate(config)
+
+	credentialWarnings, err := s.checkCredentials(session, config, rata.Param(r, "team_name"), rata.Param(r, "pipeline_name"))
+	if err != nil {
+		session.Error("failed-to-find-credential-references", err)
+	}
+
+	w.WriteHeader(http.StatusOK)
+
+	s.writeSaveConfigResponse(w, SaveConfigResponse{
+		Errors:   errorMessages,
+		Warnings: append(warnings, credentialWarnings...),
+	}, session)
+}
+
+// checkCredentials warns about each ((name)) that won't be filled in when
+// builds run, as there's no default to fall back on.
+func (s *Server) checkCredentials(logger lager.Logger, c atc.Config, teamName string, pipelineName string) ([]config.Warning, error) {
+	names, err := creds.References(c)
+	if err != nil {
+		return nil, err
+	}
+
+	warnings := []config.Warning{}
+
+	if s.credsManager == nil {
+		for _, name := range names {
+			warnings = append(warnings, newCredentialWarning(
+				fmt.Sprintf("((%s)) will be left as it is, as no credential manager is configured", name),
+			))
+		}
+
+		return warnings, nil
+	}
+
+	variables := s.credsManager.Variables(teamName, pipelineName)
+
+	for _, name := range names {
+		_, found, err := variables.Get(name)
+		if err != nil {
+			logger.Error("failed-to-look-up-credential", err, lager.Data{"name": name})
+			warnings = append(warnings, newCredentialWarning(
+				fmt.Sprintf("((%s)) could not be looked up", name),
+			))
+			continue
+		}
+
+		if !found {
+			warnings = append(warnings, newCredentialWarning(
+				fmt.Sprintf("((%s)) is not defined for the pipeline or its team", name),
+			))
+		}
+	}
+
+	return warnings, nil
+}
+
+func newCredentialWarning(message string) config.Warning {
+	return config.Warning{
+		Type:    "credential",
+		Message: message,
+	}
+}
@@ -2,6 +2,7 @@ package api_test
 
 import (
 	"bufio"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -898,4 +899,226 @@ var _ = Describe("Containers API", func() {
 			})
 		})
 	})
+
+	Describe("GET /api/v1/builds/:build_id/hijack", func() {
+		var (
+			query string
+
+			response *http.Response
+		)
+
+		BeforeEach(func() {
+			query = "step_name=some-step&type=task"
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			response, err = client.Get(server.URL + "/api/v1/builds/" + strconv.Itoa(buildID) + "/hijack?" + query)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when not authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns 401 Unauthorized", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+
+			It("does not look for containers", func() {
+				Expect(teamDB.FindContainersByDescriptorsCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+
+				build.IDReturns(buildID)
+				build.TeamNameReturns("some-team")
+				buildsDB.GetBuildByIDReturns(build, true, nil)
+			})
+
+			Context("when the build belongs to another team", func() {
+				BeforeEach(func() {
+					userContextReader.GetTeamReturns("some-other-team", 43, false, true)
+				})
+
+				It("returns 403 Forbidden", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+				})
+			})
+
+			Context("when the build belongs to the team", func() {
+				BeforeEach(func() {
+					userContextReader.GetTeamReturns("some-team", 42, false, true)
+				})
+
+				It("looks for the build's containers matching the query", func() {
+					Expect(teamDBFactory.GetTeamDBArgsForCall(teamDBFactory.GetTeamDBCallCount() - 1)).To(Equal("some-team"))
+
+					Expect(teamDB.FindContainersByDescriptorsCallCount()).To(Equal(1))
+					Expect(teamDB.FindContainersByDescriptorsArgsForCall(0)).To(Equal(db.Container{
+						ContainerIdentifier: db.ContainerIdentifier{
+							BuildID: buildID,
+						},
+						ContainerMetadata: db.ContainerMetadata{
+							StepName: "some-step",
+							Type:     db.ContainerTypeTask,
+						},
+					}))
+				})
+
+				Context("when the query is malformed", func() {
+					BeforeEach(func() {
+						query = "type=bogus"
+					})
+
+					It("returns 400 Bad Request", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+					})
+				})
+
+				Context("when no containers match", func() {
+					BeforeEach(func() {
+						teamDB.FindContainersByDescriptorsReturns([]db.SavedContainer{}, nil)
+					})
+
+					It("returns 404 Not Found", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+					})
+				})
+
+				Context("when finding the containers fails", func() {
+					BeforeEach(func() {
+						teamDB.FindContainersByDescriptorsReturns(nil, errors.New("nope"))
+					})
+
+					It("returns 500 Internal Server Error", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+					})
+				})
+
+				Context("when more than one container matches", func() {
+					BeforeEach(func() {
+						teamDB.FindContainersByDescriptorsReturns([]db.SavedContainer{
+							{Container: db.Container{ContainerMetadata: db.ContainerMetadata{Handle: "some-handle"}}},
+							{Container: db.Container{ContainerMetadata: db.ContainerMetadata{Handle: "some-other-handle"}}},
+						}, nil)
+					})
+
+					It("returns 409 Conflict with the candidates", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusConflict))
+
+						var containers []atc.Container
+						err := json.NewDecoder(response.Body).Decode(&containers)
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(containers).To(HaveLen(2))
+						Expect(containers[0].ID).To(Equal("some-handle"))
+						Expect(containers[1].ID).To(Equal("some-other-handle"))
+					})
+
+					It("does not hijack anything", func() {
+						Expect(fakeWorkerClient.LookupContainerCallCount()).To(BeZero())
+					})
+				})
+			})
+		})
+
+		Context("when exactly one container matches", func() {
+			var (
+				conn *websocket.Conn
+
+				fakeContainer *workerfakes.FakeContainer
+				fakeProcess   *gfakes.FakeProcess
+				processExit   chan int
+			)
+
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("some-team", 42, false, true)
+
+				build.IDReturns(buildID)
+				build.TeamNameReturns("some-team")
+				buildsDB.GetBuildByIDReturns(build, true, nil)
+
+				teamDB.FindContainersByDescriptorsReturns([]db.SavedContainer{
+					{Container: db.Container{ContainerMetadata: db.ContainerMetadata{Handle: handle}}},
+				}, nil)
+
+				fakeContainer = new(workerfakes.FakeContainer)
+				fakeWorkerClient.LookupContainerReturns(fakeContainer, true, nil)
+
+				exit := make(chan int)
+				processExit = exit
+
+				fakeProcess = new(gfakes.FakeProcess)
+				fakeProcess.WaitStub = func() (int, error) {
+					return <-exit, nil
+				}
+
+				fakeContainer.RunReturns(fakeProcess, nil)
+			})
+
+			JustBeforeEach(func() {
+				wsURL, err := url.Parse(server.URL)
+				Expect(err).NotTo(HaveOccurred())
+
+				wsURL.Scheme = "ws"
+				wsURL.Path = "/api/v1/builds/" + strconv.Itoa(buildID) + "/hijack"
+
+				dialer := websocket.Dialer{}
+				conn, _, err = dialer.Dial(wsURL.String(), nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				err = conn.WriteJSON(atc.HijackProcessSpec{
+					Path: "bash",
+					TTY: &atc.HijackTTYSpec{
+						WindowSize: atc.HijackWindowSize{Columns: 80, Rows: 24},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			AfterEach(func() {
+				close(processExit)
+				conn.Close()
+			})
+
+			It("runs the process in the build's container", func() {
+				Eventually(fakeContainer.RunCallCount).Should(Equal(1))
+
+				_, lookedUpHandle := fakeWorkerClient.LookupContainerArgsForCall(0)
+				Expect(lookedUpHandle).To(Equal(handle))
+
+				spec, _ := fakeContainer.RunArgsForCall(0)
+				Expect(spec.Path).To(Equal("bash"))
+				Expect(spec.TTY).To(Equal(&garden.TTYSpec{
+					WindowSize: &garden.WindowSize{Columns: 80, Rows: 24},
+				}))
+			})
+
+			Context("when the window is resized", func() {
+				JustBeforeEach(func() {
+					Eventually(fakeContainer.RunCallCount).Should(Equal(1))
+
+					err := conn.WriteJSON(atc.HijackInput{
+						TTYSpec: &atc.HijackTTYSpec{
+							WindowSize: atc.HijackWindowSize{Columns: 120, Rows: 40},
+						},
+					})
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("resizes the process's TTY", func() {
+					Eventually(fakeProcess.SetTTYCallCount).Should(Equal(1))
+					Expect(fakeProcess.SetTTYArgsForCall(0)).To(Equal(garden.TTYSpec{
+						WindowSize: &garden.WindowSize{Columns: 120, Rows: 40},
+					}))
+				})
+			})
+		})
+	})
 })
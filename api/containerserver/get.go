@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/api/apierror"
 	"github.com/concourse/atc/api/present"
 	"github.com/concourse/atc/db"
 )
@@ -20,13 +21,13 @@ func (s *Server) GetContainer(teamDB db.TeamDB) http.Handler {
 		container, found, err := teamDB.GetContainer(handle)
 		if err != nil {
 			hLog.Error("failed-to-lookup-container", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.DBFailure(w, "failed to lookup container")
 			return
 		}
 
 		if !found {
 			hLog.Debug("container-not-found")
-			w.WriteHeader(http.StatusNotFound)
+			apierror.NotFound(w, "container not found")
 			return
 		}
 
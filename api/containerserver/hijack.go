@@ -9,6 +9,7 @@ import (
 	"code.cloudfoundry.org/garden"
 	"code.cloudfoundry.org/lager"
 	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
 	"github.com/concourse/atc/db"
 	"github.com/gorilla/websocket"
 )
@@ -28,41 +29,45 @@ func (s *Server) HijackContainer(teamDB db.TeamDB) http.Handler {
 		_, found, err := teamDB.GetContainer(handle)
 		if err != nil {
 			hLog.Error("failed-to-find-container", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.DBFailure(w, "failed to find container")
 			return
 		}
 
 		if !found {
 			hLog.Info("container-not-found")
-			w.WriteHeader(http.StatusNotFound)
+			apierror.NotFound(w, "container not found")
 			return
 		}
 
 		hLog.Debug("found-container")
 
-		conn, err := upgrader.Upgrade(w, r, nil)
-		if err != nil {
-			hLog.Error("unable-to-upgrade-connection-for-websockets", err)
-			return
-		}
+		s.upgradeAndHijack(hLog, w, r, handle)
+	})
+}
 
-		defer conn.Close()
+func (s *Server) upgradeAndHijack(hLog lager.Logger, w http.ResponseWriter, r *http.Request, handle string) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		hLog.Error("unable-to-upgrade-connection-for-websockets", err)
+		return
+	}
 
-		var processSpec atc.HijackProcessSpec
-		err = conn.ReadJSON(&processSpec)
-		if err != nil {
-			hLog.Error("malformed-process-spec", err)
-			closeWithErr(hLog, conn, websocket.CloseUnsupportedData, fmt.Sprintf("malformed process spec"))
-			return
-		}
+	defer conn.Close()
 
-		hijackRequest := hijackRequest{
-			ContainerHandle: handle,
-			Process:         processSpec,
-		}
+	var processSpec atc.HijackProcessSpec
+	err = conn.ReadJSON(&processSpec)
+	if err != nil {
+		hLog.Error("malformed-process-spec", err)
+		closeWithErr(hLog, conn, websocket.CloseUnsupportedData, fmt.Sprintf("malformed process spec"))
+		return
+	}
 
-		s.hijack(hLog, conn, hijackRequest)
-	})
+	hijackRequest := hijackRequest{
+		ContainerHandle: handle,
+		Process:         processSpec,
+	}
+
+	s.hijack(hLog, conn, hijackRequest)
 }
 
 type hijackRequest struct {
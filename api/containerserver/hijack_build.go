@@ -0,0 +1,67 @@
+package containerserver
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/api/present"
+	"github.com/concourse/atc/db"
+)
+
+// HijackBuild attaches a process to one of the containers running a build's
+// steps. The step is narrowed down with the same step_name, type and attempt
+// parameters as ListContainers; if more than one container matches, the
+// candidates are returned with 409 Conflict so the client can choose one and
+// hijack it by handle.
+func (s *Server) HijackBuild(build db.Build) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hLog := s.logger.Session("hijack-build", lager.Data{
+			"build-id": build.ID(),
+		})
+
+		descriptor, err := s.parseRequest(r)
+		if err != nil {
+			hLog.Info("malformed-request", lager.Data{"error": err.Error()})
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		descriptor.BuildID = build.ID()
+
+		teamDB := s.teamDBFactory.GetTeamDB(build.TeamName())
+
+		containers, err := teamDB.FindContainersByDescriptors(descriptor)
+		if err != nil {
+			hLog.Error("failed-to-find-containers", err)
+			apierror.DBFailure(w, "failed to find containers")
+			return
+		}
+
+		switch len(containers) {
+		case 0:
+			hLog.Info("no-containers-found")
+			apierror.NotFound(w, "no containers found")
+			return
+
+		case 1:
+
+		default:
+			hLog.Info("multiple-containers-found", lager.Data{"container-count": len(containers)})
+
+			presentedContainers := make([]atc.Container, len(containers))
+			for i, container := range containers {
+				presentedContainers[i] = present.Container(container)
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(presentedContainers)
+			return
+		}
+
+		s.upgradeAndHijack(hLog, w, r, containers[0].Handle)
+	})
+}
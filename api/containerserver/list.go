@@ -8,6 +8,7 @@ import (
 
 	"code.cloudfoundry.org/lager"
 	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
 	"github.com/concourse/atc/api/present"
 	"github.com/concourse/atc/db"
 )
@@ -33,7 +34,7 @@ func (s *Server) ListContainers(teamDB db.TeamDB) http.Handler {
 		containers, err := teamDB.FindContainersByDescriptors(containerDescriptor)
 		if err != nil {
 			hLog.Error("failed-to-find-containers", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.DBFailure(w, "failed to find containers")
 			return
 		}
 
@@ -0,0 +1,215 @@
+package api_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/db/dbfakes"
+)
+
+var _ = Describe("Dashboard API", func() {
+	Describe("GET /api/v1/dashboard", func() {
+		var response *http.Response
+		var dashboards []db.PipelineDashboard
+
+		BeforeEach(func() {
+			finishedBuild := new(dbfakes.FakeBuild)
+			finishedBuild.IDReturns(1)
+			finishedBuild.NameReturns("1")
+			finishedBuild.StatusReturns(db.StatusSucceeded)
+			finishedBuild.JobNameReturns("some-job")
+			finishedBuild.PipelineNameReturns("some-pipeline")
+			finishedBuild.TeamNameReturns("main")
+
+			olderRunningBuild := new(dbfakes.FakeBuild)
+			olderRunningBuild.IDReturns(2)
+			olderRunningBuild.NameReturns("2")
+			olderRunningBuild.StatusReturns(db.StatusStarted)
+			olderRunningBuild.JobNameReturns("some-job")
+			olderRunningBuild.PipelineNameReturns("some-pipeline")
+			olderRunningBuild.TeamNameReturns("main")
+
+			newerRunningBuild := new(dbfakes.FakeBuild)
+			newerRunningBuild.IDReturns(3)
+			newerRunningBuild.NameReturns("3")
+			newerRunningBuild.StatusReturns(db.StatusStarted)
+			newerRunningBuild.JobNameReturns("some-job")
+			newerRunningBuild.PipelineNameReturns("some-pipeline")
+			newerRunningBuild.TeamNameReturns("main")
+
+			dashboards = []db.PipelineDashboard{
+				{
+					Pipeline: db.SavedPipeline{
+						Paused:   true,
+						TeamName: "main",
+						Pipeline: db.Pipeline{
+							Name: "some-pipeline",
+							Config: atc.Config{
+								Groups: atc.GroupConfigs{
+									{Name: "some-group", Jobs: []string{"some-job"}},
+								},
+							},
+						},
+					},
+					Jobs: []db.PipelineDashboardJob{
+						{
+							Job: db.SavedJob{
+								PipelineName: "some-pipeline",
+								Job:          db.Job{Name: "some-job"},
+							},
+							JobConfig:     atc.JobConfig{Name: "some-job"},
+							FinishedBuild: finishedBuild,
+							RunningBuilds: []db.Build{newerRunningBuild, olderRunningBuild},
+						},
+						{
+							Job: db.SavedJob{
+								Paused:       true,
+								PipelineName: "some-pipeline",
+								Job:          db.Job{Name: "other-job"},
+							},
+							JobConfig:     atc.JobConfig{Name: "other-job"},
+							RunningBuilds: []db.Build{},
+						},
+					},
+				},
+			}
+
+			teamDB.GetPipelineDashboardsReturns(dashboards, nil)
+			pipelinesDB.GetPublicPipelineDashboardsReturns(dashboards, nil)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+
+			response, err = client.Get(server.URL + "/api/v1/dashboard")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when not authenticated", func() {
+			BeforeEach(func() {
+				userContextReader.GetTeamReturns("", 0, false, false)
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns 200 OK", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+			})
+
+			It("returns application/json", func() {
+				Expect(response.Header.Get("Content-Type")).To(Equal("application/json"))
+			})
+
+			It("returns the dashboards of public pipelines", func() {
+				Expect(pipelinesDB.GetPublicPipelineDashboardsCallCount()).To(Equal(1))
+				Expect(teamDB.GetPipelineDashboardsCallCount()).To(BeZero())
+
+				var presented []atc.DashboardPipeline
+				err := json.NewDecoder(response.Body).Decode(&presented)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(presented).To(HaveLen(1))
+				Expect(presented[0].Name).To(Equal("some-pipeline"))
+				Expect(presented[0].Paused).To(BeTrue())
+
+				jobs := presented[0].Jobs
+				Expect(jobs).To(HaveLen(2))
+
+				Expect(jobs[0].Name).To(Equal("some-job"))
+				Expect(jobs[0].Groups).To(Equal([]string{"some-group"}))
+				Expect(jobs[0].FinishedBuild.ID).To(Equal(1))
+				Expect(jobs[0].NextBuild.ID).To(Equal(2))
+				Expect(jobs[0].RunningBuilds).To(HaveLen(2))
+				Expect(jobs[0].RunningBuilds[0].ID).To(Equal(3))
+				Expect(jobs[0].RunningBuilds[1].ID).To(Equal(2))
+
+				Expect(jobs[1].Name).To(Equal("other-job"))
+				Expect(jobs[1].Paused).To(BeTrue())
+				Expect(jobs[1].FinishedBuild).To(BeNil())
+				Expect(jobs[1].NextBuild).To(BeNil())
+				Expect(jobs[1].RunningBuilds).To(BeEmpty())
+			})
+
+			Context("when getting the dashboards fails", func() {
+				BeforeEach(func() {
+					pipelinesDB.GetPublicPipelineDashboardsReturns(nil, errors.New("nope"))
+				})
+
+				It("returns 500 Internal Server Error", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+
+		Context("when authenticated", func() {
+			BeforeEach(func() {
+				userContextReader.GetTeamReturns("main", 5, false, true)
+				authValidator.IsAuthenticatedReturns(true)
+			})
+
+			It("returns the dashboards of the team's pipelines and public ones", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+				Expect(teamDBFactory.GetTeamDBCallCount()).To(Equal(1))
+				Expect(teamDBFactory.GetTeamDBArgsForCall(0)).To(Equal("main"))
+				Expect(teamDB.GetPipelineDashboardsCallCount()).To(Equal(1))
+				Expect(pipelinesDB.GetPublicPipelineDashboardsCallCount()).To(BeZero())
+			})
+
+			Context("when some pipelines need a higher role to view them than the request has", func() {
+				BeforeEach(func() {
+					userContextReader.GetRoleReturns(atc.RoleViewer, true)
+
+					teamDB.GetPipelineDashboardsReturns([]db.PipelineDashboard{
+						{
+							Pipeline: db.SavedPipeline{
+								TeamName:    "main",
+								Permissions: atc.PipelinePermissions{View: atc.RoleOperator},
+								Pipeline:    db.Pipeline{Name: "hidden-pipeline"},
+							},
+						},
+						{
+							Pipeline: db.SavedPipeline{
+								TeamName:    "main",
+								Public:      true,
+								Permissions: atc.PipelinePermissions{View: atc.RoleOperator},
+								Pipeline:    db.Pipeline{Name: "public-pipeline"},
+							},
+						},
+						{
+							Pipeline: db.SavedPipeline{
+								TeamName: "main",
+								Pipeline: db.Pipeline{Name: "viewable-pipeline"},
+							},
+						},
+					}, nil)
+				})
+
+				It("leaves out the private ones", func() {
+					var presented []atc.DashboardPipeline
+					err := json.NewDecoder(response.Body).Decode(&presented)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(presented).To(HaveLen(2))
+					Expect(presented[0].Name).To(Equal("public-pipeline"))
+					Expect(presented[1].Name).To(Equal("viewable-pipeline"))
+				})
+			})
+
+			Context("when getting the dashboards fails", func() {
+				BeforeEach(func() {
+					teamDB.GetPipelineDashboardsReturns(nil, errors.New("nope"))
+				})
+
+				It("returns 500 Internal Server Error", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+	})
+})
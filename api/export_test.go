@@ -0,0 +1,324 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/db/dbfakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Export API", func() {
+	var pipelineDB *dbfakes.FakePipelineDB
+
+	BeforeEach(func() {
+		pipelineDB = new(dbfakes.FakePipelineDB)
+		pipelineDBFactory.BuildReturns(pipelineDB)
+	})
+
+	Describe("GET /api/v1/export", func() {
+		var (
+			query    string
+			response *http.Response
+		)
+
+		BeforeEach(func() {
+			query = ""
+
+			teamServerDB.GetTeamsReturns([]db.SavedTeam{{Team: db.Team{Name: "some-team"}}}, nil)
+
+			teamDB.GetPipelinesReturns([]db.SavedPipeline{
+				{
+					ID:       1,
+					Paused:   true,
+					TeamName: "some-team",
+					Pipeline: db.Pipeline{
+						Name: "some-pipeline",
+						Config: atc.Config{
+							Jobs:      atc.JobConfigs{{Name: "some-job"}},
+							Resources: atc.ResourceConfigs{{Name: "some-resource", Type: "git"}},
+						},
+					},
+				},
+			}, nil)
+
+			pipelineDB.GetAllResourceVersionsReturns([]db.SavedVersionedResource{
+				{
+					Enabled: true,
+					VersionedResource: db.VersionedResource{
+						Version:  db.Version{"ref": "abc"},
+						Metadata: []db.MetadataField{{Name: "author", Value: "someone"}},
+					},
+				},
+				{
+					Enabled: false,
+					VersionedResource: db.VersionedResource{
+						Version: db.Version{"ref": "def"},
+					},
+				},
+			}, nil)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			response, err = client.Get(server.URL + "/api/v1/export" + query)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when authenticated as a team that isn't an admin", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("some-team", 5, false, true)
+			})
+
+			It("returns 403", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+				Expect(teamServerDB.GetTeamsCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when authenticated as an admin", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("main", 1, true, true)
+			})
+
+			It("exports every pipeline with its resource versions", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+				Expect(response.Header.Get("Content-Type")).To(Equal("application/json"))
+
+				var export atc.Export
+				err := json.NewDecoder(response.Body).Decode(&export)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(export.FormatVersion).To(Equal(atc.ExportFormatVersion))
+				Expect(export.Pipelines).To(HaveLen(1))
+
+				pipeline := export.Pipelines[0]
+				Expect(pipeline.TeamName).To(Equal("some-team"))
+				Expect(pipeline.Name).To(Equal("some-pipeline"))
+				Expect(pipeline.Paused).To(BeTrue())
+				Expect(pipeline.ResourceVersions).To(Equal(map[string][]atc.ExportedVersion{
+					"some-resource": {
+						{Version: atc.Version{"ref": "abc"}, Metadata: []atc.MetadataField{{Name: "author", Value: "someone"}}, Enabled: true},
+						{Version: atc.Version{"ref": "def"}, Enabled: false},
+					},
+				}))
+				Expect(pipeline.Builds).To(BeEmpty())
+
+				Expect(pipelineDB.GetJobBuildsCallCount()).To(BeZero())
+			})
+
+			Context("when asked for builds", func() {
+				BeforeEach(func() {
+					query = "?builds=2"
+
+					finished := new(dbfakes.FakeBuild)
+					finished.NameReturns("3")
+					finished.StatusReturns(db.StatusSucceeded)
+					finished.StartTimeReturns(time.Unix(100, 0))
+					finished.EndTimeReturns(time.Unix(200, 0))
+
+					running := new(dbfakes.FakeBuild)
+					running.NameReturns("4")
+					running.StatusReturns(db.StatusStarted)
+
+					pipelineDB.GetJobBuildsReturns([]db.Build{running, finished}, db.Pagination{}, nil)
+				})
+
+				It("exports the finished ones", func() {
+					Expect(pipelineDB.GetJobBuildsCallCount()).To(Equal(1))
+
+					jobName, page := pipelineDB.GetJobBuildsArgsForCall(0)
+					Expect(jobName).To(Equal("some-job"))
+					Expect(page).To(Equal(db.Page{Limit: 2}))
+
+					var export atc.Export
+					err := json.NewDecoder(response.Body).Decode(&export)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(export.Pipelines[0].Builds).To(Equal([]atc.ExportedBuild{
+						{JobName: "some-job", Name: "3", Status: atc.StatusSucceeded, StartTime: 100, EndTime: 200},
+					}))
+				})
+			})
+
+			Context("when the number of builds is malformed", func() {
+				BeforeEach(func() {
+					query = "?builds=lots"
+				})
+
+				It("returns 400", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+				})
+			})
+
+			Context("when getting the versions fails", func() {
+				BeforeEach(func() {
+					pipelineDB.GetAllResourceVersionsReturns(nil, errors.New("nope"))
+				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+	})
+
+	Describe("POST /api/v1/import", func() {
+		var (
+			export   atc.Export
+			response *http.Response
+		)
+
+		BeforeEach(func() {
+			export = atc.Export{
+				FormatVersion: atc.ExportFormatVersion,
+				Pipelines: []atc.ExportedPipeline{
+					{
+						TeamName: "some-team",
+						Name:     "some-pipeline",
+						Public:   true,
+						Config: atc.Config{
+							Jobs:      atc.JobConfigs{{Name: "some-job"}},
+							Resources: atc.ResourceConfigs{{Name: "some-resource", Type: "git"}},
+						},
+						ResourceVersions: map[string][]atc.ExportedVersion{
+							"some-resource": {
+								{Version: atc.Version{"ref": "abc"}, Enabled: false},
+							},
+						},
+						Builds: []atc.ExportedBuild{
+							{JobName: "some-job", Name: "3", Status: atc.StatusFailed, StartTime: 100, EndTime: 200},
+						},
+					},
+				},
+			}
+
+			teamDB.GetTeamReturns(db.SavedTeam{}, true, nil)
+			teamDB.GetPipelineByNameReturns(db.SavedPipeline{}, false, nil)
+			teamDB.SaveConfigReturns(db.SavedPipeline{ID: 1}, true, nil)
+
+			authValidator.IsAuthenticatedReturns(true)
+			userContextReader.GetTeamReturns("main", 1, true, true)
+		})
+
+		JustBeforeEach(func() {
+			payload, err := json.Marshal(export)
+			Expect(err).NotTo(HaveOccurred())
+
+			response, err = client.Post(server.URL+"/api/v1/import", "application/json", bytes.NewBuffer(payload))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns 204", func() {
+			Expect(response.StatusCode).To(Equal(http.StatusNoContent))
+		})
+
+		It("saves the pipeline's config", func() {
+			Expect(teamDBFactory.GetTeamDBArgsForCall(0)).To(Equal("some-team"))
+			Expect(teamDB.SaveConfigCallCount()).To(Equal(1))
+
+			name, config, _, pausedState, _ := teamDB.SaveConfigArgsForCall(0)
+			Expect(name).To(Equal("some-pipeline"))
+			Expect(config).To(Equal(export.Pipelines[0].Config))
+			Expect(pausedState).To(Equal(db.PipelineUnpaused))
+
+			Expect(pipelineDB.ExposeCallCount()).To(Equal(1))
+		})
+
+		It("imports the resource versions", func() {
+			Expect(pipelineDB.ImportResourceVersionsCallCount()).To(Equal(1))
+
+			resourceConfig, versions := pipelineDB.ImportResourceVersionsArgsForCall(0)
+			Expect(resourceConfig.Name).To(Equal("some-resource"))
+			Expect(versions).To(HaveLen(1))
+			Expect(versions[0].Version).To(Equal(db.Version{"ref": "abc"}))
+			Expect(versions[0].Enabled).To(BeFalse())
+		})
+
+		It("imports the builds", func() {
+			Expect(pipelineDB.ImportJobBuildCallCount()).To(Equal(1))
+
+			jobName, build := pipelineDB.ImportJobBuildArgsForCall(0)
+			Expect(jobName).To(Equal("some-job"))
+			Expect(build).To(Equal(db.ImportedBuild{
+				Name:      "3",
+				Status:    db.StatusFailed,
+				StartTime: time.Unix(100, 0),
+				EndTime:   time.Unix(200, 0),
+			}))
+		})
+
+		Context("when the pipeline already exists", func() {
+			BeforeEach(func() {
+				teamDB.GetPipelineByNameReturns(db.SavedPipeline{}, true, nil)
+			})
+
+			It("returns 409 without importing anything", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusConflict))
+				Expect(teamDB.SaveConfigCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when the team does not exist", func() {
+			BeforeEach(func() {
+				teamDB.GetTeamReturns(db.SavedTeam{}, false, nil)
+			})
+
+			It("returns 422", func() {
+				Expect(response.StatusCode).To(Equal(422))
+				Expect(teamDB.SaveConfigCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when a build has not finished", func() {
+			BeforeEach(func() {
+				export.Pipelines[0].Builds[0].Status = atc.StatusStarted
+			})
+
+			It("returns 422", func() {
+				Expect(response.StatusCode).To(Equal(422))
+				Expect(teamDB.SaveConfigCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when the export is in a format this ATC doesn't know", func() {
+			BeforeEach(func() {
+				export.FormatVersion = atc.ExportFormatVersion + 1
+			})
+
+			It("returns 422", func() {
+				Expect(response.StatusCode).To(Equal(422))
+			})
+		})
+
+		Context("when saving the config fails", func() {
+			BeforeEach(func() {
+				teamDB.SaveConfigReturns(db.SavedPipeline{}, false, errors.New("nope"))
+			})
+
+			It("returns 500", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+		})
+
+		Context("when not an admin", func() {
+			BeforeEach(func() {
+				userContextReader.GetTeamReturns("some-team", 5, false, true)
+			})
+
+			It("returns 403", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+				Expect(teamDB.SaveConfigCallCount()).To(BeZero())
+			})
+		})
+	})
+})
@@ -0,0 +1,161 @@
+package exportserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/db"
+)
+
+// maxExportedBuilds bounds ?builds=, as every build is held in memory
+// while the export is put together.
+const maxExportedBuilds = 100
+
+// Export serializes every team's pipelines, along with their resources'
+// versions and, with ?builds=N, the last N finished builds of each job.
+func (s *Server) Export(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.Session("export")
+
+	buildLimit := 0
+	if value := r.URL.Query().Get("builds"); value != "" {
+		var err error
+		buildLimit, err = strconv.Atoi(value)
+		if err != nil || buildLimit < 0 {
+			http.Error(w, "builds must be a non-negative number", http.StatusBadRequest)
+			return
+		}
+
+		if buildLimit > maxExportedBuilds {
+			buildLimit = maxExportedBuilds
+		}
+	}
+
+	teams, err := s.teamsDB.GetTeams()
+	if err != nil {
+		logger.Error("failed-to-get-teams", err)
+		apierror.DBFailure(w, "failed to get teams")
+		return
+	}
+
+	export := atc.Export{
+		FormatVersion: atc.ExportFormatVersion,
+		ExportedAt:    s.clock.Now().Unix(),
+		Pipelines:     []atc.ExportedPipeline{},
+	}
+
+	for _, team := range teams {
+		pipelines, err := s.teamDBFactory.GetTeamDB(team.Name).GetPipelines()
+		if err != nil {
+			logger.Error("failed-to-get-pipelines", err, lager.Data{"team": team.Name})
+			apierror.DBFailure(w, "failed to get pipelines")
+			return
+		}
+
+		for _, pipeline := range pipelines {
+			exported, err := s.exportPipeline(pipeline, buildLimit)
+			if err != nil {
+				logger.Error("failed-to-export-pipeline", err, lager.Data{"team": team.Name, "pipeline": pipeline.Name})
+				apierror.DBFailure(w, "failed to export pipeline")
+				return
+			}
+
+			export.Pipelines = append(export.Pipelines, exported)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", `attachment; filename="atc-export.json"`)
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(export)
+}
+
+func (s *Server) exportPipeline(pipeline db.SavedPipeline, buildLimit int) (atc.ExportedPipeline, error) {
+	pipelineDB := s.pipelineDBFactory.Build(pipeline)
+
+	exported := atc.ExportedPipeline{
+		TeamName: pipeline.TeamName,
+		Name:     pipeline.Name,
+		Paused:   pipeline.Paused,
+		Public:   pipeline.Public,
+		Config:   pipeline.Config,
+	}
+
+	for _, resource := range pipeline.Config.Resources {
+		versions, err := pipelineDB.GetAllResourceVersions(resource.Name)
+		if err != nil {
+			return atc.ExportedPipeline{}, err
+		}
+
+		if len(versions) == 0 {
+			continue
+		}
+
+		if exported.ResourceVersions == nil {
+			exported.ResourceVersions = map[string][]atc.ExportedVersion{}
+		}
+
+		for _, version := range versions {
+			exported.ResourceVersions[resource.Name] = append(exported.ResourceVersions[resource.Name], atc.ExportedVersion{
+				Version:  atc.Version(version.Version),
+				Metadata: exportMetadata(version.Metadata),
+				Enabled:  version.Enabled,
+			})
+		}
+	}
+
+	if buildLimit == 0 {
+		return exported, nil
+	}
+
+	for _, job := range pipeline.Config.Jobs {
+		builds, _, err := pipelineDB.GetJobBuilds(job.Name, db.Page{Limit: buildLimit})
+		if err != nil {
+			return atc.ExportedPipeline{}, err
+		}
+
+		// builds come newest first, but are imported in the order given
+		for i := len(builds) - 1; i >= 0; i-- {
+			build := builds[i]
+
+			if build.Status() == db.StatusPending || build.Status() == db.StatusStarted {
+				continue
+			}
+
+			exportedBuild := atc.ExportedBuild{
+				JobName: job.Name,
+				Name:    build.Name(),
+				Status:  atc.BuildStatus(build.Status()),
+			}
+
+			if !build.StartTime().IsZero() {
+				exportedBuild.StartTime = build.StartTime().Unix()
+			}
+
+			if !build.EndTime().IsZero() {
+				exportedBuild.EndTime = build.EndTime().Unix()
+			}
+
+			exported.Builds = append(exported.Builds, exportedBuild)
+		}
+	}
+
+	return exported, nil
+}
+
+func exportMetadata(metadata []db.MetadataField) []atc.MetadataField {
+	if len(metadata) == 0 {
+		return nil
+	}
+
+	exported := make([]atc.MetadataField, len(metadata))
+	for i, field := range metadata {
+		exported[i] = atc.MetadataField{Name: field.Name, Value: field.Value}
+	}
+
+	return exported
+}
@@ -0,0 +1,167 @@
+package exportserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/auth"
+	"github.com/concourse/atc/db"
+)
+
+// Import recreates the pipelines in an export. Every team they belong to
+// must already exist, and none of the pipelines may; nothing is imported
+// unless that holds for all of them.
+func (s *Server) Import(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.Session("import")
+
+	var export atc.Export
+	err := json.NewDecoder(r.Body).Decode(&export)
+	if err != nil {
+		logger.Info("malformed-request", lager.Data{"error": err.Error()})
+		http.Error(w, "malformed export", http.StatusBadRequest)
+		return
+	}
+
+	if export.FormatVersion != atc.ExportFormatVersion {
+		http.Error(w, fmt.Sprintf("unsupported export format version %d", export.FormatVersion), http.StatusUnprocessableEntity)
+		return
+	}
+
+	for _, pipeline := range export.Pipelines {
+		teamDB := s.teamDBFactory.GetTeamDB(pipeline.TeamName)
+
+		_, found, err := teamDB.GetTeam()
+		if err != nil {
+			logger.Error("failed-to-get-team", err, lager.Data{"team": pipeline.TeamName})
+			apierror.DBFailure(w, "failed to get team")
+			return
+		}
+
+		if !found {
+			http.Error(w, fmt.Sprintf("team '%s' does not exist", pipeline.TeamName), http.StatusUnprocessableEntity)
+			return
+		}
+
+		_, found, err = teamDB.GetPipelineByName(pipeline.Name)
+		if err != nil {
+			logger.Error("failed-to-get-pipeline", err, lager.Data{"team": pipeline.TeamName, "pipeline": pipeline.Name})
+			apierror.DBFailure(w, "failed to get pipeline")
+			return
+		}
+
+		if found {
+			http.Error(w, fmt.Sprintf("pipeline '%s' already exists in team '%s'", pipeline.Name, pipeline.TeamName), http.StatusConflict)
+			return
+		}
+
+		for _, build := range pipeline.Builds {
+			switch build.Status {
+			case atc.StatusSucceeded, atc.StatusFailed, atc.StatusErrored, atc.StatusAborted, atc.StatusTimedOut:
+			default:
+				http.Error(w, fmt.Sprintf("build '%s/%s' has not finished", build.JobName, build.Name), http.StatusUnprocessableEntity)
+				return
+			}
+		}
+	}
+
+	author := auth.GetActor(r)
+
+	for _, pipeline := range export.Pipelines {
+		pipelineLogger := logger.Session("pipeline", lager.Data{"team": pipeline.TeamName, "pipeline": pipeline.Name})
+
+		err := s.importPipeline(pipeline, author)
+		if err != nil {
+			pipelineLogger.Error("failed-to-import", err)
+			apierror.DBFailure(w, fmt.Sprintf("failed to import pipeline '%s'", pipeline.Name))
+			return
+		}
+
+		pipelineLogger.Info("imported")
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) importPipeline(pipeline atc.ExportedPipeline, author string) error {
+	pausedState := db.PipelineUnpaused
+	if pipeline.Paused {
+		pausedState = db.PipelinePaused
+	}
+
+	savedPipeline, _, err := s.teamDBFactory.GetTeamDB(pipeline.TeamName).SaveConfig(pipeline.Name, pipeline.Config, 0, pausedState, author)
+	if err != nil {
+		return err
+	}
+
+	pipelineDB := s.pipelineDBFactory.Build(savedPipeline)
+
+	if pipeline.Public {
+		err := pipelineDB.Expose()
+		if err != nil {
+			return err
+		}
+	}
+
+	for name, versions := range pipeline.ResourceVersions {
+		resourceConfig, found := pipeline.Config.Resources.Lookup(name)
+		if !found {
+			continue
+		}
+
+		imported := make([]db.SavedVersionedResource, len(versions))
+		for i, version := range versions {
+			imported[i] = db.SavedVersionedResource{
+				Enabled: version.Enabled,
+				VersionedResource: db.VersionedResource{
+					Version:  db.Version(version.Version),
+					Metadata: importMetadata(version.Metadata),
+				},
+			}
+		}
+
+		err := pipelineDB.ImportResourceVersions(resourceConfig, imported)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, build := range pipeline.Builds {
+		if _, found := pipeline.Config.Jobs.Lookup(build.JobName); !found {
+			continue
+		}
+
+		_, err := pipelineDB.ImportJobBuild(build.JobName, db.ImportedBuild{
+			Name:      build.Name,
+			Status:    db.Status(build.Status),
+			StartTime: unixTime(build.StartTime),
+			EndTime:   unixTime(build.EndTime),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func importMetadata(metadata []atc.MetadataField) []db.MetadataField {
+	imported := make([]db.MetadataField, len(metadata))
+	for i, field := range metadata {
+		imported[i] = db.MetadataField{Name: field.Name, Value: field.Value}
+	}
+
+	return imported
+}
+
+func unixTime(seconds int64) time.Time {
+	if seconds == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(seconds, 0)
+}
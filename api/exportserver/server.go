@@ -0,0 +1,38 @@
+package exportserver
+
+import (
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/db"
+)
+
+type Server struct {
+	logger lager.Logger
+
+	teamsDB           TeamsDB
+	teamDBFactory     db.TeamDBFactory
+	pipelineDBFactory db.PipelineDBFactory
+	clock             clock.Clock
+}
+
+//go:generate counterfeiter . TeamsDB
+
+type TeamsDB interface {
+	GetTeams() ([]db.SavedTeam, error)
+}
+
+func NewServer(
+	logger lager.Logger,
+	teamsDB TeamsDB,
+	teamDBFactory db.TeamDBFactory,
+	pipelineDBFactory db.PipelineDBFactory,
+	clock clock.Clock,
+) *Server {
+	return &Server{
+		logger:            logger,
+		teamsDB:           teamsDB,
+		teamDBFactory:     teamDBFactory,
+		pipelineDBFactory: pipelineDBFactory,
+		clock:             clock,
+	}
+}
@@ -0,0 +1,196 @@
+package api_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/db/dbfakes"
+	"github.com/concourse/atc/lostandfound"
+)
+
+var _ = Describe("GC API", func() {
+	Describe("GET /api/v1/gc", func() {
+		var response *http.Response
+
+		JustBeforeEach(func() {
+			var err error
+
+			response, err = client.Get(server.URL + "/api/v1/gc")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when not authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns 401", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+
+		Context("when authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+
+				gcDB.GetWorkerGCStatsReturns([]db.WorkerGCStats{
+					{
+						WorkerName:          "some-worker",
+						ContainersCreated:   10,
+						ContainersDestroyed: 7,
+						ContainersOrphaned:  1,
+						VolumesCreated:      20,
+						VolumesDestroyed:    15,
+						VolumesOrphaned:     2,
+					},
+				}, nil)
+			})
+
+			Context("when a pass has completed", func() {
+				BeforeEach(func() {
+					gcDB.GetLastGCPassReturns(db.GCPass{
+						StartedAt:  time.Unix(100, 0),
+						FinishedAt: time.Unix(102, int64(500*time.Millisecond)),
+					}, true, nil)
+				})
+
+				It("returns 200 OK", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+				})
+
+				It("returns the last pass and the counts for each worker", func() {
+					body, err := ioutil.ReadAll(response.Body)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(body).To(MatchJSON(`{
+						"last_pass_started_at": 100,
+						"last_pass_duration_ms": 2500,
+						"workers": [
+							{
+								"worker_name": "some-worker",
+								"containers": {"created": 10, "destroyed": 7, "orphaned": 1},
+								"volumes": {"created": 20, "destroyed": 15, "orphaned": 2}
+							}
+						]
+					}`))
+				})
+			})
+
+			Context("when no pass has completed yet", func() {
+				BeforeEach(func() {
+					gcDB.GetLastGCPassReturns(db.GCPass{}, false, nil)
+				})
+
+				It("leaves out the last pass", func() {
+					body, err := ioutil.ReadAll(response.Body)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(body).NotTo(ContainSubstring("last_pass"))
+				})
+			})
+
+			Context("when looking up the last pass fails", func() {
+				BeforeEach(func() {
+					gcDB.GetLastGCPassReturns(db.GCPass{}, false, errors.New("nope"))
+				})
+
+				It("returns 500 Internal Server Error", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+
+			Context("when looking up the counts fails", func() {
+				BeforeEach(func() {
+					gcDB.GetWorkerGCStatsReturns(nil, errors.New("nope"))
+				})
+
+				It("returns 500 Internal Server Error", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+	})
+
+	Describe("POST /api/v1/gc", func() {
+		var response *http.Response
+
+		JustBeforeEach(func() {
+			var err error
+
+			response, err = client.Post(server.URL+"/api/v1/gc", "", nil)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when authenticated but not admin", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+			})
+
+			It("returns 403", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+			})
+
+			It("doesn't collect garbage", func() {
+				Expect(fakeGCCollector.RunCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when authenticated as an admin", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("main", 1, true, true)
+			})
+
+			Context("when no pass is running", func() {
+				var fakeLock *dbfakes.FakeLock
+
+				BeforeEach(func() {
+					fakeLock = new(dbfakes.FakeLock)
+					gcDB.GetTaskLockReturns(fakeLock, true, nil)
+				})
+
+				It("returns 204 No Content", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusNoContent))
+				})
+
+				It("collects garbage while holding the collector's lock", func() {
+					_, taskName := gcDB.GetTaskLockArgsForCall(0)
+					Expect(taskName).To(Equal(lostandfound.TaskName))
+
+					Expect(fakeGCCollector.RunCallCount()).To(Equal(1))
+					Expect(fakeLock.ReleaseCallCount()).To(Equal(1))
+				})
+
+				Context("when collecting garbage fails", func() {
+					BeforeEach(func() {
+						fakeGCCollector.RunReturns(errors.New("nope"))
+					})
+
+					It("returns 500 Internal Server Error", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+					})
+				})
+			})
+
+			Context("when a pass is already running", func() {
+				BeforeEach(func() {
+					gcDB.GetTaskLockReturns(nil, false, nil)
+				})
+
+				It("returns 409 Conflict", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusConflict))
+				})
+
+				It("doesn't collect garbage", func() {
+					Expect(fakeGCCollector.RunCallCount()).To(BeZero())
+				})
+			})
+		})
+	})
+})
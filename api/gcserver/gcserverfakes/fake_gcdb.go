@@ -0,0 +1,156 @@
+// This file was generated by counterfeiter
+package gcserverfakes
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/api/gcserver"
+	"github.com/concourse/atc/db"
+)
+
+type FakeGCDB struct {
+	GetWorkerGCStatsStub        func() ([]db.WorkerGCStats, error)
+	getWorkerGCStatsMutex       sync.RWMutex
+	getWorkerGCStatsArgsForCall []struct{}
+	getWorkerGCStatsReturns     struct {
+		result1 []db.WorkerGCStats
+		result2 error
+	}
+	GetLastGCPassStub        func() (db.GCPass, bool, error)
+	getLastGCPassMutex       sync.RWMutex
+	getLastGCPassArgsForCall []struct{}
+	getLastGCPassReturns     struct {
+		result1 db.GCPass
+		result2 bool
+		result3 error
+	}
+	GetTaskLockStub        func(logger lager.Logger, taskName string) (db.Lock, bool, error)
+	getTaskLockMutex       sync.RWMutex
+	getTaskLockArgsForCall []struct {
+		logger   lager.Logger
+		taskName string
+	}
+	getTaskLockReturns struct {
+		result1 db.Lock
+		result2 bool
+		result3 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeGCDB) GetWorkerGCStats() ([]db.WorkerGCStats, error) {
+	fake.getWorkerGCStatsMutex.Lock()
+	fake.getWorkerGCStatsArgsForCall = append(fake.getWorkerGCStatsArgsForCall, struct{}{})
+	fake.recordInvocation("GetWorkerGCStats", []interface{}{})
+	fake.getWorkerGCStatsMutex.Unlock()
+	if fake.GetWorkerGCStatsStub != nil {
+		return fake.GetWorkerGCStatsStub()
+	} else {
+		return fake.getWorkerGCStatsReturns.result1, fake.getWorkerGCStatsReturns.result2
+	}
+}
+
+func (fake *FakeGCDB) GetWorkerGCStatsCallCount() int {
+	fake.getWorkerGCStatsMutex.RLock()
+	defer fake.getWorkerGCStatsMutex.RUnlock()
+	return len(fake.getWorkerGCStatsArgsForCall)
+}
+
+func (fake *FakeGCDB) GetWorkerGCStatsReturns(result1 []db.WorkerGCStats, result2 error) {
+	fake.GetWorkerGCStatsStub = nil
+	fake.getWorkerGCStatsReturns = struct {
+		result1 []db.WorkerGCStats
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGCDB) GetLastGCPass() (db.GCPass, bool, error) {
+	fake.getLastGCPassMutex.Lock()
+	fake.getLastGCPassArgsForCall = append(fake.getLastGCPassArgsForCall, struct{}{})
+	fake.recordInvocation("GetLastGCPass", []interface{}{})
+	fake.getLastGCPassMutex.Unlock()
+	if fake.GetLastGCPassStub != nil {
+		return fake.GetLastGCPassStub()
+	} else {
+		return fake.getLastGCPassReturns.result1, fake.getLastGCPassReturns.result2, fake.getLastGCPassReturns.result3
+	}
+}
+
+func (fake *FakeGCDB) GetLastGCPassCallCount() int {
+	fake.getLastGCPassMutex.RLock()
+	defer fake.getLastGCPassMutex.RUnlock()
+	return len(fake.getLastGCPassArgsForCall)
+}
+
+func (fake *FakeGCDB) GetLastGCPassReturns(result1 db.GCPass, result2 bool, result3 error) {
+	fake.GetLastGCPassStub = nil
+	fake.getLastGCPassReturns = struct {
+		result1 db.GCPass
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeGCDB) GetTaskLock(logger lager.Logger, taskName string) (db.Lock, bool, error) {
+	fake.getTaskLockMutex.Lock()
+	fake.getTaskLockArgsForCall = append(fake.getTaskLockArgsForCall, struct {
+		logger   lager.Logger
+		taskName string
+	}{logger, taskName})
+	fake.recordInvocation("GetTaskLock", []interface{}{logger, taskName})
+	fake.getTaskLockMutex.Unlock()
+	if fake.GetTaskLockStub != nil {
+		return fake.GetTaskLockStub(logger, taskName)
+	} else {
+		return fake.getTaskLockReturns.result1, fake.getTaskLockReturns.result2, fake.getTaskLockReturns.result3
+	}
+}
+
+func (fake *FakeGCDB) GetTaskLockCallCount() int {
+	fake.getTaskLockMutex.RLock()
+	defer fake.getTaskLockMutex.RUnlock()
+	return len(fake.getTaskLockArgsForCall)
+}
+
+func (fake *FakeGCDB) GetTaskLockArgsForCall(i int) (lager.Logger, string) {
+	fake.getTaskLockMutex.RLock()
+	defer fake.getTaskLockMutex.RUnlock()
+	return fake.getTaskLockArgsForCall[i].logger, fake.getTaskLockArgsForCall[i].taskName
+}
+
+func (fake *FakeGCDB) GetTaskLockReturns(result1 db.Lock, result2 bool, result3 error) {
+	fake.GetTaskLockStub = nil
+	fake.getTaskLockReturns = struct {
+		result1 db.Lock
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeGCDB) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.getWorkerGCStatsMutex.RLock()
+	defer fake.getWorkerGCStatsMutex.RUnlock()
+	fake.getLastGCPassMutex.RLock()
+	defer fake.getLastGCPassMutex.RUnlock()
+	fake.getTaskLockMutex.RLock()
+	defer fake.getTaskLockMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeGCDB) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ gcserver.GCDB = new(FakeGCDB)
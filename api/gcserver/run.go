@@ -0,0 +1,29 @@
+package gcserver
+
+import (
+	"net/http"
+
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/lockrunner"
+	"github.com/concourse/atc/lostandfound"
+)
+
+// RunGC collects garbage right away instead of waiting for the next pass,
+// responding once it's done. If a pass is already running, on this ATC or
+// another, it responds with 409 Conflict instead.
+func (s *Server) RunGC(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.Session("run-gc")
+
+	ran, err := lockrunner.RunOnce(logger, s.collector, lostandfound.TaskName, s.db)
+	if err != nil {
+		apierror.Internal(w, "failed to collect garbage: "+err.Error())
+		return
+	}
+
+	if !ran {
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
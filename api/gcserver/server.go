@@ -0,0 +1,34 @@
+package gcserver
+
+import (
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/lockrunner"
+)
+
+//go:generate counterfeiter . GCDB
+
+type GCDB interface {
+	GetWorkerGCStats() ([]db.WorkerGCStats, error)
+	GetLastGCPass() (db.GCPass, bool, error)
+	GetTaskLock(logger lager.Logger, taskName string) (db.Lock, bool, error)
+}
+
+type Server struct {
+	logger lager.Logger
+
+	db        GCDB
+	collector lockrunner.Task
+}
+
+func NewServer(
+	logger lager.Logger,
+	db GCDB,
+	collector lockrunner.Task,
+) *Server {
+	return &Server{
+		logger:    logger,
+		db:        db,
+		collector: collector,
+	}
+}
@@ -0,0 +1,30 @@
+package gcserver
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/api/present"
+)
+
+func (s *Server) GetGCStatus(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.Session("get-gc-status")
+
+	lastPass, found, err := s.db.GetLastGCPass()
+	if err != nil {
+		logger.Error("failed-to-get-last-gc-pass", err)
+		apierror.DBFailure(w, "failed to get last gc pass")
+		return
+	}
+
+	workerStats, err := s.db.GetWorkerGCStats()
+	if err != nil {
+		logger.Error("failed-to-get-worker-gc-stats", err)
+		apierror.DBFailure(w, "failed to get worker gc stats")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(present.GCStatus(lastPass, found, workerStats))
+}
@@ -0,0 +1,88 @@
+package grpcserver
+
+import (
+	"context"
+	"net"
+	"net/http"
+
+	"github.com/concourse/atc/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// authenticate runs the caller's token through the same validator, user
+// context reader, and scope checks as the HTTP API, by way of a stand-in
+// request carrying it in its Authorization header. The stand-in is returned
+// with what was learned about the caller, so that the auth package's other
+// checks, such as those of pipeline permissions, can be run against it too.
+func (s *Server) authenticate(ctx context.Context, scope auth.Scope) (auth.Team, *http.Request, error) {
+	r, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		return nil, nil, grpc.Errorf(codes.Internal, "failed to authenticate")
+	}
+
+	r.Header.Set("Authorization", authorization(ctx))
+
+	var authenticated bool
+	var authTeam auth.Team
+	var authTeamFound bool
+
+	auth.WrapHandler(http.HandlerFunc(func(_ http.ResponseWriter, wrapped *http.Request) {
+		authenticated = auth.IsAuthenticated(wrapped)
+		authTeam, authTeamFound = auth.GetTeam(wrapped)
+		r = wrapped
+	}), s.validator, s.userContextReader).ServeHTTP(nil, r)
+
+	if !authenticated || !authTeamFound {
+		return nil, nil, grpc.Errorf(codes.Unauthenticated, "not authenticated")
+	}
+
+	switch err := s.apiTokenChecker.Check(r, scope); err {
+	case nil:
+	case auth.ErrAPITokenRevoked:
+		return nil, nil, grpc.Errorf(codes.Unauthenticated, "not authenticated")
+	case auth.ErrInsufficientScope:
+		return nil, nil, grpc.Errorf(codes.PermissionDenied, "token does not have the %s scope", scope)
+	default:
+		return nil, nil, grpc.Errorf(codes.Internal, "failed to check token")
+	}
+
+	return authTeam, r, nil
+}
+
+func authorization(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	values := md["authorization"]
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+// rateLimitKeys mirror those of wrappa.RateLimitedHandler, so that a client
+// can't get around the limit by switching between the two APIs.
+func rateLimitKeys(ctx context.Context) []string {
+	keys := []string{}
+
+	if p, ok := peer.FromContext(ctx); ok {
+		remoteIP, _, err := net.SplitHostPort(p.Addr.String())
+		if err != nil {
+			remoteIP = p.Addr.String()
+		}
+
+		keys = append(keys, "ip:"+remoteIP)
+	}
+
+	if token := authorization(ctx); token != "" {
+		keys = append(keys, "token:"+token)
+	}
+
+	return keys
+}
@@ -0,0 +1,255 @@
+package grpcserver
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/present"
+	"github.com/concourse/atc/auth"
+	"github.com/concourse/atc/db"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+func (s *Server) CreateBuild(ctx context.Context, req *CreateBuildRequest) (*atc.Build, error) {
+	logger := s.logger.Session("create-build")
+
+	authTeam, _, err := s.authenticate(ctx, auth.ScopeTrigger)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.limiter != nil {
+		allowed, wait := s.limiter.Take(rateLimitKeys(ctx)...)
+		if !allowed {
+			logger.Info("rate-limited")
+
+			grpc.SetTrailer(ctx, metadata.Pairs("retry-after", strconv.Itoa(int(wait.Seconds())+1)))
+
+			return nil, grpc.Errorf(codes.ResourceExhausted, "too many builds created; try again later")
+		}
+	}
+
+	build, err := s.buildsDB.CreateOneOffBuild(ctx, authTeam.Name(), db.OneOffBuildSpec{
+		Plan:   req.Plan,
+		Labels: req.Labels,
+	})
+	if err != nil {
+		logger.Error("failed-to-create-one-off-build", err)
+		return nil, grpc.Errorf(codes.Internal, "failed to create one off build")
+	}
+
+	plan, claimed, err := build.ClaimPendingPlan()
+	if err != nil {
+		logger.Error("failed-to-claim-pending-plan", err)
+		return nil, grpc.Errorf(codes.Internal, "failed to claim pending plan")
+	}
+
+	if claimed {
+		engineBuild, err := s.engine.CreateBuild(logger, build, plan)
+		if err != nil {
+			logger.Error("failed-to-start-build", err)
+			return nil, grpc.Errorf(codes.Internal, "failed to start build")
+		}
+
+		go engineBuild.Resume(logger)
+	}
+
+	found, err := build.Reload()
+	if err != nil {
+		logger.Error("failed-to-reload-build", err)
+		return nil, grpc.Errorf(codes.Internal, "failed to reload build")
+	}
+
+	if !found {
+		logger.Info("build-disappeared", lager.Data{"build-id": build.ID()})
+		return nil, grpc.Errorf(codes.Internal, "build disappeared")
+	}
+
+	presented := present.Build(build)
+	return &presented, nil
+}
+
+func (s *Server) GetBuild(ctx context.Context, req *GetBuildRequest) (*atc.Build, error) {
+	build, err := s.authorizedBuild(ctx, s.logger.Session("get-build"), req.BuildID)
+	if err != nil {
+		return nil, err
+	}
+
+	presented := present.Build(build)
+	return &presented, nil
+}
+
+func (s *Server) ListBuilds(ctx context.Context, req *ListBuildsRequest) (*ListBuildsResponse, error) {
+	logger := s.logger.Session("list-builds")
+
+	authTeam, r, err := s.authenticate(ctx, auth.ScopeRead)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = atc.PaginationAPIDefaultLimit
+	}
+
+	if limit > atc.PaginationAPIMaxLimit {
+		limit = atc.PaginationAPIMaxLimit
+	}
+
+	teamDB := s.teamDBFactory.GetTeamDB(authTeam.Name())
+
+	filter := db.BuildFilter{Labels: req.Labels}
+
+	// leave out the builds of pipelines the caller's role can't view, as
+	// the HTTP API does
+	if _, hasRole := auth.GetRole(r); hasRole {
+		pipelines, err := teamDB.GetPipelines()
+		if err != nil {
+			logger.Error("failed-to-get-pipelines", err)
+			return nil, grpc.Errorf(codes.Internal, "failed to get pipelines")
+		}
+
+		filter.HiddenPipelineIDs = auth.HiddenPipelineIDs(r, pipelines)
+	}
+
+	builds, pagination, err := teamDB.GetPrivateAndPublicBuilds(
+		db.Page{Since: req.Since, Until: req.Until, Limit: limit},
+		filter,
+	)
+	if err != nil {
+		logger.Error("failed-to-get-all-builds", err)
+		return nil, grpc.Errorf(codes.Internal, "failed to get all builds")
+	}
+
+	response := &ListBuildsResponse{
+		Builds: make([]atc.Build, len(builds)),
+	}
+
+	for i, build := range builds {
+		response.Builds[i] = present.Build(build)
+	}
+
+	if pagination.Next != nil {
+		response.Next = &Page{Since: pagination.Next.Since, Limit: pagination.Next.Limit}
+	}
+
+	if pagination.Previous != nil {
+		response.Previous = &Page{Until: pagination.Previous.Until, Limit: pagination.Previous.Limit}
+	}
+
+	return response, nil
+}
+
+func (s *Server) BuildEvents(req *BuildEventsRequest, stream grpc.ServerStream) error {
+	logger := s.logger.Session("build-events", lager.Data{"build-id": req.BuildID})
+
+	build, err := s.authorizedBuild(stream.Context(), logger, req.BuildID)
+	if err != nil {
+		return err
+	}
+
+	events, err := s.eventHub.Subscribe(build, req.From)
+	if err != nil {
+		logger.Error("failed-to-get-build-events", err)
+		return grpc.Errorf(codes.Internal, "failed to get build events")
+	}
+
+	var closeOnce sync.Once
+	closeEvents := func() {
+		closeOnce.Do(func() {
+			events.Close()
+		})
+	}
+
+	defer closeEvents()
+
+	// Next blocks until the build has more to say, so closing the source is
+	// the only way to notice the client going away while it waits
+	go func() {
+		<-stream.Context().Done()
+		closeEvents()
+	}()
+
+	// callers are all members of the build's team; see authorizedBuild
+	censor := s.censorPolicies.RuleFor(build, true)
+
+	id := req.From
+	for {
+		ev, err := events.Next()
+		if err != nil {
+			if err == db.ErrEndOfBuildEventStream || err == db.ErrBuildEventStreamClosed {
+				return nil
+			}
+
+			logger.Error("failed-to-get-next-build-event", err)
+			return grpc.Errorf(codes.Internal, "failed to get next build event")
+		}
+
+		ev, send, err := censor.Censor(ev)
+		if err != nil {
+			logger.Error("failed-to-censor-event", err)
+			return grpc.Errorf(codes.Internal, "failed to censor event")
+		}
+
+		if send {
+			err = stream.SendMsg(&Event{
+				ID:      id,
+				Type:    ev.Event,
+				Version: ev.Version,
+				Data:    ev.Data,
+			})
+			if err != nil {
+				logger.Info("failed-to-send-event", lager.Data{"error": err.Error()})
+				return err
+			}
+		}
+
+		id++
+	}
+}
+
+// authorizedBuild only finds builds of the caller's own team, and of the
+// pipelines the caller's role can view. Unlike the HTTP API there is no
+// anonymous access, so builds of public pipelines are not visible to other
+// teams here.
+func (s *Server) authorizedBuild(ctx context.Context, logger lager.Logger, buildID int) (db.Build, error) {
+	authTeam, r, err := s.authenticate(ctx, auth.ScopeRead)
+	if err != nil {
+		return nil, err
+	}
+
+	builds, err := s.buildsDB.GetBuilds(ctx, []int{buildID})
+	if err != nil {
+		logger.Error("failed-to-get-build", err)
+		return nil, grpc.Errorf(codes.Internal, "failed to get build")
+	}
+
+	if len(builds) == 0 {
+		return nil, grpc.Errorf(codes.NotFound, "build %d not found", buildID)
+	}
+
+	build := builds[0]
+
+	if !authTeam.IsAuthorized(build.TeamName()) {
+		return nil, grpc.Errorf(codes.PermissionDenied, "build %d belongs to another team", buildID)
+	}
+
+	if _, hasRole := auth.GetRole(r); hasRole && !build.IsOneOff() {
+		pipeline, err := build.GetPipeline()
+		if err != nil {
+			logger.Error("failed-to-get-pipeline", err)
+			return nil, grpc.Errorf(codes.Internal, "failed to get pipeline")
+		}
+
+		if _, allowed := auth.HasPipelinePermission(r, pipeline.Permissions, atc.PipelinePermissionView); !pipeline.Public && !allowed {
+			return nil, grpc.Errorf(codes.PermissionDenied, "build %d belongs to a pipeline that can't be viewed", buildID)
+		}
+	}
+
+	return build, nil
+}
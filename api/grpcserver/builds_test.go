@@ -0,0 +1,422 @@
+package grpcserver_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"time"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/buildserver"
+	"github.com/concourse/atc/api/buildserver/buildserverfakes"
+	"github.com/concourse/atc/api/grpcserver"
+	"github.com/concourse/atc/auth"
+	"github.com/concourse/atc/auth/authfakes"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/db/dbfakes"
+	"github.com/concourse/atc/engine/enginefakes"
+	"github.com/concourse/atc/event"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Builds service", func() {
+	var (
+		fakeEngine            *enginefakes.FakeEngine
+		fakeTeamDBFactory     *dbfakes.FakeTeamDBFactory
+		fakeTeamDB            *dbfakes.FakeTeamDB
+		fakeBuildsDB          *buildserverfakes.FakeBuildsDB
+		fakeValidator         *authfakes.FakeValidator
+		fakeUserContextReader *authfakes.FakeUserContextReader
+		fakeAPITokenChecker   *authfakes.FakeAPITokenChecker
+
+		grpcServer *grpc.Server
+		conn       *grpc.ClientConn
+		ctx        context.Context
+	)
+
+	BeforeEach(func() {
+		fakeEngine = new(enginefakes.FakeEngine)
+		fakeTeamDBFactory = new(dbfakes.FakeTeamDBFactory)
+		fakeTeamDB = new(dbfakes.FakeTeamDB)
+		fakeTeamDBFactory.GetTeamDBReturns(fakeTeamDB)
+		fakeBuildsDB = new(buildserverfakes.FakeBuildsDB)
+		fakeValidator = new(authfakes.FakeValidator)
+		fakeUserContextReader = new(authfakes.FakeUserContextReader)
+		fakeAPITokenChecker = new(authfakes.FakeAPITokenChecker)
+
+		fakeValidator.IsAuthenticatedReturns(true)
+		fakeUserContextReader.GetTeamReturns("some-team", 5, false, true)
+
+		grpcServer = grpcserver.NewGRPCServer(grpcserver.NewServer(
+			lagertest.NewTestLogger("test"),
+			fakeEngine,
+			fakeTeamDBFactory,
+			fakeBuildsDB,
+			buildserver.CensorPolicies{},
+			nil,
+			nil,
+			fakeValidator,
+			fakeUserContextReader,
+			fakeAPITokenChecker,
+		))
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+
+		go grpcServer.Serve(listener)
+
+		conn, err = grpc.Dial(
+			listener.Addr().String(),
+			grpc.WithInsecure(),
+			grpc.WithCodec(grpcserver.Codec{}),
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		ctx = metadata.NewOutgoingContext(context.Background(), metadata.Pairs("authorization", "Bearer some-token"))
+	})
+
+	AfterEach(func() {
+		conn.Close()
+		grpcServer.Stop()
+	})
+
+	invoke := func(method string, req interface{}, resp interface{}) error {
+		return grpc.Invoke(ctx, "/"+grpcserver.ServiceName+"/"+method, req, resp, conn)
+	}
+
+	Describe("CreateBuild", func() {
+		var build *dbfakes.FakeBuild
+
+		BeforeEach(func() {
+			build = new(dbfakes.FakeBuild)
+			build.IDReturns(42)
+			build.NameReturns("1")
+			build.TeamNameReturns("some-team")
+			build.StatusReturns(db.StatusStarted)
+			build.ReloadReturns(true, nil)
+			build.ClaimPendingPlanReturns(atc.Plan{ID: "some-plan"}, true, nil)
+
+			fakeBuildsDB.CreateOneOffBuildReturns(build, nil)
+			fakeEngine.CreateBuildReturns(new(enginefakes.FakeBuild), nil)
+		})
+
+		It("creates and starts a one-off build for the caller's team", func() {
+			plan := atc.Plan{ID: "some-plan"}
+
+			var created atc.Build
+			err := invoke("CreateBuild", &grpcserver.CreateBuildRequest{
+				Plan:   plan,
+				Labels: map[string]string{"env": "staging"},
+			}, &created)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(created.ID).To(Equal(42))
+			Expect(created.Status).To(Equal("started"))
+
+			Expect(fakeBuildsDB.CreateOneOffBuildCallCount()).To(Equal(1))
+			_, teamName, spec := fakeBuildsDB.CreateOneOffBuildArgsForCall(0)
+			Expect(teamName).To(Equal("some-team"))
+			Expect(spec).To(Equal(db.OneOffBuildSpec{
+				Plan:   plan,
+				Labels: map[string]string{"env": "staging"},
+			}))
+
+			Expect(build.ClaimPendingPlanCallCount()).To(Equal(1))
+
+			Expect(fakeEngine.CreateBuildCallCount()).To(Equal(1))
+			_, startedBuild, startedPlan := fakeEngine.CreateBuildArgsForCall(0)
+			Expect(startedBuild).To(Equal(build))
+			Expect(startedPlan).To(Equal(plan))
+		})
+
+		It("checks the token from the metadata", func() {
+			err := invoke("CreateBuild", &grpcserver.CreateBuildRequest{}, &atc.Build{})
+			Expect(err).NotTo(HaveOccurred())
+
+			r := fakeValidator.IsAuthenticatedArgsForCall(0)
+			Expect(r.Header.Get("Authorization")).To(Equal("Bearer some-token"))
+		})
+
+		Context("when not authenticated", func() {
+			BeforeEach(func() {
+				fakeValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("fails with Unauthenticated", func() {
+				err := invoke("CreateBuild", &grpcserver.CreateBuildRequest{}, &atc.Build{})
+				Expect(grpc.Code(err)).To(Equal(codes.Unauthenticated))
+
+				Expect(fakeBuildsDB.CreateOneOffBuildCallCount()).To(BeZero())
+			})
+		})
+
+		It("needs the trigger scope", func() {
+			err := invoke("CreateBuild", &grpcserver.CreateBuildRequest{}, &atc.Build{})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, scope := fakeAPITokenChecker.CheckArgsForCall(0)
+			Expect(scope).To(Equal(auth.ScopeTrigger))
+		})
+
+		Context("when the token lacks the scope", func() {
+			BeforeEach(func() {
+				fakeAPITokenChecker.CheckReturns(auth.ErrInsufficientScope)
+			})
+
+			It("fails with PermissionDenied", func() {
+				err := invoke("CreateBuild", &grpcserver.CreateBuildRequest{}, &atc.Build{})
+				Expect(grpc.Code(err)).To(Equal(codes.PermissionDenied))
+
+				Expect(fakeBuildsDB.CreateOneOffBuildCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when the token has been revoked", func() {
+			BeforeEach(func() {
+				fakeAPITokenChecker.CheckReturns(auth.ErrAPITokenRevoked)
+			})
+
+			It("fails with Unauthenticated", func() {
+				err := invoke("CreateBuild", &grpcserver.CreateBuildRequest{}, &atc.Build{})
+				Expect(grpc.Code(err)).To(Equal(codes.Unauthenticated))
+			})
+		})
+
+		Context("when the plan has already been claimed", func() {
+			BeforeEach(func() {
+				build.ClaimPendingPlanReturns(atc.Plan{}, false, nil)
+			})
+
+			It("returns the build without starting it", func() {
+				var created atc.Build
+				err := invoke("CreateBuild", &grpcserver.CreateBuildRequest{}, &created)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(created.ID).To(Equal(42))
+				Expect(fakeEngine.CreateBuildCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when the engine fails to start the build", func() {
+			BeforeEach(func() {
+				fakeEngine.CreateBuildReturns(nil, errors.New("nope"))
+			})
+
+			It("fails with Internal", func() {
+				err := invoke("CreateBuild", &grpcserver.CreateBuildRequest{}, &atc.Build{})
+				Expect(grpc.Code(err)).To(Equal(codes.Internal))
+			})
+		})
+	})
+
+	Describe("GetBuild", func() {
+		var build *dbfakes.FakeBuild
+
+		BeforeEach(func() {
+			build = new(dbfakes.FakeBuild)
+			build.IDReturns(42)
+			build.TeamNameReturns("some-team")
+			fakeBuildsDB.GetBuildsReturns([]db.Build{build}, nil)
+		})
+
+		It("returns the build", func() {
+			var found atc.Build
+			err := invoke("GetBuild", &grpcserver.GetBuildRequest{BuildID: 42}, &found)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found.ID).To(Equal(42))
+
+			_, buildIDs := fakeBuildsDB.GetBuildsArgsForCall(0)
+			Expect(buildIDs).To(Equal([]int{42}))
+		})
+
+		Context("when the build belongs to another team", func() {
+			BeforeEach(func() {
+				build.TeamNameReturns("some-other-team")
+			})
+
+			It("fails with PermissionDenied", func() {
+				err := invoke("GetBuild", &grpcserver.GetBuildRequest{BuildID: 42}, &atc.Build{})
+				Expect(grpc.Code(err)).To(Equal(codes.PermissionDenied))
+			})
+		})
+
+		Context("when the build does not exist", func() {
+			BeforeEach(func() {
+				fakeBuildsDB.GetBuildsReturns([]db.Build{}, nil)
+			})
+
+			It("fails with NotFound", func() {
+				err := invoke("GetBuild", &grpcserver.GetBuildRequest{BuildID: 42}, &atc.Build{})
+				Expect(grpc.Code(err)).To(Equal(codes.NotFound))
+			})
+		})
+
+		Context("when the build's pipeline needs more of a role to view than the caller has", func() {
+			BeforeEach(func() {
+				build.GetPipelineReturns(db.SavedPipeline{
+					Permissions: atc.PipelinePermissions{View: atc.RoleOperator},
+				}, nil)
+
+				fakeUserContextReader.GetRoleReturns(atc.RoleViewer, true)
+			})
+
+			It("fails with PermissionDenied", func() {
+				err := invoke("GetBuild", &grpcserver.GetBuildRequest{BuildID: 42}, &atc.Build{})
+				Expect(grpc.Code(err)).To(Equal(codes.PermissionDenied))
+			})
+
+			Context("when the pipeline is public", func() {
+				BeforeEach(func() {
+					build.GetPipelineReturns(db.SavedPipeline{
+						Public:      true,
+						Permissions: atc.PipelinePermissions{View: atc.RoleOperator},
+					}, nil)
+				})
+
+				It("returns the build", func() {
+					err := invoke("GetBuild", &grpcserver.GetBuildRequest{BuildID: 42}, &atc.Build{})
+					Expect(err).NotTo(HaveOccurred())
+				})
+			})
+
+			Context("when the build is a one-off", func() {
+				BeforeEach(func() {
+					build.IsOneOffReturns(true)
+				})
+
+				It("returns the build without looking for a pipeline", func() {
+					err := invoke("GetBuild", &grpcserver.GetBuildRequest{BuildID: 42}, &atc.Build{})
+					Expect(err).NotTo(HaveOccurred())
+					Expect(build.GetPipelineCallCount()).To(BeZero())
+				})
+			})
+		})
+	})
+
+	Describe("ListBuilds", func() {
+		BeforeEach(func() {
+			build := new(dbfakes.FakeBuild)
+			build.IDReturns(42)
+
+			fakeTeamDB.GetPrivateAndPublicBuildsReturns([]db.Build{build}, db.Pagination{
+				Next: &db.Page{Since: 42, Limit: 2},
+			}, nil)
+		})
+
+		It("returns the team's builds with the next page", func() {
+			var response grpcserver.ListBuildsResponse
+			err := invoke("ListBuilds", &grpcserver.ListBuildsRequest{
+				Limit:  2,
+				Labels: map[string]string{"env": "staging"},
+			}, &response)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(response.Builds).To(HaveLen(1))
+			Expect(response.Builds[0].ID).To(Equal(42))
+			Expect(response.Next).To(Equal(&grpcserver.Page{Since: 42, Limit: 2}))
+			Expect(response.Previous).To(BeNil())
+
+			page, filter := fakeTeamDB.GetPrivateAndPublicBuildsArgsForCall(0)
+			Expect(page).To(Equal(db.Page{Limit: 2}))
+			Expect(filter).To(Equal(db.BuildFilter{Labels: map[string]string{"env": "staging"}}))
+		})
+
+		It("defaults the limit", func() {
+			err := invoke("ListBuilds", &grpcserver.ListBuildsRequest{}, &grpcserver.ListBuildsResponse{})
+			Expect(err).NotTo(HaveOccurred())
+
+			page, _ := fakeTeamDB.GetPrivateAndPublicBuildsArgsForCall(0)
+			Expect(page.Limit).To(Equal(atc.PaginationAPIDefaultLimit))
+		})
+
+		Context("when the caller has a role", func() {
+			BeforeEach(func() {
+				fakeUserContextReader.GetRoleReturns(atc.RoleViewer, true)
+
+				fakeTeamDB.GetPipelinesReturns([]db.SavedPipeline{
+					{ID: 1},
+					{ID: 2, Permissions: atc.PipelinePermissions{View: atc.RoleOperator}},
+					{ID: 3, Public: true, Permissions: atc.PipelinePermissions{View: atc.RoleOperator}},
+				}, nil)
+			})
+
+			It("leaves out the builds of pipelines it can't view", func() {
+				err := invoke("ListBuilds", &grpcserver.ListBuildsRequest{}, &grpcserver.ListBuildsResponse{})
+				Expect(err).NotTo(HaveOccurred())
+
+				_, filter := fakeTeamDB.GetPrivateAndPublicBuildsArgsForCall(0)
+				Expect(filter.HiddenPipelineIDs).To(Equal([]int{2}))
+			})
+		})
+	})
+
+	Describe("BuildEvents", func() {
+		var build *dbfakes.FakeBuild
+		var events *dbfakes.FakeEventSource
+
+		BeforeEach(func() {
+			build = new(dbfakes.FakeBuild)
+			build.IDReturns(42)
+			build.TeamNameReturns("some-team")
+			fakeBuildsDB.GetBuildsReturns([]db.Build{build}, nil)
+
+			events = new(dbfakes.FakeEventSource)
+			build.EventsReturns(events, nil)
+
+			logPayload := json.RawMessage(`{"payload":"hello"}`)
+			envelopes := []event.Envelope{
+				{Event: "log", Version: "5.0", Data: &logPayload},
+			}
+
+			events.NextStub = func() (event.Envelope, error) {
+				if len(envelopes) == 0 {
+					return event.Envelope{}, db.ErrEndOfBuildEventStream
+				}
+
+				next := envelopes[0]
+				envelopes = envelopes[1:]
+				return next, nil
+			}
+		})
+
+		It("streams the build's events from the one asked for", func() {
+			streamCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			defer cancel()
+
+			stream, err := grpc.NewClientStream(streamCtx, &grpc.StreamDesc{
+				StreamName:    "BuildEvents",
+				ServerStreams: true,
+			}, conn, "/"+grpcserver.ServiceName+"/BuildEvents")
+			Expect(err).NotTo(HaveOccurred())
+
+			err = stream.SendMsg(&grpcserver.BuildEventsRequest{BuildID: 42, From: 3})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = stream.CloseSend()
+			Expect(err).NotTo(HaveOccurred())
+
+			var ev grpcserver.Event
+			err = stream.RecvMsg(&ev)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(ev.ID).To(Equal(uint(3)))
+			Expect(ev.Type).To(Equal(atc.EventType("log")))
+			Expect(ev.Version).To(Equal(atc.EventVersion("5.0")))
+			Expect(string(*ev.Data)).To(MatchJSON(`{"payload":"hello"}`))
+
+			err = stream.RecvMsg(&ev)
+			Expect(err).To(Equal(io.EOF))
+
+			Expect(build.EventsArgsForCall(0)).To(Equal(uint(3)))
+			Eventually(events.CloseCallCount).Should(Equal(1))
+		})
+	})
+})
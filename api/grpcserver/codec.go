@@ -0,0 +1,25 @@
+package grpcserver
+
+import "encoding/json"
+
+// Codec encodes messages as JSON rather than protobuf, so that the service
+// can be described by the plain structs in messages.go, including the atc
+// types they embed. Clients must dial with it too, e.g. with
+// grpc.WithCodec(grpcserver.Codec{}).
+type Codec struct{}
+
+func (Codec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (Codec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+func (Codec) String() string {
+	return "json"
+}
+
+func (Codec) Name() string {
+	return "json"
+}
@@ -0,0 +1,51 @@
+package grpcserver
+
+import (
+	"encoding/json"
+
+	"github.com/concourse/atc"
+)
+
+type CreateBuildRequest struct {
+	Plan   atc.Plan          `json:"plan"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+type GetBuildRequest struct {
+	BuildID int `json:"build_id"`
+}
+
+type ListBuildsRequest struct {
+	Since  int               `json:"since,omitempty"`
+	Until  int               `json:"until,omitempty"`
+	Limit  int               `json:"limit,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// Page is the request to make for the next or previous page of builds.
+type Page struct {
+	Since int `json:"since,omitempty"`
+	Until int `json:"until,omitempty"`
+	Limit int `json:"limit"`
+}
+
+type ListBuildsResponse struct {
+	Builds   []atc.Build `json:"builds"`
+	Next     *Page       `json:"next,omitempty"`
+	Previous *Page       `json:"previous,omitempty"`
+}
+
+type BuildEventsRequest struct {
+	BuildID int `json:"build_id"`
+
+	// From is the ID of the first event to send, e.g. one more than the last
+	// event received before reconnecting.
+	From uint `json:"from,omitempty"`
+}
+
+type Event struct {
+	ID      uint             `json:"id"`
+	Type    atc.EventType    `json:"event"`
+	Version atc.EventVersion `json:"version"`
+	Data    *json.RawMessage `json:"data"`
+}
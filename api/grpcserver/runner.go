@@ -0,0 +1,38 @@
+package grpcserver
+
+import (
+	"net"
+	"os"
+
+	"google.golang.org/grpc"
+)
+
+// Runner listens on Addr and serves the gRPC server until signalled. Event
+// streams are long-lived, so it stops immediately rather than gracefully.
+type Runner struct {
+	Addr   string
+	Server *grpc.Server
+}
+
+func (runner Runner) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	listener, err := net.Listen("tcp", runner.Addr)
+	if err != nil {
+		return err
+	}
+
+	serveErr := make(chan error, 1)
+
+	go func() {
+		serveErr <- runner.Server.Serve(listener)
+	}()
+
+	close(ready)
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-signals:
+		runner.Server.Stop()
+		return nil
+	}
+}
@@ -0,0 +1,69 @@
+package grpcserver
+
+import (
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/api/buildserver"
+	"github.com/concourse/atc/auth"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/engine"
+	"github.com/concourse/atc/ratelimit"
+	"google.golang.org/grpc"
+)
+
+type Server struct {
+	logger lager.Logger
+
+	engine         engine.Engine
+	teamDBFactory  db.TeamDBFactory
+	buildsDB       buildserver.BuildsDB
+	censorPolicies buildserver.CensorPolicies
+	eventHub       *buildserver.EventHub
+	limiter        *ratelimit.Limiter
+
+	validator         auth.Validator
+	userContextReader auth.UserContextReader
+	apiTokenChecker   auth.APITokenChecker
+}
+
+// NewServer serves the same builds as the HTTP API, authenticating callers by
+// the token in their "authorization" metadata. The limiter may be nil, in
+// which case build creation is not rate limited.
+func NewServer(
+	logger lager.Logger,
+	engine engine.Engine,
+	teamDBFactory db.TeamDBFactory,
+	buildsDB buildserver.BuildsDB,
+	censorPolicies buildserver.CensorPolicies,
+	eventHub *buildserver.EventHub,
+	limiter *ratelimit.Limiter,
+	validator auth.Validator,
+	userContextReader auth.UserContextReader,
+	apiTokenChecker auth.APITokenChecker,
+) *Server {
+	return &Server{
+		logger: logger,
+
+		engine:         engine,
+		teamDBFactory:  teamDBFactory,
+		buildsDB:       buildsDB,
+		censorPolicies: censorPolicies,
+		eventHub:       eventHub,
+		limiter:        limiter,
+
+		validator:         validator,
+		userContextReader: userContextReader,
+		apiTokenChecker:   apiTokenChecker,
+	}
+}
+
+func (s *Server) Register(grpcServer *grpc.Server) {
+	grpcServer.RegisterService(&serviceDesc, s)
+}
+
+// NewGRPCServer returns a gRPC server with the builds service registered,
+// speaking JSON.
+func NewGRPCServer(s *Server) *grpc.Server {
+	grpcServer := grpc.NewServer(grpc.CustomCodec(Codec{}))
+	s.Register(grpcServer)
+	return grpcServer
+}
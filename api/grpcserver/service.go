@@ -0,0 +1,80 @@
+package grpcserver
+
+import (
+	"context"
+
+	"github.com/concourse/atc"
+	"google.golang.org/grpc"
+)
+
+const ServiceName = "concourse.atc.Builds"
+
+// BuildsServer is what the service descriptor dispatches to. It stands in for
+// the interface protoc would otherwise generate.
+type BuildsServer interface {
+	CreateBuild(context.Context, *CreateBuildRequest) (*atc.Build, error)
+	GetBuild(context.Context, *GetBuildRequest) (*atc.Build, error)
+	ListBuilds(context.Context, *ListBuildsRequest) (*ListBuildsResponse, error)
+	BuildEvents(*BuildEventsRequest, grpc.ServerStream) error
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*BuildsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		unary("CreateBuild", func() interface{} { return new(CreateBuildRequest) }, func(srv BuildsServer, ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.CreateBuild(ctx, req.(*CreateBuildRequest))
+		}),
+		unary("GetBuild", func() interface{} { return new(GetBuildRequest) }, func(srv BuildsServer, ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.GetBuild(ctx, req.(*GetBuildRequest))
+		}),
+		unary("ListBuilds", func() interface{} { return new(ListBuildsRequest) }, func(srv BuildsServer, ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.ListBuilds(ctx, req.(*ListBuildsRequest))
+		}),
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "BuildEvents",
+			ServerStreams: true,
+			Handler: func(srv interface{}, stream grpc.ServerStream) error {
+				req := new(BuildEventsRequest)
+				err := stream.RecvMsg(req)
+				if err != nil {
+					return err
+				}
+
+				return srv.(BuildsServer).BuildEvents(req, stream)
+			},
+		},
+	},
+}
+
+func unary(
+	name string,
+	newRequest func() interface{},
+	call func(BuildsServer, context.Context, interface{}) (interface{}, error),
+) grpc.MethodDesc {
+	return grpc.MethodDesc{
+		MethodName: name,
+		Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+			req := newRequest()
+			err := dec(req)
+			if err != nil {
+				return nil, err
+			}
+
+			if interceptor == nil {
+				return call(srv.(BuildsServer), ctx, req)
+			}
+
+			info := &grpc.UnaryServerInfo{
+				Server:     srv,
+				FullMethod: "/" + ServiceName + "/" + name,
+			}
+
+			return interceptor(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				return call(srv.(BuildsServer), ctx, req)
+			})
+		},
+	}
+}
@@ -3,29 +3,43 @@ package api
 import (
 	"net/http"
 	"path/filepath"
+	"time"
 
+	"code.cloudfoundry.org/clock"
 	"code.cloudfoundry.org/lager"
 	"github.com/tedsuo/rata"
 
 	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/alertserver"
+	"github.com/concourse/atc/api/apiv2"
+	"github.com/concourse/atc/api/auditserver"
 	"github.com/concourse/atc/api/authserver"
 	"github.com/concourse/atc/api/buildserver"
 	"github.com/concourse/atc/api/cliserver"
 	"github.com/concourse/atc/api/configserver"
 	"github.com/concourse/atc/api/containerserver"
+	"github.com/concourse/atc/api/exportserver"
+	"github.com/concourse/atc/api/gcserver"
+	"github.com/concourse/atc/api/hookserver"
 	"github.com/concourse/atc/api/infoserver"
 	"github.com/concourse/atc/api/jobserver"
 	"github.com/concourse/atc/api/loglevelserver"
 	"github.com/concourse/atc/api/pipelineserver"
 	"github.com/concourse/atc/api/pipes"
+	"github.com/concourse/atc/api/queueserver"
 	"github.com/concourse/atc/api/resourceserver"
 	"github.com/concourse/atc/api/resourceserver/versionserver"
+	"github.com/concourse/atc/api/resourcetypeserver"
 	"github.com/concourse/atc/api/teamserver"
+	"github.com/concourse/atc/api/tokenserver"
+	"github.com/concourse/atc/api/usageserver"
 	"github.com/concourse/atc/api/volumeserver"
 	"github.com/concourse/atc/api/workerserver"
 	"github.com/concourse/atc/auth"
+	"github.com/concourse/atc/creds"
 	"github.com/concourse/atc/db"
 	"github.com/concourse/atc/engine"
+	"github.com/concourse/atc/lockrunner"
 	"github.com/concourse/atc/mainredirect"
 	"github.com/concourse/atc/worker"
 	"github.com/concourse/atc/wrappa"
@@ -52,26 +66,52 @@ func NewHandler(
 	volumesDB volumeserver.VolumesDB,
 	pipeDB pipes.PipeDB,
 	pipelinesDB db.PipelinesDB,
+	auditDB auditserver.AuditDB,
+	webhookDB hookserver.WebhookDB,
+	apiTokenDB tokenserver.APITokenDB,
+	leaderDB infoserver.LeaderDB,
+	resourceTypesDB resourcetypeserver.ResourceTypesDB,
+	gcDB gcserver.GCDB,
+	usageDB usageserver.UsageDB,
+	queueDB queueserver.QueueDB,
+	alertsDB alertserver.AlertsDB,
 
 	configValidator configserver.ConfigValidator,
+	credsManager creds.Manager,
 	peerURL string,
 	eventHandlerFactory buildserver.EventHandlerFactory,
+	censorPolicies buildserver.CensorPolicies,
+	eventHub *buildserver.EventHub,
 	drain <-chan struct{},
+	drainGracePeriod time.Duration,
+	keepAlives buildserver.KeepAlivePolicy,
+	artifactStore buildserver.ArtifactStore,
+	taskCaches jobserver.TaskCacheInvalidator,
+	buildReconciler buildserver.BuildReconciler,
 
 	engine engine.Engine,
 	workerClient worker.Client,
+	workerHTTPClient *http.Client,
 
 	schedulerFactory jobserver.SchedulerFactory,
 	scannerFactory resourceserver.ScannerFactory,
 
+	gcCollector lockrunner.Task,
+
 	sink *lager.ReconfigurableSink,
 
 	cliDownloadsDir string,
+	cliDownloadURL string,
+	cliHTTPClient *http.Client,
 	version string,
 ) (http.Handler, error) {
-	absCLIDownloadsDir, err := filepath.Abs(cliDownloadsDir)
-	if err != nil {
-		return nil, err
+	var absCLIDownloadsDir string
+	if cliDownloadsDir != "" {
+		var err error
+		absCLIDownloadsDir, err = filepath.Abs(cliDownloadsDir)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	pipelineHandlerFactory := pipelineserver.NewScopedHandlerFactory(pipelineDBFactory, teamDBFactory)
@@ -95,23 +135,31 @@ func NewHandler(
 		teamDBFactory,
 		buildsDB,
 		eventHandlerFactory,
+		censorPolicies,
+		eventHub,
 		drain,
+		drainGracePeriod,
+		keepAlives,
+		artifactStore,
+		buildReconciler,
+		workerHTTPClient,
 	)
 
-	jobServer := jobserver.NewServer(logger, schedulerFactory, externalURL)
+	jobServer := jobserver.NewServer(logger, schedulerFactory, externalURL, taskCaches)
 	resourceServer := resourceserver.NewServer(logger, scannerFactory)
 	versionServer := versionserver.NewServer(logger, externalURL)
 	pipeServer := pipes.NewServer(logger, peerURL, externalURL, pipeDB)
 
 	pipelineServer := pipelineserver.NewServer(logger, teamDBFactory, pipelinesDB)
 
-	configServer := configserver.NewServer(logger, teamDBFactory, configValidator)
+	configServer := configserver.NewServer(logger, teamDBFactory, configValidator, credsManager)
 
 	workerServer := workerserver.NewServer(logger, workerDB, teamDBFactory)
 
 	logLevelServer := loglevelserver.NewServer(logger, sink)
 
-	cliServer := cliserver.NewServer(logger, absCLIDownloadsDir)
+	cliCatalog := cliserver.NewCatalog(absCLIDownloadsDir, version)
+	cliServer := cliserver.NewServer(logger, cliCatalog, cliDownloadURL, cliHTTPClient, version)
 
 	containerServer := containerserver.NewServer(logger, workerClient, containerDB, teamDBFactory)
 
@@ -119,36 +167,113 @@ func NewHandler(
 
 	teamServer := teamserver.NewServer(logger, teamDBFactory, teamsDB)
 
-	infoServer := infoserver.NewServer(logger, version)
+	infoServer := infoserver.NewServer(logger, version, leaderDB, cliCatalog)
+
+	auditServer := auditserver.NewServer(logger, auditDB)
+
+	exportServer := exportserver.NewServer(logger, teamsDB, teamDBFactory, pipelineDBFactory, clock.NewClock())
+
+	hookServer := hookserver.NewServer(logger, webhookDB, teamDBFactory, pipelineDBFactory, schedulerFactory, externalURL)
+
+	tokenServer := tokenserver.NewServer(logger, apiTokenDB, tokenGenerator)
+
+	resourceTypeServer := resourcetypeserver.NewServer(logger, resourceTypesDB)
+
+	gcServer := gcserver.NewServer(logger, gcDB, gcCollector)
+
+	usageServer := usageserver.NewServer(logger, usageDB)
+
+	queueServer := queueserver.NewServer(logger, queueDB)
+
+	alertServer := alertserver.NewServer(logger, alertsDB)
 
 	handlers := map[string]http.Handler{
 		atc.ListAuthMethods: http.HandlerFunc(authServer.ListAuthMethods),
 		atc.GetAuthToken:    http.HandlerFunc(authServer.GetAuthToken),
 
-		atc.GetConfig:  http.HandlerFunc(configServer.GetConfig),
-		atc.SaveConfig: http.HandlerFunc(configServer.SaveConfig),
-
-		atc.GetBuild:            buildHandlerFactory.HandlerFor(buildServer.GetBuild),
-		atc.ListBuilds:          http.HandlerFunc(buildServer.ListBuilds),
-		atc.CreateBuild:         teamHandlerFactory.HandlerFor(buildServer.CreateBuild),
-		atc.BuildResources:      buildHandlerFactory.HandlerFor(buildServer.BuildResources),
-		atc.AbortBuild:          buildHandlerFactory.HandlerFor(buildServer.AbortBuild),
-		atc.GetBuildPlan:        buildHandlerFactory.HandlerFor(buildServer.GetBuildPlan),
-		atc.GetBuildPreparation: buildHandlerFactory.HandlerFor(buildServer.GetBuildPreparation),
-		atc.BuildEvents:         buildHandlerFactory.HandlerFor(buildServer.BuildEvents),
-
-		atc.ListJobs:       pipelineHandlerFactory.HandlerFor(jobServer.ListJobs),
-		atc.GetJob:         pipelineHandlerFactory.HandlerFor(jobServer.GetJob),
-		atc.ListJobBuilds:  pipelineHandlerFactory.HandlerFor(jobServer.ListJobBuilds),
-		atc.ListJobInputs:  pipelineHandlerFactory.HandlerFor(jobServer.ListJobInputs),
-		atc.GetJobBuild:    pipelineHandlerFactory.HandlerFor(jobServer.GetJobBuild),
-		atc.CreateJobBuild: pipelineHandlerFactory.HandlerFor(jobServer.CreateJobBuild),
-		atc.PauseJob:       pipelineHandlerFactory.HandlerFor(jobServer.PauseJob),
-		atc.UnpauseJob:     pipelineHandlerFactory.HandlerFor(jobServer.UnpauseJob),
-		atc.JobBadge:       pipelineHandlerFactory.HandlerFor(jobServer.JobBadge),
-		atc.MainJobBadge:   mainredirect.Handler{atc.Routes, atc.JobBadge},
+		atc.GetConfig:        http.HandlerFunc(configServer.GetConfig),
+		atc.SaveConfig:       http.HandlerFunc(configServer.SaveConfig),
+		atc.GetConfigHistory: http.HandlerFunc(configServer.GetConfigHistory),
+		atc.RevertConfig:     http.HandlerFunc(configServer.RevertConfig),
+		atc.ValidateConfig:   http.HandlerFunc(configServer.ValidateConfig),
+
+		atc.SaveCandidateConfig:    http.HandlerFunc(configServer.SaveCandidateConfig),
+		atc.GetCandidateConfig:     http.HandlerFunc(configServer.GetCandidateConfig),
+		atc.DeleteCandidateConfig:  http.HandlerFunc(configServer.DeleteCandidateConfig),
+		atc.PromoteCandidateConfig: http.HandlerFunc(configServer.PromoteCandidateConfig),
+		atc.GetCandidateComparison: http.HandlerFunc(configServer.GetCandidateComparison),
+
+		atc.GetBuild:             buildHandlerFactory.HandlerFor(buildServer.GetBuild),
+		atc.WaitForBuild:         buildHandlerFactory.HandlerFor(buildServer.WaitForBuild),
+		atc.ListBuilds:           http.HandlerFunc(buildServer.ListBuilds),
+		atc.GetBuildStatuses:     http.HandlerFunc(buildServer.GetBuildStatuses),
+		atc.MultiplexEvents:      http.HandlerFunc(buildServer.MultiplexEvents),
+		atc.CreateBuild:          teamHandlerFactory.HandlerFor(buildServer.CreateBuild),
+		atc.CreateTeamBuild:      teamHandlerFactory.HandlerFor(buildServer.CreateBuild),
+		atc.ListTeamBuilds:       teamHandlerFactory.HandlerFor(buildServer.ListTeamBuilds),
+		atc.BuildResources:       buildHandlerFactory.HandlerFor(buildServer.BuildResources),
+		atc.GetBuildDiff:         buildHandlerFactory.HandlerFor(buildServer.GetBuildDiff),
+		atc.AbortBuild:           buildHandlerFactory.HandlerFor(buildServer.AbortBuild),
+		atc.RerunBuild:           buildHandlerFactory.HandlerFor(buildServer.RerunBuild),
+		atc.SetBuildPriority:     buildHandlerFactory.HandlerFor(buildServer.SetBuildPriority),
+		atc.GetBuildPlan:         buildHandlerFactory.HandlerFor(buildServer.GetBuildPlan),
+		atc.GetBuildPreparation:  buildHandlerFactory.HandlerFor(buildServer.GetBuildPreparation),
+		atc.GetBuildUsage:        buildHandlerFactory.HandlerFor(buildServer.GetBuildUsage),
+		atc.BuildEvents:          buildHandlerFactory.HandlerFor(buildServer.BuildEvents),
+		atc.ListBuildEvents:      buildHandlerFactory.HandlerFor(buildServer.ListBuildEvents),
+		atc.GetBuildLog:          buildHandlerFactory.HandlerFor(buildServer.GetBuildLog),
+		atc.GetBuildLogHTML:      buildHandlerFactory.HandlerFor(buildServer.GetBuildLogHTML),
+		atc.SearchBuildLogs:      buildHandlerFactory.HandlerFor(buildServer.SearchBuildLogs),
+		atc.VerifyBuildEvents:    buildHandlerFactory.HandlerFor(buildServer.VerifyBuildEvents),
+		atc.SearchAllBuildLogs:   teamHandlerFactory.HandlerFor(buildServer.SearchAllBuildLogs),
+		atc.GetBuildReaperStatus: http.HandlerFunc(buildServer.GetBuildReaperStatus),
+		atc.ReconcileBuilds:      http.HandlerFunc(buildServer.ReconcileBuilds),
+
+		atc.GetGlobalMaxInFlight: http.HandlerFunc(buildServer.GetGlobalMaxInFlight),
+		atc.SetGlobalMaxInFlight: http.HandlerFunc(buildServer.SetGlobalMaxInFlight),
+
+		atc.GetMaintenance:            http.HandlerFunc(buildServer.GetMaintenance),
+		atc.SetMaintenanceWindows:     http.HandlerFunc(buildServer.SetMaintenanceWindows),
+		atc.OverrideMaintenance:       http.HandlerFunc(buildServer.OverrideMaintenance),
+		atc.CancelMaintenanceOverride: http.HandlerFunc(buildServer.CancelMaintenanceOverride),
+
+		atc.RegisterBuildArtifact: buildHandlerFactory.HandlerFor(buildServer.RegisterBuildArtifact),
+		atc.ListBuildArtifacts:    buildHandlerFactory.HandlerFor(buildServer.ListBuildArtifacts),
+		atc.DownloadBuildArtifact: buildHandlerFactory.HandlerFor(buildServer.DownloadBuildArtifact),
+		atc.CreateBuildComment:    buildHandlerFactory.HandlerFor(buildServer.CreateBuildComment),
+		atc.ListBuildComments:     buildHandlerFactory.HandlerFor(buildServer.ListBuildComments),
+		atc.ListBuildApprovals:    buildHandlerFactory.HandlerFor(buildServer.ListBuildApprovals),
+		atc.DecideBuildApproval:   buildHandlerFactory.HandlerFor(buildServer.DecideBuildApproval),
+		atc.ListBuildSteps:        buildHandlerFactory.HandlerFor(buildServer.ListBuildSteps),
+		atc.GetBuildReport:        buildHandlerFactory.HandlerFor(buildServer.GetBuildReport),
+
+		atc.ListJobs:                   pipelineHandlerFactory.HandlerFor(jobServer.ListJobs),
+		atc.GetJob:                     pipelineHandlerFactory.HandlerFor(jobServer.GetJob),
+		atc.GetJobSchedule:             pipelineHandlerFactory.HandlerFor(jobServer.GetJobSchedule),
+		atc.GetJobStats:                pipelineHandlerFactory.HandlerFor(jobServer.GetJobStats),
+		atc.GetJobScheduling:           pipelineHandlerFactory.HandlerFor(jobServer.GetJobScheduling),
+		atc.ListJobCaches:              pipelineHandlerFactory.HandlerFor(jobServer.ListJobCaches),
+		atc.InvalidateJobCaches:        pipelineHandlerFactory.HandlerFor(jobServer.InvalidateJobCaches),
+		atc.ListExpiringJobBuilds:      pipelineHandlerFactory.HandlerFor(jobServer.ListExpiringJobBuilds),
+		atc.ProtectJobBuildArtifacts:   pipelineHandlerFactory.HandlerFor(jobServer.ProtectJobBuildArtifacts),
+		atc.UnprotectJobBuildArtifacts: pipelineHandlerFactory.HandlerFor(jobServer.UnprotectJobBuildArtifacts),
+		atc.ListJobBuilds:              pipelineHandlerFactory.HandlerFor(jobServer.ListJobBuilds),
+		atc.ListJobInputs:              pipelineHandlerFactory.HandlerFor(jobServer.ListJobInputs),
+		atc.GetJobBuild:                pipelineHandlerFactory.HandlerFor(jobServer.GetJobBuild),
+		atc.CreateJobBuild:             pipelineHandlerFactory.HandlerFor(jobServer.CreateJobBuild),
+		atc.ReceiveRemoteTrigger:       pipelineHandlerFactory.HandlerFor(jobServer.ReceiveRemoteTrigger),
+		atc.PauseJob:                   pipelineHandlerFactory.HandlerFor(jobServer.PauseJob),
+		atc.UnpauseJob:                 pipelineHandlerFactory.HandlerFor(jobServer.UnpauseJob),
+		atc.MakeJobManualOnly:          pipelineHandlerFactory.HandlerFor(jobServer.MakeJobManualOnly),
+		atc.MakeJobAutomatic:           pipelineHandlerFactory.HandlerFor(jobServer.MakeJobAutomatic),
+		atc.JobBadge:                   pipelineHandlerFactory.HandlerFor(jobServer.JobBadge),
+		atc.MainJobBadge:               mainredirect.Handler{atc.Routes, atc.JobBadge},
+
+		atc.SaveJobWebhook: pipelineHandlerFactory.HandlerFor(hookServer.SaveJobWebhook),
+		atc.TriggerWebhook: http.HandlerFunc(hookServer.TriggerWebhook),
 
 		atc.ListAllPipelines: http.HandlerFunc(pipelineServer.ListAllPipelines),
+		atc.GetDashboard:     http.HandlerFunc(pipelineServer.GetDashboard),
 		atc.ListPipelines:    http.HandlerFunc(pipelineServer.ListPipelines),
 		atc.GetPipeline:      pipelineHandlerFactory.HandlerFor(pipelineServer.GetPipeline),
 		atc.DeletePipeline:   pipelineHandlerFactory.HandlerFor(pipelineServer.DeletePipeline),
@@ -160,17 +285,33 @@ func NewHandler(
 		atc.GetVersionsDB:    pipelineHandlerFactory.HandlerFor(pipelineServer.GetVersionsDB),
 		atc.RenamePipeline:   pipelineHandlerFactory.HandlerFor(pipelineServer.RenamePipeline),
 
-		atc.ListResources:   pipelineHandlerFactory.HandlerFor(resourceServer.ListResources),
-		atc.GetResource:     pipelineHandlerFactory.HandlerFor(resourceServer.GetResource),
-		atc.PauseResource:   pipelineHandlerFactory.HandlerFor(resourceServer.PauseResource),
-		atc.UnpauseResource: pipelineHandlerFactory.HandlerFor(resourceServer.UnpauseResource),
-		atc.CheckResource:   pipelineHandlerFactory.HandlerFor(resourceServer.CheckResource),
+		atc.GetPipelinePermissions: pipelineHandlerFactory.HandlerFor(pipelineServer.GetPipelinePermissions),
+		atc.SetPipelinePermissions: pipelineHandlerFactory.HandlerFor(pipelineServer.SetPipelinePermissions),
+
+		atc.ListPipelineWeights: http.HandlerFunc(pipelineServer.ListPipelineWeights),
+		atc.SetPipelineWeight:   pipelineHandlerFactory.HandlerFor(pipelineServer.SetPipelineWeight),
+
+		atc.ListPipelineInstances:  http.HandlerFunc(pipelineServer.ListPipelineInstances),
+		atc.SavePipelineInstance:   http.HandlerFunc(pipelineServer.SavePipelineInstance),
+		atc.DeletePipelineInstance: http.HandlerFunc(pipelineServer.DeletePipelineInstance),
+
+		atc.ListResources:           pipelineHandlerFactory.HandlerFor(resourceServer.ListResources),
+		atc.ListResourceCheckErrors: pipelineHandlerFactory.HandlerFor(resourceServer.ListResourceCheckErrors),
+		atc.GetResource:             pipelineHandlerFactory.HandlerFor(resourceServer.GetResource),
+		atc.PauseResource:           pipelineHandlerFactory.HandlerFor(resourceServer.PauseResource),
+		atc.UnpauseResource:         pipelineHandlerFactory.HandlerFor(resourceServer.UnpauseResource),
+		atc.CheckResource:           pipelineHandlerFactory.HandlerFor(resourceServer.CheckResource),
 
 		atc.ListResourceVersions:          pipelineHandlerFactory.HandlerFor(versionServer.ListResourceVersions),
 		atc.EnableResourceVersion:         pipelineHandlerFactory.HandlerFor(versionServer.EnableResourceVersion),
 		atc.DisableResourceVersion:        pipelineHandlerFactory.HandlerFor(versionServer.DisableResourceVersion),
+		atc.PinResourceVersion:            pipelineHandlerFactory.HandlerFor(versionServer.PinResourceVersion),
+		atc.UnpinResource:                 pipelineHandlerFactory.HandlerFor(resourceServer.UnpinResource),
 		atc.ListBuildsWithVersionAsInput:  pipelineHandlerFactory.HandlerFor(versionServer.ListBuildsWithVersionAsInput),
 		atc.ListBuildsWithVersionAsOutput: pipelineHandlerFactory.HandlerFor(versionServer.ListBuildsWithVersionAsOutput),
+		atc.GetResourceVersionCausality:   pipelineHandlerFactory.HandlerFor(versionServer.GetResourceVersionCausality),
+		atc.ListPassedResourceVersions:    pipelineHandlerFactory.HandlerFor(versionServer.ListPassedResourceVersions),
+		atc.PassedResourceVersionEvents:   pipelineHandlerFactory.HandlerFor(versionServer.PassedResourceVersionEvents),
 
 		atc.CreatePipe: http.HandlerFunc(pipeServer.CreatePipe),
 		atc.WritePipe:  http.HandlerFunc(pipeServer.WritePipe),
@@ -189,12 +330,51 @@ func NewHandler(
 		atc.ListContainers:  teamHandlerFactory.HandlerFor(containerServer.ListContainers),
 		atc.GetContainer:    teamHandlerFactory.HandlerFor(containerServer.GetContainer),
 		atc.HijackContainer: teamHandlerFactory.HandlerFor(containerServer.HijackContainer),
+		atc.HijackBuild:     buildHandlerFactory.HandlerFor(containerServer.HijackBuild),
 
 		atc.ListVolumes: teamHandlerFactory.HandlerFor(volumesServer.ListVolumes),
 
 		atc.ListTeams: http.HandlerFunc(teamServer.ListTeams),
 		atc.SetTeam:   http.HandlerFunc(teamServer.SetTeam),
+
+		atc.ListAuditEvents: http.HandlerFunc(auditServer.ListAuditEvents),
+
+		atc.Export: http.HandlerFunc(exportServer.Export),
+		atc.Import: http.HandlerFunc(exportServer.Import),
+
+		atc.CreateAPIToken: http.HandlerFunc(tokenServer.CreateAPIToken),
+		atc.ListAPITokens:  http.HandlerFunc(tokenServer.ListAPITokens),
+		atc.RevokeAPIToken: http.HandlerFunc(tokenServer.RevokeAPIToken),
+
+		atc.ListRegisteredResourceTypes: http.HandlerFunc(resourceTypeServer.ListRegisteredResourceTypes),
+		atc.RegisterResourceType:        http.HandlerFunc(resourceTypeServer.RegisterResourceType),
+		atc.UnregisterResourceType:      http.HandlerFunc(resourceTypeServer.UnregisterResourceType),
+
+		atc.GetGCStatus: http.HandlerFunc(gcServer.GetGCStatus),
+		atc.RunGC:       http.HandlerFunc(gcServer.RunGC),
+
+		atc.GetUsage: http.HandlerFunc(usageServer.GetUsage),
+
+		atc.ListQueue: http.HandlerFunc(queueServer.ListQueue),
+
+		atc.ListAlerts: http.HandlerFunc(alertServer.ListAlerts),
+	}
+
+	wrapped := wrapper.Wrap(handlers)
+
+	v1Router, err := rata.NewRouter(atc.Routes, wrapped)
+	if err != nil {
+		return nil, err
+	}
+
+	v2Router, err := rata.NewRouter(atc.V2Routes, apiv2.NewWrappa(externalURL).Wrap(wrapped))
+	if err != nil {
+		return nil, err
 	}
 
-	return rata.NewRouter(atc.Routes, wrapper.Wrap(handlers))
+	mux := http.NewServeMux()
+	mux.Handle("/api/v1/", v1Router)
+	mux.Handle("/api/v2/", v2Router)
+
+	return mux, nil
 }
@@ -0,0 +1,354 @@
+package api_test
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/db/dbfakes"
+	"github.com/concourse/atc/scheduler/schedulerfakes"
+)
+
+var _ = Describe("Webhooks API", func() {
+	var pipelineDB *dbfakes.FakePipelineDB
+
+	BeforeEach(func() {
+		pipelineDB = new(dbfakes.FakePipelineDB)
+		pipelineDBFactory.BuildReturns(pipelineDB)
+
+		pipelineDB.GetConfigReturns(atc.Config{
+			Jobs: []atc.JobConfig{{Name: "some-job"}},
+
+			Resources: atc.ResourceConfigs{
+				{Name: "some-resource", Type: "some-type"},
+			},
+		}, 1, true, nil)
+	})
+
+	Describe("PUT /api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/hooks/:hook_id", func() {
+		var (
+			body     string
+			response *http.Response
+		)
+
+		BeforeEach(func() {
+			body = `{"secret":"some-secret"}`
+
+			teamDB.GetPipelineByNameReturns(db.SavedPipeline{ID: 3, TeamID: 42}, true, nil)
+			pipelineDB.TeamIDReturns(42)
+			pipelineDB.GetPipelineIDReturns(3)
+
+			webhookDB.SaveWebhookReturns(true, nil)
+		})
+
+		JustBeforeEach(func() {
+			request, err := http.NewRequest("PUT", server.URL+"/api/v1/teams/some-team/pipelines/some-pipeline/jobs/some-job/hooks/some-hook", bytes.NewBufferString(body))
+			Expect(err).NotTo(HaveOccurred())
+
+			response, err = client.Do(request)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("some-team", 42, false, true)
+			})
+
+			It("returns 200 and saves the webhook", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+				Expect(webhookDB.SaveWebhookCallCount()).To(Equal(1))
+				Expect(webhookDB.SaveWebhookArgsForCall(0)).To(Equal(db.Webhook{
+					ID:         "some-hook",
+					TeamID:     42,
+					PipelineID: 3,
+					JobName:    "some-job",
+					Secret:     "some-secret",
+				}))
+			})
+
+			Context("when the hook ID belongs to another team", func() {
+				BeforeEach(func() {
+					webhookDB.SaveWebhookReturns(false, nil)
+				})
+
+				It("returns 409", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusConflict))
+				})
+			})
+
+			Context("when saving fails", func() {
+				BeforeEach(func() {
+					webhookDB.SaveWebhookReturns(false, errors.New("nope"))
+				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+
+			Context("when the job does not exist", func() {
+				BeforeEach(func() {
+					pipelineDB.GetConfigReturns(atc.Config{}, 1, true, nil)
+				})
+
+				It("returns 404 without saving", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+					Expect(webhookDB.SaveWebhookCallCount()).To(BeZero())
+				})
+			})
+
+			Context("when no secret is given", func() {
+				BeforeEach(func() {
+					body = `{}`
+				})
+
+				It("returns 400", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+				})
+			})
+
+			Context("when the body is malformed", func() {
+				BeforeEach(func() {
+					body = `{`
+				})
+
+				It("returns 400", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+				})
+			})
+		})
+
+		Context("when authenticated as another team", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("some-other-team", 5, false, true)
+			})
+
+			It("returns 403", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+				Expect(webhookDB.SaveWebhookCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when not authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns 401", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+	})
+
+	Describe("POST /api/v1/hooks/:hook_id", func() {
+		var (
+			payload  []byte
+			headers  http.Header
+			response *http.Response
+
+			fakeScheduler *schedulerfakes.FakeBuildScheduler
+		)
+
+		sign := func(newHash func() hash.Hash, secret string) string {
+			mac := hmac.New(newHash, []byte(secret))
+			mac.Write(payload)
+			return hex.EncodeToString(mac.Sum(nil))
+		}
+
+		BeforeEach(func() {
+			payload = []byte(`{"ref":"refs/heads/master"}`)
+			headers = http.Header{}
+
+			webhookDB.GetWebhookReturns(db.Webhook{
+				ID:           "some-hook",
+				TeamName:     "some-team",
+				PipelineName: "some-pipeline",
+				JobName:      "some-job",
+				Secret:       "some-secret",
+			}, true, nil)
+
+			teamDB.GetPipelineByNameReturns(db.SavedPipeline{ID: 3}, true, nil)
+
+			fakeScheduler = new(schedulerfakes.FakeBuildScheduler)
+			fakeSchedulerFactory.BuildSchedulerReturns(fakeScheduler)
+
+			build := new(dbfakes.FakeBuild)
+			build.IDReturns(42)
+			build.NameReturns("1")
+			build.JobNameReturns("some-job")
+			build.PipelineNameReturns("some-pipeline")
+			build.TeamNameReturns("some-team")
+			build.StatusReturns(db.StatusPending)
+			fakeScheduler.TriggerImmediatelyReturns(build, nil, nil)
+		})
+
+		JustBeforeEach(func() {
+			request, err := http.NewRequest("POST", server.URL+"/api/v1/hooks/some-hook", bytes.NewBuffer(payload))
+			Expect(err).NotTo(HaveOccurred())
+
+			for name, values := range headers {
+				request.Header[name] = values
+			}
+
+			response, err = client.Do(request)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		itTriggersTheJob := func() {
+			It("returns 201 with the build", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusCreated))
+				Expect(response.Header.Get("Content-Type")).To(Equal("application/json"))
+			})
+
+			It("triggers the hook's job in the hook's pipeline", func() {
+				Expect(webhookDB.GetWebhookArgsForCall(0)).To(Equal("some-hook"))
+				Expect(teamDBFactory.GetTeamDBArgsForCall(0)).To(Equal("some-team"))
+				Expect(teamDB.GetPipelineByNameArgsForCall(0)).To(Equal("some-pipeline"))
+				Expect(pipelineDBFactory.BuildArgsForCall(0)).To(Equal(db.SavedPipeline{ID: 3}))
+
+				Expect(fakeScheduler.TriggerImmediatelyCallCount()).To(Equal(1))
+
+				_, job, resources, _, _ := fakeScheduler.TriggerImmediatelyArgsForCall(0)
+				Expect(job).To(Equal(atc.JobConfig{Name: "some-job"}))
+				Expect(resources).To(Equal(atc.ResourceConfigs{
+					{Name: "some-resource", Type: "some-type"},
+				}))
+			})
+		}
+
+		itRejectsTheRequest := func() {
+			It("returns 401 without triggering", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+				Expect(fakeScheduler.TriggerImmediatelyCallCount()).To(BeZero())
+			})
+		}
+
+		Context("when signed with SHA-256", func() {
+			BeforeEach(func() {
+				headers.Set("X-Hub-Signature-256", "sha256="+sign(sha256.New, "some-secret"))
+			})
+
+			itTriggersTheJob()
+
+			Context("when the trigger fails", func() {
+				BeforeEach(func() {
+					fakeScheduler.TriggerImmediatelyReturns(nil, nil, errors.New("nope"))
+				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+
+			Context("when the job is no longer in the pipeline", func() {
+				BeforeEach(func() {
+					pipelineDB.GetConfigReturns(atc.Config{}, 1, true, nil)
+				})
+
+				It("returns 404", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+				})
+			})
+
+			Context("when the pipeline is gone", func() {
+				BeforeEach(func() {
+					teamDB.GetPipelineByNameReturns(db.SavedPipeline{}, false, nil)
+				})
+
+				It("returns 404", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+				})
+			})
+
+			Context("when it is a ping", func() {
+				BeforeEach(func() {
+					headers.Set("X-GitHub-Event", "ping")
+				})
+
+				It("returns 200 without triggering", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+					Expect(fakeScheduler.TriggerImmediatelyCallCount()).To(BeZero())
+				})
+			})
+		})
+
+		Context("when signed with SHA-1", func() {
+			BeforeEach(func() {
+				headers.Set("X-Hub-Signature", "sha1="+sign(sha1.New, "some-secret"))
+			})
+
+			itTriggersTheJob()
+		})
+
+		Context("when given a GitLab token", func() {
+			BeforeEach(func() {
+				headers.Set("X-Gitlab-Token", "some-secret")
+			})
+
+			itTriggersTheJob()
+		})
+
+		Context("when signed with the wrong secret", func() {
+			BeforeEach(func() {
+				headers.Set("X-Hub-Signature-256", "sha256="+sign(sha256.New, "wrong-secret"))
+			})
+
+			itRejectsTheRequest()
+		})
+
+		Context("when given the wrong GitLab token", func() {
+			BeforeEach(func() {
+				headers.Set("X-Gitlab-Token", "wrong-secret")
+			})
+
+			itRejectsTheRequest()
+		})
+
+		Context("when the signature is malformed", func() {
+			BeforeEach(func() {
+				headers.Set("X-Hub-Signature-256", "sha256=not-hex")
+			})
+
+			itRejectsTheRequest()
+		})
+
+		Context("when the request is not signed", func() {
+			itRejectsTheRequest()
+		})
+
+		Context("when the hook does not exist", func() {
+			BeforeEach(func() {
+				webhookDB.GetWebhookReturns(db.Webhook{}, false, nil)
+			})
+
+			It("returns 404", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+			})
+		})
+
+		Context("when the payload is too large", func() {
+			BeforeEach(func() {
+				payload = bytes.Repeat([]byte("x"), 1024*1024+1)
+				headers.Set("X-Gitlab-Token", "some-secret")
+			})
+
+			It("returns 413", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusRequestEntityTooLarge))
+				Expect(fakeScheduler.TriggerImmediatelyCallCount()).To(BeZero())
+			})
+		})
+	})
+})
@@ -0,0 +1,126 @@
+// This file was generated by counterfeiter
+package hookserverfakes
+
+import (
+	"sync"
+
+	"github.com/concourse/atc/api/hookserver"
+	"github.com/concourse/atc/db"
+)
+
+type FakeWebhookDB struct {
+	SaveWebhookStub        func(arg1 db.Webhook) (bool, error)
+	saveWebhookMutex       sync.RWMutex
+	saveWebhookArgsForCall []struct {
+		arg1 db.Webhook
+	}
+	saveWebhookReturns struct {
+		result1 bool
+		result2 error
+	}
+	GetWebhookStub        func(id string) (db.Webhook, bool, error)
+	getWebhookMutex       sync.RWMutex
+	getWebhookArgsForCall []struct {
+		id string
+	}
+	getWebhookReturns struct {
+		result1 db.Webhook
+		result2 bool
+		result3 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeWebhookDB) SaveWebhook(arg1 db.Webhook) (bool, error) {
+	fake.saveWebhookMutex.Lock()
+	fake.saveWebhookArgsForCall = append(fake.saveWebhookArgsForCall, struct {
+		arg1 db.Webhook
+	}{arg1})
+	fake.recordInvocation("SaveWebhook", []interface{}{arg1})
+	fake.saveWebhookMutex.Unlock()
+	if fake.SaveWebhookStub != nil {
+		return fake.SaveWebhookStub(arg1)
+	} else {
+		return fake.saveWebhookReturns.result1, fake.saveWebhookReturns.result2
+	}
+}
+
+func (fake *FakeWebhookDB) SaveWebhookCallCount() int {
+	fake.saveWebhookMutex.RLock()
+	defer fake.saveWebhookMutex.RUnlock()
+	return len(fake.saveWebhookArgsForCall)
+}
+
+func (fake *FakeWebhookDB) SaveWebhookArgsForCall(i int) db.Webhook {
+	fake.saveWebhookMutex.RLock()
+	defer fake.saveWebhookMutex.RUnlock()
+	return fake.saveWebhookArgsForCall[i].arg1
+}
+
+func (fake *FakeWebhookDB) SaveWebhookReturns(result1 bool, result2 error) {
+	fake.SaveWebhookStub = nil
+	fake.saveWebhookReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeWebhookDB) GetWebhook(id string) (db.Webhook, bool, error) {
+	fake.getWebhookMutex.Lock()
+	fake.getWebhookArgsForCall = append(fake.getWebhookArgsForCall, struct {
+		id string
+	}{id})
+	fake.recordInvocation("GetWebhook", []interface{}{id})
+	fake.getWebhookMutex.Unlock()
+	if fake.GetWebhookStub != nil {
+		return fake.GetWebhookStub(id)
+	} else {
+		return fake.getWebhookReturns.result1, fake.getWebhookReturns.result2, fake.getWebhookReturns.result3
+	}
+}
+
+func (fake *FakeWebhookDB) GetWebhookCallCount() int {
+	fake.getWebhookMutex.RLock()
+	defer fake.getWebhookMutex.RUnlock()
+	return len(fake.getWebhookArgsForCall)
+}
+
+func (fake *FakeWebhookDB) GetWebhookArgsForCall(i int) string {
+	fake.getWebhookMutex.RLock()
+	defer fake.getWebhookMutex.RUnlock()
+	return fake.getWebhookArgsForCall[i].id
+}
+
+func (fake *FakeWebhookDB) GetWebhookReturns(result1 db.Webhook, result2 bool, result3 error) {
+	fake.GetWebhookStub = nil
+	fake.getWebhookReturns = struct {
+		result1 db.Webhook
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeWebhookDB) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.saveWebhookMutex.RLock()
+	defer fake.saveWebhookMutex.RUnlock()
+	fake.getWebhookMutex.RLock()
+	defer fake.getWebhookMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeWebhookDB) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ hookserver.WebhookDB = new(FakeWebhookDB)
@@ -0,0 +1,71 @@
+package hookserver
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/db"
+)
+
+func (s *Server) SaveJobWebhook(pipelineDB db.PipelineDB) http.Handler {
+	logger := s.logger.Session("save-job-webhook")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jobName := r.FormValue(":job_name")
+		hookID := r.FormValue(":hook_id")
+
+		var hookConfig atc.WebhookConfig
+		err := json.NewDecoder(r.Body).Decode(&hookConfig)
+		if err != nil {
+			logger.Info("malformed-request", lager.Data{"error": err.Error()})
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if hookConfig.Secret == "" {
+			logger.Info("missing-secret")
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		config, _, found, err := pipelineDB.GetConfig()
+		if err != nil {
+			logger.Error("could-not-get-pipeline-config", err)
+			apierror.DBFailure(w, "failed to get pipeline config")
+			return
+		}
+
+		if !found {
+			apierror.NotFound(w, "pipeline config not found")
+			return
+		}
+
+		_, found = config.Jobs.Lookup(jobName)
+		if !found {
+			apierror.NotFound(w, "job not found")
+			return
+		}
+
+		saved, err := s.db.SaveWebhook(db.Webhook{
+			ID:         hookID,
+			TeamID:     pipelineDB.TeamID(),
+			PipelineID: pipelineDB.GetPipelineID(),
+			JobName:    jobName,
+			Secret:     hookConfig.Secret,
+		})
+		if err != nil {
+			logger.Error("failed-to-save-webhook", err)
+			apierror.DBFailure(w, "failed to save webhook")
+			return
+		}
+
+		if !saved {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	})
+}
@@ -0,0 +1,42 @@
+package hookserver
+
+import (
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/api/jobserver"
+	"github.com/concourse/atc/db"
+)
+
+type Server struct {
+	logger lager.Logger
+
+	db                WebhookDB
+	teamDBFactory     db.TeamDBFactory
+	pipelineDBFactory db.PipelineDBFactory
+	schedulerFactory  jobserver.SchedulerFactory
+	externalURL       string
+}
+
+//go:generate counterfeiter . WebhookDB
+
+type WebhookDB interface {
+	SaveWebhook(db.Webhook) (bool, error)
+	GetWebhook(id string) (db.Webhook, bool, error)
+}
+
+func NewServer(
+	logger lager.Logger,
+	db WebhookDB,
+	teamDBFactory db.TeamDBFactory,
+	pipelineDBFactory db.PipelineDBFactory,
+	schedulerFactory jobserver.SchedulerFactory,
+	externalURL string,
+) *Server {
+	return &Server{
+		logger:            logger,
+		db:                db,
+		teamDBFactory:     teamDBFactory,
+		pipelineDBFactory: pipelineDBFactory,
+		schedulerFactory:  schedulerFactory,
+		externalURL:       externalURL,
+	}
+}
@@ -0,0 +1,47 @@
+package hookserver
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"hash"
+	"net/http"
+	"strings"
+)
+
+// validSignature checks the request against the hook's secret in whichever
+// way the sender signed it: GitHub sends an HMAC of the payload, preferring
+// SHA-256, and GitLab sends the secret itself as a token.
+func validSignature(r *http.Request, payload []byte, secret string) bool {
+	if signature := r.Header.Get("X-Hub-Signature-256"); signature != "" {
+		return validHMAC(sha256.New, "sha256=", signature, payload, secret)
+	}
+
+	if signature := r.Header.Get("X-Hub-Signature"); signature != "" {
+		return validHMAC(sha1.New, "sha1=", signature, payload, secret)
+	}
+
+	if token := r.Header.Get("X-Gitlab-Token"); token != "" {
+		return subtle.ConstantTimeCompare([]byte(token), []byte(secret)) == 1
+	}
+
+	return false
+}
+
+func validHMAC(newHash func() hash.Hash, prefix string, signature string, payload []byte, secret string) bool {
+	if !strings.HasPrefix(signature, prefix) {
+		return false
+	}
+
+	given, err := hex.DecodeString(strings.TrimPrefix(signature, prefix))
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(newHash, []byte(secret))
+	mac.Write(payload)
+
+	return hmac.Equal(given, mac.Sum(nil))
+}
@@ -0,0 +1,106 @@
+package hookserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/api/present"
+)
+
+// maxPayloadSize is well above what GitHub and GitLab send for a push.
+const maxPayloadSize = 1024 * 1024
+
+func (s *Server) TriggerWebhook(w http.ResponseWriter, r *http.Request) {
+	hookID := r.FormValue(":hook_id")
+
+	logger := s.logger.Session("trigger-webhook", lager.Data{"hook": hookID})
+
+	payload, err := ioutil.ReadAll(io.LimitReader(r.Body, maxPayloadSize+1))
+	if err != nil {
+		logger.Info("failed-to-read-payload", lager.Data{"error": err.Error()})
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if len(payload) > maxPayloadSize {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	hook, found, err := s.db.GetWebhook(hookID)
+	if err != nil {
+		logger.Error("failed-to-get-webhook", err)
+		apierror.DBFailure(w, "failed to get webhook")
+		return
+	}
+
+	if !found {
+		apierror.NotFound(w, "webhook not found")
+		return
+	}
+
+	if !validSignature(r, payload, hook.Secret) {
+		logger.Info("invalid-signature")
+		apierror.Unauthorized(w, "invalid webhook signature")
+		return
+	}
+
+	// GitHub pings a hook when it's first set up, to check it's reachable
+	if r.Header.Get("X-GitHub-Event") == "ping" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	teamDB := s.teamDBFactory.GetTeamDB(hook.TeamName)
+
+	savedPipeline, found, err := teamDB.GetPipelineByName(hook.PipelineName)
+	if err != nil {
+		logger.Error("failed-to-get-pipeline", err)
+		apierror.DBFailure(w, "failed to get pipeline")
+		return
+	}
+
+	if !found {
+		apierror.NotFound(w, "pipeline not found")
+		return
+	}
+
+	pipelineDB := s.pipelineDBFactory.Build(savedPipeline)
+
+	config, _, found, err := pipelineDB.GetConfig()
+	if err != nil {
+		logger.Error("could-not-get-pipeline-config", err)
+		apierror.DBFailure(w, "failed to get pipeline config")
+		return
+	}
+
+	if !found {
+		apierror.NotFound(w, "pipeline config not found")
+		return
+	}
+
+	job, found := config.Jobs.Lookup(hook.JobName)
+	if !found {
+		apierror.NotFound(w, "job not found")
+		return
+	}
+
+	scheduler := s.schedulerFactory.BuildScheduler(pipelineDB, s.externalURL)
+
+	build, _, err := scheduler.TriggerImmediately(logger, job, config.Resources, config.ResourceTypes, nil)
+	if err != nil {
+		logger.Error("failed-to-trigger", err)
+		apierror.BuilderFailure(w, fmt.Sprintf("failed to trigger: %s", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	json.NewEncoder(w).Encode(present.Build(build))
+}
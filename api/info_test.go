@@ -1,8 +1,11 @@
 package api_test
 
 import (
+	"errors"
 	"io/ioutil"
 	"net/http"
+	"os"
+	"path/filepath"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -27,5 +30,71 @@ var _ = Describe("Pipelines API", func() {
 				"version": "1.2.3"
 			}`))
 		})
+
+		Context("when there is a leader", func() {
+			BeforeEach(func() {
+				leaderDB.GetLeaderReturns("http://10.0.0.1:8080", true, nil)
+			})
+
+			It("contains the leader", func() {
+				body, err := ioutil.ReadAll(response.Body)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(body).To(MatchJSON(`{
+					"version": "1.2.3",
+					"leader": "http://10.0.0.1:8080"
+				}`))
+			})
+		})
+
+		Context("when CLIs are bundled", func() {
+			BeforeEach(func() {
+				linuxDir := filepath.Join(cliDownloadsDir, "linux", "amd64")
+
+				err := os.MkdirAll(linuxDir, 0755)
+				Expect(err).NotTo(HaveOccurred())
+
+				err = ioutil.WriteFile(filepath.Join(linuxDir, "fly"), []byte("soi soi soi"), 0644)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			AfterEach(func() {
+				os.RemoveAll(cliDownloadsDir)
+			})
+
+			It("contains their checksums", func() {
+				body, err := ioutil.ReadAll(response.Body)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(body).To(MatchJSON(`{
+					"version": "1.2.3",
+					"clis": [
+						{
+							"platform": "linux",
+							"arch": "amd64",
+							"version": "1.2.3",
+							"sha256": "8976e075cfc268eb75995006b3a96f1cc6aa4c0c4acd9da6a9e699cd5a230812"
+						}
+					]
+				}`))
+			})
+		})
+
+		Context("when looking up the leader fails", func() {
+			BeforeEach(func() {
+				leaderDB.GetLeaderReturns("", false, errors.New("nope"))
+			})
+
+			It("still contains the version", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+				body, err := ioutil.ReadAll(response.Body)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(body).To(MatchJSON(`{
+					"version": "1.2.3"
+				}`))
+			})
+		})
 	})
 })
@@ -8,5 +8,22 @@ import (
 )
 
 func (s *Server) Info(w http.ResponseWriter, r *http.Request) {
-	json.NewEncoder(w).Encode(atc.Info{Version: s.version})
+	info := atc.Info{Version: s.version}
+
+	// the rest of the info is still worth having without the leader
+	leader, found, err := s.leaderDB.GetLeader()
+	if err != nil {
+		s.logger.Error("failed-to-get-leader", err)
+	} else if found {
+		info.Leader = leader
+	}
+
+	clis, err := s.cliCatalog.List()
+	if err != nil {
+		s.logger.Error("failed-to-list-clis", err)
+	} else {
+		info.CLIs = clis
+	}
+
+	json.NewEncoder(w).Encode(info)
 }
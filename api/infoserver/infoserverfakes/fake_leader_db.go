@@ -0,0 +1,70 @@
+// This file was generated by counterfeiter
+package infoserverfakes
+
+import (
+	"sync"
+
+	"github.com/concourse/atc/api/infoserver"
+)
+
+type FakeLeaderDB struct {
+	GetLeaderStub        func() (string, bool, error)
+	getLeaderMutex       sync.RWMutex
+	getLeaderArgsForCall []struct{}
+	getLeaderReturns     struct {
+		result1 string
+		result2 bool
+		result3 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeLeaderDB) GetLeader() (string, bool, error) {
+	fake.getLeaderMutex.Lock()
+	fake.getLeaderArgsForCall = append(fake.getLeaderArgsForCall, struct{}{})
+	fake.recordInvocation("GetLeader", []interface{}{})
+	fake.getLeaderMutex.Unlock()
+	if fake.GetLeaderStub != nil {
+		return fake.GetLeaderStub()
+	} else {
+		return fake.getLeaderReturns.result1, fake.getLeaderReturns.result2, fake.getLeaderReturns.result3
+	}
+}
+
+func (fake *FakeLeaderDB) GetLeaderCallCount() int {
+	fake.getLeaderMutex.RLock()
+	defer fake.getLeaderMutex.RUnlock()
+	return len(fake.getLeaderArgsForCall)
+}
+
+func (fake *FakeLeaderDB) GetLeaderReturns(result1 string, result2 bool, result3 error) {
+	fake.GetLeaderStub = nil
+	fake.getLeaderReturns = struct {
+		result1 string
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeLeaderDB) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.getLeaderMutex.RLock()
+	defer fake.getLeaderMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeLeaderDB) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ infoserver.LeaderDB = new(FakeLeaderDB)
@@ -1,18 +1,39 @@
 package infoserver
 
-import "code.cloudfoundry.org/lager"
+import (
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+)
+
+//go:generate counterfeiter . LeaderDB
+
+type LeaderDB interface {
+	GetLeader() (string, bool, error)
+}
+
+type CLICatalog interface {
+	List() ([]atc.CLI, error)
+}
 
 type Server struct {
 	logger  lager.Logger
 	version string
+
+	leaderDB   LeaderDB
+	cliCatalog CLICatalog
 }
 
 func NewServer(
 	logger lager.Logger,
 	version string,
+	leaderDB LeaderDB,
+	cliCatalog CLICatalog,
 ) *Server {
 	return &Server{
 		logger:  logger,
 		version: version,
+
+		leaderDB:   leaderDB,
+		cliCatalog: cliCatalog,
 	}
 }
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	. "github.com/onsi/ginkgo"
@@ -150,6 +152,7 @@ var _ = Describe("Jobs API", func() {
 							pipelineDB.GetJobReturns(db.SavedJob{
 								ID:                 1,
 								Paused:             true,
+								ManualOnly:         true,
 								FirstLoggedBuildID: 99,
 								PipelineName:       "some-pipeline",
 								Job: db.Job{
@@ -169,13 +172,14 @@ var _ = Describe("Jobs API", func() {
 							Expect(response.StatusCode).To(Equal(http.StatusOK))
 						})
 
-						It("returns the job's name, url, if it's paused, and any running and finished builds", func() {
+						It("returns the job's name, url, if it's paused or manual only, and any running and finished builds", func() {
 							body, err := ioutil.ReadAll(response.Body)
 							Expect(err).NotTo(HaveOccurred())
 
 							Expect(body).To(MatchJSON(`{
 							"name": "some-job",
 							"paused": true,
+							"manual_only": true,
 							"first_logged_build_id": 99,
 							"url": "/teams/some-team/pipelines/some-pipeline/jobs/some-job",
 							"next_build": {
@@ -227,6 +231,29 @@ var _ = Describe("Jobs API", func() {
 						}`))
 
 						})
+
+						Context("when the job has been flagged as flaky", func() {
+							BeforeEach(func() {
+								pipelineDB.GetJobReturns(db.SavedJob{
+									ID:           1,
+									PipelineName: "some-pipeline",
+									Flakiness:    0.5,
+									Flaky:        true,
+									Job: db.Job{
+										Name: "job-1",
+									},
+								}, nil)
+							})
+
+							It("returns its flakiness", func() {
+								var job atc.Job
+								err := json.NewDecoder(response.Body).Decode(&job)
+								Expect(err).NotTo(HaveOccurred())
+
+								Expect(job.Flakiness).To(Equal(0.5))
+								Expect(job.Flaky).To(BeTrue())
+							})
+						})
 					})
 
 					Context("when there are no running or finished builds", func() {
@@ -295,12 +322,19 @@ var _ = Describe("Jobs API", func() {
 	})
 
 	Describe("GET /api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/badge", func() {
-		var response *http.Response
+		var (
+			query    string
+			response *http.Response
+		)
+
+		BeforeEach(func() {
+			query = ""
+		})
 
 		JustBeforeEach(func() {
 			var err error
 
-			response, err = client.Get(server.URL + "/api/v1/teams/some-team/pipelines/some-pipeline/jobs/some-job/badge")
+			response, err = client.Get(server.URL + "/api/v1/teams/some-team/pipelines/some-pipeline/jobs/some-job/badge" + query)
 			Expect(err).NotTo(HaveOccurred())
 		})
 
@@ -389,6 +423,101 @@ var _ = Describe("Jobs API", func() {
 					Expect(response.StatusCode).To(Equal(http.StatusOK))
 				})
 
+				It("asks caches to revalidate the badge", func() {
+					Expect(response.Header.Get("Cache-Control")).To(Equal("no-cache, max-age=0"))
+					Expect(response.Header.Get("ETag")).NotTo(BeEmpty())
+				})
+
+				It("returns 304 when fetched again with the same ETag", func() {
+					request, err := http.NewRequest("GET", server.URL+"/api/v1/teams/some-team/pipelines/some-pipeline/jobs/some-job/badge", nil)
+					Expect(err).NotTo(HaveOccurred())
+
+					request.Header.Set("If-None-Match", response.Header.Get("ETag"))
+
+					refetched, err := client.Do(request)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(refetched.StatusCode).To(Equal(http.StatusNotModified))
+				})
+
+				Context("when asked for builds of a resource", func() {
+					BeforeEach(func() {
+						query = "?resource=some-input"
+
+						build := new(dbfakes.FakeBuild)
+						build.StatusReturns(db.StatusFailed)
+						pipelineDB.GetJobLatestFinishedBuildWithInputReturns(build, true, nil)
+					})
+
+					It("uses the latest finished build with the resource as an input", func() {
+						Expect(pipelineDB.GetJobFinishedAndNextBuildCallCount()).To(BeZero())
+						Expect(pipelineDB.GetJobLatestFinishedBuildWithInputCallCount()).To(Equal(1))
+
+						jobName, resourceName, version := pipelineDB.GetJobLatestFinishedBuildWithInputArgsForCall(0)
+						Expect(jobName).To(Equal("some-job"))
+						Expect(resourceName).To(Equal("some-input"))
+						Expect(version).To(BeNil())
+
+						body, err := ioutil.ReadAll(response.Body)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(string(body)).To(ContainSubstring("failing"))
+					})
+
+					Context("and a version", func() {
+						BeforeEach(func() {
+							query += "&version=" + url.QueryEscape(`{"ref":"abc"}`)
+						})
+
+						It("only considers builds of that version", func() {
+							Expect(pipelineDB.GetJobLatestFinishedBuildWithInputCallCount()).To(Equal(1))
+
+							_, _, version := pipelineDB.GetJobLatestFinishedBuildWithInputArgsForCall(0)
+							Expect(version).To(Equal(atc.Version{"ref": "abc"}))
+						})
+					})
+
+					Context("and a malformed version", func() {
+						BeforeEach(func() {
+							query += "&version=nope"
+						})
+
+						It("returns 400", func() {
+							Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+						})
+					})
+
+					Context("when there is no such build", func() {
+						BeforeEach(func() {
+							pipelineDB.GetJobLatestFinishedBuildWithInputReturns(nil, false, nil)
+						})
+
+						It("returns an unknown badge", func() {
+							body, err := ioutil.ReadAll(response.Body)
+							Expect(err).NotTo(HaveOccurred())
+							Expect(string(body)).To(ContainSubstring("unknown"))
+						})
+					})
+
+					Context("when looking up the build fails", func() {
+						BeforeEach(func() {
+							pipelineDB.GetJobLatestFinishedBuildWithInputReturns(nil, false, errors.New("oh no!"))
+						})
+
+						It("returns 500", func() {
+							Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+						})
+					})
+				})
+
+				Context("when asked for a version without a resource", func() {
+					BeforeEach(func() {
+						query = "?version=" + url.QueryEscape(`{"ref":"abc"}`)
+					})
+
+					It("returns 400", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+					})
+				})
+
 				Context("when the finished build is successful", func() {
 					BeforeEach(func() {
 						build1 := new(dbfakes.FakeBuild)
@@ -1180,6 +1309,38 @@ var _ = Describe("Jobs API", func() {
 				userContextReader.GetTeamReturns("some-team", 42, true, true)
 			})
 
+			Context("when triggering the pipeline needs the admin role", func() {
+				BeforeEach(func() {
+					pipelineDB.PipelineReturns(db.SavedPipeline{
+						Permissions: atc.PipelinePermissions{Trigger: atc.RoleAdmin},
+					})
+				})
+
+				Context("and the request has the operator role", func() {
+					BeforeEach(func() {
+						userContextReader.GetRoleReturns(atc.RoleOperator, true)
+					})
+
+					It("returns 403", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+					})
+
+					It("does not trigger the build", func() {
+						Expect(fakeScheduler.TriggerImmediatelyCallCount()).To(Equal(0))
+					})
+				})
+
+				Context("and the request has the admin role", func() {
+					BeforeEach(func() {
+						userContextReader.GetRoleReturns(atc.RoleAdmin, true)
+					})
+
+					It("gets on with triggering", func() {
+						Expect(pipelineDB.GetConfigCallCount()).To(Equal(1))
+					})
+				})
+			})
+
 			Context("when manual triggering is disabled", func() {
 				BeforeEach(func() {
 					pipelineDB.GetConfigReturns(atc.Config{
@@ -1236,8 +1397,10 @@ var _ = Describe("Jobs API", func() {
 				})
 
 				Context("when triggering the build succeeds", func() {
+					var build *dbfakes.FakeBuild
+
 					BeforeEach(func() {
-						build := new(dbfakes.FakeBuild)
+						build = new(dbfakes.FakeBuild)
 						build.IDReturns(42)
 						build.NameReturns("1")
 						build.JobNameReturns("some-job")
@@ -1252,7 +1415,7 @@ var _ = Describe("Jobs API", func() {
 					It("triggers using the current config", func() {
 						Expect(fakeScheduler.TriggerImmediatelyCallCount()).To(Equal(1))
 
-						_, job, resources, resourceTypes := fakeScheduler.TriggerImmediatelyArgsForCall(0)
+						_, job, resources, resourceTypes, _ := fakeScheduler.TriggerImmediatelyArgsForCall(0)
 						Expect(job).To(Equal(atc.JobConfig{
 							Name: "some-job",
 							Plan: atc.PlanSequence{
@@ -1291,6 +1454,42 @@ var _ = Describe("Jobs API", func() {
 							"end_time": 100
 						}`))
 					})
+
+					It("does not set a priority", func() {
+						Expect(build.SetPriorityCallCount()).To(BeZero())
+					})
+
+					Context("when a priority is given", func() {
+						BeforeEach(func() {
+							request.URL.RawQuery = "priority=5"
+						})
+
+						It("sets it on the build", func() {
+							Expect(build.SetPriorityCallCount()).To(Equal(1))
+							Expect(build.SetPriorityArgsForCall(0)).To(Equal(5))
+						})
+
+						Context("when setting it fails", func() {
+							BeforeEach(func() {
+								build.SetPriorityReturns(false, errors.New("nope"))
+							})
+
+							It("returns 500", func() {
+								Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+							})
+						})
+					})
+
+					Context("when the priority is not a number", func() {
+						BeforeEach(func() {
+							request.URL.RawQuery = "priority=urgent"
+						})
+
+						It("returns 400 and does not trigger the build", func() {
+							Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+							Expect(fakeScheduler.TriggerImmediatelyCallCount()).To(BeZero())
+						})
+					})
 				})
 
 				Context("when triggering the build fails", func() {
@@ -1303,6 +1502,86 @@ var _ = Describe("Jobs API", func() {
 					})
 				})
 
+				Context("when the job declares trigger params", func() {
+					BeforeEach(func() {
+						pipelineDB.GetConfigReturns(atc.Config{
+							Jobs: []atc.JobConfig{
+								{
+									Name: "some-job",
+									TriggerParams: atc.TriggerParamConfigs{
+										{Name: "environment", Required: true, Values: []string{"staging", "production"}},
+										{Name: "feature-flag", Default: "off"},
+									},
+								},
+							},
+						}, 1, true, nil)
+
+						fakeScheduler.TriggerImmediatelyReturns(new(dbfakes.FakeBuild), nil, nil)
+					})
+
+					Context("when valid params are given", func() {
+						BeforeEach(func() {
+							request.Body = ioutil.NopCloser(strings.NewReader(`{"params":{"environment":"staging"}}`))
+						})
+
+						It("triggers the build with them, along with any defaults", func() {
+							Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+							Expect(fakeScheduler.TriggerImmediatelyCallCount()).To(Equal(1))
+							_, _, _, _, params := fakeScheduler.TriggerImmediatelyArgsForCall(0)
+							Expect(params).To(Equal(map[string]string{
+								"environment":  "staging",
+								"feature-flag": "off",
+							}))
+						})
+					})
+
+					Context("when a required param is missing", func() {
+						It("returns 400 and does not trigger the build", func() {
+							Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+
+							body, err := ioutil.ReadAll(response.Body)
+							Expect(err).NotTo(HaveOccurred())
+							Expect(string(body)).To(ContainSubstring("missing required param 'environment'"))
+
+							Expect(fakeScheduler.TriggerImmediatelyCallCount()).To(BeZero())
+						})
+					})
+
+					Context("when a param has a value that isn't allowed", func() {
+						BeforeEach(func() {
+							request.Body = ioutil.NopCloser(strings.NewReader(`{"params":{"environment":"dev"}}`))
+						})
+
+						It("returns 400 and does not trigger the build", func() {
+							Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+							Expect(fakeScheduler.TriggerImmediatelyCallCount()).To(BeZero())
+						})
+					})
+
+					Context("when an unknown param is given", func() {
+						BeforeEach(func() {
+							request.Body = ioutil.NopCloser(strings.NewReader(`{"params":{"environment":"staging","bogus":"value"}}`))
+						})
+
+						It("returns 400 and does not trigger the build", func() {
+							Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+							Expect(fakeScheduler.TriggerImmediatelyCallCount()).To(BeZero())
+						})
+					})
+				})
+
+				Context("when the request body is malformed", func() {
+					BeforeEach(func() {
+						request.Body = ioutil.NopCloser(strings.NewReader(`{`))
+					})
+
+					It("returns 400 and does not trigger the build", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+						Expect(fakeScheduler.TriggerImmediatelyCallCount()).To(BeZero())
+					})
+				})
+
 				Context("when the job is not present in the config", func() {
 					BeforeEach(func() {
 						pipelineDB.GetConfigReturns(atc.Config{
@@ -1342,104 +1621,431 @@ var _ = Describe("Jobs API", func() {
 		})
 	})
 
-	Describe("GET /api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/inputs", func() {
-		var response *http.Response
+	Describe("GET /api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/schedule", func() {
+		var (
+			query    string
+			response *http.Response
+		)
+
+		BeforeEach(func() {
+			query = ""
+
+			authValidator.IsAuthenticatedReturns(true)
+			userContextReader.GetTeamReturns("some-team", 1, true, true)
+
+			pipelineDB.GetConfigReturns(atc.Config{
+				Jobs: []atc.JobConfig{
+					{
+						Name:     "some-job",
+						Schedule: "@hourly",
+					},
+					{
+						Name: "some-unscheduled-job",
+					},
+				},
+			}, 1, true, nil)
+		})
 
 		JustBeforeEach(func() {
 			var err error
 
-			response, err = client.Get(server.URL + "/api/v1/teams/some-team/pipelines/some-pipeline/jobs/some-job/inputs")
+			response, err = client.Get(server.URL + "/api/v1/teams/some-team/pipelines/some-pipeline/jobs/some-job/schedule" + query)
 			Expect(err).NotTo(HaveOccurred())
 		})
 
-		Context("when authorized", func() {
+		decodeSchedule := func() atc.JobSchedule {
+			var schedule atc.JobSchedule
+			err := json.NewDecoder(response.Body).Decode(&schedule)
+			Expect(err).NotTo(HaveOccurred())
+			return schedule
+		}
+
+		It("returns 200 OK with the schedule and its next five trigger times", func() {
+			Expect(response.StatusCode).To(Equal(http.StatusOK))
+			Expect(response.Header.Get("Content-Type")).To(Equal("application/json"))
+
+			schedule := decodeSchedule()
+			Expect(schedule.Schedule).To(Equal("@hourly"))
+			Expect(schedule.Upcoming).To(HaveLen(5))
+
+			Expect(schedule.Upcoming[0]).To(BeNumerically(">", time.Now().Unix()))
+			Expect(schedule.Upcoming[0] % 3600).To(BeZero())
+
+			for i := 1; i < len(schedule.Upcoming); i++ {
+				Expect(schedule.Upcoming[i] - schedule.Upcoming[i-1]).To(Equal(int64(3600)))
+			}
+		})
+
+		Context("when a count is given", func() {
 			BeforeEach(func() {
-				authValidator.IsAuthenticatedReturns(true)
-				userContextReader.GetTeamReturns("some-team", 42, true, true)
+				query = "?count=2"
 			})
 
-			It("looked up the proper pipeline", func() {
-				Expect(teamDB.GetPipelineByNameCallCount()).To(Equal(1))
-				pipelineName := teamDB.GetPipelineByNameArgsForCall(0)
-				Expect(pipelineName).To(Equal("some-pipeline"))
-				Expect(pipelineDBFactory.BuildCallCount()).To(Equal(1))
-				actualSavedPipeline := pipelineDBFactory.BuildArgsForCall(0)
-				Expect(actualSavedPipeline).To(Equal(expectedSavedPipeline))
+			It("returns that many trigger times", func() {
+				Expect(decodeSchedule().Upcoming).To(HaveLen(2))
 			})
+		})
 
-			Context("when getting the config succeeds", func() {
-				Context("when it contains the requested job", func() {
-					someJob := atc.JobConfig{
-						Name: "some-job",
-						Plan: atc.PlanSequence{
-							{
-								Get:      "some-input",
-								Resource: "some-resource",
-								Passed:   []string{"job-a", "job-b"},
-								Params:   atc.Params{"some": "params"},
-							},
-							{
-								Get:      "some-other-input",
-								Resource: "some-other-resource",
-								Passed:   []string{"job-c", "job-d"},
-								Params:   atc.Params{"some": "other-params"},
-								Tags:     []string{"some-tag"},
-							},
-						},
-					}
+		Context("when the job has no schedule", func() {
+			BeforeEach(func() {
+				pipelineDB.GetConfigReturns(atc.Config{
+					Jobs: []atc.JobConfig{{Name: "some-job"}},
+				}, 1, true, nil)
+			})
 
-					var fakeScheduler *schedulerfakes.FakeBuildScheduler
-					BeforeEach(func() {
-						fakeScheduler = new(schedulerfakes.FakeBuildScheduler)
-						fakeSchedulerFactory.BuildSchedulerReturns(fakeScheduler)
-						pipelineDB.GetConfigReturns(atc.Config{
-							Jobs: atc.JobConfigs{
-								someJob,
-							},
+			It("returns 404", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+			})
+		})
 
-							Resources: atc.ResourceConfigs{
-								{
-									Name:   "some-resource",
-									Source: atc.Source{"some": "source"},
-								},
-								{
-									Name:   "some-other-resource",
-									Source: atc.Source{"some": "other-source"},
-								},
-							},
-						}, 42, true, nil)
-					})
+		Context("when the job is not in the config", func() {
+			BeforeEach(func() {
+				pipelineDB.GetConfigReturns(atc.Config{}, 1, true, nil)
+			})
 
-					Context("when the input versions for the job can be determined", func() {
-						BeforeEach(func() {
-							pipelineDB.GetNextBuildInputsStub = func(string) ([]db.BuildInput, bool, error) {
-								defer GinkgoRecover()
-								Expect(fakeScheduler.SaveNextInputMappingCallCount()).To(Equal(1))
-								return []db.BuildInput{
-									{
-										Name: "some-input",
-										VersionedResource: db.VersionedResource{
-											Resource:   "some-resource",
-											Type:       "some-type",
-											Version:    db.Version{"some": "version"},
-											PipelineID: 42,
-										},
-									},
-									{
-										Name: "some-other-input",
-										VersionedResource: db.VersionedResource{
-											Resource:   "some-other-resource",
-											Type:       "some-other-type",
-											Version:    db.Version{"some": "other-version"},
-											PipelineID: 42,
-										},
-									},
-								}, true, nil
-							}
-						})
+			It("returns 404", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+			})
+		})
 
-						It("returns 200 OK", func() {
-							Expect(response.StatusCode).To(Equal(http.StatusOK))
+		Context("when getting the config fails", func() {
+			BeforeEach(func() {
+				pipelineDB.GetConfigReturns(atc.Config{}, 0, false, errors.New("nope"))
+			})
+
+			It("returns 500", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+		})
+
+		Context("when not authorized and the pipeline is private", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+				userContextReader.GetTeamReturns("", 0, false, false)
+				pipelineDB.IsPublicReturns(false)
+			})
+
+			It("returns 401", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+	})
+
+	Describe("GET /api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/stats", func() {
+		var (
+			query    string
+			response *http.Response
+		)
+
+		BeforeEach(func() {
+			query = ""
+
+			authValidator.IsAuthenticatedReturns(true)
+			userContextReader.GetTeamReturns("some-team", 1, true, true)
+
+			pipelineDB.GetConfigReturns(atc.Config{
+				Jobs: []atc.JobConfig{{Name: "some-job"}},
+			}, 1, true, nil)
+
+			pipelineDB.GetJobBuildStatsReturns(db.JobBuildStats{
+				Succeeded: 8,
+				Failed:    1,
+				Errored:   1,
+
+				MeanDuration:   90 * time.Second,
+				MedianDuration: time.Minute,
+				P95Duration:    5 * time.Minute,
+			}, nil)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+
+			response, err = client.Get(server.URL + "/api/v1/teams/some-team/pipelines/some-pipeline/jobs/some-job/stats" + query)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		decodeStats := func() atc.JobStats {
+			var stats atc.JobStats
+			err := json.NewDecoder(response.Body).Decode(&stats)
+			Expect(err).NotTo(HaveOccurred())
+			return stats
+		}
+
+		It("returns 200 OK with the stats for the last day, week, and month", func() {
+			Expect(response.StatusCode).To(Equal(http.StatusOK))
+			Expect(response.Header.Get("Content-Type")).To(Equal("application/json"))
+
+			stats := decodeStats()
+			Expect(stats.Windows).To(HaveLen(3))
+			Expect(stats.Windows[0]).To(Equal(atc.JobStatsWindow{
+				Window:         "24h",
+				Succeeded:      8,
+				Failed:         1,
+				Errored:        1,
+				MeanDuration:   90,
+				MedianDuration: 60,
+				P95Duration:    300,
+			}))
+			Expect(stats.Windows[1].Window).To(Equal("168h"))
+			Expect(stats.Windows[2].Window).To(Equal("720h"))
+
+			Expect(pipelineDB.GetJobBuildStatsCallCount()).To(Equal(3))
+
+			jobName, window := pipelineDB.GetJobBuildStatsArgsForCall(0)
+			Expect(jobName).To(Equal("some-job"))
+			Expect(window).To(Equal(24 * time.Hour))
+		})
+
+		Context("when windows are given", func() {
+			BeforeEach(func() {
+				query = "?window=1h&window=30m"
+			})
+
+			It("returns the stats for each of them", func() {
+				stats := decodeStats()
+				Expect(stats.Windows).To(HaveLen(2))
+				Expect(stats.Windows[0].Window).To(Equal("1h"))
+				Expect(stats.Windows[1].Window).To(Equal("30m"))
+
+				_, window := pipelineDB.GetJobBuildStatsArgsForCall(1)
+				Expect(window).To(Equal(30 * time.Minute))
+			})
+		})
+
+		Context("when a window is malformed", func() {
+			BeforeEach(func() {
+				query = "?window=forever"
+			})
+
+			It("returns 400", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+			})
+
+			It("does not look up any stats", func() {
+				Expect(pipelineDB.GetJobBuildStatsCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when the job is not in the config", func() {
+			BeforeEach(func() {
+				pipelineDB.GetConfigReturns(atc.Config{}, 1, true, nil)
+			})
+
+			It("returns 404", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+			})
+		})
+
+		Context("when getting the stats fails", func() {
+			BeforeEach(func() {
+				pipelineDB.GetJobBuildStatsReturns(db.JobBuildStats{}, errors.New("nope"))
+			})
+
+			It("returns 500", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+		})
+
+		Context("when not authorized and the pipeline is private", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+				userContextReader.GetTeamReturns("", 0, false, false)
+				pipelineDB.IsPublicReturns(false)
+			})
+
+			It("returns 401", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+	})
+
+	Describe("GET /api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/scheduling", func() {
+		var response *http.Response
+
+		BeforeEach(func() {
+			authValidator.IsAuthenticatedReturns(true)
+			userContextReader.GetTeamReturns("some-team", 1, true, true)
+
+			pipelineDB.GetConfigReturns(atc.Config{
+				Jobs: []atc.JobConfig{{Name: "some-job"}},
+			}, 1, true, nil)
+
+			pipelineDB.GetJobSchedulingReturns(db.JobScheduling{
+				Reason: db.SchedulingReasonNoPendingBuilds,
+				MissingInputReasons: db.MissingInputReasons{
+					"some-input": db.NoVerionsSatisfiedPassedConstraints,
+				},
+				CheckedAt: time.Unix(100, 0),
+			}, true, nil)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+
+			response, err = client.Get(server.URL + "/api/v1/teams/some-team/pipelines/some-pipeline/jobs/some-job/scheduling")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns 200 OK with why the job was last not started", func() {
+			Expect(response.StatusCode).To(Equal(http.StatusOK))
+			Expect(response.Header.Get("Content-Type")).To(Equal("application/json"))
+
+			body, err := ioutil.ReadAll(response.Body)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(body).To(MatchJSON(`{
+				"reason": "no-pending-builds",
+				"missing_input_reasons": {
+					"some-input": "no versions satisfy passed constraints"
+				},
+				"checked_at": 100
+			}`))
+
+			Expect(pipelineDB.GetJobSchedulingArgsForCall(0)).To(Equal("some-job"))
+		})
+
+		Context("when the job is not in the config", func() {
+			BeforeEach(func() {
+				pipelineDB.GetConfigReturns(atc.Config{}, 1, true, nil)
+			})
+
+			It("returns 404", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+			})
+		})
+
+		Context("when the job hasn't been scheduled yet", func() {
+			BeforeEach(func() {
+				pipelineDB.GetJobSchedulingReturns(db.JobScheduling{}, false, nil)
+			})
+
+			It("returns 404", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+			})
+		})
+
+		Context("when getting the scheduling fails", func() {
+			BeforeEach(func() {
+				pipelineDB.GetJobSchedulingReturns(db.JobScheduling{}, false, errors.New("nope"))
+			})
+
+			It("returns 500", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+		})
+
+		Context("when not authorized and the pipeline is private", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+				userContextReader.GetTeamReturns("", 0, false, false)
+				pipelineDB.IsPublicReturns(false)
+			})
+
+			It("returns 401", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+	})
+
+	Describe("GET /api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/inputs", func() {
+		var response *http.Response
+
+		JustBeforeEach(func() {
+			var err error
+
+			response, err = client.Get(server.URL + "/api/v1/teams/some-team/pipelines/some-pipeline/jobs/some-job/inputs")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("some-team", 42, true, true)
+			})
+
+			It("looked up the proper pipeline", func() {
+				Expect(teamDB.GetPipelineByNameCallCount()).To(Equal(1))
+				pipelineName := teamDB.GetPipelineByNameArgsForCall(0)
+				Expect(pipelineName).To(Equal("some-pipeline"))
+				Expect(pipelineDBFactory.BuildCallCount()).To(Equal(1))
+				actualSavedPipeline := pipelineDBFactory.BuildArgsForCall(0)
+				Expect(actualSavedPipeline).To(Equal(expectedSavedPipeline))
+			})
+
+			Context("when getting the config succeeds", func() {
+				Context("when it contains the requested job", func() {
+					someJob := atc.JobConfig{
+						Name: "some-job",
+						Plan: atc.PlanSequence{
+							{
+								Get:      "some-input",
+								Resource: "some-resource",
+								Passed:   []string{"job-a", "job-b"},
+								Params:   atc.Params{"some": "params"},
+							},
+							{
+								Get:      "some-other-input",
+								Resource: "some-other-resource",
+								Passed:   []string{"job-c", "job-d"},
+								Params:   atc.Params{"some": "other-params"},
+								Tags:     []string{"some-tag"},
+							},
+						},
+					}
+
+					var fakeScheduler *schedulerfakes.FakeBuildScheduler
+					BeforeEach(func() {
+						fakeScheduler = new(schedulerfakes.FakeBuildScheduler)
+						fakeSchedulerFactory.BuildSchedulerReturns(fakeScheduler)
+						pipelineDB.GetConfigReturns(atc.Config{
+							Jobs: atc.JobConfigs{
+								someJob,
+							},
+
+							Resources: atc.ResourceConfigs{
+								{
+									Name:   "some-resource",
+									Source: atc.Source{"some": "source"},
+								},
+								{
+									Name:   "some-other-resource",
+									Source: atc.Source{"some": "other-source"},
+								},
+							},
+						}, 42, true, nil)
+					})
+
+					Context("when the input versions for the job can be determined", func() {
+						BeforeEach(func() {
+							pipelineDB.GetNextBuildInputsStub = func(string) ([]db.BuildInput, bool, error) {
+								defer GinkgoRecover()
+								Expect(fakeScheduler.SaveNextInputMappingCallCount()).To(Equal(1))
+								return []db.BuildInput{
+									{
+										Name: "some-input",
+										VersionedResource: db.VersionedResource{
+											Resource:   "some-resource",
+											Type:       "some-type",
+											Version:    db.Version{"some": "version"},
+											PipelineID: 42,
+										},
+									},
+									{
+										Name: "some-other-input",
+										VersionedResource: db.VersionedResource{
+											Resource:   "some-other-resource",
+											Type:       "some-other-type",
+											Version:    db.Version{"some": "other-version"},
+											PipelineID: 42,
+										},
+									},
+								}, true, nil
+							}
+						})
+
+						It("returns 200 OK", func() {
+							Expect(response.StatusCode).To(Equal(http.StatusOK))
 						})
 
 						It("created the scheduler with the correct pipelineDB and external URL", func() {
@@ -1793,4 +2399,481 @@ var _ = Describe("Jobs API", func() {
 			})
 		})
 	})
+
+	Describe("PUT /api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/manual-only", func() {
+		var response *http.Response
+
+		JustBeforeEach(func() {
+			var err error
+
+			request, err := http.NewRequest("PUT", server.URL+"/api/v1/teams/some-team/pipelines/some-pipeline/jobs/job-name/manual-only", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			response, err = client.Do(request)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("some-team", 42, true, true)
+			})
+
+			It("injects the PipelineDB", func() {
+				pipelineName := teamDB.GetPipelineByNameArgsForCall(0)
+				Expect(pipelineName).To(Equal("some-pipeline"))
+				Expect(pipelineDBFactory.BuildCallCount()).To(Equal(1))
+				actualSavedPipeline := pipelineDBFactory.BuildArgsForCall(0)
+				Expect(actualSavedPipeline).To(Equal(expectedSavedPipeline))
+			})
+
+			Context("when making the job manual only succeeds", func() {
+				BeforeEach(func() {
+					pipelineDB.MakeJobManualOnlyReturns(nil)
+				})
+
+				It("made the right job manual only", func() {
+					Expect(pipelineDB.MakeJobManualOnlyArgsForCall(0)).To(Equal("job-name"))
+				})
+
+				It("returns 200", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+				})
+			})
+
+			Context("when making the job manual only fails", func() {
+				BeforeEach(func() {
+					pipelineDB.MakeJobManualOnlyReturns(errors.New("welp"))
+				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+
+		Context("when not authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns Unauthorized", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+	})
+	Describe("PUT /api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/automatic", func() {
+		var response *http.Response
+
+		JustBeforeEach(func() {
+			var err error
+
+			request, err := http.NewRequest("PUT", server.URL+"/api/v1/teams/some-team/pipelines/some-pipeline/jobs/job-name/automatic", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			response, err = client.Do(request)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("some-team", 42, true, true)
+			})
+
+			It("injects the PipelineDB", func() {
+				pipelineName := teamDB.GetPipelineByNameArgsForCall(0)
+				Expect(pipelineName).To(Equal("some-pipeline"))
+				Expect(pipelineDBFactory.BuildCallCount()).To(Equal(1))
+				actualSavedPipeline := pipelineDBFactory.BuildArgsForCall(0)
+				Expect(actualSavedPipeline).To(Equal(expectedSavedPipeline))
+			})
+
+			Context("when making the job automatic succeeds", func() {
+				BeforeEach(func() {
+					pipelineDB.MakeJobAutomaticReturns(nil)
+				})
+
+				It("made the right job automatic", func() {
+					Expect(pipelineDB.MakeJobAutomaticArgsForCall(0)).To(Equal("job-name"))
+				})
+
+				It("returns 200", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+				})
+			})
+
+			Context("when making the job automatic fails", func() {
+				BeforeEach(func() {
+					pipelineDB.MakeJobAutomaticReturns(errors.New("welp"))
+				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+
+		Context("when not authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns Unauthorized", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+	})
+
+	Describe("GET /api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/caches", func() {
+		var response *http.Response
+
+		JustBeforeEach(func() {
+			var err error
+
+			response, err = client.Get(server.URL + "/api/v1/teams/some-team/pipelines/some-pipeline/jobs/some-job/caches")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("some-team", 42, true, true)
+			})
+
+			Context("when the job has caches", func() {
+				BeforeEach(func() {
+					pipelineDB.GetTaskCachesReturns([]db.TaskCache{
+						{
+							PipelineID: 1,
+							JobName:    "some-job",
+							Path:       "node_modules",
+							SizeBytes:  1024,
+							SavedAt:    time.Unix(100, 0),
+						},
+					}, nil)
+				})
+
+				It("returns them", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+					Expect(pipelineDB.GetTaskCachesArgsForCall(0)).To(Equal("some-job"))
+
+					body, err := ioutil.ReadAll(response.Body)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(body).To(MatchJSON(`[{"path":"node_modules","size_bytes":1024,"saved_at":100}]`))
+				})
+			})
+
+			Context("when getting them fails", func() {
+				BeforeEach(func() {
+					pipelineDB.GetTaskCachesReturns(nil, errors.New("nope"))
+				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+
+		Context("when not authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns 401", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+	})
+
+	Describe("DELETE /api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/caches", func() {
+		var (
+			query    string
+			response *http.Response
+		)
+
+		nodeModules := db.TaskCache{PipelineID: 1, JobName: "some-job", Path: "node_modules"}
+		m2 := db.TaskCache{PipelineID: 1, JobName: "some-job", Path: ".m2"}
+
+		BeforeEach(func() {
+			query = ""
+
+			pipelineDB.GetTaskCachesReturns([]db.TaskCache{m2, nodeModules}, nil)
+		})
+
+		JustBeforeEach(func() {
+			request, err := http.NewRequest("DELETE", server.URL+"/api/v1/teams/some-team/pipelines/some-pipeline/jobs/some-job/caches"+query, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			response, err = client.Do(request)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("some-team", 42, true, true)
+			})
+
+			It("deletes all of the job's caches from storage and then forgets them", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusNoContent))
+
+				Expect(fakeTaskCaches.InvalidateCallCount()).To(Equal(1))
+				_, caches := fakeTaskCaches.InvalidateArgsForCall(0)
+				Expect(caches).To(Equal([]db.TaskCache{m2, nodeModules}))
+
+				Expect(pipelineDB.DeleteTaskCachesCallCount()).To(Equal(1))
+				jobName, path := pipelineDB.DeleteTaskCachesArgsForCall(0)
+				Expect(jobName).To(Equal("some-job"))
+				Expect(path).To(BeEmpty())
+			})
+
+			Context("when given a path", func() {
+				BeforeEach(func() {
+					query = "?path=node_modules"
+				})
+
+				It("only invalidates the cache at that path", func() {
+					_, caches := fakeTaskCaches.InvalidateArgsForCall(0)
+					Expect(caches).To(Equal([]db.TaskCache{nodeModules}))
+
+					_, path := pipelineDB.DeleteTaskCachesArgsForCall(0)
+					Expect(path).To(Equal("node_modules"))
+				})
+			})
+
+			Context("when deleting them from storage fails", func() {
+				BeforeEach(func() {
+					fakeTaskCaches.InvalidateReturns(errors.New("nope"))
+				})
+
+				It("returns 500 and still lists them", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+					Expect(pipelineDB.DeleteTaskCachesCallCount()).To(BeZero())
+				})
+			})
+
+			Context("when forgetting them fails", func() {
+				BeforeEach(func() {
+					pipelineDB.DeleteTaskCachesReturns(nil, errors.New("nope"))
+				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+
+		Context("when not authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns 401 without invalidating anything", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+				Expect(fakeTaskCaches.InvalidateCallCount()).To(BeZero())
+			})
+		})
+	})
+
+	Describe("GET /api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/artifacts/expiring", func() {
+		var response *http.Response
+
+		JustBeforeEach(func() {
+			var err error
+
+			response, err = client.Get(server.URL + "/api/v1/teams/some-team/pipelines/some-pipeline/jobs/some-job/artifacts/expiring")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("some-team", 42, true, true)
+
+				pipelineDB.GetJobArtifactBuildsReturns([]db.ArtifactBuild{
+					{ID: 3, Name: "3", Status: db.StatusSucceeded, EndTime: time.Now(), Artifacts: []string{}},
+					{ID: 2, Name: "2", Status: db.StatusSucceeded, EndTime: time.Now(), Protected: true, Artifacts: []string{}},
+					{ID: 1, Name: "1", Status: db.StatusFailed, EndTime: time.Unix(100, 0), Artifacts: []string{"some-artifact"}},
+				}, nil)
+			})
+
+			Context("when the job has an artifact retention policy", func() {
+				BeforeEach(func() {
+					pipelineDB.GetConfigReturns(atc.Config{
+						Jobs: atc.JobConfigs{
+							{
+								Name:              "some-job",
+								ArtifactRetention: &atc.ArtifactRetentionConfig{Builds: 1},
+							},
+						},
+					}, 1, true, nil)
+				})
+
+				It("returns the builds it will have reaped next", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+					Expect(pipelineDB.GetJobArtifactBuildsArgsForCall(0)).To(Equal("some-job"))
+
+					body, err := ioutil.ReadAll(response.Body)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(body).To(MatchJSON(`[{"id":1,"name":"1","status":"failed","end_time":100,"artifacts":["some-artifact"]}]`))
+				})
+
+				Context("when getting the builds fails", func() {
+					BeforeEach(func() {
+						pipelineDB.GetJobArtifactBuildsReturns(nil, errors.New("nope"))
+					})
+
+					It("returns 500", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+					})
+				})
+			})
+
+			Context("when the job has no artifact retention policy", func() {
+				BeforeEach(func() {
+					pipelineDB.GetConfigReturns(atc.Config{
+						Jobs: atc.JobConfigs{{Name: "some-job"}},
+					}, 1, true, nil)
+				})
+
+				It("returns an empty list", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+					body, err := ioutil.ReadAll(response.Body)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(body).To(MatchJSON(`[]`))
+				})
+			})
+
+			Context("when the job does not exist", func() {
+				BeforeEach(func() {
+					pipelineDB.GetConfigReturns(atc.Config{}, 1, true, nil)
+				})
+
+				It("returns 404", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+				})
+			})
+		})
+
+		Context("when not authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns 401", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+	})
+
+	Describe("PUT /api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/builds/:build_name/protect", func() {
+		var response *http.Response
+
+		JustBeforeEach(func() {
+			request, err := http.NewRequest("PUT", server.URL+"/api/v1/teams/some-team/pipelines/some-pipeline/jobs/some-job/builds/some-build/protect", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			response, err = client.Do(request)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("some-team", 42, true, true)
+			})
+
+			Context("when the build exists", func() {
+				BeforeEach(func() {
+					pipelineDB.SetBuildArtifactsProtectedReturns(true, nil)
+				})
+
+				It("protects it", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusNoContent))
+
+					Expect(pipelineDB.SetBuildArtifactsProtectedCallCount()).To(Equal(1))
+					jobName, buildName, protected := pipelineDB.SetBuildArtifactsProtectedArgsForCall(0)
+					Expect(jobName).To(Equal("some-job"))
+					Expect(buildName).To(Equal("some-build"))
+					Expect(protected).To(BeTrue())
+				})
+			})
+
+			Context("when the build does not exist", func() {
+				BeforeEach(func() {
+					pipelineDB.SetBuildArtifactsProtectedReturns(false, nil)
+				})
+
+				It("returns 404", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+				})
+			})
+
+			Context("when protecting it fails", func() {
+				BeforeEach(func() {
+					pipelineDB.SetBuildArtifactsProtectedReturns(false, errors.New("nope"))
+				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+
+		Context("when not authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns 401 without protecting it", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+				Expect(pipelineDB.SetBuildArtifactsProtectedCallCount()).To(BeZero())
+			})
+		})
+	})
+
+	Describe("DELETE /api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/builds/:build_name/protect", func() {
+		var response *http.Response
+
+		JustBeforeEach(func() {
+			request, err := http.NewRequest("DELETE", server.URL+"/api/v1/teams/some-team/pipelines/some-pipeline/jobs/some-job/builds/some-build/protect", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			response, err = client.Do(request)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("some-team", 42, true, true)
+
+				pipelineDB.SetBuildArtifactsProtectedReturns(true, nil)
+			})
+
+			It("stops protecting it", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusNoContent))
+
+				jobName, buildName, protected := pipelineDB.SetBuildArtifactsProtectedArgsForCall(0)
+				Expect(jobName).To(Equal("some-job"))
+				Expect(buildName).To(Equal("some-build"))
+				Expect(protected).To(BeFalse())
+			})
+		})
+
+		Context("when not authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns 401", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+	})
 })
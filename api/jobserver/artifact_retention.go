@@ -0,0 +1,100 @@
+package jobserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/api/present"
+	"github.com/concourse/atc/artifactreaper"
+	"github.com/concourse/atc/db"
+)
+
+// ListExpiringJobBuilds lists the builds whose containers and artifacts the
+// job's artifact retention policy will have reaped next, newest first. Jobs
+// without a policy keep everything.
+func (s *Server) ListExpiringJobBuilds(pipelineDB db.PipelineDB) http.Handler {
+	logger := s.logger.Session("list-expiring-job-builds")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jobName := r.FormValue(":job_name")
+
+		config, _, found, err := pipelineDB.GetConfig()
+		if err != nil {
+			logger.Error("could-not-get-pipeline-config", err)
+			apierror.DBFailure(w, "failed to get pipeline config")
+			return
+		}
+
+		if !found {
+			apierror.NotFound(w, "pipeline config not found")
+			return
+		}
+
+		job, found := config.Jobs.Lookup(jobName)
+		if !found {
+			apierror.NotFound(w, "job not found")
+			return
+		}
+
+		presented := []atc.ExpiringBuild{}
+
+		if job.ArtifactRetention != nil {
+			builds, err := pipelineDB.GetJobArtifactBuilds(jobName)
+			if err != nil {
+				logger.Error("failed-to-get-job-artifact-builds", err)
+				apierror.DBFailure(w, "failed to get job builds")
+				return
+			}
+
+			for _, build := range artifactreaper.Expired(*job.ArtifactRetention, builds, time.Now()) {
+				presented = append(presented, present.ExpiringBuild(build))
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		json.NewEncoder(w).Encode(presented)
+	})
+}
+
+// ProtectJobBuildArtifacts keeps the build's containers and artifacts from
+// being reaped, whatever the job's artifact retention policy says.
+func (s *Server) ProtectJobBuildArtifacts(pipelineDB db.PipelineDB) http.Handler {
+	return s.setBuildArtifactsProtected(pipelineDB, "protect-job-build-artifacts", true)
+}
+
+func (s *Server) UnprotectJobBuildArtifacts(pipelineDB db.PipelineDB) http.Handler {
+	return s.setBuildArtifactsProtected(pipelineDB, "unprotect-job-build-artifacts", false)
+}
+
+func (s *Server) setBuildArtifactsProtected(pipelineDB db.PipelineDB, session string, protected bool) http.Handler {
+	logger := s.logger.Session(session)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jobName := r.FormValue(":job_name")
+		buildName := r.FormValue(":build_name")
+
+		found, err := pipelineDB.SetBuildArtifactsProtected(jobName, buildName, protected)
+		if err != nil {
+			logger.Error("failed-to-set-build-artifacts-protected", err)
+			apierror.DBFailure(w, "failed to update build")
+			return
+		}
+
+		if !found {
+			apierror.NotFound(w, "build not found")
+			return
+		}
+
+		logger.Info("updated", lager.Data{
+			"job":       jobName,
+			"build":     buildName,
+			"protected": protected,
+		})
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
@@ -2,19 +2,25 @@ package jobserver
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"text/template"
 
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/api/conditional"
 	"github.com/concourse/atc/db"
 )
 
 var (
-	badgePassing = badge{width: 88, fillColor: `#44cc11`, status: `passing`}
-	badgeFailing = badge{width: 80, fillColor: `#e05d44`, status: `failing`}
-	badgeUnknown = badge{width: 98, fillColor: `#9f9f9f`, status: `unknown`}
-	badgeAborted = badge{width: 90, fillColor: `#8f4b2d`, status: `aborted`}
-	badgeErrored = badge{width: 88, fillColor: `#fe7d37`, status: `errored`}
+	badgePassing  = badge{width: 88, fillColor: `#44cc11`, status: `passing`}
+	badgeFailing  = badge{width: 80, fillColor: `#e05d44`, status: `failing`}
+	badgeUnknown  = badge{width: 98, fillColor: `#9f9f9f`, status: `unknown`}
+	badgeAborted  = badge{width: 90, fillColor: `#8f4b2d`, status: `aborted`}
+	badgeErrored  = badge{width: 88, fillColor: `#fe7d37`, status: `errored`}
+	badgeTimedOut = badge{width: 100, fillColor: `#8f4b2d`, status: `timed out`}
 )
 
 type badge struct {
@@ -62,6 +68,8 @@ func badgeForBuild(build db.Build) *badge {
 		return &badgeAborted
 	case build.Status() == db.StatusErrored:
 		return &badgeErrored
+	case build.Status() == db.StatusTimedOut:
+		return &badgeTimedOut
 	default:
 		return &badgeUnknown
 	}
@@ -97,6 +105,16 @@ type badgeTemplateConfig struct {
 	FillColor       string
 }
 
+// JobBadge serves an SVG badge for the job's latest finished build.
+//
+// With ?resource=, only builds that used the resource as an input count, so
+// that e.g. a pipeline with a resource per branch can have a badge per
+// branch. Adding &version= (as JSON) narrows it down to builds of that
+// exact version.
+//
+// Badges are mostly fetched through image proxies that cache aggressively,
+// so the response asks to be revalidated every time and carries an ETag to
+// keep that cheap.
 func (s *Server) JobBadge(pipelineDB db.PipelineDB) http.Handler {
 	logger := s.logger.Session("job-badge")
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -105,32 +123,71 @@ func (s *Server) JobBadge(pipelineDB db.PipelineDB) http.Handler {
 		config, _, found, err := pipelineDB.GetConfig()
 		if err != nil {
 			logger.Error("could-not-get-pipeline-config", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.DBFailure(w, "failed to get pipeline config")
 			return
 		}
 
 		if !found {
-			w.WriteHeader(http.StatusNotFound)
+			apierror.NotFound(w, "pipeline config not found")
 			return
 		}
 
 		_, found = config.Jobs.Lookup(jobName)
 		if !found {
-			w.WriteHeader(http.StatusNotFound)
+			apierror.NotFound(w, "job not found")
 			return
 		}
 
-		build, _, err := pipelineDB.GetJobFinishedAndNextBuild(jobName)
-		if err != nil {
-			logger.Error("could-not-get-job-finished-and-next-build", err)
-			w.WriteHeader(http.StatusInternalServerError)
-			return
+		resourceName := r.URL.Query().Get("resource")
+		versionParam := r.URL.Query().Get("version")
+
+		var build db.Build
+
+		if resourceName == "" {
+			if versionParam != "" {
+				http.Error(w, "version can only be given along with resource", http.StatusBadRequest)
+				return
+			}
+
+			build, _, err = pipelineDB.GetJobFinishedAndNextBuild(jobName)
+			if err != nil {
+				logger.Error("could-not-get-job-finished-and-next-build", err)
+				apierror.DBFailure(w, "failed to get job builds")
+				return
+			}
+		} else {
+			var version atc.Version
+			if versionParam != "" {
+				err := json.Unmarshal([]byte(versionParam), &version)
+				if err != nil {
+					http.Error(w, "malformed version", http.StatusBadRequest)
+					return
+				}
+			}
+
+			build, found, err = pipelineDB.GetJobLatestFinishedBuildWithInput(jobName, resourceName, version)
+			if err != nil {
+				logger.Error("could-not-get-job-latest-finished-build-with-input", err, lager.Data{"resource": resourceName})
+				apierror.DBFailure(w, "failed to get job builds")
+				return
+			}
+
+			if !found {
+				build = nil
+			}
 		}
 
+		svg := []byte(badgeForBuild(build).String())
+
 		w.Header().Set("Content-type", "image/svg+xml")
+		w.Header().Set("Cache-Control", "no-cache, max-age=0")
+
+		if conditional.NotModified(w, r, svg) {
+			return
+		}
 
 		w.WriteHeader(http.StatusOK)
 
-		fmt.Fprint(w, badgeForBuild(build))
+		w.Write(svg)
 	})
 }
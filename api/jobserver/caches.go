@@ -0,0 +1,80 @@
+package jobserver
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/api/present"
+	"github.com/concourse/atc/db"
+)
+
+func (s *Server) ListJobCaches(pipelineDB db.PipelineDB) http.Handler {
+	logger := s.logger.Session("list-job-caches")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jobName := r.FormValue(":job_name")
+
+		caches, err := pipelineDB.GetTaskCaches(jobName)
+		if err != nil {
+			logger.Error("failed-to-get-task-caches", err)
+			apierror.DBFailure(w, "failed to get job caches")
+			return
+		}
+
+		presentedCaches := make([]atc.JobCache, len(caches))
+		for i, cache := range caches {
+			presentedCaches[i] = present.JobCache(cache)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		json.NewEncoder(w).Encode(presentedCaches)
+	})
+}
+
+// InvalidateJobCaches throws away the job's cache at ?path=, or all of them,
+// so that its next build starts from scratch. They're deleted from storage
+// first, so that they're still listed to try again if that fails.
+func (s *Server) InvalidateJobCaches(pipelineDB db.PipelineDB) http.Handler {
+	logger := s.logger.Session("invalidate-job-caches")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jobName := r.FormValue(":job_name")
+		path := r.FormValue("path")
+
+		caches, err := pipelineDB.GetTaskCaches(jobName)
+		if err != nil {
+			logger.Error("failed-to-get-task-caches", err)
+			apierror.DBFailure(w, "failed to get job caches")
+			return
+		}
+
+		invalidated := []db.TaskCache{}
+		for _, cache := range caches {
+			if path == "" || cache.Path == path {
+				invalidated = append(invalidated, cache)
+			}
+		}
+
+		if s.taskCaches != nil {
+			err := s.taskCaches.Invalidate(logger, invalidated)
+			if err != nil {
+				apierror.Internal(w, "failed to delete job caches from storage")
+				return
+			}
+		}
+
+		_, err = pipelineDB.DeleteTaskCaches(jobName, path)
+		if err != nil {
+			logger.Error("failed-to-delete-task-caches", err)
+			apierror.DBFailure(w, "failed to invalidate job caches")
+			return
+		}
+
+		logger.Info("invalidated", lager.Data{"job": jobName, "caches": len(invalidated)})
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
@@ -3,33 +3,64 @@ package jobserver
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"strconv"
 
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
 	"github.com/concourse/atc/api/present"
+	"github.com/concourse/atc/auth"
+	"github.com/concourse/atc/config"
 	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/requestlog"
 )
 
 func (s *Server) CreateJobBuild(pipelineDB db.PipelineDB) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		logger := s.logger.Session("create-job-build")
+		logger := requestlog.Logger(s.logger.Session("create-job-build"), r)
 
 		jobName := r.FormValue(":job_name")
 
-		config, _, found, err := pipelineDB.GetConfig()
+		if !auth.CheckPipelinePermission(w, r, pipelineDB.Pipeline().Permissions, atc.PipelinePermissionTrigger) {
+			return
+		}
+
+		priority := 0
+		if value := r.URL.Query().Get("priority"); value != "" {
+			var err error
+			priority, err = strconv.Atoi(value)
+			if err != nil {
+				logger.Info("malformed-priority", lager.Data{"error": err.Error()})
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+		}
+
+		var trigger atc.JobTrigger
+		err := json.NewDecoder(r.Body).Decode(&trigger)
+		if err != nil && err != io.EOF {
+			logger.Info("malformed-request", lager.Data{"error": err.Error()})
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		pipelineConfig, _, found, err := pipelineDB.GetConfig()
 		if err != nil {
 			logger.Error("could-not-get-pipeline-config", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.DBFailure(w, "failed to get pipeline config")
 			return
 		}
 
 		if !found {
-			w.WriteHeader(http.StatusNotFound)
+			apierror.NotFound(w, "pipeline config not found")
 			return
 		}
 
-		job, found := config.Jobs.Lookup(jobName)
+		job, found := pipelineConfig.Jobs.Lookup(jobName)
 		if !found {
-			w.WriteHeader(http.StatusNotFound)
+			apierror.NotFound(w, "job not found")
 			return
 		}
 
@@ -38,16 +69,33 @@ func (s *Server) CreateJobBuild(pipelineDB db.PipelineDB) http.Handler {
 			return
 		}
 
+		params, err := config.ResolveTriggerParams(job, trigger.Params)
+		if err != nil {
+			logger.Info("invalid-params", lager.Data{"error": err.Error()})
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
 		scheduler := s.schedulerFactory.BuildScheduler(pipelineDB, s.externalURL)
 
-		build, _, err := scheduler.TriggerImmediately(logger, job, config.Resources, config.ResourceTypes)
+		build, _, err := scheduler.TriggerImmediately(logger, job, pipelineConfig.Resources, pipelineConfig.ResourceTypes, params)
 		if err != nil {
 			logger.Error("failed-to-trigger", err)
-			w.WriteHeader(http.StatusInternalServerError)
-			fmt.Fprintf(w, "failed to trigger: %s", err)
+			apierror.BuilderFailure(w, fmt.Sprintf("failed to trigger: %s", err))
 			return
 		}
 
+		if priority != 0 {
+			// the build may already have been scheduled if its inputs were ready,
+			// in which case there is no queue left for the priority to apply to
+			_, err = build.SetPriority(priority)
+			if err != nil {
+				logger.Error("failed-to-set-priority", err)
+				apierror.DBFailure(w, "failed to set priority")
+				return
+			}
+		}
+
 		json.NewEncoder(w).Encode(present.Build(build))
 	})
 }
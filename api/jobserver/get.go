@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/concourse/atc/api/apierror"
 	"github.com/concourse/atc/api/present"
 	"github.com/concourse/atc/db"
 )
@@ -16,25 +17,25 @@ func (s *Server) GetJob(pipelineDB db.PipelineDB) http.Handler {
 		config, _, found, err := pipelineDB.GetConfig()
 		if err != nil {
 			logger.Error("could-not-get-pipeline-config", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.DBFailure(w, "failed to get pipeline config")
 			return
 		}
 
 		if !found {
-			w.WriteHeader(http.StatusNotFound)
+			apierror.NotFound(w, "pipeline config not found")
 			return
 		}
 
 		job, found := config.Jobs.Lookup(jobName)
 		if !found {
-			w.WriteHeader(http.StatusNotFound)
+			apierror.NotFound(w, "job not found")
 			return
 		}
 
 		finished, next, err := pipelineDB.GetJobFinishedAndNextBuild(jobName)
 		if err != nil {
 			logger.Error("could-not-get-job-finished-and-next-build", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.DBFailure(w, "failed to get job builds")
 			return
 		}
 
@@ -43,7 +44,7 @@ func (s *Server) GetJob(pipelineDB db.PipelineDB) http.Handler {
 		dbJob, err := pipelineDB.GetJob(job.Name)
 		if err != nil {
 			logger.Error("could-not-get-job-finished", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.DBFailure(w, "failed to get job builds")
 			return
 		}
 
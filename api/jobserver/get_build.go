@@ -1,9 +1,12 @@
 package jobserver
 
 import (
+	"bytes"
 	"encoding/json"
 	"net/http"
 
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/api/conditional"
 	"github.com/concourse/atc/api/present"
 	"github.com/concourse/atc/db"
 )
@@ -17,17 +20,24 @@ func (s *Server) GetJobBuild(pipelineDB db.PipelineDB) http.Handler {
 		build, found, err := pipelineDB.GetJobBuild(jobName, buildName)
 		if err != nil {
 			logger.Error("failed-to-get-job-build", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.DBFailure(w, "failed to get job build")
 			return
 		}
 
 		if !found {
-			w.WriteHeader(http.StatusNotFound)
+			apierror.NotFound(w, "build not found")
+			return
+		}
+
+		var payload bytes.Buffer
+		json.NewEncoder(&payload).Encode(present.Build(build))
+
+		if conditional.NotModified(w, r, payload.Bytes()) {
 			return
 		}
 
 		w.WriteHeader(http.StatusOK)
 
-		json.NewEncoder(w).Encode(present.Build(build))
+		payload.WriteTo(w)
 	})
 }
@@ -0,0 +1,84 @@
+package jobserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/cron"
+	"github.com/concourse/atc/db"
+)
+
+const (
+	defaultUpcomingTriggers = 5
+	maxUpcomingTriggers     = 100
+)
+
+func (s *Server) GetJobSchedule(pipelineDB db.PipelineDB) http.Handler {
+	logger := s.logger.Session("get-job-schedule")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jobName := r.FormValue(":job_name")
+
+		config, _, found, err := pipelineDB.GetConfig()
+		if err != nil {
+			logger.Error("could-not-get-pipeline-config", err)
+			apierror.DBFailure(w, "failed to get pipeline config")
+			return
+		}
+
+		if !found {
+			apierror.NotFound(w, "pipeline config not found")
+			return
+		}
+
+		job, found := config.Jobs.Lookup(jobName)
+		if !found {
+			apierror.NotFound(w, "job not found")
+			return
+		}
+
+		if job.Schedule == "" {
+			apierror.NotFound(w, "job has no schedule")
+			return
+		}
+
+		schedule, err := cron.Parse(job.Schedule)
+		if err != nil {
+			logger.Error("invalid-schedule", err)
+			apierror.Internal(w, "job has an invalid schedule")
+			return
+		}
+
+		count, _ := strconv.Atoi(r.FormValue("count"))
+		if count <= 0 {
+			count = defaultUpcomingTriggers
+		}
+
+		if count > maxUpcomingTriggers {
+			count = maxUpcomingTriggers
+		}
+
+		upcoming := []int64{}
+
+		next := time.Now().UTC()
+		for i := 0; i < count; i++ {
+			next = schedule.Next(next)
+			if next.IsZero() {
+				break
+			}
+
+			upcoming = append(upcoming, next.Unix())
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		json.NewEncoder(w).Encode(atc.JobSchedule{
+			Schedule: job.Schedule,
+			Upcoming: upcoming,
+		})
+	})
+}
@@ -0,0 +1,52 @@
+package jobserver
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/api/present"
+	"github.com/concourse/atc/db"
+)
+
+func (s *Server) GetJobScheduling(pipelineDB db.PipelineDB) http.Handler {
+	logger := s.logger.Session("get-job-scheduling")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jobName := r.FormValue(":job_name")
+
+		config, _, found, err := pipelineDB.GetConfig()
+		if err != nil {
+			logger.Error("could-not-get-pipeline-config", err)
+			apierror.DBFailure(w, "failed to get pipeline config")
+			return
+		}
+
+		if !found {
+			apierror.NotFound(w, "pipeline config not found")
+			return
+		}
+
+		_, found = config.Jobs.Lookup(jobName)
+		if !found {
+			apierror.NotFound(w, "job not found")
+			return
+		}
+
+		scheduling, found, err := pipelineDB.GetJobScheduling(jobName)
+		if err != nil {
+			logger.Error("could-not-get-job-scheduling", err)
+			apierror.DBFailure(w, "failed to get job scheduling")
+			return
+		}
+
+		if !found {
+			apierror.NotFound(w, "job has not been scheduled yet")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		json.NewEncoder(w).Encode(present.JobScheduling(scheduling))
+	})
+}
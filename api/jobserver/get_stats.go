@@ -0,0 +1,96 @@
+package jobserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/db"
+)
+
+const maxStatsWindows = 10
+
+var defaultStatsWindows = []string{"24h", "168h", "720h"}
+
+func (s *Server) GetJobStats(pipelineDB db.PipelineDB) http.Handler {
+	logger := s.logger.Session("get-job-stats")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jobName := r.FormValue(":job_name")
+
+		windows := r.URL.Query()["window"]
+		if len(windows) == 0 {
+			windows = defaultStatsWindows
+		}
+
+		if len(windows) > maxStatsWindows {
+			logger.Info("too-many-windows", lager.Data{"windows": len(windows)})
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		durations := make([]time.Duration, len(windows))
+		for i, window := range windows {
+			duration, err := time.ParseDuration(window)
+			if err != nil || duration <= 0 {
+				logger.Info("malformed-window", lager.Data{"window": window})
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			durations[i] = duration
+		}
+
+		config, _, found, err := pipelineDB.GetConfig()
+		if err != nil {
+			logger.Error("could-not-get-pipeline-config", err)
+			apierror.DBFailure(w, "failed to get pipeline config")
+			return
+		}
+
+		if !found {
+			apierror.NotFound(w, "pipeline config not found")
+			return
+		}
+
+		_, found = config.Jobs.Lookup(jobName)
+		if !found {
+			apierror.NotFound(w, "job not found")
+			return
+		}
+
+		stats := atc.JobStats{
+			Windows: make([]atc.JobStatsWindow, len(windows)),
+		}
+
+		for i, duration := range durations {
+			buildStats, err := pipelineDB.GetJobBuildStats(jobName, duration)
+			if err != nil {
+				logger.Error("could-not-get-job-build-stats", err, lager.Data{"window": windows[i]})
+				apierror.DBFailure(w, "failed to get job build stats")
+				return
+			}
+
+			stats.Windows[i] = atc.JobStatsWindow{
+				Window: windows[i],
+
+				Succeeded: buildStats.Succeeded,
+				Failed:    buildStats.Failed,
+				Errored:   buildStats.Errored,
+				Aborted:   buildStats.Aborted,
+				TimedOut:  buildStats.TimedOut,
+
+				MeanDuration:   int64(buildStats.MeanDuration.Seconds()),
+				MedianDuration: int64(buildStats.MedianDuration.Seconds()),
+				P95Duration:    int64(buildStats.P95Duration.Seconds()),
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		json.NewEncoder(w).Encode(stats)
+	})
+}
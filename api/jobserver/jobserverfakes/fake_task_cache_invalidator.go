@@ -0,0 +1,85 @@
+// This file was generated by counterfeiter
+package jobserverfakes
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/api/jobserver"
+	"github.com/concourse/atc/db"
+)
+
+type FakeTaskCacheInvalidator struct {
+	InvalidateStub        func(logger lager.Logger, caches []db.TaskCache) error
+	invalidateMutex       sync.RWMutex
+	invalidateArgsForCall []struct {
+		logger lager.Logger
+		caches []db.TaskCache
+	}
+	invalidateReturns struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeTaskCacheInvalidator) Invalidate(logger lager.Logger, caches []db.TaskCache) error {
+	var cachesCopy []db.TaskCache
+	if caches != nil {
+		cachesCopy = make([]db.TaskCache, len(caches))
+		copy(cachesCopy, caches)
+	}
+	fake.invalidateMutex.Lock()
+	fake.invalidateArgsForCall = append(fake.invalidateArgsForCall, struct {
+		logger lager.Logger
+		caches []db.TaskCache
+	}{logger, cachesCopy})
+	fake.recordInvocation("Invalidate", []interface{}{logger, cachesCopy})
+	fake.invalidateMutex.Unlock()
+	if fake.InvalidateStub != nil {
+		return fake.InvalidateStub(logger, caches)
+	} else {
+		return fake.invalidateReturns.result1
+	}
+}
+
+func (fake *FakeTaskCacheInvalidator) InvalidateCallCount() int {
+	fake.invalidateMutex.RLock()
+	defer fake.invalidateMutex.RUnlock()
+	return len(fake.invalidateArgsForCall)
+}
+
+func (fake *FakeTaskCacheInvalidator) InvalidateArgsForCall(i int) (lager.Logger, []db.TaskCache) {
+	fake.invalidateMutex.RLock()
+	defer fake.invalidateMutex.RUnlock()
+	return fake.invalidateArgsForCall[i].logger, fake.invalidateArgsForCall[i].caches
+}
+
+func (fake *FakeTaskCacheInvalidator) InvalidateReturns(result1 error) {
+	fake.InvalidateStub = nil
+	fake.invalidateReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeTaskCacheInvalidator) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.invalidateMutex.RLock()
+	defer fake.invalidateMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeTaskCacheInvalidator) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ jobserver.TaskCacheInvalidator = new(FakeTaskCacheInvalidator)
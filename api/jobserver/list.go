@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
 	"github.com/concourse/atc/api/present"
 	"github.com/concourse/atc/db"
 )
@@ -18,7 +19,7 @@ func (s *Server) ListJobs(pipelineDB db.PipelineDB) http.Handler {
 		dashboard, groups, err := pipelineDB.GetDashboard()
 		if err != nil {
 			logger.Error("failed-to-get-dashboard", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.DBFailure(w, "failed to get dashboard")
 			return
 		}
 
@@ -1,12 +1,15 @@
 package jobserver
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
 
 	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/api/conditional"
 	"github.com/concourse/atc/api/present"
 	"github.com/concourse/atc/db"
 )
@@ -42,7 +45,7 @@ func (s *Server) ListJobBuilds(pipelineDB db.PipelineDB) http.Handler {
 			Limit: limit,
 		})
 		if err != nil {
-			w.WriteHeader(http.StatusNotFound)
+			apierror.NotFound(w, "job not found")
 			return
 		}
 
@@ -54,13 +57,21 @@ func (s *Server) ListJobBuilds(pipelineDB db.PipelineDB) http.Handler {
 			s.addPreviousLink(w, teamName, pipelineDB.GetPipelineName(), jobName, *pagination.Previous)
 		}
 
-		w.WriteHeader(http.StatusOK)
-
 		jobBuilds := make([]atc.Build, len(builds))
 		for i := 0; i < len(builds); i++ {
 			jobBuilds[i] = present.Build(builds[i])
 		}
-		json.NewEncoder(w).Encode(jobBuilds)
+
+		var payload bytes.Buffer
+		json.NewEncoder(&payload).Encode(jobBuilds)
+
+		if conditional.NotModified(w, r, payload.Bytes()) {
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+
+		payload.WriteTo(w)
 	})
 }
 
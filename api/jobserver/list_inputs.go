@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
 	"github.com/concourse/atc/api/present"
 	"github.com/concourse/atc/config"
 	"github.com/concourse/atc/db"
@@ -18,18 +19,18 @@ func (s *Server) ListJobInputs(pipelineDB db.PipelineDB) http.Handler {
 		pipelineConfig, _, found, err := pipelineDB.GetConfig()
 		if err != nil {
 			logger.Error("failed-to-get-config", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.DBFailure(w, "failed to get pipeline config")
 			return
 		}
 
 		if !found {
-			w.WriteHeader(http.StatusNotFound)
+			apierror.NotFound(w, "pipeline config not found")
 			return
 		}
 
 		jobConfig, found := pipelineConfig.Jobs.Lookup(jobName)
 		if !found {
-			w.WriteHeader(http.StatusNotFound)
+			apierror.NotFound(w, "job not found")
 			return
 		}
 
@@ -37,19 +38,19 @@ func (s *Server) ListJobInputs(pipelineDB db.PipelineDB) http.Handler {
 
 		err = scheduler.SaveNextInputMapping(logger, jobConfig)
 		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.BuilderFailure(w, "failed to determine next build inputs")
 			return
 		}
 
 		buildInputs, found, err := pipelineDB.GetNextBuildInputs(jobName)
 		if err != nil {
 			logger.Error("failed-to-get-next-build-inputs", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.DBFailure(w, "failed to get next build inputs")
 			return
 		}
 
 		if !found {
-			w.WriteHeader(http.StatusNotFound)
+			apierror.NotFound(w, "next build inputs not found")
 			return
 		}
 
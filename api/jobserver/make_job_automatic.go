@@ -0,0 +1,23 @@
+package jobserver
+
+import (
+	"net/http"
+
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/db"
+	"github.com/tedsuo/rata"
+)
+
+func (s *Server) MakeJobAutomatic(pipelineDB db.PipelineDB) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jobName := rata.Param(r, "job_name")
+
+		err := pipelineDB.MakeJobAutomatic(jobName)
+		if err != nil {
+			apierror.DBFailure(w, "failed to make job automatic")
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	})
+}
@@ -0,0 +1,23 @@
+package jobserver
+
+import (
+	"net/http"
+
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/db"
+	"github.com/tedsuo/rata"
+)
+
+func (s *Server) MakeJobManualOnly(pipelineDB db.PipelineDB) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jobName := rata.Param(r, "job_name")
+
+		err := pipelineDB.MakeJobManualOnly(jobName)
+		if err != nil {
+			apierror.DBFailure(w, "failed to make job manual only")
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	})
+}
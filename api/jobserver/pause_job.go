@@ -3,6 +3,7 @@ package jobserver
 import (
 	"net/http"
 
+	"github.com/concourse/atc/api/apierror"
 	"github.com/concourse/atc/db"
 	"github.com/tedsuo/rata"
 )
@@ -13,7 +14,7 @@ func (s *Server) PauseJob(pipelineDB db.PipelineDB) http.Handler {
 
 		err := pipelineDB.PauseJob(jobName)
 		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.DBFailure(w, "failed to pause job")
 			return
 		}
 
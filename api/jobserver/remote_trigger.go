@@ -0,0 +1,103 @@
+package jobserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/api/present"
+	"github.com/concourse/atc/auth"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/requestlog"
+)
+
+// ReceiveRemoteTrigger triggers the job on behalf of a build on another ATC.
+// Any versions passed along are saved first, so that the triggered build
+// picks them up just as it would had its own check found them.
+func (s *Server) ReceiveRemoteTrigger(pipelineDB db.PipelineDB) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jobName := r.FormValue(":job_name")
+
+		logger := requestlog.Logger(s.logger.Session("receive-remote-trigger", lager.Data{"job": jobName}), r)
+
+		if !auth.CheckPipelinePermission(w, r, pipelineDB.Pipeline().Permissions, atc.PipelinePermissionTrigger) {
+			return
+		}
+
+		var trigger atc.RemoteTrigger
+		err := json.NewDecoder(r.Body).Decode(&trigger)
+		if err != nil {
+			logger.Info("malformed-request", lager.Data{"error": err.Error()})
+			http.Error(w, "malformed request", http.StatusBadRequest)
+			return
+		}
+
+		logger = logger.WithData(lager.Data{
+			"source-team":     trigger.Source.TeamName,
+			"source-pipeline": trigger.Source.PipelineName,
+			"source-job":      trigger.Source.JobName,
+			"source-build":    trigger.Source.BuildName,
+		})
+
+		config, _, found, err := pipelineDB.GetConfig()
+		if err != nil {
+			logger.Error("could-not-get-pipeline-config", err)
+			apierror.DBFailure(w, "failed to get pipeline config")
+			return
+		}
+
+		if !found {
+			apierror.NotFound(w, "pipeline config not found")
+			return
+		}
+
+		job, found := config.Jobs.Lookup(jobName)
+		if !found {
+			apierror.NotFound(w, "job not found")
+			return
+		}
+
+		if job.DisableManualTrigger {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+
+		for name := range trigger.Versions {
+			if _, found := config.Resources.Lookup(name); !found {
+				logger.Info("unknown-resource", lager.Data{"resource": name})
+				http.Error(w, fmt.Sprintf("unknown resource '%s'", name), http.StatusUnprocessableEntity)
+				return
+			}
+		}
+
+		for name, version := range trigger.Versions {
+			resourceConfig, _ := config.Resources.Lookup(name)
+
+			err := pipelineDB.SaveResourceVersions(resourceConfig, []atc.Version{version})
+			if err != nil {
+				logger.Error("failed-to-save-version", err, lager.Data{"resource": name})
+				apierror.DBFailure(w, "failed to save version")
+				return
+			}
+		}
+
+		scheduler := s.schedulerFactory.BuildScheduler(pipelineDB, s.externalURL)
+
+		build, _, err := scheduler.TriggerImmediately(logger, job, config.Resources, config.ResourceTypes, nil)
+		if err != nil {
+			logger.Error("failed-to-trigger", err)
+			apierror.BuilderFailure(w, fmt.Sprintf("failed to trigger: %s", err))
+			return
+		}
+
+		logger.Info("triggered", lager.Data{"build": build.ID()})
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+
+		json.NewEncoder(w).Encode(present.Build(build))
+	})
+}
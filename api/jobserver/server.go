@@ -13,11 +13,18 @@ type SchedulerFactory interface {
 	BuildScheduler(db.PipelineDB, string) scheduler.BuildScheduler
 }
 
+//go:generate counterfeiter . TaskCacheInvalidator
+
+type TaskCacheInvalidator interface {
+	Invalidate(logger lager.Logger, caches []db.TaskCache) error
+}
+
 type Server struct {
 	logger lager.Logger
 
 	schedulerFactory SchedulerFactory
 	externalURL      string
+	taskCaches       TaskCacheInvalidator
 	rejector         auth.Rejector
 }
 
@@ -25,11 +32,13 @@ func NewServer(
 	logger lager.Logger,
 	schedulerFactory SchedulerFactory,
 	externalURL string,
+	taskCaches TaskCacheInvalidator,
 ) *Server {
 	return &Server{
 		logger:           logger,
 		schedulerFactory: schedulerFactory,
 		externalURL:      externalURL,
+		taskCaches:       taskCaches,
 		rejector:         auth.UnauthorizedRejector{},
 	}
 }
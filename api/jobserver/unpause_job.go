@@ -3,6 +3,7 @@ package jobserver
 import (
 	"net/http"
 
+	"github.com/concourse/atc/api/apierror"
 	"github.com/concourse/atc/db"
 	"github.com/tedsuo/rata"
 )
@@ -15,7 +16,7 @@ func (s *Server) UnpauseJob(pipelineDB db.PipelineDB) http.Handler {
 		err := pipelineDB.UnpauseJob(jobName)
 		if err != nil {
 			logger.Error("failed-to-unpause-job", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.DBFailure(w, "failed to unpause job")
 			return
 		}
 
@@ -0,0 +1,317 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Maintenance API", func() {
+	Describe("GET /api/v1/maintenance", func() {
+		var response *http.Response
+
+		JustBeforeEach(func() {
+			var err error
+			response, err = client.Get(server.URL + "/api/v1/maintenance")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when not authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns 401", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+
+		Context("when authenticated but not admin", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+			})
+
+			It("returns 403", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+			})
+		})
+
+		Context("when authenticated as an admin", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("main", 1, true, true)
+			})
+
+			Context("when a window is open", func() {
+				var maintenance db.Maintenance
+				var freezeEnd time.Time
+
+				BeforeEach(func() {
+					freezeEnd = time.Now().Add(time.Hour).Truncate(time.Second)
+
+					maintenance = db.Maintenance{
+						Windows: []db.MaintenanceWindow{
+							{
+								Name:     "freeze",
+								StartsAt: time.Unix(100, 0),
+								EndsAt:   freezeEnd,
+							},
+							{
+								Name:     "weekends",
+								Schedule: "0 0 * * sat",
+								Duration: 48 * time.Hour,
+							},
+						},
+					}
+
+					buildServerDB.GetMaintenanceReturns(maintenance, nil)
+				})
+
+				It("returns the windows and the one that's open", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+					var maintenance atc.Maintenance
+					err := json.NewDecoder(response.Body).Decode(&maintenance)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(maintenance.Windows).To(Equal([]atc.MaintenanceWindow{
+						{Name: "freeze", StartTime: 100, EndTime: freezeEnd.Unix()},
+						{Name: "weekends", Schedule: "0 0 * * sat", Duration: "48h0m0s"},
+					}))
+
+					Expect(maintenance.Active).NotTo(BeNil())
+					Expect(maintenance.Active.Name).To(Equal("freeze"))
+					Expect(maintenance.Active.EndTime).To(Equal(freezeEnd.Unix()))
+					Expect(maintenance.OverriddenUntil).To(BeZero())
+				})
+
+				Context("when the windows are overridden", func() {
+					var overriddenUntil time.Time
+
+					BeforeEach(func() {
+						overriddenUntil = time.Now().Add(time.Minute).Truncate(time.Second)
+
+						maintenance.OverriddenUntil = overriddenUntil
+						buildServerDB.GetMaintenanceReturns(maintenance, nil)
+					})
+
+					It("says until when", func() {
+						var maintenance atc.Maintenance
+						err := json.NewDecoder(response.Body).Decode(&maintenance)
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(maintenance.OverriddenUntil).To(Equal(overriddenUntil.Unix()))
+					})
+				})
+			})
+
+			Context("when there are no windows", func() {
+				BeforeEach(func() {
+					buildServerDB.GetMaintenanceReturns(db.Maintenance{
+						Windows:         []db.MaintenanceWindow{},
+						OverriddenUntil: time.Unix(100, 0),
+					}, nil)
+				})
+
+				It("returns none, and leaves out overrides that have run out", func() {
+					body, err := ioutil.ReadAll(response.Body)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(body).To(MatchJSON(`{"windows":[]}`))
+				})
+			})
+
+			Context("when getting the windows fails", func() {
+				BeforeEach(func() {
+					buildServerDB.GetMaintenanceReturns(db.Maintenance{}, errors.New("nope"))
+				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+	})
+
+	Describe("PUT /api/v1/maintenance/windows", func() {
+		var (
+			payload string
+
+			response *http.Response
+		)
+
+		BeforeEach(func() {
+			payload = `[
+				{"name":"freeze","start_time":100,"end_time":200},
+				{"name":"weekends","schedule":"0 0 * * sat","duration":"48h"}
+			]`
+
+			buildServerDB.GetMaintenanceReturns(db.Maintenance{}, nil)
+		})
+
+		JustBeforeEach(func() {
+			req, err := http.NewRequest("PUT", server.URL+"/api/v1/maintenance/windows", bytes.NewBufferString(payload))
+			Expect(err).NotTo(HaveOccurred())
+
+			response, err = client.Do(req)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when authenticated but not admin", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+			})
+
+			It("returns 403 without setting the windows", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+				Expect(buildServerDB.SetMaintenanceWindowsCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when authenticated as an admin", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("main", 1, true, true)
+			})
+
+			It("sets the windows", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+				Expect(buildServerDB.SetMaintenanceWindowsCallCount()).To(Equal(1))
+				_, windows := buildServerDB.SetMaintenanceWindowsArgsForCall(0)
+				Expect(windows).To(Equal([]db.MaintenanceWindow{
+					{Name: "freeze", StartsAt: time.Unix(100, 0), EndsAt: time.Unix(200, 0)},
+					{Name: "weekends", Schedule: "0 0 * * sat", Duration: 48 * time.Hour},
+				}))
+			})
+
+			Context("when setting them fails", func() {
+				BeforeEach(func() {
+					buildServerDB.SetMaintenanceWindowsReturns(errors.New("nope"))
+				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+
+			for _, invalid := range []string{
+				`{"schedule":"@daily","duration":"1h"}`,
+				`{"name":"a","start_time":200,"end_time":100}`,
+				`{"name":"a","duration":"1h","start_time":100,"end_time":200}`,
+				`{"name":"a","schedule":"@daily"}`,
+				`{"name":"a","schedule":"@daily","duration":"30s"}`,
+				`{"name":"a","schedule":"every day","duration":"1h"}`,
+				`{"name":"a","schedule":"@daily","duration":"1h","end_time":200}`,
+			} {
+				invalid := invalid
+
+				Context(fmt.Sprintf("when given the invalid window %s", invalid), func() {
+					BeforeEach(func() {
+						payload = "[" + invalid + "]"
+					})
+
+					It("returns 400 without setting the windows", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+						Expect(buildServerDB.SetMaintenanceWindowsCallCount()).To(BeZero())
+					})
+				})
+			}
+
+			Context("when two windows have the same name", func() {
+				BeforeEach(func() {
+					payload = `[
+						{"name":"a","start_time":100,"end_time":200},
+						{"name":"a","start_time":300,"end_time":400}
+					]`
+				})
+
+				It("returns 400", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+				})
+			})
+		})
+	})
+
+	Describe("PUT /api/v1/maintenance/override", func() {
+		var (
+			until    time.Time
+			response *http.Response
+		)
+
+		BeforeEach(func() {
+			until = time.Now().Add(time.Hour).Truncate(time.Second)
+
+			buildServerDB.GetMaintenanceReturns(db.Maintenance{}, nil)
+
+			authValidator.IsAuthenticatedReturns(true)
+			userContextReader.GetTeamReturns("main", 1, true, true)
+		})
+
+		JustBeforeEach(func() {
+			payload := fmt.Sprintf(`{"until":%d}`, until.Unix())
+
+			req, err := http.NewRequest("PUT", server.URL+"/api/v1/maintenance/override", bytes.NewBufferString(payload))
+			Expect(err).NotTo(HaveOccurred())
+
+			response, err = client.Do(req)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("overrides the windows until then", func() {
+			Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+			Expect(buildServerDB.SetMaintenanceOverrideCallCount()).To(Equal(1))
+			_, overriddenUntil := buildServerDB.SetMaintenanceOverrideArgsForCall(0)
+			Expect(overriddenUntil).To(Equal(until))
+		})
+
+		Context("when the time has already passed", func() {
+			BeforeEach(func() {
+				until = time.Now().Add(-time.Hour)
+			})
+
+			It("returns 400", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+				Expect(buildServerDB.SetMaintenanceOverrideCallCount()).To(BeZero())
+			})
+		})
+	})
+
+	Describe("DELETE /api/v1/maintenance/override", func() {
+		var response *http.Response
+
+		BeforeEach(func() {
+			buildServerDB.GetMaintenanceReturns(db.Maintenance{}, nil)
+
+			authValidator.IsAuthenticatedReturns(true)
+			userContextReader.GetTeamReturns("main", 1, true, true)
+		})
+
+		JustBeforeEach(func() {
+			req, err := http.NewRequest("DELETE", server.URL+"/api/v1/maintenance/override", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			response, err = client.Do(req)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("removes the override", func() {
+			Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+			Expect(buildServerDB.SetMaintenanceOverrideCallCount()).To(Equal(1))
+			_, overriddenUntil := buildServerDB.SetMaintenanceOverrideArgsForCall(0)
+			Expect(overriddenUntil).To(BeZero())
+		})
+	})
+})
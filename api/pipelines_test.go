@@ -2,6 +2,7 @@ package api_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"io"
 	"io/ioutil"
@@ -283,6 +284,35 @@ var _ = Describe("Pipelines API", func() {
 					}]`))
 			})
 
+			Context("when a private pipeline needs a higher role to view it than the request has", func() {
+				BeforeEach(func() {
+					userContextReader.GetRoleReturns(atc.RoleViewer, true)
+
+					teamDB.GetPipelinesReturns([]db.SavedPipeline{
+						{
+							TeamName:    "main",
+							Permissions: atc.PipelinePermissions{View: atc.RoleOperator},
+							Pipeline:    db.Pipeline{Name: "private-pipeline"},
+						},
+						{
+							TeamName:    "main",
+							Public:      true,
+							Permissions: atc.PipelinePermissions{View: atc.RoleOperator},
+							Pipeline:    db.Pipeline{Name: "public-pipeline"},
+						},
+					}, nil)
+				})
+
+				It("leaves it out", func() {
+					var pipelines []atc.Pipeline
+					err := json.NewDecoder(response.Body).Decode(&pipelines)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(pipelines).To(HaveLen(1))
+					Expect(pipelines[0].Name).To(Equal("public-pipeline"))
+				})
+			})
+
 			Context("when the call to get active pipelines fails", func() {
 				BeforeEach(func() {
 					teamDB.GetPipelinesReturns(nil, errors.New("disaster"))
@@ -1049,6 +1079,9 @@ var _ = Describe("Pipelines API", func() {
 						ResourceIDs: map[string]int{
 							"resource-127": 127,
 						},
+						PinnedVersionIDs: map[int]int{
+							127: 73,
+						},
 						CachedAt: time.Unix(42, 0).UTC(),
 					},
 					nil,
@@ -1105,6 +1138,9 @@ var _ = Describe("Pipelines API", func() {
 				"ResourceIDs": {
 					"resource-127": 127
 				},
+				"PinnedVersionIDs": {
+					"127": 73
+				},
 				"CachedAt": "1970-01-01T00:00:42Z"
 				}`))
 			})
@@ -1121,6 +1157,299 @@ var _ = Describe("Pipelines API", func() {
 		})
 	})
 
+	Describe("GET /api/v1/teams/:team_name/pipelines/:pipeline_name/permissions", func() {
+		var response *http.Response
+
+		JustBeforeEach(func() {
+			var err error
+
+			response, err = client.Get(server.URL + "/api/v1/teams/a-team/pipelines/a-pipeline/permissions")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when requester belongs to the team", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("a-team", 42, true, true)
+
+				pipelineDB.PipelineReturns(db.SavedPipeline{
+					Permissions: atc.PipelinePermissions{
+						Trigger: atc.RoleAdmin,
+					},
+				})
+			})
+
+			It("returns 200", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+			})
+
+			It("returns the pipeline's permissions", func() {
+				body, err := ioutil.ReadAll(response.Body)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(body).To(MatchJSON(`{"trigger":"admin"}`))
+			})
+		})
+
+		Context("when requester does not belong to the team", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("another-team", 42, true, true)
+			})
+
+			It("returns 403 Forbidden", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+			})
+		})
+	})
+
+	Describe("PUT /api/v1/teams/:team_name/pipelines/:pipeline_name/permissions", func() {
+		var (
+			requestBody string
+			response    *http.Response
+		)
+
+		BeforeEach(func() {
+			requestBody = `{"trigger":"admin","configure":"admin"}`
+		})
+
+		JustBeforeEach(func() {
+			request, err := http.NewRequest("PUT", server.URL+"/api/v1/teams/a-team/pipelines/a-pipeline/permissions", bytes.NewBufferString(requestBody))
+			Expect(err).NotTo(HaveOccurred())
+
+			response, err = client.Do(request)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when requester belongs to the team", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("a-team", 42, true, true)
+			})
+
+			It("returns 200", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+			})
+
+			It("sets the pipeline's permissions", func() {
+				Expect(pipelineDB.SetPermissionsCallCount()).To(Equal(1))
+				Expect(pipelineDB.SetPermissionsArgsForCall(0)).To(Equal(atc.PipelinePermissions{
+					Trigger:   atc.RoleAdmin,
+					Configure: atc.RoleAdmin,
+				}))
+			})
+
+			Context("when a permission asks for an unknown role", func() {
+				BeforeEach(func() {
+					requestBody = `{"trigger":"bogus"}`
+				})
+
+				It("returns 400", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+				})
+
+				It("does not set the permissions", func() {
+					Expect(pipelineDB.SetPermissionsCallCount()).To(BeZero())
+				})
+			})
+
+			Context("when the request is malformed", func() {
+				BeforeEach(func() {
+					requestBody = `{`
+				})
+
+				It("returns 400", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+				})
+			})
+
+			Context("when setting the permissions fails", func() {
+				BeforeEach(func() {
+					pipelineDB.SetPermissionsReturns(errors.New("nope"))
+				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+
+		Context("when requester does not belong to the team", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("another-team", 42, true, true)
+			})
+
+			It("returns 403 Forbidden", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+			})
+		})
+	})
+
+	Describe("GET /api/v1/pipeline-weights", func() {
+		var response *http.Response
+
+		JustBeforeEach(func() {
+			var err error
+
+			response, err = client.Get(server.URL + "/api/v1/pipeline-weights")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when authenticated as an admin", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("main", 1, true, true)
+			})
+
+			Context("when the weights can be looked up", func() {
+				BeforeEach(func() {
+					pipelinesDB.GetPipelineWeightsReturns([]db.PipelineWeight{
+						{PipelineID: 1, PipelineName: "a-pipeline", TeamName: "a-team", Weight: 1},
+						{PipelineID: 2, PipelineName: "another-pipeline", TeamName: "a-team", Weight: 3},
+					}, nil)
+				})
+
+				It("returns 200", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+				})
+
+				It("returns every pipeline's weight", func() {
+					body, err := ioutil.ReadAll(response.Body)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(body).To(MatchJSON(`[
+						{"team_name":"a-team","pipeline_name":"a-pipeline","weight":1},
+						{"team_name":"a-team","pipeline_name":"another-pipeline","weight":3}
+					]`))
+				})
+			})
+
+			Context("when looking up the weights fails", func() {
+				BeforeEach(func() {
+					pipelinesDB.GetPipelineWeightsReturns(nil, errors.New("nope"))
+				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+
+		Context("when authenticated but not admin", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("a-team", 42, false, true)
+			})
+
+			It("returns 403 Forbidden", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+			})
+		})
+	})
+
+	Describe("PUT /api/v1/teams/:team_name/pipelines/:pipeline_name/weight", func() {
+		var (
+			requestBody string
+			response    *http.Response
+		)
+
+		BeforeEach(func() {
+			requestBody = `{"weight":3}`
+		})
+
+		JustBeforeEach(func() {
+			request, err := http.NewRequest("PUT", server.URL+"/api/v1/teams/a-team/pipelines/a-pipeline/weight", bytes.NewBufferString(requestBody))
+			Expect(err).NotTo(HaveOccurred())
+
+			response, err = client.Do(request)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when authenticated as an admin", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("main", 1, true, true)
+
+				pipelineDB.GetPipelineIDReturns(7)
+				pipelineDB.GetPipelineNameReturns("a-pipeline")
+				pipelineDB.PipelineReturns(db.SavedPipeline{TeamName: "a-team"})
+			})
+
+			It("returns 200", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+			})
+
+			It("looks up the pipeline in the requested team", func() {
+				Expect(teamDBFactory.GetTeamDBArgsForCall(teamDBFactory.GetTeamDBCallCount() - 1)).To(Equal("a-team"))
+				Expect(teamDB.GetPipelineByNameArgsForCall(0)).To(Equal("a-pipeline"))
+			})
+
+			It("sets the pipeline's weight", func() {
+				Expect(pipelinesDB.SetPipelineWeightCallCount()).To(Equal(1))
+				_, pipelineID, weight := pipelinesDB.SetPipelineWeightArgsForCall(0)
+				Expect(pipelineID).To(Equal(7))
+				Expect(weight).To(Equal(3))
+			})
+
+			It("returns the new weight", func() {
+				body, err := ioutil.ReadAll(response.Body)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(body).To(MatchJSON(`{"team_name":"a-team","pipeline_name":"a-pipeline","weight":3}`))
+			})
+
+			Context("when the weight is less than 1", func() {
+				BeforeEach(func() {
+					requestBody = `{"weight":0}`
+				})
+
+				It("returns 400", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+				})
+
+				It("does not set the weight", func() {
+					Expect(pipelinesDB.SetPipelineWeightCallCount()).To(BeZero())
+				})
+			})
+
+			Context("when the request is malformed", func() {
+				BeforeEach(func() {
+					requestBody = `{`
+				})
+
+				It("returns 400", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+				})
+			})
+
+			Context("when setting the weight fails", func() {
+				BeforeEach(func() {
+					pipelinesDB.SetPipelineWeightReturns(errors.New("nope"))
+				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+
+		Context("when authenticated as a member of the pipeline's team", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("a-team", 42, false, true)
+			})
+
+			It("returns 403 Forbidden", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+			})
+
+			It("does not set the weight", func() {
+				Expect(pipelinesDB.SetPipelineWeightCallCount()).To(BeZero())
+			})
+		})
+	})
+
 	Describe("PUT /api/v1/teams/:team_name/pipelines/:pipeline_name/rename", func() {
 		var response *http.Response
 
@@ -1187,6 +1516,293 @@ var _ = Describe("Pipelines API", func() {
 			})
 		})
 
+		Context("when not authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns 401 Unauthorized", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+	})
+	Describe("GET /api/v1/teams/:team_name/pipelines/:pipeline_name/instances", func() {
+		var response *http.Response
+
+		JustBeforeEach(func() {
+			var err error
+
+			response, err = client.Get(server.URL + "/api/v1/teams/a-team/pipelines/a-template/instances")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("a-team", 42, true, true)
+			})
+
+			Context("when the template exists", func() {
+				BeforeEach(func() {
+					teamDB.GetPipelineInstancesReturns([]db.SavedPipeline{
+						{
+							ID:           2,
+							Paused:       true,
+							TeamName:     "a-team",
+							InstanceOf:   1,
+							InstanceVars: map[string]interface{}{"branch": "release/1"},
+							Pipeline: db.Pipeline{
+								Name: "release-1",
+							},
+						},
+					}, true, nil)
+				})
+
+				It("returns 200 OK", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+				})
+
+				It("returns the template's instances", func() {
+					Expect(teamDBFactory.GetTeamDBArgsForCall(0)).To(Equal("a-team"))
+					Expect(teamDB.GetPipelineInstancesArgsForCall(0)).To(Equal("a-template"))
+
+					body, err := ioutil.ReadAll(response.Body)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(body).To(MatchJSON(`[
+						{
+							"name": "release-1",
+							"url": "/teams/a-team/pipelines/release-1",
+							"paused": true,
+							"public": false,
+							"team_name": "a-team",
+							"instance_vars": {"branch": "release/1"}
+						}
+					]`))
+				})
+			})
+
+			Context("when the template doesn't exist", func() {
+				BeforeEach(func() {
+					teamDB.GetPipelineInstancesReturns(nil, false, nil)
+				})
+
+				It("returns 404 Not Found", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+				})
+			})
+
+			Context("when looking up the instances fails", func() {
+				BeforeEach(func() {
+					teamDB.GetPipelineInstancesReturns(nil, false, errors.New("nope"))
+				})
+
+				It("returns 500 Internal Server Error", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+
+		Context("when not authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns 401 Unauthorized", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+	})
+
+	Describe("PUT /api/v1/teams/:team_name/pipelines/:pipeline_name/instances/:instance_name", func() {
+		var (
+			body     string
+			response *http.Response
+		)
+
+		BeforeEach(func() {
+			body = `{"vars": {"branch": "release/1"}}`
+		})
+
+		JustBeforeEach(func() {
+			request, err := http.NewRequest("PUT", server.URL+"/api/v1/teams/a-team/pipelines/a-template/instances/release-1", bytes.NewBufferString(body))
+			Expect(err).NotTo(HaveOccurred())
+
+			response, err = client.Do(request)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("a-team", 42, true, true)
+
+				teamDB.SavePipelineInstanceReturns(db.SavedPipeline{
+					ID:           2,
+					Paused:       true,
+					TeamName:     "a-team",
+					InstanceOf:   1,
+					InstanceVars: map[string]interface{}{"branch": "release/1"},
+					Pipeline: db.Pipeline{
+						Name: "release-1",
+					},
+				}, true, nil)
+			})
+
+			It("saves the instance with the vars", func() {
+				Expect(teamDB.SavePipelineInstanceCallCount()).To(Equal(1))
+				templateName, instanceName, vars, _ := teamDB.SavePipelineInstanceArgsForCall(0)
+				Expect(templateName).To(Equal("a-template"))
+				Expect(instanceName).To(Equal("release-1"))
+				Expect(vars).To(Equal(map[string]interface{}{"branch": "release/1"}))
+			})
+
+			It("returns 201 Created with the instance", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusCreated))
+
+				body, err := ioutil.ReadAll(response.Body)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(body).To(MatchJSON(`{
+					"name": "release-1",
+					"url": "/teams/a-team/pipelines/release-1",
+					"paused": true,
+					"public": false,
+					"team_name": "a-team",
+					"instance_vars": {"branch": "release/1"}
+				}`))
+			})
+
+			Context("when the instance already existed", func() {
+				BeforeEach(func() {
+					teamDB.SavePipelineInstanceReturns(db.SavedPipeline{}, false, nil)
+				})
+
+				It("returns 200 OK", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+				})
+			})
+
+			Context("with invalid json", func() {
+				BeforeEach(func() {
+					body = `{`
+				})
+
+				It("returns 400 Bad Request", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+					Expect(teamDB.SavePipelineInstanceCallCount()).To(BeZero())
+				})
+			})
+
+			Context("when the template doesn't exist", func() {
+				BeforeEach(func() {
+					teamDB.SavePipelineInstanceReturns(db.SavedPipeline{}, false, db.ErrPipelineNotFound)
+				})
+
+				It("returns 404 Not Found", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+				})
+			})
+
+			Context("when the template is an instance itself", func() {
+				BeforeEach(func() {
+					teamDB.SavePipelineInstanceReturns(db.SavedPipeline{}, false, db.ErrPipelineIsInstance)
+				})
+
+				It("returns 400 Bad Request", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+				})
+			})
+
+			Context("when another pipeline has the instance's name", func() {
+				BeforeEach(func() {
+					teamDB.SavePipelineInstanceReturns(db.SavedPipeline{}, false, db.ErrPipelineNameTaken)
+				})
+
+				It("returns 409 Conflict", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusConflict))
+				})
+			})
+
+			Context("when saving the instance fails", func() {
+				BeforeEach(func() {
+					teamDB.SavePipelineInstanceReturns(db.SavedPipeline{}, false, errors.New("nope"))
+				})
+
+				It("returns 500 Internal Server Error", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+
+		Context("when requester does not belong to the team", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("another-team", 42, true, true)
+			})
+
+			It("returns 403 Forbidden", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+				Expect(teamDB.SavePipelineInstanceCallCount()).To(BeZero())
+			})
+		})
+	})
+
+	Describe("DELETE /api/v1/teams/:team_name/pipelines/:pipeline_name/instances/:instance_name", func() {
+		var response *http.Response
+
+		JustBeforeEach(func() {
+			request, err := http.NewRequest("DELETE", server.URL+"/api/v1/teams/a-team/pipelines/a-template/instances/release-1", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			response, err = client.Do(request)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("a-team", 42, true, true)
+			})
+
+			Context("when the instance exists", func() {
+				BeforeEach(func() {
+					teamDB.DestroyPipelineInstanceReturns(true, nil)
+				})
+
+				It("destroys it", func() {
+					Expect(teamDB.DestroyPipelineInstanceCallCount()).To(Equal(1))
+					templateName, instanceName := teamDB.DestroyPipelineInstanceArgsForCall(0)
+					Expect(templateName).To(Equal("a-template"))
+					Expect(instanceName).To(Equal("release-1"))
+				})
+
+				It("returns 204 No Content", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusNoContent))
+				})
+			})
+
+			Context("when it isn't an instance of the template", func() {
+				BeforeEach(func() {
+					teamDB.DestroyPipelineInstanceReturns(false, nil)
+				})
+
+				It("returns 404 Not Found", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+				})
+			})
+
+			Context("when destroying it fails", func() {
+				BeforeEach(func() {
+					teamDB.DestroyPipelineInstanceReturns(false, errors.New("nope"))
+				})
+
+				It("returns 500 Internal Server Error", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+
 		Context("when not authenticated", func() {
 			BeforeEach(func() {
 				authValidator.IsAuthenticatedReturns(false)
@@ -0,0 +1,36 @@
+package pipelineserver
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/api/present"
+	"github.com/concourse/atc/auth"
+	"github.com/concourse/atc/db"
+)
+
+// GetDashboard shows the same pipelines as ListAllPipelines, each with the
+// state of its jobs.
+func (s *Server) GetDashboard(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.Session("get-dashboard")
+	authTeam, authTeamFound := auth.GetTeam(r)
+
+	var dashboards []db.PipelineDashboard
+	var err error
+	if authTeamFound {
+		dashboards, err = s.teamDBFactory.GetTeamDB(authTeam.Name()).GetPipelineDashboards()
+	} else {
+		dashboards, err = s.pipelinesDB.GetPublicPipelineDashboards()
+	}
+
+	if err != nil {
+		logger.Error("failed-to-get-pipeline-dashboards", err)
+		apierror.DBFailure(w, "failed to get pipeline dashboards")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	json.NewEncoder(w).Encode(present.Dashboard(viewableDashboards(r, dashboards)))
+}
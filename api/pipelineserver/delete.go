@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/api/apierror"
 	"github.com/concourse/atc/db"
 )
 
@@ -19,7 +20,7 @@ func (s *Server) DeletePipeline(pipelineDB db.PipelineDB) http.Handler {
 		if err != nil {
 			s.logger.Error("failed", err)
 
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.DBFailure(w, "failed to delete pipeline")
 			return
 		}
 
@@ -3,6 +3,7 @@ package pipelineserver
 import (
 	"net/http"
 
+	"github.com/concourse/atc/api/apierror"
 	"github.com/concourse/atc/db"
 )
 
@@ -12,7 +13,7 @@ func (s *Server) ExposePipeline(pipelineDB db.PipelineDB) http.Handler {
 		err := pipelineDB.Expose()
 		if err != nil {
 			logger.Error("failed-to-expose-pipeline", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.DBFailure(w, "failed to expose pipeline")
 			return
 		}
 
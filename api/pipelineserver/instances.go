@@ -0,0 +1,125 @@
+package pipelineserver
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/api/present"
+	"github.com/concourse/atc/auth"
+	"github.com/concourse/atc/db"
+)
+
+func (s *Server) ListPipelineInstances(w http.ResponseWriter, r *http.Request) {
+	teamName := r.FormValue(":team_name")
+	templateName := r.FormValue(":pipeline_name")
+
+	logger := s.logger.Session("list-pipeline-instances", lager.Data{
+		"pipeline": templateName,
+	})
+
+	teamDB := s.teamDBFactory.GetTeamDB(teamName)
+
+	instances, found, err := teamDB.GetPipelineInstances(templateName)
+	if err != nil {
+		logger.Error("failed-to-get-instances", err)
+		apierror.DBFailure(w, "failed to get pipeline instances")
+		return
+	}
+
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	json.NewEncoder(w).Encode(present.Pipelines(instances))
+}
+
+// SavePipelineInstance creates an instance of the pipeline, or replaces the
+// vars of an existing one.
+func (s *Server) SavePipelineInstance(w http.ResponseWriter, r *http.Request) {
+	teamName := r.FormValue(":team_name")
+	templateName := r.FormValue(":pipeline_name")
+	instanceName := r.FormValue(":instance_name")
+
+	logger := s.logger.Session("save-pipeline-instance", lager.Data{
+		"pipeline": templateName,
+		"instance": instanceName,
+	})
+
+	var request struct {
+		Vars map[string]interface{} `json:"vars"`
+	}
+
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		logger.Error("invalid-json", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if request.Vars == nil {
+		request.Vars = map[string]interface{}{}
+	}
+
+	teamDB := s.teamDBFactory.GetTeamDB(teamName)
+
+	instance, created, err := teamDB.SavePipelineInstance(templateName, instanceName, request.Vars, auth.GetActor(r))
+	switch err {
+	case nil:
+	case db.ErrPipelineNotFound:
+		apierror.NotFound(w, "pipeline not found")
+		return
+	case db.ErrPipelineIsInstance:
+		// instances can't have instances of their own
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	case db.ErrPipelineNameTaken:
+		w.WriteHeader(http.StatusConflict)
+		return
+	default:
+		logger.Error("failed-to-save-instance", err)
+		apierror.DBFailure(w, "failed to save pipeline instance")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if created {
+		w.WriteHeader(http.StatusCreated)
+	} else {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	json.NewEncoder(w).Encode(present.Pipeline(instance))
+}
+
+func (s *Server) DeletePipelineInstance(w http.ResponseWriter, r *http.Request) {
+	teamName := r.FormValue(":team_name")
+	templateName := r.FormValue(":pipeline_name")
+	instanceName := r.FormValue(":instance_name")
+
+	logger := s.logger.Session("delete-pipeline-instance", lager.Data{
+		"pipeline": templateName,
+		"instance": instanceName,
+	})
+
+	teamDB := s.teamDBFactory.GetTeamDB(teamName)
+
+	found, err := teamDB.DestroyPipelineInstance(templateName, instanceName)
+	if err != nil {
+		logger.Error("failed-to-destroy-instance", err)
+		apierror.DBFailure(w, "failed to delete pipeline instance")
+		return
+	}
+
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/concourse/atc/api/apierror"
 	"github.com/concourse/atc/api/present"
 	"github.com/concourse/atc/auth"
 	"github.com/concourse/atc/db"
@@ -26,11 +27,11 @@ func (s *Server) ListPipelines(w http.ResponseWriter, r *http.Request) {
 
 	if err != nil {
 		logger.Error("failed-to-get-all-active-pipelines", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		apierror.DBFailure(w, "failed to get all active pipelines")
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 
-	json.NewEncoder(w).Encode(present.Pipelines(pipelines))
+	json.NewEncoder(w).Encode(present.Pipelines(viewablePipelines(r, pipelines)))
 }
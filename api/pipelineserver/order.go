@@ -5,6 +5,8 @@ import (
 	"net/http"
 
 	"code.cloudfoundry.org/lager"
+
+	"github.com/concourse/atc/api/apierror"
 )
 
 func (s *Server) OrderPipelines(w http.ResponseWriter, r *http.Request) {
@@ -26,7 +28,7 @@ func (s *Server) OrderPipelines(w http.ResponseWriter, r *http.Request) {
 			"pipeline-names": pipelineNames,
 		})
 
-		w.WriteHeader(http.StatusInternalServerError)
+		apierror.DBFailure(w, "failed to order pipelines")
 		return
 	}
 
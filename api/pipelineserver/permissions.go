@@ -0,0 +1,50 @@
+package pipelineserver
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/db"
+)
+
+func (s *Server) GetPipelinePermissions(pipelineDB db.PipelineDB) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(pipelineDB.Pipeline().Permissions)
+	})
+}
+
+// SetPipelinePermissions replaces the pipeline's permissions, so that
+// permissions left out of the request are unset.
+func (s *Server) SetPipelinePermissions(pipelineDB db.PipelineDB) http.Handler {
+	logger := s.logger.Session("set-pipeline-permissions")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var permissions atc.PipelinePermissions
+		err := json.NewDecoder(r.Body).Decode(&permissions)
+		if err != nil {
+			logger.Info("malformed-request", lager.Data{"error": err.Error()})
+			http.Error(w, "malformed request", http.StatusBadRequest)
+			return
+		}
+
+		err = permissions.Validate()
+		if err != nil {
+			logger.Info("invalid-permissions", lager.Data{"error": err.Error()})
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		err = pipelineDB.SetPermissions(permissions)
+		if err != nil {
+			logger.Error("failed-to-set-permissions", err)
+			apierror.DBFailure(w, "failed to set pipeline permissions")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(permissions)
+	})
+}
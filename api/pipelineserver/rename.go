@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"net/http"
 
+	"github.com/concourse/atc/api/apierror"
 	"github.com/concourse/atc/db"
 )
 
@@ -13,7 +14,7 @@ func (s *Server) RenamePipeline(pipelineDB db.PipelineDB) http.Handler {
 		data, err := ioutil.ReadAll(r.Body)
 		if err != nil {
 			s.logger.Error("call-to-update-pipeline-name-copy-failed", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.Internal(w, "failed to read request body")
 			return
 		}
 
@@ -21,14 +22,14 @@ func (s *Server) RenamePipeline(pipelineDB db.PipelineDB) http.Handler {
 		err = json.Unmarshal(data, &value)
 		if err != nil {
 			s.logger.Error("call-to-update-pipeline-name-unmarshal-failed", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.Internal(w, "failed to unmarshal request body")
 			return
 		}
 
 		err = pipelineDB.UpdateName(value.Name)
 		if err != nil {
 			s.logger.Error("call-to-update-pipeline-name-failed", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.DBFailure(w, "failed to rename pipeline")
 			return
 		}
 
@@ -3,6 +3,7 @@ package pipelineserver
 import (
 	"net/http"
 
+	"github.com/concourse/atc/api/apierror"
 	"github.com/concourse/atc/auth"
 	"github.com/concourse/atc/db"
 )
@@ -32,12 +33,12 @@ func (pdbh *ScopedHandlerFactory) HandlerFor(pipelineScopedHandler func(db.Pipel
 			teamDB := pdbh.teamDBFactory.GetTeamDB(requestTeamName)
 			savedPipeline, found, err := teamDB.GetPipelineByName(pipelineName)
 			if err != nil {
-				w.WriteHeader(http.StatusInternalServerError)
+				apierror.DBFailure(w, "failed to get pipeline")
 				return
 			}
 
 			if !found {
-				w.WriteHeader(http.StatusNotFound)
+				apierror.NotFound(w, "pipeline not found")
 				return
 			}
 
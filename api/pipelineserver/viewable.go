@@ -0,0 +1,43 @@
+package pipelineserver
+
+import (
+	"net/http"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/auth"
+	"github.com/concourse/atc/db"
+)
+
+// viewablePipelines leaves out the team's private pipelines that the
+// request's role isn't enough to view. Public pipelines can be viewed by
+// anyone, whatever their permissions.
+func viewablePipelines(r *http.Request, pipelines []db.SavedPipeline) []db.SavedPipeline {
+	viewable := []db.SavedPipeline{}
+	for _, pipeline := range pipelines {
+		if canView(r, pipeline) {
+			viewable = append(viewable, pipeline)
+		}
+	}
+
+	return viewable
+}
+
+func viewableDashboards(r *http.Request, dashboards []db.PipelineDashboard) []db.PipelineDashboard {
+	viewable := []db.PipelineDashboard{}
+	for _, dashboard := range dashboards {
+		if canView(r, dashboard.Pipeline) {
+			viewable = append(viewable, dashboard)
+		}
+	}
+
+	return viewable
+}
+
+func canView(r *http.Request, pipeline db.SavedPipeline) bool {
+	if pipeline.Public {
+		return true
+	}
+
+	_, allowed := auth.HasPipelinePermission(r, pipeline.Permissions, atc.PipelinePermissionView)
+	return allowed
+}
@@ -0,0 +1,71 @@
+package pipelineserver
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/db"
+)
+
+func (s *Server) ListPipelineWeights(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.Session("list-pipeline-weights")
+
+	weights, err := s.pipelinesDB.GetPipelineWeights(r.Context())
+	if err != nil {
+		logger.Error("failed-to-get-pipeline-weights", err)
+		apierror.DBFailure(w, "failed to get pipeline weights")
+		return
+	}
+
+	presented := make([]atc.PipelineWeight, len(weights))
+	for i, weight := range weights {
+		presented[i] = atc.PipelineWeight{
+			TeamName:     weight.TeamName,
+			PipelineName: weight.PipelineName,
+			Weight:       weight.Weight,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	json.NewEncoder(w).Encode(presented)
+}
+
+func (s *Server) SetPipelineWeight(pipelineDB db.PipelineDB) http.Handler {
+	logger := s.logger.Session("set-pipeline-weight")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var weight atc.PipelineWeight
+		err := json.NewDecoder(r.Body).Decode(&weight)
+		if err != nil {
+			logger.Info("malformed-request", lager.Data{"error": err.Error()})
+			http.Error(w, "malformed request", http.StatusBadRequest)
+			return
+		}
+
+		if weight.Weight < 1 {
+			logger.Info("invalid-weight", lager.Data{"weight": weight.Weight})
+			http.Error(w, "weight must be at least 1", http.StatusBadRequest)
+			return
+		}
+
+		err = s.pipelinesDB.SetPipelineWeight(r.Context(), pipelineDB.GetPipelineID(), weight.Weight)
+		if err != nil {
+			logger.Error("failed-to-set-pipeline-weight", err)
+			apierror.DBFailure(w, "failed to set pipeline weight")
+			return
+		}
+
+		logger.Info("set", lager.Data{"pipeline": pipelineDB.GetPipelineName(), "weight": weight.Weight})
+
+		w.Header().Set("Content-Type", "application/json")
+
+		json.NewEncoder(w).Encode(atc.PipelineWeight{
+			TeamName:     pipelineDB.Pipeline().TeamName,
+			PipelineName: pipelineDB.GetPipelineName(),
+			Weight:       weight.Weight,
+		})
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/tedsuo/rata"
 
 	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
 	"github.com/concourse/atc/auth"
 )
 
@@ -16,20 +17,20 @@ func (s *Server) CreatePipe(w http.ResponseWriter, r *http.Request) {
 	logger := s.logger.Session("create-pipe")
 	guid, err := uuid.NewV4()
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		apierror.Internal(w, "failed to generate pipe id")
 		return
 	}
 
 	authTeam, found := auth.GetTeam(r)
 	if !found {
-		w.WriteHeader(http.StatusInternalServerError)
+		apierror.Internal(w, "failed to get team from auth")
 		return
 	}
 
 	err = s.db.CreatePipe(guid.String(), s.url, authTeam.ID())
 	if err != nil {
 		logger.Error("failed-to-create-pipe", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		apierror.DBFailure(w, "failed to create pipe")
 		return
 	}
 
@@ -44,7 +45,7 @@ func (s *Server) CreatePipe(w http.ResponseWriter, r *http.Request) {
 	}, nil)
 	if err != nil {
 		logger.Error("failed-to-create-pipe", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		apierror.DBFailure(w, "failed to create pipe")
 		return
 	}
 
@@ -53,7 +54,7 @@ func (s *Server) CreatePipe(w http.ResponseWriter, r *http.Request) {
 	}, nil)
 	if err != nil {
 		logger.Error("failed-to-create-pipe", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		apierror.DBFailure(w, "failed to create pipe")
 		return
 	}
 
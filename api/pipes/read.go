@@ -7,6 +7,7 @@ import (
 
 	"code.cloudfoundry.org/lager"
 	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
 	"github.com/concourse/atc/auth"
 )
 
@@ -17,14 +18,14 @@ func (s *Server) ReadPipe(w http.ResponseWriter, r *http.Request) {
 	authTeam, found := auth.GetTeam(r)
 	if !found {
 		logger.Error("failed-to-get-team", errors.New("failed-to-get-team"))
-		w.WriteHeader(http.StatusInternalServerError)
+		apierror.DBFailure(w, "failed to get team")
 		return
 	}
 
 	dbPipe, err := s.db.GetPipe(pipeID)
 	if err != nil {
 		logger.Error("failed-to-get-pipe", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		apierror.DBFailure(w, "failed to get pipe")
 		return
 	}
 
@@ -32,7 +33,7 @@ func (s *Server) ReadPipe(w http.ResponseWriter, r *http.Request) {
 		logger.Error("team-not-authorized-to-read-pipe",
 			errors.New("team-not-authorized-to-read-pipe"),
 			lager.Data{"TeamID": authTeam.ID(), "PipeID": dbPipe.ID})
-		w.WriteHeader(http.StatusForbidden)
+		apierror.Forbidden(w, "pipe belongs to another team")
 		return
 	}
 
@@ -44,7 +45,7 @@ func (s *Server) ReadPipe(w http.ResponseWriter, r *http.Request) {
 		s.pipesL.RUnlock()
 
 		if !found {
-			w.WriteHeader(http.StatusNotFound)
+			apierror.NotFound(w, "pipe not found")
 			return
 		}
 
@@ -77,7 +78,7 @@ func (s *Server) ReadPipe(w http.ResponseWriter, r *http.Request) {
 		response, err := s.forwardRequest(w, r, dbPipe.URL, atc.ReadPipe, dbPipe.ID)
 		if err != nil {
 			logger.Error("failed-to-forward-request", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.Internal(w, "failed to forward request")
 			return
 		}
 
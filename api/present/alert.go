@@ -0,0 +1,21 @@
+package present
+
+import (
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+)
+
+func DurationAlert(alert db.DurationAlert) atc.Alert {
+	return atc.Alert{
+		Type:         atc.AlertTypeDuration,
+		BuildID:      alert.BuildID,
+		BuildName:    alert.BuildName,
+		TeamName:     alert.TeamName,
+		PipelineName: alert.PipelineName,
+		JobName:      alert.JobName,
+		Percentile:   alert.Percentile,
+		Threshold:    alert.Threshold.Seconds(),
+		Elapsed:      alert.Elapsed.Seconds(),
+		CreatedAt:    alert.CreatedAt.Unix(),
+	}
+}
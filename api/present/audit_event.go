@@ -0,0 +1,18 @@
+package present
+
+import (
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+)
+
+func AuditEvent(event db.AuditEvent) atc.AuditEvent {
+	return atc.AuditEvent{
+		ID:         event.ID,
+		Time:       event.Time.Unix(),
+		Actor:      event.Actor,
+		Action:     event.Action,
+		Resource:   event.Resource,
+		BodySHA256: event.BodySHA256,
+		Status:     event.Status,
+	}
+}
@@ -52,6 +52,11 @@ func Build(build db.Build) atc.Build {
 		TeamName:     build.TeamName(),
 		URL:          reqURL,
 		APIURL:       apiURL,
+		Labels:       build.Labels(),
+		Params:       build.Params(),
+		LogTruncated: build.LogTruncated(),
+		Priority:     build.Priority(),
+		RerunOf:      build.RerunOf(),
 	}
 
 	if !build.StartTime().IsZero() {
@@ -66,5 +71,10 @@ func Build(build db.Build) atc.Build {
 		atcBuild.ReapTime = build.ReapTime().Unix()
 	}
 
+	if usage := build.ResourceUsage(); usage != (db.ResourceUsage{}) {
+		buildUsage := BuildUsage(usage)
+		atcBuild.Usage = &buildUsage
+	}
+
 	return atcBuild
 }
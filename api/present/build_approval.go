@@ -0,0 +1,21 @@
+package present
+
+import (
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+)
+
+func BuildApproval(approval db.BuildApproval) atc.BuildApproval {
+	presented := atc.BuildApproval{
+		Name:        approval.Name,
+		Status:      string(approval.Status),
+		Approver:    approval.Approver,
+		RequestedAt: approval.RequestedAt.Unix(),
+	}
+
+	if !approval.DecidedAt.IsZero() {
+		presented.DecidedAt = approval.DecidedAt.Unix()
+	}
+
+	return presented
+}
@@ -0,0 +1,14 @@
+package present
+
+import (
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+)
+
+func BuildArtifact(artifact db.BuildArtifact) atc.BuildArtifact {
+	return atc.BuildArtifact{
+		Name:            artifact.Name,
+		ContainerHandle: artifact.ContainerHandle,
+		Path:            artifact.Path,
+	}
+}
@@ -0,0 +1,15 @@
+package present
+
+import (
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+)
+
+func BuildComment(comment db.BuildComment) atc.BuildComment {
+	return atc.BuildComment{
+		ID:        comment.ID,
+		Author:    comment.Author,
+		Text:      comment.Text,
+		CreatedAt: comment.CreatedAt.Unix(),
+	}
+}
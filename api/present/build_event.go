@@ -0,0 +1,22 @@
+package present
+
+import (
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/event"
+)
+
+func BuildEvent(id uint, ev event.Envelope) atc.BuildEvent {
+	presented := atc.BuildEvent{
+		ID:      id,
+		Event:   ev.Event,
+		Version: ev.Version,
+		Data:    ev.Data,
+	}
+
+	if !ev.Time.IsZero() {
+		at := ev.Time.UTC()
+		presented.Time = &at
+	}
+
+	return presented
+}
@@ -0,0 +1,23 @@
+package present
+
+import (
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+)
+
+func BuildEventVerification(verification db.EventChainVerification) atc.BuildEventVerification {
+	presented := atc.BuildEventVerification{
+		Valid:          verification.Valid,
+		SignedEvents:   verification.SignedEvents,
+		UnsignedEvents: verification.UnsignedEvents,
+		Anchors:        verification.Anchors,
+	}
+
+	if !verification.Valid {
+		brokenAt := uint(verification.BrokenAt)
+		presented.BrokenAt = &brokenAt
+		presented.Reason = verification.Reason
+	}
+
+	return presented
+}
@@ -0,0 +1,16 @@
+package present
+
+import (
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+)
+
+func BuildLogMatch(match db.LogMatch) atc.BuildLogMatch {
+	return atc.BuildLogMatch{
+		BuildID:  match.BuildID,
+		EventID:  match.EventID,
+		OriginID: string(match.Origin.ID),
+		Source:   string(match.Origin.Source),
+		Payload:  match.Payload,
+	}
+}
@@ -0,0 +1,22 @@
+package present
+
+import (
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+)
+
+func BuildStep(step db.BuildStep) atc.BuildStep {
+	presented := atc.BuildStep{
+		ID:        step.PlanID,
+		Type:      step.Type,
+		Status:    string(step.Status),
+		StartTime: step.StartedAt.Unix(),
+	}
+
+	if !step.FinishedAt.IsZero() {
+		presented.EndTime = step.FinishedAt.Unix()
+		presented.Duration = int64(step.FinishedAt.Sub(step.StartedAt).Seconds())
+	}
+
+	return presented
+}
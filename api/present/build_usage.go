@@ -0,0 +1,14 @@
+package present
+
+import (
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+)
+
+func BuildUsage(usage db.ResourceUsage) atc.BuildUsage {
+	return atc.BuildUsage{
+		CPUNanoseconds: usage.CPUNanoseconds,
+		MemoryBytes:    usage.MemoryBytes,
+		DiskBytes:      usage.DiskBytes,
+	}
+}
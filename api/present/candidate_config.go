@@ -0,0 +1,34 @@
+package present
+
+import (
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+)
+
+func CandidateConfig(candidate db.CandidateConfig) atc.CandidateConfig {
+	return atc.CandidateConfig{
+		Percentage: candidate.Percentage,
+		Author:     candidate.Author,
+		CreatedAt:  candidate.CreatedAt.Unix(),
+		Config:     candidate.Config,
+	}
+}
+
+func CandidateComparison(comparison db.CandidateComparison) atc.CandidateComparison {
+	return atc.CandidateComparison{
+		JobName:   comparison.JobName,
+		Current:   candidateBuildStats(comparison.Current),
+		Candidate: candidateBuildStats(comparison.Candidate),
+	}
+}
+
+func candidateBuildStats(stats db.CandidateBuildStats) atc.CandidateBuildStats {
+	return atc.CandidateBuildStats{
+		Builds:          stats.Builds,
+		Succeeded:       stats.Succeeded,
+		Failed:          stats.Failed,
+		Errored:         stats.Errored,
+		Aborted:         stats.Aborted,
+		AverageDuration: int64(stats.AverageDuration.Seconds()),
+	}
+}
@@ -0,0 +1,34 @@
+package present
+
+import (
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+)
+
+func Causality(causality db.Causality) atc.Causality {
+	presented := atc.Causality{
+		Versions:  []atc.VersionedResource{},
+		Builds:    []atc.Build{},
+		Edges:     []atc.CausalityEdge{},
+		Truncated: causality.Truncated,
+	}
+
+	for _, version := range causality.Versions {
+		presented.Versions = append(presented.Versions, SavedVersionedResource(version))
+	}
+
+	for _, build := range causality.Builds {
+		presented.Builds = append(presented.Builds, Build(build))
+	}
+
+	for _, edge := range causality.Edges {
+		presented.Edges = append(presented.Edges, atc.CausalityEdge{
+			Type:          string(edge.Type),
+			VersionID:     edge.VersionID,
+			BuildID:       edge.BuildID,
+			SameVersionID: edge.SameVersionID,
+		})
+	}
+
+	return presented
+}
@@ -0,0 +1,15 @@
+package present
+
+import (
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+)
+
+func ConfigRevision(revision db.ConfigRevision) atc.ConfigRevision {
+	return atc.ConfigRevision{
+		Version:   int(revision.Version),
+		Author:    revision.Author,
+		CreatedAt: revision.CreatedAt.Unix(),
+		Config:    revision.Config,
+	}
+}
@@ -0,0 +1,46 @@
+package present
+
+import (
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+)
+
+func Dashboard(dashboards []db.PipelineDashboard) []atc.DashboardPipeline {
+	presented := make([]atc.DashboardPipeline, len(dashboards))
+
+	for i, dashboard := range dashboards {
+		jobs := make([]atc.DashboardJob, len(dashboard.Jobs))
+
+		for j, job := range dashboard.Jobs {
+			runningBuilds := make([]atc.Build, len(job.RunningBuilds))
+			for k, build := range job.RunningBuilds {
+				runningBuilds[k] = Build(build)
+			}
+
+			// the running builds are newest first, so the next is the oldest
+			var nextBuild db.Build
+			if len(job.RunningBuilds) > 0 {
+				nextBuild = job.RunningBuilds[len(job.RunningBuilds)-1]
+			}
+
+			jobs[j] = atc.DashboardJob{
+				Job: Job(
+					dashboard.Pipeline.TeamName,
+					job.Job,
+					job.JobConfig,
+					dashboard.Pipeline.Config.Groups,
+					job.FinishedBuild,
+					nextBuild,
+				),
+				RunningBuilds: runningBuilds,
+			}
+		}
+
+		presented[i] = atc.DashboardPipeline{
+			Pipeline: Pipeline(dashboard.Pipeline),
+			Jobs:     jobs,
+		}
+	}
+
+	return presented
+}
@@ -0,0 +1,21 @@
+package present
+
+import (
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+)
+
+func ExpiringBuild(build db.ArtifactBuild) atc.ExpiringBuild {
+	var endTime int64
+	if !build.EndTime.IsZero() {
+		endTime = build.EndTime.Unix()
+	}
+
+	return atc.ExpiringBuild{
+		ID:        build.ID,
+		Name:      build.Name,
+		Status:    string(build.Status),
+		EndTime:   endTime,
+		Artifacts: build.Artifacts,
+	}
+}
@@ -0,0 +1,37 @@
+package present
+
+import (
+	"time"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+)
+
+func GCStatus(lastPass db.GCPass, found bool, workerStats []db.WorkerGCStats) atc.GCStatus {
+	status := atc.GCStatus{
+		Workers: make([]atc.WorkerGCStats, len(workerStats)),
+	}
+
+	if found {
+		status.LastPassStartedAt = lastPass.StartedAt.Unix()
+		status.LastPassDurationMS = int64(lastPass.Duration() / time.Millisecond)
+	}
+
+	for i, stats := range workerStats {
+		status.Workers[i] = atc.WorkerGCStats{
+			WorkerName: stats.WorkerName,
+			Containers: atc.GCCounts{
+				Created:   stats.ContainersCreated,
+				Destroyed: stats.ContainersDestroyed,
+				Orphaned:  stats.ContainersOrphaned,
+			},
+			Volumes: atc.GCCounts{
+				Created:   stats.VolumesCreated,
+				Destroyed: stats.VolumesDestroyed,
+				Orphaned:  stats.VolumesOrphaned,
+			},
+		}
+	}
+
+	return status
+}
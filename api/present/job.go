@@ -75,9 +75,12 @@ func Job(
 		URL:                  req.URL.String(),
 		DisableManualTrigger: job.DisableManualTrigger,
 		Paused:               dbJob.Paused,
+		ManualOnly:           dbJob.ManualOnly,
 		FirstLoggedBuildID:   dbJob.FirstLoggedBuildID,
 		FinishedBuild:        presentedFinishedBuild,
 		NextBuild:            presentedNextBuild,
+		Flakiness:            dbJob.Flakiness,
+		Flaky:                dbJob.Flaky,
 
 		Inputs:  sanitizedInputs,
 		Outputs: sanitizedOutputs,
@@ -0,0 +1,14 @@
+package present
+
+import (
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+)
+
+func JobCache(cache db.TaskCache) atc.JobCache {
+	return atc.JobCache{
+		Path:      cache.Path,
+		SizeBytes: cache.SizeBytes,
+		SavedAt:   cache.SavedAt.Unix(),
+	}
+}
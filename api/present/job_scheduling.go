@@ -0,0 +1,19 @@
+package present
+
+import (
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+)
+
+func JobScheduling(scheduling db.JobScheduling) atc.JobScheduling {
+	missingInputReasons := atc.MissingInputReasons{}
+	for name, reason := range scheduling.MissingInputReasons {
+		missingInputReasons[name] = reason
+	}
+
+	return atc.JobScheduling{
+		Reason:              atc.SchedulingReason(scheduling.Reason),
+		MissingInputReasons: missingInputReasons,
+		CheckedAt:           scheduling.CheckedAt.Unix(),
+	}
+}
@@ -0,0 +1,47 @@
+package present
+
+import (
+	"time"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+)
+
+func Maintenance(maintenance db.Maintenance, now time.Time) atc.Maintenance {
+	presented := atc.Maintenance{
+		Windows: make([]atc.MaintenanceWindow, len(maintenance.Windows)),
+	}
+
+	for i, window := range maintenance.Windows {
+		presented.Windows[i] = MaintenanceWindow(window)
+	}
+
+	if window, end, found := maintenance.ActiveWindowAt(now); found {
+		presented.Active = &atc.ActiveMaintenanceWindow{
+			Name:    window.Name,
+			EndTime: end.Unix(),
+		}
+	}
+
+	if now.Before(maintenance.OverriddenUntil) {
+		presented.OverriddenUntil = maintenance.OverriddenUntil.Unix()
+	}
+
+	return presented
+}
+
+func MaintenanceWindow(window db.MaintenanceWindow) atc.MaintenanceWindow {
+	if window.Schedule != "" {
+		return atc.MaintenanceWindow{
+			Name:     window.Name,
+			Schedule: window.Schedule,
+			Duration: window.Duration.String(),
+		}
+	}
+
+	return atc.MaintenanceWindow{
+		Name:      window.Name,
+		StartTime: window.StartsAt.Unix(),
+		EndTime:   window.EndsAt.Unix(),
+	}
+}
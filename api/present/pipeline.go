@@ -17,6 +17,11 @@ func Pipeline(savedPipeline db.SavedPipeline) atc.Pipeline {
 		panic("failed to generate url: " + err.Error())
 	}
 
+	var permissions *atc.PipelinePermissions
+	if !savedPipeline.Permissions.IsZero() {
+		permissions = &savedPipeline.Permissions
+	}
+
 	return atc.Pipeline{
 		Name:     savedPipeline.Name,
 		TeamName: savedPipeline.TeamName,
@@ -24,5 +29,9 @@ func Pipeline(savedPipeline db.SavedPipeline) atc.Pipeline {
 		Paused:   savedPipeline.Paused,
 		Public:   savedPipeline.Public,
 		Groups:   savedPipeline.Config.Groups,
+
+		InstanceVars: savedPipeline.InstanceVars,
+
+		Permissions: permissions,
 	}
 }
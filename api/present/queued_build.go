@@ -0,0 +1,29 @@
+package present
+
+import (
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+)
+
+func QueuedBuild(queued db.QueuedBuild) atc.QueuedBuild {
+	presented := atc.QueuedBuild{
+		BuildID:      queued.BuildID,
+		BuildName:    queued.BuildName,
+		TeamName:     queued.TeamName,
+		PipelineName: queued.PipelineName,
+		JobName:      queued.JobName,
+		Priority:     queued.Priority,
+		Reason:       atc.SchedulingReason(queued.Reason),
+		Position:     queued.Position,
+	}
+
+	if !queued.CreateTime.IsZero() {
+		presented.EnqueuedAt = queued.CreateTime.Unix()
+	}
+
+	if !queued.CheckedAt.IsZero() {
+		presented.CheckedAt = queued.CheckedAt.Unix()
+	}
+
+	return presented
+}
@@ -33,8 +33,13 @@ func Resource(resource atc.ResourceConfig, groups atc.GroupConfigs, dbResource d
 	}
 
 	var checkErrString string
+	var checkErroredAt int64
 	if dbResource.CheckError != nil && showCheckError {
 		checkErrString = dbResource.CheckError.Error()
+
+		if !dbResource.CheckErroredAt.IsZero() {
+			checkErroredAt = dbResource.CheckErroredAt.Unix()
+		}
 	}
 
 	return atc.Resource{
@@ -45,7 +50,10 @@ func Resource(resource atc.ResourceConfig, groups atc.GroupConfigs, dbResource d
 
 		Paused: dbResource.Paused,
 
+		PinnedVersionID: dbResource.PinnedVersionID,
+
 		FailingToCheck: dbResource.FailingToCheck(),
 		CheckError:     checkErrString,
+		CheckErroredAt: checkErroredAt,
 	}
 }
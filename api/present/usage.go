@@ -0,0 +1,19 @@
+package present
+
+import (
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+)
+
+func Usage(usage db.Usage) atc.Usage {
+	return atc.Usage{
+		TeamName:          usage.TeamName,
+		PipelineName:      usage.PipelineName,
+		Start:             usage.Start.Unix(),
+		Builds:            usage.Builds,
+		BuildSeconds:      int64(usage.BuildTime.Seconds()),
+		WorkerSeconds:     int64(usage.WorkerTime.Seconds()),
+		CPUSeconds:        int64(usage.CPUTime.Seconds()),
+		MemoryByteSeconds: int64(usage.MemoryByteSeconds),
+	}
+}
@@ -0,0 +1,154 @@
+package api_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/atc/db"
+)
+
+var _ = Describe("Queue API", func() {
+	Describe("GET /api/v1/queue", func() {
+		var (
+			query    string
+			response *http.Response
+		)
+
+		BeforeEach(func() {
+			query = ""
+
+			queueDB.GetBuildQueueReturns([]db.QueuedBuild{
+				{
+					BuildID:      42,
+					BuildName:    "7",
+					TeamName:     "some-team",
+					PipelineName: "some-pipeline",
+					JobName:      "some-job",
+					CreateTime:   time.Unix(100, 0),
+					Reason:       db.SchedulingReasonSerialGroupBusy,
+					CheckedAt:    time.Unix(160, 0),
+					Position:     1,
+				},
+				{
+					BuildID:      43,
+					BuildName:    "8",
+					TeamName:     "some-team",
+					PipelineName: "some-pipeline",
+					JobName:      "some-job",
+					Priority:     -1,
+					Position:     2,
+				},
+			}, nil)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+
+			response, err = client.Get(server.URL + "/api/v1/queue" + query)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when not authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns 401", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+
+		Context("when authenticated but not admin", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+			})
+
+			It("returns 403 without getting the queue", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+				Expect(queueDB.GetBuildQueueCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when authenticated as an admin", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("main", 1, true, true)
+			})
+
+			It("lists the pending builds with what they're waiting on", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+				Expect(response.Header.Get("Content-Type")).To(Equal("application/json"))
+
+				body, err := ioutil.ReadAll(response.Body)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(body).To(MatchJSON(`[
+					{
+						"build_id": 42,
+						"build_name": "7",
+						"team_name": "some-team",
+						"pipeline_name": "some-pipeline",
+						"job_name": "some-job",
+						"enqueued_at": 100,
+						"reason": "serial-group-busy",
+						"checked_at": 160,
+						"position": 1
+					},
+					{
+						"build_id": 43,
+						"build_name": "8",
+						"team_name": "some-team",
+						"pipeline_name": "some-pipeline",
+						"job_name": "some-job",
+						"priority": -1,
+						"position": 2
+					}
+				]`))
+
+				Expect(queueDB.GetBuildQueueCallCount()).To(Equal(1))
+				_, teamName := queueDB.GetBuildQueueArgsForCall(0)
+				Expect(teamName).To(BeEmpty())
+			})
+
+			Context("when narrowed down to a team", func() {
+				BeforeEach(func() {
+					query = "?team=some-team"
+				})
+
+				It("gets just that team's queue", func() {
+					Expect(queueDB.GetBuildQueueCallCount()).To(Equal(1))
+					_, teamName := queueDB.GetBuildQueueArgsForCall(0)
+					Expect(teamName).To(Equal("some-team"))
+				})
+			})
+
+			Context("when the queue is empty", func() {
+				BeforeEach(func() {
+					queueDB.GetBuildQueueReturns([]db.QueuedBuild{}, nil)
+				})
+
+				It("returns an empty list", func() {
+					body, err := ioutil.ReadAll(response.Body)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(body).To(MatchJSON(`[]`))
+				})
+			})
+
+			Context("when getting the queue fails", func() {
+				BeforeEach(func() {
+					queueDB.GetBuildQueueReturns(nil, errors.New("nope"))
+				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+	})
+})
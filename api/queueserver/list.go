@@ -0,0 +1,34 @@
+package queueserver
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/api/present"
+)
+
+// ListQueue lists every job's pending builds, or those of one ?team=, with
+// why the scheduler hasn't started them.
+func (s *Server) ListQueue(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.Session("list-queue")
+
+	queue, err := s.db.GetBuildQueue(r.Context(), r.FormValue("team"))
+	if err != nil {
+		logger.Error("failed-to-get-build-queue", err)
+		apierror.DBFailure(w, "failed to get build queue")
+		return
+	}
+
+	presented := make([]atc.QueuedBuild, len(queue))
+	for i, queued := range queue {
+		presented[i] = present.QueuedBuild(queued)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(presented)
+}
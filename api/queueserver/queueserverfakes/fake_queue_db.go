@@ -0,0 +1,82 @@
+// This file was generated by counterfeiter
+package queueserverfakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/concourse/atc/api/queueserver"
+	"github.com/concourse/atc/db"
+)
+
+type FakeQueueDB struct {
+	GetBuildQueueStub        func(ctx context.Context, teamName string) ([]db.QueuedBuild, error)
+	getBuildQueueMutex       sync.RWMutex
+	getBuildQueueArgsForCall []struct {
+		ctx      context.Context
+		teamName string
+	}
+	getBuildQueueReturns struct {
+		result1 []db.QueuedBuild
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeQueueDB) GetBuildQueue(ctx context.Context, teamName string) ([]db.QueuedBuild, error) {
+	fake.getBuildQueueMutex.Lock()
+	fake.getBuildQueueArgsForCall = append(fake.getBuildQueueArgsForCall, struct {
+		ctx      context.Context
+		teamName string
+	}{ctx, teamName})
+	fake.recordInvocation("GetBuildQueue", []interface{}{ctx, teamName})
+	fake.getBuildQueueMutex.Unlock()
+	if fake.GetBuildQueueStub != nil {
+		return fake.GetBuildQueueStub(ctx, teamName)
+	} else {
+		return fake.getBuildQueueReturns.result1, fake.getBuildQueueReturns.result2
+	}
+}
+
+func (fake *FakeQueueDB) GetBuildQueueCallCount() int {
+	fake.getBuildQueueMutex.RLock()
+	defer fake.getBuildQueueMutex.RUnlock()
+	return len(fake.getBuildQueueArgsForCall)
+}
+
+func (fake *FakeQueueDB) GetBuildQueueArgsForCall(i int) (context.Context, string) {
+	fake.getBuildQueueMutex.RLock()
+	defer fake.getBuildQueueMutex.RUnlock()
+	return fake.getBuildQueueArgsForCall[i].ctx, fake.getBuildQueueArgsForCall[i].teamName
+}
+
+func (fake *FakeQueueDB) GetBuildQueueReturns(result1 []db.QueuedBuild, result2 error) {
+	fake.GetBuildQueueStub = nil
+	fake.getBuildQueueReturns = struct {
+		result1 []db.QueuedBuild
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeQueueDB) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.getBuildQueueMutex.RLock()
+	defer fake.getBuildQueueMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeQueueDB) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ queueserver.QueueDB = new(FakeQueueDB)
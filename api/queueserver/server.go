@@ -0,0 +1,30 @@
+package queueserver
+
+import (
+	"context"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/db"
+)
+
+//go:generate counterfeiter . QueueDB
+
+type QueueDB interface {
+	GetBuildQueue(ctx context.Context, teamName string) ([]db.QueuedBuild, error)
+}
+
+type Server struct {
+	logger lager.Logger
+
+	db QueueDB
+}
+
+func NewServer(
+	logger lager.Logger,
+	db QueueDB,
+) *Server {
+	return &Server{
+		logger: logger,
+		db:     db,
+	}
+}
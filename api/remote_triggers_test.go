@@ -0,0 +1,196 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/db/dbfakes"
+	"github.com/concourse/atc/scheduler/schedulerfakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Remote Triggers API", func() {
+	var pipelineDB *dbfakes.FakePipelineDB
+
+	BeforeEach(func() {
+		pipelineDB = new(dbfakes.FakePipelineDB)
+		pipelineDBFactory.BuildReturns(pipelineDB)
+		teamDB.GetPipelineByNameReturns(db.SavedPipeline{}, true, nil)
+	})
+
+	Describe("POST /api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/remote-triggers", func() {
+		var (
+			body          string
+			response      *http.Response
+			fakeScheduler *schedulerfakes.FakeBuildScheduler
+		)
+
+		BeforeEach(func() {
+			body = `{
+				"source": {
+					"team_name": "other-team",
+					"pipeline_name": "other-pipeline",
+					"job_name": "other-job",
+					"build_name": "3"
+				},
+				"versions": {
+					"some-resource": {"ref": "abc"}
+				}
+			}`
+
+			fakeScheduler = new(schedulerfakes.FakeBuildScheduler)
+			fakeSchedulerFactory.BuildSchedulerReturns(fakeScheduler)
+
+			pipelineDB.GetConfigReturns(atc.Config{
+				Jobs: atc.JobConfigs{
+					{Name: "some-job", Plan: atc.PlanSequence{{Get: "some-resource"}}},
+				},
+				Resources: atc.ResourceConfigs{
+					{Name: "some-resource", Type: "git"},
+				},
+			}, 1, true, nil)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			response, err = client.Post(
+				server.URL+"/api/v1/teams/some-team/pipelines/some-pipeline/jobs/some-job/remote-triggers",
+				"application/json",
+				bytes.NewBufferString(body),
+			)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when not authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns 401", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+
+			It("does not trigger the job", func() {
+				Expect(fakeScheduler.TriggerImmediatelyCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("some-team", 42, false, true)
+			})
+
+			Context("when triggering the build succeeds", func() {
+				BeforeEach(func() {
+					build := new(dbfakes.FakeBuild)
+					build.IDReturns(42)
+					build.NameReturns("1")
+					build.JobNameReturns("some-job")
+					build.PipelineNameReturns("some-pipeline")
+					build.TeamNameReturns("some-team")
+					build.StatusReturns(db.StatusPending)
+					fakeScheduler.TriggerImmediatelyReturns(build, nil, nil)
+				})
+
+				It("saves the versions it was given", func() {
+					Expect(pipelineDB.SaveResourceVersionsCallCount()).To(Equal(1))
+
+					resourceConfig, versions := pipelineDB.SaveResourceVersionsArgsForCall(0)
+					Expect(resourceConfig).To(Equal(atc.ResourceConfig{Name: "some-resource", Type: "git"}))
+					Expect(versions).To(Equal([]atc.Version{{"ref": "abc"}}))
+				})
+
+				It("triggers the job", func() {
+					Expect(fakeScheduler.TriggerImmediatelyCallCount()).To(Equal(1))
+
+					_, job, _, _, _ := fakeScheduler.TriggerImmediatelyArgsForCall(0)
+					Expect(job.Name).To(Equal("some-job"))
+				})
+
+				It("returns 201 with the build", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusCreated))
+
+					var returned atc.Build
+					err := json.NewDecoder(response.Body).Decode(&returned)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(returned.ID).To(Equal(42))
+				})
+			})
+
+			Context("when a version is for a resource the pipeline doesn't have", func() {
+				BeforeEach(func() {
+					body = `{"source":{},"versions":{"bogus":{"ref":"abc"}}}`
+				})
+
+				It("returns 422 without triggering the job", func() {
+					Expect(response.StatusCode).To(Equal(422))
+					Expect(pipelineDB.SaveResourceVersionsCallCount()).To(BeZero())
+					Expect(fakeScheduler.TriggerImmediatelyCallCount()).To(BeZero())
+				})
+			})
+
+			Context("when saving a version fails", func() {
+				BeforeEach(func() {
+					pipelineDB.SaveResourceVersionsReturns(errors.New("nope"))
+				})
+
+				It("returns 500 without triggering the job", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+					Expect(fakeScheduler.TriggerImmediatelyCallCount()).To(BeZero())
+				})
+			})
+
+			Context("when triggering the build fails", func() {
+				BeforeEach(func() {
+					fakeScheduler.TriggerImmediatelyReturns(nil, nil, errors.New("nope"))
+				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+
+			Context("when manual triggering is disabled", func() {
+				BeforeEach(func() {
+					pipelineDB.GetConfigReturns(atc.Config{
+						Jobs: atc.JobConfigs{
+							{Name: "some-job", DisableManualTrigger: true},
+						},
+					}, 1, true, nil)
+				})
+
+				It("returns 409", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusConflict))
+					Expect(fakeScheduler.TriggerImmediatelyCallCount()).To(BeZero())
+				})
+			})
+
+			Context("when the job does not exist", func() {
+				BeforeEach(func() {
+					pipelineDB.GetConfigReturns(atc.Config{}, 1, true, nil)
+				})
+
+				It("returns 404", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+				})
+			})
+
+			Context("when the request body is malformed", func() {
+				BeforeEach(func() {
+					body = `{`
+				})
+
+				It("returns 400", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+				})
+			})
+		})
+	})
+})
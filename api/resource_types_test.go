@@ -0,0 +1,264 @@
+package api_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/atc"
+)
+
+var _ = Describe("Registered resource types API", func() {
+	Describe("GET /api/v1/resource-types", func() {
+		var response *http.Response
+
+		JustBeforeEach(func() {
+			var err error
+
+			response, err = client.Get(server.URL + "/api/v1/resource-types")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when not authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns 401", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+
+		Context("when authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+			})
+
+			Context("when the resource types can be looked up", func() {
+				BeforeEach(func() {
+					resourceTypesDB.GetRegisteredResourceTypesReturns([]atc.RegisteredResourceType{
+						{Name: "some-type", Image: "some/image", Version: "1.2.3"},
+					}, nil)
+				})
+
+				It("returns 200 OK", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+				})
+
+				It("returns the registered resource types", func() {
+					body, err := ioutil.ReadAll(response.Body)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(body).To(MatchJSON(`[
+						{"name": "some-type", "image": "some/image", "version": "1.2.3"}
+					]`))
+				})
+			})
+
+			Context("when looking up the resource types fails", func() {
+				BeforeEach(func() {
+					resourceTypesDB.GetRegisteredResourceTypesReturns(nil, errors.New("nope"))
+				})
+
+				It("returns 500 Internal Server Error", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+	})
+
+	Describe("PUT /api/v1/resource-types/:resource_type_name", func() {
+		var (
+			payload string
+
+			response *http.Response
+		)
+
+		BeforeEach(func() {
+			payload = `{"image": "some/image", "version": "1.2.3"}`
+		})
+
+		JustBeforeEach(func() {
+			req, err := http.NewRequest("PUT", server.URL+"/api/v1/resource-types/some-type", bytes.NewBufferString(payload))
+			Expect(err).NotTo(HaveOccurred())
+
+			response, err = client.Do(req)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when not authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns 401", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+
+			It("does not register the resource type", func() {
+				Expect(resourceTypesDB.RegisterResourceTypeCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when authenticated but not admin", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+			})
+
+			It("returns 403", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+			})
+
+			It("does not register the resource type", func() {
+				Expect(resourceTypesDB.RegisterResourceTypeCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when authenticated as an admin", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("main", 1, true, true)
+			})
+
+			It("returns 200 OK", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+			})
+
+			It("registers the resource type under the name in the URL", func() {
+				Expect(resourceTypesDB.RegisterResourceTypeCallCount()).To(Equal(1))
+				Expect(resourceTypesDB.RegisterResourceTypeArgsForCall(0)).To(Equal(atc.RegisteredResourceType{
+					Name:    "some-type",
+					Image:   "some/image",
+					Version: "1.2.3",
+				}))
+			})
+
+			It("returns the registered resource type", func() {
+				body, err := ioutil.ReadAll(response.Body)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(body).To(MatchJSON(`{"name": "some-type", "image": "some/image", "version": "1.2.3"}`))
+			})
+
+			Context("when the image is missing", func() {
+				BeforeEach(func() {
+					payload = `{"version": "1.2.3"}`
+				})
+
+				It("returns 400 Bad Request", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+				})
+
+				It("does not register the resource type", func() {
+					Expect(resourceTypesDB.RegisterResourceTypeCallCount()).To(BeZero())
+				})
+			})
+
+			Context("when the version is missing", func() {
+				BeforeEach(func() {
+					payload = `{"image": "some/image"}`
+				})
+
+				It("returns 400 Bad Request", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+				})
+
+				It("does not register the resource type", func() {
+					Expect(resourceTypesDB.RegisterResourceTypeCallCount()).To(BeZero())
+				})
+			})
+
+			Context("when the request is malformed", func() {
+				BeforeEach(func() {
+					payload = `{`
+				})
+
+				It("returns 400 Bad Request", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+				})
+			})
+
+			Context("when registering the resource type fails", func() {
+				BeforeEach(func() {
+					resourceTypesDB.RegisterResourceTypeReturns(errors.New("nope"))
+				})
+
+				It("returns 500 Internal Server Error", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+	})
+
+	Describe("DELETE /api/v1/resource-types/:resource_type_name", func() {
+		var response *http.Response
+
+		JustBeforeEach(func() {
+			req, err := http.NewRequest("DELETE", server.URL+"/api/v1/resource-types/some-type", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			response, err = client.Do(req)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when authenticated but not admin", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+			})
+
+			It("returns 403", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+			})
+
+			It("does not unregister the resource type", func() {
+				Expect(resourceTypesDB.UnregisterResourceTypeCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when authenticated as an admin", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("main", 1, true, true)
+			})
+
+			Context("when the resource type is registered", func() {
+				BeforeEach(func() {
+					resourceTypesDB.UnregisterResourceTypeReturns(true, nil)
+				})
+
+				It("returns 204 No Content", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusNoContent))
+				})
+
+				It("unregisters the resource type", func() {
+					Expect(resourceTypesDB.UnregisterResourceTypeCallCount()).To(Equal(1))
+					Expect(resourceTypesDB.UnregisterResourceTypeArgsForCall(0)).To(Equal("some-type"))
+				})
+			})
+
+			Context("when the resource type is not registered", func() {
+				BeforeEach(func() {
+					resourceTypesDB.UnregisterResourceTypeReturns(false, nil)
+				})
+
+				It("returns 404 Not Found", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+				})
+			})
+
+			Context("when unregistering the resource type fails", func() {
+				BeforeEach(func() {
+					resourceTypesDB.UnregisterResourceTypeReturns(false, errors.New("nope"))
+				})
+
+				It("returns 500 Internal Server Error", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+	})
+})
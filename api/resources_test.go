@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -68,11 +69,12 @@ var _ = Describe("Resources API", func() {
 
 				dashboardResource2 := db.DashboardResource{
 					Resource: db.SavedResource{
-						ID:           2,
-						CheckError:   errors.New("sup"),
-						Paused:       false,
-						PipelineName: "a-pipeline",
-						Resource:     db.Resource{Name: "resource-2"},
+						ID:             2,
+						CheckError:     errors.New("sup"),
+						CheckErroredAt: time.Unix(1234, 0),
+						Paused:         false,
+						PipelineName:   "a-pipeline",
+						Resource:       db.Resource{Name: "resource-2"},
 					},
 					ResourceConfig: atc.ResourceConfig{
 						Name: "resource-2",
@@ -183,7 +185,8 @@ var _ = Describe("Resources API", func() {
 							"groups": ["group-2"],
 							"url": "/teams/a-team/pipelines/a-pipeline/resources/resource-2",
 							"failing_to_check": true,
-							"check_error": "sup"
+							"check_error": "sup",
+							"check_errored_at": 1234
 						},
 						{
 							"name": "resource-3",
@@ -220,6 +223,121 @@ var _ = Describe("Resources API", func() {
 		})
 	})
 
+	Describe("GET /api/v1/teams/:team_name/pipelines/:pipeline_name/resource-check-errors", func() {
+		var response *http.Response
+
+		JustBeforeEach(func() {
+			var err error
+
+			response, err = client.Get(server.URL + "/api/v1/teams/a-team/pipelines/a-pipeline/resource-check-errors")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when not authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+				userContextReader.GetTeamReturns("", 0, false, false)
+				fakePipelineDB.IsPublicReturns(true)
+			})
+
+			It("returns 401", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+
+		Context("when authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("a-team", 1, true, true)
+			})
+
+			Context("when some resources are failing to check", func() {
+				BeforeEach(func() {
+					fakePipelineDB.GetResourcesReturns([]db.DashboardResource{
+						{
+							Resource: db.SavedResource{
+								ID:           1,
+								PipelineName: "a-pipeline",
+								Resource:     db.Resource{Name: "resource-1"},
+							},
+							ResourceConfig: atc.ResourceConfig{
+								Name: "resource-1",
+								Type: "type-1",
+							},
+						},
+						{
+							Resource: db.SavedResource{
+								ID:             2,
+								CheckError:     errors.New("bad credentials"),
+								CheckErroredAt: time.Unix(1234, 0),
+								PipelineName:   "a-pipeline",
+								Resource:       db.Resource{Name: "resource-2"},
+							},
+							ResourceConfig: atc.ResourceConfig{
+								Name: "resource-2",
+								Type: "type-2",
+							},
+						},
+					}, atc.GroupConfigs{}, true, nil)
+				})
+
+				It("returns 200 OK", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+				})
+
+				It("returns only the failing resources, with their errors", func() {
+					body, err := ioutil.ReadAll(response.Body)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(body).To(MatchJSON(`[
+						{
+							"name": "resource-2",
+							"type": "type-2",
+							"groups": [],
+							"url": "/teams/a-team/pipelines/a-pipeline/resources/resource-2",
+							"failing_to_check": true,
+							"check_error": "bad credentials",
+							"check_errored_at": 1234
+						}
+					]`))
+				})
+			})
+
+			Context("when no resources are failing to check", func() {
+				BeforeEach(func() {
+					fakePipelineDB.GetResourcesReturns([]db.DashboardResource{}, atc.GroupConfigs{}, true, nil)
+				})
+
+				It("returns an empty list", func() {
+					body, err := ioutil.ReadAll(response.Body)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(body).To(MatchJSON(`[]`))
+				})
+			})
+
+			Context("when the pipeline is no longer configured", func() {
+				BeforeEach(func() {
+					fakePipelineDB.GetResourcesReturns(nil, nil, false, nil)
+				})
+
+				It("returns 404", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+				})
+			})
+
+			Context("when getting the resources fails", func() {
+				BeforeEach(func() {
+					fakePipelineDB.GetResourcesReturns(nil, nil, false, errors.New("oh no!"))
+				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+	})
+
 	Describe("GET /api/v1/teams/:team_name/pipelines/:pipeline_name/resources/:resource_name", func() {
 		var response *http.Response
 		var resourceName string
@@ -393,6 +511,33 @@ var _ = Describe("Resources API", func() {
 							Expect(response.StatusCode).To(Equal(http.StatusOK))
 						})
 
+						Context("when the resource is pinned", func() {
+							BeforeEach(func() {
+								fakePipelineDB.GetResourceReturns(db.SavedResource{
+									ID:              1,
+									PipelineName:    "a-pipeline",
+									PinnedVersionID: 42,
+									Resource: db.Resource{
+										Name: "resource-1",
+									},
+								}, true, nil)
+							})
+
+							It("includes the pinned version", func() {
+								body, err := ioutil.ReadAll(response.Body)
+								Expect(err).NotTo(HaveOccurred())
+
+								Expect(body).To(MatchJSON(`
+								{
+									"name": "resource-1",
+									"type": "type-1",
+									"groups": ["group-1", "group-2"],
+									"url": "/teams/a-team/pipelines/a-pipeline/resources/resource-1",
+									"pinned_version_id": 42
+								}`))
+							})
+						})
+
 						It("returns the resource json with the check error", func() {
 							body, err := ioutil.ReadAll(response.Body)
 							Expect(err).NotTo(HaveOccurred())
@@ -578,6 +723,88 @@ var _ = Describe("Resources API", func() {
 		})
 	})
 
+	Describe("PUT /api/v1/teams/:team_name/pipelines/:pipeline_name/resources/:resource_name/unpin", func() {
+		var response *http.Response
+
+		BeforeEach(func() {
+			fakePipelineDB.GetResourceReturns(db.SavedResource{
+				Resource: db.Resource{
+					Name: "resource-name",
+				},
+			}, true, nil)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+
+			request, err := http.NewRequest("PUT", server.URL+"/api/v1/teams/a-team/pipelines/a-pipeline/resources/resource-name/unpin", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			response, err = client.Do(request)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("a-team", 42, true, true)
+			})
+
+			It("injects the proper pipelineDB", func() {
+				Expect(teamDB.GetPipelineByNameCallCount()).To(Equal(1))
+				pipelineName := teamDB.GetPipelineByNameArgsForCall(0)
+				Expect(pipelineName).To(Equal("a-pipeline"))
+				Expect(pipelineDBFactory.BuildCallCount()).To(Equal(1))
+				actualSavedPipeline := pipelineDBFactory.BuildArgsForCall(0)
+				Expect(actualSavedPipeline).To(Equal(expectedSavedPipeline))
+			})
+
+			Context("when unpinning the resource succeeds", func() {
+				BeforeEach(func() {
+					fakePipelineDB.UnpinResourceReturns(nil)
+				})
+
+				It("unpinned the right resource", func() {
+					Expect(fakePipelineDB.UnpinResourceArgsForCall(0)).To(Equal("resource-name"))
+				})
+
+				It("returns 200", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+				})
+			})
+
+			Context("when resource can not be found", func() {
+				BeforeEach(func() {
+					fakePipelineDB.GetResourceReturns(db.SavedResource{}, false, nil)
+				})
+
+				It("returns 404", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+				})
+			})
+
+			Context("when unpinning the resource fails", func() {
+				BeforeEach(func() {
+					fakePipelineDB.UnpinResourceReturns(errors.New("welp"))
+				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+
+		Context("when not authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns Unauthorized", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+	})
+
 	Describe("GET /api/v1/teams/:team_name/pipelines/:pipeline_name/resources/:resource_name/check", func() {
 		var fakeScanner *radarfakes.FakeScanner
 		var checkRequestBody atc.CheckRequestBody
@@ -617,6 +844,38 @@ var _ = Describe("Resources API", func() {
 				Expect(actualSavedPipeline).To(Equal(expectedSavedPipeline))
 			})
 
+			Context("when triggering the pipeline needs the admin role", func() {
+				BeforeEach(func() {
+					fakePipelineDB.PipelineReturns(db.SavedPipeline{
+						Permissions: atc.PipelinePermissions{Trigger: atc.RoleAdmin},
+					})
+				})
+
+				Context("and the request has the operator role", func() {
+					BeforeEach(func() {
+						userContextReader.GetRoleReturns(atc.RoleOperator, true)
+					})
+
+					It("returns 403", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+					})
+
+					It("does not check the resource", func() {
+						Expect(fakeScanner.ScanFromVersionCallCount()).To(BeZero())
+					})
+				})
+
+				Context("and the request has the admin role", func() {
+					BeforeEach(func() {
+						userContextReader.GetRoleReturns(atc.RoleAdmin, true)
+					})
+
+					It("checks the resource", func() {
+						Expect(fakeScanner.ScanFromVersionCallCount()).To(Equal(1))
+					})
+				})
+			})
+
 			It("tries to scan with no version specified", func() {
 				Expect(fakeScanner.ScanFromVersionCallCount()).To(Equal(1))
 				_, actualResourceName, actualFromVersion := fakeScanner.ScanFromVersionArgsForCall(0)
@@ -628,6 +887,70 @@ var _ = Describe("Resources API", func() {
 				Expect(response.StatusCode).To(Equal(http.StatusOK))
 			})
 
+			It("returns application/json", func() {
+				Expect(response.Header.Get("Content-Type")).To(Equal("application/json"))
+			})
+
+			It("looks up every version of the resource, as none were known before", func() {
+				Expect(fakePipelineDB.GetResourceVersionsCallCount()).To(Equal(1))
+				actualResourceName, actualPage := fakePipelineDB.GetResourceVersionsArgsForCall(0)
+				Expect(actualResourceName).To(Equal("resource-name"))
+				Expect(actualPage).To(Equal(db.Page{Limit: 100}))
+			})
+
+			Context("when the check finds versions", func() {
+				BeforeEach(func() {
+					fakePipelineDB.GetResourceVersionsReturns([]db.SavedVersionedResource{
+						{
+							ID:      5,
+							Enabled: true,
+							VersionedResource: db.VersionedResource{
+								Resource:   "resource-name",
+								Type:       "some-type",
+								Version:    db.Version{"some": "new-version"},
+								PipelineID: 42,
+							},
+						},
+					}, db.Pagination{}, true, nil)
+				})
+
+				It("returns them", func() {
+					body, err := ioutil.ReadAll(response.Body)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(body).To(MatchJSON(`[
+						{
+							"id": 5,
+							"pipeline_id": 42,
+							"resource": "resource-name",
+							"enabled": true,
+							"type": "some-type",
+							"metadata": null,
+							"version": {"some": "new-version"}
+						}
+					]`))
+				})
+			})
+
+			Context("when the check finds nothing", func() {
+				It("returns an empty list", func() {
+					body, err := ioutil.ReadAll(response.Body)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(body).To(MatchJSON(`[]`))
+				})
+			})
+
+			Context("when looking up the checked versions fails", func() {
+				BeforeEach(func() {
+					fakePipelineDB.GetResourceVersionsReturns(nil, db.Pagination{}, false, errors.New("disaster"))
+				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+
 			Context("when checking with a version specified", func() {
 				BeforeEach(func() {
 					checkRequestBody = atc.CheckRequestBody{
@@ -664,6 +987,7 @@ var _ = Describe("Resources API", func() {
 							},
 							PipelineID: 42,
 						},
+						CheckOrder: 7,
 					}
 					fakePipelineDB.GetLatestVersionedResourceReturns(returnedVersion, true, nil)
 				})
@@ -674,6 +998,12 @@ var _ = Describe("Resources API", func() {
 					Expect(actualResourceName).To(Equal("resource-name"))
 					Expect(actualFromVersion).To(Equal(atc.Version{"some": "version"}))
 				})
+
+				It("looks up the versions saved after the latest version", func() {
+					Expect(fakePipelineDB.GetResourceVersionsCallCount()).To(Equal(1))
+					_, actualPage := fakePipelineDB.GetResourceVersionsArgsForCall(0)
+					Expect(actualPage).To(Equal(db.Page{Until: 7, Limit: 100}))
+				})
 			})
 
 			Context("when failing to get latest version for resource", func() {
@@ -708,6 +1038,17 @@ var _ = Describe("Resources API", func() {
 				It("returns 500", func() {
 					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
 				})
+
+				It("returns the error in the response body", func() {
+					body, err := ioutil.ReadAll(response.Body)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(body).To(MatchJSON(`{"code":"builder-failure","message":"failed to check resource: welp"}`))
+				})
+
+				It("does not look up any versions", func() {
+					Expect(fakePipelineDB.GetResourceVersionsCallCount()).To(BeZero())
+				})
 			})
 
 			Context("when checking the resource fails with ErrResourceScriptFailed", func() {
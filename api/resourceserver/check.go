@@ -2,11 +2,16 @@ package resourceserver
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 
 	"code.cloudfoundry.org/lager"
 	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/api/present"
+	"github.com/concourse/atc/auth"
 	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/requestlog"
 	"github.com/concourse/atc/resource"
 	"github.com/tedsuo/rata"
 )
@@ -15,8 +20,14 @@ func (s *Server) CheckResource(pipelineDB db.PipelineDB) http.Handler {
 	logger := s.logger.Session("check-resource")
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger := requestlog.Logger(logger, r)
+
 		resourceName := rata.Param(r, "resource_name")
 
+		if !auth.CheckPipelinePermission(w, r, pipelineDB.Pipeline().Permissions, atc.PipelinePermissionTrigger) {
+			return
+		}
+
 		var reqBody atc.CheckRequestBody
 		err := json.NewDecoder(r.Body).Decode(&reqBody)
 		if err != nil {
@@ -25,18 +36,16 @@ func (s *Server) CheckResource(pipelineDB db.PipelineDB) http.Handler {
 			return
 		}
 
-		fromVersion := reqBody.From
-		if fromVersion == nil {
-			latestVersion, found, err := pipelineDB.GetLatestVersionedResource(resourceName)
-			if err != nil {
-				logger.Info("failed-to-get-latest-versioned-resource", lager.Data{"error": err.Error()})
-				w.WriteHeader(http.StatusInternalServerError)
-				return
-			}
+		latestVersion, found, err := pipelineDB.GetLatestVersionedResource(resourceName)
+		if err != nil {
+			logger.Info("failed-to-get-latest-versioned-resource", lager.Data{"error": err.Error()})
+			apierror.DBFailure(w, "failed to get latest versioned resource")
+			return
+		}
 
-			if found {
-				fromVersion = atc.Version(latestVersion.Version)
-			}
+		fromVersion := reqBody.From
+		if fromVersion == nil && found {
+			fromVersion = atc.Version(latestVersion.Version)
 		}
 
 		scanner := s.scannerFactory.NewResourceScanner(pipelineDB)
@@ -52,12 +61,38 @@ func (s *Server) CheckResource(pipelineDB db.PipelineDB) http.Handler {
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusBadRequest)
 			json.NewEncoder(w).Encode(checkResponseBody)
+			return
 		case db.ResourceNotFoundError:
-			w.WriteHeader(http.StatusNotFound)
+			apierror.NotFound(w, "resource not found")
+			return
 		case error:
-			w.WriteHeader(http.StatusInternalServerError)
-		default:
-			w.WriteHeader(http.StatusOK)
+			logger.Error("failed-to-check", err)
+			apierror.BuilderFailure(w, fmt.Sprintf("failed to check resource: %s", err))
+			return
+		}
+
+		// anything the check returned is saved with a higher check order than
+		// the version that was latest beforehand, including versions that were
+		// already known
+		page := db.Page{Limit: atc.PaginationAPIDefaultLimit}
+		if found {
+			page.Until = latestVersion.CheckOrder
 		}
+
+		versions, _, _, err := pipelineDB.GetResourceVersions(resourceName, page)
+		if err != nil {
+			logger.Error("failed-to-get-checked-versions", err)
+			apierror.DBFailure(w, "failed to get checked versions")
+			return
+		}
+
+		checkedVersions := make([]atc.VersionedResource, len(versions))
+		for i, version := range versions {
+			checkedVersions[i] = present.SavedVersionedResource(version)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(checkedVersions)
 	})
 }
@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/api/apierror"
 	"github.com/concourse/atc/api/present"
 	"github.com/concourse/atc/auth"
 	"github.com/concourse/atc/db"
@@ -16,13 +17,13 @@ func (s *Server) GetResource(pipelineDB db.PipelineDB) http.Handler {
 		config, _, found, err := pipelineDB.GetConfig()
 		if err != nil {
 			logger.Error("failed-to-get-config", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.DBFailure(w, "failed to get pipeline config")
 			return
 		}
 
 		if !found {
 			logger.Info("config-not-found")
-			w.WriteHeader(http.StatusNotFound)
+			apierror.NotFound(w, "pipeline config not found")
 			return
 		}
 
@@ -32,20 +33,20 @@ func (s *Server) GetResource(pipelineDB db.PipelineDB) http.Handler {
 		resourceConfig, resourceFound := config.Resources.Lookup(resourceName)
 		if !resourceFound {
 			logger.Info("resource-not-in-config")
-			w.WriteHeader(http.StatusNotFound)
+			apierror.NotFound(w, "resource not found")
 			return
 		}
 
 		dbResource, found, err := pipelineDB.GetResource(resourceName)
 		if err != nil {
 			logger.Error("failed-to-get-resource", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.DBFailure(w, "failed to get resource")
 			return
 		}
 
 		if !found {
 			logger.Debug("resource-not-found", lager.Data{"resource": resourceName})
-			w.WriteHeader(http.StatusNotFound)
+			apierror.NotFound(w, "resource not found")
 			return
 		}
 
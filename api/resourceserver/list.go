@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
 	"github.com/concourse/atc/api/present"
 	"github.com/concourse/atc/auth"
 	"github.com/concourse/atc/db"
@@ -16,12 +17,12 @@ func (s *Server) ListResources(pipelineDB db.PipelineDB) http.Handler {
 		dashboardResources, groupConfigs, found, err := pipelineDB.GetResources()
 		if err != nil {
 			logger.Error("failed-to-get-dashboard-resources", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.DBFailure(w, "failed to get dashboard resources")
 			return
 		}
 
 		if !found {
-			w.WriteHeader(http.StatusNotFound)
+			apierror.NotFound(w, "pipeline config not found")
 			return
 		}
 
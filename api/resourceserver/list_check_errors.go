@@ -0,0 +1,55 @@
+package resourceserver
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/api/present"
+	"github.com/concourse/atc/db"
+)
+
+// ListResourceCheckErrors lists only the resources whose last check failed,
+// along with the error, so that they can be alerted on without fetching
+// every resource.
+func (s *Server) ListResourceCheckErrors(pipelineDB db.PipelineDB) http.Handler {
+	logger := s.logger.Session("list-resource-check-errors")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		dashboardResources, groupConfigs, found, err := pipelineDB.GetResources()
+		if err != nil {
+			logger.Error("failed-to-get-dashboard-resources", err)
+			apierror.DBFailure(w, "failed to get dashboard resources")
+			return
+		}
+
+		if !found {
+			apierror.NotFound(w, "pipeline config not found")
+			return
+		}
+
+		teamName := r.FormValue(":team_name")
+
+		resources := []atc.Resource{}
+		for _, dashboardResource := range dashboardResources {
+			if !dashboardResource.Resource.FailingToCheck() {
+				continue
+			}
+
+			resources = append(
+				resources,
+				present.Resource(
+					dashboardResource.ResourceConfig,
+					groupConfigs,
+					dashboardResource.Resource,
+					true,
+					teamName,
+				),
+			)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(resources)
+	})
+}
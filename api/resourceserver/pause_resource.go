@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/api/apierror"
 	"github.com/concourse/atc/db"
 	"github.com/tedsuo/rata"
 )
@@ -16,20 +17,20 @@ func (s *Server) PauseResource(pipelineDB db.PipelineDB) http.Handler {
 		_, found, err := pipelineDB.GetResource(resourceName)
 		if err != nil {
 			logger.Error("failed-to-get-resource", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.DBFailure(w, "failed to get resource")
 			return
 		}
 
 		if !found {
 			logger.Debug("resource-not-found", lager.Data{"resource": resourceName})
-			w.WriteHeader(http.StatusNotFound)
+			apierror.NotFound(w, "resource not found")
 			return
 		}
 
 		err = pipelineDB.PauseResource(resourceName)
 		if err != nil {
 			logger.Error("failed-to-pause-resource", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.DBFailure(w, "failed to pause resource")
 			return
 		}
 
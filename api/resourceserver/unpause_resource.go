@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/api/apierror"
 	"github.com/concourse/atc/db"
 	"github.com/tedsuo/rata"
 )
@@ -15,19 +16,19 @@ func (s *Server) UnpauseResource(pipelineDB db.PipelineDB) http.Handler {
 		_, found, err := pipelineDB.GetResource(resourceName)
 		if err != nil {
 			s.logger.Error("failed-to-get-resource", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.DBFailure(w, "failed to get resource")
 			return
 		}
 
 		if !found {
 			s.logger.Debug("resource-not-found", lager.Data{"resource": resourceName})
-			w.WriteHeader(http.StatusNotFound)
+			apierror.NotFound(w, "resource not found")
 			return
 		}
 
 		err = pipelineDB.UnpauseResource(resourceName)
 		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.DBFailure(w, "failed to unpause resource")
 			return
 		}
 
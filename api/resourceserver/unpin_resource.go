@@ -0,0 +1,39 @@
+package resourceserver
+
+import (
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/db"
+	"github.com/tedsuo/rata"
+)
+
+func (s *Server) UnpinResource(pipelineDB db.PipelineDB) http.Handler {
+	logger := s.logger.Session("unpin-resource")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resourceName := rata.Param(r, "resource_name")
+
+		_, found, err := pipelineDB.GetResource(resourceName)
+		if err != nil {
+			logger.Error("failed-to-get-resource", err)
+			apierror.DBFailure(w, "failed to get resource")
+			return
+		}
+
+		if !found {
+			logger.Debug("resource-not-found", lager.Data{"resource": resourceName})
+			apierror.NotFound(w, "resource not found")
+			return
+		}
+
+		err = pipelineDB.UnpinResource(resourceName)
+		if err != nil {
+			logger.Error("failed-to-unpin-resource", err)
+			apierror.DBFailure(w, "failed to unpin resource")
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	})
+}
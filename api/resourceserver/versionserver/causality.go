@@ -0,0 +1,39 @@
+package versionserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/api/present"
+	"github.com/concourse/atc/db"
+)
+
+func (s *Server) GetResourceVersionCausality(pipelineDB db.PipelineDB) http.Handler {
+	logger := s.logger.Session("get-resource-version-causality")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		versionID, err := strconv.Atoi(r.FormValue(":resource_version_id"))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		causality, found, err := pipelineDB.GetVersionCausality(versionID)
+		if err != nil {
+			logger.Error("failed-to-get-causality", err)
+			apierror.DBFailure(w, "failed to get causality")
+			return
+		}
+
+		if !found {
+			apierror.NotFound(w, "resource version not found")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(present.Causality(causality))
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/concourse/atc/api/apierror"
 	"github.com/concourse/atc/db"
 	"github.com/tedsuo/rata"
 )
@@ -20,7 +21,7 @@ func (s *Server) DisableResourceVersion(pipelineDB db.PipelineDB) http.Handler {
 		err = pipelineDB.DisableVersionedResource(resourceID)
 		if err != nil {
 			logger.Error("failed-to-disable-versioned-resource", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.DBFailure(w, "failed to disable versioned resource")
 			return
 		}
 
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/concourse/atc/api/apierror"
 	"github.com/concourse/atc/db"
 	"github.com/tedsuo/rata"
 )
@@ -20,7 +21,7 @@ func (s *Server) EnableResourceVersion(pipelineDB db.PipelineDB) http.Handler {
 		err = pipelineDB.EnableVersionedResource(resourceID)
 		if err != nil {
 			logger.Error("failed-to-enable-versioned-resource", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.DBFailure(w, "failed to enable versioned resource")
 			return
 		}
 
@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
 	"github.com/concourse/atc/api/present"
 	"github.com/concourse/atc/db"
 )
@@ -17,7 +18,7 @@ func (s *Server) ListBuildsWithVersionAsInput(pipelineDB db.PipelineDB) http.Han
 
 		builds, err := pipelineDB.GetBuildsWithVersionAsInput(versionID)
 		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.DBFailure(w, "failed to get builds")
 			return
 		}
 
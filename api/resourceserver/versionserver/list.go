@@ -7,6 +7,7 @@ import (
 	"strconv"
 
 	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
 	"github.com/concourse/atc/api/present"
 	"github.com/concourse/atc/db"
 )
@@ -37,15 +38,19 @@ func (s *Server) ListResourceVersions(pipelineDB db.PipelineDB) http.Handler {
 			limit = atc.PaginationAPIDefaultLimit
 		}
 
+		if limit > atc.PaginationAPIMaxLimit {
+			limit = atc.PaginationAPIMaxLimit
+		}
+
 		versions, pagination, found, err := pipelineDB.GetResourceVersions(resourceName, db.Page{Until: until, Since: since, Limit: limit})
 		if err != nil {
 			logger.Error("failed-to-get-resource-versions", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.DBFailure(w, "failed to get resource versions")
 			return
 		}
 
 		if !found {
-			w.WriteHeader(http.StatusNotFound)
+			apierror.NotFound(w, "resource not found")
 			return
 		}
 
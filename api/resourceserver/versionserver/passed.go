@@ -0,0 +1,177 @@
+package versionserver
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/api/present"
+	"github.com/concourse/atc/db"
+	"github.com/vito/go-sse/sse"
+)
+
+// PassedJobsQueryParam is the comma-separated list of jobs that versions must
+// have passed, e.g. ?jobs=unit,integration.
+const PassedJobsQueryParam = "jobs"
+
+// passedVersionsPollInterval is how often a stream of passed versions looks
+// for new ones. Loading the versions is cached until the pipeline's builds or
+// versions change, so this is cheap while nothing is happening.
+const passedVersionsPollInterval = 10 * time.Second
+
+func (s *Server) ListPassedResourceVersions(pipelineDB db.PipelineDB) http.Handler {
+	logger := s.logger.Session("list-passed-resource-versions")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resourceName := r.FormValue(":resource_name")
+
+		jobs, err := passedJobsFrom(r)
+		if err != nil {
+			logger.Info("malformed-jobs", lager.Data{"error": err.Error()})
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		versions, found, err := pipelineDB.GetVersionsPassedJobs(resourceName, jobs)
+		if err != nil {
+			logger.Error("failed-to-get-passed-versions", err)
+			apierror.DBFailure(w, "failed to get passed versions")
+			return
+		}
+
+		if !found {
+			apierror.NotFound(w, "resource or job not found")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		presented := make([]atc.VersionedResource, len(versions))
+		for i, version := range versions {
+			presented[i] = present.SavedVersionedResource(version)
+		}
+
+		json.NewEncoder(w).Encode(presented)
+	})
+}
+
+// PassedResourceVersionEvents streams a "passed" event for each version of
+// the resource that has passed all of the jobs, starting with those that
+// already have and then each one as it does.
+func (s *Server) PassedResourceVersionEvents(pipelineDB db.PipelineDB) http.Handler {
+	logger := s.logger.Session("passed-resource-version-events")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resourceName := r.FormValue(":resource_name")
+
+		jobs, err := passedJobsFrom(r)
+		if err != nil {
+			logger.Info("malformed-jobs", lager.Data{"error": err.Error()})
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		versions, found, err := pipelineDB.GetVersionsPassedJobs(resourceName, jobs)
+		if err != nil {
+			logger.Error("failed-to-get-passed-versions", err)
+			apierror.DBFailure(w, "failed to get passed versions")
+			return
+		}
+
+		if !found {
+			apierror.NotFound(w, "resource or job not found")
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+		w.WriteHeader(http.StatusOK)
+
+		flusher := w.(http.Flusher)
+		flusher.Flush()
+
+		sent := map[int]bool{}
+
+		ticker := time.NewTicker(passedVersionsPollInterval)
+		defer ticker.Stop()
+
+		for {
+			// versions come newest first, but are sent in the order they'd
+			// have passed had they been seen one at a time
+			for i := len(versions) - 1; i >= 0; i-- {
+				version := versions[i]
+				if sent[version.ID] {
+					continue
+				}
+
+				err := writePassedVersion(w, version, jobs)
+				if err != nil {
+					logger.Info("failed-to-write-event", lager.Data{"error": err.Error()})
+					return
+				}
+
+				sent[version.ID] = true
+			}
+
+			flusher.Flush()
+
+			select {
+			case <-ticker.C:
+			case <-r.Context().Done():
+				return
+			}
+
+			latest, found, err := pipelineDB.GetVersionsPassedJobs(resourceName, jobs)
+			if err != nil {
+				logger.Error("failed-to-get-passed-versions", err)
+				continue
+			}
+
+			if !found {
+				logger.Info("resource-or-job-went-away")
+				return
+			}
+
+			versions = latest
+		}
+	})
+}
+
+func writePassedVersion(w http.ResponseWriter, version db.SavedVersionedResource, jobs []string) error {
+	payload, err := json.Marshal(atc.PassedVersion{
+		Version: present.SavedVersionedResource(version),
+		Passed:  jobs,
+	})
+	if err != nil {
+		return err
+	}
+
+	return sse.Event{
+		ID:   strconv.Itoa(version.ID),
+		Name: "passed",
+		Data: payload,
+	}.Write(w)
+}
+
+func passedJobsFrom(r *http.Request) ([]string, error) {
+	jobs := []string{}
+	for _, job := range strings.Split(r.FormValue(PassedJobsQueryParam), ",") {
+		job = strings.TrimSpace(job)
+		if job != "" {
+			jobs = append(jobs, job)
+		}
+	}
+
+	if len(jobs) == 0 {
+		return nil, errors.New("no jobs given")
+	}
+
+	return jobs, nil
+}
@@ -0,0 +1,42 @@
+package versionserver
+
+import (
+	"net/http"
+	"strconv"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/db"
+	"github.com/tedsuo/rata"
+)
+
+func (s *Server) PinResourceVersion(pipelineDB db.PipelineDB) http.Handler {
+	logger := s.logger.Session("pin-resource-version")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resourceName := rata.Param(r, "resource_name")
+
+		versionID, err := strconv.Atoi(rata.Param(r, "resource_version_id"))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		pinned, err := pipelineDB.PinResourceVersion(resourceName, versionID)
+		if err != nil {
+			logger.Error("failed-to-pin-resource-version", err)
+			apierror.DBFailure(w, "failed to pin resource version")
+			return
+		}
+
+		if !pinned {
+			logger.Debug("resource-version-not-found", lager.Data{
+				"resource": resourceName,
+				"version":  versionID,
+			})
+			apierror.NotFound(w, "resource version not found")
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	})
+}
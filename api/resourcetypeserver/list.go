@@ -0,0 +1,22 @@
+package resourcetypeserver
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/concourse/atc/api/apierror"
+)
+
+func (s *Server) ListRegisteredResourceTypes(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.Session("list-registered-resource-types")
+
+	resourceTypes, err := s.db.GetRegisteredResourceTypes()
+	if err != nil {
+		logger.Error("failed-to-get-registered-resource-types", err)
+		apierror.DBFailure(w, "failed to get registered resource types")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resourceTypes)
+}
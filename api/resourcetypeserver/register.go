@@ -0,0 +1,50 @@
+package resourcetypeserver
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+)
+
+func (s *Server) RegisterResourceType(w http.ResponseWriter, r *http.Request) {
+	name := r.FormValue(":resource_type_name")
+
+	logger := s.logger.Session("register-resource-type", lager.Data{
+		"resource-type": name,
+	})
+
+	var resourceType atc.RegisteredResourceType
+	err := json.NewDecoder(r.Body).Decode(&resourceType)
+	if err != nil {
+		logger.Info("malformed-request", lager.Data{"error": err.Error()})
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	resourceType.Name = name
+
+	if resourceType.Image == "" {
+		logger.Info("missing-image")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if resourceType.Version == "" {
+		logger.Info("missing-version")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = s.db.RegisterResourceType(resourceType)
+	if err != nil {
+		logger.Error("failed-to-register-resource-type", err)
+		apierror.DBFailure(w, "failed to register resource type")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resourceType)
+}
@@ -0,0 +1,157 @@
+// This file was generated by counterfeiter
+package resourcetypeserverfakes
+
+import (
+	"sync"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/resourcetypeserver"
+)
+
+type FakeResourceTypesDB struct {
+	RegisterResourceTypeStub        func(arg1 atc.RegisteredResourceType) error
+	registerResourceTypeMutex       sync.RWMutex
+	registerResourceTypeArgsForCall []struct {
+		arg1 atc.RegisteredResourceType
+	}
+	registerResourceTypeReturns struct {
+		result1 error
+	}
+	UnregisterResourceTypeStub        func(name string) (bool, error)
+	unregisterResourceTypeMutex       sync.RWMutex
+	unregisterResourceTypeArgsForCall []struct {
+		name string
+	}
+	unregisterResourceTypeReturns struct {
+		result1 bool
+		result2 error
+	}
+	GetRegisteredResourceTypesStub        func() ([]atc.RegisteredResourceType, error)
+	getRegisteredResourceTypesMutex       sync.RWMutex
+	getRegisteredResourceTypesArgsForCall []struct{}
+	getRegisteredResourceTypesReturns     struct {
+		result1 []atc.RegisteredResourceType
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeResourceTypesDB) RegisterResourceType(arg1 atc.RegisteredResourceType) error {
+	fake.registerResourceTypeMutex.Lock()
+	fake.registerResourceTypeArgsForCall = append(fake.registerResourceTypeArgsForCall, struct {
+		arg1 atc.RegisteredResourceType
+	}{arg1})
+	fake.recordInvocation("RegisterResourceType", []interface{}{arg1})
+	fake.registerResourceTypeMutex.Unlock()
+	if fake.RegisterResourceTypeStub != nil {
+		return fake.RegisterResourceTypeStub(arg1)
+	} else {
+		return fake.registerResourceTypeReturns.result1
+	}
+}
+
+func (fake *FakeResourceTypesDB) RegisterResourceTypeCallCount() int {
+	fake.registerResourceTypeMutex.RLock()
+	defer fake.registerResourceTypeMutex.RUnlock()
+	return len(fake.registerResourceTypeArgsForCall)
+}
+
+func (fake *FakeResourceTypesDB) RegisterResourceTypeArgsForCall(i int) atc.RegisteredResourceType {
+	fake.registerResourceTypeMutex.RLock()
+	defer fake.registerResourceTypeMutex.RUnlock()
+	return fake.registerResourceTypeArgsForCall[i].arg1
+}
+
+func (fake *FakeResourceTypesDB) RegisterResourceTypeReturns(result1 error) {
+	fake.RegisterResourceTypeStub = nil
+	fake.registerResourceTypeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeResourceTypesDB) UnregisterResourceType(name string) (bool, error) {
+	fake.unregisterResourceTypeMutex.Lock()
+	fake.unregisterResourceTypeArgsForCall = append(fake.unregisterResourceTypeArgsForCall, struct {
+		name string
+	}{name})
+	fake.recordInvocation("UnregisterResourceType", []interface{}{name})
+	fake.unregisterResourceTypeMutex.Unlock()
+	if fake.UnregisterResourceTypeStub != nil {
+		return fake.UnregisterResourceTypeStub(name)
+	} else {
+		return fake.unregisterResourceTypeReturns.result1, fake.unregisterResourceTypeReturns.result2
+	}
+}
+
+func (fake *FakeResourceTypesDB) UnregisterResourceTypeCallCount() int {
+	fake.unregisterResourceTypeMutex.RLock()
+	defer fake.unregisterResourceTypeMutex.RUnlock()
+	return len(fake.unregisterResourceTypeArgsForCall)
+}
+
+func (fake *FakeResourceTypesDB) UnregisterResourceTypeArgsForCall(i int) string {
+	fake.unregisterResourceTypeMutex.RLock()
+	defer fake.unregisterResourceTypeMutex.RUnlock()
+	return fake.unregisterResourceTypeArgsForCall[i].name
+}
+
+func (fake *FakeResourceTypesDB) UnregisterResourceTypeReturns(result1 bool, result2 error) {
+	fake.UnregisterResourceTypeStub = nil
+	fake.unregisterResourceTypeReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeResourceTypesDB) GetRegisteredResourceTypes() ([]atc.RegisteredResourceType, error) {
+	fake.getRegisteredResourceTypesMutex.Lock()
+	fake.getRegisteredResourceTypesArgsForCall = append(fake.getRegisteredResourceTypesArgsForCall, struct{}{})
+	fake.recordInvocation("GetRegisteredResourceTypes", []interface{}{})
+	fake.getRegisteredResourceTypesMutex.Unlock()
+	if fake.GetRegisteredResourceTypesStub != nil {
+		return fake.GetRegisteredResourceTypesStub()
+	} else {
+		return fake.getRegisteredResourceTypesReturns.result1, fake.getRegisteredResourceTypesReturns.result2
+	}
+}
+
+func (fake *FakeResourceTypesDB) GetRegisteredResourceTypesCallCount() int {
+	fake.getRegisteredResourceTypesMutex.RLock()
+	defer fake.getRegisteredResourceTypesMutex.RUnlock()
+	return len(fake.getRegisteredResourceTypesArgsForCall)
+}
+
+func (fake *FakeResourceTypesDB) GetRegisteredResourceTypesReturns(result1 []atc.RegisteredResourceType, result2 error) {
+	fake.GetRegisteredResourceTypesStub = nil
+	fake.getRegisteredResourceTypesReturns = struct {
+		result1 []atc.RegisteredResourceType
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeResourceTypesDB) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.registerResourceTypeMutex.RLock()
+	defer fake.registerResourceTypeMutex.RUnlock()
+	fake.unregisterResourceTypeMutex.RLock()
+	defer fake.unregisterResourceTypeMutex.RUnlock()
+	fake.getRegisteredResourceTypesMutex.RLock()
+	defer fake.getRegisteredResourceTypesMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeResourceTypesDB) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ resourcetypeserver.ResourceTypesDB = new(FakeResourceTypesDB)
@@ -0,0 +1,30 @@
+package resourcetypeserver
+
+import (
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+)
+
+//go:generate counterfeiter . ResourceTypesDB
+
+type ResourceTypesDB interface {
+	RegisterResourceType(atc.RegisteredResourceType) error
+	UnregisterResourceType(name string) (bool, error)
+	GetRegisteredResourceTypes() ([]atc.RegisteredResourceType, error)
+}
+
+type Server struct {
+	logger lager.Logger
+
+	db ResourceTypesDB
+}
+
+func NewServer(
+	logger lager.Logger,
+	db ResourceTypesDB,
+) *Server {
+	return &Server{
+		logger: logger,
+		db:     db,
+	}
+}
@@ -0,0 +1,30 @@
+package resourcetypeserver
+
+import (
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/api/apierror"
+)
+
+func (s *Server) UnregisterResourceType(w http.ResponseWriter, r *http.Request) {
+	name := r.FormValue(":resource_type_name")
+
+	logger := s.logger.Session("unregister-resource-type", lager.Data{
+		"resource-type": name,
+	})
+
+	unregistered, err := s.db.UnregisterResourceType(name)
+	if err != nil {
+		logger.Error("failed-to-unregister-resource-type", err)
+		apierror.DBFailure(w, "failed to unregister resource type")
+		return
+	}
+
+	if !unregistered {
+		apierror.NotFound(w, "resource type not registered")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -6,6 +6,7 @@ import (
 
 	"code.cloudfoundry.org/lager"
 
+	"github.com/concourse/atc/api/apierror"
 	"github.com/concourse/atc/auth"
 	"github.com/concourse/atc/db"
 )
@@ -32,7 +33,7 @@ func (f *TeamScopedHandlerFactory) HandlerFor(teamScopedHandler func(db.TeamDB)
 		authTeam, authTeamFound := auth.GetTeam(r)
 		if !authTeamFound {
 			logger.Error("team-not-found-in-context", errors.New("team-not-found-in-context"))
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.Internal(w, "team not found in context")
 			return
 		}
 
@@ -14,6 +14,21 @@ import (
 	. "github.com/onsi/gomega"
 )
 
+const samlIdPCertificate = `-----BEGIN CERTIFICATE-----
+MIICEjCCAXugAwIBAgIUQ+4+WgQweLZg0m78GCWtnO0Ku7IwDQYJKoZIhvcNAQEL
+BQAwGjEYMBYGA1UEAwwPaWRwLmV4YW1wbGUuY29tMCAXDTI2MTAxNDA4MTMyOFoY
+DzIxMjYwOTIwMDgxMzI4WjAaMRgwFgYDVQQDDA9pZHAuZXhhbXBsZS5jb20wgZ8w
+DQYJKoZIhvcNAQEBBQADgY0AMIGJAoGBANjgshrU8GX3nPG0xa4EmtPSrG9RZH0k
+YOVwm9GCEkwj74FgdfMMdxHYwFtlFSZJd9yHJJwr55500+/RwWhPmdVtoZvOdXO3
+gCOzEMcPGxOFsp+2w/x/rqquMAadDhFlT9WSLkZrnTTdsXKRG4FmE303eDrXX5Mr
+inzmfaSmWlmfAgMBAAGjUzBRMB0GA1UdDgQWBBQwxsctK9NzHlIruczL0XR+cyC2
+8zAfBgNVHSMEGDAWgBQwxsctK9NzHlIruczL0XR+cyC28zAPBgNVHRMBAf8EBTAD
+AQH/MA0GCSqGSIb3DQEBCwUAA4GBAL7Xpf6wKk3LLqSZ90BBCBSfjo7srAfpB9f2
+ZTr9GZNkGy+VxugrX0dwvf/RIw9AMzVVnTwoExtS+ETg4LBREi3Y/SDC/BHvK0rV
+q9eCCPczzwc+ntdIz508CsV3oUH8HT/WZeGNP0FIU++pUuuRVORnkl1iGbGG42UJ
+c8KjA4Ey
+-----END CERTIFICATE-----`
+
 func jsonEncode(object interface{}) *bytes.Buffer {
 	reqPayload, err := json.Marshal(object)
 	Expect(err).NotTo(HaveOccurred())
@@ -238,6 +253,22 @@ var _ = Describe("Teams API", func() {
 					})
 				})
 
+				Describe("roles", func() {
+					Context("with an unknown role", func() {
+						BeforeEach(func() {
+							team = atc.Team{
+								Roles: &atc.TeamRoles{
+									GitHubAuth: "overlord",
+								},
+							}
+						})
+
+						It("returns a 400 Bad Request", func() {
+							Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+						})
+					})
+				})
+
 				Describe("GitHub authenticaiton", func() {
 					Context("ClientID not filled in", func() {
 						BeforeEach(func() {
@@ -501,6 +532,105 @@ var _ = Describe("Teams API", func() {
 				})
 			})
 
+			Describe("OIDC Authentication", func() {
+				BeforeEach(func() {
+					team = atc.Team{
+						OIDCAuth: &atc.OIDCAuth{
+							ClientID:     "Brock Samson",
+							ClientSecret: "09262-8765-001",
+							Issuer:       "https://accounts.google.com",
+							DisplayName:  "Google",
+							Groups:       []string{"OSI"},
+						},
+					}
+				})
+
+				Context("when passed a valid team with OIDC Auth", func() {
+					It("responds with 201", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusCreated))
+					})
+				})
+
+				Context("Issuer not filled in", func() {
+					BeforeEach(func() {
+						team.OIDCAuth.Issuer = ""
+					})
+
+					It("returns a 400 Bad Request", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+					})
+				})
+
+				Context("ClientSecret not filled in", func() {
+					BeforeEach(func() {
+						team.OIDCAuth.ClientSecret = ""
+					})
+
+					It("returns a 400 Bad Request", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+					})
+				})
+
+				Context("neither Groups nor Users filled in", func() {
+					BeforeEach(func() {
+						team.OIDCAuth.Groups = nil
+					})
+
+					It("returns a 400 Bad Request", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+					})
+				})
+			})
+
+			Describe("SAML Authentication", func() {
+				BeforeEach(func() {
+					team = atc.Team{
+						SAMLAuth: &atc.SAMLAuth{
+							DisplayName:    "Okta",
+							SSOURL:         "https://okta.example.com/sso",
+							IdPCertificate: samlIdPCertificate,
+							Users:          []string{"brock@osi.example.com"},
+						},
+					}
+				})
+
+				Context("when passed a valid team with SAML Auth", func() {
+					It("responds with 201", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusCreated))
+					})
+				})
+
+				Context("SSOURL not filled in", func() {
+					BeforeEach(func() {
+						team.SAMLAuth.SSOURL = ""
+					})
+
+					It("returns a 400 Bad Request", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+					})
+				})
+
+				Context("IdPCertificate is not a certificate", func() {
+					BeforeEach(func() {
+						team.SAMLAuth.IdPCertificate = "bogus-cert-contents"
+					})
+
+					It("returns a 400 Bad Request", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+					})
+				})
+
+				Context("neither Groups nor Users filled in", func() {
+					BeforeEach(func() {
+						team.SAMLAuth.Users = nil
+					})
+
+					It("returns a 400 Bad Request", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+					})
+				})
+			})
+
 			Context("when there's a problem finding teams", func() {
 				BeforeEach(func() {
 					teamDB.GetTeamReturns(db.SavedTeam{}, false, errors.New("a dingo ate my baby!"))
@@ -666,6 +796,24 @@ var _ = Describe("Teams API", func() {
 						})
 					})
 
+					Context("when passed roles", func() {
+						BeforeEach(func() {
+							team.Roles = &atc.TeamRoles{
+								GitHubAuth: atc.RoleViewer,
+								UAAAuth:    atc.RoleOperator,
+							}
+						})
+
+						It("updates the roles for that team", func() {
+							Expect(response.StatusCode).To(Equal(http.StatusOK))
+							Expect(teamDB.UpdateRolesCallCount()).To(Equal(1))
+							Expect(teamDB.UpdateRolesArgsForCall(0)).To(Equal(atc.TeamRoles{
+								GitHubAuth: atc.RoleViewer,
+								UAAAuth:    atc.RoleOperator,
+							}))
+						})
+					})
+
 				})
 			})
 
@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
 	"github.com/concourse/atc/api/present"
 )
 
@@ -15,7 +16,7 @@ func (s *Server) ListTeams(w http.ResponseWriter, r *http.Request) {
 	savedTeams, err := s.teamsDB.GetTeams()
 	if err != nil {
 		hLog.Error("failed-to-get-teams", errors.New("sorry"))
-		w.WriteHeader(http.StatusInternalServerError)
+		apierror.DBFailure(w, "failed to get teams")
 	}
 
 	presentedTeams := make([]atc.Team, len(savedTeams))
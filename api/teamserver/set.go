@@ -5,8 +5,11 @@ import (
 	"encoding/json"
 	"encoding/pem"
 	"errors"
+	"fmt"
 	"net/http"
 
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
 	"github.com/concourse/atc/api/present"
 	"github.com/concourse/atc/auth"
 	"github.com/concourse/atc/db"
@@ -19,7 +22,7 @@ func (s *Server) SetTeam(w http.ResponseWriter, r *http.Request) {
 	authTeam, authTeamFound := auth.GetTeam(r)
 	if !authTeamFound {
 		hLog.Error("failed-to-get-team-from-auth", errors.New("failed-to-get-team-from-auth"))
-		w.WriteHeader(http.StatusInternalServerError)
+		apierror.Internal(w, "failed to get team from auth")
 		return
 	}
 
@@ -35,7 +38,7 @@ func (s *Server) SetTeam(w http.ResponseWriter, r *http.Request) {
 	}
 	team.Name = teamName
 	if !authTeam.IsAdmin() && !authTeam.IsAuthorized(teamName) {
-		w.WriteHeader(http.StatusForbidden)
+		apierror.Forbidden(w, "not authorized to modify team")
 		return
 	}
 
@@ -49,7 +52,7 @@ func (s *Server) SetTeam(w http.ResponseWriter, r *http.Request) {
 	savedTeam, found, err := teamDB.GetTeam()
 	if err != nil {
 		hLog.Error("failed-to-get-team", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		apierror.DBFailure(w, "failed to get team")
 		return
 	}
 
@@ -58,7 +61,7 @@ func (s *Server) SetTeam(w http.ResponseWriter, r *http.Request) {
 		err = s.updateCredentials(team, teamDB)
 		if err != nil {
 			hLog.Error("failed-to-update-team", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.DBFailure(w, "failed to update team")
 			return
 		}
 
@@ -69,12 +72,12 @@ func (s *Server) SetTeam(w http.ResponseWriter, r *http.Request) {
 		savedTeam, err = s.teamsDB.CreateTeam(team)
 		if err != nil {
 			hLog.Error("failed-to-save-team", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.DBFailure(w, "failed to save team")
 			return
 		}
 		w.WriteHeader(http.StatusCreated)
 	} else {
-		w.WriteHeader(http.StatusForbidden)
+		apierror.Forbidden(w, "only admins can create teams")
 		return
 	}
 
@@ -102,6 +105,21 @@ func (s *Server) updateCredentials(team db.Team, teamDB db.TeamDB) error {
 		return err
 	}
 
+	_, err = teamDB.UpdateOIDCAuth(team.OIDCAuth)
+	if err != nil {
+		return err
+	}
+
+	_, err = teamDB.UpdateSAMLAuth(team.SAMLAuth)
+	if err != nil {
+		return err
+	}
+
+	_, err = teamDB.UpdateRoles(team.Roles)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -166,5 +184,60 @@ func (s *Server) validate(team db.Team) error {
 		}
 	}
 
+	if team.OIDCAuth != nil {
+		if team.OIDCAuth.ClientID == "" || team.OIDCAuth.ClientSecret == "" {
+			return errors.New("OIDC auth requires ClientID and ClientSecret")
+		}
+
+		if team.OIDCAuth.Issuer == "" {
+			return errors.New("OIDC auth requires an Issuer")
+		}
+
+		if team.OIDCAuth.DisplayName == "" {
+			return errors.New("OIDC auth requires a Display Name")
+		}
+
+		if len(team.OIDCAuth.Groups) == 0 && len(team.OIDCAuth.Users) == 0 {
+			return errors.New("OIDC auth requires at least one Group or User")
+		}
+	}
+
+	if team.SAMLAuth != nil {
+		if team.SAMLAuth.SSOURL == "" {
+			return errors.New("SAML auth requires an SSO URL")
+		}
+
+		if team.SAMLAuth.DisplayName == "" {
+			return errors.New("SAML auth requires a Display Name")
+		}
+
+		block, _ := pem.Decode([]byte(team.SAMLAuth.IdPCertificate))
+		if block == nil {
+			return errors.New("SAML auth requires a PEM-encoded IdP certificate")
+		}
+
+		_, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return errors.New("SAML IdP certificate is invalid")
+		}
+
+		if len(team.SAMLAuth.Groups) == 0 && len(team.SAMLAuth.Users) == 0 {
+			return errors.New("SAML auth requires at least one Group or User")
+		}
+	}
+
+	for method, role := range map[string]atc.Role{
+		"basic auth":    team.Roles.BasicAuth,
+		"GitHub auth":   team.Roles.GitHubAuth,
+		"CF auth":       team.Roles.UAAAuth,
+		"Generic OAuth": team.Roles.GenericOAuth,
+		"OIDC auth":     team.Roles.OIDCAuth,
+		"SAML auth":     team.Roles.SAMLAuth,
+	} {
+		if role != "" && !role.IsValid() {
+			return fmt.Errorf("%s has an unknown role '%s'", method, role)
+		}
+	}
+
 	return nil
 }
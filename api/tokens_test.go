@@ -0,0 +1,328 @@
+package api_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/auth"
+	"github.com/concourse/atc/db"
+)
+
+var _ = Describe("API tokens API", func() {
+	Describe("POST /api/v1/teams/:team_name/tokens", func() {
+		var (
+			body     string
+			response *http.Response
+		)
+
+		BeforeEach(func() {
+			body = `{"name":"ci-bot","scopes":["read","trigger"]}`
+
+			apiTokenDB.CreateAPITokenStub = func(token db.APIToken) (db.APIToken, bool, error) {
+				token.ID = 7
+				token.CreatedAt = time.Unix(100, 0)
+				return token, true, nil
+			}
+
+			fakeTokenGenerator.GenerateAPITokenReturns("Bearer", "some-token", nil)
+		})
+
+		JustBeforeEach(func() {
+			request, err := http.NewRequest("POST", server.URL+"/api/v1/teams/some-team/tokens", bytes.NewBufferString(body))
+			Expect(err).NotTo(HaveOccurred())
+
+			response, err = client.Do(request)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("some-team", 42, true, true)
+			})
+
+			It("returns 201 with the token, which is never shown again", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusCreated))
+
+				responseBody, err := ioutil.ReadAll(response.Body)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(responseBody).To(MatchJSON(`{
+					"id": 7,
+					"name": "ci-bot",
+					"scopes": ["read", "trigger"],
+					"created_at": 100,
+					"token": "Bearer some-token"
+				}`))
+			})
+
+			It("saves the token for the team", func() {
+				Expect(apiTokenDB.CreateAPITokenCallCount()).To(Equal(1))
+				Expect(apiTokenDB.CreateAPITokenArgsForCall(0)).To(Equal(db.APIToken{
+					TeamID: 42,
+					Name:   "ci-bot",
+					Scopes: []string{"read", "trigger"},
+				}))
+			})
+
+			It("signs a token tied to the saved record", func() {
+				Expect(fakeTokenGenerator.GenerateAPITokenCallCount()).To(Equal(1))
+				tokenID, scopes, expiration, teamName, teamID, isAdmin, role := fakeTokenGenerator.GenerateAPITokenArgsForCall(0)
+				Expect(tokenID).To(Equal(7))
+				Expect(scopes).To(Equal([]auth.Scope{auth.ScopeRead, auth.ScopeTrigger}))
+				Expect(expiration).To(BeZero())
+				Expect(teamName).To(Equal("some-team"))
+				Expect(teamID).To(Equal(42))
+				Expect(isAdmin).To(BeTrue())
+				Expect(role).To(Equal(atc.RoleAdmin))
+			})
+
+			Context("when the creator has a role", func() {
+				BeforeEach(func() {
+					userContextReader.GetRoleReturns(atc.RoleViewer, true)
+				})
+
+				It("gives the token the same role", func() {
+					_, _, _, _, _, _, role := fakeTokenGenerator.GenerateAPITokenArgsForCall(0)
+					Expect(role).To(Equal(atc.RoleViewer))
+				})
+			})
+
+			Context("when an expiry is given", func() {
+				BeforeEach(func() {
+					body = `{"name":"ci-bot","scopes":["read"],"expires_in":"1h"}`
+				})
+
+				It("expires the token after it", func() {
+					token := apiTokenDB.CreateAPITokenArgsForCall(0)
+					Expect(token.ExpiresAt).To(BeTemporally("~", time.Now().Add(time.Hour), time.Minute))
+
+					_, _, expiration, _, _, _, _ := fakeTokenGenerator.GenerateAPITokenArgsForCall(0)
+					Expect(expiration).To(Equal(token.ExpiresAt))
+				})
+			})
+
+			Context("when the expiry is malformed", func() {
+				BeforeEach(func() {
+					body = `{"name":"ci-bot","scopes":["read"],"expires_in":"soon"}`
+				})
+
+				It("returns 400", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+					Expect(apiTokenDB.CreateAPITokenCallCount()).To(BeZero())
+				})
+			})
+
+			Context("when a scope is unknown", func() {
+				BeforeEach(func() {
+					body = `{"name":"ci-bot","scopes":["read","everything"]}`
+				})
+
+				It("returns 400", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+					Expect(apiTokenDB.CreateAPITokenCallCount()).To(BeZero())
+				})
+			})
+
+			Context("when no scopes are given", func() {
+				BeforeEach(func() {
+					body = `{"name":"ci-bot"}`
+				})
+
+				It("returns 400", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+				})
+			})
+
+			Context("when no name is given", func() {
+				BeforeEach(func() {
+					body = `{"scopes":["read"]}`
+				})
+
+				It("returns 400", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+				})
+			})
+
+			Context("when the team already has a token of that name", func() {
+				BeforeEach(func() {
+					apiTokenDB.CreateAPITokenStub = nil
+					apiTokenDB.CreateAPITokenReturns(db.APIToken{}, false, nil)
+				})
+
+				It("returns 409 without signing a token", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusConflict))
+					Expect(fakeTokenGenerator.GenerateAPITokenCallCount()).To(BeZero())
+				})
+			})
+
+			Context("when saving the token fails", func() {
+				BeforeEach(func() {
+					apiTokenDB.CreateAPITokenStub = nil
+					apiTokenDB.CreateAPITokenReturns(db.APIToken{}, false, errors.New("nope"))
+				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+
+		Context("when authenticated as another team", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("some-other-team", 5, false, true)
+			})
+
+			It("returns 403", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+				Expect(apiTokenDB.CreateAPITokenCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when not authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns 401", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+	})
+
+	Describe("GET /api/v1/teams/:team_name/tokens", func() {
+		var response *http.Response
+
+		JustBeforeEach(func() {
+			var err error
+			response, err = client.Get(server.URL + "/api/v1/teams/some-team/tokens")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("some-team", 42, false, true)
+
+				apiTokenDB.GetAPITokensReturns([]db.APIToken{
+					{
+						ID:        7,
+						TeamID:    42,
+						Name:      "ci-bot",
+						Scopes:    []string{"read"},
+						CreatedAt: time.Unix(100, 0),
+						ExpiresAt: time.Unix(200, 0),
+					},
+				}, nil)
+			})
+
+			It("returns the team's tokens, without the tokens themselves", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+				Expect(apiTokenDB.GetAPITokensArgsForCall(0)).To(Equal(42))
+
+				responseBody, err := ioutil.ReadAll(response.Body)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(responseBody).To(MatchJSON(`[{
+					"id": 7,
+					"name": "ci-bot",
+					"scopes": ["read"],
+					"created_at": 100,
+					"expires_at": 200
+				}]`))
+			})
+
+			Context("when getting the tokens fails", func() {
+				BeforeEach(func() {
+					apiTokenDB.GetAPITokensReturns(nil, errors.New("nope"))
+				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+
+		Context("when not authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns 401", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+	})
+
+	Describe("DELETE /api/v1/teams/:team_name/tokens/:token_id", func() {
+		var tokenID string
+		var response *http.Response
+
+		BeforeEach(func() {
+			tokenID = "7"
+		})
+
+		JustBeforeEach(func() {
+			request, err := http.NewRequest("DELETE", server.URL+"/api/v1/teams/some-team/tokens/"+tokenID, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			response, err = client.Do(request)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("some-team", 42, false, true)
+
+				apiTokenDB.RevokeAPITokenReturns(true, nil)
+			})
+
+			It("revokes the team's token", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusNoContent))
+
+				teamID, id := apiTokenDB.RevokeAPITokenArgsForCall(0)
+				Expect(teamID).To(Equal(42))
+				Expect(id).To(Equal(7))
+			})
+
+			Context("when the team has no such token", func() {
+				BeforeEach(func() {
+					apiTokenDB.RevokeAPITokenReturns(false, nil)
+				})
+
+				It("returns 404", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+				})
+			})
+
+			Context("when the token id is not a number", func() {
+				BeforeEach(func() {
+					tokenID = "ci-bot"
+				})
+
+				It("returns 400", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+					Expect(apiTokenDB.RevokeAPITokenCallCount()).To(BeZero())
+				})
+			})
+		})
+
+		Context("when not authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns 401", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+	})
+})
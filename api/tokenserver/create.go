@@ -0,0 +1,110 @@
+package tokenserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/api/present"
+	"github.com/concourse/atc/auth"
+	"github.com/concourse/atc/db"
+)
+
+func (s *Server) CreateAPIToken(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.Session("create-api-token")
+
+	var request atc.APITokenRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		logger.Info("malformed-request", lager.Data{"error": err.Error()})
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if request.Name == "" {
+		logger.Info("missing-name")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if len(request.Scopes) == 0 {
+		logger.Info("missing-scopes")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	scopes := make([]auth.Scope, len(request.Scopes))
+	for i, name := range request.Scopes {
+		scopes[i] = auth.Scope(name)
+
+		if !scopes[i].IsValid() {
+			logger.Info("unknown-scope", lager.Data{"scope": name})
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+
+	var expiresAt time.Time
+	if request.ExpiresIn != "" {
+		expiresIn, err := time.ParseDuration(request.ExpiresIn)
+		if err != nil || expiresIn <= 0 {
+			logger.Info("malformed-expires-in", lager.Data{"expires-in": request.ExpiresIn})
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		expiresAt = time.Now().Add(expiresIn)
+	}
+
+	authTeam, found := auth.GetTeam(r)
+	if !found {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	token, created, err := s.db.CreateAPIToken(db.APIToken{
+		TeamID:    authTeam.ID(),
+		Name:      request.Name,
+		Scopes:    request.Scopes,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		logger.Error("failed-to-create-api-token", err)
+		apierror.DBFailure(w, "failed to create api token")
+		return
+	}
+
+	if !created {
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+
+	// the token can do no more than whoever made it
+	role, _ := auth.GetRole(r)
+
+	tokenType, tokenValue, err := s.tokenGenerator.GenerateAPIToken(
+		token.ID,
+		scopes,
+		expiresAt,
+		authTeam.Name(),
+		authTeam.ID(),
+		authTeam.IsAdmin(),
+		role.OrAdmin(),
+	)
+	if err != nil {
+		logger.Error("failed-to-generate-api-token", err)
+		apierror.Internal(w, "failed to generate api token")
+		return
+	}
+
+	presented := present.APIToken(token)
+	presented.Token = string(tokenType) + " " + string(tokenValue)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	json.NewEncoder(w).Encode(presented)
+}
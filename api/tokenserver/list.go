@@ -0,0 +1,36 @@
+package tokenserver
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/api/present"
+	"github.com/concourse/atc/auth"
+)
+
+func (s *Server) ListAPITokens(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.Session("list-api-tokens")
+
+	authTeam, found := auth.GetTeam(r)
+	if !found {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	savedTokens, err := s.db.GetAPITokens(authTeam.ID())
+	if err != nil {
+		logger.Error("failed-to-get-api-tokens", err)
+		apierror.DBFailure(w, "failed to get api tokens")
+		return
+	}
+
+	tokens := make([]atc.APIToken, len(savedTokens))
+	for i, token := range savedTokens {
+		tokens[i] = present.APIToken(token)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tokens)
+}
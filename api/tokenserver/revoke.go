@@ -0,0 +1,39 @@
+package tokenserver
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/auth"
+)
+
+func (s *Server) RevokeAPIToken(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.Session("revoke-api-token")
+
+	tokenID, err := strconv.Atoi(r.FormValue(":token_id"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	authTeam, found := auth.GetTeam(r)
+	if !found {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	revoked, err := s.db.RevokeAPIToken(authTeam.ID(), tokenID)
+	if err != nil {
+		logger.Error("failed-to-revoke-api-token", err)
+		apierror.DBFailure(w, "failed to revoke api token")
+		return
+	}
+
+	if !revoked {
+		apierror.NotFound(w, "api token not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,34 @@
+package tokenserver
+
+import (
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/auth"
+	"github.com/concourse/atc/db"
+)
+
+type Server struct {
+	logger lager.Logger
+
+	db             APITokenDB
+	tokenGenerator auth.TokenGenerator
+}
+
+//go:generate counterfeiter . APITokenDB
+
+type APITokenDB interface {
+	CreateAPIToken(db.APIToken) (db.APIToken, bool, error)
+	GetAPITokens(teamID int) ([]db.APIToken, error)
+	RevokeAPIToken(teamID int, id int) (bool, error)
+}
+
+func NewServer(
+	logger lager.Logger,
+	db APITokenDB,
+	tokenGenerator auth.TokenGenerator,
+) *Server {
+	return &Server{
+		logger:         logger,
+		db:             db,
+		tokenGenerator: tokenGenerator,
+	}
+}
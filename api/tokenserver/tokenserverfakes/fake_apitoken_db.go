@@ -0,0 +1,173 @@
+// This file was generated by counterfeiter
+package tokenserverfakes
+
+import (
+	"sync"
+
+	"github.com/concourse/atc/api/tokenserver"
+	"github.com/concourse/atc/db"
+)
+
+type FakeAPITokenDB struct {
+	CreateAPITokenStub        func(arg1 db.APIToken) (db.APIToken, bool, error)
+	createAPITokenMutex       sync.RWMutex
+	createAPITokenArgsForCall []struct {
+		arg1 db.APIToken
+	}
+	createAPITokenReturns struct {
+		result1 db.APIToken
+		result2 bool
+		result3 error
+	}
+	GetAPITokensStub        func(teamID int) ([]db.APIToken, error)
+	getAPITokensMutex       sync.RWMutex
+	getAPITokensArgsForCall []struct {
+		teamID int
+	}
+	getAPITokensReturns struct {
+		result1 []db.APIToken
+		result2 error
+	}
+	RevokeAPITokenStub        func(teamID int, id int) (bool, error)
+	revokeAPITokenMutex       sync.RWMutex
+	revokeAPITokenArgsForCall []struct {
+		teamID int
+		id     int
+	}
+	revokeAPITokenReturns struct {
+		result1 bool
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeAPITokenDB) CreateAPIToken(arg1 db.APIToken) (db.APIToken, bool, error) {
+	fake.createAPITokenMutex.Lock()
+	fake.createAPITokenArgsForCall = append(fake.createAPITokenArgsForCall, struct {
+		arg1 db.APIToken
+	}{arg1})
+	fake.recordInvocation("CreateAPIToken", []interface{}{arg1})
+	fake.createAPITokenMutex.Unlock()
+	if fake.CreateAPITokenStub != nil {
+		return fake.CreateAPITokenStub(arg1)
+	} else {
+		return fake.createAPITokenReturns.result1, fake.createAPITokenReturns.result2, fake.createAPITokenReturns.result3
+	}
+}
+
+func (fake *FakeAPITokenDB) CreateAPITokenCallCount() int {
+	fake.createAPITokenMutex.RLock()
+	defer fake.createAPITokenMutex.RUnlock()
+	return len(fake.createAPITokenArgsForCall)
+}
+
+func (fake *FakeAPITokenDB) CreateAPITokenArgsForCall(i int) db.APIToken {
+	fake.createAPITokenMutex.RLock()
+	defer fake.createAPITokenMutex.RUnlock()
+	return fake.createAPITokenArgsForCall[i].arg1
+}
+
+func (fake *FakeAPITokenDB) CreateAPITokenReturns(result1 db.APIToken, result2 bool, result3 error) {
+	fake.CreateAPITokenStub = nil
+	fake.createAPITokenReturns = struct {
+		result1 db.APIToken
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeAPITokenDB) GetAPITokens(teamID int) ([]db.APIToken, error) {
+	fake.getAPITokensMutex.Lock()
+	fake.getAPITokensArgsForCall = append(fake.getAPITokensArgsForCall, struct {
+		teamID int
+	}{teamID})
+	fake.recordInvocation("GetAPITokens", []interface{}{teamID})
+	fake.getAPITokensMutex.Unlock()
+	if fake.GetAPITokensStub != nil {
+		return fake.GetAPITokensStub(teamID)
+	} else {
+		return fake.getAPITokensReturns.result1, fake.getAPITokensReturns.result2
+	}
+}
+
+func (fake *FakeAPITokenDB) GetAPITokensCallCount() int {
+	fake.getAPITokensMutex.RLock()
+	defer fake.getAPITokensMutex.RUnlock()
+	return len(fake.getAPITokensArgsForCall)
+}
+
+func (fake *FakeAPITokenDB) GetAPITokensArgsForCall(i int) int {
+	fake.getAPITokensMutex.RLock()
+	defer fake.getAPITokensMutex.RUnlock()
+	return fake.getAPITokensArgsForCall[i].teamID
+}
+
+func (fake *FakeAPITokenDB) GetAPITokensReturns(result1 []db.APIToken, result2 error) {
+	fake.GetAPITokensStub = nil
+	fake.getAPITokensReturns = struct {
+		result1 []db.APIToken
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPITokenDB) RevokeAPIToken(teamID int, id int) (bool, error) {
+	fake.revokeAPITokenMutex.Lock()
+	fake.revokeAPITokenArgsForCall = append(fake.revokeAPITokenArgsForCall, struct {
+		teamID int
+		id     int
+	}{teamID, id})
+	fake.recordInvocation("RevokeAPIToken", []interface{}{teamID, id})
+	fake.revokeAPITokenMutex.Unlock()
+	if fake.RevokeAPITokenStub != nil {
+		return fake.RevokeAPITokenStub(teamID, id)
+	} else {
+		return fake.revokeAPITokenReturns.result1, fake.revokeAPITokenReturns.result2
+	}
+}
+
+func (fake *FakeAPITokenDB) RevokeAPITokenCallCount() int {
+	fake.revokeAPITokenMutex.RLock()
+	defer fake.revokeAPITokenMutex.RUnlock()
+	return len(fake.revokeAPITokenArgsForCall)
+}
+
+func (fake *FakeAPITokenDB) RevokeAPITokenArgsForCall(i int) (int, int) {
+	fake.revokeAPITokenMutex.RLock()
+	defer fake.revokeAPITokenMutex.RUnlock()
+	return fake.revokeAPITokenArgsForCall[i].teamID, fake.revokeAPITokenArgsForCall[i].id
+}
+
+func (fake *FakeAPITokenDB) RevokeAPITokenReturns(result1 bool, result2 error) {
+	fake.RevokeAPITokenStub = nil
+	fake.revokeAPITokenReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPITokenDB) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.createAPITokenMutex.RLock()
+	defer fake.createAPITokenMutex.RUnlock()
+	fake.getAPITokensMutex.RLock()
+	defer fake.getAPITokensMutex.RUnlock()
+	fake.revokeAPITokenMutex.RLock()
+	defer fake.revokeAPITokenMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeAPITokenDB) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ tokenserver.APITokenDB = new(FakeAPITokenDB)
@@ -0,0 +1,187 @@
+package api_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/atc/db"
+)
+
+var _ = Describe("Usage API", func() {
+	Describe("GET /api/v1/usage", func() {
+		var (
+			query    string
+			response *http.Response
+		)
+
+		BeforeEach(func() {
+			query = ""
+
+			usageDB.GetUsageReturns([]db.Usage{
+				{
+					TeamName:          "some-team",
+					PipelineName:      "some-pipeline",
+					Start:             time.Date(2016, 1, 2, 0, 0, 0, 0, time.UTC),
+					Builds:            3,
+					BuildTime:         90 * time.Second,
+					WorkerTime:        150 * time.Second,
+					CPUTime:           45500 * time.Millisecond,
+					MemoryByteSeconds: 1024 * 90,
+				},
+				{
+					TeamName:   "some-team",
+					Start:      time.Date(2016, 1, 2, 0, 0, 0, 0, time.UTC),
+					Builds:     1,
+					BuildTime:  10 * time.Second,
+					WorkerTime: 10 * time.Second,
+				},
+			}, nil)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+
+			response, err = client.Get(server.URL + "/api/v1/usage" + query)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when not authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns 401", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+
+		Context("when authenticated but not admin", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+			})
+
+			It("returns 403 without getting the usage", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+				Expect(usageDB.GetUsageCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when authenticated as an admin", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("main", 1, true, true)
+			})
+
+			It("returns the usage", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+				Expect(response.Header.Get("Content-Type")).To(Equal("application/json"))
+
+				body, err := ioutil.ReadAll(response.Body)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(body).To(MatchJSON(`[
+					{
+						"team_name": "some-team",
+						"pipeline_name": "some-pipeline",
+						"start": 1451692800,
+						"builds": 3,
+						"build_seconds": 90,
+						"worker_seconds": 150,
+						"cpu_seconds": 45,
+						"memory_byte_seconds": 92160
+					},
+					{
+						"team_name": "some-team",
+						"start": 1451692800,
+						"builds": 1,
+						"build_seconds": 10,
+						"worker_seconds": 10,
+						"cpu_seconds": 0,
+						"memory_byte_seconds": 0
+					}
+				]`))
+			})
+
+			It("defaults to the last 30 days, a day at a time", func() {
+				Expect(usageDB.GetUsageCallCount()).To(Equal(1))
+				_, filter := usageDB.GetUsageArgsForCall(0)
+				Expect(filter.Until).To(BeTemporally("~", time.Now(), time.Minute))
+				Expect(filter.Since).To(Equal(filter.Until.Add(-30 * 24 * time.Hour)))
+				Expect(filter.Interval).To(Equal(db.UsageIntervalDay))
+				Expect(filter.TeamName).To(BeEmpty())
+			})
+
+			Context("when given a range, interval and team", func() {
+				BeforeEach(func() {
+					query = "?since=100&until=200&interval=month&team=some-team"
+				})
+
+				It("gets the usage for them", func() {
+					_, filter := usageDB.GetUsageArgsForCall(0)
+					Expect(filter).To(Equal(db.UsageFilter{
+						Since:    time.Unix(100, 0),
+						Until:    time.Unix(200, 0),
+						Interval: db.UsageIntervalMonth,
+						TeamName: "some-team",
+					}))
+				})
+			})
+
+			Context("when asked for CSV", func() {
+				BeforeEach(func() {
+					query = "?format=csv"
+				})
+
+				It("returns the usage as CSV", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+					Expect(response.Header.Get("Content-Type")).To(Equal("text/csv; charset=utf-8"))
+					Expect(response.Header.Get("Content-Disposition")).To(Equal(`attachment; filename="usage.csv"`))
+
+					body, err := ioutil.ReadAll(response.Body)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(string(body)).To(Equal(
+						"team,pipeline,start,builds,build_seconds,worker_seconds,cpu_seconds,memory_byte_seconds\n" +
+							"some-team,some-pipeline,2016-01-02T00:00:00Z,3,90,150,45,92160\n" +
+							"some-team,,2016-01-02T00:00:00Z,1,10,10,0,0\n",
+					))
+				})
+			})
+
+			for _, invalid := range []string{
+				"?since=yesterday",
+				"?until=now",
+				"?since=200&until=100",
+				"?interval=fortnight",
+			} {
+				invalid := invalid
+
+				Context("when given "+invalid, func() {
+					BeforeEach(func() {
+						query = invalid
+					})
+
+					It("returns 400", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+						Expect(usageDB.GetUsageCallCount()).To(BeZero())
+					})
+				})
+			}
+
+			Context("when getting the usage fails", func() {
+				BeforeEach(func() {
+					usageDB.GetUsageReturns(nil, errors.New("nope"))
+				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+	})
+})
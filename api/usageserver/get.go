@@ -0,0 +1,137 @@
+package usageserver
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/api/present"
+	"github.com/concourse/atc/db"
+)
+
+// DefaultUsagePeriod is how far back usage is reported from when no ?since=
+// is given.
+const DefaultUsagePeriod = 30 * 24 * time.Hour
+
+var usageCSVHeader = []string{
+	"team",
+	"pipeline",
+	"start",
+	"builds",
+	"build_seconds",
+	"worker_seconds",
+	"cpu_seconds",
+	"memory_byte_seconds",
+}
+
+// GetUsage reports what builds that finished between ?since= and ?until=
+// (both Unix times) used, for each team, pipeline and ?interval= (day, week
+// or month). It can be narrowed down to one ?team=, and is given as CSV with
+// ?format=csv.
+func (s *Server) GetUsage(w http.ResponseWriter, r *http.Request) {
+	logger := s.logger.Session("get-usage")
+
+	filter := db.UsageFilter{
+		Until:    time.Now(),
+		Interval: db.UsageIntervalDay,
+		TeamName: r.FormValue("team"),
+	}
+
+	if until := r.FormValue("until"); until != "" {
+		unix, err := strconv.ParseInt(until, 10, 64)
+		if err != nil {
+			http.Error(w, "malformed until", http.StatusBadRequest)
+			return
+		}
+
+		filter.Until = time.Unix(unix, 0)
+	}
+
+	filter.Since = filter.Until.Add(-DefaultUsagePeriod)
+
+	if since := r.FormValue("since"); since != "" {
+		unix, err := strconv.ParseInt(since, 10, 64)
+		if err != nil {
+			http.Error(w, "malformed since", http.StatusBadRequest)
+			return
+		}
+
+		filter.Since = time.Unix(unix, 0)
+	}
+
+	if !filter.Since.Before(filter.Until) {
+		http.Error(w, "since must be before until", http.StatusBadRequest)
+		return
+	}
+
+	switch interval := db.UsageInterval(r.FormValue("interval")); interval {
+	case "":
+	case db.UsageIntervalDay, db.UsageIntervalWeek, db.UsageIntervalMonth:
+		filter.Interval = interval
+	default:
+		http.Error(w, "interval must be day, week or month", http.StatusBadRequest)
+		return
+	}
+
+	usages, err := s.db.GetUsage(r.Context(), filter)
+	if err != nil {
+		logger.Error("failed-to-get-usage", err)
+		apierror.DBFailure(w, "failed to get usage")
+		return
+	}
+
+	presentedUsages := make([]atc.Usage, len(usages))
+	for i, usage := range usages {
+		presentedUsages[i] = present.Usage(usage)
+	}
+
+	if r.FormValue("format") == "csv" {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="usage.csv"`)
+		w.WriteHeader(http.StatusOK)
+
+		err := writeUsageCSV(w, presentedUsages)
+		if err != nil {
+			logger.Info("failed-to-write-csv", lager.Data{"error": err.Error()})
+		}
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(presentedUsages)
+}
+
+func writeUsageCSV(w http.ResponseWriter, usages []atc.Usage) error {
+	writer := csv.NewWriter(w)
+
+	err := writer.Write(usageCSVHeader)
+	if err != nil {
+		return err
+	}
+
+	for _, usage := range usages {
+		err := writer.Write([]string{
+			usage.TeamName,
+			usage.PipelineName,
+			time.Unix(usage.Start, 0).UTC().Format(time.RFC3339),
+			strconv.Itoa(usage.Builds),
+			strconv.FormatInt(usage.BuildSeconds, 10),
+			strconv.FormatInt(usage.WorkerSeconds, 10),
+			strconv.FormatInt(usage.CPUSeconds, 10),
+			strconv.FormatInt(usage.MemoryByteSeconds, 10),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
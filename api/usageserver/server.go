@@ -0,0 +1,30 @@
+package usageserver
+
+import (
+	"context"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/db"
+)
+
+//go:generate counterfeiter . UsageDB
+
+type UsageDB interface {
+	GetUsage(ctx context.Context, filter db.UsageFilter) ([]db.Usage, error)
+}
+
+type Server struct {
+	logger lager.Logger
+
+	db UsageDB
+}
+
+func NewServer(
+	logger lager.Logger,
+	db UsageDB,
+) *Server {
+	return &Server{
+		logger: logger,
+		db:     db,
+	}
+}
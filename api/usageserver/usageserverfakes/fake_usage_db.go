@@ -0,0 +1,82 @@
+// This file was generated by counterfeiter
+package usageserverfakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/concourse/atc/api/usageserver"
+	"github.com/concourse/atc/db"
+)
+
+type FakeUsageDB struct {
+	GetUsageStub        func(ctx context.Context, filter db.UsageFilter) ([]db.Usage, error)
+	getUsageMutex       sync.RWMutex
+	getUsageArgsForCall []struct {
+		ctx    context.Context
+		filter db.UsageFilter
+	}
+	getUsageReturns struct {
+		result1 []db.Usage
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeUsageDB) GetUsage(ctx context.Context, filter db.UsageFilter) ([]db.Usage, error) {
+	fake.getUsageMutex.Lock()
+	fake.getUsageArgsForCall = append(fake.getUsageArgsForCall, struct {
+		ctx    context.Context
+		filter db.UsageFilter
+	}{ctx, filter})
+	fake.recordInvocation("GetUsage", []interface{}{ctx, filter})
+	fake.getUsageMutex.Unlock()
+	if fake.GetUsageStub != nil {
+		return fake.GetUsageStub(ctx, filter)
+	} else {
+		return fake.getUsageReturns.result1, fake.getUsageReturns.result2
+	}
+}
+
+func (fake *FakeUsageDB) GetUsageCallCount() int {
+	fake.getUsageMutex.RLock()
+	defer fake.getUsageMutex.RUnlock()
+	return len(fake.getUsageArgsForCall)
+}
+
+func (fake *FakeUsageDB) GetUsageArgsForCall(i int) (context.Context, db.UsageFilter) {
+	fake.getUsageMutex.RLock()
+	defer fake.getUsageMutex.RUnlock()
+	return fake.getUsageArgsForCall[i].ctx, fake.getUsageArgsForCall[i].filter
+}
+
+func (fake *FakeUsageDB) GetUsageReturns(result1 []db.Usage, result2 error) {
+	fake.GetUsageStub = nil
+	fake.getUsageReturns = struct {
+		result1 []db.Usage
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeUsageDB) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.getUsageMutex.RLock()
+	defer fake.getUsageMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeUsageDB) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ usageserver.UsageDB = new(FakeUsageDB)
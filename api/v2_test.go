@@ -0,0 +1,120 @@
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/db/dbfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("V2 API", func() {
+	var response *http.Response
+
+	get := func(path string) {
+		request, err := http.NewRequest("GET", server.URL+path, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		request.Header.Set("Accept", atc.V2MediaType)
+
+		response, err = client.Do(request)
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	envelope := func() atc.Envelope {
+		var envelope atc.Envelope
+		err := json.NewDecoder(response.Body).Decode(&envelope)
+		Expect(err).NotTo(HaveOccurred())
+		return envelope
+	}
+
+	Describe("GET /api/v2/teams", func() {
+		BeforeEach(func() {
+			teamServerDB.GetTeamsReturns([]db.SavedTeam{
+				{ID: 5, Team: db.Team{Name: "avengers"}},
+				{ID: 9, Team: db.Team{Name: "aliens"}},
+			}, nil)
+		})
+
+		It("responds with the v1 teams in an envelope, a page at a time", func() {
+			get("/api/v2/teams?limit=1")
+
+			Expect(response.StatusCode).To(Equal(http.StatusOK))
+			Expect(response.Header.Get("Content-Type")).To(Equal(atc.V2MediaType))
+
+			body := envelope()
+			Expect(body.Data).To(MatchJSON(`[{"id":5,"name":"avengers"}]`))
+			Expect(body.Pagination).To(Equal(&atc.EnvelopePagination{
+				Next: externalURL + "/api/v2/teams?limit=1&offset=1",
+			}))
+		})
+
+		It("leaves the v1 response as it was", func() {
+			response, err := client.Get(server.URL + "/api/v1/teams")
+			Expect(err).NotTo(HaveOccurred())
+
+			body, err := ioutil.ReadAll(response.Body)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(response.Header.Get("Content-Type")).To(Equal("application/json"))
+			Expect(body).To(MatchJSON(`[{"id":5,"name":"avengers"},{"id":9,"name":"aliens"}]`))
+		})
+	})
+
+	Describe("GET /api/v2/builds", func() {
+		BeforeEach(func() {
+			build := new(dbfakes.FakeBuild)
+			build.IDReturns(3)
+			build.NameReturns("1")
+			build.JobNameReturns("job1")
+			build.PipelineNameReturns("pipeline1")
+			build.TeamNameReturns("some-team")
+			build.StatusReturns(db.StatusSucceeded)
+			build.StartTimeReturns(time.Unix(101, 0))
+			build.EndTimeReturns(time.Unix(200, 0))
+
+			buildServerDB.GetPublicBuildsReturns([]db.Build{build}, db.Pagination{
+				Next: &db.Page{Since: 3, Limit: 1},
+			}, nil)
+		})
+
+		It("adds how long each build took and links to the v2 pages", func() {
+			get("/api/v2/builds?limit=1")
+
+			Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+			body := envelope()
+
+			var builds []map[string]interface{}
+			err := json.Unmarshal(body.Data, &builds)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(builds).To(HaveLen(1))
+			Expect(builds[0]["id"]).To(BeNumerically("==", 3))
+			Expect(builds[0]["duration"]).To(BeNumerically("==", 99))
+
+			Expect(body.Pagination).To(Equal(&atc.EnvelopePagination{
+				Next: fmt.Sprintf("%s/api/v2/builds?since=3&limit=1", externalURL),
+			}))
+		})
+	})
+
+	Describe("GET /api/v2/teams/:team_name/pipelines/:pipeline_name", func() {
+		BeforeEach(func() {
+			authValidator.IsAuthenticatedReturns(false)
+			teamDB.GetPipelineByNameReturns(db.SavedPipeline{}, false, nil)
+		})
+
+		It("responds with the error in an envelope", func() {
+			get("/api/v2/teams/a-team/pipelines/a-pipeline")
+
+			Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+			Expect(envelope().Error.Code).To(Equal(atc.ErrorCodeNotFound))
+		})
+	})
+})
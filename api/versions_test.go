@@ -1,6 +1,7 @@
 package api_test
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -10,8 +11,10 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	"github.com/concourse/atc"
 	"github.com/concourse/atc/db"
 	"github.com/concourse/atc/db/dbfakes"
+	"github.com/vito/go-sse/sse"
 )
 
 var _ = Describe("Versions API", func() {
@@ -109,6 +112,19 @@ var _ = Describe("Versions API", func() {
 				})
 			})
 
+			Context("when the limit is more than the most that can be asked for", func() {
+				BeforeEach(func() {
+					queryParams = "?limit=100000"
+				})
+
+				It("asks for the most it can", func() {
+					Expect(pipelineDB.GetResourceVersionsCallCount()).To(Equal(1))
+
+					_, page := pipelineDB.GetResourceVersionsArgsForCall(0)
+					Expect(page.Limit).To(Equal(atc.PaginationAPIMaxLimit))
+				})
+			})
+
 			Context("when getting the versions succeeds", func() {
 				var returnedVersions []db.SavedVersionedResource
 
@@ -366,6 +382,82 @@ var _ = Describe("Versions API", func() {
 		})
 	})
 
+	Describe("PUT /api/v1/teams/:team_name/pipelines/:pipeline_name/resources/:resource_name/versions/:resource_version_id/pin", func() {
+		var response *http.Response
+
+		JustBeforeEach(func() {
+			var err error
+
+			request, err := http.NewRequest("PUT", server.URL+"/api/v1/teams/a-team/pipelines/a-pipeline/resources/resource-name/versions/42/pin", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			response, err = client.Do(request)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("a-team", 42, true, true)
+			})
+
+			It("injects the proper pipelineDB", func() {
+				Expect(teamDB.GetPipelineByNameCallCount()).To(Equal(1))
+				Expect(teamDB.GetPipelineByNameArgsForCall(0)).To(Equal("a-pipeline"))
+				Expect(pipelineDBFactory.BuildCallCount()).To(Equal(1))
+				actualSavedPipeline := pipelineDBFactory.BuildArgsForCall(0)
+				Expect(actualSavedPipeline).To(Equal(expectedSavedPipeline))
+			})
+
+			Context("when pinning the resource succeeds", func() {
+				BeforeEach(func() {
+					pipelineDB.PinResourceVersionReturns(true, nil)
+				})
+
+				It("pins the resource to the right version", func() {
+					Expect(pipelineDB.PinResourceVersionCallCount()).To(Equal(1))
+					resourceName, versionID := pipelineDB.PinResourceVersionArgsForCall(0)
+					Expect(resourceName).To(Equal("resource-name"))
+					Expect(versionID).To(Equal(42))
+				})
+
+				It("returns 200", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+				})
+			})
+
+			Context("when the version is not a version of the resource", func() {
+				BeforeEach(func() {
+					pipelineDB.PinResourceVersionReturns(false, nil)
+				})
+
+				It("returns 404", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+				})
+			})
+
+			Context("when pinning the resource fails", func() {
+				BeforeEach(func() {
+					pipelineDB.PinResourceVersionReturns(false, errors.New("welp"))
+				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+
+		Context("when not authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns Unauthorized", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+	})
+
 	Describe("GET /api/v1/teams/:team_name/pipelines/:pipeline_name/resources/:resource_name/versions/:resource_version_id/input_to", func() {
 		var response *http.Response
 		var stringVersionID string
@@ -659,4 +751,395 @@ var _ = Describe("Versions API", func() {
 			})
 		})
 	})
+
+	Describe("GET /api/v1/teams/:team_name/pipelines/:pipeline_name/resources/:resource_name/versions/:resource_version_id/causality", func() {
+		var response *http.Response
+		var stringVersionID string
+
+		BeforeEach(func() {
+			stringVersionID = "123"
+		})
+
+		JustBeforeEach(func() {
+			var err error
+
+			request, err := http.NewRequest("GET", server.URL+"/api/v1/teams/a-team/pipelines/a-pipeline/resources/some-resource/versions/"+stringVersionID+"/causality", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			response, err = client.Do(request)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when not authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+				userContextReader.GetTeamReturns("", 0, false, false)
+				pipelineDB.IsPublicReturns(true)
+			})
+
+			It("returns 401, even if the pipeline is public", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+
+		Context("when authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("a-team", 1, true, true)
+			})
+
+			It("looks up the causality of the given version ID", func() {
+				Expect(pipelineDB.GetVersionCausalityCallCount()).To(Equal(1))
+				Expect(pipelineDB.GetVersionCausalityArgsForCall(0)).To(Equal(123))
+			})
+
+			Context("when the version has downstream builds", func() {
+				BeforeEach(func() {
+					build := new(dbfakes.FakeBuild)
+					build.IDReturns(1024)
+					build.NameReturns("5")
+					build.JobNameReturns("some-job")
+					build.PipelineNameReturns("a-pipeline")
+					build.TeamNameReturns("a-team")
+					build.StatusReturns(db.StatusSucceeded)
+					build.StartTimeReturns(time.Unix(1, 0))
+					build.EndTimeReturns(time.Unix(100, 0))
+
+					pipelineDB.GetVersionCausalityReturns(db.Causality{
+						Versions: []db.SavedVersionedResource{
+							{
+								ID:      123,
+								Enabled: true,
+								VersionedResource: db.VersionedResource{
+									Resource:   "some-resource",
+									Type:       "git",
+									Version:    db.Version{"ref": "abc123"},
+									PipelineID: 1,
+								},
+							},
+							{
+								ID:      456,
+								Enabled: true,
+								VersionedResource: db.VersionedResource{
+									Resource:   "some-output",
+									Type:       "s3",
+									Version:    db.Version{"path": "some-artifact.tgz"},
+									PipelineID: 1,
+								},
+							},
+						},
+						Builds: []db.Build{build},
+						Edges: []db.CausalityEdge{
+							{Type: db.CausalityInput, VersionID: 123, BuildID: 1024},
+							{Type: db.CausalityOutput, VersionID: 456, BuildID: 1024},
+						},
+						Truncated: true,
+					}, true, nil)
+				})
+
+				It("returns 200 OK", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+				})
+
+				It("returns content type application/json", func() {
+					Expect(response.Header.Get("Content-type")).To(Equal("application/json"))
+				})
+
+				It("returns the graph", func() {
+					body, err := ioutil.ReadAll(response.Body)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(body).To(MatchJSON(`{
+						"versions": [
+							{
+								"id": 123,
+								"pipeline_id": 1,
+								"resource": "some-resource",
+								"type": "git",
+								"enabled": true,
+								"metadata": null,
+								"version": {"ref": "abc123"}
+							},
+							{
+								"id": 456,
+								"pipeline_id": 1,
+								"resource": "some-output",
+								"type": "s3",
+								"enabled": true,
+								"metadata": null,
+								"version": {"path": "some-artifact.tgz"}
+							}
+						],
+						"builds": [
+							{
+								"id": 1024,
+								"team_name": "a-team",
+								"name": "5",
+								"status": "succeeded",
+								"job_name": "some-job",
+								"url": "/teams/a-team/pipelines/a-pipeline/jobs/some-job/builds/5",
+								"api_url": "/api/v1/builds/1024",
+								"pipeline_name": "a-pipeline",
+								"start_time": 1,
+								"end_time": 100
+							}
+						],
+						"edges": [
+							{"type": "input", "version_id": 123, "build_id": 1024},
+							{"type": "output", "version_id": 456, "build_id": 1024}
+						],
+						"truncated": true
+					}`))
+				})
+			})
+
+			Context("when the version is not found", func() {
+				BeforeEach(func() {
+					pipelineDB.GetVersionCausalityReturns(db.Causality{}, false, nil)
+				})
+
+				It("returns 404", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+				})
+			})
+
+			Context("when the version ID is invalid", func() {
+				BeforeEach(func() {
+					stringVersionID = "hello"
+				})
+
+				It("returns 400", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+				})
+
+				It("does not look anything up", func() {
+					Expect(pipelineDB.GetVersionCausalityCallCount()).To(BeZero())
+				})
+			})
+
+			Context("when looking up the causality fails", func() {
+				BeforeEach(func() {
+					pipelineDB.GetVersionCausalityReturns(db.Causality{}, false, errors.New("NOPE"))
+				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+	})
+
+	Describe("GET /api/v1/teams/:team_name/pipelines/:pipeline_name/resources/:resource_name/versions/passed", func() {
+		var response *http.Response
+		var jobs string
+
+		BeforeEach(func() {
+			jobs = "unit,integration"
+		})
+
+		JustBeforeEach(func() {
+			var err error
+
+			response, err = client.Get(server.URL + "/api/v1/teams/a-team/pipelines/a-pipeline/resources/some-resource/versions/passed?jobs=" + jobs)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when not authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			Context("and the pipeline is private", func() {
+				BeforeEach(func() {
+					pipelineDB.IsPublicReturns(false)
+				})
+
+				It("returns 401", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+				})
+			})
+
+			Context("and the pipeline is public", func() {
+				BeforeEach(func() {
+					pipelineDB.IsPublicReturns(true)
+					pipelineDB.GetVersionsPassedJobsReturns([]db.SavedVersionedResource{}, true, nil)
+				})
+
+				It("returns 200 OK", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+				})
+			})
+		})
+
+		Context("when authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("a-team", 1, true, true)
+			})
+
+			Context("when versions have passed the jobs", func() {
+				BeforeEach(func() {
+					pipelineDB.GetVersionsPassedJobsReturns([]db.SavedVersionedResource{
+						{
+							ID:      2,
+							Enabled: true,
+							VersionedResource: db.VersionedResource{
+								Resource:   "some-resource",
+								Type:       "some-type",
+								Version:    db.Version{"ref": "def"},
+								PipelineID: 42,
+							},
+						},
+						{
+							ID:      1,
+							Enabled: true,
+							VersionedResource: db.VersionedResource{
+								Resource:   "some-resource",
+								Type:       "some-type",
+								Version:    db.Version{"ref": "abc"},
+								PipelineID: 42,
+							},
+						},
+					}, true, nil)
+				})
+
+				It("looks them up for the given jobs", func() {
+					Expect(pipelineDB.GetVersionsPassedJobsCallCount()).To(Equal(1))
+
+					resourceName, jobNames := pipelineDB.GetVersionsPassedJobsArgsForCall(0)
+					Expect(resourceName).To(Equal("some-resource"))
+					Expect(jobNames).To(Equal([]string{"unit", "integration"}))
+				})
+
+				It("returns them", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+					Expect(response.Header.Get("Content-Type")).To(Equal("application/json"))
+
+					body, err := ioutil.ReadAll(response.Body)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(body).To(MatchJSON(`[
+						{
+							"id": 2,
+							"pipeline_id": 42,
+							"type": "some-type",
+							"metadata": null,
+							"resource": "some-resource",
+							"version": {"ref": "def"},
+							"enabled": true
+						},
+						{
+							"id": 1,
+							"pipeline_id": 42,
+							"type": "some-type",
+							"metadata": null,
+							"resource": "some-resource",
+							"version": {"ref": "abc"},
+							"enabled": true
+						}
+					]`))
+				})
+			})
+
+			Context("when no jobs are given", func() {
+				BeforeEach(func() {
+					jobs = ""
+				})
+
+				It("returns 400", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+				})
+			})
+
+			Context("when the resource or a job doesn't exist", func() {
+				BeforeEach(func() {
+					pipelineDB.GetVersionsPassedJobsReturns(nil, false, nil)
+				})
+
+				It("returns 404", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+				})
+			})
+
+			Context("when looking them up fails", func() {
+				BeforeEach(func() {
+					pipelineDB.GetVersionsPassedJobsReturns(nil, false, errors.New("nope"))
+				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+	})
+
+	Describe("GET /api/v1/teams/:team_name/pipelines/:pipeline_name/resources/:resource_name/versions/passed/events", func() {
+		var response *http.Response
+
+		BeforeEach(func() {
+			authValidator.IsAuthenticatedReturns(true)
+			userContextReader.GetTeamReturns("a-team", 1, true, true)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+
+			response, err = client.Get(server.URL + "/api/v1/teams/a-team/pipelines/a-pipeline/resources/some-resource/versions/passed/events?jobs=unit")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when versions have already passed the jobs", func() {
+			BeforeEach(func() {
+				pipelineDB.GetVersionsPassedJobsReturns([]db.SavedVersionedResource{
+					{
+						ID: 2,
+						VersionedResource: db.VersionedResource{
+							Resource: "some-resource",
+							Version:  db.Version{"ref": "def"},
+						},
+					},
+					{
+						ID: 1,
+						VersionedResource: db.VersionedResource{
+							Resource: "some-resource",
+							Version:  db.Version{"ref": "abc"},
+						},
+					},
+				}, true, nil)
+			})
+
+			It("streams them oldest first", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+				Expect(response.Header.Get("Content-Type")).To(Equal("text/event-stream; charset=utf-8"))
+
+				reader := sse.NewReadCloser(response.Body)
+				defer reader.Close()
+
+				ev, err := reader.Next()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ev.ID).To(Equal("1"))
+				Expect(ev.Name).To(Equal("passed"))
+
+				var passed atc.PassedVersion
+				err = json.Unmarshal(ev.Data, &passed)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(passed.Version.Version).To(Equal(atc.Version{"ref": "abc"}))
+				Expect(passed.Passed).To(Equal([]string{"unit"}))
+
+				ev, err = reader.Next()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ev.ID).To(Equal("2"))
+			})
+		})
+
+		Context("when the resource or a job doesn't exist", func() {
+			BeforeEach(func() {
+				pipelineDB.GetVersionsPassedJobsReturns(nil, false, nil)
+			})
+
+			It("returns 404", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+			})
+		})
+	})
 })
@@ -6,6 +6,7 @@ import (
 
 	"code.cloudfoundry.org/lager"
 	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
 	"github.com/concourse/atc/api/present"
 	"github.com/concourse/atc/db"
 )
@@ -19,7 +20,7 @@ func (s *Server) ListVolumes(teamDB db.TeamDB) http.Handler {
 		volumes, err := teamDB.GetVolumes()
 		if err != nil {
 			hLog.Error("failed-to-find-volumes", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.DBFailure(w, "failed to find volumes")
 			return
 		}
 
@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
 	"github.com/concourse/atc/api/present"
 	"github.com/concourse/atc/db"
 )
@@ -16,7 +17,7 @@ func (s *Server) ListWorkers(teamDB db.TeamDB) http.Handler {
 		savedWorkers, err := teamDB.Workers()
 		if err != nil {
 			logger.Error("failed-to-get-workers", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.DBFailure(w, "failed to get workers")
 			return
 		}
 
@@ -10,6 +10,7 @@ import (
 
 	"code.cloudfoundry.org/lager"
 	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
 	"github.com/concourse/atc/db"
 	"github.com/concourse/atc/metric"
 )
@@ -27,7 +28,7 @@ func (s *Server) RegisterWorker(w http.ResponseWriter, r *http.Request) {
 	isSystem, present := r.Context().Value("system").(bool)
 
 	if !present || !isSystem {
-		w.WriteHeader(http.StatusForbidden)
+		apierror.Forbidden(w, "only system tokens can register workers")
 		return
 	}
 
@@ -42,7 +43,7 @@ func (s *Server) RegisterWorker(w http.ResponseWriter, r *http.Request) {
 		team, found, err := s.teamDBFactory.GetTeamDB(registration.Team).GetTeam()
 		if err != nil {
 			logger.Error("failed-to-get-team", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			apierror.DBFailure(w, "failed to get team")
 			return
 		}
 
@@ -97,7 +98,7 @@ func (s *Server) RegisterWorker(w http.ResponseWriter, r *http.Request) {
 	}, ttl)
 	if err != nil {
 		logger.Error("failed-to-save-worker", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		apierror.DBFailure(w, "failed to save worker")
 		return
 	}
 
@@ -0,0 +1,26 @@
+package atc
+
+type ErrorCode string
+
+const (
+	ErrorCodeNotFound       ErrorCode = "not-found"
+	ErrorCodeUnauthorized   ErrorCode = "unauthorized"
+	ErrorCodeForbidden      ErrorCode = "forbidden"
+	ErrorCodeDBFailure      ErrorCode = "db-failure"
+	ErrorCodeBuilderFailure ErrorCode = "builder-failure"
+	ErrorCodeInternal       ErrorCode = "internal"
+	ErrorCodeRateLimited    ErrorCode = "rate-limited"
+	ErrorCodeBadRequest     ErrorCode = "bad-request"
+	ErrorCodeNotAcceptable  ErrorCode = "not-acceptable"
+)
+
+// APIError is the body of every error response returned by the API.
+type APIError struct {
+	Code    ErrorCode         `json:"code"`
+	Message string            `json:"message"`
+	Details map[string]string `json:"details,omitempty"`
+}
+
+func (err APIError) Error() string {
+	return string(err.Code) + ": " + err.Message
+}
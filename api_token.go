@@ -0,0 +1,20 @@
+package atc
+
+// APITokenRequest asks for a new API token. ExpiresIn is a duration such as
+// "720h"; the token never expires if it is empty.
+type APITokenRequest struct {
+	Name      string   `json:"name"`
+	Scopes    []string `json:"scopes"`
+	ExpiresIn string   `json:"expires_in,omitempty"`
+}
+
+type APIToken struct {
+	ID        int      `json:"id"`
+	Name      string   `json:"name"`
+	Scopes    []string `json:"scopes"`
+	CreatedAt int64    `json:"created_at"`
+	ExpiresAt int64    `json:"expires_at,omitempty"`
+
+	// Token is only ever returned when the token is created.
+	Token string `json:"token,omitempty"`
+}
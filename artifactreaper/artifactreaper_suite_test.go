@@ -0,0 +1,13 @@
+package artifactreaper_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestArtifactreaper(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Artifact Reaper Suite")
+}
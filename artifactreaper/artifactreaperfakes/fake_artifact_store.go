@@ -0,0 +1,78 @@
+// This file was generated by counterfeiter
+package artifactreaperfakes
+
+import (
+	"sync"
+
+	"github.com/concourse/atc/artifactreaper"
+)
+
+type FakeArtifactStore struct {
+	DeleteStub        func(buildID int, name string) error
+	deleteMutex       sync.RWMutex
+	deleteArgsForCall []struct {
+		buildID int
+		name    string
+	}
+	deleteReturns struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeArtifactStore) Delete(buildID int, name string) error {
+	fake.deleteMutex.Lock()
+	fake.deleteArgsForCall = append(fake.deleteArgsForCall, struct {
+		buildID int
+		name    string
+	}{buildID, name})
+	fake.recordInvocation("Delete", []interface{}{buildID, name})
+	fake.deleteMutex.Unlock()
+	if fake.DeleteStub != nil {
+		return fake.DeleteStub(buildID, name)
+	} else {
+		return fake.deleteReturns.result1
+	}
+}
+
+func (fake *FakeArtifactStore) DeleteCallCount() int {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	return len(fake.deleteArgsForCall)
+}
+
+func (fake *FakeArtifactStore) DeleteArgsForCall(i int) (int, string) {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	return fake.deleteArgsForCall[i].buildID, fake.deleteArgsForCall[i].name
+}
+
+func (fake *FakeArtifactStore) DeleteReturns(result1 error) {
+	fake.DeleteStub = nil
+	fake.deleteReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeArtifactStore) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeArtifactStore) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ artifactreaper.ArtifactStore = new(FakeArtifactStore)
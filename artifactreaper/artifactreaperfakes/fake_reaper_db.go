@@ -0,0 +1,243 @@
+// This file was generated by counterfeiter
+package artifactreaperfakes
+
+import (
+	"sync"
+
+	"github.com/concourse/atc/artifactreaper"
+	"github.com/concourse/atc/db"
+)
+
+type FakeReaperDB struct {
+	GetAllPipelinesStub        func() ([]db.SavedPipeline, error)
+	getAllPipelinesMutex       sync.RWMutex
+	getAllPipelinesArgsForCall []struct{}
+	getAllPipelinesReturns     struct {
+		result1 []db.SavedPipeline
+		result2 error
+	}
+	GetContainerHandlesForBuildStub        func(buildID int) ([]string, error)
+	getContainerHandlesForBuildMutex       sync.RWMutex
+	getContainerHandlesForBuildArgsForCall []struct {
+		buildID int
+	}
+	getContainerHandlesForBuildReturns struct {
+		result1 []string
+		result2 error
+	}
+	DeleteContainerStub        func(handle string) error
+	deleteContainerMutex       sync.RWMutex
+	deleteContainerArgsForCall []struct {
+		handle string
+	}
+	deleteContainerReturns struct {
+		result1 error
+	}
+	ReapContainerStub        func(handle string) error
+	reapContainerMutex       sync.RWMutex
+	reapContainerArgsForCall []struct {
+		handle string
+	}
+	reapContainerReturns struct {
+		result1 error
+	}
+	MarkBuildArtifactsReapedStub        func(buildID int) error
+	markBuildArtifactsReapedMutex       sync.RWMutex
+	markBuildArtifactsReapedArgsForCall []struct {
+		buildID int
+	}
+	markBuildArtifactsReapedReturns struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeReaperDB) GetAllPipelines() ([]db.SavedPipeline, error) {
+	fake.getAllPipelinesMutex.Lock()
+	fake.getAllPipelinesArgsForCall = append(fake.getAllPipelinesArgsForCall, struct{}{})
+	fake.recordInvocation("GetAllPipelines", []interface{}{})
+	fake.getAllPipelinesMutex.Unlock()
+	if fake.GetAllPipelinesStub != nil {
+		return fake.GetAllPipelinesStub()
+	} else {
+		return fake.getAllPipelinesReturns.result1, fake.getAllPipelinesReturns.result2
+	}
+}
+
+func (fake *FakeReaperDB) GetAllPipelinesCallCount() int {
+	fake.getAllPipelinesMutex.RLock()
+	defer fake.getAllPipelinesMutex.RUnlock()
+	return len(fake.getAllPipelinesArgsForCall)
+}
+
+func (fake *FakeReaperDB) GetAllPipelinesReturns(result1 []db.SavedPipeline, result2 error) {
+	fake.GetAllPipelinesStub = nil
+	fake.getAllPipelinesReturns = struct {
+		result1 []db.SavedPipeline
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeReaperDB) GetContainerHandlesForBuild(buildID int) ([]string, error) {
+	fake.getContainerHandlesForBuildMutex.Lock()
+	fake.getContainerHandlesForBuildArgsForCall = append(fake.getContainerHandlesForBuildArgsForCall, struct {
+		buildID int
+	}{buildID})
+	fake.recordInvocation("GetContainerHandlesForBuild", []interface{}{buildID})
+	fake.getContainerHandlesForBuildMutex.Unlock()
+	if fake.GetContainerHandlesForBuildStub != nil {
+		return fake.GetContainerHandlesForBuildStub(buildID)
+	} else {
+		return fake.getContainerHandlesForBuildReturns.result1, fake.getContainerHandlesForBuildReturns.result2
+	}
+}
+
+func (fake *FakeReaperDB) GetContainerHandlesForBuildCallCount() int {
+	fake.getContainerHandlesForBuildMutex.RLock()
+	defer fake.getContainerHandlesForBuildMutex.RUnlock()
+	return len(fake.getContainerHandlesForBuildArgsForCall)
+}
+
+func (fake *FakeReaperDB) GetContainerHandlesForBuildArgsForCall(i int) int {
+	fake.getContainerHandlesForBuildMutex.RLock()
+	defer fake.getContainerHandlesForBuildMutex.RUnlock()
+	return fake.getContainerHandlesForBuildArgsForCall[i].buildID
+}
+
+func (fake *FakeReaperDB) GetContainerHandlesForBuildReturns(result1 []string, result2 error) {
+	fake.GetContainerHandlesForBuildStub = nil
+	fake.getContainerHandlesForBuildReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeReaperDB) DeleteContainer(handle string) error {
+	fake.deleteContainerMutex.Lock()
+	fake.deleteContainerArgsForCall = append(fake.deleteContainerArgsForCall, struct {
+		handle string
+	}{handle})
+	fake.recordInvocation("DeleteContainer", []interface{}{handle})
+	fake.deleteContainerMutex.Unlock()
+	if fake.DeleteContainerStub != nil {
+		return fake.DeleteContainerStub(handle)
+	} else {
+		return fake.deleteContainerReturns.result1
+	}
+}
+
+func (fake *FakeReaperDB) DeleteContainerCallCount() int {
+	fake.deleteContainerMutex.RLock()
+	defer fake.deleteContainerMutex.RUnlock()
+	return len(fake.deleteContainerArgsForCall)
+}
+
+func (fake *FakeReaperDB) DeleteContainerArgsForCall(i int) string {
+	fake.deleteContainerMutex.RLock()
+	defer fake.deleteContainerMutex.RUnlock()
+	return fake.deleteContainerArgsForCall[i].handle
+}
+
+func (fake *FakeReaperDB) DeleteContainerReturns(result1 error) {
+	fake.DeleteContainerStub = nil
+	fake.deleteContainerReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeReaperDB) ReapContainer(handle string) error {
+	fake.reapContainerMutex.Lock()
+	fake.reapContainerArgsForCall = append(fake.reapContainerArgsForCall, struct {
+		handle string
+	}{handle})
+	fake.recordInvocation("ReapContainer", []interface{}{handle})
+	fake.reapContainerMutex.Unlock()
+	if fake.ReapContainerStub != nil {
+		return fake.ReapContainerStub(handle)
+	} else {
+		return fake.reapContainerReturns.result1
+	}
+}
+
+func (fake *FakeReaperDB) ReapContainerCallCount() int {
+	fake.reapContainerMutex.RLock()
+	defer fake.reapContainerMutex.RUnlock()
+	return len(fake.reapContainerArgsForCall)
+}
+
+func (fake *FakeReaperDB) ReapContainerArgsForCall(i int) string {
+	fake.reapContainerMutex.RLock()
+	defer fake.reapContainerMutex.RUnlock()
+	return fake.reapContainerArgsForCall[i].handle
+}
+
+func (fake *FakeReaperDB) ReapContainerReturns(result1 error) {
+	fake.ReapContainerStub = nil
+	fake.reapContainerReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeReaperDB) MarkBuildArtifactsReaped(buildID int) error {
+	fake.markBuildArtifactsReapedMutex.Lock()
+	fake.markBuildArtifactsReapedArgsForCall = append(fake.markBuildArtifactsReapedArgsForCall, struct {
+		buildID int
+	}{buildID})
+	fake.recordInvocation("MarkBuildArtifactsReaped", []interface{}{buildID})
+	fake.markBuildArtifactsReapedMutex.Unlock()
+	if fake.MarkBuildArtifactsReapedStub != nil {
+		return fake.MarkBuildArtifactsReapedStub(buildID)
+	} else {
+		return fake.markBuildArtifactsReapedReturns.result1
+	}
+}
+
+func (fake *FakeReaperDB) MarkBuildArtifactsReapedCallCount() int {
+	fake.markBuildArtifactsReapedMutex.RLock()
+	defer fake.markBuildArtifactsReapedMutex.RUnlock()
+	return len(fake.markBuildArtifactsReapedArgsForCall)
+}
+
+func (fake *FakeReaperDB) MarkBuildArtifactsReapedArgsForCall(i int) int {
+	fake.markBuildArtifactsReapedMutex.RLock()
+	defer fake.markBuildArtifactsReapedMutex.RUnlock()
+	return fake.markBuildArtifactsReapedArgsForCall[i].buildID
+}
+
+func (fake *FakeReaperDB) MarkBuildArtifactsReapedReturns(result1 error) {
+	fake.MarkBuildArtifactsReapedStub = nil
+	fake.markBuildArtifactsReapedReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeReaperDB) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.getAllPipelinesMutex.RLock()
+	defer fake.getAllPipelinesMutex.RUnlock()
+	fake.getContainerHandlesForBuildMutex.RLock()
+	defer fake.getContainerHandlesForBuildMutex.RUnlock()
+	fake.deleteContainerMutex.RLock()
+	defer fake.deleteContainerMutex.RUnlock()
+	fake.reapContainerMutex.RLock()
+	defer fake.reapContainerMutex.RUnlock()
+	fake.markBuildArtifactsReapedMutex.RLock()
+	defer fake.markBuildArtifactsReapedMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeReaperDB) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ artifactreaper.ReaperDB = new(FakeReaperDB)
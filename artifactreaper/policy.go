@@ -0,0 +1,51 @@
+package artifactreaper
+
+import (
+	"time"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+)
+
+// Expired returns the builds, given newest first, whose containers and
+// artifacts the policy no longer keeps.
+func Expired(policy atc.ArtifactRetentionConfig, builds []db.ArtifactBuild, now time.Time) []db.ArtifactBuild {
+	expired := []db.ArtifactBuild{}
+	kept := 0
+
+	for _, build := range builds {
+		if build.Protected {
+			continue
+		}
+
+		if policy.SucceededOnly && build.Status != db.StatusSucceeded {
+			expired = append(expired, build)
+			continue
+		}
+
+		if keeps(policy, kept, build, now) {
+			kept++
+			continue
+		}
+
+		expired = append(expired, build)
+	}
+
+	return expired
+}
+
+func keeps(policy atc.ArtifactRetentionConfig, kept int, build db.ArtifactBuild, now time.Time) bool {
+	if policy.Builds == 0 && policy.Days == 0 {
+		return true
+	}
+
+	if kept < policy.Builds {
+		return true
+	}
+
+	if policy.Days > 0 && now.Sub(build.EndTime) < time.Duration(policy.Days)*24*time.Hour {
+		return true
+	}
+
+	return false
+}
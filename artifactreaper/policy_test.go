@@ -0,0 +1,87 @@
+package artifactreaper_test
+
+import (
+	"time"
+
+	"github.com/concourse/atc"
+	. "github.com/concourse/atc/artifactreaper"
+	"github.com/concourse/atc/db"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Expired", func() {
+	var (
+		now    time.Time
+		policy atc.ArtifactRetentionConfig
+		builds []db.ArtifactBuild
+	)
+
+	BeforeEach(func() {
+		now = time.Unix(123456789, 0)
+		policy = atc.ArtifactRetentionConfig{}
+
+		builds = []db.ArtifactBuild{
+			{ID: 5, Status: db.StatusSucceeded, EndTime: now.Add(-time.Hour)},
+			{ID: 4, Status: db.StatusFailed, EndTime: now.Add(-25 * time.Hour)},
+			{ID: 3, Status: db.StatusSucceeded, EndTime: now.Add(-49 * time.Hour)},
+			{ID: 2, Status: db.StatusSucceeded, EndTime: now.Add(-73 * time.Hour)},
+			{ID: 1, Status: db.StatusErrored, EndTime: now.Add(-97 * time.Hour)},
+		}
+	})
+
+	expiredIDs := func() []int {
+		ids := []int{}
+		for _, build := range Expired(policy, builds, now) {
+			ids = append(ids, build.ID)
+		}
+
+		return ids
+	}
+
+	It("keeps every build with an empty policy", func() {
+		Expect(expiredIDs()).To(BeEmpty())
+	})
+
+	It("keeps the last builds", func() {
+		policy.Builds = 2
+		Expect(expiredIDs()).To(Equal([]int{3, 2, 1}))
+	})
+
+	It("keeps the builds that ended within the days", func() {
+		policy.Days = 2
+		Expect(expiredIDs()).To(Equal([]int{3, 2, 1}))
+	})
+
+	It("keeps builds that either of the last builds and the days keep", func() {
+		policy.Builds = 4
+		policy.Days = 1
+		Expect(expiredIDs()).To(Equal([]int{1}))
+
+		policy.Builds = 1
+		policy.Days = 3
+		Expect(expiredIDs()).To(Equal([]int{2, 1}))
+	})
+
+	It("only keeps succeeded builds, counting them towards the last builds", func() {
+		policy.SucceededOnly = true
+		Expect(expiredIDs()).To(Equal([]int{4, 1}))
+
+		policy.Builds = 2
+		Expect(expiredIDs()).To(Equal([]int{4, 2, 1}))
+	})
+
+	It("keeps protected builds without counting them towards the last builds", func() {
+		builds[0].Protected = true
+		builds[3].Protected = true
+		policy.Builds = 2
+
+		Expect(expiredIDs()).To(Equal([]int{1}))
+
+		policy.SucceededOnly = true
+		builds[4].Protected = true
+
+		Expect(expiredIDs()).To(Equal([]int{4}))
+	})
+})
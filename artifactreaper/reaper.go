@@ -0,0 +1,172 @@
+package artifactreaper
+
+import (
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/worker"
+)
+
+//go:generate counterfeiter . ReaperDB
+
+type ReaperDB interface {
+	GetAllPipelines() ([]db.SavedPipeline, error)
+	GetContainerHandlesForBuild(buildID int) ([]string, error)
+	DeleteContainer(handle string) error
+	ReapContainer(handle string) error
+	MarkBuildArtifactsReaped(buildID int) error
+}
+
+//go:generate counterfeiter . ArtifactStore
+
+// ArtifactStore is where copies of builds' artifacts are kept, if anywhere.
+type ArtifactStore interface {
+	Delete(buildID int, name string) error
+}
+
+type Reaper interface {
+	Run() error
+}
+
+type reaper struct {
+	logger            lager.Logger
+	db                ReaperDB
+	pipelineDBFactory db.PipelineDBFactory
+	workerClient      worker.Client
+	artifactStore     ArtifactStore
+	batchSize         int
+	clock             clock.Clock
+}
+
+// NewReaper returns a reaper that destroys the containers, and deletes the
+// stored artifacts, of the builds of every job with an artifact retention
+// policy that the policy no longer keeps. At most batchSize builds are reaped
+// each run. The artifact store may be nil if artifacts aren't being stored.
+func NewReaper(
+	logger lager.Logger,
+	db ReaperDB,
+	pipelineDBFactory db.PipelineDBFactory,
+	workerClient worker.Client,
+	artifactStore ArtifactStore,
+	batchSize int,
+	clock clock.Clock,
+) Reaper {
+	return &reaper{
+		logger:            logger,
+		db:                db,
+		pipelineDBFactory: pipelineDBFactory,
+		workerClient:      workerClient,
+		artifactStore:     artifactStore,
+		batchSize:         batchSize,
+		clock:             clock,
+	}
+}
+
+func (r *reaper) Run() error {
+	pipelines, err := r.db.GetAllPipelines()
+	if err != nil {
+		r.logger.Error("could-not-get-pipelines", err)
+		return err
+	}
+
+	remaining := r.batchSize
+
+	for _, pipeline := range pipelines {
+		pipelineDB := r.pipelineDBFactory.Build(pipeline)
+
+		for _, job := range pipeline.Config.Jobs {
+			if job.ArtifactRetention == nil {
+				continue
+			}
+
+			logger := r.logger.Session("reap", lager.Data{
+				"pipeline": pipeline.Name,
+				"job":      job.Name,
+			})
+
+			builds, err := pipelineDB.GetJobArtifactBuilds(job.Name)
+			if err != nil {
+				logger.Error("could-not-get-job-artifact-builds", err)
+				return err
+			}
+
+			for _, build := range Expired(*job.ArtifactRetention, builds, r.clock.Now()) {
+				if remaining == 0 {
+					return nil
+				}
+
+				err := r.reap(logger.Session("build", lager.Data{"build": build.ID}), build)
+				if err != nil {
+					return err
+				}
+
+				remaining--
+			}
+		}
+	}
+
+	return nil
+}
+
+func (r *reaper) reap(logger lager.Logger, build db.ArtifactBuild) error {
+	handles, err := r.db.GetContainerHandlesForBuild(build.ID)
+	if err != nil {
+		logger.Error("could-not-get-containers", err)
+		return err
+	}
+
+	for _, handle := range handles {
+		err := r.reapContainer(logger, handle)
+		if err != nil {
+			return err
+		}
+	}
+
+	if r.artifactStore != nil {
+		for _, name := range build.Artifacts {
+			err := r.artifactStore.Delete(build.ID, name)
+			if err != nil {
+				logger.Error("could-not-delete-artifact", err, lager.Data{"artifact": name})
+				return err
+			}
+		}
+	}
+
+	err = r.db.MarkBuildArtifactsReaped(build.ID)
+	if err != nil {
+		logger.Error("could-not-mark-build-artifacts-reaped", err)
+		return err
+	}
+
+	logger.Info("reaped", lager.Data{
+		"containers": len(handles),
+		"artifacts":  len(build.Artifacts),
+	})
+
+	return nil
+}
+
+func (r *reaper) reapContainer(logger lager.Logger, handle string) error {
+	container, found, err := r.workerClient.LookupContainer(logger, handle)
+	if err == worker.ErrMissingWorker {
+		// the container went with its worker
+		return r.db.ReapContainer(handle)
+	}
+
+	if err != nil {
+		logger.Error("could-not-lookup-container", err, lager.Data{"handle": handle})
+		return err
+	}
+
+	if !found {
+		return r.db.ReapContainer(handle)
+	}
+
+	err = container.Destroy()
+	if err != nil {
+		logger.Error("could-not-destroy-container", err, lager.Data{"handle": handle})
+		return err
+	}
+
+	return r.db.DeleteContainer(handle)
+}
@@ -0,0 +1,195 @@
+package artifactreaper_test
+
+import (
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/lager/lagertest"
+	"github.com/concourse/atc"
+	. "github.com/concourse/atc/artifactreaper"
+	"github.com/concourse/atc/artifactreaper/artifactreaperfakes"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/db/dbfakes"
+	"github.com/concourse/atc/worker"
+	"github.com/concourse/atc/worker/workerfakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Reaper", func() {
+	var (
+		fakeReaperDB          *artifactreaperfakes.FakeReaperDB
+		fakePipelineDBFactory *dbfakes.FakePipelineDBFactory
+		fakePipelineDB        *dbfakes.FakePipelineDB
+		fakeWorkerClient      *workerfakes.FakeClient
+		fakeArtifactStore     *artifactreaperfakes.FakeArtifactStore
+		fakeClock             *fakeclock.FakeClock
+		batchSize             int
+
+		artifactStore ArtifactStore
+
+		runErr error
+	)
+
+	BeforeEach(func() {
+		fakeReaperDB = new(artifactreaperfakes.FakeReaperDB)
+		fakePipelineDBFactory = new(dbfakes.FakePipelineDBFactory)
+		fakePipelineDB = new(dbfakes.FakePipelineDB)
+		fakePipelineDBFactory.BuildReturns(fakePipelineDB)
+		fakeWorkerClient = new(workerfakes.FakeClient)
+		fakeArtifactStore = new(artifactreaperfakes.FakeArtifactStore)
+		fakeClock = fakeclock.NewFakeClock(time.Unix(123456789, 0))
+		batchSize = 10
+
+		artifactStore = fakeArtifactStore
+
+		fakeReaperDB.GetAllPipelinesReturns([]db.SavedPipeline{
+			{
+				ID: 1,
+				Pipeline: db.Pipeline{
+					Name: "some-pipeline",
+					Config: atc.Config{
+						Jobs: atc.JobConfigs{
+							{
+								Name:              "some-job",
+								ArtifactRetention: &atc.ArtifactRetentionConfig{Builds: 1},
+							},
+							{
+								Name: "some-other-job",
+							},
+						},
+					},
+				},
+			},
+		}, nil)
+
+		fakePipelineDB.GetJobArtifactBuildsReturns([]db.ArtifactBuild{
+			{ID: 3, Status: db.StatusSucceeded, Artifacts: []string{}},
+			{ID: 2, Status: db.StatusSucceeded, Artifacts: []string{"some-artifact", "some-other-artifact"}},
+			{ID: 1, Status: db.StatusFailed, Artifacts: []string{}},
+		}, nil)
+
+		fakeReaperDB.GetContainerHandlesForBuildStub = func(buildID int) ([]string, error) {
+			if buildID == 2 {
+				return []string{"found-handle", "missing-handle", "gone-worker-handle"}, nil
+			}
+
+			return []string{}, nil
+		}
+
+		fakeWorkerClient.LookupContainerStub = func(_ lager.Logger, handle string) (worker.Container, bool, error) {
+			switch handle {
+			case "found-handle":
+				return new(workerfakes.FakeContainer), true, nil
+			case "gone-worker-handle":
+				return nil, false, worker.ErrMissingWorker
+			default:
+				return nil, false, nil
+			}
+		}
+	})
+
+	JustBeforeEach(func() {
+		runErr = NewReaper(
+			lagertest.NewTestLogger("test"),
+			fakeReaperDB,
+			fakePipelineDBFactory,
+			fakeWorkerClient,
+			artifactStore,
+			batchSize,
+			fakeClock,
+		).Run()
+	})
+
+	It("only looks at the builds of jobs with a retention policy", func() {
+		Expect(runErr).NotTo(HaveOccurred())
+
+		Expect(fakePipelineDB.GetJobArtifactBuildsCallCount()).To(Equal(1))
+		Expect(fakePipelineDB.GetJobArtifactBuildsArgsForCall(0)).To(Equal("some-job"))
+	})
+
+	It("reaps the expired builds", func() {
+		Expect(fakeReaperDB.MarkBuildArtifactsReapedCallCount()).To(Equal(2))
+		Expect(fakeReaperDB.MarkBuildArtifactsReapedArgsForCall(0)).To(Equal(2))
+		Expect(fakeReaperDB.MarkBuildArtifactsReapedArgsForCall(1)).To(Equal(1))
+	})
+
+	It("destroys the containers that are still there, and forgets the rest", func() {
+		Expect(fakeWorkerClient.LookupContainerCallCount()).To(Equal(3))
+
+		Expect(fakeReaperDB.DeleteContainerCallCount()).To(Equal(1))
+		Expect(fakeReaperDB.DeleteContainerArgsForCall(0)).To(Equal("found-handle"))
+
+		Expect(fakeReaperDB.ReapContainerCallCount()).To(Equal(2))
+		Expect(fakeReaperDB.ReapContainerArgsForCall(0)).To(Equal("missing-handle"))
+		Expect(fakeReaperDB.ReapContainerArgsForCall(1)).To(Equal("gone-worker-handle"))
+	})
+
+	It("deletes the stored artifacts", func() {
+		Expect(fakeArtifactStore.DeleteCallCount()).To(Equal(2))
+
+		buildID, name := fakeArtifactStore.DeleteArgsForCall(0)
+		Expect(buildID).To(Equal(2))
+		Expect(name).To(Equal("some-artifact"))
+
+		buildID, name = fakeArtifactStore.DeleteArgsForCall(1)
+		Expect(buildID).To(Equal(2))
+		Expect(name).To(Equal("some-other-artifact"))
+	})
+
+	Context("when artifacts aren't being stored", func() {
+		BeforeEach(func() {
+			artifactStore = nil
+		})
+
+		It("still reaps the builds", func() {
+			Expect(runErr).NotTo(HaveOccurred())
+			Expect(fakeReaperDB.MarkBuildArtifactsReapedCallCount()).To(Equal(2))
+		})
+	})
+
+	Context("when there are more expired builds than the batch size", func() {
+		BeforeEach(func() {
+			batchSize = 1
+		})
+
+		It("only reaps the batch", func() {
+			Expect(runErr).NotTo(HaveOccurred())
+
+			Expect(fakeReaperDB.MarkBuildArtifactsReapedCallCount()).To(Equal(1))
+			Expect(fakeReaperDB.MarkBuildArtifactsReapedArgsForCall(0)).To(Equal(2))
+		})
+	})
+
+	Context("when destroying a container fails", func() {
+		disaster := errors.New("nope")
+
+		BeforeEach(func() {
+			fakeContainer := new(workerfakes.FakeContainer)
+			fakeContainer.DestroyReturns(disaster)
+			fakeWorkerClient.LookupContainerReturns(fakeContainer, true, nil)
+		})
+
+		It("returns the error without marking the build reaped", func() {
+			Expect(runErr).To(Equal(disaster))
+
+			Expect(fakeReaperDB.DeleteContainerCallCount()).To(BeZero())
+			Expect(fakeReaperDB.MarkBuildArtifactsReapedCallCount()).To(BeZero())
+		})
+	})
+
+	Context("when getting the job's builds fails", func() {
+		disaster := errors.New("nope")
+
+		BeforeEach(func() {
+			fakePipelineDB.GetJobArtifactBuildsReturns(nil, disaster)
+		})
+
+		It("returns the error", func() {
+			Expect(runErr).To(Equal(disaster))
+		})
+	})
+})
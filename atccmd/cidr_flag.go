@@ -0,0 +1,42 @@
+package atccmd
+
+import (
+	"fmt"
+	"net"
+)
+
+type CIDRFlag struct {
+	network *net.IPNet
+}
+
+func (f *CIDRFlag) UnmarshalFlag(value string) error {
+	_, network, err := net.ParseCIDR(value)
+	if err != nil {
+		return fmt.Errorf("invalid CIDR: '%s'", value)
+	}
+
+	f.network = network
+
+	return nil
+}
+
+func (f CIDRFlag) String() string {
+	if f.network == nil {
+		return ""
+	}
+
+	return f.network.String()
+}
+
+func (f CIDRFlag) IPNet() *net.IPNet {
+	return f.network
+}
+
+func cidrNetworks(flags []CIDRFlag) []*net.IPNet {
+	var networks []*net.IPNet
+	for _, flag := range flags {
+		networks = append(networks, flag.IPNet())
+	}
+
+	return networks
+}
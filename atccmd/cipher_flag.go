@@ -0,0 +1,37 @@
+package atccmd
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"fmt"
+)
+
+type CipherFlag struct {
+	aead cipher.AEAD
+}
+
+func (flag *CipherFlag) UnmarshalFlag(value string) error {
+	switch len(value) {
+	case 16, 24, 32:
+	default:
+		return fmt.Errorf("key must be 16, 24 or 32 characters long, for AES-128, AES-192 or AES-256; got %d", len(value))
+	}
+
+	block, err := aes.NewCipher([]byte(value))
+	if err != nil {
+		return err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return err
+	}
+
+	flag.aead = aead
+
+	return nil
+}
+
+func (flag CipherFlag) AEAD() cipher.AEAD {
+	return flag.aead
+}
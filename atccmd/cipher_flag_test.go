@@ -0,0 +1,32 @@
+package atccmd_test
+
+import (
+	"github.com/concourse/atc/atccmd"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CipherFlag", func() {
+	It("accepts keys for AES-128, AES-192 and AES-256", func() {
+		for _, key := range []string{
+			"0123456789abcdef",
+			"0123456789abcdef01234567",
+			"0123456789abcdef0123456789abcdef",
+		} {
+			flag := atccmd.CipherFlag{}
+
+			err := flag.UnmarshalFlag(key)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(flag.AEAD()).ToNot(BeNil())
+		}
+	})
+
+	It("returns an error when the key is the wrong length", func() {
+		flag := atccmd.CipherFlag{}
+
+		err := flag.UnmarshalFlag("too-short")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("got 9"))
+	})
+})
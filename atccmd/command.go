@@ -4,38 +4,61 @@ import (
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/tls"
+	"database/sql"
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
 	_ "net/http/pprof"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	"code.cloudfoundry.org/clock"
+	gconn "code.cloudfoundry.org/garden/client/connection"
 	"code.cloudfoundry.org/lager"
 	"github.com/concourse/atc"
 	"github.com/concourse/atc/api"
 	"github.com/concourse/atc/api/buildserver"
+	"github.com/concourse/atc/api/grpcserver"
+	"github.com/concourse/atc/api/jobserver"
+	"github.com/concourse/atc/artifactreaper"
 	"github.com/concourse/atc/auth"
 	"github.com/concourse/atc/auth/provider"
+	"github.com/concourse/atc/buildarchiver"
 	"github.com/concourse/atc/buildreaper"
 	"github.com/concourse/atc/builds"
 	"github.com/concourse/atc/config"
 	"github.com/concourse/atc/containerkeepaliver"
+	"github.com/concourse/atc/cors"
+	"github.com/concourse/atc/creds"
 	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/db/cache"
+	"github.com/concourse/atc/db/encryption"
 	"github.com/concourse/atc/db/migrations"
 	"github.com/concourse/atc/engine"
 	"github.com/concourse/atc/exec"
+	"github.com/concourse/atc/flakiness"
+	"github.com/concourse/atc/health"
+	"github.com/concourse/atc/leadership"
 	"github.com/concourse/atc/lockrunner"
 	"github.com/concourse/atc/lostandfound"
 	"github.com/concourse/atc/metric"
+	"github.com/concourse/atc/mtls"
+	"github.com/concourse/atc/notify"
 	"github.com/concourse/atc/pipelines"
 	"github.com/concourse/atc/radar"
+	"github.com/concourse/atc/ratelimit"
 	"github.com/concourse/atc/resource"
 	"github.com/concourse/atc/scheduler"
+	"github.com/concourse/atc/storage"
+	"github.com/concourse/atc/taskcache"
+	"github.com/concourse/atc/testhelpers/fakebuilder"
+	"github.com/concourse/atc/tracing"
+	"github.com/concourse/atc/versionpruner"
 	"github.com/concourse/atc/web"
 	"github.com/concourse/atc/web/webhandler"
 	"github.com/concourse/atc/worker"
@@ -54,12 +77,19 @@ import (
 )
 
 type ATCCommand struct {
+	// Clock is what everything run by the ATC tells the time by, for tests
+	// to control. It's the real clock if left unset.
+	Clock clock.Clock `no-flag:"true"`
+
 	BindIP   IPFlag `long:"bind-ip"   default:"0.0.0.0" description:"IP address on which to listen for web traffic."`
 	BindPort uint16 `long:"bind-port" default:"8080"    description:"Port on which to listen for HTTP traffic."`
 
-	TLSBindPort uint16   `long:"tls-bind-port" description:"Port on which to listen for HTTPS traffic."`
-	TLSCert     FileFlag `long:"tls-cert"      description:"File containing an SSL certificate."`
-	TLSKey      FileFlag `long:"tls-key"       description:"File containing an RSA private key, used to encrypt HTTPS traffic."`
+	TLSBindPort     uint16   `long:"tls-bind-port"      description:"Port on which to listen for HTTPS traffic."`
+	TLSCert         FileFlag `long:"tls-cert"           description:"File containing an SSL certificate."`
+	TLSKey          FileFlag `long:"tls-key"            description:"File containing an RSA private key, used to encrypt HTTPS traffic."`
+	TLSClientCACert FileFlag `long:"tls-client-ca-cert" description:"File containing the CA certificates to verify client certificates with. Workers registering over HTTPS must then present one."`
+
+	GRPCBindPort uint16 `long:"grpc-bind-port" description:"Port on which to listen for gRPC traffic to the builds service. Disabled if not specified."`
 
 	ExternalURL URLFlag `long:"external-url" default:"http://127.0.0.1:8080" description:"URL used to reach any ATC from the outside world."`
 	PeerURL     URLFlag `long:"peer-url"     default:"http://127.0.0.1:8080" description:"URL used to reach this ATC from other ATCs in the cluster."`
@@ -68,6 +98,22 @@ type ATCCommand struct {
 
 	PostgresDataSource string `long:"postgres-data-source" default:"postgres://127.0.0.1:5432/atc?sslmode=disable" description:"PostgreSQL connection string."`
 
+	PostgresReadReplicaDataSource string        `long:"postgres-read-replica-data-source" description:"PostgreSQL connection string of a read-only replica, e.g. a hot standby, to read builds and their events from for the API. Reads go to the primary while it's behind, and writes always do. Disabled by default."`
+	PostgresReadReplicaMaxLag     time.Duration `long:"postgres-read-replica-max-lag" default:"10s" description:"How far behind the primary the read replica may fall before reads go back to the primary until it catches up."`
+
+	DatabaseMaxOpenConnections int           `long:"database-max-open-connections" default:"64" description:"Maximum number of connections to open to the database. Queries wait for a free one once this many are in use."`
+	DatabaseMaxIdleConnections int           `long:"database-max-idle-connections" default:"2"  description:"Maximum number of idle connections to keep open to the database."`
+	DatabaseConnectionLifetime time.Duration `long:"database-connection-lifetime"               description:"Close database connections once they've been open for this long. Unlimited by default."`
+	DatabaseQueryTimeout       time.Duration `long:"database-query-timeout"                     description:"Cancel any database query still running after this long, freeing up its connection. Unlimited by default."`
+
+	EncryptionKey    CipherFlag `long:"encryption-key"     description:"A 16, 24 or 32 character key used to encrypt pipeline configs, build plans, build params and secrets before storing them in the database."`
+	OldEncryptionKey CipherFlag `long:"old-encryption-key" description:"The key data was encrypted with before switching to --encryption-key. Data is decrypted with it as needed until it's been rewritten with --reencrypt."`
+	Reencrypt        bool       `long:"reencrypt"          description:"Rewrite everything encrypted in the database with --encryption-key, or in the clear if only --old-encryption-key is given, then exit."`
+
+	CompressBuildEvents bool `long:"compress-build-events" description:"Compress the build events saved before they were compressed as they're saved, then exit."`
+
+	BuildEventSigningKey string `long:"build-event-signing-key" description:"A key to sign build events with as they're saved, chaining each build's events together so that they can be checked for having been altered. Every ATC must be given the same key."`
+
 	DebugBindIP   IPFlag `long:"debug-bind-ip"   default:"127.0.0.1" description:"IP address on which to listen for the pprof debugger endpoints."`
 	DebugBindPort uint16 `long:"debug-bind-port" default:"8079"      description:"Port on which to listen for the pprof debugger endpoints."`
 
@@ -76,12 +122,100 @@ type ATCCommand struct {
 	ResourceCheckingInterval     time.Duration `long:"resource-checking-interval" default:"1m" description:"Interval on which to check for new versions of resources."`
 	OldResourceGracePeriod       time.Duration `long:"old-resource-grace-period" default:"5m" description:"How long to cache the result of a get step after a newer version of the resource is found."`
 	ResourceCacheCleanupInterval time.Duration `long:"resource-cache-cleanup-interval" default:"30s" description:"Interval on which to cleanup old caches of resources."`
+	SchedulerSweepInterval       time.Duration `long:"scheduler-sweep-interval" default:"1m" description:"Interval on which to schedule each pipeline's jobs regardless, in case a new version, finished build or config change was missed."`
+
+	ResourceVersionRetention int `long:"resource-version-retention" description:"Keep this many of the latest versions of each resource, pruning older ones that no build used and that aren't pinned or disabled. Keeps every version by default."`
+
+	BuildLogRetentionPeriod time.Duration `long:"build-log-retention-period" description:"Delete the events of builds that finished longer ago than this. The builds themselves, with their inputs and outputs, are kept for scheduling. Applies to one-off builds and all jobs, in addition to build_logs_to_retain. Disabled by default."`
+	BuildRetentionPeriod    time.Duration `long:"build-retention-period" description:"Delete builds that finished longer ago than this, along with their events, inputs and outputs. Each job's latest build and latest successful build are always kept. Disabled by default."`
+	BuildsToRetain          int           `long:"builds-to-retain" description:"Delete all but this many of each job's latest builds, along with their events, inputs and outputs. Each job's latest successful build is always kept. Disabled by default."`
+	MaxBuildLogBytes        int64         `long:"max-build-log-bytes" description:"Stop saving a build's log output once it exceeds this many bytes. Unlimited by default."`
+
+	DefaultBuildTimeout time.Duration `long:"default-build-timeout" description:"Abort builds that have been running for longer than this, unless they have a timeout of their own. Disabled by default."`
+
+	BuildReconcileGracePeriod time.Duration `long:"build-reconcile-grace-period" default:"10m" description:"How long a build must have been running for before it's errored for having been left behind, with nothing tracking it and all of its containers gone."`
+
+	DurationAlert struct {
+		Percentile int           `long:"percentile" description:"Alert on builds that have been running for longer than this percent of their job's recent builds took, e.g. 95, warning about it in the build's events. Disabled by default."`
+		Window     time.Duration `long:"window"     default:"168h" description:"How far back to look at a job's finished builds when working out how long they usually take."`
+		MinBuilds  int           `long:"min-builds" default:"10"   description:"Builds a job must have finished within the window before its builds are alerted on."`
+	} `group:"Build Duration Alerts (optional)" namespace:"duration-alert"`
+
+	FlakyJobThreshold float64 `long:"flaky-job-threshold" default:"0.25" description:"Flag jobs as flaky once at least this fraction of their recent builds succeeded or failed where the last build of the same inputs did the opposite."`
+
+	BuildArtifactStoreDir DirFlag `long:"build-artifact-store-dir" description:"Directory in which to keep copies of downloaded build artifacts, so they remain available after their containers expire."`
 
 	CLIArtifactsDir DirFlag `long:"cli-artifacts-dir" description:"Directory containing downloadable CLI binaries."`
+	CLIDownloadURL  string  `long:"cli-download-url"  description:"URL from which to fetch CLI binaries that aren't in --cli-artifacts-dir, proxying them to whoever asked. {version}, {platform} and {arch} in it are replaced, e.g. https://example.com/fly/v{version}/fly_{platform}_{arch}."`
+
+	BuildCreationRateLimit float64 `long:"build-creation-rate-limit" description:"Builds that may be created per second by any one remote IP address or API token. Unlimited by default."`
+	BuildCreationBurst     int     `long:"build-creation-burst" default:"10" description:"Builds that may be created in a burst before the build creation rate limit applies."`
+
+	TrustedProxies []CIDRFlag `long:"trusted-proxy" description:"Network of proxies whose X-Forwarded-For header is believed when working out the IP address a request came from, for rate limiting and the network policy. Can be specified multiple times." value-name:"CIDR"`
+
+	NetworkPolicy struct {
+		Read    []CIDRFlag `long:"allow-read"    description:"Network from which API routes that only read may be called. Can be specified multiple times. Any network is allowed by default." value-name:"CIDR"`
+		Trigger []CIDRFlag `long:"allow-trigger" description:"Network from which API routes that start and stop builds may be called. Can be specified multiple times. Any network is allowed by default." value-name:"CIDR"`
+		Admin   []CIDRFlag `long:"allow-admin"   description:"Network from which every other API route, e.g. setting pipelines, may be called. Can be specified multiple times. Any network is allowed by default." value-name:"CIDR"`
+	} `group:"API Network Policy (optional)" namespace:"network-policy"`
+
+	LeadershipTTL time.Duration `long:"leadership-ttl" default:"30s" description:"How long the ATC scheduling builds and checking resources stays the leader without renewing its leadership. Another ATC takes over within this long of the leader going away."`
+
+	BuildCacheSize int           `long:"build-cache-size" default:"1000" description:"Builds, and the configs of their pipelines, to keep in memory for API requests that look up the same build repeatedly. Set to 0 to disable."`
+	BuildCacheTTL  time.Duration `long:"build-cache-ttl"  default:"10s"  description:"How long a cached build may be used for. Status changes are picked up straight away; anything else may be stale for this long."`
+
+	EventStreamDrainGracePeriod time.Duration `long:"event-stream-drain-grace-period" default:"10s" description:"How long to keep streaming build events to connected clients after being told to shut down."`
+
+	EventStreamKeepAliveInterval time.Duration            `long:"event-stream-keepalive-interval" default:"30s" description:"How often to send a keepalive on event streams with no events to send, so that load balancers don't close them as idle. Set to 0 to disable."`
+	EventStreamKeepAlives        map[string]time.Duration `long:"event-stream-keepalive"          description:"Keepalive interval for streams served by a particular API route, overriding --event-stream-keepalive-interval. Can be specified multiple times." value-name:"ROUTE:INTERVAL"`
+
+	EventStreamBufferSize int `long:"event-stream-buffer-size" default:"1000" description:"Events of a running build to buffer for each client streaming them. Once a client's buffer is full, log events are skipped until it catches up, and it is told how many it missed. Set to 0 to never skip events."`
+
+	EventCensorPolicies FileFlag `long:"event-censor-policies" description:"YAML file describing which build event types and fields to withhold from team members and from public viewers, by default or per pipeline."`
+
+	BuildEventArchive struct {
+		Endpoint        URLFlag       `long:"endpoint"          description:"S3-compatible object storage API in which to archive build events, e.g. https://s3.amazonaws.com or https://storage.googleapis.com."`
+		Bucket          string        `long:"bucket"            description:"Bucket in which to keep archived build events."`
+		Region          string        `long:"region"            default:"us-east-1" description:"Region to sign requests for. Use 'auto' for GCS."`
+		AccessKeyID     string        `long:"access-key-id"     description:"Access key ID for the object storage API."`
+		SecretAccessKey string        `long:"secret-access-key" description:"Secret access key for the object storage API."`
+		After           time.Duration `long:"after"             description:"Move the events of builds that finished longer ago than this out of the database and into the archive. Archived events are still served by the API. Disabled by default."`
+	} `group:"Build Event Archive (optional)" namespace:"build-event-archive"`
+
+	Storage struct {
+		Driver          string  `long:"driver"            choice:"local" choice:"s3" choice:"gcs" description:"Where to keep task caches and copies of downloaded build artifacts, so that they outlive the workers that made them. Disabled by default."`
+		Dir             DirFlag `long:"dir"               description:"Directory in which the local driver keeps them, e.g. on a disk shared by every ATC."`
+		Endpoint        URLFlag `long:"endpoint"          description:"S3-compatible object storage API for the s3 driver, e.g. https://s3.amazonaws.com or a Minio server."`
+		Bucket          string  `long:"bucket"            description:"Bucket in which the s3 and gcs drivers keep them."`
+		Region          string  `long:"region"            default:"us-east-1" description:"Region for the s3 driver to sign requests for."`
+		AccessKeyID     string  `long:"access-key-id"     description:"Access key ID for the s3 driver, or HMAC key ID for the gcs driver."`
+		SecretAccessKey string  `long:"secret-access-key" description:"Secret access key for the s3 driver, or HMAC secret for the gcs driver."`
+	} `group:"Storage (optional)" namespace:"storage"`
+
+	Vault struct {
+		URL         URLFlag `long:"url"          description:"Vault server address, e.g. https://vault.example.com:8200, from which to look up the credentials pipelines refer to as ((name))."`
+		ClientToken string  `long:"client-token" description:"Client token for accessing secrets under the path prefix."`
+		PathPrefix  string  `long:"path-prefix"  default:"/concourse" description:"Path under which credentials are looked up, first as PREFIX/TEAM/PIPELINE/NAME and then as PREFIX/TEAM/NAME."`
+	} `group:"Vault Credential Management (optional)" namespace:"vault"`
+
+	CredHub struct {
+		URL          URLFlag `long:"url"           description:"CredHub server address, from which to look up the credentials pipelines refer to as ((name))."`
+		ClientID     string  `long:"client-id"     description:"UAA client ID for accessing credentials under the path prefix."`
+		ClientSecret string  `long:"client-secret" description:"UAA client secret for accessing credentials under the path prefix."`
+		PathPrefix   string  `long:"path-prefix"   default:"/concourse" description:"Path under which credentials are looked up, first as PREFIX/TEAM/PIPELINE/NAME and then as PREFIX/TEAM/NAME."`
+	} `group:"CredHub Credential Management (optional)" namespace:"credhub"`
+
+	CORS struct {
+		AllowedOrigins   []string `long:"allowed-origin"    description:"Origin, e.g. https://dashboard.example.com, from which browsers may make API requests, or * for any. Can be specified multiple times. Disabled by default." value-name:"ORIGIN"`
+		AllowedMethods   []string `long:"allowed-method"    description:"Method that cross-origin API requests may use. Can be specified multiple times." value-name:"METHOD" default:"GET" default:"POST" default:"PUT" default:"DELETE"`
+		AllowedHeaders   []string `long:"allowed-header"    description:"Header that cross-origin API requests may send. Can be specified multiple times." value-name:"HEADER" default:"Authorization" default:"Content-Type"`
+		AllowCredentials bool     `long:"allow-credentials" description:"Allow cross-origin API requests to send the auth cookie."`
+	} `group:"Cross-Origin Resource Sharing (optional)" namespace:"cors"`
 
 	Developer struct {
 		DevelopmentMode bool `short:"d" long:"development-mode"  description:"Lax security rules to make local development easier."`
 		Noop            bool `short:"n" long:"noop"              description:"Don't actually do any automatic scheduling or checking."`
+		FakeBuilds      bool `long:"fake-builds"                 description:"Run builds in memory instead of on workers, with every step succeeding straight away, for end-to-end tests."`
 	} `group:"Developer Options"`
 
 	AllowSelfSignedCertificates bool `long:"allow-self-signed-certificates" description:"Allow self signed certificates."`
@@ -92,6 +226,25 @@ type ATCCommand struct {
 		ResourceTypes   map[string]string `long:"resource"         description:"A resource type to advertise for the worker. Can be specified multiple times." value-name:"TYPE:IMAGE"`
 	} `group:"Static Worker (optional)" namespace:"worker"`
 
+	WorkerClient struct {
+		DialTimeout           time.Duration `long:"dial-timeout"            default:"30s" description:"How long to wait for a connection to a worker to be established."`
+		TLSHandshakeTimeout   time.Duration `long:"tls-handshake-timeout"   default:"10s" description:"How long to wait for the TLS handshake with a worker."`
+		ResponseHeaderTimeout time.Duration `long:"response-header-timeout" default:"5m"  description:"How long to wait for a worker to start responding once a request has been sent."`
+
+		KeepAlive         time.Duration `long:"keepalive"           default:"30s" description:"Interval between TCP keepalive probes on connections to workers."`
+		IdleConnTimeout   time.Duration `long:"idle-conn-timeout"   default:"90s" description:"How long an idle connection to a worker is kept around for reuse."`
+		MaxIdleConns      int           `long:"max-idle-conns"      default:"2"   description:"Idle connections to keep around per worker."`
+		DisableKeepAlives bool          `long:"disable-keep-alives"               description:"Use a new connection for every request to a worker."`
+
+		CACert  FileFlag `long:"ca-cert"  description:"File containing the CA certificates to verify workers with, instead of the system's."`
+		TLSCert FileFlag `long:"tls-cert" description:"File containing a client certificate to present to workers."`
+		TLSKey  FileFlag `long:"tls-key"  description:"File containing the private key of the client certificate."`
+
+		MutualTLS bool `long:"mutual-tls" description:"Talk to workers' Garden and Baggageclaim servers over TLS, presenting --worker-client-tls-cert and verifying them with --worker-client-ca-cert."`
+
+		Proxy URLFlag `long:"proxy" description:"Proxy through which to talk to workers. Defaults to the one given by the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables."`
+	} `group:"Worker HTTP Client" namespace:"worker-client"`
+
 	BasicAuth atc.BasicAuthFlag `group:"Basic Authentication" namespace:"basic-auth"`
 
 	GitHubAuth atc.GitHubAuthFlag `group:"GitHub Authentication" namespace:"github-auth"`
@@ -100,6 +253,10 @@ type ATCCommand struct {
 
 	GenericOAuth atc.GenericOAuthFlag `group:"Generic OAuth Authentication (Allows access to ALL authenticated users)" namespace:"generic-oauth"`
 
+	OIDCAuth atc.OIDCAuthFlag `group:"OpenID Connect Authentication" namespace:"oidc-auth"`
+
+	SAMLAuth atc.SAMLAuthFlag `group:"SAML Authentication" namespace:"saml-auth"`
+
 	Metrics struct {
 		HostName   string            `long:"metrics-host-name"   description:"Host string to attach to emitted metrics."`
 		Tags       []string          `long:"metrics-tag"         description:"Tag to attach to emitted metrics. Can be specified multiple times." value-name:"TAG"`
@@ -110,10 +267,29 @@ type ATCCommand struct {
 
 		RiemannHost string `long:"riemann-host"                description:"Riemann server address to emit metrics to."`
 		RiemannPort uint16 `long:"riemann-port" default:"5555" description:"Port of the Riemann server to emit metrics to."`
+
+		PrometheusBindIP   IPFlag `long:"prometheus-bind-ip"   default:"0.0.0.0" description:"IP address on which to listen for Prometheus scrapes of /metrics."`
+		PrometheusBindPort uint16 `long:"prometheus-bind-port"                   description:"Port on which to listen for Prometheus scrapes of /metrics. Disabled if not specified."`
 	} `group:"Metrics & Diagnostics"`
+
+	Tracing struct {
+		OTLPEndpoint  URLFlag           `long:"otlp-endpoint"  description:"OpenTelemetry collector to send traces of API requests, scheduling and builds to over OTLP/HTTP, e.g. http://localhost:4318. Jaeger can receive them directly. Disabled if not specified."`
+		OTLPHeaders   map[string]string `long:"otlp-header"    description:"A header to send to the collector, e.g. for authenticating. Can be specified multiple times." value-name:"NAME:VALUE"`
+		ServiceName   string            `long:"service-name"   default:"atc" description:"Service name to attach to traces."`
+		Attributes    map[string]string `long:"attribute"      description:"A key-value attribute to attach to traces. Can be specified multiple times." value-name:"NAME:VALUE"`
+		FlushInterval time.Duration     `long:"flush-interval" default:"5s" description:"How often to send finished spans to the collector."`
+	} `group:"Tracing (optional)" namespace:"tracing"`
 }
 
 func (cmd *ATCCommand) Execute(args []string) error {
+	if cmd.Reencrypt {
+		return cmd.reencrypt()
+	}
+
+	if cmd.CompressBuildEvents {
+		return cmd.compressBuildEvents()
+	}
+
 	runner, err := cmd.Runner(args)
 	if err != nil {
 		return err
@@ -136,6 +312,10 @@ func (cmd *ATCCommand) Runner(args []string) (ifrit.Runner, error) {
 		cmd.configureMetrics(logger)
 	}
 
+	if cmd.Tracing.OTLPEndpoint.URL() != nil {
+		cmd.configureTracing(logger)
+	}
+
 	dbConn, err := cmd.constructDBConn(logger)
 	if err != nil {
 		return nil, err
@@ -151,19 +331,70 @@ func (cmd *ATCCommand) Runner(args []string) (ifrit.Runner, error) {
 	bus := db.NewNotificationsBus(listener, dbConn)
 
 	sqlDB := db.NewSQL(dbConn, bus, lockFactory)
+
+	// the API reads builds from the replica, if there is one
+	readDB := sqlDB
+	if cmd.PostgresReadReplicaDataSource != "" {
+		replica, err := cmd.openReadReplica(logger)
+		if err != nil {
+			return nil, err
+		}
+
+		readDB = sqlDB.WithReadReplica(replica)
+	}
+
 	trackerFactory := resource.NewTrackerFactory()
-	resourceFetcherFactory := resource.NewFetcherFactory(sqlDB, clock.NewClock())
-	workerClient := cmd.constructWorkerPool(logger, sqlDB, trackerFactory, resourceFetcherFactory)
+	resourceFetcherFactory := resource.NewFetcherFactory(sqlDB, cmd.clock())
+
+	workerKeyPair, err := cmd.loadWorkerKeyPair()
+	if err != nil {
+		return nil, err
+	}
+
+	workerClient := cmd.constructWorkerPool(logger, sqlDB, cmd.workerDialer(workerKeyPair), trackerFactory, resourceFetcherFactory)
 
 	tracker := trackerFactory.TrackerFor(workerClient)
 	resourceFetcher := resourceFetcherFactory.FetcherFor(workerClient)
 	teamDBFactory := db.NewTeamDBFactory(dbConn, bus, lockFactory)
-	engine := cmd.constructEngine(workerClient, tracker, resourceFetcher, teamDBFactory)
+
+	drain := make(chan struct{})
+
+	// builds still running when the ATC shuts down are handed off to the
+	// other ATCs, which look for them as soon as they're told to
+	buildHandoff := engine.NewHandoff(drain)
+
+	buildHandoffs, err := bus.Listen(db.BuildHandoffChannel)
+	if err != nil {
+		return nil, err
+	}
+
+	storageDriver := cmd.constructStorageDriver()
+
+	var taskCacheStore exec.TaskCacheStore
+	var taskCacheInvalidator jobserver.TaskCacheInvalidator
+	if storageDriver != nil {
+		taskCaches := taskcache.NewStore(storage.Prefixed(storageDriver, "task-caches"), sqlDB)
+		taskCacheStore = taskCaches
+		taskCacheInvalidator = taskCaches
+	}
+
+	var artifactStore buildserver.ArtifactStore
+	if storageDriver != nil {
+		artifactStore = buildserver.NewDriverArtifactStore(storage.Prefixed(storageDriver, "build-artifacts"))
+	} else if cmd.BuildArtifactStoreDir != "" {
+		artifactStore = buildserver.NewDirArtifactStore(cmd.BuildArtifactStoreDir.Path())
+	}
+
+	engine := cmd.constructEngine(workerClient, tracker, resourceFetcher, teamDBFactory, buildHandoff, taskCacheStore)
+
+	credsManager := cmd.constructCredsManager()
 
 	radarSchedulerFactory := pipelines.NewRadarSchedulerFactory(
 		tracker,
 		cmd.ResourceCheckingInterval,
 		engine,
+		sqlDB,
+		credsManager,
 	)
 
 	radarScannerFactory := radar.NewScannerFactory(
@@ -197,33 +428,114 @@ func (cmd *ATCCommand) Runner(args []string) (ifrit.Runner, error) {
 		return nil, err
 	}
 
-	drain := make(chan struct{})
+	censorPolicies := buildserver.CensorPolicies{}
+	if cmd.EventCensorPolicies != "" {
+		censorPolicies, err = buildserver.LoadCensorPolicies(string(cmd.EventCensorPolicies))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load event censor policies: %s", err)
+		}
+	}
+
+	var eventArchive buildarchiver.EventArchive
+	if cmd.BuildEventArchive.Endpoint.URL() != nil {
+		eventArchive = buildarchiver.NewEventArchive(buildarchiver.NewS3ObjectStore(
+			cmd.BuildEventArchive.Endpoint.URL(),
+			cmd.BuildEventArchive.Bucket,
+			cmd.BuildEventArchive.Region,
+			cmd.BuildEventArchive.AccessKeyID,
+			cmd.BuildEventArchive.SecretAccessKey,
+		))
+	}
+
+	// shared by both APIs, so that a build's events are only read once no
+	// matter which of them it's being watched through
+	eventHub := buildserver.NewEventHub(eventArchive, cmd.EventStreamBufferSize)
+
+	// shared by both APIs, so that the limit holds across them
+	var buildCreationLimiter *ratelimit.Limiter
+	if cmd.BuildCreationRateLimit > 0 {
+		buildCreationLimiter = ratelimit.NewLimiter(cmd.clock(), cmd.BuildCreationRateLimit, cmd.BuildCreationBurst)
+	}
+
+	var buildsDB auth.BuildsDB = readDB
+	if cmd.BuildCacheSize > 0 {
+		buildCache := cache.NewBuildCache(readDB, cmd.BuildCacheSize, cmd.BuildCacheTTL, cmd.clock())
+
+		err := cache.Watch(logger.Session("build-cache"), bus, buildCache)
+		if err != nil {
+			return nil, err
+		}
+
+		buildsDB = buildCache
+	}
 
 	pipelineDBFactory := db.NewPipelineDBFactory(dbConn, bus, lockFactory)
+
+	// shared by the API, so that builds can be reconciled through it
+	buildReconciler := builds.NewReconciler(
+		logger.Session("build-reconciler"),
+		sqlDB,
+		workerClient,
+		cmd.BuildReconcileGracePeriod,
+		cmd.clock(),
+	)
+
+	// shared by the API, so that garbage collection can be forced through it
+	baggageCollector := lostandfound.NewBaggageCollector(
+		logger.Session("baggage-collector"),
+		workerClient,
+		sqlDB,
+		pipelineDBFactory,
+		cmd.OldResourceGracePeriod,
+		24*time.Hour,
+		cmd.clock(),
+	)
+
 	apiHandler, err := cmd.constructAPIHandler(
 		logger,
 		reconfigurableSink,
 		sqlDB,
+		readDB,
+		buildsDB,
 		teamDBFactory,
 		providerFactory,
 		signingKey,
 		pipelineDBFactory,
 		engine,
 		workerClient,
+		workerKeyPair,
 		drain,
 		radarSchedulerFactory,
 		radarScannerFactory,
+		censorPolicies,
+		eventHub,
+		buildCreationLimiter,
+		baggageCollector,
+		credsManager,
+		artifactStore,
+		taskCacheInvalidator,
+		buildReconciler,
 	)
 
 	if err != nil {
 		return nil, err
 	}
 
+	if len(cmd.CORS.AllowedOrigins) > 0 {
+		apiHandler = cors.NewHandler(cors.Policy{
+			AllowedOrigins:   cmd.CORS.AllowedOrigins,
+			AllowedMethods:   cmd.CORS.AllowedMethods,
+			AllowedHeaders:   cmd.CORS.AllowedHeaders,
+			AllowCredentials: cmd.CORS.AllowCredentials,
+		}, apiHandler)
+	}
+
 	oauthHandler, err := auth.NewOAuthHandler(
 		logger,
 		providerFactory,
 		teamDBFactory,
 		signingKey,
+		cmd.oauthBaseURL(),
 	)
 	if err != nil {
 		return nil, err
@@ -238,6 +550,11 @@ func (cmd *ATCCommand) Runner(args []string) (ifrit.Runner, error) {
 		return nil, err
 	}
 
+	healthHandler := health.NewHandler(logger, drain, map[string]health.Check{
+		"database": health.DatabaseCheck(dbConn),
+		"workers":  health.WorkersCheck(sqlDB),
+	})
+
 	var httpHandler, httpsHandler http.Handler
 	if cmd.TLSBindPort != 0 {
 		httpHandler = cmd.constructHTTPHandler(
@@ -259,63 +576,100 @@ func (cmd *ATCCommand) Runner(args []string) (ifrit.Runner, error) {
 				externalHost: cmd.ExternalURL.URL().Host,
 				baseHandler:  oauthHandler,
 			},
+
+			// load balancers probe whichever port they're pointed at
+			healthHandler,
 		)
 
 		httpsHandler = cmd.constructHTTPHandler(
 			webHandler,
 			apiHandler,
 			oauthHandler,
+			healthHandler,
 		)
 	} else {
 		httpHandler = cmd.constructHTTPHandler(
 			webHandler,
 			apiHandler,
 			oauthHandler,
+			healthHandler,
 		)
 	}
 
 	members := []grouper.Member{
 		{"drainer", drainer(drain)},
+		{"build-handoff", buildHandoff},
 
 		{"debug", http_server.New(
 			cmd.debugBindAddr(),
 			http.DefaultServeMux,
 		)},
 
-		{"pipelines", pipelines.SyncRunner{
-			Syncer: cmd.constructPipelineSyncer(
-				logger.Session("syncer"),
+		// every ATC serves the API, but only the leader schedules builds and
+		// checks for new versions
+		{"pipelines", leadership.NewRunner(
+			logger.Session("pipelines"),
+			pipelines.SyncRunner{
+				Syncer: cmd.constructPipelineSyncer(
+					logger.Session("syncer"),
+					sqlDB,
+					pipelineDBFactory,
+					radarSchedulerFactory,
+				),
+				Interval: 10 * time.Second,
+				Clock:    cmd.clock(),
+			},
+			cmd.PeerURL.String(),
+			sqlDB,
+			cmd.clock(),
+			cmd.LeadershipTTL,
+		)},
+
+		{"builds", builds.TrackerRunner{
+			Tracker: builds.NewTracker(
+				logger.Session("build-tracker"),
 				sqlDB,
-				pipelineDBFactory,
-				radarSchedulerFactory,
+				engine,
+			),
+			Interval:      10 * time.Second,
+			Clock:         cmd.clock(),
+			Notifications: buildHandoffs,
+		}},
+
+		{"build-timeouts", builds.TrackerRunner{
+			Tracker: builds.NewTimeoutEnforcer(
+				logger.Session("build-timeout-enforcer"),
+				sqlDB,
+				engine,
+				cmd.DefaultBuildTimeout,
+				cmd.clock(),
 			),
 			Interval: 10 * time.Second,
-			Clock:    clock.NewClock(),
+			Clock:    cmd.clock(),
 		}},
 
-		{"builds", builds.TrackerRunner{
-			Tracker: builds.NewTracker(
-				logger.Session("build-tracker"),
+		{"build-reconciler", builds.TrackerRunner{
+			Tracker:  buildReconciler,
+			Interval: time.Minute,
+			Clock:    cmd.clock(),
+		}},
+
+		{"build-dependencies", builds.TrackerRunner{
+			Tracker: builds.NewDependencyStarter(
+				logger.Session("build-dependencies"),
 				sqlDB,
 				engine,
 			),
 			Interval: 10 * time.Second,
-			Clock:    clock.NewClock(),
+			Clock:    cmd.clock(),
 		}},
 
 		{"lostandfound", lockrunner.NewRunner(
 			logger.Session("lost-and-found"),
-			lostandfound.NewBaggageCollector(
-				logger.Session("baggage-collector"),
-				workerClient,
-				sqlDB,
-				pipelineDBFactory,
-				cmd.OldResourceGracePeriod,
-				24*time.Hour,
-			),
-			"baggage-collector",
+			baggageCollector,
+			lostandfound.TaskName,
 			sqlDB,
-			clock.NewClock(),
+			cmd.clock(),
 			cmd.ResourceCacheCleanupInterval,
 		)},
 
@@ -329,7 +683,7 @@ func (cmd *ATCCommand) Runner(args []string) (ifrit.Runner, error) {
 			),
 			"container-keepaliver",
 			sqlDB,
-			clock.NewClock(),
+			cmd.clock(),
 			30*time.Second,
 		)},
 
@@ -340,12 +694,97 @@ func (cmd *ATCCommand) Runner(args []string) (ifrit.Runner, error) {
 				sqlDB,
 				pipelineDBFactory,
 				500,
+				cmd.BuildLogRetentionPeriod,
+				cmd.BuildRetentionPeriod,
+				cmd.BuildsToRetain,
+				cmd.clock(),
 			),
 			"build-reaper",
 			sqlDB,
-			clock.NewClock(),
+			cmd.clock(),
 			30*time.Second,
 		)},
+
+		{"artifactreaper", lockrunner.NewRunner(
+			logger.Session("artifact-reaper-runner"),
+			artifactreaper.NewReaper(
+				logger.Session("artifact-reaper"),
+				sqlDB,
+				pipelineDBFactory,
+				workerClient,
+				artifactStore,
+				500,
+				cmd.clock(),
+			),
+			"artifact-reaper",
+			sqlDB,
+			cmd.clock(),
+			30*time.Second,
+		)},
+
+		{"flakiness", lockrunner.NewRunner(
+			logger.Session("flakiness-analyzer-runner"),
+			flakiness.NewAnalyzer(
+				logger.Session("flakiness-analyzer"),
+				sqlDB,
+				pipelineDBFactory,
+				100,
+				cmd.FlakyJobThreshold,
+			),
+			"flakiness-analyzer",
+			sqlDB,
+			cmd.clock(),
+			5*time.Minute,
+		)},
+	}
+
+	if cmd.ResourceVersionRetention != 0 {
+		members = append(members, grouper.Member{"versionpruner", lockrunner.NewRunner(
+			logger.Session("version-pruner-runner"),
+			versionpruner.NewVersionPruner(
+				logger.Session("version-pruner"),
+				sqlDB,
+				cmd.ResourceVersionRetention,
+				1000,
+			),
+			"version-pruner",
+			sqlDB,
+			cmd.clock(),
+			time.Minute,
+		)})
+	}
+
+	if cmd.DurationAlert.Percentile != 0 {
+		members = append(members, grouper.Member{"build-duration-alerts", builds.TrackerRunner{
+			Tracker: builds.NewDurationWatcher(
+				logger.Session("build-duration-watcher"),
+				sqlDB,
+				cmd.DurationAlert.Percentile,
+				cmd.DurationAlert.Window,
+				cmd.DurationAlert.MinBuilds,
+				cmd.clock(),
+			),
+			Interval: 30 * time.Second,
+			Clock:    cmd.clock(),
+		}})
+	}
+
+	if eventArchive != nil && cmd.BuildEventArchive.After != 0 {
+		members = append(members, grouper.Member{"buildarchiver", lockrunner.NewRunner(
+			logger.Session("build-archiver-runner"),
+			buildarchiver.NewBuildArchiver(
+				logger.Session("build-archiver"),
+				sqlDB,
+				eventArchive,
+				100,
+				cmd.BuildEventArchive.After,
+				cmd.clock(),
+			),
+			"build-archiver",
+			sqlDB,
+			cmd.clock(),
+			time.Minute,
+		)})
 	}
 
 	if cmd.Worker.GardenURL.URL() != nil {
@@ -353,15 +792,14 @@ func (cmd *ATCCommand) Runner(args []string) (ifrit.Runner, error) {
 	}
 
 	if httpsHandler != nil {
-		cert, err := tls.LoadX509KeyPair(string(cmd.TLSCert), string(cmd.TLSKey))
+		keyPair, err := mtls.LoadKeyPair(string(cmd.TLSCert), string(cmd.TLSKey), string(cmd.TLSClientCACert))
 		if err != nil {
 			return nil, err
 		}
 
-		tlsConfig := &tls.Config{
-			Certificates: []tls.Certificate{cert},
-			NextProtos:   []string{"h2"},
-		}
+		tlsConfig := keyPair.ServerConfig(&tls.Config{
+			NextProtos: []string{"h2"},
+		})
 
 		members = append(members, grouper.Member{"web-tls", http_server.NewTLSServer(
 			cmd.tlsBindAddr(),
@@ -375,6 +813,36 @@ func (cmd *ATCCommand) Runner(args []string) (ifrit.Runner, error) {
 		httpHandler,
 	)})
 
+	if cmd.GRPCBindPort != 0 {
+		grpcServer := grpcserver.NewGRPCServer(grpcserver.NewServer(
+			logger.Session("grpc"),
+			engine,
+			teamDBFactory,
+			sqlDB,
+			censorPolicies,
+			eventHub,
+			buildCreationLimiter,
+			auth.JWTValidator{PublicKey: &signingKey.PublicKey},
+			auth.JWTReader{PublicKey: &signingKey.PublicKey},
+			auth.NewAPITokenChecker(&signingKey.PublicKey, sqlDB),
+		))
+
+		members = append(members, grouper.Member{"grpc", grpcserver.Runner{
+			Addr:   cmd.grpcBindAddr(),
+			Server: grpcServer,
+		}})
+	}
+
+	if cmd.Metrics.PrometheusBindPort != 0 {
+		prometheusMux := http.NewServeMux()
+		prometheusMux.Handle("/metrics", metric.PrometheusHandler())
+
+		members = append(members, grouper.Member{"prometheus", http_server.New(
+			cmd.prometheusBindAddr(),
+			prometheusMux,
+		)})
+	}
+
 	return onReady(grouper.NewParallel(os.Interrupt, members), func() {
 		logData := lager.Data{
 			"http":  cmd.nonTLSBindAddr(),
@@ -385,6 +853,14 @@ func (cmd *ATCCommand) Runner(args []string) (ifrit.Runner, error) {
 			logData["https"] = cmd.tlsBindAddr()
 		}
 
+		if cmd.GRPCBindPort != 0 {
+			logData["grpc"] = cmd.grpcBindAddr()
+		}
+
+		if cmd.Metrics.PrometheusBindPort != 0 {
+			logData["prometheus"] = cmd.prometheusBindAddr()
+		}
+
 		logger.Info("listening", logData)
 	}), nil
 }
@@ -437,7 +913,7 @@ func (cmd *ATCCommand) oauthBaseURL() string {
 }
 
 func (cmd *ATCCommand) authConfigured() bool {
-	return cmd.BasicAuth.IsConfigured() || cmd.GitHubAuth.IsConfigured() || cmd.UAAAuth.IsConfigured() || cmd.GenericOAuth.IsConfigured()
+	return cmd.BasicAuth.IsConfigured() || cmd.GitHubAuth.IsConfigured() || cmd.UAAAuth.IsConfigured() || cmd.GenericOAuth.IsConfigured() || cmd.OIDCAuth.IsConfigured() || cmd.SAMLAuth.IsConfigured()
 }
 
 func (cmd *ATCCommand) gitHubAuthConfigured() bool {
@@ -477,6 +953,34 @@ func (cmd *ATCCommand) validate() error {
 		}
 	}
 
+	if cmd.OIDCAuth.IsConfigured() {
+		if cmd.ExternalURL.URL() == nil {
+			errs = multierror.Append(
+				errs,
+				errors.New("must specify --external-url to use OpenID Connect"),
+			)
+		}
+
+		err := cmd.OIDCAuth.Validate()
+		if err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+
+	if cmd.SAMLAuth.IsConfigured() {
+		if cmd.ExternalURL.URL() == nil {
+			errs = multierror.Append(
+				errs,
+				errors.New("must specify --external-url to use SAML"),
+			)
+		}
+
+		err := cmd.SAMLAuth.Validate()
+		if err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+
 	if cmd.BasicAuth.IsConfigured() {
 		err := cmd.BasicAuth.Validate()
 		if err != nil {
@@ -491,6 +995,24 @@ func (cmd *ATCCommand) validate() error {
 		}
 	}
 
+	if cmd.DurationAlert.Percentile < 0 || cmd.DurationAlert.Percentile > 100 {
+		errs = multierror.Append(
+			errs,
+			errors.New("--duration-alert-percentile must be from 1 to 100"),
+		)
+	}
+
+	if cmd.CORS.AllowCredentials {
+		for _, origin := range cmd.CORS.AllowedOrigins {
+			if origin == "*" {
+				errs = multierror.Append(
+					errs,
+					errors.New("must not allow any origin (--cors-allowed-origin '*') when allowing credentials"),
+				)
+			}
+		}
+	}
+
 	tlsFlagCount := 0
 	if cmd.TLSBindPort != 0 {
 		tlsFlagCount++
@@ -516,6 +1038,95 @@ func (cmd *ATCCommand) validate() error {
 		)
 	}
 
+	if cmd.TLSClientCACert != "" && tlsFlagCount != 3 {
+		errs = multierror.Append(
+			errs,
+			errors.New("must use TLS to verify client certificates with --tls-client-ca-cert"),
+		)
+	}
+
+	if cmd.BuildEventArchive.Endpoint.URL() != nil {
+		if cmd.BuildEventArchive.Bucket == "" {
+			errs = multierror.Append(
+				errs,
+				errors.New("must specify --build-event-archive-bucket to use the build event archive"),
+			)
+		}
+	} else if cmd.BuildEventArchive.After != 0 {
+		errs = multierror.Append(
+			errs,
+			errors.New("must specify --build-event-archive-endpoint to archive build events"),
+		)
+	}
+
+	switch cmd.Storage.Driver {
+	case "local":
+		if cmd.Storage.Dir == "" {
+			errs = multierror.Append(
+				errs,
+				errors.New("must specify --storage-dir to use the local storage driver"),
+			)
+		}
+	case "s3", "gcs":
+		if cmd.Storage.Bucket == "" {
+			errs = multierror.Append(
+				errs,
+				fmt.Errorf("must specify --storage-bucket to use the %s storage driver", cmd.Storage.Driver),
+			)
+		}
+
+		if cmd.Storage.Driver == "s3" && cmd.Storage.Endpoint.URL() == nil {
+			errs = multierror.Append(
+				errs,
+				errors.New("must specify --storage-endpoint to use the s3 storage driver"),
+			)
+		}
+	}
+
+	if cmd.Vault.URL.URL() != nil && cmd.CredHub.URL.URL() != nil {
+		errs = multierror.Append(
+			errs,
+			errors.New("must specify only one of --vault-url and --credhub-url"),
+		)
+	}
+
+	if cmd.Vault.URL.URL() != nil && cmd.Vault.ClientToken == "" {
+		errs = multierror.Append(
+			errs,
+			errors.New("must specify --vault-client-token to use Vault"),
+		)
+	}
+
+	if cmd.CredHub.URL.URL() != nil && (cmd.CredHub.ClientID == "" || cmd.CredHub.ClientSecret == "") {
+		errs = multierror.Append(
+			errs,
+			errors.New("must specify --credhub-client-id and --credhub-client-secret to use CredHub"),
+		)
+	}
+
+	if (cmd.WorkerClient.TLSCert == "") != (cmd.WorkerClient.TLSKey == "") {
+		errs = multierror.Append(
+			errs,
+			errors.New("must specify both --worker-client-tls-cert and --worker-client-tls-key to present a client certificate to workers"),
+		)
+	}
+
+	if cmd.WorkerClient.MutualTLS && cmd.WorkerClient.TLSCert == "" {
+		errs = multierror.Append(
+			errs,
+			errors.New("must specify --worker-client-tls-cert and --worker-client-tls-key to use --worker-client-mutual-tls"),
+		)
+	}
+
+	for route := range cmd.EventStreamKeepAlives {
+		if route != atc.BuildEvents && route != atc.MultiplexEvents && route != atc.WaitForBuild {
+			errs = multierror.Append(
+				errs,
+				fmt.Errorf("unknown event stream route '%s' for --event-stream-keepalive; available: %s, %s, %s", route, atc.BuildEvents, atc.MultiplexEvents, atc.WaitForBuild),
+			)
+		}
+	}
+
 	return errs.ErrorOrNil()
 }
 
@@ -543,6 +1154,14 @@ func (cmd *ATCCommand) debugBindAddr() string {
 	return fmt.Sprintf("%s:%d", cmd.DebugBindIP, cmd.DebugBindPort)
 }
 
+func (cmd *ATCCommand) grpcBindAddr() string {
+	return fmt.Sprintf("%s:%d", cmd.BindIP, cmd.GRPCBindPort)
+}
+
+func (cmd *ATCCommand) prometheusBindAddr() string {
+	return fmt.Sprintf("%s:%d", cmd.Metrics.PrometheusBindIP, cmd.Metrics.PrometheusBindPort)
+}
+
 func (cmd *ATCCommand) constructLogger() (lager.Logger, *lager.ReconfigurableSink) {
 	logger := lager.NewLogger("atc")
 
@@ -586,9 +1205,65 @@ func (cmd *ATCCommand) constructDBConn(logger lager.Logger) (db.Conn, error) {
 		return nil, fmt.Errorf("failed to migrate database: %s", err)
 	}
 
-	dbConn.SetMaxOpenConns(64)
+	// migrations can take a while, so they run without the timeout, on a
+	// connection of their own
+	if cmd.DatabaseQueryTimeout > 0 {
+		dbConn.Close()
+
+		dbConn, err = db.WrapWithError(sql.Open(driverName, withStatementTimeout(cmd.PostgresDataSource, cmd.DatabaseQueryTimeout)))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	cmd.setPoolLimits(dbConn)
 
-	return metric.CountQueries(dbConn), nil
+	metric.DatabasePool.Watch(dbConn.Stats)
+
+	conn := db.WithEncryption(metric.CountQueries(dbConn), cmd.encryptionStrategy())
+
+	if cmd.BuildEventSigningKey != "" {
+		conn = db.WithEventSigning(conn, []byte(cmd.BuildEventSigningKey))
+	}
+
+	if tracing.Configured() {
+		conn = tracing.TraceQueries(conn)
+	}
+
+	return conn, nil
+}
+
+func (cmd *ATCCommand) setPoolLimits(conn db.Conn) {
+	if cmd.DatabaseMaxOpenConnections > 0 {
+		conn.SetMaxOpenConns(cmd.DatabaseMaxOpenConnections)
+	}
+
+	if cmd.DatabaseMaxIdleConnections > 0 {
+		conn.SetMaxIdleConns(cmd.DatabaseMaxIdleConnections)
+	}
+
+	if cmd.DatabaseConnectionLifetime > 0 {
+		conn.SetConnMaxLifetime(cmd.DatabaseConnectionLifetime)
+	}
+}
+
+// withStatementTimeout adds a statement_timeout to the data source, which
+// lib/pq passes along to be set on every connection it opens. Data sources
+// may be either URLs or space-separated key=value pairs.
+func withStatementTimeout(dataSource string, timeout time.Duration) string {
+	milliseconds := strconv.FormatInt(int64(timeout/time.Millisecond), 10)
+
+	if strings.HasPrefix(dataSource, "postgres://") ||
+		strings.HasPrefix(dataSource, "postgresql://") {
+		separator := "?"
+		if strings.Contains(dataSource, "?") {
+			separator = "&"
+		}
+
+		return dataSource + separator + "statement_timeout=" + milliseconds
+	}
+
+	return dataSource + " statement_timeout=" + milliseconds
 }
 
 func (cmd *ATCCommand) constructLockConn() (*db.RetryableConn, error) {
@@ -616,9 +1291,25 @@ func (cmd *ATCCommand) constructLockConn() (*db.RetryableConn, error) {
 	return &db.RetryableConn{Connector: connector, Conn: pgxConn}, nil
 }
 
+func (cmd *ATCCommand) configureTracing(logger lager.Logger) {
+	resource := tracing.Attrs{}
+	for k, v := range cmd.Tracing.Attributes {
+		resource[k] = v
+	}
+
+	resource["service.name"] = cmd.Tracing.ServiceName
+
+	tracing.Configure(
+		logger.Session("tracing"),
+		tracing.NewOTLPExporter(cmd.Tracing.OTLPEndpoint.String(), cmd.Tracing.OTLPHeaders, resource),
+		cmd.Tracing.FlushInterval,
+	)
+}
+
 func (cmd *ATCCommand) constructWorkerPool(
 	logger lager.Logger,
 	sqlDB *db.SQLDB,
+	dialer gconn.DialerFunc,
 	trackerFactory resource.TrackerFactory,
 	resourceFetcherFactory resource.FetcherFactory,
 ) worker.Client {
@@ -626,7 +1317,7 @@ func (cmd *ATCCommand) constructWorkerPool(
 		worker.NewDBWorkerProvider(
 			logger,
 			sqlDB,
-			keepaliveDialer,
+			dialer,
 			transport.ExponentialRetryPolicy{
 				Timeout: 5 * time.Minute,
 			},
@@ -746,6 +1437,166 @@ func (cmd *ATCCommand) configureAuthForDefaultTeam(teamDBFactory db.TeamDBFactor
 		return err
 	}
 
+	var oidcAuth *db.OIDCAuth
+	if cmd.OIDCAuth.IsConfigured() {
+		oidcAuth = &db.OIDCAuth{
+			DisplayName:  cmd.OIDCAuth.DisplayName,
+			Issuer:       cmd.OIDCAuth.Issuer,
+			ClientID:     cmd.OIDCAuth.ClientID,
+			ClientSecret: cmd.OIDCAuth.ClientSecret,
+			Scopes:       cmd.OIDCAuth.Scopes,
+			GroupsClaim:  cmd.OIDCAuth.GroupsClaim,
+			Groups:       cmd.OIDCAuth.Groups,
+			Users:        cmd.OIDCAuth.Users,
+		}
+	}
+
+	_, err = teamDB.UpdateOIDCAuth(oidcAuth)
+	if err != nil {
+		return err
+	}
+
+	var samlAuth *db.SAMLAuth
+	if cmd.SAMLAuth.IsConfigured() {
+		idpCertificate, err := ioutil.ReadFile(string(cmd.SAMLAuth.IdPCertificate))
+		if err != nil {
+			return err
+		}
+
+		samlAuth = &db.SAMLAuth{
+			DisplayName:     cmd.SAMLAuth.DisplayName,
+			SSOURL:          cmd.SAMLAuth.SSOURL,
+			IdPEntityID:     cmd.SAMLAuth.IdPEntityID,
+			IdPCertificate:  string(idpCertificate),
+			GroupsAttribute: cmd.SAMLAuth.GroupsAttribute,
+			Groups:          cmd.SAMLAuth.Groups,
+			Users:           cmd.SAMLAuth.Users,
+		}
+	}
+
+	_, err = teamDB.UpdateSAMLAuth(samlAuth)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (cmd *ATCCommand) openReadReplica(logger lager.Logger) (*db.ReadReplica, error) {
+	dataSource := cmd.PostgresReadReplicaDataSource
+	if cmd.DatabaseQueryTimeout > 0 {
+		dataSource = withStatementTimeout(dataSource, cmd.DatabaseQueryTimeout)
+	}
+
+	// the replica's schema comes from the primary, so it's left alone rather
+	// than migrated
+	replicaConn, err := db.WrapWithError(sql.Open("postgres", dataSource))
+	if err != nil {
+		return nil, err
+	}
+
+	cmd.setPoolLimits(replicaConn)
+
+	conn := db.WithEncryption(metric.CountQueries(replicaConn), cmd.encryptionStrategy())
+
+	if tracing.Configured() {
+		conn = tracing.TraceQueries(conn)
+	}
+
+	return db.NewReadReplica(logger.Session("read-replica"), conn, cmd.PostgresReadReplicaMaxLag), nil
+}
+
+// encryptionStrategy encrypts with --encryption-key, if given, falling back
+// on --old-encryption-key to decrypt what hasn't been rewritten yet.
+func (cmd *ATCCommand) encryptionStrategy() encryption.Strategy {
+	var strategy encryption.Strategy = encryption.NewNoEncryption()
+	if cmd.EncryptionKey.AEAD() != nil {
+		strategy = encryption.NewKey(cmd.EncryptionKey.AEAD())
+	}
+
+	if cmd.OldEncryptionKey.AEAD() != nil {
+		strategy = encryption.NewRotationStrategy(strategy, encryption.NewKey(cmd.OldEncryptionKey.AEAD()))
+	}
+
+	return strategy
+}
+
+func (cmd *ATCCommand) reencrypt() error {
+	if cmd.EncryptionKey.AEAD() == nil && cmd.OldEncryptionKey.AEAD() == nil {
+		return errors.New("must specify --encryption-key and/or --old-encryption-key to --reencrypt")
+	}
+
+	logger, _ := cmd.constructLogger()
+
+	dbConn, err := cmd.constructDBConn(logger)
+	if err != nil {
+		return err
+	}
+
+	defer dbConn.Close()
+
+	return db.Reencrypt(logger.Session("reencrypt"), dbConn)
+}
+
+func (cmd *ATCCommand) compressBuildEvents() error {
+	logger, _ := cmd.constructLogger()
+
+	dbConn, err := cmd.constructDBConn(logger)
+	if err != nil {
+		return err
+	}
+
+	defer dbConn.Close()
+
+	return db.CompressBuildEvents(logger.Session("compress-build-events"), dbConn)
+}
+
+// constructCredsManager returns nil if no credential manager is configured.
+func (cmd *ATCCommand) constructStorageDriver() storage.Driver {
+	switch cmd.Storage.Driver {
+	case "local":
+		return storage.NewLocalDriver(cmd.Storage.Dir.Path())
+	case "s3":
+		return storage.NewS3Driver(
+			cmd.Storage.Endpoint.URL(),
+			cmd.Storage.Bucket,
+			cmd.Storage.Region,
+			cmd.Storage.AccessKeyID,
+			cmd.Storage.SecretAccessKey,
+		)
+	case "gcs":
+		return storage.NewGCSDriver(
+			cmd.Storage.Bucket,
+			cmd.Storage.AccessKeyID,
+			cmd.Storage.SecretAccessKey,
+		)
+	default:
+		return nil
+	}
+}
+
+func (cmd *ATCCommand) constructCredsManager() creds.Manager {
+	insecureSkipVerify := cmd.AllowSelfSignedCertificates || cmd.Developer.DevelopmentMode
+
+	switch {
+	case cmd.Vault.URL.URL() != nil:
+		return creds.NewVaultManager(
+			cmd.Vault.URL.URL(),
+			cmd.Vault.ClientToken,
+			cmd.Vault.PathPrefix,
+			insecureSkipVerify,
+		)
+
+	case cmd.CredHub.URL.URL() != nil:
+		return creds.NewCredHubManager(
+			cmd.CredHub.URL.URL(),
+			cmd.CredHub.ClientID,
+			cmd.CredHub.ClientSecret,
+			cmd.CredHub.PathPrefix,
+			insecureSkipVerify,
+		)
+	}
+
 	return nil
 }
 
@@ -754,33 +1605,61 @@ func (cmd *ATCCommand) constructEngine(
 	tracker resource.Tracker,
 	resourceFetcher resource.Fetcher,
 	teamDBFactory db.TeamDBFactory,
+	buildHandoff *engine.Handoff,
+	taskCaches exec.TaskCacheStore,
 ) engine.Engine {
 	gardenFactory := exec.NewGardenFactory(
 		workerClient,
 		tracker,
 		resourceFetcher,
+		taskCaches,
 	)
 
 	execV2Engine := engine.NewExecEngine(
 		gardenFactory,
-		engine.NewBuildDelegateFactory(),
+		engine.NewBuildDelegateFactory(cmd.MaxBuildLogBytes),
 		teamDBFactory,
 		cmd.ExternalURL.String(),
 	)
 
 	execV1Engine := engine.NewExecV1DummyEngine()
 
-	return engine.NewDBEngine(engine.Engines{execV2Engine, execV1Engine})
+	engines := engine.Engines{execV2Engine, execV1Engine}
+	if cmd.Developer.FakeBuilds {
+		// new builds are created with the first engine
+		engines = engine.Engines{fakebuilder.NewEngine(cmd.clock()), execV2Engine, execV1Engine}
+	}
+
+	notifier := notify.NewNotifier(
+		teamDBFactory,
+		cmd.ExternalURL.String(),
+		&http.Client{Timeout: 30 * time.Second},
+		cmd.clock(),
+	)
+
+	return engine.NewDBEngine(engines, notifier, buildHandoff)
+}
+
+func (cmd *ATCCommand) clock() clock.Clock {
+	if cmd.Clock != nil {
+		return cmd.Clock
+	}
+
+	return clock.NewClock()
 }
 
 func (cmd *ATCCommand) constructHTTPHandler(
 	webHandler http.Handler,
 	apiHandler http.Handler,
 	oauthHandler http.Handler,
+	healthHandler http.Handler,
 ) http.Handler {
 	webMux := http.NewServeMux()
 	webMux.Handle("/api/v1/", apiHandler)
+	webMux.Handle("/api/v2/", apiHandler)
 	webMux.Handle("/auth/", oauthHandler)
+	webMux.Handle("/healthz", healthHandler)
+	webMux.Handle("/readyz", healthHandler)
 	webMux.Handle("/", webHandler)
 
 	var httpHandler http.Handler
@@ -800,16 +1679,32 @@ func (cmd *ATCCommand) constructAPIHandler(
 	logger lager.Logger,
 	reconfigurableSink *lager.ReconfigurableSink,
 	sqlDB *db.SQLDB,
+	readDB *db.SQLDB,
+	buildsDB auth.BuildsDB,
 	teamDBFactory db.TeamDBFactory,
 	providerFactory provider.OAuthFactory,
 	signingKey *rsa.PrivateKey,
 	pipelineDBFactory db.PipelineDBFactory,
 	engine engine.Engine,
 	workerClient worker.Client,
+	workerKeyPair *mtls.KeyPair,
 	drain <-chan struct{},
 	radarSchedulerFactory pipelines.RadarSchedulerFactory,
 	radarScannerFactory radar.ScannerFactory,
+	censorPolicies buildserver.CensorPolicies,
+	eventHub *buildserver.EventHub,
+	buildCreationLimiter *ratelimit.Limiter,
+	baggageCollector lostandfound.BaggageCollector,
+	credsManager creds.Manager,
+	artifactStore buildserver.ArtifactStore,
+	taskCaches jobserver.TaskCacheInvalidator,
+	buildReconciler buildserver.BuildReconciler,
 ) (http.Handler, error) {
+	workerHTTPClient, err := cmd.constructWorkerHTTPClient(workerKeyPair)
+	if err != nil {
+		return nil, err
+	}
+
 	authValidator := auth.JWTValidator{
 		PublicKey: &signingKey.PublicKey,
 	}
@@ -821,12 +1716,17 @@ func (cmd *ATCCommand) constructAPIHandler(
 		teamDBFactory,
 	)
 
-	checkBuildReadAccessHandlerFactory := auth.NewCheckBuildReadAccessHandlerFactory(sqlDB)
+	// only reads go through the cache; writes look the build up afresh
+	checkBuildReadAccessHandlerFactory := auth.NewCheckBuildReadAccessHandlerFactory(buildsDB)
 
 	checkBuildWriteAccessHandlerFactory := auth.NewCheckBuildWriteAccessHandlerFactory(sqlDB)
 
 	apiWrapper := wrappa.MultiWrappa{
+		wrappa.NewAuditWrappa(logger, sqlDB),
 		wrappa.NewAPIMetricsWrappa(logger),
+		// roles are checked within the auth wrappa, as it is what finds them
+		wrappa.NewPipelinePermissionWrappa(teamDBFactory),
+		wrappa.NewRoleWrappa(),
 		wrappa.NewAPIAuthWrappa(
 			authValidator,
 			getTokenValidator,
@@ -835,9 +1735,42 @@ func (cmd *ATCCommand) constructAPIHandler(
 			checkBuildReadAccessHandlerFactory,
 			checkBuildWriteAccessHandlerFactory,
 		),
-		wrappa.NewConcourseVersionWrappa(Version),
+		wrappa.NewScopeWrappa(auth.NewAPITokenChecker(&signingKey.PublicKey, sqlDB)),
+		// outermost, so that requests turned away are traced too
+		wrappa.NewAPITracingWrappa(),
+	}
+
+	if cmd.TLSClientCACert != "" {
+		apiWrapper = append(apiWrapper, wrappa.NewClientCertWrappa(logger))
+	}
+
+	clientIPResolver := wrappa.ClientIPResolver{
+		TrustedProxies: cidrNetworks(cmd.TrustedProxies),
+	}
+
+	if buildCreationLimiter != nil {
+		apiWrapper = append(apiWrapper, wrappa.NewRateLimitWrappa(
+			logger,
+			buildCreationLimiter,
+			clientIPResolver,
+		))
 	}
 
+	// outside of the rate limit, so that requests turned away don't count
+	// towards it
+	apiWrapper = append(apiWrapper, wrappa.NewNetworkPolicyWrappa(
+		logger,
+		clientIPResolver,
+		map[auth.Scope][]*net.IPNet{
+			auth.ScopeRead:    cidrNetworks(cmd.NetworkPolicy.Read),
+			auth.ScopeTrigger: cidrNetworks(cmd.NetworkPolicy.Trigger),
+			auth.ScopeAdmin:   cidrNetworks(cmd.NetworkPolicy.Admin),
+		},
+	))
+
+	apiWrapper = append(apiWrapper, wrappa.NewConcourseVersionWrappa(Version))
+	apiWrapper = append(apiWrapper, wrappa.NewRequestLogWrappa(logger.Session("api")))
+
 	return api.NewHandler(
 		logger,
 		cmd.ExternalURL.String(),
@@ -850,27 +1783,52 @@ func (cmd *ATCCommand) constructAPIHandler(
 		pipelineDBFactory,
 		teamDBFactory,
 
-		sqlDB, // teamserver.TeamDB
-		sqlDB, // workerserver.WorkerDB
-		sqlDB, // buildserver.BuildsDB
-		sqlDB, // containerserver.ContainerDB
-		sqlDB, // volumeserver.VolumesDB
-		sqlDB, // pipes.PipeDB
-		sqlDB, // db.PipelinesDB
+		sqlDB,  // teamserver.TeamDB
+		sqlDB,  // workerserver.WorkerDB
+		readDB, // buildserver.BuildsDB
+		sqlDB,  // containerserver.ContainerDB
+		sqlDB,  // volumeserver.VolumesDB
+		sqlDB,  // pipes.PipeDB
+		sqlDB,  // db.PipelinesDB
+		sqlDB,  // auditserver.AuditDB
+		sqlDB,  // hookserver.WebhookDB
+		sqlDB,  // tokenserver.APITokenDB
+		sqlDB,  // infoserver.LeaderDB
+		sqlDB,  // resourcetypeserver.ResourceTypesDB
+		sqlDB,  // gcserver.GCDB
+		sqlDB,  // usageserver.UsageDB
+		sqlDB,  // queueserver.QueueDB
+		sqlDB,  // alertserver.AlertsDB
 
 		config.ValidateConfig,
+		credsManager,
 		cmd.PeerURL.String(),
-		buildserver.NewEventHandler,
+		buildserver.NewCensoringEventHandlerFactory(censorPolicies, eventHub),
+		censorPolicies,
+		eventHub,
 		drain,
+		cmd.EventStreamDrainGracePeriod,
+		buildserver.KeepAlivePolicy{
+			Interval: cmd.EventStreamKeepAliveInterval,
+			Routes:   cmd.EventStreamKeepAlives,
+		},
+		artifactStore,
+		taskCaches,
+		buildReconciler,
 
 		engine,
 		workerClient,
+		workerHTTPClient,
 		radarSchedulerFactory,
 		radarScannerFactory,
 
+		baggageCollector,
+
 		reconfigurableSink,
 
 		cmd.CLIArtifactsDir.Path(),
+		cmd.CLIDownloadURL,
+		&http.Client{Timeout: 5 * time.Minute},
 		Version,
 	)
 }
@@ -915,6 +1873,7 @@ func (cmd *ATCCommand) constructPipelineSyncer(
 						radarSchedulerFactory.BuildScanRunnerFactory(pipelineDB, cmd.ExternalURL.String()),
 						pipelineDB,
 						1*time.Minute,
+						cmd.clock(),
 					),
 				},
 				{
@@ -928,6 +1887,24 @@ func (cmd *ATCCommand) constructPipelineSyncer(
 
 						Noop: cmd.Developer.Noop,
 
+						Clock: cmd.clock(),
+
+						Interval: cmd.SchedulerSweepInterval,
+					},
+				},
+				{
+					pipelineDB.ScopedName("cron"),
+					&scheduler.CronRunner{
+						Logger: logger.Session(pipelineDB.ScopedName("cron")),
+
+						DB: pipelineDB,
+
+						Scheduler: radarSchedulerFactory.BuildScheduler(pipelineDB, cmd.ExternalURL.String()),
+
+						Clock: cmd.clock(),
+
+						Noop: cmd.Developer.Noop,
+
 						Interval: 10 * time.Second,
 					},
 				},
@@ -955,7 +1932,7 @@ func (cmd *ATCCommand) appendStaticWorker(
 			Runner: worker.NewHardcoded(
 				logger,
 				sqlDB,
-				clock.NewClock(),
+				cmd.clock(),
 				cmd.Worker.GardenURL.URL().Host,
 				cmd.Worker.BaggageclaimURL.String(),
 				resourceTypes,
@@ -0,0 +1,91 @@
+package atccmd
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+
+	gconn "code.cloudfoundry.org/garden/client/connection"
+	"github.com/concourse/atc/mtls"
+	"github.com/concourse/atc/tracing"
+)
+
+// loadWorkerKeyPair loads the client certificate to present to workers, if
+// one has been given.
+func (cmd *ATCCommand) loadWorkerKeyPair() (*mtls.KeyPair, error) {
+	if cmd.WorkerClient.TLSCert == "" {
+		return nil, nil
+	}
+
+	return mtls.LoadKeyPair(
+		string(cmd.WorkerClient.TLSCert),
+		string(cmd.WorkerClient.TLSKey),
+		string(cmd.WorkerClient.CACert),
+	)
+}
+
+// workerDialer is how connections to workers' Garden and Baggageclaim servers
+// are made; over TLS if --worker-client-mutual-tls is set.
+func (cmd *ATCCommand) workerDialer(keyPair *mtls.KeyPair) gconn.DialerFunc {
+	if cmd.WorkerClient.MutualTLS {
+		return keyPair.Dialer(keepaliveDialer, cmd.WorkerClient.TLSHandshakeTimeout)
+	}
+
+	return keepaliveDialer
+}
+
+// constructWorkerHTTPClient returns the client for talking to workers over
+// HTTP, configured by the --worker-client-* flags.
+func (cmd *ATCCommand) constructWorkerHTTPClient(keyPair *mtls.KeyPair) (*http.Client, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: cmd.AllowSelfSignedCertificates || cmd.Developer.DevelopmentMode,
+	}
+
+	if cmd.WorkerClient.CACert != "" {
+		caCert, err := ioutil.ReadFile(string(cmd.WorkerClient.CACert))
+		if err != nil {
+			return nil, err
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("no certificates found in '%s'", cmd.WorkerClient.CACert)
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	proxy := http.ProxyFromEnvironment
+	if cmd.WorkerClient.Proxy.URL() != nil {
+		proxy = http.ProxyURL(cmd.WorkerClient.Proxy.URL())
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   cmd.WorkerClient.DialTimeout,
+		KeepAlive: cmd.WorkerClient.KeepAlive,
+	}
+
+	httpTransport := &http.Transport{
+		Proxy:                 proxy,
+		Dial:                  dialer.Dial,
+		TLSClientConfig:       tlsConfig,
+		TLSHandshakeTimeout:   cmd.WorkerClient.TLSHandshakeTimeout,
+		ResponseHeaderTimeout: cmd.WorkerClient.ResponseHeaderTimeout,
+		IdleConnTimeout:       cmd.WorkerClient.IdleConnTimeout,
+		MaxIdleConnsPerHost:   cmd.WorkerClient.MaxIdleConns,
+		DisableKeepAlives:     cmd.WorkerClient.DisableKeepAlives,
+	}
+
+	// presenting the certificate ourselves picks up rotations of it
+	if keyPair != nil {
+		httpTransport.DialTLS = keyPair.Dialer(dialer.Dial, cmd.WorkerClient.TLSHandshakeTimeout)
+	}
+
+	// requests made on behalf of a traced API request join its trace
+	return &http.Client{
+		Transport: &tracing.Transport{Base: httpTransport},
+	}, nil
+}
@@ -0,0 +1,12 @@
+package atc
+
+type AuditEvent struct {
+	ID       int    `json:"id"`
+	Time     int64  `json:"time"`
+	Actor    string `json:"actor"`
+	Action   string `json:"action"`
+	Resource string `json:"resource"`
+
+	BodySHA256 string `json:"body_sha256"`
+	Status     int    `json:"status"`
+}
@@ -0,0 +1,233 @@
+package auth_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/auth"
+	"github.com/concourse/atc/auth/authfakes"
+	"github.com/concourse/atc/db"
+	jwt "github.com/dgrijalva/jwt-go"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("APITokenChecker", func() {
+	var (
+		signingKey     *rsa.PrivateKey
+		tokenGenerator auth.TokenGenerator
+		fakeDB         *authfakes.FakeAPITokenDB
+
+		checker auth.APITokenChecker
+		request *http.Request
+	)
+
+	BeforeEach(func() {
+		var err error
+		signingKey, err = rsa.GenerateKey(rand.Reader, 1024)
+		Expect(err).NotTo(HaveOccurred())
+
+		tokenGenerator = auth.NewTokenGenerator(signingKey)
+
+		fakeDB = new(authfakes.FakeAPITokenDB)
+		fakeDB.GetAPITokenReturns(db.APIToken{ID: 42}, true, nil)
+
+		checker = auth.NewAPITokenChecker(&signingKey.PublicKey, fakeDB)
+
+		request, err = http.NewRequest("GET", "/", nil)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	authorizeWith := func(tokenType auth.TokenType, tokenValue auth.TokenValue, err error) {
+		Expect(err).NotTo(HaveOccurred())
+		request.Header.Set("Authorization", string(tokenType)+" "+string(tokenValue))
+	}
+
+	Context("with an API token", func() {
+		BeforeEach(func() {
+			authorizeWith(tokenGenerator.GenerateAPIToken(42, []auth.Scope{auth.ScopeTrigger}, time.Time{}, "some-team", 1, false, atc.RoleOperator))
+		})
+
+		It("allows its own scope and the ones below it", func() {
+			Expect(checker.Check(request, auth.ScopeRead)).To(Succeed())
+			Expect(checker.Check(request, auth.ScopeTrigger)).To(Succeed())
+
+			Expect(fakeDB.GetAPITokenArgsForCall(0)).To(Equal(42))
+		})
+
+		It("does not allow the ones above it", func() {
+			Expect(checker.Check(request, auth.ScopeAdmin)).To(Equal(auth.ErrInsufficientScope))
+		})
+
+		Context("when the token has been revoked", func() {
+			BeforeEach(func() {
+				fakeDB.GetAPITokenReturns(db.APIToken{}, false, nil)
+			})
+
+			It("rejects it", func() {
+				Expect(checker.Check(request, auth.ScopeRead)).To(Equal(auth.ErrAPITokenRevoked))
+			})
+		})
+
+		Context("when looking the token up fails", func() {
+			disaster := errors.New("nope")
+
+			BeforeEach(func() {
+				fakeDB.GetAPITokenReturns(db.APIToken{}, false, disaster)
+			})
+
+			It("returns the error", func() {
+				Expect(checker.Check(request, auth.ScopeRead)).To(Equal(disaster))
+			})
+		})
+	})
+
+	Context("with a session token", func() {
+		BeforeEach(func() {
+			authorizeWith(tokenGenerator.GenerateToken(time.Now().Add(time.Hour), "some-team", 1, false, atc.RoleAdmin))
+		})
+
+		It("leaves it to the other checks", func() {
+			Expect(checker.Check(request, auth.ScopeAdmin)).To(Succeed())
+			Expect(fakeDB.GetAPITokenCallCount()).To(BeZero())
+		})
+	})
+
+	Context("without a token", func() {
+		It("leaves it to the other checks", func() {
+			Expect(checker.Check(request, auth.ScopeAdmin)).To(Succeed())
+		})
+	})
+})
+
+var _ = Describe("JWTReader with API tokens", func() {
+	var (
+		signingKey *rsa.PrivateKey
+		reader     auth.JWTReader
+		request    *http.Request
+	)
+
+	BeforeEach(func() {
+		var err error
+		signingKey, err = rsa.GenerateKey(rand.Reader, 1024)
+		Expect(err).NotTo(HaveOccurred())
+
+		reader = auth.JWTReader{PublicKey: &signingKey.PublicKey}
+
+		request, err = http.NewRequest("GET", "/", nil)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("reads the role the token was given", func() {
+		tokenType, tokenValue, err := auth.NewTokenGenerator(signingKey).GenerateAPIToken(42, []auth.Scope{auth.ScopeRead}, time.Time{}, "some-team", 1, false, atc.RoleOperator)
+		Expect(err).NotTo(HaveOccurred())
+		request.Header.Set("Authorization", string(tokenType)+" "+string(tokenValue))
+
+		role, found := reader.GetRole(request)
+		Expect(found).To(BeTrue())
+		Expect(role).To(Equal(atc.RoleOperator))
+	})
+
+	Context("when the token was made without a role", func() {
+		BeforeEach(func() {
+			tokenValue, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+				"teamName": "some-team",
+				"teamID":   1,
+				"isAdmin":  false,
+				"tokenID":  42,
+				"scopes":   []auth.Scope{auth.ScopeRead},
+			}).SignedString(signingKey)
+			Expect(err).NotTo(HaveOccurred())
+			request.Header.Set("Authorization", "Bearer "+tokenValue)
+		})
+
+		It("gives it the most restricted role", func() {
+			role, found := reader.GetRole(request)
+			Expect(found).To(BeTrue())
+			Expect(role).To(Equal(atc.RoleViewer))
+		})
+	})
+})
+
+var _ = Describe("CheckScopeHandler", func() {
+	var (
+		fakeChecker  *authfakes.FakeAPITokenChecker
+		fakeRejector *authfakes.FakeRejector
+
+		handler  http.Handler
+		served   bool
+		response *httptest.ResponseRecorder
+	)
+
+	BeforeEach(func() {
+		fakeChecker = new(authfakes.FakeAPITokenChecker)
+		fakeRejector = new(authfakes.FakeRejector)
+
+		fakeRejector.UnauthorizedStub = func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+
+		fakeRejector.ForbiddenStub = func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		}
+
+		served = false
+		handler = auth.CheckScopeHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			served = true
+		}), auth.ScopeTrigger, fakeChecker, fakeRejector)
+
+		response = httptest.NewRecorder()
+	})
+
+	JustBeforeEach(func() {
+		request, err := http.NewRequest("POST", "/", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(response, request)
+	})
+
+	It("checks for the handler's scope", func() {
+		_, scope := fakeChecker.CheckArgsForCall(0)
+		Expect(scope).To(Equal(auth.ScopeTrigger))
+		Expect(served).To(BeTrue())
+	})
+
+	Context("when the token lacks the scope", func() {
+		BeforeEach(func() {
+			fakeChecker.CheckReturns(auth.ErrInsufficientScope)
+		})
+
+		It("is forbidden", func() {
+			Expect(served).To(BeFalse())
+			Expect(response.Code).To(Equal(http.StatusForbidden))
+		})
+	})
+
+	Context("when the token has been revoked", func() {
+		BeforeEach(func() {
+			fakeChecker.CheckReturns(auth.ErrAPITokenRevoked)
+		})
+
+		It("is unauthorized", func() {
+			Expect(served).To(BeFalse())
+			Expect(response.Code).To(Equal(http.StatusUnauthorized))
+		})
+	})
+
+	Context("when checking fails", func() {
+		BeforeEach(func() {
+			fakeChecker.CheckReturns(errors.New("nope"))
+		})
+
+		It("errors", func() {
+			Expect(served).To(BeFalse())
+			Expect(response.Code).To(Equal(http.StatusInternalServerError))
+		})
+	})
+})
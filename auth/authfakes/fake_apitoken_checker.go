@@ -0,0 +1,79 @@
+// This file was generated by counterfeiter
+package authfakes
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/concourse/atc/auth"
+)
+
+type FakeAPITokenChecker struct {
+	CheckStub        func(r *http.Request, required auth.Scope) error
+	checkMutex       sync.RWMutex
+	checkArgsForCall []struct {
+		r        *http.Request
+		required auth.Scope
+	}
+	checkReturns struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeAPITokenChecker) Check(r *http.Request, required auth.Scope) error {
+	fake.checkMutex.Lock()
+	fake.checkArgsForCall = append(fake.checkArgsForCall, struct {
+		r        *http.Request
+		required auth.Scope
+	}{r, required})
+	fake.recordInvocation("Check", []interface{}{r, required})
+	fake.checkMutex.Unlock()
+	if fake.CheckStub != nil {
+		return fake.CheckStub(r, required)
+	} else {
+		return fake.checkReturns.result1
+	}
+}
+
+func (fake *FakeAPITokenChecker) CheckCallCount() int {
+	fake.checkMutex.RLock()
+	defer fake.checkMutex.RUnlock()
+	return len(fake.checkArgsForCall)
+}
+
+func (fake *FakeAPITokenChecker) CheckArgsForCall(i int) (*http.Request, auth.Scope) {
+	fake.checkMutex.RLock()
+	defer fake.checkMutex.RUnlock()
+	return fake.checkArgsForCall[i].r, fake.checkArgsForCall[i].required
+}
+
+func (fake *FakeAPITokenChecker) CheckReturns(result1 error) {
+	fake.CheckStub = nil
+	fake.checkReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAPITokenChecker) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.checkMutex.RLock()
+	defer fake.checkMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeAPITokenChecker) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ auth.APITokenChecker = new(FakeAPITokenChecker)
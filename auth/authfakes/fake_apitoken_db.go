@@ -0,0 +1,81 @@
+// This file was generated by counterfeiter
+package authfakes
+
+import (
+	"sync"
+
+	"github.com/concourse/atc/auth"
+	"github.com/concourse/atc/db"
+)
+
+type FakeAPITokenDB struct {
+	GetAPITokenStub        func(id int) (db.APIToken, bool, error)
+	getAPITokenMutex       sync.RWMutex
+	getAPITokenArgsForCall []struct {
+		id int
+	}
+	getAPITokenReturns struct {
+		result1 db.APIToken
+		result2 bool
+		result3 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeAPITokenDB) GetAPIToken(id int) (db.APIToken, bool, error) {
+	fake.getAPITokenMutex.Lock()
+	fake.getAPITokenArgsForCall = append(fake.getAPITokenArgsForCall, struct {
+		id int
+	}{id})
+	fake.recordInvocation("GetAPIToken", []interface{}{id})
+	fake.getAPITokenMutex.Unlock()
+	if fake.GetAPITokenStub != nil {
+		return fake.GetAPITokenStub(id)
+	} else {
+		return fake.getAPITokenReturns.result1, fake.getAPITokenReturns.result2, fake.getAPITokenReturns.result3
+	}
+}
+
+func (fake *FakeAPITokenDB) GetAPITokenCallCount() int {
+	fake.getAPITokenMutex.RLock()
+	defer fake.getAPITokenMutex.RUnlock()
+	return len(fake.getAPITokenArgsForCall)
+}
+
+func (fake *FakeAPITokenDB) GetAPITokenArgsForCall(i int) int {
+	fake.getAPITokenMutex.RLock()
+	defer fake.getAPITokenMutex.RUnlock()
+	return fake.getAPITokenArgsForCall[i].id
+}
+
+func (fake *FakeAPITokenDB) GetAPITokenReturns(result1 db.APIToken, result2 bool, result3 error) {
+	fake.GetAPITokenStub = nil
+	fake.getAPITokenReturns = struct {
+		result1 db.APIToken
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeAPITokenDB) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.getAPITokenMutex.RLock()
+	defer fake.getAPITokenMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeAPITokenDB) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ auth.APITokenDB = new(FakeAPITokenDB)
@@ -5,39 +5,58 @@ import (
 	"sync"
 	"time"
 
+	"github.com/concourse/atc"
 	"github.com/concourse/atc/auth"
 )
 
 type FakeTokenGenerator struct {
-	GenerateTokenStub        func(expiration time.Time, teamName string, teamID int, isAdmin bool) (auth.TokenType, auth.TokenValue, error)
+	GenerateTokenStub        func(expiration time.Time, teamName string, teamID int, isAdmin bool, role atc.Role) (auth.TokenType, auth.TokenValue, error)
 	generateTokenMutex       sync.RWMutex
 	generateTokenArgsForCall []struct {
 		expiration time.Time
 		teamName   string
 		teamID     int
 		isAdmin    bool
+		role       atc.Role
 	}
 	generateTokenReturns struct {
 		result1 auth.TokenType
 		result2 auth.TokenValue
 		result3 error
 	}
+	GenerateAPITokenStub        func(tokenID int, scopes []auth.Scope, expiration time.Time, teamName string, teamID int, isAdmin bool, role atc.Role) (auth.TokenType, auth.TokenValue, error)
+	generateAPITokenMutex       sync.RWMutex
+	generateAPITokenArgsForCall []struct {
+		tokenID    int
+		scopes     []auth.Scope
+		expiration time.Time
+		teamName   string
+		teamID     int
+		isAdmin    bool
+		role       atc.Role
+	}
+	generateAPITokenReturns struct {
+		result1 auth.TokenType
+		result2 auth.TokenValue
+		result3 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeTokenGenerator) GenerateToken(expiration time.Time, teamName string, teamID int, isAdmin bool) (auth.TokenType, auth.TokenValue, error) {
+func (fake *FakeTokenGenerator) GenerateToken(expiration time.Time, teamName string, teamID int, isAdmin bool, role atc.Role) (auth.TokenType, auth.TokenValue, error) {
 	fake.generateTokenMutex.Lock()
 	fake.generateTokenArgsForCall = append(fake.generateTokenArgsForCall, struct {
 		expiration time.Time
 		teamName   string
 		teamID     int
 		isAdmin    bool
-	}{expiration, teamName, teamID, isAdmin})
-	fake.recordInvocation("GenerateToken", []interface{}{expiration, teamName, teamID, isAdmin})
+		role       atc.Role
+	}{expiration, teamName, teamID, isAdmin, role})
+	fake.recordInvocation("GenerateToken", []interface{}{expiration, teamName, teamID, isAdmin, role})
 	fake.generateTokenMutex.Unlock()
 	if fake.GenerateTokenStub != nil {
-		return fake.GenerateTokenStub(expiration, teamName, teamID, isAdmin)
+		return fake.GenerateTokenStub(expiration, teamName, teamID, isAdmin, role)
 	} else {
 		return fake.generateTokenReturns.result1, fake.generateTokenReturns.result2, fake.generateTokenReturns.result3
 	}
@@ -49,10 +68,10 @@ func (fake *FakeTokenGenerator) GenerateTokenCallCount() int {
 	return len(fake.generateTokenArgsForCall)
 }
 
-func (fake *FakeTokenGenerator) GenerateTokenArgsForCall(i int) (time.Time, string, int, bool) {
+func (fake *FakeTokenGenerator) GenerateTokenArgsForCall(i int) (time.Time, string, int, bool, atc.Role) {
 	fake.generateTokenMutex.RLock()
 	defer fake.generateTokenMutex.RUnlock()
-	return fake.generateTokenArgsForCall[i].expiration, fake.generateTokenArgsForCall[i].teamName, fake.generateTokenArgsForCall[i].teamID, fake.generateTokenArgsForCall[i].isAdmin
+	return fake.generateTokenArgsForCall[i].expiration, fake.generateTokenArgsForCall[i].teamName, fake.generateTokenArgsForCall[i].teamID, fake.generateTokenArgsForCall[i].isAdmin, fake.generateTokenArgsForCall[i].role
 }
 
 func (fake *FakeTokenGenerator) GenerateTokenReturns(result1 auth.TokenType, result2 auth.TokenValue, result3 error) {
@@ -64,11 +83,59 @@ func (fake *FakeTokenGenerator) GenerateTokenReturns(result1 auth.TokenType, res
 	}{result1, result2, result3}
 }
 
+func (fake *FakeTokenGenerator) GenerateAPIToken(tokenID int, scopes []auth.Scope, expiration time.Time, teamName string, teamID int, isAdmin bool, role atc.Role) (auth.TokenType, auth.TokenValue, error) {
+	var scopesCopy []auth.Scope
+	if scopes != nil {
+		scopesCopy = make([]auth.Scope, len(scopes))
+		copy(scopesCopy, scopes)
+	}
+	fake.generateAPITokenMutex.Lock()
+	fake.generateAPITokenArgsForCall = append(fake.generateAPITokenArgsForCall, struct {
+		tokenID    int
+		scopes     []auth.Scope
+		expiration time.Time
+		teamName   string
+		teamID     int
+		isAdmin    bool
+		role       atc.Role
+	}{tokenID, scopesCopy, expiration, teamName, teamID, isAdmin, role})
+	fake.recordInvocation("GenerateAPIToken", []interface{}{tokenID, scopesCopy, expiration, teamName, teamID, isAdmin, role})
+	fake.generateAPITokenMutex.Unlock()
+	if fake.GenerateAPITokenStub != nil {
+		return fake.GenerateAPITokenStub(tokenID, scopes, expiration, teamName, teamID, isAdmin, role)
+	} else {
+		return fake.generateAPITokenReturns.result1, fake.generateAPITokenReturns.result2, fake.generateAPITokenReturns.result3
+	}
+}
+
+func (fake *FakeTokenGenerator) GenerateAPITokenCallCount() int {
+	fake.generateAPITokenMutex.RLock()
+	defer fake.generateAPITokenMutex.RUnlock()
+	return len(fake.generateAPITokenArgsForCall)
+}
+
+func (fake *FakeTokenGenerator) GenerateAPITokenArgsForCall(i int) (int, []auth.Scope, time.Time, string, int, bool, atc.Role) {
+	fake.generateAPITokenMutex.RLock()
+	defer fake.generateAPITokenMutex.RUnlock()
+	return fake.generateAPITokenArgsForCall[i].tokenID, fake.generateAPITokenArgsForCall[i].scopes, fake.generateAPITokenArgsForCall[i].expiration, fake.generateAPITokenArgsForCall[i].teamName, fake.generateAPITokenArgsForCall[i].teamID, fake.generateAPITokenArgsForCall[i].isAdmin, fake.generateAPITokenArgsForCall[i].role
+}
+
+func (fake *FakeTokenGenerator) GenerateAPITokenReturns(result1 auth.TokenType, result2 auth.TokenValue, result3 error) {
+	fake.GenerateAPITokenStub = nil
+	fake.generateAPITokenReturns = struct {
+		result1 auth.TokenType
+		result2 auth.TokenValue
+		result3 error
+	}{result1, result2, result3}
+}
+
 func (fake *FakeTokenGenerator) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.generateTokenMutex.RLock()
 	defer fake.generateTokenMutex.RUnlock()
+	fake.generateAPITokenMutex.RLock()
+	defer fake.generateAPITokenMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -4,6 +4,8 @@ import (
 	"context"
 	"net/http"
 	"strconv"
+
+	"github.com/concourse/atc"
 )
 
 type CheckBuildReadAccessHandlerFactory interface {
@@ -119,6 +121,20 @@ func (h checkBuildReadAccessHandler) ServeHTTP(w http.ResponseWriter, r *http.Re
 				return
 			}
 		}
+	} else if !build.IsOneOff() {
+		// those on the team can still be kept out by the pipeline's
+		// permissions, unless they're using a token without a role
+		if _, hasRole := GetRole(r); hasRole {
+			pipeline, err := build.GetPipeline()
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+
+			if !pipeline.Public && !CheckPipelinePermission(w, r, pipeline.Permissions, atc.PipelinePermissionView) {
+				return
+			}
+		}
 	}
 
 	ctx := context.WithValue(r.Context(), BuildKey, build)
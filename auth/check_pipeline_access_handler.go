@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 
+	"github.com/concourse/atc"
 	"github.com/concourse/atc/db"
 )
 
@@ -64,9 +65,17 @@ func (h checkPipelineAccessHandler) ServeHTTP(w http.ResponseWriter, r *http.Req
 	}
 
 	pipelineDB := h.pipelineDBFactory.Build(savedPipeline)
-	if IsAuthorized(r) || pipelineDB.IsPublic() {
-		ctx := context.WithValue(r.Context(), PipelineDBKey, pipelineDB)
-		h.delegateHandler.ServeHTTP(w, r.WithContext(ctx))
+	if pipelineDB.IsPublic() {
+		h.serveWithPipeline(w, r, pipelineDB)
+		return
+	}
+
+	if IsAuthorized(r) {
+		if !CheckPipelinePermission(w, r, savedPipeline.Permissions, atc.PipelinePermissionView) {
+			return
+		}
+
+		h.serveWithPipeline(w, r, pipelineDB)
 		return
 	}
 
@@ -77,3 +86,8 @@ func (h checkPipelineAccessHandler) ServeHTTP(w http.ResponseWriter, r *http.Req
 
 	h.rejector.Unauthorized(w, r)
 }
+
+func (h checkPipelineAccessHandler) serveWithPipeline(w http.ResponseWriter, r *http.Request, pipelineDB db.PipelineDB) {
+	ctx := context.WithValue(r.Context(), PipelineDBKey, pipelineDB)
+	h.delegateHandler.ServeHTTP(w, r.WithContext(ctx))
+}
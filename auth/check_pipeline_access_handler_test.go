@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 
+	"github.com/concourse/atc"
 	"github.com/concourse/atc/auth"
 	"github.com/concourse/atc/auth/authfakes"
 	"github.com/concourse/atc/db"
@@ -97,6 +98,55 @@ var _ = Describe("CheckPipelineAccessHandler", func() {
 				It("returns 200 OK", func() {
 					Expect(response.StatusCode).To(Equal(http.StatusOK))
 				})
+
+				Context("when viewing the pipeline needs the admin role", func() {
+					BeforeEach(func() {
+						teamDB.GetPipelineByNameReturns(db.SavedPipeline{
+							Pipeline:    db.Pipeline{Name: "some-pipeline"},
+							Permissions: atc.PipelinePermissions{View: atc.RoleAdmin},
+						}, true, nil)
+					})
+
+					Context("and the request has the viewer role", func() {
+						BeforeEach(func() {
+							userContextReader.GetRoleReturns(atc.RoleViewer, true)
+						})
+
+						It("returns 403 Forbidden", func() {
+							Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+						})
+
+						It("does not call the delegate", func() {
+							Expect(delegate.IsCalled).To(BeFalse())
+						})
+
+						Context("but the pipeline is public", func() {
+							BeforeEach(func() {
+								pipelineDB.IsPublicReturns(true)
+							})
+
+							It("returns 200 OK", func() {
+								Expect(response.StatusCode).To(Equal(http.StatusOK))
+							})
+						})
+					})
+
+					Context("and the request has the admin role", func() {
+						BeforeEach(func() {
+							userContextReader.GetRoleReturns(atc.RoleAdmin, true)
+						})
+
+						It("returns 200 OK", func() {
+							Expect(response.StatusCode).To(Equal(http.StatusOK))
+						})
+					})
+
+					Context("and the request has no role", func() {
+						It("returns 200 OK", func() {
+							Expect(response.StatusCode).To(Equal(http.StatusOK))
+						})
+					})
+				})
 			})
 
 			Context("and unauthorized", func() {
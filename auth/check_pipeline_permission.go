@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/db"
+)
+
+// HasPipelinePermission says whether the request's role is enough for what
+// the pipeline's permissions need, returning the role they need. Requests
+// made without a role are not restricted, as with CheckRoleHandler.
+func HasPipelinePermission(r *http.Request, permissions atc.PipelinePermissions, permission atc.PipelinePermission) (atc.Role, bool) {
+	required, restricted := permissions.RequiredRole(permission)
+	if !restricted {
+		return "", true
+	}
+
+	role, found := GetRole(r)
+	if !found {
+		return required, true
+	}
+
+	return required, role.Allows(required)
+}
+
+// HiddenPipelineIDs returns the IDs of the private pipelines among those
+// given that the request's role isn't enough to view, so that their builds
+// can be left out of anything listing them.
+func HiddenPipelineIDs(r *http.Request, pipelines []db.SavedPipeline) []int {
+	hidden := []int{}
+	for _, pipeline := range pipelines {
+		if pipeline.Public {
+			continue
+		}
+
+		if _, allowed := HasPipelinePermission(r, pipeline.Permissions, atc.PipelinePermissionView); !allowed {
+			hidden = append(hidden, pipeline.ID)
+		}
+	}
+
+	return hidden
+}
+
+// CheckPipelinePermission rejects the request if its role falls short of
+// what the pipeline's permissions need, returning whether it may go ahead.
+func CheckPipelinePermission(w http.ResponseWriter, r *http.Request, permissions atc.PipelinePermissions, permission atc.PipelinePermission) bool {
+	required, allowed := HasPipelinePermission(r, permissions, permission)
+	if allowed {
+		return true
+	}
+
+	role, _ := GetRole(r)
+
+	apierror.Write(w, http.StatusForbidden, atc.APIError{
+		Code:    atc.ErrorCodeForbidden,
+		Message: fmt.Sprintf("requires the %s role to %s this pipeline", required, permission),
+		Details: map[string]string{
+			"permission":    string(permission),
+			"required_role": string(required),
+			"role":          string(role),
+		},
+	})
+
+	return false
+}
@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+)
+
+type checkPipelinePermissionHandler struct {
+	handler       http.Handler
+	teamDBFactory db.TeamDBFactory
+	permission    atc.PipelinePermission
+}
+
+// CheckPipelinePermissionHandler rejects requests to a pipeline-scoped route
+// made with a role that falls short of what the pipeline's permissions need.
+// Pipelines that don't exist are left to the handler, which either 404s or,
+// when saving a config, creates them.
+func CheckPipelinePermissionHandler(
+	handler http.Handler,
+	teamDBFactory db.TeamDBFactory,
+	permission atc.PipelinePermission,
+) http.Handler {
+	return checkPipelinePermissionHandler{
+		handler:       handler,
+		teamDBFactory: teamDBFactory,
+		permission:    permission,
+	}
+}
+
+func (h checkPipelinePermissionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// requests without a role are never held back, so there's no need to
+	// look the pipeline up
+	if _, hasRole := GetRole(r); !hasRole {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+
+	teamDB := h.teamDBFactory.GetTeamDB(r.FormValue(":team_name"))
+
+	pipeline, found, err := teamDB.GetPipelineByName(r.FormValue(":pipeline_name"))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if found && !CheckPipelinePermission(w, r, pipeline.Permissions, h.permission) {
+		return
+	}
+
+	h.handler.ServeHTTP(w, r)
+}
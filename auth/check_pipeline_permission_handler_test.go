@@ -0,0 +1,128 @@
+package auth_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/auth"
+	"github.com/concourse/atc/auth/authfakes"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/db/dbfakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CheckPipelinePermissionHandler", func() {
+	var (
+		teamDBFactory     *dbfakes.FakeTeamDBFactory
+		teamDB            *dbfakes.FakeTeamDB
+		userContextReader *authfakes.FakeUserContextReader
+		delegateCalled    bool
+
+		recorder *httptest.ResponseRecorder
+	)
+
+	BeforeEach(func() {
+		teamDBFactory = new(dbfakes.FakeTeamDBFactory)
+		teamDB = new(dbfakes.FakeTeamDB)
+		teamDBFactory.GetTeamDBReturns(teamDB)
+
+		userContextReader = new(authfakes.FakeUserContextReader)
+
+		delegateCalled = false
+
+		teamDB.GetPipelineByNameReturns(db.SavedPipeline{
+			Pipeline:    db.Pipeline{Name: "some-pipeline"},
+			Permissions: atc.PipelinePermissions{Configure: atc.RoleAdmin},
+		}, true, nil)
+	})
+
+	JustBeforeEach(func() {
+		delegate := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			delegateCalled = true
+		})
+
+		handler := auth.WrapHandler(
+			auth.CheckPipelinePermissionHandler(delegate, teamDBFactory, atc.PipelinePermissionConfigure),
+			new(authfakes.FakeValidator),
+			userContextReader,
+		)
+
+		request, err := http.NewRequest("PUT", "/?:team_name=some-team&:pipeline_name=some-pipeline", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+	})
+
+	Context("when the request has a role the pipeline allows", func() {
+		BeforeEach(func() {
+			userContextReader.GetRoleReturns(atc.RoleAdmin, true)
+		})
+
+		It("looks up the pipeline of the team", func() {
+			Expect(teamDBFactory.GetTeamDBArgsForCall(0)).To(Equal("some-team"))
+			Expect(teamDB.GetPipelineByNameArgsForCall(0)).To(Equal("some-pipeline"))
+		})
+
+		It("calls the handler", func() {
+			Expect(delegateCalled).To(BeTrue())
+		})
+	})
+
+	Context("when the request has a role the pipeline doesn't allow", func() {
+		BeforeEach(func() {
+			userContextReader.GetRoleReturns(atc.RoleOperator, true)
+		})
+
+		It("returns 403, saying which role is needed", func() {
+			Expect(recorder.Code).To(Equal(http.StatusForbidden))
+
+			var apiErr atc.APIError
+			err := json.Unmarshal(recorder.Body.Bytes(), &apiErr)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(apiErr.Details).To(Equal(map[string]string{
+				"permission":    "configure",
+				"required_role": "admin",
+				"role":          "operator",
+			}))
+		})
+
+		It("does not call the handler", func() {
+			Expect(delegateCalled).To(BeFalse())
+		})
+
+		Context("when the pipeline does not exist", func() {
+			BeforeEach(func() {
+				teamDB.GetPipelineByNameReturns(db.SavedPipeline{}, false, nil)
+			})
+
+			It("leaves it to the handler", func() {
+				Expect(delegateCalled).To(BeTrue())
+			})
+		})
+
+		Context("when looking up the pipeline fails", func() {
+			BeforeEach(func() {
+				teamDB.GetPipelineByNameReturns(db.SavedPipeline{}, false, errors.New("nope"))
+			})
+
+			It("returns 500", func() {
+				Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+				Expect(delegateCalled).To(BeFalse())
+			})
+		})
+	})
+
+	Context("when the request has no role", func() {
+		It("calls the handler without looking up the pipeline", func() {
+			Expect(delegateCalled).To(BeTrue())
+			Expect(teamDB.GetPipelineByNameCallCount()).To(BeZero())
+		})
+	})
+})
@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+)
+
+type checkRoleHandler struct {
+	handler  http.Handler
+	required atc.Role
+}
+
+// CheckRoleHandler rejects requests made with a role that falls short of the
+// given one, saying which role was needed.
+func CheckRoleHandler(
+	handler http.Handler,
+	required atc.Role,
+) http.Handler {
+	return checkRoleHandler{
+		handler:  handler,
+		required: required,
+	}
+}
+
+func (h checkRoleHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	role, found := GetRole(r)
+	if found && !role.Allows(h.required) {
+		apierror.Write(w, http.StatusForbidden, atc.APIError{
+			Code:    atc.ErrorCodeForbidden,
+			Message: fmt.Sprintf("requires the %s role", h.required),
+			Details: map[string]string{
+				"required_role": string(h.required),
+				"role":          string(role),
+			},
+		})
+		return
+	}
+
+	h.handler.ServeHTTP(w, r)
+}
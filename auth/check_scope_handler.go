@@ -0,0 +1,39 @@
+package auth
+
+import "net/http"
+
+type checkScopeHandler struct {
+	handler  http.Handler
+	scope    Scope
+	checker  APITokenChecker
+	rejector Rejector
+}
+
+// CheckScopeHandler rejects requests made with API tokens that have been
+// revoked or that lack the given scope.
+func CheckScopeHandler(
+	handler http.Handler,
+	scope Scope,
+	checker APITokenChecker,
+	rejector Rejector,
+) http.Handler {
+	return checkScopeHandler{
+		handler:  handler,
+		scope:    scope,
+		checker:  checker,
+		rejector: rejector,
+	}
+}
+
+func (h checkScopeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch h.checker.Check(r, h.scope) {
+	case nil:
+		h.handler.ServeHTTP(w, r)
+	case ErrInsufficientScope:
+		h.rejector.Forbidden(w, r)
+	case ErrAPITokenRevoked:
+		h.rejector.Unauthorized(w, r)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
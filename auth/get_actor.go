@@ -0,0 +1,21 @@
+package auth
+
+import "net/http"
+
+// GetActor describes who is making the request, for recording alongside
+// whatever they changed.
+func GetActor(r *http.Request) string {
+	if !IsAuthenticated(r) {
+		return "anonymous"
+	}
+
+	if team, found := GetTeam(r); found {
+		return "team:" + team.Name()
+	}
+
+	if IsSystem(r) {
+		return "system"
+	}
+
+	return "authenticated"
+}
@@ -0,0 +1,15 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/concourse/atc"
+)
+
+// GetRole returns the role the request was made with. Requests made without
+// one (e.g. with a system token, or one issued before roles existed) are not
+// restricted by role.
+func GetRole(r *http.Request) (atc.Role, bool) {
+	role, found := r.Context().Value(roleKey).(atc.Role)
+	return role, found
+}
@@ -4,6 +4,7 @@ import (
 	"crypto/rsa"
 	"net/http"
 
+	"github.com/concourse/atc"
 	jwt "github.com/dgrijalva/jwt-go"
 )
 
@@ -47,3 +48,30 @@ func (jr JWTReader) GetSystem(r *http.Request) (bool, bool) {
 
 	return isSystemInterface.(bool), true
 }
+
+// GetRole returns the role the token was signed with. API tokens from before
+// they carried their creator's role are given the most restricted one, since
+// there's no telling who made them.
+func (jr JWTReader) GetRole(r *http.Request) (atc.Role, bool) {
+	token, err := getJWT(r, jr.PublicKey)
+	if err != nil {
+		return "", false
+	}
+
+	claims := token.Claims.(jwt.MapClaims)
+	roleInterface, roleOK := claims[roleClaimKey]
+	if !roleOK {
+		if _, isAPIToken := claims[tokenIDClaimKey]; isAPIToken {
+			return atc.RoleViewer, true
+		}
+
+		return "", false
+	}
+
+	role, isString := roleInterface.(string)
+	if !isString {
+		return "", false
+	}
+
+	return atc.Role(role), true
+}
@@ -38,6 +38,7 @@ var _ = Describe("LogOutHandler", func() {
 				fakeProviderFactory,
 				fakeTeamDBFactory,
 				signingKey,
+				"https://oauth.example.com",
 			)
 			Expect(err).ToNot(HaveOccurred())
 
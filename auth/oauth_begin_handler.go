@@ -1,8 +1,10 @@
 package auth
 
 import (
+	"crypto/rand"
 	"crypto/rsa"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"net/http"
 	"time"
@@ -17,6 +19,10 @@ const OAuthStateCookie = "_concourse_oauth_state"
 type OAuthState struct {
 	Redirect string `json:"redirect"`
 	TeamName string `json:"team_name"`
+
+	// Nonce makes the state unguessable, so that it can't be forged to log
+	// someone in as someone else.
+	Nonce string `json:"nonce"`
 }
 
 type OAuthBeginHandler struct {
@@ -82,9 +88,18 @@ func (handler *OAuthBeginHandler) ServeHTTP(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	nonce := make([]byte, 16)
+	_, err = rand.Read(nonce)
+	if err != nil {
+		handler.logger.Error("failed-to-generate-nonce", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
 	oauthState, err := json.Marshal(OAuthState{
 		Redirect: r.FormValue("redirect"),
 		TeamName: teamName,
+		Nonce:    hex.EncodeToString(nonce),
 	})
 	if err != nil {
 		handler.logger.Error("failed-to-marshal-state", err)
@@ -97,10 +112,11 @@ func (handler *OAuthBeginHandler) ServeHTTP(w http.ResponseWriter, r *http.Reque
 	authCodeURL := provider.AuthCodeURL(encodedState)
 
 	http.SetCookie(w, &http.Cookie{
-		Name:    OAuthStateCookie,
-		Value:   encodedState,
-		Path:    "/",
-		Expires: time.Now().Add(CookieAge),
+		Name:     OAuthStateCookie,
+		Value:    encodedState,
+		Path:     "/",
+		Expires:  time.Now().Add(CookieAge),
+		HttpOnly: true,
 	})
 
 	http.Redirect(w, r, authCodeURL, http.StatusTemporaryRedirect)
@@ -59,6 +59,7 @@ var _ = Describe("OAuthBeginHandler", func() {
 			fakeProviderFactory,
 			fakeTeamDBFactory,
 			signingKey,
+			"https://oauth.example.com",
 		)
 		Expect(err).ToNot(HaveOccurred())
 
@@ -145,6 +146,25 @@ var _ = Describe("OAuthBeginHandler", func() {
 					Expect(oauthState.Redirect).To(Equal("/some-path"))
 				})
 
+				It("includes a random nonce in the state, so that it can't be guessed", func() {
+					state, _ := fakeProvider.AuthCodeURLArgsForCall(0)
+
+					decoded, err := base64.RawURLEncoding.DecodeString(state)
+					Expect(err).ToNot(HaveOccurred())
+
+					var oauthState auth.OAuthState
+					err = json.Unmarshal(decoded, &oauthState)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(oauthState.Nonce).To(HaveLen(32))
+
+					nextResponse, err := client.Do(request)
+					Expect(err).NotTo(HaveOccurred())
+					nextResponse.Body.Close()
+
+					nextState, _ := fakeProvider.AuthCodeURLArgsForCall(1)
+					Expect(nextState).NotTo(Equal(state))
+				})
+
 				It("sets the base64-encoded redirect URI as the OAuth state cookie", func() {
 					Expect(fakeProvider.AuthCodeURLCallCount()).To(Equal(1))
 
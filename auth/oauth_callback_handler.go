@@ -9,6 +9,12 @@ import (
 	"time"
 
 	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/auth/genericoauth"
+	"github.com/concourse/atc/auth/github"
+	"github.com/concourse/atc/auth/oidc"
+	"github.com/concourse/atc/auth/saml"
+	"github.com/concourse/atc/auth/uaa"
 	"github.com/concourse/atc/db"
 
 	"golang.org/x/net/context"
@@ -156,7 +162,7 @@ func (handler *OAuthCallbackHandler) ServeHTTP(w http.ResponseWriter, r *http.Re
 
 	exp := time.Now().Add(CookieAge)
 
-	tokenType, signedToken, err := handler.tokenGenerator.GenerateToken(exp, team.Name, team.ID, team.Admin)
+	tokenType, signedToken, err := handler.tokenGenerator.GenerateToken(exp, team.Name, team.ID, team.Admin, providerRole(team, providerName))
 	if err != nil {
 		hLog.Error("failed-to-sign-token", err)
 		http.Error(w, "failed to sign token", http.StatusInternalServerError)
@@ -186,3 +192,22 @@ func (handler *OAuthCallbackHandler) ServeHTTP(w http.ResponseWriter, r *http.Re
 
 	fmt.Fprintln(w, tokenStr)
 }
+
+// providerRole is the role the team gives to those who log in through the
+// provider.
+func providerRole(team db.SavedTeam, providerName string) atc.Role {
+	switch providerName {
+	case github.ProviderName:
+		return team.Roles.GitHubAuth.OrAdmin()
+	case uaa.ProviderName:
+		return team.Roles.UAAAuth.OrAdmin()
+	case genericoauth.ProviderName:
+		return team.Roles.GenericOAuth.OrAdmin()
+	case oidc.ProviderName:
+		return team.Roles.OIDCAuth.OrAdmin()
+	case saml.ProviderName:
+		return team.Roles.SAMLAuth.OrAdmin()
+	default:
+		return atc.RoleAdmin
+	}
+}
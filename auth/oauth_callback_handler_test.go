@@ -82,6 +82,7 @@ var _ = Describe("OAuthCallbackHandler", func() {
 			fakeProviderFactory,
 			fakeTeamDBFactory,
 			signingKey,
+			"https://oauth.example.com",
 		)
 		Expect(err).ToNot(HaveOccurred())
 
@@ -237,6 +238,14 @@ var _ = Describe("OAuthCallbackHandler", func() {
 								Expect(claims["teamID"]).To(BeNumerically("==", team.ID))
 								Expect(token.Valid).To(BeTrue())
 							})
+
+							It("gives the admin role, as the team does not set one for the provider", func() {
+								token, err := jwt.Parse(strings.Replace(cookie.Value, "Bearer ", "", -1), keyFunc)
+								Expect(err).ToNot(HaveOccurred())
+
+								claims := token.Claims.(jwt.MapClaims)
+								Expect(claims["role"]).To(Equal("admin"))
+							})
 						})
 
 						It("does not redirect", func() {
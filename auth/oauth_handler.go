@@ -7,6 +7,7 @@ import (
 
 	"code.cloudfoundry.org/lager"
 	"github.com/concourse/atc/auth/provider"
+	"github.com/concourse/atc/auth/saml"
 	"github.com/concourse/atc/db"
 	"github.com/dgrijalva/jwt-go"
 	"github.com/tedsuo/rata"
@@ -25,7 +26,10 @@ func NewOAuthHandler(
 	providerFactory ProviderFactory,
 	teamDBFactory db.TeamDBFactory,
 	signingKey *rsa.PrivateKey,
+	oauthBaseURL string,
 ) (http.Handler, error) {
+	serviceProvider := saml.NewServiceProvider(oauthBaseURL)
+
 	return rata.NewRouter(
 		OAuthRoutes,
 		map[string]http.Handler{
@@ -44,6 +48,21 @@ func NewOAuthHandler(
 			LogOut: NewLogOutHandler(
 				logger.Session("logout"),
 			),
+			SAMLMetadata: NewSAMLMetadataHandler(
+				logger.Session("saml-metadata"),
+				serviceProvider,
+			),
+			SAMLLogIn: NewSAMLLogInHandler(
+				logger.Session("saml-login"),
+				serviceProvider,
+				teamDBFactory,
+			),
+			SAMLACS: NewSAMLACSHandler(
+				logger.Session("saml-acs"),
+				serviceProvider,
+				signingKey,
+				teamDBFactory,
+			),
 		},
 	)
 }
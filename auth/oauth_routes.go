@@ -6,10 +6,17 @@ const (
 	OAuthBegin    = "OAuthBegin"
 	OAuthCallback = "OAuthCallback"
 	LogOut        = "LogOut"
+
+	SAMLMetadata = "SAMLMetadata"
+	SAMLLogIn    = "SAMLLogIn"
+	SAMLACS      = "SAMLACS"
 )
 
 var OAuthRoutes = rata.Routes{
 	{Path: "/auth/logout", Method: "GET", Name: LogOut},
+	{Path: "/auth/saml/metadata", Method: "GET", Name: SAMLMetadata},
+	{Path: "/auth/saml/login", Method: "GET", Name: SAMLLogIn},
+	{Path: "/auth/saml/acs", Method: "POST", Name: SAMLACS},
 	{Path: "/auth/:provider", Method: "GET", Name: OAuthBegin},
 	{Path: "/auth/:provider/callback", Method: "GET", Name: OAuthCallback},
 }
@@ -0,0 +1,177 @@
+package oidc
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+	jwt "github.com/dgrijalva/jwt-go"
+	"golang.org/x/oauth2"
+)
+
+// ClaimsVerifier checks the ID token the provider gave with the access token
+// against the issuer's signing keys, and lets the user in if they are in one
+// of the groups or are one of the users. Users are matched on their email,
+// so long as the provider has verified it.
+type ClaimsVerifier struct {
+	issuerCache *IssuerCache
+	issuer      string
+	clientID    string
+	groupsClaim string
+	groups      []string
+	users       []string
+}
+
+func NewClaimsVerifier(
+	issuerCache *IssuerCache,
+	issuer string,
+	clientID string,
+	groupsClaim string,
+	groups []string,
+	users []string,
+) ClaimsVerifier {
+	return ClaimsVerifier{
+		issuerCache: issuerCache,
+		issuer:      strings.TrimSuffix(issuer, "/"),
+		clientID:    clientID,
+		groupsClaim: groupsClaim,
+		groups:      groups,
+		users:       users,
+	}
+}
+
+func (verifier ClaimsVerifier) Verify(logger lager.Logger, httpClient *http.Client) (bool, error) {
+	// the client is the one the callback made for the exchanged token, which
+	// carries the ID token alongside the access token
+	transport, ok := httpClient.Transport.(*oauth2.Transport)
+	if !ok {
+		return false, errors.New("client has no OAuth token")
+	}
+
+	token, err := transport.Source.Token()
+	if err != nil {
+		return false, err
+	}
+
+	idToken, _ := token.Extra("id_token").(string)
+	if idToken == "" {
+		logger.Info("missing-id-token")
+		return false, nil
+	}
+
+	var keyErr error
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(idToken, claims, func(parsed *jwt.Token) (interface{}, error) {
+		switch parsed.Method.(type) {
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS, *jwt.SigningMethodECDSA:
+		default:
+			return nil, fmt.Errorf("unexpected signing method: %v", parsed.Header["alg"])
+		}
+
+		keyID, _ := parsed.Header["kid"].(string)
+
+		key, err := verifier.issuerCache.Key(verifier.issuer, keyID)
+		if err != nil && err != ErrUnknownKey {
+			keyErr = err
+		}
+
+		return key, err
+	})
+	if keyErr != nil {
+		return false, keyErr
+	}
+
+	if err != nil {
+		logger.Info("invalid-id-token", lager.Data{"error": err.Error()})
+		return false, nil
+	}
+
+	if !verifier.issuedForUs(logger, claims) {
+		return false, nil
+	}
+
+	email, _ := claims["email"].(string)
+	emailVerified, _ := claims["email_verified"].(bool)
+	if email != "" && emailVerified {
+		for _, user := range verifier.users {
+			if user == email {
+				return true, nil
+			}
+		}
+	}
+
+	userGroups := claimStrings(claims[verifier.groupsClaim])
+	for _, group := range verifier.groups {
+		for _, userGroup := range userGroups {
+			if group == userGroup {
+				return true, nil
+			}
+		}
+	}
+
+	logger.Info("not-in-groups-or-users", lager.Data{
+		"have-groups": userGroups,
+		"want-groups": verifier.groups,
+		"want-users":  verifier.users,
+	})
+
+	return false, nil
+}
+
+// issuedForUs checks that the token was issued by the issuer for this
+// client and hasn't expired, which its signature alone doesn't say.
+func (verifier ClaimsVerifier) issuedForUs(logger lager.Logger, claims jwt.MapClaims) bool {
+	issuer, _ := claims["iss"].(string)
+	if strings.TrimSuffix(issuer, "/") != verifier.issuer {
+		logger.Info("id-token-from-another-issuer", lager.Data{"issuer": issuer})
+		return false
+	}
+
+	audience := claimStrings(claims["aud"])
+
+	forUs := false
+	for _, aud := range audience {
+		if aud == verifier.clientID {
+			forUs = true
+		}
+	}
+
+	// tokens for more than one client say which of them they were given to
+	if azp, found := claims["azp"]; len(audience) > 1 || found {
+		forUs = forUs && azp == verifier.clientID
+	}
+
+	if !forUs {
+		logger.Info("id-token-for-another-client", lager.Data{"audience": audience})
+		return false
+	}
+
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		logger.Info("id-token-expired")
+		return false
+	}
+
+	return true
+}
+
+// claimStrings handles providers that give a single group as a string as well
+// as those that always give a list.
+func claimStrings(claim interface{}) []string {
+	switch value := claim.(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		strings := []string{}
+		for _, v := range value {
+			if s, ok := v.(string); ok {
+				strings = append(strings, s)
+			}
+		}
+		return strings
+	default:
+		return nil
+	}
+}
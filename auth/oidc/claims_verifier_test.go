@@ -0,0 +1,343 @@
+package oidc_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"net/http"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/concourse/atc/auth/oidc"
+	"github.com/concourse/atc/auth/verifier"
+	jwt "github.com/dgrijalva/jwt-go"
+
+	"github.com/onsi/gomega/ghttp"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func rsaJWK(keyID string, key *rsa.PublicKey) map[string]string {
+	return map[string]string{
+		"kid": keyID,
+		"kty": "RSA",
+		"use": "sig",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+var _ = Describe("ClaimsVerifier", func() {
+	var verifier verifier.Verifier
+	var issuerCache *IssuerCache
+	var fakeClock *fakeclock.FakeClock
+	var issuerServer *ghttp.Server
+	var signingKey *rsa.PrivateKey
+	var jwks []map[string]string
+	var claims jwt.MapClaims
+	var signedToken string
+	var verified bool
+	var verifyErr error
+
+	BeforeEach(func() {
+		var err error
+		signingKey, err = rsa.GenerateKey(rand.Reader, 2048)
+		Expect(err).NotTo(HaveOccurred())
+
+		issuerServer = ghttp.NewServer()
+
+		jwks = []map[string]string{rsaJWK("some-key", &signingKey.PublicKey)}
+
+		issuerServer.RouteToHandler("GET", "/.well-known/openid-configuration", ghttp.RespondWithJSONEncoded(http.StatusOK, map[string]string{
+			"issuer":                 issuerServer.URL(),
+			"authorization_endpoint": issuerServer.URL() + "/auth",
+			"token_endpoint":         issuerServer.URL() + "/token",
+			"jwks_uri":               issuerServer.URL() + "/keys",
+		}))
+
+		issuerServer.RouteToHandler("GET", "/keys", func(w http.ResponseWriter, r *http.Request) {
+			ghttp.RespondWithJSONEncoded(http.StatusOK, map[string]interface{}{"keys": jwks})(w, r)
+		})
+
+		fakeClock = fakeclock.NewFakeClock(time.Now())
+		issuerCache = NewIssuerCache(http.DefaultClient, DiscoveryTTL, fakeClock)
+
+		verifier = NewClaimsVerifier(
+			issuerCache,
+			issuerServer.URL()+"/",
+			"some-client-id",
+			"groups",
+			[]string{"some-group", "some-other-group"},
+			[]string{"someone@example.com"},
+		)
+
+		claims = jwt.MapClaims{
+			"iss": issuerServer.URL(),
+			"aud": "some-client-id",
+			"exp": time.Now().Add(time.Hour).Unix(),
+		}
+
+		signedToken = ""
+	})
+
+	AfterEach(func() {
+		issuerServer.Close()
+	})
+
+	JustBeforeEach(func() {
+		if signedToken == "" {
+			token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+			token.Header["kid"] = "some-key"
+
+			var err error
+			signedToken, err = token.SignedString(signingKey)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		c := &oauth2.Config{}
+		httpClient := c.Client(oauth2.NoContext, (&oauth2.Token{AccessToken: "some-access-token"}).WithExtra(map[string]interface{}{
+			"id_token": signedToken,
+		}))
+
+		verified, verifyErr = verifier.Verify(lagertest.NewTestLogger("test"), httpClient)
+	})
+
+	Context("when the user is in one of the groups", func() {
+		BeforeEach(func() {
+			claims["email"] = "someone-else@example.com"
+			claims["groups"] = []string{"unrelated-group", "some-other-group"}
+		})
+
+		It("returns true", func() {
+			Expect(verifyErr).NotTo(HaveOccurred())
+			Expect(verified).To(BeTrue())
+		})
+
+		It("doesn't ask the provider for user info", func() {
+			for _, request := range issuerServer.ReceivedRequests() {
+				Expect(request.URL.Path).NotTo(Equal("/userinfo"))
+			}
+		})
+
+		Context("when the token is signed by another key", func() {
+			BeforeEach(func() {
+				otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+				Expect(err).NotTo(HaveOccurred())
+
+				token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+				token.Header["kid"] = "some-key"
+
+				signedToken, err = token.SignedString(otherKey)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns false", func() {
+				Expect(verifyErr).NotTo(HaveOccurred())
+				Expect(verified).To(BeFalse())
+			})
+		})
+
+		Context("when the token is signed with a shared secret", func() {
+			BeforeEach(func() {
+				token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+				token.Header["kid"] = "some-key"
+
+				var err error
+				signedToken, err = token.SignedString([]byte("some-secret"))
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns false", func() {
+				Expect(verifyErr).NotTo(HaveOccurred())
+				Expect(verified).To(BeFalse())
+			})
+		})
+
+		Context("when the token isn't signed", func() {
+			BeforeEach(func() {
+				token := jwt.NewWithClaims(jwt.SigningMethodNone, claims)
+
+				var err error
+				signedToken, err = token.SignedString(jwt.UnsafeAllowNoneSignatureType)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns false", func() {
+				Expect(verifyErr).NotTo(HaveOccurred())
+				Expect(verified).To(BeFalse())
+			})
+		})
+
+		Context("when the token is signed by a key the issuer has only just rotated in", func() {
+			BeforeEach(func() {
+				newKey, err := rsa.GenerateKey(rand.Reader, 2048)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = issuerCache.Key(issuerServer.URL(), "some-key")
+				Expect(err).NotTo(HaveOccurred())
+
+				jwks = append(jwks, rsaJWK("some-new-key", &newKey.PublicKey))
+				fakeClock.Increment(KeyRefreshInterval)
+
+				token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+				token.Header["kid"] = "some-new-key"
+
+				signedToken, err = token.SignedString(newKey)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns true", func() {
+				Expect(verifyErr).NotTo(HaveOccurred())
+				Expect(verified).To(BeTrue())
+			})
+		})
+
+		Context("when the token is from another issuer", func() {
+			BeforeEach(func() {
+				claims["iss"] = "https://evil.example.com"
+			})
+
+			It("returns false", func() {
+				Expect(verifyErr).NotTo(HaveOccurred())
+				Expect(verified).To(BeFalse())
+			})
+		})
+
+		Context("when the token is for another client", func() {
+			BeforeEach(func() {
+				claims["aud"] = "some-other-client-id"
+			})
+
+			It("returns false", func() {
+				Expect(verifyErr).NotTo(HaveOccurred())
+				Expect(verified).To(BeFalse())
+			})
+		})
+
+		Context("when the token is for several clients", func() {
+			BeforeEach(func() {
+				claims["aud"] = []string{"some-other-client-id", "some-client-id"}
+			})
+
+			It("returns false unless it was given to this one", func() {
+				Expect(verifyErr).NotTo(HaveOccurred())
+				Expect(verified).To(BeFalse())
+			})
+
+			Context("when it was given to this one", func() {
+				BeforeEach(func() {
+					claims["azp"] = "some-client-id"
+				})
+
+				It("returns true", func() {
+					Expect(verifyErr).NotTo(HaveOccurred())
+					Expect(verified).To(BeTrue())
+				})
+			})
+		})
+
+		Context("when the token has expired", func() {
+			BeforeEach(func() {
+				claims["exp"] = time.Now().Add(-time.Minute).Unix()
+			})
+
+			It("returns false", func() {
+				Expect(verifyErr).NotTo(HaveOccurred())
+				Expect(verified).To(BeFalse())
+			})
+		})
+
+		Context("when the token doesn't expire", func() {
+			BeforeEach(func() {
+				delete(claims, "exp")
+			})
+
+			It("returns false", func() {
+				Expect(verifyErr).NotTo(HaveOccurred())
+				Expect(verified).To(BeFalse())
+			})
+		})
+
+		Context("when the provider gave no ID token", func() {
+			BeforeEach(func() {
+				signedToken = "-"
+			})
+
+			JustBeforeEach(func() {
+				c := &oauth2.Config{}
+				httpClient := c.Client(oauth2.NoContext, &oauth2.Token{AccessToken: "some-access-token"})
+
+				verified, verifyErr = verifier.Verify(lagertest.NewTestLogger("test"), httpClient)
+			})
+
+			It("returns false", func() {
+				Expect(verifyErr).NotTo(HaveOccurred())
+				Expect(verified).To(BeFalse())
+			})
+		})
+
+		Context("when the issuer's keys can't be fetched", func() {
+			BeforeEach(func() {
+				issuerServer.RouteToHandler("GET", "/keys", ghttp.RespondWith(http.StatusInternalServerError, ""))
+			})
+
+			It("returns an error", func() {
+				Expect(verifyErr).To(HaveOccurred())
+				Expect(verified).To(BeFalse())
+			})
+		})
+	})
+
+	Context("when the provider gives a single group as a string", func() {
+		BeforeEach(func() {
+			claims["groups"] = "some-group"
+		})
+
+		It("returns true", func() {
+			Expect(verifyErr).NotTo(HaveOccurred())
+			Expect(verified).To(BeTrue())
+		})
+	})
+
+	Context("when the user's verified email is one of the users", func() {
+		BeforeEach(func() {
+			claims["email"] = "someone@example.com"
+			claims["email_verified"] = true
+		})
+
+		It("returns true", func() {
+			Expect(verifyErr).NotTo(HaveOccurred())
+			Expect(verified).To(BeTrue())
+		})
+	})
+
+	Context("when the user's email is one of the users but is not verified", func() {
+		BeforeEach(func() {
+			claims["email"] = "someone@example.com"
+			claims["email_verified"] = false
+		})
+
+		It("returns false", func() {
+			Expect(verifyErr).NotTo(HaveOccurred())
+			Expect(verified).To(BeFalse())
+		})
+	})
+
+	Context("when the user is in none of the groups and is none of the users", func() {
+		BeforeEach(func() {
+			claims["email"] = "someone-else@example.com"
+			claims["email_verified"] = true
+			claims["groups"] = []string{"unrelated-group"}
+		})
+
+		It("returns false", func() {
+			Expect(verifyErr).NotTo(HaveOccurred())
+			Expect(verified).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,306 @@
+package oidc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+)
+
+// DiscoveryTTL is how long an issuer's OpenID configuration and signing keys
+// are cached for before they're fetched again.
+const DiscoveryTTL = time.Hour
+
+// KeyRefreshInterval is how soon the signing keys are fetched again to find
+// one they didn't have, so that keys the issuer has rotated in are picked up
+// without each unknown key costing a request.
+const KeyRefreshInterval = time.Minute
+
+var ErrUnknownKey = errors.New("token is signed with a key the issuer doesn't have")
+
+// DefaultIssuerCache is shared by every team's provider, as they're built
+// for each login.
+var DefaultIssuerCache = NewIssuerCache(&http.Client{
+	Timeout: 10 * time.Second,
+	Transport: &http.Transport{
+		DisableKeepAlives: true,
+	},
+}, DiscoveryTTL, clock.NewClock())
+
+// IssuerCache fetches issuers' OpenID configurations and signing keys,
+// keeping them for the TTL so that logins don't each wait on the issuer.
+type IssuerCache struct {
+	httpClient *http.Client
+	ttl        time.Duration
+	clock      clock.Clock
+
+	issuersL sync.Mutex
+	issuers  map[string]cachedIssuer
+}
+
+type cachedIssuer struct {
+	discovery    Discovery
+	discoveredAt time.Time
+
+	keys          map[string]interface{}
+	keysFetchedAt time.Time
+}
+
+func NewIssuerCache(httpClient *http.Client, ttl time.Duration, clock clock.Clock) *IssuerCache {
+	return &IssuerCache{
+		httpClient: httpClient,
+		ttl:        ttl,
+		clock:      clock,
+		issuers:    map[string]cachedIssuer{},
+	}
+}
+
+// Discover returns the issuer's OpenID configuration, fetching it from the
+// well-known location under its URL once the cached one has expired.
+func (cache *IssuerCache) Discover(issuer string) (Discovery, error) {
+	issuer = strings.TrimSuffix(issuer, "/")
+
+	cached := cache.cached(issuer)
+	if !cached.discoveredAt.IsZero() && cache.clock.Since(cached.discoveredAt) < cache.ttl {
+		return cached.discovery, nil
+	}
+
+	discovery, err := cache.discover(issuer)
+	if err != nil {
+		return Discovery{}, err
+	}
+
+	cache.issuersL.Lock()
+	cached = cache.issuers[issuer]
+	cached.discovery = discovery
+	cached.discoveredAt = cache.clock.Now()
+	cache.issuers[issuer] = cached
+	cache.issuersL.Unlock()
+
+	return discovery, nil
+}
+
+// Key returns the issuer's signing key with the ID, fetching the issuer's
+// keys once the cached ones have expired or don't include it. Tokens without
+// a key ID can only be checked against an issuer with a single key.
+func (cache *IssuerCache) Key(issuer string, keyID string) (interface{}, error) {
+	issuer = strings.TrimSuffix(issuer, "/")
+
+	cached := cache.cached(issuer)
+	if cached.keys != nil {
+		age := cache.clock.Since(cached.keysFetchedAt)
+
+		key, found := findKey(cached.keys, keyID)
+		if found && age < cache.ttl {
+			return key, nil
+		}
+
+		if !found && age < KeyRefreshInterval {
+			return nil, ErrUnknownKey
+		}
+	}
+
+	discovery, err := cache.Discover(issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := cache.fetchKeys(discovery.JWKSURL)
+	if err != nil {
+		return nil, err
+	}
+
+	cache.issuersL.Lock()
+	cached = cache.issuers[issuer]
+	cached.keys = keys
+	cached.keysFetchedAt = cache.clock.Now()
+	cache.issuers[issuer] = cached
+	cache.issuersL.Unlock()
+
+	key, found := findKey(keys, keyID)
+	if !found {
+		return nil, ErrUnknownKey
+	}
+
+	return key, nil
+}
+
+func (cache *IssuerCache) cached(issuer string) cachedIssuer {
+	cache.issuersL.Lock()
+	defer cache.issuersL.Unlock()
+
+	return cache.issuers[issuer]
+}
+
+func (cache *IssuerCache) discover(issuer string) (Discovery, error) {
+	response, err := cache.httpClient.Get(issuer + "/.well-known/openid-configuration")
+	if err != nil {
+		return Discovery{}, err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return Discovery{}, fmt.Errorf("unexpected response from OpenID configuration endpoint: %s", response.Status)
+	}
+
+	var discovery Discovery
+	err = json.NewDecoder(response.Body).Decode(&discovery)
+	if err != nil {
+		return Discovery{}, err
+	}
+
+	// the spec requires this, and it stops one provider standing in for
+	// another
+	if strings.TrimSuffix(discovery.Issuer, "/") != issuer {
+		return Discovery{}, fmt.Errorf("OpenID configuration is for issuer '%s', not '%s'", discovery.Issuer, issuer)
+	}
+
+	if discovery.AuthURL == "" || discovery.TokenURL == "" || discovery.JWKSURL == "" {
+		return Discovery{}, fmt.Errorf("OpenID configuration for '%s' is missing an endpoint", issuer)
+	}
+
+	return discovery, nil
+}
+
+// jsonWebKey is the part of a JWK needed for the RSA and EC keys that ID
+// tokens are signed with.
+type jsonWebKey struct {
+	KeyID   string `json:"kid"`
+	KeyType string `json:"kty"`
+	Use     string `json:"use"`
+
+	N string `json:"n"`
+	E string `json:"e"`
+
+	Curve string `json:"crv"`
+	X     string `json:"x"`
+	Y     string `json:"y"`
+}
+
+func (cache *IssuerCache) fetchKeys(jwksURL string) (map[string]interface{}, error) {
+	response, err := cache.httpClient.Get(jwksURL)
+	if err != nil {
+		return nil, err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response from JWKS endpoint: %s", response.Status)
+	}
+
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	err = json.NewDecoder(response.Body).Decode(&jwks)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := map[string]interface{}{}
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		key, err := jwk.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("malformed key '%s': %s", jwk.KeyID, err)
+		}
+
+		// keys of other types can't sign ID tokens that we'd accept
+		if key != nil {
+			keys[jwk.KeyID] = key
+		}
+	}
+
+	return keys, nil
+}
+
+func (jwk jsonWebKey) publicKey() (interface{}, error) {
+	switch jwk.KeyType {
+	case "RSA":
+		n, err := decodeKeyInt(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := decodeKeyInt(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("exponent is too large")
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Curve {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unknown curve '%s'", jwk.Curve)
+		}
+
+		x, err := decodeKeyInt(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := decodeKeyInt(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("point is not on the curve")
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+
+	default:
+		return nil, nil
+	}
+}
+
+func decodeKeyInt(value string) (*big.Int, error) {
+	bytes, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(bytes) == 0 {
+		return nil, errors.New("missing value")
+	}
+
+	return new(big.Int).SetBytes(bytes), nil
+}
+
+func findKey(keys map[string]interface{}, keyID string) (interface{}, bool) {
+	if keyID == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key, true
+		}
+	}
+
+	key, found := keys[keyID]
+	return key, found
+}
@@ -0,0 +1,221 @@
+package oidc_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	. "github.com/concourse/atc/auth/oidc"
+	"github.com/onsi/gomega/ghttp"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("IssuerCache", func() {
+	var (
+		issuerServer *ghttp.Server
+		discovery    map[string]string
+		jwks         []map[string]string
+		publicKey    *rsa.PublicKey
+
+		fakeClock   *fakeclock.FakeClock
+		issuerCache *IssuerCache
+	)
+
+	BeforeEach(func() {
+		issuerServer = ghttp.NewServer()
+
+		discovery = map[string]string{
+			"issuer":                 issuerServer.URL(),
+			"authorization_endpoint": issuerServer.URL() + "/auth",
+			"token_endpoint":         issuerServer.URL() + "/token",
+			"jwks_uri":               issuerServer.URL() + "/keys",
+		}
+
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		Expect(err).NotTo(HaveOccurred())
+		publicKey = &key.PublicKey
+
+		jwks = []map[string]string{
+			rsaJWK("some-key", publicKey),
+			{"kid": "some-encryption-key", "kty": "RSA", "use": "enc"},
+			{"kid": "some-symmetric-key", "kty": "oct", "k": "c2VjcmV0"},
+		}
+
+		fakeClock = fakeclock.NewFakeClock(time.Unix(123, 0))
+		issuerCache = NewIssuerCache(http.DefaultClient, time.Hour, fakeClock)
+	})
+
+	AfterEach(func() {
+		issuerServer.Close()
+	})
+
+	respondWithDiscovery := func() {
+		issuerServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/.well-known/openid-configuration"),
+				func(w http.ResponseWriter, r *http.Request) {
+					ghttp.RespondWithJSONEncoded(http.StatusOK, discovery)(w, r)
+				},
+			),
+		)
+	}
+
+	respondWithKeys := func() {
+		issuerServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/keys"),
+				func(w http.ResponseWriter, r *http.Request) {
+					ghttp.RespondWithJSONEncoded(http.StatusOK, map[string]interface{}{"keys": jwks})(w, r)
+				},
+			),
+		)
+	}
+
+	Describe("Discover", func() {
+		It("caches the configuration until the TTL is up", func() {
+			respondWithDiscovery()
+
+			found, err := issuerCache.Discover(issuerServer.URL() + "/")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(Equal(Discovery{
+				Issuer:   issuerServer.URL(),
+				AuthURL:  issuerServer.URL() + "/auth",
+				TokenURL: issuerServer.URL() + "/token",
+				JWKSURL:  issuerServer.URL() + "/keys",
+			}))
+
+			fakeClock.Increment(time.Hour - time.Second)
+
+			_, err = issuerCache.Discover(issuerServer.URL())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(issuerServer.ReceivedRequests()).To(HaveLen(1))
+
+			discovery["token_endpoint"] = issuerServer.URL() + "/new-token"
+			respondWithDiscovery()
+
+			fakeClock.Increment(time.Second)
+
+			found, err = issuerCache.Discover(issuerServer.URL())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found.TokenURL).To(Equal(issuerServer.URL() + "/new-token"))
+			Expect(issuerServer.ReceivedRequests()).To(HaveLen(2))
+		})
+
+		Context("when the configuration is for another issuer", func() {
+			BeforeEach(func() {
+				discovery["issuer"] = "https://evil.example.com"
+			})
+
+			It("returns an error", func() {
+				respondWithDiscovery()
+
+				_, err := issuerCache.Discover(issuerServer.URL())
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		Context("when the configuration is missing the keys", func() {
+			BeforeEach(func() {
+				delete(discovery, "jwks_uri")
+			})
+
+			It("returns an error", func() {
+				respondWithDiscovery()
+
+				_, err := issuerCache.Discover(issuerServer.URL())
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		Context("when the issuer doesn't respond in time", func() {
+			It("returns an error", func() {
+				blocked := make(chan struct{})
+				defer close(blocked)
+
+				issuerServer.AppendHandlers(func(w http.ResponseWriter, r *http.Request) {
+					<-blocked
+				})
+
+				issuerCache = NewIssuerCache(&http.Client{Timeout: 100 * time.Millisecond}, time.Hour, fakeClock)
+
+				_, err := issuerCache.Discover(issuerServer.URL())
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("Key", func() {
+		BeforeEach(func() {
+			respondWithDiscovery()
+			respondWithKeys()
+		})
+
+		It("returns the signing key with the ID, caching the keys until the TTL is up", func() {
+			key, err := issuerCache.Key(issuerServer.URL(), "some-key")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(key).To(Equal(publicKey))
+
+			fakeClock.Increment(time.Hour - time.Second)
+
+			_, err = issuerCache.Key(issuerServer.URL(), "some-key")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(issuerServer.ReceivedRequests()).To(HaveLen(2))
+
+			respondWithDiscovery()
+			respondWithKeys()
+
+			fakeClock.Increment(time.Second)
+
+			_, err = issuerCache.Key(issuerServer.URL(), "some-key")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(issuerServer.ReceivedRequests()).To(HaveLen(4))
+		})
+
+		It("returns the only signing key for tokens without a key ID", func() {
+			key, err := issuerCache.Key(issuerServer.URL(), "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(key).To(Equal(publicKey))
+		})
+
+		It("ignores keys that aren't for signing", func() {
+			_, err := issuerCache.Key(issuerServer.URL(), "some-encryption-key")
+			Expect(err).To(Equal(ErrUnknownKey))
+
+			_, err = issuerCache.Key(issuerServer.URL(), "some-symmetric-key")
+			Expect(err).To(Equal(ErrUnknownKey))
+		})
+
+		It("only fetches the keys again for an unknown one once the refresh interval is up", func() {
+			_, err := issuerCache.Key(issuerServer.URL(), "some-unknown-key")
+			Expect(err).To(Equal(ErrUnknownKey))
+
+			_, err = issuerCache.Key(issuerServer.URL(), "some-unknown-key")
+			Expect(err).To(Equal(ErrUnknownKey))
+			Expect(issuerServer.ReceivedRequests()).To(HaveLen(2))
+
+			respondWithKeys()
+
+			fakeClock.Increment(KeyRefreshInterval)
+
+			_, err = issuerCache.Key(issuerServer.URL(), "some-unknown-key")
+			Expect(err).To(Equal(ErrUnknownKey))
+			Expect(issuerServer.ReceivedRequests()).To(HaveLen(3))
+		})
+
+		Context("when a key is malformed", func() {
+			BeforeEach(func() {
+				jwks = append(jwks, map[string]string{"kid": "some-bad-key", "kty": "EC", "crv": "P-256", "x": "AQ", "y": "AQ"})
+			})
+
+			It("returns an error", func() {
+				_, err := issuerCache.Key(issuerServer.URL(), "some-key")
+				Expect(err).To(HaveOccurred())
+				Expect(err).NotTo(Equal(ErrUnknownKey))
+			})
+		})
+	})
+})
@@ -0,0 +1,100 @@
+package oidc
+
+import (
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/auth/verifier"
+	"github.com/concourse/atc/db"
+	"golang.org/x/net/context"
+	"golang.org/x/oauth2"
+)
+
+const ProviderName = "oidc"
+
+// DefaultGroupsClaim is the ID token claim listing the user's groups, unless
+// the team names another.
+const DefaultGroupsClaim = "groups"
+
+var Scopes = []string{"openid", "email", "profile"}
+
+// Discovery is the part of the provider's OpenID configuration needed to log
+// users in and check who they are.
+type Discovery struct {
+	Issuer   string `json:"issuer"`
+	AuthURL  string `json:"authorization_endpoint"`
+	TokenURL string `json:"token_endpoint"`
+	JWKSURL  string `json:"jwks_uri"`
+}
+
+func NewProvider(
+	issuerCache *IssuerCache,
+	oidcAuth *db.OIDCAuth,
+	redirectURL string,
+) (Provider, error) {
+	discovery, err := issuerCache.Discover(oidcAuth.Issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	groupsClaim := oidcAuth.GroupsClaim
+	if groupsClaim == "" {
+		groupsClaim = DefaultGroupsClaim
+	}
+
+	return oidcProvider{
+		Verifier: NewClaimsVerifier(
+			issuerCache,
+			oidcAuth.Issuer,
+			oidcAuth.ClientID,
+			groupsClaim,
+			oidcAuth.Groups,
+			oidcAuth.Users,
+		),
+		Config: &oauth2.Config{
+			ClientID:     oidcAuth.ClientID,
+			ClientSecret: oidcAuth.ClientSecret,
+			Endpoint: oauth2.Endpoint{
+				AuthURL:  discovery.AuthURL,
+				TokenURL: discovery.TokenURL,
+			},
+			Scopes:      append(append([]string{}, Scopes...), oidcAuth.Scopes...),
+			RedirectURL: redirectURL,
+		},
+	}, nil
+}
+
+type Provider interface {
+	PreTokenClient() (*http.Client, error)
+
+	OAuthClient
+	Verifier
+}
+
+type OAuthClient interface {
+	AuthCodeURL(string, ...oauth2.AuthCodeOption) string
+	Exchange(context.Context, string) (*oauth2.Token, error)
+	Client(context.Context, *oauth2.Token) *http.Client
+}
+
+type Verifier interface {
+	Verify(lager.Logger, *http.Client) (bool, error)
+}
+
+type oidcProvider struct {
+	*oauth2.Config
+	// oauth2.Config implements the required Provider methods:
+	// AuthCodeURL(string, ...oauth2.AuthCodeOption) string
+	// Exchange(context.Context, string) (*oauth2.Token, error)
+	// Client(context.Context, *oauth2.Token) *http.Client
+
+	verifier.Verifier
+}
+
+func (oidcProvider) PreTokenClient() (*http.Client, error) {
+	return &http.Client{
+		Transport: &http.Transport{
+			DisableKeepAlives: true,
+		},
+	}, nil
+}
@@ -0,0 +1,99 @@
+package oidc_test
+
+import (
+	"net/http"
+	"net/url"
+
+	"code.cloudfoundry.org/clock"
+	"github.com/concourse/atc/auth/oidc"
+	"github.com/concourse/atc/db"
+	"github.com/onsi/gomega/ghttp"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OIDC Provider", func() {
+	var (
+		issuerServer *ghttp.Server
+		discovery    map[string]string
+
+		provider oidc.Provider
+		err      error
+	)
+
+	BeforeEach(func() {
+		issuerServer = ghttp.NewServer()
+
+		discovery = map[string]string{
+			"issuer":                 issuerServer.URL(),
+			"authorization_endpoint": issuerServer.URL() + "/auth",
+			"token_endpoint":         issuerServer.URL() + "/token",
+			"jwks_uri":               issuerServer.URL() + "/keys",
+		}
+	})
+
+	AfterEach(func() {
+		issuerServer.Close()
+	})
+
+	JustBeforeEach(func() {
+		issuerServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/.well-known/openid-configuration"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, discovery),
+			),
+		)
+
+		issuerCache := oidc.NewIssuerCache(http.DefaultClient, oidc.DiscoveryTTL, clock.NewClock())
+
+		provider, err = oidc.NewProvider(issuerCache, &db.OIDCAuth{
+			Issuer:       issuerServer.URL() + "/",
+			ClientID:     "some-client-id",
+			ClientSecret: "some-client-secret",
+			Scopes:       []string{"groups"},
+		}, "https://atc.example.com/auth/oidc/callback")
+	})
+
+	It("logs in through the discovered auth endpoint", func() {
+		Expect(err).NotTo(HaveOccurred())
+
+		authCodeURL, parseErr := url.Parse(provider.AuthCodeURL("some-state"))
+		Expect(parseErr).NotTo(HaveOccurred())
+
+		Expect(authCodeURL.Host).To(Equal(issuerServer.Addr()))
+		Expect(authCodeURL.Path).To(Equal("/auth"))
+		Expect(authCodeURL.Query().Get("client_id")).To(Equal("some-client-id"))
+		Expect(authCodeURL.Query().Get("state")).To(Equal("some-state"))
+		Expect(authCodeURL.Query().Get("redirect_uri")).To(Equal("https://atc.example.com/auth/oidc/callback"))
+	})
+
+	It("requests the standard scopes and any configured ones", func() {
+		Expect(err).NotTo(HaveOccurred())
+
+		authCodeURL, parseErr := url.Parse(provider.AuthCodeURL("some-state"))
+		Expect(parseErr).NotTo(HaveOccurred())
+
+		Expect(authCodeURL.Query().Get("scope")).To(Equal("openid email profile groups"))
+	})
+
+	Context("when the configuration is for another issuer", func() {
+		BeforeEach(func() {
+			discovery["issuer"] = "https://evil.example.com"
+		})
+
+		It("returns an error", func() {
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("when the configuration is missing an endpoint", func() {
+		BeforeEach(func() {
+			delete(discovery, "jwks_uri")
+		})
+
+		It("returns an error", func() {
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
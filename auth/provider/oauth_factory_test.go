@@ -1,13 +1,17 @@
 package provider_test
 
 import (
+	"net/http"
+
 	"code.cloudfoundry.org/lager/lagertest"
 	"github.com/concourse/atc/auth"
 	"github.com/concourse/atc/auth/genericoauth"
 	"github.com/concourse/atc/auth/github"
+	"github.com/concourse/atc/auth/oidc"
 	"github.com/concourse/atc/auth/provider"
 	"github.com/concourse/atc/auth/uaa"
 	"github.com/concourse/atc/db"
+	"github.com/onsi/gomega/ghttp"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -120,6 +124,56 @@ var _ = Describe("OAuthFactory", func() {
 			})
 		})
 
+		Context("when asking for oidc", func() {
+			Context("when OIDC provider is setup", func() {
+				var issuerServer *ghttp.Server
+
+				BeforeEach(func() {
+					issuerServer = ghttp.NewServer()
+					issuerServer.AppendHandlers(
+						ghttp.RespondWithJSONEncoded(http.StatusOK, map[string]string{
+							"issuer":                 issuerServer.URL(),
+							"authorization_endpoint": issuerServer.URL() + "/auth",
+							"token_endpoint":         issuerServer.URL() + "/token",
+							"jwks_uri":               issuerServer.URL() + "/keys",
+						}),
+					)
+				})
+
+				AfterEach(func() {
+					issuerServer.Close()
+				})
+
+				It("returns back OIDC's auth provider", func() {
+					provider, found, err := oauthFactory.GetProvider(db.SavedTeam{
+						Team: db.Team{
+							Name: "some-team",
+							OIDCAuth: &db.OIDCAuth{
+								Issuer:       issuerServer.URL(),
+								ClientID:     "user1",
+								ClientSecret: "password1",
+							},
+						},
+					}, oidc.ProviderName)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(found).To(BeTrue())
+					Expect(provider).NotTo(BeNil())
+				})
+			})
+
+			Context("when OIDC provider is not setup", func() {
+				It("returns false", func() {
+					_, found, err := oauthFactory.GetProvider(db.SavedTeam{
+						Team: db.Team{
+							Name: "some-team",
+						},
+					}, oidc.ProviderName)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(found).To(BeFalse())
+				})
+			})
+		})
+
 		Context("when asking for unknown provider", func() {
 			It("returns false", func() {
 				_, found, err := oauthFactory.GetProvider(db.SavedTeam{
@@ -0,0 +1,175 @@
+package saml
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+)
+
+// canonicalize writes out the element as exclusive XML canonicalization
+// without comments would (https://www.w3.org/TR/xml-exc-c14n/), leaving out
+// the excluded element as the enveloped signature transform would.
+//
+// Namespaces are only declared where they're used, along with any in the
+// inclusive prefixes, which are where they would be with inclusive
+// canonicalization. "#default" stands for the default namespace.
+func canonicalize(e *element, inclusivePrefixes []string, excluded *element) []byte {
+	inclusive := map[string]bool{}
+	for _, prefix := range inclusivePrefixes {
+		if prefix == "#default" {
+			prefix = ""
+		}
+
+		inclusive[prefix] = true
+	}
+
+	buf := new(bytes.Buffer)
+	writeCanonical(buf, e, inclusive, excluded, map[string]string{})
+	return buf.Bytes()
+}
+
+type canonicalAttr struct {
+	namespace string
+	name      string
+	value     string
+}
+
+func writeCanonical(buf *bytes.Buffer, e *element, inclusive map[string]bool, excluded *element, rendered map[string]string) {
+	used := map[string]bool{e.Prefix: true}
+	for _, attr := range e.Attrs {
+		if attr.Name.Space != "" && attr.Name.Space != "xml" {
+			used[attr.Name.Space] = true
+		}
+	}
+
+	for prefix := range inclusive {
+		if _, inScope := e.lookupNamespace(prefix); inScope {
+			used[prefix] = true
+		}
+	}
+
+	declared := map[string]string{}
+	for prefix := range used {
+		uri, inScope := e.lookupNamespace(prefix)
+		if !inScope {
+			continue
+		}
+
+		renderedURI, wasRendered := rendered[prefix]
+		if wasRendered && renderedURI == uri {
+			continue
+		}
+
+		// no namespace is where the default starts out, so only needs saying
+		// once something else has been made the default
+		if prefix == "" && uri == "" && !wasRendered {
+			continue
+		}
+
+		declared[prefix] = uri
+	}
+
+	prefixes := []string{}
+	for prefix := range declared {
+		prefixes = append(prefixes, prefix)
+	}
+
+	sort.Strings(prefixes)
+
+	attrs := []canonicalAttr{}
+	for _, attr := range e.Attrs {
+		namespace := ""
+		if attr.Name.Space != "" {
+			namespace, _ = e.lookupNamespace(attr.Name.Space)
+		}
+
+		attrs = append(attrs, canonicalAttr{
+			namespace: namespace,
+			name:      qualifiedName(attr.Name.Space, attr.Name.Local),
+			value:     attr.Value,
+		})
+	}
+
+	sort.Slice(attrs, func(i, j int) bool {
+		if attrs[i].namespace != attrs[j].namespace {
+			return attrs[i].namespace < attrs[j].namespace
+		}
+
+		return localName(attrs[i].name) < localName(attrs[j].name)
+	})
+
+	name := qualifiedName(e.Prefix, e.Local)
+
+	buf.WriteString("<" + name)
+
+	for _, prefix := range prefixes {
+		if prefix == "" {
+			buf.WriteString(` xmlns="`)
+		} else {
+			buf.WriteString(` xmlns:` + prefix + `="`)
+		}
+
+		buf.WriteString(escapeAttr(declared[prefix]))
+		buf.WriteString(`"`)
+	}
+
+	for _, attr := range attrs {
+		buf.WriteString(" " + attr.name + `="` + escapeAttr(attr.value) + `"`)
+	}
+
+	buf.WriteString(">")
+
+	if len(declared) > 0 {
+		inherited := map[string]string{}
+		for prefix, uri := range rendered {
+			inherited[prefix] = uri
+		}
+
+		for prefix, uri := range declared {
+			inherited[prefix] = uri
+		}
+
+		rendered = inherited
+	}
+
+	for _, child := range e.Children {
+		switch c := child.(type) {
+		case *element:
+			if c != excluded {
+				writeCanonical(buf, c, inclusive, excluded, rendered)
+			}
+		case text:
+			buf.WriteString(escapeText(string(c)))
+		}
+	}
+
+	buf.WriteString("</" + name + ">")
+}
+
+func localName(name string) string {
+	return name[strings.Index(name, ":")+1:]
+}
+
+var textEscaper = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	"\r", "&#xD;",
+)
+
+var attrEscaper = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	`"`, "&quot;",
+	"\t", "&#x9;",
+	"\n", "&#xA;",
+	"\r", "&#xD;",
+)
+
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+func escapeAttr(s string) string {
+	return attrEscaper.Replace(s)
+}
@@ -0,0 +1,240 @@
+package saml
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	statusSuccess = "urn:oasis:names:tc:SAML:2.0:status:Success"
+	bearerMethod  = "urn:oasis:names:tc:SAML:2.0:cm:bearer"
+)
+
+// ClockSkew is how far the IdP's clock may be from the ATC's.
+const ClockSkew = 3 * time.Minute
+
+// Assertion is what the IdP says about the user.
+type Assertion struct {
+	ID         string
+	Issuer     string
+	NameID     string
+	Attributes map[string][]string
+
+	// ExpiresAt is when the assertion stops being accepted, so it only
+	// needs remembering until then to make sure it's only used once.
+	ExpiresAt time.Time
+}
+
+// ParseResponse returns what the IdP asserted in a response posted to the
+// ACS URL, so long as it was in response to the request and either the
+// response or the assertion was signed by the IdP. Unsolicited responses, and
+// encrypted assertions, aren't supported.
+//
+// Nothing here stops a response from being replayed; callers must make sure
+// that each request is only answered once and that each assertion is only
+// used once before it expires.
+func (sp ServiceProvider) ParseResponse(idp IdentityProvider, samlResponse string, requestID string, now time.Time) (Assertion, error) {
+	document, err := base64.StdEncoding.DecodeString(samlResponse)
+	if err != nil {
+		return Assertion{}, err
+	}
+
+	response, err := parseXML(document)
+	if err != nil {
+		return Assertion{}, err
+	}
+
+	if !response.Is(protocolNamespace, "Response") {
+		return Assertion{}, fmt.Errorf("expected a Response, got %s", response.Local)
+	}
+
+	if response.Attr("Version") != "2.0" {
+		return Assertion{}, fmt.Errorf("unsupported SAML version: %s", response.Attr("Version"))
+	}
+
+	destination := response.Attr("Destination")
+	if destination != "" && destination != sp.ACSURL {
+		return Assertion{}, fmt.Errorf("response is for %s", destination)
+	}
+
+	if requestID == "" || response.Attr("InResponseTo") != requestID {
+		return Assertion{}, errors.New("response is not for this request")
+	}
+
+	status, err := response.ChildElement(protocolNamespace, "Status")
+	if err != nil {
+		return Assertion{}, err
+	}
+
+	statusCode, err := status.ChildElement(protocolNamespace, "StatusCode")
+	if err != nil {
+		return Assertion{}, err
+	}
+
+	if statusCode.Attr("Value") != statusSuccess {
+		return Assertion{}, fmt.Errorf("IdP returned %s", statusCode.Attr("Value"))
+	}
+
+	if len(response.ChildElements(assertionNamespace, "EncryptedAssertion")) > 0 {
+		return Assertion{}, errors.New("encrypted assertions are not supported")
+	}
+
+	assertion, err := response.ChildElement(assertionNamespace, "Assertion")
+	if err != nil {
+		return Assertion{}, err
+	}
+
+	err = verifySignature(response, idp.Certificate)
+	if err != nil && err != errNotSigned {
+		return Assertion{}, fmt.Errorf("invalid response signature: %s", err)
+	}
+
+	responseSigned := err == nil
+
+	err = verifySignature(assertion, idp.Certificate)
+	if err == errNotSigned && !responseSigned {
+		return Assertion{}, errors.New("neither the response nor the assertion is signed")
+	}
+
+	if err != nil && err != errNotSigned {
+		return Assertion{}, fmt.Errorf("invalid assertion signature: %s", err)
+	}
+
+	return sp.readAssertion(idp, assertion, requestID, now)
+}
+
+func (sp ServiceProvider) readAssertion(idp IdentityProvider, assertion *element, requestID string, now time.Time) (Assertion, error) {
+	id := assertion.Attr("ID")
+	if id == "" {
+		return Assertion{}, errors.New("assertion has no ID")
+	}
+
+	issuer, err := assertion.ChildElement(assertionNamespace, "Issuer")
+	if err != nil {
+		return Assertion{}, err
+	}
+
+	if idp.EntityID != "" && issuer.Text() != idp.EntityID {
+		return Assertion{}, fmt.Errorf("assertion was issued by %s", issuer.Text())
+	}
+
+	subject, err := assertion.ChildElement(assertionNamespace, "Subject")
+	if err != nil {
+		return Assertion{}, err
+	}
+
+	nameID, err := subject.ChildElement(assertionNamespace, "NameID")
+	if err != nil {
+		return Assertion{}, err
+	}
+
+	confirmedUntil, err := sp.checkSubjectConfirmation(subject, requestID, now)
+	if err != nil {
+		return Assertion{}, err
+	}
+
+	conditions, err := assertion.ChildElement(assertionNamespace, "Conditions")
+	if err != nil {
+		return Assertion{}, err
+	}
+
+	err = sp.checkConditions(conditions, now)
+	if err != nil {
+		return Assertion{}, err
+	}
+
+	attributes := map[string][]string{}
+	for _, statement := range assertion.ChildElements(assertionNamespace, "AttributeStatement") {
+		for _, attribute := range statement.ChildElements(assertionNamespace, "Attribute") {
+			name := attribute.Attr("Name")
+			for _, value := range attribute.ChildElements(assertionNamespace, "AttributeValue") {
+				attributes[name] = append(attributes[name], value.Text())
+			}
+		}
+	}
+
+	return Assertion{
+		ID:         id,
+		Issuer:     issuer.Text(),
+		NameID:     nameID.Text(),
+		Attributes: attributes,
+		ExpiresAt:  confirmedUntil.Add(ClockSkew),
+	}, nil
+}
+
+// checkSubjectConfirmation makes sure the assertion was meant for the ATC
+// to log the user in with, in response to this request, returning when the
+// confirmation runs out.
+func (sp ServiceProvider) checkSubjectConfirmation(subject *element, requestID string, now time.Time) (time.Time, error) {
+	for _, confirmation := range subject.ChildElements(assertionNamespace, "SubjectConfirmation") {
+		if confirmation.Attr("Method") != bearerMethod {
+			continue
+		}
+
+		for _, data := range confirmation.ChildElements(assertionNamespace, "SubjectConfirmationData") {
+			if data.Attr("Recipient") != sp.ACSURL {
+				continue
+			}
+
+			if data.Attr("InResponseTo") != requestID {
+				continue
+			}
+
+			notOnOrAfter, err := time.Parse(time.RFC3339, data.Attr("NotOnOrAfter"))
+			if err != nil || !now.Before(notOnOrAfter.Add(ClockSkew)) {
+				continue
+			}
+
+			return notOnOrAfter, nil
+		}
+	}
+
+	return time.Time{}, errors.New("assertion has no bearer subject confirmation for this request")
+}
+
+func (sp ServiceProvider) checkConditions(conditions *element, now time.Time) error {
+	if notBefore := conditions.Attr("NotBefore"); notBefore != "" {
+		t, err := time.Parse(time.RFC3339, notBefore)
+		if err != nil {
+			return err
+		}
+
+		if now.Add(ClockSkew).Before(t) {
+			return errors.New("assertion is not valid yet")
+		}
+	}
+
+	if notOnOrAfter := conditions.Attr("NotOnOrAfter"); notOnOrAfter != "" {
+		t, err := time.Parse(time.RFC3339, notOnOrAfter)
+		if err != nil {
+			return err
+		}
+
+		if !now.Before(t.Add(ClockSkew)) {
+			return errors.New("assertion has expired")
+		}
+	}
+
+	restrictions := conditions.ChildElements(assertionNamespace, "AudienceRestriction")
+	if len(restrictions) == 0 {
+		return errors.New("assertion is not restricted to an audience")
+	}
+
+	// every restriction must be met, not just one of them
+	for _, restriction := range restrictions {
+		audienced := false
+		for _, audience := range restriction.ChildElements(assertionNamespace, "Audience") {
+			if audience.Text() == sp.EntityID {
+				audienced = true
+			}
+		}
+
+		if !audienced {
+			return errors.New("assertion is for a different audience")
+		}
+	}
+
+	return nil
+}
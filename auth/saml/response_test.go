@@ -0,0 +1,579 @@
+package saml_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/concourse/atc/auth/saml"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type responseFields struct {
+	Destination  string
+	InResponseTo string
+	StatusCode   string
+
+	Issuer       string
+	NameID       string
+	Recipient    string
+	ConfirmedFor string
+	ConfirmUntil time.Time
+	NotBefore    time.Time
+	NotOnOrAfter time.Time
+	Audience     string
+}
+
+func xmlTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// these are written in their canonical form so that they can be signed as
+// they are
+func assertionXML(fields responseFields) string {
+	return `<saml:Assertion xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion" ID="_assertion" IssueInstant="` + xmlTime(fields.NotBefore) + `" Version="2.0">` +
+		`<saml:Issuer>` + fields.Issuer + `</saml:Issuer>` +
+		signatureMarker +
+		`<saml:Subject>` +
+		`<saml:NameID>` + fields.NameID + `</saml:NameID>` +
+		`<saml:SubjectConfirmation Method="urn:oasis:names:tc:SAML:2.0:cm:bearer">` +
+		`<saml:SubjectConfirmationData InResponseTo="` + fields.ConfirmedFor + `" NotOnOrAfter="` + xmlTime(fields.ConfirmUntil) + `" Recipient="` + fields.Recipient + `"></saml:SubjectConfirmationData>` +
+		`</saml:SubjectConfirmation>` +
+		`</saml:Subject>` +
+		`<saml:Conditions NotBefore="` + xmlTime(fields.NotBefore) + `" NotOnOrAfter="` + xmlTime(fields.NotOnOrAfter) + `">` +
+		`<saml:AudienceRestriction><saml:Audience>` + fields.Audience + `</saml:Audience></saml:AudienceRestriction>` +
+		`</saml:Conditions>` +
+		`<saml:AttributeStatement>` +
+		`<saml:Attribute Name="groups">` +
+		`<saml:AttributeValue>admins</saml:AttributeValue>` +
+		`<saml:AttributeValue>devs</saml:AttributeValue>` +
+		`</saml:Attribute>` +
+		`</saml:AttributeStatement>` +
+		`</saml:Assertion>`
+}
+
+func responseXML(fields responseFields, assertion string) string {
+	return `<samlp:Response xmlns:samlp="urn:oasis:names:tc:SAML:2.0:protocol" Destination="` + fields.Destination + `" ID="_response" InResponseTo="` + fields.InResponseTo + `" IssueInstant="` + xmlTime(fields.NotBefore) + `" Version="2.0">` +
+		`<saml:Issuer xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion">` + fields.Issuer + `</saml:Issuer>` +
+		signatureMarker +
+		`<samlp:Status><samlp:StatusCode Value="` + fields.StatusCode + `"></samlp:StatusCode></samlp:Status>` +
+		assertion +
+		`</samlp:Response>`
+}
+
+func unsigned(element string) string {
+	return strings.Replace(element, signatureMarker, "", 1)
+}
+
+var _ = Describe("ServiceProvider", func() {
+	var (
+		sp  saml.ServiceProvider
+		idp saml.IdentityProvider
+		now time.Time
+	)
+
+	BeforeEach(func() {
+		sp = saml.NewServiceProvider("https://atc.example.com/")
+		idp = saml.IdentityProvider{
+			SSOURL:      "https://idp.example.com/sso",
+			EntityID:    "https://idp.example.com",
+			Certificate: idpCert,
+		}
+
+		now = time.Date(2017, 3, 14, 15, 9, 26, 0, time.UTC)
+	})
+
+	It("lives under the base URL", func() {
+		Expect(sp.EntityID).To(Equal("https://atc.example.com/auth/saml/metadata"))
+		Expect(sp.ACSURL).To(Equal("https://atc.example.com/auth/saml/acs"))
+	})
+
+	Describe("ParseResponse", func() {
+		var (
+			fields        responseFields
+			signAssertion bool
+			signResponse  bool
+			alter         func(string) string
+
+			assertion saml.Assertion
+			parseErr  error
+		)
+
+		BeforeEach(func() {
+			fields = responseFields{
+				Destination:  sp.ACSURL,
+				InResponseTo: "_some-request",
+				StatusCode:   "urn:oasis:names:tc:SAML:2.0:status:Success",
+
+				Issuer:       "https://idp.example.com",
+				NameID:       "someone@example.com",
+				Recipient:    sp.ACSURL,
+				ConfirmedFor: "_some-request",
+				ConfirmUntil: now.Add(5 * time.Minute),
+				NotBefore:    now.Add(-time.Minute),
+				NotOnOrAfter: now.Add(5 * time.Minute),
+				Audience:     sp.EntityID,
+			}
+
+			signAssertion = true
+			signResponse = false
+			alter = func(document string) string { return document }
+		})
+
+		JustBeforeEach(func() {
+			assertionElement := assertionXML(fields)
+			if signAssertion {
+				assertionElement = sign(assertionElement, idpKey)
+			} else {
+				assertionElement = unsigned(assertionElement)
+			}
+
+			response := responseXML(fields, assertionElement)
+			if signResponse {
+				response = sign(response, idpKey)
+			} else {
+				response = unsigned(response)
+			}
+
+			encoded := base64.StdEncoding.EncodeToString([]byte(alter(response)))
+
+			assertion, parseErr = sp.ParseResponse(idp, encoded, "_some-request", now)
+		})
+
+		It("returns what the assertion says", func() {
+			Expect(parseErr).NotTo(HaveOccurred())
+			Expect(assertion).To(Equal(saml.Assertion{
+				ID:     "_assertion",
+				Issuer: "https://idp.example.com",
+				NameID: "someone@example.com",
+				Attributes: map[string][]string{
+					"groups": {"admins", "devs"},
+				},
+				ExpiresAt: now.Add(5*time.Minute + saml.ClockSkew),
+			}))
+		})
+
+		Context("when the signed assertion is written differently to how it was canonicalized", func() {
+			BeforeEach(func() {
+				alter = func(document string) string {
+					document = strings.Replace(document, `<saml:Assertion xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion" ID="_assertion" IssueInstant`, `<saml:Assertion IssueInstant`, 1)
+					document = strings.Replace(document, `Version="2.0"><saml:Issuer>`, `Version="2.0" ID="_assertion"><saml:Issuer>`, 1)
+					document = strings.Replace(document, `<samlp:Response xmlns:samlp`, `<samlp:Response xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion" xmlns:samlp`, 1)
+					document = strings.Replace(document, `></saml:SubjectConfirmationData>`, `/>`, 1)
+					return document
+				}
+			})
+
+			It("still verifies", func() {
+				Expect(parseErr).NotTo(HaveOccurred())
+				Expect(assertion.NameID).To(Equal("someone@example.com"))
+			})
+		})
+
+		Context("when the response is signed instead of the assertion", func() {
+			BeforeEach(func() {
+				signAssertion = false
+				signResponse = true
+			})
+
+			It("returns what the assertion says", func() {
+				Expect(parseErr).NotTo(HaveOccurred())
+				Expect(assertion.NameID).To(Equal("someone@example.com"))
+			})
+
+			Context("and the assertion has been changed", func() {
+				BeforeEach(func() {
+					alter = func(document string) string {
+						return strings.Replace(document, "someone@example.com", "admin@example.com", 1)
+					}
+				})
+
+				It("fails", func() {
+					Expect(parseErr).To(MatchError(ContainSubstring("invalid response signature")))
+				})
+			})
+		})
+
+		Context("when both are signed", func() {
+			BeforeEach(func() {
+				signResponse = true
+			})
+
+			It("returns what the assertion says", func() {
+				Expect(parseErr).NotTo(HaveOccurred())
+				Expect(assertion.NameID).To(Equal("someone@example.com"))
+			})
+		})
+
+		Context("when nothing is signed", func() {
+			BeforeEach(func() {
+				signAssertion = false
+			})
+
+			It("fails", func() {
+				Expect(parseErr).To(MatchError("neither the response nor the assertion is signed"))
+			})
+		})
+
+		Context("when the assertion has been changed since it was signed", func() {
+			BeforeEach(func() {
+				alter = func(document string) string {
+					return strings.Replace(document, "someone@example.com", "admin@example.com", 1)
+				}
+			})
+
+			It("fails", func() {
+				Expect(parseErr).To(MatchError(ContainSubstring("digest does not match")))
+			})
+		})
+
+		Context("when the assertion was signed by someone else", func() {
+			BeforeEach(func() {
+				otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+				Expect(err).NotTo(HaveOccurred())
+
+				idp.Certificate, _ = selfSignedCertificate(otherKey)
+			})
+
+			It("fails", func() {
+				Expect(parseErr).To(MatchError(ContainSubstring("invalid assertion signature")))
+			})
+		})
+
+		Context("when a forged assertion is wrapped around the signed one", func() {
+			BeforeEach(func() {
+				alter = func(document string) string {
+					start := strings.Index(document, "<saml:Assertion")
+					end := strings.Index(document, "</samlp:Response>")
+					signed := document[start:end]
+
+					forged := unsigned(assertionXML(responseFields{
+						Issuer:       fields.Issuer,
+						NameID:       "admin@example.com",
+						Recipient:    fields.Recipient,
+						ConfirmedFor: fields.ConfirmedFor,
+						ConfirmUntil: fields.ConfirmUntil,
+						NotBefore:    fields.NotBefore,
+						NotOnOrAfter: fields.NotOnOrAfter,
+						Audience:     fields.Audience,
+					}))
+
+					forged = strings.Replace(forged, "</saml:Issuer>", "</saml:Issuer><samlp:Extensions>"+signed+"</samlp:Extensions>", 1)
+
+					return document[:start] + forged + document[end:]
+				}
+			})
+
+			It("fails", func() {
+				Expect(parseErr).To(MatchError("neither the response nor the assertion is signed"))
+			})
+		})
+
+		Context("when a forged assertion with the same ID is added next to the signed one", func() {
+			BeforeEach(func() {
+				alter = func(document string) string {
+					forged := unsigned(assertionXML(responseFields{
+						Issuer:       fields.Issuer,
+						NameID:       "admin@example.com",
+						Recipient:    fields.Recipient,
+						ConfirmedFor: fields.ConfirmedFor,
+						ConfirmUntil: fields.ConfirmUntil,
+						NotBefore:    fields.NotBefore,
+						NotOnOrAfter: fields.NotOnOrAfter,
+						Audience:     fields.Audience,
+					}))
+
+					return strings.Replace(document, "<saml:Assertion", forged+"<saml:Assertion", 1)
+				}
+			})
+
+			It("fails", func() {
+				Expect(parseErr).To(MatchError("expected one Assertion in Response, found 2"))
+			})
+		})
+
+		Context("when the signed assertion is hidden in the signature of a forged one with the same ID", func() {
+			BeforeEach(func() {
+				alter = func(document string) string {
+					start := strings.Index(document, "<saml:Assertion")
+					end := strings.Index(document, "</samlp:Response>")
+					signed := document[start:end]
+
+					signature := signed[strings.Index(signed, "<ds:Signature"):strings.Index(signed, "</ds:Signature>")]
+
+					forged := assertionXML(responseFields{
+						Issuer:       fields.Issuer,
+						NameID:       "admin@example.com",
+						Recipient:    fields.Recipient,
+						ConfirmedFor: fields.ConfirmedFor,
+						ConfirmUntil: fields.ConfirmUntil,
+						NotBefore:    fields.NotBefore,
+						NotOnOrAfter: fields.NotOnOrAfter,
+						Audience:     fields.Audience,
+					})
+
+					forged = strings.Replace(forged, signatureMarker, signature+"<ds:Object>"+signed+"</ds:Object></ds:Signature>", 1)
+
+					return document[:start] + forged + document[end:]
+				}
+			})
+
+			It("fails", func() {
+				Expect(parseErr).To(MatchError(ContainSubstring("digest does not match")))
+			})
+		})
+
+		Context("when the response is signed and its assertion is swapped for an unsigned one", func() {
+			BeforeEach(func() {
+				signAssertion = false
+				signResponse = true
+
+				alter = func(document string) string {
+					start := strings.Index(document, "<saml:Assertion")
+					end := strings.Index(document, "</samlp:Response>")
+
+					forged := unsigned(assertionXML(responseFields{
+						Issuer:       fields.Issuer,
+						NameID:       "admin@example.com",
+						Recipient:    fields.Recipient,
+						ConfirmedFor: fields.ConfirmedFor,
+						ConfirmUntil: fields.ConfirmUntil,
+						NotBefore:    fields.NotBefore,
+						NotOnOrAfter: fields.NotOnOrAfter,
+						Audience:     fields.Audience,
+					}))
+
+					return document[:start] + forged + document[end:]
+				}
+			})
+
+			It("fails", func() {
+				Expect(parseErr).To(MatchError(ContainSubstring("invalid response signature")))
+			})
+		})
+
+		Context("when the signed response is wrapped in a forged one", func() {
+			BeforeEach(func() {
+				signAssertion = false
+				signResponse = true
+
+				alter = func(document string) string {
+					forged := unsigned(responseXML(fields, unsigned(assertionXML(responseFields{
+						Issuer:       fields.Issuer,
+						NameID:       "admin@example.com",
+						Recipient:    fields.Recipient,
+						ConfirmedFor: fields.ConfirmedFor,
+						ConfirmUntil: fields.ConfirmUntil,
+						NotBefore:    fields.NotBefore,
+						NotOnOrAfter: fields.NotOnOrAfter,
+						Audience:     fields.Audience,
+					}))))
+
+					return strings.Replace(forged, "</saml:Issuer>", "</saml:Issuer><samlp:Extensions>"+document+"</samlp:Extensions>", 1)
+				}
+			})
+
+			It("fails", func() {
+				Expect(parseErr).To(MatchError("neither the response nor the assertion is signed"))
+			})
+		})
+
+		Context("when the assertion's ID is given twice", func() {
+			BeforeEach(func() {
+				alter = func(document string) string {
+					return strings.Replace(document, `ID="_assertion"`, `ID="_assertion" ID="_forged"`, 1)
+				}
+			})
+
+			It("fails", func() {
+				Expect(parseErr).To(MatchError("attribute ID given more than once"))
+			})
+		})
+
+		Context("when a comment splits an attribute value", func() {
+			BeforeEach(func() {
+				alter = func(document string) string {
+					return strings.Replace(document, "<saml:AttributeValue>admins", "<saml:AttributeValue>adm<!-- in -->ins", 1)
+				}
+			})
+
+			It("reads all of it", func() {
+				Expect(parseErr).NotTo(HaveOccurred())
+				Expect(assertion.Attributes["groups"]).To(Equal([]string{"admins", "devs"}))
+			})
+		})
+
+		Context("when a comment splits the name ID", func() {
+			BeforeEach(func() {
+				fields.NameID = "admin@example.com.evil.example.org"
+
+				alter = func(document string) string {
+					return strings.Replace(document, "admin@example.com", "admin@example.com<!---->", 1)
+				}
+			})
+
+			It("reads all of it", func() {
+				Expect(parseErr).NotTo(HaveOccurred())
+				Expect(assertion.NameID).To(Equal("admin@example.com.evil.example.org"))
+			})
+		})
+
+		Context("when the document has a DTD", func() {
+			BeforeEach(func() {
+				alter = func(document string) string {
+					return `<!DOCTYPE Response [<!ENTITY x "y">]>` + document
+				}
+			})
+
+			It("fails", func() {
+				Expect(parseErr).To(HaveOccurred())
+			})
+		})
+
+		Context("when the response is in response to another request", func() {
+			BeforeEach(func() {
+				fields.InResponseTo = "_another-request"
+			})
+
+			It("fails", func() {
+				Expect(parseErr).To(MatchError("response is not for this request"))
+			})
+		})
+
+		Context("when the subject was confirmed for another request", func() {
+			BeforeEach(func() {
+				fields.ConfirmedFor = "_another-request"
+			})
+
+			It("fails", func() {
+				Expect(parseErr).To(MatchError(ContainSubstring("no bearer subject confirmation")))
+			})
+		})
+
+		Context("when the subject was confirmed without a request", func() {
+			BeforeEach(func() {
+				fields.ConfirmedFor = ""
+			})
+
+			It("fails", func() {
+				Expect(parseErr).To(MatchError(ContainSubstring("no bearer subject confirmation")))
+			})
+		})
+
+		Context("when the response is not in response to any request", func() {
+			BeforeEach(func() {
+				fields.InResponseTo = ""
+				fields.ConfirmedFor = ""
+			})
+
+			It("fails", func() {
+				Expect(parseErr).To(MatchError("response is not for this request"))
+			})
+		})
+
+		Context("when the subject was confirmed for another recipient", func() {
+			BeforeEach(func() {
+				fields.Recipient = "https://elsewhere.example.com/acs"
+			})
+
+			It("fails", func() {
+				Expect(parseErr).To(MatchError(ContainSubstring("no bearer subject confirmation")))
+			})
+		})
+
+		Context("when the subject confirmation has expired", func() {
+			BeforeEach(func() {
+				fields.ConfirmUntil = now.Add(-saml.ClockSkew - time.Second)
+			})
+
+			It("fails", func() {
+				Expect(parseErr).To(MatchError(ContainSubstring("no bearer subject confirmation")))
+			})
+		})
+
+		Context("when the response is for another destination", func() {
+			BeforeEach(func() {
+				fields.Destination = "https://elsewhere.example.com/acs"
+			})
+
+			It("fails", func() {
+				Expect(parseErr).To(MatchError("response is for https://elsewhere.example.com/acs"))
+			})
+		})
+
+		Context("when the IdP did not log the user in", func() {
+			BeforeEach(func() {
+				fields.StatusCode = "urn:oasis:names:tc:SAML:2.0:status:Requester"
+			})
+
+			It("fails", func() {
+				Expect(parseErr).To(MatchError("IdP returned urn:oasis:names:tc:SAML:2.0:status:Requester"))
+			})
+		})
+
+		Context("when the assertion was issued by someone else", func() {
+			BeforeEach(func() {
+				fields.Issuer = "https://other-idp.example.com"
+			})
+
+			It("fails", func() {
+				Expect(parseErr).To(MatchError("assertion was issued by https://other-idp.example.com"))
+			})
+
+			Context("but the IdP has no entity ID configured", func() {
+				BeforeEach(func() {
+					idp.EntityID = ""
+				})
+
+				It("returns what the assertion says", func() {
+					Expect(parseErr).NotTo(HaveOccurred())
+					Expect(assertion.Issuer).To(Equal("https://other-idp.example.com"))
+				})
+			})
+		})
+
+		Context("when the assertion is for another audience", func() {
+			BeforeEach(func() {
+				fields.Audience = "https://elsewhere.example.com"
+			})
+
+			It("fails", func() {
+				Expect(parseErr).To(MatchError("assertion is for a different audience"))
+			})
+		})
+
+		Context("when the assertion is not valid yet", func() {
+			BeforeEach(func() {
+				fields.NotBefore = now.Add(saml.ClockSkew + time.Second)
+			})
+
+			It("fails", func() {
+				Expect(parseErr).To(MatchError("assertion is not valid yet"))
+			})
+		})
+
+		Context("when the assertion is only a little early for the IdP's clock", func() {
+			BeforeEach(func() {
+				fields.NotBefore = now.Add(time.Minute)
+			})
+
+			It("allows for the skew", func() {
+				Expect(parseErr).NotTo(HaveOccurred())
+			})
+		})
+
+		Context("when the assertion has expired", func() {
+			BeforeEach(func() {
+				fields.NotOnOrAfter = now.Add(-saml.ClockSkew)
+			})
+
+			It("fails", func() {
+				Expect(parseErr).To(MatchError("assertion has expired"))
+			})
+		})
+	})
+})
@@ -0,0 +1,186 @@
+package saml
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"encoding/xml"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/concourse/atc/db"
+)
+
+const ProviderName = "saml"
+
+const (
+	protocolNamespace  = "urn:oasis:names:tc:SAML:2.0:protocol"
+	assertionNamespace = "urn:oasis:names:tc:SAML:2.0:assertion"
+	metadataNamespace  = "urn:oasis:names:tc:SAML:2.0:metadata"
+
+	httpPostBinding = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST"
+)
+
+// ServiceProvider is the ATC as far as identity providers are concerned.
+// There's one for the whole ATC; each team says which IdP it trusts.
+type ServiceProvider struct {
+	// EntityID is what the ATC calls itself, and is where its metadata is.
+	EntityID string
+
+	// ACSURL is where IdPs send users back to with their assertions.
+	ACSURL string
+}
+
+func NewServiceProvider(baseURL string) ServiceProvider {
+	baseURL = strings.TrimRight(baseURL, "/")
+
+	return ServiceProvider{
+		EntityID: baseURL + "/auth/saml/metadata",
+		ACSURL:   baseURL + "/auth/saml/acs",
+	}
+}
+
+// IdentityProvider is who a team trusts to say who its users are.
+type IdentityProvider struct {
+	SSOURL string
+
+	// EntityID, if set, must be who issued the assertion.
+	EntityID string
+
+	// Certificate has the key that responses or their assertions must be
+	// signed with.
+	Certificate *x509.Certificate
+}
+
+func NewIdentityProvider(samlAuth *db.SAMLAuth) (IdentityProvider, error) {
+	block, _ := pem.Decode([]byte(samlAuth.IdPCertificate))
+	if block == nil {
+		return IdentityProvider{}, errors.New("IdP certificate is not PEM encoded")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return IdentityProvider{}, err
+	}
+
+	return IdentityProvider{
+		SSOURL:      samlAuth.SSOURL,
+		EntityID:    samlAuth.IdPEntityID,
+		Certificate: cert,
+	}, nil
+}
+
+type entityDescriptor struct {
+	XMLName  xml.Name `xml:"urn:oasis:names:tc:SAML:2.0:metadata EntityDescriptor"`
+	EntityID string   `xml:"entityID,attr"`
+
+	SPSSODescriptor spSSODescriptor `xml:"SPSSODescriptor"`
+}
+
+type spSSODescriptor struct {
+	AuthnRequestsSigned        bool   `xml:"AuthnRequestsSigned,attr"`
+	WantAssertionsSigned       bool   `xml:"WantAssertionsSigned,attr"`
+	ProtocolSupportEnumeration string `xml:"protocolSupportEnumeration,attr"`
+
+	NameIDFormat             string                   `xml:"NameIDFormat"`
+	AssertionConsumerService assertionConsumerService `xml:"AssertionConsumerService"`
+}
+
+type assertionConsumerService struct {
+	Binding  string `xml:"Binding,attr"`
+	Location string `xml:"Location,attr"`
+	Index    int    `xml:"index,attr"`
+}
+
+// Metadata describes the ATC to IdPs, for registering it with them.
+func (sp ServiceProvider) Metadata() ([]byte, error) {
+	metadata, err := xml.MarshalIndent(entityDescriptor{
+		EntityID: sp.EntityID,
+		SPSSODescriptor: spSSODescriptor{
+			AuthnRequestsSigned:        false,
+			WantAssertionsSigned:       true,
+			ProtocolSupportEnumeration: protocolNamespace,
+
+			NameIDFormat: "urn:oasis:names:tc:SAML:1.1:nameid-format:unspecified",
+			AssertionConsumerService: assertionConsumerService{
+				Binding:  httpPostBinding,
+				Location: sp.ACSURL,
+				Index:    1,
+			},
+		},
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), metadata...), nil
+}
+
+type authnRequest struct {
+	XMLName                     xml.Name `xml:"urn:oasis:names:tc:SAML:2.0:protocol AuthnRequest"`
+	ID                          string   `xml:"ID,attr"`
+	Version                     string   `xml:"Version,attr"`
+	IssueInstant                string   `xml:"IssueInstant,attr"`
+	Destination                 string   `xml:"Destination,attr"`
+	AssertionConsumerServiceURL string   `xml:"AssertionConsumerServiceURL,attr"`
+	ProtocolBinding             string   `xml:"ProtocolBinding,attr"`
+
+	Issuer issuer `xml:"urn:oasis:names:tc:SAML:2.0:assertion Issuer"`
+}
+
+type issuer struct {
+	Value string `xml:",chardata"`
+}
+
+// AuthnRequestURL is where to send the user to log in to the IdP, using the
+// HTTP-Redirect binding. The IdP will post the response to the ACS URL along
+// with the relay state, and the response must be in response to the request
+// ID.
+func (sp ServiceProvider) AuthnRequestURL(idp IdentityProvider, requestID string, relayState string, now time.Time) (string, error) {
+	request, err := xml.Marshal(authnRequest{
+		ID:                          requestID,
+		Version:                     "2.0",
+		IssueInstant:                now.UTC().Format(time.RFC3339),
+		Destination:                 idp.SSOURL,
+		AssertionConsumerServiceURL: sp.ACSURL,
+		ProtocolBinding:             httpPostBinding,
+
+		Issuer: issuer{Value: sp.EntityID},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	deflated := new(bytes.Buffer)
+
+	writer, err := flate.NewWriter(deflated, flate.DefaultCompression)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = writer.Write(request)
+	if err != nil {
+		return "", err
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return "", err
+	}
+
+	ssoURL, err := url.Parse(idp.SSOURL)
+	if err != nil {
+		return "", err
+	}
+
+	query := ssoURL.Query()
+	query.Set("SAMLRequest", base64.StdEncoding.EncodeToString(deflated.Bytes()))
+	query.Set("RelayState", relayState)
+	ssoURL.RawQuery = query.Encode()
+
+	return ssoURL.String(), nil
+}
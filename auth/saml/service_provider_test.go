@@ -0,0 +1,111 @@
+package saml_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/base64"
+	"encoding/xml"
+	"io/ioutil"
+	"net/url"
+	"time"
+
+	"github.com/concourse/atc/auth/saml"
+	"github.com/concourse/atc/db"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ServiceProvider metadata and requests", func() {
+	var sp saml.ServiceProvider
+
+	BeforeEach(func() {
+		sp = saml.NewServiceProvider("https://atc.example.com")
+	})
+
+	Describe("Metadata", func() {
+		It("describes where to send assertions", func() {
+			metadata, err := sp.Metadata()
+			Expect(err).NotTo(HaveOccurred())
+
+			var descriptor struct {
+				EntityID string `xml:"entityID,attr"`
+				SPSSO    struct {
+					WantAssertionsSigned bool `xml:"WantAssertionsSigned,attr"`
+					ACS                  struct {
+						Binding  string `xml:"Binding,attr"`
+						Location string `xml:"Location,attr"`
+					} `xml:"AssertionConsumerService"`
+				} `xml:"SPSSODescriptor"`
+			}
+
+			err = xml.Unmarshal(metadata, &descriptor)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(descriptor.EntityID).To(Equal("https://atc.example.com/auth/saml/metadata"))
+			Expect(descriptor.SPSSO.WantAssertionsSigned).To(BeTrue())
+			Expect(descriptor.SPSSO.ACS.Binding).To(Equal("urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST"))
+			Expect(descriptor.SPSSO.ACS.Location).To(Equal("https://atc.example.com/auth/saml/acs"))
+		})
+	})
+
+	Describe("AuthnRequestURL", func() {
+		It("redirects to the IdP with a deflated request", func() {
+			idp := saml.IdentityProvider{SSOURL: "https://idp.example.com/sso?tenant=some-tenant"}
+
+			authnRequestURL, err := sp.AuthnRequestURL(idp, "_some-request", "some-state", time.Date(2017, 3, 14, 15, 9, 26, 0, time.UTC))
+			Expect(err).NotTo(HaveOccurred())
+
+			redirect, err := url.Parse(authnRequestURL)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(redirect.Host).To(Equal("idp.example.com"))
+			Expect(redirect.Path).To(Equal("/sso"))
+			Expect(redirect.Query().Get("tenant")).To(Equal("some-tenant"))
+			Expect(redirect.Query().Get("RelayState")).To(Equal("some-state"))
+
+			deflated, err := base64.StdEncoding.DecodeString(redirect.Query().Get("SAMLRequest"))
+			Expect(err).NotTo(HaveOccurred())
+
+			request, err := ioutil.ReadAll(flate.NewReader(bytes.NewReader(deflated)))
+			Expect(err).NotTo(HaveOccurred())
+
+			var authnRequest struct {
+				XMLName      xml.Name
+				ID           string `xml:"ID,attr"`
+				IssueInstant string `xml:"IssueInstant,attr"`
+				ACSURL       string `xml:"AssertionConsumerServiceURL,attr"`
+				Issuer       string `xml:"urn:oasis:names:tc:SAML:2.0:assertion Issuer"`
+			}
+
+			err = xml.Unmarshal(request, &authnRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(authnRequest.XMLName).To(Equal(xml.Name{Space: "urn:oasis:names:tc:SAML:2.0:protocol", Local: "AuthnRequest"}))
+			Expect(authnRequest.ID).To(Equal("_some-request"))
+			Expect(authnRequest.IssueInstant).To(Equal("2017-03-14T15:09:26Z"))
+			Expect(authnRequest.ACSURL).To(Equal("https://atc.example.com/auth/saml/acs"))
+			Expect(authnRequest.Issuer).To(Equal("https://atc.example.com/auth/saml/metadata"))
+		})
+	})
+
+	Describe("NewIdentityProvider", func() {
+		It("parses the certificate", func() {
+			idp, err := saml.NewIdentityProvider(&db.SAMLAuth{
+				SSOURL:         "https://idp.example.com/sso",
+				IdPEntityID:    "https://idp.example.com",
+				IdPCertificate: idpPEM,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(idp.SSOURL).To(Equal("https://idp.example.com/sso"))
+			Expect(idp.EntityID).To(Equal("https://idp.example.com"))
+			Expect(idp.Certificate.Equal(idpCert)).To(BeTrue())
+		})
+
+		It("fails when the certificate isn't PEM encoded", func() {
+			_, err := saml.NewIdentityProvider(&db.SAMLAuth{IdPCertificate: "nope"})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,177 @@
+package saml
+
+import (
+	"crypto"
+	"crypto/rsa"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	dsigNamespace = "http://www.w3.org/2000/09/xmldsig#"
+
+	excC14NAlgorithm      = "http://www.w3.org/2001/10/xml-exc-c14n#"
+	envelopedSigAlgorithm = "http://www.w3.org/2000/09/xmldsig#enveloped-signature"
+)
+
+// SHA-1 is deliberately missing; IdPs that still sign with it need to be
+// told to stop.
+var digestAlgorithms = map[string]crypto.Hash{
+	"http://www.w3.org/2001/04/xmlenc#sha256": crypto.SHA256,
+	"http://www.w3.org/2001/04/xmlenc#sha512": crypto.SHA512,
+}
+
+var signatureAlgorithms = map[string]crypto.Hash{
+	"http://www.w3.org/2001/04/xmldsig-more#rsa-sha256": crypto.SHA256,
+	"http://www.w3.org/2001/04/xmldsig-more#rsa-sha512": crypto.SHA512,
+}
+
+var errNotSigned = errors.New("not signed")
+
+// verifySignature checks that the element carries an enveloped signature
+// over itself, made with the certificate's key. Any key the signature says
+// it was made with is ignored.
+//
+// Only what's within a verified element can be trusted, so callers must go
+// on to read from the element they passed in rather than looking anything
+// up by ID.
+func verifySignature(e *element, cert *x509.Certificate) error {
+	signatures := e.ChildElements(dsigNamespace, "Signature")
+	if len(signatures) == 0 {
+		return errNotSigned
+	}
+
+	if len(signatures) > 1 {
+		return errors.New("more than one signature")
+	}
+
+	signature := signatures[0]
+
+	id := e.Attr("ID")
+	if id == "" {
+		return errors.New("signed element has no ID")
+	}
+
+	signedInfo, err := signature.ChildElement(dsigNamespace, "SignedInfo")
+	if err != nil {
+		return err
+	}
+
+	c14nMethod, err := signedInfo.ChildElement(dsigNamespace, "CanonicalizationMethod")
+	if err != nil {
+		return err
+	}
+
+	if c14nMethod.Attr("Algorithm") != excC14NAlgorithm {
+		return fmt.Errorf("unsupported canonicalization method: %s", c14nMethod.Attr("Algorithm"))
+	}
+
+	signatureMethod, err := signedInfo.ChildElement(dsigNamespace, "SignatureMethod")
+	if err != nil {
+		return err
+	}
+
+	signatureHash, found := signatureAlgorithms[signatureMethod.Attr("Algorithm")]
+	if !found {
+		return fmt.Errorf("unsupported signature method: %s", signatureMethod.Attr("Algorithm"))
+	}
+
+	reference, err := signedInfo.ChildElement(dsigNamespace, "Reference")
+	if err != nil {
+		return err
+	}
+
+	if reference.Attr("URI") != "#"+id {
+		return fmt.Errorf("signature is for %q, not %q", reference.Attr("URI"), "#"+id)
+	}
+
+	transforms, err := reference.ChildElement(dsigNamespace, "Transforms")
+	if err != nil {
+		return err
+	}
+
+	var enveloped, canonicalized bool
+	var referencePrefixes []string
+	for _, transform := range transforms.ChildElements(dsigNamespace, "Transform") {
+		switch transform.Attr("Algorithm") {
+		case envelopedSigAlgorithm:
+			enveloped = true
+		case excC14NAlgorithm:
+			canonicalized = true
+			referencePrefixes = inclusivePrefixes(transform)
+		default:
+			return fmt.Errorf("unsupported transform: %s", transform.Attr("Algorithm"))
+		}
+	}
+
+	if !enveloped || !canonicalized {
+		return errors.New("signature must be enveloped and canonicalized")
+	}
+
+	digestMethod, err := reference.ChildElement(dsigNamespace, "DigestMethod")
+	if err != nil {
+		return err
+	}
+
+	digestHash, found := digestAlgorithms[digestMethod.Attr("Algorithm")]
+	if !found {
+		return fmt.Errorf("unsupported digest method: %s", digestMethod.Attr("Algorithm"))
+	}
+
+	digestValue, err := reference.ChildElement(dsigNamespace, "DigestValue")
+	if err != nil {
+		return err
+	}
+
+	expectedDigest, err := decodeBase64(digestValue.Text())
+	if err != nil {
+		return err
+	}
+
+	digest := digestHash.New()
+	digest.Write(canonicalize(e, referencePrefixes, signature))
+
+	if subtle.ConstantTimeCompare(digest.Sum(nil), expectedDigest) != 1 {
+		return errors.New("digest does not match")
+	}
+
+	signatureValue, err := signature.ChildElement(dsigNamespace, "SignatureValue")
+	if err != nil {
+		return err
+	}
+
+	signatureBytes, err := decodeBase64(signatureValue.Text())
+	if err != nil {
+		return err
+	}
+
+	publicKey, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return errors.New("certificate does not have an RSA key")
+	}
+
+	signed := signatureHash.New()
+	signed.Write(canonicalize(signedInfo, inclusivePrefixes(c14nMethod), nil))
+
+	return rsa.VerifyPKCS1v15(publicKey, signatureHash, signed.Sum(nil), signatureBytes)
+}
+
+func inclusivePrefixes(e *element) []string {
+	for _, child := range e.ChildElements(excC14NAlgorithm, "InclusiveNamespaces") {
+		return strings.Fields(child.Attr("PrefixList"))
+	}
+
+	return nil
+}
+
+// decodeBase64 ignores the whitespace that base64 is usually wrapped with in
+// XML.
+func decodeBase64(value string) ([]byte, error) {
+	return base64.StdEncoding.DecodeString(strings.Join(strings.Fields(value), ""))
+}
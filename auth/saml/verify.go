@@ -0,0 +1,34 @@
+package saml
+
+import (
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/db"
+)
+
+// Verify lets the user in if their name ID is one of the team's users, or the
+// groups attribute puts them in one of its groups.
+func Verify(logger lager.Logger, samlAuth *db.SAMLAuth, assertion Assertion) bool {
+	for _, user := range samlAuth.Users {
+		if user == assertion.NameID {
+			return true
+		}
+	}
+
+	userGroups := assertion.Attributes[samlAuth.GroupsAttribute]
+	for _, group := range samlAuth.Groups {
+		for _, userGroup := range userGroups {
+			if group == userGroup {
+				return true
+			}
+		}
+	}
+
+	logger.Info("not-in-groups-or-users", lager.Data{
+		"name-id":     assertion.NameID,
+		"have-groups": userGroups,
+		"want-groups": samlAuth.Groups,
+		"want-users":  samlAuth.Users,
+	})
+
+	return false
+}
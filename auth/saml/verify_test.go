@@ -0,0 +1,49 @@
+package saml_test
+
+import (
+	"code.cloudfoundry.org/lager/lagertest"
+	"github.com/concourse/atc/auth/saml"
+	"github.com/concourse/atc/db"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Verify", func() {
+	var samlAuth *db.SAMLAuth
+	var assertion saml.Assertion
+
+	BeforeEach(func() {
+		samlAuth = &db.SAMLAuth{
+			GroupsAttribute: "memberOf",
+			Groups:          []string{"ci-admins"},
+			Users:           []string{"someone@example.com"},
+		}
+
+		assertion = saml.Assertion{
+			NameID: "someone-else@example.com",
+			Attributes: map[string][]string{
+				"memberOf": {"everyone"},
+				"groups":   {"ci-admins"},
+			},
+		}
+	})
+
+	verified := func() bool {
+		return saml.Verify(lagertest.NewTestLogger("test"), samlAuth, assertion)
+	}
+
+	It("rejects those who aren't users or in a group", func() {
+		Expect(verified()).To(BeFalse())
+	})
+
+	It("lets in users by their name ID", func() {
+		assertion.NameID = "someone@example.com"
+		Expect(verified()).To(BeTrue())
+	})
+
+	It("lets in those in a group through the groups attribute", func() {
+		assertion.Attributes["memberOf"] = append(assertion.Attributes["memberOf"], "ci-admins")
+		Expect(verified()).To(BeTrue())
+	})
+})
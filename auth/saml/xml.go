@@ -0,0 +1,207 @@
+package saml
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const xmlNamespace = "http://www.w3.org/XML/1998/namespace"
+
+// element is a parsed XML element that remembers the prefixes it was written
+// with, which encoding/xml's Unmarshal throws away. Signatures are made over
+// the document as it was written, so they can't be checked without them.
+type element struct {
+	Prefix string
+	Local  string
+
+	// Attrs excludes namespace declarations, which are in Namespaces keyed
+	// by prefix, with "" for the default namespace.
+	Attrs      []xml.Attr
+	Namespaces map[string]string
+
+	Children []interface{}
+
+	parent *element
+}
+
+// text is character data within an element.
+type text string
+
+func parseXML(document []byte) (*element, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(document))
+
+	var root, current *element
+	for {
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if current == nil && root != nil {
+				return nil, errors.New("more than one document element")
+			}
+
+			e := &element{
+				Prefix:     t.Name.Space,
+				Local:      t.Name.Local,
+				Namespaces: map[string]string{},
+				parent:     current,
+			}
+
+			// encoding/xml allows an attribute to be given more than once,
+			// and which of them is read needn't be which of them was signed
+			seen := map[xml.Name]bool{}
+			for _, attr := range t.Attr {
+				if seen[attr.Name] {
+					return nil, fmt.Errorf("attribute %s given more than once", qualifiedName(attr.Name.Space, attr.Name.Local))
+				}
+
+				seen[attr.Name] = true
+
+				switch {
+				case attr.Name.Space == "xmlns":
+					e.Namespaces[attr.Name.Local] = attr.Value
+				case attr.Name.Space == "" && attr.Name.Local == "xmlns":
+					e.Namespaces[""] = attr.Value
+				default:
+					e.Attrs = append(e.Attrs, attr)
+				}
+			}
+
+			if current == nil {
+				root = e
+			} else {
+				current.Children = append(current.Children, e)
+			}
+
+			current = e
+
+		case xml.EndElement:
+			if current == nil || t.Name.Space != current.Prefix || t.Name.Local != current.Local {
+				return nil, fmt.Errorf("unexpected end element </%s>", qualifiedName(t.Name.Space, t.Name.Local))
+			}
+
+			current = current.parent
+
+		case xml.CharData:
+			if current != nil {
+				current.Children = append(current.Children, text(t))
+			} else if len(bytes.TrimSpace(t)) > 0 {
+				return nil, errors.New("text outside of the document element")
+			}
+
+		case xml.ProcInst:
+			if current != nil {
+				return nil, errors.New("unexpected processing instruction")
+			}
+
+		case xml.Directive:
+			// no DTDs means no entities, external or otherwise
+			return nil, errors.New("unexpected directive")
+
+		case xml.Comment:
+			// comments aren't signed, so they may as well not be there
+		}
+	}
+
+	if root == nil {
+		return nil, errors.New("no document element")
+	}
+
+	if current != nil {
+		return nil, fmt.Errorf("unclosed element <%s>", qualifiedName(current.Prefix, current.Local))
+	}
+
+	return root, nil
+}
+
+// lookupNamespace returns the namespace the prefix is bound to where the
+// element is.
+func (e *element) lookupNamespace(prefix string) (string, bool) {
+	if prefix == "xml" {
+		return xmlNamespace, true
+	}
+
+	for scope := e; scope != nil; scope = scope.parent {
+		if uri, found := scope.Namespaces[prefix]; found {
+			return uri, true
+		}
+	}
+
+	// the default namespace starts out as no namespace at all
+	return "", prefix == ""
+}
+
+func (e *element) Namespace() string {
+	uri, _ := e.lookupNamespace(e.Prefix)
+	return uri
+}
+
+func (e *element) Is(namespace string, local string) bool {
+	return e.Local == local && e.Namespace() == namespace
+}
+
+// Attr returns the value of an unqualified attribute.
+func (e *element) Attr(name string) string {
+	for _, attr := range e.Attrs {
+		if attr.Name.Space == "" && attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+
+	return ""
+}
+
+func (e *element) ChildElements(namespace string, local string) []*element {
+	children := []*element{}
+	for _, child := range e.Children {
+		if c, ok := child.(*element); ok && c.Is(namespace, local) {
+			children = append(children, c)
+		}
+	}
+
+	return children
+}
+
+// ChildElement returns the only child with the name, failing if there are
+// none or several.
+func (e *element) ChildElement(namespace string, local string) (*element, error) {
+	children := e.ChildElements(namespace, local)
+	if len(children) != 1 {
+		return nil, fmt.Errorf("expected one %s in %s, found %d", local, e.Local, len(children))
+	}
+
+	return children[0], nil
+}
+
+// Text returns all of the text directly within the element. Comments have
+// already been dropped, so one can't be used to split a value and have only
+// part of it read.
+func (e *element) Text() string {
+	var value bytes.Buffer
+	for _, child := range e.Children {
+		if t, ok := child.(text); ok {
+			value.WriteString(string(t))
+		}
+	}
+
+	return strings.TrimSpace(value.String())
+}
+
+func qualifiedName(prefix string, local string) string {
+	if prefix == "" {
+		return local
+	}
+
+	return prefix + ":" + local
+}
@@ -0,0 +1,194 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/auth/saml"
+	"github.com/concourse/atc/db"
+)
+
+// SAMLACSHandler is the assertion consumer service, which the IdP posts its
+// response to once the user has logged in. Its session is issued just as an
+// OAuth callback's is.
+type SAMLACSHandler struct {
+	logger          lager.Logger
+	serviceProvider saml.ServiceProvider
+	tokenGenerator  TokenGenerator
+	teamDBFactory   db.TeamDBFactory
+}
+
+func NewSAMLACSHandler(
+	logger lager.Logger,
+	serviceProvider saml.ServiceProvider,
+	privateKey *rsa.PrivateKey,
+	teamDBFactory db.TeamDBFactory,
+) http.Handler {
+	return &SAMLACSHandler{
+		logger:          logger,
+		serviceProvider: serviceProvider,
+		tokenGenerator:  NewTokenGenerator(privateKey),
+		teamDBFactory:   teamDBFactory,
+	}
+}
+
+func (handler *SAMLACSHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	hLog := handler.logger.Session("saml-acs")
+
+	relayState := r.PostFormValue("RelayState")
+
+	cookieState, err := r.Cookie(SAMLStateCookie)
+	if err != nil {
+		hLog.Info("no-state-cookie", lager.Data{
+			"error": err.Error(),
+		})
+		http.Error(w, "state cookie not set", http.StatusUnauthorized)
+		return
+	}
+
+	if cookieState.Value != relayState {
+		hLog.Info("state-cookie-mismatch", lager.Data{
+			"relay-state":  relayState,
+			"cookie-state": cookieState.Value,
+		})
+		http.Error(w, "state cookie does not match relay state", http.StatusUnauthorized)
+		return
+	}
+
+	stateJSON, err := base64.RawURLEncoding.DecodeString(relayState)
+	if err != nil {
+		hLog.Info("failed-to-decode-state", lager.Data{
+			"error": err.Error(),
+		})
+		http.Error(w, "state value invalid base64", http.StatusUnauthorized)
+		return
+	}
+
+	var samlState SAMLState
+	err = json.Unmarshal(stateJSON, &samlState)
+	if err != nil {
+		hLog.Info("failed-to-unmarshal-state", lager.Data{
+			"error": err.Error(),
+		})
+		http.Error(w, "state value invalid JSON", http.StatusUnauthorized)
+		return
+	}
+
+	teamName := samlState.TeamName
+	teamDB := handler.teamDBFactory.GetTeamDB(teamName)
+	team, found, err := teamDB.GetTeam()
+	if err != nil {
+		hLog.Error("failed-to-get-team", err)
+		http.Error(w, "failed to get team", http.StatusInternalServerError)
+		return
+	}
+
+	if !found {
+		hLog.Info("failed-to-find-team", lager.Data{
+			"teamName": teamName,
+		})
+		http.Error(w, "failed to find team", http.StatusNotFound)
+		return
+	}
+
+	if team.SAMLAuth == nil {
+		hLog.Info("team-does-not-have-saml-auth", lager.Data{
+			"teamName": teamName,
+		})
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	idp, err := saml.NewIdentityProvider(team.SAMLAuth)
+	if err != nil {
+		hLog.Error("failed-to-construct-identity-provider", err)
+		http.Error(w, "failed to construct identity provider", http.StatusInternalServerError)
+		return
+	}
+
+	// the request is forgotten as it's taken, so that whatever happens next,
+	// nothing else will be accepted in response to it
+	redirect, found, err := teamDB.TakeSAMLRequest(samlState.RequestID)
+	if err != nil {
+		hLog.Error("failed-to-take-request", err)
+		http.Error(w, "failed to look up SAML request", http.StatusInternalServerError)
+		return
+	}
+
+	if !found {
+		hLog.Info("unknown-request", lager.Data{
+			"request-id": samlState.RequestID,
+		})
+		http.Error(w, "SAML request is unknown or has expired", http.StatusUnauthorized)
+		return
+	}
+
+	assertion, err := handler.serviceProvider.ParseResponse(idp, r.PostFormValue("SAMLResponse"), samlState.RequestID, time.Now())
+	if err != nil {
+		hLog.Info("invalid-saml-response", lager.Data{
+			"error": err.Error(),
+		})
+		http.Error(w, "invalid SAML response", http.StatusUnauthorized)
+		return
+	}
+
+	fresh, err := teamDB.SaveSAMLAssertion(assertion.ID, assertion.ExpiresAt)
+	if err != nil {
+		hLog.Error("failed-to-save-assertion", err)
+		http.Error(w, "failed to save SAML assertion", http.StatusInternalServerError)
+		return
+	}
+
+	if !fresh {
+		hLog.Info("assertion-replayed", lager.Data{
+			"assertion-id": assertion.ID,
+		})
+		http.Error(w, "SAML assertion has already been used", http.StatusUnauthorized)
+		return
+	}
+
+	if !saml.Verify(hLog.Session("verify"), team.SAMLAuth, assertion) {
+		hLog.Info("verification-failed")
+		http.Error(w, "verification failed", http.StatusUnauthorized)
+		return
+	}
+
+	exp := time.Now().Add(CookieAge)
+
+	tokenType, signedToken, err := handler.tokenGenerator.GenerateToken(exp, team.Name, team.ID, team.Admin, providerRole(team, saml.ProviderName))
+	if err != nil {
+		hLog.Error("failed-to-sign-token", err)
+		http.Error(w, "failed to sign token", http.StatusInternalServerError)
+		return
+	}
+
+	tokenStr := string(tokenType) + " " + string(signedToken)
+
+	http.SetCookie(w, &http.Cookie{
+		Name:    CookieName,
+		Value:   tokenStr,
+		Path:    "/",
+		Expires: exp,
+	})
+
+	// the state is no good now that its request has been answered
+	http.SetCookie(w, &http.Cookie{
+		Name:   SAMLStateCookie,
+		Path:   "/",
+		MaxAge: -1,
+	})
+
+	if redirect != "" {
+		// a temporary redirect would have the browser post the response
+		// again
+		http.Redirect(w, r, redirect, http.StatusSeeOther)
+		return
+	}
+
+	fmt.Fprintln(w, tokenStr)
+}
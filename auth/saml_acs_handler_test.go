@@ -0,0 +1,297 @@
+package auth_test
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/atc/auth"
+	"github.com/concourse/atc/auth/authfakes"
+	"github.com/concourse/atc/auth/saml"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/db/dbfakes"
+)
+
+// signedSAMLResponse is a response to the request with an assertion that the
+// key has signed, for someone@example.com to log in to atc.example.com until
+// the given time. The assertion is written in its canonical form so that it
+// can be signed as it is.
+func signedSAMLResponse(key *rsa.PrivateKey, requestID string, until time.Time) string {
+	xmlTime := func(t time.Time) string { return t.UTC().Format(time.RFC3339) }
+
+	notBefore := xmlTime(time.Now().Add(-time.Minute))
+
+	assertion := `<saml:Assertion xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion" ID="_assertion" IssueInstant="` + notBefore + `" Version="2.0">` +
+		`<saml:Issuer>https://idp.example.com</saml:Issuer>` +
+		`<saml:Subject>` +
+		`<saml:NameID>someone@example.com</saml:NameID>` +
+		`<saml:SubjectConfirmation Method="urn:oasis:names:tc:SAML:2.0:cm:bearer">` +
+		`<saml:SubjectConfirmationData InResponseTo="` + requestID + `" NotOnOrAfter="` + xmlTime(until) + `" Recipient="https://atc.example.com/auth/saml/acs"></saml:SubjectConfirmationData>` +
+		`</saml:SubjectConfirmation>` +
+		`</saml:Subject>` +
+		`<saml:Conditions NotBefore="` + notBefore + `" NotOnOrAfter="` + xmlTime(until) + `">` +
+		`<saml:AudienceRestriction><saml:Audience>https://atc.example.com/auth/saml/metadata</saml:Audience></saml:AudienceRestriction>` +
+		`</saml:Conditions>` +
+		`</saml:Assertion>`
+
+	digest := sha256.Sum256([]byte(assertion))
+
+	signedInfo := `<ds:SignedInfo xmlns:ds="http://www.w3.org/2000/09/xmldsig#">` +
+		`<ds:CanonicalizationMethod Algorithm="http://www.w3.org/2001/10/xml-exc-c14n#"></ds:CanonicalizationMethod>` +
+		`<ds:SignatureMethod Algorithm="http://www.w3.org/2001/04/xmldsig-more#rsa-sha256"></ds:SignatureMethod>` +
+		`<ds:Reference URI="#_assertion">` +
+		`<ds:Transforms>` +
+		`<ds:Transform Algorithm="http://www.w3.org/2000/09/xmldsig#enveloped-signature"></ds:Transform>` +
+		`<ds:Transform Algorithm="http://www.w3.org/2001/10/xml-exc-c14n#"></ds:Transform>` +
+		`</ds:Transforms>` +
+		`<ds:DigestMethod Algorithm="http://www.w3.org/2001/04/xmlenc#sha256"></ds:DigestMethod>` +
+		`<ds:DigestValue>` + base64.StdEncoding.EncodeToString(digest[:]) + `</ds:DigestValue>` +
+		`</ds:Reference>` +
+		`</ds:SignedInfo>`
+
+	hashed := sha256.Sum256([]byte(signedInfo))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hashed[:])
+	Expect(err).ToNot(HaveOccurred())
+
+	assertion = strings.Replace(assertion, "</saml:Issuer>", `</saml:Issuer><ds:Signature xmlns:ds="http://www.w3.org/2000/09/xmldsig#">`+
+		strings.Replace(signedInfo, ` xmlns:ds="http://www.w3.org/2000/09/xmldsig#"`, "", 1)+
+		`<ds:SignatureValue>`+base64.StdEncoding.EncodeToString(signature)+`</ds:SignatureValue>`+
+		`</ds:Signature>`, 1)
+
+	response := `<samlp:Response xmlns:samlp="urn:oasis:names:tc:SAML:2.0:protocol" Destination="https://atc.example.com/auth/saml/acs" ID="_response" InResponseTo="` + requestID + `" IssueInstant="` + notBefore + `" Version="2.0">` +
+		`<samlp:Status><samlp:StatusCode Value="urn:oasis:names:tc:SAML:2.0:status:Success"></samlp:StatusCode></samlp:Status>` +
+		assertion +
+		`</samlp:Response>`
+
+	return base64.StdEncoding.EncodeToString([]byte(response))
+}
+
+var _ = Describe("SAMLACSHandler", func() {
+	var (
+		fakeTeamDBFactory *dbfakes.FakeTeamDBFactory
+		fakeTeamDB        *dbfakes.FakeTeamDB
+
+		idpKey *rsa.PrivateKey
+
+		server *httptest.Server
+
+		relayState   string
+		cookieState  string
+		samlResponse string
+
+		response *http.Response
+	)
+
+	BeforeEach(func() {
+		var err error
+		idpKey, err = rsa.GenerateKey(rand.Reader, 1024)
+		Expect(err).ToNot(HaveOccurred())
+
+		fakeTeamDB = new(dbfakes.FakeTeamDB)
+		fakeTeamDBFactory = new(dbfakes.FakeTeamDBFactory)
+		fakeTeamDBFactory.GetTeamDBReturns(fakeTeamDB)
+
+		fakeTeamDB.GetTeamReturns(db.SavedTeam{
+			Team: db.Team{
+				Name: "some-team",
+				SAMLAuth: &db.SAMLAuth{
+					SSOURL:         "https://idp.example.com/sso",
+					IdPCertificate: samlIdPCertificate(idpKey),
+					Users:          []string{"someone@example.com"},
+				},
+			},
+		}, true, nil)
+
+		fakeTeamDB.TakeSAMLRequestReturns("/some-path", true, nil)
+		fakeTeamDB.SaveSAMLAssertionReturns(true, nil)
+
+		signingKey, err := rsa.GenerateKey(rand.Reader, 1024)
+		Expect(err).ToNot(HaveOccurred())
+
+		handler, err := auth.NewOAuthHandler(
+			lagertest.NewTestLogger("test"),
+			new(authfakes.FakeProviderFactory),
+			fakeTeamDBFactory,
+			signingKey,
+			"https://atc.example.com",
+		)
+		Expect(err).ToNot(HaveOccurred())
+
+		server = httptest.NewServer(handler)
+
+		state, err := json.Marshal(auth.SAMLState{
+			TeamName:  "some-team",
+			RequestID: "_some-request",
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		relayState = base64.RawURLEncoding.EncodeToString(state)
+		cookieState = relayState
+		samlResponse = base64.StdEncoding.EncodeToString([]byte("<samlp:Response/>"))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	JustBeforeEach(func() {
+		request, err := http.NewRequest("POST", server.URL+"/auth/saml/acs", strings.NewReader(url.Values{
+			"RelayState":   {relayState},
+			"SAMLResponse": {samlResponse},
+		}.Encode()))
+		Expect(err).ToNot(HaveOccurred())
+
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		if cookieState != "" {
+			request.AddCookie(&http.Cookie{Name: auth.SAMLStateCookie, Value: cookieState})
+		}
+
+		response, err = (&http.Transport{}).RoundTrip(request)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	Context("when the response is valid", func() {
+		var until time.Time
+
+		BeforeEach(func() {
+			until = time.Now().Add(5 * time.Minute).Truncate(time.Second)
+			samlResponse = signedSAMLResponse(idpKey, "_some-request", until)
+		})
+
+		It("logs the user in and redirects to where they were going", func() {
+			Expect(response.StatusCode).To(Equal(http.StatusSeeOther))
+			Expect(response.Header.Get("Location")).To(Equal("/some-path"))
+			Expect(response.Header.Get("Set-Cookie")).To(HavePrefix(auth.CookieName + "="))
+		})
+
+		It("takes the request from the state", func() {
+			Expect(fakeTeamDB.TakeSAMLRequestCallCount()).To(Equal(1))
+			Expect(fakeTeamDB.TakeSAMLRequestArgsForCall(0)).To(Equal("_some-request"))
+		})
+
+		It("remembers the assertion until it expires", func() {
+			Expect(fakeTeamDB.SaveSAMLAssertionCallCount()).To(Equal(1))
+			assertionID, expiresAt := fakeTeamDB.SaveSAMLAssertionArgsForCall(0)
+			Expect(assertionID).To(Equal("_assertion"))
+			Expect(expiresAt).To(BeTemporally("==", until.Add(saml.ClockSkew)))
+		})
+
+		Context("but the assertion has been used before", func() {
+			BeforeEach(func() {
+				fakeTeamDB.SaveSAMLAssertionReturns(false, nil)
+			})
+
+			It("returns 401 without logging anyone in", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+				Expect(response.Header.Get("Set-Cookie")).To(BeEmpty())
+			})
+		})
+
+		Context("but the assertion can't be saved", func() {
+			BeforeEach(func() {
+				fakeTeamDB.SaveSAMLAssertionReturns(false, errors.New("nope"))
+			})
+
+			It("returns 500 without logging anyone in", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				Expect(response.Header.Get("Set-Cookie")).To(BeEmpty())
+			})
+		})
+
+		Context("but the request has already been answered or has expired", func() {
+			BeforeEach(func() {
+				fakeTeamDB.TakeSAMLRequestReturns("", false, nil)
+			})
+
+			It("returns 401 without logging anyone in", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+				Expect(response.Header.Get("Set-Cookie")).To(BeEmpty())
+				Expect(fakeTeamDB.SaveSAMLAssertionCallCount()).To(BeZero())
+			})
+		})
+
+		Context("but the state is for a request the ATC never made", func() {
+			BeforeEach(func() {
+				state, err := json.Marshal(auth.SAMLState{
+					TeamName:  "some-team",
+					RequestID: "_forged-request",
+				})
+				Expect(err).ToNot(HaveOccurred())
+
+				relayState = base64.RawURLEncoding.EncodeToString(state)
+				cookieState = relayState
+				samlResponse = signedSAMLResponse(idpKey, "_forged-request", until)
+
+				fakeTeamDB.TakeSAMLRequestStub = func(id string) (string, bool, error) {
+					return "/some-path", id == "_some-request", nil
+				}
+			})
+
+			It("returns 401 without logging anyone in", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+				Expect(response.Header.Get("Set-Cookie")).To(BeEmpty())
+			})
+		})
+	})
+
+	Context("when the response is not valid", func() {
+		It("returns 401 without logging anyone in", func() {
+			Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			Expect(response.Header.Get("Set-Cookie")).To(BeEmpty())
+		})
+
+		It("looks up the team from the state", func() {
+			Expect(fakeTeamDBFactory.GetTeamDBArgsForCall(0)).To(Equal("some-team"))
+		})
+
+		It("still uses up the request", func() {
+			Expect(fakeTeamDB.TakeSAMLRequestCallCount()).To(Equal(1))
+		})
+	})
+
+	Context("when there is no state cookie", func() {
+		BeforeEach(func() {
+			cookieState = ""
+		})
+
+		It("returns 401", func() {
+			Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			Expect(fakeTeamDB.GetTeamCallCount()).To(BeZero())
+		})
+	})
+
+	Context("when the state cookie does not match the relay state", func() {
+		BeforeEach(func() {
+			cookieState = "some-other-state"
+		})
+
+		It("returns 401", func() {
+			Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			Expect(fakeTeamDB.GetTeamCallCount()).To(BeZero())
+		})
+	})
+
+	Context("when the team no longer logs in through SAML", func() {
+		BeforeEach(func() {
+			fakeTeamDB.GetTeamReturns(db.SavedTeam{Team: db.Team{Name: "some-team"}}, true, nil)
+		})
+
+		It("returns 404", func() {
+			Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+		})
+	})
+})
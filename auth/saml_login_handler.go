@@ -0,0 +1,150 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/auth/saml"
+	"github.com/concourse/atc/db"
+)
+
+const SAMLStateCookie = "_concourse_saml_state"
+
+// SAMLRequestAge is how long the user has to log in to the IdP.
+const SAMLRequestAge = 10 * time.Minute
+
+type SAMLState struct {
+	TeamName string `json:"team_name"`
+
+	// RequestID is what the IdP's response must be in response to. The
+	// request itself is kept in the database, along with where to redirect
+	// to, until it's been answered or expires, so only IDs that the ATC
+	// handed out and that are still waiting for a response are any good.
+	RequestID string `json:"request_id"`
+}
+
+type SAMLLogInHandler struct {
+	logger          lager.Logger
+	serviceProvider saml.ServiceProvider
+	teamDBFactory   db.TeamDBFactory
+}
+
+func NewSAMLLogInHandler(
+	logger lager.Logger,
+	serviceProvider saml.ServiceProvider,
+	teamDBFactory db.TeamDBFactory,
+) http.Handler {
+	return &SAMLLogInHandler{
+		logger:          logger,
+		serviceProvider: serviceProvider,
+		teamDBFactory:   teamDBFactory,
+	}
+}
+
+func (handler *SAMLLogInHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	hLog := handler.logger.Session("saml-login")
+	teamName := r.FormValue("team_name")
+
+	teamDB := handler.teamDBFactory.GetTeamDB(teamName)
+	team, found, err := teamDB.GetTeam()
+	if err != nil {
+		hLog.Error("failed-to-get-team", err, lager.Data{
+			"teamName": teamName,
+		})
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if !found {
+		hLog.Info("failed-to-find-team", lager.Data{
+			"teamName": teamName,
+		})
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if team.SAMLAuth == nil {
+		hLog.Info("team-does-not-have-saml-auth", lager.Data{
+			"teamName": teamName,
+		})
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	idp, err := saml.NewIdentityProvider(team.SAMLAuth)
+	if err != nil {
+		hLog.Error("failed-to-construct-identity-provider", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	id := make([]byte, 16)
+	_, err = rand.Read(id)
+	if err != nil {
+		hLog.Error("failed-to-generate-request-id", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// IDs must not start with a digit
+	requestID := "_" + hex.EncodeToString(id)
+
+	expiresAt := time.Now().Add(SAMLRequestAge)
+
+	err = teamDB.SaveSAMLRequest(requestID, r.FormValue("redirect"), expiresAt)
+	if err != nil {
+		hLog.Error("failed-to-save-request", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	samlState, err := json.Marshal(SAMLState{
+		TeamName:  teamName,
+		RequestID: requestID,
+	})
+	if err != nil {
+		hLog.Error("failed-to-marshal-state", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	encodedState := base64.RawURLEncoding.EncodeToString(samlState)
+
+	authnRequestURL, err := handler.serviceProvider.AuthnRequestURL(idp, requestID, encodedState, time.Now())
+	if err != nil {
+		hLog.Error("failed-to-construct-authn-request", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	setCrossSiteCookie(w, handler.serviceProvider, &http.Cookie{
+		Name:     SAMLStateCookie,
+		Value:    encodedState,
+		Path:     "/",
+		Expires:  expiresAt,
+		HttpOnly: true,
+	})
+
+	http.Redirect(w, r, authnRequestURL, http.StatusTemporaryRedirect)
+}
+
+// setCrossSiteCookie sets a cookie that the browser will still send with the
+// IdP's cross-site POST to the ACS. Browsers only allow that over https, so
+// over http the cookie is left as it is.
+func setCrossSiteCookie(w http.ResponseWriter, serviceProvider saml.ServiceProvider, cookie *http.Cookie) {
+	if !strings.HasPrefix(serviceProvider.ACSURL, "https://") {
+		http.SetCookie(w, cookie)
+		return
+	}
+
+	cookie.Secure = true
+
+	// net/http doesn't know about SameSite yet
+	w.Header().Add("Set-Cookie", cookie.String()+"; SameSite=None")
+}
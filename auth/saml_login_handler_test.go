@@ -0,0 +1,177 @@
+package auth_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"time"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/atc/auth"
+	"github.com/concourse/atc/auth/authfakes"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/db/dbfakes"
+)
+
+func samlIdPCertificate(key *rsa.PrivateKey) string {
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "idp.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	Expect(err).ToNot(HaveOccurred())
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+var _ = Describe("SAMLLogInHandler", func() {
+	var (
+		fakeTeamDBFactory *dbfakes.FakeTeamDBFactory
+		fakeTeamDB        *dbfakes.FakeTeamDB
+
+		idpKey *rsa.PrivateKey
+
+		server *httptest.Server
+		client *http.Client
+
+		response *http.Response
+	)
+
+	BeforeEach(func() {
+		fakeTeamDB = new(dbfakes.FakeTeamDB)
+		fakeTeamDBFactory = new(dbfakes.FakeTeamDBFactory)
+		fakeTeamDBFactory.GetTeamDBReturns(fakeTeamDB)
+
+		signingKey, err := rsa.GenerateKey(rand.Reader, 1024)
+		Expect(err).ToNot(HaveOccurred())
+
+		idpKey, err = rsa.GenerateKey(rand.Reader, 1024)
+		Expect(err).ToNot(HaveOccurred())
+
+		handler, err := auth.NewOAuthHandler(
+			lagertest.NewTestLogger("test"),
+			new(authfakes.FakeProviderFactory),
+			fakeTeamDBFactory,
+			signingKey,
+			"https://atc.example.com",
+		)
+		Expect(err).ToNot(HaveOccurred())
+
+		server = httptest.NewServer(handler)
+
+		client = &http.Client{
+			Transport: &http.Transport{},
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		response, err = client.Get(server.URL + "/auth/saml/login?" + url.Values{
+			"team_name": {"some-team"},
+			"redirect":  {"/some-path"},
+		}.Encode())
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	Context("when the team logs in through SAML", func() {
+		BeforeEach(func() {
+			fakeTeamDB.GetTeamReturns(db.SavedTeam{
+				Team: db.Team{
+					Name: "some-team",
+					SAMLAuth: &db.SAMLAuth{
+						SSOURL:         "https://idp.example.com/sso",
+						IdPCertificate: samlIdPCertificate(idpKey),
+					},
+				},
+			}, true, nil)
+		})
+
+		It("redirects to the IdP with the state as the relay state", func() {
+			Expect(response.StatusCode).To(Equal(http.StatusTemporaryRedirect))
+
+			location, err := url.Parse(response.Header.Get("Location"))
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(location.Host).To(Equal("idp.example.com"))
+			Expect(location.Path).To(Equal("/sso"))
+			Expect(location.Query().Get("SAMLRequest")).ToNot(BeEmpty())
+
+			relayState := location.Query().Get("RelayState")
+
+			stateJSON, err := base64.RawURLEncoding.DecodeString(relayState)
+			Expect(err).ToNot(HaveOccurred())
+
+			var state auth.SAMLState
+			err = json.Unmarshal(stateJSON, &state)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(state.TeamName).To(Equal("some-team"))
+			Expect(state.RequestID).To(HavePrefix("_"))
+
+			Expect(fakeTeamDB.SaveSAMLRequestCallCount()).To(Equal(1))
+			requestID, redirect, expiresAt := fakeTeamDB.SaveSAMLRequestArgsForCall(0)
+			Expect(requestID).To(Equal(state.RequestID))
+			Expect(redirect).To(Equal("/some-path"))
+			Expect(expiresAt).To(BeTemporally("~", time.Now().Add(auth.SAMLRequestAge), time.Minute))
+
+			cookie := response.Header.Get("Set-Cookie")
+			Expect(cookie).To(HavePrefix(auth.SAMLStateCookie + "=" + relayState + ";"))
+			Expect(cookie).To(ContainSubstring("; HttpOnly"))
+			Expect(cookie).To(ContainSubstring("; Secure"))
+			Expect(cookie).To(HaveSuffix("; SameSite=None"))
+		})
+
+		Context("when the request can't be saved", func() {
+			BeforeEach(func() {
+				fakeTeamDB.SaveSAMLRequestReturns(errors.New("nope"))
+			})
+
+			It("returns 500 without redirecting to the IdP", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+				Expect(response.Header.Get("Location")).To(BeEmpty())
+			})
+		})
+	})
+
+	Context("when the team does not log in through SAML", func() {
+		BeforeEach(func() {
+			fakeTeamDB.GetTeamReturns(db.SavedTeam{Team: db.Team{Name: "some-team"}}, true, nil)
+		})
+
+		It("returns 404", func() {
+			Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Context("when the team does not exist", func() {
+		BeforeEach(func() {
+			fakeTeamDB.GetTeamReturns(db.SavedTeam{}, false, nil)
+		})
+
+		It("returns 404", func() {
+			Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+		})
+	})
+})
@@ -0,0 +1,35 @@
+package auth
+
+import (
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/auth/saml"
+)
+
+type SAMLMetadataHandler struct {
+	logger          lager.Logger
+	serviceProvider saml.ServiceProvider
+}
+
+func NewSAMLMetadataHandler(
+	logger lager.Logger,
+	serviceProvider saml.ServiceProvider,
+) http.Handler {
+	return &SAMLMetadataHandler{
+		logger:          logger,
+		serviceProvider: serviceProvider,
+	}
+}
+
+func (handler *SAMLMetadataHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	metadata, err := handler.serviceProvider.Metadata()
+	if err != nil {
+		handler.logger.Error("failed-to-marshal-metadata", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/samlmetadata+xml")
+	w.Write(metadata)
+}
@@ -0,0 +1,55 @@
+package auth_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/atc/auth"
+	"github.com/concourse/atc/auth/authfakes"
+	"github.com/concourse/atc/db/dbfakes"
+)
+
+var _ = Describe("SAMLMetadataHandler", func() {
+	var server *httptest.Server
+
+	BeforeEach(func() {
+		signingKey, err := rsa.GenerateKey(rand.Reader, 1024)
+		Expect(err).ToNot(HaveOccurred())
+
+		handler, err := auth.NewOAuthHandler(
+			lagertest.NewTestLogger("test"),
+			new(authfakes.FakeProviderFactory),
+			new(dbfakes.FakeTeamDBFactory),
+			signingKey,
+			"https://atc.example.com",
+		)
+		Expect(err).ToNot(HaveOccurred())
+
+		server = httptest.NewServer(handler)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("describes the ATC to IdPs", func() {
+		response, err := http.Get(server.URL + "/auth/saml/metadata")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(response.StatusCode).To(Equal(http.StatusOK))
+		Expect(response.Header.Get("Content-Type")).To(Equal("application/samlmetadata+xml"))
+
+		body, err := ioutil.ReadAll(response.Body)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(string(body)).To(ContainSubstring(`entityID="https://atc.example.com/auth/saml/metadata"`))
+		Expect(string(body)).To(ContainSubstring(`Location="https://atc.example.com/auth/saml/acs"`))
+	})
+})
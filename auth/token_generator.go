@@ -4,6 +4,7 @@ import (
 	"crypto/rsa"
 	"time"
 
+	"github.com/concourse/atc"
 	"github.com/dgrijalva/jwt-go"
 )
 
@@ -17,9 +18,11 @@ const expClaimKey = "exp"
 const teamNameClaimKey = "teamName"
 const teamIDClaimKey = "teamID"
 const isAdminClaimKey = "isAdmin"
+const roleClaimKey = "role"
 
 type TokenGenerator interface {
-	GenerateToken(expiration time.Time, teamName string, teamID int, isAdmin bool) (TokenType, TokenValue, error)
+	GenerateToken(expiration time.Time, teamName string, teamID int, isAdmin bool, role atc.Role) (TokenType, TokenValue, error)
+	GenerateAPIToken(tokenID int, scopes []Scope, expiration time.Time, teamName string, teamID int, isAdmin bool, role atc.Role) (TokenType, TokenValue, error)
 }
 
 type tokenGenerator struct {
@@ -32,12 +35,13 @@ func NewTokenGenerator(privateKey *rsa.PrivateKey) TokenGenerator {
 	}
 }
 
-func (generator *tokenGenerator) GenerateToken(expiration time.Time, teamName string, teamID int, isAdmin bool) (TokenType, TokenValue, error) {
+func (generator *tokenGenerator) GenerateToken(expiration time.Time, teamName string, teamID int, isAdmin bool, role atc.Role) (TokenType, TokenValue, error) {
 	jwtToken := jwt.NewWithClaims(SigningMethod, jwt.MapClaims{
 		"exp":      expiration.Unix(),
 		"teamName": teamName,
 		"teamID":   teamID,
 		"isAdmin":  isAdmin,
+		"role":     role,
 	})
 
 	signed, err := jwtToken.SignedString(generator.privateKey)
@@ -47,3 +51,28 @@ func (generator *tokenGenerator) GenerateToken(expiration time.Time, teamName st
 
 	return TokenTypeBearer, TokenValue(signed), err
 }
+
+// GenerateAPIToken signs a token that is only good for as long as the record
+// with the given ID exists, and only for the given scopes and role. It never
+// expires if the expiration is zero.
+func (generator *tokenGenerator) GenerateAPIToken(tokenID int, scopes []Scope, expiration time.Time, teamName string, teamID int, isAdmin bool, role atc.Role) (TokenType, TokenValue, error) {
+	claims := jwt.MapClaims{
+		teamNameClaimKey: teamName,
+		teamIDClaimKey:   teamID,
+		isAdminClaimKey:  isAdmin,
+		roleClaimKey:     role,
+		tokenIDClaimKey:  tokenID,
+		scopesClaimKey:   scopes,
+	}
+
+	if !expiration.IsZero() {
+		claims[expClaimKey] = expiration.Unix()
+	}
+
+	signed, err := jwt.NewWithClaims(SigningMethod, claims).SignedString(generator.privateKey)
+	if err != nil {
+		return "", "", err
+	}
+
+	return TokenTypeBearer, TokenValue(signed), nil
+}
@@ -1,18 +1,17 @@
 package auth
 
 import (
-	"fmt"
 	"net/http"
+
+	"github.com/concourse/atc/api/apierror"
 )
 
 type UnauthorizedRejector struct{}
 
 func (UnauthorizedRejector) Unauthorized(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusUnauthorized)
-	fmt.Fprintf(w, "not authorized")
+	apierror.Unauthorized(w, "not authorized")
 }
 
 func (UnauthorizedRejector) Forbidden(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusForbidden)
-	fmt.Fprintf(w, "forbidden")
+	apierror.Forbidden(w, "forbidden")
 }
@@ -10,6 +10,7 @@ var teamNameKey = "teamName"
 var teamIDKey = "teamID"
 var isAdminKey = "isAdmin"
 var isSystemKey = "system"
+var roleKey = "role"
 
 func WrapHandler(
 	handler http.Handler,
@@ -42,5 +43,10 @@ func (h authHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if found {
 		ctx = context.WithValue(ctx, isSystemKey, isSystem)
 	}
+
+	role, found := h.userContextReader.GetRole(r)
+	if found {
+		ctx = context.WithValue(ctx, roleKey, role)
+	}
 	h.handler.ServeHTTP(w, r.WithContext(ctx))
 }
@@ -108,6 +108,100 @@ func (auth *GenericOAuthFlag) Validate() error {
 	return errs.ErrorOrNil()
 }
 
+type OIDCAuthFlag struct {
+	DisplayName  string   `long:"display-name"  description:"Name for this auth method on the web UI."`
+	Issuer       string   `long:"issuer"        description:"URL of the OpenID Connect provider, e.g. https://accounts.google.com."`
+	ClientID     string   `long:"client-id"     description:"Application client ID for enabling OpenID Connect."`
+	ClientSecret string   `long:"client-secret" description:"Application client secret for enabling OpenID Connect."`
+	Scopes       []string `long:"scope"         description:"Scope to request in addition to openid, email and profile. Can be specified multiple times."`
+	GroupsClaim  string   `long:"groups-claim"  description:"Claim listing the groups the user is in." default:"groups"`
+	Groups       []string `long:"group"         description:"Group whose members will have access." value-name:"GROUP"`
+	Users        []string `long:"user"          description:"Email address of a user to permit access." value-name:"EMAIL"`
+}
+
+func (auth *OIDCAuthFlag) IsConfigured() bool {
+	return auth.Issuer != "" ||
+		auth.ClientID != "" ||
+		auth.ClientSecret != "" ||
+		len(auth.Groups) > 0 ||
+		len(auth.Users) > 0
+}
+
+func (auth *OIDCAuthFlag) Validate() error {
+	var errs *multierror.Error
+	if auth.ClientID == "" || auth.ClientSecret == "" {
+		errs = multierror.Append(
+			errs,
+			errors.New("must specify --oidc-auth-client-id and --oidc-auth-client-secret to use OpenID Connect."),
+		)
+	}
+	if auth.Issuer == "" {
+		errs = multierror.Append(
+			errs,
+			errors.New("must specify --oidc-auth-issuer to use OpenID Connect."),
+		)
+	}
+	if auth.DisplayName == "" {
+		errs = multierror.Append(
+			errs,
+			errors.New("must specify --oidc-auth-display-name to use OpenID Connect."),
+		)
+	}
+	if len(auth.Groups) == 0 && len(auth.Users) == 0 {
+		errs = multierror.Append(
+			errs,
+			errors.New("at least one of the following is required for oidc-auth: groups, users."),
+		)
+	}
+	return errs.ErrorOrNil()
+}
+
+type SAMLAuthFlag struct {
+	DisplayName     string   `long:"display-name"     description:"Name for this auth method on the web UI."`
+	SSOURL          string   `long:"sso-url"          description:"URL of the identity provider's single sign-on service for the HTTP-Redirect binding."`
+	IdPEntityID     string   `long:"idp-entity-id"    description:"Entity ID of the identity provider. If set, assertions must have been issued by it."`
+	IdPCertificate  PathFlag `long:"idp-certificate"  description:"Path to the PEM-encoded certificate the identity provider signs with."`
+	GroupsAttribute string   `long:"groups-attribute" description:"Attribute listing the groups the user is in." default:"groups"`
+	Groups          []string `long:"group"            description:"Group whose members will have access." value-name:"GROUP"`
+	Users           []string `long:"user"             description:"Name ID of a user to permit access." value-name:"NAME_ID"`
+}
+
+func (auth *SAMLAuthFlag) IsConfigured() bool {
+	return auth.SSOURL != "" ||
+		auth.IdPCertificate != "" ||
+		len(auth.Groups) > 0 ||
+		len(auth.Users) > 0
+}
+
+func (auth *SAMLAuthFlag) Validate() error {
+	var errs *multierror.Error
+	if auth.SSOURL == "" {
+		errs = multierror.Append(
+			errs,
+			errors.New("must specify --saml-auth-sso-url to use SAML."),
+		)
+	}
+	if auth.IdPCertificate == "" {
+		errs = multierror.Append(
+			errs,
+			errors.New("must specify --saml-auth-idp-certificate to use SAML."),
+		)
+	}
+	if auth.DisplayName == "" {
+		errs = multierror.Append(
+			errs,
+			errors.New("must specify --saml-auth-display-name to use SAML."),
+		)
+	}
+	if len(auth.Groups) == 0 && len(auth.Users) == 0 {
+		errs = multierror.Append(
+			errs,
+			errors.New("at least one of the following is required for saml-auth: groups, users."),
+		)
+	}
+	return errs.ErrorOrNil()
+}
+
 type UAAAuthFlag struct {
 	ClientID     string   `long:"client-id"     description:"Application client ID for enabling UAA OAuth."`
 	ClientSecret string   `long:"client-secret" description:"Application client secret for enabling UAA OAuth."`
@@ -1,5 +1,10 @@
 package atc
 
+import (
+	"encoding/json"
+	"time"
+)
+
 type BuildStatus string
 
 const (
@@ -9,6 +14,7 @@ const (
 	StatusFailed    BuildStatus = "failed"
 	StatusErrored   BuildStatus = "errored"
 	StatusAborted   BuildStatus = "aborted"
+	StatusTimedOut  BuildStatus = "timed_out"
 )
 
 type Build struct {
@@ -23,6 +29,77 @@ type Build struct {
 	StartTime    int64  `json:"start_time,omitempty"`
 	EndTime      int64  `json:"end_time,omitempty"`
 	ReapTime     int64  `json:"reap_time,omitempty"`
+	LogTruncated bool   `json:"log_truncated,omitempty"`
+	Priority     int    `json:"priority,omitempty"`
+	RerunOf      int    `json:"rerun_of,omitempty"`
+
+	// QueuePosition is only shown for a single pending build: 1 means it's
+	// next to be started by its job, or by its serial groups.
+	QueuePosition int `json:"queue_position,omitempty"`
+
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Params are what the build was triggered with, if its job declares any
+	// trigger_params.
+	Params map[string]string `json:"params,omitempty"`
+
+	Usage *BuildUsage `json:"usage,omitempty"`
+
+	// Comments are only shown for a single build, when asked for with
+	// ?include=comments.
+	Comments []BuildComment `json:"comments,omitempty"`
+}
+
+// BuildUsage is what a build's task containers used. CPU time and disk add
+// up across containers; memory is the most any one container was using.
+type BuildUsage struct {
+	CPUNanoseconds uint64 `json:"cpu_nanoseconds"`
+	MemoryBytes    uint64 `json:"memory_bytes"`
+	DiskBytes      uint64 `json:"disk_bytes"`
+}
+
+// BuildEventVerification says whether a build's signed events are as they
+// were saved. BrokenAt is the first event found not to be, along with the
+// Reason why.
+type BuildEventVerification struct {
+	Valid bool `json:"valid"`
+
+	SignedEvents   int `json:"signed_events"`
+	UnsignedEvents int `json:"unsigned_events"`
+	Anchors        int `json:"anchors"`
+
+	BrokenAt *uint  `json:"broken_at,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// BuildLogMatch is a line of a build's log output that matched a search.
+type BuildLogMatch struct {
+	BuildID  int    `json:"build_id"`
+	EventID  uint   `json:"event_id"`
+	OriginID string `json:"origin_id,omitempty"`
+	Source   string `json:"source,omitempty"`
+	Payload  string `json:"payload"`
+}
+
+// BuildEvent is one of a build's events, as listed in batches rather than
+// streamed. Its ID is its offset among the build's events.
+type BuildEvent struct {
+	ID      uint             `json:"id"`
+	Event   EventType        `json:"event"`
+	Version EventVersion     `json:"version"`
+	Data    *json.RawMessage `json:"data"`
+	Time    *time.Time       `json:"time,omitempty"`
+}
+
+// BuildReconciliation lists the builds that were errored for having been
+// left running with nothing tracking them.
+type BuildReconciliation struct {
+	ErroredBuilds []int `json:"errored_builds"`
+}
+
+// BuildPriority is the body of a request to reprioritize a pending build.
+type BuildPriority struct {
+	Priority int `json:"priority"`
 }
 
 func (b Build) IsRunning() bool {
@@ -42,6 +119,63 @@ func (b Build) OneOff() bool {
 	return b.JobName == ""
 }
 
+type BuildArtifact struct {
+	Name            string `json:"name"`
+	ContainerHandle string `json:"container_handle"`
+	Path            string `json:"path"`
+}
+
+type BuildComment struct {
+	ID        int    `json:"id"`
+	Author    string `json:"author"`
+	Text      string `json:"text"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+type BuildApproval struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Approver string `json:"approver,omitempty"`
+
+	RequestedAt int64 `json:"requested_at"`
+	DecidedAt   int64 `json:"decided_at,omitempty"`
+}
+
+// BuildStep is the status and timing of one of a build's steps. ID is that
+// of the step's plan, as in the build's plan and the origins of its events.
+//
+// Duration is in seconds, and is left out until the step has finished.
+type BuildStep struct {
+	ID     PlanID `json:"id"`
+	Type   string `json:"type,omitempty"`
+	Status string `json:"status"`
+
+	StartTime int64 `json:"start_time"`
+	EndTime   int64 `json:"end_time,omitempty"`
+	Duration  int64 `json:"duration,omitempty"`
+}
+
+// ApprovalDecision is the body of a request to approve or reject an approval
+// step.
+type ApprovalDecision struct {
+	Approved bool `json:"approved"`
+}
+
+// BuildReaperStatus is when the build reaper last deleted builds' events,
+// and when it last deleted builds past their retention and how many it
+// deleted then.
+type BuildReaperStatus struct {
+	LastReapTime  int64 `json:"last_reap_time,omitempty"`
+	LastPruneTime int64 `json:"last_prune_time,omitempty"`
+	PrunedBuilds  int   `json:"pruned_builds,omitempty"`
+}
+
+// GlobalMaxInFlight limits how many builds may run at once across every
+// pipeline. A MaxInFlight of 0 means there is no limit.
+type GlobalMaxInFlight struct {
+	MaxInFlight int `json:"max_in_flight"`
+}
+
 type BuildPreparationStatus string
 
 const (
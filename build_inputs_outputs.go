@@ -24,3 +24,28 @@ type VersionedResource struct {
 	Version    Version         `json:"version"`
 	Enabled    bool            `json:"enabled"`
 }
+
+// BuildDiff compares a build's inputs with those of the last build of the
+// same job to succeed before it.
+type BuildDiff struct {
+	// PreviousBuild is nil if no build of the job succeeded before this one,
+	// in which case each of the build's inputs counts as changed.
+	PreviousBuild *Build `json:"previous_build,omitempty"`
+
+	Inputs []BuildInputDiff `json:"inputs"`
+}
+
+type BuildInputDiff struct {
+	Name    string `json:"name"`
+	Changed bool   `json:"changed"`
+
+	// Input is nil if the input was dropped since the previous build, and
+	// Previous is nil if it's new.
+	Input    *PublicBuildInput `json:"input,omitempty"`
+	Previous *PublicBuildInput `json:"previous,omitempty"`
+
+	// NewVersions are the versions found since the previous build's, up to
+	// and including this build's, newest first, e.g. each commit that's been
+	// pushed in between. There may be more of them than are listed.
+	NewVersions []VersionedResource `json:"new_versions,omitempty"`
+}
@@ -44,6 +44,7 @@ var _ = Describe("Build", func() {
 			states := []atc.BuildStatus{
 				atc.StatusAborted,
 				atc.StatusErrored,
+				atc.StatusTimedOut,
 				atc.StatusFailed,
 				atc.StatusSucceeded,
 			}
@@ -76,6 +77,7 @@ var _ = Describe("Build", func() {
 			states := []db.Status{
 				db.StatusAborted,
 				db.StatusErrored,
+				db.StatusTimedOut,
 				db.StatusFailed,
 				db.StatusSucceeded,
 			}
@@ -0,0 +1,116 @@
+package buildarchiver
+
+import (
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/event"
+)
+
+//go:generate counterfeiter . BuildArchiverDB
+
+type BuildArchiverDB interface {
+	GetUnreapedBuildsEndedBefore(endedBefore time.Time, limit int) ([]db.Build, error)
+	DeleteBuildEventsByBuildIDs(buildIDs []int) error
+}
+
+type BuildArchiver interface {
+	Run() error
+}
+
+type buildArchiver struct {
+	logger       lager.Logger
+	db           BuildArchiverDB
+	archive      EventArchive
+	batchSize    int
+	archiveAfter time.Duration
+	clock        clock.Clock
+}
+
+// NewBuildArchiver returns a BuildArchiver that moves the events of builds
+// that finished longer than archiveAfter ago out of the database and into the
+// archive, batchSize builds per run.
+func NewBuildArchiver(
+	logger lager.Logger,
+	db BuildArchiverDB,
+	archive EventArchive,
+	batchSize int,
+	archiveAfter time.Duration,
+	clock clock.Clock,
+) BuildArchiver {
+	return &buildArchiver{
+		logger:       logger,
+		db:           db,
+		archive:      archive,
+		batchSize:    batchSize,
+		archiveAfter: archiveAfter,
+		clock:        clock,
+	}
+}
+
+func (ba *buildArchiver) Run() error {
+	builds, err := ba.db.GetUnreapedBuildsEndedBefore(ba.clock.Now().Add(-ba.archiveAfter), ba.batchSize)
+	if err != nil {
+		ba.logger.Error("could-not-get-builds-to-archive", err)
+		return err
+	}
+
+	archivedBuildIDs := []int{}
+	for _, build := range builds {
+		logger := ba.logger.WithData(lager.Data{"build": build.ID()})
+
+		events, err := readAllEvents(build)
+		if err != nil {
+			logger.Error("could-not-read-build-events", err)
+			continue
+		}
+
+		// keep going so that one build that can't be archived doesn't hold
+		// back the rest
+		err = ba.archive.Save(build.ID(), events)
+		if err != nil {
+			logger.Error("could-not-archive-build-events", err)
+			continue
+		}
+
+		archivedBuildIDs = append(archivedBuildIDs, build.ID())
+	}
+
+	// only remove the events from the database once they're safely archived
+	err = ba.db.DeleteBuildEventsByBuildIDs(archivedBuildIDs)
+	if err != nil {
+		ba.logger.Error("could-not-delete-archived-build-events", err)
+		return err
+	}
+
+	if len(archivedBuildIDs) > 0 {
+		ba.logger.Info("archived-build-events", lager.Data{"builds": len(archivedBuildIDs)})
+	}
+
+	return nil
+}
+
+func readAllEvents(build db.Build) ([]event.Envelope, error) {
+	source, err := build.Events(0)
+	if err != nil {
+		return nil, err
+	}
+
+	defer source.Close()
+
+	events := []event.Envelope{}
+	for {
+		ev, err := source.Next()
+		if err != nil {
+			if err == db.ErrEndOfBuildEventStream {
+				return events, nil
+			}
+
+			return nil, err
+		}
+
+		events = append(events, ev)
+	}
+}
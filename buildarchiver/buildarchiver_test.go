@@ -0,0 +1,174 @@
+package buildarchiver_test
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/concourse/atc/buildarchiver"
+	"github.com/concourse/atc/buildarchiver/buildarchiverfakes"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/db/dbfakes"
+	"github.com/concourse/atc/event"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BuildArchiver", func() {
+	var (
+		fakeDB      *buildarchiverfakes.FakeBuildArchiverDB
+		fakeArchive *buildarchiverfakes.FakeEventArchive
+		fakeClock   *fakeclock.FakeClock
+
+		buildArchiver BuildArchiver
+
+		runErr error
+	)
+
+	BeforeEach(func() {
+		fakeDB = new(buildarchiverfakes.FakeBuildArchiverDB)
+		fakeArchive = new(buildarchiverfakes.FakeEventArchive)
+		fakeClock = fakeclock.NewFakeClock(time.Unix(123456789, 0))
+
+		buildArchiver = NewBuildArchiver(
+			lagertest.NewTestLogger("test"),
+			fakeDB,
+			fakeArchive,
+			5,
+			time.Hour,
+			fakeClock,
+		)
+	})
+
+	JustBeforeEach(func() {
+		runErr = buildArchiver.Run()
+	})
+
+	It("looks up a batch of builds that ended before the cutoff", func() {
+		Expect(fakeDB.GetUnreapedBuildsEndedBeforeCallCount()).To(Equal(1))
+		endedBefore, limit := fakeDB.GetUnreapedBuildsEndedBeforeArgsForCall(0)
+		Expect(endedBefore).To(Equal(fakeClock.Now().Add(-time.Hour)))
+		Expect(limit).To(Equal(5))
+	})
+
+	Context("when there are builds to archive", func() {
+		var (
+			build1 *dbfakes.FakeBuild
+			build2 *dbfakes.FakeBuild
+
+			someEvent event.Envelope
+		)
+
+		eventSourceFor := func(events ...event.Envelope) *dbfakes.FakeEventSource {
+			source := new(dbfakes.FakeEventSource)
+			source.NextStub = func() (event.Envelope, error) {
+				if source.NextCallCount() > len(events) {
+					return event.Envelope{}, db.ErrEndOfBuildEventStream
+				}
+
+				return events[source.NextCallCount()-1], nil
+			}
+
+			return source
+		}
+
+		BeforeEach(func() {
+			data := json.RawMessage(`{"payload":"hello"}`)
+			someEvent = event.Envelope{Data: &data, Event: "log", Version: "5.0"}
+
+			build1 = new(dbfakes.FakeBuild)
+			build1.IDReturns(1)
+			build1.EventsReturns(eventSourceFor(someEvent), nil)
+
+			build2 = new(dbfakes.FakeBuild)
+			build2.IDReturns(2)
+			build2.EventsReturns(eventSourceFor(), nil)
+
+			fakeDB.GetUnreapedBuildsEndedBeforeReturns([]db.Build{build1, build2}, nil)
+		})
+
+		It("archives every build's events", func() {
+			Expect(build1.EventsArgsForCall(0)).To(BeZero())
+
+			Expect(fakeArchive.SaveCallCount()).To(Equal(2))
+
+			buildID, events := fakeArchive.SaveArgsForCall(0)
+			Expect(buildID).To(Equal(1))
+			Expect(events).To(Equal([]event.Envelope{someEvent}))
+
+			buildID, events = fakeArchive.SaveArgsForCall(1)
+			Expect(buildID).To(Equal(2))
+			Expect(events).To(BeEmpty())
+		})
+
+		It("deletes the archived events from the database", func() {
+			Expect(runErr).NotTo(HaveOccurred())
+
+			Expect(fakeDB.DeleteBuildEventsByBuildIDsCallCount()).To(Equal(1))
+			Expect(fakeDB.DeleteBuildEventsByBuildIDsArgsForCall(0)).To(Equal([]int{1, 2}))
+		})
+
+		Context("when archiving a build fails", func() {
+			BeforeEach(func() {
+				fakeArchive.SaveStub = func(buildID int, events []event.Envelope) error {
+					if buildID == 1 {
+						return errors.New("nope")
+					}
+
+					return nil
+				}
+			})
+
+			It("leaves its events in the database, but archives the rest", func() {
+				Expect(runErr).NotTo(HaveOccurred())
+
+				Expect(fakeArchive.SaveCallCount()).To(Equal(2))
+				Expect(fakeDB.DeleteBuildEventsByBuildIDsArgsForCall(0)).To(Equal([]int{2}))
+			})
+		})
+
+		Context("when reading a build's events fails", func() {
+			BeforeEach(func() {
+				build1.EventsReturns(nil, errors.New("nope"))
+			})
+
+			It("doesn't archive it", func() {
+				Expect(fakeArchive.SaveCallCount()).To(Equal(1))
+				Expect(fakeDB.DeleteBuildEventsByBuildIDsArgsForCall(0)).To(Equal([]int{2}))
+			})
+		})
+
+		Context("when deleting the archived events fails", func() {
+			var disaster error
+
+			BeforeEach(func() {
+				disaster = errors.New("nope")
+				fakeDB.DeleteBuildEventsByBuildIDsReturns(disaster)
+			})
+
+			It("returns the error", func() {
+				Expect(runErr).To(Equal(disaster))
+			})
+		})
+	})
+
+	Context("when looking up builds fails", func() {
+		var disaster error
+
+		BeforeEach(func() {
+			disaster = errors.New("nope")
+			fakeDB.GetUnreapedBuildsEndedBeforeReturns(nil, disaster)
+		})
+
+		It("returns the error", func() {
+			Expect(runErr).To(Equal(disaster))
+		})
+
+		It("doesn't delete anything", func() {
+			Expect(fakeDB.DeleteBuildEventsByBuildIDsCallCount()).To(BeZero())
+		})
+	})
+})
@@ -0,0 +1,130 @@
+// This file was generated by counterfeiter
+package buildarchiverfakes
+
+import (
+	"sync"
+	"time"
+
+	"github.com/concourse/atc/buildarchiver"
+	"github.com/concourse/atc/db"
+)
+
+type FakeBuildArchiverDB struct {
+	GetUnreapedBuildsEndedBeforeStub        func(endedBefore time.Time, limit int) ([]db.Build, error)
+	getUnreapedBuildsEndedBeforeMutex       sync.RWMutex
+	getUnreapedBuildsEndedBeforeArgsForCall []struct {
+		endedBefore time.Time
+		limit       int
+	}
+	getUnreapedBuildsEndedBeforeReturns struct {
+		result1 []db.Build
+		result2 error
+	}
+	DeleteBuildEventsByBuildIDsStub        func(buildIDs []int) error
+	deleteBuildEventsByBuildIDsMutex       sync.RWMutex
+	deleteBuildEventsByBuildIDsArgsForCall []struct {
+		buildIDs []int
+	}
+	deleteBuildEventsByBuildIDsReturns struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeBuildArchiverDB) GetUnreapedBuildsEndedBefore(endedBefore time.Time, limit int) ([]db.Build, error) {
+	fake.getUnreapedBuildsEndedBeforeMutex.Lock()
+	fake.getUnreapedBuildsEndedBeforeArgsForCall = append(fake.getUnreapedBuildsEndedBeforeArgsForCall, struct {
+		endedBefore time.Time
+		limit       int
+	}{endedBefore, limit})
+	fake.recordInvocation("GetUnreapedBuildsEndedBefore", []interface{}{endedBefore, limit})
+	fake.getUnreapedBuildsEndedBeforeMutex.Unlock()
+	if fake.GetUnreapedBuildsEndedBeforeStub != nil {
+		return fake.GetUnreapedBuildsEndedBeforeStub(endedBefore, limit)
+	} else {
+		return fake.getUnreapedBuildsEndedBeforeReturns.result1, fake.getUnreapedBuildsEndedBeforeReturns.result2
+	}
+}
+
+func (fake *FakeBuildArchiverDB) GetUnreapedBuildsEndedBeforeCallCount() int {
+	fake.getUnreapedBuildsEndedBeforeMutex.RLock()
+	defer fake.getUnreapedBuildsEndedBeforeMutex.RUnlock()
+	return len(fake.getUnreapedBuildsEndedBeforeArgsForCall)
+}
+
+func (fake *FakeBuildArchiverDB) GetUnreapedBuildsEndedBeforeArgsForCall(i int) (time.Time, int) {
+	fake.getUnreapedBuildsEndedBeforeMutex.RLock()
+	defer fake.getUnreapedBuildsEndedBeforeMutex.RUnlock()
+	return fake.getUnreapedBuildsEndedBeforeArgsForCall[i].endedBefore, fake.getUnreapedBuildsEndedBeforeArgsForCall[i].limit
+}
+
+func (fake *FakeBuildArchiverDB) GetUnreapedBuildsEndedBeforeReturns(result1 []db.Build, result2 error) {
+	fake.GetUnreapedBuildsEndedBeforeStub = nil
+	fake.getUnreapedBuildsEndedBeforeReturns = struct {
+		result1 []db.Build
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuildArchiverDB) DeleteBuildEventsByBuildIDs(buildIDs []int) error {
+	var buildIDsCopy []int
+	if buildIDs != nil {
+		buildIDsCopy = make([]int, len(buildIDs))
+		copy(buildIDsCopy, buildIDs)
+	}
+	fake.deleteBuildEventsByBuildIDsMutex.Lock()
+	fake.deleteBuildEventsByBuildIDsArgsForCall = append(fake.deleteBuildEventsByBuildIDsArgsForCall, struct {
+		buildIDs []int
+	}{buildIDsCopy})
+	fake.recordInvocation("DeleteBuildEventsByBuildIDs", []interface{}{buildIDsCopy})
+	fake.deleteBuildEventsByBuildIDsMutex.Unlock()
+	if fake.DeleteBuildEventsByBuildIDsStub != nil {
+		return fake.DeleteBuildEventsByBuildIDsStub(buildIDs)
+	} else {
+		return fake.deleteBuildEventsByBuildIDsReturns.result1
+	}
+}
+
+func (fake *FakeBuildArchiverDB) DeleteBuildEventsByBuildIDsCallCount() int {
+	fake.deleteBuildEventsByBuildIDsMutex.RLock()
+	defer fake.deleteBuildEventsByBuildIDsMutex.RUnlock()
+	return len(fake.deleteBuildEventsByBuildIDsArgsForCall)
+}
+
+func (fake *FakeBuildArchiverDB) DeleteBuildEventsByBuildIDsArgsForCall(i int) []int {
+	fake.deleteBuildEventsByBuildIDsMutex.RLock()
+	defer fake.deleteBuildEventsByBuildIDsMutex.RUnlock()
+	return fake.deleteBuildEventsByBuildIDsArgsForCall[i].buildIDs
+}
+
+func (fake *FakeBuildArchiverDB) DeleteBuildEventsByBuildIDsReturns(result1 error) {
+	fake.DeleteBuildEventsByBuildIDsStub = nil
+	fake.deleteBuildEventsByBuildIDsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBuildArchiverDB) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.getUnreapedBuildsEndedBeforeMutex.RLock()
+	defer fake.getUnreapedBuildsEndedBeforeMutex.RUnlock()
+	fake.deleteBuildEventsByBuildIDsMutex.RLock()
+	defer fake.deleteBuildEventsByBuildIDsMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeBuildArchiverDB) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ buildarchiver.BuildArchiverDB = new(FakeBuildArchiverDB)
@@ -0,0 +1,134 @@
+// This file was generated by counterfeiter
+package buildarchiverfakes
+
+import (
+	"sync"
+
+	"github.com/concourse/atc/buildarchiver"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/event"
+)
+
+type FakeEventArchive struct {
+	SaveStub        func(buildID int, events []event.Envelope) error
+	saveMutex       sync.RWMutex
+	saveArgsForCall []struct {
+		buildID int
+		events  []event.Envelope
+	}
+	saveReturns struct {
+		result1 error
+	}
+	EventsStub        func(buildID int, from uint) (db.EventSource, bool, error)
+	eventsMutex       sync.RWMutex
+	eventsArgsForCall []struct {
+		buildID int
+		from    uint
+	}
+	eventsReturns struct {
+		result1 db.EventSource
+		result2 bool
+		result3 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeEventArchive) Save(buildID int, events []event.Envelope) error {
+	var eventsCopy []event.Envelope
+	if events != nil {
+		eventsCopy = make([]event.Envelope, len(events))
+		copy(eventsCopy, events)
+	}
+	fake.saveMutex.Lock()
+	fake.saveArgsForCall = append(fake.saveArgsForCall, struct {
+		buildID int
+		events  []event.Envelope
+	}{buildID, eventsCopy})
+	fake.recordInvocation("Save", []interface{}{buildID, eventsCopy})
+	fake.saveMutex.Unlock()
+	if fake.SaveStub != nil {
+		return fake.SaveStub(buildID, events)
+	} else {
+		return fake.saveReturns.result1
+	}
+}
+
+func (fake *FakeEventArchive) SaveCallCount() int {
+	fake.saveMutex.RLock()
+	defer fake.saveMutex.RUnlock()
+	return len(fake.saveArgsForCall)
+}
+
+func (fake *FakeEventArchive) SaveArgsForCall(i int) (int, []event.Envelope) {
+	fake.saveMutex.RLock()
+	defer fake.saveMutex.RUnlock()
+	return fake.saveArgsForCall[i].buildID, fake.saveArgsForCall[i].events
+}
+
+func (fake *FakeEventArchive) SaveReturns(result1 error) {
+	fake.SaveStub = nil
+	fake.saveReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeEventArchive) Events(buildID int, from uint) (db.EventSource, bool, error) {
+	fake.eventsMutex.Lock()
+	fake.eventsArgsForCall = append(fake.eventsArgsForCall, struct {
+		buildID int
+		from    uint
+	}{buildID, from})
+	fake.recordInvocation("Events", []interface{}{buildID, from})
+	fake.eventsMutex.Unlock()
+	if fake.EventsStub != nil {
+		return fake.EventsStub(buildID, from)
+	} else {
+		return fake.eventsReturns.result1, fake.eventsReturns.result2, fake.eventsReturns.result3
+	}
+}
+
+func (fake *FakeEventArchive) EventsCallCount() int {
+	fake.eventsMutex.RLock()
+	defer fake.eventsMutex.RUnlock()
+	return len(fake.eventsArgsForCall)
+}
+
+func (fake *FakeEventArchive) EventsArgsForCall(i int) (int, uint) {
+	fake.eventsMutex.RLock()
+	defer fake.eventsMutex.RUnlock()
+	return fake.eventsArgsForCall[i].buildID, fake.eventsArgsForCall[i].from
+}
+
+func (fake *FakeEventArchive) EventsReturns(result1 db.EventSource, result2 bool, result3 error) {
+	fake.EventsStub = nil
+	fake.eventsReturns = struct {
+		result1 db.EventSource
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeEventArchive) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.saveMutex.RLock()
+	defer fake.saveMutex.RUnlock()
+	fake.eventsMutex.RLock()
+	defer fake.eventsMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeEventArchive) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ buildarchiver.EventArchive = new(FakeEventArchive)
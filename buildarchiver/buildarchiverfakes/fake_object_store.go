@@ -0,0 +1,131 @@
+// This file was generated by counterfeiter
+package buildarchiverfakes
+
+import (
+	"io"
+	"sync"
+
+	"github.com/concourse/atc/buildarchiver"
+)
+
+type FakeObjectStore struct {
+	GetStub        func(key string) (io.ReadCloser, bool, error)
+	getMutex       sync.RWMutex
+	getArgsForCall []struct {
+		key string
+	}
+	getReturns struct {
+		result1 io.ReadCloser
+		result2 bool
+		result3 error
+	}
+	PutStub        func(key string, contents []byte) error
+	putMutex       sync.RWMutex
+	putArgsForCall []struct {
+		key      string
+		contents []byte
+	}
+	putReturns struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeObjectStore) Get(key string) (io.ReadCloser, bool, error) {
+	fake.getMutex.Lock()
+	fake.getArgsForCall = append(fake.getArgsForCall, struct {
+		key string
+	}{key})
+	fake.recordInvocation("Get", []interface{}{key})
+	fake.getMutex.Unlock()
+	if fake.GetStub != nil {
+		return fake.GetStub(key)
+	} else {
+		return fake.getReturns.result1, fake.getReturns.result2, fake.getReturns.result3
+	}
+}
+
+func (fake *FakeObjectStore) GetCallCount() int {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	return len(fake.getArgsForCall)
+}
+
+func (fake *FakeObjectStore) GetArgsForCall(i int) string {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	return fake.getArgsForCall[i].key
+}
+
+func (fake *FakeObjectStore) GetReturns(result1 io.ReadCloser, result2 bool, result3 error) {
+	fake.GetStub = nil
+	fake.getReturns = struct {
+		result1 io.ReadCloser
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeObjectStore) Put(key string, contents []byte) error {
+	var contentsCopy []byte
+	if contents != nil {
+		contentsCopy = make([]byte, len(contents))
+		copy(contentsCopy, contents)
+	}
+	fake.putMutex.Lock()
+	fake.putArgsForCall = append(fake.putArgsForCall, struct {
+		key      string
+		contents []byte
+	}{key, contentsCopy})
+	fake.recordInvocation("Put", []interface{}{key, contentsCopy})
+	fake.putMutex.Unlock()
+	if fake.PutStub != nil {
+		return fake.PutStub(key, contents)
+	} else {
+		return fake.putReturns.result1
+	}
+}
+
+func (fake *FakeObjectStore) PutCallCount() int {
+	fake.putMutex.RLock()
+	defer fake.putMutex.RUnlock()
+	return len(fake.putArgsForCall)
+}
+
+func (fake *FakeObjectStore) PutArgsForCall(i int) (string, []byte) {
+	fake.putMutex.RLock()
+	defer fake.putMutex.RUnlock()
+	return fake.putArgsForCall[i].key, fake.putArgsForCall[i].contents
+}
+
+func (fake *FakeObjectStore) PutReturns(result1 error) {
+	fake.PutStub = nil
+	fake.putReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeObjectStore) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	fake.putMutex.RLock()
+	defer fake.putMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeObjectStore) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ buildarchiver.ObjectStore = new(FakeObjectStore)
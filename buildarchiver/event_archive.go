@@ -0,0 +1,154 @@
+package buildarchiver
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/event"
+)
+
+//go:generate counterfeiter . EventArchive
+
+// EventArchive holds the events of builds that have been moved out of the
+// database.
+type EventArchive interface {
+	Save(buildID int, events []event.Envelope) error
+
+	// Events returns the build's archived events from the given index on, or
+	// false if the build has not been archived.
+	Events(buildID int, from uint) (db.EventSource, bool, error)
+}
+
+type eventArchive struct {
+	store ObjectStore
+}
+
+// NewEventArchive keeps each build's events in the store as one gzipped JSON
+// array.
+func NewEventArchive(store ObjectStore) EventArchive {
+	return eventArchive{store: store}
+}
+
+// archivedEvent is how an event.Envelope is kept in the archive. Its time is
+// in nanoseconds, as it always has been, rather than how Envelope sends it.
+type archivedEvent struct {
+	Data    *json.RawMessage `json:"data"`
+	Event   atc.EventType    `json:"event"`
+	Version atc.EventVersion `json:"version"`
+	Time    int64            `json:"time,omitempty"`
+}
+
+func (archive eventArchive) Save(buildID int, events []event.Envelope) error {
+	archived := make([]archivedEvent, len(events))
+	for i, ev := range events {
+		archived[i] = archivedEvent{
+			Data:    ev.Data,
+			Event:   ev.Event,
+			Version: ev.Version,
+		}
+
+		if !ev.Time.IsZero() {
+			archived[i].Time = ev.Time.UnixNano()
+		}
+	}
+
+	buf := new(bytes.Buffer)
+
+	gz := gzip.NewWriter(buf)
+
+	err := json.NewEncoder(gz).Encode(archived)
+	if err != nil {
+		return err
+	}
+
+	err = gz.Close()
+	if err != nil {
+		return err
+	}
+
+	return archive.store.Put(eventsKey(buildID), buf.Bytes())
+}
+
+func (archive eventArchive) Events(buildID int, from uint) (db.EventSource, bool, error) {
+	contents, found, err := archive.store.Get(eventsKey(buildID))
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !found {
+		return nil, false, nil
+	}
+
+	defer contents.Close()
+
+	gz, err := gzip.NewReader(contents)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var archived []archivedEvent
+	err = json.NewDecoder(gz).Decode(&archived)
+	if err != nil {
+		return nil, false, err
+	}
+
+	events := []event.Envelope{}
+	for i := int(from); i < len(archived); i++ {
+		ev := event.Envelope{
+			Data:    archived[i].Data,
+			Event:   archived[i].Event,
+			Version: archived[i].Version,
+		}
+
+		if archived[i].Time != 0 {
+			ev.Time = time.Unix(0, archived[i].Time)
+		}
+
+		events = append(events, ev)
+	}
+
+	return &archivedEventSource{events: events}, true, nil
+}
+
+func eventsKey(buildID int) string {
+	return fmt.Sprintf("builds/%d/events.json.gz", buildID)
+}
+
+type archivedEventSource struct {
+	events []event.Envelope
+
+	closed bool
+	lock   sync.Mutex
+}
+
+func (source *archivedEventSource) Next() (event.Envelope, error) {
+	source.lock.Lock()
+	defer source.lock.Unlock()
+
+	if source.closed {
+		return event.Envelope{}, db.ErrBuildEventStreamClosed
+	}
+
+	if len(source.events) == 0 {
+		return event.Envelope{}, db.ErrEndOfBuildEventStream
+	}
+
+	ev := source.events[0]
+	source.events = source.events[1:]
+
+	return ev, nil
+}
+
+func (source *archivedEventSource) Close() error {
+	source.lock.Lock()
+	source.closed = true
+	source.lock.Unlock()
+
+	return nil
+}
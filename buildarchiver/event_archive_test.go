@@ -0,0 +1,145 @@
+package buildarchiver_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"time"
+
+	. "github.com/concourse/atc/buildarchiver"
+	"github.com/concourse/atc/buildarchiver/buildarchiverfakes"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/event"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("EventArchive", func() {
+	var (
+		fakeStore *buildarchiverfakes.FakeObjectStore
+
+		archive EventArchive
+
+		events []event.Envelope
+	)
+
+	BeforeEach(func() {
+		fakeStore = new(buildarchiverfakes.FakeObjectStore)
+
+		archive = NewEventArchive(fakeStore)
+
+		data1 := json.RawMessage(`{"payload":"one"}`)
+		data2 := json.RawMessage(`{"payload":"two"}`)
+
+		events = []event.Envelope{
+			{Data: &data1, Event: "log", Version: "5.0", Time: time.Unix(123, 456)},
+			{Data: &data2, Event: "log", Version: "5.0"},
+		}
+	})
+
+	Describe("Save", func() {
+		It("stores the events under the build's key", func() {
+			err := archive.Save(42, events)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeStore.PutCallCount()).To(Equal(1))
+			key, _ := fakeStore.PutArgsForCall(0)
+			Expect(key).To(Equal("builds/42/events.json.gz"))
+		})
+
+		Context("when storing fails", func() {
+			var disaster error
+
+			BeforeEach(func() {
+				disaster = errors.New("nope")
+				fakeStore.PutReturns(disaster)
+			})
+
+			It("returns the error", func() {
+				Expect(archive.Save(42, events)).To(Equal(disaster))
+			})
+		})
+	})
+
+	Describe("Events", func() {
+		Context("when the build has been archived", func() {
+			BeforeEach(func() {
+				err := archive.Save(42, events)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, contents := fakeStore.PutArgsForCall(0)
+				fakeStore.GetReturns(ioutil.NopCloser(bytes.NewReader(contents)), true, nil)
+			})
+
+			It("returns the events, times and all, then the end of the stream", func() {
+				source, found, err := archive.Events(42, 0)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+
+				Expect(fakeStore.GetArgsForCall(0)).To(Equal("builds/42/events.json.gz"))
+
+				ev, err := source.Next()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ev).To(Equal(events[0]))
+
+				ev, err = source.Next()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ev).To(Equal(events[1]))
+
+				_, err = source.Next()
+				Expect(err).To(Equal(db.ErrEndOfBuildEventStream))
+			})
+
+			It("skips the events before the given index", func() {
+				source, found, err := archive.Events(42, 1)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+
+				ev, err := source.Next()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ev).To(Equal(events[1]))
+
+				_, err = source.Next()
+				Expect(err).To(Equal(db.ErrEndOfBuildEventStream))
+			})
+
+			It("stops once closed", func() {
+				source, _, err := archive.Events(42, 0)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(source.Close()).To(Succeed())
+
+				_, err = source.Next()
+				Expect(err).To(Equal(db.ErrBuildEventStreamClosed))
+			})
+		})
+
+		Context("when the build has not been archived", func() {
+			BeforeEach(func() {
+				fakeStore.GetReturns(nil, false, nil)
+			})
+
+			It("returns false", func() {
+				_, found, err := archive.Events(42, 0)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeFalse())
+			})
+		})
+
+		Context("when fetching from the store fails", func() {
+			var disaster error
+
+			BeforeEach(func() {
+				disaster = errors.New("nope")
+				fakeStore.GetReturns(nil, false, disaster)
+			})
+
+			It("returns the error", func() {
+				_, _, err := archive.Events(42, 0)
+				Expect(err).To(Equal(disaster))
+			})
+		})
+	})
+})
@@ -0,0 +1,120 @@
+package buildarchiver
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/concourse/atc/storage"
+)
+
+//go:generate counterfeiter . ObjectStore
+
+// ObjectStore is somewhere to keep archived build events, keyed by name.
+type ObjectStore interface {
+	Get(key string) (io.ReadCloser, bool, error)
+	Put(key string, contents []byte) error
+}
+
+type s3ObjectStore struct {
+	endpoint *url.URL
+	bucket   string
+	region   string
+
+	accessKeyID     string
+	secretAccessKey string
+
+	httpClient *http.Client
+}
+
+// NewS3ObjectStore talks to anything implementing the S3 API, e.g. S3 itself,
+// GCS's interoperability API (with HMAC keys and region "auto"), or Minio.
+// Buckets are addressed path-style, so that the endpoint can be an IP.
+func NewS3ObjectStore(
+	endpoint *url.URL,
+	bucket string,
+	region string,
+	accessKeyID string,
+	secretAccessKey string,
+) ObjectStore {
+	return &s3ObjectStore{
+		endpoint: endpoint,
+		bucket:   bucket,
+		region:   region,
+
+		accessKeyID:     accessKeyID,
+		secretAccessKey: secretAccessKey,
+
+		httpClient: &http.Client{
+			Timeout: 5 * time.Minute,
+		},
+	}
+}
+
+func (store *s3ObjectStore) Get(key string) (io.ReadCloser, bool, error) {
+	request, err := store.newRequest("GET", key, nil)
+	if err != nil {
+		return nil, false, err
+	}
+
+	response, err := store.httpClient.Do(request)
+	if err != nil {
+		return nil, false, err
+	}
+
+	switch response.StatusCode {
+	case http.StatusOK:
+		return response.Body, true, nil
+	case http.StatusNotFound:
+		response.Body.Close()
+		return nil, false, nil
+	default:
+		defer response.Body.Close()
+		return nil, false, unexpectedResponse(response)
+	}
+}
+
+func (store *s3ObjectStore) Put(key string, contents []byte) error {
+	request, err := store.newRequest("PUT", key, contents)
+	if err != nil {
+		return err
+	}
+
+	response, err := store.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return unexpectedResponse(response)
+	}
+
+	return nil
+}
+
+func (store *s3ObjectStore) newRequest(method string, key string, body []byte) (*http.Request, error) {
+	objectURL := *store.endpoint
+	objectURL.Path = path.Join("/", objectURL.Path, store.bucket, key)
+
+	request, err := http.NewRequest(method, objectURL.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	storage.SignV4(request, storage.SHA256Hex(body), store.region, store.accessKeyID, store.secretAccessKey, time.Now().UTC())
+
+	return request, nil
+}
+
+func unexpectedResponse(response *http.Response) error {
+	body, _ := ioutil.ReadAll(io.LimitReader(response.Body, 1024))
+	return fmt.Errorf("unexpected response from object store: %s: %s", response.Status, strings.TrimSpace(string(body)))
+}
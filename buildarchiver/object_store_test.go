@@ -0,0 +1,118 @@
+package buildarchiver_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	. "github.com/concourse/atc/buildarchiver"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("S3ObjectStore", func() {
+	var (
+		server *ghttp.Server
+
+		store ObjectStore
+	)
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+
+		endpoint, err := url.Parse(server.URL())
+		Expect(err).NotTo(HaveOccurred())
+
+		store = NewS3ObjectStore(endpoint, "some-bucket", "some-region", "some-key-id", "some-secret")
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	verifySigned := func() http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Header.Get("X-Amz-Date")).To(MatchRegexp(`^\d{8}T\d{6}Z$`))
+			Expect(r.Header.Get("X-Amz-Content-Sha256")).To(HaveLen(64))
+			Expect(r.Header.Get("Authorization")).To(MatchRegexp(
+				`^AWS4-HMAC-SHA256 Credential=some-key-id/\d{8}/some-region/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature=[0-9a-f]{64}$`,
+			))
+		}
+	}
+
+	Describe("Put", func() {
+		Context("when the store accepts the object", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(ghttp.CombineHandlers(
+					ghttp.VerifyRequest("PUT", "/some-bucket/some/key"),
+					ghttp.VerifyBody([]byte("some-contents")),
+					verifySigned(),
+					ghttp.RespondWith(http.StatusOK, ""),
+				))
+			})
+
+			It("succeeds", func() {
+				Expect(store.Put("some/key", []byte("some-contents"))).To(Succeed())
+			})
+		})
+
+		Context("when the store rejects the object", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(ghttp.RespondWith(http.StatusForbidden, "<Error>AccessDenied</Error>"))
+			})
+
+			It("returns an error with the response", func() {
+				err := store.Put("some/key", []byte("some-contents"))
+				Expect(err).To(MatchError(ContainSubstring("403")))
+				Expect(err).To(MatchError(ContainSubstring("AccessDenied")))
+			})
+		})
+	})
+
+	Describe("Get", func() {
+		Context("when the object exists", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/some-bucket/some/key"),
+					verifySigned(),
+					ghttp.RespondWith(http.StatusOK, "some-contents"),
+				))
+			})
+
+			It("returns its contents", func() {
+				contents, found, err := store.Get("some/key")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+
+				defer contents.Close()
+
+				Expect(ioutil.ReadAll(contents)).To(Equal([]byte("some-contents")))
+			})
+		})
+
+		Context("when the object does not exist", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(ghttp.RespondWith(http.StatusNotFound, ""))
+			})
+
+			It("returns false", func() {
+				_, found, err := store.Get("some/key")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeFalse())
+			})
+		})
+
+		Context("when the store fails", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(ghttp.RespondWith(http.StatusInternalServerError, ""))
+			})
+
+			It("returns an error", func() {
+				_, _, err := store.Get("some/key")
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+})
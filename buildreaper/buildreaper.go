@@ -1,6 +1,9 @@
 package buildreaper
 
 import (
+	"time"
+
+	"code.cloudfoundry.org/clock"
 	"code.cloudfoundry.org/lager"
 	"github.com/concourse/atc/db"
 )
@@ -10,8 +13,17 @@ import (
 type BuildReaperDB interface {
 	GetAllPipelines() ([]db.SavedPipeline, error)
 	DeleteBuildEventsByBuildIDs(buildIDs []int) error
+	DeleteBuildEventsBefore(endedBefore time.Time, limit int) (int, error)
+	DeleteBuildsBefore(finishedBefore time.Time, limit int) (int, error)
+	DeleteBuildsBeyond(buildsPerJob int, limit int) (int, error)
+	SaveBuildPrune(prune db.BuildPrune) error
 }
 
+// BuildReaper deletes the events of builds that are past their job's
+// build_logs_to_retain or the log retention period, keeping the builds
+// themselves. Builds that are past the build retention period, or beyond a
+// job's builds to retain, are deleted outright, except for each job's latest
+// build and latest successful build.
 type BuildReaper interface {
 	Run() error
 }
@@ -21,6 +33,10 @@ type buildReaper struct {
 	db                BuildReaperDB
 	pipelineDBFactory db.PipelineDBFactory
 	batchSize         int
+	retentionPeriod   time.Duration
+	buildRetention    time.Duration
+	buildsToRetain    int
+	clock             clock.Clock
 }
 
 func NewBuildReaper(
@@ -28,12 +44,20 @@ func NewBuildReaper(
 	db BuildReaperDB,
 	pipelineDBFactory db.PipelineDBFactory,
 	batchSize int,
+	retentionPeriod time.Duration,
+	buildRetention time.Duration,
+	buildsToRetain int,
+	clock clock.Clock,
 ) BuildReaper {
 	return &buildReaper{
 		logger:            logger,
 		db:                db,
 		pipelineDBFactory: pipelineDBFactory,
 		batchSize:         batchSize,
+		retentionPeriod:   retentionPeriod,
+		buildRetention:    buildRetention,
+		buildsToRetain:    buildsToRetain,
+		clock:             clock,
 	}
 }
 
@@ -151,5 +175,63 @@ func (br *buildReaper) Run() error {
 		}
 	}
 
+	if br.retentionPeriod != 0 {
+		// jobs without build_logs_to_retain, and one-off builds, are only
+		// reaped once they age out of the retention period
+		reaped, err := br.db.DeleteBuildEventsBefore(br.clock.Now().Add(-br.retentionPeriod), br.batchSize)
+		if err != nil {
+			br.logger.Error("could-not-delete-expired-build-events", err)
+			return err
+		}
+
+		if reaped > 0 {
+			br.logger.Info("reaped-expired-build-events", lager.Data{"builds": reaped})
+		}
+	}
+
+	return br.pruneBuilds()
+}
+
+func (br *buildReaper) pruneBuilds() error {
+	if br.buildRetention == 0 && br.buildsToRetain == 0 {
+		return nil
+	}
+
+	now := br.clock.Now()
+	pruned := 0
+
+	if br.buildRetention != 0 {
+		deleted, err := br.db.DeleteBuildsBefore(now.Add(-br.buildRetention), br.batchSize)
+		if err != nil {
+			br.logger.Error("could-not-delete-expired-builds", err)
+			return err
+		}
+
+		pruned += deleted
+	}
+
+	if br.buildsToRetain != 0 {
+		deleted, err := br.db.DeleteBuildsBeyond(br.buildsToRetain, br.batchSize)
+		if err != nil {
+			br.logger.Error("could-not-delete-builds-beyond-retention", err)
+			return err
+		}
+
+		pruned += deleted
+	}
+
+	if pruned > 0 {
+		br.logger.Info("pruned-builds", lager.Data{"builds": pruned})
+	}
+
+	err := br.db.SaveBuildPrune(db.BuildPrune{
+		PrunedAt: now,
+		Builds:   pruned,
+	})
+	if err != nil {
+		br.logger.Error("could-not-save-build-prune", err)
+		return err
+	}
+
 	return nil
 }
@@ -3,7 +3,9 @@ package buildreaper_test
 import (
 	"errors"
 	"fmt"
+	"time"
 
+	"code.cloudfoundry.org/clock/fakeclock"
 	"code.cloudfoundry.org/lager/lagertest"
 	"github.com/concourse/atc"
 	. "github.com/concourse/atc/buildreaper"
@@ -21,12 +23,20 @@ var _ = Describe("BuildReaper", func() {
 		fakeBuildReaperDB     *buildreaperfakes.FakeBuildReaperDB
 		fakePipelineDBFactory *dbfakes.FakePipelineDBFactory
 		batchSize             int
+		retentionPeriod       time.Duration
+		buildRetention        time.Duration
+		buildsToRetain        int
+		fakeClock             *fakeclock.FakeClock
 	)
 
 	BeforeEach(func() {
 		fakeBuildReaperDB = new(buildreaperfakes.FakeBuildReaperDB)
 		fakePipelineDBFactory = new(dbfakes.FakePipelineDBFactory)
 		batchSize = 5
+		retentionPeriod = 0
+		buildRetention = 0
+		buildsToRetain = 0
+		fakeClock = fakeclock.NewFakeClock(time.Unix(123456789, 0))
 	})
 
 	JustBeforeEach(func() {
@@ -36,6 +46,10 @@ var _ = Describe("BuildReaper", func() {
 			fakeBuildReaperDB,
 			fakePipelineDBFactory,
 			batchSize,
+			retentionPeriod,
+			buildRetention,
+			buildsToRetain,
+			fakeClock,
 		)
 	})
 
@@ -533,6 +547,181 @@ var _ = Describe("BuildReaper", func() {
 		})
 	})
 
+	Context("when no retention period is configured", func() {
+		It("does not reap builds by age", func() {
+			err := buildReaper.Run()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeBuildReaperDB.DeleteBuildEventsBeforeCallCount()).To(BeZero())
+		})
+	})
+
+	Context("when a retention period is configured", func() {
+		BeforeEach(func() {
+			retentionPeriod = 24 * time.Hour
+		})
+
+		It("reaps a batch of builds that ended before the retention period", func() {
+			err := buildReaper.Run()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeBuildReaperDB.DeleteBuildEventsBeforeCallCount()).To(Equal(1))
+			endedBefore, limit := fakeBuildReaperDB.DeleteBuildEventsBeforeArgsForCall(0)
+			Expect(endedBefore).To(Equal(time.Unix(123456789, 0).Add(-24 * time.Hour)))
+			Expect(limit).To(Equal(batchSize))
+		})
+
+		Context("when reaping fails", func() {
+			var disaster error
+
+			BeforeEach(func() {
+				disaster = errors.New("nope")
+				fakeBuildReaperDB.DeleteBuildEventsBeforeReturns(0, disaster)
+			})
+
+			It("returns the error", func() {
+				err := buildReaper.Run()
+				Expect(err).To(Equal(disaster))
+			})
+		})
+	})
+
+	Context("when no build retention is configured", func() {
+		It("does not delete any builds", func() {
+			err := buildReaper.Run()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeBuildReaperDB.DeleteBuildsBeforeCallCount()).To(BeZero())
+			Expect(fakeBuildReaperDB.DeleteBuildsBeyondCallCount()).To(BeZero())
+			Expect(fakeBuildReaperDB.SaveBuildPruneCallCount()).To(BeZero())
+		})
+	})
+
+	Context("when a build retention period is configured", func() {
+		BeforeEach(func() {
+			buildRetention = 30 * 24 * time.Hour
+			fakeBuildReaperDB.DeleteBuildsBeforeReturns(3, nil)
+		})
+
+		It("deletes a batch of builds that finished before the retention period", func() {
+			err := buildReaper.Run()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeBuildReaperDB.DeleteBuildsBeforeCallCount()).To(Equal(1))
+			finishedBefore, limit := fakeBuildReaperDB.DeleteBuildsBeforeArgsForCall(0)
+			Expect(finishedBefore).To(Equal(time.Unix(123456789, 0).Add(-30 * 24 * time.Hour)))
+			Expect(limit).To(Equal(batchSize))
+
+			Expect(fakeBuildReaperDB.DeleteBuildsBeyondCallCount()).To(BeZero())
+		})
+
+		It("saves when it pruned and how many builds it deleted", func() {
+			err := buildReaper.Run()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeBuildReaperDB.SaveBuildPruneCallCount()).To(Equal(1))
+			Expect(fakeBuildReaperDB.SaveBuildPruneArgsForCall(0)).To(Equal(db.BuildPrune{
+				PrunedAt: time.Unix(123456789, 0),
+				Builds:   3,
+			}))
+		})
+
+		Context("when nothing is old enough to delete", func() {
+			BeforeEach(func() {
+				fakeBuildReaperDB.DeleteBuildsBeforeReturns(0, nil)
+			})
+
+			It("still saves that it pruned", func() {
+				err := buildReaper.Run()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeBuildReaperDB.SaveBuildPruneCallCount()).To(Equal(1))
+				Expect(fakeBuildReaperDB.SaveBuildPruneArgsForCall(0).Builds).To(BeZero())
+			})
+		})
+
+		Context("when deleting fails", func() {
+			var disaster error
+
+			BeforeEach(func() {
+				disaster = errors.New("nope")
+				fakeBuildReaperDB.DeleteBuildsBeforeReturns(0, disaster)
+			})
+
+			It("returns the error without saving a prune", func() {
+				err := buildReaper.Run()
+				Expect(err).To(Equal(disaster))
+
+				Expect(fakeBuildReaperDB.SaveBuildPruneCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when saving the prune fails", func() {
+			var disaster error
+
+			BeforeEach(func() {
+				disaster = errors.New("nope")
+				fakeBuildReaperDB.SaveBuildPruneReturns(disaster)
+			})
+
+			It("returns the error", func() {
+				err := buildReaper.Run()
+				Expect(err).To(Equal(disaster))
+			})
+		})
+	})
+
+	Context("when a number of builds to retain is configured", func() {
+		BeforeEach(func() {
+			buildsToRetain = 100
+			fakeBuildReaperDB.DeleteBuildsBeyondReturns(2, nil)
+		})
+
+		It("deletes a batch of builds beyond each job's latest builds", func() {
+			err := buildReaper.Run()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeBuildReaperDB.DeleteBuildsBeyondCallCount()).To(Equal(1))
+			buildsPerJob, limit := fakeBuildReaperDB.DeleteBuildsBeyondArgsForCall(0)
+			Expect(buildsPerJob).To(Equal(100))
+			Expect(limit).To(Equal(batchSize))
+
+			Expect(fakeBuildReaperDB.DeleteBuildsBeforeCallCount()).To(BeZero())
+		})
+
+		Context("when a build retention period is configured too", func() {
+			BeforeEach(func() {
+				buildRetention = 24 * time.Hour
+				fakeBuildReaperDB.DeleteBuildsBeforeReturns(3, nil)
+			})
+
+			It("saves how many builds it deleted for either", func() {
+				err := buildReaper.Run()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeBuildReaperDB.DeleteBuildsBeforeCallCount()).To(Equal(1))
+				Expect(fakeBuildReaperDB.DeleteBuildsBeyondCallCount()).To(Equal(1))
+
+				Expect(fakeBuildReaperDB.SaveBuildPruneCallCount()).To(Equal(1))
+				Expect(fakeBuildReaperDB.SaveBuildPruneArgsForCall(0).Builds).To(Equal(5))
+			})
+		})
+
+		Context("when deleting fails", func() {
+			var disaster error
+
+			BeforeEach(func() {
+				disaster = errors.New("nope")
+				fakeBuildReaperDB.DeleteBuildsBeyondReturns(0, disaster)
+			})
+
+			It("returns the error", func() {
+				err := buildReaper.Run()
+				Expect(err).To(Equal(disaster))
+			})
+		})
+	})
+
 	Context("when getting the pipelines fails", func() {
 		var disaster error
 
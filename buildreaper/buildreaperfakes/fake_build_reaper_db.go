@@ -3,6 +3,7 @@ package buildreaperfakes
 
 import (
 	"sync"
+	"time"
 
 	"github.com/concourse/atc/buildreaper"
 	"github.com/concourse/atc/db"
@@ -24,6 +25,44 @@ type FakeBuildReaperDB struct {
 	deleteBuildEventsByBuildIDsReturns struct {
 		result1 error
 	}
+	DeleteBuildEventsBeforeStub        func(endedBefore time.Time, limit int) (int, error)
+	deleteBuildEventsBeforeMutex       sync.RWMutex
+	deleteBuildEventsBeforeArgsForCall []struct {
+		endedBefore time.Time
+		limit       int
+	}
+	deleteBuildEventsBeforeReturns struct {
+		result1 int
+		result2 error
+	}
+	DeleteBuildsBeforeStub        func(finishedBefore time.Time, limit int) (int, error)
+	deleteBuildsBeforeMutex       sync.RWMutex
+	deleteBuildsBeforeArgsForCall []struct {
+		finishedBefore time.Time
+		limit          int
+	}
+	deleteBuildsBeforeReturns struct {
+		result1 int
+		result2 error
+	}
+	DeleteBuildsBeyondStub        func(buildsPerJob int, limit int) (int, error)
+	deleteBuildsBeyondMutex       sync.RWMutex
+	deleteBuildsBeyondArgsForCall []struct {
+		buildsPerJob int
+		limit        int
+	}
+	deleteBuildsBeyondReturns struct {
+		result1 int
+		result2 error
+	}
+	SaveBuildPruneStub        func(prune db.BuildPrune) error
+	saveBuildPruneMutex       sync.RWMutex
+	saveBuildPruneArgsForCall []struct {
+		prune db.BuildPrune
+	}
+	saveBuildPruneReturns struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -92,6 +131,144 @@ func (fake *FakeBuildReaperDB) DeleteBuildEventsByBuildIDsReturns(result1 error)
 	}{result1}
 }
 
+func (fake *FakeBuildReaperDB) DeleteBuildEventsBefore(endedBefore time.Time, limit int) (int, error) {
+	fake.deleteBuildEventsBeforeMutex.Lock()
+	fake.deleteBuildEventsBeforeArgsForCall = append(fake.deleteBuildEventsBeforeArgsForCall, struct {
+		endedBefore time.Time
+		limit       int
+	}{endedBefore, limit})
+	fake.recordInvocation("DeleteBuildEventsBefore", []interface{}{endedBefore, limit})
+	fake.deleteBuildEventsBeforeMutex.Unlock()
+	if fake.DeleteBuildEventsBeforeStub != nil {
+		return fake.DeleteBuildEventsBeforeStub(endedBefore, limit)
+	} else {
+		return fake.deleteBuildEventsBeforeReturns.result1, fake.deleteBuildEventsBeforeReturns.result2
+	}
+}
+
+func (fake *FakeBuildReaperDB) DeleteBuildEventsBeforeCallCount() int {
+	fake.deleteBuildEventsBeforeMutex.RLock()
+	defer fake.deleteBuildEventsBeforeMutex.RUnlock()
+	return len(fake.deleteBuildEventsBeforeArgsForCall)
+}
+
+func (fake *FakeBuildReaperDB) DeleteBuildEventsBeforeArgsForCall(i int) (time.Time, int) {
+	fake.deleteBuildEventsBeforeMutex.RLock()
+	defer fake.deleteBuildEventsBeforeMutex.RUnlock()
+	return fake.deleteBuildEventsBeforeArgsForCall[i].endedBefore, fake.deleteBuildEventsBeforeArgsForCall[i].limit
+}
+
+func (fake *FakeBuildReaperDB) DeleteBuildEventsBeforeReturns(result1 int, result2 error) {
+	fake.DeleteBuildEventsBeforeStub = nil
+	fake.deleteBuildEventsBeforeReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuildReaperDB) DeleteBuildsBefore(finishedBefore time.Time, limit int) (int, error) {
+	fake.deleteBuildsBeforeMutex.Lock()
+	fake.deleteBuildsBeforeArgsForCall = append(fake.deleteBuildsBeforeArgsForCall, struct {
+		finishedBefore time.Time
+		limit          int
+	}{finishedBefore, limit})
+	fake.recordInvocation("DeleteBuildsBefore", []interface{}{finishedBefore, limit})
+	fake.deleteBuildsBeforeMutex.Unlock()
+	if fake.DeleteBuildsBeforeStub != nil {
+		return fake.DeleteBuildsBeforeStub(finishedBefore, limit)
+	} else {
+		return fake.deleteBuildsBeforeReturns.result1, fake.deleteBuildsBeforeReturns.result2
+	}
+}
+
+func (fake *FakeBuildReaperDB) DeleteBuildsBeforeCallCount() int {
+	fake.deleteBuildsBeforeMutex.RLock()
+	defer fake.deleteBuildsBeforeMutex.RUnlock()
+	return len(fake.deleteBuildsBeforeArgsForCall)
+}
+
+func (fake *FakeBuildReaperDB) DeleteBuildsBeforeArgsForCall(i int) (time.Time, int) {
+	fake.deleteBuildsBeforeMutex.RLock()
+	defer fake.deleteBuildsBeforeMutex.RUnlock()
+	return fake.deleteBuildsBeforeArgsForCall[i].finishedBefore, fake.deleteBuildsBeforeArgsForCall[i].limit
+}
+
+func (fake *FakeBuildReaperDB) DeleteBuildsBeforeReturns(result1 int, result2 error) {
+	fake.DeleteBuildsBeforeStub = nil
+	fake.deleteBuildsBeforeReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuildReaperDB) DeleteBuildsBeyond(buildsPerJob int, limit int) (int, error) {
+	fake.deleteBuildsBeyondMutex.Lock()
+	fake.deleteBuildsBeyondArgsForCall = append(fake.deleteBuildsBeyondArgsForCall, struct {
+		buildsPerJob int
+		limit        int
+	}{buildsPerJob, limit})
+	fake.recordInvocation("DeleteBuildsBeyond", []interface{}{buildsPerJob, limit})
+	fake.deleteBuildsBeyondMutex.Unlock()
+	if fake.DeleteBuildsBeyondStub != nil {
+		return fake.DeleteBuildsBeyondStub(buildsPerJob, limit)
+	} else {
+		return fake.deleteBuildsBeyondReturns.result1, fake.deleteBuildsBeyondReturns.result2
+	}
+}
+
+func (fake *FakeBuildReaperDB) DeleteBuildsBeyondCallCount() int {
+	fake.deleteBuildsBeyondMutex.RLock()
+	defer fake.deleteBuildsBeyondMutex.RUnlock()
+	return len(fake.deleteBuildsBeyondArgsForCall)
+}
+
+func (fake *FakeBuildReaperDB) DeleteBuildsBeyondArgsForCall(i int) (int, int) {
+	fake.deleteBuildsBeyondMutex.RLock()
+	defer fake.deleteBuildsBeyondMutex.RUnlock()
+	return fake.deleteBuildsBeyondArgsForCall[i].buildsPerJob, fake.deleteBuildsBeyondArgsForCall[i].limit
+}
+
+func (fake *FakeBuildReaperDB) DeleteBuildsBeyondReturns(result1 int, result2 error) {
+	fake.DeleteBuildsBeyondStub = nil
+	fake.deleteBuildsBeyondReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuildReaperDB) SaveBuildPrune(prune db.BuildPrune) error {
+	fake.saveBuildPruneMutex.Lock()
+	fake.saveBuildPruneArgsForCall = append(fake.saveBuildPruneArgsForCall, struct {
+		prune db.BuildPrune
+	}{prune})
+	fake.recordInvocation("SaveBuildPrune", []interface{}{prune})
+	fake.saveBuildPruneMutex.Unlock()
+	if fake.SaveBuildPruneStub != nil {
+		return fake.SaveBuildPruneStub(prune)
+	} else {
+		return fake.saveBuildPruneReturns.result1
+	}
+}
+
+func (fake *FakeBuildReaperDB) SaveBuildPruneCallCount() int {
+	fake.saveBuildPruneMutex.RLock()
+	defer fake.saveBuildPruneMutex.RUnlock()
+	return len(fake.saveBuildPruneArgsForCall)
+}
+
+func (fake *FakeBuildReaperDB) SaveBuildPruneArgsForCall(i int) db.BuildPrune {
+	fake.saveBuildPruneMutex.RLock()
+	defer fake.saveBuildPruneMutex.RUnlock()
+	return fake.saveBuildPruneArgsForCall[i].prune
+}
+
+func (fake *FakeBuildReaperDB) SaveBuildPruneReturns(result1 error) {
+	fake.SaveBuildPruneStub = nil
+	fake.saveBuildPruneReturns = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeBuildReaperDB) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -99,6 +276,14 @@ func (fake *FakeBuildReaperDB) Invocations() map[string][][]interface{} {
 	defer fake.getAllPipelinesMutex.RUnlock()
 	fake.deleteBuildEventsByBuildIDsMutex.RLock()
 	defer fake.deleteBuildEventsByBuildIDsMutex.RUnlock()
+	fake.deleteBuildEventsBeforeMutex.RLock()
+	defer fake.deleteBuildEventsBeforeMutex.RUnlock()
+	fake.deleteBuildsBeforeMutex.RLock()
+	defer fake.deleteBuildsBeforeMutex.RUnlock()
+	fake.deleteBuildsBeyondMutex.RLock()
+	defer fake.deleteBuildsBeyondMutex.RUnlock()
+	fake.saveBuildPruneMutex.RLock()
+	defer fake.saveBuildPruneMutex.RUnlock()
 	return fake.invocations
 }
 
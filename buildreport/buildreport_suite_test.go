@@ -0,0 +1,13 @@
+package buildreport_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestBuildReport(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Build Report Suite")
+}
@@ -0,0 +1,173 @@
+package buildreport
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+// JUnit writes the build as a JUnit XML test suite with a test case for each
+// step. Failed steps are failures, errored and aborted steps are errors, and
+// steps that haven't finished are skipped.
+type JUnit struct{}
+
+type junitTestSuites struct {
+	XMLName xml.Name `xml:"testsuites"`
+
+	Name     string `xml:"name,attr"`
+	Tests    int    `xml:"tests,attr"`
+	Failures int    `xml:"failures,attr"`
+	Errors   int    `xml:"errors,attr"`
+	Skipped  int    `xml:"skipped,attr"`
+	Time     string `xml:"time,attr"`
+
+	Suites []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	ID        int    `xml:"id,attr"`
+	Name      string `xml:"name,attr"`
+	Tests     int    `xml:"tests,attr"`
+	Failures  int    `xml:"failures,attr"`
+	Errors    int    `xml:"errors,attr"`
+	Skipped   int    `xml:"skipped,attr"`
+	Time      string `xml:"time,attr"`
+	Timestamp string `xml:"timestamp,attr,omitempty"`
+
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	Cases      []junitTestCase `xml:"testcase"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitTestCase struct {
+	Name      string `xml:"name,attr"`
+	ClassName string `xml:"classname,attr"`
+	Time      string `xml:"time,attr"`
+
+	Failure *junitResult `xml:"failure"`
+	Error   *junitResult `xml:"error"`
+	Skipped *junitResult `xml:"skipped"`
+}
+
+type junitResult struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+}
+
+func (JUnit) ContentType() string {
+	return "application/xml; charset=utf-8"
+}
+
+func (JUnit) Write(w io.Writer, build Build) error {
+	className := "one-off"
+	if build.JobName != "" {
+		className = build.PipelineName + "." + build.JobName
+	}
+
+	suite := junitTestSuite{
+		ID:    build.ID,
+		Name:  suiteName(build),
+		Time:  seconds(build.StartTime, build.EndTime),
+		Cases: []junitTestCase{},
+		Properties: []junitProperty{
+			{Name: "team", Value: build.TeamName},
+			{Name: "status", Value: build.Status},
+		},
+	}
+
+	if !build.StartTime.IsZero() {
+		suite.Timestamp = build.StartTime.UTC().Format("2006-01-02T15:04:05")
+	}
+
+	for _, step := range build.Steps {
+		testCase := junitTestCase{
+			Name:      caseName(step),
+			ClassName: className,
+			Time:      seconds(step.StartTime, step.EndTime),
+		}
+
+		// steps that errored before saying what they were have no type
+		kind := step.Type
+		if kind == "" {
+			kind = "step"
+		}
+
+		switch step.Status {
+		case "succeeded":
+		case "failed":
+			testCase.Failure = &junitResult{Message: kind + " failed", Type: step.Type}
+			suite.Failures++
+		case "errored", "aborted":
+			testCase.Error = &junitResult{Message: kind + " " + step.Status, Type: step.Type}
+			suite.Errors++
+		default:
+			testCase.Skipped = &junitResult{Message: "not finished"}
+			suite.Skipped++
+		}
+
+		suite.Cases = append(suite.Cases, testCase)
+		suite.Tests++
+	}
+
+	suites := junitTestSuites{
+		Name:     suite.Name,
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Errors:   suite.Errors,
+		Skipped:  suite.Skipped,
+		Time:     suite.Time,
+		Suites:   []junitTestSuite{suite},
+	}
+
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+
+	err = encoder.Encode(suites)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+func suiteName(build Build) string {
+	if build.JobName == "" {
+		return fmt.Sprintf("build #%s", build.Name)
+	}
+
+	return fmt.Sprintf("%s/%s #%s", build.PipelineName, build.JobName, build.Name)
+}
+
+func caseName(step Step) string {
+	name := step.Name
+	if name == "" {
+		name = step.ID
+	}
+
+	if step.Type == "" {
+		return name
+	}
+
+	return step.Type + " " + name
+}
+
+// seconds is how long it took from start to end, or nothing if it hasn't
+// started or finished, in the form JUnit wants.
+func seconds(start time.Time, end time.Time) string {
+	if start.IsZero() || end.IsZero() {
+		return "0"
+	}
+
+	return fmt.Sprintf("%.3f", end.Sub(start).Seconds())
+}
@@ -0,0 +1,153 @@
+package buildreport_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"time"
+
+	"github.com/concourse/atc/buildreport"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type junitReport struct {
+	Name     string `xml:"name,attr"`
+	Tests    int    `xml:"tests,attr"`
+	Failures int    `xml:"failures,attr"`
+	Errors   int    `xml:"errors,attr"`
+	Skipped  int    `xml:"skipped,attr"`
+	Time     string `xml:"time,attr"`
+
+	Suites []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	ID        int    `xml:"id,attr"`
+	Name      string `xml:"name,attr"`
+	Tests     int    `xml:"tests,attr"`
+	Timestamp string `xml:"timestamp,attr"`
+
+	Properties []junitProperty `xml:"properties>property"`
+	Cases      []junitTestCase `xml:"testcase"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitTestCase struct {
+	Name      string       `xml:"name,attr"`
+	ClassName string       `xml:"classname,attr"`
+	Time      string       `xml:"time,attr"`
+	Failure   *junitResult `xml:"failure"`
+	Error     *junitResult `xml:"error"`
+	Skipped   *junitResult `xml:"skipped"`
+}
+
+type junitResult struct {
+	Message string `xml:"message,attr"`
+}
+
+var _ = Describe("JUnit", func() {
+	var build buildreport.Build
+	var report junitReport
+
+	BeforeEach(func() {
+		started := time.Date(2017, 7, 14, 2, 40, 0, 0, time.UTC)
+
+		build = buildreport.Build{
+			ID:           12,
+			Name:         "3",
+			TeamName:     "some-team",
+			PipelineName: "some-pipeline",
+			JobName:      "some-job",
+			Status:       "failed",
+			StartTime:    started,
+			EndTime:      started.Add(90 * time.Second),
+			Steps: []buildreport.Step{
+				{ID: "1", Type: "get", Name: "some-input", Status: "succeeded", StartTime: started, EndTime: started.Add(1500 * time.Millisecond)},
+				{ID: "2", Type: "task", Name: "unit", Status: "failed", StartTime: started, EndTime: started.Add(time.Minute)},
+				{ID: "3", Type: "put", Name: "some-output", Status: "aborted", StartTime: started, EndTime: started.Add(time.Second)},
+				{ID: "4", Status: "errored", StartTime: started, EndTime: started},
+				{ID: "5", Type: "task", Name: "cleanup", Status: "started", StartTime: started},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		buf := new(bytes.Buffer)
+		err := buildreport.JUnit{}.Write(buf, build)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(buf.String()).To(HavePrefix(xml.Header))
+
+		report = junitReport{}
+		err = xml.Unmarshal(buf.Bytes(), &report)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("is registered as a format", func() {
+		Expect(buildreport.Formats[buildreport.FormatJUnit]).To(Equal(buildreport.JUnit{}))
+		Expect(buildreport.JUnit{}.ContentType()).To(Equal("application/xml; charset=utf-8"))
+	})
+
+	It("writes one suite for the build, counting how its steps went", func() {
+		Expect(report.Name).To(Equal("some-pipeline/some-job #3"))
+		Expect(report.Tests).To(Equal(5))
+		Expect(report.Failures).To(Equal(1))
+		Expect(report.Errors).To(Equal(2))
+		Expect(report.Skipped).To(Equal(1))
+		Expect(report.Time).To(Equal("90.000"))
+
+		Expect(report.Suites).To(HaveLen(1))
+
+		suite := report.Suites[0]
+		Expect(suite.ID).To(Equal(12))
+		Expect(suite.Name).To(Equal("some-pipeline/some-job #3"))
+		Expect(suite.Tests).To(Equal(5))
+		Expect(suite.Timestamp).To(Equal("2017-07-14T02:40:00"))
+		Expect(suite.Properties).To(ConsistOf(
+			junitProperty{Name: "team", Value: "some-team"},
+			junitProperty{Name: "status", Value: "failed"},
+		))
+	})
+
+	It("writes a test case for each step, in order", func() {
+		Expect(report.Suites[0].Cases).To(Equal([]junitTestCase{
+			{Name: "get some-input", ClassName: "some-pipeline.some-job", Time: "1.500"},
+			{Name: "task unit", ClassName: "some-pipeline.some-job", Time: "60.000", Failure: &junitResult{Message: "task failed"}},
+			{Name: "put some-output", ClassName: "some-pipeline.some-job", Time: "1.000", Error: &junitResult{Message: "put aborted"}},
+			{Name: "4", ClassName: "some-pipeline.some-job", Time: "0.000", Error: &junitResult{Message: "step errored"}},
+			{Name: "task cleanup", ClassName: "some-pipeline.some-job", Time: "0", Skipped: &junitResult{Message: "not finished"}},
+		}))
+	})
+
+	Context("when the build is a one-off", func() {
+		BeforeEach(func() {
+			build.PipelineName = ""
+			build.JobName = ""
+		})
+
+		It("names the suite after the build alone", func() {
+			Expect(report.Name).To(Equal("build #3"))
+			Expect(report.Suites[0].Cases[0].ClassName).To(Equal("one-off"))
+		})
+	})
+
+	Context("when the build has no steps", func() {
+		BeforeEach(func() {
+			build.Steps = nil
+			build.StartTime = time.Time{}
+			build.EndTime = time.Time{}
+		})
+
+		It("writes an empty suite", func() {
+			Expect(report.Tests).To(BeZero())
+			Expect(report.Time).To(Equal("0"))
+			Expect(report.Suites[0].Timestamp).To(BeEmpty())
+			Expect(report.Suites[0].Cases).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,51 @@
+package buildreport
+
+import (
+	"io"
+	"time"
+)
+
+// Build is what's reported about a build: how each of its steps went and how
+// long it took.
+type Build struct {
+	ID           int
+	Name         string
+	TeamName     string
+	PipelineName string
+	JobName      string
+	Status       string
+
+	StartTime time.Time
+	EndTime   time.Time
+
+	Steps []Step
+}
+
+// Step is one step of a build. Name is empty if the step's plan has no name
+// for it, and EndTime is zero if it hasn't finished.
+type Step struct {
+	ID     string
+	Type   string
+	Name   string
+	Status string
+
+	StartTime time.Time
+	EndTime   time.Time
+}
+
+// A Format writes reports for some other tool to read.
+type Format interface {
+	ContentType() string
+	Write(w io.Writer, build Build) error
+}
+
+const FormatJUnit = "junit"
+
+// DefaultFormat is used when no format is asked for.
+const DefaultFormat = FormatJUnit
+
+// Formats are the formats reports can be written in, by name. New formats
+// only need to be added here to be served.
+var Formats = map[string]Format{
+	FormatJUnit: JUnit{},
+}
@@ -0,0 +1,69 @@
+// This file was generated by counterfeiter
+package buildsfakes
+
+import (
+	"sync"
+
+	"github.com/concourse/atc/builds"
+	"github.com/concourse/atc/db"
+)
+
+type FakeDependencyDB struct {
+	GetBuildsAwaitingDependenciesStub        func() ([]db.Build, error)
+	getBuildsAwaitingDependenciesMutex       sync.RWMutex
+	getBuildsAwaitingDependenciesArgsForCall []struct{}
+	getBuildsAwaitingDependenciesReturns     struct {
+		result1 []db.Build
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeDependencyDB) GetBuildsAwaitingDependencies() ([]db.Build, error) {
+	fake.getBuildsAwaitingDependenciesMutex.Lock()
+	fake.getBuildsAwaitingDependenciesArgsForCall = append(fake.getBuildsAwaitingDependenciesArgsForCall, struct{}{})
+	fake.recordInvocation("GetBuildsAwaitingDependencies", []interface{}{})
+	fake.getBuildsAwaitingDependenciesMutex.Unlock()
+	if fake.GetBuildsAwaitingDependenciesStub != nil {
+		return fake.GetBuildsAwaitingDependenciesStub()
+	} else {
+		return fake.getBuildsAwaitingDependenciesReturns.result1, fake.getBuildsAwaitingDependenciesReturns.result2
+	}
+}
+
+func (fake *FakeDependencyDB) GetBuildsAwaitingDependenciesCallCount() int {
+	fake.getBuildsAwaitingDependenciesMutex.RLock()
+	defer fake.getBuildsAwaitingDependenciesMutex.RUnlock()
+	return len(fake.getBuildsAwaitingDependenciesArgsForCall)
+}
+
+func (fake *FakeDependencyDB) GetBuildsAwaitingDependenciesReturns(result1 []db.Build, result2 error) {
+	fake.GetBuildsAwaitingDependenciesStub = nil
+	fake.getBuildsAwaitingDependenciesReturns = struct {
+		result1 []db.Build
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDependencyDB) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.getBuildsAwaitingDependenciesMutex.RLock()
+	defer fake.getBuildsAwaitingDependenciesMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeDependencyDB) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ builds.DependencyDB = new(FakeDependencyDB)
@@ -0,0 +1,123 @@
+// This file was generated by counterfeiter
+package buildsfakes
+
+import (
+	"sync"
+	"time"
+
+	"github.com/concourse/atc/builds"
+	"github.com/concourse/atc/db"
+)
+
+type FakeDurationWatcherDB struct {
+	GetAllStartedBuildsStub        func() ([]db.Build, error)
+	getAllStartedBuildsMutex       sync.RWMutex
+	getAllStartedBuildsArgsForCall []struct{}
+	getAllStartedBuildsReturns     struct {
+		result1 []db.Build
+		result2 error
+	}
+	GetJobDurationPercentileStub        func(pipelineID int, jobName string, percentile int, window time.Duration) (time.Duration, int, error)
+	getJobDurationPercentileMutex       sync.RWMutex
+	getJobDurationPercentileArgsForCall []struct {
+		pipelineID int
+		jobName    string
+		percentile int
+		window     time.Duration
+	}
+	getJobDurationPercentileReturns struct {
+		result1 time.Duration
+		result2 int
+		result3 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeDurationWatcherDB) GetAllStartedBuilds() ([]db.Build, error) {
+	fake.getAllStartedBuildsMutex.Lock()
+	fake.getAllStartedBuildsArgsForCall = append(fake.getAllStartedBuildsArgsForCall, struct{}{})
+	fake.recordInvocation("GetAllStartedBuilds", []interface{}{})
+	fake.getAllStartedBuildsMutex.Unlock()
+	if fake.GetAllStartedBuildsStub != nil {
+		return fake.GetAllStartedBuildsStub()
+	} else {
+		return fake.getAllStartedBuildsReturns.result1, fake.getAllStartedBuildsReturns.result2
+	}
+}
+
+func (fake *FakeDurationWatcherDB) GetAllStartedBuildsCallCount() int {
+	fake.getAllStartedBuildsMutex.RLock()
+	defer fake.getAllStartedBuildsMutex.RUnlock()
+	return len(fake.getAllStartedBuildsArgsForCall)
+}
+
+func (fake *FakeDurationWatcherDB) GetAllStartedBuildsReturns(result1 []db.Build, result2 error) {
+	fake.GetAllStartedBuildsStub = nil
+	fake.getAllStartedBuildsReturns = struct {
+		result1 []db.Build
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeDurationWatcherDB) GetJobDurationPercentile(pipelineID int, jobName string, percentile int, window time.Duration) (time.Duration, int, error) {
+	fake.getJobDurationPercentileMutex.Lock()
+	fake.getJobDurationPercentileArgsForCall = append(fake.getJobDurationPercentileArgsForCall, struct {
+		pipelineID int
+		jobName    string
+		percentile int
+		window     time.Duration
+	}{pipelineID, jobName, percentile, window})
+	fake.recordInvocation("GetJobDurationPercentile", []interface{}{pipelineID, jobName, percentile, window})
+	fake.getJobDurationPercentileMutex.Unlock()
+	if fake.GetJobDurationPercentileStub != nil {
+		return fake.GetJobDurationPercentileStub(pipelineID, jobName, percentile, window)
+	} else {
+		return fake.getJobDurationPercentileReturns.result1, fake.getJobDurationPercentileReturns.result2, fake.getJobDurationPercentileReturns.result3
+	}
+}
+
+func (fake *FakeDurationWatcherDB) GetJobDurationPercentileCallCount() int {
+	fake.getJobDurationPercentileMutex.RLock()
+	defer fake.getJobDurationPercentileMutex.RUnlock()
+	return len(fake.getJobDurationPercentileArgsForCall)
+}
+
+func (fake *FakeDurationWatcherDB) GetJobDurationPercentileArgsForCall(i int) (int, string, int, time.Duration) {
+	fake.getJobDurationPercentileMutex.RLock()
+	defer fake.getJobDurationPercentileMutex.RUnlock()
+	return fake.getJobDurationPercentileArgsForCall[i].pipelineID, fake.getJobDurationPercentileArgsForCall[i].jobName, fake.getJobDurationPercentileArgsForCall[i].percentile, fake.getJobDurationPercentileArgsForCall[i].window
+}
+
+func (fake *FakeDurationWatcherDB) GetJobDurationPercentileReturns(result1 time.Duration, result2 int, result3 error) {
+	fake.GetJobDurationPercentileStub = nil
+	fake.getJobDurationPercentileReturns = struct {
+		result1 time.Duration
+		result2 int
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeDurationWatcherDB) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.getAllStartedBuildsMutex.RLock()
+	defer fake.getAllStartedBuildsMutex.RUnlock()
+	fake.getJobDurationPercentileMutex.RLock()
+	defer fake.getJobDurationPercentileMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeDurationWatcherDB) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ builds.DurationWatcherDB = new(FakeDurationWatcherDB)
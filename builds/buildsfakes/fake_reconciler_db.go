@@ -0,0 +1,114 @@
+// This file was generated by counterfeiter
+package buildsfakes
+
+import (
+	"sync"
+
+	"github.com/concourse/atc/builds"
+	"github.com/concourse/atc/db"
+)
+
+type FakeReconcilerDB struct {
+	GetAllStartedBuildsStub        func() ([]db.Build, error)
+	getAllStartedBuildsMutex       sync.RWMutex
+	getAllStartedBuildsArgsForCall []struct{}
+	getAllStartedBuildsReturns     struct {
+		result1 []db.Build
+		result2 error
+	}
+	GetContainerHandlesForBuildStub        func(buildID int) ([]string, error)
+	getContainerHandlesForBuildMutex       sync.RWMutex
+	getContainerHandlesForBuildArgsForCall []struct {
+		buildID int
+	}
+	getContainerHandlesForBuildReturns struct {
+		result1 []string
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeReconcilerDB) GetAllStartedBuilds() ([]db.Build, error) {
+	fake.getAllStartedBuildsMutex.Lock()
+	fake.getAllStartedBuildsArgsForCall = append(fake.getAllStartedBuildsArgsForCall, struct{}{})
+	fake.recordInvocation("GetAllStartedBuilds", []interface{}{})
+	fake.getAllStartedBuildsMutex.Unlock()
+	if fake.GetAllStartedBuildsStub != nil {
+		return fake.GetAllStartedBuildsStub()
+	} else {
+		return fake.getAllStartedBuildsReturns.result1, fake.getAllStartedBuildsReturns.result2
+	}
+}
+
+func (fake *FakeReconcilerDB) GetAllStartedBuildsCallCount() int {
+	fake.getAllStartedBuildsMutex.RLock()
+	defer fake.getAllStartedBuildsMutex.RUnlock()
+	return len(fake.getAllStartedBuildsArgsForCall)
+}
+
+func (fake *FakeReconcilerDB) GetAllStartedBuildsReturns(result1 []db.Build, result2 error) {
+	fake.GetAllStartedBuildsStub = nil
+	fake.getAllStartedBuildsReturns = struct {
+		result1 []db.Build
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeReconcilerDB) GetContainerHandlesForBuild(buildID int) ([]string, error) {
+	fake.getContainerHandlesForBuildMutex.Lock()
+	fake.getContainerHandlesForBuildArgsForCall = append(fake.getContainerHandlesForBuildArgsForCall, struct {
+		buildID int
+	}{buildID})
+	fake.recordInvocation("GetContainerHandlesForBuild", []interface{}{buildID})
+	fake.getContainerHandlesForBuildMutex.Unlock()
+	if fake.GetContainerHandlesForBuildStub != nil {
+		return fake.GetContainerHandlesForBuildStub(buildID)
+	} else {
+		return fake.getContainerHandlesForBuildReturns.result1, fake.getContainerHandlesForBuildReturns.result2
+	}
+}
+
+func (fake *FakeReconcilerDB) GetContainerHandlesForBuildCallCount() int {
+	fake.getContainerHandlesForBuildMutex.RLock()
+	defer fake.getContainerHandlesForBuildMutex.RUnlock()
+	return len(fake.getContainerHandlesForBuildArgsForCall)
+}
+
+func (fake *FakeReconcilerDB) GetContainerHandlesForBuildArgsForCall(i int) int {
+	fake.getContainerHandlesForBuildMutex.RLock()
+	defer fake.getContainerHandlesForBuildMutex.RUnlock()
+	return fake.getContainerHandlesForBuildArgsForCall[i].buildID
+}
+
+func (fake *FakeReconcilerDB) GetContainerHandlesForBuildReturns(result1 []string, result2 error) {
+	fake.GetContainerHandlesForBuildStub = nil
+	fake.getContainerHandlesForBuildReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeReconcilerDB) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.getAllStartedBuildsMutex.RLock()
+	defer fake.getAllStartedBuildsMutex.RUnlock()
+	fake.getContainerHandlesForBuildMutex.RLock()
+	defer fake.getContainerHandlesForBuildMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeReconcilerDB) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ builds.ReconcilerDB = new(FakeReconcilerDB)
@@ -0,0 +1,126 @@
+package builds
+
+import (
+	"fmt"
+	"sort"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/engine"
+)
+
+//go:generate counterfeiter . DependencyDB
+
+type DependencyDB interface {
+	GetBuildsAwaitingDependencies() ([]db.Build, error)
+}
+
+func NewDependencyStarter(
+	logger lager.Logger,
+
+	dependencyDB DependencyDB,
+	engine engine.Engine,
+) *DependencyStarter {
+	return &DependencyStarter{
+		logger:       logger,
+		dependencyDB: dependencyDB,
+		engine:       engine,
+	}
+}
+
+// DependencyStarter starts builds that were created with dependencies once
+// all of them have succeeded, and errors them as soon as any one of them
+// doesn't. One-off builds left pending by whoever created them have no
+// dependencies to wait on, and so are started straight away.
+type DependencyStarter struct {
+	logger lager.Logger
+
+	dependencyDB DependencyDB
+	engine       engine.Engine
+}
+
+func (ds *DependencyStarter) Track() {
+	ds.logger.Debug("start")
+	defer ds.logger.Debug("done")
+
+	builds, err := ds.dependencyDB.GetBuildsAwaitingDependencies()
+	if err != nil {
+		ds.logger.Error("failed-to-lookup-builds-awaiting-dependencies", err)
+		return
+	}
+
+	for _, build := range builds {
+		ds.check(ds.logger.Session("check", lager.Data{
+			"build": build.ID(),
+		}), build)
+	}
+}
+
+func (ds *DependencyStarter) check(logger lager.Logger, build db.Build) {
+	statuses, err := build.GetDependencyStatuses()
+	if err != nil {
+		logger.Error("failed-to-get-dependency-statuses", err)
+		return
+	}
+
+	ids := make([]int, 0, len(statuses))
+	for id := range statuses {
+		ids = append(ids, id)
+	}
+
+	sort.Ints(ids)
+
+	ready := true
+	for _, id := range ids {
+		switch statuses[id] {
+		case db.StatusSucceeded:
+		case db.StatusFailed, db.StatusErrored, db.StatusAborted, db.StatusTimedOut:
+			_, claimed, err := build.ClaimPendingPlan()
+			if err != nil {
+				logger.Error("failed-to-claim-pending-plan", err)
+				return
+			}
+
+			if !claimed {
+				return
+			}
+
+			err = build.MarkAsFailed(fmt.Errorf("dependency build %d %s", id, statuses[id]))
+			if err != nil {
+				logger.Error("failed-to-mark-build-as-errored", err)
+			}
+
+			return
+		default:
+			ready = false
+		}
+	}
+
+	if !ready {
+		return
+	}
+
+	plan, claimed, err := build.ClaimPendingPlan()
+	if err != nil {
+		logger.Error("failed-to-claim-pending-plan", err)
+		return
+	}
+
+	if !claimed {
+		return
+	}
+
+	engineBuild, err := ds.engine.CreateBuild(logger, build, plan)
+	if err != nil {
+		logger.Error("failed-to-start-build", err)
+
+		err := build.MarkAsFailed(err)
+		if err != nil {
+			logger.Error("failed-to-mark-build-as-errored", err)
+		}
+
+		return
+	}
+
+	go engineBuild.Resume(logger)
+}
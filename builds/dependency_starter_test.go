@@ -0,0 +1,146 @@
+package builds_test
+
+import (
+	"errors"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/builds"
+	"github.com/concourse/atc/builds/buildsfakes"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/db/dbfakes"
+	"github.com/concourse/atc/engine/enginefakes"
+)
+
+var _ = Describe("DependencyStarter", func() {
+	var (
+		fakeDependencyDB *buildsfakes.FakeDependencyDB
+		fakeEngine       *enginefakes.FakeEngine
+
+		build       *dbfakes.FakeBuild
+		engineBuild *enginefakes.FakeBuild
+
+		plan atc.Plan
+
+		starter *builds.DependencyStarter
+	)
+
+	BeforeEach(func() {
+		fakeDependencyDB = new(buildsfakes.FakeDependencyDB)
+		fakeEngine = new(enginefakes.FakeEngine)
+
+		build = new(dbfakes.FakeBuild)
+		build.IDReturns(42)
+		fakeDependencyDB.GetBuildsAwaitingDependenciesReturns([]db.Build{build}, nil)
+
+		plan = atc.Plan{ID: "some-plan"}
+		build.ClaimPendingPlanReturns(plan, true, nil)
+
+		engineBuild = new(enginefakes.FakeBuild)
+		fakeEngine.CreateBuildReturns(engineBuild, nil)
+
+		starter = builds.NewDependencyStarter(
+			lagertest.NewTestLogger("test"),
+			fakeDependencyDB,
+			fakeEngine,
+		)
+	})
+
+	Context("when every dependency has succeeded", func() {
+		BeforeEach(func() {
+			build.GetDependencyStatusesReturns(map[int]db.Status{
+				1: db.StatusSucceeded,
+				2: db.StatusSucceeded,
+			}, nil)
+		})
+
+		It("starts the build with its pending plan", func() {
+			starter.Track()
+
+			Expect(fakeEngine.CreateBuildCallCount()).To(Equal(1))
+			_, createdBuild, createdPlan := fakeEngine.CreateBuildArgsForCall(0)
+			Expect(createdBuild).To(Equal(build))
+			Expect(createdPlan).To(Equal(plan))
+
+			Eventually(engineBuild.ResumeCallCount).Should(Equal(1))
+		})
+
+		Context("when the plan has already been claimed", func() {
+			BeforeEach(func() {
+				build.ClaimPendingPlanReturns(atc.Plan{}, false, nil)
+			})
+
+			It("leaves the build alone", func() {
+				starter.Track()
+
+				Expect(fakeEngine.CreateBuildCallCount()).To(BeZero())
+				Expect(build.MarkAsFailedCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when the engine fails to create the build", func() {
+			BeforeEach(func() {
+				fakeEngine.CreateBuildReturns(nil, errors.New("nope"))
+			})
+
+			It("errors the build", func() {
+				starter.Track()
+
+				Expect(build.MarkAsFailedCallCount()).To(Equal(1))
+				Expect(build.MarkAsFailedArgsForCall(0)).To(Equal(errors.New("nope")))
+			})
+		})
+	})
+
+	Context("when a dependency is still running", func() {
+		BeforeEach(func() {
+			build.GetDependencyStatusesReturns(map[int]db.Status{
+				1: db.StatusSucceeded,
+				2: db.StatusStarted,
+			}, nil)
+		})
+
+		It("keeps waiting", func() {
+			starter.Track()
+
+			Expect(build.ClaimPendingPlanCallCount()).To(BeZero())
+			Expect(fakeEngine.CreateBuildCallCount()).To(BeZero())
+			Expect(build.MarkAsFailedCallCount()).To(BeZero())
+		})
+	})
+
+	Context("when a dependency did not succeed", func() {
+		BeforeEach(func() {
+			build.GetDependencyStatusesReturns(map[int]db.Status{
+				1: db.StatusSucceeded,
+				2: db.StatusFailed,
+				3: db.StatusStarted,
+			}, nil)
+		})
+
+		It("errors the build without starting it", func() {
+			starter.Track()
+
+			Expect(build.ClaimPendingPlanCallCount()).To(Equal(1))
+			Expect(fakeEngine.CreateBuildCallCount()).To(BeZero())
+
+			Expect(build.MarkAsFailedCallCount()).To(Equal(1))
+			Expect(build.MarkAsFailedArgsForCall(0)).To(MatchError("dependency build 2 failed"))
+		})
+	})
+
+	Context("when looking up the builds fails", func() {
+		BeforeEach(func() {
+			fakeDependencyDB.GetBuildsAwaitingDependenciesReturns(nil, errors.New("nope"))
+		})
+
+		It("does nothing", func() {
+			starter.Track()
+
+			Expect(fakeEngine.CreateBuildCallCount()).To(BeZero())
+		})
+	})
+})
@@ -0,0 +1,167 @@
+package builds
+
+import (
+	"fmt"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/event"
+)
+
+//go:generate counterfeiter . DurationWatcherDB
+
+type DurationWatcherDB interface {
+	GetAllStartedBuilds() ([]db.Build, error)
+	GetJobDurationPercentile(pipelineID int, jobName string, percentile int, window time.Duration) (time.Duration, int, error)
+}
+
+func NewDurationWatcher(
+	logger lager.Logger,
+
+	watcherDB DurationWatcherDB,
+	percentile int,
+	window time.Duration,
+	minBuilds int,
+	clock clock.Clock,
+) *DurationWatcher {
+	return &DurationWatcher{
+		logger:     logger,
+		watcherDB:  watcherDB,
+		percentile: percentile,
+		window:     window,
+		minBuilds:  minBuilds,
+		clock:      clock,
+
+		alerted: map[int]bool{},
+	}
+}
+
+// DurationWatcher raises an alert for started builds that have been running
+// for longer than the given percentile of their job's builds that finished
+// within the window took, and warns about it in the build's events. Jobs with
+// fewer than minBuilds builds in the window aren't watched, as there's not
+// enough to go on.
+type DurationWatcher struct {
+	logger lager.Logger
+
+	watcherDB  DurationWatcherDB
+	percentile int
+	window     time.Duration
+	minBuilds  int
+	clock      clock.Clock
+
+	// builds already alerted on, so they're not saved again every time
+	alerted map[int]bool
+}
+
+type durationWatcherJob struct {
+	pipelineID int
+	jobName    string
+}
+
+type durationThreshold struct {
+	threshold time.Duration
+	builds    int
+}
+
+func (dw *DurationWatcher) Track() {
+	dw.logger.Debug("start")
+	defer dw.logger.Debug("done")
+
+	builds, err := dw.watcherDB.GetAllStartedBuilds()
+	if err != nil {
+		dw.logger.Error("failed-to-lookup-started-builds", err)
+		return
+	}
+
+	stillStarted := map[int]bool{}
+	thresholds := map[durationWatcherJob]durationThreshold{}
+
+	for _, build := range builds {
+		if build.IsOneOff() {
+			continue
+		}
+
+		if dw.alerted[build.ID()] {
+			stillStarted[build.ID()] = true
+			continue
+		}
+
+		job := durationWatcherJob{
+			pipelineID: build.PipelineID(),
+			jobName:    build.JobName(),
+		}
+
+		threshold, found := thresholds[job]
+		if !found {
+			threshold.threshold, threshold.builds, err = dw.watcherDB.GetJobDurationPercentile(job.pipelineID, job.jobName, dw.percentile, dw.window)
+			if err != nil {
+				dw.logger.Error("failed-to-get-job-duration-percentile", err, lager.Data{
+					"pipeline": build.PipelineName(),
+					"job":      build.JobName(),
+				})
+				continue
+			}
+
+			thresholds[job] = threshold
+		}
+
+		if threshold.builds < dw.minBuilds || threshold.threshold == 0 {
+			continue
+		}
+
+		elapsed := dw.clock.Since(build.StartTime())
+		if elapsed <= threshold.threshold {
+			continue
+		}
+
+		alerted := dw.alert(dw.logger.Session("alert", lager.Data{
+			"build":     build.ID(),
+			"threshold": threshold.threshold.String(),
+		}), build, threshold.threshold, elapsed)
+
+		if alerted {
+			dw.alerted[build.ID()] = true
+			stillStarted[build.ID()] = true
+		}
+	}
+
+	// forget builds once they've finished
+	for id := range dw.alerted {
+		if !stillStarted[id] {
+			delete(dw.alerted, id)
+		}
+	}
+}
+
+func (dw *DurationWatcher) alert(logger lager.Logger, build db.Build, threshold time.Duration, elapsed time.Duration) bool {
+	// only one ATC gets to raise the alert; the rest just stop looking
+	saved, err := build.SaveDurationAlert(dw.percentile, threshold, elapsed)
+	if err != nil {
+		logger.Error("failed-to-save-duration-alert", err)
+		return false
+	}
+
+	if !saved {
+		return true
+	}
+
+	logger.Info("alerted")
+
+	err = build.SaveEvent(event.Warning{
+		Message: fmt.Sprintf(
+			"build has been running for %s, longer than %d%% of this job's recent builds took (%s)",
+			elapsed-elapsed%time.Second,
+			dw.percentile,
+			threshold-threshold%time.Second,
+		),
+		Time: dw.clock.Now().Unix(),
+	})
+	if err != nil {
+		logger.Error("failed-to-save-warning-event", err)
+	}
+
+	return true
+}
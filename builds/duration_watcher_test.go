@@ -0,0 +1,184 @@
+package builds_test
+
+import (
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/atc/builds"
+	"github.com/concourse/atc/builds/buildsfakes"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/db/dbfakes"
+	"github.com/concourse/atc/event"
+)
+
+var _ = Describe("DurationWatcher", func() {
+	var (
+		fakeWatcherDB *buildsfakes.FakeDurationWatcherDB
+		fakeClock     *fakeclock.FakeClock
+
+		fakeBuild *dbfakes.FakeBuild
+
+		watcher *builds.DurationWatcher
+	)
+
+	BeforeEach(func() {
+		fakeWatcherDB = new(buildsfakes.FakeDurationWatcherDB)
+		fakeClock = fakeclock.NewFakeClock(time.Unix(123456, 0))
+
+		fakeBuild = new(dbfakes.FakeBuild)
+		fakeBuild.IDReturns(42)
+		fakeBuild.PipelineIDReturns(7)
+		fakeBuild.JobNameReturns("some-job")
+		fakeBuild.StartTimeReturns(fakeClock.Now().Add(-40 * time.Minute))
+		fakeBuild.SaveDurationAlertReturns(true, nil)
+
+		fakeWatcherDB.GetAllStartedBuildsReturns([]db.Build{fakeBuild}, nil)
+		fakeWatcherDB.GetJobDurationPercentileReturns(4*time.Minute, 20, nil)
+
+		watcher = builds.NewDurationWatcher(
+			lagertest.NewTestLogger("test"),
+			fakeWatcherDB,
+			95,
+			168*time.Hour,
+			10,
+			fakeClock,
+		)
+	})
+
+	JustBeforeEach(func() {
+		watcher.Track()
+	})
+
+	It("looks up the percentile of the job's builds within the window", func() {
+		Expect(fakeWatcherDB.GetJobDurationPercentileCallCount()).To(Equal(1))
+		pipelineID, jobName, percentile, window := fakeWatcherDB.GetJobDurationPercentileArgsForCall(0)
+		Expect(pipelineID).To(Equal(7))
+		Expect(jobName).To(Equal("some-job"))
+		Expect(percentile).To(Equal(95))
+		Expect(window).To(Equal(168 * time.Hour))
+	})
+
+	Context("when the build has been running for longer than the percentile", func() {
+		It("saves an alert", func() {
+			Expect(fakeBuild.SaveDurationAlertCallCount()).To(Equal(1))
+			percentile, threshold, elapsed := fakeBuild.SaveDurationAlertArgsForCall(0)
+			Expect(percentile).To(Equal(95))
+			Expect(threshold).To(Equal(4 * time.Minute))
+			Expect(elapsed).To(Equal(40 * time.Minute))
+		})
+
+		It("warns about it in the build's events", func() {
+			Expect(fakeBuild.SaveEventCallCount()).To(Equal(1))
+			Expect(fakeBuild.SaveEventArgsForCall(0)).To(Equal(event.Warning{
+				Message: "build has been running for 40m0s, longer than 95% of this job's recent builds took (4m0s)",
+				Time:    123456,
+			}))
+		})
+
+		It("doesn't alert again on the next pass", func() {
+			watcher.Track()
+			Expect(fakeBuild.SaveDurationAlertCallCount()).To(Equal(1))
+			Expect(fakeBuild.SaveEventCallCount()).To(Equal(1))
+		})
+
+		Context("when another ATC already raised the alert", func() {
+			BeforeEach(func() {
+				fakeBuild.SaveDurationAlertReturns(false, nil)
+			})
+
+			It("doesn't warn about it again", func() {
+				Expect(fakeBuild.SaveEventCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when saving the alert fails", func() {
+			BeforeEach(func() {
+				fakeBuild.SaveDurationAlertReturns(false, errors.New("nope"))
+			})
+
+			It("doesn't warn and tries again on the next pass", func() {
+				Expect(fakeBuild.SaveEventCallCount()).To(BeZero())
+
+				watcher.Track()
+				Expect(fakeBuild.SaveDurationAlertCallCount()).To(Equal(2))
+			})
+		})
+	})
+
+	Context("when the build hasn't been running for longer than the percentile", func() {
+		BeforeEach(func() {
+			fakeBuild.StartTimeReturns(fakeClock.Now().Add(-3 * time.Minute))
+		})
+
+		It("does not alert", func() {
+			Expect(fakeBuild.SaveDurationAlertCallCount()).To(BeZero())
+			Expect(fakeBuild.SaveEventCallCount()).To(BeZero())
+		})
+	})
+
+	Context("when the job has too few builds within the window", func() {
+		BeforeEach(func() {
+			fakeWatcherDB.GetJobDurationPercentileReturns(4*time.Minute, 9, nil)
+		})
+
+		It("does not alert", func() {
+			Expect(fakeBuild.SaveDurationAlertCallCount()).To(BeZero())
+		})
+	})
+
+	Context("when the build is a one-off", func() {
+		BeforeEach(func() {
+			fakeBuild.IsOneOffReturns(true)
+		})
+
+		It("doesn't look at it", func() {
+			Expect(fakeWatcherDB.GetJobDurationPercentileCallCount()).To(BeZero())
+			Expect(fakeBuild.SaveDurationAlertCallCount()).To(BeZero())
+		})
+	})
+
+	Context("when several builds of the job are running", func() {
+		var otherBuild *dbfakes.FakeBuild
+
+		BeforeEach(func() {
+			otherBuild = new(dbfakes.FakeBuild)
+			otherBuild.IDReturns(43)
+			otherBuild.PipelineIDReturns(7)
+			otherBuild.JobNameReturns("some-job")
+			otherBuild.StartTimeReturns(fakeClock.Now().Add(-time.Minute))
+
+			fakeWatcherDB.GetAllStartedBuildsReturns([]db.Build{fakeBuild, otherBuild}, nil)
+		})
+
+		It("only looks up the percentile once", func() {
+			Expect(fakeWatcherDB.GetJobDurationPercentileCallCount()).To(Equal(1))
+			Expect(fakeBuild.SaveDurationAlertCallCount()).To(Equal(1))
+			Expect(otherBuild.SaveDurationAlertCallCount()).To(BeZero())
+		})
+	})
+
+	Context("when looking up the percentile fails", func() {
+		BeforeEach(func() {
+			fakeWatcherDB.GetJobDurationPercentileReturns(0, 0, errors.New("nope"))
+		})
+
+		It("does not alert", func() {
+			Expect(fakeBuild.SaveDurationAlertCallCount()).To(BeZero())
+		})
+	})
+
+	Context("when looking up the started builds fails", func() {
+		BeforeEach(func() {
+			fakeWatcherDB.GetAllStartedBuildsReturns(nil, errors.New("nope"))
+		})
+
+		It("does nothing", func() {
+			Expect(fakeWatcherDB.GetJobDurationPercentileCallCount()).To(BeZero())
+		})
+	})
+})
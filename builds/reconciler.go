@@ -0,0 +1,146 @@
+package builds
+
+import (
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/worker"
+)
+
+//go:generate counterfeiter . ReconcilerDB
+
+type ReconcilerDB interface {
+	GetAllStartedBuilds() ([]db.Build, error)
+	GetContainerHandlesForBuild(buildID int) ([]string, error)
+}
+
+func NewReconciler(
+	logger lager.Logger,
+
+	reconcilerDB ReconcilerDB,
+	workerClient worker.Client,
+	gracePeriod time.Duration,
+	clock clock.Clock,
+) *Reconciler {
+	return &Reconciler{
+		logger:       logger,
+		reconcilerDB: reconcilerDB,
+		workerClient: workerClient,
+		gracePeriod:  gracePeriod,
+		clock:        clock,
+	}
+}
+
+// Reconciler errors started builds that have been left behind, e.g. by an
+// ATC that crashed: nothing is tracking them, and every container they ran
+// in is gone, either from its worker or along with it.
+//
+// Builds without any containers are left alone, as there's no telling
+// whether they're stuck or just haven't got to running anything yet.
+type Reconciler struct {
+	logger lager.Logger
+
+	reconcilerDB ReconcilerDB
+	workerClient worker.Client
+	gracePeriod  time.Duration
+	clock        clock.Clock
+}
+
+func (r *Reconciler) Track() {
+	r.Reconcile(r.logger)
+}
+
+// Reconcile errors every build that's been left behind, returning their IDs.
+// Builds that started within the grace period are skipped, so that ATCs
+// that have just come back up get the chance to resume them first.
+func (r *Reconciler) Reconcile(logger lager.Logger) ([]int, error) {
+	logger = logger.Session("reconcile")
+
+	logger.Debug("start")
+	defer logger.Debug("done")
+
+	builds, err := r.reconcilerDB.GetAllStartedBuilds()
+	if err != nil {
+		logger.Error("failed-to-lookup-started-builds", err)
+		return nil, err
+	}
+
+	errored := []int{}
+	for _, build := range builds {
+		if r.clock.Since(build.StartTime()) < r.gracePeriod {
+			continue
+		}
+
+		bLog := logger.Session("build", lager.Data{
+			"build":    build.ID(),
+			"pipeline": build.PipelineName(),
+			"job":      build.JobName(),
+		})
+
+		if r.reconcile(bLog, build) {
+			errored = append(errored, build.ID())
+		}
+	}
+
+	if len(errored) > 0 {
+		logger.Info("errored-left-behind-builds", lager.Data{"builds": errored})
+	}
+
+	return errored, nil
+}
+
+func (r *Reconciler) reconcile(logger lager.Logger, build db.Build) bool {
+	// holding the lock keeps anyone from resuming the build while it's
+	// being looked at
+	lock, acquired, err := build.AcquireTrackingLock(logger, time.Minute)
+	if err != nil {
+		logger.Error("failed-to-get-lock", err)
+		return false
+	}
+
+	if !acquired {
+		// something is tracking it
+		return false
+	}
+
+	defer lock.Release()
+
+	handles, err := r.reconcilerDB.GetContainerHandlesForBuild(build.ID())
+	if err != nil {
+		logger.Error("failed-to-get-containers", err)
+		return false
+	}
+
+	if len(handles) == 0 {
+		return false
+	}
+
+	for _, handle := range handles {
+		container, found, err := r.workerClient.LookupContainer(logger, handle)
+		if err == worker.ErrMissingWorker {
+			continue
+		}
+
+		if err != nil {
+			// can't tell if it's still there
+			logger.Error("failed-to-lookup-container", err, lager.Data{"handle": handle})
+			return false
+		}
+
+		if found {
+			container.Release(nil)
+			return false
+		}
+	}
+
+	err = build.MarkAsFailed(errors.New("build is no longer running anywhere: nothing is tracking it, and all of its containers are gone"))
+	if err != nil {
+		logger.Error("failed-to-mark-build-as-errored", err)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,159 @@
+package builds_test
+
+import (
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/builds"
+	"github.com/concourse/atc/builds/buildsfakes"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/db/dbfakes"
+	"github.com/concourse/atc/worker"
+	"github.com/concourse/atc/worker/workerfakes"
+)
+
+var _ = Describe("Reconciler", func() {
+	var (
+		fakeReconcilerDB *buildsfakes.FakeReconcilerDB
+		fakeWorkerClient *workerfakes.FakeClient
+		fakeClock        *fakeclock.FakeClock
+
+		fakeBuild *dbfakes.FakeBuild
+		fakeLock  *dbfakes.FakeLock
+
+		reconciler *builds.Reconciler
+
+		errored      []int
+		reconcileErr error
+	)
+
+	BeforeEach(func() {
+		fakeReconcilerDB = new(buildsfakes.FakeReconcilerDB)
+		fakeWorkerClient = new(workerfakes.FakeClient)
+		fakeClock = fakeclock.NewFakeClock(time.Unix(123, 0))
+
+		fakeBuild = new(dbfakes.FakeBuild)
+		fakeBuild.IDReturns(42)
+		fakeBuild.StartTimeReturns(fakeClock.Now().Add(-time.Hour))
+
+		fakeLock = new(dbfakes.FakeLock)
+		fakeBuild.AcquireTrackingLockReturns(fakeLock, true, nil)
+
+		fakeReconcilerDB.GetAllStartedBuildsReturns([]db.Build{fakeBuild}, nil)
+		fakeReconcilerDB.GetContainerHandlesForBuildReturns([]string{"handle-1", "handle-2"}, nil)
+
+		fakeWorkerClient.LookupContainerStub = func(_ lager.Logger, handle string) (worker.Container, bool, error) {
+			if handle == "handle-1" {
+				return nil, false, worker.ErrMissingWorker
+			}
+
+			return nil, false, nil
+		}
+
+		reconciler = builds.NewReconciler(
+			lagertest.NewTestLogger("test"),
+			fakeReconcilerDB,
+			fakeWorkerClient,
+			10*time.Minute,
+			fakeClock,
+		)
+	})
+
+	JustBeforeEach(func() {
+		errored, reconcileErr = reconciler.Reconcile(lagertest.NewTestLogger("test"))
+	})
+
+	Context("when nothing is tracking the build and its containers are gone", func() {
+		It("errors it, saying why", func() {
+			Expect(reconcileErr).NotTo(HaveOccurred())
+			Expect(errored).To(Equal([]int{42}))
+
+			Expect(fakeBuild.MarkAsFailedCallCount()).To(Equal(1))
+			Expect(fakeBuild.MarkAsFailedArgsForCall(0)).To(MatchError(ContainSubstring("no longer running anywhere")))
+		})
+
+		It("looks at the build's containers while holding its tracking lock", func() {
+			Expect(fakeReconcilerDB.GetContainerHandlesForBuildArgsForCall(0)).To(Equal(42))
+			Expect(fakeWorkerClient.LookupContainerCallCount()).To(Equal(2))
+			Expect(fakeLock.ReleaseCallCount()).To(Equal(1))
+		})
+	})
+
+	Context("when one of its containers is still there", func() {
+		var fakeContainer *workerfakes.FakeContainer
+
+		BeforeEach(func() {
+			fakeContainer = new(workerfakes.FakeContainer)
+			fakeWorkerClient.LookupContainerReturns(fakeContainer, true, nil)
+			fakeWorkerClient.LookupContainerStub = nil
+		})
+
+		It("leaves the build alone", func() {
+			Expect(errored).To(BeEmpty())
+			Expect(fakeBuild.MarkAsFailedCallCount()).To(BeZero())
+			Expect(fakeContainer.ReleaseCallCount()).To(Equal(1))
+		})
+	})
+
+	Context("when a container can't be looked up", func() {
+		BeforeEach(func() {
+			fakeWorkerClient.LookupContainerStub = nil
+			fakeWorkerClient.LookupContainerReturns(nil, false, errors.New("nope"))
+		})
+
+		It("leaves the build alone", func() {
+			Expect(errored).To(BeEmpty())
+			Expect(fakeBuild.MarkAsFailedCallCount()).To(BeZero())
+		})
+	})
+
+	Context("when the build has no containers", func() {
+		BeforeEach(func() {
+			fakeReconcilerDB.GetContainerHandlesForBuildReturns([]string{}, nil)
+		})
+
+		It("leaves it alone", func() {
+			Expect(errored).To(BeEmpty())
+			Expect(fakeBuild.MarkAsFailedCallCount()).To(BeZero())
+		})
+	})
+
+	Context("when something is tracking the build", func() {
+		BeforeEach(func() {
+			fakeBuild.AcquireTrackingLockReturns(nil, false, nil)
+		})
+
+		It("leaves it alone without looking at its containers", func() {
+			Expect(errored).To(BeEmpty())
+			Expect(fakeReconcilerDB.GetContainerHandlesForBuildCallCount()).To(BeZero())
+			Expect(fakeBuild.MarkAsFailedCallCount()).To(BeZero())
+		})
+	})
+
+	Context("when the build started within the grace period", func() {
+		BeforeEach(func() {
+			fakeBuild.StartTimeReturns(fakeClock.Now().Add(-time.Minute))
+		})
+
+		It("leaves it alone", func() {
+			Expect(errored).To(BeEmpty())
+			Expect(fakeBuild.AcquireTrackingLockCallCount()).To(BeZero())
+		})
+	})
+
+	Context("when the started builds can't be looked up", func() {
+		BeforeEach(func() {
+			fakeReconcilerDB.GetAllStartedBuildsReturns(nil, errors.New("nope"))
+		})
+
+		It("returns the error", func() {
+			Expect(reconcileErr).To(MatchError("nope"))
+		})
+	})
+})
@@ -0,0 +1,102 @@
+package builds
+
+import (
+	"fmt"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/engine"
+	"github.com/concourse/atc/event"
+)
+
+func NewTimeoutEnforcer(
+	logger lager.Logger,
+
+	trackerDB TrackerDB,
+	engine engine.Engine,
+	defaultTimeout time.Duration,
+	clock clock.Clock,
+) *TimeoutEnforcer {
+	return &TimeoutEnforcer{
+		logger:         logger,
+		trackerDB:      trackerDB,
+		engine:         engine,
+		defaultTimeout: defaultTimeout,
+		clock:          clock,
+	}
+}
+
+// TimeoutEnforcer aborts started builds that have been running for longer
+// than their timeout, or the default timeout if they don't have their own,
+// and marks them as timed out.
+type TimeoutEnforcer struct {
+	logger lager.Logger
+
+	trackerDB      TrackerDB
+	engine         engine.Engine
+	defaultTimeout time.Duration
+	clock          clock.Clock
+}
+
+func (te *TimeoutEnforcer) Track() {
+	te.logger.Debug("start")
+	defer te.logger.Debug("done")
+
+	builds, err := te.trackerDB.GetAllStartedBuilds()
+	if err != nil {
+		te.logger.Error("failed-to-lookup-started-builds", err)
+		return
+	}
+
+	for _, build := range builds {
+		timeout := build.Timeout()
+		if timeout == 0 {
+			timeout = te.defaultTimeout
+		}
+
+		if timeout == 0 || te.clock.Since(build.StartTime()) < timeout {
+			continue
+		}
+
+		te.timeOut(te.logger.Session("time-out", lager.Data{
+			"build":   build.ID(),
+			"timeout": timeout.String(),
+		}), build, timeout)
+	}
+}
+
+func (te *TimeoutEnforcer) timeOut(logger lager.Logger, build db.Build, timeout time.Duration) {
+	// only one ATC gets to time the build out; the rest see it's no longer
+	// started
+	timedOut, err := build.MarkAsTimedOut()
+	if err != nil {
+		logger.Error("failed-to-mark-build-as-timed-out", err)
+		return
+	}
+
+	if !timedOut {
+		return
+	}
+
+	logger.Info("timed-out")
+
+	err = build.SaveEvent(event.Error{
+		Message: fmt.Sprintf("build timed out after %s", timeout),
+	})
+	if err != nil {
+		logger.Error("failed-to-save-timeout-event", err)
+	}
+
+	engineBuild, err := te.engine.LookupBuild(logger, build)
+	if err != nil {
+		logger.Error("failed-to-lookup-build", err)
+		return
+	}
+
+	err = engineBuild.Abort(logger)
+	if err != nil {
+		logger.Error("failed-to-abort-build", err)
+	}
+}
@@ -0,0 +1,164 @@
+package builds_test
+
+import (
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/atc/builds"
+	"github.com/concourse/atc/builds/buildsfakes"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/db/dbfakes"
+	"github.com/concourse/atc/engine/enginefakes"
+	"github.com/concourse/atc/event"
+)
+
+var _ = Describe("TimeoutEnforcer", func() {
+	var (
+		fakeTrackerDB *buildsfakes.FakeTrackerDB
+		fakeEngine    *enginefakes.FakeEngine
+		fakeClock     *fakeclock.FakeClock
+
+		defaultTimeout time.Duration
+
+		fakeBuild       *dbfakes.FakeBuild
+		fakeEngineBuild *enginefakes.FakeBuild
+
+		enforcer *builds.TimeoutEnforcer
+	)
+
+	BeforeEach(func() {
+		fakeTrackerDB = new(buildsfakes.FakeTrackerDB)
+		fakeEngine = new(enginefakes.FakeEngine)
+		fakeClock = fakeclock.NewFakeClock(time.Unix(123, 0))
+
+		defaultTimeout = 0
+
+		fakeBuild = new(dbfakes.FakeBuild)
+		fakeBuild.IDReturns(42)
+		fakeBuild.StartTimeReturns(fakeClock.Now().Add(-time.Hour))
+		fakeBuild.MarkAsTimedOutReturns(true, nil)
+
+		fakeTrackerDB.GetAllStartedBuildsReturns([]db.Build{fakeBuild}, nil)
+
+		fakeEngineBuild = new(enginefakes.FakeBuild)
+		fakeEngine.LookupBuildReturns(fakeEngineBuild, nil)
+	})
+
+	JustBeforeEach(func() {
+		enforcer = builds.NewTimeoutEnforcer(
+			lagertest.NewTestLogger("test"),
+			fakeTrackerDB,
+			fakeEngine,
+			defaultTimeout,
+			fakeClock,
+		)
+
+		enforcer.Track()
+	})
+
+	itTimesOutTheBuild := func(timeout string) {
+		It("marks the build as timed out", func() {
+			Expect(fakeBuild.MarkAsTimedOutCallCount()).To(Equal(1))
+		})
+
+		It("saves an error event saying why", func() {
+			Expect(fakeBuild.SaveEventCallCount()).To(Equal(1))
+			Expect(fakeBuild.SaveEventArgsForCall(0)).To(Equal(event.Error{
+				Message: "build timed out after " + timeout,
+			}))
+		})
+
+		It("aborts the build", func() {
+			Expect(fakeEngine.LookupBuildCallCount()).To(Equal(1))
+			_, lookedUpBuild := fakeEngine.LookupBuildArgsForCall(0)
+			Expect(lookedUpBuild).To(Equal(fakeBuild))
+
+			Expect(fakeEngineBuild.AbortCallCount()).To(Equal(1))
+		})
+
+		Context("when another ATC has already timed the build out", func() {
+			BeforeEach(func() {
+				fakeBuild.MarkAsTimedOutReturns(false, nil)
+			})
+
+			It("leaves the build alone", func() {
+				Expect(fakeBuild.SaveEventCallCount()).To(BeZero())
+				Expect(fakeEngine.LookupBuildCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when marking the build as timed out fails", func() {
+			BeforeEach(func() {
+				fakeBuild.MarkAsTimedOutReturns(false, errors.New("nope"))
+			})
+
+			It("does not abort the build", func() {
+				Expect(fakeEngine.LookupBuildCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when looking up the build fails", func() {
+			BeforeEach(func() {
+				fakeEngine.LookupBuildReturns(nil, errors.New("nope"))
+			})
+
+			It("does not abort the build", func() {
+				Expect(fakeEngineBuild.AbortCallCount()).To(BeZero())
+			})
+		})
+	}
+
+	Context("when the build has a timeout", func() {
+		Context("that it has run for longer than", func() {
+			BeforeEach(func() {
+				fakeBuild.TimeoutReturns(30 * time.Minute)
+			})
+
+			itTimesOutTheBuild("30m0s")
+		})
+
+		Context("that it has not yet reached", func() {
+			BeforeEach(func() {
+				fakeBuild.TimeoutReturns(2 * time.Hour)
+				defaultTimeout = time.Minute
+			})
+
+			It("leaves the build alone", func() {
+				Expect(fakeBuild.MarkAsTimedOutCallCount()).To(BeZero())
+				Expect(fakeEngine.LookupBuildCallCount()).To(BeZero())
+			})
+		})
+	})
+
+	Context("when the build has no timeout", func() {
+		Context("and there is a default timeout that it has run for longer than", func() {
+			BeforeEach(func() {
+				defaultTimeout = 45 * time.Minute
+			})
+
+			itTimesOutTheBuild("45m0s")
+		})
+
+		Context("and there is no default timeout", func() {
+			It("leaves the build alone", func() {
+				Expect(fakeBuild.MarkAsTimedOutCallCount()).To(BeZero())
+				Expect(fakeEngine.LookupBuildCallCount()).To(BeZero())
+			})
+		})
+	})
+
+	Context("when looking up started builds fails", func() {
+		BeforeEach(func() {
+			fakeTrackerDB.GetAllStartedBuildsReturns(nil, errors.New("nope"))
+		})
+
+		It("does nothing", func() {
+			Expect(fakeEngine.LookupBuildCallCount()).To(BeZero())
+		})
+	})
+})
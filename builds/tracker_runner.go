@@ -17,6 +17,10 @@ type TrackerRunner struct {
 	Tracker  BuildTracker
 	Interval time.Duration
 	Clock    clock.Clock
+
+	// Notifications, if set, make it track as soon as they fire rather than
+	// waiting for the next tick.
+	Notifications <-chan bool
 }
 
 func (runner TrackerRunner) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
@@ -30,6 +34,8 @@ func (runner TrackerRunner) Run(signals <-chan os.Signal, ready chan<- struct{})
 		select {
 		case <-ticker.C():
 			runner.Tracker.Track()
+		case <-runner.Notifications:
+			runner.Tracker.Track()
 		case <-signals:
 			return nil
 		}
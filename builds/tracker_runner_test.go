@@ -75,4 +75,23 @@ var _ = Describe("TrackerRunner", func() {
 			})
 		})
 	})
+
+	Context("when notified", func() {
+		var notifications chan bool
+
+		BeforeEach(func() {
+			notifications = make(chan bool, 1)
+			trackerRunner.Notifications = notifications
+		})
+
+		JustBeforeEach(func() {
+			<-tracked
+			notifications <- true
+		})
+
+		It("tracks without waiting for the interval", func() {
+			<-tracked
+			Consistently(tracked).ShouldNot(Receive())
+		})
+	})
 })
@@ -0,0 +1,29 @@
+package atc
+
+// CandidateConfig is a config being tried out on a percentage of a pipeline's
+// builds before it's promoted to be the pipeline's config.
+type CandidateConfig struct {
+	Percentage int    `json:"percentage"`
+	Author     string `json:"author"`
+	CreatedAt  int64  `json:"created_at"`
+	Config     Config `json:"config"`
+}
+
+// CandidateComparison is how a job's builds have gone since the candidate was
+// saved, built from the pipeline's config and from the candidate. Durations
+// are in seconds.
+type CandidateComparison struct {
+	JobName   string              `json:"job_name"`
+	Current   CandidateBuildStats `json:"current"`
+	Candidate CandidateBuildStats `json:"candidate"`
+}
+
+type CandidateBuildStats struct {
+	Builds    int `json:"builds"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+	Errored   int `json:"errored"`
+	Aborted   int `json:"aborted"`
+
+	AverageDuration int64 `json:"average_duration"`
+}
@@ -0,0 +1,20 @@
+package atc
+
+// Causality is the graph of builds and versions downstream of a resource
+// version. Edges refer to versions and builds by ID.
+type Causality struct {
+	Versions []VersionedResource `json:"versions"`
+	Builds   []Build             `json:"builds"`
+	Edges    []CausalityEdge     `json:"edges"`
+
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+type CausalityEdge struct {
+	// Type is one of "input", "output", or "same_version".
+	Type string `json:"type"`
+
+	VersionID     int `json:"version_id"`
+	BuildID       int `json:"build_id,omitempty"`
+	SameVersionID int `json:"same_version_id,omitempty"`
+}
@@ -19,11 +19,21 @@ type ConfigResponse struct {
 	RawConfig RawConfig `json:"raw_config"`
 }
 
+type ConfigRevision struct {
+	Version   int    `json:"version"`
+	Author    string `json:"author"`
+	CreatedAt int64  `json:"created_at"`
+	Config    Config `json:"config"`
+}
+
 type Config struct {
 	Groups        GroupConfigs    `yaml:"groups" json:"groups" mapstructure:"groups"`
 	Resources     ResourceConfigs `yaml:"resources" json:"resources" mapstructure:"resources"`
 	ResourceTypes ResourceTypes   `yaml:"resource_types" json:"resource_types" mapstructure:"resource_types"`
 	Jobs          JobConfigs      `yaml:"jobs" json:"jobs" mapstructure:"jobs"`
+
+	Notifications  NotificationConfigs  `yaml:"notifications,omitempty" json:"notifications,omitempty" mapstructure:"notifications"`
+	RemoteTriggers RemoteTriggerConfigs `yaml:"remote_triggers,omitempty" json:"remote_triggers,omitempty" mapstructure:"remote_triggers"`
 }
 
 type RawConfig string
@@ -96,10 +106,28 @@ type JobConfig struct {
 	SerialGroups         []string `yaml:"serial_groups,omitempty" json:"serial_groups,omitempty" mapstructure:"serial_groups"`
 	RawMaxInFlight       int      `yaml:"max_in_flight,omitempty" json:"max_in_flight,omitempty" mapstructure:"max_in_flight"`
 	BuildLogsToRetain    int      `yaml:"build_logs_to_retain,omitempty" json:"build_logs_to_retain,omitempty" mapstructure:"build_logs_to_retain"`
+	Schedule             string   `yaml:"schedule,omitempty" json:"schedule,omitempty" mapstructure:"schedule"`
+	BuildTimeout         string   `yaml:"build_timeout,omitempty" json:"build_timeout,omitempty" mapstructure:"build_timeout"`
+
+	ArtifactRetention *ArtifactRetentionConfig `yaml:"artifact_retention,omitempty" json:"artifact_retention,omitempty" mapstructure:"artifact_retention"`
+
+	TriggerParams TriggerParamConfigs `yaml:"trigger_params,omitempty" json:"trigger_params,omitempty" mapstructure:"trigger_params"`
 
 	Plan PlanSequence `yaml:"plan,omitempty" json:"plan,omitempty" mapstructure:"plan"`
 }
 
+// ArtifactRetentionConfig decides which of a job's finished builds keep their
+// containers and artifacts. A build keeps them while it's one of the job's
+// last Builds builds or finished within the last Days days, or forever if
+// neither is set. With SucceededOnly, builds that didn't succeed don't keep
+// them at all, and don't count towards Builds. Builds protected through the
+// API always keep them.
+type ArtifactRetentionConfig struct {
+	Builds        int  `yaml:"builds,omitempty" json:"builds,omitempty" mapstructure:"builds"`
+	Days          int  `yaml:"days,omitempty" json:"days,omitempty" mapstructure:"days"`
+	SucceededOnly bool `yaml:"succeeded_only,omitempty" json:"succeeded_only,omitempty" mapstructure:"succeeded_only"`
+}
+
 func (config JobConfig) MaxInFlight() int {
 	if config.Serial || len(config.SerialGroups) > 0 {
 		return 1
@@ -124,6 +152,35 @@ func (config JobConfig) GetSerialGroups() []string {
 	return []string{}
 }
 
+// A TriggerParamConfig declares a parameter that can be given when triggering
+// the job by hand. Every task in the build gets it as a param, and so as an
+// environment variable.
+type TriggerParamConfig struct {
+	Name string `yaml:"name" json:"name" mapstructure:"name"`
+
+	// must be given when triggering the job
+	Required bool `yaml:"required,omitempty" json:"required,omitempty" mapstructure:"required"`
+
+	// used when the param isn't given, including for builds triggered by new
+	// versions of inputs
+	Default string `yaml:"default,omitempty" json:"default,omitempty" mapstructure:"default"`
+
+	// if set, the only values the param may be given
+	Values []string `yaml:"values,omitempty" json:"values,omitempty" mapstructure:"values"`
+}
+
+type TriggerParamConfigs []TriggerParamConfig
+
+func (params TriggerParamConfigs) Lookup(name string) (TriggerParamConfig, bool) {
+	for _, param := range params {
+		if param.Name == name {
+			return param, true
+		}
+	}
+
+	return TriggerParamConfig{}, false
+}
+
 // A PlanSequence corresponds to a chain of Compose plan, with an implicit
 // `on: [success]` after every Task plan.
 type PlanSequence []PlanConfig
@@ -266,6 +323,10 @@ type PlanConfig struct {
 	// inlined task config
 	TaskConfig *TaskConfig `yaml:"config,omitempty" json:"config,omitempty" mapstructure:"config"`
 
+	// corresponds to an Approval plan
+	// name of 'approval', e.g. deploy-to-prod
+	Approval string `yaml:"approval,omitempty" json:"approval,omitempty" mapstructure:"approval"`
+
 	// used by Get and Put for specifying params to the resource
 	Params Params `yaml:"params,omitempty" json:"params,omitempty" mapstructure:"params"`
 
@@ -323,6 +384,10 @@ func (config PlanConfig) Name() string {
 		return config.Task
 	}
 
+	if config.Approval != "" {
+		return config.Approval
+	}
+
 	return ""
 }
 
@@ -369,6 +434,79 @@ func (jobs JobConfigs) Lookup(name string) (JobConfig, bool) {
 	return JobConfig{}, false
 }
 
+// NotificationConfig is a URL to POST to when a build in the pipeline
+// changes status. Statuses and Jobs narrow down which builds it hears
+// about; when empty, it hears about every status of every job.
+type NotificationConfig struct {
+	Name     string        `yaml:"name" json:"name" mapstructure:"name"`
+	URL      string        `yaml:"url" json:"url" mapstructure:"url"`
+	Statuses []BuildStatus `yaml:"statuses,omitempty" json:"statuses,omitempty" mapstructure:"statuses"`
+	Jobs     []string      `yaml:"jobs,omitempty" json:"jobs,omitempty" mapstructure:"jobs"`
+}
+
+func (config NotificationConfig) Matches(jobName string, status BuildStatus) bool {
+	return matchesAny(config.Jobs, jobName) && matchesAnyStatus(config.Statuses, status)
+}
+
+func matchesAny(names []string, name string) bool {
+	if len(names) == 0 {
+		return true
+	}
+
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchesAnyStatus(statuses []BuildStatus, status BuildStatus) bool {
+	if len(statuses) == 0 {
+		return true
+	}
+
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+
+	return false
+}
+
+type NotificationConfigs []NotificationConfig
+
+// RemoteTriggerConfig triggers a job on another ATC, authenticating with
+// Token, when a build in the pipeline finishes. Unlike notifications it only
+// fires for succeeded builds unless Statuses says otherwise.
+//
+// Versions names resources whose versions the build used or produced; they
+// are handed to the other pipeline's resources with the same names.
+type RemoteTriggerConfig struct {
+	Name     string        `yaml:"name" json:"name" mapstructure:"name"`
+	URL      string        `yaml:"url" json:"url" mapstructure:"url"`
+	Team     string        `yaml:"team" json:"team" mapstructure:"team"`
+	Pipeline string        `yaml:"pipeline" json:"pipeline" mapstructure:"pipeline"`
+	Job      string        `yaml:"job" json:"job" mapstructure:"job"`
+	Token    string        `yaml:"token" json:"token" mapstructure:"token"`
+	Statuses []BuildStatus `yaml:"statuses,omitempty" json:"statuses,omitempty" mapstructure:"statuses"`
+	Jobs     []string      `yaml:"jobs,omitempty" json:"jobs,omitempty" mapstructure:"jobs"`
+	Versions []string      `yaml:"versions,omitempty" json:"versions,omitempty" mapstructure:"versions"`
+}
+
+func (config RemoteTriggerConfig) Matches(jobName string, status BuildStatus) bool {
+	statuses := config.Statuses
+	if len(statuses) == 0 {
+		statuses = []BuildStatus{StatusSucceeded}
+	}
+
+	return matchesAny(config.Jobs, jobName) && matchesAnyStatus(statuses, status)
+}
+
+type RemoteTriggerConfigs []RemoteTriggerConfig
+
 func (config Config) JobIsPublic(jobName string) (bool, error) {
 	job, found := config.Jobs.Lookup(jobName)
 	if !found {
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/concourse/atc"
+)
+
+// ResolveTriggerParams checks the params given when triggering the job by
+// hand against the ones it declares, filling in defaults for any that
+// weren't given. It returns nil if the job declares none and none were
+// given.
+func ResolveTriggerParams(job atc.JobConfig, given map[string]string) (map[string]string, error) {
+	errorMessages := []string{}
+
+	for name := range given {
+		if _, found := job.TriggerParams.Lookup(name); !found {
+			errorMessages = append(errorMessages, fmt.Sprintf("unknown param '%s'", name))
+		}
+	}
+
+	for _, param := range job.TriggerParams {
+		value, found := given[param.Name]
+		if !found {
+			if param.Required {
+				errorMessages = append(errorMessages, fmt.Sprintf("missing required param '%s'", param.Name))
+			}
+
+			continue
+		}
+
+		if len(param.Values) > 0 && !allowedValue(param, value) {
+			errorMessages = append(errorMessages, fmt.Sprintf(
+				"param '%s' must be one of %s (got '%s')",
+				param.Name,
+				strings.Join(param.Values, ", "),
+				value,
+			))
+		}
+	}
+
+	if len(errorMessages) > 0 {
+		sort.Strings(errorMessages)
+		return nil, compositeErr(errorMessages)
+	}
+
+	resolved := DefaultTriggerParams(job)
+
+	for name, value := range given {
+		if resolved == nil {
+			resolved = map[string]string{}
+		}
+
+		resolved[name] = value
+	}
+
+	return resolved, nil
+}
+
+// DefaultTriggerParams returns the defaults of the params the job declares,
+// for builds that weren't given any, e.g. because they were triggered by new
+// versions of their inputs. It returns nil if none of them have a default.
+func DefaultTriggerParams(job atc.JobConfig) map[string]string {
+	var defaults map[string]string
+
+	for _, param := range job.TriggerParams {
+		if param.Default == "" {
+			continue
+		}
+
+		if defaults == nil {
+			defaults = map[string]string{}
+		}
+
+		defaults[param.Name] = param.Default
+	}
+
+	return defaults
+}
+
+func allowedValue(param atc.TriggerParamConfig, value string) bool {
+	for _, allowed := range param.Values {
+		if allowed == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,92 @@
+package config_test
+
+import (
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/config"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Trigger params", func() {
+	var job atc.JobConfig
+
+	BeforeEach(func() {
+		job = atc.JobConfig{
+			Name: "some-job",
+			TriggerParams: atc.TriggerParamConfigs{
+				{Name: "environment", Required: true, Values: []string{"staging", "production"}},
+				{Name: "feature-flag", Default: "off"},
+			},
+		}
+	})
+
+	Describe("ResolveTriggerParams", func() {
+		It("fills in defaults for params that weren't given", func() {
+			resolved, err := config.ResolveTriggerParams(job, map[string]string{
+				"environment": "staging",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resolved).To(Equal(map[string]string{
+				"environment":  "staging",
+				"feature-flag": "off",
+			}))
+		})
+
+		It("prefers given params over defaults", func() {
+			resolved, err := config.ResolveTriggerParams(job, map[string]string{
+				"environment":  "production",
+				"feature-flag": "on",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resolved).To(Equal(map[string]string{
+				"environment":  "production",
+				"feature-flag": "on",
+			}))
+		})
+
+		It("rejects params that the job doesn't declare", func() {
+			_, err := config.ResolveTriggerParams(job, map[string]string{
+				"environment": "staging",
+				"bogus":       "value",
+			})
+			Expect(err).To(MatchError("unknown param 'bogus'"))
+		})
+
+		It("rejects a missing required param", func() {
+			_, err := config.ResolveTriggerParams(job, nil)
+			Expect(err).To(MatchError("missing required param 'environment'"))
+		})
+
+		It("rejects a value that isn't allowed", func() {
+			_, err := config.ResolveTriggerParams(job, map[string]string{
+				"environment": "dev",
+			})
+			Expect(err).To(MatchError("param 'environment' must be one of staging, production (got 'dev')"))
+		})
+
+		Context("when the job declares no params", func() {
+			BeforeEach(func() {
+				job.TriggerParams = nil
+			})
+
+			It("returns nil if none were given", func() {
+				resolved, err := config.ResolveTriggerParams(job, nil)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resolved).To(BeNil())
+			})
+		})
+	})
+
+	Describe("DefaultTriggerParams", func() {
+		It("returns the defaults of the declared params", func() {
+			Expect(config.DefaultTriggerParams(job)).To(Equal(map[string]string{
+				"feature-flag": "off",
+			}))
+		})
+
+		It("returns nil if none of them have a default", func() {
+			job.TriggerParams = job.TriggerParams[:1]
+			Expect(config.DefaultTriggerParams(job)).To(BeNil())
+		})
+	})
+})
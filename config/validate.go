@@ -3,11 +3,13 @@ package config
 import (
 	"errors"
 	"fmt"
+	"net/url"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/concourse/atc"
+	"github.com/concourse/atc/cron"
 )
 
 func formatErr(groupName string, err error) string {
@@ -58,9 +60,139 @@ func ValidateConfig(c atc.Config) ([]Warning, []string) {
 	}
 	warnings = append(warnings, jobWarnings...)
 
+	notificationsErr := validateNotifications(c)
+	if notificationsErr != nil {
+		errorMessages = append(errorMessages, formatErr("notifications", notificationsErr))
+	}
+
+	remoteTriggersErr := validateRemoteTriggers(c)
+	if remoteTriggersErr != nil {
+		errorMessages = append(errorMessages, formatErr("remote triggers", remoteTriggersErr))
+	}
+
 	return warnings, errorMessages
 }
 
+func validateNotifications(c atc.Config) error {
+	errorMessages := []string{}
+
+	names := map[string]int{}
+
+	for i, notification := range c.Notifications {
+		var identifier string
+		if notification.Name == "" {
+			identifier = fmt.Sprintf("notifications[%d]", i)
+		} else {
+			identifier = fmt.Sprintf("notifications.%s", notification.Name)
+		}
+
+		if other, exists := names[notification.Name]; exists {
+			errorMessages = append(errorMessages,
+				fmt.Sprintf(
+					"notifications[%d] and notifications[%d] have the same name ('%s')",
+					other, i, notification.Name))
+		} else if notification.Name != "" {
+			names[notification.Name] = i
+		}
+
+		if notification.Name == "" {
+			errorMessages = append(errorMessages, identifier+" has no name")
+		}
+
+		target, err := url.Parse(notification.URL)
+		if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+			errorMessages = append(errorMessages, identifier+" has an invalid url: '"+notification.URL+"'")
+		}
+
+		for _, status := range notification.Statuses {
+			switch status {
+			case atc.StatusStarted, atc.StatusSucceeded, atc.StatusFailed, atc.StatusErrored, atc.StatusAborted, atc.StatusTimedOut:
+			default:
+				errorMessages = append(errorMessages,
+					fmt.Sprintf("%s has unknown status '%s'", identifier, status))
+			}
+		}
+
+		for _, job := range notification.Jobs {
+			_, exists := c.Jobs.Lookup(job)
+			if !exists {
+				errorMessages = append(errorMessages,
+					fmt.Sprintf("%s has unknown job '%s'", identifier, job))
+			}
+		}
+	}
+
+	return compositeErr(errorMessages)
+}
+
+func validateRemoteTriggers(c atc.Config) error {
+	errorMessages := []string{}
+
+	names := map[string]int{}
+
+	for i, trigger := range c.RemoteTriggers {
+		var identifier string
+		if trigger.Name == "" {
+			identifier = fmt.Sprintf("remote_triggers[%d]", i)
+		} else {
+			identifier = fmt.Sprintf("remote_triggers.%s", trigger.Name)
+		}
+
+		if other, exists := names[trigger.Name]; exists {
+			errorMessages = append(errorMessages,
+				fmt.Sprintf(
+					"remote_triggers[%d] and remote_triggers[%d] have the same name ('%s')",
+					other, i, trigger.Name))
+		} else if trigger.Name != "" {
+			names[trigger.Name] = i
+		}
+
+		if trigger.Name == "" {
+			errorMessages = append(errorMessages, identifier+" has no name")
+		}
+
+		target, err := url.Parse(trigger.URL)
+		if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+			errorMessages = append(errorMessages, identifier+" has an invalid url: '"+trigger.URL+"'")
+		}
+
+		if trigger.Team == "" || trigger.Pipeline == "" || trigger.Job == "" {
+			errorMessages = append(errorMessages, identifier+" must name the team, pipeline and job to trigger")
+		}
+
+		if trigger.Token == "" {
+			errorMessages = append(errorMessages, identifier+" has no token")
+		}
+
+		for _, status := range trigger.Statuses {
+			switch status {
+			case atc.StatusSucceeded, atc.StatusFailed, atc.StatusErrored, atc.StatusAborted, atc.StatusTimedOut:
+			default:
+				errorMessages = append(errorMessages,
+					fmt.Sprintf("%s has unknown or unfinished status '%s'", identifier, status))
+			}
+		}
+
+		for _, job := range trigger.Jobs {
+			_, exists := c.Jobs.Lookup(job)
+			if !exists {
+				errorMessages = append(errorMessages,
+					fmt.Sprintf("%s has unknown job '%s'", identifier, job))
+			}
+		}
+
+		for _, resource := range trigger.Versions {
+			_, exists := c.Resources.Lookup(resource)
+			if !exists {
+				errorMessages = append(errorMessages,
+					fmt.Sprintf("%s passes versions of unknown resource '%s'", identifier, resource))
+			}
+		}
+	}
+
+	return compositeErr(errorMessages)
+}
+
 func validateGroups(c atc.Config) error {
 	errorMessages := []string{}
 
@@ -114,6 +246,15 @@ func validateResources(c atc.Config) error {
 		if resource.Type == "" {
 			errorMessages = append(errorMessages, identifier+" has no type")
 		}
+
+		if resource.CheckEvery != "" {
+			interval, err := time.ParseDuration(resource.CheckEvery)
+			if err != nil {
+				errorMessages = append(errorMessages, identifier+fmt.Sprintf(" has a check_every that could not be parsed ('%s')", resource.CheckEvery))
+			} else if interval <= 0 {
+				errorMessages = append(errorMessages, identifier+fmt.Sprintf(" has a check_every that is not positive ('%s')", resource.CheckEvery))
+			}
+		}
 	}
 
 	return compositeErr(errorMessages)
@@ -187,14 +328,147 @@ func validateJobs(c atc.Config) ([]Warning, error) {
 			)
 		}
 
+		if job.ArtifactRetention != nil {
+			if job.ArtifactRetention.Builds < 0 {
+				errorMessages = append(
+					errorMessages,
+					identifier+fmt.Sprintf(" has negative artifact_retention.builds: %d", job.ArtifactRetention.Builds),
+				)
+			}
+
+			if job.ArtifactRetention.Days < 0 {
+				errorMessages = append(
+					errorMessages,
+					identifier+fmt.Sprintf(" has negative artifact_retention.days: %d", job.ArtifactRetention.Days),
+				)
+			}
+		}
+
+		if job.Schedule != "" {
+			_, err := cron.Parse(job.Schedule)
+			if err != nil {
+				errorMessages = append(errorMessages, identifier+" has an invalid schedule: "+err.Error())
+			}
+		}
+
+		if job.BuildTimeout != "" {
+			timeout, err := time.ParseDuration(job.BuildTimeout)
+			if err != nil {
+				errorMessages = append(errorMessages, identifier+fmt.Sprintf(" has a build_timeout that could not be parsed ('%s')", job.BuildTimeout))
+			} else if timeout <= 0 {
+				errorMessages = append(errorMessages, identifier+fmt.Sprintf(" has a build_timeout that is not positive ('%s')", job.BuildTimeout))
+			}
+		}
+
+		errorMessages = append(errorMessages, validateTriggerParams(identifier, job.TriggerParams)...)
+
 		planWarnings, planErrMessages := validatePlan(c, identifier+".plan", atc.PlanConfig{Do: &job.Plan})
 		warnings = append(warnings, planWarnings...)
 		errorMessages = append(errorMessages, planErrMessages...)
 	}
 
+	errorMessages = append(errorMessages, validatePassedCycles(c)...)
+
 	return warnings, compositeErr(errorMessages)
 }
 
+// validatePassedCycles finds jobs whose passed constraints lead back to
+// themselves, as none of them could ever get a version that the others
+// have already passed.
+func validatePassedCycles(c atc.Config) []string {
+	errorMessages := []string{}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := map[string]int{}
+	path := []string{}
+
+	var visit func(jobName string)
+	visit = func(jobName string) {
+		state[jobName] = visiting
+		path = append(path, jobName)
+
+		jobConfig, _ := c.Jobs.Lookup(jobName)
+		for _, input := range JobInputs(jobConfig) {
+			for _, passed := range input.Passed {
+				if _, found := c.Jobs.Lookup(passed); !found {
+					continue
+				}
+
+				switch state[passed] {
+				case unvisited:
+					visit(passed)
+				case visiting:
+					var cycle []string
+					for i, name := range path {
+						if name == passed {
+							cycle = append(cycle, path[i:]...)
+							break
+						}
+					}
+
+					cycle = append(cycle, passed)
+
+					errorMessages = append(errorMessages, fmt.Sprintf(
+						"jobs.%s has passed constraints that lead back to itself (%s)",
+						passed,
+						strings.Join(cycle, " -> "),
+					))
+				}
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[jobName] = visited
+	}
+
+	for _, job := range c.Jobs {
+		if job.Name != "" && state[job.Name] == unvisited {
+			visit(job.Name)
+		}
+	}
+
+	return errorMessages
+}
+
+func validateTriggerParams(identifier string, params atc.TriggerParamConfigs) []string {
+	errorMessages := []string{}
+
+	names := map[string]int{}
+
+	for i, param := range params {
+		paramIdentifier := fmt.Sprintf("%s.trigger_params[%d]", identifier, i)
+
+		if param.Name == "" {
+			errorMessages = append(errorMessages, paramIdentifier+" has no name")
+			continue
+		}
+
+		if other, exists := names[param.Name]; exists {
+			errorMessages = append(errorMessages,
+				fmt.Sprintf(
+					"%s.trigger_params[%d] and %s.trigger_params[%d] have the same name ('%s')",
+					identifier, other, identifier, i, param.Name))
+		} else {
+			names[param.Name] = i
+		}
+
+		if param.Required && param.Default != "" {
+			errorMessages = append(errorMessages, paramIdentifier+" is required but also has a default")
+		}
+
+		if param.Default != "" && len(param.Values) > 0 && !allowedValue(param, param.Default) {
+			errorMessages = append(errorMessages, paramIdentifier+fmt.Sprintf(" has a default that is not one of its values ('%s')", param.Default))
+		}
+	}
+
+	return errorMessages
+}
+
 func doesAnyStepMatch(planSequence atc.PlanSequence, predicate func(step atc.PlanConfig) bool) bool {
 	for _, planStep := range planSequence {
 		if planStep.Aggregate != nil {
@@ -264,6 +538,10 @@ func validatePlan(c atc.Config, identifier string, plan atc.PlanConfig) ([]Warni
 		foundTypes.Find("task")
 	}
 
+	if plan.Approval != "" {
+		foundTypes.Find("approval")
+	}
+
 	if plan.Do != nil {
 		foundTypes.Find("do")
 	}
@@ -431,6 +709,14 @@ func validatePlan(c atc.Config, identifier string, plan atc.PlanConfig) ([]Warni
 			plan, identifier)...,
 		)
 
+	case plan.Approval != "":
+		identifier = fmt.Sprintf("%s.approval.%s", identifier, plan.Approval)
+
+		errorMessages = append(errorMessages, validateInapplicableFields(
+			[]string{"resource", "passed", "trigger", "privileged", "config", "file"},
+			plan, identifier)...,
+		)
+
 	case plan.Try != nil:
 		subIdentifier := fmt.Sprintf("%s.try", identifier)
 		planWarnings, planErrMessages := validatePlan(c, subIdentifier, *plan.Try)
@@ -180,6 +180,39 @@ var _ = Describe("ValidateConfig", func() {
 				))
 			})
 		})
+
+		Context("when a resource has a check_every that cannot be parsed", func() {
+			BeforeEach(func() {
+				config.Resources[0].CheckEvery = "often"
+			})
+
+			It("returns an error", func() {
+				Expect(errorMessages).To(HaveLen(1))
+				Expect(errorMessages[0]).To(ContainSubstring("invalid resources:"))
+				Expect(errorMessages[0]).To(ContainSubstring("resources.some-resource has a check_every that could not be parsed ('often')"))
+			})
+		})
+
+		Context("when a resource has a check_every that is not positive", func() {
+			BeforeEach(func() {
+				config.Resources[0].CheckEvery = "0s"
+			})
+
+			It("returns an error", func() {
+				Expect(errorMessages).To(HaveLen(1))
+				Expect(errorMessages[0]).To(ContainSubstring("resources.some-resource has a check_every that is not positive ('0s')"))
+			})
+		})
+
+		Context("when a resource has a valid check_every", func() {
+			BeforeEach(func() {
+				config.Resources[0].CheckEvery = "10m"
+			})
+
+			It("does not return an error", func() {
+				Expect(errorMessages).To(HaveLen(0))
+			})
+		})
 	})
 
 	Describe("invalid resource types", func() {
@@ -241,6 +274,176 @@ var _ = Describe("ValidateConfig", func() {
 		})
 	})
 
+	Describe("invalid notifications", func() {
+		var notification atc.NotificationConfig
+
+		BeforeEach(func() {
+			notification = atc.NotificationConfig{
+				Name:     "some-notification",
+				URL:      "https://hooks.example.com/some-path",
+				Statuses: []atc.BuildStatus{atc.StatusFailed, atc.StatusErrored},
+				Jobs:     []string{"some-job"},
+			}
+		})
+
+		Context("when the notification is valid", func() {
+			BeforeEach(func() {
+				config.Notifications = append(config.Notifications, notification)
+			})
+
+			It("returns no error", func() {
+				Expect(errorMessages).To(BeEmpty())
+			})
+		})
+
+		Context("when a notification has no name", func() {
+			BeforeEach(func() {
+				notification.Name = ""
+				config.Notifications = append(config.Notifications, notification)
+			})
+
+			It("returns an error", func() {
+				Expect(errorMessages).To(HaveLen(1))
+				Expect(errorMessages[0]).To(ContainSubstring("invalid notifications:"))
+				Expect(errorMessages[0]).To(ContainSubstring("notifications[0] has no name"))
+			})
+		})
+
+		Context("when two notifications have the same name", func() {
+			BeforeEach(func() {
+				config.Notifications = append(config.Notifications, notification, notification)
+			})
+
+			It("returns an error", func() {
+				Expect(errorMessages).To(HaveLen(1))
+				Expect(errorMessages[0]).To(ContainSubstring("notifications[0] and notifications[1] have the same name ('some-notification')"))
+			})
+		})
+
+		Context("when a notification's url is not http", func() {
+			BeforeEach(func() {
+				notification.URL = "ftp://example.com"
+				config.Notifications = append(config.Notifications, notification)
+			})
+
+			It("returns an error", func() {
+				Expect(errorMessages).To(HaveLen(1))
+				Expect(errorMessages[0]).To(ContainSubstring("notifications.some-notification has an invalid url: 'ftp://example.com'"))
+			})
+		})
+
+		Context("when a notification has an unknown status", func() {
+			BeforeEach(func() {
+				notification.Statuses = []atc.BuildStatus{"exploded"}
+				config.Notifications = append(config.Notifications, notification)
+			})
+
+			It("returns an error", func() {
+				Expect(errorMessages).To(HaveLen(1))
+				Expect(errorMessages[0]).To(ContainSubstring("notifications.some-notification has unknown status 'exploded'"))
+			})
+		})
+
+		Context("when a notification references a bogus job", func() {
+			BeforeEach(func() {
+				notification.Jobs = []string{"bogus-job"}
+				config.Notifications = append(config.Notifications, notification)
+			})
+
+			It("returns an error", func() {
+				Expect(errorMessages).To(HaveLen(1))
+				Expect(errorMessages[0]).To(ContainSubstring("notifications.some-notification has unknown job 'bogus-job'"))
+			})
+		})
+	})
+
+	Describe("invalid remote triggers", func() {
+		var trigger atc.RemoteTriggerConfig
+
+		BeforeEach(func() {
+			trigger = atc.RemoteTriggerConfig{
+				Name:     "deploy-elsewhere",
+				URL:      "https://ci.example.com",
+				Team:     "other-team",
+				Pipeline: "other-pipeline",
+				Job:      "other-job",
+				Token:    "some-token",
+				Jobs:     []string{"some-job"},
+				Versions: []string{"some-resource"},
+			}
+		})
+
+		Context("when the remote trigger is valid", func() {
+			BeforeEach(func() {
+				config.RemoteTriggers = append(config.RemoteTriggers, trigger)
+			})
+
+			It("returns no error", func() {
+				Expect(errorMessages).To(BeEmpty())
+			})
+		})
+
+		Context("when two remote triggers have the same name", func() {
+			BeforeEach(func() {
+				config.RemoteTriggers = append(config.RemoteTriggers, trigger, trigger)
+			})
+
+			It("returns an error", func() {
+				Expect(errorMessages).To(HaveLen(1))
+				Expect(errorMessages[0]).To(ContainSubstring("invalid remote triggers:"))
+				Expect(errorMessages[0]).To(ContainSubstring("remote_triggers[0] and remote_triggers[1] have the same name ('deploy-elsewhere')"))
+			})
+		})
+
+		Context("when a remote trigger does not say which job to trigger", func() {
+			BeforeEach(func() {
+				trigger.Pipeline = ""
+				config.RemoteTriggers = append(config.RemoteTriggers, trigger)
+			})
+
+			It("returns an error", func() {
+				Expect(errorMessages).To(HaveLen(1))
+				Expect(errorMessages[0]).To(ContainSubstring("remote_triggers.deploy-elsewhere must name the team, pipeline and job to trigger"))
+			})
+		})
+
+		Context("when a remote trigger has no token", func() {
+			BeforeEach(func() {
+				trigger.Token = ""
+				config.RemoteTriggers = append(config.RemoteTriggers, trigger)
+			})
+
+			It("returns an error", func() {
+				Expect(errorMessages).To(HaveLen(1))
+				Expect(errorMessages[0]).To(ContainSubstring("remote_triggers.deploy-elsewhere has no token"))
+			})
+		})
+
+		Context("when a remote trigger fires on builds starting", func() {
+			BeforeEach(func() {
+				trigger.Statuses = []atc.BuildStatus{atc.StatusStarted}
+				config.RemoteTriggers = append(config.RemoteTriggers, trigger)
+			})
+
+			It("returns an error", func() {
+				Expect(errorMessages).To(HaveLen(1))
+				Expect(errorMessages[0]).To(ContainSubstring("remote_triggers.deploy-elsewhere has unknown or unfinished status 'started'"))
+			})
+		})
+
+		Context("when a remote trigger passes versions of a bogus resource", func() {
+			BeforeEach(func() {
+				trigger.Versions = []string{"bogus-resource"}
+				config.RemoteTriggers = append(config.RemoteTriggers, trigger)
+			})
+
+			It("returns an error", func() {
+				Expect(errorMessages).To(HaveLen(1))
+				Expect(errorMessages[0]).To(ContainSubstring("remote_triggers.deploy-elsewhere passes versions of unknown resource 'bogus-resource'"))
+			})
+		})
+	})
+
 	Describe("validating a job", func() {
 		var job atc.JobConfig
 
@@ -276,6 +479,150 @@ var _ = Describe("ValidateConfig", func() {
 			})
 		})
 
+		Context("when a job keeps a negative number of builds' artifacts", func() {
+			BeforeEach(func() {
+				job.ArtifactRetention = &atc.ArtifactRetentionConfig{Builds: -1, Days: 7}
+				config.Jobs = append(config.Jobs, job)
+			})
+
+			It("returns an error", func() {
+				Expect(errorMessages).To(HaveLen(1))
+				Expect(errorMessages[0]).To(ContainSubstring("invalid jobs:"))
+				Expect(errorMessages[0]).To(ContainSubstring("jobs.some-other-job has negative artifact_retention.builds: -1"))
+			})
+		})
+
+		Context("when a job has an invalid schedule", func() {
+			BeforeEach(func() {
+				job.Schedule = "0 25 * * *"
+				config.Jobs = append(config.Jobs, job)
+			})
+
+			It("returns an error", func() {
+				Expect(errorMessages).To(HaveLen(1))
+				Expect(errorMessages[0]).To(ContainSubstring("invalid jobs:"))
+				Expect(errorMessages[0]).To(ContainSubstring("jobs.some-other-job has an invalid schedule: invalid hour: 25 is not between 0 and 23"))
+			})
+		})
+
+		Context("when a job has a build_timeout that cannot be parsed", func() {
+			BeforeEach(func() {
+				job.BuildTimeout = "forever"
+				config.Jobs = append(config.Jobs, job)
+			})
+
+			It("returns an error", func() {
+				Expect(errorMessages).To(HaveLen(1))
+				Expect(errorMessages[0]).To(ContainSubstring("invalid jobs:"))
+				Expect(errorMessages[0]).To(ContainSubstring("jobs.some-other-job has a build_timeout that could not be parsed ('forever')"))
+			})
+		})
+
+		Context("when a job has a build_timeout that is not positive", func() {
+			BeforeEach(func() {
+				job.BuildTimeout = "-1h"
+				config.Jobs = append(config.Jobs, job)
+			})
+
+			It("returns an error", func() {
+				Expect(errorMessages).To(HaveLen(1))
+				Expect(errorMessages[0]).To(ContainSubstring("jobs.some-other-job has a build_timeout that is not positive ('-1h')"))
+			})
+		})
+
+		Context("when a job has a valid build_timeout", func() {
+			BeforeEach(func() {
+				job.BuildTimeout = "1h30m"
+				config.Jobs = append(config.Jobs, job)
+			})
+
+			It("does not return an error", func() {
+				Expect(errorMessages).To(BeEmpty())
+			})
+		})
+
+		Context("when a job has trigger params", func() {
+			Context("that are valid", func() {
+				BeforeEach(func() {
+					job.TriggerParams = atc.TriggerParamConfigs{
+						{Name: "environment", Required: true, Values: []string{"staging", "production"}},
+						{Name: "feature-flag", Default: "off", Values: []string{"on", "off"}},
+					}
+					config.Jobs = append(config.Jobs, job)
+				})
+
+				It("does not return an error", func() {
+					Expect(errorMessages).To(BeEmpty())
+				})
+			})
+
+			Context("when one has no name", func() {
+				BeforeEach(func() {
+					job.TriggerParams = atc.TriggerParamConfigs{{Default: "staging"}}
+					config.Jobs = append(config.Jobs, job)
+				})
+
+				It("returns an error", func() {
+					Expect(errorMessages).To(HaveLen(1))
+					Expect(errorMessages[0]).To(ContainSubstring("jobs.some-other-job.trigger_params[0] has no name"))
+				})
+			})
+
+			Context("when two have the same name", func() {
+				BeforeEach(func() {
+					job.TriggerParams = atc.TriggerParamConfigs{
+						{Name: "environment"},
+						{Name: "environment"},
+					}
+					config.Jobs = append(config.Jobs, job)
+				})
+
+				It("returns an error", func() {
+					Expect(errorMessages).To(HaveLen(1))
+					Expect(errorMessages[0]).To(ContainSubstring("jobs.some-other-job.trigger_params[0] and jobs.some-other-job.trigger_params[1] have the same name ('environment')"))
+				})
+			})
+
+			Context("when one is required but has a default", func() {
+				BeforeEach(func() {
+					job.TriggerParams = atc.TriggerParamConfigs{
+						{Name: "environment", Required: true, Default: "staging"},
+					}
+					config.Jobs = append(config.Jobs, job)
+				})
+
+				It("returns an error", func() {
+					Expect(errorMessages).To(HaveLen(1))
+					Expect(errorMessages[0]).To(ContainSubstring("jobs.some-other-job.trigger_params[0] is required but also has a default"))
+				})
+			})
+
+			Context("when one has a default that is not one of its values", func() {
+				BeforeEach(func() {
+					job.TriggerParams = atc.TriggerParamConfigs{
+						{Name: "environment", Default: "dev", Values: []string{"staging", "production"}},
+					}
+					config.Jobs = append(config.Jobs, job)
+				})
+
+				It("returns an error", func() {
+					Expect(errorMessages).To(HaveLen(1))
+					Expect(errorMessages[0]).To(ContainSubstring("jobs.some-other-job.trigger_params[0] has a default that is not one of its values ('dev')"))
+				})
+			})
+		})
+
+		Context("when a job has a valid schedule", func() {
+			BeforeEach(func() {
+				job.Schedule = "*/15 9-17 * * mon-fri"
+				config.Jobs = append(config.Jobs, job)
+			})
+
+			It("does not return an error", func() {
+				Expect(errorMessages).To(BeEmpty())
+			})
+		})
+
 		Describe("plans", func() {
 			Context("when multiple actions are specified in the same plan", func() {
 				Context("when it's not just Get and Put", func() {
@@ -370,6 +717,24 @@ var _ = Describe("ValidateConfig", func() {
 				})
 			})
 
+			Context("when an approval plan has invalid fields specified", func() {
+				BeforeEach(func() {
+					job.Plan = append(job.Plan, atc.PlanConfig{
+						Approval:   "lol",
+						Resource:   "some-resource",
+						Privileged: true,
+					})
+
+					config.Jobs = append(config.Jobs, job)
+				})
+
+				It("returns an error", func() {
+					Expect(errorMessages).To(HaveLen(1))
+					Expect(errorMessages[0]).To(ContainSubstring("invalid jobs:"))
+					Expect(errorMessages[0]).To(ContainSubstring("jobs.some-other-job.plan[0].approval.lol has invalid fields specified (resource, privileged)"))
+				})
+			})
+
 			Context("when a task plan has neither a config or a path set", func() {
 				BeforeEach(func() {
 					job.Plan = append(job.Plan, atc.PlanConfig{
@@ -859,6 +1224,43 @@ var _ = Describe("ValidateConfig", func() {
 					Expect(errorMessages[0]).To(ContainSubstring("jobs.some-other-job.plan[0].get.some-resource.passed references a job ('some-empty-job') which doesn't interact with the resource ('some-resource')"))
 				})
 			})
+
+			Context("when jobs' passed constraints form a cycle", func() {
+				BeforeEach(func() {
+					config.Jobs[0].Plan = append(config.Jobs[0].Plan, atc.PlanConfig{
+						Get:    "some-resource",
+						Passed: []string{"some-other-job"},
+					})
+
+					job.Plan = append(job.Plan, atc.PlanConfig{
+						Get:    "some-resource",
+						Passed: []string{"some-job"},
+					})
+
+					config.Jobs = append(config.Jobs, job)
+				})
+
+				It("returns an error", func() {
+					Expect(errorMessages).To(HaveLen(1))
+					Expect(errorMessages[0]).To(ContainSubstring("jobs.some-job has passed constraints that lead back to itself (some-job -> some-other-job -> some-job)"))
+				})
+			})
+
+			Context("when a job's input has passed the job itself", func() {
+				BeforeEach(func() {
+					job.Plan = append(job.Plan, atc.PlanConfig{
+						Get:    "some-resource",
+						Passed: []string{"some-other-job"},
+					})
+
+					config.Jobs = append(config.Jobs, job)
+				})
+
+				It("returns an error", func() {
+					Expect(errorMessages).To(HaveLen(1))
+					Expect(errorMessages[0]).To(ContainSubstring("jobs.some-other-job has passed constraints that lead back to itself (some-other-job -> some-other-job)"))
+				})
+			})
 		})
 
 		Context("when two jobs have the same name", func() {
@@ -100,4 +100,26 @@ var _ = Describe("Config", func() {
 			})
 		})
 	})
+
+	Describe("ResourceTypes", func() {
+		Describe("WithRegistered", func() {
+			It("adds registered types the pipeline doesn't declare itself", func() {
+				types := ResourceTypes{
+					{Name: "some-type", Type: "docker-image", Source: Source{"repository": "pipeline/some-type"}},
+				}
+
+				merged := types.WithRegistered([]RegisteredResourceType{
+					{Name: "some-type", Image: "registered/some-type", Version: "1.0"},
+					{Name: "other-type", Image: "registered/other-type", Version: "2.0"},
+				})
+
+				Expect(merged).To(Equal(ResourceTypes{
+					{Name: "some-type", Type: "docker-image", Source: Source{"repository": "pipeline/some-type"}},
+					{Name: "other-type", Type: "docker-image", Source: Source{"repository": "registered/other-type", "tag": "2.0"}},
+				}))
+
+				Expect(types).To(HaveLen(1))
+			})
+		})
+	})
 })
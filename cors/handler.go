@@ -0,0 +1,75 @@
+package cors
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Policy says which cross-origin browser requests may be made. An origin of
+// "*" allows any origin.
+type Policy struct {
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	AllowCredentials bool
+}
+
+func (policy Policy) allowsOrigin(origin string) bool {
+	for _, allowed := range policy.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NewHandler adds CORS headers to responses to requests from allowed
+// origins, and answers their preflight requests itself, as the handler it
+// wraps only knows about the methods its routes are for. Requests from other
+// origins are passed through untouched, leaving it to the browser to refuse
+// them.
+func NewHandler(policy Policy, handler http.Handler) http.Handler {
+	return corsHandler{
+		policy:  policy,
+		handler: handler,
+
+		methods: strings.Join(policy.AllowedMethods, ", "),
+		headers: strings.Join(policy.AllowedHeaders, ", "),
+	}
+}
+
+type corsHandler struct {
+	policy  Policy
+	handler http.Handler
+
+	methods string
+	headers string
+}
+
+func (h corsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Vary", "Origin")
+
+	origin := r.Header.Get("Origin")
+	if origin == "" || !h.policy.allowsOrigin(origin) {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+
+	// the origin is echoed back rather than sending "*", as browsers refuse
+	// "*" for requests with credentials
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+
+	if h.policy.AllowCredentials {
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+	}
+
+	if r.Method == "OPTIONS" && r.Header.Get("Access-Control-Request-Method") != "" {
+		w.Header().Set("Access-Control-Allow-Methods", h.methods)
+		w.Header().Set("Access-Control-Allow-Headers", h.headers)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	h.handler.ServeHTTP(w, r)
+}
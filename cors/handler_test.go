@@ -0,0 +1,114 @@
+package cors_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/concourse/atc/cors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Handler", func() {
+	var (
+		policy  cors.Policy
+		handled bool
+
+		request  *http.Request
+		recorder *httptest.ResponseRecorder
+	)
+
+	BeforeEach(func() {
+		policy = cors.Policy{
+			AllowedOrigins: []string{"https://dashboard.example.com"},
+			AllowedMethods: []string{"GET", "PUT"},
+			AllowedHeaders: []string{"Authorization", "Content-Type"},
+		}
+
+		handled = false
+
+		var err error
+		request, err = http.NewRequest("GET", "/api/v1/builds", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		recorder = httptest.NewRecorder()
+	})
+
+	JustBeforeEach(func() {
+		cors.NewHandler(policy, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			handled = true
+			w.WriteHeader(http.StatusOK)
+		})).ServeHTTP(recorder, request)
+	})
+
+	Context("when the request is from an allowed origin", func() {
+		BeforeEach(func() {
+			request.Header.Set("Origin", "https://dashboard.example.com")
+		})
+
+		It("allows the origin and passes the request on", func() {
+			Expect(handled).To(BeTrue())
+			Expect(recorder.Header().Get("Access-Control-Allow-Origin")).To(Equal("https://dashboard.example.com"))
+			Expect(recorder.Header().Get("Vary")).To(Equal("Origin"))
+		})
+
+		It("does not allow credentials", func() {
+			Expect(recorder.Header().Get("Access-Control-Allow-Credentials")).To(BeEmpty())
+		})
+
+		Context("when credentials are allowed", func() {
+			BeforeEach(func() {
+				policy.AllowCredentials = true
+			})
+
+			It("says so", func() {
+				Expect(recorder.Header().Get("Access-Control-Allow-Credentials")).To(Equal("true"))
+			})
+		})
+
+		Context("when it is a preflight request", func() {
+			BeforeEach(func() {
+				request.Method = "OPTIONS"
+				request.Header.Set("Access-Control-Request-Method", "PUT")
+			})
+
+			It("answers it without passing it on", func() {
+				Expect(handled).To(BeFalse())
+				Expect(recorder.Code).To(Equal(http.StatusNoContent))
+				Expect(recorder.Header().Get("Access-Control-Allow-Origin")).To(Equal("https://dashboard.example.com"))
+				Expect(recorder.Header().Get("Access-Control-Allow-Methods")).To(Equal("GET, PUT"))
+				Expect(recorder.Header().Get("Access-Control-Allow-Headers")).To(Equal("Authorization, Content-Type"))
+			})
+		})
+	})
+
+	Context("when any origin is allowed", func() {
+		BeforeEach(func() {
+			policy.AllowedOrigins = []string{"*"}
+			request.Header.Set("Origin", "https://elsewhere.example.com")
+		})
+
+		It("allows the request's origin", func() {
+			Expect(recorder.Header().Get("Access-Control-Allow-Origin")).To(Equal("https://elsewhere.example.com"))
+		})
+	})
+
+	Context("when the request is from another origin", func() {
+		BeforeEach(func() {
+			request.Header.Set("Origin", "https://evil.example.com")
+		})
+
+		It("passes the request on without allowing the origin", func() {
+			Expect(handled).To(BeTrue())
+			Expect(recorder.Header().Get("Access-Control-Allow-Origin")).To(BeEmpty())
+		})
+	})
+
+	Context("when the request is not cross-origin", func() {
+		It("passes it on without CORS headers", func() {
+			Expect(handled).To(BeTrue())
+			Expect(recorder.Header().Get("Access-Control-Allow-Origin")).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,178 @@
+package creds
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+type credHubManager struct {
+	url          *url.URL
+	clientID     string
+	clientSecret string
+	pathPrefix   string
+
+	httpClient *http.Client
+
+	lock       sync.Mutex
+	authClient *http.Client
+}
+
+// NewCredHubManager reads credentials from CredHub, authenticating as the
+// given UAA client. The UAA to authenticate with is the one CredHub reports
+// from its /info endpoint.
+func NewCredHubManager(
+	url *url.URL,
+	clientID string,
+	clientSecret string,
+	pathPrefix string,
+	insecureSkipVerify bool,
+) Manager {
+	return &credHubManager{
+		url:          url,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		pathPrefix:   pathPrefix,
+
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					InsecureSkipVerify: insecureSkipVerify,
+				},
+			},
+		},
+	}
+}
+
+func (manager *credHubManager) Variables(teamName string, pipelineName string) Variables {
+	return credHubVariables{
+		manager:      manager,
+		teamName:     teamName,
+		pipelineName: pipelineName,
+	}
+}
+
+func (manager *credHubManager) endpoint(endpointPath string) url.URL {
+	endpoint := *manager.url
+	endpoint.Path = strings.TrimRight(endpoint.Path, "/") + endpointPath
+	return endpoint
+}
+
+// client authenticates with CredHub's UAA the first time it's needed, so that
+// the ATC can start while CredHub is unavailable.
+func (manager *credHubManager) client() (*http.Client, error) {
+	manager.lock.Lock()
+	defer manager.lock.Unlock()
+
+	if manager.authClient != nil {
+		return manager.authClient, nil
+	}
+
+	infoURL := manager.endpoint("/info")
+
+	response, err := manager.httpClient.Get(infoURL.String())
+	if err != nil {
+		return nil, err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, unexpectedResponse("credhub", response)
+	}
+
+	var info struct {
+		AuthServer struct {
+			URL string `json:"url"`
+		} `json:"auth-server"`
+	}
+
+	err = json.NewDecoder(response.Body).Decode(&info)
+	if err != nil {
+		return nil, err
+	}
+
+	config := clientcredentials.Config{
+		ClientID:     manager.clientID,
+		ClientSecret: manager.clientSecret,
+		TokenURL:     strings.TrimRight(info.AuthServer.URL, "/") + "/oauth/token",
+	}
+
+	ctx := context.WithValue(oauth2.NoContext, oauth2.HTTPClient, manager.httpClient)
+
+	manager.authClient = config.Client(ctx)
+
+	return manager.authClient, nil
+}
+
+func (manager *credHubManager) read(name string) (interface{}, bool, error) {
+	client, err := manager.client()
+	if err != nil {
+		return nil, false, err
+	}
+
+	dataURL := manager.endpoint("/api/v1/data")
+	dataURL.RawQuery = url.Values{
+		"name":    {name},
+		"current": {"true"},
+	}.Encode()
+
+	response, err := client.Get(dataURL.String())
+	if err != nil {
+		return nil, false, err
+	}
+
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, false, nil
+	default:
+		return nil, false, unexpectedResponse("credhub", response)
+	}
+
+	var credentials struct {
+		Data []struct {
+			Value interface{} `json:"value"`
+		} `json:"data"`
+	}
+
+	err = json.NewDecoder(response.Body).Decode(&credentials)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if len(credentials.Data) == 0 {
+		return nil, false, nil
+	}
+
+	return credentials.Data[0].Value, true, nil
+}
+
+type credHubVariables struct {
+	manager *credHubManager
+
+	teamName     string
+	pipelineName string
+}
+
+func (variables credHubVariables) Get(name string) (interface{}, bool, error) {
+	for _, credentialName := range lookupPaths(variables.manager.pathPrefix, variables.teamName, variables.pipelineName, name) {
+		value, found, err := variables.manager.read(credentialName)
+		if err != nil || found {
+			return value, found, err
+		}
+	}
+
+	return nil, false, nil
+}
@@ -0,0 +1,119 @@
+package creds_test
+
+import (
+	"net/http"
+	"net/url"
+
+	. "github.com/concourse/atc/creds"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("CredHub", func() {
+	var (
+		credHubServer *ghttp.Server
+		uaaServer     *ghttp.Server
+
+		variables Variables
+	)
+
+	BeforeEach(func() {
+		credHubServer = ghttp.NewServer()
+		uaaServer = ghttp.NewServer()
+
+		credHubURL, err := url.Parse(credHubServer.URL())
+		Expect(err).NotTo(HaveOccurred())
+
+		variables = NewCredHubManager(credHubURL, "some-client", "some-client-secret", "/concourse", false).Variables("some-team", "some-pipeline")
+
+		credHubServer.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest("GET", "/info"),
+			ghttp.RespondWith(http.StatusOK, `{"auth-server":{"url":"`+uaaServer.URL()+`"}}`),
+		))
+
+		uaaServer.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest("POST", "/oauth/token"),
+			ghttp.VerifyBasicAuth("some-client", "some-client-secret"),
+			ghttp.RespondWith(http.StatusOK, `{"access_token":"some-access-token","token_type":"bearer","expires_in":3600}`, http.Header{
+				"Content-Type": {"application/json"},
+			}),
+		))
+	})
+
+	AfterEach(func() {
+		credHubServer.Close()
+		uaaServer.Close()
+	})
+
+	Context("when the credential is under the pipeline", func() {
+		BeforeEach(func() {
+			credHubServer.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/api/v1/data", "current=true&name=%2Fconcourse%2Fsome-team%2Fsome-pipeline%2Fsome-credential"),
+				ghttp.VerifyHeaderKV("Authorization", "Bearer some-access-token"),
+				ghttp.RespondWith(http.StatusOK, `{"data":[{"type":"value","value":"some-secret"}]}`),
+			))
+		})
+
+		It("returns its current value, authenticating with UAA", func() {
+			value, found, err := variables.Get("some-credential")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(value).To(Equal("some-secret"))
+		})
+	})
+
+	Context("when the credential is only under the team", func() {
+		BeforeEach(func() {
+			credHubServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/v1/data", "current=true&name=%2Fconcourse%2Fsome-team%2Fsome-pipeline%2Fsome-credential"),
+					ghttp.RespondWith(http.StatusNotFound, `{"error":"not found"}`),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/v1/data", "current=true&name=%2Fconcourse%2Fsome-team%2Fsome-credential"),
+					ghttp.RespondWith(http.StatusOK, `{"data":[{"type":"user","value":{"username":"some-user","password":"some-password"}}]}`),
+				),
+			)
+		})
+
+		It("returns the team's", func() {
+			value, found, err := variables.Get("some-credential")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(value).To(Equal(map[string]interface{}{
+				"username": "some-user",
+				"password": "some-password",
+			}))
+		})
+	})
+
+	Context("when the credential doesn't exist", func() {
+		BeforeEach(func() {
+			credHubServer.AppendHandlers(
+				ghttp.RespondWith(http.StatusNotFound, `{"error":"not found"}`),
+				ghttp.RespondWith(http.StatusNotFound, `{"error":"not found"}`),
+			)
+		})
+
+		It("returns false", func() {
+			_, found, err := variables.Get("some-credential")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+	})
+
+	Context("when credhub fails", func() {
+		BeforeEach(func() {
+			credHubServer.AppendHandlers(
+				ghttp.RespondWith(http.StatusInternalServerError, `{"error":"oh no"}`),
+			)
+		})
+
+		It("returns an error", func() {
+			_, _, err := variables.Get("some-credential")
+			Expect(err).To(MatchError(ContainSubstring("oh no")))
+		})
+	})
+})
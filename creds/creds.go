@@ -0,0 +1,49 @@
+package creds
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strings"
+)
+
+//go:generate counterfeiter . Manager
+
+// A Manager looks up the credentials that pipelines refer to as ((name)), in
+// a secrets store such as Vault or CredHub.
+type Manager interface {
+	Variables(teamName string, pipelineName string) Variables
+}
+
+//go:generate counterfeiter . Variables
+
+// Variables are the credentials available to one pipeline's builds.
+type Variables interface {
+	Get(name string) (interface{}, bool, error)
+}
+
+// StaticVariables are variables known up front, e.g. the instance vars of a
+// pipeline instance.
+type StaticVariables map[string]interface{}
+
+func (variables StaticVariables) Get(name string) (interface{}, bool, error) {
+	value, found := variables[name]
+	return value, found, nil
+}
+
+// lookupPaths are where a pipeline's credential is looked for, in order:
+// first under the pipeline, so that it can override one shared by the team,
+// and then under the team.
+func lookupPaths(prefix string, teamName string, pipelineName string, name string) []string {
+	return []string{
+		path.Join(prefix, teamName, pipelineName, name),
+		path.Join(prefix, teamName, name),
+	}
+}
+
+func unexpectedResponse(store string, response *http.Response) error {
+	body, _ := ioutil.ReadAll(io.LimitReader(response.Body, 1024))
+	return fmt.Errorf("unexpected response from %s: %s: %s", store, response.Status, strings.TrimSpace(string(body)))
+}
@@ -0,0 +1,488 @@
+package creds
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/concourse/atc"
+)
+
+// Redacted is what credentials are replaced with wherever they'd otherwise be
+// shown, e.g. in a build's events.
+const Redacted = "((redacted))"
+
+var credentialRegex = regexp.MustCompile(`\(\(([-/\.\w]+)\)\)`)
+
+// UndefinedCredentialsError is returned when a plan refers to credentials
+// that can't be found.
+type UndefinedCredentialsError struct {
+	Names []string
+}
+
+func (err UndefinedCredentialsError) Error() string {
+	return fmt.Sprintf("undefined credentials: %s", strings.Join(err.Names, ", "))
+}
+
+// An Interpolator replaces the ((name))s in a build's plan with credentials,
+// remembering what it replaced them with so that they can be redacted from
+// the build's events.
+type Interpolator struct {
+	variables Variables
+
+	// credentials are only looked up once, however often they're used
+	credentials map[string]interface{}
+
+	resolved  map[string]bool
+	undefined map[string]bool
+}
+
+func NewInterpolator(variables Variables) *Interpolator {
+	return &Interpolator{
+		variables: variables,
+
+		credentials: map[string]interface{}{},
+
+		resolved:  map[string]bool{},
+		undefined: map[string]bool{},
+	}
+}
+
+// Plan interpolates the sources and params of every step in the plan, along
+// with the image resource and params of inline task configs. The config of a
+// task loaded from a file isn't known until it runs, so only its params are.
+func (interpolator *Interpolator) Plan(plan *atc.Plan) error {
+	err := atc.NewPlanTraversal(interpolator.step).Traverse(plan)
+	if err != nil {
+		return err
+	}
+
+	if len(interpolator.undefined) > 0 {
+		names := []string{}
+		for name := range interpolator.undefined {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		return UndefinedCredentialsError{Names: names}
+	}
+
+	return nil
+}
+
+// Config fills in the ((name))s of a whole config. Unlike Plan, names that
+// aren't defined are left as they are rather than failing, as they may yet be
+// credentials that are filled in when the config's builds run.
+func (interpolator *Interpolator) Config(config atc.Config) (atc.Config, error) {
+	payload, err := json.Marshal(config)
+	if err != nil {
+		return atc.Config{}, err
+	}
+
+	var value interface{}
+	err = json.Unmarshal(payload, &value)
+	if err != nil {
+		return atc.Config{}, err
+	}
+
+	evaluated, err := interpolator.evaluate(value)
+	if err != nil {
+		return atc.Config{}, err
+	}
+
+	payload, err = json.Marshal(evaluated)
+	if err != nil {
+		return atc.Config{}, err
+	}
+
+	var interpolated atc.Config
+	err = json.Unmarshal(payload, &interpolated)
+	if err != nil {
+		return atc.Config{}, err
+	}
+
+	return interpolated, nil
+}
+
+// Resolved returns every string a credential was resolved to, for redaction.
+// Credentials holding structured values are broken down into their strings;
+// numbers and booleans aren't redacted, as they'd match far too much.
+func (interpolator *Interpolator) Resolved() []string {
+	values := []string{}
+	for value := range interpolator.resolved {
+		values = append(values, value)
+	}
+
+	sort.Strings(values)
+
+	return values
+}
+
+func (interpolator *Interpolator) step(plan *atc.Plan) error {
+	var err error
+
+	switch {
+	case plan.Get != nil:
+		get := *plan.Get
+
+		get.Source, err = interpolator.source(get.Source)
+		if err != nil {
+			return err
+		}
+
+		get.Params, err = interpolator.params(get.Params)
+		if err != nil {
+			return err
+		}
+
+		get.ResourceTypes, err = interpolator.resourceTypes(get.ResourceTypes)
+		if err != nil {
+			return err
+		}
+
+		plan.Get = &get
+
+	case plan.Put != nil:
+		put := *plan.Put
+
+		put.Source, err = interpolator.source(put.Source)
+		if err != nil {
+			return err
+		}
+
+		put.Params, err = interpolator.params(put.Params)
+		if err != nil {
+			return err
+		}
+
+		put.ResourceTypes, err = interpolator.resourceTypes(put.ResourceTypes)
+		if err != nil {
+			return err
+		}
+
+		plan.Put = &put
+
+	case plan.DependentGet != nil:
+		dependentGet := *plan.DependentGet
+
+		dependentGet.Source, err = interpolator.source(dependentGet.Source)
+		if err != nil {
+			return err
+		}
+
+		dependentGet.Params, err = interpolator.params(dependentGet.Params)
+		if err != nil {
+			return err
+		}
+
+		dependentGet.ResourceTypes, err = interpolator.resourceTypes(dependentGet.ResourceTypes)
+		if err != nil {
+			return err
+		}
+
+		plan.DependentGet = &dependentGet
+
+	case plan.Task != nil:
+		task := *plan.Task
+
+		task.Params, err = interpolator.params(task.Params)
+		if err != nil {
+			return err
+		}
+
+		task.ResourceTypes, err = interpolator.resourceTypes(task.ResourceTypes)
+		if err != nil {
+			return err
+		}
+
+		if task.Config != nil {
+			config := *task.Config
+
+			if config.ImageResource != nil {
+				imageResource := *config.ImageResource
+
+				imageResource.Source, err = interpolator.source(imageResource.Source)
+				if err != nil {
+					return err
+				}
+
+				config.ImageResource = &imageResource
+			}
+
+			if config.Params != nil {
+				params := map[string]string{}
+				for name, value := range config.Params {
+					params[name], err = interpolator.string(value)
+					if err != nil {
+						return err
+					}
+				}
+
+				config.Params = params
+			}
+
+			task.Config = &config
+		}
+
+		plan.Task = &task
+	}
+
+	return nil
+}
+
+func (interpolator *Interpolator) source(source atc.Source) (atc.Source, error) {
+	if source == nil {
+		return nil, nil
+	}
+
+	evaluated, err := interpolator.evaluate(map[string]interface{}(source))
+	if err != nil {
+		return nil, err
+	}
+
+	return atc.Source(evaluated.(map[string]interface{})), nil
+}
+
+func (interpolator *Interpolator) params(params atc.Params) (atc.Params, error) {
+	if params == nil {
+		return nil, nil
+	}
+
+	evaluated, err := interpolator.evaluate(map[string]interface{}(params))
+	if err != nil {
+		return nil, err
+	}
+
+	return atc.Params(evaluated.(map[string]interface{})), nil
+}
+
+func (interpolator *Interpolator) resourceTypes(resourceTypes atc.ResourceTypes) (atc.ResourceTypes, error) {
+	if resourceTypes == nil {
+		return nil, nil
+	}
+
+	interpolated := make(atc.ResourceTypes, len(resourceTypes))
+	for i, resourceType := range resourceTypes {
+		source, err := interpolator.source(resourceType.Source)
+		if err != nil {
+			return nil, err
+		}
+
+		resourceType.Source = source
+		interpolated[i] = resourceType
+	}
+
+	return interpolated, nil
+}
+
+func (interpolator *Interpolator) string(value string) (string, error) {
+	evaluated, err := interpolator.evaluate(value)
+	if err != nil {
+		return "", err
+	}
+
+	switch evaluated := evaluated.(type) {
+	case string:
+		return evaluated, nil
+	case nil:
+		// undefined; reported once the whole plan has been interpolated
+		return value, nil
+	}
+
+	return "", fmt.Errorf("credential in '%s' is not a string", value)
+}
+
+// evaluate returns a copy of the value with credentials filled in, leaving
+// the original as it was; it's often shared with the pipeline's config.
+func (interpolator *Interpolator) evaluate(value interface{}) (interface{}, error) {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		evaluated := make(map[string]interface{}, len(value))
+		for key, child := range value {
+			var err error
+			evaluated[key], err = interpolator.evaluate(child)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return evaluated, nil
+
+	case map[interface{}]interface{}:
+		evaluated := make(map[interface{}]interface{}, len(value))
+		for key, child := range value {
+			var err error
+			evaluated[key], err = interpolator.evaluate(child)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return evaluated, nil
+
+	case []interface{}:
+		evaluated := make([]interface{}, len(value))
+		for i, child := range value {
+			var err error
+			evaluated[i], err = interpolator.evaluate(child)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return evaluated, nil
+
+	case string:
+		return interpolator.evaluateString(value)
+
+	default:
+		return value, nil
+	}
+}
+
+func (interpolator *Interpolator) evaluateString(value string) (interface{}, error) {
+	// a string that's nothing but a credential becomes the credential itself,
+	// so that it may hold e.g. a map of keys
+	if match := credentialRegex.FindStringSubmatch(value); match != nil && match[0] == value {
+		credential, err := interpolator.lookup(match[1])
+		if err != nil {
+			return nil, err
+		}
+
+		if interpolator.undefined[match[1]] {
+			return value, nil
+		}
+
+		return credential, nil
+	}
+
+	var lookupErr error
+
+	interpolated := credentialRegex.ReplaceAllStringFunc(value, func(match string) string {
+		if lookupErr != nil {
+			return match
+		}
+
+		name := credentialRegex.FindStringSubmatch(match)[1]
+
+		credential, err := interpolator.lookup(name)
+		if err != nil {
+			lookupErr = err
+			return match
+		}
+
+		switch credential.(type) {
+		case nil:
+			return match
+		case string, int, int64, float64, bool:
+			return fmt.Sprintf("%v", credential)
+		default:
+			lookupErr = fmt.Errorf("credential '%s' is not a string, so it can't be interpolated into one", name)
+			return match
+		}
+	})
+	if lookupErr != nil {
+		return nil, lookupErr
+	}
+
+	return interpolated, nil
+}
+
+func (interpolator *Interpolator) lookup(name string) (interface{}, error) {
+	if credential, found := interpolator.credentials[name]; found {
+		return credential, nil
+	}
+
+	if interpolator.undefined[name] {
+		return nil, nil
+	}
+
+	credential, found, err := interpolator.variables.Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		interpolator.undefined[name] = true
+		return nil, nil
+	}
+
+	interpolator.credentials[name] = credential
+	interpolator.remember(credential)
+
+	return credential, nil
+}
+
+func (interpolator *Interpolator) remember(credential interface{}) {
+	switch credential := credential.(type) {
+	case string:
+		if credential != "" {
+			interpolator.resolved[credential] = true
+		}
+
+	case map[string]interface{}:
+		for _, child := range credential {
+			interpolator.remember(child)
+		}
+
+	case map[interface{}]interface{}:
+		for _, child := range credential {
+			interpolator.remember(child)
+		}
+
+	case []interface{}:
+		for _, child := range credential {
+			interpolator.remember(child)
+		}
+	}
+}
+
+// References returns the names of the credentials the config refers to,
+// sorted and without duplicates.
+func References(config atc.Config) ([]string, error) {
+	payload, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+
+	found := map[string]bool{}
+	names := []string{}
+	for _, match := range credentialRegex.FindAllStringSubmatch(string(payload), -1) {
+		if !found[match[1]] {
+			found[match[1]] = true
+			names = append(names, match[1])
+		}
+	}
+
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// Redact replaces each of the values wherever it appears in the text,
+// longest first so that a value containing another is redacted whole.
+func Redact(text string, values []string) string {
+	sorted := make([]string, len(values))
+	copy(sorted, values)
+
+	sort.Sort(byLength(sorted))
+
+	for _, value := range sorted {
+		if value == "" {
+			continue
+		}
+
+		text = strings.Replace(text, value, Redacted, -1)
+	}
+
+	return text
+}
+
+type byLength []string
+
+func (values byLength) Len() int           { return len(values) }
+func (values byLength) Less(i, j int) bool { return len(values[i]) > len(values[j]) }
+func (values byLength) Swap(i, j int)      { values[i], values[j] = values[j], values[i] }
@@ -0,0 +1,111 @@
+package creds
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type vaultManager struct {
+	url        *url.URL
+	token      string
+	pathPrefix string
+
+	httpClient *http.Client
+}
+
+// NewVaultManager reads credentials from Vault's generic secret backend,
+// authenticating with the given token. A secret with a single 'value' field
+// is that value; any other secret is all of its fields.
+func NewVaultManager(
+	url *url.URL,
+	token string,
+	pathPrefix string,
+	insecureSkipVerify bool,
+) Manager {
+	return &vaultManager{
+		url:        url,
+		token:      token,
+		pathPrefix: pathPrefix,
+
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					InsecureSkipVerify: insecureSkipVerify,
+				},
+			},
+		},
+	}
+}
+
+func (manager *vaultManager) Variables(teamName string, pipelineName string) Variables {
+	return vaultVariables{
+		manager:      manager,
+		teamName:     teamName,
+		pipelineName: pipelineName,
+	}
+}
+
+func (manager *vaultManager) read(secretPath string) (interface{}, bool, error) {
+	secretURL := *manager.url
+	secretURL.Path = strings.TrimRight(secretURL.Path, "/") + "/v1/" + strings.TrimLeft(secretPath, "/")
+
+	request, err := http.NewRequest("GET", secretURL.String(), nil)
+	if err != nil {
+		return nil, false, err
+	}
+
+	request.Header.Set("X-Vault-Token", manager.token)
+
+	response, err := manager.httpClient.Do(request)
+	if err != nil {
+		return nil, false, err
+	}
+
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, false, nil
+	default:
+		return nil, false, unexpectedResponse("vault", response)
+	}
+
+	var secret struct {
+		Data map[string]interface{} `json:"data"`
+	}
+
+	err = json.NewDecoder(response.Body).Decode(&secret)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if value, found := secret.Data["value"]; found && len(secret.Data) == 1 {
+		return value, true, nil
+	}
+
+	return secret.Data, true, nil
+}
+
+type vaultVariables struct {
+	manager *vaultManager
+
+	teamName     string
+	pipelineName string
+}
+
+func (variables vaultVariables) Get(name string) (interface{}, bool, error) {
+	for _, secretPath := range lookupPaths(variables.manager.pathPrefix, variables.teamName, variables.pipelineName, name) {
+		value, found, err := variables.manager.read(secretPath)
+		if err != nil || found {
+			return value, found, err
+		}
+	}
+
+	return nil, false, nil
+}
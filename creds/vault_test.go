@@ -0,0 +1,103 @@
+package creds_test
+
+import (
+	"net/http"
+	"net/url"
+
+	. "github.com/concourse/atc/creds"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Vault", func() {
+	var (
+		server *ghttp.Server
+
+		variables Variables
+	)
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+
+		vaultURL, err := url.Parse(server.URL())
+		Expect(err).NotTo(HaveOccurred())
+
+		variables = NewVaultManager(vaultURL, "some-token", "/concourse", false).Variables("some-team", "some-pipeline")
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Context("when the credential is under the pipeline", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/v1/concourse/some-team/some-pipeline/some-credential"),
+				ghttp.VerifyHeaderKV("X-Vault-Token", "some-token"),
+				ghttp.RespondWith(http.StatusOK, `{"data":{"value":"some-secret"}}`),
+			))
+		})
+
+		It("returns its value", func() {
+			value, found, err := variables.Get("some-credential")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(value).To(Equal("some-secret"))
+		})
+	})
+
+	Context("when the credential is only under the team", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/v1/concourse/some-team/some-pipeline/some-credential"),
+					ghttp.RespondWith(http.StatusNotFound, `{"errors":[]}`),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/v1/concourse/some-team/some-credential"),
+					ghttp.RespondWith(http.StatusOK, `{"data":{"username":"some-user","password":"some-password"}}`),
+				),
+			)
+		})
+
+		It("returns all of its fields when it has more than a value", func() {
+			value, found, err := variables.Get("some-credential")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(value).To(Equal(map[string]interface{}{
+				"username": "some-user",
+				"password": "some-password",
+			}))
+		})
+	})
+
+	Context("when the credential doesn't exist", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.RespondWith(http.StatusNotFound, `{"errors":[]}`),
+				ghttp.RespondWith(http.StatusNotFound, `{"errors":[]}`),
+			)
+		})
+
+		It("returns false", func() {
+			_, found, err := variables.Get("some-credential")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+	})
+
+	Context("when vault fails", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.RespondWith(http.StatusForbidden, `{"errors":["permission denied"]}`),
+			)
+		})
+
+		It("returns an error", func() {
+			_, _, err := variables.Get("some-credential")
+			Expect(err).To(MatchError(ContainSubstring("permission denied")))
+		})
+	})
+})
@@ -0,0 +1,236 @@
+// Package cron parses the cron expressions that jobs can be scheduled with.
+//
+// Expressions have the usual five fields (minute, hour, day of month, month
+// and day of week), each of which may be a '*', a value, a range, or a
+// comma-separated list of them, optionally stepped with '/'. Months and days
+// of the week may also be given by their three letter names. The shorthands
+// @yearly, @monthly, @weekly, @daily and @hourly are also understood.
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron expression.
+type Schedule struct {
+	minute uint64
+	hour   uint64
+	dom    uint64
+	month  uint64
+	dow    uint64
+
+	// as with cron, when both days are restricted a day matching either of
+	// them will do
+	domRestricted bool
+	dowRestricted bool
+}
+
+type bounds struct {
+	min   int
+	max   int
+	names map[string]int
+}
+
+var (
+	minuteBounds = bounds{0, 59, nil}
+	hourBounds   = bounds{0, 23, nil}
+	domBounds    = bounds{1, 31, nil}
+	monthBounds  = bounds{1, 12, map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+
+	// 7 is also Sunday, and is folded into 0 once parsed
+	dowBounds = bounds{0, 7, map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var shorthands = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Parse parses a cron expression.
+func Parse(expression string) (Schedule, error) {
+	expression = strings.TrimSpace(expression)
+
+	if expanded, found := shorthands[expression]; found {
+		expression = expanded
+	}
+
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return Schedule{}, fmt.Errorf("expected 5 fields, got %d", len(fields))
+	}
+
+	var schedule Schedule
+	var err error
+
+	schedule.minute, err = parseField(fields[0], minuteBounds)
+	if err != nil {
+		return Schedule{}, fmt.Errorf("invalid minute: %s", err)
+	}
+
+	schedule.hour, err = parseField(fields[1], hourBounds)
+	if err != nil {
+		return Schedule{}, fmt.Errorf("invalid hour: %s", err)
+	}
+
+	schedule.dom, err = parseField(fields[2], domBounds)
+	if err != nil {
+		return Schedule{}, fmt.Errorf("invalid day of month: %s", err)
+	}
+
+	schedule.month, err = parseField(fields[3], monthBounds)
+	if err != nil {
+		return Schedule{}, fmt.Errorf("invalid month: %s", err)
+	}
+
+	schedule.dow, err = parseField(fields[4], dowBounds)
+	if err != nil {
+		return Schedule{}, fmt.Errorf("invalid day of week: %s", err)
+	}
+
+	if schedule.dow&(1<<7) != 0 {
+		schedule.dow = schedule.dow&^(1<<7) | 1<<0
+	}
+
+	schedule.domRestricted = !strings.HasPrefix(fields[2], "*")
+	schedule.dowRestricted = !strings.HasPrefix(fields[4], "*")
+
+	return schedule, nil
+}
+
+func parseField(field string, b bounds) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(field, ",") {
+		rangePart := part
+		step := 1
+
+		if slash := strings.Index(part, "/"); slash != -1 {
+			var err error
+			step, err = strconv.Atoi(part[slash+1:])
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("bad step in '%s'", part)
+			}
+
+			rangePart = part[:slash]
+		}
+
+		var low, high int
+
+		if rangePart == "*" {
+			low, high = b.min, b.max
+		} else if dash := strings.Index(rangePart, "-"); dash != -1 {
+			var err error
+			low, err = parseValue(rangePart[:dash], b)
+			if err != nil {
+				return 0, err
+			}
+
+			high, err = parseValue(rangePart[dash+1:], b)
+			if err != nil {
+				return 0, err
+			}
+
+			if high < low {
+				return 0, fmt.Errorf("range '%s' is backwards", rangePart)
+			}
+		} else {
+			var err error
+			low, err = parseValue(rangePart, b)
+			if err != nil {
+				return 0, err
+			}
+
+			// a stepped single value runs to the end of the field, e.g. 5/15
+			// in the minutes is 5, 20, 35 and 50
+			high = low
+			if step != 1 {
+				high = b.max
+			}
+		}
+
+		for value := low; value <= high; value += step {
+			bits |= 1 << uint(value)
+		}
+	}
+
+	return bits, nil
+}
+
+func parseValue(value string, b bounds) (int, error) {
+	if named, found := b.names[strings.ToLower(value)]; found {
+		return named, nil
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("'%s' is not a number", value)
+	}
+
+	if parsed < b.min || parsed > b.max {
+		return 0, fmt.Errorf("%d is not between %d and %d", parsed, b.min, b.max)
+	}
+
+	return parsed, nil
+}
+
+// searchLimit bounds how far ahead Next looks, so that expressions that can
+// never match (e.g. the 30th of February) don't loop forever.
+const searchLimit = 5 * 366 * 24 * time.Hour
+
+// Next returns the first time after the given one that matches the
+// schedule, in the given time's location. It returns the zero time if the
+// schedule never matches.
+func (schedule Schedule) Next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(searchLimit)
+
+	for t.Before(limit) {
+		if schedule.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if !schedule.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if schedule.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if schedule.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+func (schedule Schedule) matchesDay(t time.Time) bool {
+	dom := schedule.dom&(1<<uint(t.Day())) != 0
+	dow := schedule.dow&(1<<uint(t.Weekday())) != 0
+
+	if schedule.domRestricted && schedule.dowRestricted {
+		return dom || dow
+	}
+
+	return dom && dow
+}
@@ -0,0 +1,59 @@
+package cron_test
+
+import (
+	"time"
+
+	. "github.com/concourse/atc/cron"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Schedule", func() {
+	// a Saturday
+	start := time.Date(2016, 10, 1, 12, 30, 45, 0, time.UTC)
+
+	DescribeTable("Next",
+		func(expression string, after time.Time, expected time.Time) {
+			schedule, err := Parse(expression)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(schedule.Next(after)).To(Equal(expected))
+		},
+		Entry("every minute", "* * * * *", start, time.Date(2016, 10, 1, 12, 31, 0, 0, time.UTC)),
+		Entry("exactly on a matching minute", "* * * * *", time.Date(2016, 10, 1, 12, 30, 0, 0, time.UTC), time.Date(2016, 10, 1, 12, 31, 0, 0, time.UTC)),
+		Entry("a fixed time later today", "0 18 * * *", start, time.Date(2016, 10, 1, 18, 0, 0, 0, time.UTC)),
+		Entry("a fixed time tomorrow", "15 9 * * *", start, time.Date(2016, 10, 2, 9, 15, 0, 0, time.UTC)),
+		Entry("a step", "*/20 * * * *", start, time.Date(2016, 10, 1, 12, 40, 0, 0, time.UTC)),
+		Entry("a stepped value", "5/15 * * * *", start, time.Date(2016, 10, 1, 12, 35, 0, 0, time.UTC)),
+		Entry("a list", "10,50 * * * *", start, time.Date(2016, 10, 1, 12, 50, 0, 0, time.UTC)),
+		Entry("a range of weekdays", "0 9 * * mon-fri", start, time.Date(2016, 10, 3, 9, 0, 0, 0, time.UTC)),
+		Entry("Sunday as 7", "0 0 * * 7", start, time.Date(2016, 10, 2, 0, 0, 0, 0, time.UTC)),
+		Entry("a named month", "0 0 1 jan *", start, time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)),
+		Entry("either day when both are restricted", "0 0 15 * mon", start, time.Date(2016, 10, 3, 0, 0, 0, 0, time.UTC)),
+		Entry("a leap day", "0 0 29 2 *", start, time.Date(2020, 2, 29, 0, 0, 0, 0, time.UTC)),
+		Entry("a day that never comes", "0 0 30 2 *", start, time.Time{}),
+		Entry("@hourly", "@hourly", start, time.Date(2016, 10, 1, 13, 0, 0, 0, time.UTC)),
+		Entry("@daily", "@daily", start, time.Date(2016, 10, 2, 0, 0, 0, 0, time.UTC)),
+		Entry("@weekly", "@weekly", start, time.Date(2016, 10, 2, 0, 0, 0, 0, time.UTC)),
+		Entry("@monthly", "@monthly", start, time.Date(2016, 11, 1, 0, 0, 0, 0, time.UTC)),
+		Entry("@yearly", "@yearly", start, time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)),
+	)
+
+	DescribeTable("Parse errors",
+		func(expression string, message string) {
+			_, err := Parse(expression)
+			Expect(err).To(MatchError(message))
+		},
+		Entry("too few fields", "* * * *", "expected 5 fields, got 4"),
+		Entry("too many fields", "* * * * * *", "expected 5 fields, got 6"),
+		Entry("a minute out of range", "60 * * * *", "invalid minute: 60 is not between 0 and 59"),
+		Entry("an hour out of range", "0 24 * * *", "invalid hour: 24 is not between 0 and 23"),
+		Entry("a day of month out of range", "0 0 0 * *", "invalid day of month: 0 is not between 1 and 31"),
+		Entry("an unknown month", "0 0 * foo *", "invalid month: 'foo' is not a number"),
+		Entry("a backwards range", "0 0 * * fri-mon", "invalid day of week: range 'fri-mon' is backwards"),
+		Entry("a bad step", "*/0 * * * *", "invalid minute: bad step in '*/0'"),
+		Entry("an unknown shorthand", "@sometimes", "expected 5 fields, got 1"),
+	)
+})
@@ -0,0 +1,17 @@
+package atc
+
+// DashboardPipeline is a pipeline along with the state of each of its jobs,
+// as shown on the dashboard.
+type DashboardPipeline struct {
+	Pipeline
+
+	Jobs []DashboardJob `json:"jobs"`
+}
+
+// DashboardJob is a job along with every build of it that's running, newest
+// first.
+type DashboardJob struct {
+	Job
+
+	RunningBuilds []Build `json:"running_builds"`
+}
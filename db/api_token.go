@@ -0,0 +1,18 @@
+package db
+
+import "time"
+
+// APIToken records a token issued to a team for use by scripts and other
+// tools. The token itself is never stored; revoking it deletes the record,
+// after which the token is no longer accepted.
+type APIToken struct {
+	ID     int
+	TeamID int
+	Name   string
+	Scopes []string
+
+	CreatedAt time.Time
+
+	// ExpiresAt is zero for tokens that never expire.
+	ExpiresAt time.Time
+}
@@ -0,0 +1,125 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ArtifactBuild is a finished build of a job whose containers and artifacts
+// haven't been reaped. Artifacts are the names of those registered for it.
+type ArtifactBuild struct {
+	ID        int
+	Name      string
+	Status    Status
+	EndTime   time.Time
+	Protected bool
+	Artifacts []string
+}
+
+// GetJobArtifactBuilds returns the job's finished builds that still have
+// their containers and artifacts, newest first.
+func (pdb *pipelineDB) GetJobArtifactBuilds(jobName string) ([]ArtifactBuild, error) {
+	rows, err := pdb.conn.Query(`
+		SELECT b.id, b.name, b.status, b.end_time, b.artifacts_protected, a.name
+		FROM builds b
+		INNER JOIN jobs j ON j.id = b.job_id
+		LEFT JOIN build_artifacts a ON a.build_id = b.id
+		WHERE j.pipeline_id = $1
+		AND j.name = $2
+		AND b.status IN ('succeeded', 'failed', 'errored', 'aborted')
+		AND b.artifacts_reaped_at IS NULL
+		ORDER BY b.id DESC, a.name ASC
+	`, pdb.ID, jobName)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	builds := []ArtifactBuild{}
+
+	for rows.Next() {
+		var build ArtifactBuild
+		var status string
+		var endTime pq.NullTime
+		var artifact sql.NullString
+
+		err := rows.Scan(&build.ID, &build.Name, &status, &endTime, &build.Protected, &artifact)
+		if err != nil {
+			return nil, err
+		}
+
+		// one row per artifact, so only the first of a build's rows starts it
+		if len(builds) == 0 || builds[len(builds)-1].ID != build.ID {
+			build.Status = Status(status)
+			build.EndTime = endTime.Time
+			build.Artifacts = []string{}
+
+			builds = append(builds, build)
+		}
+
+		if artifact.Valid {
+			last := &builds[len(builds)-1]
+			last.Artifacts = append(last.Artifacts, artifact.String)
+		}
+	}
+
+	return builds, rows.Err()
+}
+
+// SetBuildArtifactsProtected protects the job's build from having its
+// containers and artifacts reaped, or stops protecting it. It returns false
+// if the job has no such build.
+func (pdb *pipelineDB) SetBuildArtifactsProtected(jobName string, buildName string, protected bool) (bool, error) {
+	result, err := pdb.conn.Exec(`
+		UPDATE builds b
+		SET artifacts_protected = $4
+		FROM jobs j
+		WHERE j.id = b.job_id
+		AND j.pipeline_id = $1
+		AND j.name = $2
+		AND b.name = $3
+	`, pdb.ID, jobName, buildName, protected)
+	if err != nil {
+		return false, err
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return updated > 0, nil
+}
+
+// MarkBuildArtifactsReaped forgets the build's artifacts and records that its
+// containers and artifacts are gone, so that it isn't reaped again.
+func (db *SQLDB) MarkBuildArtifactsReaped(buildID int) error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		DELETE FROM build_artifacts
+		WHERE build_id = $1
+	`, buildID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE builds
+		SET artifacts_reaped_at = now()
+		WHERE id = $1
+	`, buildID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
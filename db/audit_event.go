@@ -0,0 +1,25 @@
+package db
+
+import "time"
+
+type AuditEvent struct {
+	ID   int
+	Time time.Time
+
+	Actor    string
+	Action   string
+	Resource string
+
+	BodySHA256 string
+	Status     int
+}
+
+// AuditEventFilter narrows down the audit events returned, newest first.
+// Empty fields match everything, and Until, if set, only returns events
+// older than the one with that ID.
+type AuditEventFilter struct {
+	Actor  string
+	Action string
+	Until  int
+	Limit  int
+}
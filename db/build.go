@@ -4,12 +4,14 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"time"
 
 	"code.cloudfoundry.org/lager"
 	"github.com/concourse/atc"
 	"github.com/concourse/atc/config"
 	"github.com/concourse/atc/event"
+	"github.com/lib/pq"
 )
 
 type Status string
@@ -21,10 +23,65 @@ const (
 	StatusSucceeded Status = "succeeded"
 	StatusFailed    Status = "failed"
 	StatusErrored   Status = "errored"
+	StatusTimedOut  Status = "timed_out"
 )
 
-const buildColumns = "id, name, job_id, team_id, status, scheduled, engine, engine_metadata, start_time, end_time, reap_time"
-const qualifiedBuildColumns = "b.id, b.name, b.job_id, b.team_id, b.status, b.scheduled, b.engine, b.engine_metadata, b.start_time, b.end_time, b.reap_time, j.name as job_name, p.id as pipeline_id, p.name as pipeline_name, t.name as team_name"
+const buildColumns = "id, name, job_id, team_id, status, scheduled, engine, engine_metadata, start_time, end_time, reap_time, labels, log_truncated, priority, cpu_usage, memory_usage, disk_usage, rerun_of, timeout, params"
+const qualifiedBuildColumns = "b.id, b.name, b.job_id, b.team_id, b.status, b.scheduled, b.engine, b.engine_metadata, b.start_time, b.end_time, b.reap_time, b.labels, b.log_truncated, b.priority, b.cpu_usage, b.memory_usage, b.disk_usage, b.rerun_of, b.timeout, b.params, j.name as job_name, p.id as pipeline_id, p.name as pipeline_name, t.name as team_name"
+
+// BuildFilter narrows down listed builds. Builds must carry every one of the
+// given labels to match, and must not belong to any of the hidden pipelines.
+type BuildFilter struct {
+	Labels            map[string]string
+	HiddenPipelineIDs []int
+}
+
+// ResourceUsage is what a build's task containers used, measured as each of
+// them finished. CPU time and disk add up across containers, while memory is
+// the most that any one of them was using.
+type ResourceUsage struct {
+	CPUNanoseconds uint64
+	MemoryBytes    uint64
+	DiskBytes      uint64
+}
+
+// BuildArtifact is a file or directory that a build has left behind in one of
+// its containers, registered under a name so that it can be downloaded.
+type BuildArtifact struct {
+	Name            string
+	ContainerHandle string
+	Path            string
+}
+
+type ApprovalStatus string
+
+const (
+	ApprovalPending  ApprovalStatus = "pending"
+	ApprovalApproved ApprovalStatus = "approved"
+	ApprovalRejected ApprovalStatus = "rejected"
+)
+
+// BuildApproval is an approval step in a build, which holds the build up
+// until someone approves or rejects it.
+type BuildApproval struct {
+	Name   string
+	PlanID atc.PlanID
+	Status ApprovalStatus
+
+	Approver string
+
+	RequestedAt time.Time
+	DecidedAt   time.Time
+}
+
+// BuildComment is a note left on a build, e.g. to say that it failed
+// because of a known flake.
+type BuildComment struct {
+	ID        int
+	Author    string
+	Text      string
+	CreatedAt time.Time
+}
 
 //go:generate counterfeiter . Build
 
@@ -33,6 +90,7 @@ type Build interface {
 	Name() string
 	JobName() string
 	PipelineName() string
+	PipelineID() int
 	TeamName() string
 	TeamID() int
 	Engine() string
@@ -41,6 +99,13 @@ type Build interface {
 	StartTime() time.Time
 	EndTime() time.Time
 	ReapTime() time.Time
+	Labels() map[string]string
+	LogTruncated() bool
+	Priority() int
+	ResourceUsage() ResourceUsage
+	RerunOf() int
+	Timeout() time.Duration
+	Params() map[string]string
 	IsOneOff() bool
 	IsScheduled() bool
 	IsRunning() bool
@@ -49,6 +114,7 @@ type Build interface {
 
 	Events(from uint) (EventSource, error)
 	SaveEvent(event atc.Event) error
+	VerifyEvents() (EventChainVerification, error)
 
 	GetVersionedResources() (SavedVersionedResources, error)
 	GetResources() ([]BuildInput, []BuildOutput, error)
@@ -56,24 +122,58 @@ type Build interface {
 	Start(string, string) (bool, error)
 	Finish(status Status) error
 	MarkAsFailed(cause error) error
+	MarkAsTimedOut() (bool, error)
+	SaveDurationAlert(percentile int, threshold time.Duration, elapsed time.Duration) (bool, error)
 	Abort() error
 	AbortNotifier() (Notifier, error)
+	FinishNotifier() (Notifier, error)
 
 	AcquireTrackingLock(logger lager.Logger, interval time.Duration) (Lock, bool, error)
+	HandOff() error
 
 	GetPreparation() (BuildPreparation, bool, error)
 
 	SaveEngineMetadata(engineMetadata string) error
+	SaveLabels(labels map[string]string) error
+	SaveSecrets(secrets []string) error
+	GetSecrets() ([]string, error)
+	MarkLogTruncated() error
+	SetPriority(priority int) (bool, error)
+	SetTimeout(timeout time.Duration) error
+	QueuePosition() (int, bool, error)
+	SaveResourceUsage(usage ResourceUsage) error
 
 	SaveInput(input BuildInput) (SavedVersionedResource, error)
 	SaveOutput(vr VersionedResource, explicit bool) (SavedVersionedResource, error)
 
+	SearchLogs(query string, limit int) ([]LogMatch, error)
+
+	SaveArtifact(artifact BuildArtifact) error
+	GetArtifact(name string) (BuildArtifact, bool, error)
+	GetArtifacts() ([]BuildArtifact, error)
+
+	SaveComment(author string, text string) (BuildComment, error)
+	GetComments() ([]BuildComment, error)
+
+	RequestApproval(planID atc.PlanID, name string) error
+	GetApproval(name string) (BuildApproval, bool, error)
+	GetApprovals() ([]BuildApproval, error)
+	DecideApproval(name string, approved bool, approver string) (bool, error)
+	ApprovalNotifier(name string) (Notifier, error)
+
+	GetSteps() ([]BuildStep, error)
+
+	SaveDependencies(dependsOn []int, plan atc.Plan) error
+	GetDependencyStatuses() (map[int]Status, error)
+	ClaimPendingPlan() (atc.Plan, bool, error)
+
 	SaveImageResourceVersion(planID atc.PlanID, identifier ResourceCacheIdentifier) error
 	GetImageResourceCacheIdentifiers() ([]ResourceCacheIdentifier, error)
 
 	GetConfig() (atc.Config, ConfigVersion, error)
 
 	GetPipeline() (SavedPipeline, error)
+	GetPreviousSuccessfulBuild() (Build, bool, error)
 }
 
 type build struct {
@@ -95,8 +195,22 @@ type build struct {
 	endTime   time.Time
 	reapTime  time.Time
 
+	labels map[string]string
+
+	logTruncated bool
+
+	priority int
+
+	resourceUsage ResourceUsage
+
+	rerunOf int
+
+	timeout time.Duration
+
+	params map[string]string
+
 	conn Conn
-	bus  *notificationsBus
+	bus  NotificationsBus
 
 	lockFactory LockFactory
 }
@@ -117,6 +231,10 @@ func (b *build) PipelineName() string {
 	return b.pipelineName
 }
 
+func (b *build) PipelineID() int {
+	return b.pipelineID
+}
+
 func (b *build) TeamName() string {
 	return b.teamName
 }
@@ -145,6 +263,40 @@ func (b *build) ReapTime() time.Time {
 	return b.reapTime
 }
 
+func (b *build) Labels() map[string]string {
+	return b.labels
+}
+
+func (b *build) LogTruncated() bool {
+	return b.logTruncated
+}
+
+func (b *build) Priority() int {
+	return b.priority
+}
+
+func (b *build) ResourceUsage() ResourceUsage {
+	return b.resourceUsage
+}
+
+// RerunOf is the ID of the build that this one was rerun from, or 0 if it
+// was not a rerun.
+func (b *build) RerunOf() int {
+	return b.rerunOf
+}
+
+// Timeout is how long the build may run before it's timed out, or 0 if it
+// should use the default.
+func (b *build) Timeout() time.Duration {
+	return b.timeout
+}
+
+// Params are the params the build was triggered with, which its tasks are
+// given.
+func (b *build) Params() map[string]string {
+	return b.params
+}
+
 func (b *build) Status() Status {
 	return b.status
 }
@@ -193,6 +345,13 @@ func (b *build) Reload() (bool, error) {
 	b.startTime = newBuild.StartTime()
 	b.endTime = newBuild.EndTime()
 	b.reapTime = newBuild.ReapTime()
+	b.labels = newBuild.Labels()
+	b.logTruncated = newBuild.LogTruncated()
+	b.priority = newBuild.Priority()
+	b.resourceUsage = newBuild.ResourceUsage()
+	b.rerunOf = newBuild.RerunOf()
+	b.timeout = newBuild.Timeout()
+	b.params = newBuild.Params()
 	b.teamName = newBuild.TeamName()
 	b.teamID = newBuild.TeamID()
 	b.jobName = newBuild.JobName()
@@ -202,28 +361,30 @@ func (b *build) Reload() (bool, error) {
 }
 
 func (b *build) Events(from uint) (EventSource, error) {
-	notifier, err := newConditionNotifier(b.bus, buildEventsChannel(b.id), func() (bool, error) {
-		return true, nil
-	})
+	channel := buildEventsChannel(b.id)
+
+	sink, err := b.bus.ListenForPayloads(channel)
 	if err != nil {
 		return nil, err
 	}
 
-	table := "build_events"
-	if b.pipelineID != 0 {
-		table = fmt.Sprintf("pipeline_build_events_%d", b.pipelineID)
-	}
-
 	return newSQLDBBuildEventSource(
 		b.id,
-		table,
+		buildEventsTable(b.pipelineID),
 		b.conn,
-		notifier,
+		b.bus,
+		channel,
+		sink,
 		from,
 	), nil
 }
 
 func (b *build) Start(engine, metadata string) (bool, error) {
+	encryptedMetadata, err := b.conn.EncryptionStrategy().Encrypt([]byte(metadata))
+	if err != nil {
+		return false, err
+	}
+
 	tx, err := b.conn.Begin()
 	if err != nil {
 		return false, err
@@ -239,7 +400,7 @@ func (b *build) Start(engine, metadata string) (bool, error) {
 		WHERE id = $1
 		AND status = 'pending'
 		RETURNING start_time
-	`, b.id, engine, metadata).Scan(&startTime)
+	`, b.id, engine, encryptedMetadata).Scan(&startTime)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return false, nil
@@ -256,12 +417,12 @@ func (b *build) Start(engine, metadata string) (bool, error) {
 		return false, err
 	}
 
-	err = tx.Commit()
+	err = notifyBuildStatus(tx, b.id)
 	if err != nil {
 		return false, err
 	}
 
-	err = b.bus.Notify(buildEventsChannel(b.id))
+	err = tx.Commit()
 	if err != nil {
 		return false, err
 	}
@@ -274,6 +435,7 @@ func (b *build) Abort() error {
    UPDATE builds
    SET status = 'aborted'
    WHERE id = $1
+   AND status != 'timed_out'
  `, b.id)
 	if err != nil {
 		return err
@@ -284,6 +446,11 @@ func (b *build) Abort() error {
 		return err
 	}
 
+	err = notifyBuildStatus(b.conn, b.id)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -291,7 +458,7 @@ func (b *build) AbortNotifier() (Notifier, error) {
 	return newConditionNotifier(b.bus, buildAbortChannel(b.id), func() (bool, error) {
 		var aborted bool
 		err := b.conn.QueryRow(`
-			SELECT status = 'aborted'
+			SELECT status IN ('aborted', 'timed_out')
 			FROM builds
 			WHERE id = $1
 		`, b.id).Scan(&aborted)
@@ -300,6 +467,20 @@ func (b *build) AbortNotifier() (Notifier, error) {
 	})
 }
 
+// FinishNotifier notifies once the build is no longer pending or running.
+func (b *build) FinishNotifier() (Notifier, error) {
+	return newConditionNotifier(b.bus, BuildStatusChannel, func() (bool, error) {
+		var finished bool
+		err := b.conn.QueryRow(`
+			SELECT status NOT IN ('pending', 'started')
+			FROM builds
+			WHERE id = $1
+		`, b.id).Scan(&finished)
+
+		return finished, err
+	})
+}
+
 func (b *build) Finish(status Status) error {
 	tx, err := b.conn.Begin()
 	if err != nil {
@@ -309,43 +490,53 @@ func (b *build) Finish(status Status) error {
 	defer tx.Rollback()
 
 	var endTime time.Time
+	var finalStatus string
 
+	// a build that timed out is aborted to stop it, but should still be seen
+	// to have timed out once it finishes
 	err = tx.QueryRow(`
 		UPDATE builds
-		SET status = $2, end_time = now(), completed = true
+		SET status = CASE
+				WHEN status = 'timed_out' AND $2::build_status = 'aborted' THEN status
+				ELSE $2::build_status
+			END,
+			end_time = now(),
+			completed = true
 		WHERE id = $1
-		RETURNING end_time
-	`, b.id, string(status)).Scan(&endTime)
+		RETURNING status, end_time
+	`, b.id, string(status)).Scan(&finalStatus, &endTime)
 	if err != nil {
 		return err
 	}
 
 	err = b.saveEvent(tx, event.Status{
-		Status: atc.BuildStatus(status),
+		Status: atc.BuildStatus(finalStatus),
 		Time:   endTime.Unix(),
 	})
 	if err != nil {
 		return err
 	}
 
-	_, err = tx.Exec(fmt.Sprintf(`
-		DROP SEQUENCE %s
-	`, buildEventSeq(b.id)))
+	err = notifyBuildStatus(tx, b.id)
 	if err != nil {
 		return err
 	}
 
-	err = tx.Commit()
-	if err != nil {
-		return err
+	if b.pipelineID != 0 {
+		err = notifyScheduling(tx, b.pipelineID)
+		if err != nil {
+			return err
+		}
 	}
 
-	err = b.bus.Notify(buildEventsChannel(b.id))
+	_, err = tx.Exec(fmt.Sprintf(`
+		DROP SEQUENCE %s
+	`, buildEventSeq(b.id)))
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return tx.Commit()
 }
 
 func (b *build) MarkAsFailed(cause error) error {
@@ -359,30 +550,52 @@ func (b *build) MarkAsFailed(cause error) error {
 	return b.Finish(StatusErrored)
 }
 
-func (b *build) SaveEvent(event atc.Event) error {
-	tx, err := b.conn.Begin()
+// MarkAsTimedOut moves a started build to timed out, returning false if it
+// was no longer running. The build still has to be aborted to stop it.
+func (b *build) MarkAsTimedOut() (bool, error) {
+	result, err := b.conn.Exec(`
+		UPDATE builds
+		SET status = 'timed_out'
+		WHERE id = $1
+		AND status = 'started'
+	`, b.id)
 	if err != nil {
-		return err
+		return false, err
 	}
 
-	defer tx.Rollback()
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
 
-	err = b.saveEvent(tx, event)
+	if rows == 0 {
+		return false, nil
+	}
+
+	b.status = StatusTimedOut
+
+	err = notifyBuildStatus(b.conn, b.id)
 	if err != nil {
-		return err
+		return false, err
 	}
 
-	err = tx.Commit()
+	return true, nil
+}
+
+func (b *build) SaveEvent(event atc.Event) error {
+	tx, err := b.conn.Begin()
 	if err != nil {
 		return err
 	}
 
-	err = b.bus.Notify(buildEventsChannel(b.id))
+	defer tx.Rollback()
+
+	err = b.saveEvent(tx, event)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return tx.Commit()
 }
 
 func (b *build) GetResources() ([]BuildInput, []BuildOutput, error) {
@@ -739,7 +952,7 @@ func (b *build) SaveInput(input BuildInput) (SavedVersionedResource, error) {
 		WHERE p.id = $1
 	`, input.VersionedResource.PipelineID)
 
-	savedPipeline, err := scanPipeline(row)
+	savedPipeline, err := scanPipeline(row, b.conn.EncryptionStrategy())
 	if err != nil {
 		return SavedVersionedResource{}, err
 	}
@@ -759,7 +972,7 @@ func (b *build) SaveOutput(vr VersionedResource, explicit bool) (SavedVersionedR
 		WHERE p.id = $1
 	`, vr.PipelineID)
 
-	savedPipeline, err := scanPipeline(row)
+	savedPipeline, err := scanPipeline(row, b.conn.EncryptionStrategy())
 	if err != nil {
 		return SavedVersionedResource{}, err
 	}
@@ -770,11 +983,16 @@ func (b *build) SaveOutput(vr VersionedResource, explicit bool) (SavedVersionedR
 }
 
 func (b *build) SaveEngineMetadata(engineMetadata string) error {
-	_, err := b.conn.Exec(`
+	encryptedMetadata, err := b.conn.EncryptionStrategy().Encrypt([]byte(engineMetadata))
+	if err != nil {
+		return err
+	}
+
+	_, err = b.conn.Exec(`
 		UPDATE builds
 		SET engine_metadata = $2
 		WHERE id = $1
-	`, b.id, engineMetadata)
+	`, b.id, encryptedMetadata)
 	if err != nil {
 		return err
 	}
@@ -782,163 +1000,837 @@ func (b *build) SaveEngineMetadata(engineMetadata string) error {
 	return nil
 }
 
-func (b *build) SaveImageResourceVersion(planID atc.PlanID, identifier ResourceCacheIdentifier) error {
-	version, err := json.Marshal(identifier.ResourceVersion)
+func (b *build) SaveLabels(labels map[string]string) error {
+	payload, err := json.Marshal(labels)
 	if err != nil {
 		return err
 	}
 
-	result, err := b.conn.Exec(`
-		UPDATE image_resource_versions
-		SET version = $1, resource_hash = $4
-		WHERE build_id = $2 AND plan_id = $3
-	`, version, b.id, string(planID), identifier.ResourceHash)
+	_, err = b.conn.Exec(`
+		UPDATE builds
+		SET labels = $2
+		WHERE id = $1
+	`, b.id, string(payload))
 	if err != nil {
 		return err
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	b.labels = labels
+
+	return nil
+}
+
+// SaveSecrets records the credentials that the build's plan was given, so
+// that they can be redacted from its events. They aren't loaded along with
+// the build, as most of what looks at builds has no use for them.
+func (b *build) SaveSecrets(secrets []string) error {
+	payload, err := json.Marshal(secrets)
 	if err != nil {
 		return err
 	}
 
-	if rowsAffected == 0 {
-		_, err := b.conn.Exec(`
-			INSERT INTO image_resource_versions(version, build_id, plan_id, resource_hash)
-			VALUES ($1, $2, $3, $4)
-		`, version, b.id, string(planID), identifier.ResourceHash)
-		if err != nil {
-			return err
-		}
+	encryptedPayload, err := b.conn.EncryptionStrategy().Encrypt(payload)
+	if err != nil {
+		return err
 	}
 
-	return nil
+	_, err = b.conn.Exec(`
+		UPDATE builds
+		SET secrets = $2
+		WHERE id = $1
+	`, b.id, encryptedPayload)
+	return err
 }
 
-func (b *build) GetImageResourceCacheIdentifiers() ([]ResourceCacheIdentifier, error) {
-	rows, err := b.conn.Query(`
-  	SELECT version, resource_hash
-  	FROM image_resource_versions
-  	WHERE build_id = $1
-  `, b.id)
+func (b *build) GetSecrets() ([]string, error) {
+	var payload sql.NullString
+	err := b.conn.QueryRow(`
+		SELECT secrets
+		FROM builds
+		WHERE id = $1
+	`, b.id).Scan(&payload)
 	if err != nil {
 		return nil, err
 	}
 
-	defer rows.Close()
-
-	var identifiers []ResourceCacheIdentifier
-
-	for rows.Next() {
-		var identifier ResourceCacheIdentifier
-		var marshalledVersion []byte
-
-		err := rows.Scan(&marshalledVersion, &identifier.ResourceHash)
-		if err != nil {
-			return nil, err
-		}
+	if !payload.Valid {
+		return nil, nil
+	}
 
-		err = json.Unmarshal(marshalledVersion, &identifier.ResourceVersion)
-		if err != nil {
-			return nil, err
-		}
+	decrypted, err := b.conn.EncryptionStrategy().Decrypt(payload.String)
+	if err != nil {
+		return nil, err
+	}
 
-		identifiers = append(identifiers, identifier)
+	var secrets []string
+	err = json.Unmarshal(decrypted, &secrets)
+	if err != nil {
+		return nil, err
 	}
 
-	return identifiers, nil
+	return secrets, nil
 }
 
-func (b *build) AcquireTrackingLock(logger lager.Logger, interval time.Duration) (Lock, bool, error) {
-	tx, err := b.conn.Begin()
+func (b *build) MarkLogTruncated() error {
+	_, err := b.conn.Exec(`
+		UPDATE builds
+		SET log_truncated = true
+		WHERE id = $1
+	`, b.id)
 	if err != nil {
-		return nil, false, err
+		return err
 	}
 
-	defer tx.Rollback()
+	b.logTruncated = true
 
-	updated, err := checkIfRowsUpdated(tx, `
+	return nil
+}
+
+func (b *build) SetTimeout(timeout time.Duration) error {
+	var seconds interface{}
+	if timeout != 0 {
+		seconds = int(timeout.Seconds())
+	}
+
+	_, err := b.conn.Exec(`
 		UPDATE builds
-		SET last_tracked = now()
+		SET timeout = $2
 		WHERE id = $1
-			AND now() - last_tracked > ($2 || ' SECONDS')::INTERVAL
-	`, b.id, interval.Seconds())
+	`, b.id, seconds)
 	if err != nil {
-		return nil, false, err
+		return err
 	}
 
-	if !updated {
-		return nil, false, nil
-	}
+	b.timeout = timeout
 
-	lock := b.lockFactory.NewLock(
-		logger.Session("lock", lager.Data{
-			"build_id": b.id,
-		}),
-		buildTrackingLockID(b.id),
-	)
+	return nil
+}
 
-	acquired, err := lock.Acquire()
+// SetPriority only applies to builds that are still pending; once a build
+// has been scheduled its place in the queue no longer matters.
+func (b *build) SetPriority(priority int) (bool, error) {
+	result, err := b.conn.Exec(`
+		UPDATE builds
+		SET priority = $2
+		WHERE id = $1
+		AND status = 'pending'
+		AND scheduled = false
+	`, b.id, priority)
 	if err != nil {
-		return nil, false, err
+		return false, err
 	}
 
-	if !acquired {
-		return nil, false, nil
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
 	}
 
-	err = tx.Commit()
-	if err != nil {
-		lock.Release()
-		return nil, false, err
+	if rows == 0 {
+		return false, nil
 	}
 
-	return lock, true, nil
+	b.priority = priority
+
+	return true, nil
 }
 
-func (b *build) GetConfig() (atc.Config, ConfigVersion, error) {
-	var configBlob []byte
-	var version int
+// QueuePosition is where a pending build stands among the pending builds of
+// its job and of any job sharing a serial group with it, in the order the
+// scheduler will start them; the first in line is at 1. Builds that are not
+// pending, or not of a job, are not queued.
+func (b *build) QueuePosition() (int, bool, error) {
+	var position int
 	err := b.conn.QueryRow(`
-			SELECT p.config, p.version
-			FROM builds b
-			INNER JOIN jobs j ON b.job_id = j.id
-			INNER JOIN pipelines p ON j.pipeline_id = p.id
-			WHERE b.id = $1
-		`, b.id).Scan(&configBlob, &version)
+		SELECT COUNT(ob.id) + 1
+		FROM builds b
+		INNER JOIN jobs j ON j.id = b.job_id
+		LEFT JOIN builds ob ON ob.status = 'pending'
+			AND (ob.priority > b.priority OR (ob.priority = b.priority AND ob.id < b.id))
+			AND (
+				ob.job_id = j.id
+				OR ob.job_id IN (
+					SELECT ojsg.job_id
+					FROM jobs_serial_groups jsg
+					INNER JOIN jobs_serial_groups ojsg ON ojsg.serial_group = jsg.serial_group
+					INNER JOIN jobs oj ON oj.id = ojsg.job_id
+					WHERE jsg.job_id = j.id
+					AND oj.pipeline_id = j.pipeline_id
+				)
+			)
+		WHERE b.id = $1
+		AND b.status = 'pending'
+		GROUP BY b.id
+	`, b.id).Scan(&position)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return atc.Config{}, 0, nil
-		} else {
-			return atc.Config{}, 0, err
+			return 0, false, nil
 		}
-	}
 
-	var config atc.Config
-	err = json.Unmarshal(configBlob, &config)
-	if err != nil {
-		return atc.Config{}, 0, err
+		return 0, false, err
 	}
 
-	return config, ConfigVersion(version), nil
+	return position, true, nil
 }
 
-func (b *build) GetPipeline() (SavedPipeline, error) {
-	if b.IsOneOff() {
-		return SavedPipeline{}, nil
+// SaveResourceUsage adds the usage of one of the build's containers to what
+// has been recorded for the build so far.
+func (b *build) SaveResourceUsage(usage ResourceUsage) error {
+	err := b.conn.QueryRow(`
+		UPDATE builds
+		SET cpu_usage = cpu_usage + $2,
+			memory_usage = GREATEST(memory_usage, $3),
+			disk_usage = disk_usage + $4
+		WHERE id = $1
+		RETURNING cpu_usage, memory_usage, disk_usage
+	`, b.id, usage.CPUNanoseconds, usage.MemoryBytes, usage.DiskBytes).Scan(
+		&b.resourceUsage.CPUNanoseconds,
+		&b.resourceUsage.MemoryBytes,
+		&b.resourceUsage.DiskBytes,
+	)
+	if err == sql.ErrNoRows {
+		return ErrNoBuild
 	}
 
-	row := b.conn.QueryRow(`
-		SELECT `+pipelineColumns+`
-		FROM pipelines p
-		INNER JOIN teams t ON t.id = p.team_id
-		WHERE p.id = $1
-	`, b.pipelineID)
-
-	return scanPipeline(row)
+	return err
 }
 
-func newConditionNotifier(bus *notificationsBus, channel string, cond func() (bool, error)) (Notifier, error) {
+func (b *build) SaveArtifact(artifact BuildArtifact) error {
+	result, err := b.conn.Exec(`
+		UPDATE build_artifacts
+		SET container_handle = $3, path = $4
+		WHERE build_id = $1 AND name = $2
+	`, b.id, artifact.Name, artifact.ContainerHandle, artifact.Path)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		_, err := b.conn.Exec(`
+			INSERT INTO build_artifacts (build_id, name, container_handle, path)
+			VALUES ($1, $2, $3, $4)
+		`, b.id, artifact.Name, artifact.ContainerHandle, artifact.Path)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (b *build) GetArtifact(name string) (BuildArtifact, bool, error) {
+	var artifact BuildArtifact
+
+	err := b.conn.QueryRow(`
+		SELECT name, container_handle, path
+		FROM build_artifacts
+		WHERE build_id = $1 AND name = $2
+	`, b.id, name).Scan(&artifact.Name, &artifact.ContainerHandle, &artifact.Path)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return BuildArtifact{}, false, nil
+		}
+
+		return BuildArtifact{}, false, err
+	}
+
+	return artifact, true, nil
+}
+
+func (b *build) GetArtifacts() ([]BuildArtifact, error) {
+	rows, err := b.conn.Query(`
+		SELECT name, container_handle, path
+		FROM build_artifacts
+		WHERE build_id = $1
+		ORDER BY name ASC
+	`, b.id)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	artifacts := []BuildArtifact{}
+
+	for rows.Next() {
+		var artifact BuildArtifact
+
+		err := rows.Scan(&artifact.Name, &artifact.ContainerHandle, &artifact.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		artifacts = append(artifacts, artifact)
+	}
+
+	return artifacts, nil
+}
+
+func (b *build) SaveComment(author string, text string) (BuildComment, error) {
+	comment := BuildComment{
+		Author: author,
+		Text:   text,
+	}
+
+	err := b.conn.QueryRow(`
+		INSERT INTO build_comments (build_id, author, text)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`, b.id, author, text).Scan(&comment.ID, &comment.CreatedAt)
+	if err != nil {
+		return BuildComment{}, err
+	}
+
+	return comment, nil
+}
+
+func (b *build) GetComments() ([]BuildComment, error) {
+	rows, err := b.conn.Query(`
+		SELECT id, author, text, created_at
+		FROM build_comments
+		WHERE build_id = $1
+		ORDER BY id ASC
+	`, b.id)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	comments := []BuildComment{}
+
+	for rows.Next() {
+		var comment BuildComment
+
+		err := rows.Scan(&comment.ID, &comment.Author, &comment.Text, &comment.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		comments = append(comments, comment)
+	}
+
+	return comments, nil
+}
+
+const buildApprovalColumns = "name, plan_id, status, approver, requested_at, decided_at"
+
+// RequestApproval records that the build is waiting on the approval step. It
+// is fine to request the same approval again, e.g. when the build is resumed
+// by another ATC; it is left as it was.
+func (b *build) RequestApproval(planID atc.PlanID, name string) error {
+	tx, err := b.conn.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	var requestedAt time.Time
+	err = tx.QueryRow(`
+		INSERT INTO build_approvals (build_id, name, plan_id)
+		SELECT $1, $2, $3
+		WHERE NOT EXISTS (
+			SELECT 1 FROM build_approvals WHERE build_id = $1 AND name = $2
+		)
+		RETURNING requested_at
+	`, b.id, name, string(planID)).Scan(&requestedAt)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	err = b.saveEvent(tx, event.ApprovalRequested{
+		Origin: event.Origin{ID: event.OriginID(planID)},
+		Name:   name,
+		Time:   requestedAt.Unix(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (b *build) GetApproval(name string) (BuildApproval, bool, error) {
+	approval, err := scanBuildApproval(b.conn.QueryRow(`
+		SELECT `+buildApprovalColumns+`
+		FROM build_approvals
+		WHERE build_id = $1
+		AND name = $2
+	`, b.id, name))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return BuildApproval{}, false, nil
+		}
+
+		return BuildApproval{}, false, err
+	}
+
+	return approval, true, nil
+}
+
+func (b *build) GetApprovals() ([]BuildApproval, error) {
+	rows, err := b.conn.Query(`
+		SELECT `+buildApprovalColumns+`
+		FROM build_approvals
+		WHERE build_id = $1
+		ORDER BY requested_at ASC, name ASC
+	`, b.id)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	approvals := []BuildApproval{}
+
+	for rows.Next() {
+		approval, err := scanBuildApproval(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		approvals = append(approvals, approval)
+	}
+
+	return approvals, nil
+}
+
+// DecideApproval approves or rejects a pending approval, saving an event
+// that says who did it. It returns false if the approval wasn't pending,
+// either because it has already been decided or because the build never got
+// to it.
+func (b *build) DecideApproval(name string, approved bool, approver string) (bool, error) {
+	status := ApprovalRejected
+	if approved {
+		status = ApprovalApproved
+	}
+
+	tx, err := b.conn.Begin()
+	if err != nil {
+		return false, err
+	}
+
+	defer tx.Rollback()
+
+	var planID string
+	var decidedAt time.Time
+	err = tx.QueryRow(`
+		UPDATE build_approvals
+		SET status = $3, approver = $4, decided_at = now()
+		WHERE build_id = $1
+		AND name = $2
+		AND status = 'pending'
+		RETURNING plan_id, decided_at
+	`, b.id, name, string(status), approver).Scan(&planID, &decidedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	err = b.saveEvent(tx, event.ApprovalDecided{
+		Origin:   event.Origin{ID: event.OriginID(planID)},
+		Name:     name,
+		Approved: approved,
+		Approver: approver,
+		Time:     decidedAt.Unix(),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	_, err = tx.Exec(`SELECT pg_notify($1, $2)`, buildApprovalChannel(b.id), name)
+	if err != nil {
+		return false, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// ApprovalNotifier notifies once the approval has been decided.
+func (b *build) ApprovalNotifier(name string) (Notifier, error) {
+	return newConditionNotifier(b.bus, buildApprovalChannel(b.id), func() (bool, error) {
+		var decided bool
+		err := b.conn.QueryRow(`
+			SELECT status != 'pending'
+			FROM build_approvals
+			WHERE build_id = $1
+			AND name = $2
+		`, b.id, name).Scan(&decided)
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+
+		return decided, err
+	})
+}
+
+func scanBuildApproval(row scannable) (BuildApproval, error) {
+	var approval BuildApproval
+	var planID, status string
+	var approver sql.NullString
+	var decidedAt pq.NullTime
+
+	err := row.Scan(&approval.Name, &planID, &status, &approver, &approval.RequestedAt, &decidedAt)
+	if err != nil {
+		return BuildApproval{}, err
+	}
+
+	approval.PlanID = atc.PlanID(planID)
+	approval.Status = ApprovalStatus(status)
+
+	if approver.Valid {
+		approval.Approver = approver.String
+	}
+
+	if decidedAt.Valid {
+		approval.DecidedAt = decidedAt.Time
+	}
+
+	return approval, nil
+}
+
+// SaveDependencies holds the build back until the builds it depends on have
+// succeeded, keeping hold of the plan to start it with once they have.
+func (b *build) SaveDependencies(dependsOn []int, plan atc.Plan) error {
+	payload, err := json.Marshal(plan)
+	if err != nil {
+		return err
+	}
+
+	encryptedPayload, err := b.conn.EncryptionStrategy().Encrypt(payload)
+	if err != nil {
+		return err
+	}
+
+	tx, err := b.conn.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	for _, dependency := range dependsOn {
+		_, err := tx.Exec(`
+			INSERT INTO build_dependencies (build_id, depends_on_build_id)
+			VALUES ($1, $2)
+		`, b.id, dependency)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(`
+		UPDATE builds
+		SET pending_plan = $2
+		WHERE id = $1
+	`, b.id, encryptedPayload)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (b *build) GetDependencyStatuses() (map[int]Status, error) {
+	rows, err := b.conn.Query(`
+		SELECT b.id, b.status
+		FROM build_dependencies d, builds b
+		WHERE d.depends_on_build_id = b.id
+			AND d.build_id = $1
+	`, b.id)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	statuses := map[int]Status{}
+
+	for rows.Next() {
+		var id int
+		var status string
+
+		err := rows.Scan(&id, &status)
+		if err != nil {
+			return nil, err
+		}
+
+		statuses[id] = Status(status)
+	}
+
+	return statuses, nil
+}
+
+// ClaimPendingPlan takes the plan saved by SaveDependencies, so that only
+// one caller ever gets to start the build with it.
+func (b *build) ClaimPendingPlan() (atc.Plan, bool, error) {
+	var payload string
+
+	err := b.conn.QueryRow(`
+		UPDATE builds b
+		SET pending_plan = NULL
+		FROM (
+			SELECT id, pending_plan
+			FROM builds
+			WHERE id = $1
+			FOR UPDATE
+		) old
+		WHERE b.id = old.id
+			AND old.pending_plan IS NOT NULL
+		RETURNING old.pending_plan
+	`, b.id).Scan(&payload)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return atc.Plan{}, false, nil
+		}
+
+		return atc.Plan{}, false, err
+	}
+
+	decrypted, err := b.conn.EncryptionStrategy().Decrypt(payload)
+	if err != nil {
+		return atc.Plan{}, false, err
+	}
+
+	var plan atc.Plan
+	err = json.Unmarshal(decrypted, &plan)
+	if err != nil {
+		return atc.Plan{}, false, err
+	}
+
+	return plan, true, nil
+}
+
+func (b *build) SaveImageResourceVersion(planID atc.PlanID, identifier ResourceCacheIdentifier) error {
+	version, err := json.Marshal(identifier.ResourceVersion)
+	if err != nil {
+		return err
+	}
+
+	result, err := b.conn.Exec(`
+		UPDATE image_resource_versions
+		SET version = $1, resource_hash = $4
+		WHERE build_id = $2 AND plan_id = $3
+	`, version, b.id, string(planID), identifier.ResourceHash)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		_, err := b.conn.Exec(`
+			INSERT INTO image_resource_versions(version, build_id, plan_id, resource_hash)
+			VALUES ($1, $2, $3, $4)
+		`, version, b.id, string(planID), identifier.ResourceHash)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (b *build) GetImageResourceCacheIdentifiers() ([]ResourceCacheIdentifier, error) {
+	rows, err := b.conn.Query(`
+  	SELECT version, resource_hash
+  	FROM image_resource_versions
+  	WHERE build_id = $1
+  `, b.id)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var identifiers []ResourceCacheIdentifier
+
+	for rows.Next() {
+		var identifier ResourceCacheIdentifier
+		var marshalledVersion []byte
+
+		err := rows.Scan(&marshalledVersion, &identifier.ResourceHash)
+		if err != nil {
+			return nil, err
+		}
+
+		err = json.Unmarshal(marshalledVersion, &identifier.ResourceVersion)
+		if err != nil {
+			return nil, err
+		}
+
+		identifiers = append(identifiers, identifier)
+	}
+
+	return identifiers, nil
+}
+
+func (b *build) AcquireTrackingLock(logger lager.Logger, interval time.Duration) (Lock, bool, error) {
+	tx, err := b.conn.Begin()
+	if err != nil {
+		return nil, false, err
+	}
+
+	defer tx.Rollback()
+
+	updated, err := checkIfRowsUpdated(tx, `
+		UPDATE builds
+		SET last_tracked = now()
+		WHERE id = $1
+			AND now() - last_tracked > ($2 || ' SECONDS')::INTERVAL
+	`, b.id, interval.Seconds())
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !updated {
+		return nil, false, nil
+	}
+
+	lock := b.lockFactory.NewLock(
+		logger.Session("lock", lager.Data{
+			"build_id": b.id,
+		}),
+		buildTrackingLockID(b.id),
+	)
+
+	acquired, err := lock.Acquire()
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !acquired {
+		return nil, false, nil
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		lock.Release()
+		return nil, false, err
+	}
+
+	return lock, true, nil
+}
+
+// HandOff gives up on tracking the build before its lease is up, and tells
+// every ATC listening on BuildHandoffChannel that it needs tracking, so that
+// one of them can pick it up right away rather than once the lease expires.
+// The tracking lock should already be released.
+func (b *build) HandOff() error {
+	tx, err := b.conn.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		UPDATE builds
+		SET last_tracked = 'epoch'
+		WHERE id = $1
+	`, b.id)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`SELECT pg_notify($1, $2)`, BuildHandoffChannel, strconv.Itoa(b.id))
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (b *build) GetConfig() (atc.Config, ConfigVersion, error) {
+	var configBlob []byte
+	var version int
+	err := b.conn.QueryRow(`
+			SELECT p.config, p.version
+			FROM builds b
+			INNER JOIN jobs j ON b.job_id = j.id
+			INNER JOIN pipelines p ON j.pipeline_id = p.id
+			WHERE b.id = $1
+		`, b.id).Scan(&configBlob, &version)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return atc.Config{}, 0, nil
+		} else {
+			return atc.Config{}, 0, err
+		}
+	}
+
+	decrypted, err := b.conn.EncryptionStrategy().Decrypt(string(configBlob))
+	if err != nil {
+		return atc.Config{}, 0, err
+	}
+
+	var config atc.Config
+	err = json.Unmarshal(decrypted, &config)
+	if err != nil {
+		return atc.Config{}, 0, err
+	}
+
+	return config, ConfigVersion(version), nil
+}
+
+func (b *build) GetPipeline() (SavedPipeline, error) {
+	if b.IsOneOff() {
+		return SavedPipeline{}, nil
+	}
+
+	row := b.conn.QueryRow(`
+		SELECT `+pipelineColumns+`
+		FROM pipelines p
+		INNER JOIN teams t ON t.id = p.team_id
+		WHERE p.id = $1
+	`, b.pipelineID)
+
+	return scanPipeline(row, b.conn.EncryptionStrategy())
+}
+
+// GetPreviousSuccessfulBuild returns the last build of the same job that was
+// created before this one and succeeded. One-off builds have none.
+func (b *build) GetPreviousSuccessfulBuild() (Build, bool, error) {
+	if b.IsOneOff() {
+		return nil, false, nil
+	}
+
+	buildFactory := newBuildFactory(b.conn, b.bus, b.lockFactory)
+	return buildFactory.ScanBuild(b.conn.QueryRow(`
+		SELECT `+qualifiedBuildColumns+`
+		FROM builds b
+		INNER JOIN jobs j ON b.job_id = j.id
+		INNER JOIN pipelines p ON j.pipeline_id = p.id
+		INNER JOIN teams t ON b.team_id = t.id
+		WHERE b.job_id = (SELECT job_id FROM builds WHERE id = $1)
+		AND b.id < $1
+		AND b.status = $2
+		ORDER BY b.id DESC
+		LIMIT 1
+	`, b.id, string(StatusSucceeded)))
+}
+
+func newConditionNotifier(bus NotificationsBus, channel string, cond func() (bool, error)) (Notifier, error) {
 	notified, err := bus.Listen(channel)
 	if err != nil {
 		return nil, err
@@ -966,15 +1858,78 @@ func (b *build) saveEvent(tx Tx, event atc.Event) error {
 		return err
 	}
 
-	table := "build_events"
-	if b.pipelineID != 0 {
-		table = fmt.Sprintf("pipeline_build_events_%d", b.pipelineID)
+	table := buildEventsTable(b.pipelineID)
+
+	compressedPayload, err := compressEventPayload(payload)
+	if err != nil {
+		return err
 	}
 
-	_, err = tx.Exec(fmt.Sprintf(`
-		INSERT INTO %s (event_id, build_id, type, version, payload)
-		VALUES (nextval('%s'), $1, $2, $3, $4)
-	`, table, buildEventSeq(b.id)), b.id, string(event.EventType()), string(event.Version()), payload)
+	nextEventID := fmt.Sprintf("nextval('%s')", buildEventSeq(b.id))
+
+	var signature []byte
+	if key := b.conn.EventSigningKey(); key != nil {
+		var signedEventID int64
+		signedEventID, signature, err = b.chainEvent(tx, table, key, event, payload)
+		if err != nil {
+			return err
+		}
+
+		nextEventID = strconv.FormatInt(signedEventID, 10)
+	}
+
+	var eventID int64
+	var savedAt time.Time
+	err = tx.QueryRow(fmt.Sprintf(`
+		INSERT INTO %s (event_id, build_id, type, version, compressed_payload, log_search, signature)
+		VALUES (%s, $1, $2, $3, $4, to_tsvector('simple', $5), NULLIF($6, ''::bytea))
+		RETURNING event_id, time
+	`, table, nextEventID), b.id, string(event.EventType()), string(event.Version()), compressedPayload, logSearchText(event), signature).Scan(&eventID, &savedAt)
+	if err != nil {
+		return err
+	}
+
+	if signature != nil {
+		err = b.anchorEvent(tx, event, eventID, signature)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = b.trackStep(tx, event, savedAt)
+	if err != nil {
+		return err
+	}
+
+	data := json.RawMessage(payload)
+
+	notification, err := json.Marshal(eventNotification{
+		ID:      eventID,
+		Type:    event.EventType(),
+		Version: event.Version(),
+		Time:    savedAt,
+		Data:    &data,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(notification) > maxEventNotificationSize {
+		// too big to send along; listeners will read it from the table instead
+		notification, err = json.Marshal(eventNotification{
+			ID:      eventID,
+			Type:    event.EventType(),
+			Version: event.Version(),
+			Time:    savedAt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// sent as part of the transaction, so listeners only hear of the event
+	// once it has been committed
+	_, err = tx.Exec(`SELECT pg_notify($1, $2)`, buildEventsChannel(b.id), string(notification))
 	if err != nil {
 		return err
 	}
@@ -982,10 +1937,28 @@ func (b *build) saveEvent(tx Tx, event atc.Event) error {
 	return nil
 }
 
+// maxEventNotificationSize keeps event notifications comfortably under
+// Postgres's 8000 byte limit on NOTIFY payloads.
+const maxEventNotificationSize = 7000
+
+// eventNotification is the payload sent on a build's events channel for each
+// event saved. Data is left out of events too large to fit.
+type eventNotification struct {
+	ID      int64            `json:"id"`
+	Type    atc.EventType    `json:"type"`
+	Version atc.EventVersion `json:"version"`
+	Time    time.Time        `json:"time"`
+	Data    *json.RawMessage `json:"data,omitempty"`
+}
+
 func buildAbortChannel(buildID int) string {
 	return fmt.Sprintf("build_abort_%d", buildID)
 }
 
+func buildApprovalChannel(buildID int) string {
+	return fmt.Sprintf("build_approval_%d", buildID)
+}
+
 func buildEventsChannel(buildID int) string {
 	return fmt.Sprintf("build_events_%d", buildID)
 }
@@ -0,0 +1,157 @@
+package db
+
+import (
+	"bytes"
+	"compress/flate"
+	"database/sql"
+	"io/ioutil"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/event"
+)
+
+const compressBatchSize = 1000
+
+// Build events are stored with their payloads deflated in
+// compressed_payload, leaving payload NULL. Events saved before compression
+// was introduced have payload set instead, until CompressBuildEvents gets to
+// them. Log events also keep their text as a tsvector in log_search, as they
+// can no longer be searched by their payload.
+
+func compressEventPayload(payload []byte) ([]byte, error) {
+	buf := new(bytes.Buffer)
+
+	writer, err := flate.NewWriter(buf, flate.DefaultCompression)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = writer.Write(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// eventPayload returns the payload of an event as it was saved, whichever
+// way it's stored.
+func eventPayload(payload sql.NullString, compressedPayload []byte) ([]byte, error) {
+	if payload.Valid {
+		return []byte(payload.String), nil
+	}
+
+	reader := flate.NewReader(bytes.NewReader(compressedPayload))
+	defer reader.Close()
+
+	return ioutil.ReadAll(reader)
+}
+
+// logSearchText is what a log event is searched by; other events aren't.
+func logSearchText(ev atc.Event) sql.NullString {
+	log, ok := ev.(event.Log)
+	if !ok {
+		return sql.NullString{}
+	}
+
+	return sql.NullString{String: log.Payload, Valid: true}
+}
+
+// CompressBuildEvents compresses the payloads of build events saved before
+// they were compressed as they're saved. Events are compressed in batches,
+// each in its own transaction, so it can be stopped and run again, while
+// ATCs are running.
+func CompressBuildEvents(logger lager.Logger, conn Conn) error {
+	var afterBuildID, afterEventID int64 = 0, -1
+
+	compressed := 0
+
+	for {
+		rows, err := conn.Query(`
+			SELECT build_id, event_id, payload
+			FROM build_events
+			WHERE payload IS NOT NULL
+				AND (build_id, event_id) > ($1, $2)
+			ORDER BY build_id, event_id
+			LIMIT $3
+		`, afterBuildID, afterEventID, compressBatchSize)
+		if err != nil {
+			return err
+		}
+
+		type storedEvent struct {
+			buildID int64
+			eventID int64
+			payload string
+		}
+
+		batch := []storedEvent{}
+
+		for rows.Next() {
+			var stored storedEvent
+			err := rows.Scan(&stored.buildID, &stored.eventID, &stored.payload)
+			if err != nil {
+				rows.Close()
+				return err
+			}
+
+			batch = append(batch, stored)
+
+			afterBuildID = stored.buildID
+			afterEventID = stored.eventID
+		}
+
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return err
+		}
+
+		if len(batch) == 0 {
+			logger.Info("compressed", lager.Data{"events": compressed})
+			return nil
+		}
+
+		tx, err := conn.Begin()
+		if err != nil {
+			return err
+		}
+
+		for _, stored := range batch {
+			compressedPayload, err := compressEventPayload([]byte(stored.payload))
+			if err != nil {
+				tx.Rollback()
+				return err
+			}
+
+			_, err = tx.Exec(`
+				UPDATE build_events
+				SET compressed_payload = $1,
+					log_search = CASE WHEN type = 'log' THEN `+logSearchVector+` END,
+					payload = NULL
+				WHERE build_id = $2
+					AND event_id = $3
+					AND payload IS NOT NULL
+			`, compressedPayload, stored.buildID, stored.eventID)
+			if err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+
+		err = tx.Commit()
+		if err != nil {
+			return err
+		}
+
+		compressed += len(batch)
+
+		logger.Debug("compressed-batch", lager.Data{"events": compressed})
+	}
+}
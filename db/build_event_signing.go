@@ -0,0 +1,238 @@
+package db
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/event"
+)
+
+// Signed build events each carry an HMAC of the event and the signature of
+// the event before it, so that changing, removing or reordering any of them
+// breaks the chain from there on. Every eventAnchorInterval events, and at
+// each status event, the head of the chain is also kept in
+// build_event_anchors, so that removing events from the end of the chain can
+// be noticed too.
+const eventAnchorInterval = 100
+
+var ErrEventSigningNotConfigured = errors.New("build events are not being signed")
+
+// EventChainVerification is the outcome of checking a build's events against
+// their signatures.
+type EventChainVerification struct {
+	// Unsigned events were saved before signing was turned on, so can only
+	// come before the signed ones.
+	UnsignedEvents int
+	SignedEvents   int
+	Anchors        int
+
+	Valid bool
+
+	// BrokenAt is the first event at which the chain doesn't hold, and Reason
+	// says why, if it's not Valid.
+	BrokenAt int64
+	Reason   string
+}
+
+func signEvent(key []byte, previous []byte, eventID int64, eventType atc.EventType, version atc.EventVersion, payload []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+
+	mac.Write([]byte{byte(len(previous))})
+	mac.Write(previous)
+
+	binary.Write(mac, binary.BigEndian, eventID)
+
+	mac.Write([]byte(eventType))
+	mac.Write([]byte{0})
+	mac.Write([]byte(version))
+	mac.Write([]byte{0})
+	mac.Write(payload)
+
+	return mac.Sum(nil)
+}
+
+// chainEvent takes the ID of the build's next event and signs it, along with
+// the event before it. The build is locked for the rest of the transaction,
+// so that events saved at the same time can't both follow the same one.
+func (b *build) chainEvent(tx Tx, table string, key []byte, ev atc.Event, payload []byte) (int64, []byte, error) {
+	_, err := tx.Exec(`
+		SELECT 1
+		FROM builds
+		WHERE id = $1
+		FOR UPDATE
+	`, b.id)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var previous []byte
+	err = tx.QueryRow(`
+		SELECT signature
+		FROM `+table+`
+		WHERE build_id = $1
+		ORDER BY event_id DESC
+		LIMIT 1
+	`, b.id).Scan(&previous)
+	if err != nil && err != sql.ErrNoRows {
+		return 0, nil, err
+	}
+
+	var eventID int64
+	err = tx.QueryRow(fmt.Sprintf(`SELECT nextval('%s')`, buildEventSeq(b.id))).Scan(&eventID)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return eventID, signEvent(key, previous, eventID, ev.EventType(), ev.Version(), payload), nil
+}
+
+func (b *build) anchorEvent(tx Tx, ev atc.Event, eventID int64, signature []byte) error {
+	_, isStatus := ev.(event.Status)
+	if !isStatus && eventID%eventAnchorInterval != 0 {
+		return nil
+	}
+
+	_, err := tx.Exec(`
+		INSERT INTO build_event_anchors (build_id, event_id, signature)
+		VALUES ($1, $2, $3)
+	`, b.id, eventID, signature)
+	return err
+}
+
+// VerifyEvents goes through the build's events in order, checking that each
+// signed one follows the one before it, and that every anchor is still part
+// of the chain.
+func (b *build) VerifyEvents() (EventChainVerification, error) {
+	key := b.conn.EventSigningKey()
+	if key == nil {
+		return EventChainVerification{}, ErrEventSigningNotConfigured
+	}
+
+	table := buildEventsTable(b.pipelineID)
+
+	anchors, err := b.eventAnchors()
+	if err != nil {
+		return EventChainVerification{}, err
+	}
+
+	rows, err := b.conn.Query(`
+		SELECT event_id, type, version, payload, compressed_payload, signature
+		FROM `+table+`
+		WHERE build_id = $1
+		ORDER BY event_id ASC
+	`, b.id)
+	if err != nil {
+		return EventChainVerification{}, err
+	}
+
+	defer rows.Close()
+
+	verification := EventChainVerification{
+		Anchors: len(anchors),
+		Valid:   true,
+	}
+
+	broken := func(eventID int64, reason string) {
+		if verification.Valid {
+			verification.Valid = false
+			verification.BrokenAt = eventID
+			verification.Reason = reason
+		}
+	}
+
+	var previous []byte
+
+	for rows.Next() {
+		var eventID int64
+		var eventType, version string
+		var payload sql.NullString
+		var compressedPayload, signature []byte
+		err := rows.Scan(&eventID, &eventType, &version, &payload, &compressedPayload, &signature)
+		if err != nil {
+			return EventChainVerification{}, err
+		}
+
+		if len(signature) == 0 {
+			if verification.SignedEvents > 0 {
+				broken(eventID, "unsigned event after signed events")
+			}
+
+			verification.UnsignedEvents++
+			continue
+		}
+
+		verification.SignedEvents++
+
+		data, err := eventPayload(payload, compressedPayload)
+		if err != nil {
+			return EventChainVerification{}, err
+		}
+
+		expected := signEvent(key, previous, eventID, atc.EventType(eventType), atc.EventVersion(version), data)
+		if !hmac.Equal(signature, expected) {
+			broken(eventID, "signature does not match")
+		}
+
+		if anchor, found := anchors[eventID]; found {
+			if !hmac.Equal(anchor, signature) {
+				broken(eventID, "signature does not match anchor")
+			}
+
+			delete(anchors, eventID)
+		}
+
+		previous = signature
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return EventChainVerification{}, err
+	}
+
+	// whatever's left was anchored but is no longer there; earliest first
+	missing := int64(-1)
+	for eventID := range anchors {
+		if missing == -1 || eventID < missing {
+			missing = eventID
+		}
+	}
+
+	if missing != -1 {
+		broken(missing, "anchored event is missing")
+	}
+
+	return verification, nil
+}
+
+func (b *build) eventAnchors() (map[int64][]byte, error) {
+	rows, err := b.conn.Query(`
+		SELECT event_id, signature
+		FROM build_event_anchors
+		WHERE build_id = $1
+	`, b.id)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	anchors := map[int64][]byte{}
+
+	for rows.Next() {
+		var eventID int64
+		var signature []byte
+		err := rows.Scan(&eventID, &signature)
+		if err != nil {
+			return nil, err
+		}
+
+		anchors[eventID] = signature
+	}
+
+	return anchors, rows.Err()
+}
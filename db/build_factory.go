@@ -2,11 +2,13 @@ package db
 
 import (
 	"database/sql"
+	"encoding/json"
+	"time"
 
 	"github.com/lib/pq"
 )
 
-func newBuildFactory(conn Conn, bus *notificationsBus, lockFactory LockFactory) *buildFactory {
+func newBuildFactory(conn Conn, bus NotificationsBus, lockFactory LockFactory) *buildFactory {
 	return &buildFactory{
 		conn:        conn,
 		lockFactory: lockFactory,
@@ -16,7 +18,7 @@ func newBuildFactory(conn Conn, bus *notificationsBus, lockFactory LockFactory)
 
 type buildFactory struct {
 	conn Conn
-	bus  *notificationsBus
+	bus  NotificationsBus
 
 	lockFactory LockFactory
 }
@@ -24,16 +26,20 @@ type buildFactory struct {
 func (f *buildFactory) ScanBuild(row scannable) (Build, bool, error) {
 	var id int
 	var name string
-	var jobID, pipelineID, teamID sql.NullInt64
+	var jobID, pipelineID, teamID, rerunOf, timeout sql.NullInt64
 	var status string
 	var scheduled bool
 	var engine, engineMetadata, jobName, pipelineName sql.NullString
 	var startTime pq.NullTime
 	var endTime pq.NullTime
 	var reapTime pq.NullTime
+	var labels, params sql.NullString
+	var logTruncated bool
+	var priority int
+	var resourceUsage ResourceUsage
 	var teamName string
 
-	err := row.Scan(&id, &name, &jobID, &teamID, &status, &scheduled, &engine, &engineMetadata, &startTime, &endTime, &reapTime, &jobName, &pipelineID, &pipelineName, &teamName)
+	err := row.Scan(&id, &name, &jobID, &teamID, &status, &scheduled, &engine, &engineMetadata, &startTime, &endTime, &reapTime, &labels, &logTruncated, &priority, &resourceUsage.CPUNanoseconds, &resourceUsage.MemoryBytes, &resourceUsage.DiskBytes, &rerunOf, &timeout, &params, &jobName, &pipelineID, &pipelineName, &teamName)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, false, nil
@@ -52,16 +58,32 @@ func (f *buildFactory) ScanBuild(row scannable) (Build, bool, error) {
 		status:    Status(status),
 		scheduled: scheduled,
 
-		engine:         engine.String,
-		engineMetadata: engineMetadata.String,
+		engine: engine.String,
 
 		startTime: startTime.Time,
 		endTime:   endTime.Time,
 		reapTime:  reapTime.Time,
 
+		logTruncated: logTruncated,
+
+		priority: priority,
+
+		resourceUsage: resourceUsage,
+
 		teamName: teamName,
 	}
 
+	strategy := f.conn.EncryptionStrategy()
+
+	if engineMetadata.Valid {
+		metadata, err := strategy.Decrypt(engineMetadata.String)
+		if err != nil {
+			return nil, false, err
+		}
+
+		build.engineMetadata = string(metadata)
+	}
+
 	if jobID.Valid {
 		build.jobName = jobName.String
 		build.pipelineName = pipelineName.String
@@ -72,5 +94,32 @@ func (f *buildFactory) ScanBuild(row scannable) (Build, bool, error) {
 		build.teamID = int(teamID.Int64)
 	}
 
+	if rerunOf.Valid {
+		build.rerunOf = int(rerunOf.Int64)
+	}
+
+	if timeout.Valid {
+		build.timeout = time.Duration(timeout.Int64) * time.Second
+	}
+
+	if labels.Valid {
+		err = json.Unmarshal([]byte(labels.String), &build.labels)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	if params.Valid {
+		decrypted, err := strategy.Decrypt(params.String)
+		if err != nil {
+			return nil, false, err
+		}
+
+		err = json.Unmarshal(decrypted, &build.params)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
 	return build, true, nil
 }
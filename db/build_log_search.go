@@ -0,0 +1,119 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/concourse/atc/event"
+)
+
+// logSearchVector must match the expression the log search indexes were
+// created with, or Postgres won't use them.
+const logSearchVector = "to_tsvector('simple', (payload::json)->>'payload')"
+
+// logSearchMatches matches log events against the query in $2, whether
+// they're compressed or not.
+const logSearchMatches = logSearchVector + " @@ plainto_tsquery('simple', $2) OR log_search @@ plainto_tsquery('simple', $2)"
+
+// LogMatch is a log event that matched a search.
+type LogMatch struct {
+	BuildID int
+	EventID uint
+	Origin  event.Origin
+	Payload string
+}
+
+func scanLogMatches(rows *sql.Rows) ([]LogMatch, error) {
+	matches := []LogMatch{}
+
+	for rows.Next() {
+		var match LogMatch
+		var payload sql.NullString
+		var compressedPayload []byte
+
+		err := rows.Scan(&match.BuildID, &match.EventID, &payload, &compressedPayload)
+		if err != nil {
+			return nil, err
+		}
+
+		decompressed, err := eventPayload(payload, compressedPayload)
+		if err != nil {
+			return nil, err
+		}
+
+		var log event.Log
+		err = json.Unmarshal(decompressed, &log)
+		if err != nil {
+			return nil, fmt.Errorf("malformed log event %d of build %d: %s", match.EventID, match.BuildID, err)
+		}
+
+		match.Origin = log.Origin
+		match.Payload = log.Payload
+
+		matches = append(matches, match)
+	}
+
+	return matches, rows.Err()
+}
+
+func (b *build) SearchLogs(query string, limit int) ([]LogMatch, error) {
+	table := buildEventsTable(b.pipelineID)
+
+	rows, err := b.conn.Query(fmt.Sprintf(`
+		SELECT build_id, event_id, payload, compressed_payload
+		FROM %s
+		WHERE build_id = $1
+		AND type = 'log'
+		AND (`+logSearchMatches+`)
+		ORDER BY event_id ASC
+		LIMIT $3
+	`, table), b.id, query, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return scanLogMatches(rows)
+}
+
+// SearchBuildLogs searches the logs of every one of the team's builds, other
+// than those of the hidden pipelines.
+func (db *teamDB) SearchBuildLogs(query string, limit int, hiddenPipelineIDs []int) ([]LogMatch, error) {
+	args := []interface{}{db.teamName, query, limit}
+
+	notHidden := ""
+	if len(hiddenPipelineIDs) > 0 {
+		placeholders := make([]string, len(hiddenPipelineIDs))
+		for i, pipelineID := range hiddenPipelineIDs {
+			placeholders[i] = "$" + strconv.Itoa(len(args)+1)
+			args = append(args, pipelineID)
+		}
+
+		notHidden = "AND (j.pipeline_id IS NULL OR j.pipeline_id NOT IN (" + strings.Join(placeholders, ",") + "))"
+	}
+
+	rows, err := db.conn.Query(`
+		SELECT e.build_id, e.event_id, e.payload, e.compressed_payload
+		FROM build_events e
+		INNER JOIN builds b ON b.id = e.build_id
+		INNER JOIN teams t ON t.id = b.team_id
+		LEFT JOIN jobs j ON j.id = b.job_id
+		WHERE LOWER(t.name) = LOWER($1)
+		AND e.type = 'log'
+		AND (`+logSearchMatches+`)
+		`+notHidden+`
+		ORDER BY e.build_id DESC, e.event_id ASC
+		LIMIT $3
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return scanLogMatches(rows)
+}
@@ -0,0 +1,186 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BuildPrune is the last time the build reaper deleted builds past their
+// retention, and how many it deleted.
+type BuildPrune struct {
+	PrunedAt time.Time
+	Builds   int
+}
+
+// prunableBuild matches finished builds other than each job's latest build
+// and latest successful build, which are kept so that the job still shows
+// its status and passed constraints still have a version to go by.
+const prunableBuild = `
+	b.completed = true
+	AND b.id NOT IN (
+		SELECT MAX(id)
+		FROM builds
+		WHERE job_id IS NOT NULL
+		GROUP BY job_id
+	)
+	AND b.id NOT IN (
+		SELECT MAX(id)
+		FROM builds
+		WHERE job_id IS NOT NULL
+		AND status = 'succeeded'
+		GROUP BY job_id
+	)
+`
+
+// DeleteBuildsBefore deletes at most limit builds that finished before the
+// time, oldest first, along with their events, inputs and outputs. It
+// returns how many builds it deleted.
+func (db *SQLDB) DeleteBuildsBefore(finishedBefore time.Time, limit int) (int, error) {
+	return db.deleteBuilds(`
+		SELECT b.id
+		FROM builds b
+		WHERE `+prunableBuild+`
+		AND b.end_time < $1
+		ORDER BY b.id ASC
+		LIMIT $2
+	`, finishedBefore, limit)
+}
+
+// DeleteBuildsBeyond deletes at most limit builds of jobs that have had more
+// than buildsPerJob builds since, oldest first, along with their events,
+// inputs and outputs. It returns how many builds it deleted.
+func (db *SQLDB) DeleteBuildsBeyond(buildsPerJob int, limit int) (int, error) {
+	return db.deleteBuilds(`
+		SELECT b.id
+		FROM (
+			SELECT *, row_number() OVER (PARTITION BY job_id ORDER BY id DESC) AS newer
+			FROM builds
+			WHERE job_id IS NOT NULL
+		) b
+		WHERE `+prunableBuild+`
+		AND b.newer > $1
+		ORDER BY b.id ASC
+		LIMIT $2
+	`, buildsPerJob, limit)
+}
+
+func (db *SQLDB) deleteBuilds(query string, args ...interface{}) (int, error) {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return 0, err
+	}
+
+	defer tx.Rollback()
+
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	buildIDs := []interface{}{}
+	for rows.Next() {
+		var id int
+		err := rows.Scan(&id)
+		if err != nil {
+			rows.Close()
+			return 0, err
+		}
+
+		buildIDs = append(buildIDs, id)
+	}
+
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return 0, err
+	}
+
+	if len(buildIDs) == 0 {
+		return 0, nil
+	}
+
+	indexStrings := make([]string, len(buildIDs))
+	for i := range indexStrings {
+		indexStrings[i] = "$" + strconv.Itoa(i+1)
+	}
+
+	// the per-pipeline event tables inherit from build_events, but not its
+	// foreign key, so their events have to be deleted by hand
+	_, err = tx.Exec(`
+		DELETE FROM build_events
+		WHERE build_id IN (`+strings.Join(indexStrings, ",")+`)
+	`, buildIDs...)
+	if err != nil {
+		return 0, err
+	}
+
+	// everything else of the builds' goes with them
+	_, err = tx.Exec(`
+		DELETE FROM builds
+		WHERE id IN (`+strings.Join(indexStrings, ",")+`)
+	`, buildIDs...)
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, err
+	}
+
+	return len(buildIDs), nil
+}
+
+func (db *SQLDB) SaveBuildPrune(prune BuildPrune) error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE last_build_prune
+		SET pruned_at = $1, builds = $2
+	`, prune.PrunedAt, prune.Builds)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		_, err = tx.Exec(`
+			INSERT INTO last_build_prune (pruned_at, builds)
+			VALUES ($1, $2)
+		`, prune.PrunedAt, prune.Builds)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (db *SQLDB) GetLastBuildPrune(ctx context.Context) (BuildPrune, bool, error) {
+	var prune BuildPrune
+	err := db.conn.QueryRowContext(ctx, `
+		SELECT pruned_at, builds
+		FROM last_build_prune
+	`).Scan(&prune.PrunedAt, &prune.Builds)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return BuildPrune{}, false, nil
+		}
+
+		return BuildPrune{}, false, err
+	}
+
+	return prune, true, nil
+}
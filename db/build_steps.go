@@ -0,0 +1,198 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/event"
+	"github.com/lib/pq"
+)
+
+type BuildStepStatus string
+
+const (
+	BuildStepStarted   BuildStepStatus = "started"
+	BuildStepSucceeded BuildStepStatus = "succeeded"
+	BuildStepFailed    BuildStepStatus = "failed"
+	BuildStepErrored   BuildStepStatus = "errored"
+	BuildStepAborted   BuildStepStatus = "aborted"
+)
+
+// BuildStep is how far one step of a build has got, as told by the events it
+// has saved. Steps are identified by the ID of their plan, which is also the
+// origin of their events.
+//
+// Type is empty for steps that errored before saying what they were.
+type BuildStep struct {
+	PlanID atc.PlanID
+	Type   string
+	Status BuildStepStatus
+
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+const buildStepColumns = "plan_id, type, status, started_at, finished_at"
+
+func (b *build) GetSteps() ([]BuildStep, error) {
+	rows, err := b.conn.Query(`
+		SELECT `+buildStepColumns+`
+		FROM build_steps
+		WHERE build_id = $1
+		ORDER BY started_at ASC, plan_id ASC
+	`, b.id)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	steps := []BuildStep{}
+
+	for rows.Next() {
+		step, err := scanBuildStep(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		steps = append(steps, step)
+	}
+
+	return steps, nil
+}
+
+// trackStep keeps build_steps up to date with an event that was just saved
+// at the given time. Events that aren't about a step's progress are ignored.
+func (b *build) trackStep(tx Tx, ev atc.Event, at time.Time) error {
+	switch e := ev.(type) {
+	case event.InitializeTask:
+		return b.startStep(tx, e.Origin.ID, "task", at)
+	case event.InitializeGet:
+		return b.startStep(tx, e.Origin.ID, "get", at)
+	case event.InitializePut:
+		return b.startStep(tx, e.Origin.ID, "put", at)
+	case event.ApprovalRequested:
+		return b.startStep(tx, e.Origin.ID, "approval", at)
+
+	case event.FinishTask:
+		return b.finishStep(tx, e.Origin.ID, "task", exitStepStatus(e.ExitStatus), at)
+	case event.FinishGet:
+		return b.finishStep(tx, e.Origin.ID, "get", exitStepStatus(e.ExitStatus), at)
+	case event.FinishPut:
+		return b.finishStep(tx, e.Origin.ID, "put", exitStepStatus(e.ExitStatus), at)
+	case event.ApprovalDecided:
+		status := BuildStepFailed
+		if e.Approved {
+			status = BuildStepSucceeded
+		}
+
+		return b.finishStep(tx, e.Origin.ID, "approval", status, at)
+
+	case event.Error:
+		// errors without an origin are the build's, not a step's
+		if e.Origin.ID == "" {
+			return nil
+		}
+
+		return b.finishStep(tx, e.Origin.ID, "", BuildStepErrored, at)
+
+	case event.Status:
+		switch e.Status {
+		case atc.StatusPending, atc.StatusStarted:
+			return nil
+		}
+
+		// anything still running when the build finishes was cut short
+		status := BuildStepErrored
+		if e.Status == atc.StatusAborted || e.Status == atc.StatusTimedOut {
+			status = BuildStepAborted
+		}
+
+		_, err := tx.Exec(`
+			UPDATE build_steps
+			SET status = $2, finished_at = $3
+			WHERE build_id = $1
+			AND finished_at IS NULL
+		`, b.id, string(status), at)
+		return err
+	}
+
+	return nil
+}
+
+// startStep is fine to call again for a step that has already started, e.g.
+// when the build is resumed by another ATC; it is left as it was.
+func (b *build) startStep(tx Tx, id event.OriginID, stepType string, at time.Time) error {
+	_, err := tx.Exec(`
+		INSERT INTO build_steps (build_id, plan_id, type, started_at)
+		SELECT $1, $2, $3, $4
+		WHERE NOT EXISTS (
+			SELECT 1 FROM build_steps WHERE build_id = $1 AND plan_id = $2
+		)
+	`, b.id, string(id), stepType, at)
+	return err
+}
+
+// finishStep also starts the step if it never said it had, so that steps
+// which fail to even initialize still show up.
+func (b *build) finishStep(tx Tx, id event.OriginID, stepType string, status BuildStepStatus, at time.Time) error {
+	result, err := tx.Exec(`
+		UPDATE build_steps
+		SET status = $3, finished_at = $4, type = COALESCE(type, NULLIF($5, ''))
+		WHERE build_id = $1
+		AND plan_id = $2
+		AND finished_at IS NULL
+	`, b.id, string(id), string(status), at, stepType)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows > 0 {
+		return nil
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO build_steps (build_id, plan_id, type, status, started_at, finished_at)
+		SELECT $1, $2, NULLIF($3, ''), $4, $5, $5
+		WHERE NOT EXISTS (
+			SELECT 1 FROM build_steps WHERE build_id = $1 AND plan_id = $2
+		)
+	`, b.id, string(id), stepType, string(status), at)
+	return err
+}
+
+func exitStepStatus(exitStatus int) BuildStepStatus {
+	if exitStatus == 0 {
+		return BuildStepSucceeded
+	}
+
+	return BuildStepFailed
+}
+
+func scanBuildStep(row scannable) (BuildStep, error) {
+	var step BuildStep
+	var planID, status string
+	var stepType sql.NullString
+	var finishedAt pq.NullTime
+
+	err := row.Scan(&planID, &stepType, &status, &step.StartedAt, &finishedAt)
+	if err != nil {
+		return BuildStep{}, err
+	}
+
+	step.PlanID = atc.PlanID(planID)
+	step.Type = stepType.String
+	step.Status = BuildStepStatus(status)
+
+	if finishedAt.Valid {
+		step.FinishedAt = finishedAt.Time
+	}
+
+	return step, nil
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"code.cloudfoundry.org/lager/lagertest"
@@ -21,6 +22,8 @@ import (
 var _ = Describe("Build", func() {
 	var dbConn db.Conn
 	var listener *pq.Listener
+	var bus db.NotificationsBus
+	var lockFactory db.LockFactory
 
 	var teamDB db.TeamDB
 	var pipelineDB db.PipelineDB
@@ -35,13 +38,13 @@ var _ = Describe("Build", func() {
 		listener = pq.NewListener(postgresRunner.DataSourceName(), time.Second, time.Minute, nil)
 
 		Eventually(listener.Ping, 5*time.Second).ShouldNot(HaveOccurred())
-		bus := db.NewNotificationsBus(listener, dbConn)
+		bus = db.NewNotificationsBus(listener, dbConn)
 
 		pgxConn := postgresRunner.OpenPgx()
 		fakeConnector := new(dbfakes.FakeConnector)
 		retryableConn := &db.RetryableConn{Connector: fakeConnector, Conn: pgxConn}
 
-		lockFactory := db.NewLockFactory(retryableConn)
+		lockFactory = db.NewLockFactory(retryableConn)
 		teamDBFactory := db.NewTeamDBFactory(dbConn, bus, lockFactory)
 		teamDB = teamDBFactory.GetTeamDB(atc.DefaultTeamName)
 
@@ -71,7 +74,7 @@ var _ = Describe("Build", func() {
 		}
 
 		var err error
-		pipeline, _, err = teamDB.SaveConfig("some-pipeline", pipelineConfig, db.ConfigVersion(1), db.PipelineUnpaused)
+		pipeline, _, err = teamDB.SaveConfig("some-pipeline", pipelineConfig, db.ConfigVersion(1), db.PipelineUnpaused, "some-author")
 		Expect(err).NotTo(HaveOccurred())
 
 		pipelineDBFactory := db.NewPipelineDBFactory(dbConn, bus, lockFactory)
@@ -117,6 +120,849 @@ var _ = Describe("Build", func() {
 		})
 	})
 
+	Describe("MarkLogTruncated", func() {
+		It("records that the build's log was truncated", func() {
+			build, err := teamDB.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(build.LogTruncated()).To(BeFalse())
+
+			err = build.MarkLogTruncated()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(build.LogTruncated()).To(BeTrue())
+
+			found, err := build.Reload()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(build.LogTruncated()).To(BeTrue())
+		})
+	})
+
+	Describe("SaveResourceUsage", func() {
+		It("adds up the usage of each container", func() {
+			build, err := teamDB.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(build.ResourceUsage()).To(BeZero())
+
+			err = build.SaveResourceUsage(db.ResourceUsage{
+				CPUNanoseconds: 100,
+				MemoryBytes:    4096,
+				DiskBytes:      10,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = build.SaveResourceUsage(db.ResourceUsage{
+				CPUNanoseconds: 50,
+				MemoryBytes:    1024,
+				DiskBytes:      5,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			expectedUsage := db.ResourceUsage{
+				CPUNanoseconds: 150,
+				MemoryBytes:    4096,
+				DiskBytes:      15,
+			}
+
+			Expect(build.ResourceUsage()).To(Equal(expectedUsage))
+
+			found, err := build.Reload()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(build.ResourceUsage()).To(Equal(expectedUsage))
+		})
+	})
+
+	Describe("SetPriority", func() {
+		var build db.Build
+
+		BeforeEach(func() {
+			var err error
+			build, err = pipelineDB.CreateJobBuild("some-job")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(build.Priority()).To(BeZero())
+		})
+
+		It("updates the priority of a pending build", func() {
+			updated, err := build.SetPriority(5)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updated).To(BeTrue())
+			Expect(build.Priority()).To(Equal(5))
+
+			found, err := build.Reload()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(build.Priority()).To(Equal(5))
+		})
+
+		It("orders the job's pending builds", func() {
+			laterBuild, err := pipelineDB.CreateJobBuild("some-job")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = laterBuild.SetPriority(1)
+			Expect(err).NotTo(HaveOccurred())
+
+			nextBuild, found, err := pipelineDB.GetNextPendingBuild("some-job")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(nextBuild.ID()).To(Equal(laterBuild.ID()))
+		})
+
+		Context("when the build has been scheduled", func() {
+			BeforeEach(func() {
+				scheduled, err := pipelineDB.UpdateBuildToScheduled(build.ID())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(scheduled).To(BeTrue())
+			})
+
+			It("does not update it", func() {
+				updated, err := build.SetPriority(5)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(updated).To(BeFalse())
+				Expect(build.Priority()).To(BeZero())
+			})
+		})
+	})
+
+	Describe("QueuePosition", func() {
+		var build db.Build
+
+		BeforeEach(func() {
+			pipelineConfig.Jobs = atc.JobConfigs{
+				{Name: "some-job", SerialGroups: []string{"some-group"}},
+				{Name: "some-other-job", SerialGroups: []string{"some-group"}},
+				{Name: "some-unrelated-job"},
+			}
+
+			_, _, err := teamDB.SaveConfig("some-pipeline", pipelineConfig, pipeline.Version, db.PipelineNoChange, "some-author")
+			Expect(err).NotTo(HaveOccurred())
+
+			earlierBuild, err := pipelineDB.CreateJobBuild("some-job")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(earlierBuild.Status()).To(Equal(db.StatusPending))
+
+			_, err = pipelineDB.CreateJobBuild("some-unrelated-job")
+			Expect(err).NotTo(HaveOccurred())
+
+			build, err = pipelineDB.CreateJobBuild("some-other-job")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("counts the pending builds ahead of it in its serial groups", func() {
+			position, queued, err := build.QueuePosition()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(queued).To(BeTrue())
+			Expect(position).To(Equal(2))
+		})
+
+		It("puts builds with a higher priority ahead", func() {
+			_, err := build.SetPriority(1)
+			Expect(err).NotTo(HaveOccurred())
+
+			position, _, err := build.QueuePosition()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(position).To(Equal(1))
+		})
+
+		It("does not count builds behind it", func() {
+			_, err := pipelineDB.CreateJobBuild("some-job")
+			Expect(err).NotTo(HaveOccurred())
+
+			position, _, err := build.QueuePosition()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(position).To(Equal(2))
+		})
+
+		Context("when the build is no longer pending", func() {
+			BeforeEach(func() {
+				started, err := build.Start("engine", "metadata")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(started).To(BeTrue())
+			})
+
+			It("is not queued", func() {
+				_, queued, err := build.QueuePosition()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(queued).To(BeFalse())
+			})
+		})
+
+		Context("when the build is a one-off", func() {
+			BeforeEach(func() {
+				var err error
+				build, err = teamDB.CreateOneOffBuild()
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("is not queued", func() {
+				_, queued, err := build.QueuePosition()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(queued).To(BeFalse())
+			})
+		})
+	})
+
+	Describe("SetTimeout", func() {
+		var build db.Build
+
+		BeforeEach(func() {
+			var err error
+			build, err = teamDB.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(build.Timeout()).To(BeZero())
+		})
+
+		It("saves the timeout", func() {
+			err := build.SetTimeout(90 * time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(build.Timeout()).To(Equal(90 * time.Minute))
+
+			found, err := build.Reload()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(build.Timeout()).To(Equal(90 * time.Minute))
+		})
+
+		It("can clear the timeout", func() {
+			err := build.SetTimeout(time.Hour)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = build.SetTimeout(0)
+			Expect(err).NotTo(HaveOccurred())
+
+			found, err := build.Reload()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(build.Timeout()).To(BeZero())
+		})
+	})
+
+	Describe("FinishNotifier", func() {
+		var build db.Build
+		var notifier db.Notifier
+
+		BeforeEach(func() {
+			var err error
+			build, err = teamDB.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+
+			notifier, err = build.FinishNotifier()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			notifier.Close()
+		})
+
+		It("notifies once the build finishes", func() {
+			_, err := build.Start("engine", "metadata")
+			Expect(err).NotTo(HaveOccurred())
+
+			Consistently(notifier.Notify()).ShouldNot(Receive())
+
+			err = build.Finish(db.StatusSucceeded)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(notifier.Notify()).Should(Receive())
+		})
+
+		It("notifies straight away if the build has already finished", func() {
+			err := build.Finish(db.StatusFailed)
+			Expect(err).NotTo(HaveOccurred())
+
+			finishedNotifier, err := build.FinishNotifier()
+			Expect(err).NotTo(HaveOccurred())
+
+			defer finishedNotifier.Close()
+
+			Eventually(finishedNotifier.Notify()).Should(Receive())
+		})
+	})
+
+	Describe("Secrets", func() {
+		var build db.Build
+
+		BeforeEach(func() {
+			var err error
+			build, err = teamDB.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("has none to begin with", func() {
+			secrets, err := build.GetSecrets()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(secrets).To(BeEmpty())
+		})
+
+		It("returns the secrets that were saved", func() {
+			err := build.SaveSecrets([]string{"some-password", "some-key"})
+			Expect(err).NotTo(HaveOccurred())
+
+			secrets, err := build.GetSecrets()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(secrets).To(Equal([]string{"some-password", "some-key"}))
+		})
+	})
+
+	Describe("log search", func() {
+		var jobBuild db.Build
+		var oneOffBuild db.Build
+
+		BeforeEach(func() {
+			var err error
+			jobBuild, err = pipelineDB.CreateJobBuild("some-job")
+			Expect(err).NotTo(HaveOccurred())
+
+			oneOffBuild, err = teamDB.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+
+			origin := event.Origin{ID: "some-step", Source: event.OriginSourceStderr}
+
+			Expect(jobBuild.SaveEvent(event.Log{Origin: origin, Payload: "cloning repository\n"})).To(Succeed())
+			Expect(jobBuild.SaveEvent(event.Log{Origin: origin, Payload: "dial tcp: connection refused\n"})).To(Succeed())
+			Expect(jobBuild.SaveEvent(event.Error{Message: "connection refused"})).To(Succeed())
+			Expect(oneOffBuild.SaveEvent(event.Log{Origin: origin, Payload: "connection refused again\n"})).To(Succeed())
+		})
+
+		Describe("SearchLogs", func() {
+			It("returns the build's matching log events", func() {
+				matches, err := jobBuild.SearchLogs("connection refused", 10)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(matches).To(Equal([]db.LogMatch{
+					{
+						BuildID: jobBuild.ID(),
+						EventID: 1,
+						Origin:  event.Origin{ID: "some-step", Source: event.OriginSourceStderr},
+						Payload: "dial tcp: connection refused\n",
+					},
+				}))
+
+				matches, err = oneOffBuild.SearchLogs("refused", 10)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(matches).To(HaveLen(1))
+				Expect(matches[0].BuildID).To(Equal(oneOffBuild.ID()))
+			})
+
+			It("returns nothing when nothing matches", func() {
+				matches, err := jobBuild.SearchLogs("segfault", 10)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(matches).To(BeEmpty())
+			})
+		})
+
+		Describe("SearchBuildLogs", func() {
+			It("searches every build of the team, newest first", func() {
+				matches, err := teamDB.SearchBuildLogs("refused", 10, nil)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(matches).To(HaveLen(2))
+				Expect(matches[0].BuildID).To(Equal(oneOffBuild.ID()))
+				Expect(matches[1].BuildID).To(Equal(jobBuild.ID()))
+			})
+
+			It("respects the limit", func() {
+				matches, err := teamDB.SearchBuildLogs("refused", 1, nil)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(matches).To(HaveLen(1))
+			})
+
+			It("leaves out the builds of hidden pipelines", func() {
+				matches, err := teamDB.SearchBuildLogs("refused", 10, []int{pipelineDB.GetPipelineID()})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(matches).To(HaveLen(1))
+				Expect(matches[0].BuildID).To(Equal(oneOffBuild.ID()))
+
+				matches, err = teamDB.SearchBuildLogs("refused", 10, []int{pipelineDB.GetPipelineID() + 1})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(matches).To(HaveLen(2))
+			})
+		})
+	})
+
+	Describe("event compression", func() {
+		var build db.Build
+
+		origin := event.Origin{ID: "some-step", Source: event.OriginSourceStdout}
+
+		saveUncompressedLog := func(payload string) {
+			data, err := json.Marshal(event.Log{Origin: origin, Payload: payload})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = dbConn.Exec(fmt.Sprintf(`
+				INSERT INTO build_events (event_id, build_id, type, version, payload)
+				VALUES (nextval('build_event_id_seq_%d'), $1, 'log', '5.0', $2)
+			`, build.ID()), build.ID(), string(data))
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		uncompressedEvents := func() int {
+			var count int
+			err := dbConn.QueryRow(`
+				SELECT COUNT(*)
+				FROM build_events
+				WHERE build_id = $1
+				AND payload IS NOT NULL
+			`, build.ID()).Scan(&count)
+			Expect(err).NotTo(HaveOccurred())
+			return count
+		}
+
+		itReadsEveryEvent := func() {
+			events, err := build.Events(0)
+			Expect(err).NotTo(HaveOccurred())
+
+			defer events.Close()
+
+			Expect(untimed(events.Next())).To(Equal(envelope(event.Log{Origin: origin, Payload: "saved before compression\n"})))
+			Expect(untimed(events.Next())).To(Equal(envelope(event.Log{Origin: origin, Payload: "saved compressed\n"})))
+
+			matches, err := build.SearchLogs("saved", 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(matches).To(HaveLen(2))
+		}
+
+		BeforeEach(func() {
+			var err error
+			build, err = teamDB.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+
+			saveUncompressedLog("saved before compression\n")
+
+			err = build.SaveEvent(event.Log{Origin: origin, Payload: "saved compressed\n"})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("saves events compressed", func() {
+			Expect(uncompressedEvents()).To(Equal(1))
+		})
+
+		It("reads and searches events however they're stored", func() {
+			itReadsEveryEvent()
+		})
+
+		Describe("CompressBuildEvents", func() {
+			BeforeEach(func() {
+				err := db.CompressBuildEvents(lagertest.NewTestLogger("test"), dbConn)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("compresses the events saved before compression", func() {
+				Expect(uncompressedEvents()).To(BeZero())
+			})
+
+			It("leaves them readable and searchable", func() {
+				itReadsEveryEvent()
+			})
+		})
+	})
+
+	Describe("Artifacts", func() {
+		var build db.Build
+
+		BeforeEach(func() {
+			var err error
+			build, err = teamDB.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("saves and returns artifacts by name", func() {
+			err := build.SaveArtifact(db.BuildArtifact{
+				Name:            "some-artifact",
+				ContainerHandle: "some-handle",
+				Path:            "/tmp/build/some-output",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = build.SaveArtifact(db.BuildArtifact{
+				Name:            "another-artifact",
+				ContainerHandle: "some-handle",
+				Path:            "/tmp/build/another-output",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			artifact, found, err := build.GetArtifact("some-artifact")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(artifact).To(Equal(db.BuildArtifact{
+				Name:            "some-artifact",
+				ContainerHandle: "some-handle",
+				Path:            "/tmp/build/some-output",
+			}))
+
+			artifacts, err := build.GetArtifacts()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(artifacts).To(HaveLen(2))
+			Expect(artifacts[0].Name).To(Equal("another-artifact"))
+			Expect(artifacts[1].Name).To(Equal("some-artifact"))
+		})
+
+		It("replaces an artifact saved under the same name", func() {
+			err := build.SaveArtifact(db.BuildArtifact{Name: "some-artifact", ContainerHandle: "some-handle", Path: "/old"})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = build.SaveArtifact(db.BuildArtifact{Name: "some-artifact", ContainerHandle: "other-handle", Path: "/new"})
+			Expect(err).NotTo(HaveOccurred())
+
+			artifacts, err := build.GetArtifacts()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(artifacts).To(Equal([]db.BuildArtifact{
+				{Name: "some-artifact", ContainerHandle: "other-handle", Path: "/new"},
+			}))
+		})
+
+		It("does not find artifacts that were never saved", func() {
+			_, found, err := build.GetArtifact("bogus")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+	})
+
+	Describe("Comments", func() {
+		var build db.Build
+
+		BeforeEach(func() {
+			var err error
+			build, err = teamDB.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("has none to begin with", func() {
+			comments, err := build.GetComments()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(comments).To(BeEmpty())
+		})
+
+		It("saves comments and returns them in the order they were left", func() {
+			first, err := build.SaveComment("team:main", "known flake")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(first.ID).NotTo(BeZero())
+			Expect(first.CreatedAt).To(BeTemporally("~", time.Now(), time.Minute))
+
+			second, err := build.SaveComment("team:other", "rolled back manually")
+			Expect(err).NotTo(HaveOccurred())
+
+			comments, err := build.GetComments()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(comments).To(HaveLen(2))
+
+			Expect(comments[0].ID).To(Equal(first.ID))
+			Expect(comments[0].Author).To(Equal("team:main"))
+			Expect(comments[0].Text).To(Equal("known flake"))
+			Expect(comments[0].CreatedAt.Unix()).To(Equal(first.CreatedAt.Unix()))
+
+			Expect(comments[1].ID).To(Equal(second.ID))
+			Expect(comments[1].Text).To(Equal("rolled back manually"))
+		})
+	})
+
+	Describe("Approvals", func() {
+		var build db.Build
+
+		BeforeEach(func() {
+			var err error
+			build, err = teamDB.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("has none to begin with", func() {
+			approvals, err := build.GetApprovals()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(approvals).To(BeEmpty())
+
+			_, found, err := build.GetApproval("ship-it")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+
+		It("cannot decide an approval that wasn't requested", func() {
+			decided, err := build.DecideApproval("ship-it", true, "team:main")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(decided).To(BeFalse())
+		})
+
+		Context("when an approval is requested", func() {
+			BeforeEach(func() {
+				err := build.RequestApproval("some-plan-id", "ship-it")
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("is pending", func() {
+				approval, found, err := build.GetApproval("ship-it")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(approval.Name).To(Equal("ship-it"))
+				Expect(approval.PlanID).To(Equal(atc.PlanID("some-plan-id")))
+				Expect(approval.Status).To(Equal(db.ApprovalPending))
+				Expect(approval.RequestedAt).To(BeTemporally("~", time.Now(), time.Minute))
+				Expect(approval.DecidedAt).To(BeZero())
+
+				approvals, err := build.GetApprovals()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(approvals).To(Equal([]db.BuildApproval{approval}))
+			})
+
+			It("saves an event", func() {
+				events, err := build.Events(0)
+				Expect(err).NotTo(HaveOccurred())
+
+				defer events.Close()
+
+				approval, _, err := build.GetApproval("ship-it")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(untimed(events.Next())).To(Equal(envelope(event.ApprovalRequested{
+					Origin: event.Origin{ID: "some-plan-id"},
+					Name:   "ship-it",
+					Time:   approval.RequestedAt.Unix(),
+				})))
+			})
+
+			It("leaves it alone when it is requested again", func() {
+				decided, err := build.DecideApproval("ship-it", true, "team:main")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(decided).To(BeTrue())
+
+				err = build.RequestApproval("some-plan-id", "ship-it")
+				Expect(err).NotTo(HaveOccurred())
+
+				approval, _, err := build.GetApproval("ship-it")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(approval.Status).To(Equal(db.ApprovalApproved))
+			})
+
+			Context("when it is approved", func() {
+				var notifier db.Notifier
+
+				BeforeEach(func() {
+					var err error
+					notifier, err = build.ApprovalNotifier("ship-it")
+					Expect(err).NotTo(HaveOccurred())
+
+					Consistently(notifier.Notify()).ShouldNot(Receive())
+
+					decided, err := build.DecideApproval("ship-it", true, "team:main")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(decided).To(BeTrue())
+				})
+
+				AfterEach(func() {
+					err := notifier.Close()
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("records who approved it", func() {
+					approval, _, err := build.GetApproval("ship-it")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(approval.Status).To(Equal(db.ApprovalApproved))
+					Expect(approval.Approver).To(Equal("team:main"))
+					Expect(approval.DecidedAt).To(BeTemporally("~", time.Now(), time.Minute))
+				})
+
+				It("saves an event", func() {
+					events, err := build.Events(1)
+					Expect(err).NotTo(HaveOccurred())
+
+					defer events.Close()
+
+					approval, _, err := build.GetApproval("ship-it")
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(untimed(events.Next())).To(Equal(envelope(event.ApprovalDecided{
+						Origin:   event.Origin{ID: "some-plan-id"},
+						Name:     "ship-it",
+						Approved: true,
+						Approver: "team:main",
+						Time:     approval.DecidedAt.Unix(),
+					})))
+				})
+
+				It("notifies", func() {
+					Eventually(notifier.Notify(), 5*time.Second).Should(Receive())
+				})
+
+				It("cannot be decided again", func() {
+					decided, err := build.DecideApproval("ship-it", false, "team:other")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(decided).To(BeFalse())
+
+					approval, _, err := build.GetApproval("ship-it")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(approval.Status).To(Equal(db.ApprovalApproved))
+					Expect(approval.Approver).To(Equal("team:main"))
+				})
+			})
+
+			Context("when it is rejected", func() {
+				BeforeEach(func() {
+					decided, err := build.DecideApproval("ship-it", false, "team:main")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(decided).To(BeTrue())
+				})
+
+				It("records who rejected it", func() {
+					approval, _, err := build.GetApproval("ship-it")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(approval.Status).To(Equal(db.ApprovalRejected))
+					Expect(approval.Approver).To(Equal("team:main"))
+				})
+			})
+		})
+	})
+
+	Describe("Steps", func() {
+		var build db.Build
+
+		BeforeEach(func() {
+			var err error
+			build, err = teamDB.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		stepsByID := func() map[atc.PlanID]db.BuildStep {
+			steps, err := build.GetSteps()
+			Expect(err).NotTo(HaveOccurred())
+
+			byID := map[atc.PlanID]db.BuildStep{}
+			for _, step := range steps {
+				byID[step.PlanID] = step
+			}
+
+			return byID
+		}
+
+		It("has none to begin with", func() {
+			Expect(build.GetSteps()).To(BeEmpty())
+		})
+
+		It("tracks each step from the events saved by it", func() {
+			Expect(build.SaveEvent(event.InitializeGet{Origin: event.Origin{ID: "1"}})).To(Succeed())
+			Expect(build.SaveEvent(event.InitializeTask{Origin: event.Origin{ID: "2"}})).To(Succeed())
+			Expect(build.SaveEvent(event.InitializeTask{Origin: event.Origin{ID: "3"}})).To(Succeed())
+
+			steps := stepsByID()
+			Expect(steps).To(HaveLen(3))
+			Expect(steps["1"].Type).To(Equal("get"))
+			Expect(steps["1"].Status).To(Equal(db.BuildStepStarted))
+			Expect(steps["1"].StartedAt).To(BeTemporally("~", time.Now(), time.Minute))
+			Expect(steps["1"].FinishedAt).To(BeZero())
+
+			Expect(build.SaveEvent(event.FinishGet{Origin: event.Origin{ID: "1"}, ExitStatus: 0})).To(Succeed())
+			Expect(build.SaveEvent(event.FinishTask{Origin: event.Origin{ID: "2"}, ExitStatus: 1})).To(Succeed())
+			Expect(build.SaveEvent(event.Error{Origin: event.Origin{ID: "3"}, Message: "nope"})).To(Succeed())
+
+			steps = stepsByID()
+			Expect(steps["1"].Status).To(Equal(db.BuildStepSucceeded))
+			Expect(steps["1"].FinishedAt).To(BeTemporally(">=", steps["1"].StartedAt))
+			Expect(steps["2"].Type).To(Equal("task"))
+			Expect(steps["2"].Status).To(Equal(db.BuildStepFailed))
+			Expect(steps["3"].Type).To(Equal("task"))
+			Expect(steps["3"].Status).To(Equal(db.BuildStepErrored))
+		})
+
+		It("leaves a step as it was when it's started again", func() {
+			Expect(build.SaveEvent(event.InitializePut{Origin: event.Origin{ID: "1"}})).To(Succeed())
+			Expect(build.SaveEvent(event.FinishPut{Origin: event.Origin{ID: "1"}})).To(Succeed())
+			Expect(build.SaveEvent(event.InitializePut{Origin: event.Origin{ID: "1"}})).To(Succeed())
+
+			Expect(stepsByID()["1"].Status).To(Equal(db.BuildStepSucceeded))
+		})
+
+		It("tracks steps that error before starting", func() {
+			Expect(build.SaveEvent(event.Error{Origin: event.Origin{ID: "1"}, Message: "nope"})).To(Succeed())
+
+			step := stepsByID()["1"]
+			Expect(step.Type).To(BeEmpty())
+			Expect(step.Status).To(Equal(db.BuildStepErrored))
+			Expect(step.FinishedAt).To(Equal(step.StartedAt))
+		})
+
+		It("ignores errors that aren't from a step", func() {
+			Expect(build.SaveEvent(event.Error{Message: "nope"})).To(Succeed())
+			Expect(build.GetSteps()).To(BeEmpty())
+		})
+
+		It("tracks approvals as they're requested and decided", func() {
+			Expect(build.RequestApproval("1", "ship-it")).To(Succeed())
+			Expect(stepsByID()["1"].Type).To(Equal("approval"))
+
+			_, err := build.DecideApproval("ship-it", false, "team:main")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stepsByID()["1"].Status).To(Equal(db.BuildStepFailed))
+		})
+
+		Context("when the build is aborted with steps still running", func() {
+			BeforeEach(func() {
+				Expect(build.SaveEvent(event.InitializeTask{Origin: event.Origin{ID: "1"}})).To(Succeed())
+				Expect(build.SaveEvent(event.InitializeTask{Origin: event.Origin{ID: "2"}})).To(Succeed())
+				Expect(build.SaveEvent(event.FinishTask{Origin: event.Origin{ID: "2"}})).To(Succeed())
+
+				Expect(build.Finish(db.StatusAborted)).To(Succeed())
+			})
+
+			It("aborts them too", func() {
+				steps := stepsByID()
+				Expect(steps["1"].Status).To(Equal(db.BuildStepAborted))
+				Expect(steps["1"].FinishedAt).NotTo(BeZero())
+				Expect(steps["2"].Status).To(Equal(db.BuildStepSucceeded))
+			})
+		})
+	})
+
+	Describe("Dependencies", func() {
+		var build db.Build
+		var dependency1 db.Build
+		var dependency2 db.Build
+
+		plan := atc.Plan{
+			ID: "some-plan",
+			Task: &atc.TaskPlan{
+				Name: "some-task",
+			},
+		}
+
+		BeforeEach(func() {
+			var err error
+			dependency1, err = teamDB.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+
+			dependency2, err = teamDB.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+
+			build, err = teamDB.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+
+			err = build.SaveDependencies([]int{dependency1.ID(), dependency2.ID()}, plan)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("reports the status of each dependency", func() {
+			err := dependency1.Finish(db.StatusSucceeded)
+			Expect(err).NotTo(HaveOccurred())
+
+			statuses, err := build.GetDependencyStatuses()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statuses).To(Equal(map[int]db.Status{
+				dependency1.ID(): db.StatusSucceeded,
+				dependency2.ID(): db.StatusPending,
+			}))
+		})
+
+		It("hands out the pending plan exactly once", func() {
+			claimedPlan, claimed, err := build.ClaimPendingPlan()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(claimed).To(BeTrue())
+			Expect(claimedPlan).To(Equal(plan))
+
+			_, claimed, err = build.ClaimPendingPlan()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(claimed).To(BeFalse())
+		})
+
+		It("has no pending plan for builds without dependencies", func() {
+			_, claimed, err := dependency1.ClaimPendingPlan()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(claimed).To(BeFalse())
+		})
+	})
+
 	Describe("SaveEvent", func() {
 		It("saves and propagates events correctly", func() {
 			build, err := teamDB.CreateOneOffBuild()
@@ -134,7 +980,11 @@ var _ = Describe("Build", func() {
 			})
 			Expect(err).NotTo(HaveOccurred())
 
-			Expect(events.Next()).To(Equal(envelope(event.Log{
+			someEvent, err := events.Next()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(someEvent.Time).To(BeTemporally("~", time.Now(), time.Minute))
+
+			Expect(untimed(someEvent, err)).To(Equal(envelope(event.Log{
 				Payload: "some ",
 			})))
 
@@ -143,7 +993,7 @@ var _ = Describe("Build", func() {
 			})
 			Expect(err).NotTo(HaveOccurred())
 
-			Expect(events.Next()).To(Equal(envelope(event.Log{
+			Expect(untimed(events.Next())).To(Equal(envelope(event.Log{
 				Payload: "log",
 			})))
 
@@ -153,7 +1003,7 @@ var _ = Describe("Build", func() {
 
 			defer eventsFrom1.Close()
 
-			Expect(eventsFrom1.Next()).To(Equal(envelope(event.Log{
+			Expect(untimed(eventsFrom1.Next())).To(Equal(envelope(event.Log{
 				Payload: "log",
 			})))
 
@@ -162,7 +1012,7 @@ var _ = Describe("Build", func() {
 			nextErr := make(chan error)
 
 			go func() {
-				event, err := events.Next()
+				event, err := untimed(events.Next())
 				if err != nil {
 					nextErr <- err
 				} else {
@@ -194,6 +1044,202 @@ var _ = Describe("Build", func() {
 				return err
 			}).Should(Equal(db.ErrBuildEventStreamClosed))
 		})
+
+		It("propagates events too large to be sent along with their notification", func() {
+			build, err := teamDB.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+
+			events, err := build.Events(0)
+			Expect(err).NotTo(HaveOccurred())
+
+			defer events.Close()
+
+			err = build.SaveEvent(event.Log{
+				Payload: "small",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			largePayload := strings.Repeat("x", 10000)
+
+			err = build.SaveEvent(event.Log{
+				Payload: largePayload,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = build.SaveEvent(event.Log{
+				Payload: "small again",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(untimed(events.Next())).To(Equal(envelope(event.Log{
+				Payload: "small",
+			})))
+
+			Expect(untimed(events.Next())).To(Equal(envelope(event.Log{
+				Payload: largePayload,
+			})))
+
+			Expect(untimed(events.Next())).To(Equal(envelope(event.Log{
+				Payload: "small again",
+			})))
+		})
+
+		It("propagates events of pipeline builds", func() {
+			build, err := pipelineDB.CreateJobBuild("some-job")
+			Expect(err).NotTo(HaveOccurred())
+
+			events, err := build.Events(0)
+			Expect(err).NotTo(HaveOccurred())
+
+			defer events.Close()
+
+			err = build.SaveEvent(event.Log{
+				Payload: "some log",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			ev, err := events.Next()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ev.Time).To(BeTemporally("~", time.Now(), time.Minute))
+
+			Expect(untimed(ev, err)).To(Equal(envelope(event.Log{
+				Payload: "some log",
+			})))
+		})
+	})
+
+	Describe("VerifyEvents", func() {
+		var signingConn db.Conn
+		var signedTeamDB db.TeamDB
+
+		BeforeEach(func() {
+			signingConn = db.WithEventSigning(dbConn, []byte("some-key"))
+			signedTeamDB = db.NewTeamDBFactory(signingConn, bus, lockFactory).GetTeamDB(atc.DefaultTeamName)
+		})
+
+		saveLogs := func(build db.Build, count int) {
+			for i := 0; i < count; i++ {
+				Expect(build.SaveEvent(event.Log{Payload: fmt.Sprintf("line %d\n", i)})).To(Succeed())
+			}
+		}
+
+		It("fails when events aren't being signed", func() {
+			build, err := teamDB.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = build.VerifyEvents()
+			Expect(err).To(Equal(db.ErrEventSigningNotConfigured))
+		})
+
+		It("finds the events of a build intact, anchored as the build finishes", func() {
+			build, err := signedTeamDB.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+
+			saveLogs(build, 3)
+			Expect(build.Finish(db.StatusSucceeded)).To(Succeed())
+
+			verification, err := build.VerifyEvents()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(verification).To(Equal(db.EventChainVerification{
+				SignedEvents: 4,
+				// the first event, and the status event
+				Anchors: 2,
+				Valid:   true,
+			}))
+		})
+
+		It("allows for events saved before signing was turned on", func() {
+			build, err := teamDB.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+
+			saveLogs(build, 2)
+
+			signedBuild, found, err := db.NewSQL(signingConn, bus, lockFactory).GetBuildByID(build.ID())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+
+			saveLogs(signedBuild, 2)
+
+			verification, err := signedBuild.VerifyEvents()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(verification.Valid).To(BeTrue())
+			Expect(verification.UnsignedEvents).To(Equal(2))
+			Expect(verification.SignedEvents).To(Equal(2))
+
+			saveLogs(build, 1)
+
+			verification, err = signedBuild.VerifyEvents()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(verification.Valid).To(BeFalse())
+			Expect(verification.BrokenAt).To(Equal(int64(4)))
+			Expect(verification.Reason).To(Equal("unsigned event after signed events"))
+		})
+
+		It("notices an event being altered", func() {
+			build, err := signedTeamDB.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+
+			saveLogs(build, 3)
+
+			_, err = dbConn.Exec(`
+				UPDATE build_events
+				SET payload = '{"payload":"something else"}', compressed_payload = NULL
+				WHERE build_id = $1 AND event_id = 1
+			`, build.ID())
+			Expect(err).NotTo(HaveOccurred())
+
+			verification, err := build.VerifyEvents()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(verification.Valid).To(BeFalse())
+			Expect(verification.BrokenAt).To(Equal(int64(1)))
+			Expect(verification.Reason).To(Equal("signature does not match"))
+		})
+
+		It("notices an event being removed from the middle", func() {
+			build, err := signedTeamDB.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+
+			saveLogs(build, 3)
+
+			_, err = dbConn.Exec(`DELETE FROM build_events WHERE build_id = $1 AND event_id = 1`, build.ID())
+			Expect(err).NotTo(HaveOccurred())
+
+			verification, err := build.VerifyEvents()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(verification.Valid).To(BeFalse())
+			Expect(verification.BrokenAt).To(Equal(int64(2)))
+		})
+
+		It("notices anchored events being removed from the end", func() {
+			build, err := signedTeamDB.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+
+			saveLogs(build, 3)
+			Expect(build.Finish(db.StatusSucceeded)).To(Succeed())
+
+			_, err = dbConn.Exec(`DELETE FROM build_events WHERE build_id = $1 AND event_id = 3`, build.ID())
+			Expect(err).NotTo(HaveOccurred())
+
+			verification, err := build.VerifyEvents()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(verification.Valid).To(BeFalse())
+			Expect(verification.BrokenAt).To(Equal(int64(3)))
+			Expect(verification.Reason).To(Equal("anchored event is missing"))
+		})
+
+		It("chains the events of pipeline builds too", func() {
+			signedPipelineDB := db.NewPipelineDBFactory(signingConn, bus, lockFactory).Build(pipeline)
+
+			build, err := signedPipelineDB.CreateJobBuild("some-job")
+			Expect(err).NotTo(HaveOccurred())
+
+			saveLogs(build, 2)
+
+			verification, err := build.VerifyEvents()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(verification.Valid).To(BeTrue())
+			Expect(verification.SignedEvents).To(Equal(2))
+		})
 	})
 
 	Describe("Events", func() {
@@ -216,7 +1262,7 @@ var _ = Describe("Build", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(found).To(BeTrue())
 
-			Expect(events.Next()).To(Equal(envelope(event.Status{
+			Expect(untimed(events.Next())).To(Equal(envelope(event.Status{
 				Status: atc.StatusStarted,
 				Time:   build.StartTime().Unix(),
 			})))
@@ -229,7 +1275,7 @@ var _ = Describe("Build", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(found).To(BeTrue())
 
-			Expect(events.Next()).To(Equal(envelope(event.Status{
+			Expect(untimed(events.Next())).To(Equal(envelope(event.Status{
 				Status: atc.StatusSucceeded,
 				Time:   build.EndTime().Unix(),
 			})))
@@ -369,7 +1415,7 @@ var _ = Describe("Build", func() {
 
 				defer events.Close()
 
-				Expect(events.Next()).To(Equal(envelope(event.Status{
+				Expect(untimed(events.Next())).To(Equal(envelope(event.Status{
 					Status: atc.StatusStarted,
 					Time:   build.StartTime().Unix(),
 				})))
@@ -414,7 +1460,7 @@ var _ = Describe("Build", func() {
 
 				defer events.Close()
 
-				Expect(events.Next()).To(Equal(envelope(event.Status{
+				Expect(untimed(events.Next())).To(Equal(envelope(event.Status{
 					Status: atc.StatusSucceeded,
 					Time:   build.EndTime().Unix(),
 				})))
@@ -428,6 +1474,117 @@ var _ = Describe("Build", func() {
 			})
 		})
 
+		Describe("HandOff", func() {
+			BeforeEach(func() {
+				lock, acquired, err := build.AcquireTrackingLock(lagertest.NewTestLogger("test"), time.Minute)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(acquired).To(BeTrue())
+
+				err = lock.Release()
+				Expect(err).NotTo(HaveOccurred())
+
+				err = listener.Listen(db.BuildHandoffChannel)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			AfterEach(func() {
+				err := listener.Unlisten(db.BuildHandoffChannel)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			JustBeforeEach(func() {
+				err := build.HandOff()
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("lets the build be tracked again before the lease is up", func() {
+				lock, acquired, err := build.AcquireTrackingLock(lagertest.NewTestLogger("test"), time.Minute)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(acquired).To(BeTrue())
+
+				err = lock.Release()
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("notifies with the build's ID", func() {
+				var notification *pq.Notification
+				Eventually(listener.Notify, 5*time.Second).Should(Receive(&notification))
+				Expect(notification.Channel).To(Equal(db.BuildHandoffChannel))
+				Expect(notification.Extra).To(Equal(fmt.Sprintf("%d", build.ID())))
+			})
+		})
+
+		Describe("MarkAsTimedOut", func() {
+			Context("when the build has not started", func() {
+				It("does not mark it", func() {
+					timedOut, err := build.MarkAsTimedOut()
+					Expect(err).NotTo(HaveOccurred())
+					Expect(timedOut).To(BeFalse())
+
+					found, err := build.Reload()
+					Expect(err).NotTo(HaveOccurred())
+					Expect(found).To(BeTrue())
+					Expect(build.Status()).To(Equal(db.StatusPending))
+				})
+			})
+
+			Context("when the build has started", func() {
+				BeforeEach(func() {
+					started, err := build.Start("engine", "metadata")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(started).To(BeTrue())
+
+					timedOut, err := build.MarkAsTimedOut()
+					Expect(err).NotTo(HaveOccurred())
+					Expect(timedOut).To(BeTrue())
+				})
+
+				It("updates build status", func() {
+					found, err := build.Reload()
+					Expect(err).NotTo(HaveOccurred())
+					Expect(found).To(BeTrue())
+					Expect(build.Status()).To(Equal(db.StatusTimedOut))
+				})
+
+				It("only marks it once", func() {
+					timedOut, err := build.MarkAsTimedOut()
+					Expect(err).NotTo(HaveOccurred())
+					Expect(timedOut).To(BeFalse())
+				})
+
+				It("is not overwritten by aborting the build", func() {
+					err := build.Abort()
+					Expect(err).NotTo(HaveOccurred())
+
+					found, err := build.Reload()
+					Expect(err).NotTo(HaveOccurred())
+					Expect(found).To(BeTrue())
+					Expect(build.Status()).To(Equal(db.StatusTimedOut))
+				})
+
+				It("stays timed out when the aborted build finishes", func() {
+					err := build.Finish(db.StatusAborted)
+					Expect(err).NotTo(HaveOccurred())
+
+					found, err := build.Reload()
+					Expect(err).NotTo(HaveOccurred())
+					Expect(found).To(BeTrue())
+					Expect(build.Status()).To(Equal(db.StatusTimedOut))
+					Expect(build.IsRunning()).To(BeFalse())
+
+					events, err := build.Events(1)
+					Expect(err).NotTo(HaveOccurred())
+
+					defer events.Close()
+
+					Expect(untimed(events.Next())).To(Equal(envelope(event.Status{
+						Status: atc.StatusTimedOut,
+						Time:   build.EndTime().Unix(),
+					})))
+				})
+			})
+		})
+
 		Describe("MarkAsFailed", func() {
 			var cause error
 
@@ -448,7 +1605,7 @@ var _ = Describe("Build", func() {
 
 				defer events.Close()
 
-				Expect(events.Next()).To(Equal(envelope(event.Error{
+				Expect(untimed(events.Next())).To(Equal(envelope(event.Error{
 					Message: "disaster",
 				})))
 			})
@@ -687,7 +1844,7 @@ var _ = Describe("Build", func() {
 						},
 					}
 
-					pipeline, _, err = teamDB.SaveConfig("some-pipeline", pipelineConfig, db.ConfigVersion(1), db.PipelineUnpaused)
+					pipeline, _, err = teamDB.SaveConfig("some-pipeline", pipelineConfig, db.ConfigVersion(1), db.PipelineUnpaused, "some-author")
 					Expect(err).NotTo(HaveOccurred())
 
 					err = pipelineDB.SaveResourceVersions(
@@ -765,7 +1922,7 @@ var _ = Describe("Build", func() {
 					},
 				}
 
-				pipeline, _, err = teamDB.SaveConfig("some-pipeline", pipelineConfig, db.ConfigVersion(1), db.PipelineUnpaused)
+				pipeline, _, err = teamDB.SaveConfig("some-pipeline", pipelineConfig, db.ConfigVersion(1), db.PipelineUnpaused, "some-author")
 				Expect(err).NotTo(HaveOccurred())
 
 				build1, err = pipelineDB.CreateJobBuild("some-job")
@@ -852,8 +2009,56 @@ var _ = Describe("Build", func() {
 			})
 		})
 	})
+
+	Describe("GetPreviousSuccessfulBuild", func() {
+		It("returns the job's last build before it to have succeeded", func() {
+			firstBuild, err := pipelineDB.CreateJobBuild("some-job")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(firstBuild.Finish(db.StatusSucceeded)).To(Succeed())
+
+			secondBuild, err := pipelineDB.CreateJobBuild("some-job")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(secondBuild.Finish(db.StatusSucceeded)).To(Succeed())
+
+			failedBuild, err := pipelineDB.CreateJobBuild("some-job")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(failedBuild.Finish(db.StatusFailed)).To(Succeed())
+
+			otherJobBuild, err := pipelineDB.CreateJobBuild("some-other-job")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(otherJobBuild.Finish(db.StatusSucceeded)).To(Succeed())
+
+			build, err := pipelineDB.CreateJobBuild("some-job")
+			Expect(err).NotTo(HaveOccurred())
+
+			previous, found, err := build.GetPreviousSuccessfulBuild()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(previous.ID()).To(Equal(secondBuild.ID()))
+			Expect(previous.JobName()).To(Equal("some-job"))
+
+			_, found, err = firstBuild.GetPreviousSuccessfulBuild()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+
+		It("returns nothing for one-off builds", func() {
+			build, err := teamDB.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+
+			_, found, err := build.GetPreviousSuccessfulBuild()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+	})
 })
 
+// untimed clears the time an event was saved at, which can't be predicted
+func untimed(ev event.Envelope, err error) (event.Envelope, error) {
+	ev.Time = time.Time{}
+	return ev, err
+}
+
 func envelope(ev atc.Event) event.Envelope {
 	payload, err := json.Marshal(ev)
 	Expect(err).ToNot(HaveOccurred())
@@ -0,0 +1,216 @@
+package cache
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/metric"
+)
+
+const (
+	buildsCacheName  = "builds"
+	configsCacheName = "pipeline_configs"
+)
+
+//go:generate counterfeiter . BuildsDB
+
+type BuildsDB interface {
+	GetBuildByID(buildID int) (db.Build, bool, error)
+}
+
+// BuildCache is a read-through cache in front of the builds DB, for requests
+// that look up the same few builds over and over, e.g. everyone polling a
+// build that's running. It caches builds by ID along with the configs of
+// their pipelines.
+//
+// Builds are dropped when their status changes and configs when their
+// pipeline changes, on whichever ATC that happens, as long as the cache is
+// being told about it by Watch. Anything else about a build, such as its
+// labels, may be stale for up to the TTL.
+type BuildCache struct {
+	buildsDB BuildsDB
+
+	lock    sync.Mutex
+	builds  *lru
+	configs *lru
+
+	// generation is bumped by every invalidation, so that a lookup that
+	// raced with one doesn't cache what it found
+	generation int
+}
+
+type cachedConfig struct {
+	config  atc.Config
+	version db.ConfigVersion
+}
+
+func NewBuildCache(buildsDB BuildsDB, size int, ttl time.Duration, clock clock.Clock) *BuildCache {
+	return &BuildCache{
+		buildsDB: buildsDB,
+
+		builds:  newLRU(size, ttl, clock),
+		configs: newLRU(size, ttl, clock),
+	}
+}
+
+func (cache *BuildCache) GetBuildByID(buildID int) (db.Build, bool, error) {
+	cache.lock.Lock()
+	cached, found := cache.builds.Get(buildID)
+	generation := cache.generation
+	cache.lock.Unlock()
+
+	if found {
+		metric.CacheLookups.Inc(buildsCacheName, "hit")
+		return cached.(db.Build), true, nil
+	}
+
+	metric.CacheLookups.Inc(buildsCacheName, "miss")
+
+	build, found, err := cache.buildsDB.GetBuildByID(buildID)
+	if err != nil || !found {
+		return build, found, err
+	}
+
+	wrapped := cachedBuild{Build: build, cache: cache}
+
+	cache.lock.Lock()
+	if cache.generation == generation {
+		cache.builds.Add(buildID, wrapped)
+	}
+	cache.lock.Unlock()
+
+	return wrapped, true, nil
+}
+
+// InvalidateBuild drops the build, so that it's looked up again next time.
+func (cache *BuildCache) InvalidateBuild(buildID int) {
+	cache.lock.Lock()
+	cache.builds.Remove(buildID)
+	cache.generation++
+	cache.lock.Unlock()
+}
+
+// InvalidatePipeline drops the pipeline's config along with its builds, as
+// they carry its name.
+func (cache *BuildCache) InvalidatePipeline(pipelineID int) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	cache.generation++
+
+	cache.configs.Remove(pipelineID)
+	cache.builds.RemoveIf(func(value interface{}) bool {
+		return value.(db.Build).PipelineID() == pipelineID
+	})
+}
+
+// Purge drops everything.
+func (cache *BuildCache) Purge() {
+	cache.lock.Lock()
+	cache.builds.Purge()
+	cache.configs.Purge()
+	cache.generation++
+	cache.lock.Unlock()
+}
+
+func (cache *BuildCache) getConfig(build db.Build) (atc.Config, db.ConfigVersion, error) {
+	pipelineID := build.PipelineID()
+
+	cache.lock.Lock()
+	cached, found := cache.configs.Get(pipelineID)
+	generation := cache.generation
+	cache.lock.Unlock()
+
+	if found {
+		metric.CacheLookups.Inc(configsCacheName, "hit")
+
+		config := cached.(cachedConfig)
+		return config.config, config.version, nil
+	}
+
+	metric.CacheLookups.Inc(configsCacheName, "miss")
+
+	config, version, err := build.GetConfig()
+	if err != nil {
+		return atc.Config{}, 0, err
+	}
+
+	cache.lock.Lock()
+	if cache.generation == generation {
+		cache.configs.Add(pipelineID, cachedConfig{
+			config:  config,
+			version: version,
+		})
+	}
+	cache.lock.Unlock()
+
+	return config, version, nil
+}
+
+// Watch invalidates the cache as builds' statuses and pipelines change, for
+// as long as the process runs. If it misses any notifications, e.g. because
+// the connection dropped, it purges the whole cache.
+func Watch(logger lager.Logger, bus db.NotificationsBus, cache *BuildCache) error {
+	builds, err := bus.ListenForPayloads(db.BuildStatusChannel)
+	if err != nil {
+		return err
+	}
+
+	pipelines, err := bus.ListenForPayloads(db.PipelineChannel)
+	if err != nil {
+		bus.UnlistenForPayloads(db.BuildStatusChannel, builds)
+		return err
+	}
+
+	go func() {
+		for {
+			select {
+			case <-builds.Ready():
+				invalidate(logger, builds, cache, cache.InvalidateBuild)
+			case <-pipelines.Ready():
+				invalidate(logger, pipelines, cache, cache.InvalidatePipeline)
+			}
+		}
+	}()
+
+	return nil
+}
+
+func invalidate(logger lager.Logger, sink *db.PayloadSink, cache *BuildCache, remove func(int)) {
+	payloads, missed := sink.Take()
+	if missed {
+		logger.Info("missed-notifications-purging-cache")
+		cache.Purge()
+		return
+	}
+
+	for _, payload := range payloads {
+		id, err := strconv.Atoi(payload)
+		if err != nil {
+			logger.Error("failed-to-parse-notification", err, lager.Data{"payload": payload})
+			continue
+		}
+
+		remove(id)
+	}
+}
+
+// cachedBuild looks up its pipeline's config through the cache.
+type cachedBuild struct {
+	db.Build
+
+	cache *BuildCache
+}
+
+func (build cachedBuild) GetConfig() (atc.Config, db.ConfigVersion, error) {
+	if build.IsOneOff() {
+		return build.Build.GetConfig()
+	}
+
+	return build.cache.getConfig(build.Build)
+}
@@ -0,0 +1,211 @@
+package cache_test
+
+import (
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/db/cache"
+	"github.com/concourse/atc/db/cache/cachefakes"
+	"github.com/concourse/atc/db/dbfakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BuildCache", func() {
+	var (
+		fakeBuildsDB *cachefakes.FakeBuildsDB
+		fakeClock    *fakeclock.FakeClock
+
+		builds map[int]*dbfakes.FakeBuild
+
+		buildCache *cache.BuildCache
+	)
+
+	BeforeEach(func() {
+		fakeBuildsDB = new(cachefakes.FakeBuildsDB)
+		fakeClock = fakeclock.NewFakeClock(time.Unix(123, 0))
+
+		builds = map[int]*dbfakes.FakeBuild{}
+		for id := 1; id <= 3; id++ {
+			build := new(dbfakes.FakeBuild)
+			build.IDReturns(id)
+			build.PipelineIDReturns(42)
+			build.GetConfigReturns(atc.Config{
+				Jobs: atc.JobConfigs{{Name: "some-job"}},
+			}, db.ConfigVersion(7), nil)
+
+			builds[id] = build
+		}
+
+		fakeBuildsDB.GetBuildByIDStub = func(id int) (db.Build, bool, error) {
+			build, found := builds[id]
+			if !found {
+				return nil, false, nil
+			}
+
+			return build, true, nil
+		}
+
+		buildCache = cache.NewBuildCache(fakeBuildsDB, 2, time.Minute, fakeClock)
+	})
+
+	lookUp := func(id int) db.Build {
+		build, found, err := buildCache.GetBuildByID(id)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeTrue())
+		Expect(build.ID()).To(Equal(id))
+		return build
+	}
+
+	Describe("GetBuildByID", func() {
+		It("looks the build up once, and then returns it from the cache", func() {
+			lookUp(1)
+			lookUp(1)
+
+			Expect(fakeBuildsDB.GetBuildByIDCallCount()).To(Equal(1))
+			Expect(fakeBuildsDB.GetBuildByIDArgsForCall(0)).To(Equal(1))
+		})
+
+		It("looks the build up again once it has expired", func() {
+			lookUp(1)
+
+			fakeClock.Increment(time.Minute)
+
+			lookUp(1)
+
+			Expect(fakeBuildsDB.GetBuildByIDCallCount()).To(Equal(2))
+		})
+
+		It("evicts the least recently used build when full", func() {
+			lookUp(1)
+			lookUp(2)
+			lookUp(1)
+			lookUp(3)
+
+			Expect(fakeBuildsDB.GetBuildByIDCallCount()).To(Equal(3))
+
+			lookUp(1)
+			Expect(fakeBuildsDB.GetBuildByIDCallCount()).To(Equal(3))
+
+			lookUp(2)
+			Expect(fakeBuildsDB.GetBuildByIDCallCount()).To(Equal(4))
+		})
+
+		It("does not cache builds that are not found", func() {
+			_, found, err := buildCache.GetBuildByID(4)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+
+			_, found, err = buildCache.GetBuildByID(4)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+
+			Expect(fakeBuildsDB.GetBuildByIDCallCount()).To(Equal(2))
+		})
+
+		Context("when looking the build up fails", func() {
+			BeforeEach(func() {
+				fakeBuildsDB.GetBuildByIDStub = nil
+				fakeBuildsDB.GetBuildByIDReturns(nil, false, errors.New("nope"))
+			})
+
+			It("returns the error", func() {
+				_, _, err := buildCache.GetBuildByID(1)
+				Expect(err).To(MatchError("nope"))
+			})
+		})
+	})
+
+	Describe("the config of a cached build", func() {
+		It("is looked up once for every build in the pipeline", func() {
+			config, version, err := lookUp(1).GetConfig()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.Jobs[0].Name).To(Equal("some-job"))
+			Expect(version).To(Equal(db.ConfigVersion(7)))
+
+			_, _, err = lookUp(2).GetConfig()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(builds[1].GetConfigCallCount()).To(Equal(1))
+			Expect(builds[2].GetConfigCallCount()).To(BeZero())
+		})
+
+		Context("when the build is a one-off", func() {
+			BeforeEach(func() {
+				builds[1].IsOneOffReturns(true)
+			})
+
+			It("is not cached", func() {
+				lookUp(1).GetConfig()
+				lookUp(1).GetConfig()
+
+				Expect(builds[1].GetConfigCallCount()).To(Equal(2))
+			})
+		})
+
+		Context("when looking it up fails", func() {
+			BeforeEach(func() {
+				builds[1].GetConfigReturns(atc.Config{}, 0, errors.New("nope"))
+			})
+
+			It("returns the error and does not cache it", func() {
+				_, _, err := lookUp(1).GetConfig()
+				Expect(err).To(MatchError("nope"))
+
+				lookUp(1).GetConfig()
+
+				Expect(builds[1].GetConfigCallCount()).To(Equal(2))
+			})
+		})
+	})
+
+	Describe("InvalidateBuild", func() {
+		It("drops only that build", func() {
+			lookUp(1)
+			lookUp(2)
+
+			buildCache.InvalidateBuild(1)
+
+			lookUp(1)
+			lookUp(2)
+
+			Expect(fakeBuildsDB.GetBuildByIDCallCount()).To(Equal(3))
+		})
+	})
+
+	Describe("InvalidatePipeline", func() {
+		BeforeEach(func() {
+			builds[2].PipelineIDReturns(43)
+		})
+
+		It("drops the pipeline's config and builds", func() {
+			lookUp(1).GetConfig()
+			lookUp(2)
+
+			buildCache.InvalidatePipeline(42)
+
+			lookUp(1).GetConfig()
+			lookUp(2)
+
+			Expect(fakeBuildsDB.GetBuildByIDCallCount()).To(Equal(3))
+			Expect(builds[1].GetConfigCallCount()).To(Equal(2))
+		})
+	})
+
+	Describe("Purge", func() {
+		It("drops everything", func() {
+			lookUp(1).GetConfig()
+
+			buildCache.Purge()
+
+			lookUp(1).GetConfig()
+
+			Expect(fakeBuildsDB.GetBuildByIDCallCount()).To(Equal(2))
+			Expect(builds[1].GetConfigCallCount()).To(Equal(2))
+		})
+	})
+})
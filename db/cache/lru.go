@@ -0,0 +1,109 @@
+package cache
+
+import (
+	"container/list"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+)
+
+// lru holds up to size values, evicting the least recently used one to make
+// room for more. Values also expire once they're older than the ttl. It is
+// not safe for concurrent use.
+type lru struct {
+	size  int
+	ttl   time.Duration
+	clock clock.Clock
+
+	entries map[int]*list.Element
+	order   *list.List
+}
+
+type lruEntry struct {
+	key     int
+	value   interface{}
+	expires time.Time
+}
+
+func newLRU(size int, ttl time.Duration, clock clock.Clock) *lru {
+	return &lru{
+		size:  size,
+		ttl:   ttl,
+		clock: clock,
+
+		entries: map[int]*list.Element{},
+		order:   list.New(),
+	}
+}
+
+func (cache *lru) Get(key int) (interface{}, bool) {
+	element, found := cache.entries[key]
+	if !found {
+		return nil, false
+	}
+
+	entry := element.Value.(*lruEntry)
+	if !cache.clock.Now().Before(entry.expires) {
+		cache.remove(element)
+		return nil, false
+	}
+
+	cache.order.MoveToFront(element)
+
+	return entry.value, true
+}
+
+func (cache *lru) Add(key int, value interface{}) {
+	expires := cache.clock.Now().Add(cache.ttl)
+
+	element, found := cache.entries[key]
+	if found {
+		entry := element.Value.(*lruEntry)
+		entry.value = value
+		entry.expires = expires
+
+		cache.order.MoveToFront(element)
+
+		return
+	}
+
+	cache.entries[key] = cache.order.PushFront(&lruEntry{
+		key:     key,
+		value:   value,
+		expires: expires,
+	})
+
+	for cache.order.Len() > cache.size {
+		cache.remove(cache.order.Back())
+	}
+}
+
+func (cache *lru) Remove(key int) {
+	element, found := cache.entries[key]
+	if found {
+		cache.remove(element)
+	}
+}
+
+// RemoveIf removes every value the predicate returns true for.
+func (cache *lru) RemoveIf(predicate func(interface{}) bool) {
+	for element := cache.order.Front(); element != nil; {
+		next := element.Next()
+
+		if predicate(element.Value.(*lruEntry).value) {
+			cache.remove(element)
+		}
+
+		element = next
+	}
+}
+
+func (cache *lru) Purge() {
+	cache.entries = map[int]*list.Element{}
+	cache.order.Init()
+}
+
+func (cache *lru) remove(element *list.Element) {
+	cache.order.Remove(element)
+	delete(cache.entries, element.Value.(*lruEntry).key)
+}
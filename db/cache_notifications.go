@@ -0,0 +1,45 @@
+package db
+
+import (
+	"database/sql"
+	"strconv"
+)
+
+// BuildStatusChannel is notified with the ID of a build whenever its status
+// changes, for anything holding on to builds to know to look them up again.
+const BuildStatusChannel = "build_status"
+
+// PipelineChannel is notified with the ID of a pipeline whenever its config
+// or name changes, or it is destroyed.
+const PipelineChannel = "pipeline_changed"
+
+// BuildHandoffChannel is notified with the ID of a build whenever the ATC
+// tracking it hands it off, e.g. because it is shutting down.
+const BuildHandoffChannel = "build_handoff"
+
+// schedulingChannel is notified whenever something happens that could let one
+// of the pipeline's jobs schedule a build: a new version of one of its
+// resources, an output of one of its builds, one of its builds finishing, or
+// it or one of its jobs being configured or unpaused.
+func schedulingChannel(pipelineID int) string {
+	return "scheduling_" + strconv.Itoa(pipelineID)
+}
+
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+func notifyBuildStatus(conn execer, buildID int) error {
+	_, err := conn.Exec(`SELECT pg_notify($1, $2)`, BuildStatusChannel, strconv.Itoa(buildID))
+	return err
+}
+
+func notifyPipelineChanged(conn execer, pipelineID int) error {
+	_, err := conn.Exec(`SELECT pg_notify($1, $2)`, PipelineChannel, strconv.Itoa(pipelineID))
+	return err
+}
+
+func notifyScheduling(conn execer, pipelineID int) error {
+	_, err := conn.Exec(`SELECT pg_notify($1, '')`, schedulingChannel(pipelineID))
+	return err
+}
@@ -0,0 +1,369 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/concourse/atc"
+)
+
+// maxCausalityVersions bounds how much of the graph GetVersionCausality will
+// walk, as a version fed into a busy pipeline can reach a lot of builds.
+const maxCausalityVersions = 500
+
+type CausalityEdgeType string
+
+const (
+	// CausalityInput is a version being used as an input to a build.
+	CausalityInput CausalityEdgeType = "input"
+
+	// CausalityOutput is a build producing a version.
+	CausalityOutput CausalityEdgeType = "output"
+
+	// CausalitySameVersion is a version of a resource in one pipeline being
+	// the same as a version of a resource with the same type and source
+	// in another pipeline.
+	CausalitySameVersion CausalityEdgeType = "same_version"
+)
+
+type CausalityEdge struct {
+	Type CausalityEdgeType
+
+	VersionID     int
+	BuildID       int
+	SameVersionID int
+}
+
+// Causality is everything downstream of a version: the builds that used it,
+// the versions they produced, and so on, across the team's pipelines.
+type Causality struct {
+	Versions []SavedVersionedResource
+	Builds   []Build
+	Edges    []CausalityEdge
+
+	// Truncated is true if the walk stopped before reaching everything.
+	Truncated bool
+}
+
+type causalityVersion struct {
+	SavedVersionedResource
+
+	resourceID int
+	version    string
+}
+
+const causalityVersionColumns = "v.id, v.enabled, v.type, v.version, v.metadata, v.check_order, v.resource_id, r.name, r.pipeline_id"
+
+func (pdb *pipelineDB) GetVersionCausality(versionedResourceID int) (Causality, bool, error) {
+	tx, err := pdb.conn.Begin()
+	if err != nil {
+		return Causality{}, false, err
+	}
+
+	defer tx.Rollback()
+
+	root, err := scanCausalityVersion(tx.QueryRow(`
+		SELECT `+causalityVersionColumns+`
+		FROM versioned_resources v
+		INNER JOIN resources r ON r.id = v.resource_id
+		WHERE v.id = $1
+		AND r.pipeline_id = $2
+	`, versionedResourceID, pdb.ID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return Causality{}, false, nil
+		}
+
+		return Causality{}, false, err
+	}
+
+	resourceHashes, err := pdb.teamResourceHashes(tx)
+	if err != nil {
+		return Causality{}, false, err
+	}
+
+	causality := Causality{}
+
+	seenVersions := map[int]bool{root.ID: true}
+	seenBuilds := map[int]bool{}
+	seenSameVersions := map[[2]int]bool{}
+
+	queue := []causalityVersion{root}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		causality.Versions = append(causality.Versions, current.SavedVersionedResource)
+
+		reached := []causalityVersion{}
+
+		sameVersions, err := pdb.getSameVersions(tx, current, resourceHashes)
+		if err != nil {
+			return Causality{}, false, err
+		}
+
+		for _, sameVersion := range sameVersions {
+			// the relation goes both ways; only report it once
+			pair := [2]int{current.ID, sameVersion.ID}
+			if pair[0] > pair[1] {
+				pair[0], pair[1] = pair[1], pair[0]
+			}
+
+			if seenSameVersions[pair] {
+				continue
+			}
+
+			seenSameVersions[pair] = true
+
+			causality.Edges = append(causality.Edges, CausalityEdge{
+				Type:          CausalitySameVersion,
+				VersionID:     current.ID,
+				SameVersionID: sameVersion.ID,
+			})
+
+			reached = append(reached, sameVersion)
+		}
+
+		builds, err := pdb.getBuildsWithInput(tx, current.ID)
+		if err != nil {
+			return Causality{}, false, err
+		}
+
+		for _, build := range builds {
+			causality.Edges = append(causality.Edges, CausalityEdge{
+				Type:      CausalityInput,
+				VersionID: current.ID,
+				BuildID:   build.ID(),
+			})
+
+			if seenBuilds[build.ID()] {
+				continue
+			}
+
+			seenBuilds[build.ID()] = true
+			causality.Builds = append(causality.Builds, build)
+
+			outputs, err := pdb.getExplicitBuildOutputs(tx, build.ID())
+			if err != nil {
+				return Causality{}, false, err
+			}
+
+			for _, output := range outputs {
+				causality.Edges = append(causality.Edges, CausalityEdge{
+					Type:      CausalityOutput,
+					VersionID: output.ID,
+					BuildID:   build.ID(),
+				})
+
+				reached = append(reached, output)
+			}
+		}
+
+		for _, version := range reached {
+			if seenVersions[version.ID] {
+				continue
+			}
+
+			if len(seenVersions) >= maxCausalityVersions {
+				causality.Truncated = true
+				continue
+			}
+
+			seenVersions[version.ID] = true
+			queue = append(queue, version)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return Causality{}, false, err
+	}
+
+	return causality, true, nil
+}
+
+// teamResourceHashes returns the type and source of every resource in the
+// pipeline's team, keyed by resource ID, so that versions can be matched up
+// with versions of the same resource in other pipelines.
+func (pdb *pipelineDB) teamResourceHashes(tx Tx) (map[int]string, error) {
+	rows, err := tx.Query(`
+		SELECT r.id, r.name, p.config
+		FROM resources r
+		INNER JOIN pipelines p ON p.id = r.pipeline_id
+		WHERE p.team_id = $1
+	`, pdb.TeamID())
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	configs := map[string]atc.Config{}
+	hashes := map[int]string{}
+
+	for rows.Next() {
+		var id int
+		var name, configBlob string
+		err := rows.Scan(&id, &name, &configBlob)
+		if err != nil {
+			return nil, err
+		}
+
+		config, found := configs[configBlob]
+		if !found {
+			decrypted, err := pdb.conn.EncryptionStrategy().Decrypt(configBlob)
+			if err != nil {
+				return nil, err
+			}
+
+			err = json.Unmarshal(decrypted, &config)
+			if err != nil {
+				return nil, err
+			}
+
+			configs[configBlob] = config
+		}
+
+		resourceConfig, found := config.Resources.Lookup(name)
+		if !found {
+			continue
+		}
+
+		hashes[id] = HashResourceConfig(resourceConfig.Type, resourceConfig.Source)
+	}
+
+	return hashes, rows.Err()
+}
+
+func (pdb *pipelineDB) getSameVersions(tx Tx, version causalityVersion, resourceHashes map[int]string) ([]causalityVersion, error) {
+	hash, found := resourceHashes[version.resourceID]
+	if !found {
+		return nil, nil
+	}
+
+	rows, err := tx.Query(`
+		SELECT `+causalityVersionColumns+`
+		FROM versioned_resources v
+		INNER JOIN resources r ON r.id = v.resource_id
+		INNER JOIN pipelines p ON p.id = r.pipeline_id
+		WHERE v.type = $1
+		AND v.version = $2
+		AND v.resource_id != $3
+		AND p.team_id = $4
+	`, version.Type, version.version, version.resourceID, pdb.TeamID())
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := scanCausalityVersions(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	sameVersions := []causalityVersion{}
+	for _, candidate := range candidates {
+		if resourceHashes[candidate.resourceID] == hash {
+			sameVersions = append(sameVersions, candidate)
+		}
+	}
+
+	return sameVersions, nil
+}
+
+func (pdb *pipelineDB) getBuildsWithInput(tx Tx, versionedResourceID int) ([]Build, error) {
+	rows, err := tx.Query(`
+		SELECT `+qualifiedBuildColumns+`
+		FROM builds b
+		INNER JOIN jobs j ON b.job_id = j.id
+		INNER JOIN pipelines p ON j.pipeline_id = p.id
+		INNER JOIN teams t ON b.team_id = t.id
+		WHERE b.id IN (
+			SELECT build_id
+			FROM build_inputs
+			WHERE versioned_resource_id = $1
+		)
+		ORDER BY b.id ASC
+	`, versionedResourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	builds := []Build{}
+	for rows.Next() {
+		build, _, err := pdb.buildFactory.ScanBuild(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		builds = append(builds, build)
+	}
+
+	return builds, rows.Err()
+}
+
+func (pdb *pipelineDB) getExplicitBuildOutputs(tx Tx, buildID int) ([]causalityVersion, error) {
+	rows, err := tx.Query(`
+		SELECT `+causalityVersionColumns+`
+		FROM build_outputs bo
+		INNER JOIN versioned_resources v ON v.id = bo.versioned_resource_id
+		INNER JOIN resources r ON r.id = v.resource_id
+		WHERE bo.build_id = $1
+		AND bo.explicit
+		ORDER BY v.id ASC
+	`, buildID)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanCausalityVersions(rows)
+}
+
+func scanCausalityVersions(rows *sql.Rows) ([]causalityVersion, error) {
+	defer rows.Close()
+
+	versions := []causalityVersion{}
+	for rows.Next() {
+		version, err := scanCausalityVersion(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		versions = append(versions, version)
+	}
+
+	return versions, rows.Err()
+}
+
+func scanCausalityVersion(row scannable) (causalityVersion, error) {
+	var version causalityVersion
+	var metadata string
+
+	err := row.Scan(
+		&version.ID,
+		&version.Enabled,
+		&version.Type,
+		&version.version,
+		&metadata,
+		&version.CheckOrder,
+		&version.resourceID,
+		&version.Resource,
+		&version.PipelineID,
+	)
+	if err != nil {
+		return causalityVersion{}, err
+	}
+
+	err = json.Unmarshal([]byte(version.version), &version.Version)
+	if err != nil {
+		return causalityVersion{}, err
+	}
+
+	err = json.Unmarshal([]byte(metadata), &version.Metadata)
+	if err != nil {
+		return causalityVersion{}, err
+	}
+
+	return version, nil
+}
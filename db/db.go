@@ -1,6 +1,7 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
 	"encoding/json"
@@ -9,6 +10,7 @@ import (
 
 	"code.cloudfoundry.org/lager"
 	"github.com/concourse/atc"
+	"github.com/concourse/atc/db/encryption"
 	"github.com/concourse/atc/event"
 	"github.com/lib/pq"
 )
@@ -17,15 +19,28 @@ import (
 
 type Conn interface {
 	Begin() (Tx, error)
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (Tx, error)
 	Close() error
 	Driver() driver.Driver
 	Exec(query string, args ...interface{}) (sql.Result, error)
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
 	Ping() error
 	Prepare(query string) (*sql.Stmt, error)
 	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
 	QueryRow(query string, args ...interface{}) *sql.Row
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
 	SetMaxIdleConns(n int)
 	SetMaxOpenConns(n int)
+	SetConnMaxLifetime(d time.Duration)
+	Stats() sql.DBStats
+
+	// EncryptionStrategy is used for columns holding anything sensitive,
+	// e.g. pipeline configs and build plans.
+	EncryptionStrategy() encryption.Strategy
+
+	// EventSigningKey chains together the events of each build, if not nil.
+	EventSigningKey() []byte
 }
 
 //go:generate counterfeiter . Tx
@@ -56,6 +71,59 @@ func (wrapped *wrappedDB) Begin() (Tx, error) {
 	return wrapped.DB.Begin()
 }
 
+// BeginTx begins a transaction that's rolled back should the context be
+// done before it's committed.
+func (wrapped *wrappedDB) BeginTx(ctx context.Context, opts *sql.TxOptions) (Tx, error) {
+	return wrapped.DB.BeginTx(ctx, opts)
+}
+
+func (wrapped *wrappedDB) EncryptionStrategy() encryption.Strategy {
+	return encryption.NewNoEncryption()
+}
+
+func (wrapped *wrappedDB) EventSigningKey() []byte {
+	return nil
+}
+
+// WithEncryption has the db encrypt sensitive columns written over the
+// connection with the given strategy, and decrypt them when they're read.
+func WithEncryption(conn Conn, strategy encryption.Strategy) Conn {
+	return &encryptedConn{
+		Conn:     conn,
+		strategy: strategy,
+	}
+}
+
+type encryptedConn struct {
+	Conn
+
+	strategy encryption.Strategy
+}
+
+func (conn *encryptedConn) EncryptionStrategy() encryption.Strategy {
+	return conn.strategy
+}
+
+// WithEventSigning has build events saved over the connection signed with
+// key, each signature covering the event before it too, so that a build's
+// events can later be checked for having been altered.
+func WithEventSigning(conn Conn, key []byte) Conn {
+	return &signingConn{
+		Conn: conn,
+		key:  key,
+	}
+}
+
+type signingConn struct {
+	Conn
+
+	key []byte
+}
+
+func (conn *signingConn) EventSigningKey() []byte {
+	return conn.key
+}
+
 func swallowUniqueViolation(err error) error {
 	if err != nil {
 		if pgErr, ok := err.(*pq.Error); ok {
@@ -81,8 +149,35 @@ type DB interface {
 	CreateDefaultTeamIfNotExists() error
 	DeleteTeamByName(teamName string) error
 
+	CreateOneOffBuild(ctx context.Context, teamName string, spec OneOffBuildSpec) (Build, error)
 	GetAllStartedBuilds() ([]Build, error)
-	GetPublicBuilds(page Page) ([]Build, Pagination, error)
+	GetBuildsAwaitingDependencies() ([]Build, error)
+	GetPublicBuilds(ctx context.Context, page Page, filter BuildFilter) ([]Build, Pagination, error)
+	GetBuilds(ctx context.Context, buildIDs []int) ([]Build, error)
+
+	GetGlobalMaxInFlight(ctx context.Context) (int, error)
+	SetGlobalMaxInFlight(ctx context.Context, maxInFlight int) error
+	GlobalMaxInFlightReached() (bool, error)
+
+	GetMaintenance(ctx context.Context) (Maintenance, error)
+	SetMaintenanceWindows(ctx context.Context, windows []MaintenanceWindow) error
+	SetMaintenanceOverride(ctx context.Context, until time.Time) error
+	MaintenanceHoldsBuilds() (bool, error)
+
+	GetPipelineWeights(ctx context.Context) ([]PipelineWeight, error)
+	SetPipelineWeight(ctx context.Context, pipelineID int, weight int) error
+	FairShareReached(pipelineID int) (bool, error)
+
+	PruneResourceVersions(retain int, limit int) (int, error)
+
+	GetUsage(ctx context.Context, filter UsageFilter) ([]Usage, error)
+
+	GetBuildQueue(ctx context.Context, teamName string) ([]QueuedBuild, error)
+
+	GetJobDurationPercentile(pipelineID int, jobName string, percentile int, window time.Duration) (time.Duration, int, error)
+	GetDurationAlerts(ctx context.Context, teamName string) ([]DurationAlert, error)
+
+	SaveTaskCache(cache TaskCache) error
 
 	FindJobIDForBuild(buildID int) (int, bool, error)
 
@@ -91,7 +186,20 @@ type DB interface {
 
 	GetTaskLock(logger lager.Logger, taskName string) (Lock, bool, error)
 
+	AcquireLeadership(holder string, ttl time.Duration) (bool, error)
+	ReleaseLeadership(holder string) error
+	GetLeader() (string, bool, error)
+
 	DeleteBuildEventsByBuildIDs(buildIDs []int) error
+	DeleteBuildEventsBefore(endedBefore time.Time, limit int) (int, error)
+	GetUnreapedBuildsEndedBefore(endedBefore time.Time, limit int) ([]Build, error)
+	GetLastBuildReapTime(ctx context.Context) (time.Time, bool, error)
+	DeleteBuildsBefore(finishedBefore time.Time, limit int) (int, error)
+	DeleteBuildsBeyond(buildsPerJob int, limit int) (int, error)
+	SaveBuildPrune(prune BuildPrune) error
+	GetLastBuildPrune(ctx context.Context) (BuildPrune, bool, error)
+	GetBuildEventsFrom(ctx context.Context, buildID int, offset uint, limit int) ([]event.Envelope, error)
+	GetResourceVersionsBetween(ctx context.Context, pipelineID int, resourceName string, after Version, upTo Version, limit int) ([]SavedVersionedResource, error)
 
 	Workers() ([]SavedWorker, error) // auto-expires workers based on ttl
 	GetWorker(workerName string) (SavedWorker, bool, error)
@@ -100,10 +208,12 @@ type DB interface {
 	GetContainer(string) (SavedContainer, bool, error)
 	CreateContainer(container Container, ttl time.Duration, maxLifetime time.Duration, volumeHandles []string) (SavedContainer, error)
 	FindContainerByIdentifier(ContainerIdentifier) (SavedContainer, bool, error)
+	GetContainerHandlesForBuild(buildID int) ([]string, error)
 	FindLatestSuccessfulBuildsPerJob() (map[int]int, error)
 	FindJobContainersFromUnsuccessfulBuilds() ([]SavedContainer, error)
 	UpdateExpiresAtOnContainer(handle string, ttl time.Duration) error
 	ReapContainer(handle string) error
+	MarkBuildArtifactsReaped(buildID int) error
 
 	DeleteContainer(string) error
 
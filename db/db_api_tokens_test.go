@@ -0,0 +1,132 @@
+package db_test
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/db/dbfakes"
+)
+
+var _ = Describe("API tokens", func() {
+	var dbConn db.Conn
+	var listener *pq.Listener
+	var database *db.SQLDB
+
+	var team db.SavedTeam
+	var otherTeam db.SavedTeam
+
+	BeforeEach(func() {
+		postgresRunner.Truncate()
+
+		dbConn = db.Wrap(postgresRunner.Open())
+		listener = pq.NewListener(postgresRunner.DataSourceName(), time.Second, time.Minute, nil)
+
+		Eventually(listener.Ping, 5*time.Second).ShouldNot(HaveOccurred())
+		bus := db.NewNotificationsBus(listener, dbConn)
+
+		pgxConn := postgresRunner.OpenPgx()
+		fakeConnector := new(dbfakes.FakeConnector)
+		retryableConn := &db.RetryableConn{Connector: fakeConnector, Conn: pgxConn}
+
+		lockFactory := db.NewLockFactory(retryableConn)
+		database = db.NewSQL(dbConn, bus, lockFactory)
+
+		var err error
+		team, err = database.CreateTeam(db.Team{Name: "some-team"})
+		Expect(err).NotTo(HaveOccurred())
+
+		otherTeam, err = database.CreateTeam(db.Team{Name: "some-other-team"})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		err := dbConn.Close()
+		Expect(err).NotTo(HaveOccurred())
+
+		err = listener.Close()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("creates, lists, and looks up tokens", func() {
+		expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+
+		created, ok, err := database.CreateAPIToken(db.APIToken{
+			TeamID:    team.ID,
+			Name:      "ci-bot",
+			Scopes:    []string{"read", "trigger"},
+			ExpiresAt: expiresAt,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		Expect(created.ID).NotTo(BeZero())
+		Expect(created.CreatedAt).NotTo(BeZero())
+
+		found, ok, err := database.GetAPIToken(created.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		Expect(found.Name).To(Equal("ci-bot"))
+		Expect(found.TeamID).To(Equal(team.ID))
+		Expect(found.Scopes).To(Equal([]string{"read", "trigger"}))
+		Expect(found.ExpiresAt.Unix()).To(Equal(expiresAt.Unix()))
+
+		_, _, err = database.CreateAPIToken(db.APIToken{
+			TeamID: otherTeam.ID,
+			Name:   "dashboard",
+			Scopes: []string{"read"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		tokens, err := database.GetAPITokens(team.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tokens).To(HaveLen(1))
+		Expect(tokens[0].ID).To(Equal(created.ID))
+	})
+
+	It("leaves tokens without an expiry unexpiring", func() {
+		created, _, err := database.CreateAPIToken(db.APIToken{
+			TeamID: team.ID,
+			Name:   "ci-bot",
+			Scopes: []string{"read"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		found, _, err := database.GetAPIToken(created.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found.ExpiresAt).To(BeZero())
+	})
+
+	It("does not create two tokens of the same name for a team", func() {
+		_, ok, err := database.CreateAPIToken(db.APIToken{TeamID: team.ID, Name: "ci-bot", Scopes: []string{"read"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+
+		_, ok, err = database.CreateAPIToken(db.APIToken{TeamID: team.ID, Name: "ci-bot", Scopes: []string{"admin"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeFalse())
+
+		_, ok, err = database.CreateAPIToken(db.APIToken{TeamID: otherTeam.ID, Name: "ci-bot", Scopes: []string{"read"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+	})
+
+	It("only revokes the team's own tokens", func() {
+		created, _, err := database.CreateAPIToken(db.APIToken{TeamID: team.ID, Name: "ci-bot", Scopes: []string{"read"}})
+		Expect(err).NotTo(HaveOccurred())
+
+		revoked, err := database.RevokeAPIToken(otherTeam.ID, created.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(revoked).To(BeFalse())
+
+		revoked, err = database.RevokeAPIToken(team.ID, created.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(revoked).To(BeTrue())
+
+		_, found, err := database.GetAPIToken(created.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeFalse())
+	})
+})
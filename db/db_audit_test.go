@@ -0,0 +1,90 @@
+package db_test
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/db/dbfakes"
+)
+
+var _ = Describe("Audit events", func() {
+	var dbConn db.Conn
+	var listener *pq.Listener
+	var database *db.SQLDB
+
+	BeforeEach(func() {
+		postgresRunner.Truncate()
+
+		dbConn = db.Wrap(postgresRunner.Open())
+		listener = pq.NewListener(postgresRunner.DataSourceName(), time.Second, time.Minute, nil)
+
+		Eventually(listener.Ping, 5*time.Second).ShouldNot(HaveOccurred())
+		bus := db.NewNotificationsBus(listener, dbConn)
+
+		pgxConn := postgresRunner.OpenPgx()
+		fakeConnector := new(dbfakes.FakeConnector)
+		retryableConn := &db.RetryableConn{Connector: fakeConnector, Conn: pgxConn}
+
+		lockFactory := db.NewLockFactory(retryableConn)
+		database = db.NewSQL(dbConn, bus, lockFactory)
+
+		for _, event := range []db.AuditEvent{
+			{Actor: "team:some-team", Action: "SaveConfig", Resource: "/a", BodySHA256: "sha-1", Status: 200},
+			{Actor: "system", Action: "AbortBuild", Resource: "/b", BodySHA256: "sha-2", Status: 204},
+			{Actor: "team:some-team", Action: "AbortBuild", Resource: "/c", BodySHA256: "sha-3", Status: 404},
+		} {
+			err := database.SaveAuditEvent(event)
+			Expect(err).NotTo(HaveOccurred())
+		}
+	})
+
+	AfterEach(func() {
+		err := dbConn.Close()
+		Expect(err).NotTo(HaveOccurred())
+
+		err = listener.Close()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	resources := func(events []db.AuditEvent) []string {
+		names := []string{}
+		for _, event := range events {
+			names = append(names, event.Resource)
+		}
+
+		return names
+	}
+
+	It("returns every event, newest first", func() {
+		events, err := database.GetAuditEvents(db.AuditEventFilter{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resources(events)).To(Equal([]string{"/c", "/b", "/a"}))
+
+		Expect(events[0].ID).To(Equal(3))
+		Expect(events[0].Time).To(BeTemporally("~", time.Now(), time.Minute))
+		Expect(events[0].Actor).To(Equal("team:some-team"))
+		Expect(events[0].Action).To(Equal("AbortBuild"))
+		Expect(events[0].BodySHA256).To(Equal("sha-3"))
+		Expect(events[0].Status).To(Equal(404))
+	})
+
+	It("filters by actor and action", func() {
+		events, err := database.GetAuditEvents(db.AuditEventFilter{Actor: "team:some-team"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resources(events)).To(Equal([]string{"/c", "/a"}))
+
+		events, err = database.GetAuditEvents(db.AuditEventFilter{Action: "AbortBuild"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resources(events)).To(Equal([]string{"/c", "/b"}))
+	})
+
+	It("pages with until and limit", func() {
+		events, err := database.GetAuditEvents(db.AuditEventFilter{Until: 3, Limit: 1})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resources(events)).To(Equal([]string{"/b"}))
+	})
+})
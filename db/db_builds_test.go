@@ -1,6 +1,7 @@
 package db_test
 
 import (
+	"context"
 	"time"
 
 	"github.com/lib/pq"
@@ -95,7 +96,7 @@ var _ = Describe("Builds", func() {
 			},
 		}
 
-		pipeline, _, err = teamDB.SaveConfig("some-pipeline", config, db.ConfigVersion(1), db.PipelineUnpaused)
+		pipeline, _, err = teamDB.SaveConfig("some-pipeline", config, db.ConfigVersion(1), db.PipelineUnpaused, "some-author")
 		Expect(err).NotTo(HaveOccurred())
 
 		pipelineDBFactory = db.NewPipelineDBFactory(dbConn, bus, lockFactory)
@@ -179,14 +180,14 @@ var _ = Describe("Builds", func() {
 			Expect(err).NotTo(HaveOccurred())
 
 			config := atc.Config{Jobs: atc.JobConfigs{{Name: "some-job"}}}
-			privatePipeline, _, err := teamDB.SaveConfig("private-pipeline", config, db.ConfigVersion(1), db.PipelineUnpaused)
+			privatePipeline, _, err := teamDB.SaveConfig("private-pipeline", config, db.ConfigVersion(1), db.PipelineUnpaused, "some-author")
 			Expect(err).NotTo(HaveOccurred())
 			privatePipelineDB := pipelineDBFactory.Build(privatePipeline)
 
 			privateBuild, err = privatePipelineDB.CreateJobBuild("some-job")
 			Expect(err).NotTo(HaveOccurred())
 
-			publicPipeline, _, err := teamDB.SaveConfig("public-pipeline", config, db.ConfigVersion(1), db.PipelineUnpaused)
+			publicPipeline, _, err := teamDB.SaveConfig("public-pipeline", config, db.ConfigVersion(1), db.PipelineUnpaused, "some-author")
 			Expect(err).NotTo(HaveOccurred())
 			publicPipelineDB := pipelineDBFactory.Build(publicPipeline)
 			publicPipelineDB.Expose()
@@ -196,7 +197,7 @@ var _ = Describe("Builds", func() {
 		})
 
 		It("returns public builds", func() {
-			builds, _, err := database.GetPublicBuilds(db.Page{Limit: 10})
+			builds, _, err := database.GetPublicBuilds(context.Background(), db.Page{Limit: 10}, db.BuildFilter{})
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(builds).To(HaveLen(1))
@@ -204,6 +205,42 @@ var _ = Describe("Builds", func() {
 		})
 	})
 
+	Describe("GetBuilds", func() {
+		It("returns the builds with the given ids", func() {
+			build1DB, err := teamDB.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = teamDB.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+
+			build3DB, err := teamDB.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+
+			builds, err := database.GetBuilds(context.Background(), []int{build1DB.ID(), build3DB.ID(), 4242})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(builds).To(HaveLen(2))
+			Expect(builds[0].ID()).To(Equal(build3DB.ID()))
+			Expect(builds[1].ID()).To(Equal(build1DB.ID()))
+		})
+
+		It("returns no builds when no ids are given", func() {
+			builds, err := database.GetBuilds(context.Background(), []int{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(builds).To(BeEmpty())
+		})
+
+		It("gives up once the context is done", func() {
+			build, err := teamDB.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			_, err = database.GetBuilds(ctx, []int{build.ID()})
+			Expect(err).To(Equal(context.Canceled))
+		})
+	})
+
 	Describe("GetAllStartedBuilds", func() {
 		var build1DB db.Build
 		var build2DB db.Build
@@ -238,6 +275,166 @@ var _ = Describe("Builds", func() {
 		})
 	})
 
+	Describe("CreateOneOffBuild", func() {
+		var plan atc.Plan
+
+		BeforeEach(func() {
+			plan = atc.Plan{
+				ID: "some-plan",
+				Task: &atc.TaskPlan{
+					Name: "some-task",
+				},
+			}
+		})
+
+		It("creates a pending one-off build for the team with everything in the spec", func() {
+			build, err := database.CreateOneOffBuild(context.Background(), "some-team", db.OneOffBuildSpec{
+				Plan:     plan,
+				Labels:   map[string]string{"env": "staging"},
+				Priority: 3,
+				Timeout:  90 * time.Minute,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(build.IsOneOff()).To(BeTrue())
+			Expect(build.Status()).To(Equal(db.StatusPending))
+			Expect(build.TeamName()).To(Equal("some-team"))
+			Expect(build.Labels()).To(Equal(map[string]string{"env": "staging"}))
+			Expect(build.Priority()).To(Equal(3))
+			Expect(build.Timeout()).To(Equal(90 * time.Minute))
+
+			found, err := build.Reload()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(build.Labels()).To(Equal(map[string]string{"env": "staging"}))
+			Expect(build.Priority()).To(Equal(3))
+			Expect(build.Timeout()).To(Equal(90 * time.Minute))
+		})
+
+		It("can have events saved", func() {
+			build, err := database.CreateOneOffBuild(context.Background(), "some-team", db.OneOffBuildSpec{Plan: plan})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = build.SaveEvent(event.Log{Payload: "some-log"})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("leaves the plan to be claimed, once", func() {
+			build, err := database.CreateOneOffBuild(context.Background(), "some-team", db.OneOffBuildSpec{Plan: plan})
+			Expect(err).NotTo(HaveOccurred())
+
+			claimedPlan, claimed, err := build.ClaimPendingPlan()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(claimed).To(BeTrue())
+			Expect(claimedPlan).To(Equal(plan))
+
+			_, claimed, err = build.ClaimPendingPlan()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(claimed).To(BeFalse())
+		})
+
+		It("is picked up along with builds awaiting dependencies until its plan is claimed", func() {
+			build, err := database.CreateOneOffBuild(context.Background(), "some-team", db.OneOffBuildSpec{Plan: plan})
+			Expect(err).NotTo(HaveOccurred())
+
+			builds, err := database.GetBuildsAwaitingDependencies()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(builds).To(HaveLen(1))
+			Expect(builds[0].ID()).To(Equal(build.ID()))
+
+			statuses, err := builds[0].GetDependencyStatuses()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statuses).To(BeEmpty())
+
+			_, _, err = build.ClaimPendingPlan()
+			Expect(err).NotTo(HaveOccurred())
+
+			builds, err = database.GetBuildsAwaitingDependencies()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(builds).To(BeEmpty())
+		})
+
+		Context("with dependencies", func() {
+			It("holds the build back until they've finished", func() {
+				dependency, err := teamDB.CreateOneOffBuild()
+				Expect(err).NotTo(HaveOccurred())
+
+				build, err := database.CreateOneOffBuild(context.Background(), "some-team", db.OneOffBuildSpec{
+					Plan:      plan,
+					DependsOn: []int{dependency.ID()},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				statuses, err := build.GetDependencyStatuses()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(statuses).To(Equal(map[int]db.Status{
+					dependency.ID(): db.StatusPending,
+				}))
+			})
+
+			It("creates nothing if a dependency does not exist", func() {
+				_, err := database.CreateOneOffBuild(context.Background(), "some-team", db.OneOffBuildSpec{
+					Plan:      plan,
+					DependsOn: []int{4242},
+				})
+				Expect(err).To(HaveOccurred())
+
+				builds, _, err := teamDB.GetBuilds(db.Page{Limit: 10}, db.BuildFilter{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(builds).To(BeEmpty())
+			})
+		})
+
+		Context("when the team does not exist", func() {
+			It("returns ErrTeamNotFound", func() {
+				_, err := database.CreateOneOffBuild(context.Background(), "some-bogus-team", db.OneOffBuildSpec{Plan: plan})
+				Expect(err).To(Equal(db.ErrTeamNotFound))
+			})
+		})
+
+		Context("when the context is already done", func() {
+			It("creates nothing", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				_, err := database.CreateOneOffBuild(ctx, "some-team", db.OneOffBuildSpec{Plan: plan})
+				Expect(err).To(HaveOccurred())
+
+				builds, _, err := teamDB.GetBuilds(db.Page{Limit: 10}, db.BuildFilter{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(builds).To(BeEmpty())
+			})
+		})
+	})
+
+	Describe("GetBuildsAwaitingDependencies", func() {
+		It("returns pending builds that have a plan held back", func() {
+			dependency, err := teamDB.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+
+			awaiting, err := teamDB.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+
+			err = awaiting.SaveDependencies([]int{dependency.ID()}, atc.Plan{ID: "some-plan"})
+			Expect(err).NotTo(HaveOccurred())
+
+			claimed, err := teamDB.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+
+			err = claimed.SaveDependencies([]int{dependency.ID()}, atc.Plan{ID: "some-plan"})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, found, err := claimed.ClaimPendingPlan()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+
+			builds, err := database.GetBuildsAwaitingDependencies()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(builds).To(HaveLen(1))
+			Expect(builds[0].ID()).To(Equal(awaiting.ID()))
+		})
+	})
+
 	Describe("DeleteBuildEventsByBuildIDs", func() {
 		It("deletes all build logs corresponding to the given build ids", func() {
 			build1DB, err := teamDB.CreateOneOffBuild()
@@ -295,7 +492,7 @@ var _ = Describe("Builds", func() {
 			Expect(err).NotTo(HaveOccurred())
 			defer events2.Close()
 
-			build2Event1, err := events2.Next()
+			build2Event1, err := untimed(events2.Next())
 			Expect(err).NotTo(HaveOccurred())
 			Expect(build2Event1).To(Equal(envelope(event.Log{
 				Payload: "log 2",
@@ -353,4 +550,767 @@ var _ = Describe("Builds", func() {
 			Expect(build4DB.ReapTime()).To(Equal(build1DB.ReapTime()))
 		})
 	})
+
+	Describe("DeleteBuildEventsBefore", func() {
+		It("reaps completed builds that ended before the given time", func() {
+			build1DB, err := teamDB.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+
+			err = build1DB.Finish(db.StatusSucceeded)
+			Expect(err).NotTo(HaveOccurred())
+
+			build2DB, err := teamDB.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+
+			By("not reaping anything that ended after the cutoff")
+			reaped, err := database.DeleteBuildEventsBefore(time.Now().Add(-time.Hour), 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reaped).To(BeZero())
+
+			By("reaping only completed builds")
+			reaped, err = database.DeleteBuildEventsBefore(time.Now().Add(time.Hour), 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reaped).To(Equal(1))
+
+			found, err := build1DB.Reload()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(build1DB.ReapTime()).NotTo(BeZero())
+
+			found, err = build2DB.Reload()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(build2DB.ReapTime()).To(BeZero())
+
+			By("not reaping the same build twice")
+			reaped, err = database.DeleteBuildEventsBefore(time.Now().Add(time.Hour), 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reaped).To(BeZero())
+		})
+	})
+
+	Describe("GetUnreapedBuildsEndedBefore", func() {
+		It("returns completed builds that ended before the given time and have not been reaped", func() {
+			build1DB, err := teamDB.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+
+			err = build1DB.Finish(db.StatusSucceeded)
+			Expect(err).NotTo(HaveOccurred())
+
+			build2DB, err := teamDB.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+
+			err = build2DB.Finish(db.StatusFailed)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = teamDB.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+
+			builds, err := database.GetUnreapedBuildsEndedBefore(time.Now().Add(-time.Hour), 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(builds).To(BeEmpty())
+
+			err = database.DeleteBuildEventsByBuildIDs([]int{build2DB.ID()})
+			Expect(err).NotTo(HaveOccurred())
+
+			builds, err = database.GetUnreapedBuildsEndedBefore(time.Now().Add(time.Hour), 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(builds).To(HaveLen(1))
+			Expect(builds[0].ID()).To(Equal(build1DB.ID()))
+		})
+	})
+
+	Describe("GetLastBuildReapTime", func() {
+		It("returns the most recent reap time", func() {
+			_, found, err := database.GetLastBuildReapTime(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+
+			buildDB, err := teamDB.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+
+			err = buildDB.Finish(db.StatusSucceeded)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = database.DeleteBuildEventsByBuildIDs([]int{buildDB.ID()})
+			Expect(err).NotTo(HaveOccurred())
+
+			found, err = buildDB.Reload()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+
+			reapTime, found, err := database.GetLastBuildReapTime(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(reapTime).To(BeTemporally("~", buildDB.ReapTime(), time.Second))
+		})
+	})
+
+	Describe("DeleteBuildsBefore", func() {
+		It("deletes finished builds other than each job's latest and latest successful", func() {
+			oldBuild := createAndStartBuild(database, pipelineDB, "some-job", "some-engine")
+			err := oldBuild.SaveEvent(event.Log{Payload: "some-log"})
+			Expect(err).NotTo(HaveOccurred())
+			err = oldBuild.Finish(db.StatusSucceeded)
+			Expect(err).NotTo(HaveOccurred())
+
+			failedBuild := createAndFinishBuild(database, pipelineDB, "some-job", db.StatusFailed)
+			latestSuccessfulBuild := createAndFinishBuild(database, pipelineDB, "some-job", db.StatusSucceeded)
+			latestBuild := createAndFinishBuild(database, pipelineDB, "some-job", db.StatusFailed)
+
+			oneOffBuild, err := teamDB.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+			err = oneOffBuild.Finish(db.StatusSucceeded)
+			Expect(err).NotTo(HaveOccurred())
+
+			runningBuild, err := teamDB.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+
+			By("not deleting anything that finished after the cutoff")
+			deleted, err := database.DeleteBuildsBefore(time.Now().Add(-time.Hour), 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(BeZero())
+
+			By("deleting at most the limit, oldest first")
+			deleted, err = database.DeleteBuildsBefore(time.Now().Add(time.Hour), 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(Equal(1))
+
+			found, err := oldBuild.Reload()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+
+			events, err := database.GetBuildEventsFrom(context.Background(), oldBuild.ID(), 0, 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(BeEmpty())
+
+			By("deleting the rest")
+			deleted, err = database.DeleteBuildsBefore(time.Now().Add(time.Hour), 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(Equal(2))
+
+			for _, build := range []db.Build{failedBuild, oneOffBuild} {
+				found, err := build.Reload()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeFalse())
+			}
+
+			for _, build := range []db.Build{latestSuccessfulBuild, latestBuild, runningBuild} {
+				found, err := build.Reload()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+			}
+		})
+	})
+
+	Describe("DeleteBuildsBeyond", func() {
+		It("deletes finished builds beyond each job's latest, keeping its latest successful", func() {
+			successfulBuild := createAndFinishBuild(database, pipelineDB, "some-job", db.StatusSucceeded)
+			failedBuild1 := createAndFinishBuild(database, pipelineDB, "some-job", db.StatusFailed)
+			failedBuild2 := createAndFinishBuild(database, pipelineDB, "some-job", db.StatusFailed)
+			failedBuild3 := createAndFinishBuild(database, pipelineDB, "some-job", db.StatusFailed)
+			failedBuild4 := createAndFinishBuild(database, pipelineDB, "some-job", db.StatusFailed)
+
+			otherJobBuild := createAndFinishBuild(database, pipelineDB, "some-other-job", db.StatusFailed)
+
+			oneOffBuild, err := teamDB.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+			err = oneOffBuild.Finish(db.StatusSucceeded)
+			Expect(err).NotTo(HaveOccurred())
+
+			deleted, err := database.DeleteBuildsBeyond(2, 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(Equal(2))
+
+			for _, build := range []db.Build{failedBuild1, failedBuild2} {
+				found, err := build.Reload()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeFalse())
+			}
+
+			for _, build := range []db.Build{successfulBuild, failedBuild3, failedBuild4, otherJobBuild, oneOffBuild} {
+				found, err := build.Reload()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+			}
+
+			deleted, err = database.DeleteBuildsBeyond(2, 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(BeZero())
+		})
+	})
+
+	Describe("GetLastBuildPrune", func() {
+		It("returns only the last prune", func() {
+			_, found, err := database.GetLastBuildPrune(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+
+			err = database.SaveBuildPrune(db.BuildPrune{PrunedAt: time.Unix(100, 0), Builds: 3})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = database.SaveBuildPrune(db.BuildPrune{PrunedAt: time.Unix(200, 0), Builds: 0})
+			Expect(err).NotTo(HaveOccurred())
+
+			prune, found, err := database.GetLastBuildPrune(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(prune.PrunedAt.Unix()).To(Equal(int64(200)))
+			Expect(prune.Builds).To(BeZero())
+		})
+	})
+
+	Describe("GetBuildEventsFrom", func() {
+		It("returns a batch of the build's events from the offset on", func() {
+			oneOffBuild, err := teamDB.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, build := range []db.Build{
+				createAndStartBuild(database, pipelineDB, "some-job", "some-engine"),
+				oneOffBuild,
+			} {
+				for _, payload := range []string{"a", "b", "c", "d"} {
+					err := build.SaveEvent(event.Log{Payload: payload})
+					Expect(err).NotTo(HaveOccurred())
+				}
+
+				events, err := database.GetBuildEventsFrom(context.Background(), build.ID(), 1, 2)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(events).To(HaveLen(2))
+				Expect(events[0].Event).To(Equal(event.EventTypeLog))
+				Expect(string(*events[0].Data)).To(ContainSubstring(`"payload":"b"`))
+				Expect(string(*events[1].Data)).To(ContainSubstring(`"payload":"c"`))
+				Expect(events[1].Time).NotTo(BeZero())
+
+				events, err = database.GetBuildEventsFrom(context.Background(), build.ID(), 3, 2)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(events).To(HaveLen(1))
+
+				events, err = database.GetBuildEventsFrom(context.Background(), build.ID(), 4, 2)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(events).To(BeEmpty())
+			}
+		})
+
+		It("returns nothing for builds that don't exist", func() {
+			events, err := database.GetBuildEventsFrom(context.Background(), 1234, 0, 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(BeEmpty())
+		})
+	})
+
+	Describe("global max in flight", func() {
+		It("defaults to no limit", func() {
+			maxInFlight, err := database.GetGlobalMaxInFlight(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(maxInFlight).To(BeZero())
+
+			reached, err := database.GlobalMaxInFlightReached()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reached).To(BeFalse())
+		})
+
+		It("can be set and updated", func() {
+			err := database.SetGlobalMaxInFlight(context.Background(), 2)
+			Expect(err).NotTo(HaveOccurred())
+
+			maxInFlight, err := database.GetGlobalMaxInFlight(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(maxInFlight).To(Equal(2))
+
+			err = database.SetGlobalMaxInFlight(context.Background(), 5)
+			Expect(err).NotTo(HaveOccurred())
+
+			maxInFlight, err = database.GetGlobalMaxInFlight(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(maxInFlight).To(Equal(5))
+		})
+
+		It("is reached once enough builds are started or about to start", func() {
+			err := database.SetGlobalMaxInFlight(context.Background(), 2)
+			Expect(err).NotTo(HaveOccurred())
+
+			createAndStartBuild(database, pipelineDB, "some-job", "some-engine")
+
+			reached, err := database.GlobalMaxInFlightReached()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reached).To(BeFalse())
+
+			scheduledBuild, err := pipelineDB.CreateJobBuild("some-other-job")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = pipelineDB.CreateJobBuild("some-random-job")
+			Expect(err).NotTo(HaveOccurred())
+
+			reached, err = database.GlobalMaxInFlightReached()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reached).To(BeFalse())
+
+			scheduled, err := pipelineDB.UpdateBuildToScheduled(scheduledBuild.ID())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(scheduled).To(BeTrue())
+
+			reached, err = database.GlobalMaxInFlightReached()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reached).To(BeTrue())
+
+			err = database.SetGlobalMaxInFlight(context.Background(), 0)
+			Expect(err).NotTo(HaveOccurred())
+
+			reached, err = database.GlobalMaxInFlightReached()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reached).To(BeFalse())
+		})
+	})
+
+	Describe("pipeline weights", func() {
+		var otherPipelineDB db.PipelineDB
+
+		BeforeEach(func() {
+			otherPipeline, _, err := teamDB.SaveConfig("some-other-pipeline", config, db.ConfigVersion(1), db.PipelineUnpaused, "some-author")
+			Expect(err).NotTo(HaveOccurred())
+
+			otherPipelineDB = pipelineDBFactory.Build(otherPipeline)
+		})
+
+		It("defaults to the same weight for every pipeline", func() {
+			weights, err := database.GetPipelineWeights(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(weights).To(ConsistOf(
+				db.PipelineWeight{PipelineID: pipelineDB.GetPipelineID(), PipelineName: "some-pipeline", TeamName: "some-team", Weight: db.DefaultPipelineWeight},
+				db.PipelineWeight{PipelineID: otherPipelineDB.GetPipelineID(), PipelineName: "some-other-pipeline", TeamName: "some-team", Weight: db.DefaultPipelineWeight},
+			))
+		})
+
+		It("can be set and updated", func() {
+			err := database.SetPipelineWeight(context.Background(), otherPipelineDB.GetPipelineID(), 3)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = database.SetPipelineWeight(context.Background(), otherPipelineDB.GetPipelineID(), 5)
+			Expect(err).NotTo(HaveOccurred())
+
+			weights, err := database.GetPipelineWeights(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(weights).To(ContainElement(
+				db.PipelineWeight{PipelineID: otherPipelineDB.GetPipelineID(), PipelineName: "some-other-pipeline", TeamName: "some-team", Weight: 5},
+			))
+		})
+
+		Describe("FairShareReached", func() {
+			It("is not reached without a global max in flight", func() {
+				createAndStartBuild(database, pipelineDB, "some-job", "some-engine")
+				createAndStartBuild(database, pipelineDB, "some-job", "some-engine")
+
+				_, err := otherPipelineDB.CreateJobBuild("some-job")
+				Expect(err).NotTo(HaveOccurred())
+
+				reached, err := database.FairShareReached(pipelineDB.GetPipelineID())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(reached).To(BeFalse())
+			})
+
+			Context("with a global max in flight", func() {
+				BeforeEach(func() {
+					err := database.SetGlobalMaxInFlight(context.Background(), 4)
+					Expect(err).NotTo(HaveOccurred())
+
+					createAndStartBuild(database, pipelineDB, "some-job", "some-engine")
+					createAndStartBuild(database, pipelineDB, "some-job", "some-engine")
+
+					_, err = pipelineDB.CreateJobBuild("some-job")
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("is not reached while nobody else is waiting", func() {
+					reached, err := database.FairShareReached(pipelineDB.GetPipelineID())
+					Expect(err).NotTo(HaveOccurred())
+					Expect(reached).To(BeFalse())
+				})
+
+				Context("when another pipeline is waiting for its share", func() {
+					BeforeEach(func() {
+						_, err := otherPipelineDB.CreateJobBuild("some-job")
+						Expect(err).NotTo(HaveOccurred())
+
+						_, err = otherPipelineDB.CreateJobBuild("some-other-job")
+						Expect(err).NotTo(HaveOccurred())
+					})
+
+					It("is reached by the pipeline with its share", func() {
+						reached, err := database.FairShareReached(pipelineDB.GetPipelineID())
+						Expect(err).NotTo(HaveOccurred())
+						Expect(reached).To(BeTrue())
+
+						reached, err = database.FairShareReached(otherPipelineDB.GetPipelineID())
+						Expect(err).NotTo(HaveOccurred())
+						Expect(reached).To(BeFalse())
+					})
+
+					It("is not reached once the pipeline is weighted above the other", func() {
+						err := database.SetPipelineWeight(context.Background(), pipelineDB.GetPipelineID(), 3)
+						Expect(err).NotTo(HaveOccurred())
+
+						reached, err := database.FairShareReached(pipelineDB.GetPipelineID())
+						Expect(err).NotTo(HaveOccurred())
+						Expect(reached).To(BeFalse())
+					})
+
+					It("is not reached once the other pipeline is paused", func() {
+						err := otherPipelineDB.Pause()
+						Expect(err).NotTo(HaveOccurred())
+
+						reached, err := database.FairShareReached(pipelineDB.GetPipelineID())
+						Expect(err).NotTo(HaveOccurred())
+						Expect(reached).To(BeFalse())
+					})
+				})
+			})
+		})
+	})
+
+	Describe("GetUsage", func() {
+		day := time.Date(2016, 1, 2, 0, 0, 0, 0, time.UTC)
+
+		finishBuildAt := func(build db.Build, start time.Time, duration time.Duration) {
+			err := build.Finish(db.StatusSucceeded)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = dbConn.Exec(`
+				UPDATE builds
+				SET start_time = $2, end_time = $3
+				WHERE id = $1
+			`, build.ID(), start, start.Add(duration))
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		BeforeEach(func() {
+			first := createAndStartBuild(database, pipelineDB, "some-job", "some-engine")
+			Expect(first.SaveResourceUsage(db.ResourceUsage{CPUNanoseconds: 30e9, MemoryBytes: 100})).To(Succeed())
+
+			// two steps of 20s each, run in parallel
+			Expect(first.SaveEvent(event.InitializeTask{Origin: event.Origin{ID: "1"}})).To(Succeed())
+			Expect(first.SaveEvent(event.InitializeTask{Origin: event.Origin{ID: "2"}})).To(Succeed())
+			Expect(first.SaveEvent(event.FinishTask{Origin: event.Origin{ID: "1"}})).To(Succeed())
+			Expect(first.SaveEvent(event.FinishTask{Origin: event.Origin{ID: "2"}})).To(Succeed())
+
+			_, err := dbConn.Exec(`
+				UPDATE build_steps
+				SET started_at = $2, finished_at = $3
+				WHERE build_id = $1
+			`, first.ID(), day, day.Add(20*time.Second))
+			Expect(err).NotTo(HaveOccurred())
+
+			finishBuildAt(first, day, 30*time.Second)
+
+			second := createAndStartBuild(database, pipelineDB, "some-other-job", "some-engine")
+			Expect(second.SaveResourceUsage(db.ResourceUsage{CPUNanoseconds: 10e9, MemoryBytes: 200})).To(Succeed())
+			finishBuildAt(second, day.Add(time.Hour), 10*time.Second)
+
+			oneOff, err := teamDB.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+
+			started, err := oneOff.Start("some-engine", "so-meta")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(started).To(BeTrue())
+
+			finishBuildAt(oneOff, day.Add(24*time.Hour), 5*time.Second)
+
+			// never started, so it used nothing
+			_, err = pipelineDB.CreateJobBuild("some-random-job")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("adds up each pipeline's usage over each interval", func() {
+			usages, err := database.GetUsage(context.Background(), db.UsageFilter{
+				Since:    day.Add(-time.Hour),
+				Until:    day.Add(48 * time.Hour),
+				Interval: db.UsageIntervalDay,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(usages).To(HaveLen(2))
+
+			Expect(usages[0].TeamName).To(Equal("some-team"))
+			Expect(usages[0].PipelineName).To(Equal("some-pipeline"))
+			Expect(usages[0].Start.Equal(day)).To(BeTrue())
+			Expect(usages[0].Builds).To(Equal(2))
+			Expect(usages[0].BuildTime).To(Equal(40 * time.Second))
+			Expect(usages[0].WorkerTime).To(Equal(40 * time.Second))
+			Expect(usages[0].CPUTime).To(Equal(40 * time.Second))
+			Expect(usages[0].MemoryByteSeconds).To(Equal(float64(100*30 + 200*10)))
+
+			Expect(usages[1].TeamName).To(Equal("some-team"))
+			Expect(usages[1].PipelineName).To(BeEmpty())
+			Expect(usages[1].Start.Equal(day.Add(24 * time.Hour))).To(BeTrue())
+			Expect(usages[1].Builds).To(Equal(1))
+			Expect(usages[1].BuildTime).To(Equal(5 * time.Second))
+			Expect(usages[1].WorkerTime).To(BeZero())
+		})
+
+		It("only counts builds that finished in the given range", func() {
+			usages, err := database.GetUsage(context.Background(), db.UsageFilter{
+				Since:    day.Add(time.Minute),
+				Until:    day.Add(2 * time.Hour),
+				Interval: db.UsageIntervalMonth,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(usages).To(HaveLen(1))
+			Expect(usages[0].Start.Equal(time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC))).To(BeTrue())
+			Expect(usages[0].Builds).To(Equal(1))
+			Expect(usages[0].BuildTime).To(Equal(10 * time.Second))
+		})
+
+		It("can be narrowed down to a team", func() {
+			usages, err := database.GetUsage(context.Background(), db.UsageFilter{
+				Since:    day.Add(-time.Hour),
+				Until:    day.Add(48 * time.Hour),
+				Interval: db.UsageIntervalWeek,
+				TeamName: "some-other-team",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(usages).To(BeEmpty())
+		})
+	})
+
+	Describe("GetBuildQueue", func() {
+		var first, second, third, checking db.Build
+
+		BeforeEach(func() {
+			var err error
+			first, err = pipelineDB.CreateJobBuild("some-job")
+			Expect(err).NotTo(HaveOccurred())
+
+			second, err = pipelineDB.CreateJobBuild("some-job")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = second.SetPriority(10)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = pipelineDB.SaveSchedulingReason("some-job", db.SchedulingReasonMaxInFlightReached)
+			Expect(err).NotTo(HaveOccurred())
+
+			third, err = pipelineDB.CreateJobBuild("some-job")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = dbConn.Exec(`
+				UPDATE jobs
+				SET resource_checking = true, resource_check_waiver_end = $1
+				WHERE name = 'some-other-job'
+			`, third.ID())
+			Expect(err).NotTo(HaveOccurred())
+
+			checking, err = pipelineDB.CreateJobBuild("some-other-job")
+			Expect(err).NotTo(HaveOccurred())
+
+			createAndStartBuild(database, pipelineDB, "some-random-job", "some-engine")
+			createAndFinishBuild(database, pipelineDB, "some-random-job", db.StatusSucceeded)
+		})
+
+		It("lists each job's pending builds in the order they'll be started", func() {
+			queue, err := database.GetBuildQueue(context.Background(), "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(queue).To(HaveLen(4))
+
+			Expect(queue[0].BuildID).To(Equal(checking.ID()))
+			Expect(queue[0].JobName).To(Equal("some-other-job"))
+			Expect(queue[0].Reason).To(Equal(db.SchedulingReasonCheckingResources))
+			Expect(queue[0].Position).To(Equal(1))
+
+			Expect(queue[1].BuildID).To(Equal(second.ID()))
+			Expect(queue[1].BuildName).To(Equal(second.Name()))
+			Expect(queue[1].TeamName).To(Equal("some-team"))
+			Expect(queue[1].PipelineName).To(Equal("some-pipeline"))
+			Expect(queue[1].JobName).To(Equal("some-job"))
+			Expect(queue[1].Priority).To(Equal(10))
+			Expect(queue[1].CreateTime).To(BeTemporally("~", time.Now(), time.Minute))
+			Expect(queue[1].Reason).To(Equal(db.SchedulingReasonMaxInFlightReached))
+			Expect(queue[1].CheckedAt).To(BeTemporally("~", time.Now(), time.Minute))
+			Expect(queue[1].Position).To(Equal(1))
+
+			Expect(queue[2].BuildID).To(Equal(first.ID()))
+			Expect(queue[2].Reason).To(Equal(db.SchedulingReasonMaxInFlightReached))
+			Expect(queue[2].Position).To(Equal(2))
+
+			Expect(queue[3].BuildID).To(Equal(third.ID()))
+			Expect(queue[3].Position).To(Equal(3))
+		})
+
+		It("leaves out reasons from before the build was created", func() {
+			queue, err := database.GetBuildQueue(context.Background(), "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(queue).To(HaveLen(4))
+
+			Expect(queue[3].BuildID).To(Equal(third.ID()))
+			Expect(queue[3].Reason).To(BeEmpty())
+		})
+
+		It("can be narrowed down to a team", func() {
+			queue, err := database.GetBuildQueue(context.Background(), "some-other-team")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(queue).To(BeEmpty())
+		})
+	})
+
+	Describe("duration alerts", func() {
+		finishBuild := func(jobName string, status db.Status, duration time.Duration, endedAgo time.Duration) {
+			build := createAndStartBuild(database, pipelineDB, jobName, "some-engine")
+
+			err := build.Finish(status)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = dbConn.Exec(`
+				UPDATE builds
+				SET end_time = now() - ($2 || ' SECONDS')::INTERVAL,
+					start_time = now() - ($3 || ' SECONDS')::INTERVAL
+				WHERE id = $1
+			`, build.ID(), endedAgo.Seconds(), (endedAgo + duration).Seconds())
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		Describe("GetJobDurationPercentile", func() {
+			BeforeEach(func() {
+				for i := 1; i <= 19; i++ {
+					finishBuild("some-job", db.StatusSucceeded, time.Duration(i)*time.Minute, time.Hour)
+				}
+
+				finishBuild("some-job", db.StatusFailed, 20*time.Minute, time.Hour)
+				finishBuild("some-job", db.StatusErrored, 2*time.Hour, time.Hour)
+				finishBuild("some-job", db.StatusSucceeded, 10*time.Hour, 48*time.Hour)
+				finishBuild("some-other-job", db.StatusSucceeded, 5*time.Hour, time.Hour)
+			})
+
+			It("ranks the job's succeeded and failed builds within the window", func() {
+				duration, builds, err := database.GetJobDurationPercentile(pipeline.ID, "some-job", 95, 24*time.Hour)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(builds).To(Equal(20))
+				Expect(duration).To(BeNumerically("~", 19*time.Minute, time.Second))
+			})
+
+			It("includes older builds in wider windows", func() {
+				duration, builds, err := database.GetJobDurationPercentile(pipeline.ID, "some-job", 100, 72*time.Hour)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(builds).To(Equal(21))
+				Expect(duration).To(BeNumerically("~", 10*time.Hour, time.Second))
+			})
+
+			It("returns nothing for jobs without builds", func() {
+				duration, builds, err := database.GetJobDurationPercentile(pipeline.ID, "some-random-job", 95, 24*time.Hour)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(builds).To(BeZero())
+				Expect(duration).To(BeZero())
+			})
+		})
+
+		Describe("SaveDurationAlert", func() {
+			var build db.Build
+
+			BeforeEach(func() {
+				build = createAndStartBuild(database, pipelineDB, "some-job", "some-engine")
+
+				saved, err := build.SaveDurationAlert(95, 4*time.Minute, 40*time.Minute)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(saved).To(BeTrue())
+			})
+
+			It("only saves one per build", func() {
+				saved, err := build.SaveDurationAlert(95, 4*time.Minute, 41*time.Minute)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(saved).To(BeFalse())
+			})
+
+			It("is listed for the build's team", func() {
+				alerts, err := database.GetDurationAlerts(context.Background(), "some-team")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(alerts).To(HaveLen(1))
+
+				Expect(alerts[0].BuildID).To(Equal(build.ID()))
+				Expect(alerts[0].BuildName).To(Equal(build.Name()))
+				Expect(alerts[0].TeamName).To(Equal("some-team"))
+				Expect(alerts[0].PipelineName).To(Equal("some-pipeline"))
+				Expect(alerts[0].JobName).To(Equal("some-job"))
+				Expect(alerts[0].Percentile).To(Equal(95))
+				Expect(alerts[0].Threshold).To(Equal(4 * time.Minute))
+				Expect(alerts[0].Elapsed).To(Equal(40 * time.Minute))
+				Expect(alerts[0].CreatedAt).To(BeTemporally("~", time.Now(), time.Minute))
+			})
+
+			It("lists the most recent first", func() {
+				other := createAndStartBuild(database, pipelineDB, "some-other-job", "some-engine")
+
+				saved, err := other.SaveDurationAlert(95, time.Minute, 10*time.Minute)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(saved).To(BeTrue())
+
+				alerts, err := database.GetDurationAlerts(context.Background(), "some-team")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(alerts).To(HaveLen(2))
+				Expect(alerts[0].BuildID).To(Equal(other.ID()))
+				Expect(alerts[1].BuildID).To(Equal(build.ID()))
+			})
+
+			It("isn't listed for other teams", func() {
+				alerts, err := database.GetDurationAlerts(context.Background(), "some-other-team")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(alerts).To(BeEmpty())
+			})
+		})
+	})
+
+	Describe("task caches", func() {
+		cacheAt := func(jobName string, path string, size int64) db.TaskCache {
+			return db.TaskCache{
+				PipelineID: pipeline.ID,
+				JobName:    jobName,
+				Path:       path,
+				SizeBytes:  size,
+			}
+		}
+
+		BeforeEach(func() {
+			Expect(database.SaveTaskCache(cacheAt("some-job", "node_modules", 100))).To(Succeed())
+			Expect(database.SaveTaskCache(cacheAt("some-job", ".m2", 200))).To(Succeed())
+			Expect(database.SaveTaskCache(cacheAt("some-other-job", ".m2", 300))).To(Succeed())
+		})
+
+		It("lists each job's caches by path", func() {
+			caches, err := pipelineDB.GetTaskCaches("some-job")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(caches).To(HaveLen(2))
+
+			Expect(caches[0].Path).To(Equal(".m2"))
+			Expect(caches[0].SizeBytes).To(Equal(int64(200)))
+			Expect(caches[0].SavedAt).To(BeTemporally("~", time.Now(), time.Minute))
+			Expect(caches[1].Path).To(Equal("node_modules"))
+		})
+
+		It("replaces a cache when it's saved again", func() {
+			Expect(database.SaveTaskCache(cacheAt("some-job", "node_modules", 400))).To(Succeed())
+
+			caches, err := pipelineDB.GetTaskCaches("some-job")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(caches).To(HaveLen(2))
+			Expect(caches[1].SizeBytes).To(Equal(int64(400)))
+		})
+
+		It("deletes one of the job's caches, or all of them", func() {
+			deleted, err := pipelineDB.DeleteTaskCaches("some-job", "node_modules")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(HaveLen(1))
+			Expect(deleted[0].Path).To(Equal("node_modules"))
+
+			deleted, err = pipelineDB.DeleteTaskCaches("some-job", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(HaveLen(1))
+			Expect(deleted[0].Path).To(Equal(".m2"))
+
+			caches, err := pipelineDB.GetTaskCaches("some-job")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(caches).To(BeEmpty())
+
+			caches, err = pipelineDB.GetTaskCaches("some-other-job")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(caches).To(HaveLen(1))
+		})
+	})
 })
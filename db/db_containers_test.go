@@ -82,10 +82,10 @@ var _ = Describe("Keeping track of containers", func() {
 		teamDBFactory := db.NewTeamDBFactory(dbConn, bus, lockFactory)
 		teamDB = teamDBFactory.GetTeamDB("team-name")
 
-		savedPipeline, _, err = teamDB.SaveConfig("some-pipeline", config, 0, db.PipelineUnpaused)
+		savedPipeline, _, err = teamDB.SaveConfig("some-pipeline", config, 0, db.PipelineUnpaused, "some-author")
 		Expect(err).NotTo(HaveOccurred())
 
-		savedOtherPipeline, _, err = teamDB.SaveConfig("some-other-pipeline", config, 0, db.PipelineUnpaused)
+		savedOtherPipeline, _, err = teamDB.SaveConfig("some-other-pipeline", config, 0, db.PipelineUnpaused, "some-author")
 		Expect(err).NotTo(HaveOccurred())
 
 		pipelineDBFactory := db.NewPipelineDBFactory(dbConn, bus, lockFactory)
@@ -242,6 +242,43 @@ var _ = Describe("Keeping track of containers", func() {
 		Expect([]string{handle0, handle1}).To(ConsistOf("handle-0", "handle-1"))
 	})
 
+	It("can find the handles of a build's containers", func() {
+		savedBuild, err := pipelineDB.CreateJobBuild("some-job")
+		Expect(err).NotTo(HaveOccurred())
+
+		otherBuild, err := pipelineDB.CreateJobBuild("some-job")
+		Expect(err).NotTo(HaveOccurred())
+
+		for handle, build := range map[string]db.Build{
+			"some-handle":  savedBuild,
+			"other-handle": otherBuild,
+		} {
+			_, err = database.CreateContainer(db.Container{
+				ContainerIdentifier: db.ContainerIdentifier{
+					BuildID: build.ID(),
+					PlanID:  "some-plan-id",
+					Stage:   db.ContainerStageRun,
+				},
+				ContainerMetadata: db.ContainerMetadata{
+					Handle:     handle,
+					PipelineID: savedPipeline.ID,
+					JobName:    build.JobName(),
+					Type:       db.ContainerTypeTask,
+					TeamID:     teamID,
+				},
+			}, 5*time.Minute, 0, []string{})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		handles, err := database.GetContainerHandlesForBuild(savedBuild.ID())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(handles).To(Equal([]string{"some-handle"}))
+
+		handles, err = database.GetContainerHandlesForBuild(12345)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(handles).To(BeEmpty())
+	})
+
 	It("can create and get a resource container object", func() {
 		resourceTypeVersion := atc.Version{
 			"some-resource-type": "some-version",
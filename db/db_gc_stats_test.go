@@ -0,0 +1,92 @@
+package db_test
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/db/dbfakes"
+)
+
+var _ = Describe("Garbage collection stats", func() {
+	var dbConn db.Conn
+	var listener *pq.Listener
+	var database *db.SQLDB
+
+	BeforeEach(func() {
+		postgresRunner.Truncate()
+
+		dbConn = db.Wrap(postgresRunner.Open())
+		listener = pq.NewListener(postgresRunner.DataSourceName(), time.Second, time.Minute, nil)
+
+		Eventually(listener.Ping, 5*time.Second).ShouldNot(HaveOccurred())
+		bus := db.NewNotificationsBus(listener, dbConn)
+
+		pgxConn := postgresRunner.OpenPgx()
+		fakeConnector := new(dbfakes.FakeConnector)
+		retryableConn := &db.RetryableConn{Connector: fakeConnector, Conn: pgxConn}
+
+		lockFactory := db.NewLockFactory(retryableConn)
+		database = db.NewSQL(dbConn, bus, lockFactory)
+	})
+
+	AfterEach(func() {
+		err := dbConn.Close()
+		Expect(err).NotTo(HaveOccurred())
+
+		err = listener.Close()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("counts volumes created and orphaned on each worker", func() {
+		stats, err := database.GetWorkerGCStats()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stats).To(BeEmpty())
+
+		for _, handle := range []string{"volume-1", "volume-2"} {
+			err := database.InsertVolume(db.Volume{
+				Handle:     handle,
+				WorkerName: "some-worker",
+				TTL:        time.Hour,
+				Identifier: db.VolumeIdentifier{
+					COW: &db.COWIdentifier{ParentVolumeHandle: "parent-volume"},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		err = database.ReapVolume("volume-1")
+		Expect(err).NotTo(HaveOccurred())
+
+		stats, err = database.GetWorkerGCStats()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stats).To(Equal([]db.WorkerGCStats{
+			{
+				WorkerName:      "some-worker",
+				VolumesCreated:  2,
+				VolumesOrphaned: 1,
+			},
+		}))
+	})
+
+	It("keeps only the last pass", func() {
+		_, found, err := database.GetLastGCPass()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeFalse())
+
+		err = database.SaveGCPass(db.GCPass{StartedAt: time.Unix(100, 0), FinishedAt: time.Unix(105, 0)})
+		Expect(err).NotTo(HaveOccurred())
+
+		err = database.SaveGCPass(db.GCPass{StartedAt: time.Unix(200, 0), FinishedAt: time.Unix(203, 0)})
+		Expect(err).NotTo(HaveOccurred())
+
+		pass, found, err := database.GetLastGCPass()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeTrue())
+		Expect(pass.StartedAt.Unix()).To(Equal(int64(200)))
+		Expect(pass.Duration()).To(Equal(3 * time.Second))
+	})
+})
@@ -0,0 +1,129 @@
+package db_test
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/db/dbfakes"
+)
+
+var _ = Describe("Leadership", func() {
+	var dbConn db.Conn
+	var listener *pq.Listener
+	var database db.DB
+
+	BeforeEach(func() {
+		postgresRunner.Truncate()
+
+		dbConn = db.Wrap(postgresRunner.Open())
+		listener = pq.NewListener(postgresRunner.DataSourceName(), time.Second, time.Minute, nil)
+
+		Eventually(listener.Ping, 5*time.Second).ShouldNot(HaveOccurred())
+		bus := db.NewNotificationsBus(listener, dbConn)
+
+		pgxConn := postgresRunner.OpenPgx()
+		fakeConnector := new(dbfakes.FakeConnector)
+		retryableConn := &db.RetryableConn{Connector: fakeConnector, Conn: pgxConn}
+
+		lockFactory := db.NewLockFactory(retryableConn)
+		database = db.NewSQL(dbConn, bus, lockFactory)
+	})
+
+	AfterEach(func() {
+		err := dbConn.Close()
+		Expect(err).NotTo(HaveOccurred())
+
+		err = listener.Close()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("has no leader to begin with", func() {
+		_, found, err := database.GetLeader()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeFalse())
+	})
+
+	Describe("AcquireLeadership", func() {
+		It("makes the first to ask the leader", func() {
+			acquired, err := database.AcquireLeadership("some-atc", time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(acquired).To(BeTrue())
+
+			leader, found, err := database.GetLeader()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(leader).To(Equal("some-atc"))
+		})
+
+		It("lets the leader renew its leadership", func() {
+			_, err := database.AcquireLeadership("some-atc", time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+
+			acquired, err := database.AcquireLeadership("some-atc", time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(acquired).To(BeTrue())
+		})
+
+		It("does not let anyone else lead while the leadership lasts", func() {
+			_, err := database.AcquireLeadership("some-atc", time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+
+			acquired, err := database.AcquireLeadership("some-other-atc", time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(acquired).To(BeFalse())
+
+			leader, _, err := database.GetLeader()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(leader).To(Equal("some-atc"))
+		})
+
+		It("lets someone else take over once the leadership expires", func() {
+			_, err := database.AcquireLeadership("some-atc", time.Second)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(func() bool {
+				acquired, err := database.AcquireLeadership("some-other-atc", time.Minute)
+				Expect(err).NotTo(HaveOccurred())
+				return acquired
+			}, 5*time.Second).Should(BeTrue())
+
+			acquired, err := database.AcquireLeadership("some-atc", time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(acquired).To(BeFalse())
+		})
+	})
+
+	Describe("ReleaseLeadership", func() {
+		It("lets someone else take over straight away", func() {
+			_, err := database.AcquireLeadership("some-atc", time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = database.ReleaseLeadership("some-atc")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, found, err := database.GetLeader()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+
+			acquired, err := database.AcquireLeadership("some-other-atc", time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(acquired).To(BeTrue())
+		})
+
+		It("does nothing for anyone but the leader", func() {
+			_, err := database.AcquireLeadership("some-atc", time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = database.ReleaseLeadership("some-other-atc")
+			Expect(err).NotTo(HaveOccurred())
+
+			leader, _, err := database.GetLeader()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(leader).To(Equal("some-atc"))
+		})
+	})
+})
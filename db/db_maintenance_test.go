@@ -0,0 +1,171 @@
+package db_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/lib/pq"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/db/dbfakes"
+)
+
+var _ = Describe("Maintenance", func() {
+	Describe("windows", func() {
+		saturday := time.Date(2016, 1, 2, 0, 0, 0, 0, time.UTC)
+
+		weekends := db.MaintenanceWindow{
+			Name:     "weekends",
+			Schedule: "0 0 * * sat",
+			Duration: 48 * time.Hour,
+		}
+
+		freeze := db.MaintenanceWindow{
+			Name:     "freeze",
+			StartsAt: saturday.Add(-time.Hour),
+			EndsAt:   saturday.Add(72 * time.Hour),
+		}
+
+		It("opens scheduled windows for their duration", func() {
+			end, open := weekends.EndOfWindowAt(saturday.Add(-time.Second))
+			Expect(open).To(BeFalse())
+
+			end, open = weekends.EndOfWindowAt(saturday)
+			Expect(open).To(BeTrue())
+			Expect(end).To(Equal(saturday.Add(48 * time.Hour)))
+
+			end, open = weekends.EndOfWindowAt(saturday.Add(47*time.Hour + 59*time.Minute + 30*time.Second))
+			Expect(open).To(BeTrue())
+			Expect(end).To(Equal(saturday.Add(48 * time.Hour)))
+
+			_, open = weekends.EndOfWindowAt(saturday.Add(48 * time.Hour))
+			Expect(open).To(BeFalse())
+		})
+
+		It("opens one-off windows between their start and end", func() {
+			_, open := freeze.EndOfWindowAt(freeze.StartsAt.Add(-time.Second))
+			Expect(open).To(BeFalse())
+
+			end, open := freeze.EndOfWindowAt(freeze.StartsAt)
+			Expect(open).To(BeTrue())
+			Expect(end).To(Equal(freeze.EndsAt))
+
+			_, open = freeze.EndOfWindowAt(freeze.EndsAt)
+			Expect(open).To(BeFalse())
+		})
+
+		It("picks whichever open window ends last", func() {
+			maintenance := db.Maintenance{Windows: []db.MaintenanceWindow{weekends, freeze}}
+
+			window, end, found := maintenance.ActiveWindowAt(saturday.Add(time.Hour))
+			Expect(found).To(BeTrue())
+			Expect(window.Name).To(Equal("freeze"))
+			Expect(end).To(Equal(freeze.EndsAt))
+
+			Expect(maintenance.HoldsBuildsAt(saturday.Add(time.Hour))).To(BeTrue())
+			Expect(maintenance.HoldsBuildsAt(saturday.Add(-2 * time.Hour))).To(BeFalse())
+		})
+
+		It("doesn't hold builds while overridden", func() {
+			maintenance := db.Maintenance{
+				Windows:         []db.MaintenanceWindow{weekends},
+				OverriddenUntil: saturday.Add(time.Hour),
+			}
+
+			Expect(maintenance.HoldsBuildsAt(saturday)).To(BeFalse())
+			Expect(maintenance.HoldsBuildsAt(saturday.Add(time.Hour))).To(BeTrue())
+		})
+	})
+
+	Describe("saving them", func() {
+		var dbConn db.Conn
+		var listener *pq.Listener
+		var database *db.SQLDB
+
+		BeforeEach(func() {
+			postgresRunner.Truncate()
+
+			dbConn = db.Wrap(postgresRunner.Open())
+			listener = pq.NewListener(postgresRunner.DataSourceName(), time.Second, time.Minute, nil)
+
+			Eventually(listener.Ping, 5*time.Second).ShouldNot(HaveOccurred())
+			bus := db.NewNotificationsBus(listener, dbConn)
+
+			pgxConn := postgresRunner.OpenPgx()
+			fakeConnector := new(dbfakes.FakeConnector)
+			retryableConn := &db.RetryableConn{Connector: fakeConnector, Conn: pgxConn}
+
+			lockFactory := db.NewLockFactory(retryableConn)
+			database = db.NewSQL(dbConn, bus, lockFactory)
+		})
+
+		AfterEach(func() {
+			err := dbConn.Close()
+			Expect(err).NotTo(HaveOccurred())
+
+			err = listener.Close()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("has none to begin with", func() {
+			maintenance, err := database.GetMaintenance(context.TODO())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(maintenance.Windows).To(BeEmpty())
+			Expect(maintenance.OverriddenUntil).To(BeZero())
+
+			Expect(database.MaintenanceHoldsBuilds()).To(BeFalse())
+		})
+
+		It("replaces the windows and holds builds while one is open", func() {
+			now := time.Now()
+
+			err := database.SetMaintenanceWindows(context.TODO(), []db.MaintenanceWindow{
+				{Name: "old", StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(database.MaintenanceHoldsBuilds()).To(BeTrue())
+
+			err = database.SetMaintenanceWindows(context.TODO(), []db.MaintenanceWindow{
+				{Name: "every-day", Schedule: "@daily", Duration: time.Hour},
+				{Name: "later", StartsAt: now.Add(time.Hour), EndsAt: now.Add(2 * time.Hour)},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			maintenance, err := database.GetMaintenance(context.TODO())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(maintenance.Windows).To(HaveLen(2))
+			Expect(maintenance.Windows[0].Name).To(Equal("every-day"))
+			Expect(maintenance.Windows[0].Schedule).To(Equal("@daily"))
+			Expect(maintenance.Windows[0].Duration).To(Equal(time.Hour))
+			Expect(maintenance.Windows[1].Name).To(Equal("later"))
+			Expect(maintenance.Windows[1].StartsAt).To(BeTemporally("~", now.Add(time.Hour), time.Second))
+			Expect(maintenance.Windows[1].EndsAt).To(BeTemporally("~", now.Add(2*time.Hour), time.Second))
+		})
+
+		It("overrides the windows until told otherwise", func() {
+			now := time.Now()
+
+			err := database.SetMaintenanceWindows(context.TODO(), []db.MaintenanceWindow{
+				{Name: "freeze", StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = database.SetMaintenanceOverride(context.TODO(), now.Add(time.Minute))
+			Expect(err).NotTo(HaveOccurred())
+
+			maintenance, err := database.GetMaintenance(context.TODO())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(maintenance.OverriddenUntil).To(BeTemporally("~", now.Add(time.Minute), time.Second))
+
+			Expect(database.MaintenanceHoldsBuilds()).To(BeFalse())
+
+			err = database.SetMaintenanceOverride(context.TODO(), time.Time{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(database.MaintenanceHoldsBuilds()).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,80 @@
+package db_test
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/db/dbfakes"
+)
+
+var _ = Describe("Registered resource types", func() {
+	var dbConn db.Conn
+	var listener *pq.Listener
+	var database *db.SQLDB
+
+	BeforeEach(func() {
+		postgresRunner.Truncate()
+
+		dbConn = db.Wrap(postgresRunner.Open())
+		listener = pq.NewListener(postgresRunner.DataSourceName(), time.Second, time.Minute, nil)
+
+		Eventually(listener.Ping, 5*time.Second).ShouldNot(HaveOccurred())
+		bus := db.NewNotificationsBus(listener, dbConn)
+
+		pgxConn := postgresRunner.OpenPgx()
+		fakeConnector := new(dbfakes.FakeConnector)
+		retryableConn := &db.RetryableConn{Connector: fakeConnector, Conn: pgxConn}
+
+		lockFactory := db.NewLockFactory(retryableConn)
+		database = db.NewSQL(dbConn, bus, lockFactory)
+	})
+
+	AfterEach(func() {
+		err := dbConn.Close()
+		Expect(err).NotTo(HaveOccurred())
+
+		err = listener.Close()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("registers, replaces, lists, and unregisters resource types", func() {
+		resourceTypes, err := database.GetRegisteredResourceTypes()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resourceTypes).To(BeEmpty())
+
+		err = database.RegisterResourceType(atc.RegisteredResourceType{Name: "some-type", Image: "some/image", Version: "1.0"})
+		Expect(err).NotTo(HaveOccurred())
+
+		err = database.RegisterResourceType(atc.RegisteredResourceType{Name: "other-type", Image: "other/image", Version: "3.0"})
+		Expect(err).NotTo(HaveOccurred())
+
+		err = database.RegisterResourceType(atc.RegisteredResourceType{Name: "some-type", Image: "some/image", Version: "2.0"})
+		Expect(err).NotTo(HaveOccurred())
+
+		resourceTypes, err = database.GetRegisteredResourceTypes()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resourceTypes).To(Equal([]atc.RegisteredResourceType{
+			{Name: "other-type", Image: "other/image", Version: "3.0"},
+			{Name: "some-type", Image: "some/image", Version: "2.0"},
+		}))
+
+		unregistered, err := database.UnregisterResourceType("some-type")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(unregistered).To(BeTrue())
+
+		unregistered, err = database.UnregisterResourceType("some-type")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(unregistered).To(BeFalse())
+
+		resourceTypes, err = database.GetRegisteredResourceTypes()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resourceTypes).To(Equal([]atc.RegisteredResourceType{
+			{Name: "other-type", Image: "other/image", Version: "3.0"},
+		}))
+	})
+})
@@ -0,0 +1,121 @@
+package db_test
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/db/dbfakes"
+)
+
+var _ = Describe("Webhooks", func() {
+	var dbConn db.Conn
+	var listener *pq.Listener
+	var database *db.SQLDB
+
+	var pipeline db.SavedPipeline
+	var otherTeam db.SavedTeam
+
+	BeforeEach(func() {
+		postgresRunner.Truncate()
+
+		dbConn = db.Wrap(postgresRunner.Open())
+		listener = pq.NewListener(postgresRunner.DataSourceName(), time.Second, time.Minute, nil)
+
+		Eventually(listener.Ping, 5*time.Second).ShouldNot(HaveOccurred())
+		bus := db.NewNotificationsBus(listener, dbConn)
+
+		pgxConn := postgresRunner.OpenPgx()
+		fakeConnector := new(dbfakes.FakeConnector)
+		retryableConn := &db.RetryableConn{Connector: fakeConnector, Conn: pgxConn}
+
+		lockFactory := db.NewLockFactory(retryableConn)
+		database = db.NewSQL(dbConn, bus, lockFactory)
+
+		teamDBFactory := db.NewTeamDBFactory(dbConn, bus, lockFactory)
+		teamDB := teamDBFactory.GetTeamDB(atc.DefaultTeamName)
+
+		var err error
+		pipeline, _, err = teamDB.SaveConfig("some-pipeline", atc.Config{
+			Jobs: atc.JobConfigs{{Name: "some-job"}, {Name: "some-other-job"}},
+		}, db.ConfigVersion(1), db.PipelineUnpaused, "some-author")
+		Expect(err).NotTo(HaveOccurred())
+
+		otherTeam, err = database.CreateTeam(db.Team{Name: "some-other-team"})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		err := dbConn.Close()
+		Expect(err).NotTo(HaveOccurred())
+
+		err = listener.Close()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("saves and looks up webhooks", func() {
+		saved, err := database.SaveWebhook(db.Webhook{
+			ID:         "some-hook",
+			TeamID:     pipeline.TeamID,
+			PipelineID: pipeline.ID,
+			JobName:    "some-job",
+			Secret:     "some-secret",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(saved).To(BeTrue())
+
+		hook, found, err := database.GetWebhook("some-hook")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeTrue())
+		Expect(hook).To(Equal(db.Webhook{
+			ID:           "some-hook",
+			TeamID:       pipeline.TeamID,
+			TeamName:     atc.DefaultTeamName,
+			PipelineID:   pipeline.ID,
+			PipelineName: "some-pipeline",
+			JobName:      "some-job",
+			Secret:       "some-secret",
+		}))
+
+		By("updating it for the same team")
+		saved, err = database.SaveWebhook(db.Webhook{
+			ID:         "some-hook",
+			TeamID:     pipeline.TeamID,
+			PipelineID: pipeline.ID,
+			JobName:    "some-other-job",
+			Secret:     "some-other-secret",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(saved).To(BeTrue())
+
+		hook, _, err = database.GetWebhook("some-hook")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hook.JobName).To(Equal("some-other-job"))
+		Expect(hook.Secret).To(Equal("some-other-secret"))
+
+		By("refusing to hand it over to another team")
+		saved, err = database.SaveWebhook(db.Webhook{
+			ID:         "some-hook",
+			TeamID:     otherTeam.ID,
+			PipelineID: pipeline.ID,
+			JobName:    "some-job",
+			Secret:     "stolen",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(saved).To(BeFalse())
+
+		hook, _, err = database.GetWebhook("some-hook")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hook.Secret).To(Equal("some-other-secret"))
+	})
+
+	It("does not find webhooks that don't exist", func() {
+		_, found, err := database.GetWebhook("bogus-hook")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeFalse())
+	})
+})
@@ -256,6 +256,291 @@ type FakeBuild struct {
 		result1 db.SavedPipeline
 		result2 error
 	}
+	LabelsStub        func() map[string]string
+	labelsMutex       sync.RWMutex
+	labelsArgsForCall []struct{}
+	labelsReturns     struct {
+		result1 map[string]string
+	}
+	SaveLabelsStub        func(labels map[string]string) error
+	saveLabelsMutex       sync.RWMutex
+	saveLabelsArgsForCall []struct {
+		labels map[string]string
+	}
+	saveLabelsReturns struct {
+		result1 error
+	}
+	LogTruncatedStub        func() bool
+	logTruncatedMutex       sync.RWMutex
+	logTruncatedArgsForCall []struct{}
+	logTruncatedReturns     struct {
+		result1 bool
+	}
+	MarkLogTruncatedStub        func() error
+	markLogTruncatedMutex       sync.RWMutex
+	markLogTruncatedArgsForCall []struct{}
+	markLogTruncatedReturns     struct {
+		result1 error
+	}
+	SaveArtifactStub        func(artifact db.BuildArtifact) error
+	saveArtifactMutex       sync.RWMutex
+	saveArtifactArgsForCall []struct {
+		artifact db.BuildArtifact
+	}
+	saveArtifactReturns struct {
+		result1 error
+	}
+	GetArtifactStub        func(name string) (db.BuildArtifact, bool, error)
+	getArtifactMutex       sync.RWMutex
+	getArtifactArgsForCall []struct {
+		name string
+	}
+	getArtifactReturns struct {
+		result1 db.BuildArtifact
+		result2 bool
+		result3 error
+	}
+	GetArtifactsStub        func() ([]db.BuildArtifact, error)
+	getArtifactsMutex       sync.RWMutex
+	getArtifactsArgsForCall []struct{}
+	getArtifactsReturns     struct {
+		result1 []db.BuildArtifact
+		result2 error
+	}
+	PriorityStub        func() int
+	priorityMutex       sync.RWMutex
+	priorityArgsForCall []struct{}
+	priorityReturns     struct {
+		result1 int
+	}
+	SetPriorityStub        func(priority int) (bool, error)
+	setPriorityMutex       sync.RWMutex
+	setPriorityArgsForCall []struct {
+		priority int
+	}
+	setPriorityReturns struct {
+		result1 bool
+		result2 error
+	}
+	SearchLogsStub        func(query string, limit int) ([]db.LogMatch, error)
+	searchLogsMutex       sync.RWMutex
+	searchLogsArgsForCall []struct {
+		query string
+		limit int
+	}
+	searchLogsReturns struct {
+		result1 []db.LogMatch
+		result2 error
+	}
+	ResourceUsageStub        func() db.ResourceUsage
+	resourceUsageMutex       sync.RWMutex
+	resourceUsageArgsForCall []struct{}
+	resourceUsageReturns     struct {
+		result1 db.ResourceUsage
+	}
+	SaveResourceUsageStub        func(usage db.ResourceUsage) error
+	saveResourceUsageMutex       sync.RWMutex
+	saveResourceUsageArgsForCall []struct {
+		usage db.ResourceUsage
+	}
+	saveResourceUsageReturns struct {
+		result1 error
+	}
+	SaveDependenciesStub        func(dependsOn []int, plan atc.Plan) error
+	saveDependenciesMutex       sync.RWMutex
+	saveDependenciesArgsForCall []struct {
+		dependsOn []int
+		plan      atc.Plan
+	}
+	saveDependenciesReturns struct {
+		result1 error
+	}
+	GetDependencyStatusesStub        func() (map[int]db.Status, error)
+	getDependencyStatusesMutex       sync.RWMutex
+	getDependencyStatusesArgsForCall []struct{}
+	getDependencyStatusesReturns     struct {
+		result1 map[int]db.Status
+		result2 error
+	}
+	ClaimPendingPlanStub        func() (atc.Plan, bool, error)
+	claimPendingPlanMutex       sync.RWMutex
+	claimPendingPlanArgsForCall []struct{}
+	claimPendingPlanReturns     struct {
+		result1 atc.Plan
+		result2 bool
+		result3 error
+	}
+	RerunOfStub        func() int
+	rerunOfMutex       sync.RWMutex
+	rerunOfArgsForCall []struct{}
+	rerunOfReturns     struct {
+		result1 int
+	}
+	TimeoutStub        func() time.Duration
+	timeoutMutex       sync.RWMutex
+	timeoutArgsForCall []struct{}
+	timeoutReturns     struct {
+		result1 time.Duration
+	}
+	MarkAsTimedOutStub        func() (bool, error)
+	markAsTimedOutMutex       sync.RWMutex
+	markAsTimedOutArgsForCall []struct{}
+	markAsTimedOutReturns     struct {
+		result1 bool
+		result2 error
+	}
+	SetTimeoutStub        func(timeout time.Duration) error
+	setTimeoutMutex       sync.RWMutex
+	setTimeoutArgsForCall []struct {
+		timeout time.Duration
+	}
+	setTimeoutReturns struct {
+		result1 error
+	}
+	QueuePositionStub        func() (int, bool, error)
+	queuePositionMutex       sync.RWMutex
+	queuePositionArgsForCall []struct{}
+	queuePositionReturns     struct {
+		result1 int
+		result2 bool
+		result3 error
+	}
+	SaveCommentStub        func(author string, text string) (db.BuildComment, error)
+	saveCommentMutex       sync.RWMutex
+	saveCommentArgsForCall []struct {
+		author string
+		text   string
+	}
+	saveCommentReturns struct {
+		result1 db.BuildComment
+		result2 error
+	}
+	GetCommentsStub        func() ([]db.BuildComment, error)
+	getCommentsMutex       sync.RWMutex
+	getCommentsArgsForCall []struct{}
+	getCommentsReturns     struct {
+		result1 []db.BuildComment
+		result2 error
+	}
+	PipelineIDStub        func() int
+	pipelineIDMutex       sync.RWMutex
+	pipelineIDArgsForCall []struct{}
+	pipelineIDReturns     struct {
+		result1 int
+	}
+	HandOffStub        func() error
+	handOffMutex       sync.RWMutex
+	handOffArgsForCall []struct{}
+	handOffReturns     struct {
+		result1 error
+	}
+	RequestApprovalStub        func(planID atc.PlanID, name string) error
+	requestApprovalMutex       sync.RWMutex
+	requestApprovalArgsForCall []struct {
+		planID atc.PlanID
+		name   string
+	}
+	requestApprovalReturns struct {
+		result1 error
+	}
+	GetApprovalStub        func(name string) (db.BuildApproval, bool, error)
+	getApprovalMutex       sync.RWMutex
+	getApprovalArgsForCall []struct {
+		name string
+	}
+	getApprovalReturns struct {
+		result1 db.BuildApproval
+		result2 bool
+		result3 error
+	}
+	GetApprovalsStub        func() ([]db.BuildApproval, error)
+	getApprovalsMutex       sync.RWMutex
+	getApprovalsArgsForCall []struct{}
+	getApprovalsReturns     struct {
+		result1 []db.BuildApproval
+		result2 error
+	}
+	DecideApprovalStub        func(name string, approved bool, approver string) (bool, error)
+	decideApprovalMutex       sync.RWMutex
+	decideApprovalArgsForCall []struct {
+		name     string
+		approved bool
+		approver string
+	}
+	decideApprovalReturns struct {
+		result1 bool
+		result2 error
+	}
+	ApprovalNotifierStub        func(name string) (db.Notifier, error)
+	approvalNotifierMutex       sync.RWMutex
+	approvalNotifierArgsForCall []struct {
+		name string
+	}
+	approvalNotifierReturns struct {
+		result1 db.Notifier
+		result2 error
+	}
+	ParamsStub        func() map[string]string
+	paramsMutex       sync.RWMutex
+	paramsArgsForCall []struct{}
+	paramsReturns     struct {
+		result1 map[string]string
+	}
+	SaveSecretsStub        func(secrets []string) error
+	saveSecretsMutex       sync.RWMutex
+	saveSecretsArgsForCall []struct {
+		secrets []string
+	}
+	saveSecretsReturns struct {
+		result1 error
+	}
+	GetSecretsStub        func() ([]string, error)
+	getSecretsMutex       sync.RWMutex
+	getSecretsArgsForCall []struct{}
+	getSecretsReturns     struct {
+		result1 []string
+		result2 error
+	}
+	VerifyEventsStub        func() (db.EventChainVerification, error)
+	verifyEventsMutex       sync.RWMutex
+	verifyEventsArgsForCall []struct{}
+	verifyEventsReturns     struct {
+		result1 db.EventChainVerification
+		result2 error
+	}
+	FinishNotifierStub        func() (db.Notifier, error)
+	finishNotifierMutex       sync.RWMutex
+	finishNotifierArgsForCall []struct{}
+	finishNotifierReturns     struct {
+		result1 db.Notifier
+		result2 error
+	}
+	GetStepsStub        func() ([]db.BuildStep, error)
+	getStepsMutex       sync.RWMutex
+	getStepsArgsForCall []struct{}
+	getStepsReturns     struct {
+		result1 []db.BuildStep
+		result2 error
+	}
+	GetPreviousSuccessfulBuildStub        func() (db.Build, bool, error)
+	getPreviousSuccessfulBuildMutex       sync.RWMutex
+	getPreviousSuccessfulBuildArgsForCall []struct{}
+	getPreviousSuccessfulBuildReturns     struct {
+		result1 db.Build
+		result2 bool
+		result3 error
+	}
+	SaveDurationAlertStub        func(percentile int, threshold time.Duration, elapsed time.Duration) (bool, error)
+	saveDurationAlertMutex       sync.RWMutex
+	saveDurationAlertArgsForCall []struct {
+		percentile int
+		threshold  time.Duration
+		elapsed    time.Duration
+	}
+	saveDurationAlertReturns struct {
+		result1 bool
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -1211,6 +1496,1094 @@ func (fake *FakeBuild) GetPipelineReturns(result1 db.SavedPipeline, result2 erro
 	}{result1, result2}
 }
 
+func (fake *FakeBuild) Labels() map[string]string {
+	fake.labelsMutex.Lock()
+	fake.labelsArgsForCall = append(fake.labelsArgsForCall, struct{}{})
+	fake.recordInvocation("Labels", []interface{}{})
+	fake.labelsMutex.Unlock()
+	if fake.LabelsStub != nil {
+		return fake.LabelsStub()
+	} else {
+		return fake.labelsReturns.result1
+	}
+}
+
+func (fake *FakeBuild) LabelsCallCount() int {
+	fake.labelsMutex.RLock()
+	defer fake.labelsMutex.RUnlock()
+	return len(fake.labelsArgsForCall)
+}
+
+func (fake *FakeBuild) LabelsReturns(result1 map[string]string) {
+	fake.LabelsStub = nil
+	fake.labelsReturns = struct {
+		result1 map[string]string
+	}{result1}
+}
+
+func (fake *FakeBuild) SaveLabels(labels map[string]string) error {
+	fake.saveLabelsMutex.Lock()
+	fake.saveLabelsArgsForCall = append(fake.saveLabelsArgsForCall, struct {
+		labels map[string]string
+	}{labels})
+	fake.recordInvocation("SaveLabels", []interface{}{labels})
+	fake.saveLabelsMutex.Unlock()
+	if fake.SaveLabelsStub != nil {
+		return fake.SaveLabelsStub(labels)
+	} else {
+		return fake.saveLabelsReturns.result1
+	}
+}
+
+func (fake *FakeBuild) SaveLabelsCallCount() int {
+	fake.saveLabelsMutex.RLock()
+	defer fake.saveLabelsMutex.RUnlock()
+	return len(fake.saveLabelsArgsForCall)
+}
+
+func (fake *FakeBuild) SaveLabelsArgsForCall(i int) map[string]string {
+	fake.saveLabelsMutex.RLock()
+	defer fake.saveLabelsMutex.RUnlock()
+	return fake.saveLabelsArgsForCall[i].labels
+}
+
+func (fake *FakeBuild) SaveLabelsReturns(result1 error) {
+	fake.SaveLabelsStub = nil
+	fake.saveLabelsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBuild) LogTruncated() bool {
+	fake.logTruncatedMutex.Lock()
+	fake.logTruncatedArgsForCall = append(fake.logTruncatedArgsForCall, struct{}{})
+	fake.recordInvocation("LogTruncated", []interface{}{})
+	fake.logTruncatedMutex.Unlock()
+	if fake.LogTruncatedStub != nil {
+		return fake.LogTruncatedStub()
+	} else {
+		return fake.logTruncatedReturns.result1
+	}
+}
+
+func (fake *FakeBuild) LogTruncatedCallCount() int {
+	fake.logTruncatedMutex.RLock()
+	defer fake.logTruncatedMutex.RUnlock()
+	return len(fake.logTruncatedArgsForCall)
+}
+
+func (fake *FakeBuild) LogTruncatedReturns(result1 bool) {
+	fake.LogTruncatedStub = nil
+	fake.logTruncatedReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeBuild) MarkLogTruncated() error {
+	fake.markLogTruncatedMutex.Lock()
+	fake.markLogTruncatedArgsForCall = append(fake.markLogTruncatedArgsForCall, struct{}{})
+	fake.recordInvocation("MarkLogTruncated", []interface{}{})
+	fake.markLogTruncatedMutex.Unlock()
+	if fake.MarkLogTruncatedStub != nil {
+		return fake.MarkLogTruncatedStub()
+	} else {
+		return fake.markLogTruncatedReturns.result1
+	}
+}
+
+func (fake *FakeBuild) MarkLogTruncatedCallCount() int {
+	fake.markLogTruncatedMutex.RLock()
+	defer fake.markLogTruncatedMutex.RUnlock()
+	return len(fake.markLogTruncatedArgsForCall)
+}
+
+func (fake *FakeBuild) MarkLogTruncatedReturns(result1 error) {
+	fake.MarkLogTruncatedStub = nil
+	fake.markLogTruncatedReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBuild) SaveArtifact(artifact db.BuildArtifact) error {
+	fake.saveArtifactMutex.Lock()
+	fake.saveArtifactArgsForCall = append(fake.saveArtifactArgsForCall, struct {
+		artifact db.BuildArtifact
+	}{artifact})
+	fake.recordInvocation("SaveArtifact", []interface{}{artifact})
+	fake.saveArtifactMutex.Unlock()
+	if fake.SaveArtifactStub != nil {
+		return fake.SaveArtifactStub(artifact)
+	} else {
+		return fake.saveArtifactReturns.result1
+	}
+}
+
+func (fake *FakeBuild) SaveArtifactCallCount() int {
+	fake.saveArtifactMutex.RLock()
+	defer fake.saveArtifactMutex.RUnlock()
+	return len(fake.saveArtifactArgsForCall)
+}
+
+func (fake *FakeBuild) SaveArtifactArgsForCall(i int) db.BuildArtifact {
+	fake.saveArtifactMutex.RLock()
+	defer fake.saveArtifactMutex.RUnlock()
+	return fake.saveArtifactArgsForCall[i].artifact
+}
+
+func (fake *FakeBuild) SaveArtifactReturns(result1 error) {
+	fake.SaveArtifactStub = nil
+	fake.saveArtifactReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBuild) GetArtifact(name string) (db.BuildArtifact, bool, error) {
+	fake.getArtifactMutex.Lock()
+	fake.getArtifactArgsForCall = append(fake.getArtifactArgsForCall, struct {
+		name string
+	}{name})
+	fake.recordInvocation("GetArtifact", []interface{}{name})
+	fake.getArtifactMutex.Unlock()
+	if fake.GetArtifactStub != nil {
+		return fake.GetArtifactStub(name)
+	} else {
+		return fake.getArtifactReturns.result1, fake.getArtifactReturns.result2, fake.getArtifactReturns.result3
+	}
+}
+
+func (fake *FakeBuild) GetArtifactCallCount() int {
+	fake.getArtifactMutex.RLock()
+	defer fake.getArtifactMutex.RUnlock()
+	return len(fake.getArtifactArgsForCall)
+}
+
+func (fake *FakeBuild) GetArtifactArgsForCall(i int) string {
+	fake.getArtifactMutex.RLock()
+	defer fake.getArtifactMutex.RUnlock()
+	return fake.getArtifactArgsForCall[i].name
+}
+
+func (fake *FakeBuild) GetArtifactReturns(result1 db.BuildArtifact, result2 bool, result3 error) {
+	fake.GetArtifactStub = nil
+	fake.getArtifactReturns = struct {
+		result1 db.BuildArtifact
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeBuild) GetArtifacts() ([]db.BuildArtifact, error) {
+	fake.getArtifactsMutex.Lock()
+	fake.getArtifactsArgsForCall = append(fake.getArtifactsArgsForCall, struct{}{})
+	fake.recordInvocation("GetArtifacts", []interface{}{})
+	fake.getArtifactsMutex.Unlock()
+	if fake.GetArtifactsStub != nil {
+		return fake.GetArtifactsStub()
+	} else {
+		return fake.getArtifactsReturns.result1, fake.getArtifactsReturns.result2
+	}
+}
+
+func (fake *FakeBuild) GetArtifactsCallCount() int {
+	fake.getArtifactsMutex.RLock()
+	defer fake.getArtifactsMutex.RUnlock()
+	return len(fake.getArtifactsArgsForCall)
+}
+
+func (fake *FakeBuild) GetArtifactsReturns(result1 []db.BuildArtifact, result2 error) {
+	fake.GetArtifactsStub = nil
+	fake.getArtifactsReturns = struct {
+		result1 []db.BuildArtifact
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuild) Priority() int {
+	fake.priorityMutex.Lock()
+	fake.priorityArgsForCall = append(fake.priorityArgsForCall, struct{}{})
+	fake.recordInvocation("Priority", []interface{}{})
+	fake.priorityMutex.Unlock()
+	if fake.PriorityStub != nil {
+		return fake.PriorityStub()
+	} else {
+		return fake.priorityReturns.result1
+	}
+}
+
+func (fake *FakeBuild) PriorityCallCount() int {
+	fake.priorityMutex.RLock()
+	defer fake.priorityMutex.RUnlock()
+	return len(fake.priorityArgsForCall)
+}
+
+func (fake *FakeBuild) PriorityReturns(result1 int) {
+	fake.PriorityStub = nil
+	fake.priorityReturns = struct {
+		result1 int
+	}{result1}
+}
+
+func (fake *FakeBuild) SetPriority(priority int) (bool, error) {
+	fake.setPriorityMutex.Lock()
+	fake.setPriorityArgsForCall = append(fake.setPriorityArgsForCall, struct {
+		priority int
+	}{priority})
+	fake.recordInvocation("SetPriority", []interface{}{priority})
+	fake.setPriorityMutex.Unlock()
+	if fake.SetPriorityStub != nil {
+		return fake.SetPriorityStub(priority)
+	} else {
+		return fake.setPriorityReturns.result1, fake.setPriorityReturns.result2
+	}
+}
+
+func (fake *FakeBuild) SetPriorityCallCount() int {
+	fake.setPriorityMutex.RLock()
+	defer fake.setPriorityMutex.RUnlock()
+	return len(fake.setPriorityArgsForCall)
+}
+
+func (fake *FakeBuild) SetPriorityArgsForCall(i int) int {
+	fake.setPriorityMutex.RLock()
+	defer fake.setPriorityMutex.RUnlock()
+	return fake.setPriorityArgsForCall[i].priority
+}
+
+func (fake *FakeBuild) SetPriorityReturns(result1 bool, result2 error) {
+	fake.SetPriorityStub = nil
+	fake.setPriorityReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuild) SearchLogs(query string, limit int) ([]db.LogMatch, error) {
+	fake.searchLogsMutex.Lock()
+	fake.searchLogsArgsForCall = append(fake.searchLogsArgsForCall, struct {
+		query string
+		limit int
+	}{query, limit})
+	fake.recordInvocation("SearchLogs", []interface{}{query, limit})
+	fake.searchLogsMutex.Unlock()
+	if fake.SearchLogsStub != nil {
+		return fake.SearchLogsStub(query, limit)
+	} else {
+		return fake.searchLogsReturns.result1, fake.searchLogsReturns.result2
+	}
+}
+
+func (fake *FakeBuild) SearchLogsCallCount() int {
+	fake.searchLogsMutex.RLock()
+	defer fake.searchLogsMutex.RUnlock()
+	return len(fake.searchLogsArgsForCall)
+}
+
+func (fake *FakeBuild) SearchLogsArgsForCall(i int) (string, int) {
+	fake.searchLogsMutex.RLock()
+	defer fake.searchLogsMutex.RUnlock()
+	return fake.searchLogsArgsForCall[i].query, fake.searchLogsArgsForCall[i].limit
+}
+
+func (fake *FakeBuild) SearchLogsReturns(result1 []db.LogMatch, result2 error) {
+	fake.SearchLogsStub = nil
+	fake.searchLogsReturns = struct {
+		result1 []db.LogMatch
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuild) ResourceUsage() db.ResourceUsage {
+	fake.resourceUsageMutex.Lock()
+	fake.resourceUsageArgsForCall = append(fake.resourceUsageArgsForCall, struct{}{})
+	fake.recordInvocation("ResourceUsage", []interface{}{})
+	fake.resourceUsageMutex.Unlock()
+	if fake.ResourceUsageStub != nil {
+		return fake.ResourceUsageStub()
+	} else {
+		return fake.resourceUsageReturns.result1
+	}
+}
+
+func (fake *FakeBuild) ResourceUsageCallCount() int {
+	fake.resourceUsageMutex.RLock()
+	defer fake.resourceUsageMutex.RUnlock()
+	return len(fake.resourceUsageArgsForCall)
+}
+
+func (fake *FakeBuild) ResourceUsageReturns(result1 db.ResourceUsage) {
+	fake.ResourceUsageStub = nil
+	fake.resourceUsageReturns = struct {
+		result1 db.ResourceUsage
+	}{result1}
+}
+
+func (fake *FakeBuild) SaveResourceUsage(usage db.ResourceUsage) error {
+	fake.saveResourceUsageMutex.Lock()
+	fake.saveResourceUsageArgsForCall = append(fake.saveResourceUsageArgsForCall, struct {
+		usage db.ResourceUsage
+	}{usage})
+	fake.recordInvocation("SaveResourceUsage", []interface{}{usage})
+	fake.saveResourceUsageMutex.Unlock()
+	if fake.SaveResourceUsageStub != nil {
+		return fake.SaveResourceUsageStub(usage)
+	} else {
+		return fake.saveResourceUsageReturns.result1
+	}
+}
+
+func (fake *FakeBuild) SaveResourceUsageCallCount() int {
+	fake.saveResourceUsageMutex.RLock()
+	defer fake.saveResourceUsageMutex.RUnlock()
+	return len(fake.saveResourceUsageArgsForCall)
+}
+
+func (fake *FakeBuild) SaveResourceUsageArgsForCall(i int) db.ResourceUsage {
+	fake.saveResourceUsageMutex.RLock()
+	defer fake.saveResourceUsageMutex.RUnlock()
+	return fake.saveResourceUsageArgsForCall[i].usage
+}
+
+func (fake *FakeBuild) SaveResourceUsageReturns(result1 error) {
+	fake.SaveResourceUsageStub = nil
+	fake.saveResourceUsageReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBuild) SaveDependencies(dependsOn []int, plan atc.Plan) error {
+	var dependsOnCopy []int
+	if dependsOn != nil {
+		dependsOnCopy = make([]int, len(dependsOn))
+		copy(dependsOnCopy, dependsOn)
+	}
+	fake.saveDependenciesMutex.Lock()
+	fake.saveDependenciesArgsForCall = append(fake.saveDependenciesArgsForCall, struct {
+		dependsOn []int
+		plan      atc.Plan
+	}{dependsOnCopy, plan})
+	fake.recordInvocation("SaveDependencies", []interface{}{dependsOnCopy, plan})
+	fake.saveDependenciesMutex.Unlock()
+	if fake.SaveDependenciesStub != nil {
+		return fake.SaveDependenciesStub(dependsOn, plan)
+	} else {
+		return fake.saveDependenciesReturns.result1
+	}
+}
+
+func (fake *FakeBuild) SaveDependenciesCallCount() int {
+	fake.saveDependenciesMutex.RLock()
+	defer fake.saveDependenciesMutex.RUnlock()
+	return len(fake.saveDependenciesArgsForCall)
+}
+
+func (fake *FakeBuild) SaveDependenciesArgsForCall(i int) ([]int, atc.Plan) {
+	fake.saveDependenciesMutex.RLock()
+	defer fake.saveDependenciesMutex.RUnlock()
+	return fake.saveDependenciesArgsForCall[i].dependsOn, fake.saveDependenciesArgsForCall[i].plan
+}
+
+func (fake *FakeBuild) SaveDependenciesReturns(result1 error) {
+	fake.SaveDependenciesStub = nil
+	fake.saveDependenciesReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBuild) GetDependencyStatuses() (map[int]db.Status, error) {
+	fake.getDependencyStatusesMutex.Lock()
+	fake.getDependencyStatusesArgsForCall = append(fake.getDependencyStatusesArgsForCall, struct{}{})
+	fake.recordInvocation("GetDependencyStatuses", []interface{}{})
+	fake.getDependencyStatusesMutex.Unlock()
+	if fake.GetDependencyStatusesStub != nil {
+		return fake.GetDependencyStatusesStub()
+	} else {
+		return fake.getDependencyStatusesReturns.result1, fake.getDependencyStatusesReturns.result2
+	}
+}
+
+func (fake *FakeBuild) GetDependencyStatusesCallCount() int {
+	fake.getDependencyStatusesMutex.RLock()
+	defer fake.getDependencyStatusesMutex.RUnlock()
+	return len(fake.getDependencyStatusesArgsForCall)
+}
+
+func (fake *FakeBuild) GetDependencyStatusesReturns(result1 map[int]db.Status, result2 error) {
+	fake.GetDependencyStatusesStub = nil
+	fake.getDependencyStatusesReturns = struct {
+		result1 map[int]db.Status
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuild) ClaimPendingPlan() (atc.Plan, bool, error) {
+	fake.claimPendingPlanMutex.Lock()
+	fake.claimPendingPlanArgsForCall = append(fake.claimPendingPlanArgsForCall, struct{}{})
+	fake.recordInvocation("ClaimPendingPlan", []interface{}{})
+	fake.claimPendingPlanMutex.Unlock()
+	if fake.ClaimPendingPlanStub != nil {
+		return fake.ClaimPendingPlanStub()
+	} else {
+		return fake.claimPendingPlanReturns.result1, fake.claimPendingPlanReturns.result2, fake.claimPendingPlanReturns.result3
+	}
+}
+
+func (fake *FakeBuild) ClaimPendingPlanCallCount() int {
+	fake.claimPendingPlanMutex.RLock()
+	defer fake.claimPendingPlanMutex.RUnlock()
+	return len(fake.claimPendingPlanArgsForCall)
+}
+
+func (fake *FakeBuild) ClaimPendingPlanReturns(result1 atc.Plan, result2 bool, result3 error) {
+	fake.ClaimPendingPlanStub = nil
+	fake.claimPendingPlanReturns = struct {
+		result1 atc.Plan
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeBuild) RerunOf() int {
+	fake.rerunOfMutex.Lock()
+	fake.rerunOfArgsForCall = append(fake.rerunOfArgsForCall, struct{}{})
+	fake.recordInvocation("RerunOf", []interface{}{})
+	fake.rerunOfMutex.Unlock()
+	if fake.RerunOfStub != nil {
+		return fake.RerunOfStub()
+	} else {
+		return fake.rerunOfReturns.result1
+	}
+}
+
+func (fake *FakeBuild) RerunOfCallCount() int {
+	fake.rerunOfMutex.RLock()
+	defer fake.rerunOfMutex.RUnlock()
+	return len(fake.rerunOfArgsForCall)
+}
+
+func (fake *FakeBuild) RerunOfReturns(result1 int) {
+	fake.RerunOfStub = nil
+	fake.rerunOfReturns = struct {
+		result1 int
+	}{result1}
+}
+
+func (fake *FakeBuild) Timeout() time.Duration {
+	fake.timeoutMutex.Lock()
+	fake.timeoutArgsForCall = append(fake.timeoutArgsForCall, struct{}{})
+	fake.recordInvocation("Timeout", []interface{}{})
+	fake.timeoutMutex.Unlock()
+	if fake.TimeoutStub != nil {
+		return fake.TimeoutStub()
+	} else {
+		return fake.timeoutReturns.result1
+	}
+}
+
+func (fake *FakeBuild) TimeoutCallCount() int {
+	fake.timeoutMutex.RLock()
+	defer fake.timeoutMutex.RUnlock()
+	return len(fake.timeoutArgsForCall)
+}
+
+func (fake *FakeBuild) TimeoutReturns(result1 time.Duration) {
+	fake.TimeoutStub = nil
+	fake.timeoutReturns = struct {
+		result1 time.Duration
+	}{result1}
+}
+
+func (fake *FakeBuild) MarkAsTimedOut() (bool, error) {
+	fake.markAsTimedOutMutex.Lock()
+	fake.markAsTimedOutArgsForCall = append(fake.markAsTimedOutArgsForCall, struct{}{})
+	fake.recordInvocation("MarkAsTimedOut", []interface{}{})
+	fake.markAsTimedOutMutex.Unlock()
+	if fake.MarkAsTimedOutStub != nil {
+		return fake.MarkAsTimedOutStub()
+	} else {
+		return fake.markAsTimedOutReturns.result1, fake.markAsTimedOutReturns.result2
+	}
+}
+
+func (fake *FakeBuild) MarkAsTimedOutCallCount() int {
+	fake.markAsTimedOutMutex.RLock()
+	defer fake.markAsTimedOutMutex.RUnlock()
+	return len(fake.markAsTimedOutArgsForCall)
+}
+
+func (fake *FakeBuild) MarkAsTimedOutReturns(result1 bool, result2 error) {
+	fake.MarkAsTimedOutStub = nil
+	fake.markAsTimedOutReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuild) SetTimeout(timeout time.Duration) error {
+	fake.setTimeoutMutex.Lock()
+	fake.setTimeoutArgsForCall = append(fake.setTimeoutArgsForCall, struct {
+		timeout time.Duration
+	}{timeout})
+	fake.recordInvocation("SetTimeout", []interface{}{timeout})
+	fake.setTimeoutMutex.Unlock()
+	if fake.SetTimeoutStub != nil {
+		return fake.SetTimeoutStub(timeout)
+	} else {
+		return fake.setTimeoutReturns.result1
+	}
+}
+
+func (fake *FakeBuild) SetTimeoutCallCount() int {
+	fake.setTimeoutMutex.RLock()
+	defer fake.setTimeoutMutex.RUnlock()
+	return len(fake.setTimeoutArgsForCall)
+}
+
+func (fake *FakeBuild) SetTimeoutArgsForCall(i int) time.Duration {
+	fake.setTimeoutMutex.RLock()
+	defer fake.setTimeoutMutex.RUnlock()
+	return fake.setTimeoutArgsForCall[i].timeout
+}
+
+func (fake *FakeBuild) SetTimeoutReturns(result1 error) {
+	fake.SetTimeoutStub = nil
+	fake.setTimeoutReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBuild) QueuePosition() (int, bool, error) {
+	fake.queuePositionMutex.Lock()
+	fake.queuePositionArgsForCall = append(fake.queuePositionArgsForCall, struct{}{})
+	fake.recordInvocation("QueuePosition", []interface{}{})
+	fake.queuePositionMutex.Unlock()
+	if fake.QueuePositionStub != nil {
+		return fake.QueuePositionStub()
+	} else {
+		return fake.queuePositionReturns.result1, fake.queuePositionReturns.result2, fake.queuePositionReturns.result3
+	}
+}
+
+func (fake *FakeBuild) QueuePositionCallCount() int {
+	fake.queuePositionMutex.RLock()
+	defer fake.queuePositionMutex.RUnlock()
+	return len(fake.queuePositionArgsForCall)
+}
+
+func (fake *FakeBuild) QueuePositionReturns(result1 int, result2 bool, result3 error) {
+	fake.QueuePositionStub = nil
+	fake.queuePositionReturns = struct {
+		result1 int
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeBuild) SaveComment(author string, text string) (db.BuildComment, error) {
+	fake.saveCommentMutex.Lock()
+	fake.saveCommentArgsForCall = append(fake.saveCommentArgsForCall, struct {
+		author string
+		text   string
+	}{author, text})
+	fake.recordInvocation("SaveComment", []interface{}{author, text})
+	fake.saveCommentMutex.Unlock()
+	if fake.SaveCommentStub != nil {
+		return fake.SaveCommentStub(author, text)
+	} else {
+		return fake.saveCommentReturns.result1, fake.saveCommentReturns.result2
+	}
+}
+
+func (fake *FakeBuild) SaveCommentCallCount() int {
+	fake.saveCommentMutex.RLock()
+	defer fake.saveCommentMutex.RUnlock()
+	return len(fake.saveCommentArgsForCall)
+}
+
+func (fake *FakeBuild) SaveCommentArgsForCall(i int) (string, string) {
+	fake.saveCommentMutex.RLock()
+	defer fake.saveCommentMutex.RUnlock()
+	return fake.saveCommentArgsForCall[i].author, fake.saveCommentArgsForCall[i].text
+}
+
+func (fake *FakeBuild) SaveCommentReturns(result1 db.BuildComment, result2 error) {
+	fake.SaveCommentStub = nil
+	fake.saveCommentReturns = struct {
+		result1 db.BuildComment
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuild) GetComments() ([]db.BuildComment, error) {
+	fake.getCommentsMutex.Lock()
+	fake.getCommentsArgsForCall = append(fake.getCommentsArgsForCall, struct{}{})
+	fake.recordInvocation("GetComments", []interface{}{})
+	fake.getCommentsMutex.Unlock()
+	if fake.GetCommentsStub != nil {
+		return fake.GetCommentsStub()
+	} else {
+		return fake.getCommentsReturns.result1, fake.getCommentsReturns.result2
+	}
+}
+
+func (fake *FakeBuild) GetCommentsCallCount() int {
+	fake.getCommentsMutex.RLock()
+	defer fake.getCommentsMutex.RUnlock()
+	return len(fake.getCommentsArgsForCall)
+}
+
+func (fake *FakeBuild) GetCommentsReturns(result1 []db.BuildComment, result2 error) {
+	fake.GetCommentsStub = nil
+	fake.getCommentsReturns = struct {
+		result1 []db.BuildComment
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuild) PipelineID() int {
+	fake.pipelineIDMutex.Lock()
+	fake.pipelineIDArgsForCall = append(fake.pipelineIDArgsForCall, struct{}{})
+	fake.recordInvocation("PipelineID", []interface{}{})
+	fake.pipelineIDMutex.Unlock()
+	if fake.PipelineIDStub != nil {
+		return fake.PipelineIDStub()
+	} else {
+		return fake.pipelineIDReturns.result1
+	}
+}
+
+func (fake *FakeBuild) PipelineIDCallCount() int {
+	fake.pipelineIDMutex.RLock()
+	defer fake.pipelineIDMutex.RUnlock()
+	return len(fake.pipelineIDArgsForCall)
+}
+
+func (fake *FakeBuild) PipelineIDReturns(result1 int) {
+	fake.PipelineIDStub = nil
+	fake.pipelineIDReturns = struct {
+		result1 int
+	}{result1}
+}
+
+func (fake *FakeBuild) HandOff() error {
+	fake.handOffMutex.Lock()
+	fake.handOffArgsForCall = append(fake.handOffArgsForCall, struct{}{})
+	fake.recordInvocation("HandOff", []interface{}{})
+	fake.handOffMutex.Unlock()
+	if fake.HandOffStub != nil {
+		return fake.HandOffStub()
+	} else {
+		return fake.handOffReturns.result1
+	}
+}
+
+func (fake *FakeBuild) HandOffCallCount() int {
+	fake.handOffMutex.RLock()
+	defer fake.handOffMutex.RUnlock()
+	return len(fake.handOffArgsForCall)
+}
+
+func (fake *FakeBuild) HandOffReturns(result1 error) {
+	fake.HandOffStub = nil
+	fake.handOffReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBuild) RequestApproval(planID atc.PlanID, name string) error {
+	fake.requestApprovalMutex.Lock()
+	fake.requestApprovalArgsForCall = append(fake.requestApprovalArgsForCall, struct {
+		planID atc.PlanID
+		name   string
+	}{planID, name})
+	fake.recordInvocation("RequestApproval", []interface{}{planID, name})
+	fake.requestApprovalMutex.Unlock()
+	if fake.RequestApprovalStub != nil {
+		return fake.RequestApprovalStub(planID, name)
+	} else {
+		return fake.requestApprovalReturns.result1
+	}
+}
+
+func (fake *FakeBuild) RequestApprovalCallCount() int {
+	fake.requestApprovalMutex.RLock()
+	defer fake.requestApprovalMutex.RUnlock()
+	return len(fake.requestApprovalArgsForCall)
+}
+
+func (fake *FakeBuild) RequestApprovalArgsForCall(i int) (atc.PlanID, string) {
+	fake.requestApprovalMutex.RLock()
+	defer fake.requestApprovalMutex.RUnlock()
+	return fake.requestApprovalArgsForCall[i].planID, fake.requestApprovalArgsForCall[i].name
+}
+
+func (fake *FakeBuild) RequestApprovalReturns(result1 error) {
+	fake.RequestApprovalStub = nil
+	fake.requestApprovalReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBuild) GetApproval(name string) (db.BuildApproval, bool, error) {
+	fake.getApprovalMutex.Lock()
+	fake.getApprovalArgsForCall = append(fake.getApprovalArgsForCall, struct {
+		name string
+	}{name})
+	fake.recordInvocation("GetApproval", []interface{}{name})
+	fake.getApprovalMutex.Unlock()
+	if fake.GetApprovalStub != nil {
+		return fake.GetApprovalStub(name)
+	} else {
+		return fake.getApprovalReturns.result1, fake.getApprovalReturns.result2, fake.getApprovalReturns.result3
+	}
+}
+
+func (fake *FakeBuild) GetApprovalCallCount() int {
+	fake.getApprovalMutex.RLock()
+	defer fake.getApprovalMutex.RUnlock()
+	return len(fake.getApprovalArgsForCall)
+}
+
+func (fake *FakeBuild) GetApprovalArgsForCall(i int) string {
+	fake.getApprovalMutex.RLock()
+	defer fake.getApprovalMutex.RUnlock()
+	return fake.getApprovalArgsForCall[i].name
+}
+
+func (fake *FakeBuild) GetApprovalReturns(result1 db.BuildApproval, result2 bool, result3 error) {
+	fake.GetApprovalStub = nil
+	fake.getApprovalReturns = struct {
+		result1 db.BuildApproval
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeBuild) GetApprovals() ([]db.BuildApproval, error) {
+	fake.getApprovalsMutex.Lock()
+	fake.getApprovalsArgsForCall = append(fake.getApprovalsArgsForCall, struct{}{})
+	fake.recordInvocation("GetApprovals", []interface{}{})
+	fake.getApprovalsMutex.Unlock()
+	if fake.GetApprovalsStub != nil {
+		return fake.GetApprovalsStub()
+	} else {
+		return fake.getApprovalsReturns.result1, fake.getApprovalsReturns.result2
+	}
+}
+
+func (fake *FakeBuild) GetApprovalsCallCount() int {
+	fake.getApprovalsMutex.RLock()
+	defer fake.getApprovalsMutex.RUnlock()
+	return len(fake.getApprovalsArgsForCall)
+}
+
+func (fake *FakeBuild) GetApprovalsReturns(result1 []db.BuildApproval, result2 error) {
+	fake.GetApprovalsStub = nil
+	fake.getApprovalsReturns = struct {
+		result1 []db.BuildApproval
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuild) DecideApproval(name string, approved bool, approver string) (bool, error) {
+	fake.decideApprovalMutex.Lock()
+	fake.decideApprovalArgsForCall = append(fake.decideApprovalArgsForCall, struct {
+		name     string
+		approved bool
+		approver string
+	}{name, approved, approver})
+	fake.recordInvocation("DecideApproval", []interface{}{name, approved, approver})
+	fake.decideApprovalMutex.Unlock()
+	if fake.DecideApprovalStub != nil {
+		return fake.DecideApprovalStub(name, approved, approver)
+	} else {
+		return fake.decideApprovalReturns.result1, fake.decideApprovalReturns.result2
+	}
+}
+
+func (fake *FakeBuild) DecideApprovalCallCount() int {
+	fake.decideApprovalMutex.RLock()
+	defer fake.decideApprovalMutex.RUnlock()
+	return len(fake.decideApprovalArgsForCall)
+}
+
+func (fake *FakeBuild) DecideApprovalArgsForCall(i int) (string, bool, string) {
+	fake.decideApprovalMutex.RLock()
+	defer fake.decideApprovalMutex.RUnlock()
+	return fake.decideApprovalArgsForCall[i].name, fake.decideApprovalArgsForCall[i].approved, fake.decideApprovalArgsForCall[i].approver
+}
+
+func (fake *FakeBuild) DecideApprovalReturns(result1 bool, result2 error) {
+	fake.DecideApprovalStub = nil
+	fake.decideApprovalReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuild) ApprovalNotifier(name string) (db.Notifier, error) {
+	fake.approvalNotifierMutex.Lock()
+	fake.approvalNotifierArgsForCall = append(fake.approvalNotifierArgsForCall, struct {
+		name string
+	}{name})
+	fake.recordInvocation("ApprovalNotifier", []interface{}{name})
+	fake.approvalNotifierMutex.Unlock()
+	if fake.ApprovalNotifierStub != nil {
+		return fake.ApprovalNotifierStub(name)
+	} else {
+		return fake.approvalNotifierReturns.result1, fake.approvalNotifierReturns.result2
+	}
+}
+
+func (fake *FakeBuild) ApprovalNotifierCallCount() int {
+	fake.approvalNotifierMutex.RLock()
+	defer fake.approvalNotifierMutex.RUnlock()
+	return len(fake.approvalNotifierArgsForCall)
+}
+
+func (fake *FakeBuild) ApprovalNotifierArgsForCall(i int) string {
+	fake.approvalNotifierMutex.RLock()
+	defer fake.approvalNotifierMutex.RUnlock()
+	return fake.approvalNotifierArgsForCall[i].name
+}
+
+func (fake *FakeBuild) ApprovalNotifierReturns(result1 db.Notifier, result2 error) {
+	fake.ApprovalNotifierStub = nil
+	fake.approvalNotifierReturns = struct {
+		result1 db.Notifier
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuild) Params() map[string]string {
+	fake.paramsMutex.Lock()
+	fake.paramsArgsForCall = append(fake.paramsArgsForCall, struct{}{})
+	fake.recordInvocation("Params", []interface{}{})
+	fake.paramsMutex.Unlock()
+	if fake.ParamsStub != nil {
+		return fake.ParamsStub()
+	} else {
+		return fake.paramsReturns.result1
+	}
+}
+
+func (fake *FakeBuild) ParamsCallCount() int {
+	fake.paramsMutex.RLock()
+	defer fake.paramsMutex.RUnlock()
+	return len(fake.paramsArgsForCall)
+}
+
+func (fake *FakeBuild) ParamsReturns(result1 map[string]string) {
+	fake.ParamsStub = nil
+	fake.paramsReturns = struct {
+		result1 map[string]string
+	}{result1}
+}
+
+func (fake *FakeBuild) SaveSecrets(secrets []string) error {
+	var secretsCopy []string
+	if secrets != nil {
+		secretsCopy = make([]string, len(secrets))
+		copy(secretsCopy, secrets)
+	}
+	fake.saveSecretsMutex.Lock()
+	fake.saveSecretsArgsForCall = append(fake.saveSecretsArgsForCall, struct {
+		secrets []string
+	}{secretsCopy})
+	fake.recordInvocation("SaveSecrets", []interface{}{secretsCopy})
+	fake.saveSecretsMutex.Unlock()
+	if fake.SaveSecretsStub != nil {
+		return fake.SaveSecretsStub(secrets)
+	} else {
+		return fake.saveSecretsReturns.result1
+	}
+}
+
+func (fake *FakeBuild) SaveSecretsCallCount() int {
+	fake.saveSecretsMutex.RLock()
+	defer fake.saveSecretsMutex.RUnlock()
+	return len(fake.saveSecretsArgsForCall)
+}
+
+func (fake *FakeBuild) SaveSecretsArgsForCall(i int) []string {
+	fake.saveSecretsMutex.RLock()
+	defer fake.saveSecretsMutex.RUnlock()
+	return fake.saveSecretsArgsForCall[i].secrets
+}
+
+func (fake *FakeBuild) SaveSecretsReturns(result1 error) {
+	fake.SaveSecretsStub = nil
+	fake.saveSecretsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBuild) GetSecrets() ([]string, error) {
+	fake.getSecretsMutex.Lock()
+	fake.getSecretsArgsForCall = append(fake.getSecretsArgsForCall, struct{}{})
+	fake.recordInvocation("GetSecrets", []interface{}{})
+	fake.getSecretsMutex.Unlock()
+	if fake.GetSecretsStub != nil {
+		return fake.GetSecretsStub()
+	} else {
+		return fake.getSecretsReturns.result1, fake.getSecretsReturns.result2
+	}
+}
+
+func (fake *FakeBuild) GetSecretsCallCount() int {
+	fake.getSecretsMutex.RLock()
+	defer fake.getSecretsMutex.RUnlock()
+	return len(fake.getSecretsArgsForCall)
+}
+
+func (fake *FakeBuild) GetSecretsReturns(result1 []string, result2 error) {
+	fake.GetSecretsStub = nil
+	fake.getSecretsReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuild) VerifyEvents() (db.EventChainVerification, error) {
+	fake.verifyEventsMutex.Lock()
+	fake.verifyEventsArgsForCall = append(fake.verifyEventsArgsForCall, struct{}{})
+	fake.recordInvocation("VerifyEvents", []interface{}{})
+	fake.verifyEventsMutex.Unlock()
+	if fake.VerifyEventsStub != nil {
+		return fake.VerifyEventsStub()
+	} else {
+		return fake.verifyEventsReturns.result1, fake.verifyEventsReturns.result2
+	}
+}
+
+func (fake *FakeBuild) VerifyEventsCallCount() int {
+	fake.verifyEventsMutex.RLock()
+	defer fake.verifyEventsMutex.RUnlock()
+	return len(fake.verifyEventsArgsForCall)
+}
+
+func (fake *FakeBuild) VerifyEventsReturns(result1 db.EventChainVerification, result2 error) {
+	fake.VerifyEventsStub = nil
+	fake.verifyEventsReturns = struct {
+		result1 db.EventChainVerification
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuild) FinishNotifier() (db.Notifier, error) {
+	fake.finishNotifierMutex.Lock()
+	fake.finishNotifierArgsForCall = append(fake.finishNotifierArgsForCall, struct{}{})
+	fake.recordInvocation("FinishNotifier", []interface{}{})
+	fake.finishNotifierMutex.Unlock()
+	if fake.FinishNotifierStub != nil {
+		return fake.FinishNotifierStub()
+	} else {
+		return fake.finishNotifierReturns.result1, fake.finishNotifierReturns.result2
+	}
+}
+
+func (fake *FakeBuild) FinishNotifierCallCount() int {
+	fake.finishNotifierMutex.RLock()
+	defer fake.finishNotifierMutex.RUnlock()
+	return len(fake.finishNotifierArgsForCall)
+}
+
+func (fake *FakeBuild) FinishNotifierReturns(result1 db.Notifier, result2 error) {
+	fake.FinishNotifierStub = nil
+	fake.finishNotifierReturns = struct {
+		result1 db.Notifier
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuild) GetSteps() ([]db.BuildStep, error) {
+	fake.getStepsMutex.Lock()
+	fake.getStepsArgsForCall = append(fake.getStepsArgsForCall, struct{}{})
+	fake.recordInvocation("GetSteps", []interface{}{})
+	fake.getStepsMutex.Unlock()
+	if fake.GetStepsStub != nil {
+		return fake.GetStepsStub()
+	} else {
+		return fake.getStepsReturns.result1, fake.getStepsReturns.result2
+	}
+}
+
+func (fake *FakeBuild) GetStepsCallCount() int {
+	fake.getStepsMutex.RLock()
+	defer fake.getStepsMutex.RUnlock()
+	return len(fake.getStepsArgsForCall)
+}
+
+func (fake *FakeBuild) GetStepsReturns(result1 []db.BuildStep, result2 error) {
+	fake.GetStepsStub = nil
+	fake.getStepsReturns = struct {
+		result1 []db.BuildStep
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuild) GetPreviousSuccessfulBuild() (db.Build, bool, error) {
+	fake.getPreviousSuccessfulBuildMutex.Lock()
+	fake.getPreviousSuccessfulBuildArgsForCall = append(fake.getPreviousSuccessfulBuildArgsForCall, struct{}{})
+	fake.recordInvocation("GetPreviousSuccessfulBuild", []interface{}{})
+	fake.getPreviousSuccessfulBuildMutex.Unlock()
+	if fake.GetPreviousSuccessfulBuildStub != nil {
+		return fake.GetPreviousSuccessfulBuildStub()
+	} else {
+		return fake.getPreviousSuccessfulBuildReturns.result1, fake.getPreviousSuccessfulBuildReturns.result2, fake.getPreviousSuccessfulBuildReturns.result3
+	}
+}
+
+func (fake *FakeBuild) GetPreviousSuccessfulBuildCallCount() int {
+	fake.getPreviousSuccessfulBuildMutex.RLock()
+	defer fake.getPreviousSuccessfulBuildMutex.RUnlock()
+	return len(fake.getPreviousSuccessfulBuildArgsForCall)
+}
+
+func (fake *FakeBuild) GetPreviousSuccessfulBuildReturns(result1 db.Build, result2 bool, result3 error) {
+	fake.GetPreviousSuccessfulBuildStub = nil
+	fake.getPreviousSuccessfulBuildReturns = struct {
+		result1 db.Build
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeBuild) SaveDurationAlert(percentile int, threshold time.Duration, elapsed time.Duration) (bool, error) {
+	fake.saveDurationAlertMutex.Lock()
+	fake.saveDurationAlertArgsForCall = append(fake.saveDurationAlertArgsForCall, struct {
+		percentile int
+		threshold  time.Duration
+		elapsed    time.Duration
+	}{percentile, threshold, elapsed})
+	fake.recordInvocation("SaveDurationAlert", []interface{}{percentile, threshold, elapsed})
+	fake.saveDurationAlertMutex.Unlock()
+	if fake.SaveDurationAlertStub != nil {
+		return fake.SaveDurationAlertStub(percentile, threshold, elapsed)
+	} else {
+		return fake.saveDurationAlertReturns.result1, fake.saveDurationAlertReturns.result2
+	}
+}
+
+func (fake *FakeBuild) SaveDurationAlertCallCount() int {
+	fake.saveDurationAlertMutex.RLock()
+	defer fake.saveDurationAlertMutex.RUnlock()
+	return len(fake.saveDurationAlertArgsForCall)
+}
+
+func (fake *FakeBuild) SaveDurationAlertArgsForCall(i int) (int, time.Duration, time.Duration) {
+	fake.saveDurationAlertMutex.RLock()
+	defer fake.saveDurationAlertMutex.RUnlock()
+	return fake.saveDurationAlertArgsForCall[i].percentile, fake.saveDurationAlertArgsForCall[i].threshold, fake.saveDurationAlertArgsForCall[i].elapsed
+}
+
+func (fake *FakeBuild) SaveDurationAlertReturns(result1 bool, result2 error) {
+	fake.SaveDurationAlertStub = nil
+	fake.saveDurationAlertReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeBuild) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -1282,6 +2655,80 @@ func (fake *FakeBuild) Invocations() map[string][][]interface{} {
 	defer fake.getConfigMutex.RUnlock()
 	fake.getPipelineMutex.RLock()
 	defer fake.getPipelineMutex.RUnlock()
+	fake.labelsMutex.RLock()
+	defer fake.labelsMutex.RUnlock()
+	fake.saveLabelsMutex.RLock()
+	defer fake.saveLabelsMutex.RUnlock()
+	fake.logTruncatedMutex.RLock()
+	defer fake.logTruncatedMutex.RUnlock()
+	fake.markLogTruncatedMutex.RLock()
+	defer fake.markLogTruncatedMutex.RUnlock()
+	fake.saveArtifactMutex.RLock()
+	defer fake.saveArtifactMutex.RUnlock()
+	fake.getArtifactMutex.RLock()
+	defer fake.getArtifactMutex.RUnlock()
+	fake.getArtifactsMutex.RLock()
+	defer fake.getArtifactsMutex.RUnlock()
+	fake.priorityMutex.RLock()
+	defer fake.priorityMutex.RUnlock()
+	fake.setPriorityMutex.RLock()
+	defer fake.setPriorityMutex.RUnlock()
+	fake.searchLogsMutex.RLock()
+	defer fake.searchLogsMutex.RUnlock()
+	fake.resourceUsageMutex.RLock()
+	defer fake.resourceUsageMutex.RUnlock()
+	fake.saveResourceUsageMutex.RLock()
+	defer fake.saveResourceUsageMutex.RUnlock()
+	fake.saveDependenciesMutex.RLock()
+	defer fake.saveDependenciesMutex.RUnlock()
+	fake.getDependencyStatusesMutex.RLock()
+	defer fake.getDependencyStatusesMutex.RUnlock()
+	fake.claimPendingPlanMutex.RLock()
+	defer fake.claimPendingPlanMutex.RUnlock()
+	fake.rerunOfMutex.RLock()
+	defer fake.rerunOfMutex.RUnlock()
+	fake.timeoutMutex.RLock()
+	defer fake.timeoutMutex.RUnlock()
+	fake.markAsTimedOutMutex.RLock()
+	defer fake.markAsTimedOutMutex.RUnlock()
+	fake.setTimeoutMutex.RLock()
+	defer fake.setTimeoutMutex.RUnlock()
+	fake.queuePositionMutex.RLock()
+	defer fake.queuePositionMutex.RUnlock()
+	fake.saveCommentMutex.RLock()
+	defer fake.saveCommentMutex.RUnlock()
+	fake.getCommentsMutex.RLock()
+	defer fake.getCommentsMutex.RUnlock()
+	fake.pipelineIDMutex.RLock()
+	defer fake.pipelineIDMutex.RUnlock()
+	fake.handOffMutex.RLock()
+	defer fake.handOffMutex.RUnlock()
+	fake.requestApprovalMutex.RLock()
+	defer fake.requestApprovalMutex.RUnlock()
+	fake.getApprovalMutex.RLock()
+	defer fake.getApprovalMutex.RUnlock()
+	fake.getApprovalsMutex.RLock()
+	defer fake.getApprovalsMutex.RUnlock()
+	fake.decideApprovalMutex.RLock()
+	defer fake.decideApprovalMutex.RUnlock()
+	fake.approvalNotifierMutex.RLock()
+	defer fake.approvalNotifierMutex.RUnlock()
+	fake.paramsMutex.RLock()
+	defer fake.paramsMutex.RUnlock()
+	fake.saveSecretsMutex.RLock()
+	defer fake.saveSecretsMutex.RUnlock()
+	fake.getSecretsMutex.RLock()
+	defer fake.getSecretsMutex.RUnlock()
+	fake.verifyEventsMutex.RLock()
+	defer fake.verifyEventsMutex.RUnlock()
+	fake.finishNotifierMutex.RLock()
+	defer fake.finishNotifierMutex.RUnlock()
+	fake.getStepsMutex.RLock()
+	defer fake.getStepsMutex.RUnlock()
+	fake.getPreviousSuccessfulBuildMutex.RLock()
+	defer fake.getPreviousSuccessfulBuildMutex.RUnlock()
+	fake.saveDurationAlertMutex.RLock()
+	defer fake.saveDurationAlertMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -5,8 +5,10 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"sync"
+	"time"
 
 	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/db/encryption"
 )
 
 type FakeConn struct {
@@ -83,6 +85,71 @@ type FakeConn struct {
 	setMaxOpenConnsArgsForCall []struct {
 		n int
 	}
+	SetConnMaxLifetimeStub        func(d time.Duration)
+	setConnMaxLifetimeMutex       sync.RWMutex
+	setConnMaxLifetimeArgsForCall []struct {
+		d time.Duration
+	}
+	StatsStub        func() sql.DBStats
+	statsMutex       sync.RWMutex
+	statsArgsForCall []struct{}
+	statsReturns     struct {
+		result1 sql.DBStats
+	}
+	EncryptionStrategyStub        func() encryption.Strategy
+	encryptionStrategyMutex       sync.RWMutex
+	encryptionStrategyArgsForCall []struct{}
+	encryptionStrategyReturns     struct {
+		result1 encryption.Strategy
+	}
+	BeginTxStub        func(ctx context.Context, opts *sql.TxOptions) (db.Tx, error)
+	beginTxMutex       sync.RWMutex
+	beginTxArgsForCall []struct {
+		ctx  context.Context
+		opts *sql.TxOptions
+	}
+	beginTxReturns struct {
+		result1 db.Tx
+		result2 error
+	}
+	ExecContextStub        func(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	execContextMutex       sync.RWMutex
+	execContextArgsForCall []struct {
+		ctx   context.Context
+		query string
+		args  []interface{}
+	}
+	execContextReturns struct {
+		result1 sql.Result
+		result2 error
+	}
+	QueryContextStub        func(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	queryContextMutex       sync.RWMutex
+	queryContextArgsForCall []struct {
+		ctx   context.Context
+		query string
+		args  []interface{}
+	}
+	queryContextReturns struct {
+		result1 *sql.Rows
+		result2 error
+	}
+	QueryRowContextStub        func(ctx context.Context, query string, args ...interface{}) *sql.Row
+	queryRowContextMutex       sync.RWMutex
+	queryRowContextArgsForCall []struct {
+		ctx   context.Context
+		query string
+		args  []interface{}
+	}
+	queryRowContextReturns struct {
+		result1 *sql.Row
+	}
+	EventSigningKeyStub        func() []byte
+	eventSigningKeyMutex       sync.RWMutex
+	eventSigningKeyArgsForCall []struct{}
+	eventSigningKeyReturns     struct {
+		result1 []byte
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -374,6 +441,247 @@ func (fake *FakeConn) SetMaxOpenConnsArgsForCall(i int) int {
 	return fake.setMaxOpenConnsArgsForCall[i].n
 }
 
+func (fake *FakeConn) SetConnMaxLifetime(d time.Duration) {
+	fake.setConnMaxLifetimeMutex.Lock()
+	fake.setConnMaxLifetimeArgsForCall = append(fake.setConnMaxLifetimeArgsForCall, struct {
+		d time.Duration
+	}{d})
+	fake.recordInvocation("SetConnMaxLifetime", []interface{}{d})
+	fake.setConnMaxLifetimeMutex.Unlock()
+	if fake.SetConnMaxLifetimeStub != nil {
+		fake.SetConnMaxLifetimeStub(d)
+	}
+}
+
+func (fake *FakeConn) SetConnMaxLifetimeCallCount() int {
+	fake.setConnMaxLifetimeMutex.RLock()
+	defer fake.setConnMaxLifetimeMutex.RUnlock()
+	return len(fake.setConnMaxLifetimeArgsForCall)
+}
+
+func (fake *FakeConn) SetConnMaxLifetimeArgsForCall(i int) time.Duration {
+	fake.setConnMaxLifetimeMutex.RLock()
+	defer fake.setConnMaxLifetimeMutex.RUnlock()
+	return fake.setConnMaxLifetimeArgsForCall[i].d
+}
+
+func (fake *FakeConn) Stats() sql.DBStats {
+	fake.statsMutex.Lock()
+	fake.statsArgsForCall = append(fake.statsArgsForCall, struct{}{})
+	fake.recordInvocation("Stats", []interface{}{})
+	fake.statsMutex.Unlock()
+	if fake.StatsStub != nil {
+		return fake.StatsStub()
+	} else {
+		return fake.statsReturns.result1
+	}
+}
+
+func (fake *FakeConn) StatsCallCount() int {
+	fake.statsMutex.RLock()
+	defer fake.statsMutex.RUnlock()
+	return len(fake.statsArgsForCall)
+}
+
+func (fake *FakeConn) StatsReturns(result1 sql.DBStats) {
+	fake.StatsStub = nil
+	fake.statsReturns = struct {
+		result1 sql.DBStats
+	}{result1}
+}
+
+func (fake *FakeConn) EncryptionStrategy() encryption.Strategy {
+	fake.encryptionStrategyMutex.Lock()
+	fake.encryptionStrategyArgsForCall = append(fake.encryptionStrategyArgsForCall, struct{}{})
+	fake.recordInvocation("EncryptionStrategy", []interface{}{})
+	fake.encryptionStrategyMutex.Unlock()
+	if fake.EncryptionStrategyStub != nil {
+		return fake.EncryptionStrategyStub()
+	} else {
+		return fake.encryptionStrategyReturns.result1
+	}
+}
+
+func (fake *FakeConn) EncryptionStrategyCallCount() int {
+	fake.encryptionStrategyMutex.RLock()
+	defer fake.encryptionStrategyMutex.RUnlock()
+	return len(fake.encryptionStrategyArgsForCall)
+}
+
+func (fake *FakeConn) EncryptionStrategyReturns(result1 encryption.Strategy) {
+	fake.EncryptionStrategyStub = nil
+	fake.encryptionStrategyReturns = struct {
+		result1 encryption.Strategy
+	}{result1}
+}
+
+func (fake *FakeConn) BeginTx(ctx context.Context, opts *sql.TxOptions) (db.Tx, error) {
+	fake.beginTxMutex.Lock()
+	fake.beginTxArgsForCall = append(fake.beginTxArgsForCall, struct {
+		ctx  context.Context
+		opts *sql.TxOptions
+	}{ctx, opts})
+	fake.recordInvocation("BeginTx", []interface{}{ctx, opts})
+	fake.beginTxMutex.Unlock()
+	if fake.BeginTxStub != nil {
+		return fake.BeginTxStub(ctx, opts)
+	} else {
+		return fake.beginTxReturns.result1, fake.beginTxReturns.result2
+	}
+}
+
+func (fake *FakeConn) BeginTxCallCount() int {
+	fake.beginTxMutex.RLock()
+	defer fake.beginTxMutex.RUnlock()
+	return len(fake.beginTxArgsForCall)
+}
+
+func (fake *FakeConn) BeginTxArgsForCall(i int) (context.Context, *sql.TxOptions) {
+	fake.beginTxMutex.RLock()
+	defer fake.beginTxMutex.RUnlock()
+	return fake.beginTxArgsForCall[i].ctx, fake.beginTxArgsForCall[i].opts
+}
+
+func (fake *FakeConn) BeginTxReturns(result1 db.Tx, result2 error) {
+	fake.BeginTxStub = nil
+	fake.beginTxReturns = struct {
+		result1 db.Tx
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConn) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	fake.execContextMutex.Lock()
+	fake.execContextArgsForCall = append(fake.execContextArgsForCall, struct {
+		ctx   context.Context
+		query string
+		args  []interface{}
+	}{ctx, query, args})
+	fake.recordInvocation("ExecContext", []interface{}{ctx, query, args})
+	fake.execContextMutex.Unlock()
+	if fake.ExecContextStub != nil {
+		return fake.ExecContextStub(ctx, query, args...)
+	} else {
+		return fake.execContextReturns.result1, fake.execContextReturns.result2
+	}
+}
+
+func (fake *FakeConn) ExecContextCallCount() int {
+	fake.execContextMutex.RLock()
+	defer fake.execContextMutex.RUnlock()
+	return len(fake.execContextArgsForCall)
+}
+
+func (fake *FakeConn) ExecContextArgsForCall(i int) (context.Context, string, []interface{}) {
+	fake.execContextMutex.RLock()
+	defer fake.execContextMutex.RUnlock()
+	return fake.execContextArgsForCall[i].ctx, fake.execContextArgsForCall[i].query, fake.execContextArgsForCall[i].args
+}
+
+func (fake *FakeConn) ExecContextReturns(result1 sql.Result, result2 error) {
+	fake.ExecContextStub = nil
+	fake.execContextReturns = struct {
+		result1 sql.Result
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConn) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	fake.queryContextMutex.Lock()
+	fake.queryContextArgsForCall = append(fake.queryContextArgsForCall, struct {
+		ctx   context.Context
+		query string
+		args  []interface{}
+	}{ctx, query, args})
+	fake.recordInvocation("QueryContext", []interface{}{ctx, query, args})
+	fake.queryContextMutex.Unlock()
+	if fake.QueryContextStub != nil {
+		return fake.QueryContextStub(ctx, query, args...)
+	} else {
+		return fake.queryContextReturns.result1, fake.queryContextReturns.result2
+	}
+}
+
+func (fake *FakeConn) QueryContextCallCount() int {
+	fake.queryContextMutex.RLock()
+	defer fake.queryContextMutex.RUnlock()
+	return len(fake.queryContextArgsForCall)
+}
+
+func (fake *FakeConn) QueryContextArgsForCall(i int) (context.Context, string, []interface{}) {
+	fake.queryContextMutex.RLock()
+	defer fake.queryContextMutex.RUnlock()
+	return fake.queryContextArgsForCall[i].ctx, fake.queryContextArgsForCall[i].query, fake.queryContextArgsForCall[i].args
+}
+
+func (fake *FakeConn) QueryContextReturns(result1 *sql.Rows, result2 error) {
+	fake.QueryContextStub = nil
+	fake.queryContextReturns = struct {
+		result1 *sql.Rows
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeConn) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	fake.queryRowContextMutex.Lock()
+	fake.queryRowContextArgsForCall = append(fake.queryRowContextArgsForCall, struct {
+		ctx   context.Context
+		query string
+		args  []interface{}
+	}{ctx, query, args})
+	fake.recordInvocation("QueryRowContext", []interface{}{ctx, query, args})
+	fake.queryRowContextMutex.Unlock()
+	if fake.QueryRowContextStub != nil {
+		return fake.QueryRowContextStub(ctx, query, args...)
+	} else {
+		return fake.queryRowContextReturns.result1
+	}
+}
+
+func (fake *FakeConn) QueryRowContextCallCount() int {
+	fake.queryRowContextMutex.RLock()
+	defer fake.queryRowContextMutex.RUnlock()
+	return len(fake.queryRowContextArgsForCall)
+}
+
+func (fake *FakeConn) QueryRowContextArgsForCall(i int) (context.Context, string, []interface{}) {
+	fake.queryRowContextMutex.RLock()
+	defer fake.queryRowContextMutex.RUnlock()
+	return fake.queryRowContextArgsForCall[i].ctx, fake.queryRowContextArgsForCall[i].query, fake.queryRowContextArgsForCall[i].args
+}
+
+func (fake *FakeConn) QueryRowContextReturns(result1 *sql.Row) {
+	fake.QueryRowContextStub = nil
+	fake.queryRowContextReturns = struct {
+		result1 *sql.Row
+	}{result1}
+}
+
+func (fake *FakeConn) EventSigningKey() []byte {
+	fake.eventSigningKeyMutex.Lock()
+	fake.eventSigningKeyArgsForCall = append(fake.eventSigningKeyArgsForCall, struct{}{})
+	fake.recordInvocation("EventSigningKey", []interface{}{})
+	fake.eventSigningKeyMutex.Unlock()
+	if fake.EventSigningKeyStub != nil {
+		return fake.EventSigningKeyStub()
+	} else {
+		return fake.eventSigningKeyReturns.result1
+	}
+}
+
+func (fake *FakeConn) EventSigningKeyCallCount() int {
+	fake.eventSigningKeyMutex.RLock()
+	defer fake.eventSigningKeyMutex.RUnlock()
+	return len(fake.eventSigningKeyArgsForCall)
+}
+
+func (fake *FakeConn) EventSigningKeyReturns(result1 []byte) {
+	fake.EventSigningKeyStub = nil
+	fake.eventSigningKeyReturns = struct {
+		result1 []byte
+	}{result1}
+}
+
 func (fake *FakeConn) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -397,6 +705,22 @@ func (fake *FakeConn) Invocations() map[string][][]interface{} {
 	defer fake.setMaxIdleConnsMutex.RUnlock()
 	fake.setMaxOpenConnsMutex.RLock()
 	defer fake.setMaxOpenConnsMutex.RUnlock()
+	fake.setConnMaxLifetimeMutex.RLock()
+	defer fake.setConnMaxLifetimeMutex.RUnlock()
+	fake.statsMutex.RLock()
+	defer fake.statsMutex.RUnlock()
+	fake.encryptionStrategyMutex.RLock()
+	defer fake.encryptionStrategyMutex.RUnlock()
+	fake.beginTxMutex.RLock()
+	defer fake.beginTxMutex.RUnlock()
+	fake.execContextMutex.RLock()
+	defer fake.execContextMutex.RUnlock()
+	fake.queryContextMutex.RLock()
+	defer fake.queryContextMutex.RUnlock()
+	fake.queryRowContextMutex.RLock()
+	defer fake.queryRowContextMutex.RUnlock()
+	fake.eventSigningKeyMutex.RLock()
+	defer fake.eventSigningKeyMutex.RUnlock()
 	return fake.invocations
 }
 
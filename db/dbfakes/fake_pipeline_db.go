@@ -532,6 +532,274 @@ type FakePipelineDB struct {
 	hideReturns     struct {
 		result1 error
 	}
+	GetPendingBuildPrioritiesStub        func() (map[string]int, error)
+	getPendingBuildPrioritiesMutex       sync.RWMutex
+	getPendingBuildPrioritiesArgsForCall []struct{}
+	getPendingBuildPrioritiesReturns     struct {
+		result1 map[string]int
+		result2 error
+	}
+	GetJobLastScheduledAtStub        func(job string) (time.Time, error)
+	getJobLastScheduledAtMutex       sync.RWMutex
+	getJobLastScheduledAtArgsForCall []struct {
+		job string
+	}
+	getJobLastScheduledAtReturns struct {
+		result1 time.Time
+		result2 error
+	}
+	SaveJobLastScheduledAtStub        func(job string, previous time.Time, at time.Time) (bool, error)
+	saveJobLastScheduledAtMutex       sync.RWMutex
+	saveJobLastScheduledAtArgsForCall []struct {
+		job      string
+		previous time.Time
+		at       time.Time
+	}
+	saveJobLastScheduledAtReturns struct {
+		result1 bool
+		result2 error
+	}
+	PinResourceVersionStub        func(resourceName string, versionedResourceID int) (bool, error)
+	pinResourceVersionMutex       sync.RWMutex
+	pinResourceVersionArgsForCall []struct {
+		resourceName        string
+		versionedResourceID int
+	}
+	pinResourceVersionReturns struct {
+		result1 bool
+		result2 error
+	}
+	UnpinResourceStub        func(resourceName string) error
+	unpinResourceMutex       sync.RWMutex
+	unpinResourceArgsForCall []struct {
+		resourceName string
+	}
+	unpinResourceReturns struct {
+		result1 error
+	}
+	MakeJobManualOnlyStub        func(job string) error
+	makeJobManualOnlyMutex       sync.RWMutex
+	makeJobManualOnlyArgsForCall []struct {
+		job string
+	}
+	makeJobManualOnlyReturns struct {
+		result1 error
+	}
+	MakeJobAutomaticStub        func(job string) error
+	makeJobAutomaticMutex       sync.RWMutex
+	makeJobAutomaticArgsForCall []struct {
+		job string
+	}
+	makeJobAutomaticReturns struct {
+		result1 error
+	}
+	GetVersionCausalityStub        func(versionedResourceID int) (db.Causality, bool, error)
+	getVersionCausalityMutex       sync.RWMutex
+	getVersionCausalityArgsForCall []struct {
+		versionedResourceID int
+	}
+	getVersionCausalityReturns struct {
+		result1 db.Causality
+		result2 bool
+		result3 error
+	}
+	GetJobLatestFinishedBuildWithInputStub        func(job string, resourceName string, version atc.Version) (db.Build, bool, error)
+	getJobLatestFinishedBuildWithInputMutex       sync.RWMutex
+	getJobLatestFinishedBuildWithInputArgsForCall []struct {
+		job          string
+		resourceName string
+		version      atc.Version
+	}
+	getJobLatestFinishedBuildWithInputReturns struct {
+		result1 db.Build
+		result2 bool
+		result3 error
+	}
+	GetAllResourceVersionsStub        func(resourceName string) ([]db.SavedVersionedResource, error)
+	getAllResourceVersionsMutex       sync.RWMutex
+	getAllResourceVersionsArgsForCall []struct {
+		resourceName string
+	}
+	getAllResourceVersionsReturns struct {
+		result1 []db.SavedVersionedResource
+		result2 error
+	}
+	ImportResourceVersionsStub        func(config atc.ResourceConfig, versions []db.SavedVersionedResource) error
+	importResourceVersionsMutex       sync.RWMutex
+	importResourceVersionsArgsForCall []struct {
+		config   atc.ResourceConfig
+		versions []db.SavedVersionedResource
+	}
+	importResourceVersionsReturns struct {
+		result1 error
+	}
+	ImportJobBuildStub        func(job string, build db.ImportedBuild) (db.Build, error)
+	importJobBuildMutex       sync.RWMutex
+	importJobBuildArgsForCall []struct {
+		job   string
+		build db.ImportedBuild
+	}
+	importJobBuildReturns struct {
+		result1 db.Build
+		result2 error
+	}
+	GetJobBuildStatsStub        func(job string, window time.Duration) (db.JobBuildStats, error)
+	getJobBuildStatsMutex       sync.RWMutex
+	getJobBuildStatsArgsForCall []struct {
+		job    string
+		window time.Duration
+	}
+	getJobBuildStatsReturns struct {
+		result1 db.JobBuildStats
+		result2 error
+	}
+	GetJobOutcomesStub        func(job string, builds int) (db.JobOutcomes, error)
+	getJobOutcomesMutex       sync.RWMutex
+	getJobOutcomesArgsForCall []struct {
+		job    string
+		builds int
+	}
+	getJobOutcomesReturns struct {
+		result1 db.JobOutcomes
+		result2 error
+	}
+	SaveJobFlakinessStub        func(job string, flakiness float64, flaky bool) error
+	saveJobFlakinessMutex       sync.RWMutex
+	saveJobFlakinessArgsForCall []struct {
+		job       string
+		flakiness float64
+		flaky     bool
+	}
+	saveJobFlakinessReturns struct {
+		result1 error
+	}
+	CreateJobBuildWithParamsStub        func(job string, params map[string]string) (db.Build, error)
+	createJobBuildWithParamsMutex       sync.RWMutex
+	createJobBuildWithParamsArgsForCall []struct {
+		job    string
+		params map[string]string
+	}
+	createJobBuildWithParamsReturns struct {
+		result1 db.Build
+		result2 error
+	}
+	GetRegisteredResourceTypesStub        func() ([]atc.RegisteredResourceType, error)
+	getRegisteredResourceTypesMutex       sync.RWMutex
+	getRegisteredResourceTypesArgsForCall []struct{}
+	getRegisteredResourceTypesReturns     struct {
+		result1 []atc.RegisteredResourceType
+		result2 error
+	}
+	SaveSchedulingReasonStub        func(job string, reason db.SchedulingReason) error
+	saveSchedulingReasonMutex       sync.RWMutex
+	saveSchedulingReasonArgsForCall []struct {
+		job    string
+		reason db.SchedulingReason
+	}
+	saveSchedulingReasonReturns struct {
+		result1 error
+	}
+	SaveMissingInputReasonsStub        func(job string, reasons db.MissingInputReasons) error
+	saveMissingInputReasonsMutex       sync.RWMutex
+	saveMissingInputReasonsArgsForCall []struct {
+		job     string
+		reasons db.MissingInputReasons
+	}
+	saveMissingInputReasonsReturns struct {
+		result1 error
+	}
+	GetJobSchedulingStub        func(job string) (db.JobScheduling, bool, error)
+	getJobSchedulingMutex       sync.RWMutex
+	getJobSchedulingArgsForCall []struct {
+		job string
+	}
+	getJobSchedulingReturns struct {
+		result1 db.JobScheduling
+		result2 bool
+		result3 error
+	}
+	GetVersionsPassedJobsStub        func(resourceName string, jobNames []string) ([]db.SavedVersionedResource, bool, error)
+	getVersionsPassedJobsMutex       sync.RWMutex
+	getVersionsPassedJobsArgsForCall []struct {
+		resourceName string
+		jobNames     []string
+	}
+	getVersionsPassedJobsReturns struct {
+		result1 []db.SavedVersionedResource
+		result2 bool
+		result3 error
+	}
+	GetTaskCachesStub        func(job string) ([]db.TaskCache, error)
+	getTaskCachesMutex       sync.RWMutex
+	getTaskCachesArgsForCall []struct {
+		job string
+	}
+	getTaskCachesReturns struct {
+		result1 []db.TaskCache
+		result2 error
+	}
+	DeleteTaskCachesStub        func(job string, path string) ([]db.TaskCache, error)
+	deleteTaskCachesMutex       sync.RWMutex
+	deleteTaskCachesArgsForCall []struct {
+		job  string
+		path string
+	}
+	deleteTaskCachesReturns struct {
+		result1 []db.TaskCache
+		result2 error
+	}
+	SetPermissionsStub        func(permissions atc.PipelinePermissions) error
+	setPermissionsMutex       sync.RWMutex
+	setPermissionsArgsForCall []struct {
+		permissions atc.PipelinePermissions
+	}
+	setPermissionsReturns struct {
+		result1 error
+	}
+	SchedulingNotifierStub        func() (db.Notifier, error)
+	schedulingNotifierMutex       sync.RWMutex
+	schedulingNotifierArgsForCall []struct{}
+	schedulingNotifierReturns     struct {
+		result1 db.Notifier
+		result2 error
+	}
+	GetCandidateConfigStub        func() (db.CandidateConfig, bool, error)
+	getCandidateConfigMutex       sync.RWMutex
+	getCandidateConfigArgsForCall []struct{}
+	getCandidateConfigReturns     struct {
+		result1 db.CandidateConfig
+		result2 bool
+		result3 error
+	}
+	UseCandidateConfigForBuildStub        func(buildID int, candidateID int) error
+	useCandidateConfigForBuildMutex       sync.RWMutex
+	useCandidateConfigForBuildArgsForCall []struct {
+		buildID     int
+		candidateID int
+	}
+	useCandidateConfigForBuildReturns struct {
+		result1 error
+	}
+	GetJobArtifactBuildsStub        func(job string) ([]db.ArtifactBuild, error)
+	getJobArtifactBuildsMutex       sync.RWMutex
+	getJobArtifactBuildsArgsForCall []struct {
+		job string
+	}
+	getJobArtifactBuildsReturns struct {
+		result1 []db.ArtifactBuild
+		result2 error
+	}
+	SetBuildArtifactsProtectedStub        func(job string, buildName string, protected bool) (bool, error)
+	setBuildArtifactsProtectedMutex       sync.RWMutex
+	setBuildArtifactsProtectedArgsForCall []struct {
+		job       string
+		buildName string
+		protected bool
+	}
+	setBuildArtifactsProtectedReturns struct {
+		result1 bool
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -2461,6 +2729,985 @@ func (fake *FakePipelineDB) HideReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakePipelineDB) GetPendingBuildPriorities() (map[string]int, error) {
+	fake.getPendingBuildPrioritiesMutex.Lock()
+	fake.getPendingBuildPrioritiesArgsForCall = append(fake.getPendingBuildPrioritiesArgsForCall, struct{}{})
+	fake.recordInvocation("GetPendingBuildPriorities", []interface{}{})
+	fake.getPendingBuildPrioritiesMutex.Unlock()
+	if fake.GetPendingBuildPrioritiesStub != nil {
+		return fake.GetPendingBuildPrioritiesStub()
+	} else {
+		return fake.getPendingBuildPrioritiesReturns.result1, fake.getPendingBuildPrioritiesReturns.result2
+	}
+}
+
+func (fake *FakePipelineDB) GetPendingBuildPrioritiesCallCount() int {
+	fake.getPendingBuildPrioritiesMutex.RLock()
+	defer fake.getPendingBuildPrioritiesMutex.RUnlock()
+	return len(fake.getPendingBuildPrioritiesArgsForCall)
+}
+
+func (fake *FakePipelineDB) GetPendingBuildPrioritiesReturns(result1 map[string]int, result2 error) {
+	fake.GetPendingBuildPrioritiesStub = nil
+	fake.getPendingBuildPrioritiesReturns = struct {
+		result1 map[string]int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePipelineDB) GetJobLastScheduledAt(job string) (time.Time, error) {
+	fake.getJobLastScheduledAtMutex.Lock()
+	fake.getJobLastScheduledAtArgsForCall = append(fake.getJobLastScheduledAtArgsForCall, struct {
+		job string
+	}{job})
+	fake.recordInvocation("GetJobLastScheduledAt", []interface{}{job})
+	fake.getJobLastScheduledAtMutex.Unlock()
+	if fake.GetJobLastScheduledAtStub != nil {
+		return fake.GetJobLastScheduledAtStub(job)
+	} else {
+		return fake.getJobLastScheduledAtReturns.result1, fake.getJobLastScheduledAtReturns.result2
+	}
+}
+
+func (fake *FakePipelineDB) GetJobLastScheduledAtCallCount() int {
+	fake.getJobLastScheduledAtMutex.RLock()
+	defer fake.getJobLastScheduledAtMutex.RUnlock()
+	return len(fake.getJobLastScheduledAtArgsForCall)
+}
+
+func (fake *FakePipelineDB) GetJobLastScheduledAtArgsForCall(i int) string {
+	fake.getJobLastScheduledAtMutex.RLock()
+	defer fake.getJobLastScheduledAtMutex.RUnlock()
+	return fake.getJobLastScheduledAtArgsForCall[i].job
+}
+
+func (fake *FakePipelineDB) GetJobLastScheduledAtReturns(result1 time.Time, result2 error) {
+	fake.GetJobLastScheduledAtStub = nil
+	fake.getJobLastScheduledAtReturns = struct {
+		result1 time.Time
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePipelineDB) SaveJobLastScheduledAt(job string, previous time.Time, at time.Time) (bool, error) {
+	fake.saveJobLastScheduledAtMutex.Lock()
+	fake.saveJobLastScheduledAtArgsForCall = append(fake.saveJobLastScheduledAtArgsForCall, struct {
+		job      string
+		previous time.Time
+		at       time.Time
+	}{job, previous, at})
+	fake.recordInvocation("SaveJobLastScheduledAt", []interface{}{job, previous, at})
+	fake.saveJobLastScheduledAtMutex.Unlock()
+	if fake.SaveJobLastScheduledAtStub != nil {
+		return fake.SaveJobLastScheduledAtStub(job, previous, at)
+	} else {
+		return fake.saveJobLastScheduledAtReturns.result1, fake.saveJobLastScheduledAtReturns.result2
+	}
+}
+
+func (fake *FakePipelineDB) SaveJobLastScheduledAtCallCount() int {
+	fake.saveJobLastScheduledAtMutex.RLock()
+	defer fake.saveJobLastScheduledAtMutex.RUnlock()
+	return len(fake.saveJobLastScheduledAtArgsForCall)
+}
+
+func (fake *FakePipelineDB) SaveJobLastScheduledAtArgsForCall(i int) (string, time.Time, time.Time) {
+	fake.saveJobLastScheduledAtMutex.RLock()
+	defer fake.saveJobLastScheduledAtMutex.RUnlock()
+	return fake.saveJobLastScheduledAtArgsForCall[i].job, fake.saveJobLastScheduledAtArgsForCall[i].previous, fake.saveJobLastScheduledAtArgsForCall[i].at
+}
+
+func (fake *FakePipelineDB) SaveJobLastScheduledAtReturns(result1 bool, result2 error) {
+	fake.SaveJobLastScheduledAtStub = nil
+	fake.saveJobLastScheduledAtReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePipelineDB) PinResourceVersion(resourceName string, versionedResourceID int) (bool, error) {
+	fake.pinResourceVersionMutex.Lock()
+	fake.pinResourceVersionArgsForCall = append(fake.pinResourceVersionArgsForCall, struct {
+		resourceName        string
+		versionedResourceID int
+	}{resourceName, versionedResourceID})
+	fake.recordInvocation("PinResourceVersion", []interface{}{resourceName, versionedResourceID})
+	fake.pinResourceVersionMutex.Unlock()
+	if fake.PinResourceVersionStub != nil {
+		return fake.PinResourceVersionStub(resourceName, versionedResourceID)
+	} else {
+		return fake.pinResourceVersionReturns.result1, fake.pinResourceVersionReturns.result2
+	}
+}
+
+func (fake *FakePipelineDB) PinResourceVersionCallCount() int {
+	fake.pinResourceVersionMutex.RLock()
+	defer fake.pinResourceVersionMutex.RUnlock()
+	return len(fake.pinResourceVersionArgsForCall)
+}
+
+func (fake *FakePipelineDB) PinResourceVersionArgsForCall(i int) (string, int) {
+	fake.pinResourceVersionMutex.RLock()
+	defer fake.pinResourceVersionMutex.RUnlock()
+	return fake.pinResourceVersionArgsForCall[i].resourceName, fake.pinResourceVersionArgsForCall[i].versionedResourceID
+}
+
+func (fake *FakePipelineDB) PinResourceVersionReturns(result1 bool, result2 error) {
+	fake.PinResourceVersionStub = nil
+	fake.pinResourceVersionReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePipelineDB) UnpinResource(resourceName string) error {
+	fake.unpinResourceMutex.Lock()
+	fake.unpinResourceArgsForCall = append(fake.unpinResourceArgsForCall, struct {
+		resourceName string
+	}{resourceName})
+	fake.recordInvocation("UnpinResource", []interface{}{resourceName})
+	fake.unpinResourceMutex.Unlock()
+	if fake.UnpinResourceStub != nil {
+		return fake.UnpinResourceStub(resourceName)
+	} else {
+		return fake.unpinResourceReturns.result1
+	}
+}
+
+func (fake *FakePipelineDB) UnpinResourceCallCount() int {
+	fake.unpinResourceMutex.RLock()
+	defer fake.unpinResourceMutex.RUnlock()
+	return len(fake.unpinResourceArgsForCall)
+}
+
+func (fake *FakePipelineDB) UnpinResourceArgsForCall(i int) string {
+	fake.unpinResourceMutex.RLock()
+	defer fake.unpinResourceMutex.RUnlock()
+	return fake.unpinResourceArgsForCall[i].resourceName
+}
+
+func (fake *FakePipelineDB) UnpinResourceReturns(result1 error) {
+	fake.UnpinResourceStub = nil
+	fake.unpinResourceReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePipelineDB) MakeJobManualOnly(job string) error {
+	fake.makeJobManualOnlyMutex.Lock()
+	fake.makeJobManualOnlyArgsForCall = append(fake.makeJobManualOnlyArgsForCall, struct {
+		job string
+	}{job})
+	fake.recordInvocation("MakeJobManualOnly", []interface{}{job})
+	fake.makeJobManualOnlyMutex.Unlock()
+	if fake.MakeJobManualOnlyStub != nil {
+		return fake.MakeJobManualOnlyStub(job)
+	} else {
+		return fake.makeJobManualOnlyReturns.result1
+	}
+}
+
+func (fake *FakePipelineDB) MakeJobManualOnlyCallCount() int {
+	fake.makeJobManualOnlyMutex.RLock()
+	defer fake.makeJobManualOnlyMutex.RUnlock()
+	return len(fake.makeJobManualOnlyArgsForCall)
+}
+
+func (fake *FakePipelineDB) MakeJobManualOnlyArgsForCall(i int) string {
+	fake.makeJobManualOnlyMutex.RLock()
+	defer fake.makeJobManualOnlyMutex.RUnlock()
+	return fake.makeJobManualOnlyArgsForCall[i].job
+}
+
+func (fake *FakePipelineDB) MakeJobManualOnlyReturns(result1 error) {
+	fake.MakeJobManualOnlyStub = nil
+	fake.makeJobManualOnlyReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePipelineDB) MakeJobAutomatic(job string) error {
+	fake.makeJobAutomaticMutex.Lock()
+	fake.makeJobAutomaticArgsForCall = append(fake.makeJobAutomaticArgsForCall, struct {
+		job string
+	}{job})
+	fake.recordInvocation("MakeJobAutomatic", []interface{}{job})
+	fake.makeJobAutomaticMutex.Unlock()
+	if fake.MakeJobAutomaticStub != nil {
+		return fake.MakeJobAutomaticStub(job)
+	} else {
+		return fake.makeJobAutomaticReturns.result1
+	}
+}
+
+func (fake *FakePipelineDB) MakeJobAutomaticCallCount() int {
+	fake.makeJobAutomaticMutex.RLock()
+	defer fake.makeJobAutomaticMutex.RUnlock()
+	return len(fake.makeJobAutomaticArgsForCall)
+}
+
+func (fake *FakePipelineDB) MakeJobAutomaticArgsForCall(i int) string {
+	fake.makeJobAutomaticMutex.RLock()
+	defer fake.makeJobAutomaticMutex.RUnlock()
+	return fake.makeJobAutomaticArgsForCall[i].job
+}
+
+func (fake *FakePipelineDB) MakeJobAutomaticReturns(result1 error) {
+	fake.MakeJobAutomaticStub = nil
+	fake.makeJobAutomaticReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePipelineDB) GetVersionCausality(versionedResourceID int) (db.Causality, bool, error) {
+	fake.getVersionCausalityMutex.Lock()
+	fake.getVersionCausalityArgsForCall = append(fake.getVersionCausalityArgsForCall, struct {
+		versionedResourceID int
+	}{versionedResourceID})
+	fake.recordInvocation("GetVersionCausality", []interface{}{versionedResourceID})
+	fake.getVersionCausalityMutex.Unlock()
+	if fake.GetVersionCausalityStub != nil {
+		return fake.GetVersionCausalityStub(versionedResourceID)
+	} else {
+		return fake.getVersionCausalityReturns.result1, fake.getVersionCausalityReturns.result2, fake.getVersionCausalityReturns.result3
+	}
+}
+
+func (fake *FakePipelineDB) GetVersionCausalityCallCount() int {
+	fake.getVersionCausalityMutex.RLock()
+	defer fake.getVersionCausalityMutex.RUnlock()
+	return len(fake.getVersionCausalityArgsForCall)
+}
+
+func (fake *FakePipelineDB) GetVersionCausalityArgsForCall(i int) int {
+	fake.getVersionCausalityMutex.RLock()
+	defer fake.getVersionCausalityMutex.RUnlock()
+	return fake.getVersionCausalityArgsForCall[i].versionedResourceID
+}
+
+func (fake *FakePipelineDB) GetVersionCausalityReturns(result1 db.Causality, result2 bool, result3 error) {
+	fake.GetVersionCausalityStub = nil
+	fake.getVersionCausalityReturns = struct {
+		result1 db.Causality
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakePipelineDB) GetJobLatestFinishedBuildWithInput(job string, resourceName string, version atc.Version) (db.Build, bool, error) {
+	fake.getJobLatestFinishedBuildWithInputMutex.Lock()
+	fake.getJobLatestFinishedBuildWithInputArgsForCall = append(fake.getJobLatestFinishedBuildWithInputArgsForCall, struct {
+		job          string
+		resourceName string
+		version      atc.Version
+	}{job, resourceName, version})
+	fake.recordInvocation("GetJobLatestFinishedBuildWithInput", []interface{}{job, resourceName, version})
+	fake.getJobLatestFinishedBuildWithInputMutex.Unlock()
+	if fake.GetJobLatestFinishedBuildWithInputStub != nil {
+		return fake.GetJobLatestFinishedBuildWithInputStub(job, resourceName, version)
+	} else {
+		return fake.getJobLatestFinishedBuildWithInputReturns.result1, fake.getJobLatestFinishedBuildWithInputReturns.result2, fake.getJobLatestFinishedBuildWithInputReturns.result3
+	}
+}
+
+func (fake *FakePipelineDB) GetJobLatestFinishedBuildWithInputCallCount() int {
+	fake.getJobLatestFinishedBuildWithInputMutex.RLock()
+	defer fake.getJobLatestFinishedBuildWithInputMutex.RUnlock()
+	return len(fake.getJobLatestFinishedBuildWithInputArgsForCall)
+}
+
+func (fake *FakePipelineDB) GetJobLatestFinishedBuildWithInputArgsForCall(i int) (string, string, atc.Version) {
+	fake.getJobLatestFinishedBuildWithInputMutex.RLock()
+	defer fake.getJobLatestFinishedBuildWithInputMutex.RUnlock()
+	return fake.getJobLatestFinishedBuildWithInputArgsForCall[i].job, fake.getJobLatestFinishedBuildWithInputArgsForCall[i].resourceName, fake.getJobLatestFinishedBuildWithInputArgsForCall[i].version
+}
+
+func (fake *FakePipelineDB) GetJobLatestFinishedBuildWithInputReturns(result1 db.Build, result2 bool, result3 error) {
+	fake.GetJobLatestFinishedBuildWithInputStub = nil
+	fake.getJobLatestFinishedBuildWithInputReturns = struct {
+		result1 db.Build
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakePipelineDB) GetAllResourceVersions(resourceName string) ([]db.SavedVersionedResource, error) {
+	fake.getAllResourceVersionsMutex.Lock()
+	fake.getAllResourceVersionsArgsForCall = append(fake.getAllResourceVersionsArgsForCall, struct {
+		resourceName string
+	}{resourceName})
+	fake.recordInvocation("GetAllResourceVersions", []interface{}{resourceName})
+	fake.getAllResourceVersionsMutex.Unlock()
+	if fake.GetAllResourceVersionsStub != nil {
+		return fake.GetAllResourceVersionsStub(resourceName)
+	} else {
+		return fake.getAllResourceVersionsReturns.result1, fake.getAllResourceVersionsReturns.result2
+	}
+}
+
+func (fake *FakePipelineDB) GetAllResourceVersionsCallCount() int {
+	fake.getAllResourceVersionsMutex.RLock()
+	defer fake.getAllResourceVersionsMutex.RUnlock()
+	return len(fake.getAllResourceVersionsArgsForCall)
+}
+
+func (fake *FakePipelineDB) GetAllResourceVersionsArgsForCall(i int) string {
+	fake.getAllResourceVersionsMutex.RLock()
+	defer fake.getAllResourceVersionsMutex.RUnlock()
+	return fake.getAllResourceVersionsArgsForCall[i].resourceName
+}
+
+func (fake *FakePipelineDB) GetAllResourceVersionsReturns(result1 []db.SavedVersionedResource, result2 error) {
+	fake.GetAllResourceVersionsStub = nil
+	fake.getAllResourceVersionsReturns = struct {
+		result1 []db.SavedVersionedResource
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePipelineDB) ImportResourceVersions(config atc.ResourceConfig, versions []db.SavedVersionedResource) error {
+	var versionsCopy []db.SavedVersionedResource
+	if versions != nil {
+		versionsCopy = make([]db.SavedVersionedResource, len(versions))
+		copy(versionsCopy, versions)
+	}
+	fake.importResourceVersionsMutex.Lock()
+	fake.importResourceVersionsArgsForCall = append(fake.importResourceVersionsArgsForCall, struct {
+		config   atc.ResourceConfig
+		versions []db.SavedVersionedResource
+	}{config, versionsCopy})
+	fake.recordInvocation("ImportResourceVersions", []interface{}{config, versionsCopy})
+	fake.importResourceVersionsMutex.Unlock()
+	if fake.ImportResourceVersionsStub != nil {
+		return fake.ImportResourceVersionsStub(config, versions)
+	} else {
+		return fake.importResourceVersionsReturns.result1
+	}
+}
+
+func (fake *FakePipelineDB) ImportResourceVersionsCallCount() int {
+	fake.importResourceVersionsMutex.RLock()
+	defer fake.importResourceVersionsMutex.RUnlock()
+	return len(fake.importResourceVersionsArgsForCall)
+}
+
+func (fake *FakePipelineDB) ImportResourceVersionsArgsForCall(i int) (atc.ResourceConfig, []db.SavedVersionedResource) {
+	fake.importResourceVersionsMutex.RLock()
+	defer fake.importResourceVersionsMutex.RUnlock()
+	return fake.importResourceVersionsArgsForCall[i].config, fake.importResourceVersionsArgsForCall[i].versions
+}
+
+func (fake *FakePipelineDB) ImportResourceVersionsReturns(result1 error) {
+	fake.ImportResourceVersionsStub = nil
+	fake.importResourceVersionsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePipelineDB) ImportJobBuild(job string, build db.ImportedBuild) (db.Build, error) {
+	fake.importJobBuildMutex.Lock()
+	fake.importJobBuildArgsForCall = append(fake.importJobBuildArgsForCall, struct {
+		job   string
+		build db.ImportedBuild
+	}{job, build})
+	fake.recordInvocation("ImportJobBuild", []interface{}{job, build})
+	fake.importJobBuildMutex.Unlock()
+	if fake.ImportJobBuildStub != nil {
+		return fake.ImportJobBuildStub(job, build)
+	} else {
+		return fake.importJobBuildReturns.result1, fake.importJobBuildReturns.result2
+	}
+}
+
+func (fake *FakePipelineDB) ImportJobBuildCallCount() int {
+	fake.importJobBuildMutex.RLock()
+	defer fake.importJobBuildMutex.RUnlock()
+	return len(fake.importJobBuildArgsForCall)
+}
+
+func (fake *FakePipelineDB) ImportJobBuildArgsForCall(i int) (string, db.ImportedBuild) {
+	fake.importJobBuildMutex.RLock()
+	defer fake.importJobBuildMutex.RUnlock()
+	return fake.importJobBuildArgsForCall[i].job, fake.importJobBuildArgsForCall[i].build
+}
+
+func (fake *FakePipelineDB) ImportJobBuildReturns(result1 db.Build, result2 error) {
+	fake.ImportJobBuildStub = nil
+	fake.importJobBuildReturns = struct {
+		result1 db.Build
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePipelineDB) GetJobBuildStats(job string, window time.Duration) (db.JobBuildStats, error) {
+	fake.getJobBuildStatsMutex.Lock()
+	fake.getJobBuildStatsArgsForCall = append(fake.getJobBuildStatsArgsForCall, struct {
+		job    string
+		window time.Duration
+	}{job, window})
+	fake.recordInvocation("GetJobBuildStats", []interface{}{job, window})
+	fake.getJobBuildStatsMutex.Unlock()
+	if fake.GetJobBuildStatsStub != nil {
+		return fake.GetJobBuildStatsStub(job, window)
+	} else {
+		return fake.getJobBuildStatsReturns.result1, fake.getJobBuildStatsReturns.result2
+	}
+}
+
+func (fake *FakePipelineDB) GetJobBuildStatsCallCount() int {
+	fake.getJobBuildStatsMutex.RLock()
+	defer fake.getJobBuildStatsMutex.RUnlock()
+	return len(fake.getJobBuildStatsArgsForCall)
+}
+
+func (fake *FakePipelineDB) GetJobBuildStatsArgsForCall(i int) (string, time.Duration) {
+	fake.getJobBuildStatsMutex.RLock()
+	defer fake.getJobBuildStatsMutex.RUnlock()
+	return fake.getJobBuildStatsArgsForCall[i].job, fake.getJobBuildStatsArgsForCall[i].window
+}
+
+func (fake *FakePipelineDB) GetJobBuildStatsReturns(result1 db.JobBuildStats, result2 error) {
+	fake.GetJobBuildStatsStub = nil
+	fake.getJobBuildStatsReturns = struct {
+		result1 db.JobBuildStats
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePipelineDB) GetJobOutcomes(job string, builds int) (db.JobOutcomes, error) {
+	fake.getJobOutcomesMutex.Lock()
+	fake.getJobOutcomesArgsForCall = append(fake.getJobOutcomesArgsForCall, struct {
+		job    string
+		builds int
+	}{job, builds})
+	fake.recordInvocation("GetJobOutcomes", []interface{}{job, builds})
+	fake.getJobOutcomesMutex.Unlock()
+	if fake.GetJobOutcomesStub != nil {
+		return fake.GetJobOutcomesStub(job, builds)
+	} else {
+		return fake.getJobOutcomesReturns.result1, fake.getJobOutcomesReturns.result2
+	}
+}
+
+func (fake *FakePipelineDB) GetJobOutcomesCallCount() int {
+	fake.getJobOutcomesMutex.RLock()
+	defer fake.getJobOutcomesMutex.RUnlock()
+	return len(fake.getJobOutcomesArgsForCall)
+}
+
+func (fake *FakePipelineDB) GetJobOutcomesArgsForCall(i int) (string, int) {
+	fake.getJobOutcomesMutex.RLock()
+	defer fake.getJobOutcomesMutex.RUnlock()
+	return fake.getJobOutcomesArgsForCall[i].job, fake.getJobOutcomesArgsForCall[i].builds
+}
+
+func (fake *FakePipelineDB) GetJobOutcomesReturns(result1 db.JobOutcomes, result2 error) {
+	fake.GetJobOutcomesStub = nil
+	fake.getJobOutcomesReturns = struct {
+		result1 db.JobOutcomes
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePipelineDB) SaveJobFlakiness(job string, flakiness float64, flaky bool) error {
+	fake.saveJobFlakinessMutex.Lock()
+	fake.saveJobFlakinessArgsForCall = append(fake.saveJobFlakinessArgsForCall, struct {
+		job       string
+		flakiness float64
+		flaky     bool
+	}{job, flakiness, flaky})
+	fake.recordInvocation("SaveJobFlakiness", []interface{}{job, flakiness, flaky})
+	fake.saveJobFlakinessMutex.Unlock()
+	if fake.SaveJobFlakinessStub != nil {
+		return fake.SaveJobFlakinessStub(job, flakiness, flaky)
+	} else {
+		return fake.saveJobFlakinessReturns.result1
+	}
+}
+
+func (fake *FakePipelineDB) SaveJobFlakinessCallCount() int {
+	fake.saveJobFlakinessMutex.RLock()
+	defer fake.saveJobFlakinessMutex.RUnlock()
+	return len(fake.saveJobFlakinessArgsForCall)
+}
+
+func (fake *FakePipelineDB) SaveJobFlakinessArgsForCall(i int) (string, float64, bool) {
+	fake.saveJobFlakinessMutex.RLock()
+	defer fake.saveJobFlakinessMutex.RUnlock()
+	return fake.saveJobFlakinessArgsForCall[i].job, fake.saveJobFlakinessArgsForCall[i].flakiness, fake.saveJobFlakinessArgsForCall[i].flaky
+}
+
+func (fake *FakePipelineDB) SaveJobFlakinessReturns(result1 error) {
+	fake.SaveJobFlakinessStub = nil
+	fake.saveJobFlakinessReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePipelineDB) CreateJobBuildWithParams(job string, params map[string]string) (db.Build, error) {
+	fake.createJobBuildWithParamsMutex.Lock()
+	fake.createJobBuildWithParamsArgsForCall = append(fake.createJobBuildWithParamsArgsForCall, struct {
+		job    string
+		params map[string]string
+	}{job, params})
+	fake.recordInvocation("CreateJobBuildWithParams", []interface{}{job, params})
+	fake.createJobBuildWithParamsMutex.Unlock()
+	if fake.CreateJobBuildWithParamsStub != nil {
+		return fake.CreateJobBuildWithParamsStub(job, params)
+	} else {
+		return fake.createJobBuildWithParamsReturns.result1, fake.createJobBuildWithParamsReturns.result2
+	}
+}
+
+func (fake *FakePipelineDB) CreateJobBuildWithParamsCallCount() int {
+	fake.createJobBuildWithParamsMutex.RLock()
+	defer fake.createJobBuildWithParamsMutex.RUnlock()
+	return len(fake.createJobBuildWithParamsArgsForCall)
+}
+
+func (fake *FakePipelineDB) CreateJobBuildWithParamsArgsForCall(i int) (string, map[string]string) {
+	fake.createJobBuildWithParamsMutex.RLock()
+	defer fake.createJobBuildWithParamsMutex.RUnlock()
+	return fake.createJobBuildWithParamsArgsForCall[i].job, fake.createJobBuildWithParamsArgsForCall[i].params
+}
+
+func (fake *FakePipelineDB) CreateJobBuildWithParamsReturns(result1 db.Build, result2 error) {
+	fake.CreateJobBuildWithParamsStub = nil
+	fake.createJobBuildWithParamsReturns = struct {
+		result1 db.Build
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePipelineDB) GetRegisteredResourceTypes() ([]atc.RegisteredResourceType, error) {
+	fake.getRegisteredResourceTypesMutex.Lock()
+	fake.getRegisteredResourceTypesArgsForCall = append(fake.getRegisteredResourceTypesArgsForCall, struct{}{})
+	fake.recordInvocation("GetRegisteredResourceTypes", []interface{}{})
+	fake.getRegisteredResourceTypesMutex.Unlock()
+	if fake.GetRegisteredResourceTypesStub != nil {
+		return fake.GetRegisteredResourceTypesStub()
+	} else {
+		return fake.getRegisteredResourceTypesReturns.result1, fake.getRegisteredResourceTypesReturns.result2
+	}
+}
+
+func (fake *FakePipelineDB) GetRegisteredResourceTypesCallCount() int {
+	fake.getRegisteredResourceTypesMutex.RLock()
+	defer fake.getRegisteredResourceTypesMutex.RUnlock()
+	return len(fake.getRegisteredResourceTypesArgsForCall)
+}
+
+func (fake *FakePipelineDB) GetRegisteredResourceTypesReturns(result1 []atc.RegisteredResourceType, result2 error) {
+	fake.GetRegisteredResourceTypesStub = nil
+	fake.getRegisteredResourceTypesReturns = struct {
+		result1 []atc.RegisteredResourceType
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePipelineDB) SaveSchedulingReason(job string, reason db.SchedulingReason) error {
+	fake.saveSchedulingReasonMutex.Lock()
+	fake.saveSchedulingReasonArgsForCall = append(fake.saveSchedulingReasonArgsForCall, struct {
+		job    string
+		reason db.SchedulingReason
+	}{job, reason})
+	fake.recordInvocation("SaveSchedulingReason", []interface{}{job, reason})
+	fake.saveSchedulingReasonMutex.Unlock()
+	if fake.SaveSchedulingReasonStub != nil {
+		return fake.SaveSchedulingReasonStub(job, reason)
+	} else {
+		return fake.saveSchedulingReasonReturns.result1
+	}
+}
+
+func (fake *FakePipelineDB) SaveSchedulingReasonCallCount() int {
+	fake.saveSchedulingReasonMutex.RLock()
+	defer fake.saveSchedulingReasonMutex.RUnlock()
+	return len(fake.saveSchedulingReasonArgsForCall)
+}
+
+func (fake *FakePipelineDB) SaveSchedulingReasonArgsForCall(i int) (string, db.SchedulingReason) {
+	fake.saveSchedulingReasonMutex.RLock()
+	defer fake.saveSchedulingReasonMutex.RUnlock()
+	return fake.saveSchedulingReasonArgsForCall[i].job, fake.saveSchedulingReasonArgsForCall[i].reason
+}
+
+func (fake *FakePipelineDB) SaveSchedulingReasonReturns(result1 error) {
+	fake.SaveSchedulingReasonStub = nil
+	fake.saveSchedulingReasonReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePipelineDB) SaveMissingInputReasons(job string, reasons db.MissingInputReasons) error {
+	fake.saveMissingInputReasonsMutex.Lock()
+	fake.saveMissingInputReasonsArgsForCall = append(fake.saveMissingInputReasonsArgsForCall, struct {
+		job     string
+		reasons db.MissingInputReasons
+	}{job, reasons})
+	fake.recordInvocation("SaveMissingInputReasons", []interface{}{job, reasons})
+	fake.saveMissingInputReasonsMutex.Unlock()
+	if fake.SaveMissingInputReasonsStub != nil {
+		return fake.SaveMissingInputReasonsStub(job, reasons)
+	} else {
+		return fake.saveMissingInputReasonsReturns.result1
+	}
+}
+
+func (fake *FakePipelineDB) SaveMissingInputReasonsCallCount() int {
+	fake.saveMissingInputReasonsMutex.RLock()
+	defer fake.saveMissingInputReasonsMutex.RUnlock()
+	return len(fake.saveMissingInputReasonsArgsForCall)
+}
+
+func (fake *FakePipelineDB) SaveMissingInputReasonsArgsForCall(i int) (string, db.MissingInputReasons) {
+	fake.saveMissingInputReasonsMutex.RLock()
+	defer fake.saveMissingInputReasonsMutex.RUnlock()
+	return fake.saveMissingInputReasonsArgsForCall[i].job, fake.saveMissingInputReasonsArgsForCall[i].reasons
+}
+
+func (fake *FakePipelineDB) SaveMissingInputReasonsReturns(result1 error) {
+	fake.SaveMissingInputReasonsStub = nil
+	fake.saveMissingInputReasonsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePipelineDB) GetJobScheduling(job string) (db.JobScheduling, bool, error) {
+	fake.getJobSchedulingMutex.Lock()
+	fake.getJobSchedulingArgsForCall = append(fake.getJobSchedulingArgsForCall, struct {
+		job string
+	}{job})
+	fake.recordInvocation("GetJobScheduling", []interface{}{job})
+	fake.getJobSchedulingMutex.Unlock()
+	if fake.GetJobSchedulingStub != nil {
+		return fake.GetJobSchedulingStub(job)
+	} else {
+		return fake.getJobSchedulingReturns.result1, fake.getJobSchedulingReturns.result2, fake.getJobSchedulingReturns.result3
+	}
+}
+
+func (fake *FakePipelineDB) GetJobSchedulingCallCount() int {
+	fake.getJobSchedulingMutex.RLock()
+	defer fake.getJobSchedulingMutex.RUnlock()
+	return len(fake.getJobSchedulingArgsForCall)
+}
+
+func (fake *FakePipelineDB) GetJobSchedulingArgsForCall(i int) string {
+	fake.getJobSchedulingMutex.RLock()
+	defer fake.getJobSchedulingMutex.RUnlock()
+	return fake.getJobSchedulingArgsForCall[i].job
+}
+
+func (fake *FakePipelineDB) GetJobSchedulingReturns(result1 db.JobScheduling, result2 bool, result3 error) {
+	fake.GetJobSchedulingStub = nil
+	fake.getJobSchedulingReturns = struct {
+		result1 db.JobScheduling
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakePipelineDB) GetVersionsPassedJobs(resourceName string, jobNames []string) ([]db.SavedVersionedResource, bool, error) {
+	var jobNamesCopy []string
+	if jobNames != nil {
+		jobNamesCopy = make([]string, len(jobNames))
+		copy(jobNamesCopy, jobNames)
+	}
+	fake.getVersionsPassedJobsMutex.Lock()
+	fake.getVersionsPassedJobsArgsForCall = append(fake.getVersionsPassedJobsArgsForCall, struct {
+		resourceName string
+		jobNames     []string
+	}{resourceName, jobNamesCopy})
+	fake.recordInvocation("GetVersionsPassedJobs", []interface{}{resourceName, jobNamesCopy})
+	fake.getVersionsPassedJobsMutex.Unlock()
+	if fake.GetVersionsPassedJobsStub != nil {
+		return fake.GetVersionsPassedJobsStub(resourceName, jobNames)
+	} else {
+		return fake.getVersionsPassedJobsReturns.result1, fake.getVersionsPassedJobsReturns.result2, fake.getVersionsPassedJobsReturns.result3
+	}
+}
+
+func (fake *FakePipelineDB) GetVersionsPassedJobsCallCount() int {
+	fake.getVersionsPassedJobsMutex.RLock()
+	defer fake.getVersionsPassedJobsMutex.RUnlock()
+	return len(fake.getVersionsPassedJobsArgsForCall)
+}
+
+func (fake *FakePipelineDB) GetVersionsPassedJobsArgsForCall(i int) (string, []string) {
+	fake.getVersionsPassedJobsMutex.RLock()
+	defer fake.getVersionsPassedJobsMutex.RUnlock()
+	return fake.getVersionsPassedJobsArgsForCall[i].resourceName, fake.getVersionsPassedJobsArgsForCall[i].jobNames
+}
+
+func (fake *FakePipelineDB) GetVersionsPassedJobsReturns(result1 []db.SavedVersionedResource, result2 bool, result3 error) {
+	fake.GetVersionsPassedJobsStub = nil
+	fake.getVersionsPassedJobsReturns = struct {
+		result1 []db.SavedVersionedResource
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakePipelineDB) GetTaskCaches(job string) ([]db.TaskCache, error) {
+	fake.getTaskCachesMutex.Lock()
+	fake.getTaskCachesArgsForCall = append(fake.getTaskCachesArgsForCall, struct {
+		job string
+	}{job})
+	fake.recordInvocation("GetTaskCaches", []interface{}{job})
+	fake.getTaskCachesMutex.Unlock()
+	if fake.GetTaskCachesStub != nil {
+		return fake.GetTaskCachesStub(job)
+	} else {
+		return fake.getTaskCachesReturns.result1, fake.getTaskCachesReturns.result2
+	}
+}
+
+func (fake *FakePipelineDB) GetTaskCachesCallCount() int {
+	fake.getTaskCachesMutex.RLock()
+	defer fake.getTaskCachesMutex.RUnlock()
+	return len(fake.getTaskCachesArgsForCall)
+}
+
+func (fake *FakePipelineDB) GetTaskCachesArgsForCall(i int) string {
+	fake.getTaskCachesMutex.RLock()
+	defer fake.getTaskCachesMutex.RUnlock()
+	return fake.getTaskCachesArgsForCall[i].job
+}
+
+func (fake *FakePipelineDB) GetTaskCachesReturns(result1 []db.TaskCache, result2 error) {
+	fake.GetTaskCachesStub = nil
+	fake.getTaskCachesReturns = struct {
+		result1 []db.TaskCache
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePipelineDB) DeleteTaskCaches(job string, path string) ([]db.TaskCache, error) {
+	fake.deleteTaskCachesMutex.Lock()
+	fake.deleteTaskCachesArgsForCall = append(fake.deleteTaskCachesArgsForCall, struct {
+		job  string
+		path string
+	}{job, path})
+	fake.recordInvocation("DeleteTaskCaches", []interface{}{job, path})
+	fake.deleteTaskCachesMutex.Unlock()
+	if fake.DeleteTaskCachesStub != nil {
+		return fake.DeleteTaskCachesStub(job, path)
+	} else {
+		return fake.deleteTaskCachesReturns.result1, fake.deleteTaskCachesReturns.result2
+	}
+}
+
+func (fake *FakePipelineDB) DeleteTaskCachesCallCount() int {
+	fake.deleteTaskCachesMutex.RLock()
+	defer fake.deleteTaskCachesMutex.RUnlock()
+	return len(fake.deleteTaskCachesArgsForCall)
+}
+
+func (fake *FakePipelineDB) DeleteTaskCachesArgsForCall(i int) (string, string) {
+	fake.deleteTaskCachesMutex.RLock()
+	defer fake.deleteTaskCachesMutex.RUnlock()
+	return fake.deleteTaskCachesArgsForCall[i].job, fake.deleteTaskCachesArgsForCall[i].path
+}
+
+func (fake *FakePipelineDB) DeleteTaskCachesReturns(result1 []db.TaskCache, result2 error) {
+	fake.DeleteTaskCachesStub = nil
+	fake.deleteTaskCachesReturns = struct {
+		result1 []db.TaskCache
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePipelineDB) SetPermissions(permissions atc.PipelinePermissions) error {
+	fake.setPermissionsMutex.Lock()
+	fake.setPermissionsArgsForCall = append(fake.setPermissionsArgsForCall, struct {
+		permissions atc.PipelinePermissions
+	}{permissions})
+	fake.recordInvocation("SetPermissions", []interface{}{permissions})
+	fake.setPermissionsMutex.Unlock()
+	if fake.SetPermissionsStub != nil {
+		return fake.SetPermissionsStub(permissions)
+	} else {
+		return fake.setPermissionsReturns.result1
+	}
+}
+
+func (fake *FakePipelineDB) SetPermissionsCallCount() int {
+	fake.setPermissionsMutex.RLock()
+	defer fake.setPermissionsMutex.RUnlock()
+	return len(fake.setPermissionsArgsForCall)
+}
+
+func (fake *FakePipelineDB) SetPermissionsArgsForCall(i int) atc.PipelinePermissions {
+	fake.setPermissionsMutex.RLock()
+	defer fake.setPermissionsMutex.RUnlock()
+	return fake.setPermissionsArgsForCall[i].permissions
+}
+
+func (fake *FakePipelineDB) SetPermissionsReturns(result1 error) {
+	fake.SetPermissionsStub = nil
+	fake.setPermissionsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePipelineDB) SchedulingNotifier() (db.Notifier, error) {
+	fake.schedulingNotifierMutex.Lock()
+	fake.schedulingNotifierArgsForCall = append(fake.schedulingNotifierArgsForCall, struct{}{})
+	fake.recordInvocation("SchedulingNotifier", []interface{}{})
+	fake.schedulingNotifierMutex.Unlock()
+	if fake.SchedulingNotifierStub != nil {
+		return fake.SchedulingNotifierStub()
+	} else {
+		return fake.schedulingNotifierReturns.result1, fake.schedulingNotifierReturns.result2
+	}
+}
+
+func (fake *FakePipelineDB) SchedulingNotifierCallCount() int {
+	fake.schedulingNotifierMutex.RLock()
+	defer fake.schedulingNotifierMutex.RUnlock()
+	return len(fake.schedulingNotifierArgsForCall)
+}
+
+func (fake *FakePipelineDB) SchedulingNotifierReturns(result1 db.Notifier, result2 error) {
+	fake.SchedulingNotifierStub = nil
+	fake.schedulingNotifierReturns = struct {
+		result1 db.Notifier
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePipelineDB) GetCandidateConfig() (db.CandidateConfig, bool, error) {
+	fake.getCandidateConfigMutex.Lock()
+	fake.getCandidateConfigArgsForCall = append(fake.getCandidateConfigArgsForCall, struct{}{})
+	fake.recordInvocation("GetCandidateConfig", []interface{}{})
+	fake.getCandidateConfigMutex.Unlock()
+	if fake.GetCandidateConfigStub != nil {
+		return fake.GetCandidateConfigStub()
+	} else {
+		return fake.getCandidateConfigReturns.result1, fake.getCandidateConfigReturns.result2, fake.getCandidateConfigReturns.result3
+	}
+}
+
+func (fake *FakePipelineDB) GetCandidateConfigCallCount() int {
+	fake.getCandidateConfigMutex.RLock()
+	defer fake.getCandidateConfigMutex.RUnlock()
+	return len(fake.getCandidateConfigArgsForCall)
+}
+
+func (fake *FakePipelineDB) GetCandidateConfigReturns(result1 db.CandidateConfig, result2 bool, result3 error) {
+	fake.GetCandidateConfigStub = nil
+	fake.getCandidateConfigReturns = struct {
+		result1 db.CandidateConfig
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakePipelineDB) UseCandidateConfigForBuild(buildID int, candidateID int) error {
+	fake.useCandidateConfigForBuildMutex.Lock()
+	fake.useCandidateConfigForBuildArgsForCall = append(fake.useCandidateConfigForBuildArgsForCall, struct {
+		buildID     int
+		candidateID int
+	}{buildID, candidateID})
+	fake.recordInvocation("UseCandidateConfigForBuild", []interface{}{buildID, candidateID})
+	fake.useCandidateConfigForBuildMutex.Unlock()
+	if fake.UseCandidateConfigForBuildStub != nil {
+		return fake.UseCandidateConfigForBuildStub(buildID, candidateID)
+	} else {
+		return fake.useCandidateConfigForBuildReturns.result1
+	}
+}
+
+func (fake *FakePipelineDB) UseCandidateConfigForBuildCallCount() int {
+	fake.useCandidateConfigForBuildMutex.RLock()
+	defer fake.useCandidateConfigForBuildMutex.RUnlock()
+	return len(fake.useCandidateConfigForBuildArgsForCall)
+}
+
+func (fake *FakePipelineDB) UseCandidateConfigForBuildArgsForCall(i int) (int, int) {
+	fake.useCandidateConfigForBuildMutex.RLock()
+	defer fake.useCandidateConfigForBuildMutex.RUnlock()
+	return fake.useCandidateConfigForBuildArgsForCall[i].buildID, fake.useCandidateConfigForBuildArgsForCall[i].candidateID
+}
+
+func (fake *FakePipelineDB) UseCandidateConfigForBuildReturns(result1 error) {
+	fake.UseCandidateConfigForBuildStub = nil
+	fake.useCandidateConfigForBuildReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePipelineDB) GetJobArtifactBuilds(job string) ([]db.ArtifactBuild, error) {
+	fake.getJobArtifactBuildsMutex.Lock()
+	fake.getJobArtifactBuildsArgsForCall = append(fake.getJobArtifactBuildsArgsForCall, struct {
+		job string
+	}{job})
+	fake.recordInvocation("GetJobArtifactBuilds", []interface{}{job})
+	fake.getJobArtifactBuildsMutex.Unlock()
+	if fake.GetJobArtifactBuildsStub != nil {
+		return fake.GetJobArtifactBuildsStub(job)
+	} else {
+		return fake.getJobArtifactBuildsReturns.result1, fake.getJobArtifactBuildsReturns.result2
+	}
+}
+
+func (fake *FakePipelineDB) GetJobArtifactBuildsCallCount() int {
+	fake.getJobArtifactBuildsMutex.RLock()
+	defer fake.getJobArtifactBuildsMutex.RUnlock()
+	return len(fake.getJobArtifactBuildsArgsForCall)
+}
+
+func (fake *FakePipelineDB) GetJobArtifactBuildsArgsForCall(i int) string {
+	fake.getJobArtifactBuildsMutex.RLock()
+	defer fake.getJobArtifactBuildsMutex.RUnlock()
+	return fake.getJobArtifactBuildsArgsForCall[i].job
+}
+
+func (fake *FakePipelineDB) GetJobArtifactBuildsReturns(result1 []db.ArtifactBuild, result2 error) {
+	fake.GetJobArtifactBuildsStub = nil
+	fake.getJobArtifactBuildsReturns = struct {
+		result1 []db.ArtifactBuild
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePipelineDB) SetBuildArtifactsProtected(job string, buildName string, protected bool) (bool, error) {
+	fake.setBuildArtifactsProtectedMutex.Lock()
+	fake.setBuildArtifactsProtectedArgsForCall = append(fake.setBuildArtifactsProtectedArgsForCall, struct {
+		job       string
+		buildName string
+		protected bool
+	}{job, buildName, protected})
+	fake.recordInvocation("SetBuildArtifactsProtected", []interface{}{job, buildName, protected})
+	fake.setBuildArtifactsProtectedMutex.Unlock()
+	if fake.SetBuildArtifactsProtectedStub != nil {
+		return fake.SetBuildArtifactsProtectedStub(job, buildName, protected)
+	} else {
+		return fake.setBuildArtifactsProtectedReturns.result1, fake.setBuildArtifactsProtectedReturns.result2
+	}
+}
+
+func (fake *FakePipelineDB) SetBuildArtifactsProtectedCallCount() int {
+	fake.setBuildArtifactsProtectedMutex.RLock()
+	defer fake.setBuildArtifactsProtectedMutex.RUnlock()
+	return len(fake.setBuildArtifactsProtectedArgsForCall)
+}
+
+func (fake *FakePipelineDB) SetBuildArtifactsProtectedArgsForCall(i int) (string, string, bool) {
+	fake.setBuildArtifactsProtectedMutex.RLock()
+	defer fake.setBuildArtifactsProtectedMutex.RUnlock()
+	return fake.setBuildArtifactsProtectedArgsForCall[i].job, fake.setBuildArtifactsProtectedArgsForCall[i].buildName, fake.setBuildArtifactsProtectedArgsForCall[i].protected
+}
+
+func (fake *FakePipelineDB) SetBuildArtifactsProtectedReturns(result1 bool, result2 error) {
+	fake.SetBuildArtifactsProtectedStub = nil
+	fake.setBuildArtifactsProtectedReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakePipelineDB) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -2582,6 +3829,64 @@ func (fake *FakePipelineDB) Invocations() map[string][][]interface{} {
 	defer fake.exposeMutex.RUnlock()
 	fake.hideMutex.RLock()
 	defer fake.hideMutex.RUnlock()
+	fake.getPendingBuildPrioritiesMutex.RLock()
+	defer fake.getPendingBuildPrioritiesMutex.RUnlock()
+	fake.getJobLastScheduledAtMutex.RLock()
+	defer fake.getJobLastScheduledAtMutex.RUnlock()
+	fake.saveJobLastScheduledAtMutex.RLock()
+	defer fake.saveJobLastScheduledAtMutex.RUnlock()
+	fake.pinResourceVersionMutex.RLock()
+	defer fake.pinResourceVersionMutex.RUnlock()
+	fake.unpinResourceMutex.RLock()
+	defer fake.unpinResourceMutex.RUnlock()
+	fake.makeJobManualOnlyMutex.RLock()
+	defer fake.makeJobManualOnlyMutex.RUnlock()
+	fake.makeJobAutomaticMutex.RLock()
+	defer fake.makeJobAutomaticMutex.RUnlock()
+	fake.getVersionCausalityMutex.RLock()
+	defer fake.getVersionCausalityMutex.RUnlock()
+	fake.getJobLatestFinishedBuildWithInputMutex.RLock()
+	defer fake.getJobLatestFinishedBuildWithInputMutex.RUnlock()
+	fake.getAllResourceVersionsMutex.RLock()
+	defer fake.getAllResourceVersionsMutex.RUnlock()
+	fake.importResourceVersionsMutex.RLock()
+	defer fake.importResourceVersionsMutex.RUnlock()
+	fake.importJobBuildMutex.RLock()
+	defer fake.importJobBuildMutex.RUnlock()
+	fake.getJobBuildStatsMutex.RLock()
+	defer fake.getJobBuildStatsMutex.RUnlock()
+	fake.getJobOutcomesMutex.RLock()
+	defer fake.getJobOutcomesMutex.RUnlock()
+	fake.saveJobFlakinessMutex.RLock()
+	defer fake.saveJobFlakinessMutex.RUnlock()
+	fake.createJobBuildWithParamsMutex.RLock()
+	defer fake.createJobBuildWithParamsMutex.RUnlock()
+	fake.getRegisteredResourceTypesMutex.RLock()
+	defer fake.getRegisteredResourceTypesMutex.RUnlock()
+	fake.saveSchedulingReasonMutex.RLock()
+	defer fake.saveSchedulingReasonMutex.RUnlock()
+	fake.saveMissingInputReasonsMutex.RLock()
+	defer fake.saveMissingInputReasonsMutex.RUnlock()
+	fake.getJobSchedulingMutex.RLock()
+	defer fake.getJobSchedulingMutex.RUnlock()
+	fake.getVersionsPassedJobsMutex.RLock()
+	defer fake.getVersionsPassedJobsMutex.RUnlock()
+	fake.getTaskCachesMutex.RLock()
+	defer fake.getTaskCachesMutex.RUnlock()
+	fake.deleteTaskCachesMutex.RLock()
+	defer fake.deleteTaskCachesMutex.RUnlock()
+	fake.setPermissionsMutex.RLock()
+	defer fake.setPermissionsMutex.RUnlock()
+	fake.schedulingNotifierMutex.RLock()
+	defer fake.schedulingNotifierMutex.RUnlock()
+	fake.getCandidateConfigMutex.RLock()
+	defer fake.getCandidateConfigMutex.RUnlock()
+	fake.useCandidateConfigForBuildMutex.RLock()
+	defer fake.useCandidateConfigForBuildMutex.RUnlock()
+	fake.getJobArtifactBuildsMutex.RLock()
+	defer fake.getJobArtifactBuildsMutex.RUnlock()
+	fake.setBuildArtifactsProtectedMutex.RLock()
+	defer fake.setBuildArtifactsProtectedMutex.RUnlock()
 	return fake.invocations
 }
 
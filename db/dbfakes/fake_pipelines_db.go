@@ -2,6 +2,7 @@
 package dbfakes
 
 import (
+	"context"
 	"sync"
 
 	"github.com/concourse/atc/db"
@@ -15,6 +16,32 @@ type FakePipelinesDB struct {
 		result1 []db.SavedPipeline
 		result2 error
 	}
+	GetPublicPipelineDashboardsStub        func() ([]db.PipelineDashboard, error)
+	getPublicPipelineDashboardsMutex       sync.RWMutex
+	getPublicPipelineDashboardsArgsForCall []struct{}
+	getPublicPipelineDashboardsReturns     struct {
+		result1 []db.PipelineDashboard
+		result2 error
+	}
+	GetPipelineWeightsStub        func(ctx context.Context) ([]db.PipelineWeight, error)
+	getPipelineWeightsMutex       sync.RWMutex
+	getPipelineWeightsArgsForCall []struct {
+		ctx context.Context
+	}
+	getPipelineWeightsReturns struct {
+		result1 []db.PipelineWeight
+		result2 error
+	}
+	SetPipelineWeightStub        func(ctx context.Context, pipelineID int, weight int) error
+	setPipelineWeightMutex       sync.RWMutex
+	setPipelineWeightArgsForCall []struct {
+		ctx        context.Context
+		pipelineID int
+		weight     int
+	}
+	setPipelineWeightReturns struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -45,11 +72,112 @@ func (fake *FakePipelinesDB) GetAllPublicPipelinesReturns(result1 []db.SavedPipe
 	}{result1, result2}
 }
 
+func (fake *FakePipelinesDB) GetPublicPipelineDashboards() ([]db.PipelineDashboard, error) {
+	fake.getPublicPipelineDashboardsMutex.Lock()
+	fake.getPublicPipelineDashboardsArgsForCall = append(fake.getPublicPipelineDashboardsArgsForCall, struct{}{})
+	fake.recordInvocation("GetPublicPipelineDashboards", []interface{}{})
+	fake.getPublicPipelineDashboardsMutex.Unlock()
+	if fake.GetPublicPipelineDashboardsStub != nil {
+		return fake.GetPublicPipelineDashboardsStub()
+	} else {
+		return fake.getPublicPipelineDashboardsReturns.result1, fake.getPublicPipelineDashboardsReturns.result2
+	}
+}
+
+func (fake *FakePipelinesDB) GetPublicPipelineDashboardsCallCount() int {
+	fake.getPublicPipelineDashboardsMutex.RLock()
+	defer fake.getPublicPipelineDashboardsMutex.RUnlock()
+	return len(fake.getPublicPipelineDashboardsArgsForCall)
+}
+
+func (fake *FakePipelinesDB) GetPublicPipelineDashboardsReturns(result1 []db.PipelineDashboard, result2 error) {
+	fake.GetPublicPipelineDashboardsStub = nil
+	fake.getPublicPipelineDashboardsReturns = struct {
+		result1 []db.PipelineDashboard
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePipelinesDB) GetPipelineWeights(ctx context.Context) ([]db.PipelineWeight, error) {
+	fake.getPipelineWeightsMutex.Lock()
+	fake.getPipelineWeightsArgsForCall = append(fake.getPipelineWeightsArgsForCall, struct {
+		ctx context.Context
+	}{ctx})
+	fake.recordInvocation("GetPipelineWeights", []interface{}{ctx})
+	fake.getPipelineWeightsMutex.Unlock()
+	if fake.GetPipelineWeightsStub != nil {
+		return fake.GetPipelineWeightsStub(ctx)
+	} else {
+		return fake.getPipelineWeightsReturns.result1, fake.getPipelineWeightsReturns.result2
+	}
+}
+
+func (fake *FakePipelinesDB) GetPipelineWeightsCallCount() int {
+	fake.getPipelineWeightsMutex.RLock()
+	defer fake.getPipelineWeightsMutex.RUnlock()
+	return len(fake.getPipelineWeightsArgsForCall)
+}
+
+func (fake *FakePipelinesDB) GetPipelineWeightsArgsForCall(i int) context.Context {
+	fake.getPipelineWeightsMutex.RLock()
+	defer fake.getPipelineWeightsMutex.RUnlock()
+	return fake.getPipelineWeightsArgsForCall[i].ctx
+}
+
+func (fake *FakePipelinesDB) GetPipelineWeightsReturns(result1 []db.PipelineWeight, result2 error) {
+	fake.GetPipelineWeightsStub = nil
+	fake.getPipelineWeightsReturns = struct {
+		result1 []db.PipelineWeight
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePipelinesDB) SetPipelineWeight(ctx context.Context, pipelineID int, weight int) error {
+	fake.setPipelineWeightMutex.Lock()
+	fake.setPipelineWeightArgsForCall = append(fake.setPipelineWeightArgsForCall, struct {
+		ctx        context.Context
+		pipelineID int
+		weight     int
+	}{ctx, pipelineID, weight})
+	fake.recordInvocation("SetPipelineWeight", []interface{}{ctx, pipelineID, weight})
+	fake.setPipelineWeightMutex.Unlock()
+	if fake.SetPipelineWeightStub != nil {
+		return fake.SetPipelineWeightStub(ctx, pipelineID, weight)
+	} else {
+		return fake.setPipelineWeightReturns.result1
+	}
+}
+
+func (fake *FakePipelinesDB) SetPipelineWeightCallCount() int {
+	fake.setPipelineWeightMutex.RLock()
+	defer fake.setPipelineWeightMutex.RUnlock()
+	return len(fake.setPipelineWeightArgsForCall)
+}
+
+func (fake *FakePipelinesDB) SetPipelineWeightArgsForCall(i int) (context.Context, int, int) {
+	fake.setPipelineWeightMutex.RLock()
+	defer fake.setPipelineWeightMutex.RUnlock()
+	return fake.setPipelineWeightArgsForCall[i].ctx, fake.setPipelineWeightArgsForCall[i].pipelineID, fake.setPipelineWeightArgsForCall[i].weight
+}
+
+func (fake *FakePipelinesDB) SetPipelineWeightReturns(result1 error) {
+	fake.SetPipelineWeightStub = nil
+	fake.setPipelineWeightReturns = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakePipelinesDB) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.getAllPublicPipelinesMutex.RLock()
 	defer fake.getAllPublicPipelinesMutex.RUnlock()
+	fake.getPublicPipelineDashboardsMutex.RLock()
+	defer fake.getPublicPipelineDashboardsMutex.RUnlock()
+	fake.getPipelineWeightsMutex.RLock()
+	defer fake.getPipelineWeightsMutex.RUnlock()
+	fake.setPipelineWeightMutex.RLock()
+	defer fake.setPipelineWeightMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -3,6 +3,7 @@ package dbfakes
 
 import (
 	"sync"
+	"time"
 
 	"github.com/concourse/atc"
 	"github.com/concourse/atc/db"
@@ -103,13 +104,14 @@ type FakeTeamDB struct {
 		result3 db.ConfigVersion
 		result4 error
 	}
-	SaveConfigStub        func(string, atc.Config, db.ConfigVersion, db.PipelinePausedState) (db.SavedPipeline, bool, error)
+	SaveConfigStub        func(pipelineName string, config atc.Config, from db.ConfigVersion, pausedState db.PipelinePausedState, author string) (db.SavedPipeline, bool, error)
 	saveConfigMutex       sync.RWMutex
 	saveConfigArgsForCall []struct {
-		arg1 string
-		arg2 atc.Config
-		arg3 db.ConfigVersion
-		arg4 db.PipelinePausedState
+		pipelineName string
+		config       atc.Config
+		from         db.ConfigVersion
+		pausedState  db.PipelinePausedState
+		author       string
 	}
 	saveConfigReturns struct {
 		result1 db.SavedPipeline
@@ -123,10 +125,11 @@ type FakeTeamDB struct {
 		result1 db.Build
 		result2 error
 	}
-	GetPrivateAndPublicBuildsStub        func(page db.Page) ([]db.Build, db.Pagination, error)
+	GetPrivateAndPublicBuildsStub        func(page db.Page, filter db.BuildFilter) ([]db.Build, db.Pagination, error)
 	getPrivateAndPublicBuildsMutex       sync.RWMutex
 	getPrivateAndPublicBuildsArgsForCall []struct {
-		page db.Page
+		page   db.Page
+		filter db.BuildFilter
 	}
 	getPrivateAndPublicBuildsReturns struct {
 		result1 []db.Build
@@ -166,6 +169,208 @@ type FakeTeamDB struct {
 		result1 []db.SavedVolume
 		result2 error
 	}
+	SearchBuildLogsStub        func(query string, limit int, hiddenPipelineIDs []int) ([]db.LogMatch, error)
+	searchBuildLogsMutex       sync.RWMutex
+	searchBuildLogsArgsForCall []struct {
+		query             string
+		limit             int
+		hiddenPipelineIDs []int
+	}
+	searchBuildLogsReturns struct {
+		result1 []db.LogMatch
+		result2 error
+	}
+	GetBuildsStub        func(page db.Page, filter db.BuildFilter) ([]db.Build, db.Pagination, error)
+	getBuildsMutex       sync.RWMutex
+	getBuildsArgsForCall []struct {
+		page   db.Page
+		filter db.BuildFilter
+	}
+	getBuildsReturns struct {
+		result1 []db.Build
+		result2 db.Pagination
+		result3 error
+	}
+	CreateRerunBuildStub        func(original db.Build) (db.Build, error)
+	createRerunBuildMutex       sync.RWMutex
+	createRerunBuildArgsForCall []struct {
+		original db.Build
+	}
+	createRerunBuildReturns struct {
+		result1 db.Build
+		result2 error
+	}
+	GetConfigRevisionsStub        func(pipelineName string) ([]db.ConfigRevision, bool, error)
+	getConfigRevisionsMutex       sync.RWMutex
+	getConfigRevisionsArgsForCall []struct {
+		pipelineName string
+	}
+	getConfigRevisionsReturns struct {
+		result1 []db.ConfigRevision
+		result2 bool
+		result3 error
+	}
+	RevertConfigStub        func(pipelineName string, to db.ConfigVersion, author string) (db.SavedPipeline, bool, error)
+	revertConfigMutex       sync.RWMutex
+	revertConfigArgsForCall []struct {
+		pipelineName string
+		to           db.ConfigVersion
+		author       string
+	}
+	revertConfigReturns struct {
+		result1 db.SavedPipeline
+		result2 bool
+		result3 error
+	}
+	UpdateRolesStub        func(roles atc.TeamRoles) (db.SavedTeam, error)
+	updateRolesMutex       sync.RWMutex
+	updateRolesArgsForCall []struct {
+		roles atc.TeamRoles
+	}
+	updateRolesReturns struct {
+		result1 db.SavedTeam
+		result2 error
+	}
+	UpdateOIDCAuthStub        func(oidcAuth *db.OIDCAuth) (db.SavedTeam, error)
+	updateOIDCAuthMutex       sync.RWMutex
+	updateOIDCAuthArgsForCall []struct {
+		oidcAuth *db.OIDCAuth
+	}
+	updateOIDCAuthReturns struct {
+		result1 db.SavedTeam
+		result2 error
+	}
+	GetPipelineInstancesStub        func(templateName string) ([]db.SavedPipeline, bool, error)
+	getPipelineInstancesMutex       sync.RWMutex
+	getPipelineInstancesArgsForCall []struct {
+		templateName string
+	}
+	getPipelineInstancesReturns struct {
+		result1 []db.SavedPipeline
+		result2 bool
+		result3 error
+	}
+	SavePipelineInstanceStub        func(templateName string, instanceName string, vars map[string]interface{}, author string) (db.SavedPipeline, bool, error)
+	savePipelineInstanceMutex       sync.RWMutex
+	savePipelineInstanceArgsForCall []struct {
+		templateName string
+		instanceName string
+		vars         map[string]interface{}
+		author       string
+	}
+	savePipelineInstanceReturns struct {
+		result1 db.SavedPipeline
+		result2 bool
+		result3 error
+	}
+	DestroyPipelineInstanceStub        func(templateName string, instanceName string) (bool, error)
+	destroyPipelineInstanceMutex       sync.RWMutex
+	destroyPipelineInstanceArgsForCall []struct {
+		templateName string
+		instanceName string
+	}
+	destroyPipelineInstanceReturns struct {
+		result1 bool
+		result2 error
+	}
+	GetPipelineDashboardsStub        func() ([]db.PipelineDashboard, error)
+	getPipelineDashboardsMutex       sync.RWMutex
+	getPipelineDashboardsArgsForCall []struct{}
+	getPipelineDashboardsReturns     struct {
+		result1 []db.PipelineDashboard
+		result2 error
+	}
+	UpdateSAMLAuthStub        func(samlAuth *db.SAMLAuth) (db.SavedTeam, error)
+	updateSAMLAuthMutex       sync.RWMutex
+	updateSAMLAuthArgsForCall []struct {
+		samlAuth *db.SAMLAuth
+	}
+	updateSAMLAuthReturns struct {
+		result1 db.SavedTeam
+		result2 error
+	}
+	SaveCandidateConfigStub        func(pipelineName string, config atc.Config, percentage int, author string) (bool, error)
+	saveCandidateConfigMutex       sync.RWMutex
+	saveCandidateConfigArgsForCall []struct {
+		pipelineName string
+		config       atc.Config
+		percentage   int
+		author       string
+	}
+	saveCandidateConfigReturns struct {
+		result1 bool
+		result2 error
+	}
+	GetCandidateConfigStub        func(pipelineName string) (db.CandidateConfig, bool, error)
+	getCandidateConfigMutex       sync.RWMutex
+	getCandidateConfigArgsForCall []struct {
+		pipelineName string
+	}
+	getCandidateConfigReturns struct {
+		result1 db.CandidateConfig
+		result2 bool
+		result3 error
+	}
+	DeleteCandidateConfigStub        func(pipelineName string) (bool, error)
+	deleteCandidateConfigMutex       sync.RWMutex
+	deleteCandidateConfigArgsForCall []struct {
+		pipelineName string
+	}
+	deleteCandidateConfigReturns struct {
+		result1 bool
+		result2 error
+	}
+	PromoteCandidateConfigStub        func(pipelineName string, author string) (db.SavedPipeline, bool, error)
+	promoteCandidateConfigMutex       sync.RWMutex
+	promoteCandidateConfigArgsForCall []struct {
+		pipelineName string
+		author       string
+	}
+	promoteCandidateConfigReturns struct {
+		result1 db.SavedPipeline
+		result2 bool
+		result3 error
+	}
+	GetCandidateComparisonStub        func(pipelineName string) ([]db.CandidateComparison, bool, error)
+	getCandidateComparisonMutex       sync.RWMutex
+	getCandidateComparisonArgsForCall []struct {
+		pipelineName string
+	}
+	getCandidateComparisonReturns struct {
+		result1 []db.CandidateComparison
+		result2 bool
+		result3 error
+	}
+	SaveSAMLRequestStub        func(id string, redirect string, expiresAt time.Time) error
+	saveSAMLRequestMutex       sync.RWMutex
+	saveSAMLRequestArgsForCall []struct {
+		id        string
+		redirect  string
+		expiresAt time.Time
+	}
+	saveSAMLRequestReturns struct {
+		result1 error
+	}
+	TakeSAMLRequestStub        func(id string) (string, bool, error)
+	takeSAMLRequestMutex       sync.RWMutex
+	takeSAMLRequestArgsForCall []struct {
+		id string
+	}
+	takeSAMLRequestReturns struct {
+		result1 string
+		result2 bool
+		result3 error
+	}
+	SaveSAMLAssertionStub        func(id string, expiresAt time.Time) (bool, error)
+	saveSAMLAssertionMutex       sync.RWMutex
+	saveSAMLAssertionArgsForCall []struct {
+		id        string
+		expiresAt time.Time
+	}
+	saveSAMLAssertionReturns struct {
+		result1 bool
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -520,18 +725,19 @@ func (fake *FakeTeamDB) GetConfigReturns(result1 atc.Config, result2 atc.RawConf
 	}{result1, result2, result3, result4}
 }
 
-func (fake *FakeTeamDB) SaveConfig(arg1 string, arg2 atc.Config, arg3 db.ConfigVersion, arg4 db.PipelinePausedState) (db.SavedPipeline, bool, error) {
+func (fake *FakeTeamDB) SaveConfig(pipelineName string, config atc.Config, from db.ConfigVersion, pausedState db.PipelinePausedState, author string) (db.SavedPipeline, bool, error) {
 	fake.saveConfigMutex.Lock()
 	fake.saveConfigArgsForCall = append(fake.saveConfigArgsForCall, struct {
-		arg1 string
-		arg2 atc.Config
-		arg3 db.ConfigVersion
-		arg4 db.PipelinePausedState
-	}{arg1, arg2, arg3, arg4})
-	fake.recordInvocation("SaveConfig", []interface{}{arg1, arg2, arg3, arg4})
+		pipelineName string
+		config       atc.Config
+		from         db.ConfigVersion
+		pausedState  db.PipelinePausedState
+		author       string
+	}{pipelineName, config, from, pausedState, author})
+	fake.recordInvocation("SaveConfig", []interface{}{pipelineName, config, from, pausedState, author})
 	fake.saveConfigMutex.Unlock()
 	if fake.SaveConfigStub != nil {
-		return fake.SaveConfigStub(arg1, arg2, arg3, arg4)
+		return fake.SaveConfigStub(pipelineName, config, from, pausedState, author)
 	} else {
 		return fake.saveConfigReturns.result1, fake.saveConfigReturns.result2, fake.saveConfigReturns.result3
 	}
@@ -543,10 +749,10 @@ func (fake *FakeTeamDB) SaveConfigCallCount() int {
 	return len(fake.saveConfigArgsForCall)
 }
 
-func (fake *FakeTeamDB) SaveConfigArgsForCall(i int) (string, atc.Config, db.ConfigVersion, db.PipelinePausedState) {
+func (fake *FakeTeamDB) SaveConfigArgsForCall(i int) (string, atc.Config, db.ConfigVersion, db.PipelinePausedState, string) {
 	fake.saveConfigMutex.RLock()
 	defer fake.saveConfigMutex.RUnlock()
-	return fake.saveConfigArgsForCall[i].arg1, fake.saveConfigArgsForCall[i].arg2, fake.saveConfigArgsForCall[i].arg3, fake.saveConfigArgsForCall[i].arg4
+	return fake.saveConfigArgsForCall[i].pipelineName, fake.saveConfigArgsForCall[i].config, fake.saveConfigArgsForCall[i].from, fake.saveConfigArgsForCall[i].pausedState, fake.saveConfigArgsForCall[i].author
 }
 
 func (fake *FakeTeamDB) SaveConfigReturns(result1 db.SavedPipeline, result2 bool, result3 error) {
@@ -584,15 +790,16 @@ func (fake *FakeTeamDB) CreateOneOffBuildReturns(result1 db.Build, result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeTeamDB) GetPrivateAndPublicBuilds(page db.Page) ([]db.Build, db.Pagination, error) {
+func (fake *FakeTeamDB) GetPrivateAndPublicBuilds(page db.Page, filter db.BuildFilter) ([]db.Build, db.Pagination, error) {
 	fake.getPrivateAndPublicBuildsMutex.Lock()
 	fake.getPrivateAndPublicBuildsArgsForCall = append(fake.getPrivateAndPublicBuildsArgsForCall, struct {
-		page db.Page
-	}{page})
-	fake.recordInvocation("GetPrivateAndPublicBuilds", []interface{}{page})
+		page   db.Page
+		filter db.BuildFilter
+	}{page, filter})
+	fake.recordInvocation("GetPrivateAndPublicBuilds", []interface{}{page, filter})
 	fake.getPrivateAndPublicBuildsMutex.Unlock()
 	if fake.GetPrivateAndPublicBuildsStub != nil {
-		return fake.GetPrivateAndPublicBuildsStub(page)
+		return fake.GetPrivateAndPublicBuildsStub(page, filter)
 	} else {
 		return fake.getPrivateAndPublicBuildsReturns.result1, fake.getPrivateAndPublicBuildsReturns.result2, fake.getPrivateAndPublicBuildsReturns.result3
 	}
@@ -604,10 +811,10 @@ func (fake *FakeTeamDB) GetPrivateAndPublicBuildsCallCount() int {
 	return len(fake.getPrivateAndPublicBuildsArgsForCall)
 }
 
-func (fake *FakeTeamDB) GetPrivateAndPublicBuildsArgsForCall(i int) db.Page {
+func (fake *FakeTeamDB) GetPrivateAndPublicBuildsArgsForCall(i int) (db.Page, db.BuildFilter) {
 	fake.getPrivateAndPublicBuildsMutex.RLock()
 	defer fake.getPrivateAndPublicBuildsMutex.RUnlock()
-	return fake.getPrivateAndPublicBuildsArgsForCall[i].page
+	return fake.getPrivateAndPublicBuildsArgsForCall[i].page, fake.getPrivateAndPublicBuildsArgsForCall[i].filter
 }
 
 func (fake *FakeTeamDB) GetPrivateAndPublicBuildsReturns(result1 []db.Build, result2 db.Pagination, result3 error) {
@@ -740,6 +947,707 @@ func (fake *FakeTeamDB) GetVolumesReturns(result1 []db.SavedVolume, result2 erro
 	}{result1, result2}
 }
 
+func (fake *FakeTeamDB) SearchBuildLogs(query string, limit int, hiddenPipelineIDs []int) ([]db.LogMatch, error) {
+	var hiddenPipelineIDsCopy []int
+	if hiddenPipelineIDs != nil {
+		hiddenPipelineIDsCopy = make([]int, len(hiddenPipelineIDs))
+		copy(hiddenPipelineIDsCopy, hiddenPipelineIDs)
+	}
+	fake.searchBuildLogsMutex.Lock()
+	fake.searchBuildLogsArgsForCall = append(fake.searchBuildLogsArgsForCall, struct {
+		query             string
+		limit             int
+		hiddenPipelineIDs []int
+	}{query, limit, hiddenPipelineIDsCopy})
+	fake.recordInvocation("SearchBuildLogs", []interface{}{query, limit, hiddenPipelineIDsCopy})
+	fake.searchBuildLogsMutex.Unlock()
+	if fake.SearchBuildLogsStub != nil {
+		return fake.SearchBuildLogsStub(query, limit, hiddenPipelineIDs)
+	} else {
+		return fake.searchBuildLogsReturns.result1, fake.searchBuildLogsReturns.result2
+	}
+}
+
+func (fake *FakeTeamDB) SearchBuildLogsCallCount() int {
+	fake.searchBuildLogsMutex.RLock()
+	defer fake.searchBuildLogsMutex.RUnlock()
+	return len(fake.searchBuildLogsArgsForCall)
+}
+
+func (fake *FakeTeamDB) SearchBuildLogsArgsForCall(i int) (string, int, []int) {
+	fake.searchBuildLogsMutex.RLock()
+	defer fake.searchBuildLogsMutex.RUnlock()
+	return fake.searchBuildLogsArgsForCall[i].query, fake.searchBuildLogsArgsForCall[i].limit, fake.searchBuildLogsArgsForCall[i].hiddenPipelineIDs
+}
+
+func (fake *FakeTeamDB) SearchBuildLogsReturns(result1 []db.LogMatch, result2 error) {
+	fake.SearchBuildLogsStub = nil
+	fake.searchBuildLogsReturns = struct {
+		result1 []db.LogMatch
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeTeamDB) GetBuilds(page db.Page, filter db.BuildFilter) ([]db.Build, db.Pagination, error) {
+	fake.getBuildsMutex.Lock()
+	fake.getBuildsArgsForCall = append(fake.getBuildsArgsForCall, struct {
+		page   db.Page
+		filter db.BuildFilter
+	}{page, filter})
+	fake.recordInvocation("GetBuilds", []interface{}{page, filter})
+	fake.getBuildsMutex.Unlock()
+	if fake.GetBuildsStub != nil {
+		return fake.GetBuildsStub(page, filter)
+	} else {
+		return fake.getBuildsReturns.result1, fake.getBuildsReturns.result2, fake.getBuildsReturns.result3
+	}
+}
+
+func (fake *FakeTeamDB) GetBuildsCallCount() int {
+	fake.getBuildsMutex.RLock()
+	defer fake.getBuildsMutex.RUnlock()
+	return len(fake.getBuildsArgsForCall)
+}
+
+func (fake *FakeTeamDB) GetBuildsArgsForCall(i int) (db.Page, db.BuildFilter) {
+	fake.getBuildsMutex.RLock()
+	defer fake.getBuildsMutex.RUnlock()
+	return fake.getBuildsArgsForCall[i].page, fake.getBuildsArgsForCall[i].filter
+}
+
+func (fake *FakeTeamDB) GetBuildsReturns(result1 []db.Build, result2 db.Pagination, result3 error) {
+	fake.GetBuildsStub = nil
+	fake.getBuildsReturns = struct {
+		result1 []db.Build
+		result2 db.Pagination
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeTeamDB) CreateRerunBuild(original db.Build) (db.Build, error) {
+	fake.createRerunBuildMutex.Lock()
+	fake.createRerunBuildArgsForCall = append(fake.createRerunBuildArgsForCall, struct {
+		original db.Build
+	}{original})
+	fake.recordInvocation("CreateRerunBuild", []interface{}{original})
+	fake.createRerunBuildMutex.Unlock()
+	if fake.CreateRerunBuildStub != nil {
+		return fake.CreateRerunBuildStub(original)
+	} else {
+		return fake.createRerunBuildReturns.result1, fake.createRerunBuildReturns.result2
+	}
+}
+
+func (fake *FakeTeamDB) CreateRerunBuildCallCount() int {
+	fake.createRerunBuildMutex.RLock()
+	defer fake.createRerunBuildMutex.RUnlock()
+	return len(fake.createRerunBuildArgsForCall)
+}
+
+func (fake *FakeTeamDB) CreateRerunBuildArgsForCall(i int) db.Build {
+	fake.createRerunBuildMutex.RLock()
+	defer fake.createRerunBuildMutex.RUnlock()
+	return fake.createRerunBuildArgsForCall[i].original
+}
+
+func (fake *FakeTeamDB) CreateRerunBuildReturns(result1 db.Build, result2 error) {
+	fake.CreateRerunBuildStub = nil
+	fake.createRerunBuildReturns = struct {
+		result1 db.Build
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeTeamDB) GetConfigRevisions(pipelineName string) ([]db.ConfigRevision, bool, error) {
+	fake.getConfigRevisionsMutex.Lock()
+	fake.getConfigRevisionsArgsForCall = append(fake.getConfigRevisionsArgsForCall, struct {
+		pipelineName string
+	}{pipelineName})
+	fake.recordInvocation("GetConfigRevisions", []interface{}{pipelineName})
+	fake.getConfigRevisionsMutex.Unlock()
+	if fake.GetConfigRevisionsStub != nil {
+		return fake.GetConfigRevisionsStub(pipelineName)
+	} else {
+		return fake.getConfigRevisionsReturns.result1, fake.getConfigRevisionsReturns.result2, fake.getConfigRevisionsReturns.result3
+	}
+}
+
+func (fake *FakeTeamDB) GetConfigRevisionsCallCount() int {
+	fake.getConfigRevisionsMutex.RLock()
+	defer fake.getConfigRevisionsMutex.RUnlock()
+	return len(fake.getConfigRevisionsArgsForCall)
+}
+
+func (fake *FakeTeamDB) GetConfigRevisionsArgsForCall(i int) string {
+	fake.getConfigRevisionsMutex.RLock()
+	defer fake.getConfigRevisionsMutex.RUnlock()
+	return fake.getConfigRevisionsArgsForCall[i].pipelineName
+}
+
+func (fake *FakeTeamDB) GetConfigRevisionsReturns(result1 []db.ConfigRevision, result2 bool, result3 error) {
+	fake.GetConfigRevisionsStub = nil
+	fake.getConfigRevisionsReturns = struct {
+		result1 []db.ConfigRevision
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeTeamDB) RevertConfig(pipelineName string, to db.ConfigVersion, author string) (db.SavedPipeline, bool, error) {
+	fake.revertConfigMutex.Lock()
+	fake.revertConfigArgsForCall = append(fake.revertConfigArgsForCall, struct {
+		pipelineName string
+		to           db.ConfigVersion
+		author       string
+	}{pipelineName, to, author})
+	fake.recordInvocation("RevertConfig", []interface{}{pipelineName, to, author})
+	fake.revertConfigMutex.Unlock()
+	if fake.RevertConfigStub != nil {
+		return fake.RevertConfigStub(pipelineName, to, author)
+	} else {
+		return fake.revertConfigReturns.result1, fake.revertConfigReturns.result2, fake.revertConfigReturns.result3
+	}
+}
+
+func (fake *FakeTeamDB) RevertConfigCallCount() int {
+	fake.revertConfigMutex.RLock()
+	defer fake.revertConfigMutex.RUnlock()
+	return len(fake.revertConfigArgsForCall)
+}
+
+func (fake *FakeTeamDB) RevertConfigArgsForCall(i int) (string, db.ConfigVersion, string) {
+	fake.revertConfigMutex.RLock()
+	defer fake.revertConfigMutex.RUnlock()
+	return fake.revertConfigArgsForCall[i].pipelineName, fake.revertConfigArgsForCall[i].to, fake.revertConfigArgsForCall[i].author
+}
+
+func (fake *FakeTeamDB) RevertConfigReturns(result1 db.SavedPipeline, result2 bool, result3 error) {
+	fake.RevertConfigStub = nil
+	fake.revertConfigReturns = struct {
+		result1 db.SavedPipeline
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeTeamDB) UpdateRoles(roles atc.TeamRoles) (db.SavedTeam, error) {
+	fake.updateRolesMutex.Lock()
+	fake.updateRolesArgsForCall = append(fake.updateRolesArgsForCall, struct {
+		roles atc.TeamRoles
+	}{roles})
+	fake.recordInvocation("UpdateRoles", []interface{}{roles})
+	fake.updateRolesMutex.Unlock()
+	if fake.UpdateRolesStub != nil {
+		return fake.UpdateRolesStub(roles)
+	} else {
+		return fake.updateRolesReturns.result1, fake.updateRolesReturns.result2
+	}
+}
+
+func (fake *FakeTeamDB) UpdateRolesCallCount() int {
+	fake.updateRolesMutex.RLock()
+	defer fake.updateRolesMutex.RUnlock()
+	return len(fake.updateRolesArgsForCall)
+}
+
+func (fake *FakeTeamDB) UpdateRolesArgsForCall(i int) atc.TeamRoles {
+	fake.updateRolesMutex.RLock()
+	defer fake.updateRolesMutex.RUnlock()
+	return fake.updateRolesArgsForCall[i].roles
+}
+
+func (fake *FakeTeamDB) UpdateRolesReturns(result1 db.SavedTeam, result2 error) {
+	fake.UpdateRolesStub = nil
+	fake.updateRolesReturns = struct {
+		result1 db.SavedTeam
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeTeamDB) UpdateOIDCAuth(oidcAuth *db.OIDCAuth) (db.SavedTeam, error) {
+	fake.updateOIDCAuthMutex.Lock()
+	fake.updateOIDCAuthArgsForCall = append(fake.updateOIDCAuthArgsForCall, struct {
+		oidcAuth *db.OIDCAuth
+	}{oidcAuth})
+	fake.recordInvocation("UpdateOIDCAuth", []interface{}{oidcAuth})
+	fake.updateOIDCAuthMutex.Unlock()
+	if fake.UpdateOIDCAuthStub != nil {
+		return fake.UpdateOIDCAuthStub(oidcAuth)
+	} else {
+		return fake.updateOIDCAuthReturns.result1, fake.updateOIDCAuthReturns.result2
+	}
+}
+
+func (fake *FakeTeamDB) UpdateOIDCAuthCallCount() int {
+	fake.updateOIDCAuthMutex.RLock()
+	defer fake.updateOIDCAuthMutex.RUnlock()
+	return len(fake.updateOIDCAuthArgsForCall)
+}
+
+func (fake *FakeTeamDB) UpdateOIDCAuthArgsForCall(i int) *db.OIDCAuth {
+	fake.updateOIDCAuthMutex.RLock()
+	defer fake.updateOIDCAuthMutex.RUnlock()
+	return fake.updateOIDCAuthArgsForCall[i].oidcAuth
+}
+
+func (fake *FakeTeamDB) UpdateOIDCAuthReturns(result1 db.SavedTeam, result2 error) {
+	fake.UpdateOIDCAuthStub = nil
+	fake.updateOIDCAuthReturns = struct {
+		result1 db.SavedTeam
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeTeamDB) GetPipelineInstances(templateName string) ([]db.SavedPipeline, bool, error) {
+	fake.getPipelineInstancesMutex.Lock()
+	fake.getPipelineInstancesArgsForCall = append(fake.getPipelineInstancesArgsForCall, struct {
+		templateName string
+	}{templateName})
+	fake.recordInvocation("GetPipelineInstances", []interface{}{templateName})
+	fake.getPipelineInstancesMutex.Unlock()
+	if fake.GetPipelineInstancesStub != nil {
+		return fake.GetPipelineInstancesStub(templateName)
+	} else {
+		return fake.getPipelineInstancesReturns.result1, fake.getPipelineInstancesReturns.result2, fake.getPipelineInstancesReturns.result3
+	}
+}
+
+func (fake *FakeTeamDB) GetPipelineInstancesCallCount() int {
+	fake.getPipelineInstancesMutex.RLock()
+	defer fake.getPipelineInstancesMutex.RUnlock()
+	return len(fake.getPipelineInstancesArgsForCall)
+}
+
+func (fake *FakeTeamDB) GetPipelineInstancesArgsForCall(i int) string {
+	fake.getPipelineInstancesMutex.RLock()
+	defer fake.getPipelineInstancesMutex.RUnlock()
+	return fake.getPipelineInstancesArgsForCall[i].templateName
+}
+
+func (fake *FakeTeamDB) GetPipelineInstancesReturns(result1 []db.SavedPipeline, result2 bool, result3 error) {
+	fake.GetPipelineInstancesStub = nil
+	fake.getPipelineInstancesReturns = struct {
+		result1 []db.SavedPipeline
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeTeamDB) SavePipelineInstance(templateName string, instanceName string, vars map[string]interface{}, author string) (db.SavedPipeline, bool, error) {
+	fake.savePipelineInstanceMutex.Lock()
+	fake.savePipelineInstanceArgsForCall = append(fake.savePipelineInstanceArgsForCall, struct {
+		templateName string
+		instanceName string
+		vars         map[string]interface{}
+		author       string
+	}{templateName, instanceName, vars, author})
+	fake.recordInvocation("SavePipelineInstance", []interface{}{templateName, instanceName, vars, author})
+	fake.savePipelineInstanceMutex.Unlock()
+	if fake.SavePipelineInstanceStub != nil {
+		return fake.SavePipelineInstanceStub(templateName, instanceName, vars, author)
+	} else {
+		return fake.savePipelineInstanceReturns.result1, fake.savePipelineInstanceReturns.result2, fake.savePipelineInstanceReturns.result3
+	}
+}
+
+func (fake *FakeTeamDB) SavePipelineInstanceCallCount() int {
+	fake.savePipelineInstanceMutex.RLock()
+	defer fake.savePipelineInstanceMutex.RUnlock()
+	return len(fake.savePipelineInstanceArgsForCall)
+}
+
+func (fake *FakeTeamDB) SavePipelineInstanceArgsForCall(i int) (string, string, map[string]interface{}, string) {
+	fake.savePipelineInstanceMutex.RLock()
+	defer fake.savePipelineInstanceMutex.RUnlock()
+	return fake.savePipelineInstanceArgsForCall[i].templateName, fake.savePipelineInstanceArgsForCall[i].instanceName, fake.savePipelineInstanceArgsForCall[i].vars, fake.savePipelineInstanceArgsForCall[i].author
+}
+
+func (fake *FakeTeamDB) SavePipelineInstanceReturns(result1 db.SavedPipeline, result2 bool, result3 error) {
+	fake.SavePipelineInstanceStub = nil
+	fake.savePipelineInstanceReturns = struct {
+		result1 db.SavedPipeline
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeTeamDB) DestroyPipelineInstance(templateName string, instanceName string) (bool, error) {
+	fake.destroyPipelineInstanceMutex.Lock()
+	fake.destroyPipelineInstanceArgsForCall = append(fake.destroyPipelineInstanceArgsForCall, struct {
+		templateName string
+		instanceName string
+	}{templateName, instanceName})
+	fake.recordInvocation("DestroyPipelineInstance", []interface{}{templateName, instanceName})
+	fake.destroyPipelineInstanceMutex.Unlock()
+	if fake.DestroyPipelineInstanceStub != nil {
+		return fake.DestroyPipelineInstanceStub(templateName, instanceName)
+	} else {
+		return fake.destroyPipelineInstanceReturns.result1, fake.destroyPipelineInstanceReturns.result2
+	}
+}
+
+func (fake *FakeTeamDB) DestroyPipelineInstanceCallCount() int {
+	fake.destroyPipelineInstanceMutex.RLock()
+	defer fake.destroyPipelineInstanceMutex.RUnlock()
+	return len(fake.destroyPipelineInstanceArgsForCall)
+}
+
+func (fake *FakeTeamDB) DestroyPipelineInstanceArgsForCall(i int) (string, string) {
+	fake.destroyPipelineInstanceMutex.RLock()
+	defer fake.destroyPipelineInstanceMutex.RUnlock()
+	return fake.destroyPipelineInstanceArgsForCall[i].templateName, fake.destroyPipelineInstanceArgsForCall[i].instanceName
+}
+
+func (fake *FakeTeamDB) DestroyPipelineInstanceReturns(result1 bool, result2 error) {
+	fake.DestroyPipelineInstanceStub = nil
+	fake.destroyPipelineInstanceReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeTeamDB) GetPipelineDashboards() ([]db.PipelineDashboard, error) {
+	fake.getPipelineDashboardsMutex.Lock()
+	fake.getPipelineDashboardsArgsForCall = append(fake.getPipelineDashboardsArgsForCall, struct{}{})
+	fake.recordInvocation("GetPipelineDashboards", []interface{}{})
+	fake.getPipelineDashboardsMutex.Unlock()
+	if fake.GetPipelineDashboardsStub != nil {
+		return fake.GetPipelineDashboardsStub()
+	} else {
+		return fake.getPipelineDashboardsReturns.result1, fake.getPipelineDashboardsReturns.result2
+	}
+}
+
+func (fake *FakeTeamDB) GetPipelineDashboardsCallCount() int {
+	fake.getPipelineDashboardsMutex.RLock()
+	defer fake.getPipelineDashboardsMutex.RUnlock()
+	return len(fake.getPipelineDashboardsArgsForCall)
+}
+
+func (fake *FakeTeamDB) GetPipelineDashboardsReturns(result1 []db.PipelineDashboard, result2 error) {
+	fake.GetPipelineDashboardsStub = nil
+	fake.getPipelineDashboardsReturns = struct {
+		result1 []db.PipelineDashboard
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeTeamDB) UpdateSAMLAuth(samlAuth *db.SAMLAuth) (db.SavedTeam, error) {
+	fake.updateSAMLAuthMutex.Lock()
+	fake.updateSAMLAuthArgsForCall = append(fake.updateSAMLAuthArgsForCall, struct {
+		samlAuth *db.SAMLAuth
+	}{samlAuth})
+	fake.recordInvocation("UpdateSAMLAuth", []interface{}{samlAuth})
+	fake.updateSAMLAuthMutex.Unlock()
+	if fake.UpdateSAMLAuthStub != nil {
+		return fake.UpdateSAMLAuthStub(samlAuth)
+	} else {
+		return fake.updateSAMLAuthReturns.result1, fake.updateSAMLAuthReturns.result2
+	}
+}
+
+func (fake *FakeTeamDB) UpdateSAMLAuthCallCount() int {
+	fake.updateSAMLAuthMutex.RLock()
+	defer fake.updateSAMLAuthMutex.RUnlock()
+	return len(fake.updateSAMLAuthArgsForCall)
+}
+
+func (fake *FakeTeamDB) UpdateSAMLAuthArgsForCall(i int) *db.SAMLAuth {
+	fake.updateSAMLAuthMutex.RLock()
+	defer fake.updateSAMLAuthMutex.RUnlock()
+	return fake.updateSAMLAuthArgsForCall[i].samlAuth
+}
+
+func (fake *FakeTeamDB) UpdateSAMLAuthReturns(result1 db.SavedTeam, result2 error) {
+	fake.UpdateSAMLAuthStub = nil
+	fake.updateSAMLAuthReturns = struct {
+		result1 db.SavedTeam
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeTeamDB) SaveCandidateConfig(pipelineName string, config atc.Config, percentage int, author string) (bool, error) {
+	fake.saveCandidateConfigMutex.Lock()
+	fake.saveCandidateConfigArgsForCall = append(fake.saveCandidateConfigArgsForCall, struct {
+		pipelineName string
+		config       atc.Config
+		percentage   int
+		author       string
+	}{pipelineName, config, percentage, author})
+	fake.recordInvocation("SaveCandidateConfig", []interface{}{pipelineName, config, percentage, author})
+	fake.saveCandidateConfigMutex.Unlock()
+	if fake.SaveCandidateConfigStub != nil {
+		return fake.SaveCandidateConfigStub(pipelineName, config, percentage, author)
+	} else {
+		return fake.saveCandidateConfigReturns.result1, fake.saveCandidateConfigReturns.result2
+	}
+}
+
+func (fake *FakeTeamDB) SaveCandidateConfigCallCount() int {
+	fake.saveCandidateConfigMutex.RLock()
+	defer fake.saveCandidateConfigMutex.RUnlock()
+	return len(fake.saveCandidateConfigArgsForCall)
+}
+
+func (fake *FakeTeamDB) SaveCandidateConfigArgsForCall(i int) (string, atc.Config, int, string) {
+	fake.saveCandidateConfigMutex.RLock()
+	defer fake.saveCandidateConfigMutex.RUnlock()
+	return fake.saveCandidateConfigArgsForCall[i].pipelineName, fake.saveCandidateConfigArgsForCall[i].config, fake.saveCandidateConfigArgsForCall[i].percentage, fake.saveCandidateConfigArgsForCall[i].author
+}
+
+func (fake *FakeTeamDB) SaveCandidateConfigReturns(result1 bool, result2 error) {
+	fake.SaveCandidateConfigStub = nil
+	fake.saveCandidateConfigReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeTeamDB) GetCandidateConfig(pipelineName string) (db.CandidateConfig, bool, error) {
+	fake.getCandidateConfigMutex.Lock()
+	fake.getCandidateConfigArgsForCall = append(fake.getCandidateConfigArgsForCall, struct {
+		pipelineName string
+	}{pipelineName})
+	fake.recordInvocation("GetCandidateConfig", []interface{}{pipelineName})
+	fake.getCandidateConfigMutex.Unlock()
+	if fake.GetCandidateConfigStub != nil {
+		return fake.GetCandidateConfigStub(pipelineName)
+	} else {
+		return fake.getCandidateConfigReturns.result1, fake.getCandidateConfigReturns.result2, fake.getCandidateConfigReturns.result3
+	}
+}
+
+func (fake *FakeTeamDB) GetCandidateConfigCallCount() int {
+	fake.getCandidateConfigMutex.RLock()
+	defer fake.getCandidateConfigMutex.RUnlock()
+	return len(fake.getCandidateConfigArgsForCall)
+}
+
+func (fake *FakeTeamDB) GetCandidateConfigArgsForCall(i int) string {
+	fake.getCandidateConfigMutex.RLock()
+	defer fake.getCandidateConfigMutex.RUnlock()
+	return fake.getCandidateConfigArgsForCall[i].pipelineName
+}
+
+func (fake *FakeTeamDB) GetCandidateConfigReturns(result1 db.CandidateConfig, result2 bool, result3 error) {
+	fake.GetCandidateConfigStub = nil
+	fake.getCandidateConfigReturns = struct {
+		result1 db.CandidateConfig
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeTeamDB) DeleteCandidateConfig(pipelineName string) (bool, error) {
+	fake.deleteCandidateConfigMutex.Lock()
+	fake.deleteCandidateConfigArgsForCall = append(fake.deleteCandidateConfigArgsForCall, struct {
+		pipelineName string
+	}{pipelineName})
+	fake.recordInvocation("DeleteCandidateConfig", []interface{}{pipelineName})
+	fake.deleteCandidateConfigMutex.Unlock()
+	if fake.DeleteCandidateConfigStub != nil {
+		return fake.DeleteCandidateConfigStub(pipelineName)
+	} else {
+		return fake.deleteCandidateConfigReturns.result1, fake.deleteCandidateConfigReturns.result2
+	}
+}
+
+func (fake *FakeTeamDB) DeleteCandidateConfigCallCount() int {
+	fake.deleteCandidateConfigMutex.RLock()
+	defer fake.deleteCandidateConfigMutex.RUnlock()
+	return len(fake.deleteCandidateConfigArgsForCall)
+}
+
+func (fake *FakeTeamDB) DeleteCandidateConfigArgsForCall(i int) string {
+	fake.deleteCandidateConfigMutex.RLock()
+	defer fake.deleteCandidateConfigMutex.RUnlock()
+	return fake.deleteCandidateConfigArgsForCall[i].pipelineName
+}
+
+func (fake *FakeTeamDB) DeleteCandidateConfigReturns(result1 bool, result2 error) {
+	fake.DeleteCandidateConfigStub = nil
+	fake.deleteCandidateConfigReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeTeamDB) PromoteCandidateConfig(pipelineName string, author string) (db.SavedPipeline, bool, error) {
+	fake.promoteCandidateConfigMutex.Lock()
+	fake.promoteCandidateConfigArgsForCall = append(fake.promoteCandidateConfigArgsForCall, struct {
+		pipelineName string
+		author       string
+	}{pipelineName, author})
+	fake.recordInvocation("PromoteCandidateConfig", []interface{}{pipelineName, author})
+	fake.promoteCandidateConfigMutex.Unlock()
+	if fake.PromoteCandidateConfigStub != nil {
+		return fake.PromoteCandidateConfigStub(pipelineName, author)
+	} else {
+		return fake.promoteCandidateConfigReturns.result1, fake.promoteCandidateConfigReturns.result2, fake.promoteCandidateConfigReturns.result3
+	}
+}
+
+func (fake *FakeTeamDB) PromoteCandidateConfigCallCount() int {
+	fake.promoteCandidateConfigMutex.RLock()
+	defer fake.promoteCandidateConfigMutex.RUnlock()
+	return len(fake.promoteCandidateConfigArgsForCall)
+}
+
+func (fake *FakeTeamDB) PromoteCandidateConfigArgsForCall(i int) (string, string) {
+	fake.promoteCandidateConfigMutex.RLock()
+	defer fake.promoteCandidateConfigMutex.RUnlock()
+	return fake.promoteCandidateConfigArgsForCall[i].pipelineName, fake.promoteCandidateConfigArgsForCall[i].author
+}
+
+func (fake *FakeTeamDB) PromoteCandidateConfigReturns(result1 db.SavedPipeline, result2 bool, result3 error) {
+	fake.PromoteCandidateConfigStub = nil
+	fake.promoteCandidateConfigReturns = struct {
+		result1 db.SavedPipeline
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeTeamDB) GetCandidateComparison(pipelineName string) ([]db.CandidateComparison, bool, error) {
+	fake.getCandidateComparisonMutex.Lock()
+	fake.getCandidateComparisonArgsForCall = append(fake.getCandidateComparisonArgsForCall, struct {
+		pipelineName string
+	}{pipelineName})
+	fake.recordInvocation("GetCandidateComparison", []interface{}{pipelineName})
+	fake.getCandidateComparisonMutex.Unlock()
+	if fake.GetCandidateComparisonStub != nil {
+		return fake.GetCandidateComparisonStub(pipelineName)
+	} else {
+		return fake.getCandidateComparisonReturns.result1, fake.getCandidateComparisonReturns.result2, fake.getCandidateComparisonReturns.result3
+	}
+}
+
+func (fake *FakeTeamDB) GetCandidateComparisonCallCount() int {
+	fake.getCandidateComparisonMutex.RLock()
+	defer fake.getCandidateComparisonMutex.RUnlock()
+	return len(fake.getCandidateComparisonArgsForCall)
+}
+
+func (fake *FakeTeamDB) GetCandidateComparisonArgsForCall(i int) string {
+	fake.getCandidateComparisonMutex.RLock()
+	defer fake.getCandidateComparisonMutex.RUnlock()
+	return fake.getCandidateComparisonArgsForCall[i].pipelineName
+}
+
+func (fake *FakeTeamDB) GetCandidateComparisonReturns(result1 []db.CandidateComparison, result2 bool, result3 error) {
+	fake.GetCandidateComparisonStub = nil
+	fake.getCandidateComparisonReturns = struct {
+		result1 []db.CandidateComparison
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeTeamDB) SaveSAMLRequest(id string, redirect string, expiresAt time.Time) error {
+	fake.saveSAMLRequestMutex.Lock()
+	fake.saveSAMLRequestArgsForCall = append(fake.saveSAMLRequestArgsForCall, struct {
+		id        string
+		redirect  string
+		expiresAt time.Time
+	}{id, redirect, expiresAt})
+	fake.recordInvocation("SaveSAMLRequest", []interface{}{id, redirect, expiresAt})
+	fake.saveSAMLRequestMutex.Unlock()
+	if fake.SaveSAMLRequestStub != nil {
+		return fake.SaveSAMLRequestStub(id, redirect, expiresAt)
+	} else {
+		return fake.saveSAMLRequestReturns.result1
+	}
+}
+
+func (fake *FakeTeamDB) SaveSAMLRequestCallCount() int {
+	fake.saveSAMLRequestMutex.RLock()
+	defer fake.saveSAMLRequestMutex.RUnlock()
+	return len(fake.saveSAMLRequestArgsForCall)
+}
+
+func (fake *FakeTeamDB) SaveSAMLRequestArgsForCall(i int) (string, string, time.Time) {
+	fake.saveSAMLRequestMutex.RLock()
+	defer fake.saveSAMLRequestMutex.RUnlock()
+	return fake.saveSAMLRequestArgsForCall[i].id, fake.saveSAMLRequestArgsForCall[i].redirect, fake.saveSAMLRequestArgsForCall[i].expiresAt
+}
+
+func (fake *FakeTeamDB) SaveSAMLRequestReturns(result1 error) {
+	fake.SaveSAMLRequestStub = nil
+	fake.saveSAMLRequestReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeTeamDB) TakeSAMLRequest(id string) (string, bool, error) {
+	fake.takeSAMLRequestMutex.Lock()
+	fake.takeSAMLRequestArgsForCall = append(fake.takeSAMLRequestArgsForCall, struct {
+		id string
+	}{id})
+	fake.recordInvocation("TakeSAMLRequest", []interface{}{id})
+	fake.takeSAMLRequestMutex.Unlock()
+	if fake.TakeSAMLRequestStub != nil {
+		return fake.TakeSAMLRequestStub(id)
+	} else {
+		return fake.takeSAMLRequestReturns.result1, fake.takeSAMLRequestReturns.result2, fake.takeSAMLRequestReturns.result3
+	}
+}
+
+func (fake *FakeTeamDB) TakeSAMLRequestCallCount() int {
+	fake.takeSAMLRequestMutex.RLock()
+	defer fake.takeSAMLRequestMutex.RUnlock()
+	return len(fake.takeSAMLRequestArgsForCall)
+}
+
+func (fake *FakeTeamDB) TakeSAMLRequestArgsForCall(i int) string {
+	fake.takeSAMLRequestMutex.RLock()
+	defer fake.takeSAMLRequestMutex.RUnlock()
+	return fake.takeSAMLRequestArgsForCall[i].id
+}
+
+func (fake *FakeTeamDB) TakeSAMLRequestReturns(result1 string, result2 bool, result3 error) {
+	fake.TakeSAMLRequestStub = nil
+	fake.takeSAMLRequestReturns = struct {
+		result1 string
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeTeamDB) SaveSAMLAssertion(id string, expiresAt time.Time) (bool, error) {
+	fake.saveSAMLAssertionMutex.Lock()
+	fake.saveSAMLAssertionArgsForCall = append(fake.saveSAMLAssertionArgsForCall, struct {
+		id        string
+		expiresAt time.Time
+	}{id, expiresAt})
+	fake.recordInvocation("SaveSAMLAssertion", []interface{}{id, expiresAt})
+	fake.saveSAMLAssertionMutex.Unlock()
+	if fake.SaveSAMLAssertionStub != nil {
+		return fake.SaveSAMLAssertionStub(id, expiresAt)
+	} else {
+		return fake.saveSAMLAssertionReturns.result1, fake.saveSAMLAssertionReturns.result2
+	}
+}
+
+func (fake *FakeTeamDB) SaveSAMLAssertionCallCount() int {
+	fake.saveSAMLAssertionMutex.RLock()
+	defer fake.saveSAMLAssertionMutex.RUnlock()
+	return len(fake.saveSAMLAssertionArgsForCall)
+}
+
+func (fake *FakeTeamDB) SaveSAMLAssertionArgsForCall(i int) (string, time.Time) {
+	fake.saveSAMLAssertionMutex.RLock()
+	defer fake.saveSAMLAssertionMutex.RUnlock()
+	return fake.saveSAMLAssertionArgsForCall[i].id, fake.saveSAMLAssertionArgsForCall[i].expiresAt
+}
+
+func (fake *FakeTeamDB) SaveSAMLAssertionReturns(result1 bool, result2 error) {
+	fake.SaveSAMLAssertionStub = nil
+	fake.saveSAMLAssertionReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeTeamDB) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -779,6 +1687,46 @@ func (fake *FakeTeamDB) Invocations() map[string][][]interface{} {
 	defer fake.findContainersByDescriptorsMutex.RUnlock()
 	fake.getVolumesMutex.RLock()
 	defer fake.getVolumesMutex.RUnlock()
+	fake.searchBuildLogsMutex.RLock()
+	defer fake.searchBuildLogsMutex.RUnlock()
+	fake.getBuildsMutex.RLock()
+	defer fake.getBuildsMutex.RUnlock()
+	fake.createRerunBuildMutex.RLock()
+	defer fake.createRerunBuildMutex.RUnlock()
+	fake.getConfigRevisionsMutex.RLock()
+	defer fake.getConfigRevisionsMutex.RUnlock()
+	fake.revertConfigMutex.RLock()
+	defer fake.revertConfigMutex.RUnlock()
+	fake.updateRolesMutex.RLock()
+	defer fake.updateRolesMutex.RUnlock()
+	fake.updateOIDCAuthMutex.RLock()
+	defer fake.updateOIDCAuthMutex.RUnlock()
+	fake.getPipelineInstancesMutex.RLock()
+	defer fake.getPipelineInstancesMutex.RUnlock()
+	fake.savePipelineInstanceMutex.RLock()
+	defer fake.savePipelineInstanceMutex.RUnlock()
+	fake.destroyPipelineInstanceMutex.RLock()
+	defer fake.destroyPipelineInstanceMutex.RUnlock()
+	fake.getPipelineDashboardsMutex.RLock()
+	defer fake.getPipelineDashboardsMutex.RUnlock()
+	fake.updateSAMLAuthMutex.RLock()
+	defer fake.updateSAMLAuthMutex.RUnlock()
+	fake.saveCandidateConfigMutex.RLock()
+	defer fake.saveCandidateConfigMutex.RUnlock()
+	fake.getCandidateConfigMutex.RLock()
+	defer fake.getCandidateConfigMutex.RUnlock()
+	fake.deleteCandidateConfigMutex.RLock()
+	defer fake.deleteCandidateConfigMutex.RUnlock()
+	fake.promoteCandidateConfigMutex.RLock()
+	defer fake.promoteCandidateConfigMutex.RUnlock()
+	fake.getCandidateComparisonMutex.RLock()
+	defer fake.getCandidateComparisonMutex.RUnlock()
+	fake.saveSAMLRequestMutex.RLock()
+	defer fake.saveSAMLRequestMutex.RUnlock()
+	fake.takeSAMLRequestMutex.RLock()
+	defer fake.takeSAMLRequestMutex.RUnlock()
+	fake.saveSAMLAssertionMutex.RLock()
+	defer fake.saveSAMLAssertionMutex.RUnlock()
 	return fake.invocations
 }
 
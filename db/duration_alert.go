@@ -0,0 +1,44 @@
+package db
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// DurationAlert is raised for a build that has been running for longer than
+// Percentile percent of its job's recent builds took. Threshold is how long
+// that was, and Elapsed how long the build had been running when it was
+// raised.
+type DurationAlert struct {
+	BuildID      int
+	BuildName    string
+	TeamName     string
+	PipelineName string
+	JobName      string
+
+	Percentile int
+	Threshold  time.Duration
+	Elapsed    time.Duration
+
+	CreatedAt time.Time
+}
+
+// SaveDurationAlert raises an alert for the build, returning false if it
+// already has one.
+func (b *build) SaveDurationAlert(percentile int, threshold time.Duration, elapsed time.Duration) (bool, error) {
+	_, err := b.conn.Exec(`
+		INSERT INTO build_duration_alerts (build_id, percentile, threshold_seconds, elapsed_seconds)
+		VALUES ($1, $2, $3, $4)
+	`, b.id, percentile, threshold.Seconds(), elapsed.Seconds())
+	if err != nil {
+		// another ATC got to it first
+		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code.Name() == "unique_violation" {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
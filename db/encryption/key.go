@@ -0,0 +1,57 @@
+package encryption
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+)
+
+// Key encrypts with AES-GCM, storing the nonce alongside the ciphertext.
+type Key struct {
+	aead cipher.AEAD
+}
+
+func NewKey(aead cipher.AEAD) *Key {
+	return &Key{
+		aead: aead,
+	}
+}
+
+func (key *Key) Encrypt(plaintext []byte) (string, error) {
+	nonce := make([]byte, key.aead.NonceSize())
+
+	_, err := io.ReadFull(rand.Reader, nonce)
+	if err != nil {
+		return "", err
+	}
+
+	sealed := key.aead.Seal(nonce, nonce, plaintext, nil)
+
+	return Prefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt returns values that were never encrypted as they are, so that
+// encryption can be turned on without first rewriting everything.
+func (key *Key) Decrypt(ciphertext string) ([]byte, error) {
+	if !IsEncrypted(ciphertext) {
+		return []byte(ciphertext), nil
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(ciphertext[len(Prefix):])
+	if err != nil {
+		return nil, ErrDataIsNotDecryptable
+	}
+
+	nonceSize := key.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return nil, ErrDataIsNotDecryptable
+	}
+
+	plaintext, err := key.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], nil)
+	if err != nil {
+		return nil, ErrDataIsNotDecryptable
+	}
+
+	return plaintext, nil
+}
@@ -0,0 +1,34 @@
+package encryption
+
+// NewRotationStrategy encrypts with the new strategy, falling back on the
+// old one for values it can't decrypt. It lets the ATC keep working while a
+// key is being rotated, until everything's been re-encrypted with the new
+// key; either may be NoEncryption, to turn encryption on or off.
+func NewRotationStrategy(newStrategy Strategy, oldStrategy Strategy) Strategy {
+	return rotationStrategy{
+		newStrategy: newStrategy,
+		oldStrategy: oldStrategy,
+	}
+}
+
+type rotationStrategy struct {
+	newStrategy Strategy
+	oldStrategy Strategy
+}
+
+func (strategy rotationStrategy) Encrypt(plaintext []byte) (string, error) {
+	return strategy.newStrategy.Encrypt(plaintext)
+}
+
+func (strategy rotationStrategy) Decrypt(ciphertext string) ([]byte, error) {
+	plaintext, err := strategy.newStrategy.Decrypt(ciphertext)
+	if err == nil {
+		return plaintext, nil
+	}
+
+	if err != ErrDataIsEncrypted && err != ErrDataIsNotDecryptable {
+		return nil, err
+	}
+
+	return strategy.oldStrategy.Decrypt(ciphertext)
+}
@@ -0,0 +1,46 @@
+// Package encryption provides the strategies the db package uses to encrypt
+// sensitive columns, e.g. pipeline configs and build plans, before they're
+// written to the database.
+package encryption
+
+import (
+	"errors"
+	"strings"
+)
+
+// Prefix marks a value as encrypted. Everything stored encrypted is JSON,
+// which can't start with it, so values written before encryption was
+// configured can be told apart and read as they are.
+const Prefix = "aes-gcm:"
+
+var ErrDataIsEncrypted = errors.New("data is encrypted, but no encryption key is configured")
+var ErrDataIsNotDecryptable = errors.New("data could not be decrypted; it may have been encrypted with another key")
+
+type Strategy interface {
+	Encrypt(plaintext []byte) (string, error)
+	Decrypt(ciphertext string) ([]byte, error)
+}
+
+// IsEncrypted reports whether the value was written by a Key.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, Prefix)
+}
+
+// NoEncryption stores values as they are, refusing to read encrypted ones.
+type NoEncryption struct{}
+
+func NewNoEncryption() Strategy {
+	return NoEncryption{}
+}
+
+func (NoEncryption) Encrypt(plaintext []byte) (string, error) {
+	return string(plaintext), nil
+}
+
+func (NoEncryption) Decrypt(ciphertext string) ([]byte, error) {
+	if IsEncrypted(ciphertext) {
+		return nil, ErrDataIsEncrypted
+	}
+
+	return []byte(ciphertext), nil
+}
@@ -0,0 +1,132 @@
+package encryption_test
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+
+	. "github.com/concourse/atc/db/encryption"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Strategies", func() {
+	newKey := func(secret string) *Key {
+		block, err := aes.NewCipher([]byte(secret))
+		Expect(err).NotTo(HaveOccurred())
+
+		aead, err := cipher.NewGCM(block)
+		Expect(err).NotTo(HaveOccurred())
+
+		return NewKey(aead)
+	}
+
+	var (
+		key    *Key
+		oldKey *Key
+	)
+
+	BeforeEach(func() {
+		key = newKey("AES256Key-32Characters1234567890")
+		oldKey = newKey("AES128Key-16Char")
+	})
+
+	Describe("Key", func() {
+		It("round-trips values, without storing them in the clear", func() {
+			ciphertext, err := key.Encrypt([]byte(`{"password":"some-password"}`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ciphertext).To(HavePrefix(Prefix))
+			Expect(ciphertext).NotTo(ContainSubstring("some-password"))
+
+			plaintext, err := key.Decrypt(ciphertext)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(plaintext)).To(Equal(`{"password":"some-password"}`))
+		})
+
+		It("uses a new nonce every time", func() {
+			first, err := key.Encrypt([]byte(`{}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			second, err := key.Encrypt([]byte(`{}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(first).NotTo(Equal(second))
+		})
+
+		It("reads values that were never encrypted as they are", func() {
+			plaintext, err := key.Decrypt(`{"some":"config"}`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(plaintext)).To(Equal(`{"some":"config"}`))
+		})
+
+		It("fails to decrypt values encrypted with another key", func() {
+			ciphertext, err := oldKey.Encrypt([]byte(`{}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = key.Decrypt(ciphertext)
+			Expect(err).To(Equal(ErrDataIsNotDecryptable))
+		})
+
+		It("fails to decrypt garbage", func() {
+			_, err := key.Decrypt(Prefix + "bm9wZQ==")
+			Expect(err).To(Equal(ErrDataIsNotDecryptable))
+
+			_, err = key.Decrypt(Prefix + "???")
+			Expect(err).To(Equal(ErrDataIsNotDecryptable))
+		})
+	})
+
+	Describe("NoEncryption", func() {
+		It("stores values as they are", func() {
+			ciphertext, err := NewNoEncryption().Encrypt([]byte(`{}`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ciphertext).To(Equal(`{}`))
+		})
+
+		It("refuses to read encrypted values", func() {
+			ciphertext, err := key.Encrypt([]byte(`{}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = NewNoEncryption().Decrypt(ciphertext)
+			Expect(err).To(Equal(ErrDataIsEncrypted))
+		})
+	})
+
+	Describe("NewRotationStrategy", func() {
+		It("encrypts with the new key", func() {
+			ciphertext, err := NewRotationStrategy(key, oldKey).Encrypt([]byte(`{}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = key.Decrypt(ciphertext)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("decrypts with either key", func() {
+			strategy := NewRotationStrategy(key, oldKey)
+
+			for _, encrypter := range []*Key{key, oldKey} {
+				ciphertext, err := encrypter.Encrypt([]byte(`{}`))
+				Expect(err).NotTo(HaveOccurred())
+
+				plaintext, err := strategy.Decrypt(ciphertext)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(plaintext)).To(Equal(`{}`))
+			}
+		})
+
+		It("can turn encryption off", func() {
+			strategy := NewRotationStrategy(NewNoEncryption(), oldKey)
+
+			ciphertext, err := oldKey.Encrypt([]byte(`{}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			plaintext, err := strategy.Decrypt(ciphertext)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(plaintext)).To(Equal(`{}`))
+
+			stored, err := strategy.Encrypt([]byte(`{}`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stored).To(Equal(`{}`))
+		})
+	})
+})
@@ -0,0 +1,192 @@
+package db_test
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"time"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	"github.com/lib/pq"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/db/dbfakes"
+	"github.com/concourse/atc/db/encryption"
+)
+
+var _ = Describe("Encryption at rest", func() {
+	var (
+		plainConn db.Conn
+		listener  *pq.Listener
+		bus       db.NotificationsBus
+
+		lockFactory db.LockFactory
+
+		oldKey *encryption.Key
+		newKey *encryption.Key
+
+		config atc.Config
+	)
+
+	newAESKey := func(secret string) *encryption.Key {
+		block, err := aes.NewCipher([]byte(secret))
+		Expect(err).NotTo(HaveOccurred())
+
+		aead, err := cipher.NewGCM(block)
+		Expect(err).NotTo(HaveOccurred())
+
+		return encryption.NewKey(aead)
+	}
+
+	teamDBWith := func(strategy encryption.Strategy) db.TeamDB {
+		return db.NewTeamDBFactory(db.WithEncryption(plainConn, strategy), bus, lockFactory).GetTeamDB("some-team")
+	}
+
+	storedConfig := func() string {
+		var stored string
+		err := plainConn.QueryRow(`SELECT config FROM pipelines WHERE name = 'some-pipeline'`).Scan(&stored)
+		Expect(err).NotTo(HaveOccurred())
+		return stored
+	}
+
+	BeforeEach(func() {
+		postgresRunner.Truncate()
+
+		plainConn = db.Wrap(postgresRunner.Open())
+		listener = pq.NewListener(postgresRunner.DataSourceName(), time.Second, time.Minute, nil)
+
+		Eventually(listener.Ping, 5*time.Second).ShouldNot(HaveOccurred())
+		bus = db.NewNotificationsBus(listener, plainConn)
+
+		pgxConn := postgresRunner.OpenPgx()
+		fakeConnector := new(dbfakes.FakeConnector)
+		retryableConn := &db.RetryableConn{Connector: fakeConnector, Conn: pgxConn}
+
+		lockFactory = db.NewLockFactory(retryableConn)
+
+		_, err := db.NewSQL(plainConn, bus, lockFactory).CreateTeam(db.Team{Name: "some-team"})
+		Expect(err).NotTo(HaveOccurred())
+
+		oldKey = newAESKey("AES128Key-16Char")
+		newKey = newAESKey("AES256Key-32Characters1234567890")
+
+		config = atc.Config{
+			Resources: atc.ResourceConfigs{
+				{
+					Name:   "some-resource",
+					Type:   "git",
+					Source: atc.Source{"private_key": "some-private-key"},
+				},
+			},
+			Jobs: atc.JobConfigs{
+				{Name: "some-job"},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		err := plainConn.Close()
+		Expect(err).NotTo(HaveOccurred())
+
+		err = listener.Close()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("encrypts pipeline configs, reading them back decrypted", func() {
+		teamDB := teamDBWith(oldKey)
+
+		_, _, err := teamDB.SaveConfig("some-pipeline", config, 0, db.PipelineUnpaused, "some-author")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(storedConfig()).To(HavePrefix(encryption.Prefix))
+		Expect(storedConfig()).NotTo(ContainSubstring("some-private-key"))
+
+		savedConfig, _, _, err := teamDB.GetConfig("some-pipeline")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(savedConfig).To(Equal(config))
+
+		revisions, _, err := teamDB.GetConfigRevisions("some-pipeline")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(revisions).To(HaveLen(1))
+		Expect(revisions[0].Config).To(Equal(config))
+
+		By("refusing to read them without the key")
+		_, _, _, err = teamDBWith(encryption.NewNoEncryption()).GetConfig("some-pipeline")
+		Expect(err).To(Equal(encryption.ErrDataIsEncrypted))
+	})
+
+	It("encrypts the params, plans and secrets of builds", func() {
+		conn := db.WithEncryption(plainConn, oldKey)
+
+		savedPipeline, _, err := teamDBWith(oldKey).SaveConfig("some-pipeline", config, 0, db.PipelineUnpaused, "some-author")
+		Expect(err).NotTo(HaveOccurred())
+
+		pipelineDB := db.NewPipelineDBFactory(conn, bus, lockFactory).Build(savedPipeline)
+
+		build, err := pipelineDB.CreateJobBuildWithParams("some-job", map[string]string{"TOKEN": "some-token"})
+		Expect(err).NotTo(HaveOccurred())
+
+		started, err := build.Start("exec.v2", `{"plan":"some-plan"}`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(started).To(BeTrue())
+
+		err = build.SaveSecrets([]string{"some-secret"})
+		Expect(err).NotTo(HaveOccurred())
+
+		var params, metadata, secrets string
+		err = plainConn.QueryRow(`
+			SELECT params, engine_metadata, secrets
+			FROM builds
+			WHERE id = $1
+		`, build.ID()).Scan(&params, &metadata, &secrets)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(params).NotTo(ContainSubstring("some-token"))
+		Expect(metadata).NotTo(ContainSubstring("some-plan"))
+		Expect(secrets).NotTo(ContainSubstring("some-secret"))
+
+		found, err := build.Reload()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeTrue())
+
+		Expect(build.Params()).To(Equal(map[string]string{"TOKEN": "some-token"}))
+		Expect(build.EngineMetadata()).To(Equal(`{"plan":"some-plan"}`))
+		Expect(build.GetSecrets()).To(Equal([]string{"some-secret"}))
+	})
+
+	Describe("Reencrypt", func() {
+		BeforeEach(func() {
+			_, _, err := teamDBWith(oldKey).SaveConfig("some-pipeline", config, 0, db.PipelineUnpaused, "some-author")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("rotates values to the new key", func() {
+			rotating := db.WithEncryption(plainConn, encryption.NewRotationStrategy(newKey, oldKey))
+
+			err := db.Reencrypt(lagertest.NewTestLogger("test"), rotating)
+			Expect(err).NotTo(HaveOccurred())
+
+			savedConfig, _, _, err := teamDBWith(newKey).GetConfig("some-pipeline")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(savedConfig).To(Equal(config))
+
+			revisions, _, err := teamDBWith(newKey).GetConfigRevisions("some-pipeline")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(revisions[0].Config).To(Equal(config))
+
+			_, _, _, err = teamDBWith(oldKey).GetConfig("some-pipeline")
+			Expect(err).To(Equal(encryption.ErrDataIsNotDecryptable))
+		})
+
+		It("decrypts everything when encryption is being turned off", func() {
+			decrypting := db.WithEncryption(plainConn, encryption.NewRotationStrategy(encryption.NewNoEncryption(), oldKey))
+
+			err := db.Reencrypt(lagertest.NewTestLogger("test"), decrypting)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(storedConfig()).To(ContainSubstring("some-private-key"))
+		})
+	})
+})
@@ -6,6 +6,10 @@ var ErrNoVersions = errors.New("no versions found")
 var ErrNoBuild = errors.New("no build found")
 
 var ErrPipelineNotFound = errors.New("pipeline not found")
+var ErrPipelineIsInstance = errors.New("pipeline is an instance of another pipeline")
+var ErrPipelineNameTaken = errors.New("pipeline name is already taken")
+
+var ErrTeamNotFound = errors.New("team not found")
 
 var ErrLockRowNotPresentOrAlreadyDeleted = errors.New("lock could not be acquired because it didn't exist or was already cleaned up")
 
@@ -0,0 +1,206 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+)
+
+// WorkerGCStats counts the containers and volumes created on a worker, and
+// how they went away: destroyed once they expired or were no longer needed,
+// or orphaned when the worker no longer had them.
+type WorkerGCStats struct {
+	WorkerName string
+
+	ContainersCreated   int
+	ContainersDestroyed int
+	ContainersOrphaned  int
+
+	VolumesCreated   int
+	VolumesDestroyed int
+	VolumesOrphaned  int
+}
+
+// GCPass is the last time garbage collection ran to completion.
+type GCPass struct {
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+func (pass GCPass) Duration() time.Duration {
+	return pass.FinishedAt.Sub(pass.StartedAt)
+}
+
+const (
+	gcContainersCreated   = "containers_created"
+	gcContainersDestroyed = "containers_destroyed"
+	gcContainersOrphaned  = "containers_orphaned"
+	gcVolumesCreated      = "volumes_created"
+	gcVolumesDestroyed    = "volumes_destroyed"
+	gcVolumesOrphaned     = "volumes_orphaned"
+)
+
+// gcConn is satisfied by both Conn and Tx, so that counts can be kept in
+// the same transaction as what they count.
+type gcConn interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func countGC(conn gcConn, workerName string, counter string, n int) error {
+	result, err := conn.Exec(`
+		UPDATE worker_gc_stats
+		SET `+counter+` = `+counter+` + $2
+		WHERE worker_name = $1
+	`, workerName, n)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		_, err = conn.Exec(`
+			INSERT INTO worker_gc_stats (worker_name, `+counter+`)
+			VALUES ($1, $2)
+		`, workerName, n)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteCountingGC runs a DELETE that returns the worker_name of each row it
+// deleted, and adds them to each worker's counter. It returns how many rows
+// were deleted.
+func deleteCountingGC(conn gcConn, counter string, query string, args ...interface{}) (int, error) {
+	rows, err := conn.Query(query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	perWorker := map[string]int{}
+	deleted := 0
+
+	for rows.Next() {
+		var workerName sql.NullString
+		err := rows.Scan(&workerName)
+		if err != nil {
+			rows.Close()
+			return 0, err
+		}
+
+		perWorker[workerName.String]++
+		deleted++
+	}
+
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return 0, err
+	}
+
+	for workerName, n := range perWorker {
+		if workerName == "" {
+			continue
+		}
+
+		err := countGC(conn, workerName, counter, n)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return deleted, nil
+}
+
+func (db *SQLDB) GetWorkerGCStats() ([]WorkerGCStats, error) {
+	rows, err := db.conn.Query(`
+		SELECT worker_name, containers_created, containers_destroyed, containers_orphaned,
+			volumes_created, volumes_destroyed, volumes_orphaned
+		FROM worker_gc_stats
+		ORDER BY worker_name ASC
+	`)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	stats := []WorkerGCStats{}
+
+	for rows.Next() {
+		var s WorkerGCStats
+		err := rows.Scan(
+			&s.WorkerName,
+			&s.ContainersCreated,
+			&s.ContainersDestroyed,
+			&s.ContainersOrphaned,
+			&s.VolumesCreated,
+			&s.VolumesDestroyed,
+			&s.VolumesOrphaned,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		stats = append(stats, s)
+	}
+
+	return stats, rows.Err()
+}
+
+func (db *SQLDB) SaveGCPass(pass GCPass) error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE last_gc_pass
+		SET started_at = $1, finished_at = $2
+	`, pass.StartedAt, pass.FinishedAt)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		_, err = tx.Exec(`
+			INSERT INTO last_gc_pass (started_at, finished_at)
+			VALUES ($1, $2)
+		`, pass.StartedAt, pass.FinishedAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (db *SQLDB) GetLastGCPass() (GCPass, bool, error) {
+	var pass GCPass
+	err := db.conn.QueryRow(`
+		SELECT started_at, finished_at
+		FROM last_gc_pass
+	`).Scan(&pass.StartedAt, &pass.FinishedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return GCPass{}, false, nil
+		}
+
+		return GCPass{}, false, err
+	}
+
+	return pass, true, nil
+}
@@ -1,6 +1,10 @@
 package db
 
-import "github.com/concourse/atc"
+import (
+	"time"
+
+	"github.com/concourse/atc"
+)
 
 type Job struct {
 	Name string
@@ -9,9 +13,12 @@ type Job struct {
 type SavedJob struct {
 	ID                 int
 	Paused             bool
+	ManualOnly         bool
 	PipelineName       string
 	FirstLoggedBuildID int
 	TeamID             int
+	Flakiness          float64
+	Flaky              bool
 	Job
 }
 
@@ -24,3 +31,59 @@ type DashboardJob struct {
 }
 
 type Dashboard []DashboardJob
+
+// JobBuildStats summarizes the builds of a job that finished within some
+// window. Durations are of the builds that ran to completion, i.e. succeeded
+// or failed, as errored and aborted builds can stop at any point.
+type JobBuildStats struct {
+	Succeeded int
+	Failed    int
+	Errored   int
+	Aborted   int
+	TimedOut  int
+
+	MeanDuration   time.Duration
+	MedianDuration time.Duration
+	P95Duration    time.Duration
+}
+
+// JobOutcomes compares each of a job's builds with the build before it that
+// had exactly the same inputs.
+type JobOutcomes struct {
+	// Compared is how many builds had an earlier build with the same inputs.
+	Compared int
+
+	// Flipped is how many of those didn't have the same outcome, i.e. one
+	// succeeded and the other failed.
+	Flipped int
+}
+
+// SchedulingReason is why the scheduler last didn't start a build of a job.
+type SchedulingReason string
+
+const (
+	SchedulingReasonNoPendingBuilds          SchedulingReason = "no-pending-builds"
+	SchedulingReasonMaxInFlightReached       SchedulingReason = "max-in-flight-reached"
+	SchedulingReasonInputsNotSatisfied       SchedulingReason = "inputs-not-satisfied"
+	SchedulingReasonPipelinePaused           SchedulingReason = "pipeline-paused"
+	SchedulingReasonJobPaused                SchedulingReason = "job-paused"
+	SchedulingReasonGlobalMaxInFlightReached SchedulingReason = "global-max-in-flight-reached"
+	SchedulingReasonMaintenanceWindow        SchedulingReason = "maintenance-window"
+	SchedulingReasonFairShareReached         SchedulingReason = "fair-share-reached"
+	SchedulingReasonSerialGroupBusy          SchedulingReason = "serial-group-busy"
+
+	// SchedulingReasonCheckingResources is never recorded for a job; it's
+	// what a queued build is waiting on when it was triggered by hand and
+	// the scheduler is holding it back until its resources have been checked.
+	SchedulingReasonCheckingResources SchedulingReason = "checking-resources"
+)
+
+// JobScheduling is what the scheduler decided for a job the last time it
+// looked at it. The reason is recorded when builds are started, and the
+// missing inputs when they're mapped to versions, so the missing inputs can
+// explain why there are no pending builds to start.
+type JobScheduling struct {
+	Reason              SchedulingReason
+	MissingInputReasons MissingInputReasons
+	CheckedAt           time.Time
+}
@@ -0,0 +1,88 @@
+package db
+
+import (
+	"time"
+
+	"github.com/concourse/atc/cron"
+)
+
+// MaintenanceWindow is a time during which the scheduler holds new builds.
+// Windows with a Schedule start whenever it matches in UTC and last for
+// Duration; the rest run once, from StartsAt to EndsAt.
+type MaintenanceWindow struct {
+	Name string
+
+	Schedule string
+	Duration time.Duration
+
+	StartsAt time.Time
+	EndsAt   time.Time
+}
+
+// EndOfWindowAt returns when the window ends, if it's open at the given
+// time.
+func (window MaintenanceWindow) EndOfWindowAt(t time.Time) (time.Time, bool) {
+	if window.Schedule == "" {
+		if t.Before(window.StartsAt) || !t.Before(window.EndsAt) {
+			return time.Time{}, false
+		}
+
+		return window.EndsAt, true
+	}
+
+	schedule, err := cron.Parse(window.Schedule)
+	if err != nil {
+		// schedules are checked before they're saved
+		return time.Time{}, false
+	}
+
+	// the window is open if it last started less than its duration ago
+	start := schedule.Next(t.UTC().Add(-window.Duration))
+	if start.IsZero() || start.After(t) {
+		return time.Time{}, false
+	}
+
+	return start.Add(window.Duration), true
+}
+
+// Maintenance is every maintenance window and how long they've been
+// overridden for, which lets builds start in spite of them.
+type Maintenance struct {
+	Windows []MaintenanceWindow
+
+	OverriddenUntil time.Time
+}
+
+// ActiveWindowAt returns the window open at the given time, and when it
+// ends. When windows overlap it's the one that ends last.
+func (maintenance Maintenance) ActiveWindowAt(t time.Time) (MaintenanceWindow, time.Time, bool) {
+	var active MaintenanceWindow
+	var activeUntil time.Time
+	var found bool
+
+	for _, window := range maintenance.Windows {
+		end, open := window.EndOfWindowAt(t)
+		if !open {
+			continue
+		}
+
+		if !found || end.After(activeUntil) {
+			active = window
+			activeUntil = end
+			found = true
+		}
+	}
+
+	return active, activeUntil, found
+}
+
+// HoldsBuildsAt returns whether a window is open at the given time and isn't
+// overridden.
+func (maintenance Maintenance) HoldsBuildsAt(t time.Time) bool {
+	if t.Before(maintenance.OverriddenUntil) {
+		return false
+	}
+
+	_, _, found := maintenance.ActiveWindowAt(t)
+	return found
+}
@@ -0,0 +1,17 @@
+package migrations
+
+import "github.com/BurntSushi/migration"
+
+func CreateBuildArtifacts(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE build_artifacts (
+			id serial PRIMARY KEY,
+			build_id integer NOT NULL REFERENCES builds (id) ON DELETE CASCADE,
+			name text NOT NULL,
+			container_handle text NOT NULL,
+			path text NOT NULL,
+			UNIQUE (build_id, name)
+		)
+	`)
+	return err
+}
@@ -0,0 +1,20 @@
+package migrations
+
+import "github.com/BurntSushi/migration"
+
+func AddPriorityToBuilds(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		ALTER TABLE builds
+		ADD COLUMN priority integer NOT NULL DEFAULT 0;
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		CREATE INDEX builds_pending_priority_idx
+		ON builds (priority DESC, id ASC)
+		WHERE status = 'pending';
+	`)
+	return err
+}
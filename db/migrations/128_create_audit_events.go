@@ -0,0 +1,25 @@
+package migrations
+
+import "github.com/BurntSushi/migration"
+
+func CreateAuditEvents(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE audit_events (
+			id serial PRIMARY KEY,
+			time timestamp with time zone NOT NULL DEFAULT now(),
+			actor text NOT NULL,
+			action text NOT NULL,
+			resource text NOT NULL,
+			body_sha256 text NOT NULL,
+			status integer NOT NULL
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		CREATE INDEX audit_events_actor_idx ON audit_events (actor)
+	`)
+	return err
+}
@@ -0,0 +1,19 @@
+package migrations
+
+import "github.com/BurntSushi/migration"
+
+func AddTimeToBuildEvents(tx migration.LimitedTx) error {
+	// the column is added without a default first, so that existing events
+	// are left without a time rather than all claiming to be saved now
+	_, err := tx.Exec(`
+		ALTER TABLE build_events ADD COLUMN time timestamp with time zone
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		ALTER TABLE build_events ALTER COLUMN time SET DEFAULT now()
+	`)
+	return err
+}
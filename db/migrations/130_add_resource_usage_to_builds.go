@@ -0,0 +1,13 @@
+package migrations
+
+import "github.com/BurntSushi/migration"
+
+func AddResourceUsageToBuilds(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		ALTER TABLE builds
+		ADD COLUMN cpu_usage bigint NOT NULL DEFAULT 0,
+		ADD COLUMN memory_usage bigint NOT NULL DEFAULT 0,
+		ADD COLUMN disk_usage bigint NOT NULL DEFAULT 0
+	`)
+	return err
+}
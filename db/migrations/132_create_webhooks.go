@@ -0,0 +1,16 @@
+package migrations
+
+import "github.com/BurntSushi/migration"
+
+func CreateWebhooks(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE webhooks (
+			id text PRIMARY KEY,
+			team_id integer NOT NULL REFERENCES teams (id) ON DELETE CASCADE,
+			pipeline_id integer NOT NULL REFERENCES pipelines (id) ON DELETE CASCADE,
+			job_name text NOT NULL,
+			secret text NOT NULL
+		)
+	`)
+	return err
+}
@@ -0,0 +1,22 @@
+package migrations
+
+import "github.com/BurntSushi/migration"
+
+func CreateBuildDependencies(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE build_dependencies (
+			build_id integer NOT NULL REFERENCES builds (id) ON DELETE CASCADE,
+			depends_on_build_id integer NOT NULL REFERENCES builds (id) ON DELETE CASCADE,
+			PRIMARY KEY (build_id, depends_on_build_id)
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		ALTER TABLE builds
+		ADD COLUMN pending_plan text
+	`)
+	return err
+}
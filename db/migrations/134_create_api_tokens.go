@@ -0,0 +1,18 @@
+package migrations
+
+import "github.com/BurntSushi/migration"
+
+func CreateAPITokens(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE api_tokens (
+			id serial PRIMARY KEY,
+			team_id integer NOT NULL REFERENCES teams (id) ON DELETE CASCADE,
+			name text NOT NULL,
+			scopes text NOT NULL,
+			created_at timestamp with time zone NOT NULL DEFAULT now(),
+			expires_at timestamp with time zone,
+			UNIQUE (team_id, name)
+		)
+	`)
+	return err
+}
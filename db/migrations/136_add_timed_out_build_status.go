@@ -0,0 +1,58 @@
+package migrations
+
+import "github.com/BurntSushi/migration"
+
+func AddTimedOutBuildStatus(tx migration.LimitedTx) error {
+	// ALTER TYPE ... ADD VALUE can't be run inside a transaction, so the type is
+	// replaced by one with the new value instead
+	_, err := tx.Exec(`
+		DROP INDEX builds_pending_priority_idx
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		ALTER TYPE build_status RENAME TO build_status_old
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		CREATE TYPE build_status AS ENUM (
+			'pending',
+			'started',
+			'aborted',
+			'succeeded',
+			'failed',
+			'errored',
+			'timed_out'
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		ALTER TABLE builds
+		ALTER COLUMN status TYPE build_status USING status::text::build_status
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		DROP TYPE build_status_old
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		CREATE INDEX builds_pending_priority_idx
+		ON builds (priority DESC, id ASC)
+		WHERE status = 'pending'
+	`)
+	return err
+}
@@ -0,0 +1,12 @@
+package migrations
+
+import "github.com/BurntSushi/migration"
+
+func AddTimeoutToBuilds(tx migration.LimitedTx) error {
+	// in seconds; builds without one fall back to the ATC's default
+	_, err := tx.Exec(`
+		ALTER TABLE builds
+		ADD COLUMN timeout integer
+	`)
+	return err
+}
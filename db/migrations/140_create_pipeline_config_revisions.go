@@ -0,0 +1,28 @@
+package migrations
+
+import "github.com/BurntSushi/migration"
+
+func CreatePipelineConfigRevisions(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE pipeline_config_revisions (
+			pipeline_id integer NOT NULL REFERENCES pipelines (id) ON DELETE CASCADE,
+			version integer NOT NULL,
+			config text NOT NULL,
+			author text NOT NULL,
+			created_at timestamp with time zone NOT NULL DEFAULT now(),
+			PRIMARY KEY (pipeline_id, version)
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	// who saved the configs that are already there is lost to history, but
+	// they can still be reverted to
+	_, err = tx.Exec(`
+		INSERT INTO pipeline_config_revisions (pipeline_id, version, config, author)
+		SELECT id, version, config, ''
+		FROM pipelines
+	`)
+	return err
+}
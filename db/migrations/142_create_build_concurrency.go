@@ -0,0 +1,12 @@
+package migrations
+
+import "github.com/BurntSushi/migration"
+
+func CreateBuildConcurrency(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE build_concurrency (
+			max_in_flight integer NOT NULL DEFAULT 0
+		)
+	`)
+	return err
+}
@@ -0,0 +1,23 @@
+package migrations
+
+import "github.com/BurntSushi/migration"
+
+func CreateBuildComments(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE build_comments (
+			id serial PRIMARY KEY,
+			build_id integer NOT NULL REFERENCES builds (id) ON DELETE CASCADE,
+			author text NOT NULL,
+			text text NOT NULL,
+			created_at timestamp with time zone NOT NULL DEFAULT now()
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		CREATE INDEX build_comments_build_id_idx ON build_comments (build_id)
+	`)
+	return err
+}
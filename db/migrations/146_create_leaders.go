@@ -0,0 +1,14 @@
+package migrations
+
+import "github.com/BurntSushi/migration"
+
+func CreateLeaders(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE leaders (
+			name text PRIMARY KEY,
+			holder text NOT NULL,
+			expires_at timestamp with time zone NOT NULL
+		)
+	`)
+	return err
+}
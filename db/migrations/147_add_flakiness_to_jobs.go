@@ -0,0 +1,12 @@
+package migrations
+
+import "github.com/BurntSushi/migration"
+
+func AddFlakinessToJobs(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		ALTER TABLE jobs
+		ADD COLUMN flakiness double precision NOT NULL DEFAULT 0,
+		ADD COLUMN flaky boolean NOT NULL DEFAULT false
+	`)
+	return err
+}
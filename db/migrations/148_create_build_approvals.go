@@ -0,0 +1,19 @@
+package migrations
+
+import "github.com/BurntSushi/migration"
+
+func CreateBuildApprovals(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE build_approvals (
+			build_id integer NOT NULL REFERENCES builds (id) ON DELETE CASCADE,
+			name text NOT NULL,
+			plan_id text NOT NULL,
+			status text NOT NULL DEFAULT 'pending',
+			approver text,
+			requested_at timestamp with time zone NOT NULL DEFAULT now(),
+			decided_at timestamp with time zone,
+			PRIMARY KEY (build_id, name)
+		)
+	`)
+	return err
+}
@@ -0,0 +1,10 @@
+package migrations
+
+import "github.com/BurntSushi/migration"
+
+func AddParamsToBuilds(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		ALTER TABLE builds ADD COLUMN params text
+	`)
+	return err
+}
@@ -0,0 +1,10 @@
+package migrations
+
+import "github.com/BurntSushi/migration"
+
+func AddSecretsToBuilds(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		ALTER TABLE builds ADD COLUMN secrets text
+	`)
+	return err
+}
@@ -0,0 +1,14 @@
+package migrations
+
+import "github.com/BurntSushi/migration"
+
+func CreateRegisteredResourceTypes(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE registered_resource_types (
+			name text PRIMARY KEY,
+			image text NOT NULL,
+			version text NOT NULL
+		)
+	`)
+	return err
+}
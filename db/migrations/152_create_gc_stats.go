@@ -0,0 +1,28 @@
+package migrations
+
+import "github.com/BurntSushi/migration"
+
+func CreateGCStats(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE worker_gc_stats (
+			worker_name text PRIMARY KEY,
+			containers_created integer NOT NULL DEFAULT 0,
+			containers_destroyed integer NOT NULL DEFAULT 0,
+			containers_orphaned integer NOT NULL DEFAULT 0,
+			volumes_created integer NOT NULL DEFAULT 0,
+			volumes_destroyed integer NOT NULL DEFAULT 0,
+			volumes_orphaned integer NOT NULL DEFAULT 0
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		CREATE TABLE last_gc_pass (
+			started_at timestamp with time zone NOT NULL,
+			finished_at timestamp with time zone NOT NULL
+		)
+	`)
+	return err
+}
@@ -0,0 +1,13 @@
+package migrations
+
+import "github.com/BurntSushi/migration"
+
+func AddSchedulingToJobs(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		ALTER TABLE jobs
+		ADD COLUMN scheduling_reason text NOT NULL DEFAULT '',
+		ADD COLUMN missing_input_reasons text NOT NULL DEFAULT '{}',
+		ADD COLUMN scheduling_checked_at timestamp with time zone
+	`)
+	return err
+}
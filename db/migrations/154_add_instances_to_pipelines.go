@@ -0,0 +1,19 @@
+package migrations
+
+import "github.com/BurntSushi/migration"
+
+func AddInstancesToPipelines(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		ALTER TABLE pipelines
+		ADD COLUMN instance_of integer REFERENCES pipelines (id) ON DELETE CASCADE,
+		ADD COLUMN instance_vars text NOT NULL DEFAULT '{}'
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		CREATE INDEX pipelines_instance_of ON pipelines (instance_of)
+	`)
+	return err
+}
@@ -0,0 +1,62 @@
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/BurntSushi/migration"
+)
+
+func AddCompressedPayloadToBuildEvents(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		ALTER TABLE build_events
+		ALTER COLUMN payload DROP NOT NULL,
+		ADD COLUMN compressed_payload bytea,
+		ADD COLUMN log_search tsvector
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		CREATE INDEX build_events_compressed_log_search_idx
+		ON build_events
+		USING gin (log_search)
+		WHERE type = 'log'
+	`)
+	if err != nil {
+		return err
+	}
+
+	rows, err := tx.Query(`SELECT id FROM pipelines`)
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	var pipelineIDs []int
+
+	for rows.Next() {
+		var pipelineID int
+		err = rows.Scan(&pipelineID)
+		if err != nil {
+			return fmt.Errorf("failed to scan pipeline ID: %s", err)
+		}
+
+		pipelineIDs = append(pipelineIDs, pipelineID)
+	}
+
+	for _, pipelineID := range pipelineIDs {
+		_, err = tx.Exec(fmt.Sprintf(`
+			CREATE INDEX pipeline_build_events_%[1]d_compressed_log_search
+			ON pipeline_build_events_%[1]d
+			USING gin (log_search)
+			WHERE type = 'log'
+		`, pipelineID))
+		if err != nil {
+			return fmt.Errorf("failed to create compressed log search index: %s", err)
+		}
+	}
+
+	return nil
+}
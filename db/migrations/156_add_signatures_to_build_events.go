@@ -0,0 +1,24 @@
+package migrations
+
+import "github.com/BurntSushi/migration"
+
+func AddSignaturesToBuildEvents(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		ALTER TABLE build_events
+		ADD COLUMN signature bytea
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		CREATE TABLE build_event_anchors (
+			build_id integer NOT NULL REFERENCES builds (id) ON DELETE CASCADE,
+			event_id integer NOT NULL,
+			signature bytea NOT NULL,
+			anchored_at timestamp with time zone NOT NULL DEFAULT now(),
+			PRIMARY KEY (build_id, event_id)
+		)
+	`)
+	return err
+}
@@ -0,0 +1,18 @@
+package migrations
+
+import "github.com/BurntSushi/migration"
+
+func CreateBuildSteps(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE build_steps (
+			build_id integer NOT NULL REFERENCES builds (id) ON DELETE CASCADE,
+			plan_id text NOT NULL,
+			type text,
+			status text NOT NULL DEFAULT 'started',
+			started_at timestamp with time zone NOT NULL,
+			finished_at timestamp with time zone,
+			PRIMARY KEY (build_id, plan_id)
+		)
+	`)
+	return err
+}
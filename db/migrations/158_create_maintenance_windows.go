@@ -0,0 +1,25 @@
+package migrations
+
+import "github.com/BurntSushi/migration"
+
+func CreateMaintenanceWindows(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE maintenance_windows (
+			name text PRIMARY KEY,
+			schedule text,
+			duration_seconds integer,
+			starts_at timestamp with time zone,
+			ends_at timestamp with time zone
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		CREATE TABLE maintenance_overrides (
+			until timestamp with time zone NOT NULL
+		)
+	`)
+	return err
+}
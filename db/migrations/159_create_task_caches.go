@@ -0,0 +1,17 @@
+package migrations
+
+import "github.com/BurntSushi/migration"
+
+func CreateTaskCaches(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE task_caches (
+			pipeline_id integer NOT NULL REFERENCES pipelines (id) ON DELETE CASCADE,
+			job_name text NOT NULL,
+			path text NOT NULL,
+			size_bytes bigint NOT NULL DEFAULT 0,
+			saved_at timestamp with time zone NOT NULL DEFAULT now(),
+			PRIMARY KEY (pipeline_id, job_name, path)
+		)
+	`)
+	return err
+}
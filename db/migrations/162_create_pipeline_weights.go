@@ -0,0 +1,13 @@
+package migrations
+
+import "github.com/BurntSushi/migration"
+
+func CreatePipelineWeights(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE pipeline_weights (
+			pipeline_id integer PRIMARY KEY REFERENCES pipelines (id) ON DELETE CASCADE,
+			weight integer NOT NULL
+		)
+	`)
+	return err
+}
@@ -0,0 +1,35 @@
+package migrations
+
+import "github.com/BurntSushi/migration"
+
+func AddResourceVersionIndexes(tx migration.LimitedTx) error {
+	// versions are always listed by check order, so have the index do the
+	// sorting; it covers looking them up by resource alone too
+	_, err := tx.Exec(`
+		CREATE INDEX versioned_resources_resource_id_check_order_idx ON versioned_resources (resource_id, check_order)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		DROP INDEX versioned_resources_resource_id_idx
+	`)
+	if err != nil {
+		return err
+	}
+
+	// the existing indexes lead with the build, which is no help in finding
+	// whether any build used a version
+	_, err = tx.Exec(`
+		CREATE INDEX build_inputs_versioned_resource_id_idx ON build_inputs (versioned_resource_id)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		CREATE INDEX build_outputs_versioned_resource_id_idx ON build_outputs (versioned_resource_id)
+	`)
+	return err
+}
@@ -0,0 +1,19 @@
+package migrations
+
+import "github.com/BurntSushi/migration"
+
+func AddCreateTimeToBuilds(tx migration.LimitedTx) error {
+	// existing builds are left without one rather than all being given the
+	// time of the migration
+	_, err := tx.Exec(`
+		ALTER TABLE builds ADD COLUMN create_time timestamp with time zone
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		ALTER TABLE builds ALTER COLUMN create_time SET DEFAULT now()
+	`)
+	return err
+}
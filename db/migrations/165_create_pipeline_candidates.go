@@ -0,0 +1,26 @@
+package migrations
+
+import "github.com/BurntSushi/migration"
+
+func CreatePipelineCandidates(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE pipeline_candidates (
+			id serial PRIMARY KEY,
+			pipeline_id integer NOT NULL UNIQUE REFERENCES pipelines (id) ON DELETE CASCADE,
+			config text NOT NULL,
+			percentage integer NOT NULL,
+			author text NOT NULL,
+			created_at timestamp with time zone NOT NULL DEFAULT now()
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	// not a foreign key; builds of a candidate that has since been replaced
+	// shouldn't start counting as builds of the current config
+	_, err = tx.Exec(`
+		ALTER TABLE builds ADD COLUMN candidate_id integer
+	`)
+	return err
+}
@@ -0,0 +1,17 @@
+package migrations
+
+import "github.com/BurntSushi/migration"
+
+func CreateBuildDurationAlerts(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE build_duration_alerts (
+			id serial PRIMARY KEY,
+			build_id integer NOT NULL UNIQUE REFERENCES builds (id) ON DELETE CASCADE,
+			percentile integer NOT NULL,
+			threshold_seconds double precision NOT NULL,
+			elapsed_seconds double precision NOT NULL,
+			created_at timestamp with time zone NOT NULL DEFAULT now()
+		)
+	`)
+	return err
+}
@@ -0,0 +1,12 @@
+package migrations
+
+import "github.com/BurntSushi/migration"
+
+func AddArtifactRetentionToBuilds(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		ALTER TABLE builds
+		ADD COLUMN artifacts_protected boolean NOT NULL DEFAULT false,
+		ADD COLUMN artifacts_reaped_at timestamp with time zone
+	`)
+	return err
+}
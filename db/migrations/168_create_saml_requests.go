@@ -0,0 +1,25 @@
+package migrations
+
+import "github.com/BurntSushi/migration"
+
+func CreateSAMLRequests(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE saml_requests (
+			id text PRIMARY KEY,
+			team_id integer NOT NULL REFERENCES teams (id) ON DELETE CASCADE,
+			redirect text NOT NULL,
+			expires_at timestamp with time zone NOT NULL
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		CREATE TABLE saml_assertions (
+			id text PRIMARY KEY,
+			expires_at timestamp with time zone NOT NULL
+		)
+	`)
+	return err
+}
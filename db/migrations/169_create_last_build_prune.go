@@ -0,0 +1,13 @@
+package migrations
+
+import "github.com/BurntSushi/migration"
+
+func CreateLastBuildPrune(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE last_build_prune (
+			pruned_at timestamp with time zone NOT NULL,
+			builds integer NOT NULL
+		)
+	`)
+	return err
+}
@@ -123,4 +123,51 @@ var Migrations = []migration.Migrator{
 	AddGenericOAuthToTeams,
 	MigrateFromLeasesToLocks,
 	AddTeamNameToPipe,
+	AddLabelsToBuilds,
+	AddLogTruncatedToBuilds,
+	CreateBuildArtifacts,
+	AddPriorityToBuilds,
+	AddBuildLogSearchIndexes,
+	CreateAuditEvents,
+	AddTimeToBuildEvents,
+	AddResourceUsageToBuilds,
+	AddLastScheduledAtToJobs,
+	CreateWebhooks,
+	CreateBuildDependencies,
+	CreateAPITokens,
+	AddRerunOfToBuilds,
+	AddTimedOutBuildStatus,
+	AddTimeoutToBuilds,
+	AddPinnedVersionToResources,
+	AddManualOnlyToJobs,
+	CreatePipelineConfigRevisions,
+	AddCheckErroredAtToResources,
+	CreateBuildConcurrency,
+	AddRolesToTeams,
+	AddOIDCAuthToTeams,
+	CreateBuildComments,
+	CreateLeaders,
+	AddFlakinessToJobs,
+	CreateBuildApprovals,
+	AddParamsToBuilds,
+	AddSecretsToBuilds,
+	CreateRegisteredResourceTypes,
+	CreateGCStats,
+	AddSchedulingToJobs,
+	AddInstancesToPipelines,
+	AddCompressedPayloadToBuildEvents,
+	AddSignaturesToBuildEvents,
+	CreateBuildSteps,
+	CreateMaintenanceWindows,
+	CreateTaskCaches,
+	AddPermissionsToPipelines,
+	AddSAMLAuthToTeams,
+	CreatePipelineWeights,
+	AddResourceVersionIndexes,
+	AddCreateTimeToBuilds,
+	CreatePipelineCandidates,
+	CreateBuildDurationAlerts,
+	AddArtifactRetentionToBuilds,
+	CreateSAMLRequests,
+	CreateLastBuildPrune,
 }
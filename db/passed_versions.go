@@ -0,0 +1,102 @@
+package db
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/concourse/atc/db/algorithm"
+)
+
+// GetVersionsPassedJobs returns the versions of the resource that have made
+// it through every one of the jobs, newest first. They're worked out the same
+// way the scheduler works out which versions satisfy an input's passed
+// constraint, so a job with that constraint could run with any of them.
+//
+// It returns false if the resource or any of the jobs don't exist.
+func (pdb *pipelineDB) GetVersionsPassedJobs(resourceName string, jobNames []string) ([]SavedVersionedResource, bool, error) {
+	versionsDB, err := pdb.LoadVersionsDB()
+	if err != nil {
+		return nil, false, err
+	}
+
+	resourceID, found := versionsDB.ResourceIDs[resourceName]
+	if !found {
+		return nil, false, nil
+	}
+
+	passed := algorithm.JobSet{}
+	for _, jobName := range jobNames {
+		jobID, found := versionsDB.JobIDs[jobName]
+		if !found {
+			return nil, false, nil
+		}
+
+		passed[jobID] = struct{}{}
+	}
+
+	versionIDs := versionsDB.VersionsOfResourcePassedJobs(resourceID, passed).VersionIDs()
+	if len(versionIDs) == 0 {
+		return []SavedVersionedResource{}, true, nil
+	}
+
+	placeholders := make([]string, len(versionIDs))
+	args := make([]interface{}, len(versionIDs))
+	for i, versionID := range versionIDs {
+		placeholders[i] = "$" + strconv.Itoa(i+1)
+		args[i] = versionID
+	}
+
+	rows, err := pdb.conn.Query(`
+		SELECT v.id, v.enabled, v.type, v.version, v.metadata, r.name, v.check_order
+		FROM versioned_resources v
+		INNER JOIN resources r ON v.resource_id = r.id
+		WHERE v.id IN (`+strings.Join(placeholders, ",")+`)
+		ORDER BY v.check_order DESC
+	`, args...)
+	if err != nil {
+		return nil, false, err
+	}
+
+	defer rows.Close()
+
+	versions := []SavedVersionedResource{}
+	for rows.Next() {
+		var version SavedVersionedResource
+		var versionString, metadataString string
+
+		err := rows.Scan(
+			&version.ID,
+			&version.Enabled,
+			&version.Type,
+			&versionString,
+			&metadataString,
+			&version.Resource,
+			&version.CheckOrder,
+		)
+		if err != nil {
+			return nil, false, err
+		}
+
+		err = json.Unmarshal([]byte(versionString), &version.Version)
+		if err != nil {
+			return nil, false, err
+		}
+
+		err = json.Unmarshal([]byte(metadataString), &version.Metadata)
+		if err != nil {
+			return nil, false, err
+		}
+
+		version.PipelineID = pdb.GetPipelineID()
+
+		versions = append(versions, version)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, false, err
+	}
+
+	return versions, true, nil
+}
@@ -1,6 +1,10 @@
 package db
 
-import "github.com/concourse/atc"
+import (
+	"time"
+
+	"github.com/concourse/atc"
+)
 
 type Pipeline struct {
 	Name    string
@@ -15,5 +19,21 @@ type SavedPipeline struct {
 	TeamID   int
 	TeamName string
 
+	// InstanceOf is the ID of the pipeline whose config this is an instance
+	// of, filled in with InstanceVars, or 0 if it isn't an instance.
+	InstanceOf   int
+	InstanceVars map[string]interface{}
+
+	// Permissions raise the role needed to view, trigger or configure the
+	// pipeline.
+	Permissions atc.PipelinePermissions
+
 	Pipeline
 }
+
+type ConfigRevision struct {
+	Version   ConfigVersion
+	Config    atc.Config
+	Author    string
+	CreatedAt time.Time
+}
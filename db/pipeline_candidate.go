@@ -0,0 +1,334 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/concourse/atc"
+)
+
+// CandidateConfig is a config being tried out on a pipeline before it's
+// saved as the pipeline's config. The scheduler builds Percentage percent of
+// the pipeline's builds from it instead.
+type CandidateConfig struct {
+	ID         int
+	Config     atc.Config
+	Percentage int
+	Author     string
+	CreatedAt  time.Time
+}
+
+// CandidateComparison is how a job's builds have gone since the candidate was
+// saved, from the current config and from the candidate.
+type CandidateComparison struct {
+	JobName   string
+	Current   CandidateBuildStats
+	Candidate CandidateBuildStats
+}
+
+// CandidateBuildStats counts finished builds by status. AverageDuration is of
+// the builds that got to start.
+type CandidateBuildStats struct {
+	Builds    int
+	Succeeded int
+	Failed    int
+	Errored   int
+	Aborted   int
+
+	AverageDuration time.Duration
+}
+
+type candidateDurations struct {
+	seconds float64
+	timed   int
+}
+
+// SaveCandidateConfig saves the config as the pipeline's candidate, replacing
+// any candidate it already has. A replaced candidate's builds no longer count
+// towards the comparison.
+func (db *teamDB) SaveCandidateConfig(pipelineName string, config atc.Config, percentage int, author string) (bool, error) {
+	pipeline, found, err := db.GetPipelineByName(pipelineName)
+	if err != nil {
+		return false, err
+	}
+
+	if !found {
+		return false, nil
+	}
+
+	// instances take their config from their template
+	if pipeline.InstanceOf != 0 {
+		return false, ErrPipelineIsInstance
+	}
+
+	configBlob, err := json.Marshal(config)
+	if err != nil {
+		return false, err
+	}
+
+	payload, err := db.conn.EncryptionStrategy().Encrypt(configBlob)
+	if err != nil {
+		return false, err
+	}
+
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return false, err
+	}
+
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		DELETE FROM pipeline_candidates
+		WHERE pipeline_id = $1
+	`, pipeline.ID)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO pipeline_candidates (pipeline_id, config, percentage, author)
+		VALUES ($1, $2, $3, $4)
+	`, pipeline.ID, payload, percentage, author)
+	if err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+func (db *teamDB) GetCandidateConfig(pipelineName string) (CandidateConfig, bool, error) {
+	pipeline, found, err := db.GetPipelineByName(pipelineName)
+	if err != nil {
+		return CandidateConfig{}, false, err
+	}
+
+	if !found {
+		return CandidateConfig{}, false, nil
+	}
+
+	return getCandidateConfig(db.conn, pipeline.ID)
+}
+
+func (db *teamDB) DeleteCandidateConfig(pipelineName string) (bool, error) {
+	pipeline, found, err := db.GetPipelineByName(pipelineName)
+	if err != nil {
+		return false, err
+	}
+
+	if !found {
+		return false, nil
+	}
+
+	result, err := db.conn.Exec(`
+		DELETE FROM pipeline_candidates
+		WHERE pipeline_id = $1
+	`, pipeline.ID)
+	if err != nil {
+		return false, err
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return deleted > 0, nil
+}
+
+// PromoteCandidateConfig saves the pipeline's candidate as its config and
+// stops trying it out.
+func (db *teamDB) PromoteCandidateConfig(pipelineName string, author string) (SavedPipeline, bool, error) {
+	pipeline, found, err := db.GetPipelineByName(pipelineName)
+	if err != nil {
+		return SavedPipeline{}, false, err
+	}
+
+	if !found {
+		return SavedPipeline{}, false, nil
+	}
+
+	candidate, found, err := getCandidateConfig(db.conn, pipeline.ID)
+	if err != nil {
+		return SavedPipeline{}, false, err
+	}
+
+	if !found {
+		return SavedPipeline{}, false, nil
+	}
+
+	savedPipeline, _, err := db.SaveConfig(pipelineName, candidate.Config, pipeline.Version, PipelineNoChange, author)
+	if err != nil {
+		return SavedPipeline{}, false, err
+	}
+
+	_, err = db.conn.Exec(`
+		DELETE FROM pipeline_candidates
+		WHERE id = $1
+	`, candidate.ID)
+	if err != nil {
+		return SavedPipeline{}, false, err
+	}
+
+	return savedPipeline, true, nil
+}
+
+// GetCandidateComparison compares the builds of each job that have finished
+// since the candidate was saved, by job name.
+func (db *teamDB) GetCandidateComparison(pipelineName string) ([]CandidateComparison, bool, error) {
+	pipeline, found, err := db.GetPipelineByName(pipelineName)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !found {
+		return nil, false, nil
+	}
+
+	candidate, found, err := getCandidateConfig(db.conn, pipeline.ID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !found {
+		return nil, false, nil
+	}
+
+	rows, err := db.conn.Query(`
+		SELECT j.name, b.candidate_id IS NOT NULL, b.status, COUNT(*),
+			COUNT(b.start_time),
+			COALESCE(SUM(EXTRACT(EPOCH FROM b.end_time - b.start_time)), 0)
+		FROM builds b
+		INNER JOIN jobs j ON j.id = b.job_id
+		WHERE j.pipeline_id = $1
+		AND b.create_time >= $2
+		AND (b.candidate_id IS NULL OR b.candidate_id = $3)
+		AND b.status IN ('succeeded', 'failed', 'errored', 'aborted')
+		GROUP BY j.name, b.candidate_id IS NOT NULL, b.status
+	`, pipeline.ID, candidate.CreatedAt, candidate.ID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	defer rows.Close()
+
+	comparisons := map[string]*CandidateComparison{}
+	durations := map[*CandidateBuildStats]*candidateDurations{}
+
+	for rows.Next() {
+		var jobName string
+		var fromCandidate bool
+		var status string
+		var count int
+		var timed int
+		var seconds float64
+
+		err := rows.Scan(&jobName, &fromCandidate, &status, &count, &timed, &seconds)
+		if err != nil {
+			return nil, false, err
+		}
+
+		comparison, ok := comparisons[jobName]
+		if !ok {
+			comparison = &CandidateComparison{JobName: jobName}
+			comparisons[jobName] = comparison
+		}
+
+		stats := &comparison.Current
+		if fromCandidate {
+			stats = &comparison.Candidate
+		}
+
+		switch Status(status) {
+		case StatusSucceeded:
+			stats.Succeeded += count
+		case StatusFailed:
+			stats.Failed += count
+		case StatusErrored:
+			stats.Errored += count
+		case StatusAborted:
+			stats.Aborted += count
+		}
+
+		stats.Builds += count
+
+		total, ok := durations[stats]
+		if !ok {
+			total = &candidateDurations{}
+			durations[stats] = total
+		}
+
+		total.seconds += seconds
+		total.timed += timed
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, false, err
+	}
+
+	result := []CandidateComparison{}
+	for _, comparison := range comparisons {
+		for _, stats := range []*CandidateBuildStats{&comparison.Current, &comparison.Candidate} {
+			total, ok := durations[stats]
+			if ok && total.timed > 0 {
+				stats.AverageDuration = time.Duration(total.seconds/float64(total.timed)) * time.Second
+			}
+		}
+
+		result = append(result, *comparison)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].JobName < result[j].JobName
+	})
+
+	return result, true, nil
+}
+
+func (pdb *pipelineDB) GetCandidateConfig() (CandidateConfig, bool, error) {
+	return getCandidateConfig(pdb.conn, pdb.ID)
+}
+
+// UseCandidateConfigForBuild records that the build was planned from the
+// candidate rather than the pipeline's config.
+func (pdb *pipelineDB) UseCandidateConfigForBuild(buildID int, candidateID int) error {
+	_, err := pdb.conn.Exec(`
+		UPDATE builds
+		SET candidate_id = $2
+		WHERE id = $1
+	`, buildID, candidateID)
+	return err
+}
+
+func getCandidateConfig(conn Conn, pipelineID int) (CandidateConfig, bool, error) {
+	var candidate CandidateConfig
+	var configBlob []byte
+
+	err := conn.QueryRow(`
+		SELECT id, config, percentage, author, created_at
+		FROM pipeline_candidates
+		WHERE pipeline_id = $1
+	`, pipelineID).Scan(&candidate.ID, &configBlob, &candidate.Percentage, &candidate.Author, &candidate.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return CandidateConfig{}, false, nil
+		}
+
+		return CandidateConfig{}, false, err
+	}
+
+	decrypted, err := conn.EncryptionStrategy().Decrypt(string(configBlob))
+	if err != nil {
+		return CandidateConfig{}, false, err
+	}
+
+	err = json.Unmarshal(decrypted, &candidate.Config)
+	if err != nil {
+		return CandidateConfig{}, false, err
+	}
+
+	return candidate, true, nil
+}
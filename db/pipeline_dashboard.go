@@ -0,0 +1,219 @@
+package db
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+
+	"github.com/concourse/atc"
+)
+
+// PipelineDashboard is a pipeline and the state of each of its jobs, in the
+// order they're configured.
+type PipelineDashboard struct {
+	Pipeline SavedPipeline
+	Jobs     []PipelineDashboardJob
+}
+
+type PipelineDashboardJob struct {
+	Job       SavedJob
+	JobConfig atc.JobConfig
+
+	// FinishedBuild is nil if the job has never finished a build.
+	FinishedBuild Build
+	RunningBuilds []Build
+}
+
+// withColumns has the columns selected after those that row is scanned for
+// scanned into extra, so that a query can join more onto what's scanned by
+// e.g. scanPipeline.
+type withColumns struct {
+	row   scannable
+	extra []interface{}
+}
+
+func (row withColumns) Scan(dest ...interface{}) error {
+	return row.row.Scan(append(dest, row.extra...)...)
+}
+
+type dashboardBuildKey struct {
+	pipelineID int
+	jobName    string
+}
+
+// getPipelineDashboards returns every pipeline of the team, followed by the
+// public pipelines of every other team. The pipelines are fetched with their
+// jobs, and then the builds of all of them, so it takes the same two queries
+// however many pipelines and jobs there are. Pipelines are returned whatever
+// their permissions say about viewing them, as the role isn't known here.
+func getPipelineDashboards(conn Conn, buildFactory *buildFactory, teamName string) ([]PipelineDashboard, error) {
+	rows, err := conn.Query(`
+		SELECT `+pipelineColumns+`, j.name, j.id, j.paused, j.manual_only, j.first_logged_build_id, j.flakiness, j.flaky
+		FROM pipelines p
+		INNER JOIN teams t ON t.id = p.team_id
+		LEFT OUTER JOIN jobs j ON j.pipeline_id = p.id
+		WHERE LOWER(t.name) = LOWER($1)
+			OR p.public = true
+		ORDER BY LOWER(t.name) != LOWER($1), t.name, p.ordering, j.id
+	`, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	dashboards := []PipelineDashboard{}
+	savedJobs := map[int]map[string]SavedJob{}
+
+	for rows.Next() {
+		var jobName sql.NullString
+		var jobID, firstLoggedBuildID sql.NullInt64
+		var paused, manualOnly, flaky sql.NullBool
+		var flakiness sql.NullFloat64
+
+		pipeline, err := scanPipeline(withColumns{
+			row:   rows,
+			extra: []interface{}{&jobName, &jobID, &paused, &manualOnly, &firstLoggedBuildID, &flakiness, &flaky},
+		}, conn.EncryptionStrategy())
+		if err != nil {
+			return nil, err
+		}
+
+		jobs, seen := savedJobs[pipeline.ID]
+		if !seen {
+			jobs = map[string]SavedJob{}
+			savedJobs[pipeline.ID] = jobs
+
+			dashboards = append(dashboards, PipelineDashboard{Pipeline: pipeline})
+		}
+
+		if jobName.Valid {
+			jobs[jobName.String] = SavedJob{
+				ID:                 int(jobID.Int64),
+				Paused:             paused.Bool,
+				ManualOnly:         manualOnly.Bool,
+				PipelineName:       pipeline.Name,
+				FirstLoggedBuildID: int(firstLoggedBuildID.Int64),
+				TeamID:             pipeline.TeamID,
+				Flakiness:          flakiness.Float64,
+				Flaky:              flaky.Bool,
+				Job: Job{
+					Name: jobName.String,
+				},
+			}
+		}
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	finishedBuilds, runningBuilds, err := getDashboardBuilds(conn, buildFactory, dashboards)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, dashboard := range dashboards {
+		jobs := savedJobs[dashboard.Pipeline.ID]
+
+		dashboardJobs := []PipelineDashboardJob{}
+
+		// jobs no longer in the config are left out
+		for _, jobConfig := range dashboard.Pipeline.Config.Jobs {
+			savedJob, found := jobs[jobConfig.Name]
+			if !found {
+				continue
+			}
+
+			key := dashboardBuildKey{dashboard.Pipeline.ID, jobConfig.Name}
+
+			running := runningBuilds[key]
+			if running == nil {
+				running = []Build{}
+			}
+
+			dashboardJobs = append(dashboardJobs, PipelineDashboardJob{
+				Job:           savedJob,
+				JobConfig:     jobConfig,
+				FinishedBuild: finishedBuilds[key],
+				RunningBuilds: running,
+			})
+		}
+
+		dashboards[i].Jobs = dashboardJobs
+	}
+
+	return dashboards, nil
+}
+
+// getDashboardBuilds returns the last finished build of each job of the
+// pipelines, along with every build of theirs that's running.
+func getDashboardBuilds(conn Conn, buildFactory *buildFactory, dashboards []PipelineDashboard) (map[dashboardBuildKey]Build, map[dashboardBuildKey][]Build, error) {
+	finishedBuilds := map[dashboardBuildKey]Build{}
+	runningBuilds := map[dashboardBuildKey][]Build{}
+
+	if len(dashboards) == 0 {
+		return finishedBuilds, runningBuilds, nil
+	}
+
+	placeholders := make([]string, len(dashboards))
+	pipelineIDs := make([]interface{}, len(dashboards))
+	for i, dashboard := range dashboards {
+		placeholders[i] = "$" + strconv.Itoa(i+1)
+		pipelineIDs[i] = dashboard.Pipeline.ID
+	}
+
+	inPipelines := "(" + strings.Join(placeholders, ",") + ")"
+
+	rows, err := conn.Query(`
+		SELECT `+qualifiedBuildColumns+`
+		FROM builds b
+		INNER JOIN jobs j ON b.job_id = j.id
+		INNER JOIN pipelines p ON j.pipeline_id = p.id
+		INNER JOIN teams t ON b.team_id = t.id
+		WHERE j.pipeline_id IN `+inPipelines+`
+			AND (
+				b.status = 'started'
+				OR b.id IN (
+					SELECT MAX(fb.id)
+					FROM builds fb
+					INNER JOIN jobs fj ON fb.job_id = fj.id
+					WHERE fj.pipeline_id IN `+inPipelines+`
+						AND fb.status NOT IN ('pending', 'started')
+					GROUP BY fb.job_id
+				)
+			)
+		ORDER BY b.id DESC
+	`, pipelineIDs...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		build, _, err := buildFactory.ScanBuild(rows)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		key := dashboardBuildKey{build.PipelineID(), build.JobName()}
+
+		if build.IsRunning() {
+			runningBuilds[key] = append(runningBuilds[key], build)
+		} else {
+			finishedBuilds[key] = build
+		}
+	}
+
+	return finishedBuilds, runningBuilds, rows.Err()
+}
+
+func (db *teamDB) GetPipelineDashboards() ([]PipelineDashboard, error) {
+	return getPipelineDashboards(db.conn, db.buildFactory, db.teamName)
+}
+
+func (db *SQLDB) GetPublicPipelineDashboards() ([]PipelineDashboard, error) {
+	return getPipelineDashboards(db.conn, db.buildFactory, "")
+}
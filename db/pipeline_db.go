@@ -11,6 +11,7 @@ import (
 	"code.cloudfoundry.org/lager"
 	"github.com/concourse/atc"
 	"github.com/concourse/atc/db/algorithm"
+	"github.com/lib/pq"
 )
 
 //go:generate counterfeiter . PipelineDB
@@ -33,15 +34,22 @@ type PipelineDB interface {
 	GetConfig() (atc.Config, ConfigVersion, bool, error)
 
 	AcquireSchedulingLock(lager.Logger, time.Duration) (Lock, bool, error)
+	SchedulingNotifier() (Notifier, error)
 
 	GetResource(resourceName string) (SavedResource, bool, error)
 	GetResources() ([]DashboardResource, atc.GroupConfigs, bool, error)
 	GetResourceType(resourceTypeName string) (SavedResourceType, bool, error)
+	GetRegisteredResourceTypes() ([]atc.RegisteredResourceType, error)
 	GetResourceVersions(resourceName string, page Page) ([]SavedVersionedResource, Pagination, bool, error)
+	GetAllResourceVersions(resourceName string) ([]SavedVersionedResource, error)
+	ImportResourceVersions(config atc.ResourceConfig, versions []SavedVersionedResource) error
 
 	PauseResource(resourceName string) error
 	UnpauseResource(resourceName string) error
 
+	PinResourceVersion(resourceName string, versionedResourceID int) (bool, error)
+	UnpinResource(resourceName string) error
+
 	SaveResourceVersions(atc.ResourceConfig, []atc.Version) error
 	SaveResourceTypeVersion(atc.ResourceType, atc.Version) error
 	GetLatestVersionedResource(resourceName string) (SavedVersionedResource, bool, error)
@@ -55,16 +63,37 @@ type PipelineDB interface {
 	GetJob(job string) (SavedJob, error)
 	PauseJob(job string) error
 	UnpauseJob(job string) error
+	MakeJobManualOnly(job string) error
+	MakeJobAutomatic(job string) error
 	SetMaxInFlightReached(string, bool) error
 	UpdateFirstLoggedBuildID(job string, newFirstLoggedBuildID int) error
+	GetJobLastScheduledAt(job string) (time.Time, error)
+	SaveJobLastScheduledAt(job string, previous time.Time, at time.Time) (bool, error)
 
 	GetJobFinishedAndNextBuild(job string) (Build, Build, error)
+	GetJobBuildStats(job string, window time.Duration) (JobBuildStats, error)
+	GetJobOutcomes(job string, builds int) (JobOutcomes, error)
+	SaveJobFlakiness(job string, flakiness float64, flaky bool) error
+	SaveSchedulingReason(job string, reason SchedulingReason) error
+	SaveMissingInputReasons(job string, reasons MissingInputReasons) error
+	GetJobScheduling(job string) (JobScheduling, bool, error)
+	GetJobLatestFinishedBuildWithInput(job string, resourceName string, version atc.Version) (Build, bool, error)
+	GetTaskCaches(job string) ([]TaskCache, error)
+	DeleteTaskCaches(job string, path string) ([]TaskCache, error)
+
+	GetJobArtifactBuilds(job string) ([]ArtifactBuild, error)
+	SetBuildArtifactsProtected(job string, buildName string, protected bool) (bool, error)
+
+	GetCandidateConfig() (CandidateConfig, bool, error)
+	UseCandidateConfigForBuild(buildID int, candidateID int) error
 
 	GetJobBuilds(job string, page Page) ([]Build, Pagination, error)
 	GetAllJobBuilds(job string) ([]Build, error)
 
 	GetJobBuild(job string, build string) (Build, bool, error)
 	CreateJobBuild(job string) (Build, error)
+	CreateJobBuildWithParams(job string, params map[string]string) (Build, error)
+	ImportJobBuild(job string, build ImportedBuild) (Build, error)
 	EnsurePendingBuildExists(jobName string) error
 	GetNextPendingBuild(jobName string) (Build, bool, error)
 	UseInputsForBuild(buildID int, inputs []BuildInput) error
@@ -80,22 +109,26 @@ type PipelineDB interface {
 
 	GetRunningBuildsBySerialGroup(jobName string, serialGroups []string) ([]Build, error)
 	GetNextPendingBuildBySerialGroup(jobName string, serialGroups []string) (Build, bool, error)
+	GetPendingBuildPriorities() (map[string]int, error)
 
 	UpdateBuildToScheduled(buildID int) (bool, error)
 	SaveInput(buildID int, input BuildInput) (SavedVersionedResource, error)
 	SaveOutput(buildID int, vr VersionedResource, explicit bool) (SavedVersionedResource, error)
 	GetBuildsWithVersionAsInput(versionedResourceID int) ([]Build, error)
 	GetBuildsWithVersionAsOutput(versionedResourceID int) ([]Build, error)
+	GetVersionCausality(versionedResourceID int) (Causality, bool, error)
+	GetVersionsPassedJobs(resourceName string, jobNames []string) ([]SavedVersionedResource, bool, error)
 
 	GetDashboard() (Dashboard, atc.GroupConfigs, error)
 
 	Expose() error
 	Hide() error
+	SetPermissions(atc.PipelinePermissions) error
 }
 
 type pipelineDB struct {
 	conn Conn
-	bus  *notificationsBus
+	bus  NotificationsBus
 
 	SavedPipeline
 
@@ -161,7 +194,23 @@ func (pdb *pipelineDB) Unpause() error {
 		SET paused = false
 		WHERE id = $1
 	`, pdb.ID)
-	return err
+	if err != nil {
+		return err
+	}
+
+	return notifyScheduling(pdb.conn, pdb.ID)
+}
+
+// SchedulingNotifier notifies whenever something happens that could let one of
+// the pipeline's jobs schedule a build, and after losing the connection to the
+// database, as anything could have happened in the meantime.
+func (pdb *pipelineDB) SchedulingNotifier() (Notifier, error) {
+	reconnected := false
+	return newConditionNotifier(pdb.bus, schedulingChannel(pdb.ID), func() (bool, error) {
+		missed := reconnected
+		reconnected = true
+		return missed, nil
+	})
 }
 
 func (pdb *pipelineDB) Pause() error {
@@ -179,7 +228,11 @@ func (pdb *pipelineDB) UpdateName(newName string) error {
 		SET name = $1
 		WHERE id = $2
 	`, newName, pdb.ID)
-	return err
+	if err != nil {
+		return err
+	}
+
+	return notifyPipelineChanged(pdb.conn, pdb.ID)
 }
 
 func scanIDs(rows *sql.Rows) ([]string, error) {
@@ -206,21 +259,66 @@ func (pdb *pipelineDB) Destroy() error {
 
 	defer tx.Rollback()
 
+	err = destroyPipeline(tx, pdb.ID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// destroyPipeline destroys the pipeline along with its instances, if it's
+// the template of any.
+func destroyPipeline(tx Tx, pipelineID int) error {
+	rows, err := tx.Query(`
+		SELECT id
+		FROM pipelines
+		WHERE instance_of = $1
+	`, pipelineID)
+	if err != nil {
+		return err
+	}
+
+	instanceIDs := []int{}
+	for rows.Next() {
+		var id int
+		err := rows.Scan(&id)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+
+		instanceIDs = append(instanceIDs, id)
+	}
+
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return err
+	}
+
+	for _, id := range instanceIDs {
+		err := destroyPipeline(tx, id)
+		if err != nil {
+			return err
+		}
+	}
+
 	_, err = tx.Exec(fmt.Sprintf(`
 		DROP TABLE pipeline_build_events_%d
-	`, pdb.ID))
+	`, pipelineID))
 	if err != nil {
 		return err
 	}
 
 	_, err = tx.Exec(`
 		DELETE FROM pipelines WHERE id = $1;
-	`, pdb.ID)
+	`, pipelineID)
 	if err != nil {
 		return err
 	}
 
-	return tx.Commit()
+	return notifyPipelineChanged(tx, pipelineID)
 }
 
 func (pdb *pipelineDB) GetConfig() (atc.Config, ConfigVersion, bool, error) {
@@ -240,8 +338,13 @@ func (pdb *pipelineDB) GetConfig() (atc.Config, ConfigVersion, bool, error) {
 		return atc.Config{}, 0, false, err
 	}
 
+	decrypted, err := pdb.conn.EncryptionStrategy().Decrypt(string(configBlob))
+	if err != nil {
+		return atc.Config{}, 0, false, err
+	}
+
 	var config atc.Config
-	err = json.Unmarshal(configBlob, &config)
+	err = json.Unmarshal(decrypted, &config)
 	if err != nil {
 		return atc.Config{}, 0, false, err
 	}
@@ -272,7 +375,7 @@ func (pdb *pipelineDB) GetResource(resourceName string) (SavedResource, bool, er
 
 func (pdb *pipelineDB) GetResources() ([]DashboardResource, atc.GroupConfigs, bool, error) {
 	rows, err := pdb.conn.Query(`
-			SELECT id, name, check_error, paused
+			SELECT id, name, check_error, check_errored_at, paused, pinned_version_id
 			FROM resources
 			WHERE pipeline_id = $1
 		`, pdb.ID)
@@ -288,7 +391,9 @@ func (pdb *pipelineDB) GetResources() ([]DashboardResource, atc.GroupConfigs, bo
 	for rows.Next() {
 		savedResource := SavedResource{PipelineName: pdb.Name}
 		var checkErr sql.NullString
-		err := rows.Scan(&savedResource.ID, &savedResource.Name, &checkErr, &savedResource.Paused)
+		var checkErroredAt pq.NullTime
+		var pinnedVersionID sql.NullInt64
+		err := rows.Scan(&savedResource.ID, &savedResource.Name, &checkErr, &checkErroredAt, &savedResource.Paused, &pinnedVersionID)
 		if err != nil {
 			return nil, nil, false, err
 		}
@@ -296,6 +401,14 @@ func (pdb *pipelineDB) GetResources() ([]DashboardResource, atc.GroupConfigs, bo
 		if checkErr.Valid {
 			savedResource.CheckError = errors.New(checkErr.String)
 		}
+
+		if checkErroredAt.Valid {
+			savedResource.CheckErroredAt = checkErroredAt.Time
+		}
+
+		if pinnedVersionID.Valid {
+			savedResource.PinnedVersionID = int(pinnedVersionID.Int64)
+		}
 		savedResources[savedResource.Name] = savedResource
 	}
 
@@ -684,14 +797,16 @@ func (pdb *pipelineDB) GetResourceVersions(resourceName string, page Page) ([]Sa
 
 func (pdb *pipelineDB) getResource(tx Tx, name string) (SavedResource, bool, error) {
 	var checkErr sql.NullString
+	var checkErroredAt pq.NullTime
+	var pinnedVersionID sql.NullInt64
 	var resource SavedResource
 
 	err := tx.QueryRow(`
-			SELECT id, name, check_error, paused
+			SELECT id, name, check_error, check_errored_at, paused, pinned_version_id
 			FROM resources
 			WHERE name = $1
 				AND pipeline_id = $2
-		`, name, pdb.ID).Scan(&resource.ID, &resource.Name, &checkErr, &resource.Paused)
+		`, name, pdb.ID).Scan(&resource.ID, &resource.Name, &checkErr, &checkErroredAt, &resource.Paused, &pinnedVersionID)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return SavedResource{}, false, nil
@@ -706,6 +821,14 @@ func (pdb *pipelineDB) getResource(tx Tx, name string) (SavedResource, bool, err
 		resource.CheckError = errors.New(checkErr.String)
 	}
 
+	if checkErroredAt.Valid {
+		resource.CheckErroredAt = checkErroredAt.Time
+	}
+
+	if pinnedVersionID.Valid {
+		resource.PinnedVersionID = int(pinnedVersionID.Int64)
+	}
+
 	return resource, true, nil
 }
 
@@ -794,6 +917,104 @@ func (pdb *pipelineDB) updatePaused(resource string, pause bool) error {
 	return tx.Commit()
 }
 
+func (pdb *pipelineDB) PinResourceVersion(resource string, versionedResourceID int) (bool, error) {
+	tx, err := pdb.conn.Begin()
+	if err != nil {
+		return false, err
+	}
+
+	defer tx.Rollback()
+
+	err = pdb.touchPinnedVersion(tx, resource)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := tx.Exec(`
+		UPDATE resources r
+		SET pinned_version_id = v.id
+		FROM versioned_resources v
+		WHERE v.id = $1
+			AND v.resource_id = r.id
+			AND r.name = $2
+			AND r.pipeline_id = $3
+	`, versionedResourceID, resource, pdb.ID)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	if rowsAffected != 1 {
+		return false, nil
+	}
+
+	err = pdb.touchPinnedVersion(tx, resource)
+	if err != nil {
+		return false, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (pdb *pipelineDB) UnpinResource(resource string) error {
+	tx, err := pdb.conn.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	err = pdb.touchPinnedVersion(tx, resource)
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(`
+		UPDATE resources
+		SET pinned_version_id = NULL
+		WHERE name = $1
+			AND pipeline_id = $2
+	`, resource, pdb.ID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected != 1 {
+		return nonOneRowAffectedError{rowsAffected}
+	}
+
+	return tx.Commit()
+}
+
+// touchPinnedVersion bumps the modified time of the version the resource is
+// pinned to, if any, so that the cached versions DB is reloaded with the new
+// pin.
+func (pdb *pipelineDB) touchPinnedVersion(tx Tx, resource string) error {
+	_, err := tx.Exec(`
+		UPDATE versioned_resources v
+		SET modified_time = now()
+		FROM resources r
+		WHERE v.id = r.pinned_version_id
+			AND r.name = $1
+			AND r.pipeline_id = $2
+	`, resource, pdb.ID)
+	return err
+}
+
 func (pdb *pipelineDB) SaveResourceVersions(config atc.ResourceConfig, versions []atc.Version) error {
 	tx, err := pdb.conn.Begin()
 	if err != nil {
@@ -802,6 +1023,8 @@ func (pdb *pipelineDB) SaveResourceVersions(config atc.ResourceConfig, versions
 
 	defer tx.Rollback()
 
+	var anyCreated bool
+
 	for _, version := range versions {
 		vr := VersionedResource{
 			Resource: config.Name,
@@ -823,17 +1046,28 @@ func (pdb *pipelineDB) SaveResourceVersions(config atc.ResourceConfig, versions
 			return ResourceNotFoundError{Name: vr.Resource}
 		}
 
-		_, _, err = pdb.saveVersionedResource(tx, savedResource, vr)
+		_, created, err := pdb.saveVersionedResource(tx, savedResource, vr)
 		if err != nil {
 			return err
 		}
 
+		anyCreated = anyCreated || created
+
 		err = pdb.incrementCheckOrderWhenNewerVersion(tx, savedResource.ID, vr.Type, string(versionJSON))
 		if err != nil {
 			return err
 		}
 	}
 
+	// checks save the latest version every time, so only a version that
+	// wasn't there before is worth scheduling for
+	if anyCreated {
+		err = notifyScheduling(tx, pdb.ID)
+		if err != nil {
+			return err
+		}
+	}
+
 	err = tx.Commit()
 	if err != nil {
 		return err
@@ -1002,13 +1236,13 @@ func (pdb *pipelineDB) SetResourceCheckError(resource SavedResource, cause error
 	if cause == nil {
 		_, err = pdb.conn.Exec(`
 			UPDATE resources
-			SET check_error = NULL
+			SET check_error = NULL, check_errored_at = NULL
 			WHERE id = $1
 			`, resource.ID)
 	} else {
 		_, err = pdb.conn.Exec(`
 			UPDATE resources
-			SET check_error = $2
+			SET check_error = $2, check_errored_at = now()
 			WHERE id = $1
 		`, resource.ID, cause.Error())
 	}
@@ -1203,6 +1437,26 @@ func (pdb *pipelineDB) UseInputsForBuild(buildID int, inputs []BuildInput) error
 }
 
 func (pdb *pipelineDB) CreateJobBuild(jobName string) (Build, error) {
+	return pdb.CreateJobBuildWithParams(jobName, nil)
+}
+
+// CreateJobBuildWithParams creates a pending build of the job, recording the
+// params it was triggered with. The params aren't checked against the job's
+// config; that's up to whoever triggered it.
+func (pdb *pipelineDB) CreateJobBuildWithParams(jobName string, params map[string]string) (Build, error) {
+	var paramsPayload interface{}
+	if params != nil {
+		payload, err := json.Marshal(params)
+		if err != nil {
+			return nil, err
+		}
+
+		paramsPayload, err = pdb.conn.EncryptionStrategy().Encrypt(payload)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	tx, err := pdb.conn.Begin()
 	if err != nil {
 		return nil, err
@@ -1218,14 +1472,14 @@ func (pdb *pipelineDB) CreateJobBuild(jobName string) (Build, error) {
 	// We had to resort to sub-selects here because you can't paramaterize a
 	// RETURNING statement in lib/pq... sorry
 	build, _, err := pdb.buildFactory.ScanBuild(tx.QueryRow(`
-		INSERT INTO builds (name, job_id, team_id, status)
-		VALUES ($1, $2, $3, 'pending')
+		INSERT INTO builds (name, job_id, team_id, status, params)
+		VALUES ($1, $2, $3, 'pending', $5)
 		RETURNING `+buildColumns+`,
 			(SELECT name FROM jobs WHERE id = $2),
 			(SELECT id FROM pipelines WHERE id = $4),
 			(SELECT name FROM pipelines WHERE id = $4),
 			(SELECT name FROM teams WHERE id = $3)
-	`, buildName, jobID, pdb.SavedPipeline.TeamID, pdb.ID))
+	`, buildName, jobID, pdb.SavedPipeline.TeamID, pdb.ID, paramsPayload))
 	if err != nil {
 		return nil, err
 	}
@@ -1462,6 +1716,11 @@ func (pdb *pipelineDB) SaveOutput(buildID int, vr VersionedResource, explicit bo
 		return SavedVersionedResource{}, err
 	}
 
+	err = notifyScheduling(tx, pdb.ID)
+	if err != nil {
+		return SavedVersionedResource{}, err
+	}
+
 	err = tx.Commit()
 	if err != nil {
 		return SavedVersionedResource{}, err
@@ -1500,7 +1759,7 @@ func (pdb *pipelineDB) GetNextPendingBuild(jobName string) (Build, bool, error)
 			b.id <= j.resource_check_waiver_end
 			OR j.resource_checking = false
 		)
-		ORDER BY b.id ASC
+		ORDER BY b.priority DESC, b.id ASC
 		LIMIT 1
 	`, dbJob.ID))
 }
@@ -1561,11 +1820,44 @@ func (pdb *pipelineDB) GetNextPendingBuildBySerialGroup(jobName string, serialGr
 		WHERE b.status = 'pending'
 			AND j.inputs_determined = true
 			AND j.pipeline_id = $1
-		ORDER BY b.id ASC
+		ORDER BY b.priority DESC, b.id ASC
 		LIMIT 1
 	`, args...))
 }
 
+// GetPendingBuildPriorities returns, for every job in the pipeline with a
+// pending build, the priority of its most important one.
+func (pdb *pipelineDB) GetPendingBuildPriorities() (map[string]int, error) {
+	rows, err := pdb.conn.Query(`
+		SELECT j.name, MAX(b.priority)
+		FROM builds b
+		INNER JOIN jobs j ON b.job_id = j.id
+		WHERE b.status = 'pending'
+			AND j.pipeline_id = $1
+		GROUP BY j.name
+	`, pdb.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	priorities := map[string]int{}
+
+	for rows.Next() {
+		var jobName string
+		var priority int
+		err := rows.Scan(&jobName, &priority)
+		if err != nil {
+			return nil, err
+		}
+
+		priorities[jobName] = priority
+	}
+
+	return priorities, nil
+}
+
 func (pdb *pipelineDB) GetRunningBuildsBySerialGroup(jobName string, serialGroups []string) ([]Build, error) {
 	pdb.updateSerialGroupsForJob(jobName, serialGroups)
 
@@ -1699,6 +1991,7 @@ func (pdb *pipelineDB) LoadVersionsDB() (*algorithm.VersionsDB, error) {
 		ResourceVersions: []algorithm.ResourceVersion{},
 		JobIDs:           map[string]int{},
 		ResourceIDs:      map[string]int{},
+		PinnedVersionIDs: map[int]int{},
 		CachedAt:         latestModifiedTime,
 	}
 
@@ -1797,7 +2090,7 @@ func (pdb *pipelineDB) LoadVersionsDB() (*algorithm.VersionsDB, error) {
 	}
 
 	rows, err = pdb.conn.Query(`
-    SELECT r.name, r.id
+    SELECT r.name, r.id, r.pinned_version_id
     FROM resources r
     WHERE r.pipeline_id = $1
   `, pdb.ID)
@@ -1808,12 +2101,17 @@ func (pdb *pipelineDB) LoadVersionsDB() (*algorithm.VersionsDB, error) {
 	for rows.Next() {
 		var name string
 		var id int
-		err := rows.Scan(&name, &id)
+		var pinnedVersionID sql.NullInt64
+		err := rows.Scan(&name, &id, &pinnedVersionID)
 		if err != nil {
 			return nil, err
 		}
 
 		db.ResourceIDs[name] = id
+
+		if pinnedVersionID.Valid {
+			db.PinnedVersionIDs[id] = int(pinnedVersionID.Int64)
+		}
 	}
 
 	pdb.versionsDB = db
@@ -2067,7 +2365,20 @@ func (pdb *pipelineDB) PauseJob(job string) error {
 }
 
 func (pdb *pipelineDB) UnpauseJob(job string) error {
-	return pdb.updatePausedJob(job, false)
+	err := pdb.updatePausedJob(job, false)
+	if err != nil {
+		return err
+	}
+
+	return notifyScheduling(pdb.conn, pdb.ID)
+}
+
+func (pdb *pipelineDB) MakeJobManualOnly(job string) error {
+	return pdb.updateManualOnlyJob(job, true)
+}
+
+func (pdb *pipelineDB) MakeJobAutomatic(job string) error {
+	return pdb.updateManualOnlyJob(job, false)
 }
 
 func (pdb *pipelineDB) SetMaxInFlightReached(jobName string, reached bool) error {
@@ -2092,6 +2403,55 @@ func (pdb *pipelineDB) SetMaxInFlightReached(jobName string, reached bool) error
 	return nil
 }
 
+// GetJobLastScheduledAt returns when the job's schedule last fired, or the
+// zero time if it never has.
+func (pdb *pipelineDB) GetJobLastScheduledAt(job string) (time.Time, error) {
+	var lastScheduledAt pq.NullTime
+
+	err := pdb.conn.QueryRow(`
+		SELECT last_scheduled_at
+		FROM jobs
+		WHERE name = $1
+			AND pipeline_id = $2
+	`, job, pdb.ID).Scan(&lastScheduledAt)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if !lastScheduledAt.Valid {
+		return time.Time{}, nil
+	}
+
+	return lastScheduledAt.Time, nil
+}
+
+// SaveJobLastScheduledAt moves the job's last scheduled time from previous
+// to at, returning false if it has since been moved by someone else.
+func (pdb *pipelineDB) SaveJobLastScheduledAt(job string, previous time.Time, at time.Time) (bool, error) {
+	var previousParam interface{}
+	if !previous.IsZero() {
+		previousParam = previous
+	}
+
+	result, err := pdb.conn.Exec(`
+		UPDATE jobs
+		SET last_scheduled_at = $1
+		WHERE name = $2
+			AND pipeline_id = $3
+			AND last_scheduled_at IS NOT DISTINCT FROM $4
+	`, at, job, pdb.ID, previousParam)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected == 1, nil
+}
+
 func (pdb *pipelineDB) UpdateFirstLoggedBuildID(job string, newFirstLoggedBuildID int) error {
 	tx, err := pdb.conn.Begin()
 	if err != nil {
@@ -2168,6 +2528,40 @@ func (pdb *pipelineDB) updatePausedJob(job string, pause bool) error {
 	return tx.Commit()
 }
 
+func (pdb *pipelineDB) updateManualOnlyJob(job string, manualOnly bool) error {
+	tx, err := pdb.conn.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	dbJob, err := pdb.getJob(tx, job)
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(`
+		UPDATE jobs
+		SET manual_only = $1
+		WHERE id = $2
+	`, manualOnly, dbJob.ID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected != 1 {
+		return nonOneRowAffectedError{rowsAffected}
+	}
+
+	return tx.Commit()
+}
+
 func (pdb *pipelineDB) GetJobBuilds(jobName string, page Page) ([]Build, Pagination, error) {
 	var (
 		err        error
@@ -2340,6 +2734,263 @@ func (pdb *pipelineDB) GetJobFinishedAndNextBuild(job string) (Build, Build, err
 	return finished, next, nil
 }
 
+// GetJobLatestFinishedBuildWithInput returns the job's latest finished build
+// that used the resource as an input. If version is given, only builds that
+// used exactly that version are considered.
+func (pdb *pipelineDB) GetJobBuildStats(jobName string, window time.Duration) (JobBuildStats, error) {
+	tx, err := pdb.conn.Begin()
+	if err != nil {
+		return JobBuildStats{}, err
+	}
+
+	defer tx.Rollback()
+
+	dbJob, err := pdb.getJob(tx, jobName)
+	if err != nil {
+		return JobBuildStats{}, err
+	}
+
+	var stats JobBuildStats
+	var mean sql.NullFloat64
+	err = tx.QueryRow(`
+		SELECT
+			COUNT(CASE WHEN status = 'succeeded' THEN 1 END),
+			COUNT(CASE WHEN status = 'failed' THEN 1 END),
+			COUNT(CASE WHEN status = 'errored' THEN 1 END),
+			COUNT(CASE WHEN status = 'aborted' THEN 1 END),
+			COUNT(CASE WHEN status = 'timed_out' THEN 1 END),
+			AVG(CASE WHEN status IN ('succeeded', 'failed') THEN EXTRACT(EPOCH FROM end_time - start_time) END)
+		FROM builds
+		WHERE job_id = $1
+			AND end_time > now() - ($2 || ' SECONDS')::INTERVAL
+	`, dbJob.ID, window.Seconds()).Scan(
+		&stats.Succeeded,
+		&stats.Failed,
+		&stats.Errored,
+		&stats.Aborted,
+		&stats.TimedOut,
+		&mean,
+	)
+	if err != nil {
+		return JobBuildStats{}, err
+	}
+
+	if mean.Valid {
+		stats.MeanDuration = secondsDuration(mean.Float64)
+	}
+
+	// nearest-rank percentiles, ranking the durations in the database rather
+	// than loading every build
+	rows, err := tx.Query(`
+		SELECT duration, position = CEIL(total * 0.5), position = CEIL(total * 0.95)
+		FROM (
+			SELECT
+				EXTRACT(EPOCH FROM end_time - start_time) AS duration,
+				ROW_NUMBER() OVER (ORDER BY end_time - start_time) AS position,
+				COUNT(*) OVER () AS total
+			FROM builds
+			WHERE job_id = $1
+				AND status IN ('succeeded', 'failed')
+				AND end_time > now() - ($2 || ' SECONDS')::INTERVAL
+		) durations
+		WHERE position = CEIL(total * 0.5)
+			OR position = CEIL(total * 0.95)
+	`, dbJob.ID, window.Seconds())
+	if err != nil {
+		return JobBuildStats{}, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var duration float64
+		var median, p95 bool
+		err := rows.Scan(&duration, &median, &p95)
+		if err != nil {
+			return JobBuildStats{}, err
+		}
+
+		if median {
+			stats.MedianDuration = secondsDuration(duration)
+		}
+
+		if p95 {
+			stats.P95Duration = secondsDuration(duration)
+		}
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return JobBuildStats{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return JobBuildStats{}, err
+	}
+
+	return stats, nil
+}
+
+func secondsDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// GetJobOutcomes looks at the job's most recent succeeded and failed builds,
+// up to the given number of them. Builds without any inputs aren't compared.
+func (pdb *pipelineDB) GetJobOutcomes(jobName string, builds int) (JobOutcomes, error) {
+	tx, err := pdb.conn.Begin()
+	if err != nil {
+		return JobOutcomes{}, err
+	}
+
+	defer tx.Rollback()
+
+	dbJob, err := pdb.getJob(tx, jobName)
+	if err != nil {
+		return JobOutcomes{}, err
+	}
+
+	var outcomes JobOutcomes
+	err = tx.QueryRow(`
+		SELECT
+			COUNT(previous),
+			COUNT(CASE WHEN previous != status THEN 1 END)
+		FROM (
+			SELECT status, LAG(status) OVER (PARTITION BY inputs ORDER BY id) AS previous
+			FROM (
+				SELECT b.id, b.status, string_agg(i.versioned_resource_id::text, ',' ORDER BY i.versioned_resource_id) AS inputs
+				FROM builds b
+				INNER JOIN build_inputs i ON i.build_id = b.id
+				WHERE b.job_id = $1
+					AND b.status IN ('succeeded', 'failed')
+				GROUP BY b.id, b.status
+				ORDER BY b.id DESC
+				LIMIT $2
+			) recent
+		) compared
+	`, dbJob.ID, builds).Scan(&outcomes.Compared, &outcomes.Flipped)
+	if err != nil {
+		return JobOutcomes{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return JobOutcomes{}, err
+	}
+
+	return outcomes, nil
+}
+
+func (pdb *pipelineDB) SaveJobFlakiness(jobName string, flakiness float64, flaky bool) error {
+	_, err := pdb.conn.Exec(`
+		UPDATE jobs
+		SET flakiness = $1, flaky = $2
+		WHERE name = $3
+			AND pipeline_id = $4
+	`, flakiness, flaky, jobName, pdb.ID)
+	return err
+}
+
+func (pdb *pipelineDB) SaveSchedulingReason(jobName string, reason SchedulingReason) error {
+	_, err := pdb.conn.Exec(`
+		UPDATE jobs
+		SET scheduling_reason = $1, scheduling_checked_at = now()
+		WHERE name = $2
+			AND pipeline_id = $3
+	`, string(reason), jobName, pdb.ID)
+	return err
+}
+
+func (pdb *pipelineDB) SaveMissingInputReasons(jobName string, reasons MissingInputReasons) error {
+	reasonsJSON, err := json.Marshal(reasons)
+	if err != nil {
+		return err
+	}
+
+	_, err = pdb.conn.Exec(`
+		UPDATE jobs
+		SET missing_input_reasons = $1
+		WHERE name = $2
+			AND pipeline_id = $3
+	`, string(reasonsJSON), jobName, pdb.ID)
+	return err
+}
+
+// GetJobScheduling returns what the scheduler last decided for the job. It
+// isn't found if the scheduler hasn't looked at the job yet.
+func (pdb *pipelineDB) GetJobScheduling(jobName string) (JobScheduling, bool, error) {
+	var reason string
+	var reasonsJSON string
+	var checkedAt pq.NullTime
+	err := pdb.conn.QueryRow(`
+		SELECT scheduling_reason, missing_input_reasons, scheduling_checked_at
+		FROM jobs
+		WHERE name = $1
+			AND pipeline_id = $2
+	`, jobName, pdb.ID).Scan(&reason, &reasonsJSON, &checkedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return JobScheduling{}, false, nil
+		}
+
+		return JobScheduling{}, false, err
+	}
+
+	if !checkedAt.Valid {
+		return JobScheduling{}, false, nil
+	}
+
+	scheduling := JobScheduling{
+		Reason:    SchedulingReason(reason),
+		CheckedAt: checkedAt.Time,
+	}
+
+	err = json.Unmarshal([]byte(reasonsJSON), &scheduling.MissingInputReasons)
+	if err != nil {
+		return JobScheduling{}, false, err
+	}
+
+	return scheduling, true, nil
+}
+
+func (pdb *pipelineDB) GetJobLatestFinishedBuildWithInput(job string, resourceName string, version atc.Version) (Build, bool, error) {
+	query := `
+		SELECT ` + qualifiedBuildColumns + `
+		FROM builds b
+			INNER JOIN jobs j ON b.job_id = j.id
+			INNER JOIN pipelines p ON j.pipeline_id = p.id
+			INNER JOIN teams t ON b.team_id = t.id
+		WHERE j.name = $1
+			AND j.pipeline_id = $2
+			AND b.status NOT IN ('pending', 'started')
+			AND EXISTS (
+				SELECT 1
+				FROM build_inputs bi
+					INNER JOIN versioned_resources v ON v.id = bi.versioned_resource_id
+					INNER JOIN resources r ON r.id = v.resource_id
+				WHERE bi.build_id = b.id
+					AND r.name = $3
+					AND r.pipeline_id = $2
+					AND ($4 = '' OR v.version = $4)
+			)
+		ORDER BY b.id DESC
+		LIMIT 1
+	`
+
+	var versionJSON string
+	if version != nil {
+		versionBytes, err := json.Marshal(version)
+		if err != nil {
+			return nil, false, err
+		}
+
+		versionJSON = string(versionBytes)
+	}
+
+	return pdb.buildFactory.ScanBuild(pdb.conn.QueryRow(query, job, pdb.ID, resourceName, versionJSON))
+}
+
 func (pdb *pipelineDB) GetDashboard() (Dashboard, atc.GroupConfigs, error) {
 	pipelineConfig, _, _, err := pdb.GetConfig()
 	if err != nil {
@@ -2413,9 +3064,29 @@ func (pdb *pipelineDB) Hide() error {
 	return err
 }
 
+func (pdb *pipelineDB) SetPermissions(permissions atc.PipelinePermissions) error {
+	payload, err := json.Marshal(permissions)
+	if err != nil {
+		return err
+	}
+
+	_, err = pdb.conn.Exec(`
+		UPDATE pipelines
+		SET permissions = $1
+		WHERE id = $2
+	`, payload, pdb.ID)
+	if err != nil {
+		return err
+	}
+
+	pdb.Permissions = permissions
+
+	return nil
+}
+
 func (pdb *pipelineDB) getJobs() (map[string]SavedJob, error) {
 	rows, err := pdb.conn.Query(`
-	SELECT j.id, j.name, j.paused, j.first_logged_build_id, p.team_id
+	SELECT j.id, j.name, j.paused, j.manual_only, j.first_logged_build_id, p.team_id, j.flakiness, j.flaky
   	FROM jobs j, pipelines p
 		WHERE j.pipeline_id = p.id
   		AND pipeline_id = $1
@@ -2431,7 +3102,7 @@ func (pdb *pipelineDB) getJobs() (map[string]SavedJob, error) {
 	for rows.Next() {
 		var savedJob SavedJob
 
-		err := rows.Scan(&savedJob.ID, &savedJob.Name, &savedJob.Paused, &savedJob.FirstLoggedBuildID, &savedJob.TeamID)
+		err := rows.Scan(&savedJob.ID, &savedJob.Name, &savedJob.Paused, &savedJob.ManualOnly, &savedJob.FirstLoggedBuildID, &savedJob.TeamID, &savedJob.Flakiness, &savedJob.Flaky)
 		if err != nil {
 			return nil, err
 		}
@@ -2490,12 +3161,12 @@ func (pdb *pipelineDB) getJob(tx Tx, name string) (SavedJob, error) {
 	var job SavedJob
 
 	err := tx.QueryRow(`
- 	SELECT j.id, j.name, j.paused, j.first_logged_build_id, p.team_id
+ 	SELECT j.id, j.name, j.paused, j.manual_only, j.first_logged_build_id, p.team_id, j.flakiness, j.flaky
   	FROM jobs j, pipelines p
   	WHERE j.pipeline_id = p.id
 			AND j.name = $1
   		AND j.pipeline_id = $2
-  `, name, pdb.ID).Scan(&job.ID, &job.Name, &job.Paused, &job.FirstLoggedBuildID, &job.TeamID)
+  `, name, pdb.ID).Scan(&job.ID, &job.Name, &job.Paused, &job.ManualOnly, &job.FirstLoggedBuildID, &job.TeamID, &job.Flakiness, &job.Flaky)
 	if err != nil {
 		return SavedJob{}, err
 	}
@@ -8,14 +8,14 @@ type PipelineDBFactory interface {
 
 type pipelineDBFactory struct {
 	conn Conn
-	bus  *notificationsBus
+	bus  NotificationsBus
 
 	lockFactory LockFactory
 }
 
 func NewPipelineDBFactory(
 	sqldbConnection Conn,
-	bus *notificationsBus,
+	bus NotificationsBus,
 	lockFactory LockFactory,
 ) *pipelineDBFactory {
 	return &pipelineDBFactory{
@@ -0,0 +1,190 @@
+package db
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/concourse/atc"
+	"github.com/lib/pq"
+)
+
+// ImportedBuild is a finished build of a job, as it happened on another ATC.
+type ImportedBuild struct {
+	Name      string
+	Status    Status
+	StartTime time.Time
+	EndTime   time.Time
+}
+
+// GetAllResourceVersions returns every version of the resource, oldest
+// first.
+func (pdb *pipelineDB) GetAllResourceVersions(resourceName string) ([]SavedVersionedResource, error) {
+	rows, err := pdb.conn.Query(`
+		SELECT v.id, v.enabled, v.type, v.version, v.metadata, r.name, v.check_order
+		FROM versioned_resources v
+		INNER JOIN resources r ON v.resource_id = r.id
+		WHERE r.name = $1
+		AND r.pipeline_id = $2
+		ORDER BY v.check_order ASC
+	`, resourceName, pdb.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	versions := []SavedVersionedResource{}
+	for rows.Next() {
+		var version SavedVersionedResource
+		var versionString, metadataString string
+
+		err := rows.Scan(
+			&version.ID,
+			&version.Enabled,
+			&version.Type,
+			&versionString,
+			&metadataString,
+			&version.Resource,
+			&version.CheckOrder,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		err = json.Unmarshal([]byte(versionString), &version.Version)
+		if err != nil {
+			return nil, err
+		}
+
+		err = json.Unmarshal([]byte(metadataString), &version.Metadata)
+		if err != nil {
+			return nil, err
+		}
+
+		version.PipelineID = pdb.ID
+
+		versions = append(versions, version)
+	}
+
+	return versions, rows.Err()
+}
+
+// ImportResourceVersions saves the versions in the order given, oldest
+// first, so that they end up in the same order they were found in, and
+// disables the ones that aren't enabled. Their IDs and check orders are
+// ignored.
+func (pdb *pipelineDB) ImportResourceVersions(config atc.ResourceConfig, versions []SavedVersionedResource) error {
+	tx, err := pdb.conn.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	savedResource, found, err := pdb.getResource(tx, config.Name)
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		return ResourceNotFoundError{Name: config.Name}
+	}
+
+	for _, version := range versions {
+		vr := version.VersionedResource
+		vr.Resource = config.Name
+		vr.Type = config.Type
+
+		versionJSON, err := json.Marshal(vr.Version)
+		if err != nil {
+			return err
+		}
+
+		saved, _, err := pdb.saveVersionedResource(tx, savedResource, vr)
+		if err != nil {
+			return err
+		}
+
+		err = pdb.incrementCheckOrderWhenNewerVersion(tx, savedResource.ID, vr.Type, string(versionJSON))
+		if err != nil {
+			return err
+		}
+
+		if !version.Enabled {
+			_, err := tx.Exec(`
+				UPDATE versioned_resources
+				SET enabled = false
+				WHERE id = $1
+			`, saved.ID)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ImportJobBuild records a finished build of the job. Builds imported with
+// numeric names push the job's build numbers past them, so that the job's
+// next build doesn't clash with its history.
+func (pdb *pipelineDB) ImportJobBuild(jobName string, imported ImportedBuild) (Build, error) {
+	tx, err := pdb.conn.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	var jobID int
+	err = tx.QueryRow(`
+		SELECT id
+		FROM jobs
+		WHERE name = $1
+		AND pipeline_id = $2
+	`, jobName, pdb.ID).Scan(&jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	build, _, err := pdb.buildFactory.ScanBuild(tx.QueryRow(`
+		INSERT INTO builds (name, job_id, team_id, status, scheduled, completed, start_time, end_time)
+		VALUES ($1, $2, $3, $4, true, true, $5, $6)
+		RETURNING `+buildColumns+`,
+			(SELECT name FROM jobs WHERE id = $2),
+			(SELECT id FROM pipelines WHERE id = $7),
+			(SELECT name FROM pipelines WHERE id = $7),
+			(SELECT name FROM teams WHERE id = $3)
+	`, imported.Name, jobID, pdb.SavedPipeline.TeamID, string(imported.Status), nullTime(imported.StartTime), nullTime(imported.EndTime), pdb.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	err = createBuildEventSeq(tx, build.ID())
+	if err != nil {
+		return nil, err
+	}
+
+	if number, err := strconv.Atoi(imported.Name); err == nil {
+		_, err := tx.Exec(`
+			UPDATE jobs
+			SET build_number_seq = GREATEST(build_number_seq, $2)
+			WHERE id = $1
+		`, jobID, number)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return build, nil
+}
+
+func nullTime(t time.Time) pq.NullTime {
+	return pq.NullTime{Time: t, Valid: !t.IsZero()}
+}
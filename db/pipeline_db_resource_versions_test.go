@@ -21,6 +21,7 @@ var _ = Describe("Resource History", func() {
 	var sqlDB *db.SQLDB
 	var pipelineDB db.PipelineDB
 	var savedPipeline db.SavedPipeline
+	var teamDB db.TeamDB
 
 	BeforeEach(func() {
 		postgresRunner.Truncate()
@@ -60,8 +61,8 @@ var _ = Describe("Resource History", func() {
 		}
 
 		teamDBFactory := db.NewTeamDBFactory(dbConn, bus, lockFactory)
-		teamDB := teamDBFactory.GetTeamDB("some-team")
-		savedPipeline, _, err = teamDB.SaveConfig("a-pipeline-name", config, 0, db.PipelineUnpaused)
+		teamDB = teamDBFactory.GetTeamDB("some-team")
+		savedPipeline, _, err = teamDB.SaveConfig("a-pipeline-name", config, 0, db.PipelineUnpaused, "some-author")
 		Expect(err).NotTo(HaveOccurred())
 
 		pipelineDB = pipelineDBFactory.Build(savedPipeline)
@@ -289,6 +290,152 @@ var _ = Describe("Resource History", func() {
 		})
 	})
 
+	Context("ImportResourceVersions", func() {
+		var resource atc.ResourceConfig
+
+		BeforeEach(func() {
+			resource = atc.ResourceConfig{
+				Name: "some-resource",
+				Type: "some-type",
+			}
+
+			err := pipelineDB.ImportResourceVersions(resource, []db.SavedVersionedResource{
+				{
+					Enabled: true,
+					VersionedResource: db.VersionedResource{
+						Version:  db.Version{"version": "1"},
+						Metadata: []db.MetadataField{{Name: "some", Value: "metadata"}},
+					},
+				},
+				{
+					Enabled: false,
+					VersionedResource: db.VersionedResource{
+						Version: db.Version{"version": "2"},
+					},
+				},
+				{
+					Enabled: true,
+					VersionedResource: db.VersionedResource{
+						Version: db.Version{"version": "3"},
+					},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("saves them in the order given, keeping whether they're enabled", func() {
+			versions, err := pipelineDB.GetAllResourceVersions("some-resource")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(versions).To(HaveLen(3))
+
+			Expect(versions[0].Version).To(Equal(db.Version{"version": "1"}))
+			Expect(versions[0].Metadata).To(Equal([]db.MetadataField{{Name: "some", Value: "metadata"}}))
+			Expect(versions[0].Enabled).To(BeTrue())
+
+			Expect(versions[1].Version).To(Equal(db.Version{"version": "2"}))
+			Expect(versions[1].Enabled).To(BeFalse())
+
+			Expect(versions[2].Version).To(Equal(db.Version{"version": "3"}))
+			Expect(versions[2].Enabled).To(BeTrue())
+
+			latest, found, err := pipelineDB.GetLatestVersionedResource("some-resource")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(latest.Version).To(Equal(db.Version{"version": "3"}))
+		})
+
+		It("returns an error for a resource the pipeline doesn't have", func() {
+			err := pipelineDB.ImportResourceVersions(atc.ResourceConfig{Name: "bogus"}, nil)
+			Expect(err).To(Equal(db.ResourceNotFoundError{Name: "bogus"}))
+		})
+	})
+
+	Context("ImportJobBuild", func() {
+		It("records a finished build and keeps new builds numbered after it", func() {
+			imported, err := pipelineDB.ImportJobBuild("some-job", db.ImportedBuild{
+				Name:      "41",
+				Status:    db.StatusFailed,
+				StartTime: time.Unix(100, 0),
+				EndTime:   time.Unix(200, 0),
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(imported.Name()).To(Equal("41"))
+			Expect(imported.JobName()).To(Equal("some-job"))
+			Expect(imported.Status()).To(Equal(db.StatusFailed))
+			Expect(imported.StartTime().Unix()).To(Equal(int64(100)))
+			Expect(imported.EndTime().Unix()).To(Equal(int64(200)))
+
+			next, err := pipelineDB.CreateJobBuild("some-job")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(next.Name()).To(Equal("42"))
+		})
+	})
+
+	Context("GetJobLatestFinishedBuildWithInput", func() {
+		var v1Build, v2Build db.Build
+
+		saveInput := func(build db.Build, version string) {
+			_, err := pipelineDB.SaveInput(build.ID(), db.BuildInput{
+				Name: "some-input",
+				VersionedResource: db.VersionedResource{
+					Resource:   "some-resource",
+					Type:       "some-type",
+					Version:    db.Version{"version": version},
+					PipelineID: savedPipeline.ID,
+				},
+				FirstOccurrence: true,
+			})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		BeforeEach(func() {
+			var err error
+			v1Build, err = pipelineDB.CreateJobBuild("some-job")
+			Expect(err).NotTo(HaveOccurred())
+			saveInput(v1Build, "v1")
+			Expect(v1Build.Finish(db.StatusSucceeded)).To(Succeed())
+
+			v2Build, err = pipelineDB.CreateJobBuild("some-job")
+			Expect(err).NotTo(HaveOccurred())
+			saveInput(v2Build, "v2")
+			Expect(v2Build.Finish(db.StatusFailed)).To(Succeed())
+
+			runningBuild, err := pipelineDB.CreateJobBuild("some-job")
+			Expect(err).NotTo(HaveOccurred())
+			saveInput(runningBuild, "v2")
+
+			_, err = pipelineDB.CreateJobBuild("some-job")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns the latest finished build with the resource as an input", func() {
+			build, found, err := pipelineDB.GetJobLatestFinishedBuildWithInput("some-job", "some-resource", nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(build.ID()).To(Equal(v2Build.ID()))
+		})
+
+		It("returns the latest finished build of the given version", func() {
+			build, found, err := pipelineDB.GetJobLatestFinishedBuildWithInput("some-job", "some-resource", atc.Version{"version": "v1"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(build.ID()).To(Equal(v1Build.ID()))
+		})
+
+		It("does not find builds of versions that were never used", func() {
+			_, found, err := pipelineDB.GetJobLatestFinishedBuildWithInput("some-job", "some-resource", atc.Version{"version": "v3"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+
+		It("does not find builds of other jobs", func() {
+			_, found, err := pipelineDB.GetJobLatestFinishedBuildWithInput("some-other-job", "some-resource", nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+	})
+
 	Context("GetBuildsWithVersionAsOutput", func() {
 		var savedVersionedResource db.SavedVersionedResource
 		var expectedBuilds []db.Build
@@ -351,4 +498,222 @@ var _ = Describe("Resource History", func() {
 			Expect(builds).To(Equal([]db.Build{}))
 		})
 	})
+
+	Context("GetVersionCausality", func() {
+		var upstreamDB db.PipelineDB
+		var downstreamDB db.PipelineDB
+
+		var commit db.SavedVersionedResource
+		var artifact db.SavedVersionedResource
+		var downstreamArtifact db.SavedVersionedResource
+
+		var build db.Build
+		var deploy db.Build
+
+		BeforeEach(func() {
+			artifactSource := atc.Source{"bucket": "artifacts"}
+
+			upstreamPipeline, _, err := teamDB.SaveConfig("upstream", atc.Config{
+				Jobs: atc.JobConfigs{{Name: "build"}},
+				Resources: atc.ResourceConfigs{
+					{Name: "source-code", Type: "git", Source: atc.Source{"uri": "some-repo"}},
+					{Name: "artifact", Type: "s3", Source: artifactSource},
+				},
+			}, 0, db.PipelineUnpaused, "some-author")
+			Expect(err).NotTo(HaveOccurred())
+
+			downstreamPipeline, _, err := teamDB.SaveConfig("downstream", atc.Config{
+				Jobs: atc.JobConfigs{{Name: "deploy"}},
+				Resources: atc.ResourceConfigs{
+					{Name: "artifact", Type: "s3", Source: artifactSource},
+					{Name: "other-artifact", Type: "s3", Source: atc.Source{"bucket": "elsewhere"}},
+				},
+			}, 0, db.PipelineUnpaused, "some-author")
+			Expect(err).NotTo(HaveOccurred())
+
+			upstreamDB = pipelineDBFactory.Build(upstreamPipeline)
+			downstreamDB = pipelineDBFactory.Build(downstreamPipeline)
+
+			build, err = upstreamDB.CreateJobBuild("build")
+			Expect(err).NotTo(HaveOccurred())
+
+			commit, err = upstreamDB.SaveInput(build.ID(), db.BuildInput{
+				Name: "source-code",
+				VersionedResource: db.VersionedResource{
+					Resource:   "source-code",
+					Type:       "git",
+					Version:    db.Version{"ref": "abc123"},
+					PipelineID: upstreamPipeline.ID,
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			// inputs are also recorded as implicit outputs; those aren't
+			// produced by the build
+			_, err = upstreamDB.SaveOutput(build.ID(), commit.VersionedResource, false)
+			Expect(err).NotTo(HaveOccurred())
+
+			artifact, err = upstreamDB.SaveOutput(build.ID(), db.VersionedResource{
+				Resource:   "artifact",
+				Type:       "s3",
+				Version:    db.Version{"path": "artifact-1.tgz"},
+				PipelineID: upstreamPipeline.ID,
+			}, true)
+			Expect(err).NotTo(HaveOccurred())
+
+			deploy, err = downstreamDB.CreateJobBuild("deploy")
+			Expect(err).NotTo(HaveOccurred())
+
+			downstreamArtifact, err = downstreamDB.SaveInput(deploy.ID(), db.BuildInput{
+				Name: "artifact",
+				VersionedResource: db.VersionedResource{
+					Resource:   "artifact",
+					Type:       "s3",
+					Version:    db.Version{"path": "artifact-1.tgz"},
+					PipelineID: downstreamPipeline.ID,
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = downstreamDB.SaveResourceVersions(atc.ResourceConfig{
+				Name:   "other-artifact",
+				Type:   "s3",
+				Source: atc.Source{"bucket": "elsewhere"},
+			}, []atc.Version{{"path": "artifact-1.tgz"}})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("walks the builds and versions downstream of the version, across pipelines", func() {
+			causality, found, err := upstreamDB.GetVersionCausality(commit.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+
+			versionIDs := []int{}
+			for _, version := range causality.Versions {
+				versionIDs = append(versionIDs, version.ID)
+			}
+
+			Expect(versionIDs).To(Equal([]int{commit.ID, artifact.ID, downstreamArtifact.ID}))
+
+			buildIDs := []int{}
+			for _, causedBuild := range causality.Builds {
+				buildIDs = append(buildIDs, causedBuild.ID())
+			}
+
+			Expect(buildIDs).To(Equal([]int{build.ID(), deploy.ID()}))
+
+			Expect(causality.Edges).To(ConsistOf(
+				db.CausalityEdge{Type: db.CausalityInput, VersionID: commit.ID, BuildID: build.ID()},
+				db.CausalityEdge{Type: db.CausalityOutput, VersionID: artifact.ID, BuildID: build.ID()},
+				db.CausalityEdge{Type: db.CausalitySameVersion, VersionID: artifact.ID, SameVersionID: downstreamArtifact.ID},
+				db.CausalityEdge{Type: db.CausalityInput, VersionID: downstreamArtifact.ID, BuildID: deploy.ID()},
+			))
+
+			Expect(causality.Truncated).To(BeFalse())
+		})
+
+		It("only includes what is downstream of the version", func() {
+			causality, found, err := downstreamDB.GetVersionCausality(downstreamArtifact.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+
+			buildIDs := []int{}
+			for _, causedBuild := range causality.Builds {
+				buildIDs = append(buildIDs, causedBuild.ID())
+			}
+
+			Expect(buildIDs).To(Equal([]int{deploy.ID()}))
+		})
+
+		It("does not find versions of other pipelines", func() {
+			_, found, err := downstreamDB.GetVersionCausality(commit.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+	})
+
+	Context("PruneResourceVersions", func() {
+		BeforeEach(func() {
+			versions := []db.SavedVersionedResource{}
+			for i := 1; i <= 6; i++ {
+				versions = append(versions, db.SavedVersionedResource{
+					Enabled: i != 2,
+					VersionedResource: db.VersionedResource{
+						Version: db.Version{"version": fmt.Sprintf("%d", i)},
+					},
+				})
+			}
+
+			err := pipelineDB.ImportResourceVersions(atc.ResourceConfig{Name: "some-resource", Type: "some-type"}, versions)
+			Expect(err).NotTo(HaveOccurred())
+
+			build, err := pipelineDB.CreateJobBuild("some-job")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = pipelineDB.SaveInput(build.ID(), db.BuildInput{
+				Name: "some-input",
+				VersionedResource: db.VersionedResource{
+					Resource: "some-resource",
+					Type:     "some-type",
+					Version:  db.Version{"version": "3"},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		remainingVersions := func() []string {
+			versions, err := pipelineDB.GetAllResourceVersions("some-resource")
+			Expect(err).NotTo(HaveOccurred())
+
+			remaining := []string{}
+			for _, version := range versions {
+				remaining = append(remaining, version.Version["version"])
+			}
+
+			return remaining
+		}
+
+		It("prunes the older versions that aren't of use", func() {
+			pruned, err := sqlDB.PruneResourceVersions(2, 100)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pruned).To(Equal(2))
+
+			Expect(remainingVersions()).To(Equal([]string{"2", "3", "5", "6"}))
+		})
+
+		It("prunes no more than the limit", func() {
+			pruned, err := sqlDB.PruneResourceVersions(2, 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pruned).To(Equal(1))
+
+			Expect(remainingVersions()).To(HaveLen(5))
+		})
+
+		It("keeps pinned versions", func() {
+			versions, err := pipelineDB.GetAllResourceVersions("some-resource")
+			Expect(err).NotTo(HaveOccurred())
+
+			pinned, err := pipelineDB.PinResourceVersion("some-resource", versions[0].ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pinned).To(BeTrue())
+
+			_, err = sqlDB.PruneResourceVersions(2, 100)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(remainingVersions()).To(Equal([]string{"1", "2", "3", "5", "6"}))
+		})
+
+		It("has the pipeline see the versions are gone", func() {
+			before, err := pipelineDB.LoadVersionsDB()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(before.ResourceVersions).To(HaveLen(5))
+
+			_, err = sqlDB.PruneResourceVersions(2, 100)
+			Expect(err).NotTo(HaveOccurred())
+
+			after, err := pipelineDB.LoadVersionsDB()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(after.ResourceVersions).To(HaveLen(3))
+		})
+	})
 })
@@ -1,6 +1,7 @@
 package db_test
 
 import (
+	"context"
 	"errors"
 	"time"
 
@@ -213,10 +214,10 @@ var _ = Describe("PipelineDB", func() {
 
 		teamDB = teamDBFactory.GetTeamDB("some-team")
 
-		savedPipeline, _, err = teamDB.SaveConfig("a-pipeline-name", pipelineConfig, 0, db.PipelineUnpaused)
+		savedPipeline, _, err = teamDB.SaveConfig("a-pipeline-name", pipelineConfig, 0, db.PipelineUnpaused, "some-author")
 		Expect(err).NotTo(HaveOccurred())
 
-		otherSavedPipeline, _, err = teamDB.SaveConfig("other-pipeline-name", otherPipelineConfig, 0, db.PipelineUnpaused)
+		otherSavedPipeline, _, err = teamDB.SaveConfig("other-pipeline-name", otherPipelineConfig, 0, db.PipelineUnpaused, "some-author")
 		Expect(err).NotTo(HaveOccurred())
 
 		pipelineDB = pipelineDBFactory.Build(savedPipeline)
@@ -226,7 +227,7 @@ var _ = Describe("PipelineDB", func() {
 	Describe("destroying a pipeline", func() {
 		It("can be deleted", func() {
 			// populate pipelines table
-			pipelineThatWillBeDeleted, _, err := teamDB.SaveConfig("a-pipeline-that-will-be-deleted", pipelineConfig, 0, db.PipelineUnpaused)
+			pipelineThatWillBeDeleted, _, err := teamDB.SaveConfig("a-pipeline-that-will-be-deleted", pipelineConfig, 0, db.PipelineUnpaused, "some-author")
 			Expect(err).NotTo(HaveOccurred())
 
 			fetchedPipeline, found, err := teamDB.GetPipelineByName("a-pipeline-that-will-be-deleted")
@@ -416,6 +417,91 @@ var _ = Describe("PipelineDB", func() {
 		})
 	})
 
+	Describe("SchedulingNotifier", func() {
+		var notifier db.Notifier
+
+		BeforeEach(func() {
+			var err error
+			notifier, err = pipelineDB.SchedulingNotifier()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			notifier.Close()
+		})
+
+		It("doesn't notify straight away", func() {
+			Consistently(notifier.Notify()).ShouldNot(Receive())
+		})
+
+		It("notifies when a new version is saved", func() {
+			resourceConfig := atc.ResourceConfig{Name: "some-resource", Type: "some-type"}
+
+			err := pipelineDB.SaveResourceVersions(resourceConfig, []atc.Version{{"version": "1"}})
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(notifier.Notify()).Should(Receive())
+
+			By("not notifying when the version was already saved")
+			err = pipelineDB.SaveResourceVersions(resourceConfig, []atc.Version{{"version": "1"}})
+			Expect(err).NotTo(HaveOccurred())
+
+			Consistently(notifier.Notify()).ShouldNot(Receive())
+		})
+
+		It("notifies when a build of one of its jobs finishes", func() {
+			build, err := pipelineDB.CreateJobBuild("some-job")
+			Expect(err).NotTo(HaveOccurred())
+
+			Consistently(notifier.Notify()).ShouldNot(Receive())
+
+			err = build.Finish(db.StatusSucceeded)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(notifier.Notify()).Should(Receive())
+		})
+
+		It("notifies when a build saves an output", func() {
+			build, err := pipelineDB.CreateJobBuild("some-job")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = pipelineDB.SaveOutput(build.ID(), db.VersionedResource{
+				Resource: "some-resource",
+				Type:     "some-type",
+				Version:  db.Version{"version": "1"},
+			}, true)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(notifier.Notify()).Should(Receive())
+		})
+
+		It("notifies when its config is saved", func() {
+			_, _, err := teamDB.SaveConfig("a-pipeline-name", pipelineConfig, savedPipeline.Version, db.PipelineNoChange, "some-author")
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(notifier.Notify()).Should(Receive())
+		})
+
+		It("notifies when it or one of its jobs is unpaused", func() {
+			err := pipelineDB.Unpause()
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(notifier.Notify()).Should(Receive())
+
+			err = pipelineDB.UnpauseJob("some-job")
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(notifier.Notify()).Should(Receive())
+		})
+
+		It("doesn't notify for anything happening in other pipelines", func() {
+			err := otherPipelineDB.Unpause()
+			Expect(err).NotTo(HaveOccurred())
+
+			Consistently(notifier.Notify()).ShouldNot(Receive())
+		})
+	})
+
 	Describe("UpdateName", func() {
 		var teamDB db.TeamDB
 
@@ -444,7 +530,7 @@ var _ = Describe("PipelineDB", func() {
 				Expect(err).NotTo(HaveOccurred())
 
 				team2DB = teamDBFactory.GetTeamDB(team2.Name)
-				_, _, err = team2DB.SaveConfig("a-pipeline-name", pipelineConfig, 0, db.PipelineUnpaused)
+				_, _, err = team2DB.SaveConfig("a-pipeline-name", pipelineConfig, 0, db.PipelineUnpaused, "some-author")
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -458,6 +544,40 @@ var _ = Describe("PipelineDB", func() {
 		})
 	})
 
+	Describe("SetPermissions", func() {
+		It("starts without any", func() {
+			Expect(pipelineDB.Pipeline().Permissions).To(BeZero())
+		})
+
+		It("saves them with the pipeline", func() {
+			permissions := atc.PipelinePermissions{
+				Trigger:   atc.RoleAdmin,
+				Configure: atc.RoleAdmin,
+			}
+
+			err := pipelineDB.SetPermissions(permissions)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(pipelineDB.Pipeline().Permissions).To(Equal(permissions))
+
+			pipeline, found, err := teamDB.GetPipelineByName("a-pipeline-name")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+
+			Expect(pipeline.Permissions).To(Equal(permissions))
+		})
+
+		It("keeps them when the config is saved", func() {
+			err := pipelineDB.SetPermissions(atc.PipelinePermissions{Trigger: atc.RoleAdmin})
+			Expect(err).NotTo(HaveOccurred())
+
+			savedPipeline, _, err := teamDB.SaveConfig("a-pipeline-name", pipelineConfig, pipelineDB.Pipeline().Version, db.PipelineNoChange, "some-author")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(savedPipeline.Permissions).To(Equal(atc.PipelinePermissions{Trigger: atc.RoleAdmin}))
+		})
+	})
+
 	Describe("ScopedName", func() {
 		It("concatenates the pipeline name with the passed in name", func() {
 			pipelineDB := pipelineDBFactory.Build(db.SavedPipeline{
@@ -517,9 +637,9 @@ var _ = Describe("PipelineDB", func() {
 			})
 
 			By("being able to update the config with a valid config")
-			_, _, err = teamDB.SaveConfig("a-pipeline-name", updatedConfig, configVersion, db.PipelineUnpaused)
+			_, _, err = teamDB.SaveConfig("a-pipeline-name", updatedConfig, configVersion, db.PipelineUnpaused, "some-author")
 			Expect(err).NotTo(HaveOccurred())
-			_, _, err = teamDB.SaveConfig("other-pipeline-name", updatedConfig, otherConfigVersion, db.PipelineUnpaused)
+			_, _, err = teamDB.SaveConfig("other-pipeline-name", updatedConfig, otherConfigVersion, db.PipelineUnpaused, "some-author")
 			Expect(err).NotTo(HaveOccurred())
 
 			By("returning the updated config")
@@ -1292,6 +1412,107 @@ var _ = Describe("PipelineDB", func() {
 			})
 		})
 
+		Describe("pinning and unpinning resources", func() {
+			var savedVR db.SavedVersionedResource
+
+			BeforeEach(func() {
+				err := pipelineDB.SaveResourceVersions(atc.ResourceConfig{
+					Name:   resourceName,
+					Type:   "some-type",
+					Source: atc.Source{"some": "source"},
+				}, []atc.Version{{"version": "1"}, {"version": "2"}})
+				Expect(err).NotTo(HaveOccurred())
+
+				var found bool
+				savedVR, found, err = pipelineDB.GetVersionedResourceByVersion(atc.Version{"version": "1"}, resourceName)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+			})
+
+			It("starts out as unpinned", func() {
+				Expect(resource.PinnedVersionID).To(BeZero())
+			})
+
+			It("can be pinned to one of its versions", func() {
+				pinned, err := pipelineDB.PinResourceVersion(resourceName, savedVR.ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pinned).To(BeTrue())
+
+				pinnedResource, _, err := pipelineDB.GetResource(resourceName)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pinnedResource.PinnedVersionID).To(Equal(savedVR.ID))
+
+				versionsDB, err := pipelineDB.LoadVersionsDB()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(versionsDB.PinnedVersionIDs).To(Equal(map[int]int{resource.ID: savedVR.ID}))
+
+				otherPipelineResource, _, err := otherPipelineDB.GetResource(resourceName)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(otherPipelineResource.PinnedVersionID).To(BeZero())
+			})
+
+			It("cannot be pinned to a version of another resource", func() {
+				err := otherPipelineDB.SaveResourceVersions(atc.ResourceConfig{
+					Name:   resourceName,
+					Type:   "some-type",
+					Source: atc.Source{"some": "source"},
+				}, []atc.Version{{"version": "1"}})
+				Expect(err).NotTo(HaveOccurred())
+
+				otherVR, found, err := otherPipelineDB.GetVersionedResourceByVersion(atc.Version{"version": "1"}, resourceName)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+
+				pinned, err := pipelineDB.PinResourceVersion(resourceName, otherVR.ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pinned).To(BeFalse())
+
+				unpinnedResource, _, err := pipelineDB.GetResource(resourceName)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(unpinnedResource.PinnedVersionID).To(BeZero())
+			})
+
+			It("can be unpinned", func() {
+				_, err := pipelineDB.PinResourceVersion(resourceName, savedVR.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				pinnedVersionsDB, err := pipelineDB.LoadVersionsDB()
+				Expect(err).NotTo(HaveOccurred())
+
+				err = pipelineDB.UnpinResource(resourceName)
+				Expect(err).NotTo(HaveOccurred())
+
+				unpinnedResource, _, err := pipelineDB.GetResource(resourceName)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(unpinnedResource.PinnedVersionID).To(BeZero())
+
+				versionsDB, err := pipelineDB.LoadVersionsDB()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(versionsDB != pinnedVersionsDB).To(BeTrue(), "Expected VersionsDB to be reloaded")
+				Expect(versionsDB.PinnedVersionIDs).To(BeEmpty())
+			})
+
+			It("invalidates the cached VersionsDB when repinned", func() {
+				_, err := pipelineDB.PinResourceVersion(resourceName, savedVR.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				versionsDB, err := pipelineDB.LoadVersionsDB()
+				Expect(err).NotTo(HaveOccurred())
+
+				latestVR, found, err := pipelineDB.GetLatestVersionedResource(resourceName)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+
+				_, err = pipelineDB.PinResourceVersion(resourceName, latestVR.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				cachedVersionsDB, err := pipelineDB.LoadVersionsDB()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(versionsDB != cachedVersionsDB).To(BeTrue(), "Expected VersionsDB to be different objects")
+				Expect(cachedVersionsDB.PinnedVersionIDs).To(Equal(map[int]int{resource.ID: latestVR.ID}))
+			})
+		})
+
 		Describe("enabling and disabling versioned resources", func() {
 			It("returns an error if the resource or version is bogus", func() {
 				err := pipelineDB.EnableVersionedResource(42)
@@ -1648,6 +1869,46 @@ var _ = Describe("PipelineDB", func() {
 			})
 		})
 
+		Describe("GetResourceVersionsBetween", func() {
+			BeforeEach(func() {
+				err := pipelineDB.SaveResourceVersions(atc.ResourceConfig{
+					Name:   "some-resource",
+					Type:   "some-type",
+					Source: atc.Source{"some": "source"},
+				}, []atc.Version{{"version": "1"}, {"version": "2"}, {"version": "3"}, {"version": "4"}})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			versionsOf := func(svrs []db.SavedVersionedResource) []db.Version {
+				versions := []db.Version{}
+				for _, svr := range svrs {
+					versions = append(versions, svr.Version)
+				}
+
+				return versions
+			}
+
+			It("returns the versions after one and up to another, newest first", func() {
+				versions, err := sqlDB.GetResourceVersionsBetween(context.TODO(), pipelineDB.GetPipelineID(), "some-resource", db.Version{"version": "1"}, db.Version{"version": "3"}, 10)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(versionsOf(versions)).To(Equal([]db.Version{{"version": "3"}, {"version": "2"}}))
+				Expect(versions[0].Resource).To(Equal("some-resource"))
+				Expect(versions[0].PipelineID).To(Equal(pipelineDB.GetPipelineID()))
+			})
+
+			It("returns at most the limit, keeping the newest", func() {
+				versions, err := sqlDB.GetResourceVersionsBetween(context.TODO(), pipelineDB.GetPipelineID(), "some-resource", db.Version{"version": "1"}, db.Version{"version": "4"}, 2)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(versionsOf(versions)).To(Equal([]db.Version{{"version": "4"}, {"version": "3"}}))
+			})
+
+			It("returns nothing when a version isn't known", func() {
+				versions, err := sqlDB.GetResourceVersionsBetween(context.TODO(), pipelineDB.GetPipelineID(), "some-resource", db.Version{"version": "bogus"}, db.Version{"version": "3"}, 10)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(versions).To(BeEmpty())
+			})
+		})
+
 		It("initially reports zero builds for a job", func() {
 			builds, err := pipelineDB.GetAllJobBuilds("some-job")
 			Expect(err).NotTo(HaveOccurred())
@@ -1672,6 +1933,7 @@ var _ = Describe("PipelineDB", func() {
 			Context("when the resource is first created", func() {
 				It("is not errored", func() {
 					Expect(resource.CheckError).To(BeNil())
+					Expect(resource.CheckErroredAt).To(BeZero())
 				})
 			})
 
@@ -1686,6 +1948,25 @@ var _ = Describe("PipelineDB", func() {
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(returnedResource.CheckError).To(Equal(originalCause))
+					Expect(returnedResource.CheckErroredAt).To(BeTemporally("~", time.Now(), time.Minute))
+				})
+
+				It("shows up as errored when listing resources", func() {
+					err := pipelineDB.SetResourceCheckError(resource, errors.New("on fire"))
+					Expect(err).NotTo(HaveOccurred())
+
+					dashboardResources, _, _, err := pipelineDB.GetResources()
+					Expect(err).NotTo(HaveOccurred())
+
+					var listedResource db.SavedResource
+					for _, dashboardResource := range dashboardResources {
+						if dashboardResource.Resource.Name == "some-resource" {
+							listedResource = dashboardResource.Resource
+						}
+					}
+
+					Expect(listedResource.CheckError).To(Equal(errors.New("on fire")))
+					Expect(listedResource.CheckErroredAt).To(BeTemporally("~", time.Now(), time.Minute))
 				})
 			})
 
@@ -1703,11 +1984,87 @@ var _ = Describe("PipelineDB", func() {
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(returnedResource.CheckError).To(BeNil())
+					Expect(returnedResource.CheckErroredAt).To(BeZero())
 				})
 			})
 		})
 	})
 
+	Describe("GetVersionsPassedJobs", func() {
+		var savedVR1, savedVR2 db.SavedVersionedResource
+
+		saveVersion := func(version string) db.SavedVersionedResource {
+			err := pipelineDB.SaveResourceVersions(atc.ResourceConfig{
+				Name:   "some-resource",
+				Type:   "some-type",
+				Source: atc.Source{"some": "source"},
+			}, []atc.Version{{"version": version}})
+			Expect(err).NotTo(HaveOccurred())
+
+			savedVR, found, err := pipelineDB.GetLatestVersionedResource("some-resource")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+
+			return savedVR
+		}
+
+		buildWithOutputs := func(jobName string, status db.Status, outputs ...db.SavedVersionedResource) {
+			build, err := pipelineDB.CreateJobBuild(jobName)
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, output := range outputs {
+				_, err := pipelineDB.SaveOutput(build.ID(), output.VersionedResource, false)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			err = build.Finish(status)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		BeforeEach(func() {
+			savedVR1 = saveVersion("1")
+			savedVR2 = saveVersion("2")
+			saveVersion("3")
+
+			buildWithOutputs("a-job", db.StatusSucceeded, savedVR1, savedVR2)
+			buildWithOutputs("shared-job", db.StatusSucceeded, savedVR2)
+			buildWithOutputs("shared-job", db.StatusFailed, savedVR1)
+		})
+
+		It("returns the versions that succeeded through every job, newest first", func() {
+			versions, found, err := pipelineDB.GetVersionsPassedJobs("some-resource", []string{"a-job"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(versions).To(HaveLen(2))
+			Expect(versions[0].ID).To(Equal(savedVR2.ID))
+			Expect(versions[0].Version).To(Equal(db.Version{"version": "2"}))
+			Expect(versions[1].ID).To(Equal(savedVR1.ID))
+
+			versions, found, err = pipelineDB.GetVersionsPassedJobs("some-resource", []string{"a-job", "shared-job"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(versions).To(HaveLen(1))
+			Expect(versions[0].ID).To(Equal(savedVR2.ID))
+		})
+
+		It("returns no versions when none have passed", func() {
+			versions, found, err := pipelineDB.GetVersionsPassedJobs("some-resource", []string{"random-job"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(versions).To(BeEmpty())
+		})
+
+		It("returns false if the resource or a job doesn't exist", func() {
+			_, found, err := pipelineDB.GetVersionsPassedJobs("bogus-resource", []string{"a-job"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+
+			_, found, err = pipelineDB.GetVersionsPassedJobs("some-resource", []string{"a-job", "bogus-job"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+	})
+
 	Describe("GetResourceType", func() {
 		It("returns no SavedResourceType with none saved", func() {
 			_, found, err := pipelineDB.GetResourceType("resource-type-name")
@@ -1839,7 +2196,7 @@ var _ = Describe("PipelineDB", func() {
 					pipelineConfig.Resources[2],
 				}
 
-				_, _, err := teamDB.SaveConfig("a-pipeline-name", pipelineConfigMinusResource, 1, db.PipelineNoChange)
+				_, _, err := teamDB.SaveConfig("a-pipeline-name", pipelineConfigMinusResource, 1, db.PipelineNoChange, "some-author")
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -2139,6 +2496,21 @@ var _ = Describe("PipelineDB", func() {
 				Expect(build.Status()).To(Equal(db.StatusPending))
 				Expect(build.IsScheduled()).To(BeFalse())
 				Expect(build.TeamName()).To(Equal("some-team"))
+				Expect(build.Params()).To(BeNil())
+			})
+		})
+
+		Describe("CreateJobBuildWithParams", func() {
+			It("records the params on the build", func() {
+				build, err := pipelineDB.CreateJobBuildWithParams("some-job", map[string]string{"environment": "staging"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(build.Status()).To(Equal(db.StatusPending))
+				Expect(build.Params()).To(Equal(map[string]string{"environment": "staging"}))
+
+				found, err := build.Reload()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(build.Params()).To(Equal(map[string]string{"environment": "staging"}))
 			})
 		})
 
@@ -2406,6 +2778,44 @@ var _ = Describe("PipelineDB", func() {
 			})
 		})
 
+		Describe("making jobs manual only and automatic", func() {
+			job := "some-job"
+
+			It("starts out as automatic", func() {
+				job, err := pipelineDB.GetJob(job)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(job.ManualOnly).To(BeFalse())
+			})
+
+			It("can be made manual only", func() {
+				err := pipelineDB.MakeJobManualOnly(job)
+				Expect(err).NotTo(HaveOccurred())
+
+				manualOnlyJob, err := pipelineDB.GetJob(job)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(manualOnlyJob.ManualOnly).To(BeTrue())
+				Expect(manualOnlyJob.Paused).To(BeFalse())
+
+				otherJob, err := otherPipelineDB.GetJob(job)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(otherJob.ManualOnly).To(BeFalse())
+			})
+
+			It("can be made automatic again", func() {
+				err := pipelineDB.MakeJobManualOnly(job)
+				Expect(err).NotTo(HaveOccurred())
+
+				err = pipelineDB.MakeJobAutomatic(job)
+				Expect(err).NotTo(HaveOccurred())
+
+				automaticJob, err := pipelineDB.GetJob(job)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(automaticJob.ManualOnly).To(BeFalse())
+			})
+		})
+
 		Describe("UpdateFirstLoggedBuildID", func() {
 			It("updates FirstLoggedBuildID on a job", func() {
 				By("starting out as 0")
@@ -2437,6 +2847,79 @@ var _ = Describe("PipelineDB", func() {
 			})
 		})
 
+		Describe("SaveJobLastScheduledAt", func() {
+			It("only moves the last scheduled time on from the time it was read at", func() {
+				By("starting out as never scheduled")
+				lastScheduledAt, err := pipelineDB.GetJobLastScheduledAt("some-job")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(lastScheduledAt).To(BeZero())
+
+				By("claiming the first slot")
+				firstSlot := time.Date(2016, 10, 1, 12, 0, 0, 0, time.UTC)
+				claimed, err := pipelineDB.SaveJobLastScheduledAt("some-job", time.Time{}, firstSlot)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(claimed).To(BeTrue())
+
+				lastScheduledAt, err = pipelineDB.GetJobLastScheduledAt("some-job")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(lastScheduledAt.Equal(firstSlot)).To(BeTrue())
+
+				By("refusing a claim made from a stale read")
+				claimed, err = pipelineDB.SaveJobLastScheduledAt("some-job", time.Time{}, firstSlot.Add(time.Hour))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(claimed).To(BeFalse())
+
+				By("claiming the next slot")
+				claimed, err = pipelineDB.SaveJobLastScheduledAt("some-job", firstSlot, firstSlot.Add(time.Hour))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(claimed).To(BeTrue())
+			})
+		})
+
+		Describe("GetJobScheduling", func() {
+			It("returns what the scheduler last recorded for the job", func() {
+				By("not being found until the scheduler has looked at the job")
+				_, found, err := pipelineDB.GetJobScheduling("some-job")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeFalse())
+
+				err = pipelineDB.SaveMissingInputReasons("some-job", db.MissingInputReasons{
+					"some-input": db.NoVersionsAvailable,
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				err = pipelineDB.SaveSchedulingReason("some-job", db.SchedulingReasonNoPendingBuilds)
+				Expect(err).NotTo(HaveOccurred())
+
+				scheduling, found, err := pipelineDB.GetJobScheduling("some-job")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(scheduling.Reason).To(Equal(db.SchedulingReasonNoPendingBuilds))
+				Expect(scheduling.MissingInputReasons).To(Equal(db.MissingInputReasons{
+					"some-input": db.NoVersionsAvailable,
+				}))
+				Expect(scheduling.CheckedAt).To(BeTemporally("~", time.Now(), time.Minute))
+
+				By("replacing them when the scheduler looks again")
+				err = pipelineDB.SaveMissingInputReasons("some-job", db.MissingInputReasons{})
+				Expect(err).NotTo(HaveOccurred())
+
+				err = pipelineDB.SaveSchedulingReason("some-job", db.SchedulingReasonJobPaused)
+				Expect(err).NotTo(HaveOccurred())
+
+				scheduling, found, err = pipelineDB.GetJobScheduling("some-job")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(scheduling.Reason).To(Equal(db.SchedulingReasonJobPaused))
+				Expect(scheduling.MissingInputReasons).To(BeEmpty())
+
+				By("not being found for jobs that don't exist")
+				_, found, err = pipelineDB.GetJobScheduling("bogus-job")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeFalse())
+			})
+		})
+
 		Describe("GetJobBuild", func() {
 			var firstBuild db.Build
 			var job db.SavedJob
@@ -2542,6 +3025,54 @@ var _ = Describe("PipelineDB", func() {
 				Expect(found).To(BeTrue())
 				Expect(build.ID()).To(Equal(buildThree.ID()))
 			})
+
+			It("returns higher priority builds first", func() {
+				_, err := pipelineDB.CreateJobBuild(jobOneConfig.Name)
+				Expect(err).NotTo(HaveOccurred())
+
+				urgentBuild, err := pipelineDB.CreateJobBuild(jobOneTwoConfig.Name)
+				Expect(err).NotTo(HaveOccurred())
+
+				updated, err := urgentBuild.SetPriority(10)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(updated).To(BeTrue())
+
+				err = pipelineDB.SaveNextInputMapping(nil, "some-job")
+				Expect(err).NotTo(HaveOccurred())
+				err = pipelineDB.SaveNextInputMapping(nil, "other-serial-group-job")
+				Expect(err).NotTo(HaveOccurred())
+
+				build, found, err := pipelineDB.GetNextPendingBuildBySerialGroup(jobOneConfig.Name, []string{"serial-group"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(build.ID()).To(Equal(urgentBuild.ID()))
+				Expect(build.Priority()).To(Equal(10))
+			})
+		})
+
+		Describe("GetPendingBuildPriorities", func() {
+			It("returns the highest priority of each job's pending builds", func() {
+				_, err := pipelineDB.CreateJobBuild("some-job")
+				Expect(err).NotTo(HaveOccurred())
+
+				urgentBuild, err := pipelineDB.CreateJobBuild("some-job")
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = urgentBuild.SetPriority(3)
+				Expect(err).NotTo(HaveOccurred())
+
+				finishedBuild, err := pipelineDB.CreateJobBuild("some-other-job")
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = finishedBuild.SetPriority(7)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(finishedBuild.Finish(db.StatusSucceeded)).To(Succeed())
+
+				priorities, err := pipelineDB.GetPendingBuildPriorities()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(priorities).To(Equal(map[string]int{"some-job": 3}))
+			})
 		})
 
 		Describe("GetRunningBuildsBySerialGroup", func() {
@@ -2861,5 +3392,242 @@ var _ = Describe("PipelineDB", func() {
 			Expect(next.ID()).To(Equal(anotherRunningBuild.ID()))
 			Expect(finished.ID()).To(Equal(nextBuild.ID()))
 		})
+
+		Describe("GetJobBuildStats", func() {
+			finishBuild := func(status db.Status, duration time.Duration, endedAgo time.Duration) {
+				build, err := pipelineDB.CreateJobBuild("some-job")
+				Expect(err).NotTo(HaveOccurred())
+
+				started, err := build.Start("some-engine", "meta")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(started).To(BeTrue())
+
+				err = build.Finish(status)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = dbConn.Exec(`
+					UPDATE builds
+					SET end_time = now() - ($2 || ' SECONDS')::INTERVAL,
+						start_time = now() - ($3 || ' SECONDS')::INTERVAL
+					WHERE id = $1
+				`, build.ID(), endedAgo.Seconds(), (endedAgo + duration).Seconds())
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			BeforeEach(func() {
+				for i := 1; i <= 18; i++ {
+					finishBuild(db.StatusSucceeded, time.Duration(i)*time.Minute, time.Hour)
+				}
+
+				finishBuild(db.StatusFailed, 19*time.Minute, time.Hour)
+				finishBuild(db.StatusFailed, 20*time.Minute, time.Hour)
+				finishBuild(db.StatusErrored, 2*time.Hour, time.Hour)
+				finishBuild(db.StatusAborted, 3*time.Hour, time.Hour)
+
+				finishBuild(db.StatusSucceeded, 10*time.Hour, 48*time.Hour)
+
+				otherBuild, err := otherPipelineDB.CreateJobBuild("some-job")
+				Expect(err).NotTo(HaveOccurred())
+
+				err = otherBuild.Finish(db.StatusFailed)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("summarizes the job's builds that finished within the window", func() {
+				stats, err := pipelineDB.GetJobBuildStats("some-job", 24*time.Hour)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(stats.Succeeded).To(Equal(18))
+				Expect(stats.Failed).To(Equal(2))
+				Expect(stats.Errored).To(Equal(1))
+				Expect(stats.Aborted).To(Equal(1))
+				Expect(stats.TimedOut).To(BeZero())
+
+				Expect(stats.MeanDuration).To(BeNumerically("~", 630*time.Second, time.Second))
+				Expect(stats.MedianDuration).To(BeNumerically("~", 10*time.Minute, time.Second))
+				Expect(stats.P95Duration).To(BeNumerically("~", 19*time.Minute, time.Second))
+			})
+
+			It("includes older builds in wider windows", func() {
+				stats, err := pipelineDB.GetJobBuildStats("some-job", 72*time.Hour)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(stats.Succeeded).To(Equal(19))
+				Expect(stats.P95Duration).To(BeNumerically("~", 20*time.Minute, time.Second))
+			})
+
+			Context("when no builds finished within the window", func() {
+				It("returns zero stats", func() {
+					stats, err := pipelineDB.GetJobBuildStats("some-job", time.Minute)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(stats).To(BeZero())
+				})
+			})
+
+			Context("when the job does not exist", func() {
+				It("returns an error", func() {
+					_, err := pipelineDB.GetJobBuildStats("bogus-job", time.Hour)
+					Expect(err).To(HaveOccurred())
+				})
+			})
+		})
+
+		Describe("GetJobOutcomes", func() {
+			var versions []db.SavedVersionedResource
+
+			finishBuild := func(version int, status db.Status) {
+				build, err := pipelineDB.CreateJobBuild("some-job")
+				Expect(err).NotTo(HaveOccurred())
+
+				if version >= 0 {
+					_, err = pipelineDB.SaveInput(build.ID(), db.BuildInput{
+						Name:              "some-input",
+						VersionedResource: versions[version].VersionedResource,
+					})
+					Expect(err).NotTo(HaveOccurred())
+				}
+
+				err = build.Finish(status)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			BeforeEach(func() {
+				resourceConfig := atc.ResourceConfig{
+					Name:   "some-resource",
+					Type:   "some-type",
+					Source: atc.Source{"source-config": "some-value"},
+				}
+
+				err := pipelineDB.SaveResourceVersions(resourceConfig, []atc.Version{{"version": "1"}, {"version": "2"}})
+				Expect(err).NotTo(HaveOccurred())
+
+				versions = nil
+				for _, version := range []string{"1", "2"} {
+					savedVR, found, err := pipelineDB.GetVersionedResourceByVersion(atc.Version{"version": version}, "some-resource")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(found).To(BeTrue())
+
+					versions = append(versions, savedVR)
+				}
+
+				finishBuild(0, db.StatusSucceeded)
+				finishBuild(1, db.StatusSucceeded)
+				finishBuild(0, db.StatusFailed)
+				finishBuild(1, db.StatusSucceeded)
+				finishBuild(1, db.StatusErrored)
+				finishBuild(0, db.StatusSucceeded)
+				finishBuild(-1, db.StatusFailed)
+			})
+
+			It("compares each build with the last one of the same inputs", func() {
+				outcomes, err := pipelineDB.GetJobOutcomes("some-job", 100)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(outcomes).To(Equal(db.JobOutcomes{
+					Compared: 3,
+					Flipped:  2,
+				}))
+			})
+
+			It("only looks at the most recent builds", func() {
+				outcomes, err := pipelineDB.GetJobOutcomes("some-job", 3)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(outcomes).To(Equal(db.JobOutcomes{
+					Compared: 1,
+					Flipped:  1,
+				}))
+			})
+
+			It("can save the job's flakiness", func() {
+				err := pipelineDB.SaveJobFlakiness("some-job", 0.5, true)
+				Expect(err).NotTo(HaveOccurred())
+
+				job, err := pipelineDB.GetJob("some-job")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(job.Flakiness).To(Equal(0.5))
+				Expect(job.Flaky).To(BeTrue())
+
+				otherJob, err := otherPipelineDB.GetJob("some-job")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(otherJob.Flaky).To(BeFalse())
+			})
+		})
+
+		Describe("GetJobArtifactBuilds", func() {
+			var succeeded, failed db.Build
+
+			BeforeEach(func() {
+				var err error
+				succeeded, err = pipelineDB.CreateJobBuild("some-job")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(succeeded.SaveArtifact(db.BuildArtifact{Name: "some-artifact", ContainerHandle: "some-handle", Path: "/tmp/some"})).To(Succeed())
+				Expect(succeeded.SaveArtifact(db.BuildArtifact{Name: "other-artifact", ContainerHandle: "some-handle", Path: "/tmp/other"})).To(Succeed())
+				Expect(succeeded.Finish(db.StatusSucceeded)).To(Succeed())
+
+				failed, err = pipelineDB.CreateJobBuild("some-job")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(failed.Finish(db.StatusFailed)).To(Succeed())
+
+				_, err = pipelineDB.CreateJobBuild("some-job")
+				Expect(err).NotTo(HaveOccurred())
+
+				otherBuild, err := otherPipelineDB.CreateJobBuild("some-job")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(otherBuild.Finish(db.StatusSucceeded)).To(Succeed())
+			})
+
+			It("returns the job's finished builds with their artifacts, newest first", func() {
+				builds, err := pipelineDB.GetJobArtifactBuilds("some-job")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(builds).To(HaveLen(2))
+
+				Expect(builds[0].ID).To(Equal(failed.ID()))
+				Expect(builds[0].Status).To(Equal(db.StatusFailed))
+				Expect(builds[0].Artifacts).To(BeEmpty())
+
+				Expect(builds[1].ID).To(Equal(succeeded.ID()))
+				Expect(builds[1].Name).To(Equal(succeeded.Name()))
+				Expect(builds[1].Status).To(Equal(db.StatusSucceeded))
+				Expect(builds[1].EndTime).NotTo(BeZero())
+				Expect(builds[1].Protected).To(BeFalse())
+				Expect(builds[1].Artifacts).To(Equal([]string{"other-artifact", "some-artifact"}))
+			})
+
+			It("can protect and unprotect builds", func() {
+				found, err := pipelineDB.SetBuildArtifactsProtected("some-job", succeeded.Name(), true)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+
+				builds, err := pipelineDB.GetJobArtifactBuilds("some-job")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(builds[0].Protected).To(BeFalse())
+				Expect(builds[1].Protected).To(BeTrue())
+
+				found, err = pipelineDB.SetBuildArtifactsProtected("some-job", succeeded.Name(), false)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+
+				builds, err = pipelineDB.GetJobArtifactBuilds("some-job")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(builds[1].Protected).To(BeFalse())
+
+				found, err = pipelineDB.SetBuildArtifactsProtected("some-job", "bogus", true)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeFalse())
+			})
+
+			It("stops returning builds once they've been reaped", func() {
+				Expect(sqlDB.MarkBuildArtifactsReaped(succeeded.ID())).To(Succeed())
+
+				builds, err := pipelineDB.GetJobArtifactBuilds("some-job")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(builds).To(HaveLen(1))
+				Expect(builds[0].ID).To(Equal(failed.ID()))
+
+				artifacts, err := succeeded.GetArtifacts()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(artifacts).To(BeEmpty())
+			})
+		})
 	})
 })
@@ -0,0 +1,79 @@
+package db
+
+// DefaultPipelineWeight is the weight of pipelines that haven't been given
+// one.
+const DefaultPipelineWeight = 1
+
+type PipelineWeight struct {
+	PipelineID   int
+	PipelineName string
+	TeamName     string
+	Weight       int
+}
+
+// PipelineLoad is how many of a pipeline's builds are running, or have been
+// scheduled to, and how many more are pending and could be started.
+type PipelineLoad struct {
+	PipelineID int
+	Weight     int
+	InFlight   int
+	Waiting    int
+}
+
+// PipelineLoads is every pipeline with builds in flight or waiting to start.
+type PipelineLoads []PipelineLoad
+
+// FairShareReached returns whether a pipeline should leave the rest of the
+// global max in flight to others. Each pipeline with builds in flight or
+// waiting gets a share of the limit in proportion to its weight. A pipeline
+// at or over its share may still start builds, but only while there are more
+// free slots than the pipelines under their share are waiting for, so that
+// slots aren't left idle.
+func (loads PipelineLoads) FairShareReached(pipelineID int, maxInFlight int) bool {
+	if maxInFlight <= 0 {
+		return false
+	}
+
+	var totalWeight, inFlight int
+	for _, load := range loads {
+		totalWeight += load.Weight
+		inFlight += load.InFlight
+	}
+
+	if totalWeight == 0 {
+		return false
+	}
+
+	// how many more builds a pipeline may have before it reaches its share,
+	// rounded up
+	shortfall := func(load PipelineLoad) int {
+		short := maxInFlight*load.Weight - load.InFlight*totalWeight
+		if short <= 0 {
+			return 0
+		}
+
+		return (short + totalWeight - 1) / totalWeight
+	}
+
+	var reserved int
+	for _, load := range loads {
+		if load.PipelineID == pipelineID {
+			if shortfall(load) > 0 {
+				return false
+			}
+
+			continue
+		}
+
+		if load.Waiting > 0 {
+			short := shortfall(load)
+			if load.Waiting < short {
+				short = load.Waiting
+			}
+
+			reserved += short
+		}
+	}
+
+	return maxInFlight-inFlight <= reserved
+}
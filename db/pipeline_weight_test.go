@@ -0,0 +1,88 @@
+package db_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/atc/db"
+)
+
+var _ = Describe("PipelineLoads", func() {
+	Describe("FairShareReached", func() {
+		It("is never reached without a global max in flight", func() {
+			loads := db.PipelineLoads{
+				{PipelineID: 1, Weight: 1, InFlight: 10, Waiting: 100},
+				{PipelineID: 2, Weight: 1, Waiting: 1},
+			}
+
+			Expect(loads.FairShareReached(1, 0)).To(BeFalse())
+		})
+
+		It("lets a pipeline under its share start builds", func() {
+			loads := db.PipelineLoads{
+				{PipelineID: 1, Weight: 1, InFlight: 1, Waiting: 100},
+				{PipelineID: 2, Weight: 1, InFlight: 3, Waiting: 1},
+			}
+
+			Expect(loads.FairShareReached(1, 4)).To(BeFalse())
+		})
+
+		It("holds back a pipeline at its share while another is waiting for its own", func() {
+			loads := db.PipelineLoads{
+				{PipelineID: 1, Weight: 1, InFlight: 2, Waiting: 100},
+				{PipelineID: 2, Weight: 1, InFlight: 0, Waiting: 5},
+			}
+
+			Expect(loads.FairShareReached(1, 4)).To(BeTrue())
+			Expect(loads.FairShareReached(2, 4)).To(BeFalse())
+		})
+
+		It("lets a pipeline over its share use slots nobody else is waiting for", func() {
+			loads := db.PipelineLoads{
+				{PipelineID: 1, Weight: 1, InFlight: 2, Waiting: 100},
+				{PipelineID: 2, Weight: 1, InFlight: 1},
+			}
+
+			Expect(loads.FairShareReached(1, 4)).To(BeFalse())
+		})
+
+		It("only reserves as many slots as others are waiting for", func() {
+			loads := db.PipelineLoads{
+				{PipelineID: 1, Weight: 1, InFlight: 3, Waiting: 100},
+				{PipelineID: 2, Weight: 1, InFlight: 0, Waiting: 1},
+			}
+
+			Expect(loads.FairShareReached(1, 10)).To(BeFalse())
+
+			loads[0].InFlight = 9
+			Expect(loads.FairShareReached(1, 10)).To(BeTrue())
+		})
+
+		It("gives pipelines shares in proportion to their weight", func() {
+			loads := db.PipelineLoads{
+				{PipelineID: 1, Weight: 3, InFlight: 2, Waiting: 100},
+				{PipelineID: 2, Weight: 1, InFlight: 1, Waiting: 100},
+			}
+
+			Expect(loads.FairShareReached(1, 4)).To(BeFalse())
+			Expect(loads.FairShareReached(2, 4)).To(BeTrue())
+
+			loads[0].InFlight = 3
+			loads[1].InFlight = 0
+			Expect(loads.FairShareReached(1, 4)).To(BeTrue())
+			Expect(loads.FairShareReached(2, 4)).To(BeFalse())
+		})
+
+		It("takes turns when there are more pipelines than slots", func() {
+			loads := db.PipelineLoads{
+				{PipelineID: 1, Weight: 1, InFlight: 1, Waiting: 10},
+				{PipelineID: 2, Weight: 1, InFlight: 0, Waiting: 10},
+				{PipelineID: 3, Weight: 1, InFlight: 0, Waiting: 10},
+			}
+
+			Expect(loads.FairShareReached(1, 2)).To(BeTrue())
+			Expect(loads.FairShareReached(2, 2)).To(BeFalse())
+			Expect(loads.FairShareReached(3, 2)).To(BeFalse())
+		})
+	})
+})
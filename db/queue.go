@@ -0,0 +1,28 @@
+package db
+
+import "time"
+
+// QueuedBuild is a pending build of a job, and what it's waiting on as far as
+// the scheduler knows.
+//
+// Reason is what the scheduler last recorded for the job, which applies to
+// the job's next build to start; it's empty if the scheduler hasn't looked at
+// the job since the build was created. Position is where the build is in the
+// order the job's builds will be started in, counting from 1, which is a
+// lower bound on how long it'll be as builds of other jobs in its serial
+// groups may get in first.
+type QueuedBuild struct {
+	BuildID      int
+	BuildName    string
+	TeamName     string
+	PipelineName string
+	JobName      string
+	Priority     int
+
+	// CreateTime is zero for builds created before it was recorded.
+	CreateTime time.Time
+
+	Reason    SchedulingReason
+	CheckedAt time.Time
+	Position  int
+}
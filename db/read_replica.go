@@ -0,0 +1,105 @@
+package db
+
+import (
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+)
+
+// replicaLagCheckInterval is how long a replica's lag is trusted for before
+// it's checked again.
+const replicaLagCheckInterval = 5 * time.Second
+
+// ReadReplica is a read-only copy of the database, e.g. a Postgres hot
+// standby, that reads which can put up with being a little behind are sent
+// to in order to take load off the primary. It's only read from for as long
+// as it's no further behind the primary than MaxLag.
+//
+// Lag is measured from the last transaction the replica replayed, so a
+// replica of a primary that's had nothing to write for a while looks like
+// it's fallen behind, and reads go to the primary until it writes again.
+type ReadReplica struct {
+	logger lager.Logger
+
+	conn   Conn
+	maxLag time.Duration
+
+	checkedL  sync.Mutex
+	checkedAt time.Time
+	caughtUp  bool
+}
+
+func NewReadReplica(logger lager.Logger, conn Conn, maxLag time.Duration) *ReadReplica {
+	return &ReadReplica{
+		logger: logger,
+		conn:   conn,
+		maxLag: maxLag,
+	}
+}
+
+func (replica *ReadReplica) usable() bool {
+	replica.checkedL.Lock()
+	defer replica.checkedL.Unlock()
+
+	if time.Since(replica.checkedAt) < replicaLagCheckInterval {
+		return replica.caughtUp
+	}
+
+	replica.checkedAt = time.Now()
+
+	// a database that isn't replaying anything isn't a replica, and so is as
+	// caught up as it gets
+	var lag float64
+	err := replica.conn.QueryRow(`
+		SELECT COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+	`).Scan(&lag)
+	if err != nil {
+		replica.logger.Error("failed-to-check-replica-lag", err)
+		replica.caughtUp = false
+		return false
+	}
+
+	caughtUp := secondsDuration(lag) <= replica.maxLag
+	if caughtUp != replica.caughtUp {
+		replica.logger.Info("replica-lag-changed", lager.Data{
+			"lag":       secondsDuration(lag).String(),
+			"caught-up": caughtUp,
+		})
+	}
+
+	replica.caughtUp = caughtUp
+
+	return caughtUp
+}
+
+// WithReadReplica returns a copy of the SQLDB that sends reads that can put
+// up with being a little stale to the replica. Everything else, writes
+// included, keeps going to the primary, as does anything done with the
+// builds that are read.
+func (db *SQLDB) WithReadReplica(replica *ReadReplica) *SQLDB {
+	withReplica := *db
+	withReplica.replica = replica
+	return &withReplica
+}
+
+// readStale runs the read on the read replica, if there is one and it's
+// caught up enough, and on the primary otherwise. The read is run again on
+// the primary should it fail on the replica, or should it return false for
+// the replica maybe not having caught up with what was asked for, e.g. a
+// build that was only just created.
+func (db *SQLDB) readStale(read func(conn Conn) (bool, error)) error {
+	if db.replica != nil && db.replica.usable() {
+		complete, err := read(db.replica.conn)
+		if err == nil && complete {
+			return nil
+		}
+
+		if err != nil {
+			db.replica.logger.Error("failed-to-read-from-replica", err)
+		}
+	}
+
+	_, err := read(db.conn)
+	return err
+}
@@ -0,0 +1,227 @@
+package db_test
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	"github.com/lib/pq"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/db/dbfakes"
+	"github.com/concourse/atc/event"
+)
+
+// snapshotConn reads from a snapshot of the database taken when it was
+// opened, like a replica that has stopped replaying the primary's writes.
+type snapshotConn struct {
+	db.Conn
+
+	tx *sql.Tx
+}
+
+func openSnapshot() snapshotConn {
+	conn := db.Wrap(postgresRunner.Open())
+
+	tx, err := conn.BeginTx(context.Background(), &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	Expect(err).NotTo(HaveOccurred())
+
+	// the snapshot is taken on the first query
+	_, err = tx.Exec(`SELECT 1`)
+	Expect(err).NotTo(HaveOccurred())
+
+	return snapshotConn{Conn: conn, tx: tx.(*sql.Tx)}
+}
+
+func (conn snapshotConn) Close() error {
+	conn.tx.Rollback()
+	return conn.Conn.Close()
+}
+
+func (conn snapshotConn) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return conn.tx.Query(query, args...)
+}
+
+func (conn snapshotConn) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return conn.tx.QueryContext(ctx, query, args...)
+}
+
+func (conn snapshotConn) QueryRow(query string, args ...interface{}) *sql.Row {
+	return conn.tx.QueryRow(query, args...)
+}
+
+func (conn snapshotConn) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return conn.tx.QueryRowContext(ctx, query, args...)
+}
+
+var _ = Describe("Read replicas", func() {
+	var (
+		dbConn     db.Conn
+		listener   *pq.Listener
+		database   *db.SQLDB
+		pipelineDB db.PipelineDB
+
+		replicaConn snapshotConn
+		maxLag      time.Duration
+
+		replicated *db.SQLDB
+
+		oldBuild db.Build
+		newBuild db.Build
+	)
+
+	BeforeEach(func() {
+		postgresRunner.Truncate()
+
+		dbConn = db.Wrap(postgresRunner.Open())
+		listener = pq.NewListener(postgresRunner.DataSourceName(), time.Second, time.Minute, nil)
+
+		Eventually(listener.Ping, 5*time.Second).ShouldNot(HaveOccurred())
+		bus := db.NewNotificationsBus(listener, dbConn)
+
+		pgxConn := postgresRunner.OpenPgx()
+		fakeConnector := new(dbfakes.FakeConnector)
+		retryableConn := &db.RetryableConn{Connector: fakeConnector, Conn: pgxConn}
+
+		lockFactory := db.NewLockFactory(retryableConn)
+		database = db.NewSQL(dbConn, bus, lockFactory)
+		_, err := database.CreateTeam(db.Team{Name: "some-team"})
+		Expect(err).NotTo(HaveOccurred())
+
+		teamDB := db.NewTeamDBFactory(dbConn, bus, lockFactory).GetTeamDB("some-team")
+
+		pipeline, _, err := teamDB.SaveConfig("some-pipeline", atc.Config{
+			Jobs: atc.JobConfigs{{Name: "some-job"}},
+		}, db.ConfigVersion(1), db.PipelineUnpaused, "some-author")
+		Expect(err).NotTo(HaveOccurred())
+
+		pipelineDB = db.NewPipelineDBFactory(dbConn, bus, lockFactory).Build(pipeline)
+
+		oldBuild, err = pipelineDB.CreateJobBuild("some-job")
+		Expect(err).NotTo(HaveOccurred())
+
+		err = oldBuild.SaveEvent(event.Log{Payload: "some-log"})
+		Expect(err).NotTo(HaveOccurred())
+
+		replicaConn = openSnapshot()
+		maxLag = time.Minute
+
+		// writes since the snapshot that the replica hasn't caught up with
+		_, err = oldBuild.SetPriority(10)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = oldBuild.SaveEvent(event.Log{Payload: "more-log"})
+		Expect(err).NotTo(HaveOccurred())
+
+		newBuild, err = pipelineDB.CreateJobBuild("some-job")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		replica := db.NewReadReplica(lagertest.NewTestLogger("test"), replicaConn, maxLag)
+		replicated = database.WithReadReplica(replica)
+	})
+
+	AfterEach(func() {
+		replicaConn.Close()
+
+		err := dbConn.Close()
+		Expect(err).NotTo(HaveOccurred())
+
+		err = listener.Close()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Describe("GetBuildByID", func() {
+		It("reads builds from the replica", func() {
+			build, found, err := replicated.GetBuildByID(oldBuild.ID())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(build.Priority()).To(BeZero())
+		})
+
+		It("writes to the primary", func() {
+			build, _, err := replicated.GetBuildByID(oldBuild.ID())
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = build.SetPriority(20)
+			Expect(err).NotTo(HaveOccurred())
+
+			reloaded, _, err := database.GetBuildByID(oldBuild.ID())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reloaded.Priority()).To(Equal(20))
+		})
+
+		It("falls back on the primary for builds the replica doesn't have yet", func() {
+			build, found, err := replicated.GetBuildByID(newBuild.ID())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(build.ID()).To(Equal(newBuild.ID()))
+		})
+
+		It("doesn't find builds that don't exist anywhere", func() {
+			_, found, err := replicated.GetBuildByID(newBuild.ID() + 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+
+		Context("when the replica is further behind than the max lag", func() {
+			BeforeEach(func() {
+				maxLag = -time.Second
+			})
+
+			It("reads from the primary", func() {
+				build, found, err := replicated.GetBuildByID(oldBuild.ID())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(build.Priority()).To(Equal(10))
+			})
+		})
+
+		Context("when the replica can't be read from", func() {
+			BeforeEach(func() {
+				replicaConn.Close()
+			})
+
+			It("reads from the primary", func() {
+				build, found, err := replicated.GetBuildByID(oldBuild.ID())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(build.Priority()).To(Equal(10))
+			})
+		})
+	})
+
+	Describe("GetBuilds", func() {
+		It("falls back on the primary when the replica doesn't have all of them", func() {
+			builds, err := replicated.GetBuilds(context.Background(), []int{oldBuild.ID(), newBuild.ID()})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(builds).To(HaveLen(2))
+			Expect(builds[0].ID()).To(Equal(newBuild.ID()))
+			Expect(builds[1].ID()).To(Equal(oldBuild.ID()))
+			Expect(builds[1].Priority()).To(Equal(10))
+		})
+
+		It("reads from the replica when it has them all", func() {
+			builds, err := replicated.GetBuilds(context.Background(), []int{oldBuild.ID(), oldBuild.ID()})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(builds).To(HaveLen(1))
+			Expect(builds[0].Priority()).To(BeZero())
+		})
+	})
+
+	Describe("GetBuildEventsFrom", func() {
+		It("falls back on the primary for the latest events of running builds", func() {
+			events, err := replicated.GetBuildEventsFrom(context.Background(), oldBuild.ID(), 0, 100)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(HaveLen(2))
+		})
+	})
+})
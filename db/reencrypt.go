@@ -0,0 +1,174 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+
+	"code.cloudfoundry.org/lager"
+)
+
+const reencryptBatchSize = 500
+
+type encryptedColumn struct {
+	table  string
+	keys   []string
+	column string
+}
+
+// encryptedColumns are every column written with the conn's encryption
+// strategy.
+var encryptedColumns = []encryptedColumn{
+	{table: "pipelines", keys: []string{"id"}, column: "config"},
+	{table: "pipeline_config_revisions", keys: []string{"pipeline_id", "version"}, column: "config"},
+	{table: "pipeline_candidates", keys: []string{"id"}, column: "config"},
+	{table: "builds", keys: []string{"id"}, column: "engine_metadata"},
+	{table: "builds", keys: []string{"id"}, column: "pending_plan"},
+	{table: "builds", keys: []string{"id"}, column: "params"},
+	{table: "builds", keys: []string{"id"}, column: "secrets"},
+}
+
+// Reencrypt rewrites every encrypted column with the conn's encryption
+// strategy, for rotating keys: given a strategy from
+// encryption.NewRotationStrategy, values encrypted with the old key end up
+// encrypted with the new one. Values still in the clear are encrypted, or
+// with NoEncryption as the new strategy, everything is decrypted.
+//
+// Rows are rewritten in batches, and only if they haven't changed since
+// they were read, so it's safe to run while ATCs using the same strategy are
+// writing to them.
+func Reencrypt(logger lager.Logger, conn Conn) error {
+	for _, column := range encryptedColumns {
+		rewritten, err := column.reencrypt(conn)
+		if err != nil {
+			return fmt.Errorf("failed to re-encrypt %s.%s: %s", column.table, column.column, err)
+		}
+
+		logger.Info("reencrypted", lager.Data{
+			"table":  column.table,
+			"column": column.column,
+			"rows":   rewritten,
+		})
+	}
+
+	return nil
+}
+
+func (column encryptedColumn) reencrypt(conn Conn) (int, error) {
+	strategy := conn.EncryptionStrategy()
+
+	keyList := strings.Join(column.keys, ", ")
+	after := make([]int, len(column.keys))
+
+	afterParams := make([]string, len(column.keys))
+	updateConditions := make([]string, len(column.keys))
+	for i, key := range column.keys {
+		afterParams[i] = fmt.Sprintf("$%d", i+1)
+		updateConditions[i] = fmt.Sprintf("%s = $%d", key, i+3)
+	}
+
+	selectQuery := fmt.Sprintf(`
+		SELECT %[1]s, %[2]s
+		FROM %[3]s
+		WHERE %[2]s IS NOT NULL
+			AND (%[1]s) > (%[4]s)
+		ORDER BY %[1]s
+		LIMIT %[5]d
+	`, keyList, column.column, column.table, strings.Join(afterParams, ", "), reencryptBatchSize)
+
+	updateQuery := fmt.Sprintf(`
+		UPDATE %[1]s
+		SET %[2]s = $1
+		WHERE %[2]s = $2
+			AND %[3]s
+	`, column.table, column.column, strings.Join(updateConditions, " AND "))
+
+	rewritten := 0
+
+	for {
+		start := make([]interface{}, len(after))
+		for i, key := range after {
+			start[i] = key
+		}
+
+		rows, err := conn.Query(selectQuery, start...)
+		if err != nil {
+			return rewritten, err
+		}
+
+		type storedValue struct {
+			keys  []interface{}
+			value string
+		}
+
+		batch := []storedValue{}
+
+		for rows.Next() {
+			keys := make([]int, len(column.keys))
+			var value string
+
+			destinations := make([]interface{}, len(keys)+1)
+			for i := range keys {
+				destinations[i] = &keys[i]
+			}
+			destinations[len(keys)] = &value
+
+			err := rows.Scan(destinations...)
+			if err != nil {
+				rows.Close()
+				return rewritten, err
+			}
+
+			stored := storedValue{value: value}
+			for _, key := range keys {
+				stored.keys = append(stored.keys, key)
+			}
+
+			batch = append(batch, stored)
+			after = keys
+		}
+
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return rewritten, err
+		}
+
+		if len(batch) == 0 {
+			return rewritten, nil
+		}
+
+		tx, err := conn.Begin()
+		if err != nil {
+			return rewritten, err
+		}
+
+		for _, stored := range batch {
+			plaintext, err := strategy.Decrypt(stored.value)
+			if err != nil {
+				tx.Rollback()
+				return rewritten, err
+			}
+
+			ciphertext, err := strategy.Encrypt(plaintext)
+			if err != nil {
+				tx.Rollback()
+				return rewritten, err
+			}
+
+			args := append([]interface{}{ciphertext, stored.value}, stored.keys...)
+
+			_, err = tx.Exec(updateQuery, args...)
+			if err != nil {
+				tx.Rollback()
+				return rewritten, err
+			}
+		}
+
+		err = tx.Commit()
+		if err != nil {
+			return rewritten, err
+		}
+
+		rewritten += len(batch)
+	}
+}
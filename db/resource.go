@@ -15,6 +15,15 @@ type SavedResource struct {
 	CheckError   error
 	Paused       bool
 	PipelineName string
+
+	// CheckErroredAt is when the resource last failed to check; zero when
+	// CheckError is nil.
+	CheckErroredAt time.Time
+
+	// PinnedVersionID is the versioned resource that jobs must use as
+	// input, regardless of their config; 0 when not pinned.
+	PinnedVersionID int
+
 	Resource
 }
 
@@ -0,0 +1,82 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// SaveSAMLRequest remembers a request made to the team's IdP, along with
+// where to send the user once they're logged in, until it expires.
+func (db *teamDB) SaveSAMLRequest(id string, redirect string, expiresAt time.Time) error {
+	_, err := db.conn.Exec(`
+		DELETE FROM saml_requests
+		WHERE expires_at <= now()
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.conn.Exec(`
+		INSERT INTO saml_requests (id, team_id, redirect, expires_at)
+		SELECT $1, id, $3, $4
+		FROM teams
+		WHERE LOWER(name) = LOWER($2)
+	`, id, db.teamName, redirect, expiresAt)
+	return err
+}
+
+// TakeSAMLRequest returns where to send the user once they're logged in
+// through the request, so long as it was made for the team and hasn't
+// expired. It's forgotten as it's taken, so only one response can ever be
+// accepted for it.
+func (db *teamDB) TakeSAMLRequest(id string) (string, bool, error) {
+	var redirect string
+	err := db.conn.QueryRow(`
+		DELETE FROM saml_requests
+		WHERE id = $1
+		AND team_id = (
+			SELECT id FROM teams WHERE LOWER(name) = LOWER($2)
+		)
+		AND expires_at > now()
+		RETURNING redirect
+	`, id, db.teamName).Scan(&redirect)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", false, nil
+		}
+
+		return "", false, err
+	}
+
+	return redirect, true, nil
+}
+
+// SaveSAMLAssertion remembers that an assertion has been used until it
+// expires, returning false if it already had been. IdPs give every assertion
+// a unique ID, so they're remembered across teams.
+func (db *teamDB) SaveSAMLAssertion(id string, expiresAt time.Time) (bool, error) {
+	_, err := db.conn.Exec(`
+		DELETE FROM saml_assertions
+		WHERE expires_at <= now()
+	`)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = db.conn.Exec(`
+		INSERT INTO saml_assertions (id, expires_at)
+		VALUES ($1, $2)
+	`, id, expiresAt)
+	if err != nil {
+		// it's been used already, possibly through another ATC
+		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code.Name() == "unique_violation" {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
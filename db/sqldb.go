@@ -8,14 +8,16 @@ import (
 type SQLDB struct {
 	conn        Conn
 	lockFactory LockFactory
-	bus         *notificationsBus
+	bus         NotificationsBus
 
 	buildFactory *buildFactory
+
+	replica *ReadReplica
 }
 
 func NewSQL(
 	sqldbConnection Conn,
-	bus *notificationsBus,
+	bus NotificationsBus,
 	lockFactory LockFactory,
 ) *SQLDB {
 	return &SQLDB{
@@ -41,7 +43,7 @@ type scannable interface {
 type conditionNotifier struct {
 	cond func() (bool, error)
 
-	bus     *notificationsBus
+	bus     NotificationsBus
 	channel string
 
 	notified chan bool
@@ -0,0 +1,151 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// CreateAPIToken saves a new token for the team, returning false if the team
+// already has one with the same name.
+func (db *SQLDB) CreateAPIToken(token APIToken) (APIToken, bool, error) {
+	scopes, err := json.Marshal(token.Scopes)
+	if err != nil {
+		return APIToken{}, false, err
+	}
+
+	var expiresAt *time.Time
+	if !token.ExpiresAt.IsZero() {
+		expiresAt = &token.ExpiresAt
+	}
+
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return APIToken{}, false, err
+	}
+
+	defer tx.Rollback()
+
+	var exists bool
+	err = tx.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1
+			FROM api_tokens
+			WHERE team_id = $1
+				AND name = $2
+		)
+	`, token.TeamID, token.Name).Scan(&exists)
+	if err != nil {
+		return APIToken{}, false, err
+	}
+
+	if exists {
+		return APIToken{}, false, nil
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO api_tokens (team_id, name, scopes, expires_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`, token.TeamID, token.Name, string(scopes), expiresAt).Scan(&token.ID, &token.CreatedAt)
+	if err != nil {
+		return APIToken{}, false, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return APIToken{}, false, err
+	}
+
+	return token, true, nil
+}
+
+func (db *SQLDB) GetAPIToken(id int) (APIToken, bool, error) {
+	token, err := scanAPIToken(db.conn.QueryRow(`
+		SELECT `+apiTokenColumns+`
+		FROM api_tokens
+		WHERE id = $1
+	`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return APIToken{}, false, nil
+		}
+
+		return APIToken{}, false, err
+	}
+
+	return token, true, nil
+}
+
+func (db *SQLDB) GetAPITokens(teamID int) ([]APIToken, error) {
+	rows, err := db.conn.Query(`
+		SELECT `+apiTokenColumns+`
+		FROM api_tokens
+		WHERE team_id = $1
+		ORDER BY name ASC
+	`, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	tokens := []APIToken{}
+
+	for rows.Next() {
+		token, err := scanAPIToken(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		tokens = append(tokens, token)
+	}
+
+	return tokens, nil
+}
+
+// RevokeAPIToken deletes the team's token, returning false if it has no
+// such token.
+func (db *SQLDB) RevokeAPIToken(teamID int, id int) (bool, error) {
+	result, err := db.conn.Exec(`
+		DELETE FROM api_tokens
+		WHERE team_id = $1
+			AND id = $2
+	`, teamID, id)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+const apiTokenColumns = "id, team_id, name, scopes, created_at, expires_at"
+
+func scanAPIToken(row scannable) (APIToken, error) {
+	var token APIToken
+	var scopes string
+	var expiresAt pq.NullTime
+
+	err := row.Scan(&token.ID, &token.TeamID, &token.Name, &scopes, &token.CreatedAt, &expiresAt)
+	if err != nil {
+		return APIToken{}, err
+	}
+
+	err = json.Unmarshal([]byte(scopes), &token.Scopes)
+	if err != nil {
+		return APIToken{}, err
+	}
+
+	if expiresAt.Valid {
+		token.ExpiresAt = expiresAt.Time
+	}
+
+	return token, nil
+}
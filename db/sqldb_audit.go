@@ -0,0 +1,60 @@
+package db
+
+import sq "github.com/Masterminds/squirrel"
+
+func (db *SQLDB) SaveAuditEvent(event AuditEvent) error {
+	_, err := db.conn.Exec(`
+		INSERT INTO audit_events (actor, action, resource, body_sha256, status)
+		VALUES ($1, $2, $3, $4, $5)
+	`, event.Actor, event.Action, event.Resource, event.BodySHA256, event.Status)
+	return err
+}
+
+func (db *SQLDB) GetAuditEvents(filter AuditEventFilter) ([]AuditEvent, error) {
+	eventsQuery := sq.Select("id, time, actor, action, resource, body_sha256, status").
+		From("audit_events").
+		OrderBy("id DESC").
+		PlaceholderFormat(sq.Dollar)
+
+	if filter.Actor != "" {
+		eventsQuery = eventsQuery.Where(sq.Eq{"actor": filter.Actor})
+	}
+
+	if filter.Action != "" {
+		eventsQuery = eventsQuery.Where(sq.Eq{"action": filter.Action})
+	}
+
+	if filter.Until != 0 {
+		eventsQuery = eventsQuery.Where(sq.Lt{"id": filter.Until})
+	}
+
+	if filter.Limit != 0 {
+		eventsQuery = eventsQuery.Limit(uint64(filter.Limit))
+	}
+
+	query, args, err := eventsQuery.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	events := []AuditEvent{}
+
+	for rows.Next() {
+		var event AuditEvent
+		err := rows.Scan(&event.ID, &event.Time, &event.Actor, &event.Action, &event.Resource, &event.BodySHA256, &event.Status)
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
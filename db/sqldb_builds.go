@@ -1,11 +1,18 @@
 package db
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/event"
+	"github.com/lib/pq"
 )
 
 func (db *SQLDB) FindJobIDForBuild(buildID int) (int, bool, error) {
@@ -27,25 +34,109 @@ func (db *SQLDB) FindJobIDForBuild(buildID int) (int, bool, error) {
 	return id, true, nil
 }
 
+// GetBuildByID may read from the read replica, falling back on the primary
+// if the build isn't there yet.
 func (db *SQLDB) GetBuildByID(buildID int) (Build, bool, error) {
-	return db.buildFactory.ScanBuild(db.conn.QueryRow(`
-		SELECT `+qualifiedBuildColumns+`
-		FROM builds b
-		LEFT OUTER JOIN jobs j ON b.job_id = j.id
-		LEFT OUTER JOIN pipelines p ON j.pipeline_id = p.id
-		LEFT OUTER JOIN teams t ON b.team_id = t.id
-		WHERE b.id = $1
-	`, buildID))
+	var build Build
+	var found bool
+
+	err := db.readStale(func(conn Conn) (bool, error) {
+		var err error
+		build, found, err = db.buildFactory.ScanBuild(conn.QueryRow(`
+			SELECT `+qualifiedBuildColumns+`
+			FROM builds b
+			LEFT OUTER JOIN jobs j ON b.job_id = j.id
+			LEFT OUTER JOIN pipelines p ON j.pipeline_id = p.id
+			LEFT OUTER JOIN teams t ON b.team_id = t.id
+			WHERE b.id = $1
+		`, buildID))
+		return found, err
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return build, found, nil
 }
 
-func (db *SQLDB) GetPublicBuilds(page Page) ([]Build, Pagination, error) {
+func (db *SQLDB) GetPublicBuilds(ctx context.Context, page Page, filter BuildFilter) ([]Build, Pagination, error) {
 	buildsQuery := sq.Select(qualifiedBuildColumns).From("builds b").
 		LeftJoin("jobs j ON b.job_id = j.id").
 		LeftJoin("pipelines p ON j.pipeline_id = p.id").
 		LeftJoin("teams t ON b.team_id = t.id").
 		Where(sq.Eq{"p.public": true})
 
-	return getBuildsWithPagination(buildsQuery, page, db.conn, db.buildFactory)
+	buildsQuery = applyBuildFilter(buildsQuery, filter)
+
+	var builds []Build
+	var pagination Pagination
+
+	// a page that's a little behind is no different to one loaded a little
+	// earlier
+	err := db.readStale(func(conn Conn) (bool, error) {
+		var err error
+		builds, pagination, err = getBuildsWithPagination(ctx, buildsQuery, page, conn, db.buildFactory)
+		return true, err
+	})
+	if err != nil {
+		return nil, Pagination{}, err
+	}
+
+	return builds, pagination, nil
+}
+
+// GetBuilds may read from the read replica, falling back on the primary if
+// any of the builds aren't there yet.
+func (db *SQLDB) GetBuilds(ctx context.Context, buildIDs []int) ([]Build, error) {
+	if len(buildIDs) == 0 {
+		return []Build{}, nil
+	}
+
+	distinctIDs := map[int]bool{}
+	for _, id := range buildIDs {
+		distinctIDs[id] = true
+	}
+
+	query, args, err := sq.Select(qualifiedBuildColumns).From("builds b").
+		LeftJoin("jobs j ON b.job_id = j.id").
+		LeftJoin("pipelines p ON j.pipeline_id = p.id").
+		LeftJoin("teams t ON b.team_id = t.id").
+		Where(sq.Eq{"b.id": buildIDs}).
+		OrderBy("b.id DESC").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var bs []Build
+
+	err = db.readStale(func(conn Conn) (bool, error) {
+		rows, err := conn.QueryContext(ctx, query, args...)
+		if err != nil {
+			return false, err
+		}
+
+		defer rows.Close()
+
+		bs = []Build{}
+
+		for rows.Next() {
+			build, _, err := db.buildFactory.ScanBuild(rows)
+			if err != nil {
+				return false, err
+			}
+
+			bs = append(bs, build)
+		}
+
+		return len(bs) == len(distinctIDs), rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return bs, nil
 }
 
 func (db *SQLDB) GetAllStartedBuilds() ([]Build, error) {
@@ -77,6 +168,135 @@ func (db *SQLDB) GetAllStartedBuilds() ([]Build, error) {
 	return bs, nil
 }
 
+// OneOffBuildSpec is everything a one-off build is created with.
+type OneOffBuildSpec struct {
+	Plan atc.Plan
+
+	Labels   map[string]string
+	Priority int
+	Timeout  time.Duration
+
+	// DependsOn holds the build back until each of these builds has
+	// succeeded.
+	DependsOn []int
+}
+
+// CreateOneOffBuild creates a pending build for the team along with
+// everything in the spec, all in one transaction, so that there's never a
+// build without its plan. The plan is left pending: builds with dependencies
+// are started once they've succeeded, and builds without are for the caller
+// to start after claiming the plan with ClaimPendingPlan. Should the caller
+// never get to, e.g. because the ATC went away, the build is started along
+// with those awaiting dependencies instead.
+func (db *SQLDB) CreateOneOffBuild(ctx context.Context, teamName string, spec OneOffBuildSpec) (Build, error) {
+	plan, err := json.Marshal(spec.Plan)
+	if err != nil {
+		return nil, err
+	}
+
+	encryptedPlan, err := db.conn.EncryptionStrategy().Encrypt(plan)
+	if err != nil {
+		return nil, err
+	}
+
+	var labels interface{}
+	if len(spec.Labels) > 0 {
+		payload, err := json.Marshal(spec.Labels)
+		if err != nil {
+			return nil, err
+		}
+
+		labels = string(payload)
+	}
+
+	var timeout interface{}
+	if spec.Timeout != 0 {
+		timeout = int(spec.Timeout.Seconds())
+	}
+
+	// should the request go away before this is committed, the build is
+	// rolled back rather than left for nobody to see
+	tx, err := db.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	build, found, err := db.buildFactory.ScanBuild(tx.QueryRow(`
+		INSERT INTO builds (name, team_id, status, labels, priority, timeout, pending_plan)
+		SELECT nextval('one_off_name'), t.id, 'pending', $2, $3, $4, $5
+		FROM teams t WHERE LOWER(t.name) = LOWER($1)
+		RETURNING `+buildColumns+`, null, null, null,
+		(
+			SELECT name FROM teams WHERE LOWER(name) = LOWER($1)
+		)
+	`, teamName, labels, spec.Priority, timeout, encryptedPlan))
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return nil, ErrTeamNotFound
+	}
+
+	err = createBuildEventSeq(tx, build.ID())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dependency := range spec.DependsOn {
+		_, err := tx.Exec(`
+			INSERT INTO build_dependencies (build_id, depends_on_build_id)
+			VALUES ($1, $2)
+		`, build.ID(), dependency)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return build, nil
+}
+
+// GetBuildsAwaitingDependencies returns the builds that are being held back
+// until the builds they depend on have finished, along with any one-off
+// builds whose plans were never claimed by whoever created them.
+func (db *SQLDB) GetBuildsAwaitingDependencies() ([]Build, error) {
+	rows, err := db.conn.Query(`
+		SELECT ` + qualifiedBuildColumns + `
+		FROM builds b
+		LEFT OUTER JOIN jobs j ON b.job_id = j.id
+		LEFT OUTER JOIN pipelines p ON j.pipeline_id = p.id
+		LEFT OUTER JOIN teams t ON b.team_id = t.id
+		WHERE b.status = 'pending'
+			AND b.pending_plan IS NOT NULL
+		ORDER BY b.id ASC
+	`)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	bs := []Build{}
+
+	for rows.Next() {
+		build, _, err := db.buildFactory.ScanBuild(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		bs = append(bs, build)
+	}
+
+	return bs, nil
+}
+
 func (db *SQLDB) DeleteBuildEventsByBuildIDs(buildIDs []int) error {
 	if len(buildIDs) == 0 {
 		return nil
@@ -107,6 +327,14 @@ func (db *SQLDB) DeleteBuildEventsByBuildIDs(buildIDs []int) error {
 		return err
 	}
 
+	_, err = tx.Exec(`
+		DELETE FROM build_event_anchors
+		WHERE build_id IN (`+strings.Join(indexStrings, ",")+`)
+	`, interfaceBuildIDs...)
+	if err != nil {
+		return err
+	}
+
 	_, err = tx.Exec(`
 		UPDATE builds
 		SET reap_time = now()
@@ -120,6 +348,226 @@ func (db *SQLDB) DeleteBuildEventsByBuildIDs(buildIDs []int) error {
 	return err
 }
 
+// DeleteBuildEventsBefore deletes the events of at most limit builds that
+// finished before the time, oldest first, and returns how many builds it
+// reaped. The builds themselves are left in place.
+func (db *SQLDB) DeleteBuildEventsBefore(endedBefore time.Time, limit int) (int, error) {
+	rows, err := db.conn.Query(`
+		SELECT id
+		FROM builds
+		WHERE completed = true
+		AND reap_time IS NULL
+		AND end_time < $1
+		ORDER BY id ASC
+		LIMIT $2
+	`, endedBefore, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	defer rows.Close()
+
+	buildIDs := []int{}
+	for rows.Next() {
+		var id int
+		err := rows.Scan(&id)
+		if err != nil {
+			return 0, err
+		}
+
+		buildIDs = append(buildIDs, id)
+	}
+
+	err = db.DeleteBuildEventsByBuildIDs(buildIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(buildIDs), nil
+}
+
+// GetUnreapedBuildsEndedBefore returns finished builds whose events are still
+// in the database, oldest first.
+func (db *SQLDB) GetUnreapedBuildsEndedBefore(endedBefore time.Time, limit int) ([]Build, error) {
+	rows, err := db.conn.Query(`
+		SELECT `+qualifiedBuildColumns+`
+		FROM builds b
+		LEFT OUTER JOIN jobs j ON b.job_id = j.id
+		LEFT OUTER JOIN pipelines p ON j.pipeline_id = p.id
+		LEFT OUTER JOIN teams t ON b.team_id = t.id
+		WHERE b.completed = true
+		AND b.reap_time IS NULL
+		AND b.end_time < $1
+		ORDER BY b.id ASC
+		LIMIT $2
+	`, endedBefore, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	bs := []Build{}
+
+	for rows.Next() {
+		build, _, err := db.buildFactory.ScanBuild(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		bs = append(bs, build)
+	}
+
+	return bs, rows.Err()
+}
+
+// GetLastBuildReapTime returns when the events of a build were last deleted.
+func (db *SQLDB) GetLastBuildReapTime(ctx context.Context) (time.Time, bool, error) {
+	var reapTime pq.NullTime
+	err := db.conn.QueryRowContext(ctx, `
+		SELECT MAX(reap_time)
+		FROM builds
+	`).Scan(&reapTime)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	return reapTime.Time, reapTime.Valid, nil
+}
+
+// GetBuildEventsFrom returns at most limit of the build's events, starting
+// from the offset'th, so that long builds can be read a batch at a time.
+// Builds that have been reaped or don't exist have no events.
+//
+// The events may be read from the read replica. Those of a running build are
+// read from the primary should the replica not have a full batch of them, as
+// it may not have caught up with the latest.
+func (db *SQLDB) GetBuildEventsFrom(ctx context.Context, buildID int, offset uint, limit int) ([]event.Envelope, error) {
+	var events []event.Envelope
+
+	err := db.readStale(func(conn Conn) (bool, error) {
+		var pipelineID sql.NullInt64
+		var running bool
+		err := conn.QueryRowContext(ctx, `
+			SELECT j.pipeline_id, b.status IN ('pending', 'started')
+			FROM builds b
+			LEFT JOIN jobs j ON b.job_id = j.id
+			WHERE b.id = $1
+		`, buildID).Scan(&pipelineID, &running)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				events = []event.Envelope{}
+				return false, nil
+			}
+
+			return false, err
+		}
+
+		rows, err := conn.QueryContext(ctx, `
+			SELECT `+buildEventColumns+`
+			FROM `+buildEventsTable(int(pipelineID.Int64))+`
+			WHERE build_id = $1
+			ORDER BY event_id ASC
+			OFFSET $2
+			LIMIT $3
+		`, buildID, offset, limit)
+		if err != nil {
+			return false, err
+		}
+
+		defer rows.Close()
+
+		events = []event.Envelope{}
+		for rows.Next() {
+			_, ev, err := scanBuildEvent(rows)
+			if err != nil {
+				return false, err
+			}
+
+			events = append(events, ev)
+		}
+
+		return !running || len(events) == limit, rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// GetGlobalMaxInFlight returns how many builds may be running at once across
+// every pipeline and team; 0 means there is no limit.
+func (db *SQLDB) GetGlobalMaxInFlight(ctx context.Context) (int, error) {
+	var maxInFlight int
+	err := db.conn.QueryRowContext(ctx, `
+		SELECT COALESCE(MAX(max_in_flight), 0)
+		FROM build_concurrency
+	`).Scan(&maxInFlight)
+	if err != nil {
+		return 0, err
+	}
+
+	return maxInFlight, nil
+}
+
+func (db *SQLDB) SetGlobalMaxInFlight(ctx context.Context, maxInFlight int) error {
+	tx, err := db.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE build_concurrency
+		SET max_in_flight = $1
+	`, maxInFlight)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		_, err = tx.Exec(`
+			INSERT INTO build_concurrency (max_in_flight)
+			VALUES ($1)
+		`, maxInFlight)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GlobalMaxInFlightReached counts builds that have been scheduled but not
+// yet started, as they are about to be.
+func (db *SQLDB) GlobalMaxInFlightReached() (bool, error) {
+	var reached bool
+	err := db.conn.QueryRow(`
+		SELECT c.max_in_flight > 0 AND (
+			SELECT COUNT(1)
+			FROM builds
+			WHERE status = 'started'
+			OR (status = 'pending' AND scheduled = true)
+		) >= c.max_in_flight
+		FROM (
+			SELECT COALESCE(MAX(max_in_flight), 0) AS max_in_flight
+			FROM build_concurrency
+		) c
+	`).Scan(&reached)
+	if err != nil {
+		return false, err
+	}
+
+	return reached, nil
+}
+
 func (db *SQLDB) FindLatestSuccessfulBuildsPerJob() (map[int]int, error) {
 	rows, err := db.conn.Query(
 		`SELECT max(id), job_id
@@ -152,7 +600,29 @@ func (db *SQLDB) FindLatestSuccessfulBuildsPerJob() (map[int]int, error) {
 	return latestSuccessfulBuildsPerJob, nil
 }
 
-func getBuildsWithPagination(buildsQuery sq.SelectBuilder, page Page, dbConn Conn, buildFactory *buildFactory) ([]Build, Pagination, error) {
+func applyBuildFilter(buildsQuery sq.SelectBuilder, filter BuildFilter) sq.SelectBuilder {
+	keys := make([]string, 0, len(filter.Labels))
+	for key := range filter.Labels {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		buildsQuery = buildsQuery.Where(sq.Expr("b.labels::json->>? = ?", key, filter.Labels[key]))
+	}
+
+	if len(filter.HiddenPipelineIDs) > 0 {
+		buildsQuery = buildsQuery.Where(sq.Or{
+			sq.Eq{"j.pipeline_id": nil},
+			sq.NotEq{"j.pipeline_id": filter.HiddenPipelineIDs},
+		})
+	}
+
+	return buildsQuery
+}
+
+func getBuildsWithPagination(ctx context.Context, buildsQuery sq.SelectBuilder, page Page, dbConn Conn, buildFactory *buildFactory) ([]Build, Pagination, error) {
 	var rows *sql.Rows
 	var err error
 
@@ -170,7 +640,7 @@ func getBuildsWithPagination(buildsQuery sq.SelectBuilder, page Page, dbConn Con
 		return nil, Pagination{}, err
 	}
 
-	rows, err = dbConn.Query(query, args...)
+	rows, err = dbConn.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, Pagination{}, err
 	}
@@ -203,7 +673,7 @@ func getBuildsWithPagination(buildsQuery sq.SelectBuilder, page Page, dbConn Con
 		return nil, Pagination{}, err
 	}
 
-	err = dbConn.QueryRow(maxMinBuildIDQuery).Scan(&maxID, &minID)
+	err = dbConn.QueryRowContext(ctx, maxMinBuildIDQuery).Scan(&maxID, &minID)
 	if err != nil {
 		return nil, Pagination{}, err
 	}
@@ -6,20 +6,105 @@ import (
 	"github.com/lib/pq"
 )
 
+// NotificationsBus lets one ATC wake up whoever is waiting on a channel, such
+// as the event streams of a build, including those served by other ATCs.
+type NotificationsBus interface {
+	Listen(channel string) (chan bool, error)
+	Notify(channel string) error
+	Unlisten(channel string, notify chan bool) error
+
+	ListenForPayloads(channel string) (*PayloadSink, error)
+	UnlistenForPayloads(channel string, sink *PayloadSink) error
+}
+
+// maxQueuedPayloads bounds how far a payload listener can fall behind before
+// its queue is dropped and it is told to catch up some other way.
+const maxQueuedPayloads = 1000
+
+// PayloadSink receives the payloads sent on a channel, in the order they were
+// sent.
+type PayloadSink struct {
+	ready chan struct{}
+
+	lock     sync.Mutex
+	payloads []string
+	missed   bool
+}
+
+func newPayloadSink() *PayloadSink {
+	return &PayloadSink{
+		ready: make(chan struct{}, 1),
+	}
+}
+
+// Ready is signalled whenever there are payloads to take.
+func (sink *PayloadSink) Ready() <-chan struct{} {
+	return sink.ready
+}
+
+// Take returns the payloads received since the last call. If missed is true
+// some payloads may have been lost, either because the connection dropped or
+// because too many queued up, and the listener should check for anything that
+// changed in the meantime.
+func (sink *PayloadSink) Take() (payloads []string, missed bool) {
+	sink.lock.Lock()
+	defer sink.lock.Unlock()
+
+	payloads, missed = sink.payloads, sink.missed
+
+	sink.payloads = nil
+	sink.missed = false
+
+	return payloads, missed
+}
+
+func (sink *PayloadSink) deliver(payload string) {
+	sink.lock.Lock()
+
+	if len(sink.payloads) >= maxQueuedPayloads {
+		sink.payloads = nil
+		sink.missed = true
+	} else if !sink.missed {
+		sink.payloads = append(sink.payloads, payload)
+	}
+
+	sink.lock.Unlock()
+
+	sink.signal()
+}
+
+func (sink *PayloadSink) miss() {
+	sink.lock.Lock()
+	sink.payloads = nil
+	sink.missed = true
+	sink.lock.Unlock()
+
+	sink.signal()
+}
+
+func (sink *PayloadSink) signal() {
+	select {
+	case sink.ready <- struct{}{}:
+	default:
+	}
+}
+
 type notificationsBus struct {
 	listener *pq.Listener
 	conn     Conn
 
 	notifications  map[string]map[chan bool]struct{}
+	payloadSinks   map[string]map[*PayloadSink]struct{}
 	notificationsL sync.Mutex
 }
 
-func NewNotificationsBus(listener *pq.Listener, conn Conn) *notificationsBus {
+func NewNotificationsBus(listener *pq.Listener, conn Conn) NotificationsBus {
 	bus := &notificationsBus{
 		listener: listener,
 		conn:     conn,
 
 		notifications: make(map[string]map[chan bool]struct{}),
+		payloadSinks:  make(map[string]map[*PayloadSink]struct{}),
 	}
 
 	go bus.dispatchNotifications()
@@ -29,9 +114,8 @@ func NewNotificationsBus(listener *pq.Listener, conn Conn) *notificationsBus {
 
 func (bus *notificationsBus) Listen(channel string) (chan bool, error) {
 	bus.notificationsL.Lock()
-	firstListen := len(bus.notifications[channel]) == 0
 
-	if firstListen {
+	if bus.listenerCount(channel) == 0 {
 		err := bus.listener.Listen(channel)
 		if err != nil {
 			bus.notificationsL.Unlock()
@@ -55,6 +139,51 @@ func (bus *notificationsBus) Listen(channel string) (chan bool, error) {
 	return notify, nil
 }
 
+func (bus *notificationsBus) ListenForPayloads(channel string) (*PayloadSink, error) {
+	bus.notificationsL.Lock()
+	defer bus.notificationsL.Unlock()
+
+	if bus.listenerCount(channel) == 0 {
+		err := bus.listener.Listen(channel)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sink := newPayloadSink()
+
+	sinks, found := bus.payloadSinks[channel]
+	if !found {
+		sinks = map[*PayloadSink]struct{}{}
+		bus.payloadSinks[channel] = sinks
+	}
+
+	sinks[sink] = struct{}{}
+
+	return sink, nil
+}
+
+func (bus *notificationsBus) UnlistenForPayloads(channel string, sink *PayloadSink) error {
+	bus.notificationsL.Lock()
+	delete(bus.payloadSinks[channel], sink)
+	if len(bus.payloadSinks[channel]) == 0 {
+		delete(bus.payloadSinks, channel)
+	}
+	lastSink := bus.listenerCount(channel) == 0
+	bus.notificationsL.Unlock()
+
+	if lastSink {
+		return bus.listener.Unlisten(channel)
+	}
+
+	return nil
+}
+
+// listenerCount must be called with notificationsL held.
+func (bus *notificationsBus) listenerCount(channel string) int {
+	return len(bus.notifications[channel]) + len(bus.payloadSinks[channel])
+}
+
 func (bus *notificationsBus) Notify(channel string) error {
 	_, err := bus.conn.Exec("NOTIFY " + channel)
 	return err
@@ -63,7 +192,10 @@ func (bus *notificationsBus) Notify(channel string) error {
 func (bus *notificationsBus) Unlisten(channel string, notify chan bool) error {
 	bus.notificationsL.Lock()
 	delete(bus.notifications[channel], notify)
-	lastSink := len(bus.notifications[channel]) == 0
+	if len(bus.notifications[channel]) == 0 {
+		delete(bus.notifications, channel)
+	}
+	lastSink := bus.listenerCount(channel) == 0
 	bus.notificationsL.Unlock()
 
 	if lastSink {
@@ -95,6 +227,10 @@ func (bus *notificationsBus) dispatchNotifications() {
 					// already had notification queued up; no need to handle it twice
 				}
 			}
+
+			for sink := range bus.payloadSinks[notification.Channel] {
+				sink.deliver(notification.Extra)
+			}
 		} else {
 			// alert all listeners of connection break so they can check for things
 			// they may have missed
@@ -110,6 +246,12 @@ func (bus *notificationsBus) dispatchNotifications() {
 					}
 				}
 			}
+
+			for _, sinks := range bus.payloadSinks {
+				for sink := range sinks {
+					sink.miss()
+				}
+			}
 		}
 
 		bus.notificationsL.Unlock()
@@ -42,7 +42,7 @@ func (db *SQLDB) FindJobContainersFromUnsuccessfulBuilds() ([]SavedContainer, er
 	rows, err := db.conn.Query(
 		`SELECT ` + containerColumns + `
 		FROM containers c ` + containerJoins + `
-		WHERE (b.status = 'failed' OR b.status = 'errored')
+		WHERE (b.status = 'failed' OR b.status = 'errored' OR b.status = 'timed_out')
 		AND b.job_id is not null`)
 
 	if err != nil {
@@ -55,6 +55,35 @@ func (db *SQLDB) FindJobContainersFromUnsuccessfulBuilds() ([]SavedContainer, er
 	return scanRows(rows)
 }
 
+// GetContainerHandlesForBuild returns the handles of every container the
+// build has run steps in that hasn't been reaped.
+func (db *SQLDB) GetContainerHandlesForBuild(buildID int) ([]string, error) {
+	rows, err := db.conn.Query(`
+		SELECT handle
+		FROM containers
+		WHERE build_id = $1
+		ORDER BY id
+	`, buildID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	handles := []string{}
+	for rows.Next() {
+		var handle string
+		err := rows.Scan(&handle)
+		if err != nil {
+			return nil, err
+		}
+
+		handles = append(handles, handle)
+	}
+
+	return handles, rows.Err()
+}
+
 func (db *SQLDB) FindContainerByIdentifier(id ContainerIdentifier) (SavedContainer, bool, error) {
 	err := db.deleteExpiredContainers()
 	if err != nil {
@@ -324,6 +353,11 @@ func (db *SQLDB) CreateContainer(
 		return SavedContainer{}, err
 	}
 
+	err = countGC(tx, container.WorkerName, gcContainersCreated, 1)
+	if err != nil {
+		return SavedContainer{}, err
+	}
+
 	newContainer, err := scanContainer(tx.QueryRow(`
 		SELECT `+containerColumns+`
 	  FROM containers c `+containerJoins+`
@@ -389,26 +423,15 @@ func (db *SQLDB) UpdateExpiresAtOnContainer(handle string, ttl time.Duration) er
 	return tx.Commit()
 }
 
+// ReapContainer deletes a container the worker no longer has, counting it
+// as orphaned.
 func (db *SQLDB) ReapContainer(handle string) error {
-	rows, err := db.conn.Exec(`
+	// reaping 0 containers is fine; it may have already expired
+	_, err := deleteCountingGC(db.conn, gcContainersOrphaned, `
 		DELETE FROM containers WHERE handle = $1
+		RETURNING worker_name
 	`, handle)
-	if err != nil {
-		return err
-	}
-
-	affected, err := rows.RowsAffected()
-	if err != nil {
-		return err
-	}
-
-	// just to be explicit: reaping 0 containers is fine;
-	// it may have already expired
-	if affected == 0 {
-		return nil
-	}
-
-	return nil
+	return err
 }
 
 func (db *SQLDB) DeleteContainer(handle string) error {
@@ -419,8 +442,9 @@ func (db *SQLDB) DeleteContainer(handle string) error {
 
 	defer tx.Rollback()
 
-	_, err = tx.Exec(`
+	_, err = deleteCountingGC(tx, gcContainersDestroyed, `
 		DELETE FROM containers WHERE handle = $1
+		RETURNING worker_name
 	`, handle)
 	if err != nil {
 		return err
@@ -600,10 +624,11 @@ func scanContainer(row scannable) (SavedContainer, error) {
 }
 
 func (db *SQLDB) deleteExpiredContainers() error {
-	_, err := db.conn.Exec(`
+	_, err := deleteCountingGC(db.conn, gcContainersDestroyed, `
 		DELETE FROM containers
 		WHERE expires_at IS NOT NULL
 		AND expires_at < NOW()
+		RETURNING worker_name
 	`)
 	if err != nil {
 		return err
@@ -0,0 +1,107 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+// GetJobDurationPercentile returns how long the given percent of the job's
+// builds that succeeded or failed within the window took, along with how
+// many of them there were. Builds that errored or were aborted are left out
+// as they tend not to have run to completion.
+func (db *SQLDB) GetJobDurationPercentile(pipelineID int, jobName string, percentile int, window time.Duration) (time.Duration, int, error) {
+	rows, err := db.conn.Query(`
+		SELECT duration, total
+		FROM (
+			SELECT
+				EXTRACT(EPOCH FROM b.end_time - b.start_time) AS duration,
+				ROW_NUMBER() OVER (ORDER BY b.end_time - b.start_time) AS position,
+				COUNT(*) OVER () AS total
+			FROM builds b
+			INNER JOIN jobs j ON b.job_id = j.id
+			WHERE j.pipeline_id = $1
+				AND j.name = $2
+				AND b.status IN ('succeeded', 'failed')
+				AND b.end_time > now() - ($3 || ' SECONDS')::INTERVAL
+		) durations
+		WHERE position = CEIL(total * $4::numeric / 100)
+	`, pipelineID, jobName, window.Seconds(), percentile)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	defer rows.Close()
+
+	var duration time.Duration
+	var total int
+
+	for rows.Next() {
+		var seconds float64
+		err := rows.Scan(&seconds, &total)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		duration = secondsDuration(seconds)
+	}
+
+	return duration, total, rows.Err()
+}
+
+// GetDurationAlerts returns the alerts raised for the team's builds, most
+// recent first.
+func (db *SQLDB) GetDurationAlerts(ctx context.Context, teamName string) ([]DurationAlert, error) {
+	rows, err := db.conn.QueryContext(ctx, `
+		SELECT
+			b.id,
+			b.name,
+			t.name,
+			p.name,
+			j.name,
+			a.percentile,
+			a.threshold_seconds,
+			a.elapsed_seconds,
+			a.created_at
+		FROM build_duration_alerts a
+		INNER JOIN builds b ON a.build_id = b.id
+		INNER JOIN jobs j ON b.job_id = j.id
+		INNER JOIN pipelines p ON j.pipeline_id = p.id
+		INNER JOIN teams t ON b.team_id = t.id
+		WHERE t.name = $1
+		ORDER BY a.created_at DESC, a.id DESC
+	`, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	alerts := []DurationAlert{}
+
+	for rows.Next() {
+		var alert DurationAlert
+		var threshold, elapsed float64
+
+		err := rows.Scan(
+			&alert.BuildID,
+			&alert.BuildName,
+			&alert.TeamName,
+			&alert.PipelineName,
+			&alert.JobName,
+			&alert.Percentile,
+			&threshold,
+			&elapsed,
+			&alert.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		alert.Threshold = secondsDuration(threshold)
+		alert.Elapsed = secondsDuration(elapsed)
+
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, rows.Err()
+}
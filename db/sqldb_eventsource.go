@@ -1,18 +1,23 @@
 package db
 
 import (
+	"database/sql"
 	"encoding/json"
+	"fmt"
 	"sync"
 
 	"github.com/concourse/atc"
 	"github.com/concourse/atc/event"
+	"github.com/lib/pq"
 )
 
 func newSQLDBBuildEventSource(
 	buildID int,
 	table string,
 	conn Conn,
-	notifier Notifier,
+	bus NotificationsBus,
+	channel string,
+	sink *PayloadSink,
 	from uint,
 ) *sqldbBuildEventSource {
 	wg := new(sync.WaitGroup)
@@ -23,7 +28,9 @@ func newSQLDBBuildEventSource(
 
 		conn: conn,
 
-		notifier: notifier,
+		bus:     bus,
+		channel: channel,
+		sink:    sink,
 
 		events: make(chan event.Envelope, 2000),
 		stop:   make(chan struct{}),
@@ -40,13 +47,21 @@ type sqldbBuildEventSource struct {
 	buildID int
 	table   string
 
-	conn     Conn
-	notifier Notifier
+	conn Conn
+
+	bus     NotificationsBus
+	channel string
+	sink    *PayloadSink
 
 	events chan event.Envelope
 	stop   chan struct{}
 	err    error
 	wg     *sync.WaitGroup
+
+	// the id the next event is expected to have, once it's known; events
+	// pushed with this id can be emitted without going back to the table
+	nextEventID      int64
+	nextEventIDKnown bool
 }
 
 func (source *sqldbBuildEventSource) Next() (event.Envelope, error) {
@@ -70,23 +85,45 @@ func (source *sqldbBuildEventSource) Close() error {
 
 	source.wg.Wait()
 
-	return source.notifier.Close()
+	return source.bus.UnlistenForPayloads(source.channel, source.sink)
 }
 
 func (source *sqldbBuildEventSource) collectEvents(cursor uint) {
 	defer source.wg.Done()
 
-	var batchSize = cap(source.events)
-
 	for {
 		select {
 		case <-source.stop:
-			source.err = ErrBuildEventStreamClosed
-			close(source.events)
+			source.end(ErrBuildEventStreamClosed)
 			return
 		default:
 		}
 
+		completed, err := source.readEvents(&cursor)
+		if err != nil {
+			source.end(err)
+			return
+		}
+
+		if completed {
+			source.end(ErrEndOfBuildEventStream)
+			return
+		}
+
+		err = source.awaitEvents(&cursor)
+		if err != nil {
+			source.end(err)
+			return
+		}
+	}
+}
+
+// readEvents emits everything in the table from the cursor on, returning
+// whether the build had completed before it started reading.
+func (source *sqldbBuildEventSource) readEvents(cursor *uint) (bool, error) {
+	var batchSize = cap(source.events)
+
+	for {
 		completed := false
 
 		err := source.conn.QueryRow(`
@@ -95,23 +132,19 @@ func (source *sqldbBuildEventSource) collectEvents(cursor uint) {
 			WHERE builds.id = $1
 		`, source.buildID).Scan(&completed)
 		if err != nil {
-			source.err = err
-			close(source.events)
-			return
+			return false, err
 		}
 
 		rows, err := source.conn.Query(`
-			SELECT type, version, payload
+			SELECT `+buildEventColumns+`
 			FROM `+source.table+`
 			WHERE build_id = $1
 			ORDER BY event_id ASC
 			OFFSET $2
 			LIMIT $3
-		`, source.buildID, cursor, batchSize)
+		`, source.buildID, *cursor, batchSize)
 		if err != nil {
-			source.err = err
-			close(source.events)
-			return
+			return false, err
 		}
 
 		rowsReturned := 0
@@ -119,35 +152,28 @@ func (source *sqldbBuildEventSource) collectEvents(cursor uint) {
 		for rows.Next() {
 			rowsReturned++
 
-			cursor++
-
-			var t, v, p string
-			err := rows.Scan(&t, &v, &p)
+			id, ev, err := scanBuildEvent(rows)
 			if err != nil {
 				rows.Close()
-
-				source.err = err
-				close(source.events)
-				return
+				return false, err
 			}
 
-			data := json.RawMessage(p)
-
-			ev := event.Envelope{
-				Data:    &data,
-				Event:   atc.EventType(t),
-				Version: atc.EventVersion(v),
+			err = source.emit(ev)
+			if err != nil {
+				rows.Close()
+				return false, err
 			}
 
-			select {
-			case source.events <- ev:
-			case <-source.stop:
-				rows.Close()
+			*cursor++
 
-				source.err = ErrBuildEventStreamClosed
-				close(source.events)
-				return
-			}
+			source.nextEventID = id + 1
+			source.nextEventIDKnown = true
+		}
+
+		if rowsReturned == 0 && *cursor == 0 {
+			// event ids start from 0; see createBuildEventSeq
+			source.nextEventID = 0
+			source.nextEventIDKnown = true
 		}
 
 		if rowsReturned == batchSize {
@@ -155,18 +181,113 @@ func (source *sqldbBuildEventSource) collectEvents(cursor uint) {
 			continue
 		}
 
-		if completed {
-			source.err = ErrEndOfBuildEventStream
-			close(source.events)
-			return
-		}
+		return completed, nil
+	}
+}
 
+// awaitEvents emits events as they're pushed, returning once the table needs
+// to be read again: because something was missed, an event arrived without
+// its data, or the build may have completed.
+func (source *sqldbBuildEventSource) awaitEvents(cursor *uint) error {
+	for {
 		select {
-		case <-source.notifier.Notify():
+		case <-source.sink.Ready():
 		case <-source.stop:
-			source.err = ErrBuildEventStreamClosed
-			close(source.events)
-			return
+			return ErrBuildEventStreamClosed
+		}
+
+		payloads, missed := source.sink.Take()
+		if missed {
+			return nil
+		}
+
+		for _, payload := range payloads {
+			var notification eventNotification
+			if payload == "" || json.Unmarshal([]byte(payload), &notification) != nil {
+				return nil
+			}
+
+			if source.nextEventIDKnown && notification.ID < source.nextEventID {
+				// already read from the table
+				continue
+			}
+
+			if !source.nextEventIDKnown || notification.ID != source.nextEventID || notification.Data == nil {
+				return nil
+			}
+
+			err := source.emit(event.Envelope{
+				Data:    notification.Data,
+				Event:   notification.Type,
+				Version: notification.Version,
+				Time:    notification.Time,
+			})
+			if err != nil {
+				return err
+			}
+
+			*cursor++
+
+			source.nextEventID++
+
+			if notification.Type == event.EventTypeStatus {
+				// the build may have just finished
+				return nil
+			}
 		}
 	}
 }
+
+func (source *sqldbBuildEventSource) emit(ev event.Envelope) error {
+	select {
+	case source.events <- ev:
+		return nil
+	case <-source.stop:
+		return ErrBuildEventStreamClosed
+	}
+}
+
+func (source *sqldbBuildEventSource) end(err error) {
+	source.err = err
+	close(source.events)
+}
+
+const buildEventColumns = "event_id, type, version, payload, compressed_payload, time"
+
+// buildEventsTable is where the events of builds of the given pipeline are
+// kept; one-off builds have a pipeline ID of 0.
+func buildEventsTable(pipelineID int) string {
+	if pipelineID == 0 {
+		return "build_events"
+	}
+
+	return fmt.Sprintf("pipeline_build_events_%d", pipelineID)
+}
+
+// scanBuildEvent scans a row of buildEventColumns, returning the event's id
+// along with it.
+func scanBuildEvent(row scannable) (int64, event.Envelope, error) {
+	var id int64
+	var t, v string
+	var p sql.NullString
+	var compressed []byte
+	var savedAt pq.NullTime
+	err := row.Scan(&id, &t, &v, &p, &compressed, &savedAt)
+	if err != nil {
+		return 0, event.Envelope{}, err
+	}
+
+	payload, err := eventPayload(p, compressed)
+	if err != nil {
+		return 0, event.Envelope{}, err
+	}
+
+	data := json.RawMessage(payload)
+
+	return id, event.Envelope{
+		Data:    &data,
+		Event:   atc.EventType(t),
+		Version: atc.EventVersion(v),
+		Time:    savedAt.Time,
+	}, nil
+}
@@ -0,0 +1,83 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+)
+
+// schedulingLeadership is held by the ATC running the scheduler and radar.
+const schedulingLeadership = "scheduling"
+
+// AcquireLeadership makes the holder the leader until the ttl elapses, so
+// long as nobody else is. Leaders call it again before then to stay on, and
+// are replaced by whoever calls it next once they stop.
+func (db *SQLDB) AcquireLeadership(holder string, ttl time.Duration) (bool, error) {
+	result, err := db.conn.Exec(`
+		UPDATE leaders
+		SET holder = $2, expires_at = now() + ($3 || ' SECONDS')::INTERVAL
+		WHERE name = $1
+			AND (holder = $2 OR expires_at < now())
+	`, schedulingLeadership, holder, ttl.Seconds())
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	if rows == 1 {
+		return true, nil
+	}
+
+	result, err = db.conn.Exec(`
+		INSERT INTO leaders (name, holder, expires_at)
+		SELECT $1, $2, now() + ($3 || ' SECONDS')::INTERVAL
+		WHERE NOT EXISTS (
+			SELECT 1 FROM leaders WHERE name = $1
+		)
+	`, schedulingLeadership, holder, ttl.Seconds())
+	if err != nil {
+		// someone else got there first
+		return false, swallowUniqueViolation(err)
+	}
+
+	rows, err = result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows == 1, nil
+}
+
+// ReleaseLeadership steps the holder down, if it's the leader, so that
+// another ATC can take over without waiting for its leadership to expire.
+func (db *SQLDB) ReleaseLeadership(holder string) error {
+	_, err := db.conn.Exec(`
+		DELETE FROM leaders
+		WHERE name = $1
+			AND holder = $2
+	`, schedulingLeadership, holder)
+	return err
+}
+
+// GetLeader returns who the leader is, if anyone.
+func (db *SQLDB) GetLeader() (string, bool, error) {
+	var holder string
+	err := db.conn.QueryRow(`
+		SELECT holder
+		FROM leaders
+		WHERE name = $1
+			AND expires_at >= now()
+	`, schedulingLeadership).Scan(&holder)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", false, nil
+		}
+
+		return "", false, err
+	}
+
+	return holder, true, nil
+}
@@ -0,0 +1,142 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+func (db *SQLDB) GetMaintenance(ctx context.Context) (Maintenance, error) {
+	rows, err := db.conn.QueryContext(ctx, `
+		SELECT name, schedule, duration_seconds, starts_at, ends_at
+		FROM maintenance_windows
+		ORDER BY name ASC
+	`)
+	if err != nil {
+		return Maintenance{}, err
+	}
+
+	defer rows.Close()
+
+	maintenance := Maintenance{
+		Windows: []MaintenanceWindow{},
+	}
+
+	for rows.Next() {
+		var window MaintenanceWindow
+		var schedule sql.NullString
+		var durationSeconds sql.NullInt64
+		var startsAt, endsAt pq.NullTime
+
+		err := rows.Scan(&window.Name, &schedule, &durationSeconds, &startsAt, &endsAt)
+		if err != nil {
+			return Maintenance{}, err
+		}
+
+		window.Schedule = schedule.String
+		window.Duration = time.Duration(durationSeconds.Int64) * time.Second
+		window.StartsAt = startsAt.Time
+		window.EndsAt = endsAt.Time
+
+		maintenance.Windows = append(maintenance.Windows, window)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return Maintenance{}, err
+	}
+
+	var overriddenUntil pq.NullTime
+	err = db.conn.QueryRowContext(ctx, `
+		SELECT MAX(until)
+		FROM maintenance_overrides
+	`).Scan(&overriddenUntil)
+	if err != nil {
+		return Maintenance{}, err
+	}
+
+	maintenance.OverriddenUntil = overriddenUntil.Time
+
+	return maintenance, nil
+}
+
+// SetMaintenanceWindows replaces every maintenance window with the given
+// ones.
+func (db *SQLDB) SetMaintenanceWindows(ctx context.Context, windows []MaintenanceWindow) error {
+	tx, err := db.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`DELETE FROM maintenance_windows`)
+	if err != nil {
+		return err
+	}
+
+	for _, window := range windows {
+		var schedule sql.NullString
+		var durationSeconds sql.NullInt64
+		var startsAt, endsAt pq.NullTime
+
+		if window.Schedule != "" {
+			schedule = sql.NullString{String: window.Schedule, Valid: true}
+			durationSeconds = sql.NullInt64{Int64: int64(window.Duration / time.Second), Valid: true}
+		} else {
+			startsAt = pq.NullTime{Time: window.StartsAt, Valid: true}
+			endsAt = pq.NullTime{Time: window.EndsAt, Valid: true}
+		}
+
+		_, err = tx.Exec(`
+			INSERT INTO maintenance_windows (name, schedule, duration_seconds, starts_at, ends_at)
+			VALUES ($1, $2, $3, $4, $5)
+		`, window.Name, schedule, durationSeconds, startsAt, endsAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// SetMaintenanceOverride lets builds start in spite of any maintenance
+// windows until the given time. The zero time removes the override.
+func (db *SQLDB) SetMaintenanceOverride(ctx context.Context, until time.Time) error {
+	tx, err := db.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`DELETE FROM maintenance_overrides`)
+	if err != nil {
+		return err
+	}
+
+	if !until.IsZero() {
+		_, err = tx.Exec(`
+			INSERT INTO maintenance_overrides (until)
+			VALUES ($1)
+		`, until)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// MaintenanceHoldsBuilds returns whether a maintenance window is holding new
+// builds right now.
+func (db *SQLDB) MaintenanceHoldsBuilds() (bool, error) {
+	maintenance, err := db.GetMaintenance(context.Background())
+	if err != nil {
+		return false, err
+	}
+
+	return maintenance.HoldsBuildsAt(time.Now()), nil
+}
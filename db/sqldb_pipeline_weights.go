@@ -0,0 +1,124 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+)
+
+// GetPipelineWeights returns the weight of every pipeline, including those
+// left at the default.
+func (db *SQLDB) GetPipelineWeights(ctx context.Context) ([]PipelineWeight, error) {
+	rows, err := db.conn.QueryContext(ctx, `
+		SELECT p.id, p.name, t.name, COALESCE(w.weight, $1)
+		FROM pipelines p
+		INNER JOIN teams t ON t.id = p.team_id
+		LEFT JOIN pipeline_weights w ON w.pipeline_id = p.id
+		ORDER BY t.name ASC, p.ordering ASC
+	`, DefaultPipelineWeight)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	weights := []PipelineWeight{}
+	for rows.Next() {
+		var weight PipelineWeight
+		err := rows.Scan(&weight.PipelineID, &weight.PipelineName, &weight.TeamName, &weight.Weight)
+		if err != nil {
+			return nil, err
+		}
+
+		weights = append(weights, weight)
+	}
+
+	return weights, rows.Err()
+}
+
+func (db *SQLDB) SetPipelineWeight(ctx context.Context, pipelineID int, weight int) error {
+	tx, err := db.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE pipeline_weights
+		SET weight = $2
+		WHERE pipeline_id = $1
+	`, pipelineID, weight)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		_, err = tx.Exec(`
+			INSERT INTO pipeline_weights (pipeline_id, weight)
+			VALUES ($1, $2)
+		`, pipelineID, weight)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// FairShareReached returns whether the pipeline has its share of the global
+// max in flight while others are waiting for theirs. Builds of paused jobs
+// and pipelines aren't counted as waiting, as they can't be started.
+func (db *SQLDB) FairShareReached(pipelineID int) (bool, error) {
+	maxInFlight, err := db.GetGlobalMaxInFlight(context.Background())
+	if err != nil {
+		return false, err
+	}
+
+	if maxInFlight == 0 {
+		return false, nil
+	}
+
+	rows, err := db.conn.Query(`
+		SELECT p.id, COALESCE(w.weight, $1),
+			SUM(CASE WHEN b.status = 'started' OR b.scheduled THEN 1 ELSE 0 END),
+			SUM(CASE WHEN b.status = 'pending' AND NOT b.scheduled AND NOT p.paused AND NOT j.paused THEN 1 ELSE 0 END)
+		FROM builds b
+		INNER JOIN jobs j ON j.id = b.job_id
+		INNER JOIN pipelines p ON p.id = j.pipeline_id
+		LEFT JOIN pipeline_weights w ON w.pipeline_id = p.id
+		WHERE b.status IN ('pending', 'started')
+		GROUP BY p.id, w.weight
+	`, DefaultPipelineWeight)
+	if err != nil {
+		return false, err
+	}
+
+	defer rows.Close()
+
+	loads := PipelineLoads{}
+	for rows.Next() {
+		var load PipelineLoad
+		var inFlight, waiting sql.NullInt64
+		err := rows.Scan(&load.PipelineID, &load.Weight, &inFlight, &waiting)
+		if err != nil {
+			return false, err
+		}
+
+		load.InFlight = int(inFlight.Int64)
+		load.Waiting = int(waiting.Int64)
+
+		loads = append(loads, load)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return false, err
+	}
+
+	return loads.FairShareReached(pipelineID, maxInFlight), nil
+}
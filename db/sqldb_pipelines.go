@@ -1,13 +1,22 @@
 package db
 
+import (
+	"context"
+	"encoding/json"
+)
+
 //go:generate counterfeiter . PipelinesDB
 
 type PipelinesDB interface {
 	GetAllPublicPipelines() ([]SavedPipeline, error)
+	GetPublicPipelineDashboards() ([]PipelineDashboard, error)
+
+	GetPipelineWeights(ctx context.Context) ([]PipelineWeight, error)
+	SetPipelineWeight(ctx context.Context, pipelineID int, weight int) error
 }
 
-const pipelineColumns = "p.id, p.name, p.config, p.version, p.paused, p.team_id, p.public, t.name as team_name"
-const unqualifiedPipelineColumns = "id, name, config, version, paused, team_id, public"
+const pipelineColumns = "p.id, p.name, p.config, p.version, p.paused, p.team_id, p.public, p.instance_of, p.instance_vars, p.permissions, t.name as team_name"
+const unqualifiedPipelineColumns = "id, name, config, version, paused, team_id, public, instance_of, instance_vars, permissions"
 
 func (db *SQLDB) GetAllPublicPipelines() ([]SavedPipeline, error) {
 	rows, err := db.conn.Query(`
@@ -23,7 +32,7 @@ func (db *SQLDB) GetAllPublicPipelines() ([]SavedPipeline, error) {
 
 	defer rows.Close()
 
-	return scanPipelines(rows)
+	return scanPipelines(rows, db.conn.EncryptionStrategy())
 }
 
 func (db *SQLDB) GetAllPipelines() ([]SavedPipeline, error) {
@@ -39,7 +48,7 @@ func (db *SQLDB) GetAllPipelines() ([]SavedPipeline, error) {
 
 	defer rows.Close()
 
-	return scanPipelines(rows)
+	return scanPipelines(rows, db.conn.EncryptionStrategy())
 }
 
 func (db *SQLDB) GetPipelineByID(pipelineID int) (SavedPipeline, error) {
@@ -50,5 +59,70 @@ func (db *SQLDB) GetPipelineByID(pipelineID int) (SavedPipeline, error) {
 		WHERE p.id = $1
 	`, pipelineID)
 
-	return scanPipeline(row)
+	return scanPipeline(row, db.conn.EncryptionStrategy())
+}
+
+// GetResourceVersionsBetween returns at most limit of the versions of the
+// pipeline's resource that were found after one version, up to and including
+// another, newest first. Nothing is returned if either version isn't known.
+func (db *SQLDB) GetResourceVersionsBetween(ctx context.Context, pipelineID int, resourceName string, after Version, upTo Version, limit int) ([]SavedVersionedResource, error) {
+	afterJSON, err := json.Marshal(after)
+	if err != nil {
+		return nil, err
+	}
+
+	upToJSON, err := json.Marshal(upTo)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.conn.QueryContext(ctx, `
+		SELECT v.id, v.enabled, v.type, v.version, v.metadata, r.name, v.check_order
+		FROM versioned_resources v
+		INNER JOIN resources r ON v.resource_id = r.id
+		WHERE r.pipeline_id = $1
+		AND r.name = $2
+		AND v.check_order > (
+			SELECT MAX(av.check_order) FROM versioned_resources av
+			WHERE av.resource_id = r.id AND av.version = $3
+		)
+		AND v.check_order <= (
+			SELECT MAX(uv.check_order) FROM versioned_resources uv
+			WHERE uv.resource_id = r.id AND uv.version = $4
+		)
+		ORDER BY v.check_order DESC
+		LIMIT $5
+	`, pipelineID, resourceName, string(afterJSON), string(upToJSON), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	versions := []SavedVersionedResource{}
+	for rows.Next() {
+		var version SavedVersionedResource
+		var versionString, metadataString string
+
+		err := rows.Scan(&version.ID, &version.Enabled, &version.Type, &versionString, &metadataString, &version.Resource, &version.CheckOrder)
+		if err != nil {
+			return nil, err
+		}
+
+		err = json.Unmarshal([]byte(versionString), &version.Version)
+		if err != nil {
+			return nil, err
+		}
+
+		err = json.Unmarshal([]byte(metadataString), &version.Metadata)
+		if err != nil {
+			return nil, err
+		}
+
+		version.PipelineID = pipelineID
+
+		versions = append(versions, version)
+	}
+
+	return versions, rows.Err()
 }
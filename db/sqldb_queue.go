@@ -0,0 +1,86 @@
+package db
+
+import (
+	"context"
+
+	"github.com/lib/pq"
+)
+
+// GetBuildQueue returns the pending builds of every job, or of just the
+// team's jobs if a team is given, in the order each job's will be started.
+func (db *SQLDB) GetBuildQueue(ctx context.Context, teamName string) ([]QueuedBuild, error) {
+	rows, err := db.conn.QueryContext(ctx, `
+		SELECT
+			b.id,
+			b.name,
+			t.name,
+			p.name,
+			j.name,
+			b.priority,
+			b.create_time,
+			j.resource_checking AND b.id > j.resource_check_waiver_end,
+			j.scheduling_reason,
+			j.scheduling_checked_at,
+			row_number() OVER (PARTITION BY b.job_id ORDER BY b.priority DESC, b.id ASC)
+		FROM builds b
+		INNER JOIN jobs j ON b.job_id = j.id
+		INNER JOIN pipelines p ON j.pipeline_id = p.id
+		INNER JOIN teams t ON b.team_id = t.id
+		WHERE b.status = 'pending'
+		AND ($1 = '' OR t.name = $1)
+		ORDER BY t.name ASC, p.name ASC, j.name ASC, b.priority DESC, b.id ASC
+	`, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	queue := []QueuedBuild{}
+
+	for rows.Next() {
+		var queued QueuedBuild
+		var createTime, checkedAt pq.NullTime
+		var awaitingCheck bool
+		var reason string
+
+		err := rows.Scan(
+			&queued.BuildID,
+			&queued.BuildName,
+			&queued.TeamName,
+			&queued.PipelineName,
+			&queued.JobName,
+			&queued.Priority,
+			&createTime,
+			&awaitingCheck,
+			&reason,
+			&checkedAt,
+			&queued.Position,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if createTime.Valid {
+			queued.CreateTime = createTime.Time
+		}
+
+		if checkedAt.Valid {
+			queued.CheckedAt = checkedAt.Time
+
+			// a reason from before the build was created, e.g. that there
+			// were no pending builds, has nothing to do with it
+			if !createTime.Valid || !checkedAt.Time.Before(createTime.Time) {
+				queued.Reason = SchedulingReason(reason)
+			}
+		}
+
+		if awaitingCheck {
+			queued.Reason = SchedulingReasonCheckingResources
+		}
+
+		queue = append(queue, queued)
+	}
+
+	return queue, rows.Err()
+}
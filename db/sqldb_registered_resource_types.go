@@ -0,0 +1,95 @@
+package db
+
+import "github.com/concourse/atc"
+
+// RegisterResourceType saves the resource type, replacing any already
+// registered with the same name.
+func (db *SQLDB) RegisterResourceType(resourceType atc.RegisteredResourceType) error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE registered_resource_types
+		SET image = $2, version = $3
+		WHERE name = $1
+	`, resourceType.Name, resourceType.Image, resourceType.Version)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		_, err = tx.Exec(`
+			INSERT INTO registered_resource_types (name, image, version)
+			VALUES ($1, $2, $3)
+		`, resourceType.Name, resourceType.Image, resourceType.Version)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// UnregisterResourceType removes the resource type, returning false if none
+// is registered with the name.
+func (db *SQLDB) UnregisterResourceType(name string) (bool, error) {
+	result, err := db.conn.Exec(`
+		DELETE FROM registered_resource_types
+		WHERE name = $1
+	`, name)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+func (db *SQLDB) GetRegisteredResourceTypes() ([]atc.RegisteredResourceType, error) {
+	return getRegisteredResourceTypes(db.conn)
+}
+
+func (pdb *pipelineDB) GetRegisteredResourceTypes() ([]atc.RegisteredResourceType, error) {
+	return getRegisteredResourceTypes(pdb.conn)
+}
+
+func getRegisteredResourceTypes(conn Conn) ([]atc.RegisteredResourceType, error) {
+	rows, err := conn.Query(`
+		SELECT name, image, version
+		FROM registered_resource_types
+		ORDER BY name ASC
+	`)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	resourceTypes := []atc.RegisteredResourceType{}
+
+	for rows.Next() {
+		var resourceType atc.RegisteredResourceType
+
+		err := rows.Scan(&resourceType.Name, &resourceType.Image, &resourceType.Version)
+		if err != nil {
+			return nil, err
+		}
+
+		resourceTypes = append(resourceTypes, resourceType)
+	}
+
+	return resourceTypes, nil
+}
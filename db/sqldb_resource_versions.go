@@ -0,0 +1,88 @@
+package db
+
+// PruneResourceVersions deletes up to limit versions that are older than the
+// latest retain versions of their resource, and returns how many it deleted.
+//
+// Versions that any build used or produced are kept, as their builds still
+// point to them, as are those that are pinned, disabled or about to be used
+// by a build, so that pruning never changes what runs.
+func (db *SQLDB) PruneResourceVersions(retain int, limit int) (int, error) {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return 0, err
+	}
+
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		DELETE FROM versioned_resources
+		WHERE id IN (
+			SELECT v.id
+			FROM (
+				SELECT id, enabled, row_number() OVER (
+					PARTITION BY resource_id
+					ORDER BY check_order DESC
+				) AS newness
+				FROM versioned_resources
+			) v
+			WHERE v.newness > $1
+			AND v.enabled
+			AND NOT EXISTS (SELECT 1 FROM build_inputs i WHERE i.versioned_resource_id = v.id)
+			AND NOT EXISTS (SELECT 1 FROM build_outputs o WHERE o.versioned_resource_id = v.id)
+			AND NOT EXISTS (SELECT 1 FROM next_build_inputs n WHERE n.version_id = v.id)
+			AND NOT EXISTS (SELECT 1 FROM independent_build_inputs n WHERE n.version_id = v.id)
+			AND NOT EXISTS (SELECT 1 FROM resources r WHERE r.pinned_version_id = v.id)
+			LIMIT $2
+		)
+		RETURNING resource_id
+	`, retain, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	defer rows.Close()
+
+	pruned := 0
+	resourceIDs := map[int]struct{}{}
+	for rows.Next() {
+		var resourceID int
+		err := rows.Scan(&resourceID)
+		if err != nil {
+			return 0, err
+		}
+
+		pruned++
+		resourceIDs[resourceID] = struct{}{}
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return 0, err
+	}
+
+	// the pipelines cache their versions until any of them are modified, so
+	// touch the latest version of each resource for them to notice
+	for resourceID := range resourceIDs {
+		_, err := tx.Exec(`
+			UPDATE versioned_resources
+			SET modified_time = now()
+			WHERE id = (
+				SELECT id
+				FROM versioned_resources
+				WHERE resource_id = $1
+				ORDER BY check_order DESC
+				LIMIT 1
+			)
+		`, resourceID)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, err
+	}
+
+	return pruned, nil
+}
@@ -9,7 +9,7 @@ import (
 
 func (db *SQLDB) GetTeams() ([]SavedTeam, error) {
 	rows, err := db.conn.Query(`
-		SELECT id, name, admin, basic_auth, github_auth, uaa_auth, genericoauth_auth FROM teams
+		SELECT id, name, admin, basic_auth, github_auth, uaa_auth, genericoauth_auth, oidc_auth, saml_auth, roles FROM teams
 	`)
 	if err != nil {
 		return nil, err
@@ -79,18 +79,33 @@ func (db *SQLDB) CreateTeam(team Team) (SavedTeam, error) {
 		return SavedTeam{}, err
 	}
 
+	jsonEncodedOIDCAuth, err := json.Marshal(team.OIDCAuth)
+	if err != nil {
+		return SavedTeam{}, err
+	}
+
+	jsonEncodedSAMLAuth, err := json.Marshal(team.SAMLAuth)
+	if err != nil {
+		return SavedTeam{}, err
+	}
+
+	jsonEncodedRoles, err := json.Marshal(team.Roles)
+	if err != nil {
+		return SavedTeam{}, err
+	}
+
 	return scanTeam(db.conn.QueryRow(`
 	INSERT INTO teams (
-    name, basic_auth, github_auth, uaa_auth, genericoauth_auth
+    name, basic_auth, github_auth, uaa_auth, genericoauth_auth, oidc_auth, saml_auth, roles
 	) VALUES (
-		$1, $2, $3, $4, $5
+		$1, $2, $3, $4, $5, $6, $7, $8
 	)
-	RETURNING id, name, admin, basic_auth, github_auth, uaa_auth, genericoauth_auth
-	`, team.Name, jsonEncodedBasicAuth, string(jsonEncodedGitHubAuth), string(jsonEncodedUAAAuth), string(jsonEncodedGenericOAuth)))
+	RETURNING id, name, admin, basic_auth, github_auth, uaa_auth, genericoauth_auth, oidc_auth, saml_auth, roles
+	`, team.Name, jsonEncodedBasicAuth, string(jsonEncodedGitHubAuth), string(jsonEncodedUAAAuth), string(jsonEncodedGenericOAuth), string(jsonEncodedOIDCAuth), string(jsonEncodedSAMLAuth), string(jsonEncodedRoles)))
 }
 
 func scanTeam(rows scannable) (SavedTeam, error) {
-	var basicAuth, gitHubAuth, uaaAuth, genericOAuth sql.NullString
+	var basicAuth, gitHubAuth, uaaAuth, genericOAuth, oidcAuth, samlAuth, roles sql.NullString
 	var savedTeam SavedTeam
 
 	err := rows.Scan(
@@ -101,6 +116,9 @@ func scanTeam(rows scannable) (SavedTeam, error) {
 		&gitHubAuth,
 		&uaaAuth,
 		&genericOAuth,
+		&oidcAuth,
+		&samlAuth,
+		&roles,
 	)
 	if err != nil {
 		return savedTeam, err
@@ -134,6 +152,27 @@ func scanTeam(rows scannable) (SavedTeam, error) {
 		}
 	}
 
+	if oidcAuth.Valid {
+		err = json.Unmarshal([]byte(oidcAuth.String), &savedTeam.OIDCAuth)
+		if err != nil {
+			return savedTeam, err
+		}
+	}
+
+	if samlAuth.Valid {
+		err = json.Unmarshal([]byte(samlAuth.String), &savedTeam.SAMLAuth)
+		if err != nil {
+			return savedTeam, err
+		}
+	}
+
+	if roles.Valid {
+		err = json.Unmarshal([]byte(roles.String), &savedTeam.Roles)
+		if err != nil {
+			return savedTeam, err
+		}
+	}
+
 	return savedTeam, nil
 }
 
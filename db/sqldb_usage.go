@@ -0,0 +1,74 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+// GetUsage adds up what finished builds used, per team, pipeline and
+// interval, oldest interval first.
+func (db *SQLDB) GetUsage(ctx context.Context, filter UsageFilter) ([]Usage, error) {
+	rows, err := db.conn.QueryContext(ctx, `
+		SELECT
+			t.name,
+			COALESCE(p.name, ''),
+			date_trunc($3, b.end_time AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' AS period,
+			COUNT(1),
+			COALESCE(SUM(EXTRACT(EPOCH FROM b.end_time - b.start_time)), 0),
+			COALESCE(SUM(s.seconds), 0),
+			COALESCE(SUM(b.cpu_usage), 0),
+			COALESCE(SUM(b.memory_usage * EXTRACT(EPOCH FROM b.end_time - b.start_time)), 0)
+		FROM builds b
+		INNER JOIN teams t ON b.team_id = t.id
+		LEFT OUTER JOIN jobs j ON b.job_id = j.id
+		LEFT OUTER JOIN pipelines p ON j.pipeline_id = p.id
+		LEFT OUTER JOIN (
+			SELECT build_id, SUM(EXTRACT(EPOCH FROM finished_at - started_at)) AS seconds
+			FROM build_steps
+			WHERE type IN ('task', 'get', 'put')
+			AND finished_at IS NOT NULL
+			GROUP BY build_id
+		) s ON s.build_id = b.id
+		WHERE b.start_time IS NOT NULL
+		AND b.end_time >= $1
+		AND b.end_time < $2
+		AND ($4 = '' OR t.name = $4)
+		GROUP BY t.name, p.name, period
+		ORDER BY period ASC, t.name ASC, p.name ASC NULLS FIRST
+	`, filter.Since, filter.Until, string(filter.Interval), filter.TeamName)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	usages := []Usage{}
+
+	for rows.Next() {
+		var usage Usage
+		var buildSeconds, workerSeconds float64
+		var cpuNanoseconds int64
+
+		err := rows.Scan(
+			&usage.TeamName,
+			&usage.PipelineName,
+			&usage.Start,
+			&usage.Builds,
+			&buildSeconds,
+			&workerSeconds,
+			&cpuNanoseconds,
+			&usage.MemoryByteSeconds,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		usage.BuildTime = secondsDuration(buildSeconds)
+		usage.WorkerTime = secondsDuration(workerSeconds)
+		usage.CPUTime = time.Duration(cpuNanoseconds)
+
+		usages = append(usages, usage)
+	}
+
+	return usages, rows.Err()
+}
@@ -101,13 +101,21 @@ func (db *SQLDB) InsertVolume(data Volume) error {
 		return err
 	}
 
+	err = countGC(tx, data.WorkerName, gcVolumesCreated, 1)
+	if err != nil {
+		return err
+	}
+
 	return tx.Commit()
 }
 
+// ReapVolume deletes a volume the worker no longer has, counting it as
+// orphaned.
 func (db *SQLDB) ReapVolume(handle string) error {
-	_, err := db.conn.Exec(`
+	_, err := deleteCountingGC(db.conn, gcVolumesOrphaned, `
 		DELETE FROM volumes
 		WHERE handle = $1
+		RETURNING worker_name
 	`, handle)
 	return err
 }
@@ -308,10 +316,11 @@ func (db *SQLDB) SetVolumeSizeInBytes(handle string, sizeInBytes int64) error {
 }
 
 func (db *SQLDB) expireVolumes() error {
-	_, err := db.conn.Exec(`
+	_, err := deleteCountingGC(db.conn, gcVolumesDestroyed, `
 		DELETE FROM volumes
 		WHERE expires_at IS NOT NULL
 		AND expires_at < NOW()
+		RETURNING worker_name
 	`)
 	return err
 }
@@ -0,0 +1,79 @@
+package db
+
+import "database/sql"
+
+// SaveWebhook creates or updates the webhook with the given ID, returning
+// false if the ID is already taken by another team.
+func (db *SQLDB) SaveWebhook(hook Webhook) (bool, error) {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return false, err
+	}
+
+	defer tx.Rollback()
+
+	var teamID int
+	err = tx.QueryRow(`
+		SELECT team_id
+		FROM webhooks
+		WHERE id = $1
+		FOR UPDATE
+	`, hook.ID).Scan(&teamID)
+	if err != nil && err != sql.ErrNoRows {
+		return false, err
+	}
+
+	if err == sql.ErrNoRows {
+		_, err = tx.Exec(`
+			INSERT INTO webhooks (id, team_id, pipeline_id, job_name, secret)
+			VALUES ($1, $2, $3, $4, $5)
+		`, hook.ID, hook.TeamID, hook.PipelineID, hook.JobName, hook.Secret)
+	} else if teamID == hook.TeamID {
+		_, err = tx.Exec(`
+			UPDATE webhooks
+			SET pipeline_id = $2, job_name = $3, secret = $4
+			WHERE id = $1
+		`, hook.ID, hook.PipelineID, hook.JobName, hook.Secret)
+	} else {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (db *SQLDB) GetWebhook(id string) (Webhook, bool, error) {
+	var hook Webhook
+
+	err := db.conn.QueryRow(`
+		SELECT w.id, t.id, t.name, p.id, p.name, w.job_name, w.secret
+		FROM webhooks w, teams t, pipelines p
+		WHERE w.team_id = t.id
+			AND w.pipeline_id = p.id
+			AND w.id = $1
+	`, id).Scan(
+		&hook.ID,
+		&hook.TeamID,
+		&hook.TeamName,
+		&hook.PipelineID,
+		&hook.PipelineName,
+		&hook.JobName,
+		&hook.Secret,
+	)
+	if err == sql.ErrNoRows {
+		return Webhook{}, false, nil
+	}
+
+	if err != nil {
+		return Webhook{}, false, err
+	}
+
+	return hook, true, nil
+}
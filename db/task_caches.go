@@ -0,0 +1,122 @@
+package db
+
+import (
+	"crypto/sha1"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// TaskCache is a directory that a job's task saved for the job's next build
+// to restore.
+type TaskCache struct {
+	PipelineID int
+	JobName    string
+	Path       string
+
+	SizeBytes int64
+	SavedAt   time.Time
+}
+
+// StorageKey is what the cache is kept under by the storage driver. Job names
+// and paths can contain anything, so they're hashed rather than escaped.
+func (cache TaskCache) StorageKey() string {
+	return fmt.Sprintf(
+		"%d/%x.tgz",
+		cache.PipelineID,
+		sha1.Sum([]byte(cache.JobName+"\x00"+cache.Path)),
+	)
+}
+
+// SaveTaskCache records that the cache has been saved, replacing any record
+// of when it was saved before.
+func (db *SQLDB) SaveTaskCache(cache TaskCache) error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE task_caches
+		SET size_bytes = $4, saved_at = now()
+		WHERE pipeline_id = $1
+		AND job_name = $2
+		AND path = $3
+	`, cache.PipelineID, cache.JobName, cache.Path, cache.SizeBytes)
+	if err != nil {
+		return err
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if updated == 0 {
+		_, err := tx.Exec(`
+			INSERT INTO task_caches (pipeline_id, job_name, path, size_bytes)
+			VALUES ($1, $2, $3, $4)
+		`, cache.PipelineID, cache.JobName, cache.Path, cache.SizeBytes)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (pdb *pipelineDB) GetTaskCaches(jobName string) ([]TaskCache, error) {
+	rows, err := pdb.conn.Query(`
+		SELECT `+taskCacheColumns+`
+		FROM task_caches
+		WHERE pipeline_id = $1
+		AND job_name = $2
+		ORDER BY path ASC
+	`, pdb.ID, jobName)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanTaskCaches(rows)
+}
+
+// DeleteTaskCaches forgets the job's cache at the path, or all of them if the
+// path is empty, and returns what it forgot so that it can be deleted from
+// storage too.
+func (pdb *pipelineDB) DeleteTaskCaches(jobName string, path string) ([]TaskCache, error) {
+	rows, err := pdb.conn.Query(`
+		DELETE FROM task_caches
+		WHERE pipeline_id = $1
+		AND job_name = $2
+		AND ($3 = '' OR path = $3)
+		RETURNING `+taskCacheColumns+`
+	`, pdb.ID, jobName, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanTaskCaches(rows)
+}
+
+const taskCacheColumns = "pipeline_id, job_name, path, size_bytes, saved_at"
+
+func scanTaskCaches(rows *sql.Rows) ([]TaskCache, error) {
+	defer rows.Close()
+
+	caches := []TaskCache{}
+
+	for rows.Next() {
+		var cache TaskCache
+
+		err := rows.Scan(&cache.PipelineID, &cache.JobName, &cache.Path, &cache.SizeBytes, &cache.SavedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		caches = append(caches, cache)
+	}
+
+	return caches, rows.Err()
+}
@@ -3,6 +3,7 @@ package db
 import (
 	"encoding/json"
 
+	"github.com/concourse/atc"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -14,10 +15,14 @@ type Team struct {
 	GitHubAuth   *GitHubAuth   `json:"github_auth"`
 	UAAAuth      *UAAAuth      `json:"uaa_auth"`
 	GenericOAuth *GenericOAuth `json:"genericoauth_auth"`
+	OIDCAuth     *OIDCAuth     `json:"oidc_auth"`
+	SAMLAuth     *SAMLAuth     `json:"saml_auth"`
+
+	Roles atc.TeamRoles `json:"roles"`
 }
 
 func (t Team) IsAuthConfigured() bool {
-	return t.BasicAuth != nil || t.GitHubAuth != nil || t.UAAAuth != nil
+	return t.BasicAuth != nil || t.GitHubAuth != nil || t.UAAAuth != nil || t.OIDCAuth != nil || t.SAMLAuth != nil
 }
 
 type BasicAuth struct {
@@ -81,3 +86,24 @@ type GenericOAuth struct {
 	ClientSecret  string            `json:"client_secret"`
 	DisplayName   string            `json:"display_name"`
 }
+
+type OIDCAuth struct {
+	DisplayName  string   `json:"display_name"`
+	Issuer       string   `json:"issuer"`
+	ClientID     string   `json:"client_id"`
+	ClientSecret string   `json:"client_secret"`
+	Scopes       []string `json:"scopes"`
+	GroupsClaim  string   `json:"groups_claim"`
+	Groups       []string `json:"groups"`
+	Users        []string `json:"users"`
+}
+
+type SAMLAuth struct {
+	DisplayName     string   `json:"display_name"`
+	SSOURL          string   `json:"sso_url"`
+	IdPEntityID     string   `json:"idp_entity_id"`
+	IdPCertificate  string   `json:"idp_certificate"`
+	GroupsAttribute string   `json:"groups_attribute"`
+	Groups          []string `json:"groups"`
+	Users           []string `json:"users"`
+}
@@ -1,14 +1,19 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
 
 	"github.com/concourse/atc"
+	"github.com/concourse/atc/creds"
+	"github.com/concourse/atc/db/encryption"
 )
 
 //go:generate counterfeiter . TeamDB
@@ -17,6 +22,7 @@ type TeamDB interface {
 	GetPipelines() ([]SavedPipeline, error)
 	GetPublicPipelines() ([]SavedPipeline, error)
 	GetPrivateAndAllPublicPipelines() ([]SavedPipeline, error)
+	GetPipelineDashboards() ([]PipelineDashboard, error)
 
 	GetPipelineByName(pipelineName string) (SavedPipeline, bool, error)
 
@@ -27,12 +33,34 @@ type TeamDB interface {
 	UpdateGitHubAuth(gitHubAuth *GitHubAuth) (SavedTeam, error)
 	UpdateUAAAuth(uaaAuth *UAAAuth) (SavedTeam, error)
 	UpdateGenericOAuth(genericOAuth *GenericOAuth) (SavedTeam, error)
+	UpdateOIDCAuth(oidcAuth *OIDCAuth) (SavedTeam, error)
+	UpdateSAMLAuth(samlAuth *SAMLAuth) (SavedTeam, error)
+	UpdateRoles(roles atc.TeamRoles) (SavedTeam, error)
+
+	SaveSAMLRequest(id string, redirect string, expiresAt time.Time) error
+	TakeSAMLRequest(id string) (string, bool, error)
+	SaveSAMLAssertion(id string, expiresAt time.Time) (bool, error)
 
 	GetConfig(pipelineName string) (atc.Config, atc.RawConfig, ConfigVersion, error)
-	SaveConfig(string, atc.Config, ConfigVersion, PipelinePausedState) (SavedPipeline, bool, error)
+	SaveConfig(pipelineName string, config atc.Config, from ConfigVersion, pausedState PipelinePausedState, author string) (SavedPipeline, bool, error)
+	GetConfigRevisions(pipelineName string) ([]ConfigRevision, bool, error)
+	RevertConfig(pipelineName string, to ConfigVersion, author string) (SavedPipeline, bool, error)
+
+	SaveCandidateConfig(pipelineName string, config atc.Config, percentage int, author string) (bool, error)
+	GetCandidateConfig(pipelineName string) (CandidateConfig, bool, error)
+	DeleteCandidateConfig(pipelineName string) (bool, error)
+	PromoteCandidateConfig(pipelineName string, author string) (SavedPipeline, bool, error)
+	GetCandidateComparison(pipelineName string) ([]CandidateComparison, bool, error)
+
+	GetPipelineInstances(templateName string) ([]SavedPipeline, bool, error)
+	SavePipelineInstance(templateName string, instanceName string, vars map[string]interface{}, author string) (SavedPipeline, bool, error)
+	DestroyPipelineInstance(templateName string, instanceName string) (bool, error)
 
 	CreateOneOffBuild() (Build, error)
-	GetPrivateAndPublicBuilds(page Page) ([]Build, Pagination, error)
+	CreateRerunBuild(original Build) (Build, error)
+	GetPrivateAndPublicBuilds(page Page, filter BuildFilter) ([]Build, Pagination, error)
+	GetBuilds(page Page, filter BuildFilter) ([]Build, Pagination, error)
+	SearchBuildLogs(query string, limit int, hiddenPipelineIDs []int) ([]LogMatch, error)
 
 	Workers() ([]SavedWorker, error)
 	GetContainer(handle string) (SavedContainer, bool, error)
@@ -58,7 +86,7 @@ func (db *teamDB) GetPipelineByName(pipelineName string) (SavedPipeline, bool, e
 			SELECT id FROM teams WHERE LOWER(name) = LOWER($2)
 		)
 	`, pipelineName, db.teamName)
-	pipeline, err := scanPipeline(row)
+	pipeline, err := scanPipeline(row, db.conn.EncryptionStrategy())
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return SavedPipeline{}, false, nil
@@ -86,7 +114,7 @@ func (db *teamDB) GetPipelines() ([]SavedPipeline, error) {
 
 	defer rows.Close()
 
-	return scanPipelines(rows)
+	return scanPipelines(rows, db.conn.EncryptionStrategy())
 }
 
 func (db *teamDB) GetPublicPipelines() ([]SavedPipeline, error) {
@@ -106,7 +134,7 @@ func (db *teamDB) GetPublicPipelines() ([]SavedPipeline, error) {
 
 	defer rows.Close()
 
-	return scanPipelines(rows)
+	return scanPipelines(rows, db.conn.EncryptionStrategy())
 }
 
 func (db *teamDB) GetPrivateAndAllPublicPipelines() ([]SavedPipeline, error) {
@@ -123,7 +151,7 @@ func (db *teamDB) GetPrivateAndAllPublicPipelines() ([]SavedPipeline, error) {
 
 	defer rows.Close()
 
-	currentTeamPipelines, err := scanPipelines(rows)
+	currentTeamPipelines, err := scanPipelines(rows, db.conn.EncryptionStrategy())
 	if err != nil {
 		return nil, err
 	}
@@ -142,7 +170,7 @@ func (db *teamDB) GetPrivateAndAllPublicPipelines() ([]SavedPipeline, error) {
 
 	defer otherRows.Close()
 
-	otherTeamPipelines, err := scanPipelines(otherRows)
+	otherTeamPipelines, err := scanPipelines(otherRows, db.conn.EncryptionStrategy())
 	if err != nil {
 		return nil, err
 	}
@@ -221,13 +249,18 @@ func (db *teamDB) GetConfig(pipelineName string) (atc.Config, atc.RawConfig, Con
 		return atc.Config{}, atc.RawConfig(""), 0, err
 	}
 
+	decrypted, err := db.conn.EncryptionStrategy().Decrypt(string(configBlob))
+	if err != nil {
+		return atc.Config{}, atc.RawConfig(""), 0, err
+	}
+
 	var config atc.Config
-	err = json.Unmarshal(configBlob, &config)
+	err = json.Unmarshal(decrypted, &config)
 	if err != nil {
-		return atc.Config{}, atc.RawConfig(string(configBlob)), ConfigVersion(version), atc.MalformedConfigError{err}
+		return atc.Config{}, atc.RawConfig(string(decrypted)), ConfigVersion(version), atc.MalformedConfigError{err}
 	}
 
-	return config, atc.RawConfig(string(configBlob)), ConfigVersion(version), nil
+	return config, atc.RawConfig(string(decrypted)), ConfigVersion(version), nil
 }
 
 func (db *teamDB) SaveConfig(
@@ -235,12 +268,8 @@ func (db *teamDB) SaveConfig(
 	config atc.Config,
 	from ConfigVersion,
 	pausedState PipelinePausedState,
+	author string,
 ) (SavedPipeline, bool, error) {
-	payload, err := json.Marshal(config)
-	if err != nil {
-		return SavedPipeline{}, false, err
-	}
-
 	tx, err := db.conn.Begin()
 	if err != nil {
 		return SavedPipeline{}, false, err
@@ -254,6 +283,73 @@ func (db *teamDB) SaveConfig(
 		return SavedPipeline{}, false, err
 	}
 
+	var instanceOf sql.NullInt64
+	err = tx.QueryRow(`
+		SELECT instance_of
+		FROM pipelines
+		WHERE name = $1
+		AND team_id = $2
+	`, pipelineName, teamID).Scan(&instanceOf)
+	if err != nil && err != sql.ErrNoRows {
+		return SavedPipeline{}, false, err
+	}
+
+	if instanceOf.Valid {
+		return SavedPipeline{}, false, ErrPipelineIsInstance
+	}
+
+	savedPipeline, created, err := db.saveConfig(tx, teamID, pipelineName, config, from, pausedState, author)
+	if err != nil {
+		return SavedPipeline{}, false, err
+	}
+
+	rows, err := tx.Query(`
+		SELECT `+pipelineColumns+`
+		FROM pipelines p
+		INNER JOIN teams t ON t.id = p.team_id
+		WHERE p.instance_of = $1
+	`, savedPipeline.ID)
+	if err != nil {
+		return SavedPipeline{}, false, err
+	}
+
+	instances, err := scanPipelines(rows, db.conn.EncryptionStrategy())
+	rows.Close()
+	if err != nil {
+		return SavedPipeline{}, false, err
+	}
+
+	for _, instance := range instances {
+		_, _, err = db.saveInstanceConfig(tx, teamID, instance.Name, config, instance.InstanceVars, instance.Version, author)
+		if err != nil {
+			return SavedPipeline{}, false, err
+		}
+	}
+
+	return savedPipeline, created, tx.Commit()
+}
+
+// saveConfig saves the config of one pipeline, creating it if it doesn't
+// exist yet, as part of a larger transaction.
+func (db *teamDB) saveConfig(
+	tx Tx,
+	teamID int,
+	pipelineName string,
+	config atc.Config,
+	from ConfigVersion,
+	pausedState PipelinePausedState,
+	author string,
+) (SavedPipeline, bool, error) {
+	configBlob, err := json.Marshal(config)
+	if err != nil {
+		return SavedPipeline{}, false, err
+	}
+
+	payload, err := db.conn.EncryptionStrategy().Encrypt(configBlob)
+	if err != nil {
+		return SavedPipeline{}, false, err
+	}
+
 	var created bool
 	var savedPipeline SavedPipeline
 
@@ -287,8 +383,13 @@ func (db *teamDB) SaveConfig(
 		(
 			SELECT t.name as team_name FROM teams t WHERE t.id = $4
 		)
-		`, pipelineName, payload, pausedState.Bool(), teamID))
+		`, pipelineName, payload, pausedState.Bool(), teamID), db.conn.EncryptionStrategy())
 		if err != nil {
+			// someone else created the pipeline since we checked
+			if pgErr, ok := err.(*pq.Error); ok && pgErr.Code.Name() == "unique_violation" {
+				return SavedPipeline{}, false, ErrConfigComparisonFailed
+			}
+
 			return SavedPipeline{}, false, err
 		}
 
@@ -315,6 +416,20 @@ func (db *teamDB) SaveConfig(
 		if err != nil {
 			return SavedPipeline{}, false, err
 		}
+
+		_, err = tx.Exec(fmt.Sprintf(`
+		CREATE INDEX pipeline_build_events_%[1]d_log_search ON pipeline_build_events_%[1]d USING gin (`+logSearchVector+`) WHERE type = 'log';
+		`, savedPipeline.ID))
+		if err != nil {
+			return SavedPipeline{}, false, err
+		}
+
+		_, err = tx.Exec(fmt.Sprintf(`
+		CREATE INDEX pipeline_build_events_%[1]d_compressed_log_search ON pipeline_build_events_%[1]d USING gin (log_search) WHERE type = 'log';
+		`, savedPipeline.ID))
+		if err != nil {
+			return SavedPipeline{}, false, err
+		}
 	} else {
 		if pausedState == PipelineNoChange {
 			savedPipeline, err = scanPipeline(tx.QueryRow(`
@@ -327,7 +442,7 @@ func (db *teamDB) SaveConfig(
 			(
 				SELECT t.name as team_name FROM teams t WHERE t.id = $4
 			)
-			`, payload, pipelineName, from, teamID))
+			`, payload, pipelineName, from, teamID), db.conn.EncryptionStrategy())
 		} else {
 			savedPipeline, err = scanPipeline(tx.QueryRow(`
 			UPDATE pipelines
@@ -337,9 +452,9 @@ func (db *teamDB) SaveConfig(
 			AND team_id = $5
 			RETURNING `+unqualifiedPipelineColumns+`,
 			(
-				SELECT t.name as team_name FROM teams t WHERE t.id = $4
+				SELECT t.name as team_name FROM teams t WHERE t.id = $5
 			)
-			`, payload, pausedState.Bool(), pipelineName, from, teamID))
+			`, payload, pausedState.Bool(), pipelineName, from, teamID), db.conn.EncryptionStrategy())
 		}
 
 		if err != nil && err != sql.ErrNoRows {
@@ -363,6 +478,14 @@ func (db *teamDB) SaveConfig(
 		}
 	}
 
+	_, err = tx.Exec(`
+		INSERT INTO pipeline_config_revisions (pipeline_id, version, config, author)
+		VALUES ($1, $2, $3, $4)
+	`, savedPipeline.ID, savedPipeline.Version, payload, author)
+	if err != nil {
+		return SavedPipeline{}, false, err
+	}
+
 	for _, resource := range config.Resources {
 		err = db.registerResource(tx, resource.Name, savedPipeline.ID)
 		if err != nil {
@@ -391,9 +514,278 @@ func (db *teamDB) SaveConfig(
 		}
 	}
 
+	err = notifyPipelineChanged(tx, savedPipeline.ID)
+	if err != nil {
+		return SavedPipeline{}, false, err
+	}
+
+	err = notifyScheduling(tx, savedPipeline.ID)
+	if err != nil {
+		return SavedPipeline{}, false, err
+	}
+
+	return savedPipeline, created, nil
+}
+
+// saveInstanceConfig saves the template's config, filled in with the
+// instance's vars, as the config of the instance.
+func (db *teamDB) saveInstanceConfig(
+	tx Tx,
+	teamID int,
+	instanceName string,
+	template atc.Config,
+	vars map[string]interface{},
+	from ConfigVersion,
+	author string,
+) (SavedPipeline, bool, error) {
+	config, err := creds.NewInterpolator(creds.StaticVariables(vars)).Config(template)
+	if err != nil {
+		return SavedPipeline{}, false, err
+	}
+
+	return db.saveConfig(tx, teamID, instanceName, config, from, PipelineNoChange, author)
+}
+
+// GetConfigRevisions returns every config that has been saved for the
+// pipeline, newest first.
+func (db *teamDB) GetConfigRevisions(pipelineName string) ([]ConfigRevision, bool, error) {
+	pipeline, found, err := db.GetPipelineByName(pipelineName)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !found {
+		return nil, false, nil
+	}
+
+	rows, err := db.conn.Query(`
+		SELECT version, config, author, created_at
+		FROM pipeline_config_revisions
+		WHERE pipeline_id = $1
+		ORDER BY version DESC
+	`, pipeline.ID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	defer rows.Close()
+
+	revisions := []ConfigRevision{}
+
+	for rows.Next() {
+		var revision ConfigRevision
+		var configBlob []byte
+
+		err := rows.Scan(&revision.Version, &configBlob, &revision.Author, &revision.CreatedAt)
+		if err != nil {
+			return nil, false, err
+		}
+
+		decrypted, err := db.conn.EncryptionStrategy().Decrypt(string(configBlob))
+		if err != nil {
+			return nil, false, err
+		}
+
+		err = json.Unmarshal(decrypted, &revision.Config)
+		if err != nil {
+			return nil, false, err
+		}
+
+		revisions = append(revisions, revision)
+	}
+
+	return revisions, true, nil
+}
+
+// RevertConfig saves the config of an earlier revision as the pipeline's
+// current config. The revert is a revision of its own, so it can be undone
+// the same way.
+func (db *teamDB) RevertConfig(pipelineName string, to ConfigVersion, author string) (SavedPipeline, bool, error) {
+	pipeline, found, err := db.GetPipelineByName(pipelineName)
+	if err != nil {
+		return SavedPipeline{}, false, err
+	}
+
+	if !found {
+		return SavedPipeline{}, false, nil
+	}
+
+	var configBlob []byte
+	err = db.conn.QueryRow(`
+		SELECT config
+		FROM pipeline_config_revisions
+		WHERE pipeline_id = $1
+			AND version = $2
+	`, pipeline.ID, to).Scan(&configBlob)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return SavedPipeline{}, false, nil
+		}
+
+		return SavedPipeline{}, false, err
+	}
+
+	decrypted, err := db.conn.EncryptionStrategy().Decrypt(string(configBlob))
+	if err != nil {
+		return SavedPipeline{}, false, err
+	}
+
+	var config atc.Config
+	err = json.Unmarshal(decrypted, &config)
+	if err != nil {
+		return SavedPipeline{}, false, err
+	}
+
+	savedPipeline, _, err := db.SaveConfig(pipelineName, config, pipeline.Version, PipelineNoChange, author)
+	if err != nil {
+		return SavedPipeline{}, false, err
+	}
+
+	return savedPipeline, true, nil
+}
+
+// GetPipelineInstances returns the instances of the pipeline, by name.
+func (db *teamDB) GetPipelineInstances(templateName string) ([]SavedPipeline, bool, error) {
+	template, found, err := db.GetPipelineByName(templateName)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !found {
+		return nil, false, nil
+	}
+
+	rows, err := db.conn.Query(`
+		SELECT `+pipelineColumns+`
+		FROM pipelines p
+		INNER JOIN teams t ON t.id = p.team_id
+		WHERE p.instance_of = $1
+		ORDER BY p.name
+	`, template.ID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	defer rows.Close()
+
+	instances, err := scanPipelines(rows, db.conn.EncryptionStrategy())
+	if err != nil {
+		return nil, false, err
+	}
+
+	return instances, true, nil
+}
+
+// SavePipelineInstance creates or updates a pipeline whose config is the
+// template's, with its ((name))s filled in from the vars. It's kept up to
+// date as the template's config is saved. New instances start out paused,
+// like any other new pipeline.
+func (db *teamDB) SavePipelineInstance(templateName string, instanceName string, vars map[string]interface{}, author string) (SavedPipeline, bool, error) {
+	varsBlob, err := json.Marshal(vars)
+	if err != nil {
+		return SavedPipeline{}, false, err
+	}
+
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return SavedPipeline{}, false, err
+	}
+
+	defer tx.Rollback()
+
+	var teamID int
+	err = tx.QueryRow(`SELECT id FROM teams WHERE LOWER(name) = LOWER($1)`, db.teamName).Scan(&teamID)
+	if err != nil {
+		return SavedPipeline{}, false, err
+	}
+
+	pipelineByName := `
+		SELECT ` + pipelineColumns + `
+		FROM pipelines p
+		INNER JOIN teams t ON t.id = p.team_id
+		WHERE p.name = $1
+		AND p.team_id = $2
+	`
+
+	template, err := scanPipeline(tx.QueryRow(pipelineByName, templateName, teamID), db.conn.EncryptionStrategy())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return SavedPipeline{}, false, ErrPipelineNotFound
+		}
+
+		return SavedPipeline{}, false, err
+	}
+
+	if template.InstanceOf != 0 {
+		return SavedPipeline{}, false, ErrPipelineIsInstance
+	}
+
+	existing, err := scanPipeline(tx.QueryRow(pipelineByName, instanceName, teamID), db.conn.EncryptionStrategy())
+	if err != nil && err != sql.ErrNoRows {
+		return SavedPipeline{}, false, err
+	}
+
+	if existing.ID != 0 && existing.InstanceOf != template.ID {
+		return SavedPipeline{}, false, ErrPipelineNameTaken
+	}
+
+	savedPipeline, created, err := db.saveInstanceConfig(tx, teamID, instanceName, template.Config, vars, existing.Version, author)
+	if err != nil {
+		return SavedPipeline{}, false, err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE pipelines
+		SET instance_of = $1, instance_vars = $2
+		WHERE id = $3
+	`, template.ID, varsBlob, savedPipeline.ID)
+	if err != nil {
+		return SavedPipeline{}, false, err
+	}
+
+	savedPipeline.InstanceOf = template.ID
+	savedPipeline.InstanceVars = vars
+
 	return savedPipeline, created, tx.Commit()
 }
 
+// DestroyPipelineInstance destroys one of the template's instances. It
+// returns false if there's no such instance of the template.
+func (db *teamDB) DestroyPipelineInstance(templateName string, instanceName string) (bool, error) {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return false, err
+	}
+
+	defer tx.Rollback()
+
+	var instanceID int
+	err = tx.QueryRow(`
+		SELECT i.id
+		FROM pipelines i
+		INNER JOIN pipelines p ON p.id = i.instance_of
+		WHERE i.name = $1
+		AND p.name = $2
+		AND i.team_id = (
+			SELECT id FROM teams WHERE LOWER(name) = LOWER($3)
+		)
+	`, instanceName, templateName, db.teamName).Scan(&instanceID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	err = destroyPipeline(tx, instanceID)
+	if err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
 func (db *teamDB) registerJob(tx Tx, name string, pipelineID int) error {
 	_, err := tx.Exec(`
 		INSERT INTO jobs (name, pipeline_id)
@@ -451,7 +843,7 @@ func (db *teamDB) registerResourceType(tx Tx, resourceType atc.ResourceType, pip
 
 func (db *teamDB) GetTeam() (SavedTeam, bool, error) {
 	query := `
-		SELECT id, name, admin, basic_auth, github_auth, uaa_auth, genericoauth_auth
+		SELECT id, name, admin, basic_auth, github_auth, uaa_auth, genericoauth_auth, oidc_auth, saml_auth, roles
 		FROM teams
 		WHERE LOWER(name) = LOWER($1)
 	`
@@ -469,7 +861,7 @@ func (db *teamDB) GetTeam() (SavedTeam, bool, error) {
 }
 
 func (db *teamDB) queryTeam(query string, params []interface{}) (SavedTeam, error) {
-	var basicAuth, gitHubAuth, uaaAuth, genericOAuth sql.NullString
+	var basicAuth, gitHubAuth, uaaAuth, genericOAuth, oidcAuth, samlAuth, roles sql.NullString
 	var savedTeam SavedTeam
 
 	tx, err := db.conn.Begin()
@@ -486,6 +878,9 @@ func (db *teamDB) queryTeam(query string, params []interface{}) (SavedTeam, erro
 		&gitHubAuth,
 		&uaaAuth,
 		&genericOAuth,
+		&oidcAuth,
+		&samlAuth,
+		&roles,
 	)
 	if err != nil {
 		return savedTeam, err
@@ -523,6 +918,27 @@ func (db *teamDB) queryTeam(query string, params []interface{}) (SavedTeam, erro
 		}
 	}
 
+	if oidcAuth.Valid {
+		err = json.Unmarshal([]byte(oidcAuth.String), &savedTeam.OIDCAuth)
+		if err != nil {
+			return savedTeam, err
+		}
+	}
+
+	if samlAuth.Valid {
+		err = json.Unmarshal([]byte(samlAuth.String), &savedTeam.SAMLAuth)
+		if err != nil {
+			return savedTeam, err
+		}
+	}
+
+	if roles.Valid {
+		err = json.Unmarshal([]byte(roles.String), &savedTeam.Roles)
+		if err != nil {
+			return savedTeam, err
+		}
+	}
+
 	return savedTeam, nil
 }
 
@@ -536,7 +952,7 @@ func (db *teamDB) UpdateBasicAuth(basicAuth *BasicAuth) (SavedTeam, error) {
 		UPDATE teams
 		SET basic_auth = $1
 		WHERE LOWER(name) = LOWER($2)
-		RETURNING id, name, admin, basic_auth, github_auth, uaa_auth, genericoauth_auth
+		RETURNING id, name, admin, basic_auth, github_auth, uaa_auth, genericoauth_auth, oidc_auth, saml_auth, roles
 	`
 
 	params := []interface{}{encryptedBasicAuth, db.teamName}
@@ -558,7 +974,7 @@ func (db *teamDB) UpdateGitHubAuth(gitHubAuth *GitHubAuth) (SavedTeam, error) {
 		UPDATE teams
 		SET github_auth = $1
 		WHERE LOWER(name) = LOWER($2)
-		RETURNING id, name, admin, basic_auth, github_auth, uaa_auth, genericoauth_auth
+		RETURNING id, name, admin, basic_auth, github_auth, uaa_auth, genericoauth_auth, oidc_auth, saml_auth, roles
 	`
 	params := []interface{}{string(jsonEncodedGitHubAuth), db.teamName}
 	return db.queryTeam(query, params)
@@ -574,7 +990,7 @@ func (db *teamDB) UpdateUAAAuth(uaaAuth *UAAAuth) (SavedTeam, error) {
 		UPDATE teams
 		SET uaa_auth = $1
 		WHERE LOWER(name) = LOWER($2)
-		RETURNING id, name, admin, basic_auth, github_auth, uaa_auth, genericoauth_auth
+		RETURNING id, name, admin, basic_auth, github_auth, uaa_auth, genericoauth_auth, oidc_auth, saml_auth, roles
 	`
 	params := []interface{}{string(jsonEncodedUAAAuth), db.teamName}
 	return db.queryTeam(query, params)
@@ -590,13 +1006,71 @@ func (db *teamDB) UpdateGenericOAuth(genericOAuth *GenericOAuth) (SavedTeam, err
 		UPDATE teams
 		SET genericoauth_auth = $1
 		WHERE LOWER(name) = LOWER($2)
-		RETURNING id, name, admin, basic_auth, github_auth, uaa_auth, genericoauth_auth
+		RETURNING id, name, admin, basic_auth, github_auth, uaa_auth, genericoauth_auth, oidc_auth, saml_auth, roles
 	`
 	params := []interface{}{string(jsonEncodedGenericOAuth), db.teamName}
 	return db.queryTeam(query, params)
 }
 
+func (db *teamDB) UpdateOIDCAuth(oidcAuth *OIDCAuth) (SavedTeam, error) {
+	jsonEncodedOIDCAuth, err := json.Marshal(oidcAuth)
+	if err != nil {
+		return SavedTeam{}, err
+	}
+
+	query := `
+		UPDATE teams
+		SET oidc_auth = $1
+		WHERE LOWER(name) = LOWER($2)
+		RETURNING id, name, admin, basic_auth, github_auth, uaa_auth, genericoauth_auth, oidc_auth, saml_auth, roles
+	`
+	params := []interface{}{string(jsonEncodedOIDCAuth), db.teamName}
+	return db.queryTeam(query, params)
+}
+
+func (db *teamDB) UpdateSAMLAuth(samlAuth *SAMLAuth) (SavedTeam, error) {
+	jsonEncodedSAMLAuth, err := json.Marshal(samlAuth)
+	if err != nil {
+		return SavedTeam{}, err
+	}
+
+	query := `
+		UPDATE teams
+		SET saml_auth = $1
+		WHERE LOWER(name) = LOWER($2)
+		RETURNING id, name, admin, basic_auth, github_auth, uaa_auth, genericoauth_auth, oidc_auth, saml_auth, roles
+	`
+	params := []interface{}{string(jsonEncodedSAMLAuth), db.teamName}
+	return db.queryTeam(query, params)
+}
+
+func (db *teamDB) UpdateRoles(roles atc.TeamRoles) (SavedTeam, error) {
+	jsonEncodedRoles, err := json.Marshal(roles)
+	if err != nil {
+		return SavedTeam{}, err
+	}
+
+	query := `
+		UPDATE teams
+		SET roles = $1
+		WHERE LOWER(name) = LOWER($2)
+		RETURNING id, name, admin, basic_auth, github_auth, uaa_auth, genericoauth_auth, oidc_auth, saml_auth, roles
+	`
+	params := []interface{}{string(jsonEncodedRoles), db.teamName}
+	return db.queryTeam(query, params)
+}
+
 func (db *teamDB) CreateOneOffBuild() (Build, error) {
+	return db.createOneOffBuild(nil)
+}
+
+// CreateRerunBuild creates a one-off build that is recorded as a rerun of the
+// given build. Running it with the original's plan is left to the caller.
+func (db *teamDB) CreateRerunBuild(original Build) (Build, error) {
+	return db.createOneOffBuild(original.ID())
+}
+
+func (db *teamDB) createOneOffBuild(rerunOf interface{}) (Build, error) {
 	tx, err := db.conn.Begin()
 	if err != nil {
 		return nil, err
@@ -605,14 +1079,14 @@ func (db *teamDB) CreateOneOffBuild() (Build, error) {
 	defer tx.Rollback()
 
 	build, _, err := db.buildFactory.ScanBuild(tx.QueryRow(`
-		INSERT INTO builds (name, team_id, status)
-		SELECT nextval('one_off_name'), t.id, 'pending'
+		INSERT INTO builds (name, team_id, status, rerun_of)
+		SELECT nextval('one_off_name'), t.id, 'pending', $2::integer
 		FROM teams t WHERE LOWER(t.name) = LOWER($1)
 		RETURNING `+buildColumns+`, null, null, null,
 		(
 			SELECT name FROM teams WHERE LOWER(name) = LOWER($1)
 		)
-	`, string(db.teamName)))
+	`, string(db.teamName), rerunOf))
 	if err != nil {
 		return nil, err
 	}
@@ -673,17 +1147,33 @@ func (db *teamDB) Workers() ([]SavedWorker, error) {
 	return savedWorkers, nil
 }
 
-func (db *teamDB) GetPrivateAndPublicBuilds(page Page) ([]Build, Pagination, error) {
+func (db *teamDB) GetPrivateAndPublicBuilds(page Page, filter BuildFilter) ([]Build, Pagination, error) {
 	buildsQuery := sq.Select(qualifiedBuildColumns).From("builds b").
 		LeftJoin("jobs j ON b.job_id = j.id").
 		LeftJoin("pipelines p ON j.pipeline_id = p.id").
 		LeftJoin("teams t ON b.team_id = t.id").
 		Where(sq.Or{sq.Eq{"p.public": true}, sq.Eq{"LOWER(t.name)": strings.ToLower(db.teamName)}})
 
-	return getBuildsWithPagination(buildsQuery, page, db.conn, db.buildFactory)
+	buildsQuery = applyBuildFilter(buildsQuery, filter)
+
+	return getBuildsWithPagination(context.Background(), buildsQuery, page, db.conn, db.buildFactory)
+}
+
+// GetBuilds only returns the team's own builds, one-off or otherwise,
+// regardless of whether their pipelines are public.
+func (db *teamDB) GetBuilds(page Page, filter BuildFilter) ([]Build, Pagination, error) {
+	buildsQuery := sq.Select(qualifiedBuildColumns).From("builds b").
+		LeftJoin("jobs j ON b.job_id = j.id").
+		LeftJoin("pipelines p ON j.pipeline_id = p.id").
+		LeftJoin("teams t ON b.team_id = t.id").
+		Where(sq.Eq{"LOWER(t.name)": strings.ToLower(db.teamName)})
+
+	buildsQuery = applyBuildFilter(buildsQuery, filter)
+
+	return getBuildsWithPagination(context.Background(), buildsQuery, page, db.conn, db.buildFactory)
 }
 
-func scanPipeline(rows scannable) (SavedPipeline, error) {
+func scanPipeline(rows scannable, strategy encryption.Strategy) (SavedPipeline, error) {
 	var id int
 	var name string
 	var configBlob []byte
@@ -691,15 +1181,37 @@ func scanPipeline(rows scannable) (SavedPipeline, error) {
 	var paused bool
 	var public bool
 	var teamID int
+	var instanceOf sql.NullInt64
+	var instanceVarsBlob []byte
+	var permissionsBlob []byte
 	var teamName string
 
-	err := rows.Scan(&id, &name, &configBlob, &version, &paused, &teamID, &public, &teamName)
+	err := rows.Scan(&id, &name, &configBlob, &version, &paused, &teamID, &public, &instanceOf, &instanceVarsBlob, &permissionsBlob, &teamName)
+	if err != nil {
+		return SavedPipeline{}, err
+	}
+
+	var permissions atc.PipelinePermissions
+	err = json.Unmarshal(permissionsBlob, &permissions)
+	if err != nil {
+		return SavedPipeline{}, err
+	}
+
+	var instanceVars map[string]interface{}
+	if instanceOf.Valid {
+		err = json.Unmarshal(instanceVarsBlob, &instanceVars)
+		if err != nil {
+			return SavedPipeline{}, err
+		}
+	}
+
+	decrypted, err := strategy.Decrypt(string(configBlob))
 	if err != nil {
 		return SavedPipeline{}, err
 	}
 
 	var config atc.Config
-	err = json.Unmarshal(configBlob, &config)
+	err = json.Unmarshal(decrypted, &config)
 	if err != nil {
 		return SavedPipeline{}, err
 	}
@@ -710,6 +1222,12 @@ func scanPipeline(rows scannable) (SavedPipeline, error) {
 		Public:   public,
 		TeamID:   teamID,
 		TeamName: teamName,
+
+		InstanceOf:   int(instanceOf.Int64),
+		InstanceVars: instanceVars,
+
+		Permissions: permissions,
+
 		Pipeline: Pipeline{
 			Name:    name,
 			Config:  config,
@@ -718,11 +1236,11 @@ func scanPipeline(rows scannable) (SavedPipeline, error) {
 	}, nil
 }
 
-func scanPipelines(rows *sql.Rows) ([]SavedPipeline, error) {
+func scanPipelines(rows *sql.Rows, strategy encryption.Strategy) ([]SavedPipeline, error) {
 	pipelines := []SavedPipeline{}
 
 	for rows.Next() {
-		pipeline, err := scanPipeline(rows)
+		pipeline, err := scanPipeline(rows, strategy)
 		if err != nil {
 			return nil, err
 		}
@@ -165,13 +165,13 @@ var _ = Describe("Updating pipeline config for specific team", func() {
 		})
 
 		It("returns true for created", func() {
-			_, created, err := teamDB.SaveConfig(pipelineName, config, 0, db.PipelineNoChange)
+			_, created, err := teamDB.SaveConfig(pipelineName, config, 0, db.PipelineNoChange, "some-author")
 			Expect(err).NotTo(HaveOccurred())
 			Expect(created).To(BeTrue())
 		})
 
 		It("caches the team id", func() {
-			_, _, err := teamDB.SaveConfig(pipelineName, config, 0, db.PipelineNoChange)
+			_, _, err := teamDB.SaveConfig(pipelineName, config, 0, db.PipelineNoChange, "some-author")
 			Expect(err).NotTo(HaveOccurred())
 
 			pipeline, found, err := teamDB.GetPipelineByName(pipelineName)
@@ -181,7 +181,7 @@ var _ = Describe("Updating pipeline config for specific team", func() {
 		})
 
 		It("can be saved as paused", func() {
-			_, _, err := teamDB.SaveConfig(pipelineName, config, 0, db.PipelinePaused)
+			_, _, err := teamDB.SaveConfig(pipelineName, config, 0, db.PipelinePaused, "some-author")
 			Expect(err).NotTo(HaveOccurred())
 
 			pipeline, found, err := teamDB.GetPipelineByName(pipelineName)
@@ -192,7 +192,7 @@ var _ = Describe("Updating pipeline config for specific team", func() {
 		})
 
 		It("can be saved as unpaused", func() {
-			_, _, err := teamDB.SaveConfig(pipelineName, config, 0, db.PipelineUnpaused)
+			_, _, err := teamDB.SaveConfig(pipelineName, config, 0, db.PipelineUnpaused, "some-author")
 			Expect(err).NotTo(HaveOccurred())
 
 			pipeline, found, err := teamDB.GetPipelineByName(pipelineName)
@@ -203,7 +203,7 @@ var _ = Describe("Updating pipeline config for specific team", func() {
 		})
 
 		It("defaults to paused", func() {
-			_, _, err := teamDB.SaveConfig(pipelineName, config, 0, db.PipelineNoChange)
+			_, _, err := teamDB.SaveConfig(pipelineName, config, 0, db.PipelineNoChange, "some-author")
 			Expect(err).NotTo(HaveOccurred())
 
 			pipeline, found, err := teamDB.GetPipelineByName(pipelineName)
@@ -214,7 +214,7 @@ var _ = Describe("Updating pipeline config for specific team", func() {
 		})
 
 		It("creates all of the resources from the pipeline in the database", func() {
-			savedPipeline, _, err := teamDB.SaveConfig(pipelineName, config, 0, db.PipelineNoChange)
+			savedPipeline, _, err := teamDB.SaveConfig(pipelineName, config, 0, db.PipelineNoChange, "some-author")
 			Expect(err).NotTo(HaveOccurred())
 
 			pipelineDB := pipelineDBFactory.Build(savedPipeline)
@@ -224,7 +224,7 @@ var _ = Describe("Updating pipeline config for specific team", func() {
 		})
 
 		It("creates all of the resource types from the pipeline in the database", func() {
-			savedPipeline, _, err := teamDB.SaveConfig(pipelineName, config, 0, db.PipelineNoChange)
+			savedPipeline, _, err := teamDB.SaveConfig(pipelineName, config, 0, db.PipelineNoChange, "some-author")
 			Expect(err).NotTo(HaveOccurred())
 
 			pipelineDB := pipelineDBFactory.Build(savedPipeline)
@@ -235,7 +235,7 @@ var _ = Describe("Updating pipeline config for specific team", func() {
 		})
 
 		It("creates all of the jobs from the pipeline in the database", func() {
-			savedPipeline, _, err := teamDB.SaveConfig(pipelineName, config, 0, db.PipelineNoChange)
+			savedPipeline, _, err := teamDB.SaveConfig(pipelineName, config, 0, db.PipelineNoChange, "some-author")
 			Expect(err).NotTo(HaveOccurred())
 
 			pipelineDB := pipelineDBFactory.Build(savedPipeline)
@@ -245,7 +245,7 @@ var _ = Describe("Updating pipeline config for specific team", func() {
 		})
 
 		It("creates all of the serial groups from the jobs in the database", func() {
-			savedPipeline, _, err := teamDB.SaveConfig(pipelineName, config, 0, db.PipelineNoChange)
+			savedPipeline, _, err := teamDB.SaveConfig(pipelineName, config, 0, db.PipelineNoChange, "some-author")
 			Expect(err).NotTo(HaveOccurred())
 
 			serialGroups := []SerialGroup{}
@@ -285,19 +285,19 @@ var _ = Describe("Updating pipeline config for specific team", func() {
 		})
 
 		It("it returns created as false", func() {
-			_, _, err := teamDB.SaveConfig(pipelineName, config, 0, db.PipelineNoChange)
+			_, _, err := teamDB.SaveConfig(pipelineName, config, 0, db.PipelineNoChange, "some-author")
 			Expect(err).NotTo(HaveOccurred())
 
 			_, _, configVersion, err := teamDB.GetConfig(pipelineName)
 			Expect(err).NotTo(HaveOccurred())
 
-			_, created, err := teamDB.SaveConfig(pipelineName, config, configVersion, db.PipelineNoChange)
+			_, created, err := teamDB.SaveConfig(pipelineName, config, configVersion, db.PipelineNoChange, "some-author")
 			Expect(err).NotTo(HaveOccurred())
 			Expect(created).To(BeFalse())
 		})
 
 		It("updating from paused to unpaused", func() {
-			_, _, err := teamDB.SaveConfig(pipelineName, config, 0, db.PipelinePaused)
+			_, _, err := teamDB.SaveConfig(pipelineName, config, 0, db.PipelinePaused, "some-author")
 			Expect(err).NotTo(HaveOccurred())
 
 			pipeline, found, err := teamDB.GetPipelineByName(pipelineName)
@@ -308,7 +308,7 @@ var _ = Describe("Updating pipeline config for specific team", func() {
 			_, _, configVersion, err := teamDB.GetConfig(pipelineName)
 			Expect(err).NotTo(HaveOccurred())
 
-			_, _, err = teamDB.SaveConfig(pipelineName, config, configVersion, db.PipelineUnpaused)
+			_, _, err = teamDB.SaveConfig(pipelineName, config, configVersion, db.PipelineUnpaused, "some-author")
 			Expect(err).NotTo(HaveOccurred())
 
 			pipeline, found, err = teamDB.GetPipelineByName(pipelineName)
@@ -318,7 +318,7 @@ var _ = Describe("Updating pipeline config for specific team", func() {
 		})
 
 		It("updating from unpaused to paused", func() {
-			_, _, err := teamDB.SaveConfig(pipelineName, config, 0, db.PipelineUnpaused)
+			_, _, err := teamDB.SaveConfig(pipelineName, config, 0, db.PipelineUnpaused, "some-author")
 			Expect(err).NotTo(HaveOccurred())
 
 			pipeline, found, err := teamDB.GetPipelineByName(pipelineName)
@@ -329,7 +329,7 @@ var _ = Describe("Updating pipeline config for specific team", func() {
 			_, _, configVersion, err := teamDB.GetConfig(pipelineName)
 			Expect(err).NotTo(HaveOccurred())
 
-			_, _, err = teamDB.SaveConfig(pipelineName, config, configVersion, db.PipelinePaused)
+			_, _, err = teamDB.SaveConfig(pipelineName, config, configVersion, db.PipelinePaused, "some-author")
 			Expect(err).NotTo(HaveOccurred())
 
 			pipeline, found, err = teamDB.GetPipelineByName(pipelineName)
@@ -340,7 +340,7 @@ var _ = Describe("Updating pipeline config for specific team", func() {
 
 		Context("updating with no change", func() {
 			It("maintains paused if the pipeline is paused", func() {
-				_, _, err := teamDB.SaveConfig(pipelineName, config, 0, db.PipelinePaused)
+				_, _, err := teamDB.SaveConfig(pipelineName, config, 0, db.PipelinePaused, "some-author")
 				Expect(err).NotTo(HaveOccurred())
 
 				pipeline, found, err := teamDB.GetPipelineByName(pipelineName)
@@ -351,7 +351,7 @@ var _ = Describe("Updating pipeline config for specific team", func() {
 				_, _, configVersion, err := teamDB.GetConfig(pipelineName)
 				Expect(err).NotTo(HaveOccurred())
 
-				_, _, err = teamDB.SaveConfig(pipelineName, config, configVersion, db.PipelineNoChange)
+				_, _, err = teamDB.SaveConfig(pipelineName, config, configVersion, db.PipelineNoChange, "some-author")
 				Expect(err).NotTo(HaveOccurred())
 
 				pipeline, found, err = teamDB.GetPipelineByName(pipelineName)
@@ -361,7 +361,7 @@ var _ = Describe("Updating pipeline config for specific team", func() {
 			})
 
 			It("maintains unpaused if the pipeline is unpaused", func() {
-				_, _, err := teamDB.SaveConfig(pipelineName, config, 0, db.PipelineUnpaused)
+				_, _, err := teamDB.SaveConfig(pipelineName, config, 0, db.PipelineUnpaused, "some-author")
 				Expect(err).NotTo(HaveOccurred())
 
 				pipeline, found, err := teamDB.GetPipelineByName(pipelineName)
@@ -372,7 +372,7 @@ var _ = Describe("Updating pipeline config for specific team", func() {
 				_, _, configVersion, err := teamDB.GetConfig(pipelineName)
 				Expect(err).NotTo(HaveOccurred())
 
-				_, _, err = teamDB.SaveConfig(pipelineName, config, configVersion, db.PipelineNoChange)
+				_, _, err = teamDB.SaveConfig(pipelineName, config, configVersion, db.PipelineNoChange, "some-author")
 				Expect(err).NotTo(HaveOccurred())
 
 				pipeline, found, err = teamDB.GetPipelineByName(pipelineName)
@@ -387,9 +387,9 @@ var _ = Describe("Updating pipeline config for specific team", func() {
 		pipelineName := "a-pipeline-name"
 		otherPipelineName := "an-other-pipeline-name"
 
-		_, _, err := teamDB.SaveConfig(pipelineName, config, 0, db.PipelineUnpaused)
+		_, _, err := teamDB.SaveConfig(pipelineName, config, 0, db.PipelineUnpaused, "some-author")
 		Expect(err).NotTo(HaveOccurred())
-		_, _, err = teamDB.SaveConfig(otherPipelineName, otherConfig, 0, db.PipelineUnpaused)
+		_, _, err = teamDB.SaveConfig(otherPipelineName, otherConfig, 0, db.PipelineUnpaused, "some-author")
 		Expect(err).NotTo(HaveOccurred())
 
 		pipeline, found, err := teamDB.GetPipelineByName(pipelineName)
@@ -408,22 +408,22 @@ var _ = Describe("Updating pipeline config for specific team", func() {
 	})
 
 	It("can order pipelines", func() {
-		_, _, err := teamDB.SaveConfig("some-pipeline", atc.Config{}, db.ConfigVersion(1), db.PipelineUnpaused)
+		_, _, err := teamDB.SaveConfig("some-pipeline", atc.Config{}, db.ConfigVersion(1), db.PipelineUnpaused, "some-author")
 		Expect(err).NotTo(HaveOccurred())
 
-		_, _, err = teamDB.SaveConfig("pipeline-1", config, 0, db.PipelineUnpaused)
+		_, _, err = teamDB.SaveConfig("pipeline-1", config, 0, db.PipelineUnpaused, "some-author")
 		Expect(err).NotTo(HaveOccurred())
 
-		_, _, err = teamDB.SaveConfig("pipeline-2", config, 0, db.PipelineUnpaused)
+		_, _, err = teamDB.SaveConfig("pipeline-2", config, 0, db.PipelineUnpaused, "some-author")
 		Expect(err).NotTo(HaveOccurred())
 
-		_, _, err = teamDB.SaveConfig("pipeline-3", config, 0, db.PipelineUnpaused)
+		_, _, err = teamDB.SaveConfig("pipeline-3", config, 0, db.PipelineUnpaused, "some-author")
 		Expect(err).NotTo(HaveOccurred())
 
-		_, _, err = teamDB.SaveConfig("pipeline-4", config, 0, db.PipelineUnpaused)
+		_, _, err = teamDB.SaveConfig("pipeline-4", config, 0, db.PipelineUnpaused, "some-author")
 		Expect(err).NotTo(HaveOccurred())
 
-		_, _, err = teamDB.SaveConfig("pipeline-5", config, 0, db.PipelineUnpaused)
+		_, _, err = teamDB.SaveConfig("pipeline-5", config, 0, db.PipelineUnpaused, "some-author")
 		Expect(err).NotTo(HaveOccurred())
 
 		err = teamDB.OrderPipelines([]string{
@@ -436,7 +436,7 @@ var _ = Describe("Updating pipeline config for specific team", func() {
 		})
 		Expect(err).NotTo(HaveOccurred())
 
-		_, _, err = teamDB.SaveConfig("pipeline-6", config, 0, db.PipelineUnpaused)
+		_, _, err = teamDB.SaveConfig("pipeline-6", config, 0, db.PipelineUnpaused, "some-author")
 		Expect(err).NotTo(HaveOccurred())
 
 		pipelines, err := teamDB.GetPipelines()
@@ -521,13 +521,13 @@ var _ = Describe("Updating pipeline config for specific team", func() {
 		pipelineName := "a-pipeline-name"
 		otherPipelineName := "an-other-pipeline-name"
 
-		_, _, err := teamDB.SaveConfig("some-pipeline", atc.Config{}, db.ConfigVersion(1), db.PipelineUnpaused)
+		_, _, err := teamDB.SaveConfig("some-pipeline", atc.Config{}, db.ConfigVersion(1), db.PipelineUnpaused, "some-author")
 		Expect(err).NotTo(HaveOccurred())
 
-		_, _, err = teamDB.SaveConfig(pipelineName, config, 0, db.PipelineUnpaused)
+		_, _, err = teamDB.SaveConfig(pipelineName, config, 0, db.PipelineUnpaused, "some-author")
 		Expect(err).NotTo(HaveOccurred())
 
-		_, _, err = teamDB.SaveConfig(otherPipelineName, otherConfig, 0, db.PipelineUnpaused)
+		_, _, err = teamDB.SaveConfig(otherPipelineName, otherConfig, 0, db.PipelineUnpaused, "some-author")
 		Expect(err).NotTo(HaveOccurred())
 
 		err = teamDB.OrderPipelines([]string{
@@ -574,7 +574,7 @@ var _ = Describe("Updating pipeline config for specific team", func() {
 	})
 
 	It("can lookup configs by build id", func() {
-		savedPipeline, _, err := teamDB.SaveConfig("my-pipeline", config, 0, db.PipelineUnpaused)
+		savedPipeline, _, err := teamDB.SaveConfig("my-pipeline", config, 0, db.PipelineUnpaused, "some-author")
 
 		myPipelineDB := pipelineDBFactory.Build(savedPipeline)
 
@@ -598,10 +598,10 @@ var _ = Describe("Updating pipeline config for specific team", func() {
 		Expect(initialOtherConfig).To(BeZero())
 
 		By("being able to save the config")
-		_, _, err = teamDB.SaveConfig(pipelineName, config, 0, db.PipelineUnpaused)
+		_, _, err = teamDB.SaveConfig(pipelineName, config, 0, db.PipelineUnpaused, "some-author")
 		Expect(err).NotTo(HaveOccurred())
 
-		_, _, err = teamDB.SaveConfig(otherPipelineName, otherConfig, 0, db.PipelineUnpaused)
+		_, _, err = teamDB.SaveConfig(otherPipelineName, otherConfig, 0, db.PipelineUnpaused, "some-author")
 		Expect(err).NotTo(HaveOccurred())
 
 		By("returning the saved config to later gets")
@@ -654,22 +654,22 @@ var _ = Describe("Updating pipeline config for specific team", func() {
 		})
 
 		By("not allowing non-sequential updates")
-		_, _, err = teamDB.SaveConfig(pipelineName, updatedConfig, configVersion-1, db.PipelineUnpaused)
+		_, _, err = teamDB.SaveConfig(pipelineName, updatedConfig, configVersion-1, db.PipelineUnpaused, "some-author")
 		Expect(err).To(Equal(db.ErrConfigComparisonFailed))
 
-		_, _, err = teamDB.SaveConfig(pipelineName, updatedConfig, configVersion+10, db.PipelineUnpaused)
+		_, _, err = teamDB.SaveConfig(pipelineName, updatedConfig, configVersion+10, db.PipelineUnpaused, "some-author")
 		Expect(err).To(Equal(db.ErrConfigComparisonFailed))
 
-		_, _, err = teamDB.SaveConfig(otherPipelineName, updatedConfig, otherConfigVersion-1, db.PipelineUnpaused)
+		_, _, err = teamDB.SaveConfig(otherPipelineName, updatedConfig, otherConfigVersion-1, db.PipelineUnpaused, "some-author")
 		Expect(err).To(Equal(db.ErrConfigComparisonFailed))
 
-		_, _, err = teamDB.SaveConfig(otherPipelineName, updatedConfig, otherConfigVersion+10, db.PipelineUnpaused)
+		_, _, err = teamDB.SaveConfig(otherPipelineName, updatedConfig, otherConfigVersion+10, db.PipelineUnpaused, "some-author")
 		Expect(err).To(Equal(db.ErrConfigComparisonFailed))
 
 		By("being able to update the config with a valid con")
-		_, _, err = teamDB.SaveConfig(pipelineName, updatedConfig, configVersion, db.PipelineUnpaused)
+		_, _, err = teamDB.SaveConfig(pipelineName, updatedConfig, configVersion, db.PipelineUnpaused, "some-author")
 		Expect(err).NotTo(HaveOccurred())
-		_, _, err = teamDB.SaveConfig(otherPipelineName, updatedConfig, otherConfigVersion, db.PipelineUnpaused)
+		_, _, err = teamDB.SaveConfig(otherPipelineName, updatedConfig, otherConfigVersion, db.PipelineUnpaused, "some-author")
 		Expect(err).NotTo(HaveOccurred())
 
 		By("returning the updated config")
@@ -689,7 +689,7 @@ var _ = Describe("Updating pipeline config for specific team", func() {
 
 		By("being able to retrieve invalid config")
 		invalidPipelineName := "invalid-config"
-		_, _, err = teamDB.SaveConfig(invalidPipelineName, config, 1, db.PipelineUnpaused)
+		_, _, err = teamDB.SaveConfig(invalidPipelineName, config, 1, db.PipelineUnpaused, "some-author")
 		Expect(err).NotTo(HaveOccurred())
 
 		dbConn.Exec(`
@@ -704,6 +704,446 @@ var _ = Describe("Updating pipeline config for specific team", func() {
 		Expect(invalidConfigVersion).NotTo(Equal(db.ConfigVersion(1)))
 	})
 
+	It("rejects saves from a version that has since been saved over", func() {
+		pipelineName := "a-pipeline-name"
+
+		savedPipeline, _, err := teamDB.SaveConfig(pipelineName, config, 0, db.PipelineUnpaused, "some-author")
+		Expect(err).NotTo(HaveOccurred())
+
+		fetchedVersion := savedPipeline.Version
+
+		savedPipeline, _, err = teamDB.SaveConfig(pipelineName, otherConfig, fetchedVersion, db.PipelinePaused, "some-author")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(savedPipeline.TeamName).To(Equal("some-team"))
+
+		_, _, err = teamDB.SaveConfig(pipelineName, config, fetchedVersion, db.PipelineNoChange, "some-other-author")
+		Expect(err).To(Equal(db.ErrConfigComparisonFailed))
+
+		actualConfig, _, actualVersion, err := teamDB.GetConfig(pipelineName)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(actualConfig).To(Equal(otherConfig))
+		Expect(actualVersion).To(Equal(savedPipeline.Version))
+	})
+
+	Describe("config revisions", func() {
+		var pipelineName string
+		var firstVersion db.ConfigVersion
+		var secondVersion db.ConfigVersion
+
+		BeforeEach(func() {
+			pipelineName = "a-pipeline-name"
+
+			savedPipeline, _, err := teamDB.SaveConfig(pipelineName, config, 0, db.PipelineUnpaused, "team:some-team")
+			Expect(err).NotTo(HaveOccurred())
+			firstVersion = savedPipeline.Version
+
+			savedPipeline, _, err = teamDB.SaveConfig(pipelineName, otherConfig, firstVersion, db.PipelineNoChange, "system")
+			Expect(err).NotTo(HaveOccurred())
+			secondVersion = savedPipeline.Version
+		})
+
+		It("records every saved config, newest first", func() {
+			revisions, found, err := teamDB.GetConfigRevisions(pipelineName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+
+			Expect(revisions).To(HaveLen(2))
+
+			Expect(revisions[0].Version).To(Equal(secondVersion))
+			Expect(revisions[0].Config).To(Equal(otherConfig))
+			Expect(revisions[0].Author).To(Equal("system"))
+			Expect(revisions[0].CreatedAt).To(BeTemporally("~", time.Now(), time.Minute))
+
+			Expect(revisions[1].Version).To(Equal(firstVersion))
+			Expect(revisions[1].Config).To(Equal(config))
+			Expect(revisions[1].Author).To(Equal("team:some-team"))
+		})
+
+		It("does not find revisions of pipelines that don't exist", func() {
+			_, found, err := teamDB.GetConfigRevisions("bogus-pipeline")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+
+		Context("when reverting to an earlier revision", func() {
+			var revertedPipeline db.SavedPipeline
+
+			BeforeEach(func() {
+				var found bool
+				var err error
+				revertedPipeline, found, err = teamDB.RevertConfig(pipelineName, firstVersion, "team:some-team")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+			})
+
+			It("makes its config the current config, as a new version", func() {
+				actualConfig, _, actualVersion, err := teamDB.GetConfig(pipelineName)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(actualConfig).To(Equal(config))
+				Expect(actualVersion).To(Equal(revertedPipeline.Version))
+				Expect(actualVersion).NotTo(Equal(secondVersion))
+			})
+
+			It("records the revert as a revision", func() {
+				revisions, _, err := teamDB.GetConfigRevisions(pipelineName)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(revisions).To(HaveLen(3))
+				Expect(revisions[0].Version).To(Equal(revertedPipeline.Version))
+				Expect(revisions[0].Config).To(Equal(config))
+				Expect(revisions[0].Author).To(Equal("team:some-team"))
+			})
+		})
+
+		It("does not revert to revisions that don't exist", func() {
+			_, found, err := teamDB.RevertConfig(pipelineName, secondVersion+100, "team:some-team")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+
+			actualConfig, _, _, err := teamDB.GetConfig(pipelineName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(actualConfig).To(Equal(otherConfig))
+		})
+
+		It("does not revert to revisions of other teams' pipelines", func() {
+			_, err := database.CreateTeam(db.Team{Name: "some-other-team"})
+			Expect(err).NotTo(HaveOccurred())
+
+			otherTeamDB := teamDBFactory.GetTeamDB("some-other-team")
+			_, found, err := otherTeamDB.RevertConfig(pipelineName, firstVersion, "team:some-other-team")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+	})
+
+	Describe("candidate configs", func() {
+		var pipelineName string
+		var savedPipeline db.SavedPipeline
+
+		BeforeEach(func() {
+			pipelineName = "a-pipeline-name"
+
+			var err error
+			savedPipeline, _, err = teamDB.SaveConfig(pipelineName, config, 0, db.PipelineUnpaused, "team:some-team")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("does not find a candidate before one is saved", func() {
+			_, found, err := teamDB.GetCandidateConfig(pipelineName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+
+		It("does not save candidates for pipelines that don't exist", func() {
+			found, err := teamDB.SaveCandidateConfig("bogus-pipeline", otherConfig, 50, "team:some-team")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+
+		Context("when a candidate is saved", func() {
+			BeforeEach(func() {
+				found, err := teamDB.SaveCandidateConfig(pipelineName, otherConfig, 50, "team:some-team")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+			})
+
+			It("can be looked up, by the team and by its pipeline", func() {
+				candidate, found, err := teamDB.GetCandidateConfig(pipelineName)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+
+				Expect(candidate.Config).To(Equal(otherConfig))
+				Expect(candidate.Percentage).To(Equal(50))
+				Expect(candidate.Author).To(Equal("team:some-team"))
+				Expect(candidate.CreatedAt).To(BeTemporally("~", time.Now(), time.Minute))
+
+				pipelineCandidate, found, err := pipelineDBFactory.Build(savedPipeline).GetCandidateConfig()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(pipelineCandidate).To(Equal(candidate))
+			})
+
+			It("leaves the pipeline's config alone", func() {
+				actualConfig, _, actualVersion, err := teamDB.GetConfig(pipelineName)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(actualConfig).To(Equal(config))
+				Expect(actualVersion).To(Equal(savedPipeline.Version))
+			})
+
+			It("replaces it when another is saved", func() {
+				found, err := teamDB.SaveCandidateConfig(pipelineName, config, 10, "system")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+
+				candidate, _, err := teamDB.GetCandidateConfig(pipelineName)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(candidate.Config).To(Equal(config))
+				Expect(candidate.Percentage).To(Equal(10))
+				Expect(candidate.Author).To(Equal("system"))
+			})
+
+			It("can be deleted", func() {
+				deleted, err := teamDB.DeleteCandidateConfig(pipelineName)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(deleted).To(BeTrue())
+
+				_, found, err := teamDB.GetCandidateConfig(pipelineName)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeFalse())
+
+				deleted, err = teamDB.DeleteCandidateConfig(pipelineName)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(deleted).To(BeFalse())
+			})
+
+			Context("when it's promoted", func() {
+				var promotedPipeline db.SavedPipeline
+
+				BeforeEach(func() {
+					var found bool
+					var err error
+					promotedPipeline, found, err = teamDB.PromoteCandidateConfig(pipelineName, "team:some-team")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(found).To(BeTrue())
+				})
+
+				It("makes its config the current config, as a new version", func() {
+					actualConfig, _, actualVersion, err := teamDB.GetConfig(pipelineName)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(actualConfig).To(Equal(otherConfig))
+					Expect(actualVersion).To(Equal(promotedPipeline.Version))
+					Expect(actualVersion).NotTo(Equal(savedPipeline.Version))
+				})
+
+				It("is no longer a candidate", func() {
+					_, found, err := teamDB.GetCandidateConfig(pipelineName)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(found).To(BeFalse())
+
+					_, found, err = teamDB.PromoteCandidateConfig(pipelineName, "team:some-team")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(found).To(BeFalse())
+				})
+			})
+
+			It("compares the builds finished since it was saved", func() {
+				pipelineDB := pipelineDBFactory.Build(savedPipeline)
+
+				candidate, _, err := pipelineDB.GetCandidateConfig()
+				Expect(err).NotTo(HaveOccurred())
+
+				currentBuild, err := pipelineDB.CreateJobBuild("some-job")
+				Expect(err).NotTo(HaveOccurred())
+
+				started, err := currentBuild.Start("some-engine", "some-metadata")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(started).To(BeTrue())
+
+				err = currentBuild.Finish(db.StatusSucceeded)
+				Expect(err).NotTo(HaveOccurred())
+
+				candidateBuild, err := pipelineDB.CreateJobBuild("some-job")
+				Expect(err).NotTo(HaveOccurred())
+
+				err = pipelineDB.UseCandidateConfigForBuild(candidateBuild.ID(), candidate.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				err = candidateBuild.Finish(db.StatusFailed)
+				Expect(err).NotTo(HaveOccurred())
+
+				pendingBuild, err := pipelineDB.CreateJobBuild("some-job")
+				Expect(err).NotTo(HaveOccurred())
+
+				err = pipelineDB.UseCandidateConfigForBuild(pendingBuild.ID(), candidate.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				comparisons, found, err := teamDB.GetCandidateComparison(pipelineName)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+
+				Expect(comparisons).To(Equal([]db.CandidateComparison{
+					{
+						JobName:   "some-job",
+						Current:   db.CandidateBuildStats{Builds: 1, Succeeded: 1},
+						Candidate: db.CandidateBuildStats{Builds: 1, Failed: 1},
+					},
+				}))
+			})
+
+			It("doesn't count the builds of a candidate it replaced", func() {
+				pipelineDB := pipelineDBFactory.Build(savedPipeline)
+
+				candidate, _, err := pipelineDB.GetCandidateConfig()
+				Expect(err).NotTo(HaveOccurred())
+
+				build, err := pipelineDB.CreateJobBuild("some-job")
+				Expect(err).NotTo(HaveOccurred())
+
+				err = pipelineDB.UseCandidateConfigForBuild(build.ID(), candidate.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				err = build.Finish(db.StatusFailed)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = teamDB.SaveCandidateConfig(pipelineName, otherConfig, 50, "team:some-team")
+				Expect(err).NotTo(HaveOccurred())
+
+				comparisons, found, err := teamDB.GetCandidateComparison(pipelineName)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(comparisons).To(BeEmpty())
+			})
+		})
+
+		It("does not compare pipelines without a candidate", func() {
+			_, found, err := teamDB.GetCandidateComparison(pipelineName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+	})
+
+	Describe("pipeline instances", func() {
+		var template atc.Config
+
+		BeforeEach(func() {
+			template = config
+			template.Resources = atc.ResourceConfigs{
+				{
+					Name: "some-resource",
+					Type: "git",
+					Source: atc.Source{
+						"branch":      "((branch))",
+						"private_key": "((private-key))",
+					},
+				},
+			}
+
+			_, _, err := teamDB.SaveConfig("some-template", template, 0, db.PipelineUnpaused, "team:some-team")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		instanceSource := func(name string) atc.Source {
+			instanceConfig, _, _, err := teamDB.GetConfig(name)
+			Expect(err).NotTo(HaveOccurred())
+			return instanceConfig.Resources[0].Source
+		}
+
+		It("creates a paused pipeline with the template's config filled in with the vars", func() {
+			instance, created, err := teamDB.SavePipelineInstance("some-template", "release-1", map[string]interface{}{"branch": "release/1"}, "team:some-team")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(created).To(BeTrue())
+
+			Expect(instance.Paused).To(BeTrue())
+			Expect(instance.InstanceVars).To(Equal(map[string]interface{}{"branch": "release/1"}))
+
+			Expect(instanceSource("release-1")).To(Equal(atc.Source{
+				"branch":      "release/1",
+				"private_key": "((private-key))",
+			}))
+
+			instances, found, err := teamDB.GetPipelineInstances("some-template")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(instances).To(HaveLen(1))
+			Expect(instances[0].Name).To(Equal("release-1"))
+			Expect(instances[0].InstanceVars).To(Equal(map[string]interface{}{"branch": "release/1"}))
+		})
+
+		It("replaces the vars of an existing instance", func() {
+			_, _, err := teamDB.SavePipelineInstance("some-template", "release-1", map[string]interface{}{"branch": "release/1"}, "team:some-team")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, created, err := teamDB.SavePipelineInstance("some-template", "release-1", map[string]interface{}{"branch": "release/1.1"}, "team:some-team")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(created).To(BeFalse())
+
+			Expect(instanceSource("release-1")["branch"]).To(Equal("release/1.1"))
+		})
+
+		It("keeps instances up to date as the template's config is saved", func() {
+			_, _, err := teamDB.SavePipelineInstance("some-template", "release-1", map[string]interface{}{"branch": "release/1"}, "team:some-team")
+			Expect(err).NotTo(HaveOccurred())
+
+			template.Resources[0].Source = atc.Source{"branch": "((branch))", "depth": 1}
+
+			_, _, version, err := teamDB.GetConfig("some-template")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, _, err = teamDB.SaveConfig("some-template", template, version, db.PipelineNoChange, "team:some-team")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(instanceSource("release-1")).To(Equal(atc.Source{
+				"branch": "release/1",
+				"depth":  float64(1),
+			}))
+		})
+
+		It("doesn't let the config of an instance be saved directly", func() {
+			instance, _, err := teamDB.SavePipelineInstance("some-template", "release-1", map[string]interface{}{"branch": "release/1"}, "team:some-team")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, _, err = teamDB.SaveConfig("release-1", config, instance.Version, db.PipelineNoChange, "team:some-team")
+			Expect(err).To(Equal(db.ErrPipelineIsInstance))
+		})
+
+		It("doesn't create instances of instances", func() {
+			_, _, err := teamDB.SavePipelineInstance("some-template", "release-1", map[string]interface{}{"branch": "release/1"}, "team:some-team")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, _, err = teamDB.SavePipelineInstance("release-1", "release-1-again", nil, "team:some-team")
+			Expect(err).To(Equal(db.ErrPipelineIsInstance))
+		})
+
+		It("doesn't take over pipelines that aren't its instances", func() {
+			_, _, err := teamDB.SaveConfig("some-other-pipeline", config, 0, db.PipelineUnpaused, "team:some-team")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, _, err = teamDB.SavePipelineInstance("some-template", "some-other-pipeline", map[string]interface{}{}, "team:some-team")
+			Expect(err).To(Equal(db.ErrPipelineNameTaken))
+		})
+
+		It("doesn't create instances of templates that don't exist", func() {
+			_, _, err := teamDB.SavePipelineInstance("bogus-template", "release-1", map[string]interface{}{}, "team:some-team")
+			Expect(err).To(Equal(db.ErrPipelineNotFound))
+
+			_, found, err := teamDB.GetPipelineInstances("bogus-template")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+
+		It("destroys instances", func() {
+			_, _, err := teamDB.SavePipelineInstance("some-template", "release-1", map[string]interface{}{"branch": "release/1"}, "team:some-team")
+			Expect(err).NotTo(HaveOccurred())
+
+			destroyed, err := teamDB.DestroyPipelineInstance("some-template", "release-1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(destroyed).To(BeTrue())
+
+			_, found, err := teamDB.GetPipelineByName("release-1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+
+			destroyed, err = teamDB.DestroyPipelineInstance("some-template", "release-1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(destroyed).To(BeFalse())
+		})
+
+		It("destroys the instances along with the template", func() {
+			_, _, err := teamDB.SavePipelineInstance("some-template", "release-1", map[string]interface{}{"branch": "release/1"}, "team:some-team")
+			Expect(err).NotTo(HaveOccurred())
+
+			savedTemplate, _, err := teamDB.GetPipelineByName("some-template")
+			Expect(err).NotTo(HaveOccurred())
+
+			err = pipelineDBFactory.Build(savedTemplate).Destroy()
+			Expect(err).NotTo(HaveOccurred())
+
+			_, found, err := teamDB.GetPipelineByName("release-1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+	})
+
 	Context("when there are multiple teams", func() {
 		var otherTeam db.SavedTeam
 		var otherTeamDB db.TeamDB
@@ -716,11 +1156,11 @@ var _ = Describe("Updating pipeline config for specific team", func() {
 		})
 
 		It("can allow pipelines with the same name across teams", func() {
-			_, _, err := teamDB.SaveConfig("steve", config, 0, db.PipelineUnpaused)
+			_, _, err := teamDB.SaveConfig("steve", config, 0, db.PipelineUnpaused, "some-author")
 			Expect(err).NotTo(HaveOccurred())
 
 			By("allowing you to save a pipeline with the same name in another team")
-			_, _, err = otherTeamDB.SaveConfig("steve", otherConfig, 0, db.PipelineUnpaused)
+			_, _, err = otherTeamDB.SaveConfig("steve", otherConfig, 0, db.PipelineUnpaused, "some-author")
 			Expect(err).NotTo(HaveOccurred())
 
 			By("getting the config for the correct team's pipeline")
@@ -731,10 +1171,10 @@ var _ = Describe("Updating pipeline config for specific team", func() {
 			Expect(actualOtherConfig).To(Equal(otherConfig))
 
 			By("updating the pipeline config for the correct team's pipeline")
-			_, _, err = teamDB.SaveConfig("steve", otherConfig, teamPipelineVersion, db.PipelineNoChange)
+			_, _, err = teamDB.SaveConfig("steve", otherConfig, teamPipelineVersion, db.PipelineNoChange, "some-author")
 			Expect(err).NotTo(HaveOccurred())
 
-			_, _, err = otherTeamDB.SaveConfig("steve", config, otherTeamPipelineVersion, db.PipelineNoChange)
+			_, _, err = otherTeamDB.SaveConfig("steve", config, otherTeamPipelineVersion, db.PipelineNoChange, "some-author")
 			Expect(err).NotTo(HaveOccurred())
 
 			actualOtherConfig, _, teamPipelineVersion, err = teamDB.GetConfig("steve")
@@ -744,7 +1184,7 @@ var _ = Describe("Updating pipeline config for specific team", func() {
 			Expect(actualConfig).To(Equal(config))
 
 			By("pausing the correct team's pipeline")
-			_, _, err = teamDB.SaveConfig("steve", otherConfig, teamPipelineVersion, db.PipelinePaused)
+			_, _, err = teamDB.SaveConfig("steve", otherConfig, teamPipelineVersion, db.PipelinePaused, "some-author")
 			Expect(err).NotTo(HaveOccurred())
 
 			pausedPipeline, found, err := teamDB.GetPipelineByName("steve")
@@ -759,10 +1199,10 @@ var _ = Describe("Updating pipeline config for specific team", func() {
 			Expect(unpausedPipeline.Paused).To(BeFalse())
 
 			By("cannot cross update configs")
-			_, _, err = teamDB.SaveConfig("steve", otherConfig, otherTeamPipelineVersion, db.PipelineNoChange)
+			_, _, err = teamDB.SaveConfig("steve", otherConfig, otherTeamPipelineVersion, db.PipelineNoChange, "some-author")
 			Expect(err).To(HaveOccurred())
 
-			_, _, err = teamDB.SaveConfig("steve", otherConfig, otherTeamPipelineVersion, db.PipelinePaused)
+			_, _, err = teamDB.SaveConfig("steve", otherConfig, otherTeamPipelineVersion, db.PipelinePaused, "some-author")
 			Expect(err).To(HaveOccurred())
 		})
 	})
@@ -159,10 +159,11 @@ func (db *teamDB) GetContainer(handle string) (SavedContainer, bool, error) {
 }
 
 func (db *teamDB) deleteExpiredContainers() error {
-	_, err := db.conn.Exec(`
+	_, err := deleteCountingGC(db.conn, gcContainersDestroyed, `
 		DELETE FROM containers
 		WHERE expires_at IS NOT NULL
 		AND expires_at < NOW()
+		RETURNING worker_name
 	`)
 	if err != nil {
 		return err
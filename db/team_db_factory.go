@@ -8,11 +8,11 @@ type TeamDBFactory interface {
 
 type teamDBFactory struct {
 	conn        Conn
-	bus         *notificationsBus
+	bus         NotificationsBus
 	lockFactory LockFactory
 }
 
-func NewTeamDBFactory(conn Conn, bus *notificationsBus, lockFactory LockFactory) TeamDBFactory {
+func NewTeamDBFactory(conn Conn, bus NotificationsBus, lockFactory LockFactory) TeamDBFactory {
 	return &teamDBFactory{
 		conn:        conn,
 		bus:         bus,
@@ -75,10 +75,10 @@ var _ = Describe("TeamDB", func() {
 		var savedPipeline db.SavedPipeline
 		BeforeEach(func() {
 			var err error
-			savedPipeline, _, err = teamDB.SaveConfig("pipeline-name", atc.Config{}, 0, db.PipelineUnpaused)
+			savedPipeline, _, err = teamDB.SaveConfig("pipeline-name", atc.Config{}, 0, db.PipelineUnpaused, "some-author")
 			Expect(err).NotTo(HaveOccurred())
 
-			_, _, err = otherTeamDB.SaveConfig("pipeline-name", atc.Config{}, 0, db.PipelineUnpaused)
+			_, _, err = otherTeamDB.SaveConfig("pipeline-name", atc.Config{}, 0, db.PipelineUnpaused, "some-author")
 			Expect(err).NotTo(HaveOccurred())
 		})
 
@@ -96,16 +96,16 @@ var _ = Describe("TeamDB", func() {
 
 		BeforeEach(func() {
 			var err error
-			savedPipeline1, _, err = teamDB.SaveConfig("pipeline-name-a", atc.Config{}, 0, db.PipelineUnpaused)
+			savedPipeline1, _, err = teamDB.SaveConfig("pipeline-name-a", atc.Config{}, 0, db.PipelineUnpaused, "some-author")
 			Expect(err).NotTo(HaveOccurred())
 
-			savedPipeline2, _, err = teamDB.SaveConfig("pipeline-name-b", atc.Config{}, 0, db.PipelineUnpaused)
+			savedPipeline2, _, err = teamDB.SaveConfig("pipeline-name-b", atc.Config{}, 0, db.PipelineUnpaused, "some-author")
 			Expect(err).NotTo(HaveOccurred())
 
-			otherSavedPublicPipeline, _, err := otherTeamDB.SaveConfig("other-team-pipeline-name-a", atc.Config{}, 0, db.PipelineUnpaused)
+			otherSavedPublicPipeline, _, err := otherTeamDB.SaveConfig("other-team-pipeline-name-a", atc.Config{}, 0, db.PipelineUnpaused, "some-author")
 			Expect(err).NotTo(HaveOccurred())
 
-			_, _, err = otherTeamDB.SaveConfig("other-team-pipeline-name-b", atc.Config{}, 0, db.PipelineUnpaused)
+			_, _, err = otherTeamDB.SaveConfig("other-team-pipeline-name-b", atc.Config{}, 0, db.PipelineUnpaused, "some-author")
 			Expect(err).NotTo(HaveOccurred())
 
 			pipelineDB := pipelineDBFactory.Build(otherSavedPublicPipeline)
@@ -127,10 +127,10 @@ var _ = Describe("TeamDB", func() {
 
 		BeforeEach(func() {
 			var err error
-			privatePipeline, _, err = teamDB.SaveConfig("private-pipeline", atc.Config{}, 0, db.PipelineUnpaused)
+			privatePipeline, _, err = teamDB.SaveConfig("private-pipeline", atc.Config{}, 0, db.PipelineUnpaused, "some-author")
 			Expect(err).NotTo(HaveOccurred())
 
-			publicPipeline, _, err = teamDB.SaveConfig("public-pipeline", atc.Config{}, 0, db.PipelineUnpaused)
+			publicPipeline, _, err = teamDB.SaveConfig("public-pipeline", atc.Config{}, 0, db.PipelineUnpaused, "some-author")
 			Expect(err).NotTo(HaveOccurred())
 
 			pipelineDB := pipelineDBFactory.Build(publicPipeline)
@@ -158,22 +158,22 @@ var _ = Describe("TeamDB", func() {
 		var otherSavedPublicPipeline3 db.SavedPipeline
 		BeforeEach(func() {
 			var err error
-			savedPipeline1, _, err = teamDB.SaveConfig("pipeline-name-a", atc.Config{}, 0, db.PipelineUnpaused)
+			savedPipeline1, _, err = teamDB.SaveConfig("pipeline-name-a", atc.Config{}, 0, db.PipelineUnpaused, "some-author")
 			Expect(err).NotTo(HaveOccurred())
 
-			savedPipeline2, _, err = teamDB.SaveConfig("pipeline-name-b", atc.Config{}, 0, db.PipelineUnpaused)
+			savedPipeline2, _, err = teamDB.SaveConfig("pipeline-name-b", atc.Config{}, 0, db.PipelineUnpaused, "some-author")
 			Expect(err).NotTo(HaveOccurred())
 
-			savedPipeline3, _, err = teamDB.SaveConfig("pipeline-name-c", atc.Config{}, 0, db.PipelineUnpaused)
+			savedPipeline3, _, err = teamDB.SaveConfig("pipeline-name-c", atc.Config{}, 0, db.PipelineUnpaused, "some-author")
 			Expect(err).NotTo(HaveOccurred())
 
-			otherSavedPublicPipeline1, _, err = otherTeamDB.SaveConfig("other-team-pipeline-name-a", atc.Config{}, 0, db.PipelineUnpaused)
+			otherSavedPublicPipeline1, _, err = otherTeamDB.SaveConfig("other-team-pipeline-name-a", atc.Config{}, 0, db.PipelineUnpaused, "some-author")
 			Expect(err).NotTo(HaveOccurred())
 
-			otherSavedPublicPipeline2, _, err = otherTeamDB.SaveConfig("other-team-pipeline-name-b", atc.Config{}, 0, db.PipelineUnpaused)
+			otherSavedPublicPipeline2, _, err = otherTeamDB.SaveConfig("other-team-pipeline-name-b", atc.Config{}, 0, db.PipelineUnpaused, "some-author")
 			Expect(err).NotTo(HaveOccurred())
 
-			otherSavedPublicPipeline3, _, err = otherTeamDB.SaveConfig("other-team-pipeline-name-c", atc.Config{}, 0, db.PipelineUnpaused)
+			otherSavedPublicPipeline3, _, err = otherTeamDB.SaveConfig("other-team-pipeline-name-c", atc.Config{}, 0, db.PipelineUnpaused, "some-author")
 			Expect(err).NotTo(HaveOccurred())
 
 			pipelineDB1 := pipelineDBFactory.Build(savedPipeline1)
@@ -229,6 +229,99 @@ var _ = Describe("TeamDB", func() {
 
 	})
 
+	Describe("GetPipelineDashboards", func() {
+		var (
+			ownPipeline         db.SavedPipeline
+			otherPublicPipeline db.SavedPipeline
+
+			finishedBuild db.Build
+			runningBuild  db.Build
+		)
+
+		BeforeEach(func() {
+			var err error
+			ownPipeline, _, err = teamDB.SaveConfig("own-pipeline", atc.Config{
+				Jobs: atc.JobConfigs{
+					{Name: "some-job"},
+					{Name: "idle-job"},
+				},
+			}, 0, db.PipelineUnpaused, "some-author")
+			Expect(err).NotTo(HaveOccurred())
+
+			otherPublicPipeline, _, err = otherTeamDB.SaveConfig("other-public-pipeline", atc.Config{
+				Jobs: atc.JobConfigs{
+					{Name: "other-job"},
+				},
+			}, 0, db.PipelineUnpaused, "some-author")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, _, err = otherTeamDB.SaveConfig("other-private-pipeline", atc.Config{}, 0, db.PipelineUnpaused, "some-author")
+			Expect(err).NotTo(HaveOccurred())
+
+			err = pipelineDBFactory.Build(otherPublicPipeline).Expose()
+			Expect(err).NotTo(HaveOccurred())
+
+			pipelineDB := pipelineDBFactory.Build(ownPipeline)
+
+			err = pipelineDB.PauseJob("idle-job")
+			Expect(err).NotTo(HaveOccurred())
+
+			olderFinishedBuild, err := pipelineDB.CreateJobBuild("some-job")
+			Expect(err).NotTo(HaveOccurred())
+
+			err = olderFinishedBuild.Finish(db.StatusFailed)
+			Expect(err).NotTo(HaveOccurred())
+
+			finishedBuild, err = pipelineDB.CreateJobBuild("some-job")
+			Expect(err).NotTo(HaveOccurred())
+
+			err = finishedBuild.Finish(db.StatusSucceeded)
+			Expect(err).NotTo(HaveOccurred())
+
+			runningBuild, err = pipelineDB.CreateJobBuild("some-job")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = runningBuild.Start("engine", "metadata")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = pipelineDB.CreateJobBuild("some-job")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns the team's pipelines and other teams' public ones, with the state of their jobs", func() {
+			dashboards, err := teamDB.GetPipelineDashboards()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dashboards).To(HaveLen(2))
+
+			Expect(dashboards[0].Pipeline.Name).To(Equal("own-pipeline"))
+			Expect(dashboards[0].Jobs).To(HaveLen(2))
+
+			someJob := dashboards[0].Jobs[0]
+			Expect(someJob.Job.Name).To(Equal("some-job"))
+			Expect(someJob.JobConfig).To(Equal(atc.JobConfig{Name: "some-job"}))
+			Expect(someJob.FinishedBuild.ID()).To(Equal(finishedBuild.ID()))
+			Expect(someJob.RunningBuilds).To(HaveLen(1))
+			Expect(someJob.RunningBuilds[0].ID()).To(Equal(runningBuild.ID()))
+
+			idleJob := dashboards[0].Jobs[1]
+			Expect(idleJob.Job.Name).To(Equal("idle-job"))
+			Expect(idleJob.Job.Paused).To(BeTrue())
+			Expect(idleJob.FinishedBuild).To(BeNil())
+			Expect(idleJob.RunningBuilds).To(BeEmpty())
+
+			Expect(dashboards[1].Pipeline.Name).To(Equal("other-public-pipeline"))
+			Expect(dashboards[1].Jobs).To(HaveLen(1))
+			Expect(dashboards[1].Jobs[0].Job.Name).To(Equal("other-job"))
+		})
+
+		It("returns only public pipelines when not asked for a team's", func() {
+			dashboards, err := database.(db.PipelinesDB).GetPublicPipelineDashboards()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dashboards).To(HaveLen(1))
+			Expect(dashboards[0].Pipeline.Name).To(Equal("other-public-pipeline"))
+		})
+	})
+
 	Describe("OrderPipelines", func() {
 		var savedPipeline1 db.SavedPipeline
 		var savedPipeline2 db.SavedPipeline
@@ -237,14 +330,14 @@ var _ = Describe("TeamDB", func() {
 
 		BeforeEach(func() {
 			var err error
-			savedPipeline1, _, err = teamDB.SaveConfig("pipeline-name-a", atc.Config{}, 0, db.PipelineUnpaused)
+			savedPipeline1, _, err = teamDB.SaveConfig("pipeline-name-a", atc.Config{}, 0, db.PipelineUnpaused, "some-author")
 			Expect(err).NotTo(HaveOccurred())
-			savedPipeline2, _, err = teamDB.SaveConfig("pipeline-name-b", atc.Config{}, 0, db.PipelineUnpaused)
+			savedPipeline2, _, err = teamDB.SaveConfig("pipeline-name-b", atc.Config{}, 0, db.PipelineUnpaused, "some-author")
 			Expect(err).NotTo(HaveOccurred())
 
-			otherTeamSavedPipeline1, _, err = otherTeamDB.SaveConfig("pipeline-name-a", atc.Config{}, 0, db.PipelineUnpaused)
+			otherTeamSavedPipeline1, _, err = otherTeamDB.SaveConfig("pipeline-name-a", atc.Config{}, 0, db.PipelineUnpaused, "some-author")
 			Expect(err).NotTo(HaveOccurred())
-			otherTeamSavedPipeline2, _, err = otherTeamDB.SaveConfig("pipeline-name-b", atc.Config{}, 0, db.PipelineUnpaused)
+			otherTeamSavedPipeline2, _, err = otherTeamDB.SaveConfig("pipeline-name-b", atc.Config{}, 0, db.PipelineUnpaused, "some-author")
 			Expect(err).NotTo(HaveOccurred())
 		})
 
@@ -447,6 +540,64 @@ var _ = Describe("TeamDB", func() {
 				Expect(savedTeam.GenericOAuth).To(Equal(genericOAuth))
 			})
 		})
+
+		Describe("UpdateOIDCAuth", func() {
+			It("saves oidc auth info to the existing team", func() {
+				oidcAuth := &db.OIDCAuth{
+					DisplayName:  "Google",
+					Issuer:       "https://accounts.google.com",
+					ClientID:     "some-client-id",
+					ClientSecret: "some-client-secret",
+					Groups:       []string{"some-group"},
+					Users:        []string{"someone@example.com"},
+				}
+
+				savedTeam, err := teamDB.UpdateOIDCAuth(oidcAuth)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(savedTeam.OIDCAuth).To(Equal(oidcAuth))
+			})
+		})
+
+		Describe("UpdateSAMLAuth", func() {
+			It("saves saml auth info to the existing team", func() {
+				samlAuth := &db.SAMLAuth{
+					DisplayName:     "Okta",
+					SSOURL:          "https://okta.example.com/sso",
+					IdPEntityID:     "https://okta.example.com",
+					IdPCertificate:  "some-certificate",
+					GroupsAttribute: "groups",
+					Groups:          []string{"some-group"},
+					Users:           []string{"someone@example.com"},
+				}
+
+				savedTeam, err := teamDB.UpdateSAMLAuth(samlAuth)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(savedTeam.SAMLAuth).To(Equal(samlAuth))
+
+				team, found, err := teamDB.GetTeam()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(team.SAMLAuth).To(Equal(samlAuth))
+			})
+		})
+
+		Describe("UpdateRoles", func() {
+			It("saves the roles to the existing team", func() {
+				roles := atc.TeamRoles{
+					GitHubAuth: atc.RoleViewer,
+					UAAAuth:    atc.RoleOperator,
+				}
+
+				savedTeam, err := teamDB.UpdateRoles(roles)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(savedTeam.Roles).To(Equal(roles))
+
+				team, found, err := teamDB.GetTeam()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(team.Roles).To(Equal(roles))
+			})
+		})
 	})
 
 	Describe("GetTeam", func() {
@@ -464,6 +615,75 @@ var _ = Describe("TeamDB", func() {
 		})
 	})
 
+	Describe("SAML requests", func() {
+		BeforeEach(func() {
+			err := teamDB.SaveSAMLRequest("_some-request", "/some-path", time.Now().Add(time.Minute))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("can be taken once", func() {
+			redirect, found, err := teamDB.TakeSAMLRequest("_some-request")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(redirect).To(Equal("/some-path"))
+
+			_, found, err = teamDB.TakeSAMLRequest("_some-request")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+
+		It("can't be taken by another team", func() {
+			_, found, err := otherTeamDB.TakeSAMLRequest("_some-request")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+
+			_, found, err = teamDB.TakeSAMLRequest("_some-request")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+		})
+
+		It("can't be taken once expired", func() {
+			err := teamDB.SaveSAMLRequest("_expired-request", "/some-path", time.Now().Add(-time.Second))
+			Expect(err).NotTo(HaveOccurred())
+
+			_, found, err := teamDB.TakeSAMLRequest("_expired-request")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+
+		It("can't be taken if never made", func() {
+			_, found, err := teamDB.TakeSAMLRequest("_bogus-request")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+	})
+
+	Describe("SaveSAMLAssertion", func() {
+		It("only saves each assertion once, across teams", func() {
+			saved, err := teamDB.SaveSAMLAssertion("_some-assertion", time.Now().Add(time.Minute))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(saved).To(BeTrue())
+
+			saved, err = teamDB.SaveSAMLAssertion("_some-assertion", time.Now().Add(time.Minute))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(saved).To(BeFalse())
+
+			saved, err = otherTeamDB.SaveSAMLAssertion("_some-assertion", time.Now().Add(time.Minute))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(saved).To(BeFalse())
+		})
+
+		It("forgets assertions once they've expired", func() {
+			saved, err := teamDB.SaveSAMLAssertion("_some-assertion", time.Now().Add(-time.Second))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(saved).To(BeTrue())
+
+			saved, err = teamDB.SaveSAMLAssertion("_some-assertion", time.Now().Add(time.Minute))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(saved).To(BeTrue())
+		})
+	})
+
 	Describe("CreateOneOffBuild", func() {
 		var (
 			oneOffBuild db.Build
@@ -485,6 +705,36 @@ var _ = Describe("TeamDB", func() {
 			Expect(nextOneOffBuild.TeamName()).To(Equal(savedTeam.Name))
 			Expect(nextOneOffBuild.Status()).To(Equal(db.StatusPending))
 		})
+
+		It("is not a rerun", func() {
+			Expect(oneOffBuild.RerunOf()).To(BeZero())
+		})
+	})
+
+	Describe("CreateRerunBuild", func() {
+		var original db.Build
+
+		BeforeEach(func() {
+			var err error
+			original, err = teamDB.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("creates a pending one-off build that links to the original", func() {
+			rerun, err := teamDB.CreateRerunBuild(original)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(rerun.ID()).NotTo(Equal(original.ID()))
+			Expect(rerun.JobName()).To(BeZero())
+			Expect(rerun.TeamName()).To(Equal(savedTeam.Name))
+			Expect(rerun.Status()).To(Equal(db.StatusPending))
+			Expect(rerun.RerunOf()).To(Equal(original.ID()))
+
+			reloaded, found, err := database.GetBuildByID(rerun.ID())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(reloaded.RerunOf()).To(Equal(original.ID()))
+		})
 	})
 
 	Describe("Workers", func() {
@@ -588,7 +838,7 @@ var _ = Describe("TeamDB", func() {
 	Describe("GetPrivateAndPublicBuilds", func() {
 		Context("when there are no builds", func() {
 			It("returns an empty list of builds", func() {
-				builds, pagination, err := teamDB.GetPrivateAndPublicBuilds(db.Page{Limit: 2})
+				builds, pagination, err := teamDB.GetPrivateAndPublicBuilds(db.Page{Limit: 2}, db.BuildFilter{})
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(pagination.Next).To(BeNil())
@@ -616,7 +866,7 @@ var _ = Describe("TeamDB", func() {
 						},
 					},
 				}
-				pipeline, _, err := teamDB.SaveConfig("some-pipeline", config, db.ConfigVersion(1), db.PipelineUnpaused)
+				pipeline, _, err := teamDB.SaveConfig("some-pipeline", config, db.ConfigVersion(1), db.PipelineUnpaused, "some-author")
 				Expect(err).NotTo(HaveOccurred())
 
 				pipelineDB = pipelineDBFactory.Build(pipeline)
@@ -629,8 +879,43 @@ var _ = Describe("TeamDB", func() {
 				}
 			})
 
+			It("returns only builds with all of the labels when filtering", func() {
+				err := allBuilds[1].SaveLabels(map[string]string{"env": "staging", "user": "some-user"})
+				Expect(err).NotTo(HaveOccurred())
+
+				err = allBuilds[3].SaveLabels(map[string]string{"env": "staging"})
+				Expect(err).NotTo(HaveOccurred())
+
+				builds, _, err := teamDB.GetPrivateAndPublicBuilds(db.Page{Limit: 10}, db.BuildFilter{
+					Labels: map[string]string{"env": "staging"},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(builds).To(HaveLen(2))
+				Expect(builds[0].ID()).To(Equal(allBuilds[3].ID()))
+				Expect(builds[1].ID()).To(Equal(allBuilds[1].ID()))
+				Expect(builds[1].Labels()).To(Equal(map[string]string{"env": "staging", "user": "some-user"}))
+
+				builds, _, err = teamDB.GetPrivateAndPublicBuilds(db.Page{Limit: 10}, db.BuildFilter{
+					Labels: map[string]string{"env": "staging", "user": "some-user"},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(builds).To(HaveLen(1))
+				Expect(builds[0].ID()).To(Equal(allBuilds[1].ID()))
+			})
+
+			It("leaves out the builds of hidden pipelines", func() {
+				builds, _, err := teamDB.GetPrivateAndPublicBuilds(db.Page{Limit: 10}, db.BuildFilter{
+					HiddenPipelineIDs: []int{pipelineDB.GetPipelineID()},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(builds).To(HaveLen(3))
+				Expect(builds[0].ID()).To(Equal(allBuilds[2].ID()))
+				Expect(builds[1].ID()).To(Equal(allBuilds[1].ID()))
+				Expect(builds[2].ID()).To(Equal(allBuilds[0].ID()))
+			})
+
 			It("returns all team builds with correct pagination", func() {
-				builds, pagination, err := teamDB.GetPrivateAndPublicBuilds(db.Page{Limit: 2})
+				builds, pagination, err := teamDB.GetPrivateAndPublicBuilds(db.Page{Limit: 2}, db.BuildFilter{})
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(len(builds)).To(Equal(2))
@@ -640,7 +925,7 @@ var _ = Describe("TeamDB", func() {
 				Expect(pagination.Previous).To(BeNil())
 				Expect(pagination.Next).To(Equal(&db.Page{Since: allBuilds[3].ID(), Limit: 2}))
 
-				builds, pagination, err = teamDB.GetPrivateAndPublicBuilds(*pagination.Next)
+				builds, pagination, err = teamDB.GetPrivateAndPublicBuilds(*pagination.Next, db.BuildFilter{})
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(len(builds)).To(Equal(2))
@@ -651,7 +936,7 @@ var _ = Describe("TeamDB", func() {
 				Expect(pagination.Previous).To(Equal(&db.Page{Until: allBuilds[2].ID(), Limit: 2}))
 				Expect(pagination.Next).To(Equal(&db.Page{Since: allBuilds[1].ID(), Limit: 2}))
 
-				builds, pagination, err = teamDB.GetPrivateAndPublicBuilds(*pagination.Next)
+				builds, pagination, err = teamDB.GetPrivateAndPublicBuilds(*pagination.Next, db.BuildFilter{})
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(len(builds)).To(Equal(1))
@@ -660,7 +945,7 @@ var _ = Describe("TeamDB", func() {
 				Expect(pagination.Previous).To(Equal(&db.Page{Until: allBuilds[0].ID(), Limit: 2}))
 				Expect(pagination.Next).To(BeNil())
 
-				builds, pagination, err = teamDB.GetPrivateAndPublicBuilds(*pagination.Previous)
+				builds, pagination, err = teamDB.GetPrivateAndPublicBuilds(*pagination.Previous, db.BuildFilter{})
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(len(builds)).To(Equal(2))
@@ -699,13 +984,13 @@ var _ = Describe("TeamDB", func() {
 
 				Context("when other team builds are private", func() {
 					It("returns only builds for requested team", func() {
-						builds, _, err := caseInsensitiveTeamADB.GetPrivateAndPublicBuilds(db.Page{Limit: 10})
+						builds, _, err := caseInsensitiveTeamADB.GetPrivateAndPublicBuilds(db.Page{Limit: 10}, db.BuildFilter{})
 						Expect(err).NotTo(HaveOccurred())
 
 						Expect(len(builds)).To(Equal(3))
 						Expect(builds).To(ConsistOf(teamABuilds))
 
-						builds, _, err = caseInsensitiveTeamBDB.GetPrivateAndPublicBuilds(db.Page{Limit: 10})
+						builds, _, err = caseInsensitiveTeamBDB.GetPrivateAndPublicBuilds(db.Page{Limit: 10}, db.BuildFilter{})
 						Expect(err).NotTo(HaveOccurred())
 
 						Expect(len(builds)).To(Equal(3))
@@ -719,7 +1004,7 @@ var _ = Describe("TeamDB", func() {
 					})
 
 					It("returns builds for requested team and public builds", func() {
-						builds, _, err := caseInsensitiveTeamADB.GetPrivateAndPublicBuilds(db.Page{Limit: 10})
+						builds, _, err := caseInsensitiveTeamADB.GetPrivateAndPublicBuilds(db.Page{Limit: 10}, db.BuildFilter{})
 						Expect(err).NotTo(HaveOccurred())
 
 						Expect(builds).To(HaveLen(5))
@@ -736,4 +1021,78 @@ var _ = Describe("TeamDB", func() {
 			})
 		})
 	})
+
+	Describe("GetBuilds", func() {
+		var teamABuilds [2]db.Build
+		var teamBBuilds [2]db.Build
+
+		var teamADB db.TeamDB
+		var teamBDB db.TeamDB
+
+		BeforeEach(func() {
+			_, err := database.CreateTeam(db.Team{Name: "team-a"})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = database.CreateTeam(db.Team{Name: "team-b"})
+			Expect(err).NotTo(HaveOccurred())
+
+			teamADB = teamDBFactory.GetTeamDB("team-A")
+			teamBDB = teamDBFactory.GetTeamDB("team-b")
+
+			for i := 0; i < 2; i++ {
+				teamABuilds[i], err = teamADB.CreateOneOffBuild()
+				Expect(err).NotTo(HaveOccurred())
+
+				teamBBuilds[i], err = teamBDB.CreateOneOffBuild()
+				Expect(err).NotTo(HaveOccurred())
+			}
+		})
+
+		It("returns only the team's own builds", func() {
+			builds, pagination, err := teamADB.GetBuilds(db.Page{Limit: 10}, db.BuildFilter{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(builds).To(Equal([]db.Build{teamABuilds[1], teamABuilds[0]}))
+			Expect(pagination.Next).To(BeNil())
+			Expect(pagination.Previous).To(BeNil())
+		})
+
+		Context("when another team's pipeline is public", func() {
+			var publicBuild db.Build
+
+			BeforeEach(func() {
+				pipeline, _, err := teamBDB.SaveConfig("some-pipeline", atc.Config{
+					Jobs: atc.JobConfigs{{Name: "some-job"}},
+				}, db.ConfigVersion(1), db.PipelineUnpaused, "some-author")
+				Expect(err).NotTo(HaveOccurred())
+
+				pipelineDB := pipelineDBFactory.Build(pipeline)
+				Expect(pipelineDB.Expose()).To(Succeed())
+
+				publicBuild, err = pipelineDB.CreateJobBuild("some-job")
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("does not include its builds", func() {
+				builds, _, err := teamADB.GetBuilds(db.Page{Limit: 10}, db.BuildFilter{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(builds).To(HaveLen(2))
+				Expect(builds).NotTo(ContainElement(publicBuild))
+			})
+		})
+
+		It("applies the label filter", func() {
+			err := teamABuilds[0].SaveLabels(map[string]string{"env": "staging"})
+			Expect(err).NotTo(HaveOccurred())
+
+			builds, _, err := teamADB.GetBuilds(db.Page{Limit: 10}, db.BuildFilter{
+				Labels: map[string]string{"env": "staging"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(builds).To(HaveLen(1))
+			Expect(builds[0].ID()).To(Equal(teamABuilds[0].ID()))
+		})
+	})
 })
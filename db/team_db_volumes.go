@@ -51,10 +51,11 @@ func (db *teamDB) GetVolumes() ([]SavedVolume, error) {
 }
 
 func (db *teamDB) expireVolumes() error {
-	_, err := db.conn.Exec(`
+	_, err := deleteCountingGC(db.conn, gcVolumesDestroyed, `
 		DELETE FROM volumes
 		WHERE expires_at IS NOT NULL
 		AND expires_at < NOW()
+		RETURNING worker_name
 	`)
 	return err
 }
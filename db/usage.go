@@ -0,0 +1,39 @@
+package db
+
+import "time"
+
+type UsageInterval string
+
+const (
+	UsageIntervalDay   UsageInterval = "day"
+	UsageIntervalWeek  UsageInterval = "week"
+	UsageIntervalMonth UsageInterval = "month"
+)
+
+// UsageFilter picks the builds to account for by when they finished, and how
+// to group them over time. Intervals start at midnight UTC, and weeks start
+// on Monday.
+type UsageFilter struct {
+	Since    time.Time
+	Until    time.Time
+	Interval UsageInterval
+
+	TeamName string
+}
+
+// Usage is what the builds of a pipeline, or a team's one-off builds, used
+// over an interval. WorkerTime counts the time spent running each step in a
+// container on a worker, so steps run in parallel add up, and memory is
+// weighted by how long each build held on to its peak.
+type Usage struct {
+	TeamName     string
+	PipelineName string
+	Start        time.Time
+
+	Builds     int
+	BuildTime  time.Duration
+	WorkerTime time.Duration
+	CPUTime    time.Duration
+
+	MemoryByteSeconds float64
+}
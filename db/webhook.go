@@ -0,0 +1,16 @@
+package db
+
+// Webhook triggers a build of a job when a correctly signed request is made
+// to it. Its ID is chosen by the team that owns it, and is unique across all
+// teams.
+type Webhook struct {
+	ID string
+
+	TeamID       int
+	TeamName     string
+	PipelineID   int
+	PipelineName string
+	JobName      string
+
+	Secret string
+}
@@ -233,6 +233,16 @@ func (build *execBuild) buildDependentGetStep(logger lager.Logger, plan atc.Plan
 	)
 }
 
+func (build *execBuild) buildApprovalStep(logger lager.Logger, plan atc.Plan) exec.StepFactory {
+	logger = logger.Session("approval", lager.Data{
+		"name": plan.Approval.Name,
+	})
+
+	return exec.Approval(
+		build.delegate.ApprovalDelegate(logger, *plan.Approval, event.OriginID(plan.ID)),
+	)
+}
+
 func (build *execBuild) buildRetryStep(logger lager.Logger, plan atc.Plan) exec.StepFactory {
 	logger = logger.Session("retry")
 
@@ -4,21 +4,25 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"sync"
 	"time"
 
 	"code.cloudfoundry.org/lager"
 	"github.com/concourse/atc"
 	"github.com/concourse/atc/db"
 	"github.com/concourse/atc/metric"
+	"github.com/concourse/atc/notify"
 )
 
 var ErrBuildNotActive = errors.New("build not yet active")
 
 const trackLeaseDuration = time.Minute
 
-func NewDBEngine(engines Engines) Engine {
+func NewDBEngine(engines Engines, notifier notify.Notifier, handoff *Handoff) Engine {
 	return &dbEngine{
-		engines: engines,
+		engines:  engines,
+		notifier: notifier,
+		handoff:  handoff,
 	}
 }
 
@@ -31,7 +35,9 @@ func (err UnknownEngineError) Error() string {
 }
 
 type dbEngine struct {
-	engines Engines
+	engines  Engines
+	notifier notify.Notifier
+	handoff  *Handoff
 }
 
 func (*dbEngine) Name() string {
@@ -53,24 +59,36 @@ func (engine *dbEngine) CreateBuild(logger lager.Logger, build db.Build, plan at
 
 	if !started {
 		createdBuild.Abort(logger.Session("aborted-immediately"))
+	} else {
+		notifyStatusChanged(logger, engine.notifier, build)
 	}
 
-	return &dbBuild{
-		engines: engine.engines,
-		build:   build,
-	}, nil
+	return engine.newBuild(build), nil
 }
 
 func (engine *dbEngine) LookupBuild(logger lager.Logger, build db.Build) (Build, error) {
+	return engine.newBuild(build), nil
+}
+
+func (engine *dbEngine) newBuild(build db.Build) *dbBuild {
 	return &dbBuild{
-		engines: engine.engines,
-		build:   build,
-	}, nil
+		engines:  engine.engines,
+		notifier: engine.notifier,
+		handoff:  engine.handoff,
+		build:    build,
+
+		released: make(chan struct{}),
+	}
 }
 
 type dbBuild struct {
-	engines Engines
-	build   db.Build
+	engines  Engines
+	notifier notify.Notifier
+	handoff  *Handoff
+	build    db.Build
+
+	released    chan struct{}
+	releaseOnce sync.Once
 }
 
 func (build *dbBuild) Metadata() string {
@@ -93,6 +111,22 @@ func (build *dbBuild) PublicPlan(logger lager.Logger) (atc.PublicBuildPlan, erro
 	return engineBuild.PublicPlan(logger)
 }
 
+func (build *dbBuild) Plan(logger lager.Logger) (atc.Plan, error) {
+	buildEngineName := build.build.Engine()
+	buildEngine, found := build.engines.Lookup(buildEngineName)
+	if !found {
+		logger.Error("unknown-engine", nil, lager.Data{"engine": buildEngineName})
+		return atc.Plan{}, UnknownEngineError{buildEngineName}
+	}
+
+	engineBuild, err := buildEngine.LookupBuild(logger, build.build)
+	if err != nil {
+		return atc.Plan{}, err
+	}
+
+	return engineBuild.Plan(logger)
+}
+
 func (build *dbBuild) Abort(logger lager.Logger) error {
 	// the order below is very important to avoid races with build creation.
 
@@ -142,7 +176,14 @@ func (build *dbBuild) Abort(logger lager.Logger) error {
 		// finish the build so that the aborted event is put into the event stream
 		// even if the build has not started yet
 		logger.Info("finishing-build-with-no-engine")
-		return build.build.Finish(db.StatusAborted)
+		err := build.build.Finish(db.StatusAborted)
+		if err != nil {
+			return err
+		}
+
+		notifyStatusChanged(logger, build.notifier, build.build)
+
+		return nil
 	}
 
 	buildEngine, found := build.engines.Lookup(buildEngineName)
@@ -163,6 +204,13 @@ func (build *dbBuild) Abort(logger lager.Logger) error {
 }
 
 func (build *dbBuild) Resume(logger lager.Logger) {
+	if !build.handoff.track() {
+		logger.Info("draining")
+		return
+	}
+
+	defer build.handoff.untrack()
+
 	lock, acquired, err := build.build.AcquireTrackingLock(logger, trackLeaseDuration)
 	if err != nil {
 		logger.Error("failed-to-get-lock", err)
@@ -227,6 +275,12 @@ func (build *dbBuild) Resume(logger lager.Logger) {
 	done := make(chan struct{})
 	defer close(done)
 
+	handingOff := make(chan struct{})
+	release := func() {
+		close(handingOff)
+		engineBuild.Release(logger)
+	}
+
 	go func() {
 		select {
 		case <-aborts.Notify():
@@ -236,6 +290,10 @@ func (build *dbBuild) Resume(logger lager.Logger) {
 			if err != nil {
 				logger.Error("failed-to-abort", err)
 			}
+		case <-build.handoff.drain:
+			release()
+		case <-build.released:
+			release()
 		case <-done:
 		}
 	}()
@@ -265,6 +323,28 @@ func (build *dbBuild) Resume(logger lager.Logger) {
 		return
 	}
 
+	select {
+	case <-handingOff:
+		// it may have finished before being released
+		if build.build.IsRunning() {
+			logger.Info("handing-off")
+
+			// hand off only once the lock is released, so that whoever picks
+			// it up can acquire it
+			lock.AfterRelease(func() error {
+				err := build.build.HandOff()
+				if err != nil {
+					logger.Error("failed-to-hand-off", err)
+				}
+
+				return err
+			})
+
+			return
+		}
+	default:
+	}
+
 	metric.BuildFinished{
 		PipelineName:  build.build.PipelineName(),
 		JobName:       build.build.JobName(),
@@ -273,6 +353,31 @@ func (build *dbBuild) Resume(logger lager.Logger) {
 		BuildStatus:   build.build.Status(),
 		BuildDuration: build.build.EndTime().Sub(build.build.StartTime()),
 	}.Emit(logger)
+
+	build.notifier.BuildStatusChanged(logger, build.build)
+}
+
+// Release hands the build off to be resumed elsewhere, if it's being resumed.
+func (build *dbBuild) Release(lager.Logger) {
+	build.releaseOnce.Do(func() {
+		close(build.released)
+	})
+}
+
+// notifyStatusChanged reloads the build first, as the status it was loaded
+// with is stale once it has been transitioned.
+func notifyStatusChanged(logger lager.Logger, notifier notify.Notifier, build db.Build) {
+	found, err := build.Reload()
+	if err != nil {
+		logger.Error("failed-to-reload-build-for-notification", err)
+		return
+	}
+
+	if !found {
+		return
+	}
+
+	notifier.BuildStatusChanged(logger, build)
 }
 
 func (build *dbBuild) finishWithError(logger lager.Logger) {
@@ -15,6 +15,7 @@ import (
 	"github.com/concourse/atc/db/dbfakes"
 	. "github.com/concourse/atc/engine"
 	"github.com/concourse/atc/engine/enginefakes"
+	"github.com/concourse/atc/notify/notifyfakes"
 )
 
 var _ = Describe("DBEngine", func() {
@@ -25,6 +26,10 @@ var _ = Describe("DBEngine", func() {
 		fakeEngineB *enginefakes.FakeEngine
 		dbBuild     *dbfakes.FakeBuild
 
+		fakeStatusNotifier *notifyfakes.FakeNotifier
+
+		drain chan struct{}
+
 		dbEngine Engine
 	)
 
@@ -40,7 +45,11 @@ var _ = Describe("DBEngine", func() {
 		dbBuild = new(dbfakes.FakeBuild)
 		dbBuild.IDReturns(128)
 
-		dbEngine = NewDBEngine(Engines{fakeEngineA, fakeEngineB})
+		fakeStatusNotifier = new(notifyfakes.FakeNotifier)
+
+		drain = make(chan struct{})
+
+		dbEngine = NewDBEngine(Engines{fakeEngineA, fakeEngineB}, fakeStatusNotifier, NewHandoff(drain))
 	})
 
 	Describe("CreateBuild", func() {
@@ -105,6 +114,20 @@ var _ = Describe("DBEngine", func() {
 				Expect(metadata).To(Equal("some-metadata"))
 			})
 
+			Context("when the build is still in the database", func() {
+				BeforeEach(func() {
+					dbBuild.ReloadReturns(true, nil)
+				})
+
+				It("notifies that the build started, with its status reloaded", func() {
+					Expect(dbBuild.ReloadCallCount()).To(Equal(1))
+
+					Expect(fakeStatusNotifier.BuildStatusChangedCallCount()).To(Equal(1))
+					_, notifiedBuild := fakeStatusNotifier.BuildStatusChangedArgsForCall(0)
+					Expect(notifiedBuild).To(Equal(dbBuild))
+				})
+			})
+
 			Context("when the build fails to transition to started", func() {
 				BeforeEach(func() {
 					dbBuild.StartReturns(false, nil)
@@ -113,6 +136,10 @@ var _ = Describe("DBEngine", func() {
 				It("aborts the build", func() {
 					Expect(fakeBuild.AbortCallCount()).To(Equal(1))
 				})
+
+				It("does not notify", func() {
+					Expect(fakeStatusNotifier.BuildStatusChangedCallCount()).To(BeZero())
+				})
 			})
 		})
 
@@ -364,6 +391,10 @@ var _ = Describe("DBEngine", func() {
 						Expect(status).To(Equal(db.StatusAborted))
 					})
 
+					It("notifies that the build was aborted", func() {
+						Expect(fakeStatusNotifier.BuildStatusChangedCallCount()).To(Equal(1))
+					})
+
 					It("releases the lock", func() {
 						Expect(fakeLease.BreakCallCount()).To(Equal(1))
 					})
@@ -513,6 +544,12 @@ var _ = Describe("DBEngine", func() {
 								Expect(realBuild.ResumeCallCount()).To(Equal(1))
 							})
 
+							It("notifies once the build has finished", func() {
+								Expect(fakeStatusNotifier.BuildStatusChangedCallCount()).To(Equal(1))
+								_, notifiedBuild := fakeStatusNotifier.BuildStatusChangedArgsForCall(0)
+								Expect(notifiedBuild).To(Equal(dbBuild))
+							})
+
 							It("releases the lock", func() {
 								Expect(fakeLease.BreakCallCount()).To(Equal(1))
 							})
@@ -551,6 +588,65 @@ var _ = Describe("DBEngine", func() {
 									Expect(notifier.CloseCallCount()).To(Equal(1))
 								})
 							})
+
+							Context("when the ATC starts draining while the build is running", func() {
+								var (
+									fakeLock *dbfakes.FakeLock
+									released chan struct{}
+								)
+
+								BeforeEach(func() {
+									fakeLock = new(dbfakes.FakeLock)
+									dbBuild.AcquireTrackingLockReturns(fakeLock, true, nil)
+
+									released = make(chan struct{})
+
+									realBuild.ReleaseStub = func(lager.Logger) {
+										close(released)
+									}
+
+									realBuild.ResumeStub = func(lager.Logger) {
+										close(drain)
+										<-released
+									}
+								})
+
+								It("releases the build", func() {
+									Expect(realBuild.ReleaseCallCount()).To(Equal(1))
+								})
+
+								It("hands the build off once the lock is released", func() {
+									Expect(fakeLock.ReleaseCallCount()).To(Equal(1))
+									Expect(fakeLock.AfterReleaseCallCount()).To(Equal(1))
+									Expect(dbBuild.HandOffCallCount()).To(BeZero())
+
+									handOff := fakeLock.AfterReleaseArgsForCall(0)
+									Expect(handOff()).To(Succeed())
+									Expect(dbBuild.HandOffCallCount()).To(Equal(1))
+								})
+
+								It("does not notify", func() {
+									Expect(fakeStatusNotifier.BuildStatusChangedCallCount()).To(BeZero())
+								})
+
+								Context("when the build finished before it was released", func() {
+									BeforeEach(func() {
+										realBuild.ResumeStub = func(lager.Logger) {
+											dbBuild.IsRunningReturns(false)
+											close(drain)
+											<-released
+										}
+									})
+
+									It("does not hand the build off", func() {
+										Expect(fakeLock.AfterReleaseCallCount()).To(BeZero())
+									})
+
+									It("notifies once the build has finished", func() {
+										Expect(fakeStatusNotifier.BuildStatusChangedCallCount()).To(Equal(1))
+									})
+								})
+							})
 						})
 
 						Context("when listening for aborts fails", func() {
@@ -650,6 +746,16 @@ var _ = Describe("DBEngine", func() {
 				})
 			})
 
+			Context("when the ATC is draining", func() {
+				BeforeEach(func() {
+					close(drain)
+				})
+
+				It("does not try to track the build", func() {
+					Expect(dbBuild.AcquireTrackingLockCallCount()).To(BeZero())
+				})
+			})
+
 			Context("when acquiring the lock fails", func() {
 				BeforeEach(func() {
 					dbBuild.AcquireTrackingLockReturns(nil, false, errors.New("no lock for you"))
@@ -779,5 +885,61 @@ var _ = Describe("DBEngine", func() {
 				})
 			})
 		})
+
+		Describe("Plan", func() {
+			var logger lager.Logger
+
+			var plan atc.Plan
+			var planErr error
+
+			BeforeEach(func() {
+				logger = lagertest.NewTestLogger("test")
+			})
+
+			JustBeforeEach(func() {
+				plan, planErr = build.Plan(logger)
+			})
+
+			Context("when the engine build exists", func() {
+				var realBuild *enginefakes.FakeBuild
+
+				BeforeEach(func() {
+					dbBuild.EngineReturns("fake-engine-b")
+
+					realBuild = new(enginefakes.FakeBuild)
+					fakeEngineB.LookupBuildReturns(realBuild, nil)
+
+					realBuild.PlanReturns(atc.Plan{ID: "some-plan"}, nil)
+				})
+
+				It("returns the plan from the engine", func() {
+					Expect(planErr).NotTo(HaveOccurred())
+					Expect(plan).To(Equal(atc.Plan{ID: "some-plan"}))
+				})
+			})
+
+			Context("when looking up the engine build fails", func() {
+				disaster := errors.New("nope")
+
+				BeforeEach(func() {
+					dbBuild.EngineReturns("fake-engine-b")
+					fakeEngineB.LookupBuildReturns(nil, disaster)
+				})
+
+				It("returns the error", func() {
+					Expect(planErr).To(Equal(disaster))
+				})
+			})
+
+			Context("when the build's engine is unknown", func() {
+				BeforeEach(func() {
+					dbBuild.EngineReturns("bogus")
+				})
+
+				It("returns an UnknownEngineError", func() {
+					Expect(planErr).To(Equal(UnknownEngineError{"bogus"}))
+				})
+			})
+		})
 	})
 })
@@ -1,6 +1,8 @@
 package engine
 
 import (
+	"errors"
+
 	"code.cloudfoundry.org/lager"
 	"github.com/concourse/atc"
 	"github.com/concourse/atc/db"
@@ -22,10 +24,21 @@ type Build interface {
 
 	PublicPlan(lager.Logger) (atc.PublicBuildPlan, error)
 
+	// Plan is the full plan the build was created with, for running it again.
+	Plan(lager.Logger) (atc.Plan, error)
+
 	Abort(lager.Logger) error
 	Resume(lager.Logger)
+
+	// Release makes Resume return without stopping the build, so that it can
+	// be resumed by another ATC.
+	Release(lager.Logger)
 }
 
+// ErrPlanUnavailable is returned for builds whose engine did not keep the
+// plan they were created with.
+var ErrPlanUnavailable = errors.New("build plan is not available")
+
 type Engines []Engine
 
 func (engines Engines) Lookup(name string) (Engine, bool) {
@@ -38,6 +38,20 @@ type FakeBuild struct {
 	resumeArgsForCall []struct {
 		arg1 lager.Logger
 	}
+	PlanStub        func(arg1 lager.Logger) (atc.Plan, error)
+	planMutex       sync.RWMutex
+	planArgsForCall []struct {
+		arg1 lager.Logger
+	}
+	planReturns struct {
+		result1 atc.Plan
+		result2 error
+	}
+	ReleaseStub        func(arg1 lager.Logger)
+	releaseMutex       sync.RWMutex
+	releaseArgsForCall []struct {
+		arg1 lager.Logger
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -158,6 +172,64 @@ func (fake *FakeBuild) ResumeArgsForCall(i int) lager.Logger {
 	return fake.resumeArgsForCall[i].arg1
 }
 
+func (fake *FakeBuild) Plan(arg1 lager.Logger) (atc.Plan, error) {
+	fake.planMutex.Lock()
+	fake.planArgsForCall = append(fake.planArgsForCall, struct {
+		arg1 lager.Logger
+	}{arg1})
+	fake.recordInvocation("Plan", []interface{}{arg1})
+	fake.planMutex.Unlock()
+	if fake.PlanStub != nil {
+		return fake.PlanStub(arg1)
+	} else {
+		return fake.planReturns.result1, fake.planReturns.result2
+	}
+}
+
+func (fake *FakeBuild) PlanCallCount() int {
+	fake.planMutex.RLock()
+	defer fake.planMutex.RUnlock()
+	return len(fake.planArgsForCall)
+}
+
+func (fake *FakeBuild) PlanArgsForCall(i int) lager.Logger {
+	fake.planMutex.RLock()
+	defer fake.planMutex.RUnlock()
+	return fake.planArgsForCall[i].arg1
+}
+
+func (fake *FakeBuild) PlanReturns(result1 atc.Plan, result2 error) {
+	fake.PlanStub = nil
+	fake.planReturns = struct {
+		result1 atc.Plan
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuild) Release(arg1 lager.Logger) {
+	fake.releaseMutex.Lock()
+	fake.releaseArgsForCall = append(fake.releaseArgsForCall, struct {
+		arg1 lager.Logger
+	}{arg1})
+	fake.recordInvocation("Release", []interface{}{arg1})
+	fake.releaseMutex.Unlock()
+	if fake.ReleaseStub != nil {
+		fake.ReleaseStub(arg1)
+	}
+}
+
+func (fake *FakeBuild) ReleaseCallCount() int {
+	fake.releaseMutex.RLock()
+	defer fake.releaseMutex.RUnlock()
+	return len(fake.releaseArgsForCall)
+}
+
+func (fake *FakeBuild) ReleaseArgsForCall(i int) lager.Logger {
+	fake.releaseMutex.RLock()
+	defer fake.releaseMutex.RUnlock()
+	return fake.releaseArgsForCall[i].arg1
+}
+
 func (fake *FakeBuild) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -169,6 +241,10 @@ func (fake *FakeBuild) Invocations() map[string][][]interface{} {
 	defer fake.abortMutex.RUnlock()
 	fake.resumeMutex.RLock()
 	defer fake.resumeMutex.RUnlock()
+	fake.planMutex.RLock()
+	defer fake.planMutex.RUnlock()
+	fake.releaseMutex.RLock()
+	defer fake.releaseMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -50,6 +50,16 @@ type FakeBuildDelegate struct {
 		arg3 exec.Success
 		arg4 bool
 	}
+	ApprovalDelegateStub        func(arg1 lager.Logger, arg2 atc.ApprovalPlan, arg3 event.OriginID) exec.ApprovalDelegate
+	approvalDelegateMutex       sync.RWMutex
+	approvalDelegateArgsForCall []struct {
+		arg1 lager.Logger
+		arg2 atc.ApprovalPlan
+		arg3 event.OriginID
+	}
+	approvalDelegateReturns struct {
+		result1 exec.ApprovalDelegate
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -186,6 +196,41 @@ func (fake *FakeBuildDelegate) FinishArgsForCall(i int) (lager.Logger, error, ex
 	return fake.finishArgsForCall[i].arg1, fake.finishArgsForCall[i].arg2, fake.finishArgsForCall[i].arg3, fake.finishArgsForCall[i].arg4
 }
 
+func (fake *FakeBuildDelegate) ApprovalDelegate(arg1 lager.Logger, arg2 atc.ApprovalPlan, arg3 event.OriginID) exec.ApprovalDelegate {
+	fake.approvalDelegateMutex.Lock()
+	fake.approvalDelegateArgsForCall = append(fake.approvalDelegateArgsForCall, struct {
+		arg1 lager.Logger
+		arg2 atc.ApprovalPlan
+		arg3 event.OriginID
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("ApprovalDelegate", []interface{}{arg1, arg2, arg3})
+	fake.approvalDelegateMutex.Unlock()
+	if fake.ApprovalDelegateStub != nil {
+		return fake.ApprovalDelegateStub(arg1, arg2, arg3)
+	} else {
+		return fake.approvalDelegateReturns.result1
+	}
+}
+
+func (fake *FakeBuildDelegate) ApprovalDelegateCallCount() int {
+	fake.approvalDelegateMutex.RLock()
+	defer fake.approvalDelegateMutex.RUnlock()
+	return len(fake.approvalDelegateArgsForCall)
+}
+
+func (fake *FakeBuildDelegate) ApprovalDelegateArgsForCall(i int) (lager.Logger, atc.ApprovalPlan, event.OriginID) {
+	fake.approvalDelegateMutex.RLock()
+	defer fake.approvalDelegateMutex.RUnlock()
+	return fake.approvalDelegateArgsForCall[i].arg1, fake.approvalDelegateArgsForCall[i].arg2, fake.approvalDelegateArgsForCall[i].arg3
+}
+
+func (fake *FakeBuildDelegate) ApprovalDelegateReturns(result1 exec.ApprovalDelegate) {
+	fake.ApprovalDelegateStub = nil
+	fake.approvalDelegateReturns = struct {
+		result1 exec.ApprovalDelegate
+	}{result1}
+}
+
 func (fake *FakeBuildDelegate) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -197,6 +242,8 @@ func (fake *FakeBuildDelegate) Invocations() map[string][][]interface{} {
 	defer fake.outputDelegateMutex.RUnlock()
 	fake.finishMutex.RLock()
 	defer fake.finishMutex.RUnlock()
+	fake.approvalDelegateMutex.RLock()
+	defer fake.approvalDelegateMutex.RUnlock()
 	return fake.invocations
 }
 
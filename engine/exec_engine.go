@@ -1,9 +1,12 @@
 package engine
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
+	"sync"
 	"time"
 
 	"os"
@@ -12,6 +15,7 @@ import (
 	"github.com/concourse/atc"
 	"github.com/concourse/atc/db"
 	"github.com/concourse/atc/exec"
+	"github.com/concourse/atc/tracing"
 	"github.com/concourse/atc/worker"
 	"github.com/tedsuo/ifrit"
 )
@@ -62,7 +66,8 @@ func (engine *execEngine) CreateBuild(logger lager.Logger, build db.Build, plan
 			Plan: plan,
 		},
 
-		signals: make(chan os.Signal, 1),
+		signals:  make(chan os.Signal, 1),
+		released: make(chan struct{}),
 
 		containerSuccessTTL: successTTL,
 		containerFailureTTL: failureTTL,
@@ -92,7 +97,8 @@ func (engine *execEngine) LookupBuild(logger lager.Logger, build db.Build) (Buil
 		delegate: engine.delegateFactory.Delegate(build),
 		metadata: metadata,
 
-		signals: make(chan os.Signal, 1),
+		signals:  make(chan os.Signal, 1),
+		released: make(chan struct{}),
 
 		containerSuccessTTL: successTTL,
 		containerFailureTTL: failureTTL,
@@ -169,6 +175,9 @@ type execBuild struct {
 
 	signals chan os.Signal
 
+	released    chan struct{}
+	releaseOnce sync.Once
+
 	metadata execMetadata
 
 	containerSuccessTTL time.Duration
@@ -191,17 +200,27 @@ func (build *execBuild) PublicPlan(lager.Logger) (atc.PublicBuildPlan, error) {
 	}, nil
 }
 
+func (build *execBuild) Plan(lager.Logger) (atc.Plan, error) {
+	return build.metadata.Plan, nil
+}
+
 func (build *execBuild) Abort(lager.Logger) error {
 	build.signals <- os.Kill
 	return nil
 }
 
 func (build *execBuild) Resume(logger lager.Logger) {
+	_, span := tracing.StartSpan(context.Background(), "build", tracing.Attrs{
+		"build.id":      strconv.Itoa(build.buildID),
+		"build.name":    build.stepMetadata.BuildName,
+		"job.name":      build.stepMetadata.JobName,
+		"pipeline.name": build.stepMetadata.PipelineName,
+		"team.name":     build.teamName,
+	})
+
 	stepFactory := build.buildStepFactory(logger, build.metadata.Plan)
 	source := stepFactory.Using(&exec.NoopStep{}, exec.NewSourceRepository())
 
-	defer source.Release()
-
 	process := ifrit.Background(source)
 
 	exited := process.Wait()
@@ -220,6 +239,12 @@ func (build *execBuild) Resume(logger lager.Logger) {
 			}
 
 			build.delegate.Finish(logger.Session("finish"), err, succeeded, aborted)
+			source.Release()
+
+			span.SetAttribute("build.succeeded", strconv.FormatBool(bool(succeeded)))
+			span.SetAttribute("build.aborted", strconv.FormatBool(aborted))
+			span.Finish(err)
+
 			return
 
 		case sig := <-build.signals:
@@ -228,10 +253,26 @@ func (build *execBuild) Resume(logger lager.Logger) {
 			if sig == os.Kill {
 				aborted = true
 			}
+
+		case <-build.released:
+			// the steps carry on until the ATC exits; their containers are
+			// left alone for whoever resumes the build to attach to
+			logger.Info("released")
+
+			span.SetAttribute("build.released", "true")
+			span.Finish(nil)
+
+			return
 		}
 	}
 }
 
+func (build *execBuild) Release(lager.Logger) {
+	build.releaseOnce.Do(func() {
+		close(build.released)
+	})
+}
+
 func (build *execBuild) buildStepFactory(logger lager.Logger, plan atc.Plan) exec.StepFactory {
 	if plan.Aggregate != nil {
 		return build.buildAggregateStep(logger, plan)
@@ -281,6 +322,10 @@ func (build *execBuild) buildStepFactory(logger lager.Logger, plan atc.Plan) exe
 		return build.buildRetryStep(logger, plan)
 	}
 
+	if plan.Approval != nil {
+		return build.buildApprovalStep(logger, plan)
+	}
+
 	return exec.Identity{}
 }
 
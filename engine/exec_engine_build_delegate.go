@@ -25,6 +25,7 @@ type BuildDelegate interface {
 	InputDelegate(lager.Logger, atc.GetPlan, event.OriginID) exec.GetDelegate
 	ExecutionDelegate(lager.Logger, atc.TaskPlan, event.OriginID) exec.TaskDelegate
 	OutputDelegate(lager.Logger, atc.PutPlan, event.OriginID) exec.PutDelegate
+	ApprovalDelegate(lager.Logger, atc.ApprovalPlan, event.OriginID) exec.ApprovalDelegate
 
 	Finish(lager.Logger, error, exec.Success, bool)
 }
@@ -35,14 +36,21 @@ type BuildDelegateFactory interface {
 	Delegate(db.Build) BuildDelegate
 }
 
-type buildDelegateFactory struct{}
+type buildDelegateFactory struct {
+	maxLogBytes int64
+}
 
-func NewBuildDelegateFactory() BuildDelegateFactory {
-	return buildDelegateFactory{}
+// NewBuildDelegateFactory constructs a factory whose delegates stop saving a
+// build's log output once it exceeds maxLogBytes. A limit of 0 means the
+// output is never truncated.
+func NewBuildDelegateFactory(maxLogBytes int64) BuildDelegateFactory {
+	return buildDelegateFactory{
+		maxLogBytes: maxLogBytes,
+	}
 }
 
 func (factory buildDelegateFactory) Delegate(build db.Build) BuildDelegate {
-	return newBuildDelegate(build)
+	return newBuildDelegate(build, factory.maxLogBytes)
 }
 
 type delegate struct {
@@ -50,14 +58,21 @@ type delegate struct {
 
 	implicitOutputs map[string]implicitOutput
 
+	logLimit *logLimit
+
 	lock sync.Mutex
 }
 
-func newBuildDelegate(build db.Build) BuildDelegate {
+func newBuildDelegate(build db.Build, maxLogBytes int64) BuildDelegate {
 	return &delegate{
 		build: build,
 
 		implicitOutputs: make(map[string]implicitOutput),
+
+		logLimit: &logLimit{
+			max:       maxLogBytes,
+			truncated: build.LogTruncated(),
+		},
 	}
 }
 
@@ -91,6 +106,16 @@ func (delegate *delegate) ExecutionDelegate(logger lager.Logger, plan atc.TaskPl
 	}
 }
 
+func (delegate *delegate) ApprovalDelegate(logger lager.Logger, plan atc.ApprovalPlan, id event.OriginID) exec.ApprovalDelegate {
+	return &approvalDelegate{
+		logger: logger,
+
+		id:       id,
+		plan:     plan,
+		delegate: delegate,
+	}
+}
+
 func (delegate *delegate) Finish(logger lager.Logger, err error, succeeded exec.Success, aborted bool) {
 	if aborted {
 		delegate.saveStatus(logger, atc.StatusAborted)
@@ -297,6 +322,7 @@ func (delegate *delegate) eventWriter(origin event.Origin) io.Writer {
 	return &dbEventWriter{
 		build:  delegate.build,
 		origin: origin,
+		limit:  delegate.logLimit,
 	}
 }
 
@@ -441,6 +467,16 @@ func (execution *executionDelegate) Failed(err error) {
 	execution.logger.Info("errored", lager.Data{"error": err.Error()})
 }
 
+func (execution *executionDelegate) ResourceUsageMeasured(usage db.ResourceUsage) {
+	err := execution.delegate.build.SaveResourceUsage(usage)
+	if err != nil {
+		execution.logger.Error("failed-to-save-resource-usage", err)
+		return
+	}
+
+	execution.logger.Debug("resource-usage-measured", lager.Data{"usage": usage})
+}
+
 func (execution *executionDelegate) ImageVersionDetermined(identifier worker.VolumeIdentifier) error {
 	return execution.delegate.build.SaveImageResourceVersion(atc.PlanID(execution.id), *identifier.ResourceCache)
 }
@@ -459,6 +495,46 @@ func (execution *executionDelegate) Stderr() io.Writer {
 	})
 }
 
+type approvalDelegate struct {
+	logger lager.Logger
+
+	plan atc.ApprovalPlan
+	id   event.OriginID
+
+	delegate *delegate
+}
+
+func (approval *approvalDelegate) Requested() (db.Notifier, error) {
+	err := approval.delegate.build.RequestApproval(atc.PlanID(approval.id), approval.plan.Name)
+	if err != nil {
+		approval.logger.Error("failed-to-request-approval", err)
+		return nil, err
+	}
+
+	approval.logger.Info("requested")
+
+	return approval.delegate.build.ApprovalNotifier(approval.plan.Name)
+}
+
+func (approval *approvalDelegate) Decision() (bool, bool, error) {
+	saved, found, err := approval.delegate.build.GetApproval(approval.plan.Name)
+	if err != nil {
+		approval.logger.Error("failed-to-get-approval", err)
+		return false, false, err
+	}
+
+	if !found || saved.Status == db.ApprovalPending {
+		return false, false, nil
+	}
+
+	approval.logger.Info("decided", lager.Data{
+		"status":   saved.Status,
+		"approver": saved.Approver,
+	})
+
+	return saved.Status == db.ApprovalApproved, true, nil
+}
+
 type dbEventWriter struct {
 	buildID    int
 	pipelineID int
@@ -466,6 +542,7 @@ type dbEventWriter struct {
 	build db.Build
 
 	origin event.Origin
+	limit  *logLimit
 
 	dangling []byte
 }
@@ -481,6 +558,26 @@ func (writer *dbEventWriter) Write(data []byte) (int, error) {
 
 	writer.dangling = nil
 
+	allowed, exceeded := writer.limit.admit(len(text))
+	if exceeded {
+		err := writer.build.SaveEvent(event.LogTruncated{
+			Origin:   writer.origin,
+			MaxBytes: writer.limit.max,
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		err = writer.build.MarkLogTruncated()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if !allowed {
+		return len(data), nil
+	}
+
 	err := writer.build.SaveEvent(event.Log{
 		Payload: string(text),
 		Origin:  writer.origin,
@@ -492,6 +589,41 @@ func (writer *dbEventWriter) Write(data []byte) (int, error) {
 	return len(data), nil
 }
 
+// logLimit tracks the log output saved for a build across all of its
+// stdout and stderr writers.
+type logLimit struct {
+	max int64
+
+	written   int64
+	truncated bool
+
+	lock sync.Mutex
+}
+
+// admit accounts for n more bytes of output. It reports whether they may be
+// saved, and whether this write is the one that crossed the limit.
+func (limit *logLimit) admit(n int) (bool, bool) {
+	if limit.max <= 0 {
+		return true, false
+	}
+
+	limit.lock.Lock()
+	defer limit.lock.Unlock()
+
+	if limit.truncated {
+		return false, false
+	}
+
+	if limit.written+int64(n) > limit.max {
+		limit.truncated = true
+		return false, true
+	}
+
+	limit.written += int64(n)
+
+	return true, false
+}
+
 func vrFromInput(plan atc.GetPlan, fetchedInfo exec.VersionInfo) db.VersionedResource {
 	return db.VersionedResource{
 		Resource:   plan.Resource,
@@ -32,7 +32,7 @@ var _ = Describe("BuildDelegate", func() {
 	)
 
 	BeforeEach(func() {
-		factory = NewBuildDelegateFactory()
+		factory = NewBuildDelegateFactory(0)
 
 		fakeBuild = new(dbfakes.FakeBuild)
 		delegate = factory.Delegate(fakeBuild)
@@ -42,6 +42,90 @@ var _ = Describe("BuildDelegate", func() {
 		originID = event.OriginID("some-origin-id")
 	})
 
+	Describe("log output limits", func() {
+		var (
+			stdout io.Writer
+			stderr io.Writer
+		)
+
+		BeforeEach(func() {
+			factory = NewBuildDelegateFactory(10)
+			delegate = factory.Delegate(fakeBuild)
+
+			executionDelegate := delegate.ExecutionDelegate(logger, atc.TaskPlan{Name: "some-task"}, originID)
+			stdout = executionDelegate.Stdout()
+			stderr = executionDelegate.Stderr()
+		})
+
+		It("saves output up to the limit across all writers", func() {
+			_, err := stdout.Write([]byte("hello "))
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = stderr.Write([]byte("world"))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeBuild.SaveEventCallCount()).To(Equal(2))
+			Expect(fakeBuild.SaveEventArgsForCall(0)).To(Equal(event.Log{
+				Origin: event.Origin{
+					Source: event.OriginSourceStdout,
+					ID:     originID,
+				},
+				Payload: "hello ",
+			}))
+			Expect(fakeBuild.SaveEventArgsForCall(1)).To(Equal(event.LogTruncated{
+				Origin: event.Origin{
+					Source: event.OriginSourceStderr,
+					ID:     originID,
+				},
+				MaxBytes: 10,
+			}))
+
+			Expect(fakeBuild.MarkLogTruncatedCallCount()).To(Equal(1))
+		})
+
+		It("discards output once the limit is exceeded", func() {
+			_, err := stdout.Write([]byte("way too much output"))
+			Expect(err).NotTo(HaveOccurred())
+
+			n, err := stdout.Write([]byte("more"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(n).To(Equal(4))
+
+			Expect(fakeBuild.SaveEventCallCount()).To(Equal(1))
+			Expect(fakeBuild.SaveEventArgsForCall(0)).To(BeAssignableToTypeOf(event.LogTruncated{}))
+			Expect(fakeBuild.MarkLogTruncatedCallCount()).To(Equal(1))
+		})
+
+		Context("when the build's log was already truncated", func() {
+			BeforeEach(func() {
+				fakeBuild.LogTruncatedReturns(true)
+				delegate = factory.Delegate(fakeBuild)
+				stdout = delegate.ExecutionDelegate(logger, atc.TaskPlan{Name: "some-task"}, originID).Stdout()
+			})
+
+			It("does not save any more output", func() {
+				_, err := stdout.Write([]byte("hi"))
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeBuild.SaveEventCallCount()).To(BeZero())
+				Expect(fakeBuild.MarkLogTruncatedCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when marking the build as truncated fails", func() {
+			disaster := errors.New("nope")
+
+			BeforeEach(func() {
+				fakeBuild.MarkLogTruncatedReturns(disaster)
+			})
+
+			It("returns the error", func() {
+				_, err := stdout.Write([]byte("way too much output"))
+				Expect(err).To(Equal(disaster))
+			})
+		})
+	})
+
 	Describe("InputDelegate", func() {
 		var (
 			getPlan atc.GetPlan
@@ -605,6 +689,17 @@ var _ = Describe("BuildDelegate", func() {
 			})
 		})
 
+		Describe("ResourceUsageMeasured", func() {
+			It("saves the usage against the build", func() {
+				usage := db.ResourceUsage{CPUNanoseconds: 100, MemoryBytes: 4096, DiskBytes: 10}
+
+				executionDelegate.ResourceUsageMeasured(usage)
+
+				Expect(fakeBuild.SaveResourceUsageCallCount()).To(Equal(1))
+				Expect(fakeBuild.SaveResourceUsageArgsForCall(0)).To(Equal(usage))
+			})
+		})
+
 		Describe("Finished", func() {
 			var exitStatus exec.ExitStatus
 
@@ -1120,6 +1215,111 @@ var _ = Describe("BuildDelegate", func() {
 		})
 	})
 
+	Describe("ApprovalDelegate", func() {
+		var approvalDelegate exec.ApprovalDelegate
+
+		BeforeEach(func() {
+			approvalDelegate = delegate.ApprovalDelegate(logger, atc.ApprovalPlan{Name: "ship-it"}, originID)
+		})
+
+		Describe("Requested", func() {
+			var fakeNotifier *dbfakes.FakeNotifier
+
+			BeforeEach(func() {
+				fakeNotifier = new(dbfakes.FakeNotifier)
+				fakeBuild.ApprovalNotifierReturns(fakeNotifier, nil)
+			})
+
+			It("requests the approval under the step's plan ID", func() {
+				_, err := approvalDelegate.Requested()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeBuild.RequestApprovalCallCount()).To(Equal(1))
+				planID, name := fakeBuild.RequestApprovalArgsForCall(0)
+				Expect(planID).To(Equal(atc.PlanID(originID)))
+				Expect(name).To(Equal("ship-it"))
+			})
+
+			It("returns a notifier for the approval", func() {
+				notifier, err := approvalDelegate.Requested()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(notifier).To(Equal(fakeNotifier))
+
+				Expect(fakeBuild.ApprovalNotifierArgsForCall(0)).To(Equal("ship-it"))
+			})
+
+			Context("when requesting the approval fails", func() {
+				disaster := errors.New("nope")
+
+				BeforeEach(func() {
+					fakeBuild.RequestApprovalReturns(disaster)
+				})
+
+				It("returns the error", func() {
+					_, err := approvalDelegate.Requested()
+					Expect(err).To(Equal(disaster))
+
+					Expect(fakeBuild.ApprovalNotifierCallCount()).To(BeZero())
+				})
+			})
+		})
+
+		Describe("Decision", func() {
+			Context("when the approval is pending", func() {
+				BeforeEach(func() {
+					fakeBuild.GetApprovalReturns(db.BuildApproval{Status: db.ApprovalPending}, true, nil)
+				})
+
+				It("is undecided", func() {
+					_, decided, err := approvalDelegate.Decision()
+					Expect(err).NotTo(HaveOccurred())
+					Expect(decided).To(BeFalse())
+
+					Expect(fakeBuild.GetApprovalArgsForCall(0)).To(Equal("ship-it"))
+				})
+			})
+
+			Context("when the approval is approved", func() {
+				BeforeEach(func() {
+					fakeBuild.GetApprovalReturns(db.BuildApproval{Status: db.ApprovalApproved, Approver: "team:main"}, true, nil)
+				})
+
+				It("is approved", func() {
+					approved, decided, err := approvalDelegate.Decision()
+					Expect(err).NotTo(HaveOccurred())
+					Expect(decided).To(BeTrue())
+					Expect(approved).To(BeTrue())
+				})
+			})
+
+			Context("when the approval is rejected", func() {
+				BeforeEach(func() {
+					fakeBuild.GetApprovalReturns(db.BuildApproval{Status: db.ApprovalRejected, Approver: "team:main"}, true, nil)
+				})
+
+				It("is not approved", func() {
+					approved, decided, err := approvalDelegate.Decision()
+					Expect(err).NotTo(HaveOccurred())
+					Expect(decided).To(BeTrue())
+					Expect(approved).To(BeFalse())
+				})
+			})
+
+			Context("when getting the approval fails", func() {
+				disaster := errors.New("nope")
+
+				BeforeEach(func() {
+					fakeBuild.GetApprovalReturns(db.BuildApproval{}, false, disaster)
+				})
+
+				It("returns the error", func() {
+					_, _, err := approvalDelegate.Decision()
+					Expect(err).To(Equal(disaster))
+				})
+			})
+		})
+	})
+
 	Describe("Aborted", func() {
 		var aborted bool
 
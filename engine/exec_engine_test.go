@@ -890,6 +890,44 @@ var _ = Describe("ExecEngine", func() {
 
 						Expect(taskStep.ReleaseCallCount()).To(Equal(1))
 					})
+
+					Context("when the build is released while the task is running", func() {
+						var stopTask chan struct{}
+
+						BeforeEach(func() {
+							stopTask = make(chan struct{})
+
+							taskStep.RunStub = func(signals <-chan os.Signal, ready chan<- struct{}) error {
+								close(ready)
+								<-stopTask
+								return nil
+							}
+						})
+
+						AfterEach(func() {
+							close(stopTask)
+						})
+
+						It("returns without finishing the build or touching the task", func() {
+							var err error
+							build, err = execEngine.CreateBuild(logger, dbBuild, plan)
+							Expect(err).NotTo(HaveOccurred())
+
+							resumed := make(chan struct{})
+							go func() {
+								defer close(resumed)
+								build.Resume(logger)
+							}()
+
+							Eventually(taskStep.RunCallCount).Should(Equal(1))
+
+							build.Release(logger)
+							Eventually(resumed).Should(BeClosed())
+
+							Expect(fakeDelegate.FinishCallCount()).To(BeZero())
+							Expect(taskStep.ReleaseCallCount()).To(BeZero())
+						})
+					})
 				})
 			})
 
@@ -1009,6 +1047,68 @@ var _ = Describe("ExecEngine", func() {
 					Expect(dependentStep.ReleaseCallCount()).To(Equal(1))
 				})
 			})
+
+			Context("that contains approvals", func() {
+				var fakeApprovalDelegate *execfakes.FakeApprovalDelegate
+
+				BeforeEach(func() {
+					plan = planFactory.NewPlan(atc.ApprovalPlan{
+						Name: "ship-it",
+					})
+
+					fakeApprovalDelegate = new(execfakes.FakeApprovalDelegate)
+					fakeApprovalDelegate.RequestedReturns(new(dbfakes.FakeNotifier), nil)
+					fakeDelegate.ApprovalDelegateReturns(fakeApprovalDelegate)
+				})
+
+				It("waits on the approval under the step's plan ID", func() {
+					fakeApprovalDelegate.DecisionReturns(true, true, nil)
+
+					var err error
+					build, err = execEngine.CreateBuild(logger, dbBuild, plan)
+					Expect(err).NotTo(HaveOccurred())
+
+					build.Resume(logger)
+
+					Expect(fakeDelegate.ApprovalDelegateCallCount()).To(Equal(1))
+					_, approvalPlan, planID := fakeDelegate.ApprovalDelegateArgsForCall(0)
+					Expect(approvalPlan).To(Equal(atc.ApprovalPlan{Name: "ship-it"}))
+					Expect(planID).To(Equal(event.OriginID(plan.ID)))
+
+					Expect(fakeApprovalDelegate.RequestedCallCount()).To(Equal(1))
+				})
+
+				It("succeeds once approved", func() {
+					fakeApprovalDelegate.DecisionReturns(true, true, nil)
+
+					var err error
+					build, err = execEngine.CreateBuild(logger, dbBuild, plan)
+					Expect(err).NotTo(HaveOccurred())
+
+					build.Resume(logger)
+
+					Expect(fakeDelegate.FinishCallCount()).To(Equal(1))
+					_, err, succeeded, aborted := fakeDelegate.FinishArgsForCall(0)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(succeeded).To(Equal(exec.Success(true)))
+					Expect(aborted).To(BeFalse())
+				})
+
+				It("fails once rejected", func() {
+					fakeApprovalDelegate.DecisionReturns(false, true, nil)
+
+					var err error
+					build, err = execEngine.CreateBuild(logger, dbBuild, plan)
+					Expect(err).NotTo(HaveOccurred())
+
+					build.Resume(logger)
+
+					Expect(fakeDelegate.FinishCallCount()).To(Equal(1))
+					_, err, succeeded, _ := fakeDelegate.FinishArgsForCall(0)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(succeeded).To(Equal(exec.Success(false)))
+				})
+			})
 		})
 	})
 
@@ -1069,6 +1169,29 @@ var _ = Describe("ExecEngine", func() {
 		})
 	})
 
+	Describe("Plan", func() {
+		It("returns the original plan, including what the public plan leaves out", func() {
+			logger := lagertest.NewTestLogger("test")
+
+			planFactory := atc.NewPlanFactory(123)
+			plan := planFactory.NewPlan(atc.GetPlan{
+				Name:     "some-get",
+				Resource: "some-input-resource",
+				Type:     "some-type",
+				Source:   atc.Source{"some": "source"},
+				Params:   atc.Params{"some": "params"},
+				Version:  atc.Version{"some": "version"},
+			})
+
+			build, err := execEngine.CreateBuild(logger, new(dbfakes.FakeBuild), plan)
+			Expect(err).ToNot(HaveOccurred())
+
+			originalPlan, err := build.Plan(logger)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(originalPlan).To(Equal(plan))
+		})
+	})
+
 	Describe("LookupBuild", func() {
 		var dbBuild *dbfakes.FakeBuild
 		BeforeEach(func() {
@@ -42,9 +42,16 @@ func (execV1DummyBuild) PublicPlan(lager.Logger) (atc.PublicBuildPlan, error) {
 	}, nil
 }
 
+func (execV1DummyBuild) Plan(lager.Logger) (atc.Plan, error) {
+	return atc.Plan{}, ErrPlanUnavailable
+}
+
 func (execV1DummyBuild) Abort(lager.Logger) error {
 	return nil
 }
 
 func (execV1DummyBuild) Resume(logger lager.Logger) {
 }
+
+func (execV1DummyBuild) Release(logger lager.Logger) {
+}
@@ -0,0 +1,60 @@
+package engine
+
+import (
+	"os"
+	"sync"
+)
+
+// Handoff keeps builds from being dropped when an ATC shuts down, e.g. during
+// a rolling deploy. Once drain is closed, builds are no longer resumed, and
+// the ones being tracked are released, still running, and handed off for
+// another ATC to resume.
+type Handoff struct {
+	drain <-chan struct{}
+
+	lock     sync.Mutex
+	tracking sync.WaitGroup
+}
+
+func NewHandoff(drain <-chan struct{}) *Handoff {
+	return &Handoff{
+		drain: drain,
+	}
+}
+
+// Run waits for every build to be handed off once it's signalled, so that the
+// ATC doesn't exit while it's still tracking any of them.
+func (handoff *Handoff) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	close(ready)
+
+	<-signals
+	<-handoff.drain
+
+	// track lets nothing in once draining, so once any call to it that was
+	// already under way is done, nothing more can be added while waiting
+	handoff.lock.Lock()
+	handoff.lock.Unlock()
+
+	handoff.tracking.Wait()
+
+	return nil
+}
+
+func (handoff *Handoff) track() bool {
+	handoff.lock.Lock()
+	defer handoff.lock.Unlock()
+
+	select {
+	case <-handoff.drain:
+		return false
+	default:
+	}
+
+	handoff.tracking.Add(1)
+
+	return true
+}
+
+func (handoff *Handoff) untrack() {
+	handoff.tracking.Done()
+}
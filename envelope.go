@@ -0,0 +1,22 @@
+package atc
+
+import "encoding/json"
+
+// V2MediaType is the content type of responses under /api/v2. Clients that
+// ask for it by name get it back by name; the rest get application/json.
+const V2MediaType = "application/vnd.concourse.v2+json"
+
+// Envelope is the body of every response under /api/v2. Data is what the v1
+// route of the same name would have responded with, and is left out when the
+// request failed, in which case Error says why.
+type Envelope struct {
+	Data       json.RawMessage     `json:"data,omitempty"`
+	Pagination *EnvelopePagination `json:"pagination,omitempty"`
+	Error      *APIError           `json:"error,omitempty"`
+}
+
+// EnvelopePagination links to the pages either side of the one in Data.
+type EnvelopePagination struct {
+	Next     string `json:"next,omitempty"`
+	Previous string `json:"previous,omitempty"`
+}
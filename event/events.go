@@ -10,6 +10,16 @@ type Error struct {
 func (Error) EventType() atc.EventType  { return EventTypeError }
 func (Error) Version() atc.EventVersion { return "4.0" }
 
+// Warning is something about the build worth knowing that doesn't stop it,
+// e.g. that it's taking much longer than the job's builds usually do.
+type Warning struct {
+	Message string `json:"message"`
+	Time    int64  `json:"time"`
+}
+
+func (Warning) EventType() atc.EventType  { return EventTypeWarning }
+func (Warning) Version() atc.EventVersion { return "1.0" }
+
 type FinishTask struct {
 	Time       int64  `json:"time"`
 	ExitStatus int    `json:"exit_status"`
@@ -93,6 +103,14 @@ type Log struct {
 func (Log) EventType() atc.EventType  { return EventTypeLog }
 func (Log) Version() atc.EventVersion { return "5.0" }
 
+type LogTruncated struct {
+	Origin   Origin `json:"origin"`
+	MaxBytes int64  `json:"max_bytes"`
+}
+
+func (LogTruncated) EventType() atc.EventType  { return EventTypeLogTruncated }
+func (LogTruncated) Version() atc.EventVersion { return "1.0" }
+
 type Origin struct {
 	ID     OriginID     `json:"id,omitempty"`
 	Source OriginSource `json:"source,omitempty"`
@@ -155,3 +173,23 @@ type InitializePut struct {
 
 func (InitializePut) EventType() atc.EventType  { return EventTypeInitializePut }
 func (InitializePut) Version() atc.EventVersion { return "1.0" }
+
+type ApprovalRequested struct {
+	Origin Origin `json:"origin"`
+	Name   string `json:"name"`
+	Time   int64  `json:"time"`
+}
+
+func (ApprovalRequested) EventType() atc.EventType  { return EventTypeApprovalRequested }
+func (ApprovalRequested) Version() atc.EventVersion { return "1.0" }
+
+type ApprovalDecided struct {
+	Origin   Origin `json:"origin"`
+	Name     string `json:"name"`
+	Approved bool   `json:"approved"`
+	Approver string `json:"approver"`
+	Time     int64  `json:"time"`
+}
+
+func (ApprovalDecided) EventType() atc.EventType  { return EventTypeApprovalDecided }
+func (ApprovalDecided) Version() atc.EventVersion { return "1.0" }
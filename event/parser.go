@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/concourse/atc"
 )
@@ -42,7 +43,11 @@ func init() {
 	registerEvent(FinishPut{})
 	registerEvent(Status{})
 	registerEvent(Log{})
+	registerEvent(LogTruncated{})
 	registerEvent(Error{})
+	registerEvent(ApprovalRequested{})
+	registerEvent(ApprovalDecided{})
+	registerEvent(Warning{})
 
 	// deprecated:
 	registerEvent(FinishV10{})
@@ -84,6 +89,60 @@ type Envelope struct {
 	Data    *json.RawMessage `json:"data"`
 	Event   atc.EventType    `json:"event"`
 	Version atc.EventVersion `json:"version"`
+
+	// Time is when the event was saved. It is zero for events saved before
+	// times were recorded, which are sent to clients without one.
+	Time time.Time `json:"-"`
+
+	// ID is the event's offset in the build's events, which clients can use
+	// to link to it. It isn't stored with the event; it's only set on events
+	// being streamed.
+	ID *uint `json:"-"`
+}
+
+type envelopeJSON struct {
+	Data    *json.RawMessage `json:"data"`
+	Event   atc.EventType    `json:"event"`
+	Version atc.EventVersion `json:"version"`
+	Time    *time.Time       `json:"time,omitempty"`
+	ID      *uint            `json:"id,omitempty"`
+}
+
+func (e Envelope) MarshalJSON() ([]byte, error) {
+	envelope := envelopeJSON{
+		Data:    e.Data,
+		Event:   e.Event,
+		Version: e.Version,
+		ID:      e.ID,
+	}
+
+	if !e.Time.IsZero() {
+		at := e.Time.UTC()
+		envelope.Time = &at
+	}
+
+	return json.Marshal(envelope)
+}
+
+func (e *Envelope) UnmarshalJSON(payload []byte) error {
+	var envelope envelopeJSON
+	err := json.Unmarshal(payload, &envelope)
+	if err != nil {
+		return err
+	}
+
+	*e = Envelope{
+		Data:    envelope.Data,
+		Event:   envelope.Event,
+		Version: envelope.Version,
+		ID:      envelope.ID,
+	}
+
+	if envelope.Time != nil {
+		e.Time = *envelope.Time
+	}
+
+	return nil
 }
 
 func (m Message) MarshalJSON() ([]byte, error) {
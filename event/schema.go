@@ -0,0 +1,144 @@
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/concourse/atc"
+)
+
+// SchemaVersion identifies the set of events a client understands. Each
+// version adds events (or new versions of them) to the one before, so a
+// client that says it understands a version can be sent events downgraded to
+// it instead of events it would fail to parse.
+type SchemaVersion int
+
+const (
+	// SchemaV1 is every event from before schemas were versioned.
+	SchemaV1 SchemaVersion = 1
+
+	// SchemaV2 adds log-truncated.
+	SchemaV2 SchemaVersion = 2
+
+	// SchemaV3 adds approval-requested and approval-decided.
+	SchemaV3 SchemaVersion = 3
+
+	// SchemaV4 adds warning.
+	SchemaV4 SchemaVersion = 4
+
+	CurrentSchemaVersion = SchemaV4
+)
+
+// schemaChange is an event introduced by a schema version, along with how to
+// turn it into something the previous version understands. A nil event from
+// the downgrade means clients on the previous version don't get it at all.
+type schemaChange struct {
+	Event     atc.EventType
+	Version   atc.EventVersion
+	Downgrade func(atc.Event) atc.Event
+}
+
+var schemaChanges = map[SchemaVersion][]schemaChange{
+	SchemaV2: {
+		{
+			Event:   EventTypeLogTruncated,
+			Version: LogTruncated{}.Version(),
+			Downgrade: func(ev atc.Event) atc.Event {
+				truncated := ev.(LogTruncated)
+
+				return Log{
+					Origin:  truncated.Origin,
+					Payload: fmt.Sprintf("\nlog truncated after %d bytes\n", truncated.MaxBytes),
+				}
+			},
+		},
+	},
+	SchemaV3: {
+		{
+			Event:   EventTypeApprovalRequested,
+			Version: ApprovalRequested{}.Version(),
+			Downgrade: func(ev atc.Event) atc.Event {
+				requested := ev.(ApprovalRequested)
+
+				return Log{
+					Origin:  requested.Origin,
+					Payload: fmt.Sprintf("waiting for approval of %s\n", requested.Name),
+				}
+			},
+		},
+		{
+			Event:   EventTypeApprovalDecided,
+			Version: ApprovalDecided{}.Version(),
+			Downgrade: func(ev atc.Event) atc.Event {
+				decided := ev.(ApprovalDecided)
+
+				decision := "rejected"
+				if decided.Approved {
+					decision = "approved"
+				}
+
+				return Log{
+					Origin:  decided.Origin,
+					Payload: fmt.Sprintf("%s %s by %s\n", decided.Name, decision, decided.Approver),
+				}
+			},
+		},
+	},
+	SchemaV4: {
+		{
+			Event:   EventTypeWarning,
+			Version: Warning{}.Version(),
+			Downgrade: func(ev atc.Event) atc.Event {
+				warning := ev.(Warning)
+
+				return Log{
+					Payload: fmt.Sprintf("warning: %s\n", warning.Message),
+				}
+			},
+		},
+	},
+}
+
+// Downgrade translates the event into the given schema version, one version
+// at a time. It returns false if the event has no equivalent in that
+// version and should not be sent. Events that were around before the version
+// are returned as-is.
+func Downgrade(envelope Envelope, to SchemaVersion) (Envelope, bool, error) {
+	for version := CurrentSchemaVersion; version > to; version-- {
+		for _, change := range schemaChanges[version] {
+			if envelope.Event != change.Event || envelope.Version != change.Version {
+				continue
+			}
+
+			if envelope.Data == nil {
+				return Envelope{}, false, nil
+			}
+
+			ev, err := ParseEvent(envelope.Version, envelope.Event, *envelope.Data)
+			if err != nil {
+				return Envelope{}, false, err
+			}
+
+			downgraded := change.Downgrade(ev)
+			if downgraded == nil {
+				return Envelope{}, false, nil
+			}
+
+			payload, err := json.Marshal(downgraded)
+			if err != nil {
+				return Envelope{}, false, err
+			}
+
+			envelope = Envelope{
+				Data:    (*json.RawMessage)(&payload),
+				Event:   downgraded.EventType(),
+				Version: downgraded.Version(),
+				Time:    envelope.Time,
+			}
+
+			break
+		}
+	}
+
+	return envelope, true, nil
+}
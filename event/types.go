@@ -8,6 +8,9 @@ const (
 	// build log (e.g. from input or build execution)
 	EventTypeLog atc.EventType = "log"
 
+	// build log output exceeded the configured limit and is no longer saved
+	EventTypeLogTruncated atc.EventType = "log-truncated"
+
 	// build status change (e.g. 'started', 'succeeded')
 	EventTypeStatus atc.EventType = "status"
 
@@ -32,6 +35,15 @@ const (
 	// finished putting something
 	EventTypeFinishPut atc.EventType = "finish-put"
 
+	// approval step waiting for someone to approve or reject the build
+	EventTypeApprovalRequested atc.EventType = "approval-requested"
+
+	// approval step approved or rejected
+	EventTypeApprovalDecided atc.EventType = "approval-decided"
+
 	// error occurred
 	EventTypeError atc.EventType = "error"
+
+	// build has been running for unusually long
+	EventTypeWarning atc.EventType = "warning"
 )
@@ -0,0 +1,92 @@
+package exec
+
+import (
+	"os"
+
+	"github.com/concourse/atc/db"
+)
+
+//go:generate counterfeiter . ApprovalDelegate
+
+// ApprovalDelegate is used by an ApprovalStep to ask for a decision and to
+// find out what it was. Approving and rejecting happen elsewhere, e.g.
+// through the API.
+type ApprovalDelegate interface {
+	// Requested records that the step is waiting on a decision, returning a
+	// notifier that fires when one may have been made.
+	Requested() (db.Notifier, error)
+
+	// Decision returns whether the step was approved, and whether it has been
+	// decided at all.
+	Decision() (bool, bool, error)
+}
+
+// ApprovalStep holds up the build until someone approves or rejects it.
+type ApprovalStep struct {
+	delegate ApprovalDelegate
+	approved bool
+}
+
+// Approval constructs an ApprovalStep factory.
+func Approval(delegate ApprovalDelegate) ApprovalStep {
+	return ApprovalStep{
+		delegate: delegate,
+	}
+}
+
+// Using constructs an *ApprovalStep.
+func (step ApprovalStep) Using(prev Step, repo *SourceRepository) Step {
+	return &step
+}
+
+// Run waits for the step to be decided on, however long that takes. It
+// returns ErrInterrupted if it's signalled first, e.g. because the build was
+// aborted.
+//
+// The step may already have been decided on if the build is being resumed,
+// in which case it returns right away.
+func (step *ApprovalStep) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	close(ready)
+
+	decisions, err := step.delegate.Requested()
+	if err != nil {
+		return err
+	}
+
+	defer decisions.Close()
+
+	for {
+		approved, decided, err := step.delegate.Decision()
+		if err != nil {
+			return err
+		}
+
+		if decided {
+			step.approved = approved
+			return nil
+		}
+
+		select {
+		case <-decisions.Notify():
+		case <-signals:
+			return ErrInterrupted
+		}
+	}
+}
+
+// Release does nothing, as the step doesn't use anything that needs
+// releasing.
+func (step *ApprovalStep) Release() {}
+
+// Result indicates Success as true if the step was approved.
+//
+// Any other type is ignored.
+func (step *ApprovalStep) Result(x interface{}) bool {
+	switch v := x.(type) {
+	case *Success:
+		*v = Success(step.approved)
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,152 @@
+package exec_test
+
+import (
+	"errors"
+	"os"
+
+	"github.com/concourse/atc/db/dbfakes"
+	. "github.com/concourse/atc/exec"
+
+	"github.com/concourse/atc/exec/execfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/tedsuo/ifrit"
+)
+
+var _ = Describe("Approval Step", func() {
+	var (
+		fakeDelegate *execfakes.FakeApprovalDelegate
+		fakeNotifier *dbfakes.FakeNotifier
+
+		decisions chan struct{}
+
+		step    Step
+		process ifrit.Process
+	)
+
+	BeforeEach(func() {
+		fakeDelegate = new(execfakes.FakeApprovalDelegate)
+		fakeNotifier = new(dbfakes.FakeNotifier)
+
+		decisions = make(chan struct{}, 1)
+		fakeNotifier.NotifyReturns(decisions)
+
+		fakeDelegate.RequestedReturns(fakeNotifier, nil)
+	})
+
+	JustBeforeEach(func() {
+		step = Approval(fakeDelegate).Using(nil, nil)
+		process = ifrit.Background(step)
+	})
+
+	Context("when requesting the approval fails", func() {
+		disaster := errors.New("nope")
+
+		BeforeEach(func() {
+			fakeDelegate.RequestedReturns(nil, disaster)
+		})
+
+		It("returns the error", func() {
+			Expect(<-process.Wait()).To(Equal(disaster))
+		})
+	})
+
+	Context("when it has not been decided yet", func() {
+		It("waits", func() {
+			Consistently(process.Wait()).ShouldNot(Receive())
+		})
+
+		Context("when it is approved", func() {
+			JustBeforeEach(func() {
+				Eventually(fakeDelegate.DecisionCallCount).Should(Equal(1))
+
+				fakeDelegate.DecisionReturns(true, true, nil)
+				decisions <- struct{}{}
+			})
+
+			It("succeeds", func() {
+				Expect(<-process.Wait()).To(Succeed())
+
+				var success Success
+				Expect(step.Result(&success)).To(BeTrue())
+				Expect(success).To(BeTrue())
+			})
+
+			It("closes the notifier", func() {
+				<-process.Wait()
+				Expect(fakeNotifier.CloseCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when it is rejected", func() {
+			JustBeforeEach(func() {
+				Eventually(fakeDelegate.DecisionCallCount).Should(Equal(1))
+
+				fakeDelegate.DecisionReturns(false, true, nil)
+				decisions <- struct{}{}
+			})
+
+			It("fails", func() {
+				Expect(<-process.Wait()).To(Succeed())
+
+				var success Success
+				Expect(step.Result(&success)).To(BeTrue())
+				Expect(success).To(BeFalse())
+			})
+		})
+
+		Context("when it is notified without having been decided", func() {
+			JustBeforeEach(func() {
+				Eventually(fakeDelegate.DecisionCallCount).Should(Equal(1))
+				decisions <- struct{}{}
+			})
+
+			It("keeps waiting", func() {
+				Eventually(fakeDelegate.DecisionCallCount).Should(Equal(2))
+				Consistently(process.Wait()).ShouldNot(Receive())
+			})
+		})
+
+		Context("when it is signalled", func() {
+			JustBeforeEach(func() {
+				process.Signal(os.Interrupt)
+			})
+
+			It("returns ErrInterrupted", func() {
+				Expect(<-process.Wait()).To(Equal(ErrInterrupted))
+			})
+
+			It("closes the notifier", func() {
+				<-process.Wait()
+				Expect(fakeNotifier.CloseCallCount()).To(Equal(1))
+			})
+		})
+	})
+
+	Context("when it has already been decided", func() {
+		BeforeEach(func() {
+			fakeDelegate.DecisionReturns(true, true, nil)
+		})
+
+		It("succeeds right away", func() {
+			Expect(<-process.Wait()).To(Succeed())
+
+			var success Success
+			Expect(step.Result(&success)).To(BeTrue())
+			Expect(success).To(BeTrue())
+		})
+	})
+
+	Context("when looking up the decision fails", func() {
+		disaster := errors.New("nope")
+
+		BeforeEach(func() {
+			fakeDelegate.DecisionReturns(false, false, disaster)
+		})
+
+		It("returns the error", func() {
+			Expect(<-process.Wait()).To(Equal(disaster))
+		})
+	})
+})
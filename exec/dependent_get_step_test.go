@@ -75,7 +75,7 @@ var _ = Describe("DependentGet", func() {
 		fakeResourceFetcher = new(rfakes.FakeFetcher)
 		fakeTracker := new(rfakes.FakeTracker)
 
-		factory = NewGardenFactory(fakeWorkerClient, fakeTracker, fakeResourceFetcher)
+		factory = NewGardenFactory(fakeWorkerClient, fakeTracker, fakeResourceFetcher, nil)
 
 		stdoutBuf = gbytes.NewBuffer()
 		stderrBuf = gbytes.NewBuffer()
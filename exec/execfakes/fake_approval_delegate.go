@@ -0,0 +1,106 @@
+// This file was generated by counterfeiter
+package execfakes
+
+import (
+	"sync"
+
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/exec"
+)
+
+type FakeApprovalDelegate struct {
+	RequestedStub        func() (db.Notifier, error)
+	requestedMutex       sync.RWMutex
+	requestedArgsForCall []struct{}
+	requestedReturns     struct {
+		result1 db.Notifier
+		result2 error
+	}
+	DecisionStub        func() (bool, bool, error)
+	decisionMutex       sync.RWMutex
+	decisionArgsForCall []struct{}
+	decisionReturns     struct {
+		result1 bool
+		result2 bool
+		result3 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeApprovalDelegate) Requested() (db.Notifier, error) {
+	fake.requestedMutex.Lock()
+	fake.requestedArgsForCall = append(fake.requestedArgsForCall, struct{}{})
+	fake.recordInvocation("Requested", []interface{}{})
+	fake.requestedMutex.Unlock()
+	if fake.RequestedStub != nil {
+		return fake.RequestedStub()
+	} else {
+		return fake.requestedReturns.result1, fake.requestedReturns.result2
+	}
+}
+
+func (fake *FakeApprovalDelegate) RequestedCallCount() int {
+	fake.requestedMutex.RLock()
+	defer fake.requestedMutex.RUnlock()
+	return len(fake.requestedArgsForCall)
+}
+
+func (fake *FakeApprovalDelegate) RequestedReturns(result1 db.Notifier, result2 error) {
+	fake.RequestedStub = nil
+	fake.requestedReturns = struct {
+		result1 db.Notifier
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeApprovalDelegate) Decision() (bool, bool, error) {
+	fake.decisionMutex.Lock()
+	fake.decisionArgsForCall = append(fake.decisionArgsForCall, struct{}{})
+	fake.recordInvocation("Decision", []interface{}{})
+	fake.decisionMutex.Unlock()
+	if fake.DecisionStub != nil {
+		return fake.DecisionStub()
+	} else {
+		return fake.decisionReturns.result1, fake.decisionReturns.result2, fake.decisionReturns.result3
+	}
+}
+
+func (fake *FakeApprovalDelegate) DecisionCallCount() int {
+	fake.decisionMutex.RLock()
+	defer fake.decisionMutex.RUnlock()
+	return len(fake.decisionArgsForCall)
+}
+
+func (fake *FakeApprovalDelegate) DecisionReturns(result1 bool, result2 bool, result3 error) {
+	fake.DecisionStub = nil
+	fake.decisionReturns = struct {
+		result1 bool
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeApprovalDelegate) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.requestedMutex.RLock()
+	defer fake.requestedMutex.RUnlock()
+	fake.decisionMutex.RLock()
+	defer fake.decisionMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeApprovalDelegate) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ exec.ApprovalDelegate = new(FakeApprovalDelegate)
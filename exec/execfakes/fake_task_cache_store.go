@@ -0,0 +1,132 @@
+// This file was generated by counterfeiter
+package execfakes
+
+import (
+	"io"
+	"sync"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/exec"
+)
+
+type FakeTaskCacheStore struct {
+	RestoreStub        func(logger lager.Logger, cache db.TaskCache) (io.ReadCloser, bool, error)
+	restoreMutex       sync.RWMutex
+	restoreArgsForCall []struct {
+		logger lager.Logger
+		cache  db.TaskCache
+	}
+	restoreReturns struct {
+		result1 io.ReadCloser
+		result2 bool
+		result3 error
+	}
+	SaveStub        func(logger lager.Logger, cache db.TaskCache, tarStream io.Reader) error
+	saveMutex       sync.RWMutex
+	saveArgsForCall []struct {
+		logger    lager.Logger
+		cache     db.TaskCache
+		tarStream io.Reader
+	}
+	saveReturns struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeTaskCacheStore) Restore(logger lager.Logger, cache db.TaskCache) (io.ReadCloser, bool, error) {
+	fake.restoreMutex.Lock()
+	fake.restoreArgsForCall = append(fake.restoreArgsForCall, struct {
+		logger lager.Logger
+		cache  db.TaskCache
+	}{logger, cache})
+	fake.recordInvocation("Restore", []interface{}{logger, cache})
+	fake.restoreMutex.Unlock()
+	if fake.RestoreStub != nil {
+		return fake.RestoreStub(logger, cache)
+	} else {
+		return fake.restoreReturns.result1, fake.restoreReturns.result2, fake.restoreReturns.result3
+	}
+}
+
+func (fake *FakeTaskCacheStore) RestoreCallCount() int {
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
+	return len(fake.restoreArgsForCall)
+}
+
+func (fake *FakeTaskCacheStore) RestoreArgsForCall(i int) (lager.Logger, db.TaskCache) {
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
+	return fake.restoreArgsForCall[i].logger, fake.restoreArgsForCall[i].cache
+}
+
+func (fake *FakeTaskCacheStore) RestoreReturns(result1 io.ReadCloser, result2 bool, result3 error) {
+	fake.RestoreStub = nil
+	fake.restoreReturns = struct {
+		result1 io.ReadCloser
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeTaskCacheStore) Save(logger lager.Logger, cache db.TaskCache, tarStream io.Reader) error {
+	fake.saveMutex.Lock()
+	fake.saveArgsForCall = append(fake.saveArgsForCall, struct {
+		logger    lager.Logger
+		cache     db.TaskCache
+		tarStream io.Reader
+	}{logger, cache, tarStream})
+	fake.recordInvocation("Save", []interface{}{logger, cache, tarStream})
+	fake.saveMutex.Unlock()
+	if fake.SaveStub != nil {
+		return fake.SaveStub(logger, cache, tarStream)
+	} else {
+		return fake.saveReturns.result1
+	}
+}
+
+func (fake *FakeTaskCacheStore) SaveCallCount() int {
+	fake.saveMutex.RLock()
+	defer fake.saveMutex.RUnlock()
+	return len(fake.saveArgsForCall)
+}
+
+func (fake *FakeTaskCacheStore) SaveArgsForCall(i int) (lager.Logger, db.TaskCache, io.Reader) {
+	fake.saveMutex.RLock()
+	defer fake.saveMutex.RUnlock()
+	return fake.saveArgsForCall[i].logger, fake.saveArgsForCall[i].cache, fake.saveArgsForCall[i].tarStream
+}
+
+func (fake *FakeTaskCacheStore) SaveReturns(result1 error) {
+	fake.SaveStub = nil
+	fake.saveReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeTaskCacheStore) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
+	fake.saveMutex.RLock()
+	defer fake.saveMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeTaskCacheStore) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ exec.TaskCacheStore = new(FakeTaskCacheStore)
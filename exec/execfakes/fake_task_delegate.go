@@ -6,6 +6,7 @@ import (
 	"sync"
 
 	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
 	"github.com/concourse/atc/exec"
 	"github.com/concourse/atc/worker"
 )
@@ -49,6 +50,11 @@ type FakeTaskDelegate struct {
 	stderrReturns     struct {
 		result1 io.Writer
 	}
+	ResourceUsageMeasuredStub        func(arg1 db.ResourceUsage)
+	resourceUsageMeasuredMutex       sync.RWMutex
+	resourceUsageMeasuredArgsForCall []struct {
+		arg1 db.ResourceUsage
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -224,6 +230,30 @@ func (fake *FakeTaskDelegate) StderrReturns(result1 io.Writer) {
 	}{result1}
 }
 
+func (fake *FakeTaskDelegate) ResourceUsageMeasured(arg1 db.ResourceUsage) {
+	fake.resourceUsageMeasuredMutex.Lock()
+	fake.resourceUsageMeasuredArgsForCall = append(fake.resourceUsageMeasuredArgsForCall, struct {
+		arg1 db.ResourceUsage
+	}{arg1})
+	fake.recordInvocation("ResourceUsageMeasured", []interface{}{arg1})
+	fake.resourceUsageMeasuredMutex.Unlock()
+	if fake.ResourceUsageMeasuredStub != nil {
+		fake.ResourceUsageMeasuredStub(arg1)
+	}
+}
+
+func (fake *FakeTaskDelegate) ResourceUsageMeasuredCallCount() int {
+	fake.resourceUsageMeasuredMutex.RLock()
+	defer fake.resourceUsageMeasuredMutex.RUnlock()
+	return len(fake.resourceUsageMeasuredArgsForCall)
+}
+
+func (fake *FakeTaskDelegate) ResourceUsageMeasuredArgsForCall(i int) db.ResourceUsage {
+	fake.resourceUsageMeasuredMutex.RLock()
+	defer fake.resourceUsageMeasuredMutex.RUnlock()
+	return fake.resourceUsageMeasuredArgsForCall[i].arg1
+}
+
 func (fake *FakeTaskDelegate) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -241,6 +271,8 @@ func (fake *FakeTaskDelegate) Invocations() map[string][][]interface{} {
 	defer fake.stdoutMutex.RUnlock()
 	fake.stderrMutex.RLock()
 	defer fake.stderrMutex.RUnlock()
+	fake.resourceUsageMeasuredMutex.RLock()
+	defer fake.resourceUsageMeasuredMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -7,6 +7,7 @@ import (
 	"code.cloudfoundry.org/clock"
 	"code.cloudfoundry.org/lager"
 	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
 	"github.com/concourse/atc/worker"
 )
 
@@ -104,6 +105,8 @@ type TaskDelegate interface {
 	Finished(ExitStatus)
 	Failed(error)
 
+	ResourceUsageMeasured(db.ResourceUsage)
+
 	ImageVersionDetermined(worker.VolumeIdentifier) error
 
 	Stdout() io.Writer
@@ -18,6 +18,7 @@ type gardenFactory struct {
 	workerClient    worker.Client
 	tracker         resource.Tracker
 	resourceFetcher resource.Fetcher
+	taskCaches      TaskCacheStore
 }
 
 //go:generate counterfeiter . TrackerFactory
@@ -30,11 +31,13 @@ func NewGardenFactory(
 	workerClient worker.Client,
 	tracker resource.Tracker,
 	resourceFetcher resource.Fetcher,
+	taskCaches TaskCacheStore,
 ) Factory {
 	return &gardenFactory{
 		workerClient:    workerClient,
 		tracker:         tracker,
 		resourceFetcher: resourceFetcher,
+		taskCaches:      taskCaches,
 	}
 }
 
@@ -191,6 +194,7 @@ func (factory *gardenFactory) Task(
 		outputMapping,
 		imageArtifactName,
 		clock,
+		factory.taskCaches,
 		containerSuccessTTL,
 		containerFailureTTL,
 	)
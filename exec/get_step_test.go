@@ -120,7 +120,7 @@ var _ = Describe("Get", func() {
 		fakeVersionedSource = new(rfakes.FakeVersionedSource)
 		fakeFetchSource.VersionedSourceReturns(fakeVersionedSource)
 
-		factory = NewGardenFactory(fakeWorkerClient, fakeTracker, fakeResourceFetcher)
+		factory = NewGardenFactory(fakeWorkerClient, fakeTracker, fakeResourceFetcher, nil)
 	})
 
 	JustBeforeEach(func() {
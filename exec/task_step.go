@@ -35,6 +35,15 @@ func (err MissingInputsError) Error() string {
 	return fmt.Sprintf("missing inputs: %s", strings.Join(err.Inputs, ", "))
 }
 
+//go:generate counterfeiter . TaskCacheStore
+
+// TaskCacheStore keeps the directories that tasks cache between a job's
+// builds somewhere that outlives the workers they ran on.
+type TaskCacheStore interface {
+	Restore(logger lager.Logger, cache db.TaskCache) (io.ReadCloser, bool, error)
+	Save(logger lager.Logger, cache db.TaskCache, tarStream io.Reader) error
+}
+
 // TaskStep executes a TaskConfig, whose inputs will be fetched from the
 // SourceRepository and outputs will be added to the SourceRepository.
 type TaskStep struct {
@@ -53,6 +62,7 @@ type TaskStep struct {
 	outputMapping     map[string]string
 	imageArtifactName string
 	clock             clock.Clock
+	taskCaches        TaskCacheStore
 	repo              *SourceRepository
 
 	container           worker.Container
@@ -80,6 +90,7 @@ func newTaskStep(
 	outputMapping map[string]string,
 	imageArtifactName string,
 	clock clock.Clock,
+	taskCaches TaskCacheStore,
 	containerSuccessTTL time.Duration,
 	containerFailureTTL time.Duration,
 ) TaskStep {
@@ -99,6 +110,7 @@ func newTaskStep(
 		outputMapping:       outputMapping,
 		imageArtifactName:   imageArtifactName,
 		clock:               clock,
+		taskCaches:          taskCaches,
 		containerSuccessTTL: containerSuccessTTL,
 		containerFailureTTL: containerFailureTTL,
 	}
@@ -131,6 +143,10 @@ func (step TaskStep) Using(prev Step, repo *SourceRepository) Step {
 // are registered with the SourceRepository. If no outputs are specified, the
 // task's entire working directory is registered as an ArtifactSource under the
 // name of the task.
+//
+// The caches specified in the TaskConfig are restored before the script runs
+// and saved after it exits successfully, if the task is part of a job.
+// Failing to restore or save a cache does not fail the task.
 func (step *TaskStep) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
 	var err error
 	var found bool
@@ -233,6 +249,11 @@ func (step *TaskStep) Run(signals <-chan os.Signal, ready chan<- struct{}) error
 			return err
 		}
 
+		err = step.restoreCaches(config.Caches)
+		if err != nil {
+			return err
+		}
+
 		step.delegate.Started()
 
 		step.process, err = step.container.Run(garden.ProcessSpec{
@@ -291,12 +312,34 @@ func (step *TaskStep) Run(signals <-chan os.Signal, ready chan<- struct{}) error
 			return err
 		}
 
+		step.measureResourceUsage()
+
+		if processStatus == 0 {
+			step.saveCaches(config.Caches)
+		}
+
 		step.delegate.Finished(ExitStatus(processStatus))
 
 		return nil
 	}
 }
 
+// measureResourceUsage reports what the task's container used to the
+// delegate. Failing to measure it does not fail the task.
+func (step *TaskStep) measureResourceUsage() {
+	metrics, err := step.container.Metrics()
+	if err != nil {
+		step.logger.Info("failed-to-measure-resource-usage", lager.Data{"error": err.Error()})
+		return
+	}
+
+	step.delegate.ResourceUsageMeasured(db.ResourceUsage{
+		CPUNanoseconds: metrics.CPUStat.Usage,
+		MemoryBytes:    metrics.MemoryStat.TotalRss,
+		DiskBytes:      metrics.DiskStat.TotalBytesUsed,
+	})
+}
+
 func (step *TaskStep) createContainer(compatibleWorkers []worker.Worker, config atc.TaskConfig, signals <-chan os.Signal) (worker.Container, []inputPair, error) {
 	chosenWorker, inputMounts, inputsToStream, err := step.chooseWorkerWithMostVolumes(compatibleWorkers, config.Inputs)
 	if err != nil {
@@ -630,6 +673,85 @@ func (step *TaskStep) setupOutputs(outputs []atc.TaskOutputConfig) error {
 	return nil
 }
 
+// restoreCaches creates each cache's directory, filling it with what the
+// job's last successful build saved for it.
+func (step *TaskStep) restoreCaches(caches []atc.TaskCacheConfig) error {
+	for _, cacheConfig := range caches {
+		dir := step.cacheDir(cacheConfig)
+
+		err := createContainerDir(step.container, dir)
+		if err != nil {
+			return err
+		}
+
+		cache, cacheable := step.taskCache(cacheConfig)
+		if !cacheable {
+			continue
+		}
+
+		logger := step.logger.Session("restore-cache", lager.Data{"path": cache.Path})
+
+		tarStream, found, err := step.taskCaches.Restore(logger, cache)
+		if err != nil || !found {
+			continue
+		}
+
+		err = step.container.StreamIn(garden.StreamInSpec{
+			Path:      dir,
+			TarStream: tarStream,
+		})
+
+		tarStream.Close()
+
+		if err != nil {
+			logger.Info("failed-to-stream-in", lager.Data{"error": err.Error()})
+		}
+	}
+
+	return nil
+}
+
+func (step *TaskStep) saveCaches(caches []atc.TaskCacheConfig) {
+	for _, cacheConfig := range caches {
+		cache, cacheable := step.taskCache(cacheConfig)
+		if !cacheable {
+			continue
+		}
+
+		logger := step.logger.Session("save-cache", lager.Data{"path": cache.Path})
+
+		tarStream, err := step.container.StreamOut(garden.StreamOutSpec{
+			Path: step.cacheDir(cacheConfig),
+		})
+		if err != nil {
+			logger.Info("failed-to-stream-out", lager.Data{"error": err.Error()})
+			continue
+		}
+
+		step.taskCaches.Save(logger, cache, tarStream)
+
+		tarStream.Close()
+	}
+}
+
+// taskCache returns false if the cache can't be kept, as there's nowhere to
+// keep it or no job whose next build would restore it.
+func (step *TaskStep) taskCache(config atc.TaskCacheConfig) (db.TaskCache, bool) {
+	if step.taskCaches == nil || step.metadata.JobName == "" {
+		return db.TaskCache{}, false
+	}
+
+	return db.TaskCache{
+		PipelineID: step.metadata.PipelineID,
+		JobName:    step.metadata.JobName,
+		Path:       path.Clean(config.Path),
+	}, true
+}
+
+func (step *TaskStep) cacheDir(config atc.TaskCacheConfig) string {
+	return path.Join(step.artifactsRoot, config.Path) + "/"
+}
+
 func (TaskStep) mergeTags(tagsOne []string, tagsTwo []string) []string {
 	var ret []string
 
@@ -34,6 +34,7 @@ var _ = Describe("GardenFactory", func() {
 	var (
 		fakeWorkerClient *wfakes.FakeClient
 		fakeTracker      *rfakes.FakeTracker
+		fakeTaskCaches   *execfakes.FakeTaskCacheStore
 
 		factory Factory
 
@@ -51,8 +52,9 @@ var _ = Describe("GardenFactory", func() {
 		fakeWorkerClient = new(wfakes.FakeClient)
 		fakeTracker = new(rfakes.FakeTracker)
 		fakeResourceFetcher := new(rfakes.FakeFetcher)
+		fakeTaskCaches = new(execfakes.FakeTaskCacheStore)
 
-		factory = NewGardenFactory(fakeWorkerClient, fakeTracker, fakeResourceFetcher)
+		factory = NewGardenFactory(fakeWorkerClient, fakeTracker, fakeResourceFetcher, fakeTaskCaches)
 
 		stdoutBuf = gbytes.NewBuffer()
 		stderrBuf = gbytes.NewBuffer()
@@ -1634,6 +1636,116 @@ var _ = Describe("GardenFactory", func() {
 							})
 						})
 
+						Context("when caches are specified", func() {
+							var restored *bytes.Buffer
+							var saved *bytes.Buffer
+
+							lastStreamedInto := func(path string) (garden.StreamInSpec, bool) {
+								for i := fakeContainer.StreamInCallCount() - 1; i >= 0; i-- {
+									spec := fakeContainer.StreamInArgsForCall(i)
+									if spec.Path == path {
+										return spec, true
+									}
+								}
+
+								return garden.StreamInSpec{}, false
+							}
+
+							BeforeEach(func() {
+								fetchedConfig.Caches = []atc.TaskCacheConfig{{Path: "some/cache/"}}
+								configSource.FetchConfigReturns(fetchedConfig, nil)
+
+								workerMetadata.PipelineID = 42
+
+								restored = bytes.NewBufferString("restored-tar")
+								fakeTaskCaches.RestoreReturns(ioutil.NopCloser(restored), true, nil)
+
+								saved = bytes.NewBufferString("saved-tar")
+								fakeContainer.StreamOutReturns(ioutil.NopCloser(saved), nil)
+							})
+
+							It("restores what the job cached into the working directory before running", func() {
+								Eventually(process.Wait()).Should(Receive(BeNil()))
+
+								Expect(fakeTaskCaches.RestoreCallCount()).To(Equal(1))
+								_, cache := fakeTaskCaches.RestoreArgsForCall(0)
+								Expect(cache).To(Equal(db.TaskCache{
+									PipelineID: 42,
+									JobName:    "some-job",
+									Path:       "some/cache",
+								}))
+
+								spec, found := lastStreamedInto("/tmp/build/a1f5c0c1/some/cache/")
+								Expect(found).To(BeTrue())
+								Expect(spec.TarStream).To(Equal(ioutil.NopCloser(restored)))
+							})
+
+							It("saves the cache once the task succeeds", func() {
+								Eventually(process.Wait()).Should(Receive(BeNil()))
+
+								Expect(fakeContainer.StreamOutCallCount()).To(Equal(1))
+								Expect(fakeContainer.StreamOutArgsForCall(0)).To(Equal(garden.StreamOutSpec{
+									Path: "/tmp/build/a1f5c0c1/some/cache/",
+								}))
+
+								Expect(fakeTaskCaches.SaveCallCount()).To(Equal(1))
+								_, cache, tarStream := fakeTaskCaches.SaveArgsForCall(0)
+								Expect(cache.JobName).To(Equal("some-job"))
+								Expect(cache.Path).To(Equal("some/cache"))
+								Expect(tarStream).To(Equal(ioutil.NopCloser(saved)))
+							})
+
+							Context("when nothing has been cached yet", func() {
+								BeforeEach(func() {
+									fakeTaskCaches.RestoreReturns(nil, false, nil)
+								})
+
+								It("creates an empty directory for the cache", func() {
+									Eventually(process.Wait()).Should(Receive(BeNil()))
+
+									spec, found := lastStreamedInto("/tmp/build/a1f5c0c1/some/cache/")
+									Expect(found).To(BeTrue())
+
+									_, err := tar.NewReader(spec.TarStream).Next()
+									Expect(err).To(Equal(io.EOF))
+								})
+							})
+
+							Context("when restoring the cache fails", func() {
+								BeforeEach(func() {
+									fakeTaskCaches.RestoreReturns(nil, false, errors.New("nope"))
+								})
+
+								It("runs the task anyway", func() {
+									Eventually(process.Wait()).Should(Receive(BeNil()))
+									Expect(fakeContainer.RunCallCount()).To(Equal(1))
+								})
+							})
+
+							Context("when the task fails", func() {
+								BeforeEach(func() {
+									fakeProcess.WaitReturns(1, nil)
+								})
+
+								It("doesn't save the cache", func() {
+									Eventually(process.Wait()).Should(Receive(BeNil()))
+									Expect(fakeTaskCaches.SaveCallCount()).To(BeZero())
+								})
+							})
+
+							Context("when the task isn't part of a job", func() {
+								BeforeEach(func() {
+									workerMetadata.JobName = ""
+								})
+
+								It("neither restores nor saves the cache", func() {
+									Eventually(process.Wait()).Should(Receive(BeNil()))
+									Expect(fakeTaskCaches.RestoreCallCount()).To(BeZero())
+									Expect(fakeTaskCaches.SaveCallCount()).To(BeZero())
+								})
+							})
+						})
+
 						Context("when the process exits 0", func() {
 							BeforeEach(func() {
 								fakeProcess.WaitReturns(0, nil)
@@ -1682,6 +1794,40 @@ var _ = Describe("GardenFactory", func() {
 								Expect(sourceMap).To(BeEmpty())
 							})
 
+							Context("when the container's metrics can be measured", func() {
+								BeforeEach(func() {
+									fakeContainer.MetricsReturns(garden.Metrics{
+										CPUStat:    garden.ContainerCPUStat{Usage: 100},
+										MemoryStat: garden.ContainerMemoryStat{TotalRss: 4096},
+										DiskStat:   garden.ContainerDiskStat{TotalBytesUsed: 10},
+									}, nil)
+								})
+
+								It("reports the resource usage to the delegate", func() {
+									Eventually(process.Wait()).Should(Receive(BeNil()))
+
+									Expect(taskDelegate.ResourceUsageMeasuredCallCount()).To(Equal(1))
+									Expect(taskDelegate.ResourceUsageMeasuredArgsForCall(0)).To(Equal(db.ResourceUsage{
+										CPUNanoseconds: 100,
+										MemoryBytes:    4096,
+										DiskBytes:      10,
+									}))
+								})
+							})
+
+							Context("when the container's metrics cannot be measured", func() {
+								BeforeEach(func() {
+									fakeContainer.MetricsReturns(garden.Metrics{}, errors.New("nope"))
+								})
+
+								It("still finishes successfully without reporting usage", func() {
+									Eventually(process.Wait()).Should(Receive(BeNil()))
+
+									Expect(taskDelegate.ResourceUsageMeasuredCallCount()).To(BeZero())
+									Expect(taskDelegate.FinishedCallCount()).To(Equal(1))
+								})
+							})
+
 							Context("when saving the exit status succeeds", func() {
 								BeforeEach(func() {
 									fakeContainer.SetPropertyReturns(nil)
@@ -0,0 +1,41 @@
+package atc
+
+// ExportFormatVersion is bumped whenever Export changes in a way that an
+// ATC that knows an older format could not import.
+const ExportFormatVersion = 1
+
+// Export is a portable copy of pipelines, for moving them between ATCs.
+type Export struct {
+	FormatVersion int                `json:"format_version"`
+	ExportedAt    int64              `json:"exported_at"`
+	Pipelines     []ExportedPipeline `json:"pipelines"`
+}
+
+type ExportedPipeline struct {
+	TeamName string `json:"team_name"`
+	Name     string `json:"name"`
+	Paused   bool   `json:"paused"`
+	Public   bool   `json:"public"`
+
+	Config Config `json:"config"`
+
+	// ResourceVersions are oldest first, by resource name.
+	ResourceVersions map[string][]ExportedVersion `json:"resource_versions,omitempty"`
+
+	// Builds are the most recent finished builds of each job, if asked for.
+	Builds []ExportedBuild `json:"builds,omitempty"`
+}
+
+type ExportedVersion struct {
+	Version  Version         `json:"version"`
+	Metadata []MetadataField `json:"metadata,omitempty"`
+	Enabled  bool            `json:"enabled"`
+}
+
+type ExportedBuild struct {
+	JobName   string      `json:"job_name"`
+	Name      string      `json:"name"`
+	Status    BuildStatus `json:"status"`
+	StartTime int64       `json:"start_time,omitempty"`
+	EndTime   int64       `json:"end_time,omitempty"`
+}
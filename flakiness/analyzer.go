@@ -0,0 +1,98 @@
+package flakiness
+
+import (
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/db"
+)
+
+// minCompared is how many builds have to have been compared before a job can
+// be flagged as flaky, so that one unlucky rerun doesn't do it.
+const minCompared = 3
+
+//go:generate counterfeiter . AnalyzerDB
+
+type AnalyzerDB interface {
+	GetAllPipelines() ([]db.SavedPipeline, error)
+}
+
+type Analyzer interface {
+	Run() error
+}
+
+type analyzer struct {
+	logger            lager.Logger
+	db                AnalyzerDB
+	pipelineDBFactory db.PipelineDBFactory
+	builds            int
+	threshold         float64
+}
+
+// NewAnalyzer returns an analyzer that scores every job by how often its
+// recent builds flipped between succeeding and failing on the same inputs, as
+// a fraction of the builds that could be compared. Jobs scoring at least the
+// threshold are flagged as flaky.
+func NewAnalyzer(
+	logger lager.Logger,
+	db AnalyzerDB,
+	pipelineDBFactory db.PipelineDBFactory,
+	builds int,
+	threshold float64,
+) Analyzer {
+	return &analyzer{
+		logger:            logger,
+		db:                db,
+		pipelineDBFactory: pipelineDBFactory,
+		builds:            builds,
+		threshold:         threshold,
+	}
+}
+
+func (a *analyzer) Run() error {
+	pipelines, err := a.db.GetAllPipelines()
+	if err != nil {
+		a.logger.Error("could-not-get-pipelines", err)
+		return err
+	}
+
+	for _, pipeline := range pipelines {
+		if pipeline.Paused {
+			continue
+		}
+
+		pipelineDB := a.pipelineDBFactory.Build(pipeline)
+
+		for _, job := range pipeline.Config.Jobs {
+			logger := a.logger.Session("analyze", lager.Data{
+				"pipeline": pipeline.Name,
+				"job":      job.Name,
+			})
+
+			outcomes, err := pipelineDB.GetJobOutcomes(job.Name, a.builds)
+			if err != nil {
+				logger.Error("could-not-get-job-outcomes", err)
+				return err
+			}
+
+			var flakiness float64
+			if outcomes.Compared > 0 {
+				flakiness = float64(outcomes.Flipped) / float64(outcomes.Compared)
+			}
+
+			flaky := outcomes.Compared >= minCompared && flakiness >= a.threshold
+			if flaky {
+				logger.Info("flaky", lager.Data{
+					"flakiness": flakiness,
+					"compared":  outcomes.Compared,
+				})
+			}
+
+			err = pipelineDB.SaveJobFlakiness(job.Name, flakiness, flaky)
+			if err != nil {
+				logger.Error("could-not-save-job-flakiness", err)
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,171 @@
+package flakiness_test
+
+import (
+	"errors"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/db/dbfakes"
+	. "github.com/concourse/atc/flakiness"
+	"github.com/concourse/atc/flakiness/flakinessfakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Analyzer", func() {
+	var (
+		fakeAnalyzerDB        *flakinessfakes.FakeAnalyzerDB
+		fakePipelineDBFactory *dbfakes.FakePipelineDBFactory
+		fakePipelineDB        *dbfakes.FakePipelineDB
+
+		analyzer Analyzer
+		runErr   error
+	)
+
+	BeforeEach(func() {
+		fakeAnalyzerDB = new(flakinessfakes.FakeAnalyzerDB)
+		fakePipelineDBFactory = new(dbfakes.FakePipelineDBFactory)
+
+		fakePipelineDB = new(dbfakes.FakePipelineDB)
+		fakePipelineDBFactory.BuildReturns(fakePipelineDB)
+
+		fakeAnalyzerDB.GetAllPipelinesReturns([]db.SavedPipeline{
+			{
+				ID: 1,
+				Pipeline: db.Pipeline{
+					Name: "some-pipeline",
+					Config: atc.Config{
+						Jobs: atc.JobConfigs{
+							{Name: "some-job"},
+						},
+					},
+				},
+			},
+			{
+				ID:     2,
+				Paused: true,
+				Pipeline: db.Pipeline{
+					Name: "paused-pipeline",
+					Config: atc.Config{
+						Jobs: atc.JobConfigs{
+							{Name: "some-paused-job"},
+						},
+					},
+				},
+			},
+		}, nil)
+
+		analyzer = NewAnalyzer(
+			lagertest.NewTestLogger("test"),
+			fakeAnalyzerDB,
+			fakePipelineDBFactory,
+			50,
+			0.25,
+		)
+	})
+
+	JustBeforeEach(func() {
+		runErr = analyzer.Run()
+	})
+
+	It("looks at the recent builds of the jobs of unpaused pipelines", func() {
+		Expect(runErr).NotTo(HaveOccurred())
+
+		Expect(fakePipelineDBFactory.BuildCallCount()).To(Equal(1))
+		Expect(fakePipelineDBFactory.BuildArgsForCall(0).ID).To(Equal(1))
+
+		Expect(fakePipelineDB.GetJobOutcomesCallCount()).To(Equal(1))
+		jobName, builds := fakePipelineDB.GetJobOutcomesArgsForCall(0)
+		Expect(jobName).To(Equal("some-job"))
+		Expect(builds).To(Equal(50))
+	})
+
+	Context("when the job's outcomes flip often enough", func() {
+		BeforeEach(func() {
+			fakePipelineDB.GetJobOutcomesReturns(db.JobOutcomes{Compared: 8, Flipped: 2}, nil)
+		})
+
+		It("saves it as flaky", func() {
+			Expect(fakePipelineDB.SaveJobFlakinessCallCount()).To(Equal(1))
+			jobName, flakiness, flaky := fakePipelineDB.SaveJobFlakinessArgsForCall(0)
+			Expect(jobName).To(Equal("some-job"))
+			Expect(flakiness).To(Equal(0.25))
+			Expect(flaky).To(BeTrue())
+		})
+	})
+
+	Context("when the job's outcomes rarely flip", func() {
+		BeforeEach(func() {
+			fakePipelineDB.GetJobOutcomesReturns(db.JobOutcomes{Compared: 10, Flipped: 1}, nil)
+		})
+
+		It("saves it as not flaky", func() {
+			_, flakiness, flaky := fakePipelineDB.SaveJobFlakinessArgsForCall(0)
+			Expect(flakiness).To(Equal(0.1))
+			Expect(flaky).To(BeFalse())
+		})
+	})
+
+	Context("when too few builds could be compared", func() {
+		BeforeEach(func() {
+			fakePipelineDB.GetJobOutcomesReturns(db.JobOutcomes{Compared: 2, Flipped: 2}, nil)
+		})
+
+		It("saves its flakiness without flagging it", func() {
+			_, flakiness, flaky := fakePipelineDB.SaveJobFlakinessArgsForCall(0)
+			Expect(flakiness).To(Equal(1.0))
+			Expect(flaky).To(BeFalse())
+		})
+	})
+
+	Context("when no builds could be compared", func() {
+		BeforeEach(func() {
+			fakePipelineDB.GetJobOutcomesReturns(db.JobOutcomes{}, nil)
+		})
+
+		It("saves it as not flaky at all", func() {
+			_, flakiness, flaky := fakePipelineDB.SaveJobFlakinessArgsForCall(0)
+			Expect(flakiness).To(BeZero())
+			Expect(flaky).To(BeFalse())
+		})
+	})
+
+	Context("when getting the pipelines fails", func() {
+		disaster := errors.New("nope")
+
+		BeforeEach(func() {
+			fakeAnalyzerDB.GetAllPipelinesReturns(nil, disaster)
+		})
+
+		It("returns the error", func() {
+			Expect(runErr).To(Equal(disaster))
+		})
+	})
+
+	Context("when getting the job's outcomes fails", func() {
+		disaster := errors.New("nope")
+
+		BeforeEach(func() {
+			fakePipelineDB.GetJobOutcomesReturns(db.JobOutcomes{}, disaster)
+		})
+
+		It("returns the error without saving anything", func() {
+			Expect(runErr).To(Equal(disaster))
+			Expect(fakePipelineDB.SaveJobFlakinessCallCount()).To(BeZero())
+		})
+	})
+
+	Context("when saving the job's flakiness fails", func() {
+		disaster := errors.New("nope")
+
+		BeforeEach(func() {
+			fakePipelineDB.SaveJobFlakinessReturns(disaster)
+		})
+
+		It("returns the error", func() {
+			Expect(runErr).To(Equal(disaster))
+		})
+	})
+})
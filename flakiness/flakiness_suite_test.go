@@ -0,0 +1,13 @@
+package flakiness_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestFlakiness(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Flakiness Suite")
+}
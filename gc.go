@@ -0,0 +1,26 @@
+package atc
+
+// GCStatus reports what garbage collection has been doing. The last pass's
+// start is a Unix timestamp and its duration is in milliseconds; both are
+// left out if no pass has completed yet.
+type GCStatus struct {
+	LastPassStartedAt  int64 `json:"last_pass_started_at,omitempty"`
+	LastPassDurationMS int64 `json:"last_pass_duration_ms,omitempty"`
+
+	Workers []WorkerGCStats `json:"workers"`
+}
+
+type WorkerGCStats struct {
+	WorkerName string   `json:"worker_name"`
+	Containers GCCounts `json:"containers"`
+	Volumes    GCCounts `json:"volumes"`
+}
+
+// GCCounts counts what was created on a worker, what was destroyed once it
+// expired or was no longer needed, and what the worker no longer had by the
+// time it was looked up.
+type GCCounts struct {
+	Created   int `json:"created"`
+	Destroyed int `json:"destroyed"`
+	Orphaned  int `json:"orphaned"`
+}
@@ -0,0 +1,42 @@
+package health
+
+import (
+	"errors"
+
+	"github.com/concourse/atc/db"
+)
+
+//go:generate counterfeiter . Pinger
+
+type Pinger interface {
+	Ping() error
+}
+
+// DatabaseCheck checks that the database can be reached.
+func DatabaseCheck(pinger Pinger) Check {
+	return pinger.Ping
+}
+
+//go:generate counterfeiter . WorkersDB
+
+type WorkersDB interface {
+	Workers() ([]db.SavedWorker, error)
+}
+
+// WorkersCheck checks that there is at least one worker to run builds on.
+// Workers drop out of the database when they stop heartbeating, so those
+// listed have been reachable recently.
+func WorkersCheck(workersDB WorkersDB) Check {
+	return func() error {
+		workers, err := workersDB.Workers()
+		if err != nil {
+			return err
+		}
+
+		if len(workers) == 0 {
+			return errors.New("no workers")
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,49 @@
+package health_test
+
+import (
+	"errors"
+
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/health"
+	"github.com/concourse/atc/health/healthfakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Checks", func() {
+	Describe("DatabaseCheck", func() {
+		It("fails when the database can't be pinged", func() {
+			fakePinger := new(healthfakes.FakePinger)
+			check := health.DatabaseCheck(fakePinger)
+
+			Expect(check()).To(Succeed())
+
+			fakePinger.PingReturns(errors.New("disaster"))
+			Expect(check()).To(MatchError("disaster"))
+		})
+	})
+
+	Describe("WorkersCheck", func() {
+		var fakeWorkersDB *healthfakes.FakeWorkersDB
+
+		BeforeEach(func() {
+			fakeWorkersDB = new(healthfakes.FakeWorkersDB)
+		})
+
+		It("passes when there are workers", func() {
+			fakeWorkersDB.WorkersReturns([]db.SavedWorker{{}}, nil)
+			Expect(health.WorkersCheck(fakeWorkersDB)()).To(Succeed())
+		})
+
+		It("fails when there are no workers", func() {
+			fakeWorkersDB.WorkersReturns([]db.SavedWorker{}, nil)
+			Expect(health.WorkersCheck(fakeWorkersDB)()).To(MatchError("no workers"))
+		})
+
+		It("fails when the workers can't be listed", func() {
+			fakeWorkersDB.WorkersReturns(nil, errors.New("disaster"))
+			Expect(health.WorkersCheck(fakeWorkersDB)()).To(MatchError("disaster"))
+		})
+	})
+})
@@ -0,0 +1,111 @@
+package health
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+)
+
+// checkTimeout bounds how long a hung dependency can hold up a probe; load
+// balancers tend to give up after a few seconds anyway.
+const checkTimeout = 5 * time.Second
+
+// Check returns an error if something the ATC depends on is unavailable.
+type Check func() error
+
+// Report is what /readyz responds with. Each check is "ok" or why it failed.
+type Report struct {
+	Ready  bool              `json:"ready"`
+	Checks map[string]string `json:"checks"`
+}
+
+// NewHandler serves /healthz, which says the process is up, and /readyz,
+// which says whether it should be sent traffic: only while all of the checks
+// pass and it is not draining.
+func NewHandler(logger lager.Logger, drain <-chan struct{}, checks map[string]Check) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	mux.Handle("/readyz", readinessHandler{
+		logger: logger.Session("readiness"),
+		drain:  drain,
+		checks: checks,
+	})
+
+	return mux
+}
+
+type readinessHandler struct {
+	logger lager.Logger
+	drain  <-chan struct{}
+	checks map[string]Check
+}
+
+func (handler readinessHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	report := Report{
+		Ready:  true,
+		Checks: map[string]string{},
+	}
+
+	select {
+	case <-handler.drain:
+		report.Ready = false
+		report.Checks["drain"] = "draining"
+	default:
+		report.Checks["drain"] = "ok"
+	}
+
+	names := []string{}
+	for name := range handler.checks {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	results := map[string]chan error{}
+	for _, name := range names {
+		result := make(chan error, 1)
+		results[name] = result
+
+		go func(check Check) {
+			result <- check()
+		}(handler.checks[name])
+	}
+
+	timeout := time.After(checkTimeout)
+
+	for _, name := range names {
+		var err error
+
+		select {
+		case err = <-results[name]:
+		case <-timeout:
+			err = fmt.Errorf("timed out after %s", checkTimeout)
+		}
+
+		if err != nil {
+			handler.logger.Info("check-failed", lager.Data{"check": name, "error": err.Error()})
+			report.Ready = false
+			report.Checks[name] = err.Error()
+		} else {
+			report.Checks[name] = "ok"
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if report.Ready {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,102 @@
+package health_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	"github.com/concourse/atc/health"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Handler", func() {
+	var (
+		drain      chan struct{}
+		checkErr   error
+		recorder   *httptest.ResponseRecorder
+		handler    http.Handler
+		requestURL string
+	)
+
+	BeforeEach(func() {
+		drain = make(chan struct{})
+		checkErr = nil
+		recorder = httptest.NewRecorder()
+	})
+
+	JustBeforeEach(func() {
+		handler = health.NewHandler(lagertest.NewTestLogger("test"), drain, map[string]health.Check{
+			"database": func() error { return checkErr },
+		})
+
+		request, err := http.NewRequest("GET", requestURL, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(recorder, request)
+	})
+
+	report := func() health.Report {
+		var report health.Report
+		err := json.Unmarshal(recorder.Body.Bytes(), &report)
+		Expect(err).NotTo(HaveOccurred())
+		return report
+	}
+
+	Describe("/healthz", func() {
+		BeforeEach(func() {
+			requestURL = "/healthz"
+			checkErr = errors.New("disaster")
+			close(drain)
+		})
+
+		It("is ok regardless of dependencies and draining", func() {
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+		})
+	})
+
+	Describe("/readyz", func() {
+		BeforeEach(func() {
+			requestURL = "/readyz"
+		})
+
+		Context("when the checks pass and it is not draining", func() {
+			It("is ready", func() {
+				Expect(recorder.Code).To(Equal(http.StatusOK))
+				Expect(report()).To(Equal(health.Report{
+					Ready: true,
+					Checks: map[string]string{
+						"database": "ok",
+						"drain":    "ok",
+					},
+				}))
+			})
+		})
+
+		Context("when a check fails", func() {
+			BeforeEach(func() {
+				checkErr = errors.New("disaster")
+			})
+
+			It("is not ready, and says why", func() {
+				Expect(recorder.Code).To(Equal(http.StatusServiceUnavailable))
+				Expect(report().Ready).To(BeFalse())
+				Expect(report().Checks["database"]).To(Equal("disaster"))
+			})
+		})
+
+		Context("when draining", func() {
+			BeforeEach(func() {
+				close(drain)
+			})
+
+			It("is not ready", func() {
+				Expect(recorder.Code).To(Equal(http.StatusServiceUnavailable))
+				Expect(report().Checks["drain"]).To(Equal("draining"))
+			})
+		})
+	})
+})
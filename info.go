@@ -2,4 +2,17 @@ package atc
 
 type Info struct {
 	Version string `json:"version"`
+
+	// Leader is the peer URL of the ATC running the scheduler and radar.
+	Leader string `json:"leader,omitempty"`
+
+	// CLIs are the fly binaries that can be downloaded from the ATC.
+	CLIs []CLI `json:"clis,omitempty"`
+}
+
+type CLI struct {
+	Platform string `json:"platform"`
+	Arch     string `json:"arch"`
+	Version  string `json:"version"`
+	SHA256   string `json:"sha256"`
 }
@@ -4,11 +4,18 @@ type Job struct {
 	Name                 string `json:"name"`
 	URL                  string `json:"url"`
 	Paused               bool   `json:"paused,omitempty"`
+	ManualOnly           bool   `json:"manual_only,omitempty"`
 	FirstLoggedBuildID   int    `json:"first_logged_build_id,omitempty"`
 	DisableManualTrigger bool   `json:"disable_manual_trigger,omitempty"`
 	NextBuild            *Build `json:"next_build"`
 	FinishedBuild        *Build `json:"finished_build"`
 
+	// Flakiness is the fraction of the job's recent builds that flipped
+	// between succeeding and failing on the same inputs. Flaky is set once
+	// it's over the ATC's threshold.
+	Flakiness float64 `json:"flakiness,omitempty"`
+	Flaky     bool    `json:"flaky,omitempty"`
+
 	Inputs  []JobInput  `json:"inputs"`
 	Outputs []JobOutput `json:"outputs"`
 
@@ -36,3 +43,75 @@ type BuildInput struct {
 	Version  Version  `json:"version"`
 	Tags     []string `json:"tags,omitempty"`
 }
+
+// JobTrigger is what may be given when triggering a job by hand. Params are
+// checked against the job's trigger_params.
+type JobTrigger struct {
+	Params map[string]string `json:"params,omitempty"`
+}
+
+type JobSchedule struct {
+	Schedule string  `json:"schedule"`
+	Upcoming []int64 `json:"upcoming"`
+}
+
+// JobScheduling is why the scheduler didn't start a build of the job the last
+// time it looked at it, and why any of its inputs couldn't be found.
+type JobScheduling struct {
+	Reason              SchedulingReason    `json:"reason"`
+	MissingInputReasons MissingInputReasons `json:"missing_input_reasons"`
+	CheckedAt           int64               `json:"checked_at"`
+}
+
+type SchedulingReason string
+
+const (
+	SchedulingReasonNoPendingBuilds          SchedulingReason = "no-pending-builds"
+	SchedulingReasonMaxInFlightReached       SchedulingReason = "max-in-flight-reached"
+	SchedulingReasonInputsNotSatisfied       SchedulingReason = "inputs-not-satisfied"
+	SchedulingReasonPipelinePaused           SchedulingReason = "pipeline-paused"
+	SchedulingReasonJobPaused                SchedulingReason = "job-paused"
+	SchedulingReasonGlobalMaxInFlightReached SchedulingReason = "global-max-in-flight-reached"
+	SchedulingReasonMaintenanceWindow        SchedulingReason = "maintenance-window"
+	SchedulingReasonFairShareReached         SchedulingReason = "fair-share-reached"
+	SchedulingReasonSerialGroupBusy          SchedulingReason = "serial-group-busy"
+	SchedulingReasonCheckingResources        SchedulingReason = "checking-resources"
+)
+
+// JobCache is a directory that the job's tasks cache between builds. Its
+// size is once compressed.
+type JobCache struct {
+	Path      string `json:"path"`
+	SizeBytes int64  `json:"size_bytes"`
+	SavedAt   int64  `json:"saved_at"`
+}
+
+// ExpiringBuild is a finished build of a job whose containers and artifacts
+// will be reaped the next time its artifact retention is enforced.
+type ExpiringBuild struct {
+	ID        int      `json:"id"`
+	Name      string   `json:"name"`
+	Status    string   `json:"status"`
+	EndTime   int64    `json:"end_time,omitempty"`
+	Artifacts []string `json:"artifacts"`
+}
+
+// JobStats summarizes a job's recent builds over each of the windows asked
+// for. Durations are in seconds.
+type JobStats struct {
+	Windows []JobStatsWindow `json:"windows"`
+}
+
+type JobStatsWindow struct {
+	Window string `json:"window"`
+
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+	Errored   int `json:"errored"`
+	Aborted   int `json:"aborted"`
+	TimedOut  int `json:"timed_out"`
+
+	MeanDuration   int64 `json:"mean_duration"`
+	MedianDuration int64 `json:"median_duration"`
+	P95Duration    int64 `json:"p95_duration"`
+}
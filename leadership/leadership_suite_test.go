@@ -0,0 +1,13 @@
+package leadership_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestLeadership(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Leadership Suite")
+}
@@ -0,0 +1,124 @@
+// This file was generated by counterfeiter
+package leadershipfakes
+
+import (
+	"sync"
+	"time"
+
+	"github.com/concourse/atc/leadership"
+)
+
+type FakeLeaderDB struct {
+	AcquireLeadershipStub        func(holder string, ttl time.Duration) (bool, error)
+	acquireLeadershipMutex       sync.RWMutex
+	acquireLeadershipArgsForCall []struct {
+		holder string
+		ttl    time.Duration
+	}
+	acquireLeadershipReturns struct {
+		result1 bool
+		result2 error
+	}
+	ReleaseLeadershipStub        func(holder string) error
+	releaseLeadershipMutex       sync.RWMutex
+	releaseLeadershipArgsForCall []struct {
+		holder string
+	}
+	releaseLeadershipReturns struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeLeaderDB) AcquireLeadership(holder string, ttl time.Duration) (bool, error) {
+	fake.acquireLeadershipMutex.Lock()
+	fake.acquireLeadershipArgsForCall = append(fake.acquireLeadershipArgsForCall, struct {
+		holder string
+		ttl    time.Duration
+	}{holder, ttl})
+	fake.recordInvocation("AcquireLeadership", []interface{}{holder, ttl})
+	fake.acquireLeadershipMutex.Unlock()
+	if fake.AcquireLeadershipStub != nil {
+		return fake.AcquireLeadershipStub(holder, ttl)
+	} else {
+		return fake.acquireLeadershipReturns.result1, fake.acquireLeadershipReturns.result2
+	}
+}
+
+func (fake *FakeLeaderDB) AcquireLeadershipCallCount() int {
+	fake.acquireLeadershipMutex.RLock()
+	defer fake.acquireLeadershipMutex.RUnlock()
+	return len(fake.acquireLeadershipArgsForCall)
+}
+
+func (fake *FakeLeaderDB) AcquireLeadershipArgsForCall(i int) (string, time.Duration) {
+	fake.acquireLeadershipMutex.RLock()
+	defer fake.acquireLeadershipMutex.RUnlock()
+	return fake.acquireLeadershipArgsForCall[i].holder, fake.acquireLeadershipArgsForCall[i].ttl
+}
+
+func (fake *FakeLeaderDB) AcquireLeadershipReturns(result1 bool, result2 error) {
+	fake.AcquireLeadershipStub = nil
+	fake.acquireLeadershipReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeLeaderDB) ReleaseLeadership(holder string) error {
+	fake.releaseLeadershipMutex.Lock()
+	fake.releaseLeadershipArgsForCall = append(fake.releaseLeadershipArgsForCall, struct {
+		holder string
+	}{holder})
+	fake.recordInvocation("ReleaseLeadership", []interface{}{holder})
+	fake.releaseLeadershipMutex.Unlock()
+	if fake.ReleaseLeadershipStub != nil {
+		return fake.ReleaseLeadershipStub(holder)
+	} else {
+		return fake.releaseLeadershipReturns.result1
+	}
+}
+
+func (fake *FakeLeaderDB) ReleaseLeadershipCallCount() int {
+	fake.releaseLeadershipMutex.RLock()
+	defer fake.releaseLeadershipMutex.RUnlock()
+	return len(fake.releaseLeadershipArgsForCall)
+}
+
+func (fake *FakeLeaderDB) ReleaseLeadershipArgsForCall(i int) string {
+	fake.releaseLeadershipMutex.RLock()
+	defer fake.releaseLeadershipMutex.RUnlock()
+	return fake.releaseLeadershipArgsForCall[i].holder
+}
+
+func (fake *FakeLeaderDB) ReleaseLeadershipReturns(result1 error) {
+	fake.ReleaseLeadershipStub = nil
+	fake.releaseLeadershipReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeLeaderDB) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.acquireLeadershipMutex.RLock()
+	defer fake.acquireLeadershipMutex.RUnlock()
+	fake.releaseLeadershipMutex.RLock()
+	defer fake.releaseLeadershipMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeLeaderDB) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ leadership.LeaderDB = new(FakeLeaderDB)
@@ -0,0 +1,104 @@
+package leadership
+
+import (
+	"os"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+	"github.com/tedsuo/ifrit"
+)
+
+//go:generate counterfeiter . LeaderDB
+
+type LeaderDB interface {
+	AcquireLeadership(holder string, ttl time.Duration) (bool, error)
+	ReleaseLeadership(holder string) error
+}
+
+// NewRunner runs the given runner only while this ATC is the leader, so
+// that, of all the ATCs sharing a database, only one ever runs it at a time.
+// It tries to become the leader straight away and then every third of the
+// ttl, renewing its leadership for as long as it stays on. Once it can't, the
+// runner is stopped, and it's started again should this ATC take over again
+// later, as it will when the leader goes away and its leadership expires.
+//
+// The Runner is ready as soon as it starts, whether it's the leader or not.
+func NewRunner(
+	logger lager.Logger,
+	runner ifrit.Runner,
+	holder string,
+	db LeaderDB,
+	clock clock.Clock,
+	ttl time.Duration,
+) ifrit.Runner {
+	return ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+		logger = logger.Session("leadership", lager.Data{"holder": holder})
+
+		close(ready)
+
+		ticker := clock.NewTicker(ttl / 3)
+		defer ticker.Stop()
+
+		var process ifrit.Process
+		var exited <-chan error
+		var renewed time.Time
+
+		stop := func() {
+			process.Signal(os.Interrupt)
+			<-exited
+
+			process = nil
+			exited = nil
+		}
+
+		for {
+			acquired, err := db.AcquireLeadership(holder, ttl)
+			if err != nil {
+				logger.Error("failed-to-acquire-leadership", err)
+
+				// as far as anyone else is concerned, leadership lasts until it
+				// expires, so hang on until then in case it's only a blip
+				if process != nil && clock.Since(renewed) >= ttl {
+					logger.Info("stepping-down")
+					stop()
+				}
+			} else if acquired {
+				renewed = clock.Now()
+
+				if process == nil {
+					logger.Info("became-leader")
+					process = ifrit.Background(runner)
+					exited = process.Wait()
+				}
+			} else if process != nil {
+				logger.Info("lost-leadership")
+				stop()
+			}
+
+			select {
+			case <-ticker.C():
+			case err := <-exited:
+				logger.Info("exited-while-leading")
+
+				releaseErr := db.ReleaseLeadership(holder)
+				if releaseErr != nil {
+					logger.Error("failed-to-release-leadership", releaseErr)
+				}
+
+				return err
+			case <-signals:
+				if process != nil {
+					stop()
+				}
+
+				err := db.ReleaseLeadership(holder)
+				if err != nil {
+					logger.Error("failed-to-release-leadership", err)
+				}
+
+				return nil
+			}
+		}
+	})
+}
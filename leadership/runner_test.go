@@ -0,0 +1,196 @@
+package leadership_test
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/fake_runner"
+
+	. "github.com/concourse/atc/leadership"
+	"github.com/concourse/atc/leadership/leadershipfakes"
+)
+
+var _ = Describe("Runner", func() {
+	var (
+		fakeDB     *leadershipfakes.FakeLeaderDB
+		fakeRunner *fake_runner.FakeRunner
+		fakeClock  *fakeclock.FakeClock
+
+		ttl time.Duration
+
+		runs    chan (<-chan os.Signal)
+		exiting chan error
+
+		process ifrit.Process
+	)
+
+	BeforeEach(func() {
+		fakeDB = new(leadershipfakes.FakeLeaderDB)
+		fakeRunner = new(fake_runner.FakeRunner)
+		fakeClock = fakeclock.NewFakeClock(time.Unix(123, 456))
+
+		ttl = 30 * time.Second
+
+		runs = make(chan (<-chan os.Signal), 10)
+		exiting = make(chan error, 1)
+
+		r := runs
+		e := exiting
+		fakeRunner.RunStub = func(signals <-chan os.Signal, ready chan<- struct{}) error {
+			close(ready)
+			r <- signals
+
+			select {
+			case <-signals:
+				return nil
+			case err := <-e:
+				return err
+			}
+		}
+	})
+
+	JustBeforeEach(func() {
+		process = ifrit.Invoke(NewRunner(
+			lagertest.NewTestLogger("test"),
+			fakeRunner,
+			"some-atc",
+			fakeDB,
+			fakeClock,
+			ttl,
+		))
+	})
+
+	AfterEach(func() {
+		process.Signal(os.Interrupt)
+		Eventually(process.Wait()).Should(Receive())
+	})
+
+	It("tries to become the leader straight away", func() {
+		Eventually(fakeDB.AcquireLeadershipCallCount).Should(Equal(1))
+
+		holder, actualTTL := fakeDB.AcquireLeadershipArgsForCall(0)
+		Expect(holder).To(Equal("some-atc"))
+		Expect(actualTTL).To(Equal(ttl))
+	})
+
+	Context("when it becomes the leader", func() {
+		BeforeEach(func() {
+			fakeDB.AcquireLeadershipReturns(true, nil)
+		})
+
+		It("starts the runner", func() {
+			Eventually(runs).Should(Receive())
+		})
+
+		It("renews its leadership every third of the ttl without starting the runner again", func() {
+			Eventually(runs).Should(Receive())
+
+			fakeClock.WaitForWatcherAndIncrement(ttl / 3)
+			Eventually(fakeDB.AcquireLeadershipCallCount).Should(Equal(2))
+
+			fakeClock.Increment(ttl / 3)
+			Eventually(fakeDB.AcquireLeadershipCallCount).Should(Equal(3))
+
+			Consistently(runs).ShouldNot(Receive())
+		})
+
+		Context("and then loses it", func() {
+			It("stops the runner, and starts it again once it's the leader again", func() {
+				Eventually(runs).Should(Receive())
+
+				fakeDB.AcquireLeadershipReturns(false, nil)
+				fakeClock.WaitForWatcherAndIncrement(ttl / 3)
+
+				Eventually(fakeDB.AcquireLeadershipCallCount).Should(Equal(2))
+				Consistently(runs).ShouldNot(Receive())
+
+				fakeDB.AcquireLeadershipReturns(true, nil)
+				fakeClock.Increment(ttl / 3)
+
+				Eventually(runs).Should(Receive())
+				Expect(fakeRunner.RunCallCount()).To(Equal(2))
+			})
+		})
+
+		Context("and then fails to renew it", func() {
+			BeforeEach(func() {
+				fakeDB.AcquireLeadershipStub = func(string, time.Duration) (bool, error) {
+					if fakeDB.AcquireLeadershipCallCount() == 1 {
+						return true, nil
+					}
+
+					return false, errors.New("disaster")
+				}
+			})
+
+			It("keeps the runner going until its leadership would have expired", func() {
+				Eventually(runs).Should(Receive())
+
+				fakeClock.WaitForWatcherAndIncrement(ttl / 3)
+				Eventually(fakeDB.AcquireLeadershipCallCount).Should(Equal(2))
+
+				fakeClock.Increment(ttl / 3)
+				Eventually(fakeDB.AcquireLeadershipCallCount).Should(Equal(3))
+
+				fakeClock.Increment(ttl / 3)
+				Eventually(fakeDB.AcquireLeadershipCallCount).Should(Equal(4))
+
+				fakeDB.AcquireLeadershipStub = nil
+				fakeDB.AcquireLeadershipReturns(true, nil)
+				fakeClock.Increment(ttl / 3)
+
+				Eventually(runs).Should(Receive())
+				Expect(fakeRunner.RunCallCount()).To(Equal(2))
+			})
+		})
+
+		Context("when the runner exits", func() {
+			It("exits with its error, stepping down", func() {
+				Eventually(runs).Should(Receive())
+
+				exiting <- errors.New("oh no")
+
+				Eventually(process.Wait()).Should(Receive(MatchError("oh no")))
+				Expect(fakeDB.ReleaseLeadershipCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when signalled", func() {
+			It("stops the runner and steps down", func() {
+				Eventually(runs).Should(Receive())
+
+				process.Signal(os.Interrupt)
+				Eventually(process.Wait()).Should(Receive(BeNil()))
+
+				Expect(fakeDB.ReleaseLeadershipCallCount()).To(Equal(1))
+				Expect(fakeDB.ReleaseLeadershipArgsForCall(0)).To(Equal("some-atc"))
+			})
+		})
+	})
+
+	Context("when someone else is the leader", func() {
+		BeforeEach(func() {
+			fakeDB.AcquireLeadershipReturns(false, nil)
+		})
+
+		It("does not start the runner", func() {
+			Eventually(fakeDB.AcquireLeadershipCallCount).Should(Equal(1))
+			Consistently(runs).ShouldNot(Receive())
+		})
+
+		It("takes over once it can", func() {
+			Eventually(fakeDB.AcquireLeadershipCallCount).Should(Equal(1))
+
+			fakeDB.AcquireLeadershipReturns(true, nil)
+			fakeClock.WaitForWatcherAndIncrement(ttl / 3)
+
+			Eventually(runs).Should(Receive())
+		})
+	})
+})
@@ -43,28 +43,37 @@ func NewRunner(
 				lockLogger := logger.Session("lock-task", lager.Data{"task-name": taskName})
 				lockLogger.Info("tick")
 
-				lock, acquired, err := db.GetTaskLock(lockLogger, taskName)
-
-				if err != nil {
-					lockLogger.Error("failed-to-get-lock", err)
-					break
-				}
-
-				if !acquired {
-					lockLogger.Debug("did-not-get-lock")
-					break
-				}
-
-				lockLogger.Info("run-task", lager.Data{"task-name": taskName})
-				err = task.Run()
-				if err != nil {
-					lockLogger.Error("failed-to-run-task", err, lager.Data{"task-name": taskName})
-				}
-
-				lock.Release()
+				RunOnce(lockLogger, task, taskName, db)
 			case <-signals:
 				return nil
 			}
 		}
 	})
 }
+
+// RunOnce runs the task right away, unless it's already running somewhere
+// else, in which case it returns false.
+func RunOnce(logger lager.Logger, task Task, taskName string, db RunnerDB) (bool, error) {
+	lock, acquired, err := db.GetTaskLock(logger, taskName)
+	if err != nil {
+		logger.Error("failed-to-get-lock", err)
+		return false, err
+	}
+
+	if !acquired {
+		logger.Debug("did-not-get-lock")
+		return false, nil
+	}
+
+	defer lock.Release()
+
+	logger.Info("run-task", lager.Data{"task-name": taskName})
+
+	err = task.Run()
+	if err != nil {
+		logger.Error("failed-to-run-task", err, lager.Data{"task-name": taskName})
+		return true, err
+	}
+
+	return true, nil
+}
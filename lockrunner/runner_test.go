@@ -118,3 +118,65 @@ var _ = Describe("Runner", func() {
 		})
 	})
 })
+
+var _ = Describe("RunOnce", func() {
+	var (
+		fakeDB    *lockrunnerfakes.FakeRunnerDB
+		fakeTask  *lockrunnerfakes.FakeTask
+		fakeLease *dbfakes.FakeLease
+
+		ran    bool
+		runErr error
+	)
+
+	BeforeEach(func() {
+		fakeDB = new(lockrunnerfakes.FakeRunnerDB)
+		fakeTask = new(lockrunnerfakes.FakeTask)
+		fakeLease = new(dbfakes.FakeLease)
+	})
+
+	JustBeforeEach(func() {
+		ran, runErr = RunOnce(lagertest.NewTestLogger("test"), fakeTask, "some-task-name", fakeDB)
+	})
+
+	Context("when the lock is acquired", func() {
+		BeforeEach(func() {
+			fakeDB.GetTaskLockReturns(fakeLease, true, nil)
+		})
+
+		It("runs the task and releases the lock", func() {
+			Expect(ran).To(BeTrue())
+			Expect(runErr).NotTo(HaveOccurred())
+
+			_, actualTaskName := fakeDB.GetTaskLockArgsForCall(0)
+			Expect(actualTaskName).To(Equal("some-task-name"))
+
+			Expect(fakeTask.RunCallCount()).To(Equal(1))
+			Expect(fakeLease.BreakCallCount()).To(Equal(1))
+		})
+
+		Context("when the task fails", func() {
+			BeforeEach(func() {
+				fakeTask.RunReturns(errors.New("disaster"))
+			})
+
+			It("returns the error and releases the lock", func() {
+				Expect(ran).To(BeTrue())
+				Expect(runErr).To(MatchError("disaster"))
+				Expect(fakeLease.BreakCallCount()).To(Equal(1))
+			})
+		})
+	})
+
+	Context("when the task is already running elsewhere", func() {
+		BeforeEach(func() {
+			fakeDB.GetTaskLockReturns(nil, false, nil)
+		})
+
+		It("doesn't run it", func() {
+			Expect(ran).To(BeFalse())
+			Expect(runErr).NotTo(HaveOccurred())
+			Expect(fakeTask.RunCallCount()).To(BeZero())
+		})
+	})
+})
@@ -9,6 +9,7 @@ import (
 	"github.com/concourse/atc/resource"
 	"github.com/concourse/atc/worker"
 
+	"code.cloudfoundry.org/clock"
 	"code.cloudfoundry.org/lager"
 )
 
@@ -19,8 +20,13 @@ type BaggageCollectorDB interface {
 	GetAllPipelines() ([]db.SavedPipeline, error)
 	GetVolumes() ([]db.SavedVolume, error)
 	GetVolumesForOneOffBuildImageResources() ([]db.SavedVolume, error)
+	SaveGCPass(db.GCPass) error
 }
 
+// TaskName is what the baggage collector is locked as while it runs, so
+// that only one ATC collects at a time.
+const TaskName = "baggage-collector"
+
 //go:generate counterfeiter . BaggageCollector
 
 type BaggageCollector interface {
@@ -34,11 +40,14 @@ type baggageCollector struct {
 	pipelineDBFactory                   db.PipelineDBFactory
 	oldResourceGracePeriod              time.Duration
 	oneOffBuildImageResourceGracePeriod time.Duration
+	clock                               clock.Clock
 }
 
 func (bc *baggageCollector) Run() error {
 	bc.logger.Info("collect")
 
+	startedAt := bc.clock.Now()
+
 	latestVersions, err := bc.getLatestVersionSet()
 	if err != nil {
 		return err
@@ -48,6 +57,16 @@ func (bc *baggageCollector) Run() error {
 	if err != nil {
 		return err
 	}
+
+	err = bc.db.SaveGCPass(db.GCPass{
+		StartedAt:  startedAt,
+		FinishedAt: bc.clock.Now(),
+	})
+	if err != nil {
+		bc.logger.Error("failed-to-save-gc-pass", err)
+		return err
+	}
+
 	return nil
 }
 
@@ -259,6 +278,7 @@ func NewBaggageCollector(
 	pipelineDBFactory db.PipelineDBFactory,
 	oldResourceGracePeriod time.Duration,
 	oneOffBuildImageResourceGracePeriod time.Duration,
+	clock clock.Clock,
 ) BaggageCollector {
 	return &baggageCollector{
 		logger:                              logger,
@@ -267,6 +287,7 @@ func NewBaggageCollector(
 		pipelineDBFactory:                   pipelineDBFactory,
 		oldResourceGracePeriod:              oldResourceGracePeriod,
 		oneOffBuildImageResourceGracePeriod: oneOffBuildImageResourceGracePeriod,
+		clock:                               clock,
 	}
 }
 
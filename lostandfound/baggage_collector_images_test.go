@@ -3,6 +3,7 @@ package lostandfound_test
 import (
 	"time"
 
+	"code.cloudfoundry.org/clock"
 	"code.cloudfoundry.org/lager"
 	"code.cloudfoundry.org/lager/lagertest"
 	. "github.com/onsi/ginkgo"
@@ -79,6 +80,7 @@ var _ = Describe("Baggage-collecting image resource volumes", func() {
 				fakePipelineDBFactory,
 				expectedOldVersionTTL,
 				expectedOneOffTTL,
+				clock.NewClock(),
 			)
 
 			savedPipeline = db.SavedPipeline{
@@ -281,6 +283,7 @@ var _ = Describe("Baggage-collecting image resource volumes", func() {
 				fakePipelineDBFactory,
 				expectedOldVersionTTL,
 				expectedOneOffTTL,
+				clock.NewClock(),
 			)
 
 			savedPipeline = db.SavedPipeline{
@@ -3,6 +3,7 @@ package lostandfound_test
 import (
 	"time"
 
+	"code.cloudfoundry.org/clock"
 	"code.cloudfoundry.org/lager/lagertest"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -71,6 +72,7 @@ var _ = Describe("Baggage-collecting image resource volumes created by one-off b
 			fakePipelineDBFactory,
 			expectedOldVersionTTL,
 			expectedOneOffTTL,
+			clock.NewClock(),
 		)
 
 		savedPipeline = db.SavedPipeline{
@@ -3,6 +3,7 @@ package lostandfound_test
 import (
 	"time"
 
+	"code.cloudfoundry.org/clock"
 	"code.cloudfoundry.org/lager"
 	"code.cloudfoundry.org/lager/lagertest"
 	. "github.com/onsi/ginkgo"
@@ -72,6 +73,7 @@ var _ = Describe("Baggage Collector", func() {
 				fakePipelineDBFactory,
 				expectedOldResourceGracePeriod,
 				expectedOneOffTTL,
+				clock.NewClock(),
 			)
 
 			fakeWorker.FindResourceTypeByPathStub = func(path string) (atc.WorkerResourceType, bool) {
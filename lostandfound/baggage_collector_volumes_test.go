@@ -4,6 +4,7 @@ import (
 	"errors"
 	"time"
 
+	"code.cloudfoundry.org/clock/fakeclock"
 	"code.cloudfoundry.org/lager/lagertest"
 	"github.com/concourse/atc"
 	"github.com/concourse/atc/db"
@@ -26,6 +27,7 @@ var _ = Describe("Volumes are reaped", func() {
 
 		fakePipelineDBFactory          *dbfakes.FakePipelineDBFactory
 		fakeBaggageCollectorDB         *lostandfoundfakes.FakeBaggageCollectorDB
+		fakeClock                      *fakeclock.FakeClock
 		expectedOldResourceGracePeriod = 4 * time.Minute
 		expectedOneOffTTL              = 5 * time.Hour
 
@@ -42,6 +44,7 @@ var _ = Describe("Volumes are reaped", func() {
 		baggageCollectorLogger := lagertest.NewTestLogger("test")
 		fakeBaggageCollectorDB = new(lostandfoundfakes.FakeBaggageCollectorDB)
 		fakePipelineDBFactory = new(dbfakes.FakePipelineDBFactory)
+		fakeClock = fakeclock.NewFakeClock(time.Unix(123, 0))
 
 		baggageCollector = lostandfound.NewBaggageCollector(
 			baggageCollectorLogger,
@@ -50,6 +53,7 @@ var _ = Describe("Volumes are reaped", func() {
 			fakePipelineDBFactory,
 			expectedOldResourceGracePeriod,
 			expectedOneOffTTL,
+			fakeClock,
 		)
 
 		returnedSavedVolume = db.SavedVolume{
@@ -178,4 +182,33 @@ var _ = Describe("Volumes are reaped", func() {
 			Expect(fakeBaggageCollectorDB.ReapVolumeArgsForCall(0)).To(Equal(returnedSavedVolume.Handle))
 		})
 	})
+
+	It("saves when the pass started and finished", func() {
+		fakeBaggageCollectorDB.GetVolumesStub = func() ([]db.SavedVolume, error) {
+			fakeClock.Increment(5 * time.Second)
+			return nil, nil
+		}
+
+		err := baggageCollector.Run()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeBaggageCollectorDB.SaveGCPassCallCount()).To(Equal(1))
+		Expect(fakeBaggageCollectorDB.SaveGCPassArgsForCall(0)).To(Equal(db.GCPass{
+			StartedAt:  time.Unix(123, 0),
+			FinishedAt: time.Unix(128, 0),
+		}))
+	})
+
+	Context("when collecting fails", func() {
+		BeforeEach(func() {
+			fakeBaggageCollectorDB.GetAllPipelinesReturns(nil, errors.New("nope"))
+		})
+
+		It("doesn't save the pass", func() {
+			err := baggageCollector.Run()
+			Expect(err).To(HaveOccurred())
+
+			Expect(fakeBaggageCollectorDB.SaveGCPassCallCount()).To(BeZero())
+		})
+	})
 })
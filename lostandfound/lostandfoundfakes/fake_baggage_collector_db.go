@@ -38,6 +38,14 @@ type FakeBaggageCollectorDB struct {
 		result1 []db.SavedVolume
 		result2 error
 	}
+	SaveGCPassStub        func(arg1 db.GCPass) error
+	saveGCPassMutex       sync.RWMutex
+	saveGCPassArgsForCall []struct {
+		arg1 db.GCPass
+	}
+	saveGCPassReturns struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -153,6 +161,39 @@ func (fake *FakeBaggageCollectorDB) GetVolumesForOneOffBuildImageResourcesReturn
 	}{result1, result2}
 }
 
+func (fake *FakeBaggageCollectorDB) SaveGCPass(arg1 db.GCPass) error {
+	fake.saveGCPassMutex.Lock()
+	fake.saveGCPassArgsForCall = append(fake.saveGCPassArgsForCall, struct {
+		arg1 db.GCPass
+	}{arg1})
+	fake.recordInvocation("SaveGCPass", []interface{}{arg1})
+	fake.saveGCPassMutex.Unlock()
+	if fake.SaveGCPassStub != nil {
+		return fake.SaveGCPassStub(arg1)
+	} else {
+		return fake.saveGCPassReturns.result1
+	}
+}
+
+func (fake *FakeBaggageCollectorDB) SaveGCPassCallCount() int {
+	fake.saveGCPassMutex.RLock()
+	defer fake.saveGCPassMutex.RUnlock()
+	return len(fake.saveGCPassArgsForCall)
+}
+
+func (fake *FakeBaggageCollectorDB) SaveGCPassArgsForCall(i int) db.GCPass {
+	fake.saveGCPassMutex.RLock()
+	defer fake.saveGCPassMutex.RUnlock()
+	return fake.saveGCPassArgsForCall[i].arg1
+}
+
+func (fake *FakeBaggageCollectorDB) SaveGCPassReturns(result1 error) {
+	fake.SaveGCPassStub = nil
+	fake.saveGCPassReturns = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeBaggageCollectorDB) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -164,6 +205,8 @@ func (fake *FakeBaggageCollectorDB) Invocations() map[string][][]interface{} {
 	defer fake.getVolumesMutex.RUnlock()
 	fake.getVolumesForOneOffBuildImageResourcesMutex.RLock()
 	defer fake.getVolumesForOneOffBuildImageResourcesMutex.RUnlock()
+	fake.saveGCPassMutex.RLock()
+	defer fake.saveGCPassMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -0,0 +1,37 @@
+package atc
+
+// MaintenanceWindow is a time during which the scheduler holds new builds
+// pending, e.g. for a change freeze. A window either recurs, starting on a
+// cron Schedule in UTC and lasting for Duration, or happens once, from
+// StartTime to EndTime.
+type MaintenanceWindow struct {
+	Name string `json:"name"`
+
+	Schedule string `json:"schedule,omitempty"`
+	Duration string `json:"duration,omitempty"`
+
+	StartTime int64 `json:"start_time,omitempty"`
+	EndTime   int64 `json:"end_time,omitempty"`
+}
+
+// Maintenance is the scheduler's maintenance windows and the one it's in, if
+// any. Builds aren't held while the windows are overridden, up until
+// OverriddenUntil.
+type Maintenance struct {
+	Windows []MaintenanceWindow `json:"windows"`
+
+	Active *ActiveMaintenanceWindow `json:"active,omitempty"`
+
+	OverriddenUntil int64 `json:"overridden_until,omitempty"`
+}
+
+type ActiveMaintenanceWindow struct {
+	Name    string `json:"name"`
+	EndTime int64  `json:"end_time"`
+}
+
+// MaintenanceOverride lets builds start in spite of maintenance windows until
+// the given time.
+type MaintenanceOverride struct {
+	Until int64 `json:"until"`
+}
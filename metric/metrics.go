@@ -25,6 +25,8 @@ type SchedulingFullDuration struct {
 }
 
 func (event SchedulingFullDuration) Emit(logger lager.Logger) {
+	SchedulingTickDuration.Observe(event.Duration)
+
 	state := "ok"
 
 	if event.Duration > time.Second {
@@ -149,6 +151,8 @@ type BuildStarted struct {
 }
 
 func (event BuildStarted) Emit(logger lager.Logger) {
+	BuildsStarted.Inc()
+
 	emit(
 		logger.Session("build-started", lager.Data{
 			"pipeline":   event.PipelineName,
@@ -180,6 +184,8 @@ type BuildFinished struct {
 }
 
 func (event BuildFinished) Emit(logger lager.Logger) {
+	BuildsFinished.Inc(string(event.BuildStatus))
+
 	emit(
 		logger.Session("build-finished", lager.Data{
 			"pipeline":     event.PipelineName,
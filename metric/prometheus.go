@@ -0,0 +1,309 @@
+package metric
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// The collectors below are exposed in the Prometheus text exposition format
+// by PrometheusHandler. They are updated alongside the Riemann events, so
+// they are populated whether or not Riemann is configured.
+var (
+	BuildsStarted = NewCounterVec(
+		"concourse_builds_started_total",
+		"Number of builds started.",
+	)
+
+	BuildsFinished = NewCounterVec(
+		"concourse_builds_finished_total",
+		"Number of builds finished, by status.",
+		"status",
+	)
+
+	ActiveEventStreams = NewUpDownCounter(
+		"concourse_build_event_streams_active",
+		"Number of build event streams currently being served.",
+	)
+
+	ReapedEventStreams = NewCounterVec(
+		"concourse_build_event_streams_reaped_total",
+		"Number of build event streams torn down because their client stopped responding, by transport.",
+		"transport",
+	)
+
+	SkippedStreamEvents = NewCounterVec(
+		"concourse_build_event_streams_skipped_logs_total",
+		"Number of log events skipped on build event streams whose client fell behind, by transport.",
+		"transport",
+	)
+
+	BuildEventsLatency = NewHistogram(
+		"concourse_build_events_subscribe_duration_seconds",
+		"Time taken to subscribe to a build's events.",
+		[]float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5},
+	)
+
+	SchedulingTickDuration = NewHistogram(
+		"concourse_scheduling_tick_duration_seconds",
+		"Time taken to schedule a pipeline, per tick.",
+		[]float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	)
+
+	CacheLookups = NewCounterVec(
+		"concourse_cache_lookups_total",
+		"Number of lookups in the in-memory caches, by cache and whether they hit.",
+		"cache",
+		"result",
+	)
+
+	DatabasePool = &PoolStats{}
+)
+
+var prometheusCollectors = []PrometheusCollector{
+	BuildsStarted,
+	BuildsFinished,
+	ActiveEventStreams,
+	ReapedEventStreams,
+	SkippedStreamEvents,
+	BuildEventsLatency,
+	SchedulingTickDuration,
+	CacheLookups,
+	DatabasePool,
+}
+
+// PrometheusCollector is implemented by the collectors in this package.
+type PrometheusCollector interface {
+	writePrometheus(io.Writer)
+}
+
+func PrometheusHandler() http.Handler {
+	return NewPrometheusHandler(prometheusCollectors...)
+}
+
+// NewPrometheusHandler exposes only the given collectors.
+func NewPrometheusHandler(collectors ...PrometheusCollector) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+		for _, collector := range collectors {
+			collector.writePrometheus(w)
+		}
+	})
+}
+
+type CounterVec struct {
+	name   string
+	help   string
+	labels []string
+
+	values map[string]float64
+	lock   sync.Mutex
+}
+
+func NewCounterVec(name string, help string, labels ...string) *CounterVec {
+	return &CounterVec{
+		name:   name,
+		help:   help,
+		labels: labels,
+		values: map[string]float64{},
+	}
+}
+
+// Inc increments the counter for the given label values, which must be given
+// in the same order as the labels the counter was constructed with.
+func (c *CounterVec) Inc(labelValues ...string) {
+	key := formatLabels(c.labels, labelValues)
+
+	c.lock.Lock()
+	c.values[key]++
+	c.lock.Unlock()
+}
+
+func (c *CounterVec) writePrometheus(w io.Writer) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	writeHeader(w, c.name, c.help, "counter")
+
+	keys := make([]string, 0, len(c.values))
+	for key := range c.values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fmt.Fprintf(w, "%s%s %s\n", c.name, key, formatValue(c.values[key]))
+	}
+}
+
+type UpDownCounter struct {
+	name string
+	help string
+
+	cur int64
+}
+
+func NewUpDownCounter(name string, help string) *UpDownCounter {
+	return &UpDownCounter{
+		name: name,
+		help: help,
+	}
+}
+
+func (c *UpDownCounter) Inc() {
+	atomic.AddInt64(&c.cur, 1)
+}
+
+func (c *UpDownCounter) Dec() {
+	atomic.AddInt64(&c.cur, -1)
+}
+
+func (c *UpDownCounter) writePrometheus(w io.Writer) {
+	writeHeader(w, c.name, c.help, "gauge")
+	fmt.Fprintf(w, "%s %d\n", c.name, atomic.LoadInt64(&c.cur))
+}
+
+// PoolStats reports on a pool of database connections as of each scrape.
+// Nothing is reported until it's told which pool to watch.
+type PoolStats struct {
+	stats func() sql.DBStats
+	lock  sync.Mutex
+}
+
+func (p *PoolStats) Watch(stats func() sql.DBStats) {
+	p.lock.Lock()
+	p.stats = stats
+	p.lock.Unlock()
+}
+
+func (p *PoolStats) writePrometheus(w io.Writer) {
+	p.lock.Lock()
+	statsFunc := p.stats
+	p.lock.Unlock()
+
+	if statsFunc == nil {
+		return
+	}
+
+	stats := statsFunc()
+
+	writeHeader(w, "concourse_db_connections_max", "Maximum number of open database connections allowed, or 0 if unlimited.", "gauge")
+	fmt.Fprintf(w, "concourse_db_connections_max %d\n", stats.MaxOpenConnections)
+
+	writeHeader(w, "concourse_db_connections", "Number of open database connections, by whether they are in use or idle.", "gauge")
+	fmt.Fprintf(w, "concourse_db_connections{state=\"in_use\"} %d\n", stats.InUse)
+	fmt.Fprintf(w, "concourse_db_connections{state=\"idle\"} %d\n", stats.Idle)
+
+	writeHeader(w, "concourse_db_connection_waits_total", "Number of times a query had to wait for a database connection to be free.", "counter")
+	fmt.Fprintf(w, "concourse_db_connection_waits_total %d\n", stats.WaitCount)
+
+	writeHeader(w, "concourse_db_connection_wait_duration_seconds_total", "Total time spent waiting for database connections to be free.", "counter")
+	fmt.Fprintf(w, "concourse_db_connection_wait_duration_seconds_total %s\n", formatValue(stats.WaitDuration.Seconds()))
+}
+
+type Histogram struct {
+	name    string
+	help    string
+	buckets []float64
+
+	counts []uint64
+	count  uint64
+	sum    float64
+	lock   sync.Mutex
+}
+
+func NewHistogram(name string, help string, buckets []float64) *Histogram {
+	return &Histogram{
+		name:    name,
+		help:    help,
+		buckets: buckets,
+		counts:  make([]uint64, len(buckets)),
+	}
+}
+
+func (h *Histogram) Observe(duration time.Duration) {
+	seconds := duration.Seconds()
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	for i, bound := range h.buckets {
+		if seconds <= bound {
+			h.counts[i]++
+		}
+	}
+
+	h.count++
+	h.sum += seconds
+}
+
+func (h *Histogram) writePrometheus(w io.Writer) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	writeHeader(w, h.name, h.help, "histogram")
+
+	for i, bound := range h.buckets {
+		fmt.Fprintf(w, "%s_bucket{le=\"%s\"} %d\n", h.name, formatValue(bound), h.counts[i])
+	}
+
+	fmt.Fprintf(w, "%s_bucket{le=\"+Inf\"} %d\n", h.name, h.count)
+	fmt.Fprintf(w, "%s_sum %s\n", h.name, formatValue(h.sum))
+	fmt.Fprintf(w, "%s_count %d\n", h.name, h.count)
+}
+
+// the exposition format's escapes aren't Go's: only backslashes and newlines
+// are escaped in help text, and quotes as well in label values
+var (
+	helpEscaper       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelValueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func writeHeader(w io.Writer, name string, help string, kind string) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, helpEscaper.Replace(help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", name, kind)
+}
+
+func formatLabels(names []string, values []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		value := ""
+		if i < len(values) {
+			value = values[i]
+		}
+
+		pairs[i] = name + `="` + labelValueEscaper.Replace(value) + `"`
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+
+	if value == math.Trunc(value) && math.Abs(value) < 1<<53 {
+		return fmt.Sprintf("%d", int64(value))
+	}
+
+	return fmt.Sprintf("%g", value)
+}
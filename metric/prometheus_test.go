@@ -0,0 +1,187 @@
+package metric_test
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/concourse/atc/metric"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PrometheusHandler", func() {
+	scrape := func() string {
+		recorder := httptest.NewRecorder()
+
+		request, err := http.NewRequest("GET", "/metrics", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		PrometheusHandler().ServeHTTP(recorder, request)
+
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(recorder.Header().Get("Content-Type")).To(Equal("text/plain; version=0.0.4"))
+
+		return recorder.Body.String()
+	}
+
+	scrapeCollector := func(collector PrometheusCollector) string {
+		recorder := httptest.NewRecorder()
+
+		request, err := http.NewRequest("GET", "/metrics", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		NewPrometheusHandler(collector).ServeHTTP(recorder, request)
+
+		return recorder.Body.String()
+	}
+
+	It("describes every metric", func() {
+		body := scrape()
+
+		Expect(body).To(ContainSubstring("# TYPE concourse_builds_started_total counter\n"))
+		Expect(body).To(ContainSubstring("# TYPE concourse_builds_finished_total counter\n"))
+		Expect(body).To(ContainSubstring("# TYPE concourse_build_event_streams_active gauge\n"))
+		Expect(body).To(ContainSubstring("# TYPE concourse_build_events_subscribe_duration_seconds histogram\n"))
+		Expect(body).To(ContainSubstring("# TYPE concourse_scheduling_tick_duration_seconds histogram\n"))
+		Expect(body).To(ContainSubstring("# TYPE concourse_cache_lookups_total counter\n"))
+	})
+
+	Describe("counters with labels", func() {
+		It("reports a sample per label value", func() {
+			BuildsFinished.Inc("aborted")
+			BuildsFinished.Inc("aborted")
+			BuildsFinished.Inc("errored")
+
+			body := scrape()
+
+			Expect(body).To(ContainSubstring(`concourse_builds_finished_total{status="aborted"} 2` + "\n"))
+			Expect(body).To(ContainSubstring(`concourse_builds_finished_total{status="errored"} 1` + "\n"))
+		})
+	})
+
+	Describe("escaping", func() {
+		It("escapes backslashes and newlines in help text", func() {
+			counter := NewCounterVec("some_total", `Some \ help`+"\nover two lines.")
+			counter.Inc()
+
+			Expect(scrapeCollector(counter)).To(Equal(
+				`# HELP some_total Some \\ help\nover two lines.` + "\n" +
+					"# TYPE some_total counter\n" +
+					"some_total 1\n",
+			))
+		})
+
+		It("escapes backslashes, quotes and newlines in label values", func() {
+			counter := NewCounterVec("some_total", "Some help.", "some_label")
+			counter.Inc(`C:\some "dir"` + "\n")
+
+			Expect(scrapeCollector(counter)).To(ContainSubstring(
+				`some_total{some_label="C:\\some \"dir\"\n"} 1` + "\n",
+			))
+		})
+
+		It("leaves other characters as they are", func() {
+			counter := NewCounterVec("some_total", "Some help.", "some_label")
+			counter.Inc("tab\tand é")
+
+			Expect(scrapeCollector(counter)).To(ContainSubstring(
+				"some_total{some_label=\"tab\tand é\"} 1\n",
+			))
+		})
+	})
+
+	Describe("gauges", func() {
+		It("reports the current value", func() {
+			ActiveEventStreams.Inc()
+			ActiveEventStreams.Inc()
+			ActiveEventStreams.Dec()
+
+			Expect(scrape()).To(ContainSubstring("concourse_build_event_streams_active 1\n"))
+
+			ActiveEventStreams.Dec()
+		})
+	})
+
+	Describe("histograms", func() {
+		It("reports cumulative buckets, the sum, and the count", func() {
+			SchedulingTickDuration.Observe(200 * time.Millisecond)
+			SchedulingTickDuration.Observe(3 * time.Second)
+
+			body := scrape()
+
+			Expect(body).To(ContainSubstring(`concourse_scheduling_tick_duration_seconds_bucket{le="0.1"} 0` + "\n"))
+			Expect(body).To(ContainSubstring(`concourse_scheduling_tick_duration_seconds_bucket{le="0.25"} 1` + "\n"))
+			Expect(body).To(ContainSubstring(`concourse_scheduling_tick_duration_seconds_bucket{le="5"} 2` + "\n"))
+			Expect(body).To(ContainSubstring(`concourse_scheduling_tick_duration_seconds_bucket{le="+Inf"} 2` + "\n"))
+			Expect(body).To(ContainSubstring("concourse_scheduling_tick_duration_seconds_sum 3.2\n"))
+			Expect(body).To(ContainSubstring("concourse_scheduling_tick_duration_seconds_count 2\n"))
+		})
+
+		It("counts observations on a bound in that bucket, and reports every bucket in order", func() {
+			histogram := NewHistogram("some_duration_seconds", "Some help.", []float64{0.5, 1, 2})
+
+			histogram.Observe(500 * time.Millisecond)
+			histogram.Observe(time.Second)
+			histogram.Observe(1500 * time.Millisecond)
+			histogram.Observe(time.Minute)
+
+			Expect(scrapeCollector(histogram)).To(Equal(
+				"# HELP some_duration_seconds Some help.\n" +
+					"# TYPE some_duration_seconds histogram\n" +
+					`some_duration_seconds_bucket{le="0.5"} 1` + "\n" +
+					`some_duration_seconds_bucket{le="1"} 2` + "\n" +
+					`some_duration_seconds_bucket{le="2"} 3` + "\n" +
+					`some_duration_seconds_bucket{le="+Inf"} 4` + "\n" +
+					"some_duration_seconds_sum 63\n" +
+					"some_duration_seconds_count 4\n",
+			))
+		})
+
+		It("reports a histogram with no observations", func() {
+			histogram := NewHistogram("some_duration_seconds", "Some help.", []float64{1})
+
+			body := scrapeCollector(histogram)
+			Expect(body).To(ContainSubstring(`some_duration_seconds_bucket{le="1"} 0` + "\n"))
+			Expect(body).To(ContainSubstring(`some_duration_seconds_bucket{le="+Inf"} 0` + "\n"))
+			Expect(body).To(ContainSubstring("some_duration_seconds_sum 0\n"))
+			Expect(body).To(ContainSubstring("some_duration_seconds_count 0\n"))
+		})
+	})
+
+	Describe("database pool stats", func() {
+		It("reports nothing until there's a pool to watch", func() {
+			Expect(scrape()).NotTo(ContainSubstring("concourse_db_connections"))
+		})
+
+		Context("once watching a pool", func() {
+			BeforeEach(func() {
+				DatabasePool.Watch(func() sql.DBStats {
+					return sql.DBStats{
+						MaxOpenConnections: 64,
+						InUse:              3,
+						Idle:               2,
+						WaitCount:          5,
+						WaitDuration:       1500 * time.Millisecond,
+					}
+				})
+			})
+
+			AfterEach(func() {
+				DatabasePool.Watch(nil)
+			})
+
+			It("reports its current stats", func() {
+				body := scrape()
+
+				Expect(body).To(ContainSubstring("concourse_db_connections_max 64\n"))
+				Expect(body).To(ContainSubstring(`concourse_db_connections{state="in_use"} 3` + "\n"))
+				Expect(body).To(ContainSubstring(`concourse_db_connections{state="idle"} 2` + "\n"))
+				Expect(body).To(ContainSubstring("concourse_db_connection_waits_total 5\n"))
+				Expect(body).To(ContainSubstring("concourse_db_connection_wait_duration_seconds_total 1.5\n"))
+			})
+		})
+	})
+})
@@ -1,6 +1,7 @@
 package metric
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/concourse/atc/db"
@@ -34,6 +35,24 @@ func (e *countingConn) Exec(query string, args ...interface{}) (sql.Result, erro
 	return e.Conn.Exec(query, args...)
 }
 
+func (e *countingConn) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	DatabaseQueries.Inc()
+
+	return e.Conn.QueryContext(ctx, query, args...)
+}
+
+func (e *countingConn) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	DatabaseQueries.Inc()
+
+	return e.Conn.QueryRowContext(ctx, query, args...)
+}
+
+func (e *countingConn) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	DatabaseQueries.Inc()
+
+	return e.Conn.ExecContext(ctx, query, args...)
+}
+
 func (e *countingConn) Begin() (db.Tx, error) {
 	tx, err := e.Conn.Begin()
 	if err != nil {
@@ -43,6 +62,15 @@ func (e *countingConn) Begin() (db.Tx, error) {
 	return &countingTx{Tx: tx}, nil
 }
 
+func (e *countingConn) BeginTx(ctx context.Context, opts *sql.TxOptions) (db.Tx, error) {
+	tx, err := e.Conn.BeginTx(ctx, opts)
+	if err != nil {
+		return tx, err
+	}
+
+	return &countingTx{Tx: tx}, nil
+}
+
 type countingTx struct {
 	db.Tx
 }
@@ -0,0 +1,201 @@
+package mtls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// KeyPair is a certificate and its key, along with the CAs that the other end
+// of a connection is verified with. They're loaded from files, which are read
+// again whenever they change, so that certificates can be rotated without a
+// restart.
+type KeyPair struct {
+	certPath string
+	keyPath  string
+	caPath   string
+
+	lock     sync.Mutex
+	modTimes map[string]time.Time
+	cert     tls.Certificate
+	cas      *x509.CertPool
+}
+
+// LoadKeyPair loads the certificate and key, and the CAs if caPath is
+// given. Without CAs, peers are verified with the system's.
+func LoadKeyPair(certPath string, keyPath string, caPath string) (*KeyPair, error) {
+	kp := &KeyPair{
+		certPath: certPath,
+		keyPath:  keyPath,
+		caPath:   caPath,
+	}
+
+	err := kp.load()
+	if err != nil {
+		return nil, err
+	}
+
+	return kp, nil
+}
+
+// ClientConfig is for connecting to serverName, presenting the certificate.
+func (kp *KeyPair) ClientConfig(serverName string) *tls.Config {
+	cert, cas := kp.current()
+
+	return &tls.Config{
+		ServerName:   serverName,
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      cas,
+	}
+}
+
+// ServerConfig is for listening with the certificate, starting from base. It
+// is looked up afresh for each handshake. If there are CAs, clients are
+// verified with them when they present a certificate; requiring one is left
+// to whatever handles the connection.
+func (kp *KeyPair) ServerConfig(base *tls.Config) *tls.Config {
+	config := base.Clone()
+
+	config.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		cert, cas := kp.current()
+
+		handshakeConfig := base.Clone()
+		handshakeConfig.Certificates = []tls.Certificate{cert}
+
+		if cas != nil {
+			handshakeConfig.ClientCAs = cas
+			handshakeConfig.ClientAuth = tls.VerifyClientCertIfGiven
+		}
+
+		return handshakeConfig, nil
+	}
+
+	return config
+}
+
+// Dialer wraps dial so that the connections it makes are over TLS,
+// authenticated with the certificate. The handshake has to finish within
+// handshakeTimeout, unless it's zero.
+func (kp *KeyPair) Dialer(
+	dial func(network string, address string) (net.Conn, error),
+	handshakeTimeout time.Duration,
+) func(network string, address string) (net.Conn, error) {
+	return func(network string, address string) (net.Conn, error) {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+
+		conn, err := dial(network, address)
+		if err != nil {
+			return nil, err
+		}
+
+		if handshakeTimeout != 0 {
+			conn.SetDeadline(time.Now().Add(handshakeTimeout))
+		}
+
+		tlsConn := tls.Client(conn, kp.ClientConfig(host))
+
+		err = tlsConn.Handshake()
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+
+		conn.SetDeadline(time.Time{})
+
+		return tlsConn, nil
+	}
+}
+
+// current reloads the files if any have changed. If they can't be loaded,
+// e.g. because the certificate has been replaced but its key not yet, the
+// ones loaded last are kept.
+func (kp *KeyPair) current() (tls.Certificate, *x509.CertPool) {
+	kp.lock.Lock()
+	defer kp.lock.Unlock()
+
+	if kp.changed() {
+		kp.loadLocked()
+	}
+
+	return kp.cert, kp.cas
+}
+
+func (kp *KeyPair) load() error {
+	kp.lock.Lock()
+	defer kp.lock.Unlock()
+
+	return kp.loadLocked()
+}
+
+func (kp *KeyPair) loadLocked() error {
+	modTimes, err := kp.statFiles()
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.LoadX509KeyPair(kp.certPath, kp.keyPath)
+	if err != nil {
+		return err
+	}
+
+	var cas *x509.CertPool
+	if kp.caPath != "" {
+		caCert, err := ioutil.ReadFile(kp.caPath)
+		if err != nil {
+			return err
+		}
+
+		cas = x509.NewCertPool()
+		if !cas.AppendCertsFromPEM(caCert) {
+			return fmt.Errorf("no certificates found in '%s'", kp.caPath)
+		}
+	}
+
+	kp.cert = cert
+	kp.cas = cas
+	kp.modTimes = modTimes
+
+	return nil
+}
+
+func (kp *KeyPair) changed() bool {
+	modTimes, err := kp.statFiles()
+	if err != nil {
+		return false
+	}
+
+	for path, modTime := range modTimes {
+		if !modTime.Equal(kp.modTimes[path]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (kp *KeyPair) statFiles() (map[string]time.Time, error) {
+	modTimes := map[string]time.Time{}
+
+	for _, path := range []string{kp.certPath, kp.keyPath, kp.caPath} {
+		if path == "" {
+			continue
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+
+		modTimes[path] = info.ModTime()
+	}
+
+	return modTimes, nil
+}
@@ -0,0 +1,222 @@
+package mtls_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/concourse/atc/mtls"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("KeyPair", func() {
+	var (
+		tmpdir string
+
+		ca     *x509.Certificate
+		caKey  *rsa.PrivateKey
+		caPath string
+
+		serverKeyPair *mtls.KeyPair
+		listener      net.Listener
+		peerSerials   chan *big.Int
+	)
+
+	writeCert := func(name string, serial int64, modTime time.Time) (string, string) {
+		key, err := rsa.GenerateKey(rand.Reader, 1024)
+		Expect(err).NotTo(HaveOccurred())
+
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: name},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+			IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		}
+
+		der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+		Expect(err).NotTo(HaveOccurred())
+
+		certPath := filepath.Join(tmpdir, name+".crt")
+		keyPath := filepath.Join(tmpdir, name+".key")
+
+		err = ioutil.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = ioutil.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}), 0600)
+		Expect(err).NotTo(HaveOccurred())
+
+		// the files are only read again once they look different
+		Expect(os.Chtimes(certPath, modTime, modTime)).To(Succeed())
+		Expect(os.Chtimes(keyPath, modTime, modTime)).To(Succeed())
+
+		return certPath, keyPath
+	}
+
+	dial := func(clientKeyPair *mtls.KeyPair) error {
+		conn, err := clientKeyPair.Dialer(net.Dial, time.Second)("tcp", listener.Addr().String())
+		if err != nil {
+			return err
+		}
+
+		return conn.Close()
+	}
+
+	BeforeEach(func() {
+		var err error
+		tmpdir, err = ioutil.TempDir("", "mtls")
+		Expect(err).NotTo(HaveOccurred())
+
+		caKey, err = rsa.GenerateKey(rand.Reader, 1024)
+		Expect(err).NotTo(HaveOccurred())
+
+		ca = &x509.Certificate{
+			SerialNumber:          big.NewInt(1),
+			Subject:               pkix.Name{CommonName: "some-ca"},
+			NotBefore:             time.Now().Add(-time.Hour),
+			NotAfter:              time.Now().Add(time.Hour),
+			IsCA:                  true,
+			BasicConstraintsValid: true,
+			KeyUsage:              x509.KeyUsageCertSign,
+		}
+
+		der, err := x509.CreateCertificate(rand.Reader, ca, ca, &caKey.PublicKey, caKey)
+		Expect(err).NotTo(HaveOccurred())
+
+		ca, err = x509.ParseCertificate(der)
+		Expect(err).NotTo(HaveOccurred())
+
+		caPath = filepath.Join(tmpdir, "ca.crt")
+		err = ioutil.WriteFile(caPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+		Expect(err).NotTo(HaveOccurred())
+
+		serverCert, serverKey := writeCert("server", 2, time.Unix(100, 0))
+
+		serverKeyPair, err = mtls.LoadKeyPair(serverCert, serverKey, caPath)
+		Expect(err).NotTo(HaveOccurred())
+
+		listener, err = tls.Listen("tcp", "127.0.0.1:0", serverKeyPair.ServerConfig(&tls.Config{}))
+		Expect(err).NotTo(HaveOccurred())
+
+		peerSerials = make(chan *big.Int, 10)
+
+		go func() {
+			for {
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+
+				tlsConn := conn.(*tls.Conn)
+				if tlsConn.Handshake() == nil {
+					certs := tlsConn.ConnectionState().PeerCertificates
+					if len(certs) > 0 {
+						peerSerials <- certs[0].SerialNumber
+					}
+				}
+
+				conn.Close()
+			}
+		}()
+	})
+
+	AfterEach(func() {
+		listener.Close()
+		os.RemoveAll(tmpdir)
+	})
+
+	It("authenticates both ends of the connection", func() {
+		clientCert, clientKey := writeCert("client", 3, time.Unix(100, 0))
+
+		clientKeyPair, err := mtls.LoadKeyPair(clientCert, clientKey, caPath)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(dial(clientKeyPair)).To(Succeed())
+		Eventually(peerSerials).Should(Receive(Equal(big.NewInt(3))))
+	})
+
+	It("picks up a rotated certificate on the next connection", func() {
+		clientCert, clientKey := writeCert("client", 3, time.Unix(100, 0))
+
+		clientKeyPair, err := mtls.LoadKeyPair(clientCert, clientKey, caPath)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(dial(clientKeyPair)).To(Succeed())
+		Eventually(peerSerials).Should(Receive(Equal(big.NewInt(3))))
+
+		writeCert("client", 4, time.Unix(200, 0))
+
+		Expect(dial(clientKeyPair)).To(Succeed())
+		Eventually(peerSerials).Should(Receive(Equal(big.NewInt(4))))
+	})
+
+	It("keeps the last good certificate while the new one can't be loaded", func() {
+		clientCert, clientKey := writeCert("client", 3, time.Unix(100, 0))
+
+		clientKeyPair, err := mtls.LoadKeyPair(clientCert, clientKey, caPath)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = ioutil.WriteFile(clientKey, []byte("half-written"), 0600)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chtimes(clientKey, time.Unix(200, 0), time.Unix(200, 0))).To(Succeed())
+
+		Expect(dial(clientKeyPair)).To(Succeed())
+		Eventually(peerSerials).Should(Receive(Equal(big.NewInt(3))))
+	})
+
+	It("rejects servers whose certificate isn't signed by the CAs", func() {
+		clientCert, clientKey := writeCert("client", 3, time.Unix(100, 0))
+
+		otherCA := filepath.Join(tmpdir, "other-ca.crt")
+		Expect(ioutil.WriteFile(otherCA, []byte(selfSigned()), 0600)).To(Succeed())
+
+		clientKeyPair, err := mtls.LoadKeyPair(clientCert, clientKey, otherCA)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(dial(clientKeyPair)).NotTo(Succeed())
+	})
+
+	Context("when the CA file has no certificates", func() {
+		It("fails to load", func() {
+			clientCert, clientKey := writeCert("client", 3, time.Unix(100, 0))
+
+			emptyCA := filepath.Join(tmpdir, "empty-ca.crt")
+			Expect(ioutil.WriteFile(emptyCA, []byte{}, 0600)).To(Succeed())
+
+			_, err := mtls.LoadKeyPair(clientCert, clientKey, emptyCA)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
+
+func selfSigned() string {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	Expect(err).NotTo(HaveOccurred())
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "other-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).NotTo(HaveOccurred())
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
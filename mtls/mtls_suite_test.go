@@ -0,0 +1,13 @@
+package mtls_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestMTLS(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "MTLS Suite")
+}
@@ -0,0 +1,201 @@
+// Package notify tells the URLs configured in a pipeline's notifications
+// when the pipeline's builds change status, and fires the pipeline's remote
+// triggers when they finish.
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/web"
+	"github.com/tedsuo/rata"
+)
+
+//go:generate counterfeiter . Notifier
+
+type Notifier interface {
+	BuildStatusChanged(logger lager.Logger, build db.Build)
+}
+
+// Payload is the body POSTed to each notification's URL.
+type Payload struct {
+	BuildID      int             `json:"build_id"`
+	BuildName    string          `json:"build_name"`
+	JobName      string          `json:"job_name"`
+	PipelineName string          `json:"pipeline_name"`
+	TeamName     string          `json:"team_name"`
+	Status       atc.BuildStatus `json:"status"`
+	URL          string          `json:"url"`
+	StartTime    int64           `json:"start_time,omitempty"`
+	EndTime      int64           `json:"end_time,omitempty"`
+}
+
+const (
+	deliveryAttempts = 5
+	initialBackoff   = time.Second
+)
+
+type notifier struct {
+	teamDBFactory db.TeamDBFactory
+	externalURL   string
+	httpClient    *http.Client
+	clock         clock.Clock
+}
+
+func NewNotifier(
+	teamDBFactory db.TeamDBFactory,
+	externalURL string,
+	httpClient *http.Client,
+	clock clock.Clock,
+) Notifier {
+	return &notifier{
+		teamDBFactory: teamDBFactory,
+		externalURL:   externalURL,
+		httpClient:    httpClient,
+		clock:         clock,
+	}
+}
+
+// BuildStatusChanged delivers the build's current status to every matching
+// notification in its pipeline, and fires every matching remote trigger.
+// Deliveries happen in the background, so that a slow or unreachable target
+// never holds up the build.
+func (n *notifier) BuildStatusChanged(logger lager.Logger, build db.Build) {
+	if build.IsOneOff() {
+		return
+	}
+
+	logger = logger.Session("notify", lager.Data{"build": build.ID()})
+
+	config, _, _, err := n.teamDBFactory.GetTeamDB(build.TeamName()).GetConfig(build.PipelineName())
+	if err != nil {
+		logger.Error("failed-to-get-pipeline-config", err)
+		return
+	}
+
+	status := atc.BuildStatus(build.Status())
+
+	var payload []byte
+
+	for _, notification := range config.Notifications {
+		if !notification.Matches(build.JobName(), status) {
+			continue
+		}
+
+		if payload == nil {
+			payload, err = json.Marshal(n.payload(build, status))
+			if err != nil {
+				logger.Error("failed-to-marshal-payload", err)
+				return
+			}
+		}
+
+		go n.deliver(logger.Session("deliver", lager.Data{"notification": notification.Name}), notificationRequest(notification.URL, payload))
+	}
+
+	n.fireRemoteTriggers(logger, build, status, config.RemoteTriggers)
+}
+
+func notificationRequest(url string, payload []byte) func() (*http.Request, error) {
+	return func() (*http.Request, error) {
+		request, err := http.NewRequest("POST", url, bytes.NewReader(payload))
+		if err != nil {
+			return nil, err
+		}
+
+		request.Header.Set("Content-Type", "application/json")
+
+		return request, nil
+	}
+}
+
+func (n *notifier) payload(build db.Build, status atc.BuildStatus) Payload {
+	payload := Payload{
+		BuildID:      build.ID(),
+		BuildName:    build.Name(),
+		JobName:      build.JobName(),
+		PipelineName: build.PipelineName(),
+		TeamName:     build.TeamName(),
+		Status:       status,
+		URL:          n.buildURL(build),
+	}
+
+	if !build.StartTime().IsZero() {
+		payload.StartTime = build.StartTime().Unix()
+	}
+
+	if !build.EndTime().IsZero() {
+		payload.EndTime = build.EndTime().Unix()
+	}
+
+	return payload
+}
+
+// deliver sends the request built by newRequest, building it afresh for
+// each attempt so that its body can be read again.
+func (n *notifier) buildURL(build db.Build) string {
+	path, err := web.Routes.CreatePathForRoute(web.GetBuild, rata.Params{
+		"team_name":     build.TeamName(),
+		"pipeline_name": build.PipelineName(),
+		"job":           build.JobName(),
+		"build":         build.Name(),
+	})
+	if err != nil {
+		return ""
+	}
+
+	return n.externalURL + path
+}
+
+func (n *notifier) deliver(logger lager.Logger, newRequest func() (*http.Request, error)) {
+	backoff := initialBackoff
+
+	for attempt := 1; ; attempt++ {
+		retry, err := n.send(newRequest)
+		if err == nil {
+			return
+		}
+
+		if !retry || attempt == deliveryAttempts {
+			logger.Error("failed-to-deliver", err, lager.Data{"attempts": attempt})
+			return
+		}
+
+		logger.Info("retrying", lager.Data{"attempt": attempt, "error": err.Error()})
+
+		n.clock.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// send returns whether a failed delivery is worth retrying: connection
+// errors and server errors may go away, but the target rejecting the
+// payload outright won't.
+func (n *notifier) send(newRequest func() (*http.Request, error)) (bool, error) {
+	request, err := newRequest()
+	if err != nil {
+		return false, err
+	}
+
+	response, err := n.httpClient.Do(request)
+	if err != nil {
+		return true, err
+	}
+
+	response.Body.Close()
+
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return false, nil
+	}
+
+	err = fmt.Errorf("unexpected response: %s", response.Status)
+
+	return response.StatusCode >= 500 || response.StatusCode == http.StatusTooManyRequests, err
+}
@@ -0,0 +1,189 @@
+package notify_test
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager/lagertest"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/db/dbfakes"
+	. "github.com/concourse/atc/notify"
+	"github.com/onsi/gomega/ghttp"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Notifier", func() {
+	var (
+		targetServer  *ghttp.Server
+		teamDBFactory *dbfakes.FakeTeamDBFactory
+		teamDB        *dbfakes.FakeTeamDB
+		fakeClock     *fakeclock.FakeClock
+		build         *dbfakes.FakeBuild
+
+		notifier Notifier
+	)
+
+	BeforeEach(func() {
+		targetServer = ghttp.NewServer()
+
+		teamDB = new(dbfakes.FakeTeamDB)
+		teamDBFactory = new(dbfakes.FakeTeamDBFactory)
+		teamDBFactory.GetTeamDBReturns(teamDB)
+
+		teamDB.GetConfigReturns(atc.Config{
+			Jobs: atc.JobConfigs{{Name: "some-job"}, {Name: "some-other-job"}},
+			Notifications: atc.NotificationConfigs{
+				{
+					Name:     "failures",
+					URL:      targetServer.URL() + "/failures",
+					Statuses: []atc.BuildStatus{atc.StatusFailed},
+					Jobs:     []string{"some-job"},
+				},
+			},
+		}, "", 1, nil)
+
+		fakeClock = fakeclock.NewFakeClock(time.Unix(123, 0))
+
+		build = new(dbfakes.FakeBuild)
+		build.IDReturns(42)
+		build.NameReturns("7")
+		build.JobNameReturns("some-job")
+		build.PipelineNameReturns("some-pipeline")
+		build.TeamNameReturns("some-team")
+		build.StatusReturns(db.StatusFailed)
+		build.StartTimeReturns(time.Unix(100, 0))
+		build.EndTimeReturns(time.Unix(200, 0))
+
+		notifier = NewNotifier(teamDBFactory, "https://ci.example.com", http.DefaultClient, fakeClock)
+	})
+
+	AfterEach(func() {
+		targetServer.Close()
+	})
+
+	notify := func() {
+		notifier.BuildStatusChanged(lagertest.NewTestLogger("test"), build)
+	}
+
+	Context("when a notification matches the build", func() {
+		BeforeEach(func() {
+			targetServer.AppendHandlers(ghttp.CombineHandler(
+				ghttp.VerifyRequest("POST", "/failures"),
+				ghttp.VerifyHeaderKV("Content-Type", "application/json"),
+				ghttp.VerifyJSON(`{
+					"build_id": 42,
+					"build_name": "7",
+					"job_name": "some-job",
+					"pipeline_name": "some-pipeline",
+					"team_name": "some-team",
+					"status": "failed",
+					"url": "https://ci.example.com/teams/some-team/pipelines/some-pipeline/jobs/some-job/builds/7",
+					"start_time": 100,
+					"end_time": 200
+				}`),
+			))
+		})
+
+		It("POSTs the build to the notification's URL", func() {
+			notify()
+
+			Eventually(targetServer.ReceivedRequests).Should(HaveLen(1))
+
+			Expect(teamDBFactory.GetTeamDBArgsForCall(0)).To(Equal("some-team"))
+			Expect(teamDB.GetConfigArgsForCall(0)).To(Equal("some-pipeline"))
+		})
+	})
+
+	Context("when the target fails", func() {
+		Context("with a server error", func() {
+			BeforeEach(func() {
+				targetServer.AppendHandlers(
+					ghttp.RespondWith(http.StatusBadGateway, ""),
+					ghttp.RespondWith(http.StatusServiceUnavailable, ""),
+					ghttp.RespondWith(http.StatusOK, ""),
+				)
+			})
+
+			It("retries with a growing backoff until it succeeds", func() {
+				notify()
+
+				Eventually(targetServer.ReceivedRequests).Should(HaveLen(1))
+
+				fakeClock.WaitForWatcherAndIncrement(time.Second)
+				Eventually(targetServer.ReceivedRequests).Should(HaveLen(2))
+
+				fakeClock.WaitForWatcherAndIncrement(time.Second)
+				Consistently(targetServer.ReceivedRequests).Should(HaveLen(2))
+
+				fakeClock.Increment(time.Second)
+				Eventually(targetServer.ReceivedRequests).Should(HaveLen(3))
+			})
+		})
+
+		Context("with a client error", func() {
+			BeforeEach(func() {
+				targetServer.AppendHandlers(ghttp.RespondWith(http.StatusBadRequest, ""))
+			})
+
+			It("gives up straight away", func() {
+				notify()
+
+				Eventually(targetServer.ReceivedRequests).Should(HaveLen(1))
+				Consistently(fakeClock.WatcherCount).Should(BeZero())
+			})
+		})
+	})
+
+	Context("when no notification matches the status", func() {
+		BeforeEach(func() {
+			build.StatusReturns(db.StatusSucceeded)
+		})
+
+		It("sends nothing", func() {
+			notify()
+
+			Consistently(targetServer.ReceivedRequests).Should(BeEmpty())
+		})
+	})
+
+	Context("when no notification matches the job", func() {
+		BeforeEach(func() {
+			build.JobNameReturns("some-other-job")
+		})
+
+		It("sends nothing", func() {
+			notify()
+
+			Consistently(targetServer.ReceivedRequests).Should(BeEmpty())
+		})
+	})
+
+	Context("when the build is a one-off", func() {
+		BeforeEach(func() {
+			build.IsOneOffReturns(true)
+		})
+
+		It("does not look up a pipeline", func() {
+			notify()
+
+			Expect(teamDBFactory.GetTeamDBCallCount()).To(BeZero())
+		})
+	})
+
+	Context("when the pipeline config can't be loaded", func() {
+		BeforeEach(func() {
+			teamDB.GetConfigReturns(atc.Config{}, "", 0, errors.New("nope"))
+		})
+
+		It("sends nothing", func() {
+			notify()
+
+			Consistently(targetServer.ReceivedRequests).Should(BeEmpty())
+		})
+	})
+})
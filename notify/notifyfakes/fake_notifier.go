@@ -0,0 +1,68 @@
+// This file was generated by counterfeiter
+package notifyfakes
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/notify"
+)
+
+type FakeNotifier struct {
+	BuildStatusChangedStub        func(logger lager.Logger, build db.Build)
+	buildStatusChangedMutex       sync.RWMutex
+	buildStatusChangedArgsForCall []struct {
+		logger lager.Logger
+		build  db.Build
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeNotifier) BuildStatusChanged(logger lager.Logger, build db.Build) {
+	fake.buildStatusChangedMutex.Lock()
+	fake.buildStatusChangedArgsForCall = append(fake.buildStatusChangedArgsForCall, struct {
+		logger lager.Logger
+		build  db.Build
+	}{logger, build})
+	fake.recordInvocation("BuildStatusChanged", []interface{}{logger, build})
+	fake.buildStatusChangedMutex.Unlock()
+	if fake.BuildStatusChangedStub != nil {
+		fake.BuildStatusChangedStub(logger, build)
+	}
+}
+
+func (fake *FakeNotifier) BuildStatusChangedCallCount() int {
+	fake.buildStatusChangedMutex.RLock()
+	defer fake.buildStatusChangedMutex.RUnlock()
+	return len(fake.buildStatusChangedArgsForCall)
+}
+
+func (fake *FakeNotifier) BuildStatusChangedArgsForCall(i int) (lager.Logger, db.Build) {
+	fake.buildStatusChangedMutex.RLock()
+	defer fake.buildStatusChangedMutex.RUnlock()
+	return fake.buildStatusChangedArgsForCall[i].logger, fake.buildStatusChangedArgsForCall[i].build
+}
+
+func (fake *FakeNotifier) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.buildStatusChangedMutex.RLock()
+	defer fake.buildStatusChangedMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeNotifier) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ notify.Notifier = new(FakeNotifier)
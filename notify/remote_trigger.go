@@ -0,0 +1,112 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+	"github.com/tedsuo/rata"
+)
+
+func (n *notifier) fireRemoteTriggers(logger lager.Logger, build db.Build, status atc.BuildStatus, triggers atc.RemoteTriggerConfigs) {
+	var versions map[string]atc.Version
+
+	for _, trigger := range triggers {
+		if !trigger.Matches(build.JobName(), status) {
+			continue
+		}
+
+		triggerLogger := logger.Session("remote-trigger", lager.Data{"remote-trigger": trigger.Name})
+
+		if versions == nil && len(trigger.Versions) > 0 {
+			var err error
+			versions, err = buildVersions(build)
+			if err != nil {
+				triggerLogger.Error("failed-to-get-build-resources", err)
+				return
+			}
+		}
+
+		path, err := atc.Routes.CreatePathForRoute(atc.ReceiveRemoteTrigger, rata.Params{
+			"team_name":     trigger.Team,
+			"pipeline_name": trigger.Pipeline,
+			"job_name":      trigger.Job,
+		})
+		if err != nil {
+			triggerLogger.Error("failed-to-create-path", err)
+			continue
+		}
+
+		remoteTrigger := atc.RemoteTrigger{
+			Source: atc.RemoteTriggerSource{
+				TeamName:     build.TeamName(),
+				PipelineName: build.PipelineName(),
+				JobName:      build.JobName(),
+				BuildName:    build.Name(),
+				BuildURL:     n.buildURL(build),
+			},
+		}
+
+		for _, resource := range trigger.Versions {
+			version, found := versions[resource]
+			if !found {
+				continue
+			}
+
+			if remoteTrigger.Versions == nil {
+				remoteTrigger.Versions = map[string]atc.Version{}
+			}
+
+			remoteTrigger.Versions[resource] = version
+		}
+
+		payload, err := json.Marshal(remoteTrigger)
+		if err != nil {
+			triggerLogger.Error("failed-to-marshal-payload", err)
+			continue
+		}
+
+		url := strings.TrimRight(trigger.URL, "/") + path
+
+		go n.deliver(triggerLogger, remoteTriggerRequest(url, trigger.Token, payload))
+	}
+}
+
+func remoteTriggerRequest(url string, token string, payload []byte) func() (*http.Request, error) {
+	return func() (*http.Request, error) {
+		request, err := http.NewRequest("POST", url, bytes.NewReader(payload))
+		if err != nil {
+			return nil, err
+		}
+
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("Authorization", "Bearer "+token)
+
+		return request, nil
+	}
+}
+
+// buildVersions returns the versions the build used, by resource name. A
+// version the build produced takes the place of the one it started with.
+func buildVersions(build db.Build) (map[string]atc.Version, error) {
+	inputs, outputs, err := build.GetResources()
+	if err != nil {
+		return nil, err
+	}
+
+	versions := map[string]atc.Version{}
+
+	for _, input := range inputs {
+		versions[input.Resource] = atc.Version(input.Version)
+	}
+
+	for _, output := range outputs {
+		versions[output.Resource] = atc.Version(output.Version)
+	}
+
+	return versions, nil
+}
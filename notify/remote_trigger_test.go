@@ -0,0 +1,169 @@
+package notify_test
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager/lagertest"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/db/dbfakes"
+	. "github.com/concourse/atc/notify"
+	"github.com/onsi/gomega/ghttp"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Remote triggers", func() {
+	var (
+		remoteATC     *ghttp.Server
+		teamDBFactory *dbfakes.FakeTeamDBFactory
+		teamDB        *dbfakes.FakeTeamDB
+		fakeClock     *fakeclock.FakeClock
+		build         *dbfakes.FakeBuild
+
+		notifier Notifier
+	)
+
+	BeforeEach(func() {
+		remoteATC = ghttp.NewServer()
+
+		teamDB = new(dbfakes.FakeTeamDB)
+		teamDBFactory = new(dbfakes.FakeTeamDBFactory)
+		teamDBFactory.GetTeamDBReturns(teamDB)
+
+		teamDB.GetConfigReturns(atc.Config{
+			Jobs: atc.JobConfigs{{Name: "some-job"}, {Name: "some-other-job"}},
+			RemoteTriggers: atc.RemoteTriggerConfigs{
+				{
+					Name:     "deploy-elsewhere",
+					URL:      remoteATC.URL() + "/",
+					Team:     "other-team",
+					Pipeline: "other-pipeline",
+					Job:      "deploy",
+					Token:    "some-token",
+					Jobs:     []string{"some-job"},
+					Versions: []string{"some-repo", "some-image"},
+				},
+			},
+		}, "", 1, nil)
+
+		fakeClock = fakeclock.NewFakeClock(time.Unix(123, 0))
+
+		build = new(dbfakes.FakeBuild)
+		build.IDReturns(42)
+		build.NameReturns("7")
+		build.JobNameReturns("some-job")
+		build.PipelineNameReturns("some-pipeline")
+		build.TeamNameReturns("some-team")
+		build.StatusReturns(db.StatusSucceeded)
+
+		build.GetResourcesReturns(
+			[]db.BuildInput{
+				{VersionedResource: db.VersionedResource{Resource: "some-repo", Version: db.Version{"ref": "abc"}}},
+				{VersionedResource: db.VersionedResource{Resource: "some-image", Version: db.Version{"digest": "old"}}},
+				{VersionedResource: db.VersionedResource{Resource: "not-passed", Version: db.Version{"v": "1"}}},
+			},
+			[]db.BuildOutput{
+				{VersionedResource: db.VersionedResource{Resource: "some-image", Version: db.Version{"digest": "new"}}},
+			},
+			nil,
+		)
+
+		notifier = NewNotifier(teamDBFactory, "https://ci.example.com", http.DefaultClient, fakeClock)
+	})
+
+	AfterEach(func() {
+		remoteATC.Close()
+	})
+
+	notify := func() {
+		notifier.BuildStatusChanged(lagertest.NewTestLogger("test"), build)
+	}
+
+	Context("when the build succeeds", func() {
+		BeforeEach(func() {
+			remoteATC.AppendHandlers(ghttp.CombineHandler(
+				ghttp.VerifyRequest("POST", "/api/v1/teams/other-team/pipelines/other-pipeline/jobs/deploy/remote-triggers"),
+				ghttp.VerifyHeaderKV("Authorization", "Bearer some-token"),
+				ghttp.VerifyJSON(`{
+					"source": {
+						"team_name": "some-team",
+						"pipeline_name": "some-pipeline",
+						"job_name": "some-job",
+						"build_name": "7",
+						"build_url": "https://ci.example.com/teams/some-team/pipelines/some-pipeline/jobs/some-job/builds/7"
+					},
+					"versions": {
+						"some-repo": {"ref": "abc"},
+						"some-image": {"digest": "new"}
+					}
+				}`),
+				ghttp.RespondWith(http.StatusCreated, "{}"),
+			))
+		})
+
+		It("triggers the job on the other ATC with the versions it used and produced", func() {
+			notify()
+
+			Eventually(remoteATC.ReceivedRequests).Should(HaveLen(1))
+		})
+	})
+
+	Context("when the other ATC is down", func() {
+		BeforeEach(func() {
+			remoteATC.AppendHandlers(
+				ghttp.RespondWith(http.StatusServiceUnavailable, ""),
+				ghttp.RespondWith(http.StatusCreated, "{}"),
+			)
+		})
+
+		It("retries", func() {
+			notify()
+
+			Eventually(remoteATC.ReceivedRequests).Should(HaveLen(1))
+
+			fakeClock.WaitForWatcherAndIncrement(time.Second)
+			Eventually(remoteATC.ReceivedRequests).Should(HaveLen(2))
+		})
+	})
+
+	Context("when the build fails", func() {
+		BeforeEach(func() {
+			build.StatusReturns(db.StatusFailed)
+		})
+
+		It("does not fire", func() {
+			notify()
+
+			Consistently(remoteATC.ReceivedRequests).Should(BeEmpty())
+		})
+	})
+
+	Context("when the build is of another job", func() {
+		BeforeEach(func() {
+			build.JobNameReturns("some-other-job")
+		})
+
+		It("does not fire", func() {
+			notify()
+
+			Consistently(remoteATC.ReceivedRequests).Should(BeEmpty())
+		})
+	})
+
+	Context("when the build's resources can't be loaded", func() {
+		BeforeEach(func() {
+			build.GetResourcesReturns(nil, nil, errors.New("nope"))
+		})
+
+		It("does not fire", func() {
+			notify()
+
+			Consistently(remoteATC.ReceivedRequests).Should(BeEmpty())
+		})
+	})
+})
@@ -7,6 +7,8 @@ const (
 	PaginationQuerySince      = "since"
 	PaginationQueryUntil      = "until"
 	PaginationQueryLimit      = "limit"
+	PaginationQueryOffset     = "offset"
 	PaginationWebLimit        = 100
 	PaginationAPIDefaultLimit = 100
+	PaginationAPIMaxLimit     = 1000
 )
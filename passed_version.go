@@ -0,0 +1,8 @@
+package atc
+
+// PassedVersion is sent on a resource's stream of passed versions when one of
+// its versions has made it through every job being watched.
+type PassedVersion struct {
+	Version VersionedResource `json:"version"`
+	Passed  []string          `json:"passed"`
+}
@@ -7,4 +7,17 @@ type Pipeline struct {
 	Public   bool         `json:"public"`
 	Groups   GroupConfigs `json:"groups,omitempty"`
 	TeamName string       `json:"team_name"`
+
+	InstanceVars map[string]interface{} `json:"instance_vars,omitempty"`
+
+	Permissions *PipelinePermissions `json:"permissions,omitempty"`
+}
+
+// PipelineWeight is how large a share of the global max in flight a pipeline
+// gets compared to the others with builds waiting. Pipelines default to a
+// weight of 1.
+type PipelineWeight struct {
+	TeamName     string `json:"team_name,omitempty"`
+	PipelineName string `json:"pipeline_name,omitempty"`
+	Weight       int    `json:"weight"`
 }
@@ -0,0 +1,58 @@
+package atc
+
+import "fmt"
+
+// PipelinePermission is something that can be done to a pipeline and its
+// builds.
+type PipelinePermission string
+
+const (
+	PipelinePermissionView      PipelinePermission = "view"
+	PipelinePermissionTrigger   PipelinePermission = "trigger"
+	PipelinePermissionConfigure PipelinePermission = "configure"
+)
+
+// PipelinePermissions sets the role needed within the pipeline's team to
+// view, trigger or configure it, e.g. so that anyone on the team can see a
+// pipeline that deploys to production but only admins can run it.
+//
+// They can only ask for more than a request would need anyway; a permission
+// that's unset leaves things as they are.
+type PipelinePermissions struct {
+	View      Role `json:"view,omitempty"`
+	Trigger   Role `json:"trigger,omitempty"`
+	Configure Role `json:"configure,omitempty"`
+}
+
+// RequiredRole returns the role the permission needs, if it's been set.
+func (permissions PipelinePermissions) RequiredRole(permission PipelinePermission) (Role, bool) {
+	var role Role
+	switch permission {
+	case PipelinePermissionView:
+		role = permissions.View
+	case PipelinePermissionTrigger:
+		role = permissions.Trigger
+	case PipelinePermissionConfigure:
+		role = permissions.Configure
+	}
+
+	return role, role != ""
+}
+
+func (permissions PipelinePermissions) IsZero() bool {
+	return permissions == PipelinePermissions{}
+}
+
+func (permissions PipelinePermissions) Validate() error {
+	for permission, role := range map[PipelinePermission]Role{
+		PipelinePermissionView:      permissions.View,
+		PipelinePermissionTrigger:   permissions.Trigger,
+		PipelinePermissionConfigure: permissions.Configure,
+	} {
+		if role != "" && !role.IsValid() {
+			return fmt.Errorf("unknown role '%s' for %s permission", role, permission)
+		}
+	}
+
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 
 	"code.cloudfoundry.org/clock"
 	"github.com/concourse/atc"
+	"github.com/concourse/atc/creds"
 	"github.com/concourse/atc/db"
 	"github.com/concourse/atc/engine"
 	"github.com/concourse/atc/radar"
@@ -25,20 +26,26 @@ type RadarSchedulerFactory interface {
 }
 
 type radarSchedulerFactory struct {
-	tracker  resource.Tracker
-	interval time.Duration
-	engine   engine.Engine
+	tracker      resource.Tracker
+	interval     time.Duration
+	engine       engine.Engine
+	limitsDB     buildstarter.BuildStarterLimitsDB
+	credsManager creds.Manager
 }
 
 func NewRadarSchedulerFactory(
 	tracker resource.Tracker,
 	interval time.Duration,
 	engine engine.Engine,
+	limitsDB buildstarter.BuildStarterLimitsDB,
+	credsManager creds.Manager,
 ) RadarSchedulerFactory {
 	return &radarSchedulerFactory{
-		tracker:  tracker,
-		interval: interval,
-		engine:   engine,
+		tracker:      tracker,
+		interval:     interval,
+		engine:       engine,
+		limitsDB:     limitsDB,
+		credsManager: credsManager,
 	}
 }
 
@@ -62,12 +69,14 @@ func (rsf *radarSchedulerFactory) BuildScheduler(pipelineDB db.PipelineDB, exter
 		),
 		BuildStarter: buildstarter.NewBuildStarter(
 			pipelineDB,
+			rsf.limitsDB,
 			maxinflight.NewUpdater(pipelineDB),
 			factory.NewBuildFactory(
 				pipelineDB.GetPipelineID(),
 				atc.NewPlanFactory(time.Now().Unix()),
 			),
 			rsf.engine,
+			rsf.credsManager,
 		),
 		Scanner: scanner,
 	}
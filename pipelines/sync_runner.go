@@ -11,6 +11,7 @@ import (
 
 type PipelineSyncer interface {
 	Sync()
+	Stop()
 }
 
 type SyncRunner struct {
@@ -21,6 +22,7 @@ type SyncRunner struct {
 
 func (runner SyncRunner) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
 	ticker := runner.Clock.NewTicker(runner.Interval)
+	defer ticker.Stop()
 
 	close(ready)
 
@@ -31,6 +33,7 @@ func (runner SyncRunner) Run(signals <-chan os.Signal, ready chan<- struct{}) er
 		case <-ticker.C():
 			runner.Syncer.Sync()
 		case <-signals:
+			runner.Syncer.Stop()
 			return nil
 		}
 	}
@@ -51,6 +51,17 @@ var _ = Describe("Pipelines Sync Runner", func() {
 		<-synced
 	})
 
+	Context("when signalled", func() {
+		It("stops the syncer's pipelines", func() {
+			<-synced
+
+			process.Signal(os.Interrupt)
+			Eventually(process.Wait()).Should(Receive())
+
+			Expect(fakeSyncer.StopCallCount()).To(Equal(1))
+		})
+	})
+
 	Context("when the interval elapses", func() {
 		JustBeforeEach(func() {
 			<-synced
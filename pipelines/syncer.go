@@ -57,6 +57,14 @@ func (syncer *Syncer) Sync() {
 		return
 	}
 
+	// templates aren't run themselves, only their instances are
+	templates := map[int]bool{}
+	for _, pipeline := range pipelines {
+		if pipeline.InstanceOf != 0 {
+			templates[pipeline.InstanceOf] = true
+		}
+	}
+
 	for id, runningPipeline := range syncer.runningPipelines {
 		select {
 		case <-runningPipeline.Exited:
@@ -67,7 +75,7 @@ func (syncer *Syncer) Sync() {
 
 		var found bool
 		for _, pipeline := range pipelines {
-			if pipeline.Paused {
+			if pipeline.Paused || templates[pipeline.ID] {
 				continue
 			}
 
@@ -84,7 +92,7 @@ func (syncer *Syncer) Sync() {
 	}
 
 	for _, pipeline := range pipelines {
-		if pipeline.Paused || syncer.isPipelineRunning(pipeline.ID) {
+		if pipeline.Paused || templates[pipeline.ID] || syncer.isPipelineRunning(pipeline.ID) {
 			continue
 		}
 
@@ -103,6 +111,17 @@ func (syncer *Syncer) Sync() {
 	}
 }
 
+// Stop stops every running pipeline, waiting for each to exit. They're
+// started again by the next Sync.
+func (syncer *Syncer) Stop() {
+	for id, runningPipeline := range syncer.runningPipelines {
+		syncer.logger.Debug("stopping-pipeline", lager.Data{"pipeline-id": id})
+		runningPipeline.Process.Signal(os.Interrupt)
+		<-runningPipeline.Exited
+		syncer.removePipeline(id)
+	}
+}
+
 func (syncer *Syncer) removePipeline(pipelineID int) {
 	delete(syncer.runningPipelines, pipelineID)
 }
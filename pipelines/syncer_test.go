@@ -114,6 +114,35 @@ var _ = Describe("Pipelines Syncer", func() {
 		})
 	})
 
+	Context("when stopped", func() {
+		It("stops every pipeline, waiting for them to exit", func() {
+			stopped := make(chan struct{})
+			go func() {
+				syncer.Stop()
+				close(stopped)
+			}()
+
+			signals, _ := fakeRunner.RunArgsForCall(0)
+			Eventually(signals).Should(Receive(Equal(os.Interrupt)))
+
+			Consistently(stopped).ShouldNot(BeClosed())
+
+			fakeRunnerExitChan <- nil
+
+			Eventually(stopped).Should(BeClosed())
+		})
+
+		It("starts them again on the next sync", func() {
+			fakeRunnerExitChan <- nil
+
+			syncer.Stop()
+			syncer.Sync()
+
+			Expect(fakeRunner.RunCallCount()).To(Equal(2))
+			Expect(otherFakeRunner.RunCallCount()).To(Equal(2))
+		})
+	})
+
 	Context("when a pipeline is deleted", func() {
 		It("stops the process", func() {
 			Expect(fakeRunner.RunCallCount()).To(Equal(1))
@@ -229,6 +258,41 @@ var _ = Describe("Pipelines Syncer", func() {
 		})
 	})
 
+	Context("when a pipeline has instances", func() {
+		JustBeforeEach(func() {
+			Expect(fakeRunner.RunCallCount()).To(Equal(1))
+			Expect(otherFakeRunner.RunCallCount()).To(Equal(1))
+
+			syncherDB.GetAllPipelinesReturns([]db.SavedPipeline{
+				{
+					ID: 1,
+					Pipeline: db.Pipeline{
+						Name: "pipeline",
+					},
+				},
+				{
+					ID:         2,
+					InstanceOf: 1,
+					Pipeline: db.Pipeline{
+						Name: "other-pipeline",
+					},
+				},
+			}, nil)
+
+			syncer.Sync()
+		})
+
+		It("stops the template's process, leaving the instance running", func() {
+			Expect(fakeRunner.RunCallCount()).To(Equal(1))
+
+			signals, _ := fakeRunner.RunArgsForCall(0)
+			Eventually(signals).Should(Receive(Equal(os.Interrupt)))
+
+			otherSignals, _ := otherFakeRunner.RunArgsForCall(0)
+			Consistently(otherSignals).ShouldNot(Receive())
+		})
+	})
+
 	Context("when the pipeline's process exits", func() {
 		BeforeEach(func() {
 			fakeRunnerExitChan <- nil
@@ -16,6 +16,7 @@ type Plan struct {
 	DependentGet *DependentGetPlan `json:"dependent_get,omitempty"`
 	Timeout      *TimeoutPlan      `json:"timeout,omitempty"`
 	Retry        *RetryPlan        `json:"retry,omitempty"`
+	Approval     *ApprovalPlan     `json:"approval,omitempty"`
 }
 
 type PlanID string
@@ -119,3 +120,7 @@ type TaskPlan struct {
 }
 
 type RetryPlan []Plan
+
+type ApprovalPlan struct {
+	Name string `json:"name"`
+}
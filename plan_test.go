@@ -244,6 +244,13 @@ var _ = Describe("Plan", func() {
 						},
 					},
 				},
+
+				atc.Plan{
+					ID: "26",
+					Approval: &atc.ApprovalPlan{
+						Name: "name",
+					},
+				},
 			},
 		}
 
@@ -417,6 +424,12 @@ var _ = Describe("Plan", func() {
           }
         }
       ]
+    },
+    {
+      "id": "26",
+      "approval": {
+        "name": "name"
+      }
     }
   ]
 }
@@ -0,0 +1,20 @@
+package atc
+
+// QueuedBuild is a pending build of a job and what's keeping it from
+// starting. Position is where it is in the order the job's builds will be
+// started in, counting from 1; builds of other jobs in the same serial
+// groups may still get in ahead of it.
+type QueuedBuild struct {
+	BuildID      int    `json:"build_id"`
+	BuildName    string `json:"build_name"`
+	TeamName     string `json:"team_name"`
+	PipelineName string `json:"pipeline_name"`
+	JobName      string `json:"job_name"`
+	Priority     int    `json:"priority,omitempty"`
+
+	EnqueuedAt int64 `json:"enqueued_at,omitempty"`
+
+	Reason    SchedulingReason `json:"reason,omitempty"`
+	CheckedAt int64            `json:"checked_at,omitempty"`
+	Position  int              `json:"position"`
+}
@@ -23,6 +23,7 @@ type RadarDB interface {
 	GetLatestVersionedResource(resourceName string) (db.SavedVersionedResource, bool, error)
 	GetResource(resourceName string) (db.SavedResource, bool, error)
 	GetResourceType(resourceTypeName string) (db.SavedResourceType, bool, error)
+	GetRegisteredResourceTypes() ([]atc.RegisteredResourceType, error)
 	PauseResource(resourceName string) error
 	UnpauseResource(resourceName string) error
 
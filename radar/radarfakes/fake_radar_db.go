@@ -153,6 +153,13 @@ type FakeRadarDB struct {
 		result2 bool
 		result3 error
 	}
+	GetRegisteredResourceTypesStub        func() ([]atc.RegisteredResourceType, error)
+	getRegisteredResourceTypesMutex       sync.RWMutex
+	getRegisteredResourceTypesArgsForCall []struct{}
+	getRegisteredResourceTypesReturns     struct {
+		result1 []atc.RegisteredResourceType
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -673,6 +680,32 @@ func (fake *FakeRadarDB) AcquireResourceTypeCheckingLockReturns(result1 db.Lock,
 	}{result1, result2, result3}
 }
 
+func (fake *FakeRadarDB) GetRegisteredResourceTypes() ([]atc.RegisteredResourceType, error) {
+	fake.getRegisteredResourceTypesMutex.Lock()
+	fake.getRegisteredResourceTypesArgsForCall = append(fake.getRegisteredResourceTypesArgsForCall, struct{}{})
+	fake.recordInvocation("GetRegisteredResourceTypes", []interface{}{})
+	fake.getRegisteredResourceTypesMutex.Unlock()
+	if fake.GetRegisteredResourceTypesStub != nil {
+		return fake.GetRegisteredResourceTypesStub()
+	} else {
+		return fake.getRegisteredResourceTypesReturns.result1, fake.getRegisteredResourceTypesReturns.result2
+	}
+}
+
+func (fake *FakeRadarDB) GetRegisteredResourceTypesCallCount() int {
+	fake.getRegisteredResourceTypesMutex.RLock()
+	defer fake.getRegisteredResourceTypesMutex.RUnlock()
+	return len(fake.getRegisteredResourceTypesArgsForCall)
+}
+
+func (fake *FakeRadarDB) GetRegisteredResourceTypesReturns(result1 []atc.RegisteredResourceType, result2 error) {
+	fake.GetRegisteredResourceTypesStub = nil
+	fake.getRegisteredResourceTypesReturns = struct {
+		result1 []atc.RegisteredResourceType
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeRadarDB) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -708,6 +741,8 @@ func (fake *FakeRadarDB) Invocations() map[string][][]interface{} {
 	defer fake.acquireResourceCheckingLockMutex.RUnlock()
 	fake.acquireResourceTypeCheckingLockMutex.RLock()
 	defer fake.acquireResourceTypeCheckingLockMutex.RUnlock()
+	fake.getRegisteredResourceTypesMutex.RLock()
+	defer fake.getRegisteredResourceTypesMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -325,5 +325,11 @@ func (scanner *resourceScanner) getResourceConfig(logger lager.Logger, resourceN
 		return resourceConfig, nil, ResourceNotConfiguredError{ResourceName: resourceName}
 	}
 
-	return resourceConfig, config.ResourceTypes, nil
+	registeredResourceTypes, err := scanner.db.GetRegisteredResourceTypes()
+	if err != nil {
+		logger.Error("failed-to-get-registered-resource-types", err)
+		return atc.ResourceConfig{}, nil, err
+	}
+
+	return resourceConfig, config.ResourceTypes.WithRegistered(registeredResourceTypes), nil
 }
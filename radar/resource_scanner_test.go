@@ -167,6 +167,45 @@ var _ = Describe("ResourceScanner", func() {
 				Expect(actualTeamID).To(Equal(teamID))
 			})
 
+			Context("when resource types are registered with the ATC", func() {
+				BeforeEach(func() {
+					fakeRadarDB.GetRegisteredResourceTypesReturns([]atc.RegisteredResourceType{
+						{Name: "some-custom-resource", Image: "registered/some-custom-resource", Version: "1.0"},
+						{Name: "registered-resource", Image: "registered/registered-resource", Version: "2.0"},
+					}, nil)
+				})
+
+				It("constructs the resource with the ones the pipeline doesn't override", func() {
+					_, _, _, _, _, _, customTypes, _ := fakeTracker.InitArgsForCall(0)
+					Expect(customTypes).To(Equal(atc.ResourceTypes{
+						{
+							Name:   "some-custom-resource",
+							Type:   "docker-image",
+							Source: atc.Source{"custom": "source"},
+						},
+						{
+							Name:   "registered-resource",
+							Type:   "docker-image",
+							Source: atc.Source{"repository": "registered/registered-resource", "tag": "2.0"},
+						},
+					}))
+				})
+			})
+
+			Context("when getting the registered resource types fails", func() {
+				BeforeEach(func() {
+					fakeRadarDB.GetRegisteredResourceTypesReturns(nil, errors.New("nope"))
+				})
+
+				It("doesn't check", func() {
+					Expect(fakeResource.CheckCallCount()).To(BeZero())
+				})
+
+				It("returns the error", func() {
+					Expect(runErr).To(MatchError("nope"))
+				})
+			})
+
 			Context("when the resource config has a specified check interval", func() {
 				BeforeEach(func() {
 					resourceConfig.CheckEvery = "10ms"
@@ -4,6 +4,7 @@ import (
 	"os"
 	"time"
 
+	"code.cloudfoundry.org/clock"
 	"code.cloudfoundry.org/lager"
 	"github.com/concourse/atc/db"
 	"github.com/tedsuo/ifrit"
@@ -21,6 +22,7 @@ type Runner struct {
 	db                db.PipelineDB
 	pipelineDBFactory db.PipelineDBFactory
 	syncInterval      time.Duration
+	clock             clock.Clock
 }
 
 func NewRunner(
@@ -29,6 +31,7 @@ func NewRunner(
 	scanRunnerFactory ScanRunnerFactory,
 	db db.PipelineDB,
 	syncInterval time.Duration,
+	clock clock.Clock,
 ) *Runner {
 	return &Runner{
 		logger:            logger,
@@ -36,6 +39,7 @@ func NewRunner(
 		scanRunnerFactory: scanRunnerFactory,
 		db:                db,
 		syncInterval:      syncInterval,
+		clock:             clock,
 	}
 }
 
@@ -50,7 +54,8 @@ func (runner *Runner) Run(signals <-chan os.Signal, ready chan<- struct{}) error
 	runner.logger.Info("start")
 	defer runner.logger.Info("done")
 
-	ticker := time.NewTicker(runner.syncInterval)
+	ticker := runner.clock.NewTicker(runner.syncInterval)
+	defer ticker.Stop()
 
 	scannersGroup := grouper.NewDynamic(nil, 0, 0)
 
@@ -88,7 +93,7 @@ dance:
 
 			delete(scanning, exited.Member.Name)
 
-		case <-ticker.C:
+		case <-ticker.C():
 			runner.tick(scanning, scanningResourceTypes, insertScanner)
 		}
 	}
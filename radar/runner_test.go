@@ -4,6 +4,7 @@ import (
 	"os"
 	"time"
 
+	"code.cloudfoundry.org/clock"
 	"code.cloudfoundry.org/lager"
 	"code.cloudfoundry.org/lager/lagertest"
 	"github.com/concourse/atc"
@@ -84,6 +85,7 @@ var _ = Describe("Runner", func() {
 			scanRunnerFactory,
 			pipelineDB,
 			syncInterval,
+			clock.NewClock(),
 		))
 	})
 
@@ -0,0 +1,110 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+)
+
+// buckets that have refilled completely carry no state worth keeping, so
+// they are dropped at most this often to stop the map from growing forever
+const sweepInterval = time.Minute
+
+// Limiter is a token bucket per key. Each bucket holds up to burst tokens and
+// refills at rate tokens per second.
+type Limiter struct {
+	clock clock.Clock
+	rate  float64
+	burst float64
+
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	lock      sync.Mutex
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func NewLimiter(clock clock.Clock, rate float64, burst int) *Limiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &Limiter{
+		clock: clock,
+		rate:  rate,
+		burst: float64(burst),
+
+		buckets:   map[string]*bucket{},
+		lastSweep: clock.Now(),
+	}
+}
+
+// Take takes a token from the bucket of every given key. Tokens are only
+// taken if every bucket has one to spare; otherwise none are, and the
+// returned duration is how long to wait until they all would.
+func (l *Limiter) Take(keys ...string) (bool, time.Duration) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := l.clock.Now()
+
+	l.sweep(now)
+
+	var wait time.Duration
+
+	buckets := make([]*bucket, len(keys))
+	for i, key := range keys {
+		b, found := l.buckets[key]
+		if !found {
+			b = &bucket{tokens: l.burst, last: now}
+			l.buckets[key] = b
+		}
+
+		l.refill(b, now)
+
+		if b.tokens < 1 {
+			missing := time.Duration(math.Ceil((1 - b.tokens) / l.rate * float64(time.Second)))
+			if missing > wait {
+				wait = missing
+			}
+		}
+
+		buckets[i] = b
+	}
+
+	if wait > 0 {
+		return false, wait
+	}
+
+	for _, b := range buckets {
+		b.tokens--
+	}
+
+	return true, 0
+}
+
+func (l *Limiter) refill(b *bucket, now time.Time) {
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+}
+
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+
+	for key, b := range l.buckets {
+		l.refill(b, now)
+
+		if b.tokens >= l.burst {
+			delete(l.buckets, key)
+		}
+	}
+
+	l.lastSweep = now
+}
@@ -0,0 +1,83 @@
+package ratelimit_test
+
+import (
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"github.com/concourse/atc/ratelimit"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Limiter", func() {
+	var (
+		fakeClock *fakeclock.FakeClock
+		limiter   *ratelimit.Limiter
+	)
+
+	BeforeEach(func() {
+		fakeClock = fakeclock.NewFakeClock(time.Unix(123, 0))
+		limiter = ratelimit.NewLimiter(fakeClock, 0.5, 2)
+	})
+
+	It("allows a burst, then makes callers wait for the bucket to refill", func() {
+		allowed, _ := limiter.Take("some-key")
+		Expect(allowed).To(BeTrue())
+
+		allowed, _ = limiter.Take("some-key")
+		Expect(allowed).To(BeTrue())
+
+		allowed, wait := limiter.Take("some-key")
+		Expect(allowed).To(BeFalse())
+		Expect(wait).To(Equal(2 * time.Second))
+
+		fakeClock.Increment(time.Second)
+
+		allowed, wait = limiter.Take("some-key")
+		Expect(allowed).To(BeFalse())
+		Expect(wait).To(Equal(time.Second))
+
+		fakeClock.Increment(time.Second)
+
+		allowed, _ = limiter.Take("some-key")
+		Expect(allowed).To(BeTrue())
+	})
+
+	It("keeps a separate bucket per key", func() {
+		limiter.Take("some-key")
+		limiter.Take("some-key")
+
+		allowed, _ := limiter.Take("some-key")
+		Expect(allowed).To(BeFalse())
+
+		allowed, _ = limiter.Take("other-key")
+		Expect(allowed).To(BeTrue())
+	})
+
+	It("does not refill beyond the burst", func() {
+		fakeClock.Increment(time.Hour)
+
+		limiter.Take("some-key")
+		limiter.Take("some-key")
+
+		allowed, _ := limiter.Take("some-key")
+		Expect(allowed).To(BeFalse())
+	})
+
+	Context("when taking from several keys at once", func() {
+		It("takes nothing unless every bucket has a token", func() {
+			limiter.Take("exhausted")
+			limiter.Take("exhausted")
+
+			allowed, _ := limiter.Take("fresh", "exhausted")
+			Expect(allowed).To(BeFalse())
+
+			allowed, _ = limiter.Take("fresh")
+			Expect(allowed).To(BeTrue())
+
+			allowed, _ = limiter.Take("fresh")
+			Expect(allowed).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,40 @@
+package atc
+
+// RegisteredResourceType is a resource type registered with the ATC itself,
+// making it available to every pipeline without being declared under
+// resource_types. Image is the repository of its Docker image, and Version
+// the tag to run.
+type RegisteredResourceType struct {
+	Name    string `json:"name"`
+	Image   string `json:"image"`
+	Version string `json:"version"`
+}
+
+func (registered RegisteredResourceType) ResourceType() ResourceType {
+	return ResourceType{
+		Name: registered.Name,
+		Type: "docker-image",
+		Source: Source{
+			"repository": registered.Image,
+			"tag":        registered.Version,
+		},
+	}
+}
+
+// WithRegistered adds the registered resource types to the pipeline's own,
+// leaving out any the pipeline overrides by declaring a type with the same
+// name.
+func (types ResourceTypes) WithRegistered(registered []RegisteredResourceType) ResourceTypes {
+	var merged ResourceTypes
+	merged = append(merged, types...)
+
+	for _, r := range registered {
+		if _, found := types.Lookup(r.Name); found {
+			continue
+		}
+
+		merged = append(merged, r.ResourceType())
+	}
+
+	return merged
+}
@@ -0,0 +1,19 @@
+package atc
+
+// RemoteTrigger is what one ATC sends another to trigger one of its jobs.
+type RemoteTrigger struct {
+	Source RemoteTriggerSource `json:"source"`
+
+	// Versions are saved for the resources of the same name before the job
+	// is triggered, as though a check had just found them.
+	Versions map[string]Version `json:"versions,omitempty"`
+}
+
+// RemoteTriggerSource says which build fired a remote trigger.
+type RemoteTriggerSource struct {
+	TeamName     string `json:"team_name"`
+	PipelineName string `json:"pipeline_name"`
+	JobName      string `json:"job_name"`
+	BuildName    string `json:"build_name"`
+	BuildURL     string `json:"build_url,omitempty"`
+}
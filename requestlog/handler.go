@@ -0,0 +1,146 @@
+package requestlog
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"regexp"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/nu7hatch/gouuid"
+)
+
+// Header carries the request's ID, both on the way in, for clients and
+// proxies that already have one, and on the way out.
+const Header = "X-Request-ID"
+
+// incoming IDs end up in logs, so anything that could garble them is replaced
+var validID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+type idKey struct{}
+
+// ID returns the ID of the request, if it went through a Handler.
+func ID(r *http.Request) (string, bool) {
+	id, found := r.Context().Value(idKey{}).(string)
+	return id, found
+}
+
+// Logger returns the logger with the request's ID attached, so that log lines
+// from handling the request, and from the builds and queries it kicks off,
+// can be tied together.
+func Logger(logger lager.Logger, r *http.Request) lager.Logger {
+	id, found := ID(r)
+	if !found {
+		return logger
+	}
+
+	return logger.WithData(lager.Data{"request-id": id})
+}
+
+type Handler struct {
+	Logger lager.Logger
+
+	Route   string
+	Handler http.Handler
+}
+
+func WrapHandler(logger lager.Logger, route string, handler http.Handler) http.Handler {
+	return Handler{
+		Logger:  logger,
+		Route:   route,
+		Handler: handler,
+	}
+}
+
+func (handler Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id := r.Header.Get(Header)
+	if !validID.MatchString(id) {
+		guid, err := uuid.NewV4()
+		if err != nil {
+			handler.Logger.Error("failed-to-generate-request-id", err)
+		} else {
+			id = guid.String()
+		}
+	}
+
+	w.Header().Set(Header, id)
+
+	recorder := &statusRecorder{ResponseWriter: w}
+
+	start := time.Now()
+	handler.Handler.ServeHTTP(recorder, r.WithContext(context.WithValue(r.Context(), idKey{}, id)))
+
+	status := recorder.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	handler.Logger.Info("request", lager.Data{
+		"request-id": id,
+		"route":      handler.Route,
+		"method":     r.Method,
+		"path":       r.URL.Path,
+		"status":     status,
+		"duration":   time.Since(start).String(),
+	})
+}
+
+// statusRecorder notes the status written while still letting the handlers
+// that stream (events) or take over the connection (hijacking) do so.
+type statusRecorder struct {
+	http.ResponseWriter
+
+	status int
+}
+
+func (recorder *statusRecorder) WriteHeader(status int) {
+	if recorder.status == 0 {
+		recorder.status = status
+	}
+
+	recorder.ResponseWriter.WriteHeader(status)
+}
+
+func (recorder *statusRecorder) Write(b []byte) (int, error) {
+	if recorder.status == 0 {
+		recorder.status = http.StatusOK
+	}
+
+	return recorder.ResponseWriter.Write(b)
+}
+
+func (recorder *statusRecorder) Flush() {
+	if flusher, ok := recorder.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (recorder *statusRecorder) CloseNotify() <-chan bool {
+	if notifier, ok := recorder.ResponseWriter.(http.CloseNotifier); ok {
+		return notifier.CloseNotify()
+	}
+
+	return make(chan bool)
+}
+
+func (recorder *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := recorder.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer cannot be hijacked")
+	}
+
+	if recorder.status == 0 {
+		recorder.status = http.StatusSwitchingProtocols
+	}
+
+	return hijacker.Hijack()
+}
+
+// Unwrap lets http.ResponseController reach the connection, e.g. for
+// streams to set write deadlines.
+func (recorder *statusRecorder) Unwrap() http.ResponseWriter {
+	return recorder.ResponseWriter
+}
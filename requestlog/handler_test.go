@@ -0,0 +1,90 @@
+package requestlog_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/lager/lagertest"
+	"github.com/concourse/atc/requestlog"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Handler", func() {
+	var (
+		logger *lagertest.TestLogger
+
+		handlerLogs *lagertest.TestLogger
+		seenID      string
+
+		request  *http.Request
+		recorder *httptest.ResponseRecorder
+	)
+
+	BeforeEach(func() {
+		logger = lagertest.NewTestLogger("api")
+		handlerLogs = lagertest.NewTestLogger("handler")
+
+		var err error
+		request, err = http.NewRequest("PUT", "/api/v1/some/path", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		recorder = httptest.NewRecorder()
+	})
+
+	JustBeforeEach(func() {
+		requestlog.WrapHandler(logger, "SomeRoute", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			seenID, _ = requestlog.ID(r)
+			requestlog.Logger(handlerLogs, r).Info("handling")
+			w.WriteHeader(http.StatusTeapot)
+		})).ServeHTTP(recorder, request)
+	})
+
+	It("gives the request an ID and responds with it", func() {
+		Expect(seenID).NotTo(BeEmpty())
+		Expect(recorder.Header().Get(requestlog.Header)).To(Equal(seenID))
+	})
+
+	It("attaches the ID to the handler's logs", func() {
+		Expect(handlerLogs.LogMessages()).To(Equal([]string{"handler.handling"}))
+		Expect(handlerLogs.Logs()[0].Data["request-id"]).To(Equal(seenID))
+	})
+
+	It("logs the request once it has been handled", func() {
+		Expect(logger.Logs()).To(HaveLen(1))
+
+		log := logger.Logs()[0]
+		Expect(log.Message).To(Equal("api.request"))
+		Expect(log.LogLevel).To(Equal(lager.INFO))
+		Expect(log.Data["request-id"]).To(Equal(seenID))
+		Expect(log.Data["route"]).To(Equal("SomeRoute"))
+		Expect(log.Data["method"]).To(Equal("PUT"))
+		Expect(log.Data["path"]).To(Equal("/api/v1/some/path"))
+		Expect(log.Data["status"]).To(BeNumerically("==", http.StatusTeapot))
+		Expect(log.Data).To(HaveKey("duration"))
+	})
+
+	Context("when the request already has an ID", func() {
+		BeforeEach(func() {
+			request.Header.Set(requestlog.Header, "some-request-id")
+		})
+
+		It("keeps it", func() {
+			Expect(seenID).To(Equal("some-request-id"))
+			Expect(recorder.Header().Get(requestlog.Header)).To(Equal("some-request-id"))
+		})
+	})
+
+	Context("when the request's ID is not fit for logging", func() {
+		BeforeEach(func() {
+			request.Header.Set(requestlog.Header, "some request id\nwith a forged log line")
+		})
+
+		It("replaces it", func() {
+			Expect(seenID).NotTo(BeEmpty())
+			Expect(seenID).NotTo(ContainSubstring("forged"))
+		})
+	})
+})
@@ -8,6 +8,9 @@ type Resource struct {
 
 	Paused bool `json:"paused,omitempty"`
 
+	PinnedVersionID int `json:"pinned_version_id,omitempty"`
+
 	FailingToCheck bool   `json:"failing_to_check,omitempty"`
 	CheckError     string `json:"check_error,omitempty"`
+	CheckErroredAt int64  `json:"check_errored_at,omitempty"`
 }
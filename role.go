@@ -0,0 +1,45 @@
+package atc
+
+// Role is what someone logged in to a team may do there. Each role allows
+// everything the ones before it do.
+type Role string
+
+const (
+	RoleViewer   Role = "viewer"
+	RoleOperator Role = "operator"
+	RoleAdmin    Role = "admin"
+)
+
+var roleLevels = map[Role]int{
+	RoleViewer:   1,
+	RoleOperator: 2,
+	RoleAdmin:    3,
+}
+
+func (role Role) IsValid() bool {
+	return roleLevels[role] != 0
+}
+
+func (role Role) Allows(required Role) bool {
+	return role.IsValid() && roleLevels[role] >= roleLevels[required]
+}
+
+// TeamRoles sets the role given to those who log in to a team through each
+// of its auth methods. Methods without a role give the admin role.
+type TeamRoles struct {
+	BasicAuth    Role `json:"basic_auth,omitempty"`
+	GitHubAuth   Role `json:"github_auth,omitempty"`
+	UAAAuth      Role `json:"uaa_auth,omitempty"`
+	GenericOAuth Role `json:"genericoauth_auth,omitempty"`
+	OIDCAuth     Role `json:"oidc_auth,omitempty"`
+	SAMLAuth     Role `json:"saml_auth,omitempty"`
+}
+
+// OrAdmin returns the role, or the admin role if it is unset.
+func (role Role) OrAdmin() Role {
+	if role == "" {
+		return RoleAdmin
+	}
+
+	return role
+}
@@ -3,43 +3,115 @@ package atc
 import "github.com/tedsuo/rata"
 
 const (
-	SaveConfig = "SaveConfig"
-	GetConfig  = "GetConfig"
+	SaveConfig       = "SaveConfig"
+	GetConfig        = "GetConfig"
+	GetConfigHistory = "GetConfigHistory"
+	RevertConfig     = "RevertConfig"
+	ValidateConfig   = "ValidateConfig"
+
+	SaveCandidateConfig    = "SaveCandidateConfig"
+	GetCandidateConfig     = "GetCandidateConfig"
+	DeleteCandidateConfig  = "DeleteCandidateConfig"
+	PromoteCandidateConfig = "PromoteCandidateConfig"
+	GetCandidateComparison = "GetCandidateComparison"
 
 	GetBuild            = "GetBuild"
 	GetBuildPlan        = "GetBuildPlan"
 	CreateBuild         = "CreateBuild"
 	ListBuilds          = "ListBuilds"
+	CreateTeamBuild     = "CreateTeamBuild"
+	ListTeamBuilds      = "ListTeamBuilds"
 	BuildEvents         = "BuildEvents"
+	ListBuildEvents     = "ListBuildEvents"
+	MultiplexEvents     = "MultiplexEvents"
+	GetBuildLog         = "GetBuildLog"
+	GetBuildLogHTML     = "GetBuildLogHTML"
 	BuildResources      = "BuildResources"
+	GetBuildDiff        = "GetBuildDiff"
 	AbortBuild          = "AbortBuild"
+	RerunBuild          = "RerunBuild"
 	GetBuildPreparation = "GetBuildPreparation"
+	GetBuildStatuses    = "GetBuildStatuses"
+	HijackBuild         = "HijackBuild"
+	SetBuildPriority    = "SetBuildPriority"
+	SearchBuildLogs     = "SearchBuildLogs"
+	SearchAllBuildLogs  = "SearchAllBuildLogs"
+	GetBuildUsage       = "GetBuildUsage"
+	VerifyBuildEvents   = "VerifyBuildEvents"
+	WaitForBuild        = "WaitForBuild"
+
+	RegisterBuildArtifact = "RegisterBuildArtifact"
+	ListBuildArtifacts    = "ListBuildArtifacts"
+	DownloadBuildArtifact = "DownloadBuildArtifact"
+
+	CreateBuildComment = "CreateBuildComment"
+	ListBuildComments  = "ListBuildComments"
+
+	ListBuildApprovals  = "ListBuildApprovals"
+	DecideBuildApproval = "DecideBuildApproval"
+
+	ListBuildSteps = "ListBuildSteps"
+	GetBuildReport = "GetBuildReport"
+
+	GetBuildReaperStatus = "GetBuildReaperStatus"
+	ReconcileBuilds      = "ReconcileBuilds"
 
-	GetJob         = "GetJob"
-	CreateJobBuild = "CreateJobBuild"
-	ListJobs       = "ListJobs"
-	ListJobBuilds  = "ListJobBuilds"
-	ListJobInputs  = "ListJobInputs"
-	GetJobBuild    = "GetJobBuild"
-	PauseJob       = "PauseJob"
-	UnpauseJob     = "UnpauseJob"
-	GetVersionsDB  = "GetVersionsDB"
-	JobBadge       = "JobBadge"
-	MainJobBadge   = "MainJobBadge"
-
-	ListResources   = "ListResources"
-	GetResource     = "GetResource"
-	PauseResource   = "PauseResource"
-	UnpauseResource = "UnpauseResource"
-	CheckResource   = "CheckResource"
+	GetGlobalMaxInFlight = "GetGlobalMaxInFlight"
+	SetGlobalMaxInFlight = "SetGlobalMaxInFlight"
+
+	ListPipelineWeights = "ListPipelineWeights"
+	SetPipelineWeight   = "SetPipelineWeight"
+
+	GetMaintenance            = "GetMaintenance"
+	SetMaintenanceWindows     = "SetMaintenanceWindows"
+	OverrideMaintenance       = "OverrideMaintenance"
+	CancelMaintenanceOverride = "CancelMaintenanceOverride"
+
+	GetJob                     = "GetJob"
+	GetJobSchedule             = "GetJobSchedule"
+	GetJobStats                = "GetJobStats"
+	GetJobScheduling           = "GetJobScheduling"
+	ListJobCaches              = "ListJobCaches"
+	InvalidateJobCaches        = "InvalidateJobCaches"
+	ListExpiringJobBuilds      = "ListExpiringJobBuilds"
+	ProtectJobBuildArtifacts   = "ProtectJobBuildArtifacts"
+	UnprotectJobBuildArtifacts = "UnprotectJobBuildArtifacts"
+	SaveJobWebhook             = "SaveJobWebhook"
+	TriggerWebhook             = "TriggerWebhook"
+	ReceiveRemoteTrigger       = "ReceiveRemoteTrigger"
+	CreateJobBuild             = "CreateJobBuild"
+	ListJobs                   = "ListJobs"
+	ListJobBuilds              = "ListJobBuilds"
+	ListJobInputs              = "ListJobInputs"
+	GetJobBuild                = "GetJobBuild"
+	PauseJob                   = "PauseJob"
+	UnpauseJob                 = "UnpauseJob"
+	MakeJobManualOnly          = "MakeJobManualOnly"
+	MakeJobAutomatic           = "MakeJobAutomatic"
+	GetVersionsDB              = "GetVersionsDB"
+	JobBadge                   = "JobBadge"
+	MainJobBadge               = "MainJobBadge"
+
+	ListResources           = "ListResources"
+	ListResourceCheckErrors = "ListResourceCheckErrors"
+	GetResource             = "GetResource"
+	PauseResource           = "PauseResource"
+	UnpauseResource         = "UnpauseResource"
+	CheckResource           = "CheckResource"
 
 	ListResourceVersions          = "ListResourceVersions"
 	EnableResourceVersion         = "EnableResourceVersion"
 	DisableResourceVersion        = "DisableResourceVersion"
+	PinResourceVersion            = "PinResourceVersion"
+	UnpinResource                 = "UnpinResource"
 	ListBuildsWithVersionAsInput  = "ListBuildsWithVersionAsInput"
 	ListBuildsWithVersionAsOutput = "ListBuildsWithVersionAsOutput"
+	GetResourceVersionCausality   = "GetResourceVersionCausality"
+	ListPassedResourceVersions    = "ListPassedResourceVersions"
+	PassedResourceVersionEvents   = "PassedResourceVersionEvents"
 
 	ListAllPipelines = "ListAllPipelines"
+	GetDashboard     = "GetDashboard"
 	ListPipelines    = "ListPipelines"
 	GetPipeline      = "GetPipeline"
 	DeletePipeline   = "DeletePipeline"
@@ -50,6 +122,13 @@ const (
 	HidePipeline     = "HidePipeline"
 	RenamePipeline   = "RenamePipeline"
 
+	GetPipelinePermissions = "GetPipelinePermissions"
+	SetPipelinePermissions = "SetPipelinePermissions"
+
+	ListPipelineInstances  = "ListPipelineInstances"
+	SavePipelineInstance   = "SavePipelineInstance"
+	DeletePipelineInstance = "DeletePipelineInstance"
+
 	CreatePipe = "CreatePipe"
 	WritePipe  = "WritePipe"
 	ReadPipe   = "ReadPipe"
@@ -75,33 +154,117 @@ const (
 
 	ListTeams = "ListTeams"
 	SetTeam   = "SetTeam"
+
+	ListAuditEvents = "ListAuditEvents"
+
+	Export = "Export"
+	Import = "Import"
+
+	CreateAPIToken = "CreateAPIToken"
+	ListAPITokens  = "ListAPITokens"
+	RevokeAPIToken = "RevokeAPIToken"
+
+	ListRegisteredResourceTypes = "ListRegisteredResourceTypes"
+	RegisterResourceType        = "RegisterResourceType"
+	UnregisterResourceType      = "UnregisterResourceType"
+
+	GetGCStatus = "GetGCStatus"
+	RunGC       = "RunGC"
+
+	GetUsage = "GetUsage"
+
+	ListQueue = "ListQueue"
+
+	ListAlerts = "ListAlerts"
 )
 
 var Routes = rata.Routes([]rata.Route{
 	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/config", Method: "PUT", Name: SaveConfig},
 	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/config", Method: "GET", Name: GetConfig},
+	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/config/history", Method: "GET", Name: GetConfigHistory},
+	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/config/revert/:config_version", Method: "POST", Name: RevertConfig},
+	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/config/validate", Method: "POST", Name: ValidateConfig},
+	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/config/candidate", Method: "PUT", Name: SaveCandidateConfig},
+	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/config/candidate", Method: "GET", Name: GetCandidateConfig},
+	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/config/candidate", Method: "DELETE", Name: DeleteCandidateConfig},
+	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/config/candidate/promote", Method: "POST", Name: PromoteCandidateConfig},
+	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/config/candidate/comparison", Method: "GET", Name: GetCandidateComparison},
 
 	{Path: "/api/v1/builds", Method: "POST", Name: CreateBuild},
 	{Path: "/api/v1/builds", Method: "GET", Name: ListBuilds},
+	{Path: "/api/v1/teams/:team_name/builds", Method: "POST", Name: CreateTeamBuild},
+	{Path: "/api/v1/teams/:team_name/builds", Method: "GET", Name: ListTeamBuilds},
+	{Path: "/api/v1/builds/status", Method: "POST", Name: GetBuildStatuses},
+	{Path: "/api/v1/builds/reconcile", Method: "POST", Name: ReconcileBuilds},
+	{Path: "/api/v1/builds/events/search", Method: "GET", Name: SearchAllBuildLogs},
+	{Path: "/api/v1/builds/events", Method: "GET", Name: MultiplexEvents},
 	{Path: "/api/v1/builds/:build_id", Method: "GET", Name: GetBuild},
 	{Path: "/api/v1/builds/:build_id/plan", Method: "GET", Name: GetBuildPlan},
+	{Path: "/api/v1/builds/:build_id/wait", Method: "GET", Name: WaitForBuild},
 	{Path: "/api/v1/builds/:build_id/events", Method: "GET", Name: BuildEvents},
+	{Path: "/api/v1/builds/:build_id/log", Method: "GET", Name: GetBuildLog},
+	{Path: "/api/v1/builds/:build_id/log.html", Method: "GET", Name: GetBuildLogHTML},
+	{Path: "/api/v1/builds/:build_id/events/list", Method: "GET", Name: ListBuildEvents},
+	{Path: "/api/v1/builds/:build_id/events/search", Method: "GET", Name: SearchBuildLogs},
+	{Path: "/api/v1/builds/:build_id/events/verification", Method: "GET", Name: VerifyBuildEvents},
 	{Path: "/api/v1/builds/:build_id/resources", Method: "GET", Name: BuildResources},
+	{Path: "/api/v1/builds/:build_id/diff", Method: "GET", Name: GetBuildDiff},
 	{Path: "/api/v1/builds/:build_id/abort", Method: "POST", Name: AbortBuild},
+	{Path: "/api/v1/builds/:build_id/rerun", Method: "POST", Name: RerunBuild},
 	{Path: "/api/v1/builds/:build_id/preparation", Method: "GET", Name: GetBuildPreparation},
+	{Path: "/api/v1/builds/:build_id/usage", Method: "GET", Name: GetBuildUsage},
+	{Path: "/api/v1/builds/:build_id/hijack", Method: "GET", Name: HijackBuild},
+	{Path: "/api/v1/builds/:build_id/priority", Method: "PUT", Name: SetBuildPriority},
+	{Path: "/api/v1/builds/:build_id/artifacts", Method: "POST", Name: RegisterBuildArtifact},
+	{Path: "/api/v1/builds/:build_id/artifacts", Method: "GET", Name: ListBuildArtifacts},
+	{Path: "/api/v1/builds/:build_id/artifacts/:artifact_name", Method: "GET", Name: DownloadBuildArtifact},
+	{Path: "/api/v1/builds/:build_id/comments", Method: "POST", Name: CreateBuildComment},
+	{Path: "/api/v1/builds/:build_id/comments", Method: "GET", Name: ListBuildComments},
+	{Path: "/api/v1/builds/:build_id/approvals", Method: "GET", Name: ListBuildApprovals},
+	{Path: "/api/v1/builds/:build_id/approvals/:step", Method: "POST", Name: DecideBuildApproval},
+	{Path: "/api/v1/builds/:build_id/steps", Method: "GET", Name: ListBuildSteps},
+	{Path: "/api/v1/builds/:build_id/report", Method: "GET", Name: GetBuildReport},
+
+	{Path: "/api/v1/build-reaper", Method: "GET", Name: GetBuildReaperStatus},
+
+	{Path: "/api/v1/max-in-flight", Method: "GET", Name: GetGlobalMaxInFlight},
+	{Path: "/api/v1/max-in-flight", Method: "PUT", Name: SetGlobalMaxInFlight},
+
+	{Path: "/api/v1/pipeline-weights", Method: "GET", Name: ListPipelineWeights},
+	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/weight", Method: "PUT", Name: SetPipelineWeight},
+
+	{Path: "/api/v1/maintenance", Method: "GET", Name: GetMaintenance},
+	{Path: "/api/v1/maintenance/windows", Method: "PUT", Name: SetMaintenanceWindows},
+	{Path: "/api/v1/maintenance/override", Method: "PUT", Name: OverrideMaintenance},
+	{Path: "/api/v1/maintenance/override", Method: "DELETE", Name: CancelMaintenanceOverride},
 
 	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/jobs", Method: "GET", Name: ListJobs},
 	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name", Method: "GET", Name: GetJob},
 	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/builds", Method: "GET", Name: ListJobBuilds},
 	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/builds", Method: "POST", Name: CreateJobBuild},
+	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/remote-triggers", Method: "POST", Name: ReceiveRemoteTrigger},
 	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/inputs", Method: "GET", Name: ListJobInputs},
+	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/schedule", Method: "GET", Name: GetJobSchedule},
+	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/stats", Method: "GET", Name: GetJobStats},
+	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/scheduling", Method: "GET", Name: GetJobScheduling},
+	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/caches", Method: "GET", Name: ListJobCaches},
+	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/caches", Method: "DELETE", Name: InvalidateJobCaches},
+	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/artifacts/expiring", Method: "GET", Name: ListExpiringJobBuilds},
+	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/hooks/:hook_id", Method: "PUT", Name: SaveJobWebhook},
 	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/builds/:build_name", Method: "GET", Name: GetJobBuild},
+	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/builds/:build_name/protect", Method: "PUT", Name: ProtectJobBuildArtifacts},
+	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/builds/:build_name/protect", Method: "DELETE", Name: UnprotectJobBuildArtifacts},
 	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/pause", Method: "PUT", Name: PauseJob},
 	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/unpause", Method: "PUT", Name: UnpauseJob},
+	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/manual-only", Method: "PUT", Name: MakeJobManualOnly},
+	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/automatic", Method: "PUT", Name: MakeJobAutomatic},
 	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/badge", Method: "GET", Name: JobBadge},
 	{Path: "/api/v1/pipelines/:pipeline_name/jobs/:job_name/badge", Method: "GET", Name: MainJobBadge},
 
+	{Path: "/api/v1/hooks/:hook_id", Method: "POST", Name: TriggerWebhook},
+
 	{Path: "/api/v1/pipelines", Method: "GET", Name: ListAllPipelines},
+	{Path: "/api/v1/dashboard", Method: "GET", Name: GetDashboard},
 	{Path: "/api/v1/teams/:team_name/pipelines", Method: "GET", Name: ListPipelines},
 	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name", Method: "GET", Name: GetPipeline},
 	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name", Method: "DELETE", Name: DeletePipeline},
@@ -112,18 +275,29 @@ var Routes = rata.Routes([]rata.Route{
 	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/hide", Method: "PUT", Name: HidePipeline},
 	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/versions-db", Method: "GET", Name: GetVersionsDB},
 	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/rename", Method: "PUT", Name: RenamePipeline},
+	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/permissions", Method: "GET", Name: GetPipelinePermissions},
+	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/permissions", Method: "PUT", Name: SetPipelinePermissions},
+	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/instances", Method: "GET", Name: ListPipelineInstances},
+	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/instances/:instance_name", Method: "PUT", Name: SavePipelineInstance},
+	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/instances/:instance_name", Method: "DELETE", Name: DeletePipelineInstance},
 
 	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/resources", Method: "GET", Name: ListResources},
+	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/resource-check-errors", Method: "GET", Name: ListResourceCheckErrors},
 	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/resources/:resource_name", Method: "GET", Name: GetResource},
 	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/resources/:resource_name/pause", Method: "PUT", Name: PauseResource},
 	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/resources/:resource_name/unpause", Method: "PUT", Name: UnpauseResource},
 	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/resources/:resource_name/check", Method: "POST", Name: CheckResource},
 
 	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/resources/:resource_name/versions", Method: "GET", Name: ListResourceVersions},
+	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/resources/:resource_name/versions/passed", Method: "GET", Name: ListPassedResourceVersions},
+	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/resources/:resource_name/versions/passed/events", Method: "GET", Name: PassedResourceVersionEvents},
 	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/resources/:resource_name/versions/:resource_version_id/enable", Method: "PUT", Name: EnableResourceVersion},
 	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/resources/:resource_name/versions/:resource_version_id/disable", Method: "PUT", Name: DisableResourceVersion},
+	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/resources/:resource_name/versions/:resource_version_id/pin", Method: "PUT", Name: PinResourceVersion},
+	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/resources/:resource_name/unpin", Method: "PUT", Name: UnpinResource},
 	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/resources/:resource_name/versions/:resource_version_id/input_to", Method: "GET", Name: ListBuildsWithVersionAsInput},
 	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/resources/:resource_name/versions/:resource_version_id/output_of", Method: "GET", Name: ListBuildsWithVersionAsOutput},
+	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/resources/:resource_name/versions/:resource_version_id/causality", Method: "GET", Name: GetResourceVersionCausality},
 
 	{Path: "/api/v1/pipes", Method: "POST", Name: CreatePipe},
 	{Path: "/api/v1/pipes/:pipe_id", Method: "PUT", Name: WritePipe},
@@ -150,4 +324,52 @@ var Routes = rata.Routes([]rata.Route{
 
 	{Path: "/api/v1/teams", Method: "GET", Name: ListTeams},
 	{Path: "/api/v1/teams/:team_name", Method: "PUT", Name: SetTeam},
+
+	{Path: "/api/v1/audit", Method: "GET", Name: ListAuditEvents},
+
+	{Path: "/api/v1/export", Method: "GET", Name: Export},
+	{Path: "/api/v1/import", Method: "POST", Name: Import},
+
+	{Path: "/api/v1/teams/:team_name/tokens", Method: "POST", Name: CreateAPIToken},
+	{Path: "/api/v1/teams/:team_name/tokens", Method: "GET", Name: ListAPITokens},
+	{Path: "/api/v1/teams/:team_name/tokens/:token_id", Method: "DELETE", Name: RevokeAPIToken},
+
+	{Path: "/api/v1/resource-types", Method: "GET", Name: ListRegisteredResourceTypes},
+	{Path: "/api/v1/resource-types/:resource_type_name", Method: "PUT", Name: RegisterResourceType},
+	{Path: "/api/v1/resource-types/:resource_type_name", Method: "DELETE", Name: UnregisterResourceType},
+
+	{Path: "/api/v1/gc", Method: "GET", Name: GetGCStatus},
+	{Path: "/api/v1/gc", Method: "POST", Name: RunGC},
+
+	{Path: "/api/v1/usage", Method: "GET", Name: GetUsage},
+
+	{Path: "/api/v1/queue", Method: "GET", Name: ListQueue},
+
+	{Path: "/api/v1/teams/:team_name/alerts", Method: "GET", Name: ListAlerts},
+})
+
+// V2Routes are served by the same handlers as the v1 routes of the same name,
+// with their responses reshaped by the v2 compatibility layer, so that the v1
+// responses can stay as they are.
+var V2Routes = rata.Routes([]rata.Route{
+	{Path: "/api/v2/info", Method: "GET", Name: GetInfo},
+
+	{Path: "/api/v2/teams", Method: "GET", Name: ListTeams},
+
+	{Path: "/api/v2/builds", Method: "GET", Name: ListBuilds},
+	{Path: "/api/v2/builds/:build_id", Method: "GET", Name: GetBuild},
+
+	{Path: "/api/v2/pipelines", Method: "GET", Name: ListAllPipelines},
+	{Path: "/api/v2/teams/:team_name/pipelines", Method: "GET", Name: ListPipelines},
+	{Path: "/api/v2/teams/:team_name/pipelines/:pipeline_name", Method: "GET", Name: GetPipeline},
+
+	{Path: "/api/v2/teams/:team_name/pipelines/:pipeline_name/jobs", Method: "GET", Name: ListJobs},
+	{Path: "/api/v2/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name", Method: "GET", Name: GetJob},
+	{Path: "/api/v2/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/builds", Method: "GET", Name: ListJobBuilds},
+
+	{Path: "/api/v2/teams/:team_name/pipelines/:pipeline_name/resources", Method: "GET", Name: ListResources},
+	{Path: "/api/v2/teams/:team_name/pipelines/:pipeline_name/resources/:resource_name", Method: "GET", Name: GetResource},
+	{Path: "/api/v2/teams/:team_name/pipelines/:pipeline_name/resources/:resource_name/versions", Method: "GET", Name: ListResourceVersions},
+
+	{Path: "/api/v2/workers", Method: "GET", Name: ListWorkers},
 })
@@ -1,8 +1,13 @@
 package buildstarter
 
 import (
+	"reflect"
+	"time"
+
 	"code.cloudfoundry.org/lager"
 	"github.com/concourse/atc"
+	"github.com/concourse/atc/config"
+	"github.com/concourse/atc/creds"
 	"github.com/concourse/atc/db"
 	"github.com/concourse/atc/engine"
 	"github.com/concourse/atc/scheduler/buildstarter/maxinflight"
@@ -26,8 +31,12 @@ type BuildStarterDB interface {
 	GetNextBuildInputs(jobName string) ([]db.BuildInput, bool, error)
 	IsPaused() (bool, error)
 	GetJob(job string) (db.SavedJob, error)
+	GetRegisteredResourceTypes() ([]atc.RegisteredResourceType, error)
 	UpdateBuildToScheduled(int) (bool, error)
 	UseInputsForBuild(buildID int, inputs []db.BuildInput) error
+	SaveSchedulingReason(jobName string, reason db.SchedulingReason) error
+	GetCandidateConfig() (db.CandidateConfig, bool, error)
+	UseCandidateConfigForBuild(buildID int, candidateID int) error
 }
 
 //go:generate counterfeiter . BuildStarterBuildsDB
@@ -36,6 +45,14 @@ type BuildStarterBuildsDB interface {
 	FinishBuild(buildID int, pipelineID int, status db.Status) error
 }
 
+//go:generate counterfeiter . BuildStarterLimitsDB
+
+type BuildStarterLimitsDB interface {
+	GlobalMaxInFlightReached() (bool, error)
+	FairShareReached(pipelineID int) (bool, error)
+	MaintenanceHoldsBuilds() (bool, error)
+}
+
 //go:generate counterfeiter . BuildFactory
 
 type BuildFactory interface {
@@ -44,23 +61,32 @@ type BuildFactory interface {
 
 func NewBuildStarter(
 	db BuildStarterDB,
+	limitsDB BuildStarterLimitsDB,
 	maxInFlightUpdater maxinflight.Updater,
 	factory BuildFactory,
 	execEngine engine.Engine,
+	credsManager creds.Manager,
 ) BuildStarter {
 	return &buildStarter{
 		db:                 db,
+		limitsDB:           limitsDB,
 		maxInFlightUpdater: maxInFlightUpdater,
 		factory:            factory,
 		execEngine:         execEngine,
+		credsManager:       credsManager,
 	}
 }
 
 type buildStarter struct {
 	db                 BuildStarterDB
+	limitsDB           BuildStarterLimitsDB
 	maxInFlightUpdater maxinflight.Updater
 	factory            BuildFactory
 	execEngine         engine.Engine
+
+	// nil if no credential manager is configured, in which case ((name))s are
+	// left as they are
+	credsManager creds.Manager
 }
 
 func (s *buildStarter) TryStartAllPendingBuilds(
@@ -95,7 +121,7 @@ func (s *buildStarter) tryStartNextPendingBuild(
 		return false, err
 	}
 	if !found {
-		return false, nil
+		return s.notStarted(logger, jobConfig.Name, db.SchedulingReasonNoPendingBuilds)
 	}
 
 	logger = logger.WithData(lager.Data{
@@ -108,7 +134,12 @@ func (s *buildStarter) tryStartNextPendingBuild(
 		return false, err
 	}
 	if reachedMaxInFlight {
-		return false, nil
+		// the builds holding it back may be of any job in the groups
+		if len(jobConfig.SerialGroups) > 0 {
+			return s.notStarted(logger, jobConfig.Name, db.SchedulingReasonSerialGroupBusy)
+		}
+
+		return s.notStarted(logger, jobConfig.Name, db.SchedulingReasonMaxInFlightReached)
 	}
 
 	buildInputs, found, err := s.db.GetNextBuildInputs(jobConfig.Name)
@@ -117,7 +148,7 @@ func (s *buildStarter) tryStartNextPendingBuild(
 		return false, err
 	}
 	if !found {
-		return false, nil
+		return s.notStarted(logger, jobConfig.Name, db.SchedulingReasonInputsNotSatisfied)
 	}
 
 	pipelinePaused, err := s.db.IsPaused()
@@ -126,7 +157,7 @@ func (s *buildStarter) tryStartNextPendingBuild(
 		return false, err
 	}
 	if pipelinePaused {
-		return false, nil
+		return s.notStarted(logger, jobConfig.Name, db.SchedulingReasonPipelinePaused)
 	}
 
 	job, err := s.db.GetJob(jobConfig.Name)
@@ -135,7 +166,47 @@ func (s *buildStarter) tryStartNextPendingBuild(
 		return false, err
 	}
 	if job.Paused {
-		return false, nil
+		return s.notStarted(logger, jobConfig.Name, db.SchedulingReasonJobPaused)
+	}
+
+	// checked last, as it's the same for every job; the build stays pending
+	// until enough builds elsewhere have finished
+	reachedGlobalMaxInFlight, err := s.limitsDB.GlobalMaxInFlightReached()
+	if err != nil {
+		logger.Error("failed-to-check-global-max-in-flight", err)
+		return false, err
+	}
+	if reachedGlobalMaxInFlight {
+		logger.Debug("global-max-in-flight-reached")
+		return s.notStarted(logger, jobConfig.Name, db.SchedulingReasonGlobalMaxInFlightReached)
+	}
+
+	// with a global limit, a busy pipeline could otherwise take every slot
+	// that frees up before the others get to look for one
+	reachedFairShare, err := s.limitsDB.FairShareReached(nextPendingBuild.PipelineID())
+	if err != nil {
+		logger.Error("failed-to-check-fair-share", err)
+		return false, err
+	}
+	if reachedFairShare {
+		logger.Debug("fair-share-reached")
+		return s.notStarted(logger, jobConfig.Name, db.SchedulingReasonFairShareReached)
+	}
+
+	inMaintenance, err := s.limitsDB.MaintenanceHoldsBuilds()
+	if err != nil {
+		logger.Error("failed-to-check-maintenance-windows", err)
+		return false, err
+	}
+	if inMaintenance {
+		logger.Debug("held-for-maintenance")
+		return s.notStarted(logger, jobConfig.Name, db.SchedulingReasonMaintenanceWindow)
+	}
+
+	registeredResourceTypes, err := s.db.GetRegisteredResourceTypes()
+	if err != nil {
+		logger.Error("failed-to-get-registered-resource-types", err)
+		return false, err
 	}
 
 	updated, err := s.db.UpdateBuildToScheduled(nextPendingBuild.ID())
@@ -154,7 +225,9 @@ func (s *buildStarter) tryStartNextPendingBuild(
 		return false, err
 	}
 
-	plan, err := s.factory.Create(jobConfig, resourceConfigs, resourceTypes, buildInputs)
+	jobConfig, resourceConfigs, resourceTypes = s.candidateConfig(logger, nextPendingBuild, jobConfig, resourceConfigs, resourceTypes)
+
+	plan, err := s.factory.Create(jobConfig, resourceConfigs, resourceTypes.WithRegistered(registeredResourceTypes), buildInputs)
 	if err != nil {
 		// Don't use ErrorBuild because it logs a build event, and this build hasn't started
 		err := nextPendingBuild.Finish(db.StatusErrored)
@@ -164,6 +237,54 @@ func (s *buildStarter) tryStartNextPendingBuild(
 		return false, nil
 	}
 
+	if s.credsManager != nil {
+		interpolator := creds.NewInterpolator(s.credsManager.Variables(nextPendingBuild.TeamName(), nextPendingBuild.PipelineName()))
+
+		err = interpolator.Plan(&plan)
+		if err != nil {
+			logger.Error("failed-to-interpolate-credentials", err)
+
+			err = nextPendingBuild.MarkAsFailed(err)
+			if err != nil {
+				logger.Error("failed-to-mark-build-as-errored", err)
+			}
+
+			return false, nil
+		}
+
+		if secrets := interpolator.Resolved(); len(secrets) > 0 {
+			err = nextPendingBuild.SaveSecrets(secrets)
+			if err != nil {
+				logger.Error("failed-to-save-secrets", err)
+				return false, err
+			}
+		}
+	}
+
+	// injected after credentials are interpolated, so that whoever triggered
+	// the build can't have them interpolated into its params
+	params := nextPendingBuild.Params()
+	if len(params) == 0 {
+		params = config.DefaultTriggerParams(jobConfig)
+	}
+
+	if len(params) > 0 {
+		injectTriggerParams(&plan, params)
+	}
+
+	if jobConfig.BuildTimeout != "" {
+		timeout, err := time.ParseDuration(jobConfig.BuildTimeout)
+		if err != nil {
+			logger.Error("failed-to-parse-build-timeout", err)
+		} else {
+			err = nextPendingBuild.SetTimeout(timeout)
+			if err != nil {
+				logger.Error("failed-to-set-build-timeout", err)
+				return false, err
+			}
+		}
+	}
+
 	createdBuild, err := s.execEngine.CreateBuild(logger, nextPendingBuild, plan)
 	if err != nil {
 		logger.Error("failed-to-create-build", err)
@@ -176,3 +297,108 @@ func (s *buildStarter) tryStartNextPendingBuild(
 
 	return true, nil
 }
+
+// candidateConfig returns the job as the pipeline's candidate config has it,
+// for the share of builds the candidate is being tried out on. The build's
+// inputs have already been chosen, so the candidate's job is only used if it
+// takes the same inputs from the same resources. Failing to look the
+// candidate up leaves the build to the pipeline's config.
+func (s *buildStarter) candidateConfig(
+	logger lager.Logger,
+	build db.Build,
+	jobConfig atc.JobConfig,
+	resourceConfigs atc.ResourceConfigs,
+	resourceTypes atc.ResourceTypes,
+) (atc.JobConfig, atc.ResourceConfigs, atc.ResourceTypes) {
+	candidate, found, err := s.db.GetCandidateConfig()
+	if err != nil {
+		logger.Error("failed-to-get-candidate-config", err)
+		return jobConfig, resourceConfigs, resourceTypes
+	}
+
+	if !found || build.ID()%100 >= candidate.Percentage {
+		return jobConfig, resourceConfigs, resourceTypes
+	}
+
+	candidateJob, found := candidate.Config.Jobs.Lookup(jobConfig.Name)
+	if !found || !sameInputs(jobConfig, resourceConfigs, candidateJob, candidate.Config.Resources) {
+		logger.Debug("candidate-job-has-different-inputs")
+		return jobConfig, resourceConfigs, resourceTypes
+	}
+
+	err = s.db.UseCandidateConfigForBuild(build.ID(), candidate.ID)
+	if err != nil {
+		logger.Error("failed-to-use-candidate-config", err)
+		return jobConfig, resourceConfigs, resourceTypes
+	}
+
+	logger.Info("using-candidate-config", lager.Data{"candidate": candidate.ID})
+
+	return candidateJob, candidate.Config.Resources, candidate.Config.ResourceTypes
+}
+
+func sameInputs(job atc.JobConfig, resources atc.ResourceConfigs, otherJob atc.JobConfig, otherResources atc.ResourceConfigs) bool {
+	inputs := map[string]string{}
+	for _, input := range config.JobInputs(job) {
+		inputs[input.Name] = input.Resource
+	}
+
+	otherInputs := config.JobInputs(otherJob)
+	if len(otherInputs) != len(inputs) {
+		return false
+	}
+
+	for _, input := range otherInputs {
+		resourceName, found := inputs[input.Name]
+		if !found || resourceName != input.Resource {
+			return false
+		}
+
+		resource, found := resources.Lookup(resourceName)
+		if !found {
+			return false
+		}
+
+		otherResource, found := otherResources.Lookup(resourceName)
+		if !found || otherResource.Type != resource.Type || !reflect.DeepEqual(otherResource.Source, resource.Source) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// notStarted records why the next pending build wasn't started, for anyone
+// wondering why their job hasn't run. Failing to record it doesn't stop
+// scheduling, since nothing was going to start anyway.
+func (s *buildStarter) notStarted(logger lager.Logger, jobName string, reason db.SchedulingReason) (bool, error) {
+	err := s.db.SaveSchedulingReason(jobName, reason)
+	if err != nil {
+		logger.Error("failed-to-save-scheduling-reason", err, lager.Data{"reason": reason})
+	}
+
+	return false, nil
+}
+
+// injectTriggerParams gives every task in the plan the params its build was
+// triggered with, over any of the same name it already had.
+func injectTriggerParams(plan *atc.Plan, params map[string]string) {
+	atc.NewPlanTraversal(func(plan *atc.Plan) error {
+		if plan.Task == nil {
+			return nil
+		}
+
+		taskParams := atc.Params{}
+		for name, value := range plan.Task.Params {
+			taskParams[name] = value
+		}
+
+		for name, value := range params {
+			taskParams[name] = value
+		}
+
+		plan.Task.Params = taskParams
+
+		return nil
+	}).Traverse(plan)
+}
@@ -2,9 +2,11 @@ package buildstarter_test
 
 import (
 	"errors"
+	"time"
 
 	"code.cloudfoundry.org/lager/lagertest"
 	"github.com/concourse/atc"
+	"github.com/concourse/atc/creds/credsfakes"
 	"github.com/concourse/atc/db"
 	"github.com/concourse/atc/db/dbfakes"
 	"github.com/concourse/atc/engine/enginefakes"
@@ -18,10 +20,14 @@ import (
 
 var _ = Describe("I'm a BuildStarter", func() {
 	var (
-		fakeDB      *buildstarterfakes.FakeBuildStarterDB
-		fakeUpdater *maxinflightfakes.FakeUpdater
-		fakeFactory *buildstarterfakes.FakeBuildFactory
-		fakeEngine  *enginefakes.FakeEngine
+		fakeDB       *buildstarterfakes.FakeBuildStarterDB
+		fakeLimitsDB *buildstarterfakes.FakeBuildStarterLimitsDB
+		fakeUpdater  *maxinflightfakes.FakeUpdater
+		fakeFactory  *buildstarterfakes.FakeBuildFactory
+		fakeEngine   *enginefakes.FakeEngine
+
+		fakeCredsManager *credsfakes.FakeManager
+		fakeVariables    *credsfakes.FakeVariables
 
 		buildStarter buildstarter.BuildStarter
 
@@ -30,22 +36,32 @@ var _ = Describe("I'm a BuildStarter", func() {
 
 	BeforeEach(func() {
 		fakeDB = new(buildstarterfakes.FakeBuildStarterDB)
+		fakeLimitsDB = new(buildstarterfakes.FakeBuildStarterLimitsDB)
 		fakeUpdater = new(maxinflightfakes.FakeUpdater)
 		fakeFactory = new(buildstarterfakes.FakeBuildFactory)
 		fakeEngine = new(enginefakes.FakeEngine)
 
-		buildStarter = buildstarter.NewBuildStarter(fakeDB, fakeUpdater, fakeFactory, fakeEngine)
+		fakeVariables = new(credsfakes.FakeVariables)
+		fakeCredsManager = new(credsfakes.FakeManager)
+		fakeCredsManager.VariablesReturns(fakeVariables)
+
+		buildStarter = buildstarter.NewBuildStarter(fakeDB, fakeLimitsDB, fakeUpdater, fakeFactory, fakeEngine, fakeCredsManager)
 
 		disaster = errors.New("bad thing")
 	})
 
 	Describe("TryStartAllPendingBuilds", func() {
+		var jobConfig atc.JobConfig
 		var tryStartErr error
 
+		BeforeEach(func() {
+			jobConfig = atc.JobConfig{Name: "some-job"}
+		})
+
 		JustBeforeEach(func() {
 			tryStartErr = buildStarter.TryStartAllPendingBuilds(
 				lagertest.NewTestLogger("test"),
-				atc.JobConfig{Name: "some-job"},
+				jobConfig,
 				atc.ResourceConfigs{{Name: "some-resource"}},
 				atc.ResourceTypes{{Name: "some-resource-type"}})
 		})
@@ -81,6 +97,23 @@ var _ = Describe("I'm a BuildStarter", func() {
 				It("doesn't return an error", func() {
 					Expect(tryStartErr).NotTo(HaveOccurred())
 				})
+
+				It("records that there was nothing to start", func() {
+					Expect(fakeDB.SaveSchedulingReasonCallCount()).To(Equal(1))
+					jobName, reason := fakeDB.SaveSchedulingReasonArgsForCall(0)
+					Expect(jobName).To(Equal("some-job"))
+					Expect(reason).To(Equal(db.SchedulingReasonNoPendingBuilds))
+				})
+
+				Context("when recording that fails", func() {
+					BeforeEach(func() {
+						fakeDB.SaveSchedulingReasonReturns(disaster)
+					})
+
+					It("doesn't return an error", func() {
+						Expect(tryStartErr).NotTo(HaveOccurred())
+					})
+				})
 			})
 
 			Context("when there is a pending build", func() {
@@ -155,6 +188,143 @@ var _ = Describe("I'm a BuildStarter", func() {
 							fakeDB.UseInputsForBuildReturns(nil)
 						})
 
+						Context("when resource types are registered with the ATC", func() {
+							BeforeEach(func() {
+								fakeDB.GetRegisteredResourceTypesReturns([]atc.RegisteredResourceType{
+									{Name: "some-resource-type", Image: "registered/some-resource-type", Version: "1.0"},
+									{Name: "registered-type", Image: "registered/registered-type", Version: "2.0"},
+								}, nil)
+							})
+
+							It("creates the build plan with the ones the pipeline doesn't override", func() {
+								Expect(fakeFactory.CreateCallCount()).To(Equal(1))
+								_, _, actualResourceTypes, _ := fakeFactory.CreateArgsForCall(0)
+								Expect(actualResourceTypes).To(Equal(atc.ResourceTypes{
+									{Name: "some-resource-type"},
+									{
+										Name:   "registered-type",
+										Type:   "docker-image",
+										Source: atc.Source{"repository": "registered/registered-type", "tag": "2.0"},
+									},
+								}))
+							})
+						})
+
+						Context("when the pipeline has a candidate config", func() {
+							var candidate db.CandidateConfig
+
+							BeforeEach(func() {
+								candidate = db.CandidateConfig{
+									ID:         42,
+									Percentage: 100,
+									Config: atc.Config{
+										Jobs: atc.JobConfigs{
+											{Name: "some-job", Public: true},
+										},
+										Resources:     atc.ResourceConfigs{{Name: "some-resource"}},
+										ResourceTypes: atc.ResourceTypes{{Name: "some-candidate-resource-type"}},
+									},
+								}
+
+								fakeEngine.CreateBuildReturns(new(enginefakes.FakeBuild), nil)
+							})
+
+							JustBeforeEach(func() {
+								Expect(fakeDB.GetCandidateConfigCallCount()).To(Equal(1))
+							})
+
+							Context("when the build is within its percentage", func() {
+								BeforeEach(func() {
+									fakeDB.GetCandidateConfigReturns(candidate, true, nil)
+								})
+
+								It("creates the build plan from the candidate", func() {
+									Expect(fakeFactory.CreateCallCount()).To(Equal(1))
+									actualJobConfig, actualResourceConfigs, actualResourceTypes, _ := fakeFactory.CreateArgsForCall(0)
+									Expect(actualJobConfig).To(Equal(atc.JobConfig{Name: "some-job", Public: true}))
+									Expect(actualResourceConfigs).To(Equal(atc.ResourceConfigs{{Name: "some-resource"}}))
+									Expect(actualResourceTypes).To(Equal(atc.ResourceTypes{{Name: "some-candidate-resource-type"}}))
+								})
+
+								It("records that the build is the candidate's", func() {
+									Expect(fakeDB.UseCandidateConfigForBuildCallCount()).To(Equal(1))
+									buildID, candidateID := fakeDB.UseCandidateConfigForBuildArgsForCall(0)
+									Expect(buildID).To(Equal(99))
+									Expect(candidateID).To(Equal(42))
+								})
+
+								Context("when the candidate's job takes different inputs", func() {
+									BeforeEach(func() {
+										candidate.Config.Jobs[0].Plan = atc.PlanSequence{{Get: "some-resource"}}
+										fakeDB.GetCandidateConfigReturns(candidate, true, nil)
+									})
+
+									It("creates the build plan from the pipeline's config", func() {
+										Expect(fakeFactory.CreateCallCount()).To(Equal(1))
+										actualJobConfig, _, _, _ := fakeFactory.CreateArgsForCall(0)
+										Expect(actualJobConfig).To(Equal(atc.JobConfig{Name: "some-job"}))
+										Expect(fakeDB.UseCandidateConfigForBuildCallCount()).To(BeZero())
+									})
+								})
+
+								Context("when the candidate doesn't have the job", func() {
+									BeforeEach(func() {
+										candidate.Config.Jobs = nil
+										fakeDB.GetCandidateConfigReturns(candidate, true, nil)
+									})
+
+									It("creates the build plan from the pipeline's config", func() {
+										Expect(fakeFactory.CreateCallCount()).To(Equal(1))
+										_, _, actualResourceTypes, _ := fakeFactory.CreateArgsForCall(0)
+										Expect(actualResourceTypes).To(Equal(atc.ResourceTypes{{Name: "some-resource-type"}}))
+										Expect(fakeDB.UseCandidateConfigForBuildCallCount()).To(BeZero())
+									})
+								})
+
+								Context("when recording that the build is the candidate's fails", func() {
+									BeforeEach(func() {
+										fakeDB.UseCandidateConfigForBuildReturns(disaster)
+									})
+
+									It("creates the build plan from the pipeline's config", func() {
+										Expect(fakeFactory.CreateCallCount()).To(Equal(1))
+										actualJobConfig, _, _, _ := fakeFactory.CreateArgsForCall(0)
+										Expect(actualJobConfig).To(Equal(atc.JobConfig{Name: "some-job"}))
+									})
+								})
+							})
+
+							Context("when the build is outside its percentage", func() {
+								BeforeEach(func() {
+									candidate.Percentage = 99
+									fakeDB.GetCandidateConfigReturns(candidate, true, nil)
+								})
+
+								It("creates the build plan from the pipeline's config", func() {
+									Expect(fakeFactory.CreateCallCount()).To(Equal(1))
+									actualJobConfig, _, _, _ := fakeFactory.CreateArgsForCall(0)
+									Expect(actualJobConfig).To(Equal(atc.JobConfig{Name: "some-job"}))
+									Expect(fakeDB.UseCandidateConfigForBuildCallCount()).To(BeZero())
+								})
+							})
+
+							Context("when getting the candidate fails", func() {
+								BeforeEach(func() {
+									fakeDB.GetCandidateConfigReturns(db.CandidateConfig{}, false, disaster)
+								})
+
+								It("creates the build plan from the pipeline's config anyway", func() {
+									Expect(fakeFactory.CreateCallCount()).To(Equal(1))
+									actualJobConfig, _, _, _ := fakeFactory.CreateArgsForCall(0)
+									Expect(actualJobConfig).To(Equal(atc.JobConfig{Name: "some-job"}))
+								})
+
+								It("doesn't return an error", func() {
+									Expect(tryStartErr).NotTo(HaveOccurred())
+								})
+							})
+						})
+
 						Context("when creating the build plan fails", func() {
 							BeforeEach(func() {
 								fakeFactory.CreateReturns(atc.Plan{}, disaster)
@@ -218,6 +388,101 @@ var _ = Describe("I'm a BuildStarter", func() {
 								})
 							})
 
+							Context("when the plan refers to credentials", func() {
+								BeforeEach(func() {
+									pendingBuild.TeamNameReturns("some-team")
+									pendingBuild.PipelineNameReturns("some-pipeline")
+									fakeFactory.CreateReturns(atc.Plan{Get: &atc.GetPlan{
+										Source: atc.Source{"password": "((some-password))"},
+									}}, nil)
+									fakeEngine.CreateBuildReturns(new(enginefakes.FakeBuild), nil)
+								})
+
+								Context("when they're defined", func() {
+									BeforeEach(func() {
+										fakeVariables.GetReturns("some-secret", true, nil)
+									})
+
+									It("looks them up for the build's pipeline", func() {
+										Expect(fakeCredsManager.VariablesCallCount()).To(Equal(1))
+										teamName, pipelineName := fakeCredsManager.VariablesArgsForCall(0)
+										Expect(teamName).To(Equal("some-team"))
+										Expect(pipelineName).To(Equal("some-pipeline"))
+									})
+
+									It("starts the build with them interpolated, saving them to be redacted", func() {
+										Expect(fakeEngine.CreateBuildCallCount()).To(Equal(1))
+										_, _, actualPlan := fakeEngine.CreateBuildArgsForCall(0)
+										Expect(actualPlan.Get.Source).To(Equal(atc.Source{"password": "some-secret"}))
+
+										Expect(pendingBuild.SaveSecretsCallCount()).To(Equal(1))
+										Expect(pendingBuild.SaveSecretsArgsForCall(0)).To(Equal([]string{"some-secret"}))
+									})
+
+									Context("when saving them fails", func() {
+										BeforeEach(func() {
+											pendingBuild.SaveSecretsReturns(disaster)
+										})
+
+										It("returns the error without starting the build", func() {
+											Expect(tryStartErr).To(Equal(disaster))
+											Expect(fakeEngine.CreateBuildCallCount()).To(BeZero())
+										})
+									})
+								})
+
+								Context("when they're undefined", func() {
+									BeforeEach(func() {
+										fakeVariables.GetReturns(nil, false, nil)
+									})
+
+									It("fails the build, saying which are missing", func() {
+										Expect(tryStartErr).NotTo(HaveOccurred())
+										Expect(fakeEngine.CreateBuildCallCount()).To(BeZero())
+
+										Expect(pendingBuild.MarkAsFailedCallCount()).To(Equal(1))
+										Expect(pendingBuild.MarkAsFailedArgsForCall(0)).To(MatchError("undefined credentials: some-password"))
+									})
+								})
+							})
+
+							Context("when the build was triggered with params", func() {
+								BeforeEach(func() {
+									pendingBuild.ParamsReturns(map[string]string{"environment": "staging"})
+									fakeFactory.CreateReturns(atc.Plan{Task: &atc.TaskPlan{
+										ConfigPath: "some-task.yml",
+										Params:     atc.Params{"environment": "dev", "other": "value"},
+									}}, nil)
+									fakeEngine.CreateBuildReturns(new(enginefakes.FakeBuild), nil)
+								})
+
+								It("gives them to its tasks, over their own params", func() {
+									Expect(fakeEngine.CreateBuildCallCount()).To(Equal(1))
+									_, _, actualPlan := fakeEngine.CreateBuildArgsForCall(0)
+									Expect(actualPlan.Task.Params).To(Equal(atc.Params{
+										"environment": "staging",
+										"other":       "value",
+									}))
+								})
+							})
+
+							Context("when the build wasn't triggered with params but the job has defaults", func() {
+								BeforeEach(func() {
+									jobConfig.TriggerParams = atc.TriggerParamConfigs{
+										{Name: "environment", Default: "staging"},
+									}
+									fakeEngine.CreateBuildReturns(new(enginefakes.FakeBuild), nil)
+								})
+
+								It("gives its tasks the defaults", func() {
+									Expect(fakeEngine.CreateBuildCallCount()).To(Equal(1))
+									_, _, actualPlan := fakeEngine.CreateBuildArgsForCall(0)
+									Expect(actualPlan.Task.Params).To(Equal(atc.Params{
+										"environment": "staging",
+									}))
+								})
+							})
+
 							Context("when creating the engine build succeeds", func() {
 								var engineBuild *enginefakes.FakeBuild
 
@@ -234,6 +499,32 @@ var _ = Describe("I'm a BuildStarter", func() {
 									Eventually(engineBuild.ResumeCallCount).Should(Equal(1))
 								})
 
+								It("leaves the build to the default timeout", func() {
+									Expect(pendingBuild.SetTimeoutCallCount()).To(BeZero())
+								})
+
+								Context("when the job has a build timeout", func() {
+									BeforeEach(func() {
+										jobConfig.BuildTimeout = "1h"
+									})
+
+									It("sets the build's timeout before starting it", func() {
+										Expect(pendingBuild.SetTimeoutCallCount()).To(Equal(1))
+										Expect(pendingBuild.SetTimeoutArgsForCall(0)).To(Equal(time.Hour))
+									})
+
+									Context("when setting the timeout fails", func() {
+										BeforeEach(func() {
+											pendingBuild.SetTimeoutReturns(disaster)
+										})
+
+										It("returns the error without starting the build", func() {
+											Expect(tryStartErr).To(Equal(disaster))
+											Expect(fakeEngine.CreateBuildCallCount()).To(BeZero())
+										})
+									})
+								})
+
 								Context("when there are 7 pending builds", func() {
 									BeforeEach(func() {
 										pendingBuildCount = 7
@@ -242,6 +533,12 @@ var _ = Describe("I'm a BuildStarter", func() {
 									It("starts 7 engine builds (asynchronously)", func() {
 										Eventually(engineBuild.ResumeCallCount).Should(Equal(7))
 									})
+
+									It("records that there was nothing left to start", func() {
+										Expect(fakeDB.SaveSchedulingReasonCallCount()).To(Equal(1))
+										_, reason := fakeDB.SaveSchedulingReasonArgsForCall(0)
+										Expect(reason).To(Equal(db.SchedulingReasonNoPendingBuilds))
+									})
 								})
 							})
 						})
@@ -264,6 +561,15 @@ var _ = Describe("I'm a BuildStarter", func() {
 					})
 				}
 
+				itRecordsWhyTheBuildWasntStarted := func(reason db.SchedulingReason) {
+					It("records why the build wasn't started", func() {
+						Expect(fakeDB.SaveSchedulingReasonCallCount()).To(Equal(1))
+						jobName, actualReason := fakeDB.SaveSchedulingReasonArgsForCall(0)
+						Expect(jobName).To(Equal("some-job"))
+						Expect(actualReason).To(Equal(reason))
+					})
+				}
+
 				itUpdatedMaxInFlightForTheRightJob := func() {
 					It("updated max in flight for the right job", func() {
 						Expect(fakeUpdater.UpdateMaxInFlightReachedCallCount()).To(Equal(1))
@@ -288,6 +594,16 @@ var _ = Describe("I'm a BuildStarter", func() {
 					})
 
 					itDoesntReturnAnErrorOrMarkTheBuildAsScheduled()
+					itRecordsWhyTheBuildWasntStarted(db.SchedulingReasonMaxInFlightReached)
+
+					Context("because of the job's serial groups", func() {
+						BeforeEach(func() {
+							jobConfig.SerialGroups = []string{"some-serial-group"}
+						})
+
+						itDoesntReturnAnErrorOrMarkTheBuildAsScheduled()
+						itRecordsWhyTheBuildWasntStarted(db.SchedulingReasonSerialGroupBusy)
+					})
 				})
 
 				Context("when getting the next build inputs fails", func() {
@@ -305,6 +621,7 @@ var _ = Describe("I'm a BuildStarter", func() {
 					})
 
 					itDoesntReturnAnErrorOrMarkTheBuildAsScheduled()
+					itRecordsWhyTheBuildWasntStarted(db.SchedulingReasonInputsNotSatisfied)
 					itUpdatedMaxInFlightForTheRightJob()
 				})
 
@@ -323,6 +640,7 @@ var _ = Describe("I'm a BuildStarter", func() {
 					})
 
 					itDoesntReturnAnErrorOrMarkTheBuildAsScheduled()
+					itRecordsWhyTheBuildWasntStarted(db.SchedulingReasonPipelinePaused)
 					itUpdatedMaxInFlightForTheRightJob()
 				})
 
@@ -341,7 +659,92 @@ var _ = Describe("I'm a BuildStarter", func() {
 					})
 
 					itDoesntReturnAnErrorOrMarkTheBuildAsScheduled()
+					itRecordsWhyTheBuildWasntStarted(db.SchedulingReasonJobPaused)
+					itUpdatedMaxInFlightForTheRightJob()
+				})
+
+				Context("when checking the global max in flight fails", func() {
+					BeforeEach(func() {
+						fakeLimitsDB.GlobalMaxInFlightReachedReturns(false, disaster)
+					})
+
+					itReturnsTheError()
 					itUpdatedMaxInFlightForTheRightJob()
+
+					It("doesn't try to mark the build as scheduled", func() {
+						Expect(fakeDB.UpdateBuildToScheduledCallCount()).To(BeZero())
+					})
+				})
+
+				Context("when the global max in flight is reached", func() {
+					BeforeEach(func() {
+						fakeLimitsDB.GlobalMaxInFlightReachedReturns(true, nil)
+					})
+
+					itDoesntReturnAnErrorOrMarkTheBuildAsScheduled()
+					itRecordsWhyTheBuildWasntStarted(db.SchedulingReasonGlobalMaxInFlightReached)
+					itUpdatedMaxInFlightForTheRightJob()
+				})
+
+				Context("when checking the fair share fails", func() {
+					BeforeEach(func() {
+						fakeLimitsDB.FairShareReachedReturns(false, disaster)
+					})
+
+					itReturnsTheError()
+
+					It("doesn't try to mark the build as scheduled", func() {
+						Expect(fakeDB.UpdateBuildToScheduledCallCount()).To(BeZero())
+					})
+				})
+
+				Context("when the pipeline has reached its fair share", func() {
+					BeforeEach(func() {
+						pendingBuild.PipelineIDReturns(42)
+						fakeLimitsDB.FairShareReachedReturns(true, nil)
+					})
+
+					itDoesntReturnAnErrorOrMarkTheBuildAsScheduled()
+					itRecordsWhyTheBuildWasntStarted(db.SchedulingReasonFairShareReached)
+
+					It("checks the share of the build's pipeline", func() {
+						Expect(fakeLimitsDB.FairShareReachedCallCount()).To(Equal(1))
+						Expect(fakeLimitsDB.FairShareReachedArgsForCall(0)).To(Equal(42))
+					})
+				})
+
+				Context("when checking the maintenance windows fails", func() {
+					BeforeEach(func() {
+						fakeLimitsDB.MaintenanceHoldsBuildsReturns(false, disaster)
+					})
+
+					itReturnsTheError()
+
+					It("doesn't try to mark the build as scheduled", func() {
+						Expect(fakeDB.UpdateBuildToScheduledCallCount()).To(BeZero())
+					})
+				})
+
+				Context("when a maintenance window is holding builds", func() {
+					BeforeEach(func() {
+						fakeLimitsDB.MaintenanceHoldsBuildsReturns(true, nil)
+					})
+
+					itDoesntReturnAnErrorOrMarkTheBuildAsScheduled()
+					itRecordsWhyTheBuildWasntStarted(db.SchedulingReasonMaintenanceWindow)
+					itUpdatedMaxInFlightForTheRightJob()
+				})
+
+				Context("when getting the registered resource types fails", func() {
+					BeforeEach(func() {
+						fakeDB.GetRegisteredResourceTypesReturns(nil, disaster)
+					})
+
+					itReturnsTheError()
+
+					It("doesn't try to mark the build as scheduled", func() {
+						Expect(fakeDB.UpdateBuildToScheduledCallCount()).To(BeZero())
+					})
 				})
 			})
 		})
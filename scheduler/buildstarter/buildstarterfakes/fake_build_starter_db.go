@@ -4,6 +4,7 @@ package buildstarterfakes
 import (
 	"sync"
 
+	"github.com/concourse/atc"
 	"github.com/concourse/atc/db"
 	"github.com/concourse/atc/scheduler/buildstarter"
 )
@@ -63,6 +64,39 @@ type FakeBuildStarterDB struct {
 	useInputsForBuildReturns struct {
 		result1 error
 	}
+	GetRegisteredResourceTypesStub        func() ([]atc.RegisteredResourceType, error)
+	getRegisteredResourceTypesMutex       sync.RWMutex
+	getRegisteredResourceTypesArgsForCall []struct{}
+	getRegisteredResourceTypesReturns     struct {
+		result1 []atc.RegisteredResourceType
+		result2 error
+	}
+	SaveSchedulingReasonStub        func(jobName string, reason db.SchedulingReason) error
+	saveSchedulingReasonMutex       sync.RWMutex
+	saveSchedulingReasonArgsForCall []struct {
+		jobName string
+		reason  db.SchedulingReason
+	}
+	saveSchedulingReasonReturns struct {
+		result1 error
+	}
+	GetCandidateConfigStub        func() (db.CandidateConfig, bool, error)
+	getCandidateConfigMutex       sync.RWMutex
+	getCandidateConfigArgsForCall []struct{}
+	getCandidateConfigReturns     struct {
+		result1 db.CandidateConfig
+		result2 bool
+		result3 error
+	}
+	UseCandidateConfigForBuildStub        func(buildID int, candidateID int) error
+	useCandidateConfigForBuildMutex       sync.RWMutex
+	useCandidateConfigForBuildArgsForCall []struct {
+		buildID     int
+		candidateID int
+	}
+	useCandidateConfigForBuildReturns struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -270,6 +304,127 @@ func (fake *FakeBuildStarterDB) UseInputsForBuildReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakeBuildStarterDB) GetRegisteredResourceTypes() ([]atc.RegisteredResourceType, error) {
+	fake.getRegisteredResourceTypesMutex.Lock()
+	fake.getRegisteredResourceTypesArgsForCall = append(fake.getRegisteredResourceTypesArgsForCall, struct{}{})
+	fake.recordInvocation("GetRegisteredResourceTypes", []interface{}{})
+	fake.getRegisteredResourceTypesMutex.Unlock()
+	if fake.GetRegisteredResourceTypesStub != nil {
+		return fake.GetRegisteredResourceTypesStub()
+	} else {
+		return fake.getRegisteredResourceTypesReturns.result1, fake.getRegisteredResourceTypesReturns.result2
+	}
+}
+
+func (fake *FakeBuildStarterDB) GetRegisteredResourceTypesCallCount() int {
+	fake.getRegisteredResourceTypesMutex.RLock()
+	defer fake.getRegisteredResourceTypesMutex.RUnlock()
+	return len(fake.getRegisteredResourceTypesArgsForCall)
+}
+
+func (fake *FakeBuildStarterDB) GetRegisteredResourceTypesReturns(result1 []atc.RegisteredResourceType, result2 error) {
+	fake.GetRegisteredResourceTypesStub = nil
+	fake.getRegisteredResourceTypesReturns = struct {
+		result1 []atc.RegisteredResourceType
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuildStarterDB) SaveSchedulingReason(jobName string, reason db.SchedulingReason) error {
+	fake.saveSchedulingReasonMutex.Lock()
+	fake.saveSchedulingReasonArgsForCall = append(fake.saveSchedulingReasonArgsForCall, struct {
+		jobName string
+		reason  db.SchedulingReason
+	}{jobName, reason})
+	fake.recordInvocation("SaveSchedulingReason", []interface{}{jobName, reason})
+	fake.saveSchedulingReasonMutex.Unlock()
+	if fake.SaveSchedulingReasonStub != nil {
+		return fake.SaveSchedulingReasonStub(jobName, reason)
+	} else {
+		return fake.saveSchedulingReasonReturns.result1
+	}
+}
+
+func (fake *FakeBuildStarterDB) SaveSchedulingReasonCallCount() int {
+	fake.saveSchedulingReasonMutex.RLock()
+	defer fake.saveSchedulingReasonMutex.RUnlock()
+	return len(fake.saveSchedulingReasonArgsForCall)
+}
+
+func (fake *FakeBuildStarterDB) SaveSchedulingReasonArgsForCall(i int) (string, db.SchedulingReason) {
+	fake.saveSchedulingReasonMutex.RLock()
+	defer fake.saveSchedulingReasonMutex.RUnlock()
+	return fake.saveSchedulingReasonArgsForCall[i].jobName, fake.saveSchedulingReasonArgsForCall[i].reason
+}
+
+func (fake *FakeBuildStarterDB) SaveSchedulingReasonReturns(result1 error) {
+	fake.SaveSchedulingReasonStub = nil
+	fake.saveSchedulingReasonReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBuildStarterDB) GetCandidateConfig() (db.CandidateConfig, bool, error) {
+	fake.getCandidateConfigMutex.Lock()
+	fake.getCandidateConfigArgsForCall = append(fake.getCandidateConfigArgsForCall, struct{}{})
+	fake.recordInvocation("GetCandidateConfig", []interface{}{})
+	fake.getCandidateConfigMutex.Unlock()
+	if fake.GetCandidateConfigStub != nil {
+		return fake.GetCandidateConfigStub()
+	} else {
+		return fake.getCandidateConfigReturns.result1, fake.getCandidateConfigReturns.result2, fake.getCandidateConfigReturns.result3
+	}
+}
+
+func (fake *FakeBuildStarterDB) GetCandidateConfigCallCount() int {
+	fake.getCandidateConfigMutex.RLock()
+	defer fake.getCandidateConfigMutex.RUnlock()
+	return len(fake.getCandidateConfigArgsForCall)
+}
+
+func (fake *FakeBuildStarterDB) GetCandidateConfigReturns(result1 db.CandidateConfig, result2 bool, result3 error) {
+	fake.GetCandidateConfigStub = nil
+	fake.getCandidateConfigReturns = struct {
+		result1 db.CandidateConfig
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeBuildStarterDB) UseCandidateConfigForBuild(buildID int, candidateID int) error {
+	fake.useCandidateConfigForBuildMutex.Lock()
+	fake.useCandidateConfigForBuildArgsForCall = append(fake.useCandidateConfigForBuildArgsForCall, struct {
+		buildID     int
+		candidateID int
+	}{buildID, candidateID})
+	fake.recordInvocation("UseCandidateConfigForBuild", []interface{}{buildID, candidateID})
+	fake.useCandidateConfigForBuildMutex.Unlock()
+	if fake.UseCandidateConfigForBuildStub != nil {
+		return fake.UseCandidateConfigForBuildStub(buildID, candidateID)
+	} else {
+		return fake.useCandidateConfigForBuildReturns.result1
+	}
+}
+
+func (fake *FakeBuildStarterDB) UseCandidateConfigForBuildCallCount() int {
+	fake.useCandidateConfigForBuildMutex.RLock()
+	defer fake.useCandidateConfigForBuildMutex.RUnlock()
+	return len(fake.useCandidateConfigForBuildArgsForCall)
+}
+
+func (fake *FakeBuildStarterDB) UseCandidateConfigForBuildArgsForCall(i int) (int, int) {
+	fake.useCandidateConfigForBuildMutex.RLock()
+	defer fake.useCandidateConfigForBuildMutex.RUnlock()
+	return fake.useCandidateConfigForBuildArgsForCall[i].buildID, fake.useCandidateConfigForBuildArgsForCall[i].candidateID
+}
+
+func (fake *FakeBuildStarterDB) UseCandidateConfigForBuildReturns(result1 error) {
+	fake.UseCandidateConfigForBuildStub = nil
+	fake.useCandidateConfigForBuildReturns = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeBuildStarterDB) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -285,6 +440,14 @@ func (fake *FakeBuildStarterDB) Invocations() map[string][][]interface{} {
 	defer fake.updateBuildToScheduledMutex.RUnlock()
 	fake.useInputsForBuildMutex.RLock()
 	defer fake.useInputsForBuildMutex.RUnlock()
+	fake.getRegisteredResourceTypesMutex.RLock()
+	defer fake.getRegisteredResourceTypesMutex.RUnlock()
+	fake.saveSchedulingReasonMutex.RLock()
+	defer fake.saveSchedulingReasonMutex.RUnlock()
+	fake.getCandidateConfigMutex.RLock()
+	defer fake.getCandidateConfigMutex.RUnlock()
+	fake.useCandidateConfigForBuildMutex.RLock()
+	defer fake.useCandidateConfigForBuildMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -0,0 +1,148 @@
+// This file was generated by counterfeiter
+package buildstarterfakes
+
+import (
+	"sync"
+
+	"github.com/concourse/atc/scheduler/buildstarter"
+)
+
+type FakeBuildStarterLimitsDB struct {
+	GlobalMaxInFlightReachedStub        func() (bool, error)
+	globalMaxInFlightReachedMutex       sync.RWMutex
+	globalMaxInFlightReachedArgsForCall []struct{}
+	globalMaxInFlightReachedReturns     struct {
+		result1 bool
+		result2 error
+	}
+	MaintenanceHoldsBuildsStub        func() (bool, error)
+	maintenanceHoldsBuildsMutex       sync.RWMutex
+	maintenanceHoldsBuildsArgsForCall []struct{}
+	maintenanceHoldsBuildsReturns     struct {
+		result1 bool
+		result2 error
+	}
+	FairShareReachedStub        func(pipelineID int) (bool, error)
+	fairShareReachedMutex       sync.RWMutex
+	fairShareReachedArgsForCall []struct {
+		pipelineID int
+	}
+	fairShareReachedReturns struct {
+		result1 bool
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeBuildStarterLimitsDB) GlobalMaxInFlightReached() (bool, error) {
+	fake.globalMaxInFlightReachedMutex.Lock()
+	fake.globalMaxInFlightReachedArgsForCall = append(fake.globalMaxInFlightReachedArgsForCall, struct{}{})
+	fake.recordInvocation("GlobalMaxInFlightReached", []interface{}{})
+	fake.globalMaxInFlightReachedMutex.Unlock()
+	if fake.GlobalMaxInFlightReachedStub != nil {
+		return fake.GlobalMaxInFlightReachedStub()
+	} else {
+		return fake.globalMaxInFlightReachedReturns.result1, fake.globalMaxInFlightReachedReturns.result2
+	}
+}
+
+func (fake *FakeBuildStarterLimitsDB) GlobalMaxInFlightReachedCallCount() int {
+	fake.globalMaxInFlightReachedMutex.RLock()
+	defer fake.globalMaxInFlightReachedMutex.RUnlock()
+	return len(fake.globalMaxInFlightReachedArgsForCall)
+}
+
+func (fake *FakeBuildStarterLimitsDB) GlobalMaxInFlightReachedReturns(result1 bool, result2 error) {
+	fake.GlobalMaxInFlightReachedStub = nil
+	fake.globalMaxInFlightReachedReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuildStarterLimitsDB) MaintenanceHoldsBuilds() (bool, error) {
+	fake.maintenanceHoldsBuildsMutex.Lock()
+	fake.maintenanceHoldsBuildsArgsForCall = append(fake.maintenanceHoldsBuildsArgsForCall, struct{}{})
+	fake.recordInvocation("MaintenanceHoldsBuilds", []interface{}{})
+	fake.maintenanceHoldsBuildsMutex.Unlock()
+	if fake.MaintenanceHoldsBuildsStub != nil {
+		return fake.MaintenanceHoldsBuildsStub()
+	} else {
+		return fake.maintenanceHoldsBuildsReturns.result1, fake.maintenanceHoldsBuildsReturns.result2
+	}
+}
+
+func (fake *FakeBuildStarterLimitsDB) MaintenanceHoldsBuildsCallCount() int {
+	fake.maintenanceHoldsBuildsMutex.RLock()
+	defer fake.maintenanceHoldsBuildsMutex.RUnlock()
+	return len(fake.maintenanceHoldsBuildsArgsForCall)
+}
+
+func (fake *FakeBuildStarterLimitsDB) MaintenanceHoldsBuildsReturns(result1 bool, result2 error) {
+	fake.MaintenanceHoldsBuildsStub = nil
+	fake.maintenanceHoldsBuildsReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuildStarterLimitsDB) FairShareReached(pipelineID int) (bool, error) {
+	fake.fairShareReachedMutex.Lock()
+	fake.fairShareReachedArgsForCall = append(fake.fairShareReachedArgsForCall, struct {
+		pipelineID int
+	}{pipelineID})
+	fake.recordInvocation("FairShareReached", []interface{}{pipelineID})
+	fake.fairShareReachedMutex.Unlock()
+	if fake.FairShareReachedStub != nil {
+		return fake.FairShareReachedStub(pipelineID)
+	} else {
+		return fake.fairShareReachedReturns.result1, fake.fairShareReachedReturns.result2
+	}
+}
+
+func (fake *FakeBuildStarterLimitsDB) FairShareReachedCallCount() int {
+	fake.fairShareReachedMutex.RLock()
+	defer fake.fairShareReachedMutex.RUnlock()
+	return len(fake.fairShareReachedArgsForCall)
+}
+
+func (fake *FakeBuildStarterLimitsDB) FairShareReachedArgsForCall(i int) int {
+	fake.fairShareReachedMutex.RLock()
+	defer fake.fairShareReachedMutex.RUnlock()
+	return fake.fairShareReachedArgsForCall[i].pipelineID
+}
+
+func (fake *FakeBuildStarterLimitsDB) FairShareReachedReturns(result1 bool, result2 error) {
+	fake.FairShareReachedStub = nil
+	fake.fairShareReachedReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuildStarterLimitsDB) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.globalMaxInFlightReachedMutex.RLock()
+	defer fake.globalMaxInFlightReachedMutex.RUnlock()
+	fake.maintenanceHoldsBuildsMutex.RLock()
+	defer fake.maintenanceHoldsBuildsMutex.RUnlock()
+	fake.fairShareReachedMutex.RLock()
+	defer fake.fairShareReachedMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeBuildStarterLimitsDB) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ buildstarter.BuildStarterLimitsDB = new(FakeBuildStarterLimitsDB)
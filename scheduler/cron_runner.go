@@ -0,0 +1,160 @@
+package scheduler
+
+import (
+	"os"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/cron"
+	"github.com/concourse/atc/db"
+)
+
+// CronRunner triggers builds of the jobs in a pipeline that have a schedule,
+// as if they had been triggered manually, whenever their schedule comes due.
+type CronRunner struct {
+	Logger lager.Logger
+
+	DB CronDB
+
+	Scheduler BuildScheduler
+
+	Clock clock.Clock
+
+	Noop bool
+
+	Interval time.Duration
+}
+
+//go:generate counterfeiter . CronDB
+
+type CronDB interface {
+	GetConfig() (atc.Config, db.ConfigVersion, bool, error)
+	IsPaused() (bool, error)
+	GetJob(job string) (db.SavedJob, error)
+	GetJobLastScheduledAt(job string) (time.Time, error)
+	SaveJobLastScheduledAt(job string, previous time.Time, at time.Time) (bool, error)
+}
+
+func (runner *CronRunner) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	close(ready)
+
+	if runner.Interval == 0 {
+		panic("unconfigured cron interval")
+	}
+
+	runner.Logger.Info("start", lager.Data{
+		"interval": runner.Interval.String(),
+	})
+
+	defer runner.Logger.Info("done")
+
+	ticker := runner.Clock.NewTicker(runner.Interval)
+	defer ticker.Stop()
+
+	for {
+		err := runner.tick(runner.Logger.Session("tick"))
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-ticker.C():
+		case <-signals:
+			return nil
+		}
+	}
+}
+
+func (runner *CronRunner) tick(logger lager.Logger) error {
+	config, _, found, err := runner.DB.GetConfig()
+	if err != nil {
+		logger.Error("failed-to-get-config", err)
+		return nil
+	}
+
+	if !found {
+		return errPipelineRemoved
+	}
+
+	if runner.Noop {
+		return nil
+	}
+
+	paused, err := runner.DB.IsPaused()
+	if err != nil {
+		logger.Error("failed-to-check-if-pipeline-is-paused", err)
+		return nil
+	}
+
+	// slots that come due while paused are left unclaimed, so that the job
+	// runs once when it is unpaused rather than not at all
+	if paused {
+		return nil
+	}
+
+	now := runner.Clock.Now()
+
+	for _, job := range config.Jobs {
+		if job.Schedule == "" {
+			continue
+		}
+
+		runner.tickJob(logger.Session("job", lager.Data{"job": job.Name}), job, config, now)
+	}
+
+	return nil
+}
+
+func (runner *CronRunner) tickJob(logger lager.Logger, job atc.JobConfig, config atc.Config, now time.Time) {
+	schedule, err := cron.Parse(job.Schedule)
+	if err != nil {
+		logger.Error("invalid-schedule", err)
+		return
+	}
+
+	savedJob, err := runner.DB.GetJob(job.Name)
+	if err != nil {
+		logger.Error("failed-to-get-job", err)
+		return
+	}
+
+	if savedJob.Paused || savedJob.ManualOnly {
+		return
+	}
+
+	lastScheduledAt, err := runner.DB.GetJobLastScheduledAt(job.Name)
+	if err != nil {
+		logger.Error("failed-to-get-last-scheduled-at", err)
+		return
+	}
+
+	// a job that's only just been given a schedule starts counting from now,
+	// rather than firing for every slot since the beginning of time
+	if !lastScheduledAt.IsZero() {
+		next := schedule.Next(lastScheduledAt)
+		if next.IsZero() || next.After(now) {
+			return
+		}
+	}
+
+	// the claim is made against the time we read, so that only one ATC
+	// triggers the job for any one slot
+	claimed, err := runner.DB.SaveJobLastScheduledAt(job.Name, lastScheduledAt, now)
+	if err != nil {
+		logger.Error("failed-to-save-last-scheduled-at", err)
+		return
+	}
+
+	if !claimed || lastScheduledAt.IsZero() {
+		return
+	}
+
+	logger.Info("triggering", lager.Data{"schedule": job.Schedule})
+
+	_, _, err = runner.Scheduler.TriggerImmediately(logger, job, config.Resources, config.ResourceTypes, nil)
+	if err != nil {
+		logger.Error("failed-to-trigger", err)
+	}
+}
@@ -0,0 +1,220 @@
+package scheduler_test
+
+import (
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager/lagertest"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+	. "github.com/concourse/atc/scheduler"
+	"github.com/concourse/atc/scheduler/schedulerfakes"
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/ginkgomon"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CronRunner", func() {
+	var (
+		cronDB    *schedulerfakes.FakeCronDB
+		scheduler *schedulerfakes.FakeBuildScheduler
+		fakeClock *fakeclock.FakeClock
+		noop      bool
+
+		now           time.Time
+		initialConfig atc.Config
+
+		process ifrit.Process
+	)
+
+	BeforeEach(func() {
+		cronDB = new(schedulerfakes.FakeCronDB)
+		scheduler = new(schedulerfakes.FakeBuildScheduler)
+		noop = false
+
+		now = time.Date(2016, 10, 1, 12, 30, 45, 0, time.UTC)
+		fakeClock = fakeclock.NewFakeClock(now)
+
+		initialConfig = atc.Config{
+			Jobs: atc.JobConfigs{
+				{
+					Name:     "some-job",
+					Schedule: "@hourly",
+				},
+				{
+					Name: "some-unscheduled-job",
+				},
+			},
+
+			Resources: atc.ResourceConfigs{
+				{
+					Name:   "some-resource",
+					Type:   "git",
+					Source: atc.Source{"uri": "git://some-resource"},
+				},
+			},
+		}
+
+		cronDB.GetConfigReturns(initialConfig, 1, true, nil)
+		cronDB.SaveJobLastScheduledAtReturns(true, nil)
+	})
+
+	JustBeforeEach(func() {
+		process = ginkgomon.Invoke(&CronRunner{
+			Logger:    lagertest.NewTestLogger("test"),
+			DB:        cronDB,
+			Scheduler: scheduler,
+			Clock:     fakeClock,
+			Noop:      noop,
+			Interval:  10 * time.Second,
+		})
+	})
+
+	AfterEach(func() {
+		ginkgomon.Interrupt(process)
+	})
+
+	Context("when the job has never been scheduled", func() {
+		BeforeEach(func() {
+			cronDB.GetJobLastScheduledAtReturns(time.Time{}, nil)
+		})
+
+		It("starts counting from now without triggering a build", func() {
+			Eventually(cronDB.SaveJobLastScheduledAtCallCount).Should(Equal(1))
+
+			job, previous, at := cronDB.SaveJobLastScheduledAtArgsForCall(0)
+			Expect(job).To(Equal("some-job"))
+			Expect(previous).To(BeZero())
+			Expect(at).To(Equal(now))
+
+			Consistently(scheduler.TriggerImmediatelyCallCount).Should(BeZero())
+		})
+	})
+
+	Context("when the job's schedule has come due", func() {
+		lastScheduledAt := time.Date(2016, 10, 1, 11, 0, 0, 0, time.UTC)
+
+		BeforeEach(func() {
+			cronDB.GetJobLastScheduledAtReturns(lastScheduledAt, nil)
+		})
+
+		It("claims the slot", func() {
+			Eventually(cronDB.SaveJobLastScheduledAtCallCount).Should(Equal(1))
+
+			job, previous, at := cronDB.SaveJobLastScheduledAtArgsForCall(0)
+			Expect(job).To(Equal("some-job"))
+			Expect(previous).To(Equal(lastScheduledAt))
+			Expect(at).To(Equal(now))
+		})
+
+		It("triggers a build of only the scheduled job", func() {
+			Eventually(scheduler.TriggerImmediatelyCallCount).Should(Equal(1))
+
+			_, job, resources, resourceTypes, _ := scheduler.TriggerImmediatelyArgsForCall(0)
+			Expect(job).To(Equal(initialConfig.Jobs[0]))
+			Expect(resources).To(Equal(initialConfig.Resources))
+			Expect(resourceTypes).To(Equal(initialConfig.ResourceTypes))
+
+			Expect(cronDB.GetJobArgsForCall(0)).To(Equal("some-job"))
+			Expect(cronDB.GetJobCallCount()).To(Equal(1))
+		})
+
+		Context("when another ATC has already claimed the slot", func() {
+			BeforeEach(func() {
+				cronDB.SaveJobLastScheduledAtReturns(false, nil)
+			})
+
+			It("does not trigger a build", func() {
+				Eventually(cronDB.SaveJobLastScheduledAtCallCount).Should(Equal(1))
+				Consistently(scheduler.TriggerImmediatelyCallCount).Should(BeZero())
+			})
+		})
+
+		Context("when claiming the slot fails", func() {
+			BeforeEach(func() {
+				cronDB.SaveJobLastScheduledAtReturns(false, errors.New("nope"))
+			})
+
+			It("does not trigger a build", func() {
+				Eventually(cronDB.SaveJobLastScheduledAtCallCount).Should(Equal(1))
+				Consistently(scheduler.TriggerImmediatelyCallCount).Should(BeZero())
+			})
+		})
+
+		Context("when the job is paused", func() {
+			BeforeEach(func() {
+				cronDB.GetJobReturns(db.SavedJob{Paused: true}, nil)
+			})
+
+			It("leaves the slot unclaimed", func() {
+				Eventually(cronDB.GetJobCallCount).Should(Equal(1))
+				Consistently(cronDB.SaveJobLastScheduledAtCallCount).Should(BeZero())
+			})
+		})
+
+		Context("when the job is manual only", func() {
+			BeforeEach(func() {
+				cronDB.GetJobReturns(db.SavedJob{ManualOnly: true}, nil)
+			})
+
+			It("leaves the slot unclaimed", func() {
+				Eventually(cronDB.GetJobCallCount).Should(Equal(1))
+				Consistently(cronDB.SaveJobLastScheduledAtCallCount).Should(BeZero())
+			})
+		})
+
+		Context("when the pipeline is paused", func() {
+			BeforeEach(func() {
+				cronDB.IsPausedReturns(true, nil)
+			})
+
+			It("leaves the slot unclaimed", func() {
+				Eventually(cronDB.IsPausedCallCount).Should(Equal(1))
+				Consistently(cronDB.SaveJobLastScheduledAtCallCount).Should(BeZero())
+			})
+		})
+
+		Context("when in noop mode", func() {
+			BeforeEach(func() {
+				noop = true
+			})
+
+			It("does not trigger a build", func() {
+				Consistently(cronDB.SaveJobLastScheduledAtCallCount).Should(BeZero())
+				Expect(scheduler.TriggerImmediatelyCallCount()).To(BeZero())
+			})
+		})
+	})
+
+	Context("when the job's schedule has not come due", func() {
+		BeforeEach(func() {
+			cronDB.GetJobLastScheduledAtReturns(time.Date(2016, 10, 1, 12, 0, 0, 0, time.UTC), nil)
+		})
+
+		It("does nothing", func() {
+			Eventually(cronDB.GetJobLastScheduledAtCallCount).Should(Equal(1))
+			Consistently(cronDB.SaveJobLastScheduledAtCallCount).Should(BeZero())
+		})
+
+		It("checks again on every interval", func() {
+			Eventually(cronDB.GetJobLastScheduledAtCallCount).Should(Equal(1))
+
+			fakeClock.WaitForWatcherAndIncrement(10 * time.Second)
+
+			Eventually(cronDB.GetJobLastScheduledAtCallCount).Should(Equal(2))
+		})
+	})
+
+	Context("when the pipeline is destroyed", func() {
+		BeforeEach(func() {
+			cronDB.GetConfigReturns(atc.Config{}, 0, false, nil)
+		})
+
+		It("exits", func() {
+			Eventually(process.Wait()).Should(Receive())
+		})
+	})
+})
@@ -236,6 +236,12 @@ func (factory *buildFactory) constructUnhookedPlan(
 			OutputMapping:     planConfig.OutputMapping,
 			ImageArtifactName: planConfig.ImageArtifactName,
 		})
+
+	case planConfig.Approval != "":
+		plan = factory.planFactory.NewPlan(atc.ApprovalPlan{
+			Name: planConfig.Approval,
+		})
+
 	case planConfig.Try != nil:
 		nextStep, err := factory.constructPlanFromConfig(
 			*planConfig.Try,
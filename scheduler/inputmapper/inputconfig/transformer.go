@@ -35,8 +35,10 @@ func (i *transformer) TransformInputConfigs(db *algorithm.VersionsDB, jobName st
 			input.Version = &atc.VersionConfig{Latest: true}
 		}
 
-		pinnedVersionID := 0
-		if input.Version.Pinned != nil {
+		// a version pinned through the API overrides the job's config, so
+		// that a broken version can be avoided without reconfiguring
+		pinnedVersionID, pinnedByAPI := db.PinnedVersionIDs[db.ResourceIDs[input.Resource]]
+		if !pinnedByAPI && input.Version.Pinned != nil {
 			savedVersion, found, err := i.db.GetVersionedResourceByVersion(input.Version.Pinned, input.Resource)
 			if err != nil {
 				return nil, err
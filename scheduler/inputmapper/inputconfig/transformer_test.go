@@ -28,16 +28,22 @@ var _ = Describe("Transformer", func() {
 	Describe("TransformInputConfigs", func() {
 		Context("when the job name exists in the versionsDB", func() {
 			var (
-				jobInputs       []config.JobInput
-				algorithmInputs algorithm.InputConfigs
-				tranformErr     error
+				jobInputs        []config.JobInput
+				pinnedVersionIDs map[int]int
+				algorithmInputs  algorithm.InputConfigs
+				tranformErr      error
 			)
 
+			BeforeEach(func() {
+				pinnedVersionIDs = map[int]int{}
+			})
+
 			JustBeforeEach(func() {
 				algorithmInputs, tranformErr = transformer.TransformInputConfigs(
 					&algorithm.VersionsDB{
-						JobIDs:      map[string]int{"j1": 1, "j2": 2},
-						ResourceIDs: map[string]int{"r1": 11, "r2": 12},
+						JobIDs:           map[string]int{"j1": 1, "j2": 2},
+						ResourceIDs:      map[string]int{"r1": 11, "r2": 12},
+						PinnedVersionIDs: pinnedVersionIDs,
 					},
 					"j1",
 					jobInputs,
@@ -87,6 +93,29 @@ var _ = Describe("Transformer", func() {
 				})
 			})
 
+			Context("when an input's resource has a version pinned through the API", func() {
+				BeforeEach(func() {
+					jobInputs = []config.JobInput{{
+						Name:     "job-input-1",
+						Resource: "r1",
+						Version:  &atc.VersionConfig{Every: true},
+					}}
+
+					pinnedVersionIDs[11] = 42
+				})
+
+				It("pins it to that version", func() {
+					Expect(algorithmInputs).To(ConsistOf(algorithm.InputConfig{
+						Name:            "job-input-1",
+						UseEveryVersion: true,
+						PinnedVersionID: 42,
+						ResourceID:      11,
+						Passed:          algorithm.JobSet{},
+						JobID:           1,
+					}))
+				})
+			})
+
 			Context("when an input has version: every", func() {
 				BeforeEach(func() {
 					jobInputs = []config.JobInput{{
@@ -124,6 +153,24 @@ var _ = Describe("Transformer", func() {
 					}
 				})
 
+				Context("when the resource has a version pinned through the API", func() {
+					BeforeEach(func() {
+						pinnedVersionIDs[11] = 42
+					})
+
+					It("uses it instead of looking up the configured version", func() {
+						Expect(fakeDB.GetVersionedResourceByVersionCallCount()).To(BeZero())
+						Expect(algorithmInputs).To(ContainElement(algorithm.InputConfig{
+							Name:            "job-input-1",
+							UseEveryVersion: false,
+							PinnedVersionID: 42,
+							ResourceID:      11,
+							Passed:          algorithm.JobSet{},
+							JobID:           1,
+						}))
+					})
+				})
+
 				Context("when looking up the pinned version fails", func() {
 					var disaster error
 
@@ -1,9 +1,12 @@
 package inputmapper
 
 import (
+	"encoding/json"
+
 	"code.cloudfoundry.org/lager"
 	"github.com/concourse/atc"
 	"github.com/concourse/atc/config"
+	"github.com/concourse/atc/db"
 	"github.com/concourse/atc/db/algorithm"
 	"github.com/concourse/atc/scheduler/inputmapper/inputconfig"
 )
@@ -24,6 +27,7 @@ type InputMapperDB interface {
 	SaveIndependentInputMapping(inputVersions algorithm.InputMapping, jobName string) error
 	SaveNextInputMapping(inputVersions algorithm.InputMapping, jobName string) error
 	DeleteNextInputMapping(jobName string) error
+	SaveMissingInputReasons(jobName string, reasons db.MissingInputReasons) error
 }
 
 func NewInputMapper(db InputMapperDB, transformer inputconfig.Transformer) InputMapper {
@@ -65,6 +69,15 @@ func (i *inputMapper) SaveNextInputMapping(
 	}
 
 	if len(independentMapping) < len(inputConfigs) {
+		missingInputReasons := db.MissingInputReasons{}
+		for _, inputConfig := range inputConfigs {
+			if _, found := independentMapping[inputConfig.Name]; !found {
+				registerMissingInput(missingInputReasons, inputConfig)
+			}
+		}
+
+		i.saveMissingInputReasons(logger, job.Name, missingInputReasons)
+
 		// this is necessary to prevent builds from running with missing pinned versions
 		err := i.db.DeleteNextInputMapping(job.Name)
 		if err != nil {
@@ -76,6 +89,17 @@ func (i *inputMapper) SaveNextInputMapping(
 
 	resolvedMapping, ok := algorithmInputConfigs.Resolve(versions)
 	if !ok {
+		// every input has versions on its own, so it's their passed
+		// constraints that can't all be satisfied together
+		missingInputReasons := db.MissingInputReasons{}
+		for _, inputConfig := range inputConfigs {
+			if len(inputConfig.Passed) > 0 {
+				missingInputReasons.RegisterPassedConstraint(inputConfig.Name)
+			}
+		}
+
+		i.saveMissingInputReasons(logger, job.Name, missingInputReasons)
+
 		err := i.db.DeleteNextInputMapping(job.Name)
 		if err != nil {
 			logger.Error("failed-to-delete-next-input-mapping-after-failed-resolve", err)
@@ -84,6 +108,8 @@ func (i *inputMapper) SaveNextInputMapping(
 		return nil, err
 	}
 
+	i.saveMissingInputReasons(logger, job.Name, db.MissingInputReasons{})
+
 	err = i.db.SaveNextInputMapping(resolvedMapping, job.Name)
 	if err != nil {
 		logger.Error("failed-to-save-next-input-mapping", err)
@@ -92,3 +118,26 @@ func (i *inputMapper) SaveNextInputMapping(
 
 	return resolvedMapping, nil
 }
+
+// saveMissingInputReasons records why inputs couldn't be mapped to versions,
+// to explain why the job isn't running. It's only logged if that fails, as
+// it doesn't affect what gets scheduled.
+func (i *inputMapper) saveMissingInputReasons(logger lager.Logger, jobName string, reasons db.MissingInputReasons) {
+	err := i.db.SaveMissingInputReasons(jobName, reasons)
+	if err != nil {
+		logger.Error("failed-to-save-missing-input-reasons", err)
+	}
+}
+
+func registerMissingInput(reasons db.MissingInputReasons, inputConfig config.JobInput) {
+	switch {
+	case len(inputConfig.Passed) > 0:
+		reasons.RegisterPassedConstraint(inputConfig.Name)
+	case inputConfig.Version != nil && inputConfig.Version.Pinned != nil:
+		// a version is only strings, so this can't fail
+		versionJSON, _ := json.Marshal(inputConfig.Version.Pinned)
+		reasons.RegisterPinnedVersionUnavailable(inputConfig.Name, string(versionJSON))
+	default:
+		reasons.RegisterNoVersions(inputConfig.Name)
+	}
+}
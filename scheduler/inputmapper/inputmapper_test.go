@@ -6,6 +6,7 @@ import (
 	"code.cloudfoundry.org/lager/lagertest"
 	"github.com/concourse/atc"
 	"github.com/concourse/atc/config"
+	"github.com/concourse/atc/db"
 	"github.com/concourse/atc/db/algorithm"
 	"github.com/concourse/atc/scheduler/inputmapper"
 	"github.com/concourse/atc/scheduler/inputmapper/inputconfig/inputconfigfakes"
@@ -192,6 +193,13 @@ var _ = Describe("Inputmapper", func() {
 						It("didn't delete the mapping", func() {
 							Expect(fakeDB.DeleteNextInputMappingCallCount()).To(BeZero())
 						})
+
+						It("records that no inputs are missing", func() {
+							Expect(fakeDB.SaveMissingInputReasonsCallCount()).To(Equal(1))
+							actualJobName, reasons := fakeDB.SaveMissingInputReasonsArgsForCall(0)
+							Expect(actualJobName).To(Equal("some-job"))
+							Expect(reasons).To(BeEmpty())
+						})
 					})
 				})
 			})
@@ -258,6 +266,14 @@ var _ = Describe("Inputmapper", func() {
 					Expect(mappingErr).NotTo(HaveOccurred())
 					Expect(inputMapping).To(BeEmpty())
 				})
+
+				It("records that the passed constraints can't be satisfied", func() {
+					_, reasons := fakeDB.SaveMissingInputReasonsArgsForCall(0)
+					Expect(reasons).To(Equal(db.MissingInputReasons{
+						"a": db.NoVerionsSatisfiedPassedConstraints,
+						"b": db.NoVerionsSatisfiedPassedConstraints,
+					}))
+				})
 			})
 		})
 
@@ -297,6 +313,25 @@ var _ = Describe("Inputmapper", func() {
 				Expect(actualJobName).To(Equal("some-job"))
 			})
 
+			It("records which inputs have no versions", func() {
+				actualJobName, reasons := fakeDB.SaveMissingInputReasonsArgsForCall(0)
+				Expect(actualJobName).To(Equal("some-job"))
+				Expect(reasons).To(Equal(db.MissingInputReasons{
+					"no-versions": db.NoVersionsAvailable,
+				}))
+			})
+
+			Context("when recording them fails", func() {
+				BeforeEach(func() {
+					fakeDB.SaveMissingInputReasonsReturns(disaster)
+				})
+
+				It("still deletes the next input mapping, without returning an error", func() {
+					Expect(fakeDB.DeleteNextInputMappingCallCount()).To(Equal(1))
+					Expect(mappingErr).NotTo(HaveOccurred())
+				})
+			})
+
 			It("deleted the next input mapping", func() {
 				Expect(fakeDB.DeleteNextInputMappingCallCount()).To(Equal(1))
 				Expect(fakeDB.DeleteNextInputMappingArgsForCall(0)).To(Equal("some-job"))
@@ -339,6 +374,13 @@ var _ = Describe("Inputmapper", func() {
 				Expect(actualJobName).To(Equal("some-job"))
 			})
 
+			It("records that the pinned version is unavailable", func() {
+				_, reasons := fakeDB.SaveMissingInputReasonsArgsForCall(0)
+				Expect(reasons).To(Equal(db.MissingInputReasons{
+					"a": `pinned version {"doesn't":"exist"} is not available`,
+				}))
+			})
+
 			It("deleted the next input mapping", func() {
 				Expect(fakeDB.DeleteNextInputMappingCallCount()).To(Equal(1))
 				Expect(fakeDB.DeleteNextInputMappingArgsForCall(0)).To(Equal("some-job"))
@@ -4,6 +4,7 @@ package inputmapperfakes
 import (
 	"sync"
 
+	"github.com/concourse/atc/db"
 	"github.com/concourse/atc/db/algorithm"
 	"github.com/concourse/atc/scheduler/inputmapper"
 )
@@ -35,6 +36,15 @@ type FakeInputMapperDB struct {
 	deleteNextInputMappingReturns struct {
 		result1 error
 	}
+	SaveMissingInputReasonsStub        func(jobName string, reasons db.MissingInputReasons) error
+	saveMissingInputReasonsMutex       sync.RWMutex
+	saveMissingInputReasonsArgsForCall []struct {
+		jobName string
+		reasons db.MissingInputReasons
+	}
+	saveMissingInputReasonsReturns struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -140,6 +150,40 @@ func (fake *FakeInputMapperDB) DeleteNextInputMappingReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakeInputMapperDB) SaveMissingInputReasons(jobName string, reasons db.MissingInputReasons) error {
+	fake.saveMissingInputReasonsMutex.Lock()
+	fake.saveMissingInputReasonsArgsForCall = append(fake.saveMissingInputReasonsArgsForCall, struct {
+		jobName string
+		reasons db.MissingInputReasons
+	}{jobName, reasons})
+	fake.recordInvocation("SaveMissingInputReasons", []interface{}{jobName, reasons})
+	fake.saveMissingInputReasonsMutex.Unlock()
+	if fake.SaveMissingInputReasonsStub != nil {
+		return fake.SaveMissingInputReasonsStub(jobName, reasons)
+	} else {
+		return fake.saveMissingInputReasonsReturns.result1
+	}
+}
+
+func (fake *FakeInputMapperDB) SaveMissingInputReasonsCallCount() int {
+	fake.saveMissingInputReasonsMutex.RLock()
+	defer fake.saveMissingInputReasonsMutex.RUnlock()
+	return len(fake.saveMissingInputReasonsArgsForCall)
+}
+
+func (fake *FakeInputMapperDB) SaveMissingInputReasonsArgsForCall(i int) (string, db.MissingInputReasons) {
+	fake.saveMissingInputReasonsMutex.RLock()
+	defer fake.saveMissingInputReasonsMutex.RUnlock()
+	return fake.saveMissingInputReasonsArgsForCall[i].jobName, fake.saveMissingInputReasonsArgsForCall[i].reasons
+}
+
+func (fake *FakeInputMapperDB) SaveMissingInputReasonsReturns(result1 error) {
+	fake.SaveMissingInputReasonsStub = nil
+	fake.saveMissingInputReasonsReturns = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeInputMapperDB) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -149,6 +193,8 @@ func (fake *FakeInputMapperDB) Invocations() map[string][][]interface{} {
 	defer fake.saveNextInputMappingMutex.RUnlock()
 	fake.deleteNextInputMappingMutex.RLock()
 	defer fake.deleteNextInputMappingMutex.RUnlock()
+	fake.saveMissingInputReasonsMutex.RLock()
+	defer fake.saveMissingInputReasonsMutex.RUnlock()
 	return fake.invocations
 }
 
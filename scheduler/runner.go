@@ -1,15 +1,19 @@
 package scheduler
 
 import (
+	"context"
 	"errors"
 	"os"
+	"sort"
 	"time"
 
+	"code.cloudfoundry.org/clock"
 	"code.cloudfoundry.org/lager"
 	"github.com/concourse/atc"
 	"github.com/concourse/atc/db"
 	"github.com/concourse/atc/db/algorithm"
 	"github.com/concourse/atc/metric"
+	"github.com/concourse/atc/tracing"
 )
 
 //go:generate counterfeiter . BuildScheduler
@@ -27,6 +31,7 @@ type BuildScheduler interface {
 		jobConfig atc.JobConfig,
 		resourceConfigs atc.ResourceConfigs,
 		resourceTypes atc.ResourceTypes,
+		params map[string]string,
 	) (db.Build, Waiter, error)
 	SaveNextInputMapping(logger lager.Logger, job atc.JobConfig) error
 }
@@ -42,6 +47,10 @@ type Runner struct {
 
 	Noop bool
 
+	Clock clock.Clock
+
+	// Interval is how often to schedule when nothing has happened to make it
+	// worth scheduling sooner, in case something was missed.
 	Interval time.Duration
 }
 
@@ -58,15 +67,32 @@ func (runner *Runner) Run(signals <-chan os.Signal, ready chan<- struct{}) error
 
 	defer runner.Logger.Info("done")
 
+	var scheduling <-chan struct{}
+
+	notifier, err := runner.DB.SchedulingNotifier()
+	if err != nil {
+		runner.Logger.Error("failed-to-listen-for-scheduling", err)
+	} else {
+		defer notifier.Close()
+		scheduling = notifier.Notify()
+	}
+
+	// sweeps are only worth doing once an interval between all of the ATCs,
+	// but anything that happens should be scheduled for right away
+	since := runner.Interval
+
 dance:
 	for {
-		err := runner.tick(runner.Logger.Session("tick"))
+		err := runner.tick(runner.Logger.Session("tick"), since)
 		if err != nil {
 			return err
 		}
 
 		select {
-		case <-time.After(runner.Interval):
+		case <-scheduling:
+			since = 0
+		case <-runner.Clock.After(runner.Interval):
+			since = runner.Interval
 		case <-signals:
 			break dance
 		}
@@ -75,7 +101,7 @@ dance:
 	return nil
 }
 
-func (runner *Runner) tick(logger lager.Logger) error {
+func (runner *Runner) tick(logger lager.Logger, since time.Duration) error {
 	config, _, found, err := runner.DB.GetConfig()
 	if err != nil {
 		logger.Error("failed-to-get-config", err)
@@ -90,7 +116,7 @@ func (runner *Runner) tick(logger lager.Logger) error {
 		return nil
 	}
 
-	schedulingLease, acquired, err := runner.DB.AcquireSchedulingLock(logger, runner.Interval)
+	schedulingLease, acquired, err := runner.DB.AcquireSchedulingLock(logger, since)
 	if err != nil {
 		logger.Error("failed-to-acquire-scheduling-lock", err)
 		return nil
@@ -102,6 +128,12 @@ func (runner *Runner) tick(logger lager.Logger) error {
 
 	defer schedulingLease.Release()
 
+	ctx, span := tracing.StartSpan(context.Background(), "schedule", tracing.Attrs{
+		"pipeline.name": runner.DB.GetPipelineName(),
+	})
+
+	defer span.Finish(nil)
+
 	start := time.Now()
 
 	defer func() {
@@ -114,6 +146,7 @@ func (runner *Runner) tick(logger lager.Logger) error {
 	versions, err := runner.DB.LoadVersionsDB()
 	if err != nil {
 		logger.Error("failed-to-load-versions-db", err)
+		span.Finish(err)
 		return err
 	}
 
@@ -122,14 +155,29 @@ func (runner *Runner) tick(logger lager.Logger) error {
 		Duration:     time.Since(start),
 	}.Emit(logger)
 
-	for _, job := range config.Jobs {
+	jobs := config.Jobs
+
+	// schedule the jobs whose pending builds matter most first, so that they
+	// get to the workers before everything else in the pipeline
+	priorities, err := runner.DB.GetPendingBuildPriorities()
+	if err != nil {
+		logger.Error("failed-to-get-pending-build-priorities", err)
+	} else {
+		jobs = byPriority(jobs, priorities)
+	}
+
+	for _, job := range jobs {
 		sLog := logger.Session("scheduling", lager.Data{
 			"job": job.Name,
 		})
 
 		jStart := time.Now()
 
-		runner.Scheduler.Schedule(sLog, versions, job, config.Resources, config.ResourceTypes)
+		_, jSpan := tracing.StartSpan(ctx, "schedule-job", tracing.Attrs{
+			"job.name": job.Name,
+		})
+
+		jSpan.Finish(runner.Scheduler.Schedule(sLog, versions, job, config.Resources, config.ResourceTypes))
 
 		metric.SchedulingJobDuration{
 			PipelineName: runner.DB.GetPipelineName(),
@@ -140,3 +188,23 @@ func (runner *Runner) tick(logger lager.Logger) error {
 
 	return nil
 }
+
+func byPriority(jobs atc.JobConfigs, priorities map[string]int) atc.JobConfigs {
+	sorted := make(atc.JobConfigs, len(jobs))
+	copy(sorted, jobs)
+
+	sort.Stable(jobsByPriority{jobs: sorted, priorities: priorities})
+
+	return sorted
+}
+
+type jobsByPriority struct {
+	jobs       atc.JobConfigs
+	priorities map[string]int
+}
+
+func (s jobsByPriority) Len() int      { return len(s.jobs) }
+func (s jobsByPriority) Swap(i, j int) { s.jobs[i], s.jobs[j] = s.jobs[j], s.jobs[i] }
+func (s jobsByPriority) Less(i, j int) bool {
+	return s.priorities[s.jobs[i].Name] > s.priorities[s.jobs[j].Name]
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"time"
 
+	"code.cloudfoundry.org/clock"
 	"code.cloudfoundry.org/lager/lagertest"
 	"github.com/concourse/atc"
 	"github.com/concourse/atc/db"
@@ -23,9 +24,13 @@ var _ = Describe("Runner", func() {
 		pipelineDB *dbfakes.FakePipelineDB
 		scheduler  *schedulerfakes.FakeBuildScheduler
 		noop       bool
+		interval   time.Duration
 
 		lock *dbfakes.FakeLease
 
+		schedulingNotifier *dbfakes.FakeNotifier
+		scheduling         chan struct{}
+
 		initialConfig atc.Config
 
 		someVersions *algorithm.VersionsDB
@@ -38,6 +43,12 @@ var _ = Describe("Runner", func() {
 		pipelineDB.GetPipelineNameReturns("some-pipeline")
 		scheduler = new(schedulerfakes.FakeBuildScheduler)
 		noop = false
+		interval = 100 * time.Millisecond
+
+		scheduling = make(chan struct{}, 1)
+		schedulingNotifier = new(dbfakes.FakeNotifier)
+		schedulingNotifier.NotifyReturns(scheduling)
+		pipelineDB.SchedulingNotifierReturns(schedulingNotifier, nil)
 
 		someVersions = &algorithm.VersionsDB{
 			BuildOutputs: []algorithm.BuildOutput{
@@ -98,7 +109,8 @@ var _ = Describe("Runner", func() {
 			DB:        pipelineDB,
 			Scheduler: scheduler,
 			Noop:      noop,
-			Interval:  100 * time.Millisecond,
+			Clock:     clock.NewClock(),
+			Interval:  interval,
 		})
 	})
 
@@ -113,6 +125,49 @@ var _ = Describe("Runner", func() {
 		Expect(duration).To(Equal(100 * time.Millisecond))
 	})
 
+	Context("when something happens worth scheduling for", func() {
+		BeforeEach(func() {
+			interval = time.Hour
+		})
+
+		It("schedules again without waiting for the interval", func() {
+			Eventually(scheduler.ScheduleCallCount).Should(Equal(2))
+
+			scheduling <- struct{}{}
+
+			Eventually(scheduler.ScheduleCallCount).Should(Equal(4))
+		})
+
+		It("doesn't hold off for anyone else's last scheduling", func() {
+			Eventually(pipelineDB.AcquireSchedulingLockCallCount).Should(Equal(1))
+
+			scheduling <- struct{}{}
+
+			Eventually(pipelineDB.AcquireSchedulingLockCallCount).Should(Equal(2))
+
+			_, duration := pipelineDB.AcquireSchedulingLockArgsForCall(1)
+			Expect(duration).To(BeZero())
+		})
+
+		It("stops listening once it exits", func() {
+			Eventually(scheduler.ScheduleCallCount).Should(Equal(2))
+
+			ginkgomon.Interrupt(process)
+
+			Expect(schedulingNotifier.CloseCallCount()).To(Equal(1))
+		})
+	})
+
+	Context("when it can't listen for anything to schedule for", func() {
+		BeforeEach(func() {
+			pipelineDB.SchedulingNotifierReturns(nil, errors.New("nope"))
+		})
+
+		It("still schedules every interval", func() {
+			Eventually(scheduler.ScheduleCallCount).Should(BeNumerically(">=", 4))
+		})
+	})
+
 	Context("when it can't get the lock", func() {
 		BeforeEach(func() {
 			pipelineDB.AcquireSchedulingLockReturns(nil, false, nil)
@@ -153,6 +208,38 @@ var _ = Describe("Runner", func() {
 		Expect(resourceTypes).To(Equal(initialConfig.ResourceTypes))
 	})
 
+	Context("when a later job has a higher priority pending build", func() {
+		BeforeEach(func() {
+			pipelineDB.GetPendingBuildPrioritiesReturns(map[string]int{
+				"some-job":       1,
+				"some-other-job": 5,
+			}, nil)
+		})
+
+		It("schedules it first", func() {
+			Eventually(scheduler.ScheduleCallCount).Should(BeNumerically(">=", 2))
+
+			_, _, job, _, _ := scheduler.ScheduleArgsForCall(0)
+			Expect(job).To(Equal(atc.JobConfig{Name: "some-other-job"}))
+
+			_, _, job, _, _ = scheduler.ScheduleArgsForCall(1)
+			Expect(job).To(Equal(atc.JobConfig{Name: "some-job"}))
+		})
+	})
+
+	Context("when getting the pending build priorities fails", func() {
+		BeforeEach(func() {
+			pipelineDB.GetPendingBuildPrioritiesReturns(nil, errors.New("nope"))
+		})
+
+		It("schedules the jobs in the order they are configured", func() {
+			Eventually(scheduler.ScheduleCallCount).Should(BeNumerically(">=", 2))
+
+			_, _, job, _, _ := scheduler.ScheduleArgsForCall(0)
+			Expect(job).To(Equal(atc.JobConfig{Name: "some-job"}))
+		})
+	})
+
 	Context("when in noop mode", func() {
 		BeforeEach(func() {
 			noop = true
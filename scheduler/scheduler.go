@@ -27,7 +27,8 @@ type SchedulerDB interface {
 	LoadVersionsDB() (*algorithm.VersionsDB, error)
 	GetPipelineName() string
 	GetConfig() (atc.Config, db.ConfigVersion, bool, error)
-	CreateJobBuild(job string) (db.Build, error)
+	GetJob(job string) (db.SavedJob, error)
+	CreateJobBuildWithParams(job string, params map[string]string) (db.Build, error)
 	EnsurePendingBuildExists(jobName string) error
 	AcquireResourceCheckingForJobLock(logger lager.Logger, job string) (db.Lock, bool, error)
 }
@@ -55,7 +56,19 @@ func (s *Scheduler) Schedule(
 
 		//trigger: true, and the version has not been used
 		if ok && inputVersion.FirstOccurrence && inputConfig.Trigger {
-			err := s.DB.EnsurePendingBuildExists(jobConfig.Name)
+			job, err := s.DB.GetJob(jobConfig.Name)
+			if err != nil {
+				logger.Error("failed-to-get-job", err)
+				return err
+			}
+
+			// manual-only jobs still start the builds that were triggered by
+			// hand below; they just never get one from new versions
+			if job.ManualOnly {
+				break
+			}
+
+			err = s.DB.EnsurePendingBuildExists(jobConfig.Name)
 			if err != nil {
 				logger.Error("failed-to-ensure-pending-build-exists", err)
 				return err
@@ -77,6 +90,7 @@ func (s *Scheduler) TriggerImmediately(
 	jobConfig atc.JobConfig,
 	resourceConfigs atc.ResourceConfigs,
 	resourceTypes atc.ResourceTypes,
+	params map[string]string,
 ) (db.Build, Waiter, error) {
 	logger = logger.Session("trigger-immediately", lager.Data{"job_name": jobConfig.Name})
 
@@ -89,7 +103,7 @@ func (s *Scheduler) TriggerImmediately(
 		return nil, nil, err
 	}
 
-	build, err := s.DB.CreateJobBuild(jobConfig.Name)
+	build, err := s.DB.CreateJobBuildWithParams(jobConfig.Name, params)
 	if err != nil {
 		logger.Error("failed-to-create-job-build", err)
 		if acquired {
@@ -183,6 +183,40 @@ var _ = Describe("Scheduler", func() {
 					}, nil)
 				})
 
+				It("looks up the job", func() {
+					Expect(fakeDB.GetJobCallCount()).To(Equal(1))
+					Expect(fakeDB.GetJobArgsForCall(0)).To(Equal("some-job"))
+				})
+
+				Context("when looking up the job fails", func() {
+					BeforeEach(func() {
+						fakeDB.GetJobReturns(db.SavedJob{}, disaster)
+					})
+
+					It("returns the error", func() {
+						Expect(scheduleErr).To(Equal(disaster))
+					})
+
+					It("does not create a pending build", func() {
+						Expect(fakeDB.EnsurePendingBuildExistsCallCount()).To(BeZero())
+					})
+				})
+
+				Context("when the job is manual only", func() {
+					BeforeEach(func() {
+						fakeDB.GetJobReturns(db.SavedJob{ManualOnly: true}, nil)
+					})
+
+					It("does not create a pending build", func() {
+						Expect(fakeDB.EnsurePendingBuildExistsCallCount()).To(BeZero())
+					})
+
+					It("still starts all pending builds", func() {
+						Expect(fakeBuildStarter.TryStartAllPendingBuildsCallCount()).To(Equal(1))
+						Expect(scheduleErr).NotTo(HaveOccurred())
+					})
+				})
+
 				Context("when creating a pending build fails", func() {
 					BeforeEach(func() {
 						fakeDB.EnsurePendingBuildExistsReturns(disaster)
@@ -227,7 +261,8 @@ var _ = Describe("Scheduler", func() {
 				lagertest.NewTestLogger("test"),
 				jobConfig,
 				atc.ResourceConfigs{{Name: "some-resource"}},
-				atc.ResourceTypes{{Name: "some-resource-type"}})
+				atc.ResourceTypes{{Name: "some-resource-type"}},
+				map[string]string{"environment": "staging"})
 			if waiter != nil {
 				waiter.Wait()
 			}
@@ -256,7 +291,7 @@ var _ = Describe("Scheduler", func() {
 
 			Context("when creating the build fails", func() {
 				BeforeEach(func() {
-					fakeDB.CreateJobBuildReturns(nil, disaster)
+					fakeDB.CreateJobBuildWithParamsReturns(nil, disaster)
 				})
 
 				It("returns the error", func() {
@@ -264,8 +299,10 @@ var _ = Describe("Scheduler", func() {
 				})
 
 				It("created a build for the right job", func() {
-					Expect(fakeDB.CreateJobBuildCallCount()).To(Equal(1))
-					Expect(fakeDB.CreateJobBuildArgsForCall(0)).To(Equal("some-job"))
+					Expect(fakeDB.CreateJobBuildWithParamsCallCount()).To(Equal(1))
+					actualJobName, actualParams := fakeDB.CreateJobBuildWithParamsArgsForCall(0)
+					Expect(actualJobName).To(Equal("some-job"))
+					Expect(actualParams).To(Equal(map[string]string{"environment": "staging"}))
 				})
 			})
 
@@ -274,7 +311,7 @@ var _ = Describe("Scheduler", func() {
 
 				BeforeEach(func() {
 					createdBuild = new(dbfakes.FakeBuild)
-					fakeDB.CreateJobBuildStub = func(jobName string) (db.Build, error) {
+					fakeDB.CreateJobBuildWithParamsStub = func(jobName string, params map[string]string) (db.Build, error) {
 						defer GinkgoRecover()
 						Expect(fakeBuildStarter.TryStartAllPendingBuildsCallCount()).To(BeZero())
 						return createdBuild, nil
@@ -320,14 +357,14 @@ var _ = Describe("Scheduler", func() {
 				fakeLease = new(dbfakes.FakeLease)
 				fakeDB.AcquireResourceCheckingForJobLockStub = func(lager.Logger, string) (db.Lock, bool, error) {
 					defer GinkgoRecover()
-					Expect(fakeDB.CreateJobBuildCallCount()).To(BeZero())
+					Expect(fakeDB.CreateJobBuildWithParamsCallCount()).To(BeZero())
 					return fakeLease, true, nil
 				}
 			})
 
 			Context("when creating the build fails", func() {
 				BeforeEach(func() {
-					fakeDB.CreateJobBuildReturns(nil, disaster)
+					fakeDB.CreateJobBuildWithParamsReturns(nil, disaster)
 				})
 
 				It("returns the error", func() {
@@ -335,8 +372,10 @@ var _ = Describe("Scheduler", func() {
 				})
 
 				It("created a build for the right job after acquiring the lock", func() {
-					Expect(fakeDB.CreateJobBuildCallCount()).To(Equal(1))
-					Expect(fakeDB.CreateJobBuildArgsForCall(0)).To(Equal("some-job"))
+					Expect(fakeDB.CreateJobBuildWithParamsCallCount()).To(Equal(1))
+					actualJobName, actualParams := fakeDB.CreateJobBuildWithParamsArgsForCall(0)
+					Expect(actualJobName).To(Equal("some-job"))
+					Expect(actualParams).To(Equal(map[string]string{"environment": "staging"}))
 				})
 
 				It("releases the lock", func() {
@@ -349,7 +388,7 @@ var _ = Describe("Scheduler", func() {
 
 				BeforeEach(func() {
 					createdBuild = new(dbfakes.FakeBuild)
-					fakeDB.CreateJobBuildReturns(createdBuild, nil)
+					fakeDB.CreateJobBuildWithParamsReturns(createdBuild, nil)
 				})
 
 				Context("when resource checking fails", func() {
@@ -0,0 +1,249 @@
+// This file was generated by counterfeiter
+package schedulerfakes
+
+import (
+	"sync"
+	"time"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/scheduler"
+)
+
+type FakeCronDB struct {
+	GetConfigStub        func() (atc.Config, db.ConfigVersion, bool, error)
+	getConfigMutex       sync.RWMutex
+	getConfigArgsForCall []struct{}
+	getConfigReturns     struct {
+		result1 atc.Config
+		result2 db.ConfigVersion
+		result3 bool
+		result4 error
+	}
+	IsPausedStub        func() (bool, error)
+	isPausedMutex       sync.RWMutex
+	isPausedArgsForCall []struct{}
+	isPausedReturns     struct {
+		result1 bool
+		result2 error
+	}
+	GetJobStub        func(job string) (db.SavedJob, error)
+	getJobMutex       sync.RWMutex
+	getJobArgsForCall []struct {
+		job string
+	}
+	getJobReturns struct {
+		result1 db.SavedJob
+		result2 error
+	}
+	GetJobLastScheduledAtStub        func(job string) (time.Time, error)
+	getJobLastScheduledAtMutex       sync.RWMutex
+	getJobLastScheduledAtArgsForCall []struct {
+		job string
+	}
+	getJobLastScheduledAtReturns struct {
+		result1 time.Time
+		result2 error
+	}
+	SaveJobLastScheduledAtStub        func(job string, previous time.Time, at time.Time) (bool, error)
+	saveJobLastScheduledAtMutex       sync.RWMutex
+	saveJobLastScheduledAtArgsForCall []struct {
+		job      string
+		previous time.Time
+		at       time.Time
+	}
+	saveJobLastScheduledAtReturns struct {
+		result1 bool
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeCronDB) GetConfig() (atc.Config, db.ConfigVersion, bool, error) {
+	fake.getConfigMutex.Lock()
+	fake.getConfigArgsForCall = append(fake.getConfigArgsForCall, struct{}{})
+	fake.recordInvocation("GetConfig", []interface{}{})
+	fake.getConfigMutex.Unlock()
+	if fake.GetConfigStub != nil {
+		return fake.GetConfigStub()
+	} else {
+		return fake.getConfigReturns.result1, fake.getConfigReturns.result2, fake.getConfigReturns.result3, fake.getConfigReturns.result4
+	}
+}
+
+func (fake *FakeCronDB) GetConfigCallCount() int {
+	fake.getConfigMutex.RLock()
+	defer fake.getConfigMutex.RUnlock()
+	return len(fake.getConfigArgsForCall)
+}
+
+func (fake *FakeCronDB) GetConfigReturns(result1 atc.Config, result2 db.ConfigVersion, result3 bool, result4 error) {
+	fake.GetConfigStub = nil
+	fake.getConfigReturns = struct {
+		result1 atc.Config
+		result2 db.ConfigVersion
+		result3 bool
+		result4 error
+	}{result1, result2, result3, result4}
+}
+
+func (fake *FakeCronDB) IsPaused() (bool, error) {
+	fake.isPausedMutex.Lock()
+	fake.isPausedArgsForCall = append(fake.isPausedArgsForCall, struct{}{})
+	fake.recordInvocation("IsPaused", []interface{}{})
+	fake.isPausedMutex.Unlock()
+	if fake.IsPausedStub != nil {
+		return fake.IsPausedStub()
+	} else {
+		return fake.isPausedReturns.result1, fake.isPausedReturns.result2
+	}
+}
+
+func (fake *FakeCronDB) IsPausedCallCount() int {
+	fake.isPausedMutex.RLock()
+	defer fake.isPausedMutex.RUnlock()
+	return len(fake.isPausedArgsForCall)
+}
+
+func (fake *FakeCronDB) IsPausedReturns(result1 bool, result2 error) {
+	fake.IsPausedStub = nil
+	fake.isPausedReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCronDB) GetJob(job string) (db.SavedJob, error) {
+	fake.getJobMutex.Lock()
+	fake.getJobArgsForCall = append(fake.getJobArgsForCall, struct {
+		job string
+	}{job})
+	fake.recordInvocation("GetJob", []interface{}{job})
+	fake.getJobMutex.Unlock()
+	if fake.GetJobStub != nil {
+		return fake.GetJobStub(job)
+	} else {
+		return fake.getJobReturns.result1, fake.getJobReturns.result2
+	}
+}
+
+func (fake *FakeCronDB) GetJobCallCount() int {
+	fake.getJobMutex.RLock()
+	defer fake.getJobMutex.RUnlock()
+	return len(fake.getJobArgsForCall)
+}
+
+func (fake *FakeCronDB) GetJobArgsForCall(i int) string {
+	fake.getJobMutex.RLock()
+	defer fake.getJobMutex.RUnlock()
+	return fake.getJobArgsForCall[i].job
+}
+
+func (fake *FakeCronDB) GetJobReturns(result1 db.SavedJob, result2 error) {
+	fake.GetJobStub = nil
+	fake.getJobReturns = struct {
+		result1 db.SavedJob
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCronDB) GetJobLastScheduledAt(job string) (time.Time, error) {
+	fake.getJobLastScheduledAtMutex.Lock()
+	fake.getJobLastScheduledAtArgsForCall = append(fake.getJobLastScheduledAtArgsForCall, struct {
+		job string
+	}{job})
+	fake.recordInvocation("GetJobLastScheduledAt", []interface{}{job})
+	fake.getJobLastScheduledAtMutex.Unlock()
+	if fake.GetJobLastScheduledAtStub != nil {
+		return fake.GetJobLastScheduledAtStub(job)
+	} else {
+		return fake.getJobLastScheduledAtReturns.result1, fake.getJobLastScheduledAtReturns.result2
+	}
+}
+
+func (fake *FakeCronDB) GetJobLastScheduledAtCallCount() int {
+	fake.getJobLastScheduledAtMutex.RLock()
+	defer fake.getJobLastScheduledAtMutex.RUnlock()
+	return len(fake.getJobLastScheduledAtArgsForCall)
+}
+
+func (fake *FakeCronDB) GetJobLastScheduledAtArgsForCall(i int) string {
+	fake.getJobLastScheduledAtMutex.RLock()
+	defer fake.getJobLastScheduledAtMutex.RUnlock()
+	return fake.getJobLastScheduledAtArgsForCall[i].job
+}
+
+func (fake *FakeCronDB) GetJobLastScheduledAtReturns(result1 time.Time, result2 error) {
+	fake.GetJobLastScheduledAtStub = nil
+	fake.getJobLastScheduledAtReturns = struct {
+		result1 time.Time
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCronDB) SaveJobLastScheduledAt(job string, previous time.Time, at time.Time) (bool, error) {
+	fake.saveJobLastScheduledAtMutex.Lock()
+	fake.saveJobLastScheduledAtArgsForCall = append(fake.saveJobLastScheduledAtArgsForCall, struct {
+		job      string
+		previous time.Time
+		at       time.Time
+	}{job, previous, at})
+	fake.recordInvocation("SaveJobLastScheduledAt", []interface{}{job, previous, at})
+	fake.saveJobLastScheduledAtMutex.Unlock()
+	if fake.SaveJobLastScheduledAtStub != nil {
+		return fake.SaveJobLastScheduledAtStub(job, previous, at)
+	} else {
+		return fake.saveJobLastScheduledAtReturns.result1, fake.saveJobLastScheduledAtReturns.result2
+	}
+}
+
+func (fake *FakeCronDB) SaveJobLastScheduledAtCallCount() int {
+	fake.saveJobLastScheduledAtMutex.RLock()
+	defer fake.saveJobLastScheduledAtMutex.RUnlock()
+	return len(fake.saveJobLastScheduledAtArgsForCall)
+}
+
+func (fake *FakeCronDB) SaveJobLastScheduledAtArgsForCall(i int) (string, time.Time, time.Time) {
+	fake.saveJobLastScheduledAtMutex.RLock()
+	defer fake.saveJobLastScheduledAtMutex.RUnlock()
+	return fake.saveJobLastScheduledAtArgsForCall[i].job, fake.saveJobLastScheduledAtArgsForCall[i].previous, fake.saveJobLastScheduledAtArgsForCall[i].at
+}
+
+func (fake *FakeCronDB) SaveJobLastScheduledAtReturns(result1 bool, result2 error) {
+	fake.SaveJobLastScheduledAtStub = nil
+	fake.saveJobLastScheduledAtReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCronDB) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.getConfigMutex.RLock()
+	defer fake.getConfigMutex.RUnlock()
+	fake.isPausedMutex.RLock()
+	defer fake.isPausedMutex.RUnlock()
+	fake.getJobMutex.RLock()
+	defer fake.getJobMutex.RUnlock()
+	fake.getJobLastScheduledAtMutex.RLock()
+	defer fake.getJobLastScheduledAtMutex.RUnlock()
+	fake.saveJobLastScheduledAtMutex.RLock()
+	defer fake.saveJobLastScheduledAtMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeCronDB) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ scheduler.CronDB = new(FakeCronDB)
@@ -46,12 +46,13 @@ type FakeSchedulerDB struct {
 		result3 bool
 		result4 error
 	}
-	CreateJobBuildStub        func(job string) (db.Build, error)
-	createJobBuildMutex       sync.RWMutex
-	createJobBuildArgsForCall []struct {
-		job string
+	CreateJobBuildWithParamsStub        func(job string, params map[string]string) (db.Build, error)
+	createJobBuildWithParamsMutex       sync.RWMutex
+	createJobBuildWithParamsArgsForCall []struct {
+		job    string
+		params map[string]string
 	}
-	createJobBuildReturns struct {
+	createJobBuildWithParamsReturns struct {
 		result1 db.Build
 		result2 error
 	}
@@ -74,6 +75,15 @@ type FakeSchedulerDB struct {
 		result2 bool
 		result3 error
 	}
+	GetJobStub        func(job string) (db.SavedJob, error)
+	getJobMutex       sync.RWMutex
+	getJobArgsForCall []struct {
+		job string
+	}
+	getJobReturns struct {
+		result1 db.SavedJob
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -193,35 +203,36 @@ func (fake *FakeSchedulerDB) GetConfigReturns(result1 atc.Config, result2 db.Con
 	}{result1, result2, result3, result4}
 }
 
-func (fake *FakeSchedulerDB) CreateJobBuild(job string) (db.Build, error) {
-	fake.createJobBuildMutex.Lock()
-	fake.createJobBuildArgsForCall = append(fake.createJobBuildArgsForCall, struct {
-		job string
-	}{job})
-	fake.recordInvocation("CreateJobBuild", []interface{}{job})
-	fake.createJobBuildMutex.Unlock()
-	if fake.CreateJobBuildStub != nil {
-		return fake.CreateJobBuildStub(job)
+func (fake *FakeSchedulerDB) CreateJobBuildWithParams(job string, params map[string]string) (db.Build, error) {
+	fake.createJobBuildWithParamsMutex.Lock()
+	fake.createJobBuildWithParamsArgsForCall = append(fake.createJobBuildWithParamsArgsForCall, struct {
+		job    string
+		params map[string]string
+	}{job, params})
+	fake.recordInvocation("CreateJobBuildWithParams", []interface{}{job, params})
+	fake.createJobBuildWithParamsMutex.Unlock()
+	if fake.CreateJobBuildWithParamsStub != nil {
+		return fake.CreateJobBuildWithParamsStub(job, params)
 	} else {
-		return fake.createJobBuildReturns.result1, fake.createJobBuildReturns.result2
+		return fake.createJobBuildWithParamsReturns.result1, fake.createJobBuildWithParamsReturns.result2
 	}
 }
 
-func (fake *FakeSchedulerDB) CreateJobBuildCallCount() int {
-	fake.createJobBuildMutex.RLock()
-	defer fake.createJobBuildMutex.RUnlock()
-	return len(fake.createJobBuildArgsForCall)
+func (fake *FakeSchedulerDB) CreateJobBuildWithParamsCallCount() int {
+	fake.createJobBuildWithParamsMutex.RLock()
+	defer fake.createJobBuildWithParamsMutex.RUnlock()
+	return len(fake.createJobBuildWithParamsArgsForCall)
 }
 
-func (fake *FakeSchedulerDB) CreateJobBuildArgsForCall(i int) string {
-	fake.createJobBuildMutex.RLock()
-	defer fake.createJobBuildMutex.RUnlock()
-	return fake.createJobBuildArgsForCall[i].job
+func (fake *FakeSchedulerDB) CreateJobBuildWithParamsArgsForCall(i int) (string, map[string]string) {
+	fake.createJobBuildWithParamsMutex.RLock()
+	defer fake.createJobBuildWithParamsMutex.RUnlock()
+	return fake.createJobBuildWithParamsArgsForCall[i].job, fake.createJobBuildWithParamsArgsForCall[i].params
 }
 
-func (fake *FakeSchedulerDB) CreateJobBuildReturns(result1 db.Build, result2 error) {
-	fake.CreateJobBuildStub = nil
-	fake.createJobBuildReturns = struct {
+func (fake *FakeSchedulerDB) CreateJobBuildWithParamsReturns(result1 db.Build, result2 error) {
+	fake.CreateJobBuildWithParamsStub = nil
+	fake.createJobBuildWithParamsReturns = struct {
 		result1 db.Build
 		result2 error
 	}{result1, result2}
@@ -296,6 +307,40 @@ func (fake *FakeSchedulerDB) AcquireResourceCheckingForJobLockReturns(result1 db
 	}{result1, result2, result3}
 }
 
+func (fake *FakeSchedulerDB) GetJob(job string) (db.SavedJob, error) {
+	fake.getJobMutex.Lock()
+	fake.getJobArgsForCall = append(fake.getJobArgsForCall, struct {
+		job string
+	}{job})
+	fake.recordInvocation("GetJob", []interface{}{job})
+	fake.getJobMutex.Unlock()
+	if fake.GetJobStub != nil {
+		return fake.GetJobStub(job)
+	} else {
+		return fake.getJobReturns.result1, fake.getJobReturns.result2
+	}
+}
+
+func (fake *FakeSchedulerDB) GetJobCallCount() int {
+	fake.getJobMutex.RLock()
+	defer fake.getJobMutex.RUnlock()
+	return len(fake.getJobArgsForCall)
+}
+
+func (fake *FakeSchedulerDB) GetJobArgsForCall(i int) string {
+	fake.getJobMutex.RLock()
+	defer fake.getJobMutex.RUnlock()
+	return fake.getJobArgsForCall[i].job
+}
+
+func (fake *FakeSchedulerDB) GetJobReturns(result1 db.SavedJob, result2 error) {
+	fake.GetJobStub = nil
+	fake.getJobReturns = struct {
+		result1 db.SavedJob
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeSchedulerDB) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -307,12 +352,14 @@ func (fake *FakeSchedulerDB) Invocations() map[string][][]interface{} {
 	defer fake.getPipelineNameMutex.RUnlock()
 	fake.getConfigMutex.RLock()
 	defer fake.getConfigMutex.RUnlock()
-	fake.createJobBuildMutex.RLock()
-	defer fake.createJobBuildMutex.RUnlock()
+	fake.createJobBuildWithParamsMutex.RLock()
+	defer fake.createJobBuildWithParamsMutex.RUnlock()
 	fake.ensurePendingBuildExistsMutex.RLock()
 	defer fake.ensurePendingBuildExistsMutex.RUnlock()
 	fake.acquireResourceCheckingForJobLockMutex.RLock()
 	defer fake.acquireResourceCheckingForJobLockMutex.RUnlock()
+	fake.getJobMutex.RLock()
+	defer fake.getJobMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -0,0 +1,18 @@
+package storage
+
+import "io"
+
+//go:generate counterfeiter . Driver
+
+// Driver is somewhere to keep things that should outlive any one worker, e.g.
+// task caches, keyed by slash-separated names.
+type Driver interface {
+	// Get returns false if nothing is stored under the key.
+	Get(key string) (io.ReadCloser, bool, error)
+
+	// Put replaces whatever is stored under the key.
+	Put(key string, contents io.Reader) error
+
+	// Delete succeeds if nothing is stored under the key.
+	Delete(key string) error
+}
@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+type localDriver struct {
+	dir string
+}
+
+// NewLocalDriver stores things as files under a directory, e.g. one on a disk
+// shared by every ATC.
+func NewLocalDriver(dir string) Driver {
+	return &localDriver{dir: dir}
+}
+
+func (driver *localDriver) Get(key string) (io.ReadCloser, bool, error) {
+	file, err := os.Open(driver.path(key))
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	return file, true, nil
+}
+
+// Put writes the contents next to where they're going and renames them into
+// place, so that nothing sees them half-written.
+func (driver *localDriver) Put(key string, contents io.Reader) error {
+	dest := driver.path(key)
+
+	err := os.MkdirAll(filepath.Dir(dest), 0755)
+	if err != nil {
+		return err
+	}
+
+	file, err := ioutil.TempFile(filepath.Dir(dest), ".put")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(file.Name())
+
+	_, err = io.Copy(file, contents)
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	err = file.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), dest)
+}
+
+func (driver *localDriver) Delete(key string) error {
+	err := os.Remove(driver.path(key))
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	return err
+}
+
+func (driver *localDriver) path(key string) string {
+	return filepath.Join(driver.dir, filepath.FromSlash(filepath.Clean("/"+key)))
+}
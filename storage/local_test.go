@@ -0,0 +1,101 @@
+package storage_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/concourse/atc/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LocalDriver", func() {
+	var (
+		dir string
+
+		driver Driver
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "local-driver")
+		Expect(err).NotTo(HaveOccurred())
+
+		driver = NewLocalDriver(dir)
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("has nothing to begin with", func() {
+		_, found, err := driver.Get("some/key")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeFalse())
+
+		Expect(driver.Delete("some/key")).To(Succeed())
+	})
+
+	It("stores things under the directory until they're deleted", func() {
+		Expect(driver.Put("some/key", bytes.NewBufferString("some-contents"))).To(Succeed())
+		Expect(driver.Put("some/key", bytes.NewBufferString("other-contents"))).To(Succeed())
+
+		Expect(ioutil.ReadFile(filepath.Join(dir, "some", "key"))).To(Equal([]byte("other-contents")))
+
+		contents, found, err := driver.Get("some/key")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeTrue())
+		Expect(ioutil.ReadAll(contents)).To(Equal([]byte("other-contents")))
+		Expect(contents.Close()).To(Succeed())
+
+		Expect(driver.Delete("some/key")).To(Succeed())
+
+		_, found, err = driver.Get("some/key")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeFalse())
+	})
+
+	It("keeps keys from escaping the directory", func() {
+		Expect(driver.Put("../../escaped", bytes.NewBufferString("some-contents"))).To(Succeed())
+
+		Expect(ioutil.ReadFile(filepath.Join(dir, "escaped"))).To(Equal([]byte("some-contents")))
+	})
+})
+
+var _ = Describe("Prefixed", func() {
+	var (
+		dir string
+
+		driver Driver
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "prefixed-driver")
+		Expect(err).NotTo(HaveOccurred())
+
+		driver = Prefixed(NewLocalDriver(dir), "some-prefix")
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("keeps everything under the prefix", func() {
+		Expect(driver.Put("some/key", bytes.NewBufferString("some-contents"))).To(Succeed())
+
+		Expect(ioutil.ReadFile(filepath.Join(dir, "some-prefix", "some", "key"))).To(Equal([]byte("some-contents")))
+
+		_, found, err := driver.Get("some/key")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeTrue())
+
+		Expect(driver.Delete("some/key")).To(Succeed())
+
+		_, err = os.Stat(filepath.Join(dir, "some-prefix", "some", "key"))
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+})
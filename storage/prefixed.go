@@ -0,0 +1,29 @@
+package storage
+
+import (
+	"io"
+	"path"
+)
+
+type prefixedDriver struct {
+	driver Driver
+	prefix string
+}
+
+// Prefixed keeps everything under the prefix, so that one bucket or
+// directory can be shared by things whose keys would otherwise clash.
+func Prefixed(driver Driver, prefix string) Driver {
+	return prefixedDriver{driver: driver, prefix: prefix}
+}
+
+func (driver prefixedDriver) Get(key string) (io.ReadCloser, bool, error) {
+	return driver.driver.Get(path.Join(driver.prefix, key))
+}
+
+func (driver prefixedDriver) Put(key string, contents io.Reader) error {
+	return driver.driver.Put(path.Join(driver.prefix, key), contents)
+}
+
+func (driver prefixedDriver) Delete(key string) error {
+	return driver.driver.Delete(path.Join(driver.prefix, key))
+}
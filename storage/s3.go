@@ -0,0 +1,236 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// GCSEndpoint is where Google Cloud Storage serves its S3-compatible API.
+var GCSEndpoint = &url.URL{Scheme: "https", Host: "storage.googleapis.com"}
+
+type s3Driver struct {
+	endpoint *url.URL
+	bucket   string
+	region   string
+
+	accessKeyID     string
+	secretAccessKey string
+
+	httpClient *http.Client
+}
+
+// NewS3Driver stores things in a bucket of anything implementing the S3 API,
+// e.g. S3 itself or Minio. Buckets are addressed path-style, so that the
+// endpoint can be an IP.
+func NewS3Driver(
+	endpoint *url.URL,
+	bucket string,
+	region string,
+	accessKeyID string,
+	secretAccessKey string,
+) Driver {
+	return &s3Driver{
+		endpoint: endpoint,
+		bucket:   bucket,
+		region:   region,
+
+		accessKeyID:     accessKeyID,
+		secretAccessKey: secretAccessKey,
+
+		httpClient: &http.Client{
+			Timeout: 30 * time.Minute,
+		},
+	}
+}
+
+// NewGCSDriver stores things in a Google Cloud Storage bucket, authenticating
+// with one of the project's HMAC keys.
+func NewGCSDriver(bucket string, accessKeyID string, secretAccessKey string) Driver {
+	return NewS3Driver(GCSEndpoint, bucket, "auto", accessKeyID, secretAccessKey)
+}
+
+func (driver *s3Driver) Get(key string) (io.ReadCloser, bool, error) {
+	request, err := driver.newRequest("GET", key, nil, emptySHA256)
+	if err != nil {
+		return nil, false, err
+	}
+
+	response, err := driver.httpClient.Do(request)
+	if err != nil {
+		return nil, false, err
+	}
+
+	switch response.StatusCode {
+	case http.StatusOK:
+		return response.Body, true, nil
+	case http.StatusNotFound:
+		response.Body.Close()
+		return nil, false, nil
+	default:
+		defer response.Body.Close()
+		return nil, false, unexpectedResponse(response)
+	}
+}
+
+// Put spools the contents to a temporary file first, as S3 needs to know
+// their length and checksum before it will accept them.
+func (driver *s3Driver) Put(key string, contents io.Reader) error {
+	spool, err := ioutil.TempFile("", "s3-put")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(spool.Name())
+	defer spool.Close()
+
+	hash := sha256.New()
+
+	size, err := io.Copy(io.MultiWriter(spool, hash), contents)
+	if err != nil {
+		return err
+	}
+
+	_, err = spool.Seek(0, io.SeekStart)
+	if err != nil {
+		return err
+	}
+
+	request, err := driver.newRequest("PUT", key, ioutil.NopCloser(spool), hex.EncodeToString(hash.Sum(nil)))
+	if err != nil {
+		return err
+	}
+
+	request.ContentLength = size
+
+	return driver.do(request, http.StatusOK)
+}
+
+func (driver *s3Driver) Delete(key string) error {
+	request, err := driver.newRequest("DELETE", key, nil, emptySHA256)
+	if err != nil {
+		return err
+	}
+
+	return driver.do(request, http.StatusNoContent, http.StatusOK, http.StatusNotFound)
+}
+
+func (driver *s3Driver) do(request *http.Request, okStatuses ...int) error {
+	response, err := driver.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	for _, status := range okStatuses {
+		if response.StatusCode == status {
+			return nil
+		}
+	}
+
+	return unexpectedResponse(response)
+}
+
+func (driver *s3Driver) newRequest(method string, key string, body io.ReadCloser, payloadHash string) (*http.Request, error) {
+	objectURL := *driver.endpoint
+	objectURL.Path = path.Join("/", objectURL.Path, driver.bucket, key)
+
+	request, err := http.NewRequest(method, objectURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if body != nil {
+		request.Body = body
+	}
+
+	SignV4(request, payloadHash, driver.region, driver.accessKeyID, driver.secretAccessKey, time.Now().UTC())
+
+	return request, nil
+}
+
+var emptySHA256 = SHA256Hex(nil)
+
+// SignV4 adds an AWS Signature Version 4 Authorization header to a request to
+// the S3 API, given the SHA-256 of its body as hex.
+//
+// See http://docs.aws.amazon.com/general/latest/gr/sigv4_signing.html
+func SignV4(
+	request *http.Request,
+	payloadHash string,
+	region string,
+	accessKeyID string,
+	secretAccessKey string,
+	now time.Time,
+) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	request.Header.Set("X-Amz-Date", amzDate)
+	request.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		request.URL.EscapedPath(),
+		request.URL.RawQuery,
+		"host:" + request.URL.Host,
+		"x-amz-content-sha256:" + payloadHash,
+		"x-amz-date:" + amzDate,
+		"",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{date, region, "s3", "aws4_request"}, "/")
+
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		SHA256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+secretAccessKey), date)
+	signingKey = hmacSHA256(signingKey, region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	request.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKeyID,
+		scope,
+		signedHeaders,
+		signature,
+	))
+}
+
+// SHA256Hex is the hex SHA-256 of the data, as SignV4 wants it.
+func SHA256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func unexpectedResponse(response *http.Response) error {
+	body, _ := ioutil.ReadAll(io.LimitReader(response.Body, 1024))
+	return fmt.Errorf("unexpected response from object store: %s: %s", response.Status, strings.TrimSpace(string(body)))
+}
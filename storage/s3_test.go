@@ -0,0 +1,158 @@
+package storage_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	. "github.com/concourse/atc/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("S3Driver", func() {
+	var (
+		server *ghttp.Server
+
+		driver Driver
+	)
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+
+		endpoint, err := url.Parse(server.URL())
+		Expect(err).NotTo(HaveOccurred())
+
+		driver = NewS3Driver(endpoint, "some-bucket", "some-region", "some-key-id", "some-secret")
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	verifySigned := func(payloadHash string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Header.Get("X-Amz-Date")).To(MatchRegexp(`^\d{8}T\d{6}Z$`))
+			Expect(r.Header.Get("X-Amz-Content-Sha256")).To(Equal(payloadHash))
+			Expect(r.Header.Get("Authorization")).To(MatchRegexp(
+				`^AWS4-HMAC-SHA256 Credential=some-key-id/\d{8}/some-region/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature=[0-9a-f]{64}$`,
+			))
+		}
+	}
+
+	Describe("Put", func() {
+		Context("when the bucket accepts the object", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(ghttp.CombineHandlers(
+					ghttp.VerifyRequest("PUT", "/some-bucket/some/key"),
+					ghttp.VerifyBody([]byte("some-contents")),
+					verifySigned(SHA256Hex([]byte("some-contents"))),
+					func(w http.ResponseWriter, r *http.Request) {
+						Expect(r.ContentLength).To(Equal(int64(len("some-contents"))))
+					},
+					ghttp.RespondWith(http.StatusOK, ""),
+				))
+			})
+
+			It("succeeds", func() {
+				Expect(driver.Put("some/key", bytes.NewBufferString("some-contents"))).To(Succeed())
+			})
+		})
+
+		Context("when the bucket rejects the object", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(ghttp.RespondWith(http.StatusForbidden, "<Error>AccessDenied</Error>"))
+			})
+
+			It("returns an error with the response", func() {
+				err := driver.Put("some/key", bytes.NewBufferString("some-contents"))
+				Expect(err).To(MatchError(ContainSubstring("403")))
+				Expect(err).To(MatchError(ContainSubstring("AccessDenied")))
+			})
+		})
+	})
+
+	Describe("Get", func() {
+		Context("when the object exists", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/some-bucket/some/key"),
+					verifySigned(SHA256Hex(nil)),
+					ghttp.RespondWith(http.StatusOK, "some-contents"),
+				))
+			})
+
+			It("returns its contents", func() {
+				contents, found, err := driver.Get("some/key")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+
+				defer contents.Close()
+
+				Expect(ioutil.ReadAll(contents)).To(Equal([]byte("some-contents")))
+			})
+		})
+
+		Context("when the object does not exist", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(ghttp.RespondWith(http.StatusNotFound, ""))
+			})
+
+			It("returns false", func() {
+				_, found, err := driver.Get("some/key")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeFalse())
+			})
+		})
+
+		Context("when the bucket fails", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(ghttp.RespondWith(http.StatusInternalServerError, ""))
+			})
+
+			It("returns an error", func() {
+				_, _, err := driver.Get("some/key")
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("Delete", func() {
+		Context("when the object exists", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(ghttp.CombineHandlers(
+					ghttp.VerifyRequest("DELETE", "/some-bucket/some/key"),
+					verifySigned(SHA256Hex(nil)),
+					ghttp.RespondWith(http.StatusNoContent, ""),
+				))
+			})
+
+			It("succeeds", func() {
+				Expect(driver.Delete("some/key")).To(Succeed())
+			})
+		})
+
+		Context("when the object does not exist", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(ghttp.RespondWith(http.StatusNotFound, ""))
+			})
+
+			It("succeeds", func() {
+				Expect(driver.Delete("some/key")).To(Succeed())
+			})
+		})
+
+		Context("when the bucket fails", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(ghttp.RespondWith(http.StatusInternalServerError, ""))
+			})
+
+			It("returns an error", func() {
+				Expect(driver.Delete("some/key")).NotTo(Succeed())
+			})
+		})
+	})
+})
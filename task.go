@@ -31,6 +31,10 @@ type TaskConfig struct {
 
 	// The set of (logical, name-only) outputs provided by the task.
 	Outputs []TaskOutputConfig `json:"outputs,omitempty" yaml:"outputs,omitempty" mapstructure:"outputs"`
+
+	// Directories, relative to the working directory, to save after the task
+	// succeeds and restore before the job's next build runs it.
+	Caches []TaskCacheConfig `json:"caches,omitempty" yaml:"caches,omitempty" mapstructure:"caches"`
 }
 
 type ImageResource struct {
@@ -106,6 +110,10 @@ func (config TaskConfig) Merge(other TaskConfig) TaskConfig {
 		config.Inputs = other.Inputs
 	}
 
+	if len(other.Caches) != 0 {
+		config.Caches = other.Caches
+	}
+
 	if other.Run.Path != "" {
 		config.Run = other.Run
 	}
@@ -125,6 +133,7 @@ func (config TaskConfig) Validate() error {
 	}
 
 	messages = append(messages, config.validateInputsAndOutputs()...)
+	messages = append(messages, config.validateCaches()...)
 
 	if len(messages) > 0 {
 		return fmt.Errorf("invalid task configuration:\n%s", strings.Join(messages, "\n"))
@@ -327,6 +336,29 @@ func (config TaskConfig) validateInputContainsNames() []string {
 	return messages
 }
 
+func (config TaskConfig) validateCaches() []string {
+	messages := []string{}
+
+	paths := map[string]bool{}
+
+	for i, cache := range config.Caches {
+		path := filepath.Clean(cache.Path)
+
+		switch {
+		case cache.Path == "":
+			messages = append(messages, fmt.Sprintf("  cache in position %d is missing a path", i))
+		case filepath.IsAbs(path) || path == "." || path == ".." || strings.HasPrefix(path, "../"):
+			messages = append(messages, fmt.Sprintf("  cache path '%s' must be a directory within the working directory", cache.Path))
+		case paths[path]:
+			messages = append(messages, fmt.Sprintf(duplicateErrorMessage, "cache", cache.Path))
+		}
+
+		paths[path] = true
+	}
+
+	return messages
+}
+
 type TaskRunConfig struct {
 	Path string   `json:"path" yaml:"path"`
 	Args []string `json:"args,omitempty" yaml:"args"`
@@ -360,6 +392,10 @@ func (output TaskOutputConfig) resolvePath() string {
 	return output.Name
 }
 
+type TaskCacheConfig struct {
+	Path string `json:"path" yaml:"path"`
+}
+
 type MetadataField struct {
 	Name  string `json:"name"`
 	Value string `json:"value"`
@@ -234,6 +234,50 @@ run: {path: a/file}
 			})
 		})
 
+		Context("when the task has caches", func() {
+			BeforeEach(func() {
+				validConfig.Caches = append(validConfig.Caches, TaskCacheConfig{Path: "node_modules"}, TaskCacheConfig{Path: "some/.m2"})
+			})
+
+			It("is valid", func() {
+				Expect(validConfig.Validate()).ToNot(HaveOccurred())
+			})
+
+			Context("when cache.path is missing", func() {
+				BeforeEach(func() {
+					invalidConfig.Caches = append(invalidConfig.Caches, TaskCacheConfig{Path: "node_modules"}, TaskCacheConfig{})
+				})
+
+				It("returns an error", func() {
+					Expect(invalidConfig.Validate()).To(MatchError(ContainSubstring("  cache in position 1 is missing a path")))
+				})
+			})
+
+			for _, path := range []string{"/tmp/cache", ".", "./", "../cache", "some/../../cache"} {
+				path := path
+
+				Context("when cache.path is "+path, func() {
+					BeforeEach(func() {
+						invalidConfig.Caches = append(invalidConfig.Caches, TaskCacheConfig{Path: path})
+					})
+
+					It("returns an error", func() {
+						Expect(invalidConfig.Validate()).To(MatchError(ContainSubstring("  cache path '" + path + "' must be a directory within the working directory")))
+					})
+				})
+			}
+
+			Context("when two caches have the same path", func() {
+				BeforeEach(func() {
+					invalidConfig.Caches = append(invalidConfig.Caches, TaskCacheConfig{Path: "node_modules"}, TaskCacheConfig{Path: "./node_modules"})
+				})
+
+				It("returns an error", func() {
+					Expect(invalidConfig.Validate()).To(MatchError(ContainSubstring("  cannot have more than one cache using the same path './node_modules'")))
+				})
+			})
+		})
+
 		Context("when run is missing", func() {
 			BeforeEach(func() {
 				invalidConfig.Run.Path = ""
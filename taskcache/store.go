@@ -0,0 +1,129 @@
+package taskcache
+
+import (
+	"compress/gzip"
+	"io"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/storage"
+)
+
+//go:generate counterfeiter . StoreDB
+
+type StoreDB interface {
+	SaveTaskCache(cache db.TaskCache) error
+}
+
+// Store keeps task caches with a storage driver, so that a job's next build
+// can restore them on whichever worker it lands on.
+type Store struct {
+	driver storage.Driver
+	db     StoreDB
+}
+
+func NewStore(driver storage.Driver, db StoreDB) *Store {
+	return &Store{
+		driver: driver,
+		db:     db,
+	}
+}
+
+// Restore returns the tar stream the cache was last saved from, or false if
+// it's never been saved or has been invalidated since.
+func (store *Store) Restore(logger lager.Logger, cache db.TaskCache) (io.ReadCloser, bool, error) {
+	stored, found, err := store.driver.Get(cache.StorageKey())
+	if err != nil {
+		logger.Error("failed-to-get-cache", err, lager.Data{"path": cache.Path})
+		return nil, false, err
+	}
+
+	if !found {
+		return nil, false, nil
+	}
+
+	tarStream, err := gzip.NewReader(stored)
+	if err != nil {
+		stored.Close()
+		logger.Error("failed-to-decompress-cache", err, lager.Data{"path": cache.Path})
+		return nil, false, err
+	}
+
+	return gzipReadCloser{Reader: tarStream, stored: stored}, true, nil
+}
+
+// Save compresses the tar stream and stores it in place of whatever the cache
+// was saved from before.
+func (store *Store) Save(logger lager.Logger, cache db.TaskCache, tarStream io.Reader) error {
+	compressedR, compressedW := io.Pipe()
+
+	go func() {
+		gzipW := gzip.NewWriter(compressedW)
+
+		_, err := io.Copy(gzipW, tarStream)
+		if err == nil {
+			err = gzipW.Close()
+		}
+
+		compressedW.CloseWithError(err)
+	}()
+
+	counter := &countingReader{Reader: compressedR}
+
+	err := store.driver.Put(cache.StorageKey(), counter)
+
+	// unblock the compressor if the driver gave up part way through
+	compressedR.Close()
+
+	if err != nil {
+		logger.Error("failed-to-put-cache", err, lager.Data{"path": cache.Path})
+		return err
+	}
+
+	cache.SizeBytes = counter.count
+
+	err = store.db.SaveTaskCache(cache)
+	if err != nil {
+		logger.Error("failed-to-save-cache", err, lager.Data{"path": cache.Path})
+		return err
+	}
+
+	logger.Debug("saved", lager.Data{"path": cache.Path, "size": cache.SizeBytes})
+
+	return nil
+}
+
+// Invalidate deletes the caches from storage, so that they're not restored
+// again. They should already have been deleted from the database.
+func (store *Store) Invalidate(logger lager.Logger, caches []db.TaskCache) error {
+	for _, cache := range caches {
+		err := store.driver.Delete(cache.StorageKey())
+		if err != nil {
+			logger.Error("failed-to-delete-cache", err, lager.Data{"path": cache.Path})
+			return err
+		}
+	}
+
+	return nil
+}
+
+type gzipReadCloser struct {
+	*gzip.Reader
+	stored io.Closer
+}
+
+func (reader gzipReadCloser) Close() error {
+	reader.Reader.Close()
+	return reader.stored.Close()
+}
+
+type countingReader struct {
+	io.Reader
+	count int64
+}
+
+func (reader *countingReader) Read(p []byte) (int, error) {
+	n, err := reader.Reader.Read(p)
+	reader.count += int64(n)
+	return n, err
+}
@@ -0,0 +1,164 @@
+package taskcache_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"io/ioutil"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/storage/storagefakes"
+	. "github.com/concourse/atc/taskcache"
+	"github.com/concourse/atc/taskcache/taskcachefakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Store", func() {
+	var (
+		fakeDriver *storagefakes.FakeDriver
+		fakeDB     *taskcachefakes.FakeStoreDB
+
+		store *Store
+
+		cache db.TaskCache
+	)
+
+	BeforeEach(func() {
+		fakeDriver = new(storagefakes.FakeDriver)
+		fakeDB = new(taskcachefakes.FakeStoreDB)
+
+		store = NewStore(fakeDriver, fakeDB)
+
+		cache = db.TaskCache{
+			PipelineID: 42,
+			JobName:    "some-job",
+			Path:       "some/path",
+		}
+	})
+
+	compress := func(contents string) []byte {
+		compressed := new(bytes.Buffer)
+
+		gzipW := gzip.NewWriter(compressed)
+		_, err := gzipW.Write([]byte(contents))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(gzipW.Close()).To(Succeed())
+
+		return compressed.Bytes()
+	}
+
+	Describe("Save", func() {
+		var stored []byte
+		var saveErr error
+
+		BeforeEach(func() {
+			stored = nil
+
+			fakeDriver.PutStub = func(key string, contents io.Reader) error {
+				var err error
+				stored, err = ioutil.ReadAll(contents)
+				return err
+			}
+		})
+
+		JustBeforeEach(func() {
+			saveErr = store.Save(lagertest.NewTestLogger("test"), cache, bytes.NewBufferString("some-tar-stream"))
+		})
+
+		It("stores the tar stream compressed, under the cache's key", func() {
+			Expect(saveErr).NotTo(HaveOccurred())
+
+			Expect(fakeDriver.PutCallCount()).To(Equal(1))
+			key, _ := fakeDriver.PutArgsForCall(0)
+			Expect(key).To(Equal(cache.StorageKey()))
+
+			gzipR, err := gzip.NewReader(bytes.NewReader(stored))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ioutil.ReadAll(gzipR)).To(Equal([]byte("some-tar-stream")))
+		})
+
+		It("records the cache with its compressed size", func() {
+			Expect(fakeDB.SaveTaskCacheCallCount()).To(Equal(1))
+
+			saved := fakeDB.SaveTaskCacheArgsForCall(0)
+			Expect(saved.PipelineID).To(Equal(42))
+			Expect(saved.JobName).To(Equal("some-job"))
+			Expect(saved.Path).To(Equal("some/path"))
+			Expect(saved.SizeBytes).To(Equal(int64(len(stored))))
+		})
+
+		Context("when storing it fails", func() {
+			disaster := errors.New("nope")
+
+			BeforeEach(func() {
+				fakeDriver.PutStub = nil
+				fakeDriver.PutReturns(disaster)
+			})
+
+			It("returns the error without recording the cache", func() {
+				Expect(saveErr).To(Equal(disaster))
+				Expect(fakeDB.SaveTaskCacheCallCount()).To(BeZero())
+			})
+		})
+	})
+
+	Describe("Restore", func() {
+		Context("when the cache has been stored", func() {
+			BeforeEach(func() {
+				fakeDriver.GetReturns(ioutil.NopCloser(bytes.NewReader(compress("some-tar-stream"))), true, nil)
+			})
+
+			It("returns the tar stream it was saved from", func() {
+				tarStream, found, err := store.Restore(lagertest.NewTestLogger("test"), cache)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+
+				Expect(ioutil.ReadAll(tarStream)).To(Equal([]byte("some-tar-stream")))
+				Expect(tarStream.Close()).To(Succeed())
+
+				Expect(fakeDriver.GetArgsForCall(0)).To(Equal(cache.StorageKey()))
+			})
+		})
+
+		Context("when it hasn't", func() {
+			BeforeEach(func() {
+				fakeDriver.GetReturns(nil, false, nil)
+			})
+
+			It("returns false", func() {
+				_, found, err := store.Restore(lagertest.NewTestLogger("test"), cache)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeFalse())
+			})
+		})
+
+		Context("when what's stored isn't compressed", func() {
+			BeforeEach(func() {
+				fakeDriver.GetReturns(ioutil.NopCloser(bytes.NewBufferString("bogus")), true, nil)
+			})
+
+			It("returns an error", func() {
+				_, _, err := store.Restore(lagertest.NewTestLogger("test"), cache)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("Invalidate", func() {
+		It("deletes each cache from storage", func() {
+			other := cache
+			other.Path = "other/path"
+
+			err := store.Invalidate(lagertest.NewTestLogger("test"), []db.TaskCache{cache, other})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeDriver.DeleteCallCount()).To(Equal(2))
+			Expect(fakeDriver.DeleteArgsForCall(0)).To(Equal(cache.StorageKey()))
+			Expect(fakeDriver.DeleteArgsForCall(1)).To(Equal(other.StorageKey()))
+		})
+	})
+})
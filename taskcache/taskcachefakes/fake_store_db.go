@@ -0,0 +1,77 @@
+// This file was generated by counterfeiter
+package taskcachefakes
+
+import (
+	"sync"
+
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/taskcache"
+)
+
+type FakeStoreDB struct {
+	SaveTaskCacheStub        func(cache db.TaskCache) error
+	saveTaskCacheMutex       sync.RWMutex
+	saveTaskCacheArgsForCall []struct {
+		cache db.TaskCache
+	}
+	saveTaskCacheReturns struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeStoreDB) SaveTaskCache(cache db.TaskCache) error {
+	fake.saveTaskCacheMutex.Lock()
+	fake.saveTaskCacheArgsForCall = append(fake.saveTaskCacheArgsForCall, struct {
+		cache db.TaskCache
+	}{cache})
+	fake.recordInvocation("SaveTaskCache", []interface{}{cache})
+	fake.saveTaskCacheMutex.Unlock()
+	if fake.SaveTaskCacheStub != nil {
+		return fake.SaveTaskCacheStub(cache)
+	} else {
+		return fake.saveTaskCacheReturns.result1
+	}
+}
+
+func (fake *FakeStoreDB) SaveTaskCacheCallCount() int {
+	fake.saveTaskCacheMutex.RLock()
+	defer fake.saveTaskCacheMutex.RUnlock()
+	return len(fake.saveTaskCacheArgsForCall)
+}
+
+func (fake *FakeStoreDB) SaveTaskCacheArgsForCall(i int) db.TaskCache {
+	fake.saveTaskCacheMutex.RLock()
+	defer fake.saveTaskCacheMutex.RUnlock()
+	return fake.saveTaskCacheArgsForCall[i].cache
+}
+
+func (fake *FakeStoreDB) SaveTaskCacheReturns(result1 error) {
+	fake.SaveTaskCacheStub = nil
+	fake.saveTaskCacheReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStoreDB) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.saveTaskCacheMutex.RLock()
+	defer fake.saveTaskCacheMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeStoreDB) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ taskcache.StoreDB = new(FakeStoreDB)
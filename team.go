@@ -12,6 +12,11 @@ type Team struct {
 	GitHubAuth   *GitHubAuth   `json:"github_auth,omitempty"`
 	UAAAuth      *UAAAuth      `json:"uaa_auth,omitempty"`
 	GenericOAuth *GenericOAuth `json:"genericoauth_auth,omitempty"`
+	OIDCAuth     *OIDCAuth     `json:"oidc_auth,omitempty"`
+	SAMLAuth     *SAMLAuth     `json:"saml_auth,omitempty"`
+
+	// Roles sets what those who log in through each auth method may do
+	Roles *TeamRoles `json:"roles,omitempty"`
 }
 
 type BasicAuth struct {
@@ -53,3 +58,30 @@ type GenericOAuth struct {
 	TokenURL      string            `json:"token_url,omitempty"`
 	AuthURLParams map[string]string `json:"auth_url_params,omitempty"`
 }
+
+// OIDCAuth logs in through an OpenID Connect provider, e.g. Google or Dex.
+// Those in any of the Groups, or whose verified email is one of the Users,
+// may log in.
+type OIDCAuth struct {
+	DisplayName  string   `json:"display_name,omitempty"`
+	Issuer       string   `json:"issuer,omitempty"`
+	ClientID     string   `json:"client_id,omitempty"`
+	ClientSecret string   `json:"client_secret,omitempty"`
+	Scopes       []string `json:"scopes,omitempty"`
+	GroupsClaim  string   `json:"groups_claim,omitempty"`
+	Groups       []string `json:"groups,omitempty"`
+	Users        []string `json:"users,omitempty"`
+}
+
+// SAMLAuth logs in through a SAML 2.0 identity provider, e.g. ADFS or Okta.
+// Those whose name ID is one of the Users, or whose GroupsAttribute lists
+// any of the Groups, may log in.
+type SAMLAuth struct {
+	DisplayName     string   `json:"display_name,omitempty"`
+	SSOURL          string   `json:"sso_url,omitempty"`
+	IdPEntityID     string   `json:"idp_entity_id,omitempty"`
+	IdPCertificate  string   `json:"idp_certificate,omitempty"`
+	GroupsAttribute string   `json:"groups_attribute,omitempty"`
+	Groups          []string `json:"groups,omitempty"`
+	Users           []string `json:"users,omitempty"`
+}
@@ -0,0 +1,409 @@
+// Package fakebuilder runs builds without any workers, for end-to-end tests
+// of the ATC that shouldn't depend on containers or real resources.
+//
+// Every step happens straight away. Gets fetch the version they were given,
+// or else whatever the build last put to the resource; puts create a version
+// named after the build and step; tasks exit with the status in their
+// ExitStatusParam, if they have one. Approvals are still waited on, so that
+// they can be decided through the API as usual.
+package fakebuilder
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/engine"
+	"github.com/concourse/atc/event"
+)
+
+// EngineName is the engine that builds run by the fake builder are started
+// with, so that they're never resumed by an ATC running them for real.
+const EngineName = "fake"
+
+// ExitStatusParam is the task param whose value fake tasks exit with.
+const ExitStatusParam = "FAKE_EXIT_STATUS"
+
+var errAborted = errors.New("aborted")
+
+type fakeEngine struct {
+	clock clock.Clock
+}
+
+// NewEngine returns an engine that runs builds with the fake builder, using
+// the clock for the times in their events.
+func NewEngine(clock clock.Clock) engine.Engine {
+	return &fakeEngine{clock: clock}
+}
+
+func (*fakeEngine) Name() string {
+	return EngineName
+}
+
+func (fake *fakeEngine) CreateBuild(logger lager.Logger, build db.Build, plan atc.Plan) (engine.Build, error) {
+	return fake.newBuild(build, metadata{Plan: plan}), nil
+}
+
+func (fake *fakeEngine) LookupBuild(logger lager.Logger, build db.Build) (engine.Build, error) {
+	var md metadata
+	err := json.Unmarshal([]byte(build.EngineMetadata()), &md)
+	if err != nil {
+		logger.Error("invalid-metadata", err)
+		return nil, err
+	}
+
+	return fake.newBuild(build, md), nil
+}
+
+func (fake *fakeEngine) newBuild(build db.Build, md metadata) *fakeBuild {
+	return &fakeBuild{
+		build:    build,
+		metadata: md,
+		clock:    fake.clock,
+
+		puts: map[string]atc.Version{},
+
+		aborted:  make(chan struct{}),
+		released: make(chan struct{}),
+	}
+}
+
+type metadata struct {
+	Plan atc.Plan `json:"plan"`
+}
+
+type fakeBuild struct {
+	build    db.Build
+	metadata metadata
+	clock    clock.Clock
+
+	// the versions put so far, by resource, for the gets that follow them
+	puts map[string]atc.Version
+
+	aborted   chan struct{}
+	abortOnce sync.Once
+
+	released    chan struct{}
+	releaseOnce sync.Once
+}
+
+func (build *fakeBuild) Metadata() string {
+	payload, err := json.Marshal(build.metadata)
+	if err != nil {
+		panic("failed to marshal build metadata: " + err.Error())
+	}
+
+	return string(payload)
+}
+
+func (build *fakeBuild) PublicPlan(lager.Logger) (atc.PublicBuildPlan, error) {
+	return atc.PublicBuildPlan{
+		Schema: EngineName,
+		Plan:   build.metadata.Plan.Public(),
+	}, nil
+}
+
+func (build *fakeBuild) Plan(lager.Logger) (atc.Plan, error) {
+	return build.metadata.Plan, nil
+}
+
+func (build *fakeBuild) Abort(lager.Logger) error {
+	build.abortOnce.Do(func() {
+		close(build.aborted)
+	})
+	return nil
+}
+
+func (build *fakeBuild) Release(lager.Logger) {
+	build.releaseOnce.Do(func() {
+		close(build.released)
+	})
+}
+
+func (build *fakeBuild) Resume(logger lager.Logger) {
+	succeeded, err := build.run(logger, build.metadata.Plan)
+
+	select {
+	case <-build.released:
+		logger.Info("released")
+		return
+	default:
+	}
+
+	status := db.StatusFailed
+	if err == errAborted {
+		status = db.StatusAborted
+	} else if err != nil {
+		logger.Error("failed-to-run-build", err)
+		status = db.StatusErrored
+
+		err = build.build.SaveEvent(event.Error{Message: err.Error()})
+		if err != nil {
+			logger.Error("failed-to-save-error-event", err)
+		}
+	} else if succeeded {
+		status = db.StatusSucceeded
+	}
+
+	err = build.build.Finish(status)
+	if err != nil {
+		logger.Error("failed-to-finish-build", err)
+	}
+}
+
+// run runs the plan, returning whether it succeeded, or an error if it
+// couldn't be run at all.
+func (build *fakeBuild) run(logger lager.Logger, plan atc.Plan) (bool, error) {
+	select {
+	case <-build.aborted:
+		return false, errAborted
+	case <-build.released:
+		return false, errAborted
+	default:
+	}
+
+	switch {
+	case plan.Aggregate != nil:
+		return build.runAll(logger, *plan.Aggregate)
+
+	case plan.Do != nil:
+		return build.runAll(logger, *plan.Do)
+
+	case plan.Timeout != nil:
+		return build.run(logger, plan.Timeout.Step)
+
+	case plan.Try != nil:
+		_, err := build.run(logger, plan.Try.Step)
+		return err == nil, err
+
+	case plan.OnSuccess != nil:
+		succeeded, err := build.run(logger, plan.OnSuccess.Step)
+		if err != nil || !succeeded {
+			return succeeded, err
+		}
+
+		return build.run(logger, plan.OnSuccess.Next)
+
+	case plan.OnFailure != nil:
+		succeeded, err := build.run(logger, plan.OnFailure.Step)
+		if err != nil || succeeded {
+			return succeeded, err
+		}
+
+		_, err = build.run(logger, plan.OnFailure.Next)
+		return false, err
+
+	case plan.Ensure != nil:
+		succeeded, err := build.run(logger, plan.Ensure.Step)
+		if err != nil {
+			return false, err
+		}
+
+		nextSucceeded, err := build.run(logger, plan.Ensure.Next)
+		return succeeded && nextSucceeded, err
+
+	case plan.Retry != nil:
+		for _, attempt := range *plan.Retry {
+			succeeded, err := build.run(logger, attempt)
+			if err != nil || succeeded {
+				return succeeded, err
+			}
+		}
+
+		return false, nil
+
+	case plan.Get != nil:
+		return true, build.get(plan.ID, *plan.Get)
+
+	case plan.DependentGet != nil:
+		return true, build.get(plan.ID, plan.DependentGet.GetPlan())
+
+	case plan.Put != nil:
+		return true, build.put(plan.ID, *plan.Put)
+
+	case plan.Task != nil:
+		return build.task(plan.ID, *plan.Task)
+
+	case plan.Approval != nil:
+		return build.approval(plan.ID, *plan.Approval)
+	}
+
+	return true, nil
+}
+
+func (build *fakeBuild) runAll(logger lager.Logger, plans []atc.Plan) (bool, error) {
+	for _, plan := range plans {
+		succeeded, err := build.run(logger, plan)
+		if err != nil || !succeeded {
+			return succeeded, err
+		}
+	}
+
+	return true, nil
+}
+
+func (build *fakeBuild) get(id atc.PlanID, plan atc.GetPlan) error {
+	origin := event.Origin{ID: event.OriginID(id)}
+
+	err := build.build.SaveEvent(event.InitializeGet{Origin: origin})
+	if err != nil {
+		return err
+	}
+
+	version := plan.Version
+	if version == nil {
+		version = build.puts[plan.Resource]
+	}
+
+	if version == nil {
+		version = atc.Version{"build": strconv.Itoa(build.build.ID())}
+	}
+
+	if plan.PipelineID != 0 {
+		_, err := build.build.SaveInput(db.BuildInput{
+			Name: plan.Name,
+			VersionedResource: db.VersionedResource{
+				Resource:   plan.Resource,
+				Type:       plan.Type,
+				Version:    db.Version(version),
+				PipelineID: plan.PipelineID,
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return build.build.SaveEvent(event.FinishGet{
+		Origin: origin,
+		Plan: event.GetPlan{
+			Name:     plan.Name,
+			Resource: plan.Resource,
+			Type:     plan.Type,
+			Version:  plan.Version,
+		},
+		FetchedVersion: version,
+	})
+}
+
+func (build *fakeBuild) put(id atc.PlanID, plan atc.PutPlan) error {
+	origin := event.Origin{ID: event.OriginID(id)}
+
+	err := build.build.SaveEvent(event.InitializePut{Origin: origin})
+	if err != nil {
+		return err
+	}
+
+	version := atc.Version{
+		"build": strconv.Itoa(build.build.ID()),
+		"step":  string(id),
+	}
+
+	build.puts[plan.Resource] = version
+
+	err = build.build.SaveEvent(event.FinishPut{
+		Origin: origin,
+		Plan: event.PutPlan{
+			Name:     plan.Name,
+			Resource: plan.Resource,
+			Type:     plan.Type,
+		},
+		CreatedVersion: version,
+	})
+	if err != nil {
+		return err
+	}
+
+	if plan.PipelineID != 0 {
+		_, err := build.build.SaveOutput(db.VersionedResource{
+			Resource:   plan.Resource,
+			Type:       plan.Type,
+			Version:    db.Version(version),
+			PipelineID: plan.PipelineID,
+		}, true)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (build *fakeBuild) task(id atc.PlanID, plan atc.TaskPlan) (bool, error) {
+	origin := event.Origin{ID: event.OriginID(id)}
+
+	exitStatus := 0
+	if value, found := plan.Params[ExitStatusParam]; found {
+		var err error
+		exitStatus, err = strconv.Atoi(fmt.Sprint(value))
+		if err != nil {
+			return false, err
+		}
+	}
+
+	err := build.build.SaveEvent(event.StartTask{
+		Time:   build.clock.Now().Unix(),
+		Origin: origin,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	err = build.build.SaveEvent(event.Log{
+		Origin:  event.Origin{ID: origin.ID, Source: event.OriginSourceStdout},
+		Payload: "running " + plan.Name + "\n",
+	})
+	if err != nil {
+		return false, err
+	}
+
+	err = build.build.SaveEvent(event.FinishTask{
+		Time:       build.clock.Now().Unix(),
+		ExitStatus: exitStatus,
+		Origin:     origin,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return exitStatus == 0, nil
+}
+
+func (build *fakeBuild) approval(id atc.PlanID, plan atc.ApprovalPlan) (bool, error) {
+	notifier, err := build.build.ApprovalNotifier(plan.Name)
+	if err != nil {
+		return false, err
+	}
+
+	defer notifier.Close()
+
+	err = build.build.RequestApproval(id, plan.Name)
+	if err != nil {
+		return false, err
+	}
+
+	for {
+		select {
+		case <-notifier.Notify():
+		case <-build.aborted:
+			return false, errAborted
+		case <-build.released:
+			return false, errAborted
+		}
+
+		approval, found, err := build.build.GetApproval(plan.Name)
+		if err != nil {
+			return false, err
+		}
+
+		if found && approval.Status != db.ApprovalPending {
+			return approval.Status == db.ApprovalApproved, nil
+		}
+	}
+}
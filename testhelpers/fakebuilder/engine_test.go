@@ -0,0 +1,223 @@
+package fakebuilder_test
+
+import (
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager/lagertest"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/db/dbfakes"
+	"github.com/concourse/atc/engine"
+	"github.com/concourse/atc/event"
+	"github.com/concourse/atc/testhelpers/fakebuilder"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Engine", func() {
+	var (
+		logger    *lagertest.TestLogger
+		fakeClock *fakeclock.FakeClock
+		dbBuild   *dbfakes.FakeBuild
+
+		fakeEngine engine.Engine
+
+		plan atc.Plan
+	)
+
+	BeforeEach(func() {
+		logger = lagertest.NewTestLogger("test")
+		fakeClock = fakeclock.NewFakeClock(time.Unix(123456789, 0))
+
+		dbBuild = new(dbfakes.FakeBuild)
+		dbBuild.IDReturns(42)
+
+		fakeEngine = fakebuilder.NewEngine(fakeClock)
+
+		plan = atc.Plan{
+			ID: "do",
+			Do: &atc.DoPlan{
+				{
+					ID: "get",
+					Get: &atc.GetPlan{
+						Name:       "some-input",
+						Resource:   "some-resource",
+						Type:       "git",
+						PipelineID: 1,
+						Version:    atc.Version{"ref": "abc"},
+					},
+				},
+				{
+					ID: "task",
+					Task: &atc.TaskPlan{
+						Name: "some-task",
+					},
+				},
+				{
+					ID: "put",
+					Put: &atc.PutPlan{
+						Name:       "some-output",
+						Resource:   "some-output-resource",
+						Type:       "git",
+						PipelineID: 1,
+					},
+				},
+			},
+		}
+	})
+
+	savedEvents := func() []atc.Event {
+		events := []atc.Event{}
+		for i := 0; i < dbBuild.SaveEventCallCount(); i++ {
+			events = append(events, dbBuild.SaveEventArgsForCall(i))
+		}
+
+		return events
+	}
+
+	resume := func() {
+		build, err := fakeEngine.CreateBuild(logger, dbBuild, plan)
+		Expect(err).NotTo(HaveOccurred())
+
+		build.Resume(logger)
+	}
+
+	It("looks up builds from the metadata they were created with", func() {
+		build, err := fakeEngine.CreateBuild(logger, dbBuild, plan)
+		Expect(err).NotTo(HaveOccurred())
+
+		dbBuild.EngineMetadataReturns(build.Metadata())
+
+		lookedUp, err := fakeEngine.LookupBuild(logger, dbBuild)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lookedUp.Plan(logger)).To(Equal(plan))
+	})
+
+	It("runs each step and succeeds", func() {
+		resume()
+
+		Expect(dbBuild.FinishCallCount()).To(Equal(1))
+		Expect(dbBuild.FinishArgsForCall(0)).To(Equal(db.StatusSucceeded))
+	})
+
+	It("saves the version it got as an input", func() {
+		resume()
+
+		Expect(dbBuild.SaveInputCallCount()).To(Equal(1))
+		Expect(dbBuild.SaveInputArgsForCall(0)).To(Equal(db.BuildInput{
+			Name: "some-input",
+			VersionedResource: db.VersionedResource{
+				Resource:   "some-resource",
+				Type:       "git",
+				Version:    db.Version{"ref": "abc"},
+				PipelineID: 1,
+			},
+		}))
+	})
+
+	It("saves a version named after the build and step as an output", func() {
+		resume()
+
+		Expect(dbBuild.SaveOutputCallCount()).To(Equal(1))
+		vr, explicit := dbBuild.SaveOutputArgsForCall(0)
+		Expect(vr).To(Equal(db.VersionedResource{
+			Resource:   "some-output-resource",
+			Type:       "git",
+			Version:    db.Version{"build": "42", "step": "put"},
+			PipelineID: 1,
+		}))
+		Expect(explicit).To(BeTrue())
+	})
+
+	It("times the task by the clock", func() {
+		resume()
+
+		Expect(savedEvents()).To(ContainElement(event.StartTask{
+			Time:   123456789,
+			Origin: event.Origin{ID: "task"},
+		}))
+
+		Expect(savedEvents()).To(ContainElement(event.FinishTask{
+			Time:       123456789,
+			ExitStatus: 0,
+			Origin:     event.Origin{ID: "task"},
+		}))
+	})
+
+	Context("when a task is told to fail", func() {
+		BeforeEach(func() {
+			(*plan.Do)[1].Task.Params = atc.Params{fakebuilder.ExitStatusParam: 1}
+		})
+
+		It("fails the build without running the rest", func() {
+			resume()
+
+			Expect(dbBuild.SaveOutputCallCount()).To(BeZero())
+
+			Expect(dbBuild.FinishCallCount()).To(Equal(1))
+			Expect(dbBuild.FinishArgsForCall(0)).To(Equal(db.StatusFailed))
+		})
+	})
+
+	Context("when the build has been aborted", func() {
+		It("finishes it as aborted without running anything", func() {
+			build, err := fakeEngine.CreateBuild(logger, dbBuild, plan)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = build.Abort(logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			build.Resume(logger)
+
+			Expect(dbBuild.SaveEventCallCount()).To(BeZero())
+
+			Expect(dbBuild.FinishCallCount()).To(Equal(1))
+			Expect(dbBuild.FinishArgsForCall(0)).To(Equal(db.StatusAborted))
+		})
+	})
+
+	Context("when the build has been released", func() {
+		It("leaves it running", func() {
+			build, err := fakeEngine.CreateBuild(logger, dbBuild, plan)
+			Expect(err).NotTo(HaveOccurred())
+
+			build.Release(logger)
+			build.Resume(logger)
+
+			Expect(dbBuild.FinishCallCount()).To(BeZero())
+		})
+	})
+
+	Context("when waiting on an approval", func() {
+		var decided chan struct{}
+
+		BeforeEach(func() {
+			plan = atc.Plan{
+				ID:       "approval",
+				Approval: &atc.ApprovalPlan{Name: "ship-it"},
+			}
+
+			decided = make(chan struct{}, 1)
+
+			notifier := new(dbfakes.FakeNotifier)
+			notifier.NotifyReturns(decided)
+			dbBuild.ApprovalNotifierReturns(notifier, nil)
+		})
+
+		It("requests it and finishes once it's rejected", func() {
+			dbBuild.GetApprovalReturns(db.BuildApproval{Status: db.ApprovalRejected}, true, nil)
+			decided <- struct{}{}
+
+			resume()
+
+			Expect(dbBuild.RequestApprovalCallCount()).To(Equal(1))
+			planID, name := dbBuild.RequestApprovalArgsForCall(0)
+			Expect(planID).To(Equal(atc.PlanID("approval")))
+			Expect(name).To(Equal("ship-it"))
+
+			Expect(dbBuild.FinishArgsForCall(0)).To(Equal(db.StatusFailed))
+		})
+	})
+})
@@ -0,0 +1,13 @@
+package fakebuilder_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestFakeBuilder(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Fake Builder Suite")
+}
@@ -0,0 +1,61 @@
+package tracing
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/concourse/atc/db"
+)
+
+// TraceQueries makes each query given a context that's within a span its own
+// span. Queries made without one aren't traced, so that background work
+// doesn't start a trace for every query it makes.
+func TraceQueries(conn db.Conn) db.Conn {
+	return &tracingConn{
+		Conn: conn,
+	}
+}
+
+type tracingConn struct {
+	db.Conn
+}
+
+func (conn *tracingConn) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	ctx, span := startQuerySpan(ctx, query)
+
+	rows, err := conn.Conn.QueryContext(ctx, query, args...)
+	span.Finish(err)
+
+	return rows, err
+}
+
+func (conn *tracingConn) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	ctx, span := startQuerySpan(ctx, query)
+
+	// the row's error isn't known until it's scanned
+	row := conn.Conn.QueryRowContext(ctx, query, args...)
+	span.Finish(nil)
+
+	return row
+}
+
+func (conn *tracingConn) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctx, span := startQuerySpan(ctx, query)
+
+	result, err := conn.Conn.ExecContext(ctx, query, args...)
+	span.Finish(err)
+
+	return result, err
+}
+
+func startQuerySpan(ctx context.Context, query string) (context.Context, *Span) {
+	if !SpanContextFrom(ctx).IsValid() {
+		return ctx, nil
+	}
+
+	return startSpan(ctx, "db.query", SpanKindClient, Attrs{
+		"db.system":    "postgresql",
+		"db.statement": strings.Join(strings.Fields(query), " "),
+	})
+}
@@ -0,0 +1,98 @@
+package tracing
+
+import (
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+)
+
+//go:generate counterfeiter . Exporter
+
+// Exporter sends finished spans somewhere they can be looked at.
+type Exporter interface {
+	Export(spans []*Span) error
+}
+
+// BatchSize is the most spans that are exported at once.
+const BatchSize = 512
+
+var tracer struct {
+	sync.RWMutex
+
+	logger   lager.Logger
+	exporter Exporter
+	spans    chan *Span
+}
+
+// Configure exports every span finished from then on in the background, a
+// batch at a time or whatever has finished every flushInterval. Spans are
+// dropped if they're finished faster than the exporter can keep up.
+func Configure(logger lager.Logger, exporter Exporter, flushInterval time.Duration) {
+	spans := make(chan *Span, 4*BatchSize)
+
+	tracer.Lock()
+	tracer.logger = logger
+	tracer.exporter = exporter
+	tracer.spans = spans
+	tracer.Unlock()
+
+	go exportLoop(logger, exporter, spans, flushInterval)
+}
+
+// Configured returns whether spans are being recorded.
+func Configured() bool {
+	tracer.RLock()
+	defer tracer.RUnlock()
+
+	return tracer.exporter != nil
+}
+
+func export(span *Span) {
+	tracer.RLock()
+	defer tracer.RUnlock()
+
+	if tracer.spans == nil {
+		return
+	}
+
+	select {
+	case tracer.spans <- span:
+	default:
+		tracer.logger.Debug("queue-full", lager.Data{"span": span.Name})
+	}
+}
+
+func exportLoop(logger lager.Logger, exporter Exporter, spans <-chan *Span, flushInterval time.Duration) {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*Span, 0, BatchSize)
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+
+		err := exporter.Export(batch)
+		if err != nil {
+			logger.Error("failed-to-export", err, lager.Data{"spans": len(batch)})
+		}
+
+		batch = make([]*Span, 0, BatchSize)
+	}
+
+	for {
+		select {
+		case span := <-spans:
+			batch = append(batch, span)
+
+			if len(batch) == BatchSize {
+				flush()
+			}
+
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
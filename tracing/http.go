@@ -0,0 +1,127 @@
+package tracing
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+)
+
+// WrapHandler runs each request within a span named after the route, joining
+// the caller's trace if the request says it has one.
+func WrapHandler(route string, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !Configured() {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		ctx, span := startSpan(Extract(r.Context(), r.Header), route, SpanKindServer, Attrs{
+			"http.method": r.Method,
+			"http.target": r.URL.Path,
+		})
+
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		handler.ServeHTTP(recorder, r.WithContext(ctx))
+
+		span.SetAttribute("http.status_code", strconv.Itoa(recorder.status))
+
+		var err error
+		if recorder.status >= 500 {
+			err = errors.New(http.StatusText(recorder.status))
+		}
+
+		span.Finish(err)
+	})
+}
+
+// statusRecorder remembers the status written while still letting long-lived
+// handlers flush, hijack and watch for the client going away.
+type statusRecorder struct {
+	http.ResponseWriter
+
+	status int
+}
+
+func (recorder *statusRecorder) WriteHeader(status int) {
+	recorder.status = status
+	recorder.ResponseWriter.WriteHeader(status)
+}
+
+func (recorder *statusRecorder) Flush() {
+	if flusher, ok := recorder.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (recorder *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := recorder.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response cannot be hijacked")
+	}
+
+	return hijacker.Hijack()
+}
+
+func (recorder *statusRecorder) CloseNotify() <-chan bool {
+	if notifier, ok := recorder.ResponseWriter.(http.CloseNotifier); ok {
+		return notifier.CloseNotify()
+	}
+
+	return make(chan bool)
+}
+
+// Unwrap gives http.ResponseController the writer underneath.
+func (recorder *statusRecorder) Unwrap() http.ResponseWriter {
+	return recorder.ResponseWriter
+}
+
+// Transport makes each request made within a span its own span, telling the
+// server about it so that its spans join the trace. Requests made outside of
+// a span go through as they are.
+type Transport struct {
+	Base http.RoundTripper
+}
+
+func (transport *Transport) RoundTrip(request *http.Request) (*http.Response, error) {
+	base := transport.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	if !SpanContextFrom(request.Context()).IsValid() {
+		return base.RoundTrip(request)
+	}
+
+	ctx, span := startSpan(request.Context(), "HTTP "+request.Method, SpanKindClient, Attrs{
+		"http.method": request.Method,
+		"http.url":    request.URL.Scheme + "://" + request.URL.Host + request.URL.Path,
+	})
+
+	// round trippers mustn't modify the request they're given
+	traced := request.WithContext(ctx)
+	traced.Header = make(http.Header, len(request.Header)+1)
+	for name, values := range request.Header {
+		traced.Header[name] = values
+	}
+
+	Inject(ctx, traced.Header)
+
+	response, err := base.RoundTrip(traced)
+	if err != nil {
+		span.Finish(err)
+		return nil, err
+	}
+
+	span.SetAttribute("http.status_code", strconv.Itoa(response.StatusCode))
+
+	if response.StatusCode >= 500 {
+		err = errors.New(http.StatusText(response.StatusCode))
+	}
+
+	span.Finish(err)
+
+	return response, nil
+}
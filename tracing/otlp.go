@@ -0,0 +1,174 @@
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type otlpExporter struct {
+	url      string
+	headers  map[string]string
+	resource Attrs
+
+	client *http.Client
+}
+
+// NewOTLPExporter sends spans to an OpenTelemetry collector, or anything else
+// that speaks OTLP over HTTP such as Jaeger, e.g. at
+// http://localhost:4318. The resource attributes describe what the spans
+// came from, e.g. service.name.
+func NewOTLPExporter(endpoint string, headers map[string]string, resource Attrs) Exporter {
+	return &otlpExporter{
+		url:      strings.TrimRight(endpoint, "/") + "/v1/traces",
+		headers:  headers,
+		resource: resource,
+
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              SpanKind        `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+const otlpStatusError = 2
+
+func (exporter *otlpExporter) Export(spans []*Span) error {
+	scope := otlpScopeSpans{
+		Scope: otlpScope{Name: "github.com/concourse/atc"},
+		Spans: make([]otlpSpan, len(spans)),
+	}
+
+	for i, span := range spans {
+		presented := otlpSpan{
+			TraceID:           span.Context.TraceID.String(),
+			SpanID:            span.Context.SpanID.String(),
+			Name:              span.Name,
+			Kind:              span.Kind,
+			StartTimeUnixNano: strconv.FormatInt(span.Start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(span.End.UnixNano(), 10),
+			Attributes:        otlpAttributes(span.Attributes),
+		}
+
+		if span.ParentID != (SpanID{}) {
+			presented.ParentSpanID = span.ParentID.String()
+		}
+
+		if span.Err != nil {
+			presented.Status = otlpStatus{
+				Code:    otlpStatusError,
+				Message: span.Err.Error(),
+			}
+		}
+
+		scope.Spans[i] = presented
+	}
+
+	payload, err := json.Marshal(otlpRequest{
+		ResourceSpans: []otlpResourceSpans{
+			{
+				Resource:   otlpResource{Attributes: otlpAttributes(exporter.resource)},
+				ScopeSpans: []otlpScopeSpans{scope},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequest("POST", exporter.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+
+	for name, value := range exporter.headers {
+		request.Header.Set(name, value)
+	}
+
+	response, err := exporter.client.Do(request)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		body, _ := ioutil.ReadAll(io.LimitReader(response.Body, 1024))
+		return fmt.Errorf("collector returned %d: %s", response.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return nil
+}
+
+func otlpAttributes(attrs Attrs) []otlpAttribute {
+	keys := make([]string, 0, len(attrs))
+	for key := range attrs {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	presented := make([]otlpAttribute, len(keys))
+	for i, key := range keys {
+		presented[i] = otlpAttribute{
+			Key:   key,
+			Value: otlpValue{StringValue: attrs[key]},
+		}
+	}
+
+	return presented
+}
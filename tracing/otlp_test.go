@@ -0,0 +1,94 @@
+package tracing_test
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+
+	"github.com/concourse/atc/tracing"
+)
+
+var _ = Describe("OTLP exporter", func() {
+	var (
+		collector *ghttp.Server
+		exporter  tracing.Exporter
+
+		span *tracing.Span
+	)
+
+	BeforeEach(func() {
+		collector = ghttp.NewServer()
+
+		exporter = tracing.NewOTLPExporter(collector.URL()+"/", map[string]string{"Authorization": "Bearer some-token"}, tracing.Attrs{
+			"service.name": "atc",
+		})
+
+		span = &tracing.Span{
+			Name:  "some-span",
+			Kind:  tracing.SpanKindServer,
+			Start: time.Unix(0, 1000),
+			End:   time.Unix(0, 2000),
+			Attributes: tracing.Attrs{
+				"b": "2",
+				"a": "1",
+			},
+			Err: errors.New("nope"),
+		}
+
+		span.Context.TraceID[0] = 0x0a
+		span.Context.SpanID[7] = 0x01
+		span.ParentID[7] = 0x02
+	})
+
+	AfterEach(func() {
+		collector.Close()
+	})
+
+	It("posts the spans as OTLP JSON", func() {
+		collector.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest("POST", "/v1/traces"),
+			ghttp.VerifyHeaderKV("Content-Type", "application/json"),
+			ghttp.VerifyHeaderKV("Authorization", "Bearer some-token"),
+			ghttp.VerifyJSON(`{
+				"resourceSpans": [{
+					"resource": {
+						"attributes": [{"key": "service.name", "value": {"stringValue": "atc"}}]
+					},
+					"scopeSpans": [{
+						"scope": {"name": "github.com/concourse/atc"},
+						"spans": [{
+							"traceId": "0a000000000000000000000000000000",
+							"spanId": "0000000000000001",
+							"parentSpanId": "0000000000000002",
+							"name": "some-span",
+							"kind": 2,
+							"startTimeUnixNano": "1000",
+							"endTimeUnixNano": "2000",
+							"attributes": [
+								{"key": "a", "value": {"stringValue": "1"}},
+								{"key": "b", "value": {"stringValue": "2"}}
+							],
+							"status": {"code": 2, "message": "nope"}
+						}]
+					}]
+				}]
+			}`),
+		))
+
+		err := exporter.Export([]*tracing.Span{span})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(collector.ReceivedRequests()).To(HaveLen(1))
+	})
+
+	It("returns an error if the collector turns the spans away", func() {
+		collector.AppendHandlers(ghttp.RespondWith(http.StatusBadRequest, "bad spans"))
+
+		err := exporter.Export([]*tracing.Span{span})
+		Expect(err).To(MatchError("collector returned 400: bad spans"))
+	})
+})
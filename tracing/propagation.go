@@ -0,0 +1,63 @@
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// TraceParentHeader carries the span a request was made within, as described
+// by https://www.w3.org/TR/trace-context/.
+const TraceParentHeader = "traceparent"
+
+// Inject sets the header to continue the context's trace, if it has one.
+func Inject(ctx context.Context, header http.Header) {
+	sc := SpanContextFrom(ctx)
+	if !sc.IsValid() {
+		return
+	}
+
+	header.Set(TraceParentHeader, fmt.Sprintf("00-%s-%s-01", sc.TraceID, sc.SpanID))
+}
+
+// Extract returns a context that continues the trace in the header, or the
+// context as it is if the header is missing or malformed.
+func Extract(ctx context.Context, header http.Header) context.Context {
+	sc, ok := parseTraceParent(header.Get(TraceParentHeader))
+	if !ok {
+		return ctx
+	}
+
+	return ContextWithSpanContext(ctx, sc)
+}
+
+func parseTraceParent(value string) (SpanContext, bool) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" {
+		return SpanContext{}, false
+	}
+
+	// later versions may add fields, but must keep these ones where they are
+	if parts[0] == "00" && len(parts) != 4 {
+		return SpanContext{}, false
+	}
+
+	var sc SpanContext
+
+	traceID, err := hex.DecodeString(parts[1])
+	if err != nil || len(traceID) != len(sc.TraceID) {
+		return SpanContext{}, false
+	}
+
+	spanID, err := hex.DecodeString(parts[2])
+	if err != nil || len(spanID) != len(sc.SpanID) {
+		return SpanContext{}, false
+	}
+
+	copy(sc.TraceID[:], traceID)
+	copy(sc.SpanID[:], spanID)
+
+	return sc, sc.IsValid()
+}
@@ -0,0 +1,156 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+type TraceID [16]byte
+
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+type SpanID [8]byte
+
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// SpanContext identifies a span, and is what's passed along to other
+// processes so that their spans join the same trace.
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+}
+
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID != TraceID{} && sc.SpanID != SpanID{}
+}
+
+type SpanKind int
+
+// These match the kinds in the OpenTelemetry protocol.
+const (
+	SpanKindInternal SpanKind = 1
+	SpanKindServer   SpanKind = 2
+	SpanKindClient   SpanKind = 3
+)
+
+type Attrs map[string]string
+
+// Span is an operation within a trace. Its fields should not be modified
+// directly; once it has ended it is handed to the exporter as it is.
+type Span struct {
+	Name     string
+	Kind     SpanKind
+	Context  SpanContext
+	ParentID SpanID
+
+	Start time.Time
+	End   time.Time
+
+	Attributes Attrs
+	Err        error
+
+	lock  sync.Mutex
+	ended bool
+}
+
+type contextKey struct{}
+
+// StartSpan starts a span within whichever one is in the context, or a new
+// trace if there isn't one. If tracing hasn't been configured the span is
+// nil, which is safe to use but records nothing.
+func StartSpan(ctx context.Context, name string, attrs Attrs) (context.Context, *Span) {
+	return startSpan(ctx, name, SpanKindInternal, attrs)
+}
+
+func startSpan(ctx context.Context, name string, kind SpanKind, attrs Attrs) (context.Context, *Span) {
+	if !Configured() {
+		return ctx, nil
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	span := &Span{
+		Name:       name,
+		Kind:       kind,
+		Start:      time.Now(),
+		Attributes: Attrs{},
+	}
+
+	for k, v := range attrs {
+		span.Attributes[k] = v
+	}
+
+	parent := SpanContextFrom(ctx)
+	if parent.IsValid() {
+		span.Context.TraceID = parent.TraceID
+		span.ParentID = parent.SpanID
+	} else {
+		rand.Read(span.Context.TraceID[:])
+	}
+
+	rand.Read(span.Context.SpanID[:])
+
+	return ContextWithSpanContext(ctx, span.Context), span
+}
+
+// SpanContextFrom returns the span context that new spans in the context
+// would be started within; it's invalid if there isn't one.
+func SpanContextFrom(ctx context.Context) SpanContext {
+	if ctx == nil {
+		return SpanContext{}
+	}
+
+	sc, _ := ctx.Value(contextKey{}).(SpanContext)
+	return sc
+}
+
+// ContextWithSpanContext has spans started in the returned context join the
+// given span, e.g. one started by another process.
+func ContextWithSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, contextKey{}, sc)
+}
+
+func (span *Span) SetAttribute(key string, value string) {
+	if span == nil {
+		return
+	}
+
+	span.lock.Lock()
+	defer span.lock.Unlock()
+
+	if !span.ended {
+		span.Attributes[key] = value
+	}
+}
+
+// Finish ends the span, recording the error it failed with if it's not nil,
+// and queues it to be exported. Only the first call has any effect.
+func (span *Span) Finish(err error) {
+	if span == nil {
+		return
+	}
+
+	span.lock.Lock()
+
+	if span.ended {
+		span.lock.Unlock()
+		return
+	}
+
+	span.ended = true
+	span.End = time.Now()
+	span.Err = err
+
+	span.lock.Unlock()
+
+	export(span)
+}
@@ -0,0 +1,192 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/atc/tracing"
+	"github.com/concourse/atc/tracing/tracingfakes"
+)
+
+var _ = Describe("Tracing", func() {
+	var (
+		fakeExporter *tracingfakes.FakeExporter
+
+		exportedLock sync.Mutex
+		exported     []*tracing.Span
+	)
+
+	exportedSpans := func() []*tracing.Span {
+		exportedLock.Lock()
+		defer exportedLock.Unlock()
+
+		return exported
+	}
+
+	BeforeEach(func() {
+		exported = nil
+
+		fakeExporter = new(tracingfakes.FakeExporter)
+		fakeExporter.ExportStub = func(spans []*tracing.Span) error {
+			exportedLock.Lock()
+			exported = append(exported, spans...)
+			exportedLock.Unlock()
+			return nil
+		}
+
+		tracing.Configure(lagertest.NewTestLogger("test"), fakeExporter, 10*time.Millisecond)
+	})
+
+	Describe("spans", func() {
+		It("are exported once they finish, within whichever span they were started in", func() {
+			ctx, parent := tracing.StartSpan(context.Background(), "parent", tracing.Attrs{"a": "b"})
+			_, child := tracing.StartSpan(ctx, "child", nil)
+
+			child.SetAttribute("c", "d")
+			child.Finish(errors.New("nope"))
+
+			Eventually(exportedSpans).Should(HaveLen(1))
+			Consistently(exportedSpans).Should(HaveLen(1))
+
+			parent.Finish(nil)
+			parent.Finish(errors.New("finished twice"))
+
+			Eventually(exportedSpans).Should(HaveLen(2))
+			Consistently(exportedSpans).Should(HaveLen(2))
+
+			spans := exportedSpans()
+			Expect(spans[0].Name).To(Equal("child"))
+			Expect(spans[0].Attributes).To(Equal(tracing.Attrs{"c": "d"}))
+			Expect(spans[0].Err).To(MatchError("nope"))
+			Expect(spans[0].Context.TraceID).To(Equal(parent.Context.TraceID))
+			Expect(spans[0].ParentID).To(Equal(parent.Context.SpanID))
+
+			Expect(spans[1].Name).To(Equal("parent"))
+			Expect(spans[1].Attributes).To(Equal(tracing.Attrs{"a": "b"}))
+			Expect(spans[1].Err).To(BeNil())
+			Expect(spans[1].ParentID).To(BeZero())
+			Expect(spans[1].End).To(BeTemporally(">=", spans[1].Start))
+		})
+	})
+
+	Describe("propagation", func() {
+		It("carries the span context in a traceparent header", func() {
+			ctx, span := tracing.StartSpan(context.Background(), "some-span", nil)
+
+			header := http.Header{}
+			tracing.Inject(ctx, header)
+
+			Expect(header.Get("traceparent")).To(Equal("00-" + span.Context.TraceID.String() + "-" + span.Context.SpanID.String() + "-01"))
+
+			extracted := tracing.SpanContextFrom(tracing.Extract(context.Background(), header))
+			Expect(extracted).To(Equal(span.Context))
+		})
+
+		It("ignores malformed headers", func() {
+			for _, value := range []string{
+				"",
+				"00-abc-def-01",
+				"00-00000000000000000000000000000000-0000000000000000-01",
+				"ff-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+				"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01-extra",
+			} {
+				header := http.Header{"Traceparent": {value}}
+				Expect(tracing.SpanContextFrom(tracing.Extract(context.Background(), header)).IsValid()).To(BeFalse(), value)
+			}
+		})
+	})
+
+	Describe("WrapHandler", func() {
+		It("traces requests, joining the caller's trace", func() {
+			handler := tracing.WrapHandler("SomeRoute", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(tracing.SpanContextFrom(r.Context()).IsValid()).To(BeTrue())
+				w.WriteHeader(http.StatusInternalServerError)
+			}))
+
+			request, err := http.NewRequest("GET", "/api/v1/some-route", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			request.Header.Set("traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+
+			Eventually(exportedSpans).Should(HaveLen(1))
+
+			span := exportedSpans()[0]
+			Expect(span.Name).To(Equal("SomeRoute"))
+			Expect(span.Kind).To(Equal(tracing.SpanKindServer))
+			Expect(span.Context.TraceID.String()).To(Equal("0af7651916cd43dd8448eb211c80319c"))
+			Expect(span.ParentID.String()).To(Equal("b7ad6b7169203331"))
+			Expect(span.Attributes).To(HaveKeyWithValue("http.method", "GET"))
+			Expect(span.Attributes).To(HaveKeyWithValue("http.target", "/api/v1/some-route"))
+			Expect(span.Attributes).To(HaveKeyWithValue("http.status_code", "500"))
+			Expect(span.Err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Transport", func() {
+		var (
+			server *httptest.Server
+
+			receivedTraceParent chan string
+		)
+
+		BeforeEach(func() {
+			receivedTraceParent = make(chan string, 1)
+
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				receivedTraceParent <- r.Header.Get("traceparent")
+			}))
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("traces requests made within a span, and tells the server about it", func() {
+			ctx, parent := tracing.StartSpan(context.Background(), "parent", nil)
+
+			request, err := http.NewRequest("GET", server.URL+"/some/path", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			client := &http.Client{Transport: &tracing.Transport{}}
+
+			response, err := client.Do(request.WithContext(ctx))
+			Expect(err).NotTo(HaveOccurred())
+			response.Body.Close()
+
+			Expect(request.Header.Get("traceparent")).To(BeEmpty())
+
+			Eventually(exportedSpans).Should(HaveLen(1))
+
+			span := exportedSpans()[0]
+			Expect(span.Kind).To(Equal(tracing.SpanKindClient))
+			Expect(span.ParentID).To(Equal(parent.Context.SpanID))
+			Expect(span.Attributes).To(HaveKeyWithValue("http.status_code", "200"))
+
+			Expect(<-receivedTraceParent).To(Equal("00-" + span.Context.TraceID.String() + "-" + span.Context.SpanID.String() + "-01"))
+		})
+
+		It("leaves requests made outside of a span alone", func() {
+			client := &http.Client{Transport: &tracing.Transport{}}
+
+			response, err := client.Get(server.URL)
+			Expect(err).NotTo(HaveOccurred())
+			response.Body.Close()
+
+			Expect(<-receivedTraceParent).To(BeEmpty())
+			Consistently(exportedSpans).Should(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,19 @@
+package atc
+
+// Usage is what the builds of a pipeline, or a team's one-off builds, used
+// over the interval beginning at Start. Times are in seconds; WorkerSeconds is
+// the time spent running steps in containers, so that steps run in parallel
+// are each counted, and memory is the peak used by each build held for as
+// long as it ran.
+type Usage struct {
+	TeamName     string `json:"team_name"`
+	PipelineName string `json:"pipeline_name,omitempty"`
+	Start        int64  `json:"start"`
+
+	Builds        int   `json:"builds"`
+	BuildSeconds  int64 `json:"build_seconds"`
+	WorkerSeconds int64 `json:"worker_seconds"`
+	CPUSeconds    int64 `json:"cpu_seconds"`
+
+	MemoryByteSeconds int64 `json:"memory_byte_seconds"`
+}
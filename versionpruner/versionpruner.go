@@ -0,0 +1,53 @@
+package versionpruner
+
+import (
+	"code.cloudfoundry.org/lager"
+)
+
+//go:generate counterfeiter . VersionPrunerDB
+
+type VersionPrunerDB interface {
+	PruneResourceVersions(retain int, limit int) (int, error)
+}
+
+type VersionPruner interface {
+	Run() error
+}
+
+type versionPruner struct {
+	logger    lager.Logger
+	db        VersionPrunerDB
+	retain    int
+	batchSize int
+}
+
+// NewVersionPruner returns a VersionPruner that deletes the versions of each
+// resource older than its latest retain, batchSize versions per run, keeping
+// any that are still of use.
+func NewVersionPruner(
+	logger lager.Logger,
+	db VersionPrunerDB,
+	retain int,
+	batchSize int,
+) VersionPruner {
+	return &versionPruner{
+		logger:    logger,
+		db:        db,
+		retain:    retain,
+		batchSize: batchSize,
+	}
+}
+
+func (vp *versionPruner) Run() error {
+	pruned, err := vp.db.PruneResourceVersions(vp.retain, vp.batchSize)
+	if err != nil {
+		vp.logger.Error("could-not-prune-resource-versions", err)
+		return err
+	}
+
+	if pruned > 0 {
+		vp.logger.Info("pruned-resource-versions", lager.Data{"versions": pruned})
+	}
+
+	return nil
+}
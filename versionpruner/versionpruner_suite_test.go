@@ -0,0 +1,13 @@
+package versionpruner_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestVersionPruner(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Version Pruner Suite")
+}
@@ -0,0 +1,58 @@
+package versionpruner_test
+
+import (
+	"errors"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/concourse/atc/versionpruner"
+	"github.com/concourse/atc/versionpruner/versionprunerfakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("VersionPruner", func() {
+	var (
+		fakeDB *versionprunerfakes.FakeVersionPrunerDB
+
+		versionPruner VersionPruner
+
+		runErr error
+	)
+
+	BeforeEach(func() {
+		fakeDB = new(versionprunerfakes.FakeVersionPrunerDB)
+
+		versionPruner = NewVersionPruner(
+			lagertest.NewTestLogger("test"),
+			fakeDB,
+			50,
+			500,
+		)
+	})
+
+	JustBeforeEach(func() {
+		runErr = versionPruner.Run()
+	})
+
+	It("prunes a batch of versions past the retention", func() {
+		Expect(runErr).NotTo(HaveOccurred())
+
+		Expect(fakeDB.PruneResourceVersionsCallCount()).To(Equal(1))
+		retain, limit := fakeDB.PruneResourceVersionsArgsForCall(0)
+		Expect(retain).To(Equal(50))
+		Expect(limit).To(Equal(500))
+	})
+
+	Context("when pruning fails", func() {
+		disaster := errors.New("nope")
+
+		BeforeEach(func() {
+			fakeDB.PruneResourceVersionsReturns(0, disaster)
+		})
+
+		It("returns the error", func() {
+			Expect(runErr).To(Equal(disaster))
+		})
+	})
+})
@@ -0,0 +1,80 @@
+// This file was generated by counterfeiter
+package versionprunerfakes
+
+import (
+	"sync"
+
+	"github.com/concourse/atc/versionpruner"
+)
+
+type FakeVersionPrunerDB struct {
+	PruneResourceVersionsStub        func(retain int, limit int) (int, error)
+	pruneResourceVersionsMutex       sync.RWMutex
+	pruneResourceVersionsArgsForCall []struct {
+		retain int
+		limit  int
+	}
+	pruneResourceVersionsReturns struct {
+		result1 int
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeVersionPrunerDB) PruneResourceVersions(retain int, limit int) (int, error) {
+	fake.pruneResourceVersionsMutex.Lock()
+	fake.pruneResourceVersionsArgsForCall = append(fake.pruneResourceVersionsArgsForCall, struct {
+		retain int
+		limit  int
+	}{retain, limit})
+	fake.recordInvocation("PruneResourceVersions", []interface{}{retain, limit})
+	fake.pruneResourceVersionsMutex.Unlock()
+	if fake.PruneResourceVersionsStub != nil {
+		return fake.PruneResourceVersionsStub(retain, limit)
+	} else {
+		return fake.pruneResourceVersionsReturns.result1, fake.pruneResourceVersionsReturns.result2
+	}
+}
+
+func (fake *FakeVersionPrunerDB) PruneResourceVersionsCallCount() int {
+	fake.pruneResourceVersionsMutex.RLock()
+	defer fake.pruneResourceVersionsMutex.RUnlock()
+	return len(fake.pruneResourceVersionsArgsForCall)
+}
+
+func (fake *FakeVersionPrunerDB) PruneResourceVersionsArgsForCall(i int) (int, int) {
+	fake.pruneResourceVersionsMutex.RLock()
+	defer fake.pruneResourceVersionsMutex.RUnlock()
+	return fake.pruneResourceVersionsArgsForCall[i].retain, fake.pruneResourceVersionsArgsForCall[i].limit
+}
+
+func (fake *FakeVersionPrunerDB) PruneResourceVersionsReturns(result1 int, result2 error) {
+	fake.PruneResourceVersionsStub = nil
+	fake.pruneResourceVersionsReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeVersionPrunerDB) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.pruneResourceVersionsMutex.RLock()
+	defer fake.pruneResourceVersionsMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeVersionPrunerDB) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ versionpruner.VersionPrunerDB = new(FakeVersionPrunerDB)
@@ -0,0 +1,5 @@
+package atc
+
+type WebhookConfig struct {
+	Secret string `json:"secret"`
+}
@@ -2,6 +2,7 @@ package worker
 
 import (
 	"errors"
+	"net/http"
 	"time"
 
 	"code.cloudfoundry.org/clock"
@@ -115,13 +116,23 @@ func (provider *dbProvider) newGardenWorker(tikTok clock.Clock, savedWorker db.S
 		provider.logger.Session("garden-connection"),
 		savedWorker.Name,
 		provider.retryPolicy,
+		provider.dialer,
 	)
 
 	connection := NewRetryableConnection(gcf.BuildConnection())
 
 	var bClient baggageclaim.Client
 	if savedWorker.BaggageclaimURL != "" {
-		bClient = bclient.New(savedWorker.BaggageclaimURL)
+		if provider.dialer != nil {
+			bClient = bclient.NewWithHTTPClient(savedWorker.BaggageclaimURL, &http.Client{
+				Transport: &http.Transport{
+					Dial:              provider.dialer,
+					DisableKeepAlives: true,
+				},
+			})
+		} else {
+			bClient = bclient.New(savedWorker.BaggageclaimURL)
+		}
 	}
 
 	volumeFactory := NewVolumeFactory(
@@ -22,6 +22,7 @@ type gardenConnectionFactory struct {
 	logger      lager.Logger
 	workerName  string
 	retryPolicy transport.RetryPolicy
+	dialer      gconn.DialerFunc
 }
 
 func NewGardenConnectionFactory(
@@ -29,22 +30,33 @@ func NewGardenConnectionFactory(
 	logger lager.Logger,
 	workerName string,
 	retryPolicy transport.RetryPolicy,
+	dialer gconn.DialerFunc,
 ) GardenConnectionFactory {
 	return &gardenConnectionFactory{
 		db:          db,
 		logger:      logger,
 		workerName:  workerName,
 		retryPolicy: retryPolicy,
+		dialer:      dialer,
 	}
 }
 
 func (gcf *gardenConnectionFactory) BuildConnection() gconn.Connection {
+	innerTransport := &http.Transport{DisableKeepAlives: true}
+	var innerHijackableClient retryhttp.HijackableClient = retryhttp.DefaultHijackableClient
+
+	// e.g. to talk to the worker over TLS
+	if gcf.dialer != nil {
+		innerTransport.Dial = gcf.dialer
+		innerHijackableClient = transport.NewDialingHijackableClient(gcf.dialer)
+	}
+
 	httpClient := &http.Client{
 		Transport: &retryhttp.RetryRoundTripper{
 			Logger:       gcf.logger.Session("retryable-http-client"),
 			Sleeper:      clock.NewClock(),
 			RetryPolicy:  gcf.retryPolicy,
-			RoundTripper: transport.NewRoundTripper(gcf.workerName, gcf.db, &http.Transport{DisableKeepAlives: true}),
+			RoundTripper: transport.NewRoundTripper(gcf.workerName, gcf.db, innerTransport),
 		},
 	}
 
@@ -52,7 +64,7 @@ func (gcf *gardenConnectionFactory) BuildConnection() gconn.Connection {
 		Logger:           gcf.logger.Session("retry-hijackable-client"),
 		Sleeper:          clock.NewClock(),
 		RetryPolicy:      gcf.retryPolicy,
-		HijackableClient: transport.NewHijackableClient(gcf.workerName, gcf.db, retryhttp.DefaultHijackableClient),
+		HijackableClient: transport.NewHijackableClient(gcf.workerName, gcf.db, innerHijackableClient),
 	}
 
 	// the request generator's address doesn't matter because it's overwritten by the worker lookup clients
@@ -0,0 +1,38 @@
+package transport
+
+import (
+	"net"
+	"net/http"
+	"net/http/httputil"
+
+	"github.com/concourse/retryhttp"
+)
+
+type dialingHijackableClient struct {
+	dial func(network string, address string) (net.Conn, error)
+}
+
+// NewDialingHijackableClient makes each request over its own connection from
+// dial, e.g. so that requests to be hijacked can be made over TLS.
+func NewDialingHijackableClient(dial func(network string, address string) (net.Conn, error)) retryhttp.HijackableClient {
+	return dialingHijackableClient{
+		dial: dial,
+	}
+}
+
+func (c dialingHijackableClient) Do(request *http.Request) (*http.Response, retryhttp.HijackCloser, error) {
+	conn, err := c.dial("tcp", request.URL.Host)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	client := httputil.NewClientConn(conn, nil)
+
+	response, err := client.Do(request)
+	if err != nil && err != httputil.ErrPersistEOF {
+		client.Close()
+		return nil, nil, err
+	}
+
+	return response, client, nil
+}
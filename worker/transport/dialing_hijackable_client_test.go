@@ -0,0 +1,72 @@
+package transport_test
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/atc/worker/transport"
+)
+
+var _ = Describe("DialingHijackableClient #Do", func() {
+	var (
+		server *httptest.Server
+		dialed []string
+	)
+
+	BeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+			w.Write([]byte("some-body"))
+		}))
+
+		dialed = nil
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("makes the request over a connection from the dialer", func() {
+		client := transport.NewDialingHijackableClient(func(network string, address string) (net.Conn, error) {
+			dialed = append(dialed, address)
+			return net.Dial(network, address)
+		})
+
+		request, err := http.NewRequest("GET", server.URL+"/something", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		response, hijackCloser, err := client.Do(request)
+		Expect(err).NotTo(HaveOccurred())
+
+		defer hijackCloser.Close()
+
+		Expect(response.StatusCode).To(Equal(http.StatusTeapot))
+
+		body, err := ioutil.ReadAll(response.Body)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(body)).To(Equal("some-body"))
+
+		Expect(dialed).To(Equal([]string{server.Listener.Addr().String()}))
+	})
+
+	Context("when dialing fails", func() {
+		It("returns the error", func() {
+			disaster := &net.OpError{Op: "dial"}
+
+			client := transport.NewDialingHijackableClient(func(string, string) (net.Conn, error) {
+				return nil, disaster
+			})
+
+			request, err := http.NewRequest("GET", server.URL+"/something", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, _, err = client.Do(request)
+			Expect(err).To(Equal(disaster))
+		})
+	})
+})
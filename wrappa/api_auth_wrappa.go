@@ -48,23 +48,47 @@ func (wrappa *APIAuthWrappa) Wrap(handlers rata.Handlers) rata.Handlers {
 			atc.GetInfo,
 			atc.ListTeams,
 			atc.ListAllPipelines,
+			atc.GetDashboard,
 			atc.ListPipelines,
 			atc.ListBuilds,
-			atc.MainJobBadge:
+			atc.GetBuildStatuses,
+			atc.MultiplexEvents,
+			atc.MainJobBadge,
+			atc.TriggerWebhook:
 
 		// pipeline is public or authorized
 		case atc.GetBuild,
+			atc.WaitForBuild,
 			atc.BuildResources,
+			atc.GetBuildDiff,
 			atc.GetBuildPlan:
 			newHandler = wrappa.checkBuildReadAccessHandlerFactory.AnyJobHandler(handler, rejector)
 
 		// pipeline and job are public or authorized
 		case atc.GetBuildPreparation,
-			atc.BuildEvents:
+			atc.GetBuildUsage,
+			atc.BuildEvents,
+			atc.ListBuildEvents,
+			atc.GetBuildLog,
+			atc.GetBuildLogHTML,
+			atc.SearchBuildLogs,
+			atc.VerifyBuildEvents,
+			atc.ListBuildArtifacts,
+			atc.DownloadBuildArtifact,
+			atc.ListBuildComments,
+			atc.ListBuildApprovals,
+			atc.ListBuildSteps,
+			atc.GetBuildReport:
 			newHandler = wrappa.checkBuildReadAccessHandlerFactory.CheckIfPrivateJobHandler(handler, rejector)
 
 		// resource belongs to authorized team
-		case atc.AbortBuild:
+		case atc.AbortBuild,
+			atc.RerunBuild,
+			atc.HijackBuild,
+			atc.SetBuildPriority,
+			atc.RegisterBuildArtifact,
+			atc.CreateBuildComment,
+			atc.DecideBuildApproval:
 			newHandler = wrappa.checkBuildWriteAccessHandlerFactory.HandlerFor(handler, rejector)
 
 		// pipeline is public or authorized
@@ -73,17 +97,24 @@ func (wrappa *APIAuthWrappa) Wrap(handlers rata.Handlers) rata.Handlers {
 			atc.JobBadge,
 			atc.ListJobs,
 			atc.GetJob,
+			atc.GetJobSchedule,
+			atc.GetJobStats,
+			atc.GetJobScheduling,
 			atc.ListJobBuilds,
 			atc.GetResource,
 			atc.ListBuildsWithVersionAsInput,
 			atc.ListBuildsWithVersionAsOutput,
 			atc.ListResources,
-			atc.ListResourceVersions:
+			atc.ListResourceVersions,
+			atc.ListPassedResourceVersions,
+			atc.PassedResourceVersionEvents:
 			newHandler = wrappa.checkPipelineAccessHandlerFactory.HandlerFor(handler, rejector)
 
 		// authenticated
 		case atc.GetAuthToken,
 			atc.CreateBuild,
+			atc.SearchAllBuildLogs,
+			atc.GetBuildReaperStatus,
 			atc.CreatePipe,
 			atc.GetContainer,
 			atc.HijackContainer,
@@ -94,30 +125,81 @@ func (wrappa *APIAuthWrappa) Wrap(handlers rata.Handlers) rata.Handlers {
 			atc.SetTeam,
 			atc.WritePipe,
 			atc.ListVolumes,
-			atc.GetUser:
+			atc.GetUser,
+			atc.ListRegisteredResourceTypes,
+			atc.GetGCStatus:
 			newHandler = auth.CheckAuthenticationHandler(handler, rejector)
 
 		case atc.GetLogLevel,
-			atc.SetLogLevel:
+			atc.SetLogLevel,
+			atc.ListAuditEvents,
+			atc.Export,
+			atc.Import,
+			atc.GetGlobalMaxInFlight,
+			atc.SetGlobalMaxInFlight,
+			atc.ListPipelineWeights,
+			atc.SetPipelineWeight,
+			atc.GetMaintenance,
+			atc.SetMaintenanceWindows,
+			atc.OverrideMaintenance,
+			atc.CancelMaintenanceOverride,
+			atc.RegisterResourceType,
+			atc.UnregisterResourceType,
+			atc.RunGC,
+			atc.ReconcileBuilds,
+			atc.GetUsage,
+			atc.ListQueue:
 			newHandler = auth.CheckAdminHandler(handler, rejector)
 
 		// authorized (requested team matches resource team)
 		case atc.CheckResource,
 			atc.CreateJobBuild,
+			atc.ReceiveRemoteTrigger,
+			atc.CreateTeamBuild,
+			atc.ListTeamBuilds,
 			atc.DeletePipeline,
 			atc.DisableResourceVersion,
 			atc.EnableResourceVersion,
 			atc.GetConfig,
+			atc.GetConfigHistory,
+			atc.GetResourceVersionCausality,
 			atc.GetVersionsDB,
 			atc.ListJobInputs,
+			atc.ListJobCaches,
+			atc.InvalidateJobCaches,
+			atc.ListExpiringJobBuilds,
+			atc.ProtectJobBuildArtifacts,
+			atc.UnprotectJobBuildArtifacts,
+			atc.ListResourceCheckErrors,
+			atc.MakeJobAutomatic,
+			atc.MakeJobManualOnly,
 			atc.OrderPipelines,
 			atc.PauseJob,
 			atc.PausePipeline,
 			atc.PauseResource,
+			atc.PinResourceVersion,
 			atc.RenamePipeline,
+			atc.GetPipelinePermissions,
+			atc.SetPipelinePermissions,
+			atc.ListPipelineInstances,
+			atc.SavePipelineInstance,
+			atc.DeletePipelineInstance,
+			atc.RevertConfig,
+			atc.SaveCandidateConfig,
+			atc.GetCandidateConfig,
+			atc.DeleteCandidateConfig,
+			atc.PromoteCandidateConfig,
+			atc.GetCandidateComparison,
+			atc.ListAlerts,
+			atc.ValidateConfig,
+			atc.SaveJobWebhook,
+			atc.CreateAPIToken,
+			atc.ListAPITokens,
+			atc.RevokeAPIToken,
 			atc.UnpauseJob,
 			atc.UnpausePipeline,
 			atc.UnpauseResource,
+			atc.UnpinResource,
 			atc.ExposePipeline,
 			atc.HidePipeline,
 			atc.SaveConfig:
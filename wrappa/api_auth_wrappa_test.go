@@ -159,22 +159,49 @@ var _ = Describe("APIAuthWrappa", func() {
 				atc.DownloadCLI:      unauthenticated(inputHandlers[atc.DownloadCLI]),
 				atc.ListAuthMethods:  unauthenticated(inputHandlers[atc.ListAuthMethods]),
 				atc.ListAllPipelines: unauthenticated(inputHandlers[atc.ListAllPipelines]),
+				atc.GetDashboard:     unauthenticated(inputHandlers[atc.GetDashboard]),
 				atc.ListBuilds:       unauthenticated(inputHandlers[atc.ListBuilds]),
+				atc.GetBuildStatuses: unauthenticated(inputHandlers[atc.GetBuildStatuses]),
+				atc.MultiplexEvents:  unauthenticated(inputHandlers[atc.MultiplexEvents]),
 				atc.ListPipelines:    unauthenticated(inputHandlers[atc.ListPipelines]),
 				atc.ListTeams:        unauthenticated(inputHandlers[atc.ListTeams]),
 				atc.MainJobBadge:     unauthenticated(inputHandlers[atc.MainJobBadge]),
+				atc.TriggerWebhook:   unauthenticated(inputHandlers[atc.TriggerWebhook]),
 
 				// authorized or public pipeline
 				atc.GetBuild:       doesNotCheckIfPrivateJob(inputHandlers[atc.GetBuild]),
+				atc.WaitForBuild:   doesNotCheckIfPrivateJob(inputHandlers[atc.WaitForBuild]),
 				atc.BuildResources: doesNotCheckIfPrivateJob(inputHandlers[atc.BuildResources]),
+				atc.GetBuildDiff:   doesNotCheckIfPrivateJob(inputHandlers[atc.GetBuildDiff]),
 				atc.GetBuildPlan:   doesNotCheckIfPrivateJob(inputHandlers[atc.GetBuildPlan]),
 
 				// authorized or public pipeline and public job
 				atc.BuildEvents:         checksIfPrivateJob(inputHandlers[atc.BuildEvents]),
+				atc.ListBuildEvents:     checksIfPrivateJob(inputHandlers[atc.ListBuildEvents]),
+				atc.GetBuildLog:         checksIfPrivateJob(inputHandlers[atc.GetBuildLog]),
+				atc.GetBuildLogHTML:     checksIfPrivateJob(inputHandlers[atc.GetBuildLogHTML]),
 				atc.GetBuildPreparation: checksIfPrivateJob(inputHandlers[atc.GetBuildPreparation]),
+				atc.GetBuildUsage:       checksIfPrivateJob(inputHandlers[atc.GetBuildUsage]),
+				atc.SearchBuildLogs:     checksIfPrivateJob(inputHandlers[atc.SearchBuildLogs]),
+				atc.VerifyBuildEvents:   checksIfPrivateJob(inputHandlers[atc.VerifyBuildEvents]),
+
+				atc.ListBuildArtifacts:    checksIfPrivateJob(inputHandlers[atc.ListBuildArtifacts]),
+				atc.DownloadBuildArtifact: checksIfPrivateJob(inputHandlers[atc.DownloadBuildArtifact]),
+				atc.ListBuildComments:     checksIfPrivateJob(inputHandlers[atc.ListBuildComments]),
+				atc.ListBuildApprovals:    checksIfPrivateJob(inputHandlers[atc.ListBuildApprovals]),
+				atc.ListBuildSteps:        checksIfPrivateJob(inputHandlers[atc.ListBuildSteps]),
+				atc.GetBuildReport:        checksIfPrivateJob(inputHandlers[atc.GetBuildReport]),
 
 				// resource belongs to authorized team
-				atc.AbortBuild: checkWritePermissionForBuild(inputHandlers[atc.AbortBuild]),
+				atc.AbortBuild:  checkWritePermissionForBuild(inputHandlers[atc.AbortBuild]),
+				atc.RerunBuild:  checkWritePermissionForBuild(inputHandlers[atc.RerunBuild]),
+				atc.HijackBuild: checkWritePermissionForBuild(inputHandlers[atc.HijackBuild]),
+
+				atc.SetBuildPriority: checkWritePermissionForBuild(inputHandlers[atc.SetBuildPriority]),
+
+				atc.RegisterBuildArtifact: checkWritePermissionForBuild(inputHandlers[atc.RegisterBuildArtifact]),
+				atc.CreateBuildComment:    checkWritePermissionForBuild(inputHandlers[atc.CreateBuildComment]),
+				atc.DecideBuildApproval:   checkWritePermissionForBuild(inputHandlers[atc.DecideBuildApproval]),
 
 				// belongs to public pipeline or authorized
 				atc.GetPipeline:                   openForPublicPipelineOrAuthorized(inputHandlers[atc.GetPipeline]),
@@ -182,53 +209,122 @@ var _ = Describe("APIAuthWrappa", func() {
 				atc.JobBadge:                      openForPublicPipelineOrAuthorized(inputHandlers[atc.JobBadge]),
 				atc.ListJobs:                      openForPublicPipelineOrAuthorized(inputHandlers[atc.ListJobs]),
 				atc.GetJob:                        openForPublicPipelineOrAuthorized(inputHandlers[atc.GetJob]),
+				atc.GetJobSchedule:                openForPublicPipelineOrAuthorized(inputHandlers[atc.GetJobSchedule]),
+				atc.GetJobStats:                   openForPublicPipelineOrAuthorized(inputHandlers[atc.GetJobStats]),
+				atc.GetJobScheduling:              openForPublicPipelineOrAuthorized(inputHandlers[atc.GetJobScheduling]),
 				atc.ListJobBuilds:                 openForPublicPipelineOrAuthorized(inputHandlers[atc.ListJobBuilds]),
 				atc.GetResource:                   openForPublicPipelineOrAuthorized(inputHandlers[atc.GetResource]),
 				atc.ListBuildsWithVersionAsInput:  openForPublicPipelineOrAuthorized(inputHandlers[atc.ListBuildsWithVersionAsInput]),
 				atc.ListBuildsWithVersionAsOutput: openForPublicPipelineOrAuthorized(inputHandlers[atc.ListBuildsWithVersionAsOutput]),
 				atc.ListResources:                 openForPublicPipelineOrAuthorized(inputHandlers[atc.ListResources]),
 				atc.ListResourceVersions:          openForPublicPipelineOrAuthorized(inputHandlers[atc.ListResourceVersions]),
+				atc.ListPassedResourceVersions:    openForPublicPipelineOrAuthorized(inputHandlers[atc.ListPassedResourceVersions]),
+				atc.PassedResourceVersionEvents:   openForPublicPipelineOrAuthorized(inputHandlers[atc.PassedResourceVersionEvents]),
 
 				// authenticated
-				atc.CreateBuild:     authenticated(inputHandlers[atc.CreateBuild]),
-				atc.CreatePipe:      authenticated(inputHandlers[atc.CreatePipe]),
-				atc.GetAuthToken:    authenticatedWithGetTokenValidator(inputHandlers[atc.GetAuthToken]),
-				atc.GetContainer:    authenticated(inputHandlers[atc.GetContainer]),
-				atc.HijackContainer: authenticated(inputHandlers[atc.HijackContainer]),
-				atc.ListContainers:  authenticated(inputHandlers[atc.ListContainers]),
-				atc.ListVolumes:     authenticated(inputHandlers[atc.ListVolumes]),
-				atc.ListWorkers:     authenticated(inputHandlers[atc.ListWorkers]),
-				atc.ReadPipe:        authenticated(inputHandlers[atc.ReadPipe]),
-				atc.RegisterWorker:  authenticated(inputHandlers[atc.RegisterWorker]),
+				atc.CreateBuild:          authenticated(inputHandlers[atc.CreateBuild]),
+				atc.SearchAllBuildLogs:   authenticated(inputHandlers[atc.SearchAllBuildLogs]),
+				atc.GetBuildReaperStatus: authenticated(inputHandlers[atc.GetBuildReaperStatus]),
+				atc.CreatePipe:           authenticated(inputHandlers[atc.CreatePipe]),
+				atc.GetAuthToken:         authenticatedWithGetTokenValidator(inputHandlers[atc.GetAuthToken]),
+				atc.GetContainer:         authenticated(inputHandlers[atc.GetContainer]),
+				atc.HijackContainer:      authenticated(inputHandlers[atc.HijackContainer]),
+				atc.ListContainers:       authenticated(inputHandlers[atc.ListContainers]),
+				atc.ListVolumes:          authenticated(inputHandlers[atc.ListVolumes]),
+				atc.ListWorkers:          authenticated(inputHandlers[atc.ListWorkers]),
+				atc.ReadPipe:             authenticated(inputHandlers[atc.ReadPipe]),
+				atc.RegisterWorker:       authenticated(inputHandlers[atc.RegisterWorker]),
 
 				atc.SetTeam:   authenticated(inputHandlers[atc.SetTeam]),
 				atc.WritePipe: authenticated(inputHandlers[atc.WritePipe]),
 				atc.GetUser:   authenticated(inputHandlers[atc.GetUser]),
 
+				atc.ListRegisteredResourceTypes: authenticated(inputHandlers[atc.ListRegisteredResourceTypes]),
+				atc.GetGCStatus:                 authenticated(inputHandlers[atc.GetGCStatus]),
+
 				// authenticated and is admin
 				atc.GetLogLevel: authenticatedAndAdmin(inputHandlers[atc.GetLogLevel]),
 				atc.SetLogLevel: authenticatedAndAdmin(inputHandlers[atc.SetLogLevel]),
 
+				atc.ListAuditEvents: authenticatedAndAdmin(inputHandlers[atc.ListAuditEvents]),
+
+				atc.Export: authenticatedAndAdmin(inputHandlers[atc.Export]),
+				atc.Import: authenticatedAndAdmin(inputHandlers[atc.Import]),
+
+				atc.GetGlobalMaxInFlight: authenticatedAndAdmin(inputHandlers[atc.GetGlobalMaxInFlight]),
+				atc.SetGlobalMaxInFlight: authenticatedAndAdmin(inputHandlers[atc.SetGlobalMaxInFlight]),
+
+				atc.ListPipelineWeights: authenticatedAndAdmin(inputHandlers[atc.ListPipelineWeights]),
+				atc.SetPipelineWeight:   authenticatedAndAdmin(inputHandlers[atc.SetPipelineWeight]),
+
+				atc.GetMaintenance:            authenticatedAndAdmin(inputHandlers[atc.GetMaintenance]),
+				atc.SetMaintenanceWindows:     authenticatedAndAdmin(inputHandlers[atc.SetMaintenanceWindows]),
+				atc.OverrideMaintenance:       authenticatedAndAdmin(inputHandlers[atc.OverrideMaintenance]),
+				atc.CancelMaintenanceOverride: authenticatedAndAdmin(inputHandlers[atc.CancelMaintenanceOverride]),
+
+				atc.RegisterResourceType:   authenticatedAndAdmin(inputHandlers[atc.RegisterResourceType]),
+				atc.UnregisterResourceType: authenticatedAndAdmin(inputHandlers[atc.UnregisterResourceType]),
+
+				atc.RunGC: authenticatedAndAdmin(inputHandlers[atc.RunGC]),
+
+				atc.GetUsage: authenticatedAndAdmin(inputHandlers[atc.GetUsage]),
+
+				atc.ListQueue: authenticatedAndAdmin(inputHandlers[atc.ListQueue]),
+
+				atc.ReconcileBuilds: authenticatedAndAdmin(inputHandlers[atc.ReconcileBuilds]),
+
 				// authorized (requested team matches resource team)
-				atc.CheckResource:          authorized(inputHandlers[atc.CheckResource]),
-				atc.CreateJobBuild:         authorized(inputHandlers[atc.CreateJobBuild]),
-				atc.DeletePipeline:         authorized(inputHandlers[atc.DeletePipeline]),
-				atc.DisableResourceVersion: authorized(inputHandlers[atc.DisableResourceVersion]),
-				atc.EnableResourceVersion:  authorized(inputHandlers[atc.EnableResourceVersion]),
-				atc.GetConfig:              authorized(inputHandlers[atc.GetConfig]),
-				atc.GetVersionsDB:          authorized(inputHandlers[atc.GetVersionsDB]),
-				atc.ListJobInputs:          authorized(inputHandlers[atc.ListJobInputs]),
-				atc.OrderPipelines:         authorized(inputHandlers[atc.OrderPipelines]),
-				atc.PauseJob:               authorized(inputHandlers[atc.PauseJob]),
-				atc.PausePipeline:          authorized(inputHandlers[atc.PausePipeline]),
-				atc.PauseResource:          authorized(inputHandlers[atc.PauseResource]),
-				atc.RenamePipeline:         authorized(inputHandlers[atc.RenamePipeline]),
-				atc.SaveConfig:             authorized(inputHandlers[atc.SaveConfig]),
-				atc.UnpauseJob:             authorized(inputHandlers[atc.UnpauseJob]),
-				atc.UnpausePipeline:        authorized(inputHandlers[atc.UnpausePipeline]),
-				atc.UnpauseResource:        authorized(inputHandlers[atc.UnpauseResource]),
-				atc.ExposePipeline:         authorized(inputHandlers[atc.ExposePipeline]),
-				atc.HidePipeline:           authorized(inputHandlers[atc.HidePipeline]),
+				atc.CheckResource:               authorized(inputHandlers[atc.CheckResource]),
+				atc.CreateJobBuild:              authorized(inputHandlers[atc.CreateJobBuild]),
+				atc.ReceiveRemoteTrigger:        authorized(inputHandlers[atc.ReceiveRemoteTrigger]),
+				atc.CreateTeamBuild:             authorized(inputHandlers[atc.CreateTeamBuild]),
+				atc.ListTeamBuilds:              authorized(inputHandlers[atc.ListTeamBuilds]),
+				atc.DeletePipeline:              authorized(inputHandlers[atc.DeletePipeline]),
+				atc.DisableResourceVersion:      authorized(inputHandlers[atc.DisableResourceVersion]),
+				atc.EnableResourceVersion:       authorized(inputHandlers[atc.EnableResourceVersion]),
+				atc.GetConfig:                   authorized(inputHandlers[atc.GetConfig]),
+				atc.GetConfigHistory:            authorized(inputHandlers[atc.GetConfigHistory]),
+				atc.GetResourceVersionCausality: authorized(inputHandlers[atc.GetResourceVersionCausality]),
+				atc.GetVersionsDB:               authorized(inputHandlers[atc.GetVersionsDB]),
+				atc.ListJobInputs:               authorized(inputHandlers[atc.ListJobInputs]),
+				atc.ListJobCaches:               authorized(inputHandlers[atc.ListJobCaches]),
+				atc.InvalidateJobCaches:         authorized(inputHandlers[atc.InvalidateJobCaches]),
+				atc.ListExpiringJobBuilds:       authorized(inputHandlers[atc.ListExpiringJobBuilds]),
+				atc.ProtectJobBuildArtifacts:    authorized(inputHandlers[atc.ProtectJobBuildArtifacts]),
+				atc.UnprotectJobBuildArtifacts:  authorized(inputHandlers[atc.UnprotectJobBuildArtifacts]),
+				atc.ListResourceCheckErrors:     authorized(inputHandlers[atc.ListResourceCheckErrors]),
+				atc.MakeJobAutomatic:            authorized(inputHandlers[atc.MakeJobAutomatic]),
+				atc.MakeJobManualOnly:           authorized(inputHandlers[atc.MakeJobManualOnly]),
+				atc.OrderPipelines:              authorized(inputHandlers[atc.OrderPipelines]),
+				atc.PauseJob:                    authorized(inputHandlers[atc.PauseJob]),
+				atc.PausePipeline:               authorized(inputHandlers[atc.PausePipeline]),
+				atc.PauseResource:               authorized(inputHandlers[atc.PauseResource]),
+				atc.PinResourceVersion:          authorized(inputHandlers[atc.PinResourceVersion]),
+				atc.RenamePipeline:              authorized(inputHandlers[atc.RenamePipeline]),
+				atc.ListPipelineInstances:       authorized(inputHandlers[atc.ListPipelineInstances]),
+				atc.SavePipelineInstance:        authorized(inputHandlers[atc.SavePipelineInstance]),
+				atc.DeletePipelineInstance:      authorized(inputHandlers[atc.DeletePipelineInstance]),
+				atc.RevertConfig:                authorized(inputHandlers[atc.RevertConfig]),
+				atc.SaveCandidateConfig:         authorized(inputHandlers[atc.SaveCandidateConfig]),
+				atc.GetCandidateConfig:          authorized(inputHandlers[atc.GetCandidateConfig]),
+				atc.DeleteCandidateConfig:       authorized(inputHandlers[atc.DeleteCandidateConfig]),
+				atc.PromoteCandidateConfig:      authorized(inputHandlers[atc.PromoteCandidateConfig]),
+				atc.GetCandidateComparison:      authorized(inputHandlers[atc.GetCandidateComparison]),
+				atc.ListAlerts:                  authorized(inputHandlers[atc.ListAlerts]),
+				atc.ValidateConfig:              authorized(inputHandlers[atc.ValidateConfig]),
+				atc.SaveJobWebhook:              authorized(inputHandlers[atc.SaveJobWebhook]),
+				atc.SaveConfig:                  authorized(inputHandlers[atc.SaveConfig]),
+				atc.UnpauseJob:                  authorized(inputHandlers[atc.UnpauseJob]),
+				atc.UnpausePipeline:             authorized(inputHandlers[atc.UnpausePipeline]),
+				atc.UnpauseResource:             authorized(inputHandlers[atc.UnpauseResource]),
+				atc.UnpinResource:               authorized(inputHandlers[atc.UnpinResource]),
+				atc.ExposePipeline:              authorized(inputHandlers[atc.ExposePipeline]),
+				atc.HidePipeline:                authorized(inputHandlers[atc.HidePipeline]),
+				atc.GetPipelinePermissions:      authorized(inputHandlers[atc.GetPipelinePermissions]),
+				atc.SetPipelinePermissions:      authorized(inputHandlers[atc.SetPipelinePermissions]),
+				atc.CreateAPIToken:              authorized(inputHandlers[atc.CreateAPIToken]),
+				atc.ListAPITokens:               authorized(inputHandlers[atc.ListAPITokens]),
+				atc.RevokeAPIToken:              authorized(inputHandlers[atc.RevokeAPIToken]),
 			}
 		})
 
@@ -22,7 +22,7 @@ func (wrappa APIMetricsWrappa) Wrap(handlers rata.Handlers) rata.Handlers {
 
 	for name, handler := range handlers {
 		switch name {
-		case atc.BuildEvents, atc.WritePipe, atc.ReadPipe, atc.DownloadCLI,
+		case atc.BuildEvents, atc.GetBuildLog, atc.GetBuildLogHTML, atc.WritePipe, atc.ReadPipe, atc.DownloadCLI,
 			atc.HijackContainer:
 			wrapped[name] = handler
 		default:
@@ -0,0 +1,22 @@
+package wrappa
+
+import (
+	"github.com/concourse/atc/tracing"
+	"github.com/tedsuo/rata"
+)
+
+type APITracingWrappa struct{}
+
+func NewAPITracingWrappa() Wrappa {
+	return APITracingWrappa{}
+}
+
+func (wrappa APITracingWrappa) Wrap(handlers rata.Handlers) rata.Handlers {
+	wrapped := rata.Handlers{}
+
+	for name, handler := range handlers {
+		wrapped[name] = tracing.WrapHandler(name, handler)
+	}
+
+	return wrapped
+}
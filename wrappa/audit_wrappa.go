@@ -0,0 +1,55 @@
+package wrappa
+
+import (
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+	"github.com/tedsuo/rata"
+)
+
+//go:generate counterfeiter . AuditDB
+
+type AuditDB interface {
+	SaveAuditEvent(event db.AuditEvent) error
+}
+
+type AuditWrappa struct {
+	logger  lager.Logger
+	auditDB AuditDB
+}
+
+func NewAuditWrappa(logger lager.Logger, auditDB AuditDB) Wrappa {
+	return AuditWrappa{
+		logger:  logger,
+		auditDB: auditDB,
+	}
+}
+
+// Wrap audits every route that can change something, which is every route
+// that isn't a GET. It must be applied before the auth wrappa so that the
+// audited handlers can tell who is making the request.
+func (wrappa AuditWrappa) Wrap(handlers rata.Handlers) rata.Handlers {
+	mutating := map[string]bool{}
+	for _, route := range atc.Routes {
+		if route.Method != "GET" {
+			mutating[route.Name] = true
+		}
+	}
+
+	wrapped := rata.Handlers{}
+
+	for name, handler := range handlers {
+		if mutating[name] {
+			wrapped[name] = AuditedHandler{
+				Logger:  wrappa.logger.Session("audit", lager.Data{"action": name}),
+				AuditDB: wrappa.auditDB,
+				Action:  name,
+				Handler: handler,
+			}
+		} else {
+			wrapped[name] = handler
+		}
+	}
+
+	return wrapped
+}
@@ -0,0 +1,144 @@
+package wrappa_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/auth"
+	"github.com/concourse/atc/auth/authfakes"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/wrappa"
+	"github.com/concourse/atc/wrappa/wrappafakes"
+	"github.com/tedsuo/rata"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AuditWrappa", func() {
+	var (
+		fakeAuditDB *wrappafakes.FakeAuditDB
+
+		inputHandlers   rata.Handlers
+		wrappedHandlers rata.Handlers
+	)
+
+	BeforeEach(func() {
+		fakeAuditDB = new(wrappafakes.FakeAuditDB)
+
+		inputHandlers = rata.Handlers{}
+
+		for _, route := range atc.Routes {
+			inputHandlers[route.Name] = &stupidHandler{}
+		}
+	})
+
+	JustBeforeEach(func() {
+		wrappedHandlers = wrappa.NewAuditWrappa(lagertest.NewTestLogger("test"), fakeAuditDB).Wrap(inputHandlers)
+	})
+
+	It("audits every route that isn't a GET", func() {
+		for _, route := range atc.Routes {
+			if route.Method == "GET" {
+				Expect(descriptiveRoute{
+					route:   route.Name,
+					handler: wrappedHandlers[route.Name],
+				}).To(Equal(descriptiveRoute{
+					route:   route.Name,
+					handler: inputHandlers[route.Name],
+				}))
+			} else {
+				Expect(wrappedHandlers[route.Name]).To(BeAssignableToTypeOf(wrappa.AuditedHandler{}), route.Name)
+			}
+		}
+	})
+
+	Describe("an audited route", func() {
+		var (
+			authValidator     *authfakes.FakeValidator
+			userContextReader *authfakes.FakeUserContextReader
+
+			recorder *httptest.ResponseRecorder
+		)
+
+		BeforeEach(func() {
+			authValidator = new(authfakes.FakeValidator)
+			userContextReader = new(authfakes.FakeUserContextReader)
+
+			inputHandlers[atc.AbortBuild] = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				buf := make([]byte, 4)
+				r.Body.Read(buf)
+
+				w.WriteHeader(http.StatusNoContent)
+			})
+		})
+
+		JustBeforeEach(func() {
+			recorder = httptest.NewRecorder()
+
+			r, err := http.NewRequest("POST", "/api/v1/builds/42/abort", bytes.NewBufferString("some-body"))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler := auth.WrapHandler(wrappedHandlers[atc.AbortBuild], authValidator, userContextReader)
+			handler.ServeHTTP(recorder, r)
+		})
+
+		Context("when authenticated as a team", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("some-team", 1, false, true)
+			})
+
+			It("records the request once it has been handled", func() {
+				Expect(recorder.Code).To(Equal(http.StatusNoContent))
+
+				Expect(fakeAuditDB.SaveAuditEventCallCount()).To(Equal(1))
+				Expect(fakeAuditDB.SaveAuditEventArgsForCall(0)).To(Equal(db.AuditEvent{
+					Actor:    "team:some-team",
+					Action:   atc.AbortBuild,
+					Resource: "/api/v1/builds/42/abort",
+
+					// sha256 of the whole body, even though only part of it was read
+					BodySHA256: "2a12617b13dd420e0d8d0c5c48c3801aabfffb2597d724d0d4c4bbcefc4aa645",
+					Status:     http.StatusNoContent,
+				}))
+			})
+
+			Context("when saving the event fails", func() {
+				BeforeEach(func() {
+					fakeAuditDB.SaveAuditEventReturns(errors.New("nope"))
+				})
+
+				It("does not fail the request", func() {
+					Expect(recorder.Code).To(Equal(http.StatusNoContent))
+				})
+			})
+		})
+
+		Context("when authenticated as the system", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetSystemReturns(true, true)
+			})
+
+			It("records the system as the actor", func() {
+				Expect(fakeAuditDB.SaveAuditEventArgsForCall(0).Actor).To(Equal("system"))
+			})
+		})
+
+		Context("when not authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+				userContextReader.GetTeamReturns("some-team", 1, false, true)
+			})
+
+			It("records the actor as anonymous", func() {
+				Expect(fakeAuditDB.SaveAuditEventArgsForCall(0).Actor).To(Equal("anonymous"))
+			})
+		})
+	})
+})
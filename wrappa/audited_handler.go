@@ -0,0 +1,76 @@
+package wrappa
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/auth"
+	"github.com/concourse/atc/db"
+)
+
+// AuditedHandler records an audit event once the request it wraps has been
+// handled. The request body is hashed as the handler reads it, so bodies
+// that are streamed, such as those written to pipes, are never buffered.
+type AuditedHandler struct {
+	Logger  lager.Logger
+	AuditDB AuditDB
+	Action  string
+	Handler http.Handler
+}
+
+func (handler AuditedHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body := &hashingReadCloser{
+		ReadCloser: r.Body,
+		hash:       sha256.New(),
+	}
+
+	r.Body = body
+
+	recorder := &statusRecorder{
+		ResponseWriter: w,
+		status:         http.StatusOK,
+	}
+
+	handler.Handler.ServeHTTP(recorder, r)
+
+	// hash whatever the handler didn't read, so that the same body always
+	// has the same hash
+	io.Copy(ioutil.Discard, body)
+
+	err := handler.AuditDB.SaveAuditEvent(db.AuditEvent{
+		Actor:      auth.GetActor(r),
+		Action:     handler.Action,
+		Resource:   r.URL.Path,
+		BodySHA256: hex.EncodeToString(body.hash.Sum(nil)),
+		Status:     recorder.status,
+	})
+	if err != nil {
+		handler.Logger.Error("failed-to-save-audit-event", err)
+	}
+}
+
+type hashingReadCloser struct {
+	io.ReadCloser
+	hash hash.Hash
+}
+
+func (r *hashingReadCloser) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.hash.Write(p[:n])
+	return n, err
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
@@ -0,0 +1,31 @@
+package wrappa
+
+import (
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+)
+
+// ClientCertHandler only lets through requests made over TLS with a client
+// certificate that was verified during the handshake. This is on top of the
+// usual auth, which still applies.
+type ClientCertHandler struct {
+	Logger  lager.Logger
+	Handler http.Handler
+}
+
+func (handler ClientCertHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+		handler.Logger.Info("missing-client-certificate", lager.Data{"remote-addr": r.RemoteAddr})
+
+		apierror.Write(w, http.StatusForbidden, atc.APIError{
+			Code:    atc.ErrorCodeForbidden,
+			Message: "a verified client certificate is required",
+		})
+		return
+	}
+
+	handler.Handler.ServeHTTP(w, r)
+}
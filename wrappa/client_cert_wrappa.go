@@ -0,0 +1,35 @@
+package wrappa
+
+import (
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/tedsuo/rata"
+)
+
+type ClientCertWrappa struct {
+	logger lager.Logger
+}
+
+func NewClientCertWrappa(logger lager.Logger) Wrappa {
+	return ClientCertWrappa{
+		logger: logger,
+	}
+}
+
+func (wrappa ClientCertWrappa) Wrap(handlers rata.Handlers) rata.Handlers {
+	wrapped := rata.Handlers{}
+
+	for name, handler := range handlers {
+		switch name {
+		case atc.RegisterWorker:
+			wrapped[name] = ClientCertHandler{
+				Logger:  wrappa.logger.Session("client-cert", lager.Data{"route": name}),
+				Handler: handler,
+			}
+		default:
+			wrapped[name] = handler
+		}
+	}
+
+	return wrapped
+}
@@ -0,0 +1,83 @@
+package wrappa_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/wrappa"
+	"github.com/tedsuo/rata"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ClientCertWrappa", func() {
+	var (
+		inputHandlers   rata.Handlers
+		wrappedHandlers rata.Handlers
+	)
+
+	BeforeEach(func() {
+		inputHandlers = rata.Handlers{}
+
+		for _, route := range atc.Routes {
+			inputHandlers[route.Name] = &stupidHandler{}
+		}
+
+		wrappedHandlers = wrappa.NewClientCertWrappa(lagertest.NewTestLogger("test")).Wrap(inputHandlers)
+	})
+
+	It("only wraps the routes that workers call", func() {
+		for name, handler := range inputHandlers {
+			switch name {
+			case atc.RegisterWorker:
+				Expect(wrappedHandlers[name]).To(BeAssignableToTypeOf(wrappa.ClientCertHandler{}))
+			default:
+				Expect(descriptiveRoute{
+					route:   name,
+					handler: wrappedHandlers[name],
+				}).To(Equal(descriptiveRoute{
+					route:   name,
+					handler: handler,
+				}))
+			}
+		}
+	})
+
+	Describe("a wrapped route", func() {
+		var request *http.Request
+
+		BeforeEach(func() {
+			var err error
+			request, err = http.NewRequest("POST", "/api/v1/workers", nil)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		serve := func() int {
+			recorder := httptest.NewRecorder()
+			wrappedHandlers[atc.RegisterWorker].ServeHTTP(recorder, request)
+			return recorder.Code
+		}
+
+		It("rejects requests that aren't over TLS", func() {
+			Expect(serve()).To(Equal(http.StatusForbidden))
+		})
+
+		It("rejects requests over TLS without a verified client certificate", func() {
+			request.TLS = &tls.ConnectionState{}
+			Expect(serve()).To(Equal(http.StatusForbidden))
+		})
+
+		It("lets through requests with a verified client certificate", func() {
+			request.TLS = &tls.ConnectionState{
+				VerifiedChains: [][]*x509.Certificate{{&x509.Certificate{}}},
+			}
+
+			Expect(serve()).To(Equal(http.StatusOK))
+		})
+	})
+})
@@ -0,0 +1,63 @@
+package wrappa
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ClientIPResolver works out the IP address a request came from. Requests
+// from the trusted proxies are taken to be from the last address in
+// X-Forwarded-For that isn't a trusted proxy too; anyone else's
+// X-Forwarded-For is ignored, as they could have put anything in it.
+type ClientIPResolver struct {
+	TrustedProxies []*net.IPNet
+}
+
+func (resolver ClientIPResolver) ClientIP(r *http.Request) net.IP {
+	remoteIP, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		remoteIP = r.RemoteAddr
+	}
+
+	ip := net.ParseIP(remoteIP)
+	if ip == nil || !resolver.trusted(ip) {
+		return ip
+	}
+
+	var forwarded []string
+	for _, header := range r.Header["X-Forwarded-For"] {
+		forwarded = append(forwarded, strings.Split(header, ",")...)
+	}
+
+	// each proxy appends the address it got the request from, so work back
+	// from the nearest one
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		forwardedIP := net.ParseIP(strings.TrimSpace(forwarded[i]))
+		if forwardedIP == nil {
+			return ip
+		}
+
+		ip = forwardedIP
+
+		if !resolver.trusted(ip) {
+			return ip
+		}
+	}
+
+	return ip
+}
+
+func (resolver ClientIPResolver) trusted(ip net.IP) bool {
+	return containsIP(resolver.TrustedProxies, ip)
+}
+
+func containsIP(networks []*net.IPNet, ip net.IP) bool {
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,37 @@
+package wrappa
+
+import (
+	"net"
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+)
+
+// NetworkPolicyHandler only lets through requests from clients within the
+// allowed networks, before any auth is checked.
+type NetworkPolicyHandler struct {
+	Logger   lager.Logger
+	Resolver ClientIPResolver
+	Allowed  []*net.IPNet
+	Handler  http.Handler
+}
+
+func (handler NetworkPolicyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	clientIP := handler.Resolver.ClientIP(r)
+	if clientIP == nil || !containsIP(handler.Allowed, clientIP) {
+		handler.Logger.Info("network-not-allowed", lager.Data{
+			"remote-addr": r.RemoteAddr,
+			"client-ip":   clientIP.String(),
+		})
+
+		apierror.Write(w, http.StatusForbidden, atc.APIError{
+			Code:    atc.ErrorCodeForbidden,
+			Message: "requests from this network are not allowed",
+		})
+		return
+	}
+
+	handler.Handler.ServeHTTP(w, r)
+}
@@ -0,0 +1,56 @@
+package wrappa
+
+import (
+	"net"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/auth"
+	"github.com/tedsuo/rata"
+)
+
+// NetworkPolicyWrappa only lets requests through from the networks allowed
+// for the route's group, which is the scope an API token would need to call
+// it: read, trigger or admin. Groups with no networks allowed are open to
+// any network.
+type NetworkPolicyWrappa struct {
+	logger   lager.Logger
+	resolver ClientIPResolver
+	allowed  map[auth.Scope][]*net.IPNet
+}
+
+func NewNetworkPolicyWrappa(logger lager.Logger, resolver ClientIPResolver, allowed map[auth.Scope][]*net.IPNet) Wrappa {
+	return NetworkPolicyWrappa{
+		logger:   logger,
+		resolver: resolver,
+		allowed:  allowed,
+	}
+}
+
+func (wrappa NetworkPolicyWrappa) Wrap(handlers rata.Handlers) rata.Handlers {
+	methods := map[string]string{}
+	for _, route := range atc.Routes {
+		methods[route.Name] = route.Method
+	}
+
+	wrapped := rata.Handlers{}
+
+	for name, handler := range handlers {
+		group := RequiredScope(name, methods[name])
+
+		networks := wrappa.allowed[group]
+		if len(networks) == 0 {
+			wrapped[name] = handler
+			continue
+		}
+
+		wrapped[name] = NetworkPolicyHandler{
+			Logger:   wrappa.logger.Session("network-policy", lager.Data{"route": name, "group": group}),
+			Resolver: wrappa.resolver,
+			Allowed:  networks,
+			Handler:  handler,
+		}
+	}
+
+	return wrapped
+}
@@ -0,0 +1,142 @@
+package wrappa_test
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/auth"
+	"github.com/concourse/atc/wrappa"
+	"github.com/tedsuo/rata"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("NetworkPolicyWrappa", func() {
+	var (
+		resolver wrappa.ClientIPResolver
+		allowed  map[auth.Scope][]*net.IPNet
+
+		inputHandlers   rata.Handlers
+		wrappedHandlers rata.Handlers
+	)
+
+	cidr := func(value string) *net.IPNet {
+		_, network, err := net.ParseCIDR(value)
+		Expect(err).NotTo(HaveOccurred())
+		return network
+	}
+
+	BeforeEach(func() {
+		resolver = wrappa.ClientIPResolver{}
+
+		allowed = map[auth.Scope][]*net.IPNet{
+			auth.ScopeTrigger: {cidr("192.168.0.0/16")},
+			auth.ScopeAdmin:   {cidr("192.168.1.0/24"), cidr("fd00::/8")},
+		}
+
+		inputHandlers = rata.Handlers{}
+
+		for _, route := range atc.Routes {
+			inputHandlers[route.Name] = &stupidHandler{}
+		}
+	})
+
+	JustBeforeEach(func() {
+		wrappedHandlers = wrappa.NewNetworkPolicyWrappa(lagertest.NewTestLogger("test"), resolver, allowed).Wrap(inputHandlers)
+	})
+
+	It("only wraps the routes in groups with allowed networks", func() {
+		methods := map[string]string{}
+		for _, route := range atc.Routes {
+			methods[route.Name] = route.Method
+		}
+
+		for name, handler := range inputHandlers {
+			if wrappa.RequiredScope(name, methods[name]) == auth.ScopeRead {
+				Expect(descriptiveRoute{
+					route:   name,
+					handler: wrappedHandlers[name],
+				}).To(Equal(descriptiveRoute{
+					route:   name,
+					handler: handler,
+				}))
+			} else {
+				Expect(wrappedHandlers[name]).To(BeAssignableToTypeOf(wrappa.NetworkPolicyHandler{}))
+			}
+		}
+	})
+
+	Describe("a wrapped route", func() {
+		var forwardedFor string
+
+		BeforeEach(func() {
+			forwardedFor = ""
+		})
+
+		request := func(route string, remoteAddr string) *httptest.ResponseRecorder {
+			recorder := httptest.NewRecorder()
+
+			r, err := http.NewRequest("PUT", "/", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			r.RemoteAddr = remoteAddr
+
+			if forwardedFor != "" {
+				r.Header.Set("X-Forwarded-For", forwardedFor)
+			}
+
+			wrappedHandlers[route].ServeHTTP(recorder, r)
+
+			return recorder
+		}
+
+		It("lets through requests from the group's networks", func() {
+			Expect(request(atc.CreateJobBuild, "192.168.2.3:1234").Code).To(Equal(http.StatusOK))
+			Expect(request(atc.SaveConfig, "192.168.1.3:1234").Code).To(Equal(http.StatusOK))
+			Expect(request(atc.SaveConfig, "[fd00::1]:1234").Code).To(Equal(http.StatusOK))
+		})
+
+		It("turns away requests from anywhere else", func() {
+			recorder := request(atc.SaveConfig, "192.168.2.3:1234")
+			Expect(recorder.Code).To(Equal(http.StatusForbidden))
+			Expect(recorder.Body.String()).To(MatchJSON(`{
+				"code": "forbidden",
+				"message": "requests from this network are not allowed"
+			}`))
+		})
+
+		It("ignores X-Forwarded-For from clients that aren't trusted proxies", func() {
+			forwardedFor = "192.168.1.3"
+			Expect(request(atc.SaveConfig, "1.2.3.4:1234").Code).To(Equal(http.StatusForbidden))
+		})
+
+		Context("behind trusted proxies", func() {
+			BeforeEach(func() {
+				resolver = wrappa.ClientIPResolver{
+					TrustedProxies: []*net.IPNet{cidr("10.0.0.0/8")},
+				}
+			})
+
+			It("goes by the address the nearest untrusted hop was forwarded for", func() {
+				forwardedFor = "1.2.3.4, 192.168.1.3, 10.0.0.2"
+				Expect(request(atc.SaveConfig, "10.0.0.1:1234").Code).To(Equal(http.StatusOK))
+
+				forwardedFor = "192.168.1.3, 1.2.3.4"
+				Expect(request(atc.SaveConfig, "10.0.0.1:1234").Code).To(Equal(http.StatusForbidden))
+			})
+
+			It("goes by the proxy when nothing was forwarded", func() {
+				Expect(request(atc.SaveConfig, "10.0.0.1:1234").Code).To(Equal(http.StatusForbidden))
+			})
+
+			It("turns away requests forwarded for something that isn't an IP", func() {
+				forwardedFor = "192.168.1.3, bogus"
+				Expect(request(atc.SaveConfig, "10.0.0.1:1234").Code).To(Equal(http.StatusForbidden))
+			})
+		})
+	})
+})
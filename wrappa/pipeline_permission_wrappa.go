@@ -0,0 +1,65 @@
+package wrappa
+
+import (
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/auth"
+	"github.com/concourse/atc/db"
+	"github.com/tedsuo/rata"
+)
+
+// PipelinePermissionWrappa holds the routes that change a pipeline to the
+// role the pipeline's own permissions ask for, on top of the role RoleWrappa
+// asks for.
+type PipelinePermissionWrappa struct {
+	teamDBFactory db.TeamDBFactory
+}
+
+func NewPipelinePermissionWrappa(teamDBFactory db.TeamDBFactory) Wrappa {
+	return PipelinePermissionWrappa{
+		teamDBFactory: teamDBFactory,
+	}
+}
+
+func (wrappa PipelinePermissionWrappa) Wrap(handlers rata.Handlers) rata.Handlers {
+	wrapped := rata.Handlers{}
+
+	for name, handler := range handlers {
+		permission, restricted := RequiredPipelinePermission(name)
+		if !restricted {
+			wrapped[name] = handler
+			continue
+		}
+
+		wrapped[name] = auth.CheckPipelinePermissionHandler(handler, wrappa.teamDBFactory, permission)
+	}
+
+	return wrapped
+}
+
+// RequiredPipelinePermission returns the permission a route needs of the
+// pipeline it's scoped to. Routes that run builds check the trigger
+// permission in their handlers, as some only find the pipeline through the
+// build.
+func RequiredPipelinePermission(route string) (atc.PipelinePermission, bool) {
+	switch route {
+	case atc.SaveConfig,
+		atc.SaveCandidateConfig,
+		atc.DeleteCandidateConfig,
+		atc.PromoteCandidateConfig,
+		atc.PausePipeline,
+		atc.UnpausePipeline,
+		atc.PauseJob,
+		atc.UnpauseJob,
+		atc.MakeJobAutomatic,
+		atc.MakeJobManualOnly,
+		atc.PauseResource,
+		atc.UnpauseResource,
+		atc.PinResourceVersion,
+		atc.UnpinResource,
+		atc.EnableResourceVersion,
+		atc.DisableResourceVersion:
+		return atc.PipelinePermissionConfigure, true
+	}
+
+	return "", false
+}
@@ -0,0 +1,76 @@
+package wrappa_test
+
+import (
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db/dbfakes"
+	"github.com/concourse/atc/wrappa"
+	"github.com/tedsuo/rata"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PipelinePermissionWrappa", func() {
+	var (
+		inputHandlers   rata.Handlers
+		wrappedHandlers rata.Handlers
+	)
+
+	BeforeEach(func() {
+		inputHandlers = rata.Handlers{}
+
+		for _, route := range atc.Routes {
+			inputHandlers[route.Name] = &stupidHandler{}
+		}
+	})
+
+	JustBeforeEach(func() {
+		wrappedHandlers = wrappa.NewPipelinePermissionWrappa(new(dbfakes.FakeTeamDBFactory)).Wrap(inputHandlers)
+	})
+
+	It("only wraps the routes that need a pipeline permission", func() {
+		for name, handler := range inputHandlers {
+			if _, restricted := wrappa.RequiredPipelinePermission(name); restricted {
+				Expect(descriptiveRoute{
+					route:   name,
+					handler: wrappedHandlers[name],
+				}).NotTo(Equal(descriptiveRoute{
+					route:   name,
+					handler: handler,
+				}))
+			} else {
+				Expect(descriptiveRoute{
+					route:   name,
+					handler: wrappedHandlers[name],
+				}).To(Equal(descriptiveRoute{
+					route:   name,
+					handler: handler,
+				}))
+			}
+		}
+	})
+
+	DescribeTable("RequiredPipelinePermission",
+		func(route string, permission atc.PipelinePermission, restricted bool) {
+			actualPermission, actualRestricted := wrappa.RequiredPipelinePermission(route)
+			Expect(actualPermission).To(Equal(permission))
+			Expect(actualRestricted).To(Equal(restricted))
+		},
+		Entry("setting pipelines", atc.SaveConfig, atc.PipelinePermissionConfigure, true),
+		Entry("promoting candidate configs", atc.PromoteCandidateConfig, atc.PipelinePermissionConfigure, true),
+		Entry("pausing pipelines", atc.PausePipeline, atc.PipelinePermissionConfigure, true),
+		Entry("unpausing pipelines", atc.UnpausePipeline, atc.PipelinePermissionConfigure, true),
+		Entry("pausing jobs", atc.PauseJob, atc.PipelinePermissionConfigure, true),
+		Entry("making jobs manual only", atc.MakeJobManualOnly, atc.PipelinePermissionConfigure, true),
+		Entry("pausing resources", atc.PauseResource, atc.PipelinePermissionConfigure, true),
+		Entry("unpausing resources", atc.UnpauseResource, atc.PipelinePermissionConfigure, true),
+		Entry("pinning versions", atc.PinResourceVersion, atc.PipelinePermissionConfigure, true),
+		Entry("unpinning resources", atc.UnpinResource, atc.PipelinePermissionConfigure, true),
+		Entry("enabling versions", atc.EnableResourceVersion, atc.PipelinePermissionConfigure, true),
+		Entry("disabling versions", atc.DisableResourceVersion, atc.PipelinePermissionConfigure, true),
+		Entry("triggering jobs, which is checked by the handler", atc.CreateJobBuild, atc.PipelinePermission(""), false),
+		Entry("checking resources, which is checked by the handler", atc.CheckResource, atc.PipelinePermission(""), false),
+		Entry("reading config", atc.GetConfig, atc.PipelinePermission(""), false),
+	)
+})
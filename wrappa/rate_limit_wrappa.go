@@ -0,0 +1,42 @@
+package wrappa
+
+import (
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/ratelimit"
+	"github.com/tedsuo/rata"
+)
+
+type RateLimitWrappa struct {
+	logger   lager.Logger
+	limiter  *ratelimit.Limiter
+	resolver ClientIPResolver
+}
+
+func NewRateLimitWrappa(logger lager.Logger, limiter *ratelimit.Limiter, resolver ClientIPResolver) Wrappa {
+	return RateLimitWrappa{
+		logger:   logger,
+		limiter:  limiter,
+		resolver: resolver,
+	}
+}
+
+func (wrappa RateLimitWrappa) Wrap(handlers rata.Handlers) rata.Handlers {
+	wrapped := rata.Handlers{}
+
+	for name, handler := range handlers {
+		switch name {
+		case atc.CreateBuild, atc.CreateTeamBuild, atc.CreateJobBuild, atc.ReceiveRemoteTrigger, atc.RerunBuild, atc.TriggerWebhook:
+			wrapped[name] = RateLimitedHandler{
+				Logger:   wrappa.logger.Session("rate-limit", lager.Data{"route": name}),
+				Limiter:  wrappa.limiter,
+				Resolver: wrappa.resolver,
+				Handler:  handler,
+			}
+		default:
+			wrapped[name] = handler
+		}
+	}
+
+	return wrapped
+}
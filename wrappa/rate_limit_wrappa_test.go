@@ -0,0 +1,152 @@
+package wrappa_test
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager/lagertest"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/ratelimit"
+	"github.com/concourse/atc/wrappa"
+	"github.com/tedsuo/rata"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RateLimitWrappa", func() {
+	var (
+		fakeClock *fakeclock.FakeClock
+		limiter   *ratelimit.Limiter
+		resolver  wrappa.ClientIPResolver
+
+		inputHandlers   rata.Handlers
+		wrappedHandlers rata.Handlers
+	)
+
+	BeforeEach(func() {
+		fakeClock = fakeclock.NewFakeClock(time.Unix(123, 0))
+		limiter = ratelimit.NewLimiter(fakeClock, 0.1, 1)
+		resolver = wrappa.ClientIPResolver{}
+
+		inputHandlers = rata.Handlers{}
+
+		for _, route := range atc.Routes {
+			inputHandlers[route.Name] = &stupidHandler{}
+		}
+	})
+
+	JustBeforeEach(func() {
+		wrappedHandlers = wrappa.NewRateLimitWrappa(lagertest.NewTestLogger("test"), limiter, resolver).Wrap(inputHandlers)
+	})
+
+	It("only limits the routes that create builds", func() {
+		for name, handler := range inputHandlers {
+			switch name {
+			case atc.CreateBuild, atc.CreateTeamBuild, atc.CreateJobBuild, atc.ReceiveRemoteTrigger, atc.RerunBuild, atc.TriggerWebhook:
+				Expect(wrappedHandlers[name]).To(BeAssignableToTypeOf(wrappa.RateLimitedHandler{}))
+			default:
+				Expect(descriptiveRoute{
+					route:   name,
+					handler: wrappedHandlers[name],
+				}).To(Equal(descriptiveRoute{
+					route:   name,
+					handler: handler,
+				}))
+			}
+		}
+	})
+
+	Describe("a limited route", func() {
+		var forwardedFor string
+
+		BeforeEach(func() {
+			forwardedFor = ""
+		})
+
+		request := func(remoteAddr string, authorization string) *httptest.ResponseRecorder {
+			recorder := httptest.NewRecorder()
+
+			r, err := http.NewRequest("POST", "/api/v1/builds", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			r.RemoteAddr = remoteAddr
+
+			if forwardedFor != "" {
+				r.Header.Set("X-Forwarded-For", forwardedFor)
+			}
+
+			if authorization != "" {
+				r.Header.Set("Authorization", authorization)
+			}
+
+			wrappedHandlers[atc.CreateBuild].ServeHTTP(recorder, r)
+
+			return recorder
+		}
+
+		It("rejects requests from the same IP once its bucket is empty", func() {
+			Expect(request("1.2.3.4:1111", "").Code).To(Equal(http.StatusOK))
+
+			recorder := request("1.2.3.4:2222", "")
+			Expect(recorder.Code).To(Equal(http.StatusTooManyRequests))
+			Expect(recorder.Header().Get("Retry-After")).To(Equal("10"))
+			Expect(recorder.Body.String()).To(MatchJSON(`{
+				"code": "rate-limited",
+				"message": "too many builds created; try again later"
+			}`))
+
+			Expect(request("5.6.7.8:1111", "").Code).To(Equal(http.StatusOK))
+
+			fakeClock.Increment(10 * time.Second)
+
+			Expect(request("1.2.3.4:1111", "").Code).To(Equal(http.StatusOK))
+		})
+
+		It("rejects requests with the same token even from other IPs", func() {
+			Expect(request("1.2.3.4:1111", "Bearer some-token").Code).To(Equal(http.StatusOK))
+			Expect(request("5.6.7.8:1111", "Bearer some-token").Code).To(Equal(http.StatusTooManyRequests))
+			Expect(request("5.6.7.8:1111", "Bearer other-token").Code).To(Equal(http.StatusOK))
+		})
+
+		It("limits webhooks, which come in without a token, by IP", func() {
+			webhook := func(remoteAddr string) int {
+				recorder := httptest.NewRecorder()
+
+				r, err := http.NewRequest("POST", "/api/v1/hooks/some-hook", nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				r.RemoteAddr = remoteAddr
+
+				wrappedHandlers[atc.TriggerWebhook].ServeHTTP(recorder, r)
+
+				return recorder.Code
+			}
+
+			Expect(webhook("1.2.3.4:1111")).To(Equal(http.StatusOK))
+			Expect(webhook("1.2.3.4:2222")).To(Equal(http.StatusTooManyRequests))
+			Expect(webhook("5.6.7.8:1111")).To(Equal(http.StatusOK))
+		})
+
+		Context("behind a trusted proxy", func() {
+			BeforeEach(func() {
+				_, proxies, err := net.ParseCIDR("10.0.0.0/8")
+				Expect(err).NotTo(HaveOccurred())
+
+				resolver = wrappa.ClientIPResolver{TrustedProxies: []*net.IPNet{proxies}}
+			})
+
+			It("limits the clients it forwards requests for separately", func() {
+				forwardedFor = "1.2.3.4"
+				Expect(request("10.0.0.1:1111", "").Code).To(Equal(http.StatusOK))
+				Expect(request("10.0.0.2:1111", "").Code).To(Equal(http.StatusTooManyRequests))
+
+				forwardedFor = "5.6.7.8"
+				Expect(request("10.0.0.1:1111", "").Code).To(Equal(http.StatusOK))
+			})
+		})
+	})
+})
@@ -0,0 +1,48 @@
+package wrappa
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/apierror"
+	"github.com/concourse/atc/ratelimit"
+)
+
+// RateLimitedHandler only lets requests through while both the client IP
+// and the API token they carry (if any) have tokens left in the limiter.
+type RateLimitedHandler struct {
+	Logger   lager.Logger
+	Limiter  *ratelimit.Limiter
+	Resolver ClientIPResolver
+	Handler  http.Handler
+}
+
+func (handler RateLimitedHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	remoteIP := r.RemoteAddr
+	if clientIP := handler.Resolver.ClientIP(r); clientIP != nil {
+		remoteIP = clientIP.String()
+	}
+
+	keys := []string{"ip:" + remoteIP}
+
+	if authorization := r.Header.Get("Authorization"); authorization != "" {
+		keys = append(keys, "token:"+authorization)
+	}
+
+	allowed, wait := handler.Limiter.Take(keys...)
+	if !allowed {
+		handler.Logger.Info("rate-limited", lager.Data{"remote-ip": remoteIP})
+
+		w.Header().Set("Retry-After", fmt.Sprintf("%d", int(math.Ceil(wait.Seconds()))))
+		apierror.Write(w, http.StatusTooManyRequests, atc.APIError{
+			Code:    atc.ErrorCodeRateLimited,
+			Message: "too many builds created; try again later",
+		})
+		return
+	}
+
+	handler.Handler.ServeHTTP(w, r)
+}
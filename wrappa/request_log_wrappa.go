@@ -0,0 +1,30 @@
+package wrappa
+
+import (
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/requestlog"
+	"github.com/tedsuo/rata"
+)
+
+// RequestLogWrappa gives each request an ID and logs it once it's been
+// handled. It should be the outermost wrappa, so that requests turned away
+// by the others are logged too.
+type RequestLogWrappa struct {
+	logger lager.Logger
+}
+
+func NewRequestLogWrappa(logger lager.Logger) Wrappa {
+	return RequestLogWrappa{
+		logger: logger,
+	}
+}
+
+func (wrappa RequestLogWrappa) Wrap(handlers rata.Handlers) rata.Handlers {
+	wrapped := rata.Handlers{}
+
+	for name, handler := range handlers {
+		wrapped[name] = requestlog.WrapHandler(wrappa.logger, name, handler)
+	}
+
+	return wrapped
+}
@@ -0,0 +1,81 @@
+package wrappa
+
+import (
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/auth"
+	"github.com/tedsuo/rata"
+)
+
+// RoleWrappa limits what each role can do within its team. Viewers can only
+// read, operators can also run builds and pause and unpause things, and
+// everything else needs the admin role.
+type RoleWrappa struct{}
+
+func NewRoleWrappa() Wrappa {
+	return RoleWrappa{}
+}
+
+func (wrappa RoleWrappa) Wrap(handlers rata.Handlers) rata.Handlers {
+	methods := map[string]string{}
+	for _, route := range atc.Routes {
+		methods[route.Name] = route.Method
+	}
+
+	wrapped := rata.Handlers{}
+
+	for name, handler := range handlers {
+		wrapped[name] = auth.CheckRoleHandler(handler, RequiredRole(name, methods[name]))
+	}
+
+	return wrapped
+}
+
+func RequiredRole(route string, method string) atc.Role {
+	switch route {
+	// reads that hand out more access than reading does
+	case atc.HijackBuild,
+		atc.HijackContainer,
+		atc.ListAPITokens,
+		atc.Export:
+		return atc.RoleAdmin
+
+	// writes that don't change anything
+	case atc.GetBuildStatuses,
+		atc.TriggerWebhook,
+		atc.ValidateConfig:
+		return atc.RoleViewer
+
+	case atc.CreateBuild,
+		atc.CreateTeamBuild,
+		atc.CreateJobBuild,
+		atc.ReceiveRemoteTrigger,
+		atc.AbortBuild,
+		atc.RerunBuild,
+		atc.SetBuildPriority,
+		atc.RegisterBuildArtifact,
+		atc.CreateBuildComment,
+		atc.DecideBuildApproval,
+		atc.CheckResource,
+		atc.CreatePipe,
+		atc.WritePipe,
+		atc.PauseJob,
+		atc.UnpauseJob,
+		atc.ProtectJobBuildArtifacts,
+		atc.UnprotectJobBuildArtifacts,
+		atc.PausePipeline,
+		atc.UnpausePipeline,
+		atc.PauseResource,
+		atc.UnpauseResource,
+		atc.EnableResourceVersion,
+		atc.DisableResourceVersion,
+		atc.PinResourceVersion,
+		atc.UnpinResource:
+		return atc.RoleOperator
+	}
+
+	if method == "GET" || method == "HEAD" {
+		return atc.RoleViewer
+	}
+
+	return atc.RoleAdmin
+}
@@ -0,0 +1,133 @@
+package wrappa_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/auth"
+	"github.com/concourse/atc/auth/authfakes"
+	"github.com/concourse/atc/wrappa"
+	"github.com/tedsuo/rata"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RoleWrappa", func() {
+	var (
+		fakeUserContextReader *authfakes.FakeUserContextReader
+
+		inputHandlers   rata.Handlers
+		wrappedHandlers rata.Handlers
+
+		recorder *httptest.ResponseRecorder
+	)
+
+	BeforeEach(func() {
+		fakeUserContextReader = new(authfakes.FakeUserContextReader)
+
+		inputHandlers = rata.Handlers{}
+
+		for _, route := range atc.Routes {
+			inputHandlers[route.Name] = &stupidHandler{}
+		}
+
+		recorder = httptest.NewRecorder()
+	})
+
+	JustBeforeEach(func() {
+		wrappedHandlers = wrappa.NewRoleWrappa().Wrap(inputHandlers)
+	})
+
+	serve := func(route string, method string, path string) {
+		r, err := http.NewRequest(method, path, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		auth.WrapHandler(
+			wrappedHandlers[route],
+			new(authfakes.FakeValidator),
+			fakeUserContextReader,
+		).ServeHTTP(recorder, r)
+	}
+
+	It("wraps every route", func() {
+		Expect(wrappedHandlers).To(HaveLen(len(inputHandlers)))
+	})
+
+	Context("when the request was made with a role", func() {
+		BeforeEach(func() {
+			fakeUserContextReader.GetRoleReturns(atc.RoleViewer, true)
+		})
+
+		It("lets it through to routes the role allows", func() {
+			serve(atc.ListBuilds, "GET", "/api/v1/builds")
+
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+		})
+
+		It("forbids routes needing a higher role, saying which one", func() {
+			serve(atc.CreateJobBuild, "POST", "/api/v1/teams/main/pipelines/p/jobs/j/builds")
+
+			Expect(recorder.Code).To(Equal(http.StatusForbidden))
+
+			var apiErr atc.APIError
+			err := json.Unmarshal(recorder.Body.Bytes(), &apiErr)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(apiErr.Code).To(Equal(atc.ErrorCodeForbidden))
+			Expect(apiErr.Details).To(Equal(map[string]string{
+				"required_role": "operator",
+				"role":          "viewer",
+			}))
+		})
+	})
+
+	Context("when the request was made without a role", func() {
+		BeforeEach(func() {
+			fakeUserContextReader.GetRoleReturns("", false)
+		})
+
+		It("leaves it to the other checks", func() {
+			serve(atc.SaveConfig, "PUT", "/api/v1/teams/main/pipelines/p/config")
+
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+		})
+	})
+
+	DescribeTable("RequiredRole",
+		func(route string, role atc.Role) {
+			var method string
+			for _, r := range atc.Routes {
+				if r.Name == route {
+					method = r.Method
+				}
+			}
+
+			Expect(wrappa.RequiredRole(route, method)).To(Equal(role))
+		},
+		Entry("reading builds", atc.ListBuilds, atc.RoleViewer),
+		Entry("reading config", atc.GetConfig, atc.RoleViewer),
+		Entry("asking for build statuses", atc.GetBuildStatuses, atc.RoleViewer),
+		Entry("validating config", atc.ValidateConfig, atc.RoleViewer),
+		Entry("triggering jobs", atc.CreateJobBuild, atc.RoleOperator),
+		Entry("triggering jobs from another ATC", atc.ReceiveRemoteTrigger, atc.RoleOperator),
+		Entry("aborting builds", atc.AbortBuild, atc.RoleOperator),
+		Entry("approving builds", atc.DecideBuildApproval, atc.RoleOperator),
+		Entry("pausing pipelines", atc.PausePipeline, atc.RoleOperator),
+		Entry("pausing jobs", atc.PauseJob, atc.RoleOperator),
+		Entry("protecting build artifacts", atc.ProtectJobBuildArtifacts, atc.RoleOperator),
+		Entry("checking resources", atc.CheckResource, atc.RoleOperator),
+		Entry("pinning versions", atc.PinResourceVersion, atc.RoleOperator),
+		Entry("setting pipelines", atc.SaveConfig, atc.RoleAdmin),
+		Entry("destroying pipelines", atc.DeletePipeline, atc.RoleAdmin),
+		Entry("setting pipeline permissions", atc.SetPipelinePermissions, atc.RoleAdmin),
+		Entry("setting teams", atc.SetTeam, atc.RoleAdmin),
+		Entry("hijacking", atc.HijackContainer, atc.RoleAdmin),
+		Entry("creating api tokens", atc.CreateAPIToken, atc.RoleAdmin),
+		Entry("registering resource types", atc.RegisterResourceType, atc.RoleAdmin),
+		Entry("forcing garbage collection", atc.RunGC, atc.RoleAdmin),
+	)
+})
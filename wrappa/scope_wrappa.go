@@ -0,0 +1,76 @@
+package wrappa
+
+import (
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/auth"
+	"github.com/tedsuo/rata"
+)
+
+// ScopeWrappa limits what API tokens can do. Reading needs the read scope,
+// starting and stopping builds needs the trigger scope, and everything else
+// needs the admin scope.
+type ScopeWrappa struct {
+	checker auth.APITokenChecker
+}
+
+func NewScopeWrappa(checker auth.APITokenChecker) Wrappa {
+	return ScopeWrappa{
+		checker: checker,
+	}
+}
+
+func (wrappa ScopeWrappa) Wrap(handlers rata.Handlers) rata.Handlers {
+	methods := map[string]string{}
+	for _, route := range atc.Routes {
+		methods[route.Name] = route.Method
+	}
+
+	rejector := auth.UnauthorizedRejector{}
+
+	wrapped := rata.Handlers{}
+
+	for name, handler := range handlers {
+		wrapped[name] = auth.CheckScopeHandler(handler, RequiredScope(name, methods[name]), wrappa.checker, rejector)
+	}
+
+	return wrapped
+}
+
+func RequiredScope(route string, method string) auth.Scope {
+	switch route {
+	// reads that hand out more access than reading does
+	case atc.GetAuthToken,
+		atc.HijackBuild,
+		atc.HijackContainer,
+		atc.ListAPITokens,
+		atc.Export:
+		return auth.ScopeAdmin
+
+	// writes that don't change anything
+	case atc.GetBuildStatuses,
+		atc.TriggerWebhook,
+		atc.ValidateConfig:
+		return auth.ScopeRead
+
+	case atc.CreateBuild,
+		atc.CreateTeamBuild,
+		atc.CreateJobBuild,
+		atc.ReceiveRemoteTrigger,
+		atc.AbortBuild,
+		atc.RerunBuild,
+		atc.SetBuildPriority,
+		atc.CheckResource,
+		atc.CreatePipe,
+		atc.WritePipe,
+		atc.RegisterBuildArtifact,
+		atc.CreateBuildComment,
+		atc.DecideBuildApproval:
+		return auth.ScopeTrigger
+	}
+
+	if method == "GET" || method == "HEAD" {
+		return auth.ScopeRead
+	}
+
+	return auth.ScopeAdmin
+}
@@ -0,0 +1,98 @@
+package wrappa_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/auth"
+	"github.com/concourse/atc/auth/authfakes"
+	"github.com/concourse/atc/wrappa"
+	"github.com/tedsuo/rata"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ScopeWrappa", func() {
+	var (
+		fakeChecker *authfakes.FakeAPITokenChecker
+
+		inputHandlers   rata.Handlers
+		wrappedHandlers rata.Handlers
+	)
+
+	BeforeEach(func() {
+		fakeChecker = new(authfakes.FakeAPITokenChecker)
+
+		inputHandlers = rata.Handlers{}
+
+		for _, route := range atc.Routes {
+			inputHandlers[route.Name] = &stupidHandler{}
+		}
+	})
+
+	JustBeforeEach(func() {
+		wrappedHandlers = wrappa.NewScopeWrappa(fakeChecker).Wrap(inputHandlers)
+	})
+
+	It("wraps every route", func() {
+		Expect(wrappedHandlers).To(HaveLen(len(inputHandlers)))
+	})
+
+	It("checks each route for the scope it requires", func() {
+		r, err := http.NewRequest("POST", "/api/v1/builds", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		wrappedHandlers[atc.CreateBuild].ServeHTTP(httptest.NewRecorder(), r)
+
+		_, scope := fakeChecker.CheckArgsForCall(0)
+		Expect(scope).To(Equal(auth.ScopeTrigger))
+	})
+
+	It("forbids requests whose token lacks the scope", func() {
+		fakeChecker.CheckReturns(auth.ErrInsufficientScope)
+
+		r, err := http.NewRequest("PUT", "/api/v1/teams/main/pipelines/p/config", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		recorder := httptest.NewRecorder()
+		wrappedHandlers[atc.SaveConfig].ServeHTTP(recorder, r)
+
+		Expect(recorder.Code).To(Equal(http.StatusForbidden))
+	})
+
+	DescribeTable("RequiredScope",
+		func(route string, scope auth.Scope) {
+			var method string
+			for _, r := range atc.Routes {
+				if r.Name == route {
+					method = r.Method
+				}
+			}
+
+			Expect(wrappa.RequiredScope(route, method)).To(Equal(scope))
+		},
+		Entry("reading builds", atc.ListBuilds, auth.ScopeRead),
+		Entry("reading config", atc.GetConfig, auth.ScopeRead),
+		Entry("asking for build statuses", atc.GetBuildStatuses, auth.ScopeRead),
+		Entry("validating config", atc.ValidateConfig, auth.ScopeRead),
+		Entry("creating builds", atc.CreateBuild, auth.ScopeTrigger),
+		Entry("creating team builds", atc.CreateTeamBuild, auth.ScopeTrigger),
+		Entry("reading team builds", atc.ListTeamBuilds, auth.ScopeRead),
+		Entry("triggering jobs", atc.CreateJobBuild, auth.ScopeTrigger),
+		Entry("triggering jobs from another ATC", atc.ReceiveRemoteTrigger, auth.ScopeTrigger),
+		Entry("aborting builds", atc.AbortBuild, auth.ScopeTrigger),
+		Entry("rerunning builds", atc.RerunBuild, auth.ScopeTrigger),
+		Entry("approving builds", atc.DecideBuildApproval, auth.ScopeTrigger),
+		Entry("setting pipelines", atc.SaveConfig, auth.ScopeAdmin),
+		Entry("pausing pipelines", atc.PausePipeline, auth.ScopeAdmin),
+		Entry("hijacking", atc.HijackContainer, auth.ScopeAdmin),
+		Entry("getting a session token", atc.GetAuthToken, auth.ScopeAdmin),
+		Entry("listing api tokens", atc.ListAPITokens, auth.ScopeAdmin),
+		Entry("creating api tokens", atc.CreateAPIToken, auth.ScopeAdmin),
+		Entry("registering resource types", atc.RegisterResourceType, auth.ScopeAdmin),
+		Entry("forcing garbage collection", atc.RunGC, auth.ScopeAdmin),
+	)
+})